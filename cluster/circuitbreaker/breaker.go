@@ -0,0 +1,293 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+)
+
+// windowBuckets is the number of buckets the sliding window of a circuit breaker is divided into
+const windowBuckets = 10
+
+// ErrCircuitOpen is returned when a request is short-circuited by an open circuit breaker
+var ErrCircuitOpen = common.NewRPCError(common.StatusCircuitOpen, errors.New("circuit breaker is open"))
+
+// IsCircuitOpen returns whether @err is caused by an open circuit breaker
+func IsCircuitOpen(err error) bool {
+	return err != nil && errors.Is(err, ErrCircuitOpen)
+}
+
+// State is the state of a circuit breaker
+type State int32
+
+const (
+	// StateClosed lets all requests through
+	StateClosed State = iota
+	// StateOpen short-circuits all requests
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type breakerConfig struct {
+	failureRate         int64
+	consecutiveFailures int64
+	minRequests         int64
+	window              time.Duration
+	openTime            time.Duration
+	halfOpenRequests    int64
+}
+
+func newBreakerConfig(url *common.URL, methodName string) *breakerConfig {
+	cfg := &breakerConfig{
+		failureRate: url.GetMethodParamInt64(methodName, constant.CircuitBreakerFailureRateKey,
+			constant.DefaultCircuitBreakerFailureRate),
+		consecutiveFailures: url.GetMethodParamInt64(methodName, constant.CircuitBreakerConsecutiveFailuresKey,
+			constant.DefaultCircuitBreakerConsecutiveFailures),
+		minRequests: url.GetMethodParamInt64(methodName, constant.CircuitBreakerMinRequestsKey,
+			constant.DefaultCircuitBreakerMinRequests),
+		window: getMethodParamDuration(url, methodName, constant.CircuitBreakerWindowKey,
+			constant.DefaultCircuitBreakerWindow),
+		openTime: getMethodParamDuration(url, methodName, constant.CircuitBreakerOpenTimeKey,
+			constant.DefaultCircuitBreakerOpenTime),
+		halfOpenRequests: url.GetMethodParamInt64(methodName, constant.CircuitBreakerHalfOpenRequestsKey,
+			constant.DefaultCircuitBreakerHalfOpenRequests),
+	}
+	if cfg.halfOpenRequests <= 0 {
+		cfg.halfOpenRequests = 1
+	}
+	return cfg
+}
+
+func getMethodParamDuration(url *common.URL, methodName, key, d string) time.Duration {
+	v := url.GetMethodParam(methodName, key, url.GetParam(key, d))
+	if t, err := common.ParseDuration(v); err == nil {
+		return t
+	}
+	t, _ := time.ParseDuration(d)
+	return t
+}
+
+type bucket struct {
+	start    time.Time
+	requests int64
+	failures int64
+}
+
+// CircuitBreaker is the circuit breaker of a method of a provider endpoint.
+// It opens after the consecutive failures or the failure rate in the sliding window exceeds the threshold, and
+// short-circuits the requests. After the open time, it turns half-open and lets a limited number of probe requests
+// through, it closes again once all of them succeed, or opens again on any failure.
+type CircuitBreaker struct {
+	mutex      sync.Mutex
+	url        *common.URL
+	methodName string
+	cfg        *breakerConfig
+	clock      func() time.Time
+
+	state State
+	// forced is true if the state is forced by ForceOpen or ForceClose, and will not change with the results
+	forced              bool
+	openedAt            time.Time
+	consecutiveFailures int64
+	buckets             [windowBuckets]bucket
+	halfOpenInflight    int64
+	halfOpenSucceeded   int64
+}
+
+// NewCircuitBreaker creates a closed circuit breaker of the method @methodName of the provider with @url
+func NewCircuitBreaker(url *common.URL, methodName string) *CircuitBreaker {
+	return &CircuitBreaker{
+		url:        url,
+		methodName: methodName,
+		cfg:        newBreakerConfig(url, methodName),
+		clock:      time.Now,
+	}
+}
+
+// Allow returns whether a request could go through. A half-open circuit breaker counts the allowed requests as
+// probes, so the result of every allowed request must be reported by OnResult.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == StateOpen {
+		if cb.forced || cb.clock().Sub(cb.openedAt) < cb.cfg.openTime {
+			return false
+		}
+		cb.transit(StateHalfOpen)
+	}
+	if cb.state == StateHalfOpen {
+		if cb.halfOpenInflight+cb.halfOpenSucceeded >= cb.cfg.halfOpenRequests {
+			return false
+		}
+		cb.halfOpenInflight++
+	}
+	return true
+}
+
+// OnResult reports the result of a request allowed by Allow
+func (cb *CircuitBreaker) OnResult(err error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.forced {
+		return
+	}
+	switch cb.state {
+	case StateClosed:
+		cb.recordClosed(err)
+	case StateHalfOpen:
+		if cb.halfOpenInflight > 0 {
+			cb.halfOpenInflight--
+		}
+		if err != nil {
+			cb.transit(StateOpen)
+			return
+		}
+		cb.halfOpenSucceeded++
+		if cb.halfOpenSucceeded >= cb.cfg.halfOpenRequests {
+			cb.transit(StateClosed)
+		}
+	default:
+		// the request was sent before the circuit breaker opened
+	}
+}
+
+func (cb *CircuitBreaker) recordClosed(err error) {
+	now := cb.clock()
+	b := cb.currentBucket(now)
+	b.requests++
+	if err == nil {
+		cb.consecutiveFailures = 0
+		return
+	}
+	b.failures++
+	cb.consecutiveFailures++
+
+	if cb.cfg.consecutiveFailures > 0 && cb.consecutiveFailures >= cb.cfg.consecutiveFailures {
+		cb.transit(StateOpen)
+		return
+	}
+	requests, failures := cb.windowCount(now)
+	if cb.cfg.failureRate > 0 && requests >= cb.cfg.minRequests && failures*100 >= cb.cfg.failureRate*requests {
+		cb.transit(StateOpen)
+	}
+}
+
+// State returns the current state of the circuit breaker
+func (cb *CircuitBreaker) State() State {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.state
+}
+
+// ForceOpen opens the circuit breaker until Reset is called
+func (cb *CircuitBreaker) ForceOpen() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.forced = true
+	cb.transit(StateOpen)
+}
+
+// ForceClose closes the circuit breaker until Reset is called
+func (cb *CircuitBreaker) ForceClose() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.forced = true
+	cb.transit(StateClosed)
+}
+
+// Reset closes the circuit breaker and lets it change with the results again
+func (cb *CircuitBreaker) Reset() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.forced = false
+	cb.transit(StateClosed)
+}
+
+// transit changes the state and clears the statistics of the previous state, it must be called with the lock held
+func (cb *CircuitBreaker) transit(state State) {
+	from := cb.state
+	cb.state = state
+	cb.consecutiveFailures = 0
+	cb.buckets = [windowBuckets]bucket{}
+	cb.halfOpenInflight = 0
+	cb.halfOpenSucceeded = 0
+	if state == StateOpen {
+		cb.openedAt = cb.clock()
+	}
+	if from == state {
+		return
+	}
+	logger.Warnf("[circuit breaker] the circuit breaker of the method %s of %s changes from %s to %s",
+		cb.methodName, cb.url.Location, from, state)
+	metrics.Publish(rpc.NewCircuitBreakerStateChangedEvent(cb.url, cb.methodName, state.String()))
+}
+
+// currentBucket returns the bucket of @now, and clears it if it belongs to a previous window
+func (cb *CircuitBreaker) currentBucket(now time.Time) *bucket {
+	width := cb.cfg.window / windowBuckets
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	start := now.Truncate(width)
+	b := &cb.buckets[(start.UnixNano()/int64(width))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
+
+// windowCount sums up the requests and failures in the window ending at @now
+func (cb *CircuitBreaker) windowCount(now time.Time) (requests, failures int64) {
+	for _, b := range cb.buckets {
+		if b.start.IsZero() || now.Sub(b.start) >= cb.cfg.window {
+			continue
+		}
+		requests += b.requests
+		failures += b.failures
+	}
+	return
+}
@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+const providerAddr = "dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider"
+
+var errRequest = errors.New("request failed")
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(t *testing.T, params string, methodName string) (*CircuitBreaker, *fakeClock) {
+	url, err := common.NewURL(providerAddr + "?" + params)
+	assert.Nil(t, err)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	cb := NewCircuitBreaker(url, methodName)
+	cb.clock = clock.Now
+	return cb, clock
+}
+
+func request(cb *CircuitBreaker, err error) bool {
+	if !cb.Allow() {
+		return false
+	}
+	cb.OnResult(err)
+	return true
+}
+
+func TestOpenOnConsecutiveFailures(t *testing.T) {
+	cb, _ := newTestBreaker(t, "circuit.breaker.consecutive.failures=3&circuit.breaker.failure.rate=0", "GetUser")
+
+	assert.True(t, request(cb, errRequest))
+	assert.True(t, request(cb, errRequest))
+	assert.True(t, request(cb, nil))
+	assert.True(t, request(cb, errRequest))
+	assert.True(t, request(cb, errRequest))
+	assert.Equal(t, StateClosed, cb.State())
+	assert.True(t, request(cb, errRequest))
+	assert.Equal(t, StateOpen, cb.State())
+	assert.False(t, cb.Allow())
+}
+
+func TestOpenOnFailureRate(t *testing.T) {
+	cb, clock := newTestBreaker(t, "circuit.breaker.consecutive.failures=0&circuit.breaker.failure.rate=50"+
+		"&circuit.breaker.min.requests=4&circuit.breaker.window=10s", "GetUser")
+
+	// failures out of the window are not counted
+	request(cb, errRequest)
+	request(cb, errRequest)
+	clock.Advance(11 * time.Second)
+	request(cb, nil)
+	request(cb, errRequest)
+	request(cb, nil)
+	assert.Equal(t, StateClosed, cb.State())
+	request(cb, errRequest)
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestHalfOpen(t *testing.T) {
+	cb, clock := newTestBreaker(t, "circuit.breaker.consecutive.failures=1&circuit.breaker.open.time=5s"+
+		"&circuit.breaker.half.open.requests=2", "GetUser")
+
+	request(cb, errRequest)
+	assert.Equal(t, StateOpen, cb.State())
+	clock.Advance(4 * time.Second)
+	assert.False(t, cb.Allow())
+
+	// the probe fails, open again
+	clock.Advance(time.Second)
+	assert.True(t, cb.Allow())
+	assert.Equal(t, StateHalfOpen, cb.State())
+	cb.OnResult(errRequest)
+	assert.Equal(t, StateOpen, cb.State())
+	assert.False(t, cb.Allow())
+
+	// only a limited number of probes go through, and all of them succeed
+	clock.Advance(5 * time.Second)
+	assert.True(t, cb.Allow())
+	assert.True(t, cb.Allow())
+	assert.False(t, cb.Allow())
+	cb.OnResult(nil)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	assert.False(t, cb.Allow())
+	cb.OnResult(nil)
+	assert.Equal(t, StateClosed, cb.State())
+	assert.True(t, cb.Allow())
+}
+
+func TestMethodConfig(t *testing.T) {
+	params := "circuit.breaker.consecutive.failures=5&methods.GetUser.circuit.breaker.consecutive.failures=1" +
+		"&methods.GetUser.circuit.breaker.open.time=1s"
+	cb, _ := newTestBreaker(t, params, "GetUser")
+	assert.Equal(t, int64(1), cb.cfg.consecutiveFailures)
+	assert.Equal(t, time.Second, cb.cfg.openTime)
+	assert.Equal(t, 10*time.Second, cb.cfg.window)
+
+	cb, _ = newTestBreaker(t, params, "GetUsers")
+	assert.Equal(t, int64(5), cb.cfg.consecutiveFailures)
+	assert.Equal(t, 5*time.Second, cb.cfg.openTime)
+}
+
+func TestForce(t *testing.T) {
+	cb, clock := newTestBreaker(t, "circuit.breaker.consecutive.failures=1", "GetUser")
+
+	cb.ForceOpen()
+	clock.Advance(time.Hour)
+	assert.False(t, cb.Allow())
+	assert.Equal(t, StateOpen, cb.State())
+
+	cb.ForceClose()
+	assert.True(t, request(cb, errRequest))
+	assert.Equal(t, StateClosed, cb.State())
+
+	cb.Reset()
+	assert.True(t, request(cb, errRequest))
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestManager(t *testing.T) {
+	url, _ := common.NewURL(providerAddr)
+	cb := GetCircuitBreaker(url, "GetUser")
+	assert.Same(t, cb, GetCircuitBreaker(url, "GetUser"))
+	assert.NotSame(t, cb, GetCircuitBreaker(url, "GetUsers"))
+
+	ForceOpen(url, "GetUser")
+	assert.Equal(t, StateOpen, States()[breakerKey(url, "GetUser")])
+	ForceClose(url, "GetUser")
+	assert.Equal(t, StateClosed, cb.State())
+	Reset(url, "GetUser")
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestIsCircuitOpen(t *testing.T) {
+	assert.False(t, IsCircuitOpen(nil))
+	assert.False(t, IsCircuitOpen(errRequest))
+	assert.True(t, IsCircuitOpen(ErrCircuitOpen))
+	assert.True(t, IsCircuitOpen(perrors.Wrap(perrors.Wrap(ErrCircuitOpen, "short-circuited"), "failover")))
+	assert.Equal(t, common.StatusCircuitOpen, common.CodeOf(perrors.Wrap(ErrCircuitOpen, "short-circuited")))
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// breakers holds the circuit breakers keyed by provider endpoint and method
+var breakers sync.Map
+
+func breakerKey(url *common.URL, methodName string) string {
+	return strings.Join([]string{url.Key(), methodName}, "#")
+}
+
+// GetCircuitBreaker returns the circuit breaker of the method @methodName of the provider with @url,
+// it is created on first use.
+func GetCircuitBreaker(url *common.URL, methodName string) *CircuitBreaker {
+	key := breakerKey(url, methodName)
+	if cb, ok := breakers.Load(key); ok {
+		return cb.(*CircuitBreaker)
+	}
+	cb, _ := breakers.LoadOrStore(key, NewCircuitBreaker(url, methodName))
+	return cb.(*CircuitBreaker)
+}
+
+// ForceOpen opens the circuit breaker of the method @methodName of the provider with @url until Reset is called
+func ForceOpen(url *common.URL, methodName string) {
+	GetCircuitBreaker(url, methodName).ForceOpen()
+}
+
+// ForceClose closes the circuit breaker of the method @methodName of the provider with @url until Reset is called
+func ForceClose(url *common.URL, methodName string) {
+	GetCircuitBreaker(url, methodName).ForceClose()
+}
+
+// Reset lets the circuit breaker of the method @methodName of the provider with @url change with the results again
+func Reset(url *common.URL, methodName string) {
+	GetCircuitBreaker(url, methodName).Reset()
+}
+
+// States returns the states of all circuit breakers, keyed by provider url key and method
+func States() map[string]State {
+	states := make(map[string]State)
+	breakers.Range(func(key, value interface{}) bool {
+		states[key.(string)] = value.(*CircuitBreaker).State()
+		return true
+	})
+	return states
+}
+
+// ProviderStates returns the states of the circuit breakers of the provider with @url, keyed by method. Unlike
+// GetCircuitBreaker, no circuit breaker is created.
+func ProviderStates(url *common.URL) map[string]State {
+	prefix := breakerKey(url, "")
+	states := make(map[string]State)
+	breakers.Range(func(key, value interface{}) bool {
+		if method := strings.TrimPrefix(key.(string), prefix); len(method) != len(key.(string)) {
+			states[method] = value.(*CircuitBreaker).State()
+		}
+		return true
+	})
+	return states
+}
@@ -94,3 +94,58 @@ func TestAvailableClusterInvokerNoAvail(t *testing.T) {
 	assert.True(t, strings.Contains(result.Error().Error(), "no provider available"))
 	assert.Nil(t, result.Result())
 }
+
+func TestAvailableClusterInvokerEmptyDirectory(t *testing.T) {
+	clusterInvoker := NewAvailableCluster().Join(static.NewDirectory(nil))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "No provider available")
+	assert.Equal(t, common.StatusNoProvider, common.CodeOf(result.Error()))
+}
+
+func TestAvailableClusterInvokerFirstAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var invokers []protocol.Invoker
+	mockResult := &protocol.RPCResult{Rest: clusterpkg.Rest{Tried: 0, Success: true}}
+	for i := 0; i < 3; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider", i))
+		invoker.EXPECT().GetURL().Return(url).AnyTimes()
+		// only the second invoker is available
+		invoker.EXPECT().IsAvailable().Return(i == 1).AnyTimes()
+		if i == 1 {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Return(mockResult).Times(1)
+		} else {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Times(0)
+		}
+		invokers = append(invokers, invoker)
+	}
+	clusterInvoker := NewAvailableCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, mockResult, result)
+}
+
+func TestAvailableClusterInvokerAllUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var invokers []protocol.Invoker
+	for i := 0; i < 3; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider", i))
+		invoker.EXPECT().GetURL().Return(url).AnyTimes()
+		invoker.EXPECT().IsAvailable().Return(false).AnyTimes()
+		invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Times(0)
+		invokers = append(invokers, invoker)
+	}
+	clusterInvoker := NewAvailableCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "no provider available")
+	assert.Equal(t, common.StatusNoProvider, common.CodeOf(result.Error()))
+}
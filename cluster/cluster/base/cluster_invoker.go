@@ -18,6 +18,10 @@
 // Package base implements invoker for the manipulation of cluster strategy.
 package base
 
+import (
+	"fmt"
+)
+
 import (
 	"github.com/dubbogo/gost/log/logger"
 
@@ -27,6 +31,7 @@ import (
 )
 
 import (
+	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common"
@@ -54,6 +59,11 @@ func (invoker *BaseClusterInvoker) GetURL() *common.URL {
 	return invoker.Directory.GetURL()
 }
 
+// GetDirectory returns the directory of the providers
+func (invoker *BaseClusterInvoker) GetDirectory() directory.Directory {
+	return invoker.Directory
+}
+
 func (invoker *BaseClusterInvoker) Destroy() {
 	// this is must atom operation
 	if invoker.Destroyed.CAS(false, true) {
@@ -72,9 +82,17 @@ func (invoker *BaseClusterInvoker) IsAvailable() bool {
 func (invoker *BaseClusterInvoker) CheckInvokers(invokers []protocol.Invoker, invocation protocol.Invocation) error {
 	if len(invokers) == 0 {
 		ip := common.GetLocalIp()
-		return perrors.Errorf("Failed to invoke the method %v. No provider available for the service %v from "+
-			"registry %v on the consumer %v using the dubbo version %v .Please check if the providers have been started and registered.",
-			invocation.MethodName(), invoker.Directory.GetURL().SubURL.Key(), invoker.Directory.GetURL().String(), ip, constant.Version)
+		// the url of a directory without any invoker may be absent, e.g. an empty static directory
+		var service, registry string
+		if url := invoker.Directory.GetURL(); url != nil {
+			service, registry = url.Key(), url.String()
+			if url.SubURL != nil {
+				service = url.SubURL.Key()
+			}
+		}
+		return perrors.Wrapf(protocol.ErrNoProvider, "Failed to invoke the method %v of the service %v from "+
+			"registry %v on the consumer %v using the dubbo version %v, please check if the providers have been started and registered",
+			invocation.MethodName(), service, registry, ip, constant.Version)
 	}
 	return nil
 }
@@ -104,19 +122,48 @@ func (invoker *BaseClusterInvoker) DoSelect(lb loadbalance.LoadBalance, invocati
 		invoker.StickyInvoker = nil
 	}
 
+	clusterpkg.RecordAvailable(invokers)
 	if sticky && invoker.AvailableCheck &&
 		invoker.StickyInvoker != nil && invoker.StickyInvoker.IsAvailable() &&
 		(invoked == nil || !isInvoked(invoker.StickyInvoker, invoked)) {
+		recordSelection(invocation, invokers, invoked, invoker.StickyInvoker, "sticky")
 		return invoker.StickyInvoker
 	}
 
-	selectedInvoker = invoker.doSelectInvoker(lb, invocation, invokers, invoked)
+	// the sticky invoker is kept even if it is remote, the locality only takes effect on load balancing
+	candidates := filterByLocality(invokers, invoked, invocation, invoker.consumerLocality())
+	selectedInvoker = invoker.doSelectInvoker(lb, invocation, candidates, invoked)
 	if sticky {
 		invoker.StickyInvoker = selectedInvoker
 	}
+	if selectedInvoker != nil {
+		recordSelection(invocation, invokers, invoked, selectedInvoker, "loadbalance")
+	}
 	return selectedInvoker
 }
 
+// recordSelection records the selection statistics, and logs the selection decision if selection.log is enabled
+func recordSelection(invocation protocol.Invocation, invokers, invoked []protocol.Invoker, selected protocol.Invoker, reason string) {
+	url := selected.GetURL()
+	clusterpkg.RecordSelection(url, len(invoked) > 0, reason == "sticky")
+	clusterpkg.RecordDecision(url, invocation.MethodName(), reason, len(invoked) > 0, len(invokers))
+	if !url.GetParamBool(constant.SelectionLogKey, false) {
+		return
+	}
+	weights := make([]string, 0, len(invokers))
+	for _, ivk := range invokers {
+		weights = append(weights, fmt.Sprintf("%s=%d", ivk.GetURL().Location,
+			ivk.GetURL().GetMethodParamInt(invocation.MethodName(), constant.WeightKey,
+				ivk.GetURL().GetParamInt(constant.WeightKey, constant.DefaultWeight))))
+	}
+	invokedAddresses := make([]string, 0, len(invoked))
+	for _, ivk := range invoked {
+		invokedAddresses = append(invokedAddresses, ivk.GetURL().Location)
+	}
+	logger.Debugf("[selection] service %s method %s selected %s by %s, routed candidates %v, already invoked %v",
+		clusterpkg.RefKey(url), invocation.MethodName(), url.Location, reason, weights, invokedAddresses)
+}
+
 func (invoker *BaseClusterInvoker) doSelectInvoker(lb loadbalance.LoadBalance, invocation protocol.Invocation, invokers []protocol.Invoker, invoked []protocol.Invoker) protocol.Invoker {
 	if len(invokers) == 0 {
 		return nil
@@ -73,3 +73,30 @@ func TestStickyNormalWhenError(t *testing.T) {
 	result1 := base.DoSelect(random.NewRandomLoadBalance(), invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil), invokers, invoked)
 	assert.NotEqual(t, result, result1)
 }
+
+func TestDoSelectStats(t *testing.T) {
+	clusterpkg.ResetStats()
+	defer clusterpkg.ResetStats()
+
+	var invokers []protocol.Invoker
+	for i := 0; i < 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf(baseClusterInvokerFormat, i))
+		url.SetParam("sticky", "true")
+		url.SetParam("selection.log", "true")
+		invokers = append(invokers, clusterpkg.NewMockInvoker(url, 1))
+	}
+	base := &BaseClusterInvoker{AvailableCheck: true}
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+
+	selected := base.DoSelect(random.NewRandomLoadBalance(), inv, invokers, nil)
+	base.DoSelect(random.NewRandomLoadBalance(), inv, invokers, nil)
+	base.DoSelect(random.NewRandomLoadBalance(), inv, invokers, []protocol.Invoker{selected})
+
+	stats, ok := clusterpkg.Stats(clusterpkg.RefKey(selected.GetURL()))
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), stats.Selections)
+	assert.Equal(t, int64(1), stats.StickyHits)
+	assert.Equal(t, int64(1), stats.Retries)
+	assert.Len(t, stats.Available, 1)
+	assert.Equal(t, 3, stats.Available[0].Count)
+}
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"reflect"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// CopyInvocation copies @inv with its own attachments and a new reply of the same type, so that the invokers called
+// concurrently don't share them
+func CopyInvocation(inv protocol.Invocation) protocol.Invocation {
+	attachments := make(map[string]interface{}, len(inv.Attachments()))
+	for k, v := range inv.Attachments() {
+		attachments[k] = v
+	}
+	copied := invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName(inv.MethodName()),
+		invocation.WithParameterTypes(inv.ParameterTypes()),
+		invocation.WithParameterTypeNames(inv.ParameterTypeNames()),
+		invocation.WithParameterValues(inv.ParameterValues()),
+		invocation.WithArguments(inv.Arguments()),
+		invocation.WithAttachments(attachments),
+		invocation.WithInvoker(inv.Invoker()),
+	)
+	if reply, ok := ReplyOf(inv); ok {
+		copied.SetReply(reflect.New(reply.Type().Elem()).Interface())
+	}
+	for k, v := range inv.Attributes() {
+		copied.SetAttribute(k, v)
+	}
+	return copied
+}
+
+// ReplyOf returns the reply of @inv if it is a non-nil pointer
+func ReplyOf(inv protocol.Invocation) (reflect.Value, bool) {
+	if inv.Reply() == nil {
+		return reflect.Value{}, false
+	}
+	reply := reflect.ValueOf(inv.Reply())
+	if reply.Kind() != reflect.Ptr || reply.IsNil() {
+		return reflect.Value{}, false
+	}
+	return reply, true
+}
+
+// CopyReply sets the reply of @inv to the reply of its copy @won, which the @result of the winning invoker is decoded
+// into, and the result is the reply of @inv then
+func CopyReply(inv, won protocol.Invocation, result protocol.Result) {
+	reply, ok := ReplyOf(inv)
+	if !ok {
+		return
+	}
+	wonReply, ok := ReplyOf(won)
+	if !ok {
+		return
+	}
+	reply.Elem().Set(wonReply.Elem())
+	if result.Result() == won.Reply() {
+		result.SetResult(inv.Reply())
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	localityHost = "host"
+	localityDC   = "dc"
+	localityNone = "none"
+)
+
+// locality is where the consumer runs
+type locality struct {
+	host       string
+	datacenter string
+}
+
+// consumerLocality returns the locality of the consumer, the datacenter is read from the consumer url
+func (invoker *BaseClusterInvoker) consumerLocality() locality {
+	local := locality{host: common.GetLocalIp()}
+	if invoker.Directory == nil {
+		return local
+	}
+	if url := invoker.Directory.GetURL(); url != nil {
+		local.datacenter = url.GetParam(constant.DatacenterKey, "")
+		if url.SubURL != nil {
+			local.datacenter = url.SubURL.GetParam(constant.DatacenterKey, local.datacenter)
+		}
+	}
+	return local
+}
+
+// filterByLocality narrows @invokers down to the first level of locality.preference which has at least
+// locality.min.healthy healthy candidates, i.e. available and not invoked yet, or returns all the invokers
+// if no level has. The invokers are routed already, so the ones ejected by the health check router are
+// never counted as healthy.
+func filterByLocality(invokers, invoked []protocol.Invoker, invocation protocol.Invocation, local locality) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	url := invokers[0].GetURL()
+	preference := url.GetMethodParam(invocation.MethodName(), constant.LocalityPreferenceKey,
+		url.GetParam(constant.LocalityPreferenceKey, localityNone))
+	minHealthy := int(url.GetMethodParamInt(invocation.MethodName(), constant.LocalityMinHealthyKey,
+		url.GetParamInt(constant.LocalityMinHealthyKey, constant.DefaultLocalityMinHealthy)))
+
+	for _, level := range strings.Split(preference, ",") {
+		var match func(*common.URL) bool
+		switch strings.TrimSpace(level) {
+		case localityHost:
+			match = func(u *common.URL) bool { return isSameHost(u.Ip, local.host) }
+		case localityDC:
+			if local.datacenter == "" {
+				continue
+			}
+			match = func(u *common.URL) bool { return u.GetParam(constant.DatacenterKey, "") == local.datacenter }
+		default:
+			// none, or an unknown level, stops preferring any locality
+			return invokers
+		}
+
+		candidates := make([]protocol.Invoker, 0, len(invokers))
+		healthy := 0
+		for _, ivk := range invokers {
+			if !match(ivk.GetURL()) {
+				continue
+			}
+			candidates = append(candidates, ivk)
+			if ivk.IsAvailable() && !isInvoked(ivk, invoked) {
+				healthy++
+			}
+		}
+		if healthy > 0 && healthy >= minHealthy {
+			return candidates
+		}
+	}
+	return invokers
+}
+
+func isSameHost(ip, localIp string) bool {
+	return ip == localIp || ip == "127.0.0.1" || ip == "localhost"
+}
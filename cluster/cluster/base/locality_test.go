@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const localityLocalHost = "10.0.0.1"
+
+var localityConsumer = locality{host: localityLocalHost, datacenter: "dc1"}
+
+// newLocalityInvokers returns a same host invoker, two same datacenter invokers and two remote invokers
+func newLocalityInvokers(t *testing.T, params string) []protocol.Invoker {
+	addresses := []string{
+		localityLocalHost + ":20000?datacenter=dc1",
+		"10.0.0.2:20000?datacenter=dc1",
+		"10.0.0.3:20000?datacenter=dc1",
+		"10.1.0.1:20000?datacenter=dc2",
+		"10.1.0.2:20000?side=provider",
+	}
+	invokers := make([]protocol.Invoker, 0, len(addresses))
+	for _, address := range addresses {
+		url, err := common.NewURL("dubbo://" + address + "&" + params)
+		assert.Nil(t, err)
+		invokers = append(invokers, clusterpkg.NewMockInvoker(url, 1))
+	}
+	return invokers
+}
+
+func TestFilterByLocality(t *testing.T) {
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+
+	invokers := newLocalityInvokers(t, "locality.preference=host,dc")
+	assert.Equal(t, invokers[:1], filterByLocality(invokers, nil, inv, localityConsumer))
+
+	invokers = newLocalityInvokers(t, "locality.preference=dc")
+	assert.Equal(t, invokers[:3], filterByLocality(invokers, nil, inv, localityConsumer))
+
+	invokers = newLocalityInvokers(t, "locality.preference=none")
+	assert.Equal(t, invokers, filterByLocality(invokers, nil, inv, localityConsumer))
+
+	// the locality is not preferred by default
+	invokers = newLocalityInvokers(t, "")
+	assert.Equal(t, invokers, filterByLocality(invokers, nil, inv, localityConsumer))
+
+	// the dc level is skipped if the consumer has no datacenter
+	invokers = newLocalityInvokers(t, "locality.preference=dc")
+	assert.Equal(t, invokers, filterByLocality(invokers, nil, inv, locality{host: localityLocalHost}))
+
+	// the method config overrides the service config
+	invokers = newLocalityInvokers(t, "locality.preference=host&methods."+baseClusterInvokerMethodName+".locality.preference=dc")
+	assert.Equal(t, invokers[:3], filterByLocality(invokers, nil, inv, localityConsumer))
+}
+
+func TestFilterByLocalityWidening(t *testing.T) {
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+
+	// the same host invoker is not enough for min healthy 2, the same datacenter invokers are
+	invokers := newLocalityInvokers(t, "locality.preference=host,dc&locality.min.healthy=2")
+	assert.Equal(t, invokers[:3], filterByLocality(invokers, nil, inv, localityConsumer))
+
+	// no level is healthy enough, all the invokers are candidates
+	invokers = newLocalityInvokers(t, "locality.preference=host,dc&locality.min.healthy=4")
+	assert.Equal(t, invokers, filterByLocality(invokers, nil, inv, localityConsumer))
+
+	// the unavailable same host invoker widens the candidates to the datacenter
+	invokers = newLocalityInvokers(t, "locality.preference=host,dc")
+	invokers[0].Destroy()
+	assert.Equal(t, invokers[:3], filterByLocality(invokers, nil, inv, localityConsumer))
+
+	// the invoked invokers are not healthy candidates when retrying
+	invokers = newLocalityInvokers(t, "locality.preference=host,dc")
+	assert.Equal(t, invokers[:3], filterByLocality(invokers, invokers[:1], inv, localityConsumer))
+	assert.Equal(t, invokers, filterByLocality(invokers, invokers[:3], inv, localityConsumer))
+}
+
+func TestDoSelectLocality(t *testing.T) {
+	base := &BaseClusterInvoker{AvailableCheck: true}
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+	lb := random.NewRandomLoadBalance()
+
+	invokers := newLocalityInvokers(t, "locality.preference=host")
+	local, _ := common.NewURL("dubbo://" + common.GetLocalIp() + ":20000?locality.preference=host")
+	invokers = append(invokers, clusterpkg.NewMockInvoker(local, 1))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, invokers[5], base.DoSelect(lb, inv, invokers, nil))
+	}
+	// retry on another invoker once the same host invoker is invoked
+	assert.NotEqual(t, invokers[5], base.DoSelect(lb, inv, invokers, invokers[5:]))
+}
+
+func TestDoSelectLocalitySticky(t *testing.T) {
+	base := &BaseClusterInvoker{AvailableCheck: true}
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+	lb := random.NewRandomLoadBalance()
+
+	invokers := newLocalityInvokers(t, "locality.preference=host&sticky=true")
+	// the sticky invoker is kept even though a same host invoker shows up
+	base.StickyInvoker = invokers[3]
+	local, _ := common.NewURL("dubbo://" + common.GetLocalIp() + ":20000?locality.preference=host&sticky=true")
+	invokers = append(invokers, clusterpkg.NewMockInvoker(local, 1))
+	assert.Equal(t, invokers[3], base.DoSelect(lb, inv, invokers, nil))
+
+	// the same host invoker becomes sticky once the sticky invoker is gone
+	invokers = append(invokers[:3], invokers[4:]...)
+	assert.Equal(t, invokers[4], base.DoSelect(lb, inv, invokers, nil))
+	assert.Equal(t, invokers[4], base.StickyInvoker)
+}
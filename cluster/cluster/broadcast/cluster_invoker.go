@@ -19,15 +19,19 @@ package broadcast
 
 import (
 	"context"
+	"sync"
 )
 
 import (
 	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -41,7 +45,13 @@ func newBroadcastClusterInvoker(directory directory.Directory) protocol.Invoker
 	}
 }
 
-// nolint
+// Invoke invokes all invokers, at most broadcast.parallelism of them at the same time.
+//
+// The broadcast fails once the failed invokers exceed broadcast.fail.percent of all invokers,
+// which is 0 by default, meaning that any failure fails the broadcast. With broadcast.fail.fast
+// no more invokers are called after that. The per-invoker errors, keyed by the invoker's location,
+// are attached to the returned result under constant.BroadcastResultsKey, nil standing for success.
+// The result and the reply are the ones of the last successful invoker in the order of the invokers.
 func (invoker *broadcastClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
 	invokers := invoker.Directory.List(invocation)
 	err := invoker.CheckInvokers(invokers, invocation)
@@ -53,16 +63,81 @@ func (invoker *broadcastClusterInvoker) Invoke(ctx context.Context, invocation p
 		return &protocol.RPCResult{Err: err}
 	}
 
-	var result protocol.Result
-	for _, ivk := range invokers {
-		result = ivk.Invoke(ctx, invocation)
-		if result.Error() != nil {
-			logger.Warnf("broadcast invoker invoke err: %v when use invoker: %v\n", result.Error(), ivk)
-			err = result.Error()
+	url := invokers[0].GetURL()
+	failPercent := url.GetParamByIntValue(constant.BroadcastFailPercentKey, 0)
+	if failPercent < 0 || failPercent > 100 {
+		logger.Warnf("broadcast fail percent %d is out of range [0, 100], use 0 instead", failPercent)
+		failPercent = 0
+	}
+	failFast := url.GetParamBool(constant.BroadcastFailFastKey, false)
+	parallelism := url.GetParamByIntValue(constant.BroadcastParallelismKey, constant.DefaultBroadcastParallelism)
+	if parallelism < 1 {
+		parallelism = constant.DefaultBroadcastParallelism
+	}
+	maxFailures := int32(len(invokers) * failPercent / 100)
+
+	var (
+		wg          sync.WaitGroup
+		failures    atomic.Int32
+		results     = make([]protocol.Result, len(invokers))
+		invocations = make([]protocol.Invocation, len(invokers))
+		tokens      = make(chan struct{}, parallelism)
+	)
+	for i, ivk := range invokers {
+		tokens <- struct{}{}
+		if failFast && failures.Load() > maxFailures {
+			<-tokens
+			break
 		}
+		// the invokers called concurrently decode their responses into their own replies
+		inv := invocation
+		if parallelism > 1 {
+			inv = base.CopyInvocation(invocation)
+		}
+		invocations[i] = inv
+		wg.Add(1)
+		go func(i int, ivk protocol.Invoker) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			result := ivk.Invoke(ctx, inv)
+			if result.Error() != nil {
+				logger.Warnf("broadcast invoker invoke err: %v when use invoker: %v\n", result.Error(), ivk)
+				failures.Inc()
+			}
+			results[i] = result
+		}(i, ivk)
 	}
-	if err != nil {
-		return &protocol.RPCResult{Err: err}
+	wg.Wait()
+
+	var (
+		result   protocol.Result
+		won      int
+		statuses = make(map[string]error, len(invokers))
+	)
+	for i, r := range results {
+		// skipped by fail fast
+		if r == nil {
+			continue
+		}
+		statuses[invokers[i].GetURL().Location] = r.Error()
+		if r.Error() != nil {
+			err = r.Error()
+		} else {
+			result, won = r, i
+		}
+	}
+	if result != nil && invocations[won] != invocation {
+		base.CopyReply(invocation, invocations[won], result)
+	}
+
+	if failures.Load() > maxFailures || result == nil {
+		return &protocol.RPCResult{
+			Err:   err,
+			Attrs: map[string]interface{}{constant.BroadcastResultsKey: statuses},
+		}
 	}
+	result.AddAttachment(constant.BroadcastResultsKey, statuses)
 	return result
 }
@@ -108,3 +108,136 @@ func TestBroadcastInvokeFailed(t *testing.T) {
 	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
 	assert.Equal(t, mockFailedResult.Err, result.Error())
 }
+
+func newBroadcastInvokers(ctrl *gomock.Controller, params string, errs ...error) ([]protocol.Invoker, []*common.URL) {
+	invokers := make([]protocol.Invoker, 0, len(errs))
+	urls := make([]*common.URL, 0, len(errs))
+	for i, err := range errs {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?%s", i+1, params))
+		invoker := mock.NewMockInvoker(ctrl)
+		invoker.EXPECT().GetURL().Return(url).AnyTimes()
+		var result protocol.Result = &protocol.RPCResult{Rest: clusterpkg.Rest{Tried: 0, Success: true}}
+		if err != nil {
+			result = &protocol.RPCResult{Err: err}
+		}
+		invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Return(result).MaxTimes(1)
+		invokers = append(invokers, invoker)
+		urls = append(urls, url)
+	}
+	return invokers, urls
+}
+
+func TestBroadcastInvokeFailPercent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := errors.New("just failed")
+	invokers, urls := newBroadcastInvokers(ctrl, constant.BroadcastFailPercentKey+"=50",
+		nil, failed, nil, failed)
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Nil(t, result.Error())
+	statuses := result.Attachment(constant.BroadcastResultsKey, nil).(map[string]error)
+	assert.Len(t, statuses, 4)
+	assert.Nil(t, statuses[urls[0].Location])
+	assert.Equal(t, failed, statuses[urls[1].Location])
+	assert.Nil(t, statuses[urls[2].Location])
+	assert.Equal(t, failed, statuses[urls[3].Location])
+}
+
+func TestBroadcastInvokeFailPercentExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := errors.New("just failed")
+	invokers, _ := newBroadcastInvokers(ctrl, constant.BroadcastFailPercentKey+"=25",
+		nil, failed, failed, nil)
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, failed, result.Error())
+	statuses := result.Attachment(constant.BroadcastResultsKey, nil).(map[string]error)
+	assert.Len(t, statuses, 4)
+}
+
+func TestBroadcastInvokeFailFast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := errors.New("just failed")
+	invokers, urls := newBroadcastInvokers(ctrl, constant.BroadcastFailFastKey+"=true",
+		nil, failed, nil, nil)
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, failed, result.Error())
+	statuses := result.Attachment(constant.BroadcastResultsKey, nil).(map[string]error)
+	assert.Len(t, statuses, 2)
+	assert.Nil(t, statuses[urls[0].Location])
+	assert.Equal(t, failed, statuses[urls[1].Location])
+}
+
+func TestBroadcastInvokeContinueOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := errors.New("just failed")
+	invokers, urls := newBroadcastInvokers(ctrl, constant.BroadcastFailFastKey+"=false",
+		nil, failed, nil, nil)
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, failed, result.Error())
+	statuses := result.Attachment(constant.BroadcastResultsKey, nil).(map[string]error)
+	assert.Len(t, statuses, 4)
+	assert.Nil(t, statuses[urls[3].Location])
+}
+
+func TestBroadcastInvokeParallel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failed := errors.New("just failed")
+	invokers, urls := newBroadcastInvokers(ctrl,
+		constant.BroadcastParallelismKey+"=3&"+constant.BroadcastFailPercentKey+"=40",
+		nil, failed, nil, failed, nil)
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Nil(t, result.Error())
+	statuses := result.Attachment(constant.BroadcastResultsKey, nil).(map[string]error)
+	assert.Len(t, statuses, 5)
+	assert.Equal(t, failed, statuses[urls[3].Location])
+}
+
+func TestBroadcastInvokeParallelReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokers := make([]protocol.Invoker, 0, 4)
+	for i := 0; i < 4; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?%s=4",
+			i+1, constant.BroadcastParallelismKey))
+		invoker := mock.NewMockInvoker(ctrl)
+		invoker.EXPECT().GetURL().Return(url).AnyTimes()
+		invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, inv protocol.Invocation) protocol.Result {
+				// the invokers write the attachments and the reply of their invocations concurrently
+				inv.SetAttachment("location", url.Location)
+				reply := inv.Reply().(*string)
+				*reply = url.Location
+				return &protocol.RPCResult{Rest: reply}
+			})
+		invokers = append(invokers, invoker)
+	}
+	clusterInvoker := newBroadcastCluster().Join(static.NewDirectory(invokers))
+
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithReply(&reply))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	assert.Nil(t, result.Error())
+	assert.Equal(t, "192.168.1.4:20000", reply)
+	assert.Equal(t, &reply, result.Result())
+	assert.Nil(t, inv.GetAttachmentInterface("location"))
+}
@@ -19,7 +19,6 @@ package failback
 
 import (
 	"context"
-	"strconv"
 	"sync"
 	"time"
 )
@@ -35,6 +34,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -49,7 +49,8 @@ type failbackClusterInvoker struct {
 	base.BaseClusterInvoker
 
 	once          sync.Once
-	ticker        *time.Ticker
+	destroyOnce   sync.Once
+	done          chan struct{}
 	maxRetries    int64
 	failbackTasks int64
 	taskList      *queue.Queue
@@ -58,11 +59,12 @@ type failbackClusterInvoker struct {
 func newFailbackClusterInvoker(directory directory.Directory) protocol.Invoker {
 	invoker := &failbackClusterInvoker{
 		BaseClusterInvoker: base.NewBaseClusterInvoker(directory),
+		done:               make(chan struct{}),
 	}
-	retriesConfig := invoker.GetURL().GetParam(constant.RetriesKey, constant.DefaultFailbackTimes)
-	retries, err := strconv.Atoi(retriesConfig)
+	retries, err := invoker.GetURL().GetParamInt64Strict(constant.RetriesKey, constant.DefaultFailbackTimesInt)
 	if err != nil || retries < 0 {
-		logger.Error("Your retries config is invalid,pls do a check. And will use the default fail back times configuration instead.")
+		logger.Errorf("The retries of %s are invalid, use the default fail back times %d instead, err: %v",
+			invoker.GetURL().Path, constant.DefaultFailbackTimesInt, err)
 		retries = constant.DefaultFailbackTimesInt
 	}
 
@@ -70,7 +72,7 @@ func newFailbackClusterInvoker(directory directory.Directory) protocol.Invoker {
 	if failbackTasksConfig <= 0 {
 		failbackTasksConfig = constant.DefaultFailbackTasks
 	}
-	invoker.maxRetries = int64(retries)
+	invoker.maxRetries = retries
 	invoker.failbackTasks = failbackTasksConfig
 	return invoker
 }
@@ -87,9 +89,16 @@ func (invoker *failbackClusterInvoker) tryTimerTaskProc(ctx context.Context, ret
 	}
 }
 
+// process retries the failed invocations every second until the invoker is destroyed
 func (invoker *failbackClusterInvoker) process(ctx context.Context) {
-	invoker.ticker = time.NewTicker(time.Second * 1)
-	for range invoker.ticker.C {
+	ticker := time.NewTicker(time.Second * 1)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-invoker.done:
+			return
+		}
 		// check each timeout task and re-run
 		for {
 			value, err := invoker.taskList.Peek()
@@ -110,7 +119,9 @@ func (invoker *failbackClusterInvoker) process(ctx context.Context) {
 				logger.Warnf("get task found err: %v\n", err)
 				break
 			}
-			go invoker.tryTimerTaskProc(ctx, retryTask)
+			diagnostics.Go("failback-retry", invoker.GetURL().ServiceKey(), func() {
+				invoker.tryTimerTaskProc(ctx, retryTask)
+			})
 		}
 	}
 }
@@ -120,7 +131,11 @@ func (invoker *failbackClusterInvoker) checkRetry(retryTask *retryTimerTask, err
 		retryTask.invocation.MethodName(), invoker.GetURL().Service(), err.Error())
 	retryTask.retries++
 	retryTask.lastT = time.Now()
-	if retryTask.retries > invoker.maxRetries {
+	maxRetries := invoker.GetURL().GetMethodParamInt(retryTask.invocation.MethodName(), constant.RetriesKey, invoker.maxRetries)
+	if maxRetries < 0 {
+		maxRetries = invoker.maxRetries
+	}
+	if retryTask.retries > maxRetries {
 		logger.Errorf("Failed retry times exceed threshold (%v), We have to abandon, invocation-> %v.\n",
 			retryTask.retries, retryTask.invocation)
 		return
@@ -157,7 +172,9 @@ func (invoker *failbackClusterInvoker) Invoke(ctx context.Context, invocation pr
 	if result.Error() != nil {
 		invoker.once.Do(func() {
 			invoker.taskList = queue.New(invoker.failbackTasks)
-			go invoker.process(ctx)
+			diagnostics.Go("failback-retry", invoker.GetURL().ServiceKey(), func() {
+				invoker.process(ctx)
+			})
 		})
 
 		taskLen := invoker.taskList.Len()
@@ -180,11 +197,15 @@ func (invoker *failbackClusterInvoker) Invoke(ctx context.Context, invocation pr
 func (invoker *failbackClusterInvoker) Destroy() {
 	invoker.BaseClusterInvoker.Destroy()
 
-	// stop ticker
-	if invoker.ticker != nil {
-		invoker.ticker.Stop()
-	}
-
+	invoker.destroyOnce.Do(func() {
+		// stop the retrying goroutine, which blocks on the ticker forever otherwise
+		close(invoker.done)
+	})
+	// the task list is created by the first failed invocation, and the retrying goroutine isn't started after it's
+	// destroyed
+	invoker.once.Do(func() {
+		invoker.taskList = queue.New(invoker.failbackTasks)
+	})
 	_ = invoker.taskList.Dispose()
 }
 
@@ -39,6 +39,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
@@ -251,3 +252,31 @@ func TestFailbackOutOfLimit(t *testing.T) {
 	invoker.EXPECT().Destroy().Return()
 	clusterInvoker.Destroy()
 }
+
+// the retrying goroutine exits once the invoker is destroyed
+func TestFailbackDestroy(t *testing.T) {
+	defer diagnostics.Enable()()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invoker := mock.NewMockInvoker(ctrl)
+	invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+	invoker.EXPECT().Destroy().Return().AnyTimes()
+	// the invoker never invoked is destroyed too
+	registerFailback(invoker).Destroy()
+
+	clusterInvoker := registerFailback(invoker).(*failbackClusterInvoker)
+	invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Return(&protocol.RPCResult{Err: perrors.New("error")}).Times(2)
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Nil(t, result.Error())
+	alive := diagnostics.Alive()
+	assert.Len(t, alive, 1)
+	assert.Equal(t, "failback-retry", alive[0].Component)
+
+	clusterInvoker.Destroy()
+	assert.Empty(t, diagnostics.Wait(time.Second))
+	// the failed invocations aren't retried after the invoker is destroyed
+	result = clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Nil(t, result.Error())
+	assert.Empty(t, diagnostics.Alive())
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 )
 
 import (
@@ -30,15 +31,21 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 type failoverClusterInvoker struct {
 	base.BaseClusterInvoker
+
+	budgetLock sync.Mutex
+	budget     *retryBudget
 }
 
 func newFailoverClusterInvoker(directory directory.Directory) protocol.Invoker {
@@ -53,6 +60,8 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 		invoked   []protocol.Invoker
 		providers []string
 		ivk       protocol.Invoker
+		// shortCircuits is the number of requests short-circuited by open circuit breakers
+		shortCircuits int
 	)
 
 	invokers := invoker.Directory.List(invocation)
@@ -63,11 +72,28 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 	methodName := invocation.ActualMethodName()
 	retries := getRetries(invokers, methodName)
 	loadBalance := base.GetLoadBalance(invokers[0], methodName)
+	budget := invoker.getRetryBudget(invokers[0].GetURL())
+	policy := newRetryPolicy(invokers[0].GetURL(), methodName)
 
 	for i := 0; i <= retries; i++ {
 		// Reselect before retry to avoid a change of candidate `invokers`.
 		// NOTE: if `invokers` changed, then `invoked` also lose accuracy.
 		if i > 0 {
+			if result != nil && !policy.shouldRetry(result.Error()) {
+				logger.Debugf("The error of the method %s is not retryable: %v", methodName, result.Error())
+				return result
+			}
+			if budget != nil && result != nil && !circuitbreaker.IsCircuitOpen(result.Error()) && !budget.withdraw() {
+				logger.Warnf("The retry budget of the service %s is exhausted, give up retrying the method %s.",
+					invoker.GetURL().Service(), methodName)
+				metrics.Publish(rpc.NewRetrySuppressedEvent(ivk, invocation))
+				return result
+			}
+			if result == nil || !circuitbreaker.IsCircuitOpen(result.Error()) {
+				if err := policy.wait(ctx, i); err != nil {
+					return &protocol.RPCResult{Err: err}
+				}
+			}
 			if err := invoker.CheckWhetherDestroyed(); err != nil {
 				return &protocol.RPCResult{Err: err}
 			}
@@ -86,8 +112,16 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 		result = ivk.Invoke(ctx, invocation)
 		if result.Error() != nil {
 			providers = append(providers, ivk.GetURL().Key())
+			// the request never reached the provider, try another invoker without counting it as a retry
+			if circuitbreaker.IsCircuitOpen(result.Error()) && shortCircuits < len(invokers) {
+				shortCircuits++
+				i--
+			}
 			continue
 		}
+		if i == 0 && budget != nil {
+			budget.deposit()
+		}
 		return result
 	}
 	ip := common.GetLocalIp()
@@ -96,8 +130,8 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 	if ivk == nil {
 		logger.Errorf("Failed to invoke the method %s of the service %s .No provider is available.", methodName, invokerSvc)
 		return &protocol.RPCResult{
-			Err: perrors.Errorf("Failed to invoke the method %s of the service %s .No provider is available because can't connect server.",
-				methodName, invokerSvc),
+			Err: common.NewRPCError(common.StatusNoProvider, perrors.Errorf("Failed to invoke the method %s of the service %s .No provider is available because can't connect server.",
+				methodName, invokerSvc)),
 		}
 	}
 
@@ -110,12 +144,30 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 	}
 }
 
+// getRetryBudget returns the retry budget of the reference, or nil if retry.budget is not configured
+func (invoker *failoverClusterInvoker) getRetryBudget(url *common.URL) *retryBudget {
+	percent := getRetryBudgetPercent(url)
+	if percent <= 0 {
+		return nil
+	}
+
+	invoker.budgetLock.Lock()
+	defer invoker.budgetLock.Unlock()
+	if invoker.budget == nil || invoker.budget.percent != percent {
+		invoker.budget = newRetryBudget(percent)
+	}
+	return invoker.budget
+}
+
 func getRetries(invokers []protocol.Invoker, methodName string) int {
 	if len(invokers) <= 0 {
 		return constant.DefaultRetriesInt
 	}
 
 	url := invokers[0].GetURL()
+	if !isRetryable(url, methodName) {
+		return 0
+	}
 	// get reties
 	retriesConfig := url.GetParam(constant.RetriesKey, constant.DefaultRetries)
 	// Get the service method loadbalance config if have
@@ -125,7 +177,8 @@ func getRetries(invokers []protocol.Invoker, methodName string) int {
 
 	retries, err := strconv.Atoi(retriesConfig)
 	if err != nil || retries < 0 {
-		logger.Error("Your retries config is invalid,pls do a check. And will use the default retries configuration instead.")
+		logger.Errorf("The retries %s of the method %s of %s are invalid, use the default retries %d instead",
+			retriesConfig, methodName, url.Path, constant.DefaultRetriesInt)
 		retries = constant.DefaultRetriesInt
 	}
 
@@ -22,13 +22,17 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 )
 
 import (
+	perrors "github.com/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
 	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
@@ -115,3 +119,283 @@ func TestFailoverDestroy(t *testing.T) {
 	clusterInvoker.Destroy()
 	assert.Equal(t, false, clusterInvoker.IsAvailable())
 }
+
+func TestFailoverRetryBudget(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	failoverCluster := newFailoverCluster()
+
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "2")
+	urlParams.Set(constant.RetryBudgetKey, "20%")
+	var invokers []protocol.Invoker
+	for i := 0; i < 10; i++ {
+		u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		// never succeed
+		invokers = append(invokers, clusterpkg.NewMockInvoker(u, 1<<30))
+	}
+	clusterInvoker := failoverCluster.Join(static.NewDirectory(invokers))
+	defer func() {
+		clusterpkg.Count = 0
+	}()
+
+	// the initial tokens allow DefaultRetryBudgetMaxTokens retries
+	for i := 0; i < constant.DefaultRetryBudgetMaxTokens/2; i++ {
+		clusterpkg.Count = 0
+		result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+		assert.Error(t, result.Error())
+		assert.Equal(t, 3, clusterpkg.Count)
+	}
+
+	// the budget is exhausted, the original error is returned without retrying
+	clusterpkg.Count = 0
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, "error", result.Error().Error())
+	assert.Equal(t, 1, clusterpkg.Count)
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(20)
+	for i := 0; i < constant.DefaultRetryBudgetMaxTokens; i++ {
+		assert.True(t, budget.withdraw())
+	}
+	assert.False(t, budget.withdraw())
+
+	// five successful first attempts earn one retry
+	for i := 0; i < 4; i++ {
+		budget.deposit()
+		assert.False(t, budget.withdraw())
+	}
+	budget.deposit()
+	assert.True(t, budget.withdraw())
+	assert.False(t, budget.withdraw())
+
+	// tokens never exceed the max
+	for i := 0; i < 1000; i++ {
+		budget.deposit()
+	}
+	for i := 0; i < constant.DefaultRetryBudgetMaxTokens; i++ {
+		assert.True(t, budget.withdraw())
+	}
+	assert.False(t, budget.withdraw())
+}
+
+func TestGetRetryBudgetPercent(t *testing.T) {
+	u, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider")
+	assert.Equal(t, 0, getRetryBudgetPercent(u))
+	u.SetParam(constant.RetryBudgetKey, "20")
+	assert.Equal(t, 20, getRetryBudgetPercent(u))
+	u.SetParam(constant.RetryBudgetKey, "30%")
+	assert.Equal(t, 30, getRetryBudgetPercent(u))
+	u.SetParam(constant.RetryBudgetKey, "abc")
+	assert.Equal(t, 0, getRetryBudgetPercent(u))
+}
+
+type resultInvoker struct {
+	*protocol.BaseInvoker
+	err     error
+	invoked int
+}
+
+func (ivk *resultInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	return &protocol.RPCResult{Err: ivk.err}
+}
+
+func TestFailoverSkipShortCircuited(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	failoverCluster := newFailoverCluster()
+
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "0")
+	var invokers []protocol.Invoker
+	var open []*resultInvoker
+	for i := 0; i < 3; i++ {
+		u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		ivk := &resultInvoker{BaseInvoker: protocol.NewBaseInvoker(u)}
+		if i < 2 {
+			ivk.err = perrors.Wrap(circuitbreaker.ErrCircuitOpen, "short-circuited")
+			open = append(open, ivk)
+		}
+		invokers = append(invokers, ivk)
+	}
+	clusterInvoker := failoverCluster.Join(static.NewDirectory(invokers))
+
+	// retries=0, but the short-circuited invokers are not counted as retries
+	for i := 0; i < 10; i++ {
+		result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+		assert.NoError(t, result.Error())
+	}
+	assert.Equal(t, 10, invokers[2].(*resultInvoker).invoked)
+
+	// all invokers are short-circuited
+	invokers[2].(*resultInvoker).err = open[0].err
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.True(t, circuitbreaker.IsCircuitOpen(result.Error()))
+}
+
+func newResultInvokers(n int, urlParams url.Values, err error) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, n)
+	for i := 0; i < n; i++ {
+		u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		invokers = append(invokers, &resultInvoker{BaseInvoker: protocol.NewBaseInvoker(u), err: err})
+	}
+	return invokers
+}
+
+func countInvoked(invokers []protocol.Invoker) int {
+	count := 0
+	for _, ivk := range invokers {
+		count += ivk.(*resultInvoker).invoked
+	}
+	return count
+}
+
+func TestFailoverMethodRetries(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "3")
+	urlParams.Set("methods.once."+constant.RetriesKey, "1")
+	urlParams.Set("methods.failfast."+constant.RetryableKey, "false")
+	invokers := newResultInvokers(5, urlParams, perrors.New("error"))
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("sayHello", nil, nil))
+	assert.Equal(t, 4, countInvoked(invokers))
+
+	invokers = newResultInvokers(5, urlParams, perrors.New("error"))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("once", nil, nil))
+	assert.Equal(t, 2, countInvoked(invokers))
+
+	invokers = newResultInvokers(5, urlParams, perrors.New("error"))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("failfast", nil, nil))
+	assert.Error(t, result.Error())
+	assert.Equal(t, 1, countInvoked(invokers))
+}
+
+func TestFailoverWildcardMethodRetries(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "3")
+	urlParams.Set("methods.get*."+constant.RetriesKey, "1")
+	urlParams.Set("methods.getAll."+constant.RetriesKey, "0")
+
+	for method, invoked := range map[string]int{"sayHello": 4, "getUser": 2, "getAll": 1} {
+		invokers := newResultInvokers(5, urlParams, perrors.New("error"))
+		clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+		clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation(method, nil, nil))
+		assert.Equal(t, invoked, countInvoked(invokers), method)
+	}
+}
+
+func TestFailoverRetryOn(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "2")
+	urlParams.Set(constant.RetryOnKey, "notsent,timeout")
+	urlParams.Set("methods.create."+constant.RetryOnKey, "notsent")
+
+	// the error raised by the provider is not retried
+	invokers := newResultInvokers(3, urlParams, common.NewRPCError(common.StatusBiz, perrors.New("biz error")))
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("query", nil, nil))
+	assert.Error(t, result.Error())
+	assert.Equal(t, 1, countInvoked(invokers))
+
+	invokers = newResultInvokers(3, urlParams, common.NewRPCError(common.StatusTimeout, perrors.New("timeout")))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("query", nil, nil))
+	assert.Equal(t, 3, countInvoked(invokers))
+
+	// the method level classification overrides the service level one
+	invokers = newResultInvokers(3, urlParams, common.NewRPCError(common.StatusTimeout, perrors.New("timeout")))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("create", nil, nil))
+	assert.Equal(t, 1, countInvoked(invokers))
+
+	invokers = newResultInvokers(3, urlParams, common.NewRPCError(common.StatusNotSent, perrors.New("connect refused")))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("create", nil, nil))
+	assert.Equal(t, 3, countInvoked(invokers))
+}
+
+func TestFailoverNotRetryThrottled(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "2")
+	invokers := newResultInvokers(3, urlParams, common.NewRPCError(common.StatusThrottled, perrors.New("blocked")))
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("query", nil, nil))
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(result.Error()))
+	assert.Equal(t, 1, countInvoked(invokers))
+}
+
+func TestFailoverNotRetryBiz(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "2")
+	invokers := newResultInvokers(3, urlParams, common.NewRPCError(common.StatusBiz, perrors.New("biz error")))
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("query", nil, nil))
+	assert.Equal(t, common.StatusBiz, common.CodeOf(result.Error()))
+	assert.Equal(t, 1, countInvoked(invokers))
+
+	// the errors never reaching a provider are retried
+	invokers = newResultInvokers(3, urlParams, common.NewRPCError(common.StatusNotSent, perrors.New("connect refused")))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("query", nil, nil))
+	assert.Equal(t, 3, countInvoked(invokers))
+}
+
+func TestFailoverRetryBackoff(t *testing.T) {
+	extension.SetLoadbalance("random", random.NewRandomLoadBalance)
+	urlParams := url.Values{}
+	urlParams.Set(constant.RetriesKey, "2")
+	urlParams.Set(constant.RetryBackoffKey, "20ms")
+	urlParams.Set(constant.RetryBackoffMultiplierKey, "2")
+	invokers := newResultInvokers(3, urlParams, perrors.New("error"))
+	clusterInvoker := newFailoverCluster().Join(static.NewDirectory(invokers))
+
+	start := time.Now()
+	clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("sayHello", nil, nil))
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+	assert.Equal(t, 3, countInvoked(invokers))
+
+	// the backoff is interrupted by the context
+	invokers = newResultInvokers(3, urlParams, perrors.New("error"))
+	clusterInvoker = newFailoverCluster().Join(static.NewDirectory(invokers))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result := clusterInvoker.Invoke(ctx, invocation.NewRPCInvocation("sayHello", nil, nil))
+	assert.ErrorIs(t, result.Error(), context.DeadlineExceeded)
+	assert.Equal(t, 1, countInvoked(invokers))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	u, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?" +
+		"retry.backoff=50ms&retry.backoff.multiplier=3&methods.slow.retry.backoff=1s&retry.on=notsent,bad")
+	policy := newRetryPolicy(u, "sayHello")
+	assert.Equal(t, time.Duration(0), policy.backoffOf(0))
+	assert.Equal(t, 50*time.Millisecond, policy.backoffOf(1))
+	assert.Equal(t, 150*time.Millisecond, policy.backoffOf(2))
+	assert.Equal(t, 450*time.Millisecond, policy.backoffOf(3))
+	assert.True(t, policy.shouldRetry(protocol.ErrClientClosed))
+	assert.True(t, policy.shouldRetry(perrors.Wrap(circuitbreaker.ErrCircuitOpen, "short-circuited")))
+	assert.False(t, policy.shouldRetry(perrors.New("error")))
+
+	policy = newRetryPolicy(u, "slow")
+	assert.Equal(t, time.Second, policy.backoffOf(1))
+
+	u, _ = common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?retry.backoff=-1s&retry.backoff.multiplier=0.5")
+	policy = newRetryPolicy(u, "sayHello")
+	assert.Equal(t, time.Duration(0), policy.backoffOf(1))
+	assert.Equal(t, constant.DefaultRetryBackoffMultiplier, policy.multiplier)
+	assert.True(t, policy.shouldRetry(perrors.New("error")))
+	assert.False(t, policy.shouldRetry(common.NewRPCError(common.StatusThrottled, perrors.New("blocked"))))
+	assert.False(t, policy.shouldRetry(common.NewRPCError(common.StatusPermissionDenied, perrors.New("denied"))))
+
+	u, _ = common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?retry.on=throttled")
+	policy = newRetryPolicy(u, "sayHello")
+	assert.True(t, policy.shouldRetry(common.NewRPCError(common.StatusThrottled, perrors.New("blocked"))))
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package failover
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// retryBudget is a token bucket limiting the retries of a reference. Every successful first attempt
+// deposits percent/100 tokens and every retry withdraws one token, so with a budget of 20% there is
+// at most one retry per five successful first attempts once the initial tokens are used up.
+type retryBudget struct {
+	mu        sync.Mutex
+	percent   int
+	ratio     float64
+	tokens    float64
+	maxTokens float64
+}
+
+func newRetryBudget(percent int) *retryBudget {
+	return &retryBudget{
+		percent:   percent,
+		ratio:     float64(percent) / 100,
+		tokens:    constant.DefaultRetryBudgetMaxTokens,
+		maxTokens: constant.DefaultRetryBudgetMaxTokens,
+	}
+}
+
+// deposit is called after a successful first attempt
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// withdraw takes one token for a retry, it returns false if the budget is exhausted
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// getRetryBudgetPercent parses retry.budget, both "20" and "20%" are accepted.
+// It returns 0, which disables the budget, if the param is absent or invalid.
+func getRetryBudgetPercent(url *common.URL) int {
+	v := strings.TrimSpace(url.GetParam(constant.RetryBudgetKey, ""))
+	if len(v) == 0 {
+		return 0
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(v, "%"))
+	if err != nil || percent < 0 {
+		logger.Warnf("Your retry budget config %s is invalid, the retry budget is disabled.", v)
+		return 0
+	}
+	return percent
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package failover
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// retryPolicy decides whether and when a failed request of a method is retried.
+// Every param can be configured on the method level, which overrides the service level one.
+type retryPolicy struct {
+	// backoff is the wait before the first retry, it is multiplied by multiplier for each further retry
+	backoff    time.Duration
+	multiplier float64
+	// retryOn is the error codes worth a retry, nil means the ones of common.IsRetriable are retried
+	retryOn map[common.RPCStatus]struct{}
+}
+
+func newRetryPolicy(url *common.URL, methodName string) *retryPolicy {
+	policy := &retryPolicy{multiplier: constant.DefaultRetryBackoffMultiplier}
+
+	if v := getMethodParam(url, methodName, constant.RetryBackoffKey); len(v) != 0 {
+		backoff, err := common.ParseDuration(v)
+		if err != nil || backoff < 0 {
+			logger.Warnf("Your retry backoff config %s of the method %s is invalid, the backoff is disabled.", v, methodName)
+		} else {
+			policy.backoff = backoff
+		}
+	}
+	if v := getMethodParam(url, methodName, constant.RetryBackoffMultiplierKey); len(v) != 0 {
+		multiplier, err := strconv.ParseFloat(v, 64)
+		if err != nil || multiplier < 1 {
+			logger.Warnf("Your retry backoff multiplier config %s of the method %s is invalid, %v is used instead.",
+				v, methodName, constant.DefaultRetryBackoffMultiplier)
+		} else {
+			policy.multiplier = multiplier
+		}
+	}
+	if v := getMethodParam(url, methodName, constant.RetryOnKey); len(v) != 0 {
+		policy.retryOn = make(map[common.RPCStatus]struct{})
+		for _, name := range strings.Split(v, ",") {
+			code, ok := common.ParseRPCStatus(name)
+			if !ok {
+				logger.Warnf("Unknown error code %s in the retry.on config of the method %s, it is ignored.", name, methodName)
+				continue
+			}
+			policy.retryOn[code] = struct{}{}
+		}
+	}
+	return policy
+}
+
+// getMethodParam returns the method level param of @key, or the service level one if absent
+func getMethodParam(url *common.URL, methodName, key string) string {
+	return strings.TrimSpace(url.GetMethodParam(methodName, key, url.GetParam(key, "")))
+}
+
+// isRetryable returns false if retryable=false is configured, which makes the method fail fast
+func isRetryable(url *common.URL, methodName string) bool {
+	return url.GetMethodParamBool(methodName, constant.RetryableKey, url.GetParamBool(constant.RetryableKey, true))
+}
+
+// shouldRetry returns whether the request failed with @err is worth a retry
+func (p *retryPolicy) shouldRetry(err error) bool {
+	code := common.CodeOf(err)
+	if code == common.StatusCircuitOpen {
+		// a short-circuited request never reached the provider, it is always safe to try another one
+		return true
+	}
+	if p.retryOn == nil {
+		return common.IsRetriable(err)
+	}
+	_, ok := p.retryOn[code]
+	return ok
+}
+
+// backoffOf returns the wait before the @retry th retry, which starts from 1
+func (p *retryPolicy) backoffOf(retry int) time.Duration {
+	if p.backoff <= 0 || retry <= 0 {
+		return 0
+	}
+	backoff := float64(p.backoff) * math.Pow(p.multiplier, float64(retry-1))
+	if backoff > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(backoff)
+}
+
+// wait blocks for the backoff of the @retry th retry, it returns the error of @ctx if it is done earlier
+func (p *retryPolicy) wait(ctx context.Context, retry int) error {
+	backoff := p.backoffOf(retry)
+	if backoff <= 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -28,8 +28,6 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
-	"dubbo.apache.org/dubbo-go/v3/common/constant"
-	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -53,27 +51,23 @@ func newFailsafeClusterInvoker(directory directory.Directory) protocol.Invoker {
 func (invoker *failsafeClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
 	invokers := invoker.Directory.List(invocation)
 
-	err := invoker.CheckInvokers(invokers, invocation)
-	if err != nil {
+	if err := invoker.CheckInvokers(invokers, invocation); err != nil {
+		logger.Errorf("Failsafe ignore exception: %v.\n", err.Error())
 		return &protocol.RPCResult{}
 	}
-
-	url := invokers[0].GetURL()
-	methodName := invocation.MethodName()
-	// Get the service loadbalance config
-	lb := url.GetParam(constant.LoadbalanceKey, constant.DefaultLoadBalance)
-	// Get the service method loadbalance config if have
-	if v := url.GetMethodParam(methodName, constant.LoadbalanceKey, ""); v != "" {
-		lb = v
+	if err := invoker.CheckWhetherDestroyed(); err != nil {
+		logger.Errorf("Failsafe ignore exception: %v.\n", err.Error())
+		return &protocol.RPCResult{}
 	}
-	loadbalance := extension.GetLoadbalance(lb)
-
-	invoked := make([]protocol.Invoker, 0)
-	var result protocol.Result
 
-	ivk := invoker.DoSelect(loadbalance, invocation, invokers, invoked)
+	loadbalance := base.GetLoadBalance(invokers[0], invocation.MethodName())
+	ivk := invoker.DoSelect(loadbalance, invocation, invokers, nil)
+	if ivk == nil {
+		logger.Errorf("Failsafe ignore exception: no provider of the service %v is available.\n", invoker.GetURL().Service())
+		return &protocol.RPCResult{}
+	}
 	// DO INVOKE
-	result = ivk.Invoke(ctx, invocation)
+	result := ivk.Invoke(ctx, invocation)
 	if result.Error() != nil {
 		// ignore
 		logger.Errorf("Failsafe ignore exception: %v.\n", result.Error().Error())
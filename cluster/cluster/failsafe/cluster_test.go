@@ -101,3 +101,26 @@ func TestFailSafeInvokeFail(t *testing.T) {
 	assert.NoError(t, result.Error())
 	assert.Nil(t, result.Result())
 }
+
+func TestFailSafeInvokeEmptyDirectory(t *testing.T) {
+	clusterInvoker := newFailsafeCluster().Join(static.NewDirectory(nil))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NoError(t, result.Error())
+	assert.Nil(t, result.Result())
+}
+
+func TestFailSafeInvokeNoAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invoker := mock.NewMockInvoker(ctrl)
+	invoker.EXPECT().GetURL().Return(failsafeUrl).AnyTimes()
+	invoker.EXPECT().IsAvailable().Return(false).AnyTimes()
+	invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Times(0)
+	clusterInvoker := newFailsafeCluster().Join(static.NewDirectory([]protocol.Invoker{invoker}))
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NoError(t, result.Error())
+	assert.Nil(t, result.Result())
+}
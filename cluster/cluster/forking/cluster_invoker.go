@@ -19,19 +19,18 @@ package forking
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 	"time"
 )
 
 import (
-	"github.com/Workiva/go-datastructures/queue"
-
-	"github.com/dubbogo/gost/log/logger"
+	perrors "github.com/pkg/errors"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -46,6 +45,12 @@ func newForkingClusterInvoker(directory directory.Directory) protocol.Invoker {
 	}
 }
 
+// Invoke invokes the selected invokers in parallel and returns the first successful result.
+// The last error is returned only when all forks fail, and a timeout error when none of them
+// answers in time. The context handed to the forks is cancelled as soon as Invoke returns,
+// so the losers can give up early, and their results are dropped into a buffered channel
+// which nobody reads any more, so they never block or leak. Each fork invokes its own copy of the
+// invocation, and only the reply of the winner is copied back.
 func (invoker *forkingClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
 	if err := invoker.CheckWhetherDestroyed(); err != nil {
 		return &protocol.RPCResult{Err: err}
@@ -56,44 +61,109 @@ func (invoker *forkingClusterInvoker) Invoke(ctx context.Context, invocation pro
 		return &protocol.RPCResult{Err: err}
 	}
 
-	var selected []protocol.Invoker
-	forks := invoker.GetURL().GetParamByIntValue(constant.ForksKey, constant.DefaultForks)
-	timeouts := invoker.GetURL().GetParamInt(constant.TimeoutKey, constant.DefaultTimeout)
-	if forks < 0 || forks > len(invokers) {
-		selected = invokers
-	} else {
-		loadBalance := base.GetLoadBalance(invokers[0], invocation.ActualMethodName())
-		for i := 0; i < forks; i++ {
-			if ivk := invoker.DoSelect(loadBalance, invocation, invokers, selected); ivk != nil {
-				selected = append(selected, ivk)
-			}
+	url := invoker.GetURL()
+	methodName := invocation.ActualMethodName()
+	forks := url.GetParamByIntValue(constant.ForksKey, constant.DefaultForks)
+	timeout := url.GetMethodParamDuration(methodName, constant.TimeoutKey,
+		url.GetParamDurationWithDefault(constant.TimeoutKey, strconv.Itoa(constant.DefaultTimeout)))
+
+	selected := invoker.selectForks(invokers, invocation, forks)
+	if len(selected) == 0 {
+		return &protocol.RPCResult{
+			Err: common.NewRPCError(common.StatusNoProvider,
+				perrors.Errorf("failed to forking invoke the method %s, no provider is available", methodName)),
 		}
 	}
 
-	resultQ := queue.New(1)
+	forkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the channel is buffered with enough room for every fork, so that a late fork never blocks
+	resultCh := make(chan forkResult, len(selected))
 	for _, ivk := range selected {
-		go func(k protocol.Invoker) {
-			result := k.Invoke(ctx, invocation)
-			if err := resultQ.Put(result); err != nil {
-				logger.Errorf("resultQ put failed with exception: %v.\n", err)
-			}
-		}(ivk)
+		inv := invocation
+		if len(selected) > 1 {
+			// the forks write the attachments and decode the replies of their own invocations, and the losers may
+			// still be decoding after the winner returns
+			inv = base.CopyInvocation(invocation)
+		}
+		go func(k protocol.Invoker, inv protocol.Invocation) {
+			resultCh <- forkResult{invocation: inv, result: k.Invoke(forkCtx, inv)}
+		}(ivk, inv)
 	}
 
-	rsps, err := resultQ.Poll(1, time.Millisecond*time.Duration(timeouts))
-	if err != nil {
-		return &protocol.RPCResult{
-			Err: fmt.Errorf("failed to forking invoke provider %v, "+
-				"but no luck to perform the invocation. Last error is: %v", selected, err),
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var lastErr error
+	for failed := 0; failed < len(selected); {
+		select {
+		case fork := <-resultCh:
+			result := fork.result
+			if result == nil {
+				failed++
+				lastErr = perrors.Errorf("failed to forking invoke provider %v, but no resp", selected)
+				continue
+			}
+			if result.Error() == nil {
+				if fork.invocation != invocation {
+					base.CopyReply(invocation, fork.invocation, result)
+				}
+				return result
+			}
+			failed++
+			lastErr = result.Error()
+		case <-timer.C:
+			return &protocol.RPCResult{
+				Err: common.NewRPCError(common.StatusTimeout,
+					perrors.Errorf("failed to forking invoke provider %v, timeout after %s", selected, timeout)),
+			}
+		case <-ctx.Done():
+			return &protocol.RPCResult{
+				Err: perrors.Wrapf(ctx.Err(), "failed to forking invoke provider %v", selected),
+			}
 		}
 	}
-	if len(rsps) == 0 {
-		return &protocol.RPCResult{Err: fmt.Errorf("failed to forking invoke provider %v, but no resp", selected)}
+
+	return &protocol.RPCResult{
+		Err: perrors.Wrapf(lastErr, "failed to forking invoke provider %v, "+
+			"but no luck to perform the invocation", selected),
+	}
+}
+
+// forkResult is the result of a fork and the invocation it invoked
+type forkResult struct {
+	invocation protocol.Invocation
+	result     protocol.Result
+}
+
+// selectForks selects at most forks distinct invokers by the load balance. All invokers are
+// returned if forks is negative or not less than the number of invokers.
+func (invoker *forkingClusterInvoker) selectForks(invokers []protocol.Invoker, invocation protocol.Invocation,
+	forks int) []protocol.Invoker {
+	if forks < 0 || forks >= len(invokers) {
+		return invokers
+	}
+
+	selected := make([]protocol.Invoker, 0, forks)
+	loadBalance := base.GetLoadBalance(invokers[0], invocation.ActualMethodName())
+	// the load balance falls back to an already selected invoker when reselecting fails,
+	// so loop a bit more than forks times and skip the duplicates
+	for i := 0; i < len(invokers) && len(selected) < forks; i++ {
+		ivk := invoker.DoSelect(loadBalance, invocation, invokers, selected)
+		if ivk == nil || isSelected(ivk, selected) {
+			continue
+		}
+		selected = append(selected, ivk)
 	}
+	return selected
+}
 
-	result, ok := rsps[0].(protocol.Result)
-	if !ok {
-		return &protocol.RPCResult{Err: fmt.Errorf("failed to forking invoke provider %v, but not legal resp", selected)}
+func isSelected(ivk protocol.Invoker, selected []protocol.Invoker) bool {
+	for _, s := range selected {
+		if s == ivk {
+			return true
+		}
 	}
-	return result
+	return false
 }
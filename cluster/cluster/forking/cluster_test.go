@@ -19,6 +19,7 @@ package forking
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -160,3 +161,144 @@ func TestForkingInvokeHalfTimeout(t *testing.T) {
 	assert.Equal(t, mockResult, result)
 	wg.Wait()
 }
+
+func TestForkingInvokeFailedFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokers := make([]*mock.MockInvoker, 0)
+
+	mockResult := &protocol.RPCResult{Rest: clusterpkg.Rest{Tried: 0, Success: true}}
+	mockFailedResult := &protocol.RPCResult{Err: errors.New("fast failure")}
+	forkingUrl.AddParam(constant.ForksKey, strconv.Itoa(2))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		invokers = append(invokers, invoker)
+		invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+		if i == 0 {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, protocol.Invocation) protocol.Result {
+					wg.Done()
+					return mockFailedResult
+				})
+		} else {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, protocol.Invocation) protocol.Result {
+					time.Sleep(100 * time.Millisecond)
+					wg.Done()
+					return mockResult
+				})
+		}
+	}
+
+	clusterInvoker := registerForking(invokers...)
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, mockResult, result)
+	wg.Wait()
+}
+
+func TestForkingInvokeAllFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokers := make([]*mock.MockInvoker, 0)
+
+	forkingUrl.AddParam(constant.ForksKey, strconv.Itoa(2))
+
+	for i := 0; i < 2; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		invokers = append(invokers, invoker)
+		invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+		err := fmt.Errorf("failure %d", i)
+		invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Return(&protocol.RPCResult{Err: err})
+	}
+
+	clusterInvoker := registerForking(invokers...)
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NotNil(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "failure")
+}
+
+func TestForkingInvokeLoserCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokers := make([]*mock.MockInvoker, 0)
+
+	mockResult := &protocol.RPCResult{Rest: clusterpkg.Rest{Tried: 0, Success: true}}
+	forkingUrl.AddParam(constant.ForksKey, strconv.Itoa(2))
+
+	cancelled := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		invokers = append(invokers, invoker)
+		invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+		if i == 0 {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).Return(mockResult)
+		} else {
+			invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, _ protocol.Invocation) protocol.Result {
+					<-ctx.Done()
+					close(cancelled)
+					return &protocol.RPCResult{Err: ctx.Err()}
+				})
+		}
+	}
+
+	clusterInvoker := registerForking(invokers...)
+
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.Equal(t, mockResult, result)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the slow fork is not cancelled after the winner returns")
+	}
+}
+
+func TestForkingInvokeReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokers := make([]*mock.MockInvoker, 0)
+	forkingUrl.AddParam(constant.ForksKey, strconv.Itoa(3))
+
+	var losers sync.WaitGroup
+	losers.Add(2)
+	release := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		invoker := mock.NewMockInvoker(ctrl)
+		invokers = append(invokers, invoker)
+		invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+		location := fmt.Sprintf("192.168.1.%d:20000", i+1)
+		winner := i == 0
+		invoker.EXPECT().Invoke(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, inv protocol.Invocation) protocol.Result {
+				if !winner {
+					defer losers.Done()
+					// the losers still decode their replies after the winner returns
+					<-release
+				}
+				inv.SetAttachment("location", location)
+				reply := inv.Reply().(*string)
+				*reply = location
+				return &protocol.RPCResult{Rest: reply}
+			})
+	}
+	clusterInvoker := registerForking(invokers...)
+
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithReply(&reply))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	close(release)
+	losers.Wait()
+	assert.Nil(t, result.Error())
+	assert.Equal(t, "192.168.1.1:20000", reply)
+	assert.Equal(t, &reply, result.Result())
+	assert.Nil(t, inv.GetAttachmentInterface("location"))
+}
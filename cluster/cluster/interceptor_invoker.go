@@ -23,6 +23,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -82,6 +83,14 @@ func (i *InterceptorInvoker) Invoke(ctx context.Context, invocation protocol.Inv
 	return i.interceptor.Invoke(ctx, i.next, invocation)
 }
 
+// GetDirectory returns the directory of the next invoker, nil if the next invoker is not built on a directory
+func (i *InterceptorInvoker) GetDirectory() directory.Directory {
+	if holder, ok := i.next.(directory.Holder); ok {
+		return holder.GetDirectory()
+	}
+	return nil
+}
+
 // Destroy will destroy invoker
 func (i *InterceptorInvoker) Destroy() {
 	i.next.Destroy()
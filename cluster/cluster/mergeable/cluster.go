@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mergeable
+
+import (
+	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/merger/builtin"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	extension.SetCluster(constant.ClusterKeyMergeable, newMergeableCluster)
+}
+
+type mergeableCluster struct{}
+
+// newMergeableCluster returns a mergeableCluster instance.
+//
+// Calling one provider of each group in parallel and merging their results.
+// It is used by the references with group=* or several groups, e.g. a service sharded by group.
+func newMergeableCluster() clusterpkg.Cluster {
+	return &mergeableCluster{}
+}
+
+// Join returns a mergeableClusterInvoker instance
+func (cluster *mergeableCluster) Join(directory directory.Directory) protocol.Invoker {
+	return clusterpkg.BuildInterceptorChain(newMergeableClusterInvoker(directory))
+}
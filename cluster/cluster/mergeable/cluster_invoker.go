@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mergeable
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var hashSetType = reflect.TypeOf(&gxset.HashSet{})
+
+type mergeableClusterInvoker struct {
+	base.BaseClusterInvoker
+}
+
+func newMergeableClusterInvoker(directory directory.Directory) protocol.Invoker {
+	return &mergeableClusterInvoker{
+		BaseClusterInvoker: base.NewBaseClusterInvoker(directory),
+	}
+}
+
+// groupResult is the result of a group
+type groupResult struct {
+	group  string
+	result protocol.Result
+	// value is the reply of the group, or the result itself without reply
+	value interface{}
+}
+
+// Invoke calls one invoker of each group in parallel and merges their results.
+//
+// The merger is chosen by the merger param of the method or the service: empty, true or default
+// chooses a built-in merger by the type of the result, false calls only one group, and others
+// name the merger extension. The failed groups are skipped unless merger.fail.any is true.
+func (invoker *mergeableClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	invokers := invoker.Directory.List(invocation)
+	if err := invoker.CheckInvokers(invokers, invocation); err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+	if err := invoker.CheckWhetherDestroyed(); err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+
+	url := invokers[0].GetURL()
+	methodName := invocation.MethodName()
+	mergerName := url.GetMethodParam(methodName, constant.MergerKey, url.GetParam(constant.MergerKey, ""))
+	groups, groupInvokers := groupByGroup(invokers)
+
+	if mergerName == "false" {
+		// opt out of merging, pick any available group
+		var selected protocol.Invoker
+		for _, group := range groups {
+			if ivk := invoker.selectInvoker(invocation, groupInvokers[group]); ivk != nil {
+				selected = ivk
+				if ivk.IsAvailable() {
+					break
+				}
+			}
+		}
+		if selected == nil {
+			return &protocol.RPCResult{Err: common.NewRPCError(common.StatusNoProvider, perrors.Errorf("no invoker is selected for the method %s of the service %s",
+				methodName, url.Service()))}
+		}
+		return selected.Invoke(ctx, invocation)
+	}
+
+	results := make([]*groupResult, len(groups))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		wg.Add(1)
+		go func(i int, group string) {
+			defer wg.Done()
+			groupInvocation := base.CopyInvocation(invocation)
+			ivk := invoker.selectInvoker(groupInvocation, groupInvokers[group])
+			if ivk == nil {
+				results[i] = &groupResult{group: group, result: &protocol.RPCResult{
+					Err: common.NewRPCError(common.StatusNoProvider, perrors.Errorf("no invoker is selected in the group %s", group))}}
+				return
+			}
+			result := ivk.Invoke(ctx, groupInvocation)
+			value := result.Result()
+			if reply, ok := base.ReplyOf(groupInvocation); ok {
+				value = reply.Elem().Interface()
+			}
+			results[i] = &groupResult{group: group, result: result, value: value}
+		}(i, group)
+	}
+	wg.Wait()
+
+	failAny := url.GetParamBool(constant.MergerFailAnyKey, false)
+	values := make([]interface{}, 0, len(results))
+	var (
+		failedGroups []string
+		lastErr      error
+	)
+	for _, r := range results {
+		if err := r.result.Error(); err != nil {
+			logger.Errorf("Failed to invoke the method %s of the group %s of the service %s, error: %v",
+				methodName, r.group, url.Service(), err)
+			failedGroups = append(failedGroups, r.group)
+			lastErr = err
+			continue
+		}
+		values = append(values, r.value)
+	}
+	if len(values) == 0 || (failAny && len(failedGroups) > 0) {
+		return &protocol.RPCResult{
+			Err: perrors.Wrapf(lastErr, "failed to invoke the method %s of the groups [%s] of the service %s",
+				methodName, strings.Join(failedGroups, ","), url.Service()),
+		}
+	}
+
+	merged, err := mergeValues(mergerName, values)
+	if err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+	if reply, ok := base.ReplyOf(invocation); ok && merged != nil {
+		mergedValue := reflect.ValueOf(merged)
+		if !mergedValue.Type().AssignableTo(reply.Elem().Type()) {
+			return &protocol.RPCResult{
+				Err: perrors.Errorf("the merged result %T can not be assigned to the reply %s", merged, reply.Type()),
+			}
+		}
+		reply.Elem().Set(mergedValue)
+		return &protocol.RPCResult{Rest: reply.Interface()}
+	}
+	return &protocol.RPCResult{Rest: merged}
+}
+
+// selectInvoker selects one invoker of a group, the invoker of a group is usually a cluster invoker already
+func (invoker *mergeableClusterInvoker) selectInvoker(invocation protocol.Invocation, invokers []protocol.Invoker) protocol.Invoker {
+	if len(invokers) == 1 {
+		return invokers[0]
+	}
+	lb := base.GetLoadBalance(invokers[0], invocation.ActualMethodName())
+	return invoker.DoSelect(lb, invocation, invokers, nil)
+}
+
+// groupByGroup groups @invokers by their group, the groups are sorted to make the merge order stable
+func groupByGroup(invokers []protocol.Invoker) ([]string, map[string][]protocol.Invoker) {
+	groupInvokers := make(map[string][]protocol.Invoker)
+	for _, ivk := range invokers {
+		group := ivk.GetURL().GetParam(constant.GroupKey, "")
+		groupInvokers[group] = append(groupInvokers[group], ivk)
+	}
+	groups := make([]string, 0, len(groupInvokers))
+	for group := range groupInvokers {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups, groupInvokers
+}
+
+// mergeValues merges @values with the merger named @mergerName, or the built-in merger of their type
+func mergeValues(mergerName string, values []interface{}) (interface{}, error) {
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	var (
+		m   merger.Merger
+		err error
+	)
+	switch mergerName {
+	case "", "true", constant.DefaultKey:
+		m, err = getMergerByType(values)
+	default:
+		m, err = extension.GetMerger(mergerName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.Merge(values)
+}
+
+// getMergerByType returns the built-in merger of the type of @values
+func getMergerByType(values []interface{}) (merger.Merger, error) {
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		t := reflect.TypeOf(v)
+		switch {
+		case t == hashSetType:
+			return extension.GetMerger(constant.SetMergerName)
+		case t.Kind() == reflect.Slice:
+			return extension.GetMerger(constant.SliceMergerName)
+		case t.Kind() == reflect.Map:
+			return extension.GetMerger(constant.MapMergerName)
+		default:
+			return nil, perrors.Errorf("there is no merger for the type %s, please specify one by the merger param", t)
+		}
+	}
+	return nil, perrors.New("there is no merger for the nil results")
+}
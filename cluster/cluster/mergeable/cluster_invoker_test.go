@@ -0,0 +1,233 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mergeable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// groupInvoker replies the value of its group
+type groupInvoker struct {
+	*protocol.BaseInvoker
+	reply   func(group string) interface{}
+	err     error
+	invoked int
+}
+
+func (ivk *groupInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	if ivk.err != nil {
+		return &protocol.RPCResult{Err: ivk.err}
+	}
+	value := ivk.reply(ivk.GetURL().GetParam(constant.GroupKey, ""))
+	reflect.ValueOf(inv.Reply()).Elem().Set(reflect.ValueOf(value))
+	return &protocol.RPCResult{Rest: inv.Reply()}
+}
+
+func newGroupInvokers(params string, reply func(group string) interface{}, groups ...string) []*groupInvoker {
+	invokers := make([]*groupInvoker, 0, len(groups))
+	for i, group := range groups {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?group=%s&%s",
+			i, group, params))
+		invokers = append(invokers, &groupInvoker{BaseInvoker: protocol.NewBaseInvoker(url), reply: reply})
+	}
+	return invokers
+}
+
+// groupDirectory lists the invokers of all groups like the registry directory of a group=* reference
+type groupDirectory struct {
+	base.Directory
+	invokers []protocol.Invoker
+}
+
+func (dir *groupDirectory) List(_ protocol.Invocation) []protocol.Invoker {
+	return dir.invokers
+}
+
+func (dir *groupDirectory) Destroy() {}
+
+func join(invokers []*groupInvoker) protocol.Invoker {
+	extension.SetLoadbalance(constant.LoadBalanceKeyRandom, random.NewRandomLoadBalance)
+	list := make([]protocol.Invoker, 0, len(invokers))
+	for _, ivk := range invokers {
+		list = append(list, ivk)
+	}
+	url, _ := common.NewURL("consumer://192.168.1.100/com.ikurento.user.UserProvider?group=*")
+	return newMergeableCluster().Join(&groupDirectory{Directory: base.NewDirectory(url), invokers: list})
+}
+
+func TestMergeSlice(t *testing.T) {
+	invokers := newGroupInvokers("", func(group string) interface{} {
+		return []string{group + "-1", group + "-2"}
+	}, "g2", "g1", "g3")
+
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"g1-1", "g1-2", "g2-1", "g2-2", "g3-1", "g3-2"}, reply)
+	assert.Equal(t, &reply, result.Result())
+}
+
+func TestMergeMap(t *testing.T) {
+	invokers := newGroupInvokers("", func(group string) interface{} {
+		return map[string]int{group: len(group), "shared": 1}
+	}, "g1", "g22")
+
+	reply := map[string]int{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUserMap"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, map[string]int{"g1": 2, "g22": 3, "shared": 1}, reply)
+}
+
+func TestMergeSet(t *testing.T) {
+	invokers := newGroupInvokers("", func(group string) interface{} {
+		return gxset.NewSet(group, "shared")
+	}, "g1", "g2")
+
+	var reply *gxset.HashSet
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUserSet"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	values := make([]string, 0)
+	for _, v := range reply.Values() {
+		values = append(values, v.(string))
+	}
+	sort.Strings(values)
+	assert.Equal(t, []string{"g1", "g2", "shared"}, values)
+}
+
+type joinMerger struct{}
+
+func (m *joinMerger) Merge(results []interface{}) (interface{}, error) {
+	merged := ""
+	for _, r := range results {
+		merged += r.(string)
+	}
+	return merged, nil
+}
+
+func TestNamedMerger(t *testing.T) {
+	extension.SetMerger("join", func() merger.Merger {
+		return &joinMerger{}
+	})
+	invokers := newGroupInvokers("merger=join", func(group string) interface{} {
+		return group
+	}, "g1", "g2")
+
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "g1g2", reply)
+
+	// no built-in merger for string
+	invokers = newGroupInvokers("", func(group string) interface{} {
+		return group
+	}, "g1", "g2")
+	result = join(invokers).Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+
+	// unknown merger
+	invokers = newGroupInvokers("merger=unknown", func(group string) interface{} {
+		return group
+	}, "g1", "g2")
+	result = join(invokers).Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+}
+
+func TestMergerOptOut(t *testing.T) {
+	invokers := newGroupInvokers("methods.GetUsers.merger=false", func(group string) interface{} {
+		return []string{group}
+	}, "g1", "g2")
+
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Len(t, reply, 1)
+	assert.Equal(t, 1, invokers[0].invoked+invokers[1].invoked)
+}
+
+func TestMergeFailedGroups(t *testing.T) {
+	reply := func(group string) interface{} {
+		return []string{group}
+	}
+	invokers := newGroupInvokers("", reply, "g1", "g2", "g3")
+	invokers[1].err = errors.New("g2 failed")
+
+	// merge the successes by default
+	var users []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&users))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"g1", "g3"}, users)
+
+	// fail if any group fails
+	invokers = newGroupInvokers("merger.fail.any=true", reply, "g1", "g2", "g3")
+	invokers[1].err = errors.New("g2 failed")
+	users = nil
+	result = join(invokers).Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "g2")
+	assert.Nil(t, users)
+
+	// all groups fail
+	invokers = newGroupInvokers("", reply, "g1", "g2")
+	invokers[0].err = errors.New("g1 failed")
+	invokers[1].err = errors.New("g2 failed")
+	result = join(invokers).Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+}
+
+func TestMergeSameGroup(t *testing.T) {
+	// the invokers of the same group are load balanced rather than merged
+	invokers := newGroupInvokers("", func(group string) interface{} {
+		return []string{group}
+	}, "g1", "g1", "g2")
+
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := join(invokers).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"g1", "g2"}, reply)
+	assert.Equal(t, 1, invokers[0].invoked+invokers[1].invoked)
+}
@@ -0,0 +1,317 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// maxTrackedProviders bounds the providers tracked by a reference, the counters of the providers beyond it
+	// are folded into the evicted counters
+	maxTrackedProviders = 1024
+	// availableSamples is the number of the available invoker count samples kept by a reference
+	availableSamples = 60
+	// availableSampleInterval is the minimum interval between two samples of the available invoker count
+	availableSampleInterval = time.Second
+	// recentDecisions is the number of the last selection decisions kept by a reference
+	recentDecisions = 16
+)
+
+// ProviderStats is the selection statistics of a provider
+type ProviderStats struct {
+	Address    string `json:"address"`
+	Selections int64  `json:"selections"`
+	Retries    int64  `json:"retries"`
+	StickyHits int64  `json:"stickyHits"`
+	Ejections  int64  `json:"ejections"`
+}
+
+// AvailableSample is the available invoker count of a reference at a point of time
+type AvailableSample struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// SelectionDecision is a provider selected by a reference
+type SelectionDecision struct {
+	Time    time.Time `json:"time"`
+	Method  string    `json:"method"`
+	Address string    `json:"address"`
+	// Reason is how the provider is selected, sticky or loadbalance
+	Reason string `json:"reason"`
+	Retry  bool   `json:"retry"`
+	// Candidates is the number of the providers the provider is selected from
+	Candidates int `json:"candidates"`
+
+	seq uint64
+}
+
+// SelectionStats is a snapshot of the selection statistics of a reference
+type SelectionStats struct {
+	RefKey     string `json:"refKey"`
+	Selections int64  `json:"selections"`
+	Retries    int64  `json:"retries"`
+	StickyHits int64  `json:"stickyHits"`
+	Ejections  int64  `json:"ejections"`
+	// Providers is sorted by address, the providers which are gone are not included but counted in the totals
+	Providers []ProviderStats `json:"providers"`
+	// Available is sorted by time, the oldest first
+	Available []AvailableSample `json:"available"`
+	// Decisions are the last selection decisions, the oldest first
+	Decisions []SelectionDecision `json:"decisions"`
+}
+
+// providerCounters are updated without locks. As each provider owns its counters, the contention is spread
+// over the providers instead of a single hot counter of the reference.
+type providerCounters struct {
+	selections atomic.Int64
+	retries    atomic.Int64
+	stickyHits atomic.Int64
+	ejections  atomic.Int64
+}
+
+type refStats struct {
+	providers sync.Map // address -> *providerCounters
+	size      atomic.Int32
+	// evicted holds the counters of the providers which are gone
+	evicted providerCounters
+
+	lastSample atomic.Int64 // unix nano of the last sample
+	sampleLock sync.Mutex
+	samples    [availableSamples]AvailableSample
+	next       int
+
+	// decisions is a ring written without locks, the slot of a decision is picked by its sequence
+	decisionSeq atomic.Uint64
+	decisions   [recentDecisions]atomic.Value // *SelectionDecision
+}
+
+var refs sync.Map // refKey -> *refStats
+
+// RefKey returns the key of the selection statistics which the invoker with @url belongs to
+func RefKey(url *common.URL) string {
+	return url.ServiceKey()
+}
+
+func getRefStats(refKey string) *refStats {
+	if s, ok := refs.Load(refKey); ok {
+		return s.(*refStats)
+	}
+	s, _ := refs.LoadOrStore(refKey, &refStats{})
+	return s.(*refStats)
+}
+
+func (s *refStats) counters(address string) *providerCounters {
+	if c, ok := s.providers.Load(address); ok {
+		return c.(*providerCounters)
+	}
+	if s.size.Load() >= maxTrackedProviders {
+		return &s.evicted
+	}
+	c, loaded := s.providers.LoadOrStore(address, &providerCounters{})
+	if !loaded {
+		s.size.Inc()
+	}
+	return c.(*providerCounters)
+}
+
+// RecordSelection records that the invoker with @url is selected, @retry and @sticky tell whether it is
+// selected for a retry and whether it is the sticky invoker
+func RecordSelection(url *common.URL, retry, sticky bool) {
+	c := getRefStats(RefKey(url)).counters(url.Location)
+	c.selections.Inc()
+	if retry {
+		c.retries.Inc()
+	}
+	if sticky {
+		c.stickyHits.Inc()
+	}
+}
+
+// RecordDecision records that the invoker with @url is selected among @candidates providers for @method, and
+// keeps it in the last decisions of the reference. @reason tells how it is selected, like loadbalance or sticky.
+func RecordDecision(url *common.URL, method, reason string, retry bool, candidates int) {
+	s := getRefStats(RefKey(url))
+	seq := s.decisionSeq.Inc()
+	s.decisions[seq%recentDecisions].Store(&SelectionDecision{
+		Time:       time.Now(),
+		Method:     method,
+		Address:    url.Location,
+		Reason:     reason,
+		Retry:      retry,
+		Candidates: candidates,
+		seq:        seq,
+	})
+}
+
+// RecordEjection records that the invoker with @url is ejected
+func RecordEjection(url *common.URL) {
+	getRefStats(RefKey(url)).counters(url.Location).ejections.Inc()
+}
+
+// RecordAvailable samples the available @invokers of the reference, at most once per second. The providers
+// which are no longer available are evicted, so the statistics stay bounded when the providers churn.
+func RecordAvailable(invokers []protocol.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	now := time.Now()
+	s := getRefStats(RefKey(invokers[0].GetURL()))
+	last := s.lastSample.Load()
+	if now.UnixNano()-last < int64(availableSampleInterval) || !s.lastSample.CAS(last, now.UnixNano()) {
+		return
+	}
+
+	s.sampleLock.Lock()
+	s.samples[s.next] = AvailableSample{Time: now, Count: len(invokers)}
+	s.next = (s.next + 1) % availableSamples
+	s.sampleLock.Unlock()
+
+	current := make(map[string]struct{}, len(invokers))
+	for _, invoker := range invokers {
+		current[invoker.GetURL().Location] = struct{}{}
+	}
+	s.providers.Range(func(key, value interface{}) bool {
+		if _, ok := current[key.(string)]; ok {
+			return true
+		}
+		if _, loaded := s.providers.LoadAndDelete(key); loaded {
+			s.size.Dec()
+			c := value.(*providerCounters)
+			s.evicted.selections.Add(c.selections.Load())
+			s.evicted.retries.Add(c.retries.Load())
+			s.evicted.stickyHits.Add(c.stickyHits.Load())
+			s.evicted.ejections.Add(c.ejections.Load())
+		}
+		return true
+	})
+}
+
+// Stats returns the selection statistics of the reference with @refKey, false if nothing has been recorded
+func Stats(refKey string) (SelectionStats, bool) {
+	s, ok := refs.Load(refKey)
+	if !ok {
+		return SelectionStats{}, false
+	}
+	return s.(*refStats).snapshot(refKey), true
+}
+
+// AllStats returns the selection statistics of all references, keyed by the ref key
+func AllStats() map[string]SelectionStats {
+	all := make(map[string]SelectionStats)
+	refs.Range(func(key, value interface{}) bool {
+		all[key.(string)] = value.(*refStats).snapshot(key.(string))
+		return true
+	})
+	return all
+}
+
+// ResetStats forgets the selection statistics of all references
+func ResetStats() {
+	refs.Range(func(key, _ interface{}) bool {
+		refs.Delete(key)
+		return true
+	})
+}
+
+func (s *refStats) snapshot(refKey string) SelectionStats {
+	stats := SelectionStats{
+		RefKey:     refKey,
+		Selections: s.evicted.selections.Load(),
+		Retries:    s.evicted.retries.Load(),
+		StickyHits: s.evicted.stickyHits.Load(),
+		Ejections:  s.evicted.ejections.Load(),
+		Providers:  make([]ProviderStats, 0),
+		Available:  make([]AvailableSample, 0, availableSamples),
+		Decisions:  make([]SelectionDecision, 0, recentDecisions),
+	}
+	s.providers.Range(func(key, value interface{}) bool {
+		c := value.(*providerCounters)
+		p := ProviderStats{
+			Address:    key.(string),
+			Selections: c.selections.Load(),
+			Retries:    c.retries.Load(),
+			StickyHits: c.stickyHits.Load(),
+			Ejections:  c.ejections.Load(),
+		}
+		stats.Selections += p.Selections
+		stats.Retries += p.Retries
+		stats.StickyHits += p.StickyHits
+		stats.Ejections += p.Ejections
+		stats.Providers = append(stats.Providers, p)
+		return true
+	})
+	sort.Slice(stats.Providers, func(i, j int) bool {
+		return stats.Providers[i].Address < stats.Providers[j].Address
+	})
+
+	s.sampleLock.Lock()
+	for i := 0; i < availableSamples; i++ {
+		sample := s.samples[(s.next+i)%availableSamples]
+		if !sample.Time.IsZero() {
+			stats.Available = append(stats.Available, sample)
+		}
+	}
+	s.sampleLock.Unlock()
+
+	for i := range s.decisions {
+		if d, ok := s.decisions[i].Load().(*SelectionDecision); ok {
+			stats.Decisions = append(stats.Decisions, *d)
+		}
+	}
+	sort.Slice(stats.Decisions, func(i, j int) bool {
+		return stats.Decisions[i].seq < stats.Decisions[j].seq
+	})
+	return stats
+}
+
+// StatsHandler returns a http handler dumping the selection statistics as json, which can be mounted by an
+// admin endpoint. The statistics of a single reference is dumped if the query param ref is given.
+func StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		if refKey := r.URL.Query().Get("ref"); len(refKey) != 0 {
+			stats, ok := Stats(refKey)
+			if !ok {
+				http.Error(w, "no selection statistics of the reference "+refKey, http.StatusNotFound)
+				return
+			}
+			body = stats
+		} else {
+			body = AllStats()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func newStatsInvokers(t *testing.T, from, to int) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, to-from)
+	for i := from; i < to; i++ {
+		url, err := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider?version=1.0.0", i))
+		assert.NoError(t, err)
+		invokers = append(invokers, NewMockInvoker(url, 1))
+	}
+	return invokers
+}
+
+func TestSelectionStats(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	invokers := newStatsInvokers(t, 0, 2)
+	refKey := RefKey(invokers[0].GetURL())
+	_, ok := Stats(refKey)
+	assert.False(t, ok)
+
+	RecordAvailable(invokers)
+	RecordSelection(invokers[0].GetURL(), false, false)
+	RecordSelection(invokers[0].GetURL(), false, true)
+	RecordSelection(invokers[1].GetURL(), true, false)
+	RecordEjection(invokers[1].GetURL())
+
+	stats, ok := Stats(refKey)
+	assert.True(t, ok)
+	assert.Equal(t, refKey, stats.RefKey)
+	assert.Equal(t, int64(3), stats.Selections)
+	assert.Equal(t, int64(1), stats.Retries)
+	assert.Equal(t, int64(1), stats.StickyHits)
+	assert.Equal(t, int64(1), stats.Ejections)
+	assert.Equal(t, []ProviderStats{
+		{Address: "192.168.1.0:20000", Selections: 2, StickyHits: 1},
+		{Address: "192.168.1.1:20000", Selections: 1, Retries: 1, Ejections: 1},
+	}, stats.Providers)
+	assert.Len(t, stats.Available, 1)
+	assert.Equal(t, 2, stats.Available[0].Count)
+
+	// samples are taken at most once per interval
+	RecordAvailable(invokers[:1])
+	stats, _ = Stats(refKey)
+	assert.Len(t, stats.Available, 1)
+	assert.Len(t, AllStats(), 1)
+}
+
+func TestSelectionStatsEviction(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	invokers := newStatsInvokers(t, 0, 2)
+	refKey := RefKey(invokers[0].GetURL())
+	for _, invoker := range invokers {
+		RecordSelection(invoker.GetURL(), false, false)
+	}
+
+	// the provider which is gone is evicted, but still counted in the totals
+	s := getRefStats(refKey)
+	s.lastSample.Store(time.Now().Add(-availableSampleInterval).UnixNano())
+	RecordAvailable(invokers[1:])
+	stats, _ := Stats(refKey)
+	assert.Equal(t, int64(2), stats.Selections)
+	assert.Equal(t, []ProviderStats{{Address: "192.168.1.1:20000", Selections: 1}}, stats.Providers)
+	assert.Equal(t, int32(1), s.size.Load())
+
+	// the providers beyond the limit are only counted in the totals
+	s.size.Store(maxTrackedProviders)
+	RecordSelection(invokers[0].GetURL(), false, false)
+	stats, _ = Stats(refKey)
+	assert.Equal(t, int64(3), stats.Selections)
+	assert.Len(t, stats.Providers, 1)
+}
+
+func TestSelectionDecisions(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	invokers := newStatsInvokers(t, 0, 2)
+	RecordDecision(invokers[0].GetURL(), "GetUser", "sticky", false, 2)
+	RecordDecision(invokers[1].GetURL(), "GetUser", "loadbalance", true, 1)
+	stats, _ := Stats(RefKey(invokers[0].GetURL()))
+	assert.Len(t, stats.Decisions, 2)
+	assert.Equal(t, "192.168.1.0:20000", stats.Decisions[0].Address)
+	assert.Equal(t, "sticky", stats.Decisions[0].Reason)
+	assert.Equal(t, 2, stats.Decisions[0].Candidates)
+	assert.True(t, stats.Decisions[1].Retry)
+
+	// only the last decisions are kept, the oldest first
+	for i := 0; i < recentDecisions+3; i++ {
+		RecordDecision(invokers[i%2].GetURL(), fmt.Sprintf("m%d", i), "loadbalance", false, 2)
+	}
+	stats, _ = Stats(RefKey(invokers[0].GetURL()))
+	assert.Len(t, stats.Decisions, recentDecisions)
+	assert.Equal(t, "m3", stats.Decisions[0].Method)
+	assert.Equal(t, fmt.Sprintf("m%d", recentDecisions+2), stats.Decisions[recentDecisions-1].Method)
+}
+
+func TestStatsHandler(t *testing.T) {
+	ResetStats()
+	defer ResetStats()
+
+	invokers := newStatsInvokers(t, 0, 1)
+	refKey := RefKey(invokers[0].GetURL())
+	RecordSelection(invokers[0].GetURL(), false, false)
+
+	rec := httptest.NewRecorder()
+	StatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?ref="+refKey, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var stats SelectionStats
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, int64(1), stats.Selections)
+
+	rec = httptest.NewRecorder()
+	StatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	var all map[string]SelectionStats
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &all))
+	assert.Contains(t, all, refKey)
+
+	rec = httptest.NewRecorder()
+	StatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?ref=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
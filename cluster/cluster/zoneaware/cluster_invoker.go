@@ -25,6 +25,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -76,9 +77,9 @@ func (invoker *zoneawareClusterInvoker) Invoke(ctx context.Context, invocation p
 		force := invocation.GetAttachmentWithDefaultValue(constant.RegistryKey+"."+constant.RegistryZoneForceKey, "")
 		if "true" == force {
 			return &protocol.RPCResult{
-				Err: fmt.Errorf("no registry instance in zone or "+
+				Err: common.NewRPCError(common.StatusNoProvider, fmt.Errorf("no registry instance in zone or "+
 					"no available providers in the registry, zone: %v, "+
-					" registries: %v", zone, invoker.GetURL()),
+					" registries: %v", zone, invoker.GetURL())),
 			}
 		}
 	}
@@ -98,7 +99,7 @@ func (invoker *zoneawareClusterInvoker) Invoke(ctx context.Context, invocation p
 	}
 
 	return &protocol.RPCResult{
-		Err: fmt.Errorf("no provider available in %v", invokers),
+		Err: common.NewRPCError(common.StatusNoProvider, fmt.Errorf("no provider available in %v", invokers)),
 	}
 }
 
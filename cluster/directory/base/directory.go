@@ -72,23 +72,22 @@ func (dir *Directory) GetDirectoryUrl() *common.URL {
 	return dir.url
 }
 
-func (dir *Directory) isProperRouter(url *common.URL) bool {
+// IsProperRouter checks whether the router rule url applies to the application or the service of the directory
+func (dir *Directory) IsProperRouter(url *common.URL) bool {
 	app := url.GetParam(constant.ApplicationKey, "")
 	dirApp := dir.GetURL().GetParam(constant.ApplicationKey, "")
 	if len(dirApp) == 0 && dir.GetURL().SubURL != nil {
 		dirApp = dir.GetURL().SubURL.GetParam(constant.ApplicationKey, "")
 	}
-	serviceKey := dir.GetURL().ServiceKey()
-	if len(serviceKey) == 0 {
-		serviceKey = dir.GetURL().SubURL.ServiceKey()
+	consumerURL := dir.GetURL()
+	if len(consumerURL.ServiceKey()) == 0 {
+		consumerURL = consumerURL.SubURL
 	}
 	if len(app) > 0 && app == dirApp {
 		return true
 	}
-	if url.ServiceKey() == serviceKey {
-		return true
-	}
-	return false
+	// a router rule applies to all groups and versions of the service unless it says otherwise
+	return consumerURL != nil && common.IsRuleMatch(url, consumerURL)
 }
 
 // Destroy Destroy
@@ -70,7 +70,7 @@ func TestIsProperRouter(t *testing.T) {
 	rule := base64.URLEncoding.EncodeToString([]byte("true => " + " host = " + localIP))
 	routeURL := getRouteURL(rule, anyURL)
 	routeURL.AddParam(constant.ApplicationKey, "mock-app")
-	rst := d.isProperRouter(routeURL)
+	rst := d.IsProperRouter(routeURL)
 	assert.True(t, rst)
 
 	regURL.AddParam(constant.ApplicationKey, "")
@@ -78,14 +78,14 @@ func TestIsProperRouter(t *testing.T) {
 	d = NewDirectory(regURL)
 	routeURL = getRouteURL(rule, anyURL)
 	routeURL.AddParam(constant.InterfaceKey, "com.foo.BarService")
-	rst = d.isProperRouter(routeURL)
+	rst = d.IsProperRouter(routeURL)
 	assert.True(t, rst)
 
 	regURL.AddParam(constant.ApplicationKey, "")
 	regURL.AddParam(constant.InterfaceKey, "")
 	d = NewDirectory(regURL)
 	routeURL = getRouteURL(rule, anyURL)
-	rst = d.isProperRouter(routeURL)
+	rst = d.IsProperRouter(routeURL)
 	assert.True(t, rst)
 
 	regURL.SetParam(constant.ApplicationKey, "")
@@ -93,7 +93,7 @@ func TestIsProperRouter(t *testing.T) {
 	d = NewDirectory(regURL)
 	routeURL = getRouteURL(rule, anyURL)
 	routeURL.AddParam(constant.ApplicationKey, "mock-service")
-	rst = d.isProperRouter(routeURL)
+	rst = d.IsProperRouter(routeURL)
 	assert.False(t, rst)
 
 	regURL.SetParam(constant.ApplicationKey, "")
@@ -101,6 +101,6 @@ func TestIsProperRouter(t *testing.T) {
 	d = NewDirectory(regURL)
 	routeURL = getRouteURL(rule, anyURL)
 	routeURL.AddParam(constant.InterfaceKey, "mock-service")
-	rst = d.isProperRouter(routeURL)
+	rst = d.IsProperRouter(routeURL)
 	assert.False(t, rst)
 }
@@ -18,6 +18,7 @@
 package directory
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -33,3 +34,63 @@ type Directory interface {
 	// the return result directly.
 	List(invocation protocol.Invocation) []protocol.Invoker
 }
+
+// InvokerLister is implemented by the directories which could list all of their invokers without routing
+type InvokerLister interface {
+	// Invokers returns the invokers of the directory, which must not be modified
+	Invokers() []protocol.Invoker
+}
+
+// Holder is implemented by the invokers built on a directory, like the cluster invokers
+type Holder interface {
+	// GetDirectory returns the directory, which is nil if it has not been created yet
+	GetDirectory() Directory
+}
+
+// Inspection is a snapshot of a directory for debugging
+type Inspection struct {
+	// URL is the url the invocations are routed by
+	URL *common.URL
+	// Invokers are the invokers of the directory before routing
+	Invokers []protocol.Invoker
+	// Routes are the steps of the routers routing the inspected invocation in order, which is empty if the
+	// directory has no router chain
+	Routes []router.RouteStep
+}
+
+// Inspector is implemented by the directories which could be inspected for debugging
+type Inspector interface {
+	// Inspect snapshots the invokers of the directory and how @invocation is routed among them. It copies the state
+	// of the directory, so the invocations are not blocked.
+	Inspect(invocation protocol.Invocation) Inspection
+}
+
+// Trace traces how @invocation is routed by the @chain with @url, which is nil if the @chain isn't a router.Tracer
+func Trace(chain router.Chain, url *common.URL, invocation protocol.Invocation) []router.RouteStep {
+	if tracer, ok := chain.(router.Tracer); ok {
+		return tracer.Trace(url, invocation)
+	}
+	return nil
+}
+
+// ProviderInvokers returns the invokers of the providers behind @invoker, which is nil if @invoker is nil. The cluster
+// invokers in the directories, like the ones of the registries or the groups of a reference, are expanded into their
+// providers.
+func ProviderInvokers(invoker protocol.Invoker) []protocol.Invoker {
+	if invoker == nil {
+		return nil
+	}
+	holder, ok := invoker.(Holder)
+	if !ok {
+		return []protocol.Invoker{invoker}
+	}
+	lister, ok := holder.GetDirectory().(InvokerLister)
+	if !ok {
+		return nil
+	}
+	var providers []protocol.Invoker
+	for _, ivk := range lister.Invokers() {
+		providers = append(providers, ProviderInvokers(ivk)...)
+	}
+	return providers
+}
@@ -22,6 +22,7 @@ import (
 )
 
 import (
+	clusterdir "dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/router/chain"
 	"dubbo.apache.org/dubbo-go/v3/common"
@@ -62,6 +63,23 @@ func (dir *directory) IsAvailable() bool {
 	return true
 }
 
+// Invokers returns the invokers of the directory
+func (dir *directory) Invokers() []protocol.Invoker {
+	return dir.invokers
+}
+
+// Inspect snapshots the invokers and how @invocation is routed among them
+func (dir *directory) Inspect(invocation protocol.Invocation) clusterdir.Inspection {
+	inspection := clusterdir.Inspection{
+		URL:      dir.GetURL(),
+		Invokers: append([]protocol.Invoker(nil), dir.invokers...),
+	}
+	if routerChain := dir.RouterChain(); routerChain != nil {
+		inspection.Routes = clusterdir.Trace(routerChain, inspection.URL, invocation)
+	}
+	return inspection
+}
+
 // List List invokers
 func (dir *directory) List(invocation protocol.Invocation) []protocol.Invoker {
 	l := len(dir.invokers)
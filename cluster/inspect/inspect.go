@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package inspect snapshots the providers of a reference, how they are routed and how they are selected, which
+// helps to debug the routing of the running consumers.
+package inspect
+
+import (
+	"sort"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/cluster/router/healthcheck"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// Provider is a provider of a reference
+type Provider struct {
+	Address string `json:"address"`
+	// URL is the url of the provider with the credentials redacted
+	URL       string `json:"url"`
+	Weight    int64  `json:"weight"`
+	Available bool   `json:"available"`
+	// Routed is whether the provider remains after all the routers
+	Routed bool `json:"routed"`
+	// Ejected is whether the provider is ejected by the outlier detection
+	Ejected bool `json:"ejected"`
+	// Circuits are the states of the circuit breakers of the provider, keyed by method
+	Circuits   map[string]string `json:"circuits,omitempty"`
+	Selections int64             `json:"selections"`
+}
+
+// Route is how a router routes the inspected invocation
+type Route struct {
+	Router string `json:"router"`
+	// Filtered are the addresses of the invokers filtered out by the router
+	Filtered []string `json:"filtered"`
+	// Remained is the number of the invokers remained after the router
+	Remained int `json:"remained"`
+}
+
+// Snapshot is the state of a reference at a point of time
+type Snapshot struct {
+	ServiceKey string `json:"serviceKey"`
+	// URL is the url the invocations are routed by, with the credentials redacted
+	URL string `json:"url"`
+	// Method is the method the invocation is routed for, which is empty for all methods
+	Method string `json:"method,omitempty"`
+	// Providers is sorted by address
+	Providers []Provider `json:"providers"`
+	// Routes are the routers in order, the routers of the directories of all the registries and the groups of the
+	// reference are included
+	Routes []Route `json:"routes"`
+	// Decisions are the last selection decisions, the oldest first
+	Decisions []cluster.SelectionDecision `json:"decisions"`
+}
+
+// Inspect snapshots the reference @invoker of the service @serviceKey, and routes an invocation of @method, which
+// could be empty, through its routers. The state is copied from the directories and the statistics without blocking
+// the invocations.
+func Inspect(serviceKey string, invoker protocol.Invoker, method string) Snapshot {
+	snapshot := Snapshot{
+		ServiceKey: serviceKey,
+		Method:     method,
+		Providers:  make([]Provider, 0),
+		Routes:     make([]Route, 0),
+		Decisions:  make([]cluster.SelectionDecision, 0),
+	}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName(method))
+	routed := make(map[protocol.Invoker]struct{})
+	var providers []protocol.Invoker
+	var walk func(invoker protocol.Invoker)
+	walk = func(invoker protocol.Invoker) {
+		holder, ok := invoker.(directory.Holder)
+		if !ok {
+			providers = append(providers, invoker)
+			return
+		}
+		inspector, ok := holder.GetDirectory().(directory.Inspector)
+		if !ok {
+			return
+		}
+		inspection := inspector.Inspect(inv)
+		if snapshot.URL == "" && inspection.URL != nil {
+			snapshot.URL = inspection.URL.Redacted()
+		}
+		remained := inspection.Invokers
+		for _, step := range inspection.Routes {
+			route := Route{Router: step.Router, Filtered: make([]string, 0, len(step.Filtered)), Remained: len(step.Routed)}
+			for _, filtered := range step.Filtered {
+				route.Filtered = append(route.Filtered, filtered.GetURL().Location)
+			}
+			snapshot.Routes = append(snapshot.Routes, route)
+			remained = step.Routed
+		}
+		for _, ivk := range remained {
+			routed[ivk] = struct{}{}
+		}
+		for _, ivk := range inspection.Invokers {
+			walk(ivk)
+		}
+	}
+	walk(invoker)
+
+	refKey := serviceKey
+	if len(providers) > 0 {
+		refKey = cluster.RefKey(providers[0].GetURL())
+	}
+	selections := make(map[string]int64)
+	if stats, ok := cluster.Stats(refKey); ok {
+		for _, p := range stats.Providers {
+			selections[p.Address] = p.Selections
+		}
+		snapshot.Decisions = stats.Decisions
+	}
+
+	detector := healthcheck.GetOutlierDetector()
+	for _, ivk := range providers {
+		url := ivk.GetURL()
+		_, isRouted := routed[ivk]
+		provider := Provider{
+			Address: url.Location,
+			URL:     url.Redacted(),
+			Weight: url.GetMethodParamInt(method, constant.WeightKey,
+				url.GetParamInt(constant.WeightKey, constant.DefaultWeight)),
+			Available:  ivk.IsAvailable(),
+			Routed:     isRouted,
+			Ejected:    detector.IsEjected(url),
+			Selections: selections[url.Location],
+		}
+		for m, state := range circuitbreaker.ProviderStates(url) {
+			if method != "" && m != method {
+				continue
+			}
+			if provider.Circuits == nil {
+				provider.Circuits = make(map[string]string)
+			}
+			provider.Circuits[m] = state.String()
+		}
+		snapshot.Providers = append(snapshot.Providers, provider)
+	}
+	sort.Slice(snapshot.Providers, func(i, j int) bool {
+		return snapshot.Providers[i].Address < snapshot.Providers[j].Address
+	})
+	return snapshot
+}
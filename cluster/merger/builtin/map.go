@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builtin
+
+import (
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(constant.MapMergerName, newMapMerger)
+}
+
+// mapMerger puts all entries into one map, the latter result wins on duplicated keys.
+// A map with empty values like map[string]struct{} is merged as a set.
+type mapMerger struct{}
+
+func newMapMerger() merger.Merger {
+	return &mapMerger{}
+}
+
+// Merge merges @results, which must be maps of the same type
+func (m *mapMerger) Merge(results []interface{}) (interface{}, error) {
+	var merged reflect.Value
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		v := reflect.ValueOf(result)
+		if v.Kind() != reflect.Map {
+			return nil, perrors.Errorf("map merger can not merge %T", result)
+		}
+		if !merged.IsValid() {
+			merged = reflect.MakeMapWithSize(v.Type(), v.Len())
+		} else if v.Type() != merged.Type() {
+			return nil, perrors.Errorf("map merger can not merge %T into %s", result, merged.Type())
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	if !merged.IsValid() {
+		return nil, nil
+	}
+	return merged.Interface(), nil
+}
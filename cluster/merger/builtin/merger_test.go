@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builtin
+
+import (
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSliceMerger(t *testing.T) {
+	m := newSliceMerger()
+	merged, err := m.Merge([]interface{}{[]int{1, 2}, nil, []int{}, []int{3}})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, merged)
+
+	_, err = m.Merge([]interface{}{[]int{1}, []string{"a"}})
+	assert.NotNil(t, err)
+	_, err = m.Merge([]interface{}{1})
+	assert.NotNil(t, err)
+}
+
+func TestMapMerger(t *testing.T) {
+	m := newMapMerger()
+	merged, err := m.Merge([]interface{}{map[string]int{"a": 1, "b": 1}, map[string]int{"b": 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, merged)
+
+	merged, err = m.Merge([]interface{}{map[string]struct{}{"a": {}}, map[string]struct{}{"b": {}}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, merged)
+
+	_, err = m.Merge([]interface{}{map[string]int{}, map[int]int{}})
+	assert.NotNil(t, err)
+}
+
+func TestSetMerger(t *testing.T) {
+	m := newSetMerger()
+	merged, err := m.Merge([]interface{}{gxset.NewSet(1, 2), gxset.NewSet(2, 3)})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, merged.(*gxset.HashSet).Size())
+	assert.True(t, merged.(*gxset.HashSet).Contains(1, 2, 3))
+
+	_, err = m.Merge([]interface{}{[]int{1}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builtin
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(constant.SetMergerName, newSetMerger)
+}
+
+// setMerger unions the *gxset.HashSet results
+type setMerger struct{}
+
+func newSetMerger() merger.Merger {
+	return &setMerger{}
+}
+
+// Merge unions @results, which must be *gxset.HashSet
+func (m *setMerger) Merge(results []interface{}) (interface{}, error) {
+	merged := gxset.NewSet()
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		set, ok := result.(*gxset.HashSet)
+		if !ok {
+			return nil, perrors.Errorf("set merger can not merge %T", result)
+		}
+		merged.Add(set.Values()...)
+	}
+	return merged, nil
+}
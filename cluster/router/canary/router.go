@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// buckets is the number of buckets the requests are divided into, so the percentage has 2 decimals
+const buckets = 10000
+
+// PriorityRouter sends a percentage of the requests of a service to the canary providers
+type PriorityRouter struct {
+	rules sync.Map
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+}
+
+func NewCanaryPriorityRouter() (*PriorityRouter, error) {
+	return &PriorityRouter{}, nil
+}
+
+// Route returns the canary providers for percentage of the requests and the stable providers for the others.
+// If the chosen providers are absent, all providers are returned.
+func (p *PriorityRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	value, ok := p.rules.Load(config_center.ServiceRuleKey(invokers[0].GetURL(), constant.CanaryRouterRuleSuffix))
+	if !ok {
+		return invokers
+	}
+	rule := value.(*Rule)
+	if !rule.Enabled {
+		return invokers
+	}
+
+	canary := make([]protocol.Invoker, 0)
+	stable := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if isCanary(invoker.GetURL(), rule) {
+			canary = append(canary, invoker)
+		} else {
+			stable = append(stable, invoker)
+		}
+	}
+
+	result := stable
+	if pickCanary(rule, invocation) {
+		result = canary
+	}
+	if len(result) == 0 {
+		return invokers
+	}
+	return result
+}
+
+func (p *PriorityRouter) URL() *common.URL {
+	return nil
+}
+
+func (p *PriorityRouter) Priority() int64 {
+	return 150
+}
+
+// Notify subscribes the canary rule of the service of the invokers
+func (p *PriorityRouter) Notify(invokers []protocol.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
+		return
+	}
+	p.subscriptions.Subscribe(dynamicConfiguration, config_center.ServiceRuleKey(invokers[0].GetURL(), constant.CanaryRouterRuleSuffix), p, 0)
+}
+
+// Process updates the canary rule, a deleted or malformed rule stops the canary
+func (p *PriorityRouter) Process(event *config_center.ConfigChangeEvent) {
+	if event.ConfigType == remoting.EventTypeDel {
+		p.rules.Delete(event.Key)
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok || len(content) == 0 {
+		p.rules.Delete(event.Key)
+		return
+	}
+	rule, err := parseRule(content)
+	if err != nil {
+		p.rules.Delete(event.Key)
+		logger.Warnf("[canary router]Parse canary rule error, %+v and we will stop the canary.", err)
+		return
+	}
+	p.rules.Store(event.Key, rule)
+	logger.Infof("[canary router]Parse canary rule success,rule=%+v", rule)
+}
+
+// isCanary returns whether the provider with @url matches all labels of the rule
+func isCanary(url *common.URL, rule *Rule) bool {
+	for k, v := range rule.Match {
+		if url.GetParam(k, "") != v {
+			return false
+		}
+	}
+	return true
+}
+
+// pickCanary returns whether the request goes to the canary providers
+func pickCanary(rule *Rule, invocation protocol.Invocation) bool {
+	threshold := int(rule.Percentage * buckets / 100)
+	if threshold <= 0 {
+		return false
+	}
+	if threshold >= buckets {
+		return true
+	}
+	if rule.Mode == ModeHash {
+		return hashBucket(rule, invocation) < threshold
+	}
+	return rand.Intn(buckets) < threshold
+}
+
+// hashBucket returns the bucket of the request by the hash of the attachment hashKey, or the method and arguments
+func hashBucket(rule *Rule, invocation protocol.Invocation) int {
+	h := fnv.New32a()
+	if value, ok := invocation.GetAttachment(rule.HashKey); rule.HashKey != "" && ok {
+		_, _ = h.Write([]byte(value))
+	} else {
+		_, _ = h.Write([]byte(invocation.MethodName()))
+		for _, arg := range invocation.Arguments() {
+			_, _ = h.Write([]byte(fmt.Sprint(arg)))
+		}
+	}
+	return int(h.Sum32() % buckets)
+}
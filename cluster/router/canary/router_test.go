@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	ruleKeyOfUserProvider = "com.xxx.xxx.UserProvider:3.1.0:.canary-router"
+	providerFormat        = "dubbo://192.168.0.%d:20000/com.xxx.xxx.UserProvider?interface=com.xxx.xxx.UserProvider&version=3.1.0"
+)
+
+func newInvokers() (stable, canary []protocol.Invoker) {
+	for i := 1; i <= 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf(providerFormat, i))
+		stable = append(stable, protocol.NewBaseInvoker(url))
+	}
+	url, _ := common.NewURL(fmt.Sprintf(providerFormat, 4) + "&canary=true")
+	canary = append(canary, protocol.NewBaseInvoker(url))
+	return
+}
+
+func newRouterWithRule(t *testing.T, rule string) *PriorityRouter {
+	p, err := NewCanaryPriorityRouter()
+	assert.Nil(t, err)
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider, Value: rule, ConfigType: remoting.EventTypeAdd})
+	return p
+}
+
+func route(p *PriorityRouter, invokers []protocol.Invoker, times int, inv func(i int) protocol.Invocation) (canaryCount int) {
+	consumerURL, _ := common.NewURL("consumer://127.0.0.1/com.xxx.xxx.UserProvider?version=3.1.0")
+	for i := 0; i < times; i++ {
+		result := p.Route(invokers, consumerURL, inv(i))
+		if len(result) == 1 && result[0].GetURL().GetParam("canary", "") == "true" {
+			canaryCount++
+		}
+	}
+	return
+}
+
+func randomInvocation(int) protocol.Invocation {
+	return invocation.NewRPCInvocation("GetUser", nil, nil)
+}
+
+func TestParseRule(t *testing.T) {
+	rule, err := parseRule(`
+key: com.xxx.xxx.UserProvider
+percentage: 5.5
+match:
+  canary: "true"`)
+	assert.Nil(t, err)
+	assert.True(t, rule.Enabled)
+	assert.Equal(t, 5.5, rule.Percentage)
+	assert.Equal(t, ModeRandom, rule.Mode)
+	assert.Equal(t, map[string]string{"canary": "true"}, rule.Match)
+
+	for _, illegal := range []string{
+		"percentage: 5",
+		"percentage: 101\nmatch:\n  canary: \"true\"",
+		"percentage: 5\nmode: unknown\nmatch:\n  canary: \"true\"",
+		"xxxxxx",
+	} {
+		_, err = parseRule(illegal)
+		assert.NotNil(t, err, illegal)
+	}
+}
+
+func TestRouteByPercentage(t *testing.T) {
+	stable, canary := newInvokers()
+	invokers := append(append([]protocol.Invoker{}, stable...), canary...)
+
+	// no rule
+	p, _ := NewCanaryPriorityRouter()
+	consumerURL, _ := common.NewURL("consumer://127.0.0.1/com.xxx.xxx.UserProvider?version=3.1.0")
+	assert.Equal(t, invokers, p.Route(invokers, consumerURL, randomInvocation(0)))
+
+	// 0 behaves like no canary
+	p = newRouterWithRule(t, "percentage: 0\nmatch:\n  canary: \"true\"")
+	assert.Equal(t, 0, route(p, invokers, 1000, randomInvocation))
+	assert.Equal(t, stable, p.Route(invokers, consumerURL, randomInvocation(0)))
+
+	// 100 behaves like full cutover
+	p = newRouterWithRule(t, "percentage: 100\nmatch:\n  canary: \"true\"")
+	assert.Equal(t, 1000, route(p, invokers, 1000, randomInvocation))
+
+	p = newRouterWithRule(t, "percentage: 20\nmatch:\n  canary: \"true\"")
+	count := route(p, invokers, 10000, randomInvocation)
+	assert.InDelta(t, 2000, count, 300)
+
+	// disabled
+	p = newRouterWithRule(t, "enabled: false\npercentage: 100\nmatch:\n  canary: \"true\"")
+	assert.Equal(t, invokers, p.Route(invokers, consumerURL, randomInvocation(0)))
+
+	// all providers are returned when the chosen ones are absent
+	p = newRouterWithRule(t, "percentage: 100\nmatch:\n  canary: \"true\"")
+	assert.Equal(t, stable, p.Route(stable, consumerURL, randomInvocation(0)))
+}
+
+func TestRouteByHash(t *testing.T) {
+	stable, canary := newInvokers()
+	invokers := append(append([]protocol.Invoker{}, stable...), canary...)
+	p := newRouterWithRule(t, "percentage: 30\nmode: hash\nhashKey: userId\nmatch:\n  canary: \"true\"")
+
+	byUser := func(i int) protocol.Invocation {
+		return invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"userId": fmt.Sprint(i)})
+	}
+	// the same request always goes the same way
+	first := route(p, invokers, 10000, byUser)
+	assert.Equal(t, first, route(p, invokers, 10000, byUser))
+	assert.InDelta(t, 3000, first, 300)
+
+	sameUser := func(int) protocol.Invocation {
+		return byUser(42)
+	}
+	count := route(p, invokers, 100, sameUser)
+	assert.True(t, count == 0 || count == 100)
+
+	// hash by method and arguments without the hash key
+	byArgs := func(i int) protocol.Invocation {
+		return invocation.NewRPCInvocation("GetUser", []interface{}{i}, nil)
+	}
+	assert.Equal(t, route(p, invokers, 1000, byArgs), route(p, invokers, 1000, byArgs))
+}
+
+func TestProcess(t *testing.T) {
+	p := newRouterWithRule(t, "percentage: 100\nmatch:\n  canary: \"true\"")
+	_, ok := p.rules.Load(ruleKeyOfUserProvider)
+	assert.True(t, ok)
+
+	// a malformed rule stops the canary
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider, Value: "xxxxxx", ConfigType: remoting.EventTypeUpdate})
+	_, ok = p.rules.Load(ruleKeyOfUserProvider)
+	assert.False(t, ok)
+
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider,
+		Value: "percentage: 100\nmatch:\n  canary: \"true\"", ConfigType: remoting.EventTypeUpdate})
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider, ConfigType: remoting.EventTypeDel})
+	_, ok = p.rules.Load(ruleKeyOfUserProvider)
+	assert.False(t, ok)
+}
+
+func TestNotify(t *testing.T) {
+	stable, canary := newInvokers()
+	invokers := append(append([]protocol.Invoker{}, stable...), canary...)
+	ccUrl, _ := common.NewURL("mock://127.0.0.1:1111")
+	mockFactory := &config_center.MockDynamicConfigurationFactory{
+		Content: "percentage: 100\nmatch:\n  canary: \"true\"",
+	}
+	dc, _ := mockFactory.GetDynamicConfiguration(ccUrl)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+
+	p, _ := NewCanaryPriorityRouter()
+	p.Notify(invokers)
+	value, ok := p.rules.Load(ruleKeyOfUserProvider)
+	assert.True(t, ok)
+	assert.Equal(t, float64(100), value.(*Rule).Percentage)
+}
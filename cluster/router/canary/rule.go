@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ModeRandom picks the canary bucket randomly for each request
+	ModeRandom = "random"
+	// ModeHash picks the canary bucket by the hash of the request, the same request always goes the same way
+	ModeHash = "hash"
+)
+
+// Rule is the canary rule of a service, e.g.
+//
+//	key: org.apache.dubbo.UserProvider
+//	enabled: true
+//	percentage: 5
+//	match:
+//	  canary: "true"
+//	mode: hash
+//	hashKey: userId
+//
+// Percentage of the requests go to the providers whose url params match all of Match, and the others go to
+// the rest providers. With hash mode the request is hashed by the attachment HashKey, or by the method and
+// arguments if HashKey is absent.
+type Rule struct {
+	Key        string            `yaml:"key"`
+	Enabled    bool              `default:"true" yaml:"enabled"`
+	Percentage float64           `yaml:"percentage"`
+	Match      map[string]string `yaml:"match"`
+	Mode       string            `default:"random" yaml:"mode"`
+	HashKey    string            `yaml:"hashKey"`
+}
+
+func parseRule(content string) (*Rule, error) {
+	rule := &Rule{}
+	if err := defaults.Set(rule); err != nil {
+		return nil, err
+	}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	if len(rule.Match) == 0 {
+		return nil, perrors.New("canary rule must match at least one label")
+	}
+	if rule.Percentage < 0 || rule.Percentage > 100 {
+		return nil, perrors.Errorf("canary percentage %v is out of range [0, 100]", rule.Percentage)
+	}
+	if rule.Mode != ModeRandom && rule.Mode != ModeHash {
+		return nil, perrors.Errorf("unknown canary mode %s, it must be %s or %s", rule.Mode, ModeRandom, ModeHash)
+	}
+	return rule, nil
+}
@@ -29,3 +29,19 @@ type Chain interface {
 	// AddRouters Add routers
 	AddRouters([]PriorityRouter)
 }
+
+// RouteStep is how a router of a chain routes an invocation
+type RouteStep struct {
+	// Router is the type name of the router
+	Router string
+	// Filtered are the invokers filtered out by the router
+	Filtered []protocol.Invoker
+	// Routed are the invokers remained after the router
+	Routed []protocol.Invoker
+}
+
+// Tracer is implemented by the chains which could trace the routing for debugging
+type Tracer interface {
+	// Trace routes the invocation like Route without blocking it, and returns the steps of the routers in order
+	Trace(*common.URL, protocol.Invocation) []RouteStep
+}
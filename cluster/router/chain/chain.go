@@ -18,7 +18,9 @@
 package chain
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -52,23 +54,57 @@ type RouterChain struct {
 
 // Route Loop routers in RouterChain and call Route method to determine the target invokers list.
 func (c *RouterChain) Route(url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
-	finalInvokers := make([]protocol.Invoker, 0, len(c.invokers))
-	// multiple invoker may include different methods, find correct invoker otherwise
-	// will return the invoker without methods
-	for _, invoker := range c.invokers {
-		if invoker.GetURL().ServiceKey() == url.ServiceKey() {
-			finalInvokers = append(finalInvokers, invoker)
-		}
+	// the routers route by a read only view of the url, which isn't affected by the url modified meanwhile
+	url = url.ToReadOnly()
+	finalInvokers := matchServiceKey(url, c.copyInvokers())
+	for _, r := range c.copyRouters() {
+		finalInvokers = r.Route(finalInvokers, url, invocation)
 	}
+	return finalInvokers
+}
 
-	if len(finalInvokers) == 0 {
-		finalInvokers = c.invokers
+// Trace routes the @invocation like Route, and returns how each router filters the invokers in order. It works on
+// the copies of the invokers and the routers, so the invocations are not blocked.
+func (c *RouterChain) Trace(url *common.URL, invocation protocol.Invocation) []router.RouteStep {
+	url = url.ToReadOnly()
+	invokers := matchServiceKey(url, c.copyInvokers())
+	routers := c.copyRouters()
+	steps := make([]router.RouteStep, 0, len(routers))
+	for _, r := range routers {
+		routed := r.Route(invokers, url, invocation)
+		remained := make(map[protocol.Invoker]struct{}, len(routed))
+		for _, invoker := range routed {
+			remained[invoker] = struct{}{}
+		}
+		step := router.RouteStep{
+			Router:   strings.TrimPrefix(fmt.Sprintf("%T", r), "*"),
+			Filtered: make([]protocol.Invoker, 0),
+			Routed:   routed,
+		}
+		for _, invoker := range invokers {
+			if _, ok := remained[invoker]; !ok {
+				step.Filtered = append(step.Filtered, invoker)
+			}
+		}
+		steps = append(steps, step)
+		invokers = routed
 	}
+	return steps
+}
 
-	for _, r := range c.copyRouters() {
-		finalInvokers = r.Route(finalInvokers, url, invocation)
+// matchServiceKey returns the @invokers matching the service key of @url, multiple invoker may include different
+// methods, find correct invoker otherwise will return the invoker without methods
+func matchServiceKey(url *common.URL, invokers []protocol.Invoker) []protocol.Invoker {
+	matched := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if common.IsServiceKeyMatch(url, invoker.GetURL()) {
+			matched = append(matched, invoker)
+		}
 	}
-	return finalInvokers
+	if len(matched) == 0 {
+		return invokers
+	}
+	return matched
 }
 
 // AddRouters Add routers to router chain
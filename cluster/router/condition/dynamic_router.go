@@ -58,23 +58,29 @@ func (d *DynamicRouter) URL() *common.URL {
 }
 
 func (d *DynamicRouter) Process(event *config_center.ConfigChangeEvent) {
-	if event.ConfigType == remoting.EventTypeDel {
-		d.routerConfig = nil
-		d.conditionRouters = make([]*StateRouter, 0)
-	} else {
-		routerConfig, err := parseRoute(event.Value.(string))
-		if err != nil {
-			logger.Warnf("[condition router]Build a new condition route config error, %+v and we will use the original condition rule configuration.", err)
-			return
-		}
-		d.routerConfig = routerConfig
-		conditions, err := generateConditions(d.routerConfig)
-		if err != nil {
-			logger.Warnf("[condition router]Build a new condition route config error, %+v and we will use the original condition rule configuration.", err)
+	content, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || len(content) == 0 {
+		// the local rule is used unless the config center has the rule
+		rule, ok := config.GetRouterRule(event.Key)
+		if !ok {
+			d.routerConfig = nil
+			d.conditionRouters = make([]*StateRouter, 0)
 			return
 		}
-		d.conditionRouters = conditions
+		content = rule.Content
+	}
+	routerConfig, err := parseRoute(content)
+	if err != nil {
+		logger.Warnf("[condition router]Build a new condition route config error, %+v and we will use the original condition rule configuration.", err)
+		return
+	}
+	conditions, err := generateConditions(routerConfig)
+	if err != nil {
+		logger.Warnf("[condition router]Build a new condition route config error, %+v and we will use the original condition rule configuration.", err)
+		return
 	}
+	d.routerConfig = routerConfig
+	d.conditionRouters = conditions
 }
 
 func generateConditions(routerConfig *config.RouterConfig) ([]*StateRouter, error) {
@@ -90,6 +96,7 @@ func generateConditions(routerConfig *config.RouterConfig) ([]*StateRouter, erro
 		url.AddParam(constant.RuleKey, conditionRule)
 		url.AddParam(constant.ForceKey, strconv.FormatBool(*routerConfig.Force))
 		url.AddParam(constant.EnabledKey, strconv.FormatBool(*routerConfig.Enabled))
+		url.AddParam(constant.PriorityKey, strconv.Itoa(routerConfig.Priority))
 		conditionRoute, err := NewConditionStateRouter(url)
 		if err != nil {
 			return nil, err
@@ -122,18 +129,25 @@ func (s *ServiceRouter) Notify(invokers []protocol.Invoker) {
 		return
 	}
 
+	key := config_center.ServiceRuleKey(url, constant.ConditionRouterRuleSuffix)
+	rule, local := config.GetRouterRule(key)
 	dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
-	if dynamicConfiguration == nil {
-		logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
+	if dynamicConfiguration == nil || (local && !rule.Subscribe) {
+		if !local {
+			logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
+			return
+		}
+		s.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule.Content, ConfigType: remoting.EventTypeAdd})
 		return
 	}
-	key := strings.Join([]string{strings.Join([]string{url.Service(), url.GetParam(constant.VersionKey, ""), url.GetParam(constant.GroupKey, "")}, ":"),
-		constant.ConditionRouterRuleSuffix}, "")
 	dynamicConfiguration.AddListener(key, s)
 	value, err := dynamicConfiguration.GetRule(key)
 	if err != nil {
 		logger.Errorf("Failed to query condition rule, key=%s, err=%v", key, err)
-		return
+		if !local {
+			return
+		}
+		value = ""
 	}
 	s.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
 }
@@ -182,23 +196,31 @@ func (a *ApplicationRouter) Notify(invokers []protocol.Invoker) {
 	defer a.mu.Unlock()
 
 	if providerApplicaton != a.application {
+		key := strings.Join([]string{providerApplicaton, constant.ConditionRouterRuleSuffix}, "")
+		rule, local := config.GetRouterRule(key)
 		dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
-		if dynamicConfiguration == nil {
+		if dynamicConfiguration == nil && !local {
 			logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
 			return
 		}
 
-		if a.application != "" {
+		if dynamicConfiguration != nil && a.application != "" {
 			dynamicConfiguration.RemoveListener(strings.Join([]string{a.application, constant.ConditionRouterRuleSuffix}, ""), a)
 		}
+		a.application = providerApplicaton
+		if dynamicConfiguration == nil || (local && !rule.Subscribe) {
+			a.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule.Content, ConfigType: remoting.EventTypeUpdate})
+			return
+		}
 
-		key := strings.Join([]string{providerApplicaton, constant.ConditionRouterRuleSuffix}, "")
 		dynamicConfiguration.AddListener(key, a)
-		a.application = providerApplicaton
 		value, err := dynamicConfiguration.GetRule(key)
 		if err != nil {
 			logger.Errorf("Failed to query condition rule, key=%s, err=%v", key, err)
-			return
+			if !local {
+				return
+			}
+			value = ""
 		}
 		a.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeUpdate})
 	}
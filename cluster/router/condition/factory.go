@@ -19,6 +19,7 @@ package condition
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 )
@@ -26,6 +27,17 @@ import (
 func init() {
 	extension.SetRouterFactory(constant.ConditionServiceRouterFactoryKey, NewServiceConditionRouterFactory)
 	extension.SetRouterFactory(constant.ConditionAppRouterFactoryKey, NewAppConditionRouterFactory)
+	extension.SetURLRouter(constant.ConditionRouteProtocol, newURLConditionRouter)
+}
+
+// newURLConditionRouter creates a condition router from a condition rule url of the registry, e.g.
+// condition://0.0.0.0/com.foo.BarService?category=routers&force=false&priority=1&rule=host%3D10.20.153.10%3D%3Ehost%3D10.20.153.11
+func newURLConditionRouter(url *common.URL) (router.PriorityRouter, error) {
+	r, err := NewConditionStateRouter(url)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 // ServiceRouteFactory router factory
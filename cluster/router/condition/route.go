@@ -25,6 +25,8 @@ import (
 )
 
 import (
+	"github.com/creasty/defaults"
+
 	"github.com/dubbogo/gost/log/logger"
 
 	"github.com/pkg/errors"
@@ -53,6 +55,7 @@ var (
 type StateRouter struct {
 	enable        bool
 	force         bool
+	priority      int64
 	url           *common.URL
 	whenCondition map[string]matcher.Matcher
 	thenCondition map[string]matcher.Matcher
@@ -68,9 +71,10 @@ func NewConditionStateRouter(url *common.URL) (*StateRouter, error) {
 	force := url.GetParamBool(constant.ForceKey, false)
 	enable := url.GetParamBool(constant.EnabledKey, true)
 	c := &StateRouter{
-		url:    url,
-		force:  force,
-		enable: enable,
+		url:      url,
+		force:    force,
+		enable:   enable,
+		priority: url.GetParamInt(constant.PriorityKey, 0),
 	}
 
 	if enable {
@@ -125,7 +129,11 @@ func (s *StateRouter) URL() *common.URL {
 }
 
 func (s *StateRouter) Priority() int64 {
-	return 0
+	return s.priority
+}
+
+// Notify does nothing, the rule of a StateRouter is fixed once created
+func (s *StateRouter) Notify(_ []protocol.Invoker) {
 }
 
 func (s *StateRouter) matchWhen(url *common.URL, invocation protocol.Invocation) bool {
@@ -321,6 +329,10 @@ func (a byPriority) Less(i, j int) bool { return a[i].Priority() < a[j].Priority
 func parseRoute(routeContent string) (*config.RouterConfig, error) {
 	routeDecoder := yaml.NewDecoder(strings.NewReader(routeContent))
 	routerConfig := &config.RouterConfig{}
+	// force, enabled and so on may be absent in the rule, use their default values then
+	if err := defaults.Set(routerConfig); err != nil {
+		return nil, err
+	}
 	err := routeDecoder.Decode(routerConfig)
 	if err != nil {
 		return nil, err
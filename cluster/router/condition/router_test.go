@@ -805,3 +805,53 @@ conditions:
 	invokers = router.Route(invokerList, consumerURL, rpcInvocation)
 	assert.Equal(t, 3, len(invokers))
 }
+
+func TestParseRule(t *testing.T) {
+	condition, err := parseRule("host = 2.2.2.2,1.1.1.1 & host != 1.1.1.1 & method = get*")
+	assert.Nil(t, err)
+	assert.Len(t, condition, 2)
+	hostMatcher := condition["host"]
+	assert.Len(t, hostMatcher.GetMatches(), 2)
+	assert.Contains(t, hostMatcher.GetMatches(), "2.2.2.2")
+	assert.Contains(t, hostMatcher.GetMatches(), "1.1.1.1")
+	assert.Len(t, hostMatcher.GetMismatches(), 1)
+	assert.Contains(t, hostMatcher.GetMismatches(), "1.1.1.1")
+	assert.Contains(t, condition["method"].GetMatches(), "get*")
+
+	condition, err = parseRule(" ")
+	assert.Nil(t, err)
+	assert.Empty(t, condition)
+
+	for _, illegal := range []string{
+		"= 1.1.1.1",
+		"host = ,1.1.1.1",
+		"!= 1.1.1.1",
+	} {
+		_, err = parseRule(illegal)
+		assert.NotNil(t, err, illegal)
+	}
+}
+
+func TestURLConditionRouter(t *testing.T) {
+	factory, ok := extension.GetURLRouter(constant.ConditionRouteProtocol)
+	assert.True(t, ok)
+
+	url, err := common.NewURL(conditionAddr + "?priority=3&force=true&rule=" +
+		"method%3DgetFoo%20%3D%3E%20host%20%3D%201.2.3.4")
+	assert.Nil(t, err)
+	r, err := factory(url)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), r.Priority())
+
+	consumerURL, _ := common.NewURL(localConsumerAddr)
+	providerURL, _ := common.NewURL(localProviderAddr)
+	invokers := []protocol.Invoker{protocol.NewBaseInvoker(providerURL)}
+	// force=true, no provider matches then condition
+	assert.Empty(t, r.Route(invokers, consumerURL, invocation.NewRPCInvocation(method, nil, nil)))
+	// when condition not matched
+	assert.Len(t, r.Route(invokers, consumerURL, invocation.NewRPCInvocation("setFoo", nil, nil)), 1)
+
+	url, _ = common.NewURL(conditionAddr + "?rule=" + "%3D%201.1.1.1%20%3D%3E%20host%20%3D%201.2.3.4")
+	_, err = factory(url)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	/*
+		Outlier detection is enabled by importing this package and adding the outlier filter to the reference,
+		e.g. filter: outlier. The health check router does nothing until the filter records results.
+	*/
+	extension.SetRouterFactory(constant.HealthCheckRouterFactoryKey, NewHealthCheckRouterFactory)
+	extension.SetFilter(constant.OutlierDetectionFilterKey, newOutlierFilter)
+}
+
+// RouteFactory router factory
+type RouteFactory struct{}
+
+// NewHealthCheckRouterFactory constructs a new PriorityRouterFactory
+func NewHealthCheckRouterFactory() router.PriorityRouterFactory {
+	return &RouteFactory{}
+}
+
+// NewPriorityRouter construct a new PriorityRouter
+func (f *RouteFactory) NewPriorityRouter() (router.PriorityRouter, error) {
+	return NewHealthCheckRouter()
+}
@@ -0,0 +1,311 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// windowBuckets is the number of buckets the sliding window of an invoker is divided into
+const windowBuckets = 10
+
+var (
+	detectorOnce    sync.Once
+	defaultDetector *OutlierDetector
+)
+
+// GetOutlierDetector returns the outlier detector shared by the outlier filter and the health check router
+func GetOutlierDetector() *OutlierDetector {
+	detectorOnce.Do(func() {
+		defaultDetector = NewOutlierDetector()
+	})
+	return defaultDetector
+}
+
+// EjectionState is a snapshot of the outlier detection state of an invoker
+type EjectionState struct {
+	ConsecutiveFailures int64
+	// Requests and Failures are counted in the sliding window
+	Requests      int64
+	Failures      int64
+	EjectionCount int64
+	Ejected       bool
+	EjectedUntil  time.Time
+}
+
+// OutlierDetector tracks the results of each invoker passively, and ejects the invokers which fail
+// consecutively or whose error rate in the sliding window exceeds the threshold. An ejected invoker is
+// re-admitted as a probe after the ejection time, which grows exponentially on repeated ejections.
+type OutlierDetector struct {
+	mutex  sync.Mutex
+	states map[string]*invokerHealth
+	clock  func() time.Time
+}
+
+// NewOutlierDetector creates an outlier detector without any state
+func NewOutlierDetector() *OutlierDetector {
+	return &OutlierDetector{
+		states: make(map[string]*invokerHealth),
+		clock:  time.Now,
+	}
+}
+
+type outlierConfig struct {
+	consecutiveErrors  int64
+	errorRate          int64
+	minRequests        int64
+	window             time.Duration
+	baseEjectionTime   time.Duration
+	maxEjectionTime    time.Duration
+	maxEjectionPercent int64
+}
+
+func newOutlierConfig(url *common.URL) *outlierConfig {
+	return &outlierConfig{
+		consecutiveErrors:  url.GetParamInt(constant.OutlierConsecutiveErrorsKey, constant.DefaultOutlierConsecutiveErrors),
+		errorRate:          url.GetParamInt(constant.OutlierErrorRateKey, constant.DefaultOutlierErrorRate),
+		minRequests:        url.GetParamInt(constant.OutlierMinRequestsKey, constant.DefaultOutlierMinRequests),
+		window:             url.GetParamDurationWithDefault(constant.OutlierWindowKey, constant.DefaultOutlierWindow),
+		baseEjectionTime:   url.GetParamDurationWithDefault(constant.OutlierBaseEjectionTimeKey, constant.DefaultOutlierBaseEjectionTime),
+		maxEjectionTime:    url.GetParamDurationWithDefault(constant.OutlierMaxEjectionTimeKey, constant.DefaultOutlierMaxEjectionTime),
+		maxEjectionPercent: url.GetParamInt(constant.OutlierMaxEjectionPercentKey, constant.DefaultOutlierMaxEjectionPercent),
+	}
+}
+
+type bucket struct {
+	start    time.Time
+	requests int64
+	failures int64
+}
+
+type invokerHealth struct {
+	// invoker is the instance notified by the directory, the state is reset once it is replaced
+	invoker protocol.Invoker
+	// url is the latest url of the invoker, which holds the outlier detection config
+	url                 *common.URL
+	consecutiveFailures int64
+	buckets             [windowBuckets]bucket
+	ejectionCount       int64
+	ejectedUntil        time.Time
+	// probing is true from the ejection until the first result after re-admission
+	probing bool
+}
+
+// RecordResult records the result of a request to the invoker with @url, and ejects it if necessary
+func (d *OutlierDetector) RecordResult(url *common.URL, err error) {
+	cfg := newOutlierConfig(url)
+	now := d.clock()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	h := d.getOrCreate(url)
+	if now.Before(h.ejectedUntil) {
+		// requests to an ejected invoker only arrive when the max ejection percent is reached
+		return
+	}
+
+	if h.probing {
+		h.probing = false
+		if err != nil {
+			d.eject(url, h, cfg, now)
+		}
+		return
+	}
+
+	b := h.currentBucket(cfg.window, now)
+	b.requests++
+	if err == nil {
+		h.consecutiveFailures = 0
+		return
+	}
+	b.failures++
+	h.consecutiveFailures++
+
+	if cfg.consecutiveErrors > 0 && h.consecutiveFailures >= cfg.consecutiveErrors {
+		d.eject(url, h, cfg, now)
+		return
+	}
+	requests, failures := h.windowCount(cfg.window, now)
+	if cfg.errorRate > 0 && requests >= cfg.minRequests && failures*100 >= cfg.errorRate*requests {
+		d.eject(url, h, cfg, now)
+	}
+}
+
+// eject ejects the invoker for base ejection time * 2^(ejections-1), which is capped by the max ejection time
+func (d *OutlierDetector) eject(url *common.URL, h *invokerHealth, cfg *outlierConfig, now time.Time) {
+	if !h.ejectedUntil.IsZero() && now.Sub(h.ejectedUntil) > cfg.maxEjectionTime {
+		// the invoker has been healthy long enough, forget the previous ejections
+		h.ejectionCount = 0
+	}
+	h.ejectionCount++
+	ejectionTime := cfg.baseEjectionTime
+	for i := int64(1); i < h.ejectionCount && ejectionTime < cfg.maxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+	if ejectionTime > cfg.maxEjectionTime {
+		ejectionTime = cfg.maxEjectionTime
+	}
+	h.ejectedUntil = now.Add(ejectionTime)
+	h.probing = true
+	h.consecutiveFailures = 0
+	h.buckets = [windowBuckets]bucket{}
+	cluster.RecordEjection(url)
+	logger.Warnf("[outlier detection] eject invoker %s for %v, ejection count: %d", url.Location, ejectionTime, h.ejectionCount)
+}
+
+// IsEjected returns whether the invoker with @url is ejected now
+func (d *OutlierDetector) IsEjected(url *common.URL) bool {
+	_, ejected := d.ejectedUntil(url)
+	return ejected
+}
+
+// ejectedUntil returns the end of the ejection if the invoker with @url is ejected now
+func (d *OutlierDetector) ejectedUntil(url *common.URL) (time.Time, bool) {
+	now := d.clock()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	h, ok := d.states[url.Key()]
+	if !ok || !now.Before(h.ejectedUntil) {
+		return time.Time{}, false
+	}
+	return h.ejectedUntil, true
+}
+
+// State returns the outlier detection state of the invoker with @url, false if nothing has been recorded
+func (d *OutlierDetector) State(url *common.URL) (EjectionState, bool) {
+	now := d.clock()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	h, ok := d.states[url.Key()]
+	if !ok {
+		return EjectionState{}, false
+	}
+	return h.state(newOutlierConfig(url).window, now), true
+}
+
+// States returns the outlier detection state of all invokers, keyed by invoker url key
+func (d *OutlierDetector) States() map[string]EjectionState {
+	now := d.clock()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	states := make(map[string]EjectionState, len(d.states))
+	for key, h := range d.states {
+		states[key] = h.state(newOutlierConfig(h.url).window, now)
+	}
+	return states
+}
+
+// Reset forgets the outlier detection state of the invoker with @url
+func (d *OutlierDetector) Reset(url *common.URL) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.states, url.Key())
+}
+
+// Notify resets the state of the invokers refreshed by the directory, and removes the state of the invokers
+// of the same services which no longer exist.
+func (d *OutlierDetector) Notify(invokers []protocol.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	current := make(map[string]struct{}, len(invokers))
+	services := make(map[string]struct{})
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, invoker := range invokers {
+		url := invoker.GetURL()
+		key := url.Key()
+		current[key] = struct{}{}
+		services[url.ServiceKey()] = struct{}{}
+		h, ok := d.states[key]
+		if !ok || (h.invoker != nil && h.invoker != invoker) {
+			d.states[key] = &invokerHealth{invoker: invoker, url: url}
+			continue
+		}
+		h.invoker = invoker
+		h.url = url
+	}
+	for key, h := range d.states {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		if _, ok := services[h.url.ServiceKey()]; ok {
+			delete(d.states, key)
+		}
+	}
+}
+
+func (d *OutlierDetector) getOrCreate(url *common.URL) *invokerHealth {
+	h, ok := d.states[url.Key()]
+	if !ok {
+		h = &invokerHealth{url: url}
+		d.states[url.Key()] = h
+	}
+	return h
+}
+
+// currentBucket returns the bucket of @now, and clears it if it belongs to a previous window
+func (h *invokerHealth) currentBucket(window time.Duration, now time.Time) *bucket {
+	width := window / windowBuckets
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	start := now.Truncate(width)
+	b := &h.buckets[(start.UnixNano()/int64(width))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
+
+// windowCount sums up the requests and failures in the window ending at @now
+func (h *invokerHealth) windowCount(window time.Duration, now time.Time) (requests, failures int64) {
+	for _, b := range h.buckets {
+		if b.start.IsZero() || now.Sub(b.start) >= window {
+			continue
+		}
+		requests += b.requests
+		failures += b.failures
+	}
+	return
+}
+
+func (h *invokerHealth) state(window time.Duration, now time.Time) EjectionState {
+	requests, failures := h.windowCount(window, now)
+	return EjectionState{
+		ConsecutiveFailures: h.consecutiveFailures,
+		Requests:            requests,
+		Failures:            failures,
+		EjectionCount:       h.ejectionCount,
+		Ejected:             now.Before(h.ejectedUntil),
+		EjectedUntil:        h.ejectedUntil,
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const providerAddr = "dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider"
+
+var errRequest = errors.New("request failed")
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestDetector() (*OutlierDetector, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	d := NewOutlierDetector()
+	d.clock = clock.Now
+	return d, clock
+}
+
+func TestConsecutiveErrorsEjection(t *testing.T) {
+	d, clock := newTestDetector()
+	url, _ := common.NewURL(providerAddr + "?outlier.consecutive.errors=3&outlier.error.rate=0&outlier.base.ejection.time=10s")
+
+	d.RecordResult(url, errRequest)
+	d.RecordResult(url, errRequest)
+	d.RecordResult(url, nil)
+	d.RecordResult(url, errRequest)
+	d.RecordResult(url, errRequest)
+	assert.False(t, d.IsEjected(url))
+	state, ok := d.State(url)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), state.ConsecutiveFailures)
+	assert.Equal(t, int64(5), state.Requests)
+	assert.Equal(t, int64(4), state.Failures)
+
+	d.RecordResult(url, errRequest)
+	assert.True(t, d.IsEjected(url))
+	state, _ = d.State(url)
+	assert.True(t, state.Ejected)
+	assert.Equal(t, int64(1), state.EjectionCount)
+	assert.Equal(t, clock.now.Add(10*time.Second), state.EjectedUntil)
+
+	clock.Advance(10 * time.Second)
+	assert.False(t, d.IsEjected(url))
+}
+
+func TestErrorRateEjection(t *testing.T) {
+	d, clock := newTestDetector()
+	url, _ := common.NewURL(providerAddr + "?outlier.consecutive.errors=0&outlier.error.rate=50" +
+		"&outlier.min.requests=4&outlier.window=10s")
+
+	// failures out of the window are not counted
+	d.RecordResult(url, errRequest)
+	d.RecordResult(url, errRequest)
+	clock.Advance(11 * time.Second)
+	d.RecordResult(url, nil)
+	d.RecordResult(url, errRequest)
+	d.RecordResult(url, nil)
+	assert.False(t, d.IsEjected(url))
+
+	d.RecordResult(url, errRequest)
+	assert.True(t, d.IsEjected(url))
+}
+
+func TestEjectionBackoffAndProbe(t *testing.T) {
+	d, clock := newTestDetector()
+	url, _ := common.NewURL(providerAddr + "?outlier.consecutive.errors=1&outlier.base.ejection.time=10s" +
+		"&outlier.max.ejection.time=30s")
+
+	d.RecordResult(url, errRequest)
+	state, _ := d.State(url)
+	assert.Equal(t, clock.now.Add(10*time.Second), state.EjectedUntil)
+
+	// the probe after the ejection fails, ejected again for twice the time
+	clock.Advance(10 * time.Second)
+	assert.False(t, d.IsEjected(url))
+	d.RecordResult(url, errRequest)
+	state, _ = d.State(url)
+	assert.Equal(t, int64(2), state.EjectionCount)
+	assert.Equal(t, clock.now.Add(20*time.Second), state.EjectedUntil)
+
+	// capped by the max ejection time
+	clock.Advance(20 * time.Second)
+	d.RecordResult(url, errRequest)
+	state, _ = d.State(url)
+	assert.Equal(t, clock.now.Add(30*time.Second), state.EjectedUntil)
+
+	// the probe succeeds, the invoker is re-admitted
+	clock.Advance(30 * time.Second)
+	d.RecordResult(url, nil)
+	assert.False(t, d.IsEjected(url))
+	d.RecordResult(url, nil)
+	state, _ = d.State(url)
+	assert.Equal(t, int64(1), state.Requests)
+
+	// healthy for longer than the max ejection time, previous ejections are forgotten
+	clock.Advance(time.Minute)
+	d.RecordResult(url, errRequest)
+	state, _ = d.State(url)
+	assert.Equal(t, int64(1), state.EjectionCount)
+	assert.Equal(t, clock.now.Add(10*time.Second), state.EjectedUntil)
+}
+
+func TestNotifyResetsState(t *testing.T) {
+	d, _ := newTestDetector()
+	url1, _ := common.NewURL(providerAddr + "?outlier.consecutive.errors=1")
+	url2, _ := common.NewURL("dubbo://192.168.1.2:20000/com.ikurento.user.UserProvider?outlier.consecutive.errors=1")
+	invoker1 := protocol.NewBaseInvoker(url1)
+	invoker2 := protocol.NewBaseInvoker(url2)
+
+	d.Notify([]protocol.Invoker{invoker1, invoker2})
+	d.RecordResult(url1, errRequest)
+	d.RecordResult(url2, errRequest)
+	assert.True(t, d.IsEjected(url1))
+	assert.True(t, d.IsEjected(url2))
+	assert.Len(t, d.States(), 2)
+
+	// the same invoker instance keeps its state
+	d.Notify([]protocol.Invoker{invoker1, invoker2})
+	assert.True(t, d.IsEjected(url1))
+
+	// invoker1 is refreshed and invoker2 is removed
+	d.Notify([]protocol.Invoker{protocol.NewBaseInvoker(url1)})
+	assert.False(t, d.IsEjected(url1))
+	_, ok := d.State(url2)
+	assert.False(t, ok)
+
+	d.RecordResult(url1, errRequest)
+	d.Reset(url1)
+	assert.False(t, d.IsEjected(url1))
+}
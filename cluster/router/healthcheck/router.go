@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"sort"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// HealthCheckRouter filters out the invokers ejected by the outlier detector
+type HealthCheckRouter struct {
+	detector *OutlierDetector
+}
+
+// NewHealthCheckRouter constructs a new HealthCheckRouter backed by the shared outlier detector
+func NewHealthCheckRouter() (*HealthCheckRouter, error) {
+	return &HealthCheckRouter{detector: GetOutlierDetector()}, nil
+}
+
+// Route filters out the ejected invokers. No more than max ejection percent of the invokers are filtered out,
+// the ejected invokers closest to the end of their ejection are kept instead, so that it never ejects everyone.
+func (r *HealthCheckRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+
+	type ejectedInvoker struct {
+		index int
+		until time.Time
+	}
+	ejected := make([]ejectedInvoker, 0)
+	for i, invoker := range invokers {
+		if until, ok := r.detector.ejectedUntil(invoker.GetURL()); ok {
+			ejected = append(ejected, ejectedInvoker{index: i, until: until})
+		}
+	}
+	if len(ejected) == 0 {
+		return invokers
+	}
+
+	maxEjectionPercent := invokers[0].GetURL().GetParamInt(constant.OutlierMaxEjectionPercentKey,
+		constant.DefaultOutlierMaxEjectionPercent)
+	maxEjected := len(invokers) * int(maxEjectionPercent) / 100
+	if len(ejected) > maxEjected {
+		sort.SliceStable(ejected, func(i, j int) bool {
+			return ejected[i].until.After(ejected[j].until)
+		})
+		ejected = ejected[:maxEjected]
+	}
+	if len(ejected) == 0 {
+		return invokers
+	}
+
+	filtered := make(map[int]struct{}, len(ejected))
+	for _, e := range ejected {
+		filtered[e.index] = struct{}{}
+	}
+	result := make([]protocol.Invoker, 0, len(invokers)-len(filtered))
+	for i, invoker := range invokers {
+		if _, ok := filtered[i]; !ok {
+			result = append(result, invoker)
+		}
+	}
+	return result
+}
+
+// URL Return URL in router
+func (r *HealthCheckRouter) URL() *common.URL {
+	return nil
+}
+
+// Priority Return Priority in router
+func (r *HealthCheckRouter) Priority() int64 {
+	return constant.HealthCheckRouterPriority
+}
+
+// Notify resets the outlier detection state of the invokers refreshed by the directory
+func (r *HealthCheckRouter) Notify(invokers []protocol.Invoker) {
+	r.detector.Notify(invokers)
+}
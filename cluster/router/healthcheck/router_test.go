@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func newTestInvokers(n int, params string) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, n)
+	for i := 0; i < n; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?%s", i, params))
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func TestHealthCheckRouterRoute(t *testing.T) {
+	d, clock := newTestDetector()
+	r := &HealthCheckRouter{detector: d}
+	invokers := newTestInvokers(4, "outlier.consecutive.errors=1&outlier.max.ejection.percent=50")
+	consumerURL, _ := common.NewURL("consumer://192.168.1.100/com.ikurento.user.UserProvider")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	r.Notify(invokers)
+
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, inv))
+
+	d.RecordResult(invokers[1].GetURL(), errRequest)
+	result := r.Route(invokers, consumerURL, inv)
+	assert.Equal(t, []protocol.Invoker{invokers[0], invokers[2], invokers[3]}, result)
+
+	// no more than 50% are ejected, the one closest to re-admission is kept
+	clock.Advance(time.Second)
+	d.RecordResult(invokers[2].GetURL(), errRequest)
+	clock.Advance(time.Second)
+	d.RecordResult(invokers[3].GetURL(), errRequest)
+	result = r.Route(invokers, consumerURL, inv)
+	assert.Equal(t, []protocol.Invoker{invokers[0], invokers[1]}, result)
+
+	// never eject everyone
+	d.RecordResult(invokers[0].GetURL(), errRequest)
+	result = r.Route(invokers, consumerURL, inv)
+	assert.Len(t, result, 2)
+	result = r.Route(invokers[:1], consumerURL, inv)
+	assert.Equal(t, invokers[:1], result)
+}
+
+func TestHealthCheckRouterNotify(t *testing.T) {
+	d, _ := newTestDetector()
+	r := &HealthCheckRouter{detector: d}
+	invokers := newTestInvokers(2, "outlier.consecutive.errors=1")
+	consumerURL, _ := common.NewURL("consumer://192.168.1.100/com.ikurento.user.UserProvider")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	r.Notify(invokers)
+
+	d.RecordResult(invokers[0].GetURL(), errRequest)
+	assert.Len(t, r.Route(invokers, consumerURL, inv), 1)
+
+	refreshed := newTestInvokers(2, "outlier.consecutive.errors=1")
+	r.Notify(refreshed)
+	assert.Len(t, r.Route(refreshed, consumerURL, inv), 2)
+}
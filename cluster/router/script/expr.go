@@ -0,0 +1,451 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// checkTimeEvery is the number of steps between two checks of the deadline, as reading the clock is not free
+const checkTimeEvery = 64
+
+var (
+	errStepLimit = perrors.New("script exceeds the step limit")
+	errTimeout   = perrors.New("script exceeds the time limit")
+)
+
+// builtins are the only functions a script can call
+var builtins = map[string]func(args []interface{}) (interface{}, error){
+	"len":       builtinLen,
+	"contains":  stringsBuiltin(strings.Contains),
+	"hasPrefix": stringsBuiltin(strings.HasPrefix),
+	"hasSuffix": stringsBuiltin(strings.HasSuffix),
+	"lower":     stringBuiltin(strings.ToLower),
+	"upper":     stringBuiltin(strings.ToUpper),
+}
+
+// program is a compiled script. A script is a go expression restricted to literals, variables, field and
+// index access, operators and the builtin functions, so it has neither loops nor side effects.
+type program struct {
+	expr ast.Expr
+}
+
+// compile parses @script and rejects everything beyond the restricted expression language
+func compile(script string) (*program, error) {
+	expr, err := parser.ParseExpr(script)
+	if err != nil {
+		return nil, perrors.Wrapf(err, "invalid script %q", script)
+	}
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if err != nil || node == nil {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.BasicLit, *ast.Ident, *ast.ParenExpr, *ast.BinaryExpr, *ast.SelectorExpr, *ast.IndexExpr:
+		case *ast.UnaryExpr:
+			if n.Op != token.NOT && n.Op != token.SUB && n.Op != token.ADD {
+				err = perrors.Errorf("operator %s is not supported", n.Op)
+			}
+		case *ast.CallExpr:
+			ident, ok := n.Fun.(*ast.Ident)
+			if !ok || builtins[ident.Name] == nil || n.Ellipsis.IsValid() {
+				err = perrors.Errorf("only the builtin functions can be called, got %s", exprString(n.Fun))
+			}
+		default:
+			err = perrors.Errorf("%T is not supported in script", node)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, perrors.Wrapf(err, "invalid script %q", script)
+	}
+	return &program{expr: expr}, nil
+}
+
+// evaluator evaluates a program against the variables, within the step and time limits
+type evaluator struct {
+	vars     map[string]interface{}
+	steps    int
+	maxSteps int
+	deadline time.Time
+}
+
+func (e *evaluator) run(p *program) (interface{}, error) {
+	e.steps = 0
+	return e.eval(p.expr)
+}
+
+func (e *evaluator) eval(node ast.Expr) (interface{}, error) {
+	e.steps++
+	if e.maxSteps > 0 && e.steps > e.maxSteps {
+		return nil, errStepLimit
+	}
+	if e.steps%checkTimeEvery == 0 && !e.deadline.IsZero() && time.Now().After(e.deadline) {
+		return nil, errTimeout
+	}
+
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		return literal(n)
+	case *ast.Ident:
+		switch n.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil":
+			return nil, nil
+		}
+		if v, ok := e.vars[n.Name]; ok {
+			return v, nil
+		}
+		return nil, perrors.Errorf("undefined variable %s", n.Name)
+	case *ast.ParenExpr:
+		return e.eval(n.X)
+	case *ast.UnaryExpr:
+		return e.unary(n)
+	case *ast.BinaryExpr:
+		return e.binary(n)
+	case *ast.SelectorExpr:
+		x, err := e.eval(n.X)
+		if err != nil {
+			return nil, err
+		}
+		return field(x, n.Sel.Name)
+	case *ast.IndexExpr:
+		x, err := e.eval(n.X)
+		if err != nil {
+			return nil, err
+		}
+		index, err := e.eval(n.Index)
+		if err != nil {
+			return nil, err
+		}
+		return indexOf(x, index)
+	case *ast.CallExpr:
+		args := make([]interface{}, 0, len(n.Args))
+		for _, arg := range n.Args {
+			v, err := e.eval(arg)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+		}
+		return builtins[n.Fun.(*ast.Ident).Name](args)
+	}
+	return nil, perrors.Errorf("%T is not supported in script", node)
+}
+
+func (e *evaluator) unary(n *ast.UnaryExpr) (interface{}, error) {
+	x, err := e.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+	if n.Op == token.NOT {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, perrors.Errorf("operator ! is not defined on %T", x)
+		}
+		return !b, nil
+	}
+	f, ok := toNumber(x)
+	if !ok {
+		return nil, perrors.Errorf("operator %s is not defined on %T", n.Op, x)
+	}
+	if n.Op == token.SUB {
+		f = -f
+	}
+	return f, nil
+}
+
+func (e *evaluator) binary(n *ast.BinaryExpr) (interface{}, error) {
+	x, err := e.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+	if n.Op == token.LAND || n.Op == token.LOR {
+		l, ok := x.(bool)
+		if !ok {
+			return nil, perrors.Errorf("operator %s is not defined on %T", n.Op, x)
+		}
+		// short circuit
+		if (n.Op == token.LAND && !l) || (n.Op == token.LOR && l) {
+			return l, nil
+		}
+		y, err := e.eval(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := y.(bool)
+		if !ok {
+			return nil, perrors.Errorf("operator %s is not defined on %T", n.Op, y)
+		}
+		return r, nil
+	}
+
+	y, err := e.eval(n.Y)
+	if err != nil {
+		return nil, err
+	}
+	switch n.Op {
+	case token.EQL:
+		return equal(x, y), nil
+	case token.NEQ:
+		return !equal(x, y), nil
+	}
+
+	if xs, ok := x.(string); ok {
+		if ys, ok := y.(string); ok {
+			switch n.Op {
+			case token.ADD:
+				return xs + ys, nil
+			case token.LSS:
+				return xs < ys, nil
+			case token.LEQ:
+				return xs <= ys, nil
+			case token.GTR:
+				return xs > ys, nil
+			case token.GEQ:
+				return xs >= ys, nil
+			}
+			return nil, perrors.Errorf("operator %s is not defined on string", n.Op)
+		}
+	}
+
+	xf, xok := toNumber(x)
+	yf, yok := toNumber(y)
+	if !xok || !yok {
+		return nil, perrors.Errorf("operator %s is not defined on %T and %T", n.Op, x, y)
+	}
+	switch n.Op {
+	case token.LSS:
+		return xf < yf, nil
+	case token.LEQ:
+		return xf <= yf, nil
+	case token.GTR:
+		return xf > yf, nil
+	case token.GEQ:
+		return xf >= yf, nil
+	case token.ADD:
+		return xf + yf, nil
+	case token.SUB:
+		return xf - yf, nil
+	case token.MUL:
+		return xf * yf, nil
+	case token.QUO, token.REM:
+		if yf == 0 {
+			return nil, perrors.New("division by zero")
+		}
+		if n.Op == token.QUO {
+			return xf / yf, nil
+		}
+		// the remainder is of the integer parts, e.g. the divisor 0.5 is 0
+		if int64(yf) == 0 {
+			return nil, perrors.New("division by zero")
+		}
+		return float64(int64(xf) % int64(yf)), nil
+	}
+	return nil, perrors.Errorf("operator %s is not supported", n.Op)
+}
+
+func literal(n *ast.BasicLit) (interface{}, error) {
+	switch n.Kind {
+	case token.INT, token.FLOAT:
+		return strconv.ParseFloat(n.Value, 64)
+	case token.STRING, token.CHAR:
+		s, err := strconv.Unquote(n.Value)
+		return s, err
+	}
+	return nil, perrors.Errorf("literal %s is not supported", n.Value)
+}
+
+// toNumber converts numbers, and strings of numbers like the url params, to float64
+func toNumber(v interface{}) (float64, bool) {
+	if s, ok := v.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// equal compares numbers by value, a number equals a string of the same number, e.g. 1 == "1"
+func equal(x, y interface{}) bool {
+	if isNil(x) || isNil(y) {
+		return isNil(x) && isNil(y)
+	}
+	xs, xIsString := x.(string)
+	ys, yIsString := y.(string)
+	if xIsString && yIsString {
+		return xs == ys
+	}
+	if xf, ok := toNumber(x); ok {
+		if yf, ok := toNumber(y); ok {
+			return xf == yf
+		}
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// field returns the map value of key @name, or the exported struct field @name, whose first letter may be lower case
+func field(x interface{}, name string) (interface{}, error) {
+	if isNil(x) {
+		return nil, perrors.Errorf("cannot access field %s of nil", name)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(x))
+	switch rv.Kind() {
+	case reflect.Map:
+		return indexOf(x, name)
+	case reflect.Struct:
+		for _, n := range []string{name, exported(name)} {
+			if !ast.IsExported(n) {
+				continue
+			}
+			if f := rv.FieldByName(n); f.IsValid() {
+				return f.Interface(), nil
+			}
+		}
+	}
+	return nil, perrors.Errorf("%T has no field %s", x, name)
+}
+
+func exported(name string) string {
+	if len(name) == 0 {
+		return name
+	}
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// indexOf returns the element @index of a slice or an array, or the value of key @index of a map, which is nil
+// if the key is absent
+func indexOf(x interface{}, index interface{}) (interface{}, error) {
+	if isNil(x) {
+		return nil, perrors.Errorf("cannot index nil by %v", index)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(x))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		f, ok := toNumber(index)
+		i := int(f)
+		if !ok || float64(i) != f || i < 0 || i >= rv.Len() {
+			return nil, perrors.Errorf("index %v out of range [0, %d)", index, rv.Len())
+		}
+		if rv.Kind() == reflect.String {
+			return string(rv.String()[i]), nil
+		}
+		return rv.Index(i).Interface(), nil
+	case reflect.Map:
+		key := reflect.ValueOf(index)
+		if rv.Type().Key().Kind() == reflect.String {
+			key = reflect.ValueOf(fmt.Sprint(index)).Convert(rv.Type().Key())
+		} else if index == nil || !key.Type().ConvertibleTo(rv.Type().Key()) {
+			return nil, perrors.Errorf("invalid key %v of %T", index, x)
+		} else {
+			key = key.Convert(rv.Type().Key())
+		}
+		v := rv.MapIndex(key)
+		if !v.IsValid() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	}
+	return nil, perrors.Errorf("%T can not be indexed", x)
+}
+
+func builtinLen(args []interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, perrors.Errorf("len expects 1 argument, got %d", len(args))
+	}
+	if isNil(args[0]) {
+		return float64(0), nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(args[0]))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return float64(rv.Len()), nil
+	}
+	return nil, perrors.Errorf("len is not defined on %T", args[0])
+}
+
+func stringsBuiltin(f func(s, t string) bool) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, perrors.Errorf("expects 2 string arguments, got %d", len(args))
+		}
+		s, ok1 := args[0].(string)
+		t, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, perrors.Errorf("expects 2 string arguments, got %T and %T", args[0], args[1])
+		}
+		return f(s, t), nil
+	}
+}
+
+func stringBuiltin(f func(s string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, perrors.Errorf("expects 1 string argument, got %d", len(args))
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, perrors.Errorf("expects 1 string argument, got %T", args[0])
+		}
+		return f(s), nil
+	}
+}
+
+func exprString(node ast.Expr) string {
+	if ident, ok := node.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return fmt.Sprintf("%T", node)
+}
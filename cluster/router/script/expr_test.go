@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	Name   string
+	Region string
+	Age    int32
+	Tags   []string
+	secret string
+}
+
+func evaluate(t *testing.T, script string, vars map[string]interface{}) (interface{}, error) {
+	p, err := compile(script)
+	assert.NoError(t, err)
+	e := &evaluator{vars: vars, maxSteps: 1000}
+	return e.run(p)
+}
+
+func TestCompile(t *testing.T) {
+	for _, script := range []string{
+		`a == 1 && !b || c != "x"`,
+		`args[0].region == provider.params["region"]`,
+		`len(args) > 0 && hasPrefix(lower(method), "get")`,
+		`-a + 2 * (b - c) / 4 % 3 >= 0`,
+	} {
+		_, err := compile(script)
+		assert.NoError(t, err, script)
+	}
+	for _, script := range []string{
+		``,
+		`a ==`,
+		`func() bool { return true }()`,
+		`os.Exit(1)`,
+		`panic("x")`,
+		`[]int{1, 2}`,
+		`a.(string)`,
+		`*a`,
+		`a[1:2]`,
+		`<-a`,
+		`len(a...)`,
+	} {
+		_, err := compile(script)
+		assert.Error(t, err, script)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]interface{}{
+		"method":      "GetUser",
+		"args":        []interface{}{&user{Name: "Alex", Region: "hz", Age: 18, Tags: []string{"vip"}, secret: "s"}, map[string]interface{}{"id": 1}},
+		"attachments": map[string]interface{}{"tenant": "t1"},
+		"provider":    map[string]interface{}{"port": "20000", "params": map[string]string{"region": "hz", "weight": "100"}},
+	}
+	cases := []struct {
+		script string
+		want   interface{}
+	}{
+		{`args[0].region == provider.params.region`, true},
+		{`args[0].Name + "!"`, "Alex!"},
+		{`args[0].age >= 18 && args[0].age < 60`, true},
+		{`args[0].tags[0] == "vip"`, true},
+		{`len(args[0].tags)`, float64(1)},
+		{`args[1].id == 1`, true},
+		{`args[1]["absent"] == nil`, true},
+		{`attachments.tenant == "t1" || attachments.tenant == "t2"`, true},
+		{`provider.params.weight > 50`, true},
+		{`provider.port == 20000`, true},
+		{`hasPrefix(lower(method), "get") && contains(method, "User") && !hasSuffix(method, "x")`, true},
+		{`upper(provider.params["region"])`, "HZ"},
+		{`-2 + 3 * 4 / 2 % 4`, float64(0)},
+		{`false && undefined`, false},
+		{`"a" < "b"`, true},
+	}
+	for _, c := range cases {
+		v, err := evaluate(t, c.script, vars)
+		assert.NoError(t, err, c.script)
+		assert.Equal(t, c.want, v, c.script)
+	}
+
+	for _, script := range []string{
+		`undefined == 1`,
+		`args[0].secret == "s"`,
+		`args[0].absent == 1`,
+		`args[5] == nil`,
+		`args[0].Age / 0`,
+		`args[0].Age % 0`,
+		`args[0].Age % 0.5`,
+		`!method`,
+		`method - 1`,
+		`len(1)`,
+		`contains(method)`,
+		`attachments.absent.field`,
+	} {
+		_, err := evaluate(t, script, vars)
+		assert.Error(t, err, script)
+	}
+}
+
+func TestEvaluateLimits(t *testing.T) {
+	p, err := compile(`a + a + a + a + a + a + a + a > 0`)
+	assert.NoError(t, err)
+
+	e := &evaluator{vars: map[string]interface{}{"a": 1}, maxSteps: 10}
+	_, err = e.run(p)
+	assert.Equal(t, errStepLimit, err)
+	// the steps are counted per run
+	e.maxSteps = 100
+	_, err = e.run(p)
+	assert.NoError(t, err)
+	_, err = e.run(p)
+	assert.NoError(t, err)
+
+	script := "a"
+	for i := 0; i < 100; i++ {
+		script += " + a"
+	}
+	p, err = compile(script)
+	assert.NoError(t, err)
+	e = &evaluator{vars: map[string]interface{}{"a": 1}, maxSteps: 1000, deadline: time.Now().Add(-time.Millisecond)}
+	_, err = e.run(p)
+	assert.Equal(t, errTimeout, err)
+}
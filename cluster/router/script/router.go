@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// warnInterval is the minimum interval between two warnings of the failed evaluations
+const warnInterval = 10 * time.Second
+
+// PriorityRouter keeps the providers for which the script of the service is true
+type PriorityRouter struct {
+	rules sync.Map
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+	// lastWarn is the unix nano of the last warning, the warnings are rate limited to protect the call path
+	lastWarn atomic.Int64
+}
+
+func NewScriptPriorityRouter() (*PriorityRouter, error) {
+	return &PriorityRouter{}, nil
+}
+
+// Route evaluates the script against each provider. It fails open, all providers are returned if the
+// evaluation fails.
+func (p *PriorityRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	value, ok := p.rules.Load(config_center.ServiceRuleKey(invokers[0].GetURL(), constant.ScriptRouterRuleSuffix))
+	if !ok {
+		return invokers
+	}
+	rule := value.(*Rule)
+	if !rule.Enabled {
+		return invokers
+	}
+
+	e := &evaluator{
+		vars: map[string]interface{}{
+			"method":      invocation.MethodName(),
+			"args":        invocation.Arguments(),
+			"attachments": invocation.Attachments(),
+			"consumer":    urlVars(url),
+		},
+		maxSteps: rule.MaxSteps,
+		deadline: time.Now().Add(rule.timeout),
+	}
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		e.vars["provider"] = urlVars(invoker.GetURL())
+		v, err := e.run(rule.program)
+		if err != nil {
+			p.warn("[script router]Evaluate script %q of %s error, %v, all providers are used.", rule.Script, rule.Key, err)
+			return invokers
+		}
+		keep, ok := v.(bool)
+		if !ok {
+			p.warn("[script router]Script %q of %s returns %T instead of bool, all providers are used.", rule.Script, rule.Key, v)
+			return invokers
+		}
+		if keep {
+			result = append(result, invoker)
+		}
+	}
+	if len(result) == 0 && !rule.Force {
+		return invokers
+	}
+	return result
+}
+
+// warn logs at most one warning in warnInterval
+func (p *PriorityRouter) warn(format string, args ...interface{}) {
+	now := time.Now().UnixNano()
+	last := p.lastWarn.Load()
+	if now-last < int64(warnInterval) || !p.lastWarn.CAS(last, now) {
+		return
+	}
+	logger.Warnf(format, args...)
+}
+
+func (p *PriorityRouter) URL() *common.URL {
+	return nil
+}
+
+func (p *PriorityRouter) Priority() int64 {
+	return 160
+}
+
+// Notify subscribes the script rule of the service of the invokers
+func (p *PriorityRouter) Notify(invokers []protocol.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
+		return
+	}
+	p.subscriptions.Subscribe(dynamicConfiguration, config_center.ServiceRuleKey(invokers[0].GetURL(), constant.ScriptRouterRuleSuffix), p, 0)
+}
+
+// Process compiles and caches the script rule, a deleted or malformed rule stops the routing
+func (p *PriorityRouter) Process(event *config_center.ConfigChangeEvent) {
+	if event.ConfigType == remoting.EventTypeDel {
+		p.rules.Delete(event.Key)
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok || len(content) == 0 {
+		p.rules.Delete(event.Key)
+		return
+	}
+	rule, err := parseRule(content)
+	if err != nil {
+		p.rules.Delete(event.Key)
+		logger.Warnf("[script router]Parse script rule error, %+v and we will stop the script routing.", err)
+		return
+	}
+	p.rules.Store(event.Key, rule)
+	logger.Infof("[script router]Parse script rule success,key=%s,script=%s", event.Key, rule.Script)
+}
+
+// urlVars exposes @url to the script
+func urlVars(url *common.URL) map[string]interface{} {
+	if url == nil {
+		return nil
+	}
+	params := make(map[string]string)
+	url.RangeParams(func(key, value string) bool {
+		params[key] = value
+		return true
+	})
+	return map[string]interface{}{
+		"protocol": url.Protocol,
+		"host":     url.Ip,
+		"port":     url.Port,
+		"address":  url.Location,
+		"service":  url.Service(),
+		"group":    url.GetParam(constant.GroupKey, ""),
+		"version":  url.GetParam(constant.VersionKey, ""),
+		"params":   params,
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	ruleKeyOfUserProvider = "com.xxx.xxx.UserProvider:3.1.0:.script-router"
+	providerFormat        = "dubbo://192.168.0.%d:20000/com.xxx.xxx.UserProvider?interface=com.xxx.xxx.UserProvider&version=3.1.0&region=%s"
+)
+
+type request struct {
+	Region string
+}
+
+func newInvokers() []protocol.Invoker {
+	var invokers []protocol.Invoker
+	for i, region := range []string{"hz", "hz", "sh"} {
+		url, _ := common.NewURL(fmt.Sprintf(providerFormat, i, region))
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func newRouterWithRule(t *testing.T, rule string) *PriorityRouter {
+	p, err := NewScriptPriorityRouter()
+	assert.Nil(t, err)
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider, Value: rule, ConfigType: remoting.EventTypeAdd})
+	return p
+}
+
+func route(p *PriorityRouter, invokers []protocol.Invoker, region string) []protocol.Invoker {
+	consumerURL, _ := common.NewURL("consumer://127.0.0.1/com.xxx.xxx.UserProvider?version=3.1.0&region=hz")
+	return p.Route(invokers, consumerURL, invocation.NewRPCInvocation("GetUser", []interface{}{&request{Region: region}}, nil))
+}
+
+func TestParseRule(t *testing.T) {
+	rule, err := parseRule(`
+key: com.xxx.xxx.UserProvider
+script: args[0].region == provider.params.region`)
+	assert.NoError(t, err)
+	assert.True(t, rule.Enabled)
+	assert.False(t, rule.Force)
+	assert.Equal(t, 1000, rule.MaxSteps)
+	assert.NotNil(t, rule.program)
+
+	for _, content := range []string{
+		`key: com.xxx.xxx.UserProvider`,
+		"script: a ==",
+		"script: os.Exit(1)",
+		"script: a\nmaxSteps: 0",
+		"script: a\ntimeout: abc",
+	} {
+		_, err = parseRule(content)
+		assert.Error(t, err, content)
+	}
+}
+
+func TestScriptRoute(t *testing.T) {
+	invokers := newInvokers()
+	p := newRouterWithRule(t, `
+key: com.xxx.xxx.UserProvider
+script: args[0].region == provider.params.region && consumer.service == provider.service`)
+
+	assert.Len(t, route(p, invokers, "hz"), 2)
+	result := route(p, invokers, "sh")
+	assert.Len(t, result, 1)
+	assert.Equal(t, "sh", result[0].GetURL().GetParam("region", ""))
+	// no provider is kept, fall back to all providers
+	assert.Len(t, route(p, invokers, "bj"), 3)
+
+	p = newRouterWithRule(t, `
+key: com.xxx.xxx.UserProvider
+force: true
+script: args[0].region == provider.params.region`)
+	assert.Len(t, route(p, invokers, "bj"), 0)
+
+	p = newRouterWithRule(t, `
+key: com.xxx.xxx.UserProvider
+enabled: false
+script: args[0].region == provider.params.region`)
+	assert.Len(t, route(p, invokers, "sh"), 3)
+}
+
+func TestScriptRouteFailOpen(t *testing.T) {
+	invokers := newInvokers()
+	for _, script := range []string{
+		`args[1].region == provider.params.region`,
+		`provider.params.region`,
+		`args[0].region == provider.params.region || args[0].region + args[0].region + args[0].region == ""`,
+	} {
+		p := newRouterWithRule(t, fmt.Sprintf("key: com.xxx.xxx.UserProvider\nmaxSteps: 8\nscript: %s", script))
+		assert.Len(t, route(p, invokers, "bj"), 3, script)
+	}
+
+	// a malformed rule stops the routing
+	p := newRouterWithRule(t, "script: a ==")
+	assert.Len(t, route(p, invokers, "sh"), 3)
+}
+
+func TestScriptRouterProcess(t *testing.T) {
+	invokers := newInvokers()
+	p := newRouterWithRule(t, `script: args[0].region == provider.params.region`)
+	assert.Len(t, route(p, invokers, "sh"), 1)
+
+	p.Process(&config_center.ConfigChangeEvent{Key: ruleKeyOfUserProvider, ConfigType: remoting.EventTypeDel})
+	assert.Len(t, route(p, invokers, "sh"), 3)
+}
+
+func TestScriptRouterNotify(t *testing.T) {
+	config := &config_center.MockDynamicConfigurationFactory{
+		Content: `script: args[0].region == provider.params.region`,
+	}
+	url, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, err := config.GetDynamicConfiguration(url)
+	assert.NoError(t, err)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	invokers := newInvokers()
+	p, err := NewScriptPriorityRouter()
+	assert.NoError(t, err)
+	p.Notify(invokers)
+	assert.Len(t, route(p, invokers, "sh"), 1)
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package script
+
+import (
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// Rule is the script rule of a service, e.g.
+//
+//	key: org.apache.dubbo.UserProvider
+//	enabled: true
+//	force: false
+//	script: args[0].region == provider.params.region
+//	maxSteps: 1000
+//	timeout: 5ms
+//
+// The script is evaluated against each provider, and the providers for which it is true are kept. These
+// variables are available in the script:
+//
+//	method       the method name of the invocation
+//	args         the arguments of the invocation
+//	attachments  the attachments of the invocation
+//	consumer     the consumer url
+//	provider     the provider url
+//
+// A url has the fields protocol, host, port, address, service, group, version and params. Struct fields and
+// map values are accessed by selectors or indexes, like args[0].region or provider.params["region"], and the
+// builtin functions len, contains, hasPrefix, hasSuffix, lower and upper can be called.
+//
+// MaxSteps limits the evaluation steps of a provider, and Timeout limits the evaluation of all providers of a
+// request. If the evaluation fails, all providers are returned. If no provider is kept, all providers are
+// returned too unless Force is true.
+type Rule struct {
+	Key      string `yaml:"key"`
+	Enabled  bool   `default:"true" yaml:"enabled"`
+	Force    bool   `yaml:"force"`
+	Script   string `yaml:"script"`
+	MaxSteps int    `default:"1000" yaml:"maxSteps"`
+	Timeout  string `default:"5ms" yaml:"timeout"`
+
+	program *program
+	timeout time.Duration
+}
+
+// parseRule parses the rule and compiles its script
+func parseRule(content string) (*Rule, error) {
+	rule := &Rule{}
+	if err := defaults.Set(rule); err != nil {
+		return nil, err
+	}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(rule.Script)) == 0 {
+		return nil, perrors.New("script rule must have a script")
+	}
+	if rule.MaxSteps <= 0 {
+		return nil, perrors.Errorf("script max steps %d must be positive", rule.MaxSteps)
+	}
+	timeout, err := common.ParseDuration(rule.Timeout)
+	if err != nil || timeout <= 0 {
+		return nil, perrors.Errorf("script timeout %s must be a positive duration", rule.Timeout)
+	}
+	rule.timeout = timeout
+	if rule.program, err = compile(rule.Script); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
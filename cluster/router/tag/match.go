@@ -123,18 +123,26 @@ func requestTag(invokers []protocol.Invoker, url *common.URL, invocation protoco
 	if len(result) != 0 {
 		return result
 	}
-	// failover: return all Providers without any tags
+	// failover: return all Providers without any tags, neither static tags nor addresses of any dynamic tag
 	result = filterInvokers(invokers, tag, func(invoker protocol.Invoker, tag interface{}) bool {
 		return invoker.GetURL().GetParam(constant.Tagkey, "") != ""
 	})
-	if len(addresses) == 0 {
-		return result
+	if ruleAddresses := allAddresses(cfg); len(ruleAddresses) != 0 {
+		result = filterInvokers(result, ruleAddresses, getAddressPredicate(true))
 	}
-	result = filterInvokers(invokers, addresses, getAddressPredicate(true))
 	logger.Debugf("[tag router] failover match all providers without any tags, invokers=%+v", result)
 	return result
 }
 
+// allAddresses returns the addresses of all tags in the rule
+func allAddresses(cfg config.RouterConfig) []string {
+	var addresses []string
+	for _, tagCfg := range cfg.Tags {
+		addresses = append(addresses, tagCfg.Addresses...)
+	}
+	return addresses
+}
+
 func filterInvokers(invokers []protocol.Invoker, param interface{}, predicate predicate) []protocol.Invoker {
 	result := make([]protocol.Invoker, len(invokers))
 	copy(result, invokers)
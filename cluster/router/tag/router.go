@@ -23,6 +23,8 @@ import (
 )
 
 import (
+	"github.com/creasty/defaults"
+
 	"github.com/dubbogo/gost/log/logger"
 
 	"gopkg.in/yaml.v2"
@@ -40,6 +42,8 @@ import (
 
 type PriorityRouter struct {
 	routerConfigs sync.Map
+	// subscriptions are the rules subscribed from the config center, or applied from the local rules
+	subscriptions config_center.RuleSubscriptions
 }
 
 func NewTagPriorityRouter() (*PriorityRouter, error) {
@@ -52,8 +56,8 @@ func (p *PriorityRouter) Route(invokers []protocol.Invoker, url *common.URL, inv
 		logger.Warnf("[tag router] invokers from previous router is empty")
 		return invokers
 	}
-	// tag is valid in application
-	key := strings.Join([]string{url.GetParam(constant.ApplicationKey, ""), constant.TagRouterRuleSuffix}, "")
+	// tag is valid in application, the rule is keyed by the application of the providers
+	key := strings.Join([]string{invokers[0].GetURL().GetParam(constant.ApplicationKey, ""), constant.TagRouterRuleSuffix}, "")
 	value, ok := p.routerConfigs.Load(key)
 	if !ok {
 		return staticTag(invokers, url, invocation)
@@ -82,30 +86,43 @@ func (p *PriorityRouter) Notify(invokers []protocol.Invoker) {
 		logger.Error("url application is empty")
 		return
 	}
+	key := strings.Join([]string{application, constant.TagRouterRuleSuffix}, "")
+	rule, local := config.GetRouterRule(key)
 	dynamicConfiguration := conf.GetEnvInstance().GetDynamicConfiguration()
-	if dynamicConfiguration == nil {
+	if dynamicConfiguration == nil && !local {
 		logger.Warnf("config center does not start, please check if the configuration center has been properly configured in dubbogo.yml")
 		return
 	}
-	key := strings.Join([]string{application, constant.TagRouterRuleSuffix}, "")
-	dynamicConfiguration.AddListener(key, p)
-	value, err := dynamicConfiguration.GetRule(key)
-	if err != nil {
-		logger.Errorf("query router rule fail,key=%s,err=%v", key, err)
+	if dynamicConfiguration == nil || (local && !rule.Subscribe) {
+		if p.subscriptions.Add(key) {
+			p.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule.Content, ConfigType: remoting.EventTypeAdd})
+		}
 		return
 	}
-	p.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
+	// the local rule is used if the config center has no rule or the query fails
+	p.subscriptions.Subscribe(dynamicConfiguration, key, p, config_center.DefaultCoalescingWindow)
 }
 
 func (p *PriorityRouter) Process(event *config_center.ConfigChangeEvent) {
-	if event.ConfigType == remoting.EventTypeDel {
-		p.routerConfigs.Delete(event.Key)
+	if event.Unchanged() {
 		return
 	}
-	routerConfig, err := parseRoute(event.Value.(string))
+	content, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || len(content) == 0 {
+		// the local rule is used unless the config center has the rule
+		rule, ok := config.GetRouterRule(event.Key)
+		if !ok {
+			p.routerConfigs.Delete(event.Key)
+			return
+		}
+		content = rule.Content
+	}
+	routerConfig, err := parseRoute(content)
 	if err != nil {
+		// a malformed rule must not keep routing by an outdated one, fall back to static tag matching
+		p.routerConfigs.Delete(event.Key)
 		logger.Warnf("[tag router]Parse new tag route config error, %+v "+
-			"and we will use the original tag rule configuration.", err)
+			"and we will fall back to static tag matching.", err)
 		return
 	}
 	p.routerConfigs.Store(event.Key, *routerConfig)
@@ -115,6 +132,10 @@ func (p *PriorityRouter) Process(event *config_center.ConfigChangeEvent) {
 func parseRoute(routeContent string) (*config.RouterConfig, error) {
 	routeDecoder := yaml.NewDecoder(strings.NewReader(routeContent))
 	routerConfig := &config.RouterConfig{}
+	// force, enabled and so on may be absent in the rule, use their default values then
+	if err := defaults.Set(routerConfig); err != nil {
+		return nil, err
+	}
 	err := routeDecoder.Decode(routerConfig)
 	if err != nil {
 		return nil, err
@@ -124,5 +145,10 @@ func parseRoute(routeContent string) (*config.RouterConfig, error) {
 	if len(routerConfig.Tags) == 0 {
 		*routerConfig.Valid = false
 	}
+	for _, tag := range routerConfig.Tags {
+		if len(tag.Name) == 0 {
+			*routerConfig.Valid = false
+		}
+	}
 	return routerConfig, nil
 }
@@ -18,6 +18,7 @@
 package tag
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -36,6 +37,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/config_center/configurator"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 var (
@@ -398,3 +400,165 @@ tags:
 		assert.True(t, value == nil)
 	})
 }
+
+func TestParseRoute(t *testing.T) {
+	// copied from TagRouterTest#tagRouterRuleParseTest of apache/dubbo for interoperability
+	content := "---\n" +
+		"force: false\n" +
+		"runtime: true\n" +
+		"enabled: false\n" +
+		"priority: 1\n" +
+		"key: demo-provider\n" +
+		"tags:\n" +
+		"  - name: tag1\n" +
+		"    addresses: null\n" +
+		"  - name: tag2\n" +
+		"    addresses: [\"30.5.120.37:20880\"]\n" +
+		"  - name: tag3\n" +
+		"    addresses: []\n" +
+		"  - name: tag4\n" +
+		"    addresses: ~\n" +
+		"..."
+	routerCfg, err := parseRoute(content)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo-provider", routerCfg.Key)
+	assert.Equal(t, 1, routerCfg.Priority)
+	assert.False(t, *routerCfg.Force)
+	assert.True(t, *routerCfg.Runtime)
+	assert.False(t, *routerCfg.Enabled)
+	assert.True(t, *routerCfg.Valid)
+	assert.Len(t, routerCfg.Tags, 4)
+	names := make([]string, 0, len(routerCfg.Tags))
+	for _, tag := range routerCfg.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"tag1", "tag2", "tag3", "tag4"}, names)
+	assert.Empty(t, routerCfg.Tags[0].Addresses)
+	assert.Equal(t, []string{"30.5.120.37:20880"}, routerCfg.Tags[1].Addresses)
+	assert.Empty(t, routerCfg.Tags[2].Addresses)
+	assert.Empty(t, routerCfg.Tags[3].Addresses)
+	assert.Equal(t, []string{"30.5.120.37:20880"}, allAddresses(*routerCfg))
+
+	t.Run("defaults", func(t *testing.T) {
+		routerCfg, err := parseRoute("key: demo-provider\ntags:\n  - name: tag1\n")
+		assert.Nil(t, err)
+		assert.False(t, *routerCfg.Force)
+		assert.True(t, *routerCfg.Enabled)
+		assert.True(t, *routerCfg.Valid)
+	})
+
+	t.Run("noTags", func(t *testing.T) {
+		routerCfg, err := parseRoute("key: demo-provider\nenabled: true\n")
+		assert.Nil(t, err)
+		assert.False(t, *routerCfg.Valid)
+	})
+
+	t.Run("emptyTagName", func(t *testing.T) {
+		routerCfg, err := parseRoute("key: demo-provider\ntags:\n  - addresses: [\"30.5.120.37:20880\"]\n")
+		assert.Nil(t, err)
+		assert.False(t, *routerCfg.Valid)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	initUrl()
+	key := constant.TagRouterRuleSuffix
+	ivk := protocol.NewBaseInvoker(url1)
+	ivk1 := protocol.NewBaseInvoker(url2)
+	ivk2 := protocol.NewBaseInvoker(url3)
+	invokerList := []protocol.Invoker{ivk, ivk1, ivk2}
+	attachments := map[string]interface{}{constant.Tagkey: "tag1"}
+	rule := `
+force: %v
+enabled: %v
+tags:
+  - name: tag1
+    addresses: [192.168.0.4:20000]
+  - name: tag2
+    addresses: [192.168.0.3:20000]`
+
+	t.Run("notForce", func(t *testing.T) {
+		p, err := NewTagPriorityRouter()
+		assert.Nil(t, err)
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: fmt.Sprintf(rule, false, true), ConfigType: remoting.EventTypeAdd})
+		result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+		// no provider of tag1, fall back to the providers without any tag
+		assert.Equal(t, []protocol.Invoker{ivk, ivk1}, result)
+	})
+
+	t.Run("force", func(t *testing.T) {
+		p, err := NewTagPriorityRouter()
+		assert.Nil(t, err)
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: fmt.Sprintf(rule, true, true), ConfigType: remoting.EventTypeAdd})
+		result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+		assert.Empty(t, result)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, err := NewTagPriorityRouter()
+		assert.Nil(t, err)
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: fmt.Sprintf(rule, true, false), ConfigType: remoting.EventTypeAdd})
+		result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		p, err := NewTagPriorityRouter()
+		assert.Nil(t, err)
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: fmt.Sprintf(rule, true, true), ConfigType: remoting.EventTypeAdd})
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: "tags: [", ConfigType: remoting.EventTypeUpdate})
+		_, ok := p.routerConfigs.Load(key)
+		assert.False(t, ok)
+		result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		p, err := NewTagPriorityRouter()
+		assert.Nil(t, err)
+		p.Process(&config_center.ConfigChangeEvent{Key: key, Value: fmt.Sprintf(rule, true, true), ConfigType: remoting.EventTypeAdd})
+		p.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeDel})
+		result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+		assert.Len(t, result, 3)
+	})
+}
+
+func TestLocalRuleOverride(t *testing.T) {
+	initUrl()
+	key := "org.apache.dubbo.UserProvider.Test" + constant.TagRouterRuleSuffix
+	ivk := protocol.NewBaseInvoker(url1)
+	ivk1 := protocol.NewBaseInvoker(url2)
+	ivk2 := protocol.NewBaseInvoker(url3)
+	for _, invoker := range []protocol.Invoker{ivk, ivk1, ivk2} {
+		invoker.GetURL().SetParam(constant.ApplicationKey, "org.apache.dubbo.UserProvider.Test")
+	}
+	invokerList := []protocol.Invoker{ivk, ivk1, ivk2}
+	attachments := map[string]interface{}{constant.Tagkey: "gray"}
+	force := true
+	assert.NoError(t, config.SetRouterRules([]*config.RouterConfig{{Scope: constant.RouterScopeApplication,
+		Key: "org.apache.dubbo.UserProvider.Test", Force: &force, Enabled: &force,
+		Tags: []config.Tag{{Name: "gray", Addresses: []string{"192.168.0.1:20000"}}}}}))
+	defer config.SetRouterRules(nil)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	// the local rule is used without the config center
+	p, err := NewTagPriorityRouter()
+	assert.Nil(t, err)
+	p.Notify(invokerList)
+	result := p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+	assert.Equal(t, []protocol.Invoker{ivk}, result)
+
+	// the rule of the config center overrides it at runtime
+	p.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeUpdate, Value: `
+force: true
+tags:
+  - name: gray
+    addresses: [192.168.0.2:20000]`})
+	result = p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+	assert.Equal(t, []protocol.Invoker{ivk1}, result)
+
+	// the local rule is used again once the rule of the config center is deleted
+	p.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeDel})
+	result = p.Route(invokerList, consumerUrl, invocation.NewRPCInvocation("GetUser", nil, attachments))
+	assert.Equal(t, []protocol.Invoker{ivk}, result)
+}
@@ -38,7 +38,9 @@ type Environment struct {
 	// externalConfigs      sync.Map
 	externalConfigMap    sync.Map
 	appExternalConfigMap sync.Map
-	dynamicConfiguration config_center.DynamicConfiguration
+	// dynamicConfigurationLock guards dynamicConfiguration, which is set by the config center in background
+	dynamicConfigurationLock sync.RWMutex
+	dynamicConfiguration     config_center.DynamicConfiguration
 }
 
 var (
@@ -97,11 +99,27 @@ func (env *Environment) Configuration() *list.List {
 
 // SetDynamicConfiguration sets value for dynamicConfiguration
 func (env *Environment) SetDynamicConfiguration(dc config_center.DynamicConfiguration) {
+	env.dynamicConfigurationLock.Lock()
+	defer env.dynamicConfigurationLock.Unlock()
 	env.dynamicConfiguration = dc
 }
 
+// SetDynamicConfigurationIfAbsent sets dynamicConfiguration to @dc unless it is set already, it returns the
+// dynamicConfiguration in use and whether it is @dc
+func (env *Environment) SetDynamicConfigurationIfAbsent(dc config_center.DynamicConfiguration) (config_center.DynamicConfiguration, bool) {
+	env.dynamicConfigurationLock.Lock()
+	defer env.dynamicConfigurationLock.Unlock()
+	if env.dynamicConfiguration != nil {
+		return env.dynamicConfiguration, false
+	}
+	env.dynamicConfiguration = dc
+	return dc, true
+}
+
 // GetDynamicConfiguration gets dynamicConfiguration
 func (env *Environment) GetDynamicConfiguration() config_center.DynamicConfiguration {
+	env.dynamicConfigurationLock.RLock()
+	defer env.dynamicConfigurationLock.RUnlock()
 	return env.dynamicConfiguration
 }
 
@@ -25,6 +25,7 @@ const (
 	ClusterKeyFailover        = "failover"
 	ClusterKeyFailsafe        = "failsafe"
 	ClusterKeyForking         = "forking"
+	ClusterKeyMergeable       = "mergeable"
 	ClusterKeyZoneAware       = "zoneAware"
 	ClusterKeyAdaptiveService = "adaptiveService"
 )
@@ -45,6 +45,11 @@ const (
 	DefaultRestClient       = "resty"
 	DefaultRestServer       = "go-restful"
 	DefaultPort             = 20000
+	DefaultWebsocketPath    = "/dubbo"
+)
+
+const (
+	TransportWebsocket = "websocket"
 )
 
 const (
@@ -19,17 +19,28 @@ package constant
 
 // nolint
 const (
-	ConfigFileEnvKey     = "DUBBO_GO_CONFIG_PATH" // key of environment variable dubbogo configure file path
-	AppLogConfFile       = "AppLogConfFile"
-	PodNameEnvKey        = "POD_NAME"
-	PodNamespaceEnvKey   = "POD_NAMESPACE"
-	ClusterDomainKey     = "CLUSTER_DOMAIN"
-	DefaultClusterDomain = "cluster.local"
-	DefaultNamespace     = "default"
-	SVC                  = "svc"
-	DefaultMeshPort      = 80
+	ConfigFileEnvKey      = "DUBBO_GO_CONFIG_PATH"      // key of environment variable dubbogo configure file path
+	PlaceholderFileEnvKey = "DUBBO_GO_PLACEHOLDER_PATH" // key of environment variable of the properties file the placeholders are resolved from
+	ProfilesActiveEnvKey  = "DUBBO_GO_PROFILES_ACTIVE"  // key of environment variable of the active profiles separated by the commas
+	AppLogConfFile        = "AppLogConfFile"
+	PodNameEnvKey         = "POD_NAME"
+	PodNamespaceEnvKey    = "POD_NAMESPACE"
+	ClusterDomainKey      = "CLUSTER_DOMAIN"
+	DefaultClusterDomain  = "cluster.local"
+	DefaultNamespace      = "default"
+	SVC                   = "svc"
+	DefaultMeshPort       = 80
 
 	DubboIpToRegistryKey       = "DUBBO_IP_TO_REGISTRY"
 	DubboPortToRegistryKey     = "DUBBO_PORT_TO_REGISTRY"
 	DubboDefaultPortToRegistry = "80"
+
+	// keys of environment variables of the config path of http(s) url
+	ConfigTokenEnvKey           = "DUBBO_GO_CONFIG_TOKEN"            // bearer token of the requests
+	ConfigFetchTimeoutEnvKey    = "DUBBO_GO_CONFIG_FETCH_TIMEOUT"    // timeout of each request, 5s by default
+	ConfigFetchAttemptsEnvKey   = "DUBBO_GO_CONFIG_FETCH_ATTEMPTS"   // max attempts to fetch the config at startup, 3 by default
+	ConfigRefreshIntervalEnvKey = "DUBBO_GO_CONFIG_REFRESH_INTERVAL" // interval of refreshing the config, no refresh by default
+	ConfigSnapshotDirEnvKey     = "DUBBO_GO_CONFIG_SNAPSHOT_DIR"     // directory of the snapshot, ~/.dubbo/config/snapshot by default
+
+	ShutdownDiagnosticsEnvKey = "DUBBO_GO_SHUTDOWN_DIAGNOSTICS" // key of environment variable enabling the shutdown diagnostics if it is true
 )
@@ -39,6 +39,7 @@ const (
 	CategoryKey            = "category"
 	CheckKey               = "check"
 	EnabledKey             = "enabled"
+	DisabledKey            = "disabled"
 	SideKey                = "side"
 	OverrideProvidersKey   = "providerAddresses"
 	BeanNameKey            = "bean.name"
@@ -62,14 +63,19 @@ const (
 	MaxServerSendMsgSize   = "max-server-send-msg-size"
 	MaxCallRecvMsgSize     = "max-call-recv-msg-size"
 	MaxServerRecvMsgSize   = "max-server-recv-msg-size"
+	HostToRegistryKey      = "host-to-registry" // key of the host of the protocol config advertised to the registries
+	TransportKey           = "transport"        // key of the transport the provider accepts besides tcp, like websocket
+	WebsocketPortKey       = "websocket-port"   // key of the port the provider listens for the websocket upgrades on
+	WebsocketPathKey       = "websocket-path"   // key of the http path of the websocket upgrades of the provider
 )
 
 // tls constant
 const (
-	TLSKey        = "tls_key"
-	TLSCert       = "tls_cert"
-	CACert        = "ca_cert"
-	TLSServerNAME = "tls_server_name"
+	TLSKey                = "tls_key"
+	TLSCert               = "tls_cert"
+	CACert                = "ca_cert"
+	TLSServerNAME         = "tls_server_name"
+	TLSInsecureSkipVerify = "tls_insecure_skip_verify"
 )
 
 const (
@@ -84,6 +90,11 @@ const (
 	AdaptiveServiceProviderFilterKey     = "padasvc"
 	AuthConsumerFilterKey                = "sign"
 	AuthProviderFilterKey                = "auth"
+	BaggageConsumerFilterKey             = "baggage-consumer"
+	BaggageProviderFilterKey             = "baggage-provider"
+	CacheFilterKey                       = "cache"
+	CircuitBreakerFilterKey              = "circuitbreaker"
+	DegradationFilterKey                 = "degradation"
 	EchoFilterKey                        = "echo"
 	ExecuteLimitFilterKey                = "execute"
 	GenericFilterKey                     = "generic"
@@ -93,13 +104,24 @@ const (
 	GracefulShutdownFilterShutdownConfig = "GracefulShutdownFilterShutdownConfig"
 	HystrixConsumerFilterKey             = "hystrix_consumer"
 	HystrixProviderFilterKey             = "hystrix_provider"
+	IPAccessFilterKey                    = "ipaccess"
 	MetricsFilterKey                     = "metrics"
+	OutlierDetectionFilterKey            = "outlier"
+	RecordingFilterKey                   = "recording"
+	RecoveryFilterKey                    = "recovery"
+	RequestIDConsumerFilterKey           = "requestid-consumer"
+	RequestIDProviderFilterKey           = "requestid-provider"
 	SeataFilterKey                       = "seata"
+	SeataConsumerFilterKey               = "seata-consumer"
+	SeataProviderFilterKey               = "seata-provider"
 	SentinelProviderFilterKey            = "sentinel-provider"
 	SentinelConsumerFilterKey            = "sentinel-consumer"
+	SlowInvocationFilterKey              = "slowinvocation"
 	TokenFilterKey                       = "token"
 	TpsLimitFilterKey                    = "tps"
 	TracingFilterKey                     = "tracing"
+	ValidationFilterKey                  = "validation"
+	ValidationConsumerFilterKey          = "cvalidation"
 	XdsCircuitBreakerKey                 = "xds_circuit_reaker"
 	OTELServerTraceKey                   = "otelServerTrace"
 	OTELClientTraceKey                   = "otelClientTrace"
@@ -113,11 +135,35 @@ const (
 	WeightKey                          = "weight"
 	WarmupKey                          = "warmup"
 	RetriesKey                         = "retries"
+	RetryBudgetKey                     = "retry.budget"
+	RetryBackoffKey                    = "retry.backoff"
+	RetryBackoffMultiplierKey          = "retry.backoff.multiplier"
+	DefaultRetryBackoffMultiplier      = 2.0
+	RetryableKey                       = "retryable"
+	RetryOnKey                         = "retry.on"
+	DefaultRetryBudgetMaxTokens        = 10
 	StickyKey                          = "sticky"
+	SubsetSizeKey                      = "subset.size" // the max providers a reference connects to, 0 disables subsetting
+	SubsetIDKey                        = "subset.id"   // the consumer identity the subset is hashed by, ip:pid by default
+	SelectionLogKey                    = "selection.log"
+	LocalityPreferenceKey              = "locality.preference"  // the ordered locality levels to prefer, host, dc or none
+	LocalityMinHealthyKey              = "locality.min.healthy" // the min healthy candidates of a level, or it widens
+	DefaultLocalityMinHealthy          = 1
+	DatacenterKey                      = "datacenter"
 	BeanName                           = "bean.name"
 	FailBackTasksKey                   = "failbacktasks"
 	ForksKey                           = "forks"
 	DefaultForks                       = 2
+	BroadcastFailPercentKey            = "broadcast.fail.percent"
+	BroadcastFailFastKey               = "broadcast.fail.fast"
+	BroadcastParallelismKey            = "broadcast.parallelism"
+	DefaultBroadcastParallelism        = 1
+	BroadcastResultsKey                = "broadcast.results"
+	MergerKey                          = "merger"
+	MergerFailAnyKey                   = "merger.fail.any" // fail if any group fails rather than merge the successes
+	SliceMergerName                    = "slice"
+	MapMergerName                      = "map"
+	SetMergerName                      = "set"
 	DefaultTimeout                     = 1000
 	TPSLimiterKey                      = "tps.limiter"
 	TPSRejectedExecutionHandlerKey     = "tps.limit.rejected.handler"
@@ -126,29 +172,152 @@ const (
 	TPSLimitIntervalKey                = "tps.limit.interval"
 	DefaultTPSLimitInterval            = -1
 	TPSLimitStrategyKey                = "tps.limit.strategy"
+	TPSLimitBurstKey                   = "tps.limit.burst" // the bucket capacity of the token bucket strategy, rate by default
+	TPSLimiterRuleSuffix               = ".tps-limiter"    // the suffix of the dynamic tps limit rule key
+	RetryAfterKey                      = "retry-after"     // the attachment of the milliseconds to retry a throttled request after
 	ExecuteLimitKey                    = "execute.limit"
 	DefaultExecuteLimit                = "-1"
 	ExecuteRejectedExecutionHandlerKey = "execute.limit.rejected.handler"
+	ExecuteLimitQueueKey               = "execute.limit.queue"         // the max invocations waiting for a slot, they are rejected at once if 0
+	ExecuteLimitQueueTimeoutKey        = "execute.limit.queue.timeout" // how long an invocation waits for a slot before it is rejected
+	DefaultExecuteLimitQueueTimeout    = "100ms"
+	ExecutorWorkersKey                 = "executor.workers"    // the workers of the pool of the service, the service shares the pool of the provider if it's absent
+	ExecutorSharedKey                  = "executor.shared"     // whether the service without its own pool shares the pool of the provider, it's handled inline otherwise
+	ExecutorQueueSizeKey               = "executor.queue.size" // the requests waiting for a worker of the pool of the service
+	ExecutorRejectionKey               = "executor.rejection"  // what's done with the requests once the pool is full, abort or caller-runs
+	ExecutorRejectionAbort             = "abort"               // answer the request by the thread pool exhausted response
+	ExecutorRejectionCallerRuns        = "caller-runs"         // execute the request by the goroutine receiving it
 	SerializationKey                   = "serialization"
 	PIDKey                             = "pid"
 	SyncReportKey                      = "sync.report"
+	ReportConsumerDefinitionKey        = "report-consumer-definition"
 	RetryPeriodKey                     = "retry.period"
 	RetryTimesKey                      = "retry.times"
 	CycleReportKey                     = "cycle.report"
 	DefaultBlackListRecoverBlock       = 16
+
+	// sentinel keys
+	SentinelRuleSuffix        = ".sentinel-rules"          // the suffix of the sentinel rule key of a service in the config center
+	SentinelSimpleResourceKey = "sentinel.resource.simple" // name the resources interface and interface:method, as the rules of the java stack
+
+	// result cache keys
+	CacheKey                 = "cache"               // the cache factory of the results of a method, e.g. lru, expiring, request
+	CacheSizeKey             = "cache.size"          // the max results in the cache of a method
+	CacheTTLKey              = "cache.ttl"           // the time to live of a result in the expiring cache
+	CacheKeyGeneratorKey     = "cache.key.generator" // the generator of the key of an invocation in the cache
+	CacheableKey             = "cacheable"           // the result attachment, false means the result must not be cached
+	DefaultCacheSize         = 1000
+	DefaultCacheTTL          = "180s"
+	DefaultCacheKeyGenerator = "default"
+
+	// validation keys
+	ValidationKey           = "validation"            // true or the name of the validator of the arguments of a method
+	ValidationViolationsKey = "validation.violations" // the result attachment of the violations in json
+	DefaultValidator        = "default"
+
+	// recovery keys
+	RecoveryPanicMessageKey = "recovery.panic.message" // debug only, return the panic message to the consumer
+	ErrorCodeKey            = "error.code"             // the result attachment of the code of the error, which crosses the wire
+
+	// baggage keys
+	BaggageKeysKey        = "baggage.keys"     // the keys of the context values propagated to the providers, besides traceparent
+	BaggageMaxSizeKey     = "baggage.max.size" // the max length of a propagated value, longer values are truncated
+	TraceparentKey        = "traceparent"      // the w3c trace context
+	DefaultBaggageMaxSize = 1024
+
+	// ip access keys
+	IPAccessAllowKey          = "ip.access.allow"           // the ips or cidrs allowed to call the service, all if empty
+	IPAccessDenyKey           = "ip.access.deny"            // the ips or cidrs denied, which takes precedence over the allowed ones
+	IPAccessTrustedProxiesKey = "ip.access.trusted.proxies" // the proxies whose x-forwarded-for is trusted, for the rest protocol
+	IPAccessRuleSuffix        = ".ip-access"                // the suffix of the ip access rule key in the config center
+	ForwardedForKey           = "x-forwarded-for"           // the attachment of the x-forwarded-for header of the rest protocol
+
+	// masking keys
+	MaskingFieldsKey        = "masking.fields"         // the patterns of the field names whose values are redacted in the logs
+	MaskingPartialFieldsKey = "masking.partial.fields" // the patterns of the field names whose values are masked except the last 4 characters
+	DefaultMaskingFields    = "*password*,*secret*"
+
+	// slow invocation keys
+	SlowInvocationThresholdKey        = "slow.threshold"     // the rt over which an invocation is slow, of the service or a method
+	SlowInvocationSampleLimitKey      = "slow.sample.limit"  // the max detailed records of a method in a sample window
+	SlowInvocationSampleWindowKey     = "slow.sample.window" // the window in which the detailed records are limited
+	SlowInvocationArgsMaxSizeKey      = "slow.args.max.size" // the max length of the arguments summary in a record
+	SlowInvocationDumpKey             = "slow.dump"          // whether to dump the goroutines on the first slow invocation of a method in a window
+	SlowInvocationDumpDirKey          = "slow.dump.dir"      // the directory of the goroutine dumps, the temp directory by default
+	SlowInvocationRuleSuffix          = ".slow-invocation"   // the suffix of the slow invocation rule key in the config center
+	DefaultSlowInvocationThreshold    = "1s"
+	DefaultSlowInvocationSampleLimit  = 10
+	DefaultSlowInvocationSampleWindow = "1m"
+	DefaultSlowInvocationArgsMaxSize  = 256
+
+	TransactionManagerKey     = "tx.manager" // the name of the transaction manager binding the propagated xid
+	TransactionXidKey         = "tx.xid.key" // the attachment key carrying the xid of the global transaction
+	DefaultTransactionManager = "seata"
+	DefaultTransactionXidKey  = "TX_XID"
+
+	RequestIDKey              = "request.id.key"       // the attachment key carrying the request id
+	RequestIDGeneratorKey     = "request.id.generator" // the generator of the request ids, uuid or snowflake
+	DefaultRequestIDKey       = "x-request-id"
+	DefaultRequestIDGenerator = "uuid"
+
+	DegradationOnKey       = "degradation.on"       // the error classes degraded, timeout, circuit-open, no-provider or the error codes
+	DegradationDefaultKey  = "degradation.default"  // the json literal of the value returned on degradation
+	DegradationFallbackKey = "degradation.fallback" // the name of the registered fallback called on degradation
+	DegradationForceKey    = "degradation.force"    // whether to degrade without calling the providers
+	DegradationRuleSuffix  = ".degradation"         // the suffix of the degradation rule key in the config center
+	DefaultDegradationOn   = "timeout,circuit-open,no-provider"
 )
 
 const (
 	DubboGoCtxKey = DubboCtxKey("dubbogo-ctx")
 )
 
+// access log keys
+const (
+	AccessLogFormatKey            = "accesslog.format"
+	AccessLogRotateKey            = "accesslog.rotate" // daily or size
+	AccessLogMaxSizeKey           = "accesslog.max.size"
+	AccessLogMaxBackupsKey        = "accesslog.max.backups"
+	AccessLogMaxAgeKey            = "accesslog.max.age"
+	AccessLogFlushSizeKey         = "accesslog.flush.size"
+	AccessLogFlushIntervalKey     = "accesslog.flush.interval"
+	DefaultAccessLogMaxSize       = 100 // megabytes
+	DefaultAccessLogMaxBackups    = 7
+	DefaultAccessLogMaxAge        = 7 // days
+	DefaultAccessLogFlushSize     = 4096
+	DefaultAccessLogFlushInterval = "1s"
+)
+
+// recording keys, the file of the recording is the param recording
+const (
+	RecordingRateKey              = "recording.rate"
+	RecordingMethodsKey           = "recording.methods"
+	RecordingEncodingKey          = "recording.encoding" // json or hessian
+	RecordingMaxRecordSizeKey     = "recording.max.record.size"
+	RecordingMaxSizeKey           = "recording.max.size"
+	RecordingMaxBackupsKey        = "recording.max.backups"
+	DefaultRecordingRate          = 1.0
+	DefaultRecordingMaxRecordSize = 64 * 1024
+	DefaultRecordingMaxSize       = 100 // megabytes
+	DefaultRecordingMaxBackups    = 7
+)
+
 // metadata report keys
 const (
 	MetadataReportNamespaceKey = "metadata-report.namespace"
 	MetadataReportGroupKey     = "metadata-report.group"
 	MetadataReportUsernameKey  = "metadata-report.username"
 	MetadataReportPasswordKey  = "metadata-report.password"
+	MetadataReportAccessKey    = "metadata-report.access"
+	MetadataReportSecretKey    = "metadata-report.secret"
 	MetadataReportProtocolKey  = "metadata-report.protocol"
+	MetadataReportTTLKey       = "ttl"
+)
+
+// redis keys
+const (
+	RedisClusterKey = "cluster"
+	RedisDBKey      = "db"
 )
 
 // registry keys
@@ -193,6 +362,7 @@ const (
 	TagRouteProtocol       = "tag"
 	ProvidersCategory      = "providers"
 	RouterKey              = "router"
+	PriorityKey            = "priority"
 	ExportKey              = "export"
 )
 
@@ -207,11 +377,12 @@ const (
 	ConfigAccessKey           = "config-center.access"
 	ConfigPasswordKey         = "config-center.password"
 	ConfigLogDirKey           = "config-center.logDir"
-	ConfigVersionKey          = "config-center.configVersion"
+	ConfigVersionKey          = "configVersion" // the version of the override rules, as the rules written by dubbo-admin
 	CompatibleConfigKey       = "config-center.compatible_config"
 	ConfigSecretKey           = "config-center.secret"
 	ConfigBackupConfigKey     = "config-center.isBackupConfig"
 	ConfigBackupConfigPathKey = "config-center.backupConfigPath"
+	ConfigRootKey             = "config-center.root"
 )
 
 const (
@@ -284,6 +455,10 @@ const (
 	EtcdV3Key = "etcdv3"
 )
 
+const (
+	ApolloKey = "apollo"
+)
+
 const (
 	// PassThroughProxyFactoryKey is key of proxy factory with raw data input service
 	PassThroughProxyFactoryKey = "dubbo-raw"
@@ -298,6 +473,10 @@ const (
 const (
 	TagRouterRuleSuffix              = ".tag-router"
 	ConditionRouterRuleSuffix        = ".condition-router" // Specify condition router suffix
+	CanaryRouterRuleSuffix           = ".canary-router"    // Specify canary router suffix
+	ScriptRouterRuleSuffix           = ".script-router"    // Specify script router suffix
+	RouterScopeService               = "service"           // the rule acts on a service
+	RouterScopeApplication           = "application"       // the rule acts on an application
 	MeshRouteSuffix                  = ".MESHAPPRULE"      // Specify mesh router suffix
 	ForceUseTag                      = "dubbo.force.tag"   // the tag in attachment
 	ForceUseCondition                = "dubbo.force.condition"
@@ -314,6 +493,44 @@ const (
 	Scope                            = "scope"
 	Wildcard                         = "wildcard"
 	MeshRouterFactoryKey             = "mesh"
+	HealthCheckRouterFactoryKey      = "health_check"
+	CanaryRouterFactoryKey           = "canary"
+	ScriptRouterFactoryKey           = "script"
+)
+
+// Use for circuit breaker, all of them can be configured per method
+const (
+	CircuitBreakerFailureRateKey             = "circuit.breaker.failure.rate" // percent of failed requests in the window
+	CircuitBreakerConsecutiveFailuresKey     = "circuit.breaker.consecutive.failures"
+	CircuitBreakerMinRequestsKey             = "circuit.breaker.min.requests"
+	CircuitBreakerWindowKey                  = "circuit.breaker.window"
+	CircuitBreakerOpenTimeKey                = "circuit.breaker.open.time"
+	CircuitBreakerHalfOpenRequestsKey        = "circuit.breaker.half.open.requests"
+	DefaultCircuitBreakerFailureRate         = 50
+	DefaultCircuitBreakerConsecutiveFailures = 5
+	DefaultCircuitBreakerMinRequests         = 20
+	DefaultCircuitBreakerWindow              = "10s"
+	DefaultCircuitBreakerOpenTime            = "5s"
+	DefaultCircuitBreakerHalfOpenRequests    = 3
+)
+
+// Use for outlier detection
+const (
+	OutlierConsecutiveErrorsKey      = "outlier.consecutive.errors"
+	OutlierErrorRateKey              = "outlier.error.rate" // percent of failed requests in the window
+	OutlierMinRequestsKey            = "outlier.min.requests"
+	OutlierWindowKey                 = "outlier.window"
+	OutlierBaseEjectionTimeKey       = "outlier.base.ejection.time"
+	OutlierMaxEjectionTimeKey        = "outlier.max.ejection.time"
+	OutlierMaxEjectionPercentKey     = "outlier.max.ejection.percent"
+	DefaultOutlierConsecutiveErrors  = 5
+	DefaultOutlierErrorRate          = 50
+	DefaultOutlierMinRequests        = 10
+	DefaultOutlierWindow             = "10s"
+	DefaultOutlierBaseEjectionTime   = "30s"
+	DefaultOutlierMaxEjectionTime    = "300s"
+	DefaultOutlierMaxEjectionPercent = 50
+	HealthCheckRouterPriority        = 1000 // route after the rule based routers narrowed the invokers
 )
 
 // Auth filter
@@ -331,6 +548,10 @@ const (
 	Consumer                    = "consumer"          // consumer
 	AccessKeyIDKey              = ".accessKeyId"      // key of access key id
 	SecretAccessKeyKey          = ".secretAccessKey"  // key of secret access key
+
+	// the max skew of the request timestamp, 0 disables the check
+	AuthTimestampSkewKey     = "auth.timestamp.skew"
+	DefaultAuthTimestampSkew = "5m"
 )
 
 // metadata report
@@ -387,6 +608,9 @@ const (
 const (
 	HealthCheckServiceTypeName  = "DubbogoHealthServer"
 	HealthCheckServiceInterface = "grpc.health.v1.Health"
+	// ConfigCenterHealthService is the service of the health check service reporting the connection health of the
+	// config center
+	ConfigCenterHealthService = "dubbo.config-center"
 )
 
 const (
@@ -408,6 +632,8 @@ const (
 	AggregationBucketNumKey              = "aggregation.bucket.num"
 	AggregationTimeWindowSecondsKey      = "aggregation.time.window.seconds"
 	HistogramEnabledKey                  = "histogram.enabled"
+	HistogramBucketsKey                  = "histogram.buckets"
+	MethodLabelEnabledKey                = "method.label.enabled"
 	PrometheusExporterEnabledKey         = "prometheus.exporter.enabled"
 	PrometheusExporterMetricsPortKey     = "prometheus.exporter.metrics.port"
 	PrometheusExporterMetricsPathKey     = "prometheus.exporter.metrics.path"
@@ -417,6 +643,10 @@ const (
 	PrometheusPushgatewayPasswordKey     = "prometheus.pushgateway.password"
 	PrometheusPushgatewayPushIntervalKey = "prometheus.pushgateway.push.interval"
 	PrometheusPushgatewayJobKey          = "prometheus.pushgateway.job"
+	MetricsNamespaceKey                  = "metrics.namespace"
+	MetricsConstLabelKeyPrefix           = "metrics.const.label."
+	MetricsEnabledKey                    = "metrics.enabled"
+	MetricsCollectIntervalKey            = "metrics.collect.interval"
 )
 
 // default meta cache config
@@ -425,3 +655,10 @@ const (
 	DefaultMetaFileName  = "dubbo.metadata."
 	DefaultEntrySize     = 100
 )
+
+// the keys of the attributes of the url, see common.URL.SetAttribute, which are never serialized
+const (
+	// RPCServiceAttributeKey is the RPCService implementing the service of the provider url, which is set by the
+	// ServiceConfig and is read by the protocols and the registry protocol on exporting the url
+	RPCServiceAttributeKey = "rpc-service"
+)
@@ -41,6 +41,16 @@ const (
 	TagGroup              = "group"
 	TagVersion            = "version"
 	TagErrorCode          = "error"
+	TagState              = "state"
+	TagSource             = "source"
+	TagSide               = "side"
+	TagTraceId            = "trace_id"
+	TagReason             = "reason"
+	TagPool               = "pool"
+	TagServer             = "server"
+	TagLimit              = "limit"
+	TagLevel              = "level"
+	TagEvent              = "event"
 )
 const (
 	MetricNamespace                     = "dubbo"
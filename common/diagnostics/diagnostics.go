@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diagnostics tracks the goroutines and the connections started by the framework in the diagnostics mode,
+// so that what's still alive after the shutdown is reported with its owner, see config.VerifyShutdown. The mode is
+// enabled by the environment variable DUBBO_GO_SHUTDOWN_DIAGNOSTICS=true, or by Enable in the tests, and the
+// tracking costs only an atomic load while it's disabled.
+package diagnostics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	KindGoroutine  = "goroutine"
+	KindConnection = "connection"
+
+	// waitInterval is the interval to check whether the tracked resources are gone
+	waitInterval = 10 * time.Millisecond
+)
+
+// Resource is a goroutine or a connection started by a component of the framework
+type Resource struct {
+	Component string `json:"component"`
+	Kind      string `json:"kind"`
+	// Name identifies the resource in the component, like the watched path or the remote address
+	Name string `json:"name,omitempty"`
+	// Site is the file:line starting the resource
+	Site  string    `json:"site,omitempty"`
+	Since time.Time `json:"since"`
+}
+
+// nolint
+func (r Resource) String() string {
+	return fmt.Sprintf("%s %s{%s} started at %s %s ago", r.Component, r.Kind, r.Name, r.Site,
+		time.Since(r.Since).Truncate(time.Millisecond))
+}
+
+// goroutineID is the key of a tracked goroutine, which never equals the owner of a connection
+type goroutineID uint64
+
+var (
+	enabled = atomic.NewBool(enabledByEnv())
+	seq     = atomic.NewUint64(0)
+	// resources are the tracked resources keyed by the goroutineID or the owner of the resource
+	resources sync.Map
+)
+
+func enabledByEnv() bool {
+	on, _ := strconv.ParseBool(os.Getenv(constant.ShutdownDiagnosticsEnvKey))
+	return on
+}
+
+// Enabled returns true if the diagnostics mode is enabled
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Enable enables the diagnostics mode in the tests, the returned function restores the mode and forgets the tracked
+// resources, e.g.
+//
+//	defer diagnostics.Enable()()
+//
+// Only the resources started after it are tracked.
+func Enable() (restore func()) {
+	previous := enabled.Swap(true)
+	return func() {
+		enabled.Store(previous)
+		resources.Range(func(key, _ interface{}) bool {
+			resources.Delete(key)
+			return true
+		})
+	}
+}
+
+// Go runs @f in a new goroutine of the @component, which is tracked until @f returns in the diagnostics mode. @name
+// identifies the goroutine in the component, like the watched path.
+func Go(component, name string, f func()) {
+	if !enabled.Load() {
+		go f()
+		return
+	}
+	id := goroutineID(seq.Inc())
+	resources.Store(id, newResource(component, KindGoroutine, name))
+	go func() {
+		defer resources.Delete(id)
+		f()
+	}()
+}
+
+// Track tracks the resource @owner of the @component in the diagnostics mode until Untrack, like a connection
+func Track(owner interface{}, component, kind, name string) {
+	if enabled.Load() {
+		resources.Store(owner, newResource(component, kind, name))
+	}
+}
+
+// Untrack stops tracking the resource @owner
+func Untrack(owner interface{}) {
+	resources.Delete(owner)
+}
+
+// newResource creates the resource started by the caller of Go or Track
+func newResource(component, kind, name string) Resource {
+	r := Resource{Component: component, Kind: kind, Name: name, Since: time.Now()}
+	if _, file, line, ok := runtime.Caller(2); ok {
+		r.Site = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	return r
+}
+
+// Alive returns the tracked resources still alive, sorted by the component and the start time
+func Alive() []Resource {
+	var alive []Resource
+	resources.Range(func(_, value interface{}) bool {
+		alive = append(alive, value.(Resource))
+		return true
+	})
+	sort.Slice(alive, func(i, j int) bool {
+		if alive[i].Component != alive[j].Component {
+			return alive[i].Component < alive[j].Component
+		}
+		return alive[i].Since.Before(alive[j].Since)
+	})
+	return alive
+}
+
+// Wait waits at most @timeout until all the tracked resources are gone, and returns the ones still alive, since the
+// goroutines take a moment to exit after their components are closed
+func Wait(timeout time.Duration) []Resource {
+	deadline := time.Now().Add(timeout)
+	for {
+		alive := Alive()
+		if len(alive) == 0 || !time.Now().Before(deadline) {
+			return alive
+		}
+		time.Sleep(waitInterval)
+	}
+}
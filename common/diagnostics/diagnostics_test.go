@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostics
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGo(t *testing.T) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	Go("test", "disabled", func() {
+		<-done
+		close(exited)
+	})
+	assert.Empty(t, Alive())
+	close(done)
+	<-exited
+
+	defer Enable()()
+	assert.True(t, Enabled())
+	done = make(chan struct{})
+	Go("test", "enabled", func() {
+		<-done
+	})
+	alive := Alive()
+	assert.Len(t, alive, 1)
+	assert.Equal(t, "test", alive[0].Component)
+	assert.Equal(t, KindGoroutine, alive[0].Kind)
+	assert.Equal(t, "enabled", alive[0].Name)
+	assert.Contains(t, alive[0].Site, "diagnostics_test.go:")
+	assert.Len(t, Wait(20*time.Millisecond), 1)
+
+	close(done)
+	assert.Empty(t, Wait(time.Second))
+}
+
+func TestTrack(t *testing.T) {
+	owner := &struct{ int }{}
+	Track(owner, "test", KindConnection, "127.0.0.1:20000")
+	assert.Empty(t, Alive())
+
+	restore := Enable()
+	Track(owner, "test", KindConnection, "127.0.0.1:20000")
+	Track(&struct{ int }{}, "a", KindConnection, "127.0.0.1:20001")
+	alive := Alive()
+	assert.Len(t, alive, 2)
+	assert.Equal(t, "a", alive[0].Component)
+	assert.Equal(t, "127.0.0.1:20000", alive[1].Name)
+	assert.Contains(t, alive[1].String(), "test connection{127.0.0.1:20000} started at diagnostics_test.go:")
+
+	Untrack(owner)
+	assert.Len(t, Alive(), 1)
+	// the restoring forgets the tracked resources
+	restore()
+	assert.Empty(t, Alive())
+	assert.False(t, Enabled())
+}
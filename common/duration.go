@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// ParseDuration parses @value, which is either a duration like 3s and 500ms, or the bare milliseconds like 3000 for
+// the compatibility with dubbo java. All the durations of the configs and the url params are parsed by it, so that
+// both forms are accepted everywhere.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	t, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, perrors.Errorf("%q is neither a duration like 3s nor the milliseconds like 3000", value)
+	}
+	return t, nil
+}
+
+// FormatDuration formats @d as the bare milliseconds which dubbo java reads, e.g. 3000 for 3s, the fraction of a
+// millisecond is dropped
+func FormatDuration(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// NormalizeDuration converts the duration @value to the bare milliseconds written to the urls for the interop with
+// dubbo java, e.g. 3000 for 3s, the empty value is kept as is
+func NormalizeDuration(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return value, nil
+	}
+	d, err := ParseDuration(value)
+	if err != nil {
+		return "", err
+	}
+	return FormatDuration(d), nil
+}
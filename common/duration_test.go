@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"3s", 3 * time.Second},
+		{"500ms", 500 * time.Millisecond},
+		{"1m30s", 90 * time.Second},
+		// the bare milliseconds of dubbo java
+		{"3000", 3 * time.Second},
+		{" 200 ", 200 * time.Millisecond},
+		{"0", 0},
+	}
+	for _, test := range tests {
+		d, err := ParseDuration(test.value)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, d, test.value)
+	}
+	for _, value := range []string{"", "3ss", "3.5", "three seconds"} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestNormalizeDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"3s", "3000"},
+		{"500ms", "500"},
+		{"1m", "60000"},
+		{"1500us", "1"},
+		{"3000", "3000"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		normalized, err := NormalizeDuration(test.value)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, normalized, test.value)
+	}
+	_, err := NormalizeDuration("3ss")
+	assert.Error(t, err)
+}
+
+func TestGetMethodParamDuration(t *testing.T) {
+	u, _ := NewURL("dubbo://127.0.0.1:20000/com.test.Service?timeout=1s&methods.GetUser.timeout=500ms" +
+		"&methods.ListUsers.timeout=2000&methods.DeleteUser.timeout=invalid")
+	assert.Equal(t, 500*time.Millisecond, u.GetMethodParamDuration("GetUser", "timeout", time.Second))
+	assert.Equal(t, 2*time.Second, u.GetMethodParamDuration("ListUsers", "timeout", time.Second))
+	assert.Equal(t, time.Second, u.GetMethodParamDuration("DeleteUser", "timeout", time.Second))
+	assert.Equal(t, time.Second, u.GetMethodParamDuration("AddUser", "timeout", time.Second))
+}
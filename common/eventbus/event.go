@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventbus
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// Type is the type of an Event
+type Type string
+
+const (
+	TypeServiceExported    = Type("service-exported")
+	TypeServiceUnexported  = Type("service-unexported")
+	TypeReferenceReady     = Type("reference-ready")
+	TypeReferenceDestroyed = Type("reference-destroyed")
+	TypeRegistryConnected  = Type("registry-connected")
+	TypeRegistryLost       = Type("registry-lost")
+	TypeShutdownInitiated  = Type("shutdown-initiated")
+)
+
+// Event is published by the framework at a point of the lifecycle of the services, the references and the registries
+type Event interface {
+	Type() Type
+}
+
+// ServiceExported is published after a service is exported by all of its protocols and registered, at startup or
+// at runtime
+type ServiceExported struct {
+	// ServiceKey is like group/interface:version
+	ServiceKey string
+	Interface  string
+	// URLs are the urls the service is exported with, one for each protocol
+	URLs []*common.URL
+}
+
+// Type returns TypeServiceExported
+func (ServiceExported) Type() Type {
+	return TypeServiceExported
+}
+
+// ServiceUnexported is published after a service is unexported at runtime, or its protocols are destroyed at shutdown
+type ServiceUnexported struct {
+	ServiceKey string
+	Interface  string
+	URLs       []*common.URL
+}
+
+// Type returns TypeServiceUnexported
+func (ServiceUnexported) Type() Type {
+	return TypeServiceUnexported
+}
+
+// ReferenceReady is published after the proxy of a reference is created, the providers of a lazy reference are
+// connected on its first invocation
+type ReferenceReady struct {
+	ServiceKey string
+	Interface  string
+	// URL is the url the reference is referred with
+	URL *common.URL
+}
+
+// Type returns TypeReferenceReady
+func (ReferenceReady) Type() Type {
+	return TypeReferenceReady
+}
+
+// ReferenceDestroyed is published after a reference is destroyed at runtime, or its protocols are destroyed at
+// shutdown
+type ReferenceDestroyed struct {
+	ServiceKey string
+	Interface  string
+	URL        *common.URL
+}
+
+// Type returns TypeReferenceDestroyed
+func (ReferenceDestroyed) Type() Type {
+	return TypeReferenceDestroyed
+}
+
+// RegistryConnected is published after a registry is connected, and after it's reconnected once lost
+type RegistryConnected struct {
+	// URL is the url of the registry
+	URL *common.URL
+}
+
+// Type returns TypeRegistryConnected
+func (RegistryConnected) Type() Type {
+	return TypeRegistryConnected
+}
+
+// RegistryLost is published once the session with a registry is found lost, before the services are registered again
+type RegistryLost struct {
+	URL *common.URL
+}
+
+// Type returns TypeRegistryLost
+func (RegistryLost) Type() Type {
+	return TypeRegistryLost
+}
+
+// ShutdownInitiated is published once the graceful shutdown begins, before the services are unregistered
+type ShutdownInitiated struct{}
+
+// Type returns TypeShutdownInitiated
+func (ShutdownInitiated) Type() Type {
+	return TypeShutdownInitiated
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eventbus dispatches the events of the lifecycle of the services, the references and the registries in
+// process, e.g. to write the audit logs, warm the caches, or register the services to a CMDB once they're exported.
+//
+// The synchronous listeners are called in the goroutine publishing the event, in the order they're subscribed, so
+// they see the events of a type in the order published and block the framework until they return. The asynchronous
+// listeners are called in the background, and the events of a type are delivered to each of them in the order
+// published, so a slow listener delays only its own later events. The panics of the listeners are recovered and
+// logged.
+package eventbus
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
+)
+
+// Listener listens the events
+type Listener func(event Event)
+
+// subscription is a listener subscribed to the events of some types
+type subscription struct {
+	listener Listener
+	// types are the types subscribed, all of the types if it's empty
+	types   map[Type]struct{}
+	async   bool
+	removed atomic.Bool
+
+	lock sync.Mutex
+	// pending are the events waiting to be delivered to the async listener by the types, a type is present while its
+	// events are being delivered
+	pending map[Type][]Event
+}
+
+var (
+	subscriptionsLock sync.RWMutex
+	subscriptions     []*subscription
+)
+
+// Subscribe subscribes the synchronous @listener to the events of @types, or all of the events if @types is empty,
+// and returns the func to unsubscribe it
+func Subscribe(listener Listener, types ...Type) (unsubscribe func()) {
+	return subscribe(listener, false, types)
+}
+
+// SubscribeAsync subscribes the asynchronous @listener to the events of @types, or all of the events if @types is
+// empty, and returns the func to unsubscribe it, the events not delivered yet are dropped once it's unsubscribed
+func SubscribeAsync(listener Listener, types ...Type) (unsubscribe func()) {
+	return subscribe(listener, true, types)
+}
+
+func subscribe(listener Listener, async bool, types []Type) func() {
+	s := &subscription{listener: listener, async: async}
+	if len(types) > 0 {
+		s.types = make(map[Type]struct{}, len(types))
+		for _, t := range types {
+			s.types[t] = struct{}{}
+		}
+	}
+	if async {
+		s.pending = make(map[Type][]Event)
+	}
+
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	// the slice is copied on write, so that the publishers iterate it without the lock
+	subscriptions = append(subscriptions[:len(subscriptions):len(subscriptions)], s)
+	return s.unsubscribe
+}
+
+func (s *subscription) unsubscribe() {
+	if !s.removed.CAS(false, true) {
+		return
+	}
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	remained := make([]*subscription, 0, len(subscriptions))
+	for _, other := range subscriptions {
+		if other != s {
+			remained = append(remained, other)
+		}
+	}
+	subscriptions = remained
+}
+
+func (s *subscription) accepts(t Type) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	_, ok := s.types[t]
+	return ok
+}
+
+// Publish publishes the @event to the listeners subscribed to its type, it returns after the synchronous listeners
+// return
+func Publish(event Event) {
+	subscriptionsLock.RLock()
+	subs := subscriptions
+	subscriptionsLock.RUnlock()
+	for _, s := range subs {
+		if !s.accepts(event.Type()) {
+			continue
+		}
+		if s.async {
+			s.enqueue(event)
+		} else {
+			s.notify(event)
+		}
+	}
+}
+
+// enqueue delivers the @event in the background after the events of its type published before
+func (s *subscription) enqueue(event Event) {
+	t := event.Type()
+	s.lock.Lock()
+	if pending, ok := s.pending[t]; ok {
+		s.pending[t] = append(pending, event)
+		s.lock.Unlock()
+		return
+	}
+	s.pending[t] = nil
+	s.lock.Unlock()
+
+	go s.deliver(event)
+}
+
+// deliver delivers the @event and the events of the same type queued after it
+func (s *subscription) deliver(event Event) {
+	t := event.Type()
+	for {
+		if !s.removed.Load() {
+			s.notify(event)
+		}
+
+		s.lock.Lock()
+		pending := s.pending[t]
+		if len(pending) == 0 {
+			delete(s.pending, t)
+			s.lock.Unlock()
+			return
+		}
+		event = pending[0]
+		s.pending[t] = pending[1:]
+		s.lock.Unlock()
+	}
+}
+
+func (s *subscription) notify(event Event) {
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("The event listener panics at the event %s %+v: %v", event.Type(), event, e)
+		}
+	}()
+	s.listener(event)
+}
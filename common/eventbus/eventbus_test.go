@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	var got []Event
+	unsubscribe := Subscribe(func(event Event) {
+		got = append(got, event)
+	}, TypeServiceExported, TypeShutdownInitiated)
+	// the panics of a listener don't affect the others
+	unsubscribePanic := Subscribe(func(Event) {
+		panic("listener")
+	})
+	defer unsubscribePanic()
+
+	Publish(ServiceExported{Interface: "com.ikurento.user.UserProvider"})
+	Publish(ReferenceReady{Interface: "com.ikurento.user.UserProvider"})
+	Publish(ShutdownInitiated{})
+	assert.Equal(t, []Event{ServiceExported{Interface: "com.ikurento.user.UserProvider"}, ShutdownInitiated{}}, got)
+
+	unsubscribe()
+	unsubscribe()
+	Publish(ShutdownInitiated{})
+	assert.Len(t, got, 2)
+}
+
+func TestSubscribeAsync(t *testing.T) {
+	var (
+		lock sync.Mutex
+		got  = make(map[Type][]string)
+	)
+	block := make(chan struct{})
+	unsubscribe := SubscribeAsync(func(event Event) {
+		if e, ok := event.(ServiceExported); ok && e.Interface == "first" {
+			<-block
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		switch e := event.(type) {
+		case ServiceExported:
+			got[e.Type()] = append(got[e.Type()], e.Interface)
+		case ServiceUnexported:
+			got[e.Type()] = append(got[e.Type()], e.Interface)
+		}
+	})
+	defer unsubscribe()
+
+	for _, name := range []string{"first", "second", "third"} {
+		Publish(ServiceExported{Interface: name})
+		Publish(ServiceUnexported{Interface: name})
+	}
+	// a blocked event delays only the later events of its type
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(got[TypeServiceUnexported]) == 3
+	}, time.Second, 10*time.Millisecond)
+	lock.Lock()
+	assert.Empty(t, got[TypeServiceExported])
+	lock.Unlock()
+
+	close(block)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(got[TypeServiceExported]) == 3
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"first", "second", "third"}, got[TypeServiceExported])
+	assert.Equal(t, []string{"first", "second", "third"}, got[TypeServiceUnexported])
+}
@@ -28,28 +28,32 @@ var (
 
 // SetAuthenticator puts the @fcn into map with name
 func SetAuthenticator(name string, fcn func() filter.Authenticator) {
-	authenticators[name] = fcn
+	register(KindAuthenticator, name, func() { authenticators[name] = fcn })
 }
 
 // GetAuthenticator finds the Authenticator with @name
 // Panic if not found
 func GetAuthenticator(name string) (filter.Authenticator, bool) {
-	if authenticators[name] == nil {
+	var fcn func() filter.Authenticator
+	read(func() { fcn = authenticators[name] })
+	if fcn == nil {
 		return nil, false
 	}
-	return authenticators[name](), true
+	return fcn(), true
 }
 
 // SetAccessKeyStorages will set the @fcn into map with this name
 func SetAccessKeyStorages(name string, fcn func() filter.AccessKeyStorage) {
-	accessKeyStorages[name] = fcn
+	register(KindAccessKeyStorage, name, func() { accessKeyStorages[name] = fcn })
 }
 
 // GetAccessKeyStorages finds the storage with the @name.
 // Panic if not found
 func GetAccessKeyStorages(name string) filter.AccessKeyStorage {
-	if accessKeyStorages[name] == nil {
-		panic("accessKeyStorages for " + name + " is not existing, make sure you have import the package.")
+	var fcn func() filter.AccessKeyStorage
+	read(func() { fcn = accessKeyStorages[name] })
+	if fcn == nil {
+		panic(UnknownExtensionError(KindAccessKeyStorage, name))
 	}
-	return accessKeyStorages[name]()
+	return fcn()
 }
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+var (
+	cacheFactories     = make(map[string]filter.CacheFactory)
+	cacheKeyGenerators = make(map[string]filter.CacheKeyGenerator)
+)
+
+// SetCacheFactory sets the CacheFactory with @name
+func SetCacheFactory(name string, factory filter.CacheFactory) {
+	register(KindCacheFactory, name, func() { cacheFactories[name] = factory })
+}
+
+// GetCacheFactory finds the CacheFactory with @name
+func GetCacheFactory(name string) (filter.CacheFactory, error) {
+	var factory filter.CacheFactory
+	read(func() { factory = cacheFactories[name] })
+	if factory == nil {
+		return nil, UnknownExtensionError(KindCacheFactory, name)
+	}
+	return factory, nil
+}
+
+// SetCacheKeyGenerator sets the CacheKeyGenerator with @name
+func SetCacheKeyGenerator(name string, generator filter.CacheKeyGenerator) {
+	register(KindCacheKeyGenerator, name, func() { cacheKeyGenerators[name] = generator })
+}
+
+// GetCacheKeyGenerator finds the CacheKeyGenerator with @name
+func GetCacheKeyGenerator(name string) (filter.CacheKeyGenerator, error) {
+	var generator filter.CacheKeyGenerator
+	read(func() { generator = cacheKeyGenerators[name] })
+	if generator == nil {
+		return nil, UnknownExtensionError(KindCacheKeyGenerator, name)
+	}
+	return generator, nil
+}
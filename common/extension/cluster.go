@@ -17,17 +17,8 @@
 
 package extension
 
-import (
-	"fmt"
-)
-
-import (
-	"github.com/pkg/errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster"
-	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
 var clusters = make(map[string]func() cluster.Cluster)
@@ -35,13 +26,20 @@ var clusters = make(map[string]func() cluster.Cluster)
 // SetCluster sets the cluster fault-tolerant mode with @name
 // For example: available/failfast/broadcast/failfast/failsafe/...
 func SetCluster(name string, fcn func() cluster.Cluster) {
-	clusters[name] = fcn
+	register(KindCluster, name, func() { clusters[name] = fcn })
 }
 
 // GetCluster finds the cluster fault-tolerant mode with @name
 func GetCluster(name string) (cluster.Cluster, error) {
-	if clusters[name] == nil {
-		return nil, errors.New(fmt.Sprintf(constant.NonImportErrorMsgFormat, constant.ClusterKeyFailover))
+	var fcn func() cluster.Cluster
+	read(func() { fcn = clusters[name] })
+	if fcn == nil {
+		return nil, UnknownExtensionError(KindCluster, name)
 	}
-	return clusters[name](), nil
+	return fcn(), nil
+}
+
+// GetAllClusterNames returns the names of the cluster fault-tolerant modes in order
+func GetAllClusterNames() []string {
+	return GetSupported(KindCluster)
 }
@@ -17,10 +17,6 @@
 
 package extension
 
-import (
-	"errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
@@ -30,14 +26,15 @@ var configCenters = make(map[string]func(config *common.URL) (config_center.Dyna
 
 // SetConfigCenter sets the DynamicConfiguration with @name
 func SetConfigCenter(name string, v func(*common.URL) (config_center.DynamicConfiguration, error)) {
-	configCenters[name] = v
+	register(KindConfigCenter, name, func() { configCenters[name] = v })
 }
 
 // GetConfigCenter finds the DynamicConfiguration with @name
 func GetConfigCenter(name string, config *common.URL) (config_center.DynamicConfiguration, error) {
-	configCenterFactory := configCenters[name]
+	var configCenterFactory func(*common.URL) (config_center.DynamicConfiguration, error)
+	read(func() { configCenterFactory = configCenters[name] })
 	if configCenterFactory == nil {
-		return nil, errors.New("config center for " + name + " is not existing, make sure you have import the package.")
+		return nil, UnknownExtensionError(KindConfigCenter, name)
 	}
 	configCenter, err := configCenterFactory(config)
 	if err != nil {
@@ -17,10 +17,6 @@
 
 package extension
 
-import (
-	"errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 )
@@ -29,13 +25,15 @@ var configCenterFactories = make(map[string]func() config_center.DynamicConfigur
 
 // SetConfigCenterFactory sets the DynamicConfigurationFactory with @name
 func SetConfigCenterFactory(name string, v func() config_center.DynamicConfigurationFactory) {
-	configCenterFactories[name] = v
+	register(KindConfigCenterFactory, name, func() { configCenterFactories[name] = v })
 }
 
 // GetConfigCenterFactory finds the DynamicConfigurationFactory with @name
 func GetConfigCenterFactory(name string) (config_center.DynamicConfigurationFactory, error) {
-	if configCenterFactories[name] == nil {
-		return nil, errors.New("config center for " + name + " is not existing, make sure you have import the package.")
+	var v func() config_center.DynamicConfigurationFactory
+	read(func() { v = configCenterFactories[name] })
+	if v == nil {
+		return nil, UnknownExtensionError(KindConfigCenterFactory, name)
 	}
-	return configCenterFactories[name](), nil
+	return v(), nil
 }
@@ -28,15 +28,17 @@ var (
 
 // SetConfigReaders sets a creator of config reader with @name
 func SetConfigReaders(name string, v func() interfaces.ConfigReader) {
-	configReaders[name] = v
+	register(KindConfigReader, name, func() { configReaders[name] = v })
 }
 
 // GetConfigReaders gets a config reader with @name
 func GetConfigReaders(name string) interfaces.ConfigReader {
-	if configReaders[name] == nil {
-		panic("config reader for " + name + " is not existing, make sure you have imported the package.")
+	var v func() interfaces.ConfigReader
+	read(func() { v = configReaders[name] })
+	if v == nil {
+		panic(UnknownExtensionError(KindConfigReader, name))
 	}
-	return configReaders[name]()
+	return v()
 }
 
 // SetDefaultConfigReader sets @name for @module in default config reader
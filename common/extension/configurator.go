@@ -33,34 +33,36 @@ var configurator = make(map[string]getConfiguratorFunc)
 
 // SetConfigurator sets the getConfiguratorFunc with @name
 func SetConfigurator(name string, v getConfiguratorFunc) {
-	configurator[name] = v
+	register(KindConfigurator, name, func() { configurator[name] = v })
 }
 
 // GetConfigurator finds the Configurator with @name
 func GetConfigurator(name string, url *common.URL) config_center.Configurator {
-	if configurator[name] == nil {
-		panic("configurator for " + name + " is not existing, make sure you have import the package.")
-	}
-	return configurator[name](url)
+	return lookupConfigurator(name)(url)
 }
 
 // SetDefaultConfigurator sets the default Configurator
 func SetDefaultConfigurator(v getConfiguratorFunc) {
-	configurator[DefaultKey] = v
+	register(KindConfigurator, DefaultKey, func() { configurator[DefaultKey] = v })
 }
 
 // GetDefaultConfigurator gets default configurator
 func GetDefaultConfigurator(url *common.URL) config_center.Configurator {
-	if configurator[DefaultKey] == nil {
-		panic("configurator for default is not existing, make sure you have import the package.")
-	}
-	return configurator[DefaultKey](url)
+	return lookupConfigurator(DefaultKey)(url)
 }
 
 // GetDefaultConfiguratorFunc gets default configurator function
 func GetDefaultConfiguratorFunc() getConfiguratorFunc {
-	if configurator[DefaultKey] == nil {
-		panic("configurator for default is not existing, make sure you have import the package.")
+	return lookupConfigurator(DefaultKey)
+}
+
+// lookupConfigurator finds the getConfiguratorFunc with @name
+// Panic if not found
+func lookupConfigurator(name string) getConfiguratorFunc {
+	var v getConfiguratorFunc
+	read(func() { v = configurator[name] })
+	if v == nil {
+		panic(UnknownExtensionError(KindConfigurator, name))
 	}
-	return configurator[DefaultKey]
+	return v
 }
@@ -18,43 +18,88 @@
 package extension
 
 import (
-	"github.com/pkg/errors"
-)
-
-import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 )
 
 var (
 	filters                  = make(map[string]func() filter.Filter)
+	filterFactories          = make(map[string]func(*common.URL) filter.Filter)
 	rejectedExecutionHandler = make(map[string]func() filter.RejectedExecutionHandler)
+	validators               = make(map[string]filter.Validator)
 )
 
 // SetFilter sets the filter extension with @name
 // For example: hystrix/metrics/token/tracing/limit/...
 func SetFilter(name string, v func() filter.Filter) {
-	filters[name] = v
+	register(KindFilter, name, func() { filters[name] = v })
+}
+
+// SetFilterFactory sets the filter extension with @name which is created by @factory with the url of the service
+// or the reference, so that the filter could keep the state and the parameters of its own service.
+// The filter is created once per exported service or reference when the filter chain is built.
+func SetFilterFactory(name string, factory func(url *common.URL) filter.Filter) {
+	register(KindFilter, name, func() { filterFactories[name] = factory })
 }
 
-// GetFilter finds the filter extension with @name
+// GetFilter finds the filter extension with @name, the filter set by SetFilterFactory is created with a nil url
 func GetFilter(name string) (filter.Filter, bool) {
-	if filters[name] == nil {
+	return GetFilterByURL(name, nil)
+}
+
+// GetFilterByURL creates the filter extension with @name for the service or the reference of @url, the filter set by
+// SetFilterFactory takes precedence over the one set by SetFilter
+func GetFilterByURL(name string, url *common.URL) (filter.Filter, bool) {
+	var (
+		v       func() filter.Filter
+		factory func(*common.URL) filter.Filter
+	)
+	read(func() {
+		v = filters[name]
+		factory = filterFactories[name]
+	})
+	if factory != nil {
+		return factory(url), true
+	}
+	if v == nil {
 		return nil, false
 	}
-	return filters[name](), true
+	return v(), true
+}
+
+// IsFilterFactory returns true if the filter extension with @name is set by SetFilterFactory
+func IsFilterFactory(name string) bool {
+	var factory func(*common.URL) filter.Filter
+	read(func() { factory = filterFactories[name] })
+	return factory != nil
 }
 
 // SetRejectedExecutionHandler sets the RejectedExecutionHandler with @name
 func SetRejectedExecutionHandler(name string, creator func() filter.RejectedExecutionHandler) {
-	rejectedExecutionHandler[name] = creator
+	register(KindRejectedExecutionHandler, name, func() { rejectedExecutionHandler[name] = creator })
 }
 
 // GetRejectedExecutionHandler finds the RejectedExecutionHandler with @name
 func GetRejectedExecutionHandler(name string) (filter.RejectedExecutionHandler, error) {
-	creator, ok := rejectedExecutionHandler[name]
-	if !ok {
-		return nil, errors.New("RejectedExecutionHandler for " + name + " is not existing, make sure you have import the package " +
-			"and you have register it by invoking extension.SetRejectedExecutionHandler.")
+	var creator func() filter.RejectedExecutionHandler
+	read(func() { creator = rejectedExecutionHandler[name] })
+	if creator == nil {
+		return nil, UnknownExtensionError(KindRejectedExecutionHandler, name)
 	}
 	return creator(), nil
 }
+
+// SetValidator sets the Validator with @name
+func SetValidator(name string, v filter.Validator) {
+	register(KindValidator, name, func() { validators[name] = v })
+}
+
+// GetValidator finds the Validator with @name
+func GetValidator(name string) (filter.Validator, error) {
+	var v filter.Validator
+	read(func() { v = validators[name] })
+	if v == nil {
+		return nil, UnknownExtensionError(KindValidator, name)
+	}
+	return v, nil
+}
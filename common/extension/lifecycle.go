@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package extension
+
+import (
+	"sync"
+)
+
+// LifecycleEvent is a point of the lifecycle of the application
+type LifecycleEvent string
+
+const (
+	// LifecycleConfigLoaded is fired after the config is loaded and initialized, before the services are exported
+	LifecycleConfigLoaded = LifecycleEvent("config-loaded")
+	// LifecycleServicesExported is fired after all of the services are exported
+	LifecycleServicesExported = LifecycleEvent("services-exported")
+	// LifecycleReferencesReady is fired after all of the references are referred
+	LifecycleReferencesReady = LifecycleEvent("references-ready")
+	// LifecycleBeforeShutdown is fired before the graceful shutdown begins
+	LifecycleBeforeShutdown = LifecycleEvent("before-shutdown")
+	// LifecycleAfterShutdown is fired after the graceful shutdown completes
+	LifecycleAfterShutdown = LifecycleEvent("after-shutdown")
+)
+
+// IsShutdown returns whether the event is fired at shutdown
+func (e LifecycleEvent) IsShutdown() bool {
+	return e == LifecycleBeforeShutdown || e == LifecycleAfterShutdown
+}
+
+// Lifecycle is notified at the LifecycleEvents
+type Lifecycle interface {
+	OnLifecycleEvent(event LifecycleEvent)
+}
+
+// LifecycleFunc is the Lifecycle by a func
+type LifecycleFunc func(event LifecycleEvent)
+
+func (f LifecycleFunc) OnLifecycleEvent(event LifecycleEvent) {
+	f(event)
+}
+
+var (
+	lifecyclesLock sync.RWMutex
+	lifecycles     []Lifecycle
+)
+
+// AddLifecycleListener adds the listener of the framework components, e.g. the metrics reporter, which are notified
+// before the ones of the application at startup and after them at shutdown, see config.AddLifecycleListener
+func AddLifecycleListener(l Lifecycle) {
+	lifecyclesLock.Lock()
+	defer lifecyclesLock.Unlock()
+	lifecycles = append(lifecycles, l)
+}
+
+// GetLifecycleListeners returns the listeners of the framework components in the order they're added
+func GetLifecycleListeners() []Lifecycle {
+	lifecyclesLock.RLock()
+	defer lifecyclesLock.RUnlock()
+	return append([]Lifecycle(nil), lifecycles...)
+}
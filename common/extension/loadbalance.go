@@ -26,14 +26,31 @@ var loadbalances = make(map[string]func() loadbalance.LoadBalance)
 // SetLoadbalance sets the loadbalance extension with @name
 // For example: random/round_robin/consistent_hash/least_active/...
 func SetLoadbalance(name string, fcn func() loadbalance.LoadBalance) {
-	loadbalances[name] = fcn
+	register(KindLoadbalance, name, func() { loadbalances[name] = fcn })
 }
 
 // GetLoadbalance finds the loadbalance extension with @name
+// Panic if not found
 func GetLoadbalance(name string) loadbalance.LoadBalance {
-	if loadbalances[name] == nil {
-		panic("loadbalance for " + name + " is not existing, make sure you have import the package.")
+	lb, err := GetLoadbalanceByName(name)
+	if err != nil {
+		panic(err)
 	}
+	return lb
+}
+
+// GetLoadbalanceByName finds the loadbalance extension with @name, it returns an error listing the registered ones
+// if not found
+func GetLoadbalanceByName(name string) (loadbalance.LoadBalance, error) {
+	var fcn func() loadbalance.LoadBalance
+	read(func() { fcn = loadbalances[name] })
+	if fcn == nil {
+		return nil, UnknownExtensionError(KindLoadbalance, name)
+	}
+	return fcn(), nil
+}
 
-	return loadbalances[name]()
+// GetAllLoadbalanceNames returns the names of the loadbalance extensions in order
+func GetAllLoadbalanceNames() []string {
+	return GetSupported(KindLoadbalance)
 }
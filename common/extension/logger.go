@@ -19,8 +19,6 @@ package extension
 
 import (
 	"github.com/dubbogo/gost/log/logger"
-
-	"github.com/pkg/errors"
 )
 
 import (
@@ -30,16 +28,14 @@ import (
 var logs = make(map[string]func(config *common.URL) (logger.Logger, error))
 
 func SetLogger(driver string, log func(config *common.URL) (logger.Logger, error)) {
-	logs[driver] = log
+	register(KindLogger, driver, func() { logs[driver] = log })
 }
 
 func GetLogger(driver string, config *common.URL) (logger.Logger, error) {
-
-	if logs[driver] != nil {
-		return logs[driver](config)
-	} else {
-		return nil, errors.Errorf("logger for %s does not exist. "+
-			"please make sure that you have imported the package "+
-			"dubbo.apache.org/dubbo-go/v3/logger/%s", driver, driver)
+	var log func(config *common.URL) (logger.Logger, error)
+	read(func() { log = logs[driver] })
+	if log == nil {
+		return nil, UnknownExtensionError(KindLogger, driver)
 	}
+	return log(config)
 }
@@ -0,0 +1,40 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+)
+
+var mergers = make(map[string]func() merger.Merger)
+
+// SetMerger sets the merger extension with @name
+// For example: slice/map/set/...
+func SetMerger(name string, fcn func() merger.Merger) {
+	register(KindMerger, name, func() { mergers[name] = fcn })
+}
+
+// GetMerger finds the merger extension with @name
+func GetMerger(name string) (merger.Merger, error) {
+	var fcn func() merger.Merger
+	read(func() { fcn = mergers[name] })
+	if fcn == nil {
+		return nil, UnknownExtensionError(KindMerger, name)
+	}
+	return fcn(), nil
+}
@@ -25,13 +25,15 @@ var metaDataReportFactories = make(map[string]func() factory.MetadataReportFacto
 
 // SetMetadataReportFactory sets the MetadataReportFactory with @name
 func SetMetadataReportFactory(name string, v func() factory.MetadataReportFactory) {
-	metaDataReportFactories[name] = v
+	register(KindMetadataReportFactory, name, func() { metaDataReportFactories[name] = v })
 }
 
 // GetMetadataReportFactory finds the MetadataReportFactory with @name
 func GetMetadataReportFactory(name string) factory.MetadataReportFactory {
-	if metaDataReportFactories[name] == nil {
+	var v func() factory.MetadataReportFactory
+	read(func() { v = metaDataReportFactories[name] })
+	if v == nil {
 		return nil
 	}
-	return metaDataReportFactories[name]()
+	return v()
 }
@@ -32,20 +32,27 @@ import (
 var traceExporterMap = make(map[string]func(config *trace.ExporterConfig) (trace.Exporter, error), 4)
 
 func SetTraceExporter(name string, createFunc func(config *trace.ExporterConfig) (trace.Exporter, error)) {
-	traceExporterMap[name] = createFunc
+	register(KindTraceExporter, name, func() { traceExporterMap[name] = createFunc })
 }
 
 func GetTraceExporter(name string, config *trace.ExporterConfig) (trace.Exporter, error) {
-	createFunc, ok := traceExporterMap[name]
-	if !ok {
-		panic("Cannot find the trace provider with name " + name)
+	var createFunc func(config *trace.ExporterConfig) (trace.Exporter, error)
+	read(func() { createFunc = traceExporterMap[name] })
+	if createFunc == nil {
+		panic(UnknownExtensionError(KindTraceExporter, name))
 	}
 	return createFunc(config)
 }
 
 func GetTraceShutdownCallback() func() {
 	return func() {
-		for name, createFunc := range traceExporterMap {
+		createFuncs := make(map[string]func(config *trace.ExporterConfig) (trace.Exporter, error))
+		read(func() {
+			for name, createFunc := range traceExporterMap {
+				createFuncs[name] = createFunc
+			}
+		})
+		for name, createFunc := range createFuncs {
 			if exporter, err := createFunc(nil); err == nil {
 				if err := exporter.GetTracerProvider().Shutdown(context.Background()); err != nil {
 					logger.Errorf("Graceful shutdown --- Failed to shutdown trace provider %s, error: %s", name, err.Error())
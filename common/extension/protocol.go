@@ -25,13 +25,21 @@ var protocols = make(map[string]func() protocol.Protocol)
 
 // SetProtocol sets the protocol extension with @name
 func SetProtocol(name string, v func() protocol.Protocol) {
-	protocols[name] = v
+	register(KindProtocol, name, func() { protocols[name] = v })
 }
 
 // GetProtocol finds the protocol extension with @name
+// Panic if not found
 func GetProtocol(name string) protocol.Protocol {
-	if protocols[name] == nil {
-		panic("protocol for " + name + " is not existing, make sure you have import the package.")
+	var v func() protocol.Protocol
+	read(func() { v = protocols[name] })
+	if v == nil {
+		panic(UnknownExtensionError(KindProtocol, name))
 	}
-	return protocols[name]()
+	return v()
+}
+
+// GetAllProtocolNames returns the names of the protocol extensions in order
+func GetAllProtocolNames() []string {
+	return GetSupported(KindProtocol)
 }
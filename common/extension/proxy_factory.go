@@ -29,7 +29,7 @@ var proxyFactories = make(map[string]func(...proxy.Option) proxy.ProxyFactory)
 
 // SetProxyFactory sets the ProxyFactory extension with @name
 func SetProxyFactory(name string, f func(...proxy.Option) proxy.ProxyFactory) {
-	proxyFactories[name] = f
+	register(KindProxyFactory, name, func() { proxyFactories[name] = f })
 }
 
 // GetProxyFactory finds the ProxyFactory extension with @name
@@ -37,9 +37,11 @@ func GetProxyFactory(name string) proxy.ProxyFactory {
 	if name == "" {
 		name = "default"
 	}
-	if proxyFactories[name] == nil {
-		logger.Warn("proxy factory for " + name + " is not existing, make sure you have import the package.")
+	var f func(...proxy.Option) proxy.ProxyFactory
+	read(func() { f = proxyFactories[name] })
+	if f == nil {
+		logger.Warn(UnknownExtensionError(KindProxyFactory, name))
 		return nil
 	}
-	return proxyFactories[name]()
+	return f()
 }
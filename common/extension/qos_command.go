@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/qos"
+)
+
+var qosCommands = make(map[string]func() qos.Command)
+
+// SetQosCommand sets the QoS command @name, which is run by the operators through the QoS server
+func SetQosCommand(name string, v func() qos.Command) {
+	register(KindQosCommand, name, func() { qosCommands[name] = v })
+}
+
+// GetQosCommand finds the QoS command @name
+func GetQosCommand(name string) (qos.Command, error) {
+	var v func() qos.Command
+	read(func() { v = qosCommands[name] })
+	if v == nil {
+		return nil, UnknownExtensionError(KindQosCommand, name)
+	}
+	return v(), nil
+}
@@ -26,13 +26,20 @@ var registries = make(map[string]func(config *common.URL) (registry.Registry, er
 
 // SetRegistry sets the registry extension with @name
 func SetRegistry(name string, v func(_ *common.URL) (registry.Registry, error)) {
-	registries[name] = v
+	register(KindRegistry, name, func() { registries[name] = v })
 }
 
 // GetRegistry finds the registry extension with @name
 func GetRegistry(name string, config *common.URL) (registry.Registry, error) {
-	if registries[name] == nil {
-		panic("registry for " + name + " does not exist. please make sure that you have imported the package dubbo.apache.org/dubbo-go/v3/registry/" + name + ".")
+	var v func(*common.URL) (registry.Registry, error)
+	read(func() { v = registries[name] })
+	if v == nil {
+		return nil, UnknownExtensionError(KindRegistry, name)
 	}
-	return registries[name](config)
+	return v(config)
+}
+
+// GetAllRegistryNames returns the names of the registry extensions in order
+func GetAllRegistryNames() []string {
+	return GetSupported(KindRegistry)
 }
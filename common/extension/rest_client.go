@@ -25,13 +25,15 @@ var restClients = make(map[string]func(restOptions *client.RestOptions) client.R
 
 // SetRestClient sets the RestClient with @name
 func SetRestClient(name string, fun func(_ *client.RestOptions) client.RestClient) {
-	restClients[name] = fun
+	register(KindRestClient, name, func() { restClients[name] = fun })
 }
 
 // GetNewRestClient finds the RestClient with @name
 func GetNewRestClient(name string, restOptions *client.RestOptions) client.RestClient {
-	if restClients[name] == nil {
-		panic("restClient for " + name + " is not existing, make sure you have import the package.")
+	var fun func(*client.RestOptions) client.RestClient
+	read(func() { fun = restClients[name] })
+	if fun == nil {
+		panic(UnknownExtensionError(KindRestClient, name))
 	}
-	return restClients[name](restOptions)
+	return fun(restOptions)
 }
@@ -25,13 +25,15 @@ var restServers = make(map[string]func() server.RestServer, 8)
 
 // SetRestServer sets the RestServer with @name
 func SetRestServer(name string, fun func() server.RestServer) {
-	restServers[name] = fun
+	register(KindRestServer, name, func() { restServers[name] = fun })
 }
 
 // GetNewRestServer finds the RestServer with @name
 func GetNewRestServer(name string) server.RestServer {
-	if restServers[name] == nil {
-		panic("restServer for " + name + " is not existing, make sure you have import the package.")
+	var fun func() server.RestServer
+	read(func() { fun = restServers[name] })
+	if fun == nil {
+		panic(UnknownExtensionError(KindRestServer, name))
 	}
-	return restServers[name]()
+	return fun()
 }
@@ -19,26 +19,51 @@ package extension
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
 )
 
 var (
-	routers = make(map[string]func() router.PriorityRouterFactory)
+	routers    = make(map[string]func() router.PriorityRouterFactory)
+	urlRouters = make(map[string]func(*common.URL) (router.PriorityRouter, error))
 )
 
 // SetRouterFactory sets create router factory function with @name
 func SetRouterFactory(name string, fun func() router.PriorityRouterFactory) {
-	routers[name] = fun
+	register(KindRouterFactory, name, func() { routers[name] = fun })
 }
 
 // GetRouterFactory gets create router factory function by @name
 func GetRouterFactory(name string) router.PriorityRouterFactory {
-	if routers[name] == nil {
-		panic("router_factory for " + name + " is not existing, make sure you have import the package.")
+	var fun func() router.PriorityRouterFactory
+	read(func() { fun = routers[name] })
+	if fun == nil {
+		panic(UnknownExtensionError(KindRouterFactory, name))
 	}
-	return routers[name]()
+	return fun()
 }
 
 // GetRouterFactories gets all create router factory function
 func GetRouterFactories() map[string]func() router.PriorityRouterFactory {
-	return routers
+	factories := make(map[string]func() router.PriorityRouterFactory)
+	read(func() {
+		for name, fun := range routers {
+			factories[name] = fun
+		}
+	})
+	return factories
+}
+
+// SetURLRouter sets the function creating a router from a rule url with @name, e.g. condition,
+// which is used for the rule urls in the routers category of the registry
+func SetURLRouter(name string, fun func(*common.URL) (router.PriorityRouter, error)) {
+	extensionLock.Lock()
+	defer extensionLock.Unlock()
+	urlRouters[name] = fun
+}
+
+// GetURLRouter gets the function creating a router from a rule url by @name
+func GetURLRouter(name string) (func(*common.URL) (router.PriorityRouter, error), bool) {
+	var fun func(*common.URL) (router.PriorityRouter, error)
+	read(func() { fun = urlRouters[name] })
+	return fun, fun != nil
 }
@@ -17,10 +17,6 @@
 
 package extension
 
-import (
-	perrors "github.com/pkg/errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
@@ -33,7 +29,7 @@ var discoveryCreatorMap = make(map[string]func(url *common.URL) (registry.Servic
 // protocol indicate the implementation, like nacos
 // the name like nacos-1...
 func SetServiceDiscovery(protocol string, creator func(url *common.URL) (registry.ServiceDiscovery, error)) {
-	discoveryCreatorMap[protocol] = creator
+	register(KindServiceDiscovery, protocol, func() { discoveryCreatorMap[protocol] = creator })
 }
 
 // GetServiceDiscovery will return the registry.ServiceDiscovery
@@ -42,9 +38,15 @@ func SetServiceDiscovery(protocol string, creator func(url *common.URL) (registr
 // if not found, or initialize instance failed, it will return error.
 func GetServiceDiscovery(url *common.URL) (registry.ServiceDiscovery, error) {
 	protocol := url.GetParam(constant.RegistryKey, "")
-	creator, ok := discoveryCreatorMap[protocol]
-	if !ok {
-		return nil, perrors.New("Could not find the service discovery with discovery protocol: " + protocol)
+	var creator func(url *common.URL) (registry.ServiceDiscovery, error)
+	read(func() { creator = discoveryCreatorMap[protocol] })
+	if creator == nil {
+		return nil, UnknownExtensionError(KindServiceDiscovery, protocol)
 	}
 	return creator(url)
 }
+
+// GetAllServiceDiscoveryNames returns the names of the protocols of the service discoveries in order
+func GetAllServiceDiscoveryNames() []string {
+	return GetSupported(KindServiceDiscovery)
+}
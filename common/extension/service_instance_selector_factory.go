@@ -17,10 +17,6 @@
 
 package extension
 
-import (
-	perrors "github.com/pkg/errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/registry/servicediscovery/instance"
 )
@@ -29,16 +25,16 @@ var serviceInstanceSelectorMappings = make(map[string]func() instance.ServiceIns
 
 // nolint
 func SetServiceInstanceSelector(name string, f func() instance.ServiceInstanceSelector) {
-	serviceInstanceSelectorMappings[name] = f
+	register(KindServiceInstanceSelector, name, func() { serviceInstanceSelectorMappings[name] = f })
 }
 
 // GetServiceInstanceSelector will create an instance
 // it will panic if selector with the @name not found
 func GetServiceInstanceSelector(name string) (instance.ServiceInstanceSelector, error) {
-	serviceInstanceSelector, ok := serviceInstanceSelectorMappings[name]
-	if !ok {
-		return nil, perrors.New("Could not find service instance selector with" +
-			"name:" + name)
+	var serviceInstanceSelector func() instance.ServiceInstanceSelector
+	read(func() { serviceInstanceSelector = serviceInstanceSelectorMappings[name] })
+	if serviceInstanceSelector == nil {
+		return nil, UnknownExtensionError(KindServiceInstanceSelector, name)
 	}
 	return serviceInstanceSelector(), nil
 }
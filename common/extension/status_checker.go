@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+)
+
+var statusCheckers = make(map[string]status.StatusChecker)
+
+// SetStatusChecker sets the status checker @name of a component, whose status is reported by the liveness and the
+// readiness probes
+func SetStatusChecker(name string, v status.StatusChecker) {
+	register(KindStatusChecker, name, func() { statusCheckers[name] = v })
+}
+
+// GetStatusCheckers returns all of the status checkers by their names
+func GetStatusCheckers() map[string]status.StatusChecker {
+	checkers := make(map[string]status.StatusChecker)
+	read(func() {
+		for name, checker := range statusCheckers {
+			checkers[name] = checker
+		}
+	})
+	return checkers
+}
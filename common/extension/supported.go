@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// the kinds of the extension points, which are listed by GetSupported
+const (
+	KindAccessKeyStorage         = "access-key-storage"
+	KindAuthenticator            = "authenticator"
+	KindCacheFactory             = "cache-factory"
+	KindCacheKeyGenerator        = "cache-key-generator"
+	KindCluster                  = "cluster"
+	KindConfigCenter             = "config-center"
+	KindConfigCenterFactory      = "config-center-factory"
+	KindConfigReader             = "config-reader"
+	KindConfigurator             = "configurator"
+	KindFilter                   = "filter"
+	KindLoadbalance              = "loadbalance"
+	KindLogger                   = "logger"
+	KindMerger                   = "merger"
+	KindMetadataReportFactory    = "metadata-report-factory"
+	KindProtocol                 = "protocol"
+	KindProxyFactory             = "proxy-factory"
+	KindQosCommand               = "qos-command"
+	KindRegistry                 = "registry"
+	KindRejectedExecutionHandler = "rejected-execution-handler"
+	KindRestClient               = "rest-client"
+	KindRestServer               = "rest-server"
+	KindRouterFactory            = "router-factory"
+	KindServiceDiscovery         = "service-discovery"
+	KindServiceInstanceSelector  = "service-instance-selector"
+	KindStatusChecker            = "status-checker"
+	KindTpsLimiter               = "tps-limiter"
+	KindTpsLimitStrategy         = "tps-limit-strategy"
+	KindTraceExporter            = "trace-exporter"
+	KindValidator                = "validator"
+)
+
+var (
+	// extensionLock guards the maps of the extension points registered by register
+	extensionLock sync.RWMutex
+	// extensionPackages records the package registering each extension, by kind and name
+	extensionPackages = make(map[string]map[string]string)
+)
+
+// Extension describes a registered extension
+type Extension struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Package string `json:"package"`
+}
+
+// register runs @set, which puts the extension @name of @kind into its map, under the write lock, and records the
+// package of the caller of the Set function as the one registering it
+func register(kind, name string, set func()) {
+	pkg := callerPackage(3)
+	extensionLock.Lock()
+	defer extensionLock.Unlock()
+	set()
+	names, ok := extensionPackages[kind]
+	if !ok {
+		names = make(map[string]string)
+		extensionPackages[kind] = names
+	}
+	names[name] = pkg
+}
+
+// read runs @get, which looks up the map of an extension point, under the read lock
+func read(get func()) {
+	extensionLock.RLock()
+	defer extensionLock.RUnlock()
+	get()
+}
+
+// callerPackage returns the package of the function @skip frames above it
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	// e.g. dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random.init.0
+	name := fn.Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// GetSupported returns the names of the registered extensions of @kind in order, e.g. the names of the loadbalances
+// for KindLoadbalance
+func GetSupported(kind string) []string {
+	extensionLock.RLock()
+	defer extensionLock.RUnlock()
+	names := make([]string, 0, len(extensionPackages[kind]))
+	for name := range extensionPackages[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetAllExtensions returns all the registered extensions ordered by kind and name
+func GetAllExtensions() []Extension {
+	extensionLock.RLock()
+	defer extensionLock.RUnlock()
+	extensions := make([]Extension, 0, len(extensionPackages))
+	for kind, names := range extensionPackages {
+		for name, pkg := range names {
+			extensions = append(extensions, Extension{Kind: kind, Name: name, Package: pkg})
+		}
+	}
+	sort.Slice(extensions, func(i, j int) bool {
+		if extensions[i].Kind != extensions[j].Kind {
+			return extensions[i].Kind < extensions[j].Kind
+		}
+		return extensions[i].Name < extensions[j].Name
+	})
+	return extensions
+}
+
+// UnknownExtensionError returns the error of looking up the extension @name of @kind which is not registered, e.g.
+// unknown loadbalance "roundrobbin", available: [random roundrobin]
+func UnknownExtensionError(kind, name string) error {
+	return perrors.Errorf("unknown %s %q, available: [%s], make sure you have imported the package of it",
+		kind, name, strings.Join(GetSupported(kind), " "))
+}
+
+// ExtensionsHandler returns the http handler dumping the registered extensions with the packages registering them,
+// in json if the query parameter format is json, otherwise one extension per line. It could be mounted on any admin
+// server, e.g. http.Handle("/dubbo/extensions", extension.ExtensionsHandler()).
+func ExtensionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		extensions := GetAllExtensions()
+		if kind := r.URL.Query().Get("kind"); kind != "" {
+			filtered := extensions[:0]
+			for _, e := range extensions {
+				if e.Kind == kind {
+					filtered = append(filtered, e)
+				}
+			}
+			extensions = filtered
+		}
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			content, err := json.Marshal(extensions)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write(content)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, e := range extensions {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Kind, e.Name, e.Package)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package extension
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
+)
+
+func TestGetSupported(t *testing.T) {
+	SetLoadbalance("test-random", func() loadbalance.LoadBalance { return nil })
+	SetLoadbalance("test-leastactive", func() loadbalance.LoadBalance { return nil })
+	assert.Equal(t, []string{"test-leastactive", "test-random"}, GetSupported(KindLoadbalance))
+	assert.Empty(t, GetSupported("unknown-kind"))
+
+	_, err := GetLoadbalanceByName("test-roundrobbin")
+	assert.EqualError(t, err, `unknown loadbalance "test-roundrobbin", available: [test-leastactive test-random], `+
+		`make sure you have imported the package of it`)
+	assert.PanicsWithError(t, err.Error(), func() {
+		GetLoadbalance("test-roundrobbin")
+	})
+
+	assert.Contains(t, GetAllExtensions(), Extension{
+		Kind:    KindLoadbalance,
+		Name:    "test-random",
+		Package: "dubbo.apache.org/dubbo-go/v3/common/extension",
+	})
+}
+
+func TestExtensionsHandler(t *testing.T) {
+	SetLoadbalance("test-random", func() loadbalance.LoadBalance { return nil })
+
+	w := httptest.NewRecorder()
+	ExtensionsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/dubbo/extensions?kind=loadbalance", nil))
+	assert.Contains(t, w.Body.String(), "loadbalance\ttest-random\tdubbo.apache.org/dubbo-go/v3/common/extension\n")
+
+	w = httptest.NewRecorder()
+	ExtensionsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/dubbo/extensions?kind=loadbalance&format=json", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var extensions []Extension
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &extensions))
+	assert.Contains(t, extensions, Extension{
+		Kind:    KindLoadbalance,
+		Name:    "test-random",
+		Package: "dubbo.apache.org/dubbo-go/v3/common/extension",
+	})
+	for _, e := range extensions {
+		assert.Equal(t, KindLoadbalance, e.Kind)
+	}
+}
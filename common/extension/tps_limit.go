@@ -17,10 +17,6 @@
 
 package extension
 
-import (
-	"errors"
-)
-
 import (
 	"dubbo.apache.org/dubbo-go/v3/filter"
 )
@@ -32,30 +28,30 @@ var (
 
 // SetTpsLimiter sets the TpsLimiter with @name
 func SetTpsLimiter(name string, creator func() filter.TpsLimiter) {
-	tpsLimiter[name] = creator
+	register(KindTpsLimiter, name, func() { tpsLimiter[name] = creator })
 }
 
 // GetTpsLimiter finds the TpsLimiter with @name
 func GetTpsLimiter(name string) (filter.TpsLimiter, error) {
-	creator, ok := tpsLimiter[name]
-	if !ok {
-		return nil, errors.New("TpsLimiter for " + name + " is not existing, make sure you have import the package " +
-			"and you have register it by invoking extension.SetTpsLimiter.")
+	var creator func() filter.TpsLimiter
+	read(func() { creator = tpsLimiter[name] })
+	if creator == nil {
+		return nil, UnknownExtensionError(KindTpsLimiter, name)
 	}
 	return creator(), nil
 }
 
 // SetTpsLimitStrategy sets the TpsLimitStrategyCreator with @name
 func SetTpsLimitStrategy(name string, creator filter.TpsLimitStrategyCreator) {
-	tpsLimitStrategy[name] = creator
+	register(KindTpsLimitStrategy, name, func() { tpsLimitStrategy[name] = creator })
 }
 
 // GetTpsLimitStrategyCreator finds the TpsLimitStrategyCreator with @name
 func GetTpsLimitStrategyCreator(name string) (filter.TpsLimitStrategyCreator, error) {
-	creator, ok := tpsLimitStrategy[name]
-	if !ok {
-		return nil, errors.New("TpsLimitStrategy for " + name + " is not existing, make sure you have import the package " +
-			"and you have register it by invoking extension.SetTpsLimitStrategy.")
+	var creator filter.TpsLimitStrategyCreator
+	read(func() { creator = tpsLimitStrategy[name] })
+	if creator == nil {
+		return nil, UnknownExtensionError(KindTpsLimitStrategy, name)
 	}
 	return creator, nil
 }
@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package gopool provides the bounded goroutine pools for the framework internals, e.g. dispatching the registry
+// notifications, publishing the metadata and executing the provider requests, so that the goroutines don't grow
+// unboundedly under the churn.
+package gopool
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
+)
+
+var (
+	// ErrPoolFull is returned if the queue of the pool is still full when the submission times out
+	ErrPoolFull = perrors.New("the goroutine pool is full")
+	// ErrPoolClosed is returned if the pool is closed
+	ErrPoolClosed = perrors.New("the goroutine pool is closed")
+)
+
+// Pool runs the tasks by at most workers goroutines, the tasks wait in a bounded queue while all the workers are
+// busy, and the submitters wait in turn while the queue is full, which is the backpressure instead of the unbounded
+// goroutines. The workers are started on demand and a panic of a task is recovered and logged, which never kills the
+// worker nor the process.
+type Pool struct {
+	name       string
+	maxWorkers int32
+	tasks      chan func()
+	workers    *atomic.Int32
+	active     *atomic.Int32
+	panics     *atomic.Int64
+	rejected   *atomic.Int64
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewPool creates a pool of at most @workers goroutines whose queue holds at most @queueSize tasks, DefaultWorkers is
+// used if @workers isn't positive.
+func NewPool(name string, workers, queueSize int) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &Pool{
+		name:       name,
+		maxWorkers: int32(workers),
+		tasks:      make(chan func(), queueSize),
+		workers:    atomic.NewInt32(0),
+		active:     atomic.NewInt32(0),
+		panics:     atomic.NewInt64(0),
+		rejected:   atomic.NewInt64(0),
+		closed:     make(chan struct{}),
+	}
+}
+
+// Submit queues the task, it waits while the queue is full
+func (p *Pool) Submit(task func()) error {
+	return p.submit(task, -1)
+}
+
+// SubmitTimeout queues the task, it waits at most @timeout while the queue is full and returns ErrPoolFull then. It
+// doesn't wait at all if @timeout isn't positive.
+func (p *Pool) SubmitTimeout(task func(), timeout time.Duration) error {
+	if timeout < 0 {
+		timeout = 0
+	}
+	return p.submit(task, timeout)
+}
+
+// submit waits forever if @timeout is negative
+func (p *Pool) submit(task func(), timeout time.Duration) error {
+	select {
+	case <-p.closed:
+		return ErrPoolClosed
+	default:
+	}
+	if p.tryStartWorker() {
+		// the worker started for the task takes it soon even if the queue is full or has no room at all
+		select {
+		case p.tasks <- task:
+			return nil
+		case <-p.closed:
+			return ErrPoolClosed
+		}
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+	}
+	if p.workers.Load() > p.active.Load() {
+		// an idle worker frees a room of the queue soon, e.g. the workers just started haven't taken their tasks yet
+		select {
+		case p.tasks <- task:
+			return nil
+		case <-p.closed:
+			return ErrPoolClosed
+		}
+	}
+	if timeout == 0 {
+		p.rejected.Inc()
+		return ErrPoolFull
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-expired:
+		p.rejected.Inc()
+		return ErrPoolFull
+	case <-p.closed:
+		return ErrPoolClosed
+	}
+}
+
+// tryStartWorker starts one more worker until there are maxWorkers, the workers are started one by one with the
+// submissions while the idle ones can't take the queued tasks, so an idle pool holds no goroutines. It returns true
+// if a worker is started.
+func (p *Pool) tryStartWorker() bool {
+	for {
+		workers := p.workers.Load()
+		if workers >= p.maxWorkers || workers > p.active.Load()+int32(len(p.tasks)) {
+			return false
+		}
+		if p.workers.CAS(workers, workers+1) {
+			go p.work()
+			return true
+		}
+	}
+}
+
+func (p *Pool) work() {
+	for {
+		select {
+		case task := <-p.tasks:
+			p.run(task)
+		case <-p.closed:
+			// the queued tasks are still done
+			for {
+				select {
+				case task := <-p.tasks:
+					p.run(task)
+				default:
+					p.workers.Dec()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool) run(task func()) {
+	p.active.Inc()
+	defer func() {
+		p.active.Dec()
+		if e := recover(); e != nil {
+			p.panics.Inc()
+			logger.Errorf("A task of the goroutine pool %s panics: %v\n%s", p.name, e, debug.Stack())
+		}
+	}()
+	task()
+}
+
+// Close stops accepting the tasks, the queued ones are still done by the workers
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+}
+
+// Name returns the name of the pool
+func (p *Pool) Name() string {
+	return p.name
+}
+
+// MaxWorkers returns the max count of the workers
+func (p *Pool) MaxWorkers() int {
+	return int(p.maxWorkers)
+}
+
+// Workers returns the count of the workers started
+func (p *Pool) Workers() int {
+	return int(p.workers.Load())
+}
+
+// ActiveWorkers returns the count of the workers running the tasks
+func (p *Pool) ActiveWorkers() int {
+	return int(p.active.Load())
+}
+
+// QueueDepth returns the count of the tasks waiting in the queue
+func (p *Pool) QueueDepth() int {
+	return len(p.tasks)
+}
+
+// QueueSize returns the capacity of the queue
+func (p *Pool) QueueSize() int {
+	return cap(p.tasks)
+}
+
+// Panics returns the count of the tasks panicked
+func (p *Pool) Panics() int64 {
+	return p.panics.Load()
+}
+
+// Rejected returns the count of the tasks rejected by ErrPoolFull
+func (p *Pool) Rejected() int64 {
+	return p.rejected.Load()
+}
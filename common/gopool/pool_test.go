@@ -0,0 +1,199 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gopool
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+func TestPoolSubmit(t *testing.T) {
+	p := NewPool("test", 4, 16)
+	defer p.Close()
+	var wg sync.WaitGroup
+	done := atomic.NewInt32(0)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		assert.NoError(t, p.Submit(func() {
+			defer wg.Done()
+			done.Inc()
+		}))
+	}
+	wg.Wait()
+	assert.Equal(t, int32(100), done.Load())
+	assert.True(t, p.Workers() >= 1 && p.Workers() <= 4)
+}
+
+func TestPoolBackpressure(t *testing.T) {
+	p := NewPool("test", 2, 4)
+	defer p.Close()
+	hanging := make(chan struct{})
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, p.Submit(func() {
+			started <- struct{}{}
+			<-hanging
+		}))
+	}
+	<-started
+	<-started
+	assert.Equal(t, 2, p.ActiveWorkers())
+
+	// the queue is filled up, then the submissions are rejected or wait
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, p.SubmitTimeout(func() {}, 0))
+	}
+	assert.Equal(t, 4, p.QueueDepth())
+	assert.Equal(t, ErrPoolFull, p.SubmitTimeout(func() {}, 0))
+	assert.Equal(t, ErrPoolFull, p.SubmitTimeout(func() {}, 10*time.Millisecond))
+	assert.Equal(t, int64(2), p.Rejected())
+
+	// a burst of the tasks waits for the workers rather than starting the goroutines
+	goroutines := runtime.NumGoroutine()
+	submitted := atomic.NewInt32(0)
+	go func() {
+		for i := 0; i < 10000; i++ {
+			_ = p.Submit(func() {})
+			submitted.Inc()
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), submitted.Load())
+	assert.True(t, runtime.NumGoroutine() <= goroutines+1)
+	assert.Equal(t, 2, p.Workers())
+
+	close(hanging)
+	assert.Eventually(t, func() bool {
+		return submitted.Load() == 10000 && p.QueueDepth() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPoolWithoutQueue(t *testing.T) {
+	p := NewPool("test", 2, 0)
+	defer p.Close()
+	hanging := make(chan struct{})
+	// the tasks are taken by the workers started for them at once
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, p.SubmitTimeout(func() {
+			<-hanging
+		}, 0))
+	}
+	assert.Equal(t, ErrPoolFull, p.SubmitTimeout(func() {}, 0))
+	assert.Equal(t, int64(1), p.Rejected())
+	close(hanging)
+	assert.Eventually(t, func() bool {
+		return p.SubmitTimeout(func() {}, 0) == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestPoolPanic(t *testing.T) {
+	p := NewPool("test", 1, 4)
+	defer p.Close()
+	assert.NoError(t, p.Submit(func() {
+		panic("oops")
+	}))
+	done := make(chan struct{})
+	assert.NoError(t, p.Submit(func() {
+		close(done)
+	}))
+	<-done
+	assert.Equal(t, int64(1), p.Panics())
+	assert.Equal(t, 1, p.Workers())
+}
+
+func TestPoolClose(t *testing.T) {
+	p := NewPool("test", 1, 4)
+	hanging := make(chan struct{})
+	assert.NoError(t, p.Submit(func() {
+		<-hanging
+	}))
+	done := atomic.NewInt32(0)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, p.Submit(func() {
+			done.Inc()
+		}))
+	}
+	p.Close()
+	assert.Equal(t, ErrPoolClosed, p.Submit(func() {}))
+
+	// the queued tasks are still done
+	close(hanging)
+	assert.Eventually(t, func() bool {
+		return done.Load() == 3 && p.Workers() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSharedPool(t *testing.T) {
+	Configure("shared-test", 3, 5)
+	p := GetPool("shared-test")
+	assert.Same(t, p, GetPool("shared-test"))
+	assert.Equal(t, 3, p.MaxWorkers())
+	assert.Equal(t, 5, p.QueueSize())
+
+	// it's too late to change the size
+	Configure("shared-test", 6, 10)
+	assert.Equal(t, 3, GetPool("shared-test").MaxWorkers())
+	assert.Contains(t, GetPools(), p)
+
+	p = GetPool(MetadataPublish)
+	assert.Equal(t, 1, p.MaxWorkers())
+	assert.Equal(t, DefaultQueueSize, p.QueueSize())
+
+	added := NewPool("added-test", 1, 1)
+	AddPool(added)
+	assert.Same(t, added, GetPool("added-test"))
+	assert.Contains(t, GetPools(), added)
+	RemovePool("added-test")
+	assert.NotContains(t, GetPools(), added)
+	assert.Equal(t, ErrPoolClosed, added.Submit(func() {}))
+}
+
+func BenchmarkPool(b *testing.B) {
+	p := NewPool("bench", runtime.GOMAXPROCS(0), DefaultQueueSize)
+	defer p.Close()
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		_ = p.Submit(func() {
+			wg.Done()
+		})
+	}
+	wg.Wait()
+}
+
+func BenchmarkGoroutine(b *testing.B) {
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		go func() {
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package gopool
+
+import (
+	"sort"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+const (
+	// RegistryNotify is the pool dispatching the notifications of the registries to the directories
+	RegistryNotify = "registry-notify"
+	// MetadataPublish is the pool publishing the metadata to the metadata center, it has only one worker by default
+	// so that the metadata is published in order
+	MetadataPublish = "metadata-publish"
+	// ProviderExecute is the pool executing the requests of the services of the dubbo protocol which have no pools of
+	// their own
+	ProviderExecute = "provider-execute"
+
+	DefaultWorkers   = 32
+	DefaultQueueSize = 1024
+)
+
+// Config is the size of a shared pool
+type Config struct {
+	Workers   int
+	QueueSize int
+}
+
+var (
+	sharedLock  sync.Mutex
+	sharedPools = make(map[string]*Pool)
+	configs     = map[string]Config{
+		RegistryNotify:  {Workers: 64, QueueSize: DefaultQueueSize},
+		MetadataPublish: {Workers: 1, QueueSize: DefaultQueueSize},
+		ProviderExecute: {Workers: 200, QueueSize: DefaultQueueSize},
+	}
+)
+
+// Configure sets the size of the shared pool @name, it takes effect only if the pool isn't created yet, so it should
+// be called at startup, see ApplicationConfig. The zero values keep the defaults.
+func Configure(name string, workers, queueSize int) {
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+	if _, ok := sharedPools[name]; ok {
+		logger.Warnf("The goroutine pool %s is in use, its size can't be changed", name)
+		return
+	}
+	conf := getConfig(name)
+	if workers > 0 {
+		conf.Workers = workers
+	}
+	if queueSize > 0 {
+		conf.QueueSize = queueSize
+	}
+	configs[name] = conf
+}
+
+func getConfig(name string) Config {
+	if conf, ok := configs[name]; ok {
+		return conf
+	}
+	return Config{Workers: DefaultWorkers, QueueSize: DefaultQueueSize}
+}
+
+// GetPool returns the shared pool @name, it's created on the first call
+func GetPool(name string) *Pool {
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+	if p, ok := sharedPools[name]; ok {
+		return p
+	}
+	conf := getConfig(name)
+	p := NewPool(name, conf.Workers, conf.QueueSize)
+	sharedPools[name] = p
+	return p
+}
+
+// GetPools returns the shared pools created, sorted by the names
+func GetPools() []*Pool {
+	sharedLock.Lock()
+	pools := make([]*Pool, 0, len(sharedPools))
+	for _, p := range sharedPools {
+		pools = append(pools, p)
+	}
+	sharedLock.Unlock()
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].name < pools[j].name
+	})
+	return pools
+}
+
+// AddPool adds the pool @p created by NewPool to the shared pools, so that it's returned by GetPool and GetPools by
+// its name until RemovePool. It replaces the shared pool of the same name, which is closed.
+func AddPool(p *Pool) {
+	sharedLock.Lock()
+	replaced := sharedPools[p.name]
+	sharedPools[p.name] = p
+	sharedLock.Unlock()
+	if replaced != nil && replaced != p {
+		replaced.Close()
+	}
+}
+
+// RemovePool removes the shared pool @name and closes it
+func RemovePool(name string) {
+	sharedLock.Lock()
+	p := sharedPools[name]
+	delete(sharedPools, name)
+	sharedLock.Unlock()
+	if p != nil {
+		p.Close()
+	}
+}
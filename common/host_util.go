@@ -14,12 +14,15 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package common
 
 import (
+	"net"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 import (
@@ -31,19 +34,193 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
+// virtualInterfacePrefixes are the prefixes of the names of the interfaces created by the container runtimes and
+// the hypervisors, whose addresses are not reachable from the other hosts
+var virtualInterfacePrefixes = []string{"docker", "veth", "br-", "virbr", "cni", "flannel", "cali", "weave",
+	"kube-ipvs", "tunl", "vmnet", "vboxnet"}
+
+// NetworkOptions are the options of choosing the local ip, see GetLocalIp.
+//
+// In kubernetes, the pod ip is the address of eth0 in the pod and is chosen with no options. With hostNetwork,
+// the interfaces of the node are listed instead, so the interface or the cidr of the node network should be
+// preferred, e.g. the cidr of the nodes rather than the one of the pods on cni0. If the provider is reached
+// through a hostPort or a NAT, neither address is reachable and DUBBO_IP_TO_REGISTRY and DUBBO_PORT_TO_REGISTRY
+// should be set to the address of the node, e.g. by the downward api status.hostIP.
+type NetworkOptions struct {
+	// PreferredInterfaces are the names of the interfaces to choose the ip from in order, e.g. eth0
+	PreferredInterfaces []string
+	// PreferredCIDRs are the networks to choose the ip from in order, e.g. 10.0.0.0/8
+	PreferredCIDRs []*net.IPNet
+	// PreferIPv6 chooses an ipv6 address rather than an ipv4 one if both are found
+	PreferIPv6 bool
+}
+
+// hostInterface is a network interface and its addresses
+type hostInterface struct {
+	name  string
+	flags net.Flags
+	ips   []net.IP
+}
+
 var (
-	localIp       string
-	localHostname string
+	localIpLock    sync.RWMutex
+	localIp        string
+	networkOptions NetworkOptions
+	localHostname  string
+
+	// hostInterfaces lists the network interfaces of the host, it's replaced in the tests
+	hostInterfaces = listHostInterfaces
 )
 
+// SetNetworkOptions sets the options of choosing the local ip, and the local ip is chosen again
+func SetNetworkOptions(opts NetworkOptions) {
+	localIpLock.Lock()
+	defer localIpLock.Unlock()
+	networkOptions = opts
+	localIp = ""
+}
+
+// GetLocalIp returns the ip of the host advertised to the others, which is chosen from the up and non-loopback
+// interfaces: the ones in the preferred interfaces and cidrs of NetworkOptions if any, otherwise the ones not
+// created by the container runtimes, e.g. docker0, and then an ipv4 address unless ipv6 is preferred.
 func GetLocalIp() string {
+	localIpLock.RLock()
+	ip := localIp
+	localIpLock.RUnlock()
+	if len(ip) != 0 {
+		return ip
+	}
+
+	localIpLock.Lock()
+	defer localIpLock.Unlock()
 	if len(localIp) != 0 {
 		return localIp
 	}
-	localIp, _ = gxnet.GetLocalIP()
+	interfaces, err := hostInterfaces()
+	if err != nil {
+		logger.Warnf("can not list the network interfaces: %v", err)
+	}
+	if chosen := chooseLocalIP(interfaces, networkOptions); chosen != nil {
+		localIp = chosen.String()
+	} else {
+		localIp, _ = gxnet.GetLocalIP()
+	}
 	return localIp
 }
 
+func listHostInterfaces() ([]hostInterface, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	hostInterfaces := make([]hostInterface, 0, len(interfaces))
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		hi := hostInterface{name: iface.Name, flags: iface.Flags}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				hi.ips = append(hi.ips, ipNet.IP)
+			}
+		}
+		hostInterfaces = append(hostInterfaces, hi)
+	}
+	return hostInterfaces, nil
+}
+
+// candidateIP is an ip of an interface and its rank of the preferences, the lower the better
+type candidateIP struct {
+	ip        net.IP
+	iface     string
+	ifaceRank int
+	cidrRank  int
+}
+
+// chooseLocalIP chooses the local ip from @interfaces by @opts, or returns nil if there isn't any
+func chooseLocalIP(interfaces []hostInterface, opts NetworkOptions) net.IP {
+	candidates := make([]candidateIP, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if iface.flags&net.FlagUp == 0 || iface.flags&net.FlagLoopback != 0 {
+			continue
+		}
+		for _, ip := range iface.ips {
+			if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+				continue
+			}
+			candidates = append(candidates, candidateIP{
+				ip:        ip,
+				iface:     iface.name,
+				ifaceRank: rankOf(len(opts.PreferredInterfaces), func(i int) bool { return opts.PreferredInterfaces[i] == iface.name }),
+				cidrRank:  rankOf(len(opts.PreferredCIDRs), func(i int) bool { return opts.PreferredCIDRs[i].Contains(ip) }),
+			})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	preferred := make([]candidateIP, 0, len(candidates))
+	if len(opts.PreferredInterfaces) > 0 || len(opts.PreferredCIDRs) > 0 {
+		for _, c := range candidates {
+			if c.ifaceRank >= 0 && c.cidrRank >= 0 {
+				preferred = append(preferred, c)
+			}
+		}
+		if len(preferred) == 0 {
+			logger.Warnf("no ip is found in the preferred interfaces %v and cidrs %v, choose one of the others",
+				opts.PreferredInterfaces, opts.PreferredCIDRs)
+		}
+		sort.SliceStable(preferred, func(i, j int) bool {
+			if preferred[i].ifaceRank != preferred[j].ifaceRank {
+				return preferred[i].ifaceRank < preferred[j].ifaceRank
+			}
+			return preferred[i].cidrRank < preferred[j].cidrRank
+		})
+	}
+	if len(preferred) == 0 {
+		for _, c := range candidates {
+			if !isVirtualInterface(c.iface) {
+				preferred = append(preferred, c)
+			}
+		}
+	}
+	if len(preferred) == 0 {
+		preferred = candidates
+	}
+
+	for _, c := range preferred {
+		if (c.ip.To4() == nil) == opts.PreferIPv6 {
+			return c.ip
+		}
+	}
+	return preferred[0].ip
+}
+
+// rankOf returns the index of the first one of the @n preferences matching, 0 if there isn't any preference,
+// or -1 if none matches
+func rankOf(n int, match func(i int) bool) int {
+	if n == 0 {
+		return 0
+	}
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetLocalHostName() string {
 	if len(localHostname) != 0 {
 		return localHostname
@@ -56,20 +233,60 @@ func GetLocalHostName() string {
 	return localHostname
 }
 
+// HandleRegisterIPAndPort sets the ip and the port of @url to the ones advertised to the registries, see
+// GetRegistryIP and GetRegistryPort
 func HandleRegisterIPAndPort(url *URL) {
-	// if developer define registry port and ip, use it first.
-	if ipToRegistry := os.Getenv(constant.DubboIpToRegistryKey); len(ipToRegistry) > 0 {
-		url.Ip = ipToRegistry
+	url.Ip = GetRegistryIP(url)
+	url.Port = GetRegistryPort(url)
+}
+
+// GetRegistryIP returns the ip of @url advertised to the registries, the first one of
+//   - the environment variable {PROTOCOL}_DUBBO_IP_TO_REGISTRY, e.g. TRI_DUBBO_IP_TO_REGISTRY, the same as dubbo java
+//   - the environment variable DUBBO_IP_TO_REGISTRY
+//   - the host of the protocol config, i.e. the param host-to-registry of @url
+//   - the ip of @url, i.e. the ip the server binds, unless it's unspecified or a loopback one
+//   - the local ip, see GetLocalIp
+func GetRegistryIP(url *URL) string {
+	if ip := registryEnv(url, constant.DubboIpToRegistryKey); len(ip) > 0 {
+		return ip
 	}
-	if len(url.Ip) == 0 {
-		url.Ip = GetLocalIp()
+	if host := url.GetParam(constant.HostToRegistryKey, ""); len(host) > 0 {
+		return host
 	}
-	if portToRegistry := os.Getenv(constant.DubboPortToRegistryKey); isValidPort(portToRegistry) {
-		url.Port = portToRegistry
+	if len(url.Ip) > 0 && !isInvalidLocalHost(url.Ip) {
+		return url.Ip
+	}
+	return GetLocalIp()
+}
+
+// GetRegistryPort returns the port of @url advertised to the registries, the first valid one of the environment
+// variables {PROTOCOL}_DUBBO_PORT_TO_REGISTRY and DUBBO_PORT_TO_REGISTRY, the port of @url, and the default 80
+func GetRegistryPort(url *URL) string {
+	if port := registryEnv(url, constant.DubboPortToRegistryKey); isValidPort(port) {
+		return port
 	}
 	if len(url.Port) == 0 || url.Port == "0" {
-		url.Port = constant.DubboDefaultPortToRegistry
+		return constant.DubboDefaultPortToRegistry
+	}
+	return url.Port
+}
+
+// registryEnv returns the environment variable @key of the protocol of @url, or the one shared by the protocols
+func registryEnv(url *URL, key string) string {
+	if len(url.Protocol) > 0 {
+		if value := os.Getenv(strings.ToUpper(url.Protocol) + "_" + key); len(value) > 0 {
+			return value
+		}
+	}
+	return os.Getenv(key)
+}
+
+func isInvalidLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsUnspecified() || ip.IsLoopback())
 }
 
 func isValidPort(port string) bool {
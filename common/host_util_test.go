@@ -18,6 +18,7 @@
 package common
 
 import (
+	"net"
 	"os"
 	"testing"
 )
@@ -77,3 +78,96 @@ func TestIsValidPort(t *testing.T) {
 	assert.Equal(t, false, isValidPort("65536"))
 	assert.Equal(t, true, isValidPort("20000"))
 }
+
+func TestGetRegistryIP(t *testing.T) {
+	defer func() {
+		_ = os.Unsetenv(constant.DubboIpToRegistryKey)
+		_ = os.Unsetenv("TRI_" + constant.DubboIpToRegistryKey)
+	}()
+	_ = os.Unsetenv(constant.DubboIpToRegistryKey)
+
+	url, _ := NewURL("tri://0.0.0.0:20000/com.test.Service")
+	assert.Equal(t, GetLocalIp(), GetRegistryIP(url))
+	url.Ip = "127.0.0.1"
+	assert.Equal(t, GetLocalIp(), GetRegistryIP(url))
+	// the ip the server binds
+	url.Ip = "10.0.0.1"
+	assert.Equal(t, "10.0.0.1", GetRegistryIP(url))
+	// the host of the protocol config
+	url.SetParam(constant.HostToRegistryKey, "1.2.3.4")
+	assert.Equal(t, "1.2.3.4", GetRegistryIP(url))
+	// the environment variables
+	_ = os.Setenv(constant.DubboIpToRegistryKey, "5.6.7.8")
+	assert.Equal(t, "5.6.7.8", GetRegistryIP(url))
+	_ = os.Setenv("TRI_"+constant.DubboIpToRegistryKey, "9.9.9.9")
+	assert.Equal(t, "9.9.9.9", GetRegistryIP(url))
+	url.Protocol = "dubbo"
+	assert.Equal(t, "5.6.7.8", GetRegistryIP(url))
+}
+
+func TestGetRegistryPort(t *testing.T) {
+	defer func() {
+		_ = os.Unsetenv(constant.DubboPortToRegistryKey)
+		_ = os.Unsetenv("TRI_" + constant.DubboPortToRegistryKey)
+	}()
+	_ = os.Unsetenv(constant.DubboPortToRegistryKey)
+
+	url, _ := NewURL("tri://10.0.0.1:20000/com.test.Service")
+	assert.Equal(t, "20000", GetRegistryPort(url))
+	_ = os.Setenv("TRI_"+constant.DubboPortToRegistryKey, "30000")
+	assert.Equal(t, "30000", GetRegistryPort(url))
+	_ = os.Setenv("TRI_"+constant.DubboPortToRegistryKey, "invalid")
+	assert.Equal(t, "20000", GetRegistryPort(url))
+}
+
+func TestChooseLocalIP(t *testing.T) {
+	up := net.FlagUp | net.FlagBroadcast
+	interfaces := []hostInterface{
+		{name: "lo", flags: up | net.FlagLoopback, ips: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}},
+		{name: "docker0", flags: up, ips: []net.IP{net.ParseIP("172.17.0.1")}},
+		{name: "eth0", flags: up, ips: []net.IP{net.ParseIP("fe80::1"), net.ParseIP("2001:db8::1"), net.ParseIP("192.168.1.10")}},
+		{name: "eth1", flags: up, ips: []net.IP{net.ParseIP("10.0.0.10")}},
+		{name: "eth2", ips: []net.IP{net.ParseIP("10.1.0.10")}},
+	}
+	cidr := func(s string) *net.IPNet {
+		_, ipNet, err := net.ParseCIDR(s)
+		assert.NoError(t, err)
+		return ipNet
+	}
+
+	// the docker bridge is skipped, and an ipv4 address is chosen
+	assert.Equal(t, "192.168.1.10", chooseLocalIP(interfaces, NetworkOptions{}).String())
+	assert.Equal(t, "2001:db8::1", chooseLocalIP(interfaces, NetworkOptions{PreferIPv6: true}).String())
+	assert.Equal(t, "10.0.0.10", chooseLocalIP(interfaces, NetworkOptions{PreferredInterfaces: []string{"eth1", "eth0"}}).String())
+	assert.Equal(t, "172.17.0.1", chooseLocalIP(interfaces, NetworkOptions{PreferredInterfaces: []string{"docker0"}}).String())
+	assert.Equal(t, "10.0.0.10", chooseLocalIP(interfaces, NetworkOptions{
+		PreferredCIDRs: []*net.IPNet{cidr("10.0.0.0/8"), cidr("192.168.0.0/16")}}).String())
+	// the down interface is skipped, and the others are chosen if none is preferred
+	assert.Equal(t, "192.168.1.10", chooseLocalIP(interfaces, NetworkOptions{PreferredInterfaces: []string{"eth2"}}).String())
+	assert.Equal(t, "192.168.1.10", chooseLocalIP(interfaces, NetworkOptions{
+		PreferredInterfaces: []string{"eth1"}, PreferredCIDRs: []*net.IPNet{cidr("192.168.0.0/16")}}).String())
+	// the ipv6 one is chosen if it's the only one
+	assert.Equal(t, "2001:db8::1", chooseLocalIP(interfaces, NetworkOptions{
+		PreferredCIDRs: []*net.IPNet{cidr("2001:db8::/32")}}).String())
+	// the docker bridge is chosen if it's the only one
+	assert.Equal(t, "172.17.0.1", chooseLocalIP(interfaces[:2], NetworkOptions{}).String())
+	assert.Nil(t, chooseLocalIP(interfaces[:1], NetworkOptions{}))
+}
+
+func TestGetLocalIpWithNetworkOptions(t *testing.T) {
+	defer func() {
+		hostInterfaces = listHostInterfaces
+		SetNetworkOptions(NetworkOptions{})
+	}()
+	hostInterfaces = func() ([]hostInterface, error) {
+		return []hostInterface{
+			{name: "eth0", flags: net.FlagUp, ips: []net.IP{net.ParseIP("192.168.1.10")}},
+			{name: "eth1", flags: net.FlagUp, ips: []net.IP{net.ParseIP("10.0.0.10")}},
+		}, nil
+	}
+
+	SetNetworkOptions(NetworkOptions{})
+	assert.Equal(t, "192.168.1.10", GetLocalIp())
+	SetNetworkOptions(NetworkOptions{PreferredInterfaces: []string{"eth1"}})
+	assert.Equal(t, "10.0.0.10", GetLocalIp())
+}
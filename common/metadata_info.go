@@ -18,8 +18,8 @@
 package common
 
 import (
-	"fmt"
-	"hash/crc32"
+	"crypto/md5"
+	"encoding/hex"
 	"net/url"
 	"sort"
 	"strings"
@@ -36,7 +36,6 @@ import (
 var IncludeKeys = gxset.NewSet(
 	constant.ApplicationKey,
 	constant.GroupKey,
-	constant.TimestampKey,
 	constant.SerializationKey,
 	constant.ClusterKey,
 	constant.LoadbalanceKey,
@@ -76,43 +75,31 @@ func (mi *MetadataInfo) JavaClassName() string {
 	return "org.apache.dubbo.metadata.MetadataInfo"
 }
 
-// CalAndGetRevision is different from Dubbo because golang doesn't support overload
-// so that we should use interface + method name as identifier and ignore the method params
-// in my opinion, it's enough because Dubbo actually ignore the URL params.
-// please refer org.apache.dubbo.common.URL#toParameterString(java.lang.String...)
+// CalAndGetRevision calculates the revision the same way as Dubbo 3 so that the applications in different languages
+// agree on it: the md5 of the app name followed by the description of each service in the order of their match keys.
+// please refer org.apache.dubbo.metadata.MetadataInfo#calAndGetRevision
 func (mi *MetadataInfo) CalAndGetRevision() string {
 	if mi.Revision != "" && mi.Reported {
 		return mi.Revision
 	}
 	if len(mi.Services) == 0 {
-		return "0"
+		mi.Revision = "0"
+		return mi.Revision
 	}
-	candidates := make([]string, 0, 8)
-
-	for _, s := range mi.Services {
-		iface := s.URL.GetParam(constant.InterfaceKey, "")
-		ms := s.URL.Methods
-		if len(ms) == 0 {
-			candidates = append(candidates, iface)
-		} else {
-			for _, m := range ms {
-				// methods are part of candidates
-				candidates = append(candidates, iface+constant.KeySeparator+m)
-			}
-		}
-
-		// append URL params if we need it
+	keys := make([]string, 0, len(mi.Services))
+	for k := range mi.Services {
+		keys = append(keys, k)
 	}
-	sort.Strings(candidates)
+	sort.Strings(keys)
 
-	// it's nearly impossible to be overflow
-	res := uint64(0)
-	for _, c := range candidates {
-		res += uint64(crc32.ChecksumIEEE([]byte(c)))
+	var sb strings.Builder
+	sb.WriteString(mi.App)
+	for _, k := range keys {
+		sb.WriteString(mi.Services[k].toDescString())
 	}
-	mi.Revision = fmt.Sprint(res)
+	sum := md5.Sum([]byte(sb.String()))
+	mi.Revision = hex.EncodeToString(sum[:])
 	return mi.Revision
-
 }
 
 // nolint
@@ -131,6 +118,7 @@ func (mi *MetadataInfo) AddService(service *ServiceInfo) {
 		return
 	}
 	mi.Services[service.GetMatchKey()] = service
+	mi.Reported = false
 }
 
 // nolint
@@ -139,6 +127,7 @@ func (mi *MetadataInfo) RemoveService(service *ServiceInfo) {
 		return
 	}
 	delete(mi.Services, service.MatchKey)
+	mi.Reported = false
 }
 
 // ServiceInfo the information of service
@@ -247,3 +236,27 @@ func (si *ServiceInfo) GetServiceKey() string {
 	si.ServiceKey = ServiceKey(si.Name, si.Group, si.Version)
 	return si.ServiceKey
 }
+
+// toDescString is the same as ServiceInfo#toDescString in Dubbo, the params are printed like a java TreeMap
+func (si *ServiceInfo) toDescString() string {
+	keys := make([]string, 0, len(si.Params))
+	for k := range si.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(si.GetMatchKey())
+	sb.WriteString(si.Path)
+	sb.WriteString("{")
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(si.Params[k])
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
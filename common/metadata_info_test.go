@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// the expected revisions are calculated by org.apache.dubbo.metadata.MetadataInfo#calAndGetRevision
+func TestMetadataInfoCalAndGetRevision(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []*ServiceInfo
+		revision string
+	}{
+		{
+			name:     "empty",
+			revision: "0",
+		},
+		{
+			name: "one service",
+			services: []*ServiceInfo{
+				NewServiceInfo("org.apache.dubbo.demo.DemoService", "", "", "dubbo", "/org.apache.dubbo.demo.DemoService",
+					map[string]string{"application": "demo-provider", "serialization": "hessian2", "timeout": "3000"}),
+			},
+			revision: "8b8155dd39f6221f151e9fc969b24686",
+		},
+		{
+			name: "services in the order of match keys",
+			services: []*ServiceInfo{
+				NewServiceInfo("org.apache.dubbo.demo.GreetService", "", "", "dubbo", "org.apache.dubbo.demo.GreetService", nil),
+				NewServiceInfo("org.apache.dubbo.demo.DemoService", "g1", "1.0.0", "tri", "org.apache.dubbo.demo.DemoService",
+					map[string]string{"sayHello.timeout": "1000", "application": "demo-provider"}),
+			},
+			revision: "c8d37d8cfadf1ff190b99e64147d04c5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mi := NewMetadataInfWithApp("demo-provider")
+			for _, s := range tt.services {
+				mi.AddService(s)
+			}
+			assert.Equal(t, tt.revision, mi.CalAndGetRevision())
+		})
+	}
+}
+
+func TestMetadataInfoRevisionIgnoresTimestamp(t *testing.T) {
+	newInfo := func(timestamp string) *MetadataInfo {
+		u, err := NewURL("dubbo://127.0.0.1:20000/com.test.Service?interface=com.test.Service&application=demo&timestamp=" + timestamp)
+		assert.Nil(t, err)
+		mi := NewMetadataInfWithApp("demo")
+		mi.AddService(NewServiceInfoWithURL(u))
+		return mi
+	}
+	mi := newInfo("1600000000")
+	assert.NotContains(t, mi.Services["com.test.Service:dubbo"].Params, constant.TimestampKey)
+	assert.Equal(t, mi.CalAndGetRevision(), newInfo("1700000000").CalAndGetRevision())
+}
+
+func TestMetadataInfoRevisionChangesWithServices(t *testing.T) {
+	mi := NewMetadataInfWithApp("demo")
+	s := NewServiceInfo("com.test.Service", "", "", "dubbo", "com.test.Service", nil)
+	mi.AddService(s)
+	revision := mi.CalAndGetRevision()
+	mi.MarkReported()
+	assert.Equal(t, revision, mi.CalAndGetRevision())
+
+	mi.AddService(NewServiceInfo("com.test.Other", "", "", "dubbo", "com.test.Other", nil))
+	assert.False(t, mi.HasReported())
+	assert.NotEqual(t, revision, mi.CalAndGetRevision())
+
+	mi.RemoveService(NewServiceInfo("com.test.Other", "", "", "dubbo", "com.test.Other", nil))
+	assert.Equal(t, revision, mi.CalAndGetRevision())
+}
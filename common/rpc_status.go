@@ -0,0 +1,201 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCStatus classifies the failure of an invocation, so that the applications, the clusters and the filters can tell
+// a timeout from a business error etc. without matching the error messages. The codes of the provider cross the wire
+// by their names, see constant.ErrorCodeKey, and the dubbo response status is mapped to them as well.
+type RPCStatus int
+
+const (
+	// StatusUnknown is the code of the errors which can not be classified
+	StatusUnknown RPCStatus = iota
+	// StatusNotSent means the request never left the consumer, e.g. the connection could not be established
+	StatusNotSent
+	// StatusTimeout means the request was sent but no response arrived in time, the dubbo response status
+	// CLIENT_TIMEOUT and SERVER_TIMEOUT
+	StatusTimeout
+	// StatusNetwork means the connection broke after the request was sent
+	StatusNetwork
+	// StatusBiz means the provider handled the request and returned an error, the dubbo response status SERVICE_ERROR
+	StatusBiz
+	// StatusThrottled means the request is rejected for the rate limit, the dubbo response status
+	// SERVER_THREADPOOL_EXHAUSTED_ERROR
+	StatusThrottled
+	// StatusInternal means the provider failed for an internal error, e.g. a panic, the dubbo response status
+	// SERVER_ERROR
+	StatusInternal
+	// StatusPermissionDenied means the provider refused the consumer, e.g. for its address
+	StatusPermissionDenied
+	// StatusNoProvider means there is no provider available for the request
+	StatusNoProvider
+	// StatusCircuitOpen means the request is short-circuited by an open circuit breaker
+	StatusCircuitOpen
+	// StatusCancelled means the request is cancelled by the consumer
+	StatusCancelled
+	// StatusSerialization means the request or the response can't be serialized, the dubbo response status
+	// BAD_REQUEST and BAD_RESPONSE
+	StatusSerialization
+	// StatusServiceNotFound means the provider doesn't export the service, the dubbo response status SERVICE_NOT_FOUND
+	StatusServiceNotFound
+)
+
+var rpcStatusNames = map[RPCStatus]string{
+	StatusUnknown:          "unknown",
+	StatusNotSent:          "notsent",
+	StatusTimeout:          "timeout",
+	StatusNetwork:          "network",
+	StatusBiz:              "biz",
+	StatusThrottled:        "throttled",
+	StatusInternal:         "internal",
+	StatusPermissionDenied: "denied",
+	StatusNoProvider:       "noprovider",
+	StatusCircuitOpen:      "circuitopen",
+	StatusCancelled:        "cancelled",
+	StatusSerialization:    "serialization",
+	StatusServiceNotFound:  "notfound",
+}
+
+// String returns the name of the code, which is also used in the retry.on configuration and the metrics labels
+func (s RPCStatus) String() string {
+	if name, ok := rpcStatusNames[s]; ok {
+		return name
+	}
+	return rpcStatusNames[StatusUnknown]
+}
+
+// ParseRPCStatus parses the name of a code, false if @name is not a valid one
+func ParseRPCStatus(name string) (RPCStatus, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for code, n := range rpcStatusNames {
+		if n == name {
+			return code, true
+		}
+	}
+	return StatusUnknown, false
+}
+
+// RPCStatusCoder is implemented by the errors which know their codes, e.g. the errors of the filters
+type RPCStatusCoder interface {
+	RPCStatus() RPCStatus
+}
+
+// RPCError is an error tagged with an RPCStatus by the layer which raised it
+type RPCError struct {
+	Code RPCStatus
+	Err  error
+}
+
+// NewRPCError tags @err with @code, nil if @err is nil
+func NewRPCError(code RPCStatus, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RPCError{Code: code, Err: err}
+}
+
+func (e *RPCError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RPCError) Unwrap() error {
+	return e.Err
+}
+
+// CodeOf returns the code of @err. The code tagged by NewRPCError or told by an RPCStatusCoder is preferred,
+// otherwise the code is inferred from the errors of the context, the network and the grpc status.
+func CodeOf(err error) RPCStatus {
+	if err == nil {
+		return StatusUnknown
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	var coder RPCStatusCoder
+	if errors.As(err, &coder) {
+		return coder.RPCStatus()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StatusTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return StatusCancelled
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return StatusNotSent
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return StatusTimeout
+		}
+		return StatusNetwork
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.DeadlineExceeded:
+			return StatusTimeout
+		case codes.Canceled:
+			return StatusCancelled
+		case codes.Unavailable:
+			return StatusNetwork
+		case codes.ResourceExhausted:
+			return StatusThrottled
+		case codes.Internal:
+			return StatusInternal
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return StatusPermissionDenied
+		case codes.Unimplemented:
+			return StatusServiceNotFound
+		case codes.Unknown:
+			return StatusUnknown
+		default:
+			return StatusBiz
+		}
+	}
+	return StatusUnknown
+}
+
+// IsRetriable returns whether the request failed with @err is safe to retry on another provider by default: it never
+// reached a provider, or the provider failed regardless of the request. The business errors, the throttled, denied,
+// cancelled requests and the serialization failures are not retried, as the other providers fail the same way or the
+// retries only add to the load. The unknown errors are retried as the clusters always did.
+func IsRetriable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch CodeOf(err) {
+	case StatusBiz, StatusThrottled, StatusPermissionDenied, StatusCancelled, StatusSerialization:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusCoder is an error which knows its code
+type statusCoder struct{}
+
+func (statusCoder) Error() string {
+	return "circuit open"
+}
+
+func (statusCoder) RPCStatus() RPCStatus {
+	return StatusCircuitOpen
+}
+
+func TestCodeOf(t *testing.T) {
+	cases := []struct {
+		err  error
+		code RPCStatus
+	}{
+		{nil, StatusUnknown},
+		{perrors.New("error"), StatusUnknown},
+		{perrors.WithStack(NewRPCError(StatusTimeout, perrors.New("timeout"))), StatusTimeout},
+		{perrors.Wrap(NewRPCError(StatusNoProvider, perrors.New("no provider")), "invoke"), StatusNoProvider},
+		{perrors.Wrap(statusCoder{}, "invoke"), StatusCircuitOpen},
+		{perrors.Wrap(context.DeadlineExceeded, "call"), StatusTimeout},
+		{perrors.Wrap(context.Canceled, "call"), StatusCancelled},
+		{&net.OpError{Op: "dial", Err: perrors.New("connection refused")}, StatusNotSent},
+		{&net.OpError{Op: "read", Err: perrors.New("connection reset")}, StatusNetwork},
+		{status.Error(codes.DeadlineExceeded, "deadline"), StatusTimeout},
+		{status.Error(codes.Canceled, "canceled"), StatusCancelled},
+		{status.Error(codes.Unavailable, "unavailable"), StatusNetwork},
+		{status.Error(codes.ResourceExhausted, "throttled"), StatusThrottled},
+		{status.Error(codes.Internal, "internal"), StatusInternal},
+		{status.Error(codes.PermissionDenied, "denied"), StatusPermissionDenied},
+		{status.Error(codes.Unauthenticated, "unauthenticated"), StatusPermissionDenied},
+		{status.Error(codes.Unimplemented, "unimplemented"), StatusServiceNotFound},
+		{status.Error(codes.Unknown, "unknown"), StatusUnknown},
+		{status.Error(codes.InvalidArgument, "invalid"), StatusBiz},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.code, CodeOf(c.err), "%v", c.err)
+	}
+	assert.Nil(t, NewRPCError(StatusBiz, nil))
+}
+
+func TestIsRetriable(t *testing.T) {
+	assert.False(t, IsRetriable(nil))
+	assert.True(t, IsRetriable(perrors.New("error")))
+	for code := range rpcStatusNames {
+		err := NewRPCError(code, perrors.New(code.String()))
+		switch code {
+		case StatusBiz, StatusThrottled, StatusPermissionDenied, StatusCancelled, StatusSerialization:
+			assert.False(t, IsRetriable(err), code.String())
+		default:
+			assert.True(t, IsRetriable(err), code.String())
+		}
+	}
+}
+
+func TestParseRPCStatus(t *testing.T) {
+	for code := range rpcStatusNames {
+		parsed, ok := ParseRPCStatus(" " + code.String() + " ")
+		assert.True(t, ok)
+		assert.Equal(t, code, parsed)
+	}
+	_, ok := ParseRPCStatus("bad")
+	assert.False(t, ok)
+	assert.Equal(t, "unknown", RPCStatus(-1).String())
+}
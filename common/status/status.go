@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package status checks the health of the components of the application, like the registries, the config center
+// and the references, for the liveness and the readiness probes. The components register their StatusCheckers by
+// extension.SetStatusChecker, and Check aggregates them.
+package status
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Level is the level of a Status
+type Level int
+
+const (
+	// LevelOK means the component works
+	LevelOK Level = iota
+	// LevelWarn means the component works partially, which doesn't fail the probes
+	LevelWarn
+	// LevelError means the component doesn't work
+	LevelError
+)
+
+var levelNames = [...]string{"OK", "WARN", "ERROR"}
+
+// nolint
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// MarshalText marshals the level as its name in the json
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText unmarshals the level from its name
+func (l *Level) UnmarshalText(text []byte) error {
+	for i, name := range levelNames {
+		if name == string(text) {
+			*l = Level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status level %q", text)
+}
+
+// Status is the status of a component
+type Status struct {
+	Level   Level  `json:"level"`
+	Message string `json:"message,omitempty"`
+}
+
+// OK returns the Status of LevelOK with the @message
+func OK(message string) Status {
+	return Status{Level: LevelOK, Message: message}
+}
+
+// Warn returns the Status of LevelWarn with the @message
+func Warn(message string) Status {
+	return Status{Level: LevelWarn, Message: message}
+}
+
+// Error returns the Status of LevelError with the @message
+func Error(message string) Status {
+	return Status{Level: LevelError, Message: message}
+}
+
+// StatusChecker checks the status of a component
+type StatusChecker interface {
+	// Check returns the status of the component, it should return in time once the @ctx is done
+	Check(ctx context.Context) Status
+}
+
+// CheckerFunc is the StatusChecker by a func
+type CheckerFunc func(ctx context.Context) Status
+
+func (f CheckerFunc) Check(ctx context.Context) Status {
+	return f(ctx)
+}
+
+// CheckerResult is the status of a checker in a Result
+type CheckerResult struct {
+	Status
+	// Gating is true if the status of the checker decides the Result
+	Gating bool `json:"gating"`
+}
+
+// Result is the aggregated status of the checkers
+type Result struct {
+	// Up is false if any of the gating checkers is at LevelError
+	Up bool `json:"up"`
+	// Message explains why the result is down if it's not caused by the checkers, like the shutdown
+	Message  string                   `json:"message,omitempty"`
+	Checkers map[string]CheckerResult `json:"checkers"`
+}
+
+// Check runs the @checkers concurrently, each of them is bounded by the @timeout and is at LevelError if it doesn't
+// return in time or panics. The result is down if any of the checkers named in the @gating is at LevelError, and a
+// gating checker absent from the @checkers is at LevelError, which catches the typos of the config. The others are
+// only reported.
+func Check(ctx context.Context, checkers map[string]StatusChecker, gating []string, timeout time.Duration) *Result {
+	gates := make(map[string]bool, len(gating))
+	for _, name := range gating {
+		gates[name] = true
+	}
+	result := &Result{Up: true, Checkers: make(map[string]CheckerResult, len(checkers))}
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+	)
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func(name string, checker StatusChecker) {
+			defer wg.Done()
+			status := checkWithTimeout(ctx, checker, timeout)
+			lock.Lock()
+			defer lock.Unlock()
+			result.Checkers[name] = CheckerResult{Status: status, Gating: gates[name]}
+		}(name, checker)
+	}
+	wg.Wait()
+	for name := range gates {
+		if _, ok := result.Checkers[name]; !ok {
+			result.Checkers[name] = CheckerResult{Status: Error("no such checker"), Gating: true}
+		}
+	}
+	for _, checker := range result.Checkers {
+		if checker.Gating && checker.Level == LevelError {
+			result.Up = false
+		}
+	}
+	return result
+}
+
+// checkWithTimeout runs the @checker, and returns LevelError if it doesn't return in the @timeout or panics
+func checkWithTimeout(ctx context.Context, checker StatusChecker, timeout time.Duration) Status {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan Status, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				done <- Error(fmt.Sprintf("panic: %v", e))
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+	select {
+	case status := <-done:
+		return status
+	case <-ctx.Done():
+		return Error(fmt.Sprintf("timeout after %v", timeout))
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	checkers := map[string]StatusChecker{
+		"ok":   CheckerFunc(func(context.Context) Status { return OK("fine") }),
+		"warn": CheckerFunc(func(context.Context) Status { return Warn("degraded") }),
+		"down": CheckerFunc(func(context.Context) Status { return Error("unreachable") }),
+		"slow": CheckerFunc(func(ctx context.Context) Status {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return OK("")
+		}),
+		"panic": CheckerFunc(func(context.Context) Status { panic("boom") }),
+	}
+
+	// the checkers not gating the result are only reported
+	start := time.Now()
+	result := Check(context.Background(), checkers, []string{"ok", "warn"}, 100*time.Millisecond)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.True(t, result.Up)
+	assert.Equal(t, CheckerResult{Status: OK("fine"), Gating: true}, result.Checkers["ok"])
+	assert.Equal(t, CheckerResult{Status: Warn("degraded"), Gating: true}, result.Checkers["warn"])
+	assert.Equal(t, CheckerResult{Status: Error("unreachable")}, result.Checkers["down"])
+	assert.Equal(t, CheckerResult{Status: Error("timeout after 100ms")}, result.Checkers["slow"])
+	assert.Equal(t, CheckerResult{Status: Error("panic: boom")}, result.Checkers["panic"])
+
+	result = Check(context.Background(), checkers, []string{"ok", "slow"}, 100*time.Millisecond)
+	assert.False(t, result.Up)
+
+	// the absent gating checker fails the result
+	result = Check(context.Background(), checkers, []string{"typo"}, 100*time.Millisecond)
+	assert.False(t, result.Up)
+	assert.Equal(t, CheckerResult{Status: Error("no such checker"), Gating: true}, result.Checkers["typo"])
+}
+
+func TestResultJSON(t *testing.T) {
+	result := Check(context.Background(), map[string]StatusChecker{
+		"registry": CheckerFunc(func(context.Context) Status { return Error("unavailable registries: zookeeper://127.0.0.1:2181") }),
+	}, []string{"registry"}, time.Second)
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"up":false,"checkers":{"registry":{"level":"ERROR",`+
+		`"message":"unavailable registries: zookeeper://127.0.0.1:2181","gating":true}}}`, string(data))
+
+	unmarshaled := &Result{}
+	assert.NoError(t, json.Unmarshal(data, unmarshaled))
+	assert.Equal(t, result, unmarshaled)
+	assert.Error(t, json.Unmarshal([]byte(`{"level":"FATAL"}`), &Status{}))
+}
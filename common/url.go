@@ -24,9 +24,11 @@ import (
 	"math"
 	"net"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -34,11 +36,10 @@ import (
 	cm "github.com/Workiva/go-datastructures/common"
 
 	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/log/logger"
 
 	"github.com/google/uuid"
 
-	"github.com/jinzhu/copier"
-
 	perrors "github.com/pkg/errors"
 )
 
@@ -98,17 +99,23 @@ func (*noCopy) Lock()   {}
 func (*noCopy) Unlock() {}
 
 // URL thread-safe. but this URL should not be copied.
-// we fail to define this struct to be immutable object.
-// but, those method which will update the URL, including SetParam, SetParams
-// are only allowed to be invoked in creating URL instance
-// Please keep in mind that this struct is immutable after it has been created and initialized.
+// The params are copied on write: SetParam and the other writers copy the params, modify the copy and replace the
+// params with it, so that the readers like GetParam load the params without locking while the params are modified,
+// e.g. by the overrides of the directory. The params loaded are never modified, and Clone shares them with the clone.
+// ToReadOnly returns a read only view of the URL for the routers and the others which shouldn't modify it.
+// Besides the params, the URL carries the attributes, the runtime objects which are never serialized, see SetAttribute.
 type URL struct {
 	noCopy noCopy
 
 	baseURL
-	// url.Values is not safe map, add to avoid concurrent map read and map write error
-	paramsLock sync.RWMutex
-	params     url.Values
+	// paramsLock serializes the writers of params, which holds the url.Values never modified once stored
+	paramsLock sync.Mutex
+	params     atomic.Value
+	// readOnly URL panics on modifying the params, see ToReadOnly
+	readOnly bool
+	// attributes hold the runtime objects passed between the layers with the URL, see SetAttribute
+	attributesLock sync.RWMutex
+	attributes     map[string]interface{}
 
 	Path     string // like  /com.ikurento.dubbo.UserProvider
 	Username string
@@ -224,7 +231,7 @@ func NewURLWithOptions(opts ...Option) *URL {
 	for _, opt := range opts {
 		opt(newURL)
 	}
-	newURL.Location = newURL.Ip + ":" + newURL.Port
+	newURL.Location = joinHostPort(newURL.Ip, newURL.Port)
 	return newURL
 }
 
@@ -236,9 +243,19 @@ func NewURL(urlString string, opts ...Option) (*URL, error) {
 		return &s, nil
 	}
 
-	rawURLString, err := url.QueryUnescape(urlString)
-	if err != nil {
-		return &s, perrors.Errorf("URL.QueryUnescape(%s),  error{%v}", urlString, err)
+	// registry node names are the whole url encoded once more, decode them
+	// once and leave the params, e.g. the nested refer url, as Java wrote them
+	rawURLString := strings.TrimSpace(urlString)
+	if isEncodedURL(rawURLString) {
+		decoded, err := URLDecode(rawURLString)
+		if err != nil {
+			return &s, perrors.Errorf("URL.QueryUnescape(%s),  error{%v}", urlString, err)
+		}
+		rawURLString = decoded
+	}
+	var query string
+	if i := strings.IndexByte(rawURLString, '?'); i >= 0 {
+		rawURLString, query = rawURLString[:i], rawURLString[i+1:]
 	}
 
 	// rawURLString = "//" + rawURLString
@@ -252,13 +269,9 @@ func NewURL(urlString string, opts ...Option) (*URL, error) {
 
 	serviceURL, urlParseErr := url.Parse(rawURLString)
 	if urlParseErr != nil {
-		return &s, perrors.Errorf("URL.Parse(URL string{%s}),  error{%v}", rawURLString, err)
-	}
-
-	s.params, err = url.ParseQuery(serviceURL.RawQuery)
-	if err != nil {
-		return &s, perrors.Errorf("URL.ParseQuery(raw URL string{%s}),  error{%v}", serviceURL.RawQuery, err)
+		return &s, perrors.Errorf("URL.Parse(URL string{%s}),  error{%v}", rawURLString, urlParseErr)
 	}
+	s.params.Store(parseQuery(query))
 
 	s.PrimitiveURL = urlString
 	s.Protocol = serviceURL.Scheme
@@ -266,6 +279,7 @@ func NewURL(urlString string, opts ...Option) (*URL, error) {
 	s.Password, _ = serviceURL.User.Password()
 	s.Location = serviceURL.Host
 	s.Path = serviceURL.Path
+	var err error
 	for _, location := range strings.Split(s.Location, ",") {
 		location = strings.Trim(location, " ")
 		if strings.Contains(location, ":") {
@@ -279,8 +293,8 @@ func NewURL(urlString string, opts ...Option) (*URL, error) {
 	for _, opt := range opts {
 		opt(&s)
 	}
-	if s.params.Get(constant.RegistryGroupKey) != "" {
-		s.PrimitiveURL = strings.Join([]string{s.PrimitiveURL, s.params.Get(constant.RegistryGroupKey)}, constant.PathSeparator)
+	if group := s.GetParam(constant.RegistryGroupKey, ""); group != "" {
+		s.PrimitiveURL = strings.Join([]string{s.PrimitiveURL, group}, constant.PathSeparator)
 	}
 	return &s, nil
 }
@@ -304,12 +318,35 @@ func (c *URL) Version() string {
 	return c.GetParam(constant.VersionKey, "")
 }
 
-// Address with format "ip:port"
+// Address with format "ip:port", or "[ip]:port" for an ipv6 ip
 func (c *URL) Address() string {
 	if c.Port == "" {
 		return c.Ip
 	}
-	return c.Ip + ":" + c.Port
+	return joinHostPort(c.Ip, c.Port)
+}
+
+// splitLocation splits the first address of @location into the host and the port, which is empty if absent,
+// an ipv6 host is enclosed in the square brackets if there is a port
+func splitLocation(location string) (host, port string) {
+	if i := strings.IndexByte(location, ','); i >= 0 {
+		location = strings.TrimSpace(location[:i])
+	}
+	if h, p, err := net.SplitHostPort(location); err == nil {
+		return h, p
+	}
+	if i := strings.LastIndexByte(location, ':'); i >= 0 && !strings.Contains(location[:i], ":") {
+		return location[:i], location[i+1:]
+	}
+	return location, ""
+}
+
+// joinHostPort joins @host and @port like net.JoinHostPort, and keeps the colon even if both are empty
+func joinHostPort(host, port string) string {
+	if strings.Contains(host, ":") {
+		return "[" + host + "]:" + port
+	}
+	return host + ":" + port
 }
 
 // URLEqual judge @URL and @c is equal or not.
@@ -344,31 +381,93 @@ func (c *URL) URLEqual(url *URL) bool {
 	}
 
 	// TODO :may need add interface key any value condition
-	return isMatchCategory(tmpURL.GetParam(constant.CategoryKey, constant.DefaultCategory), tmpC.GetParam(constant.CategoryKey, constant.DefaultCategory))
+	return IsMatchCategory(tmpURL.GetParam(constant.CategoryKey, constant.DefaultCategory), tmpC.GetParam(constant.CategoryKey, constant.DefaultCategory))
 }
 
-func isMatchCategory(category1 string, category2 string) bool {
-	if len(category2) == 0 {
-		return category1 == constant.DefaultCategory
-	} else if strings.Contains(category2, constant.AnyValue) {
-		return true
-	} else if strings.Contains(category2, constant.RemoveValuePrefix) {
-		return !strings.Contains(category2, constant.RemoveValuePrefix+category1)
+func (c *URL) String() string {
+	var buf strings.Builder
+	if len(c.Username) == 0 && len(c.Password) == 0 {
+		buf.WriteString(fmt.Sprintf("%s://%s%s?", c.Protocol, joinHostPort(c.Ip, c.Port), c.Path))
 	} else {
-		return strings.Contains(category2, category1)
+		buf.WriteString(fmt.Sprintf("%s://%s:%s@%s%s?", c.Protocol, c.Username, c.Password, joinHostPort(c.Ip, c.Port), c.Path))
 	}
+	// a '+' would be read back as is, so spaces are written as %20
+	buf.WriteString(strings.ReplaceAll(c.loadParams().Encode(), "+", "%20"))
+	return buf.String()
 }
 
-func (c *URL) String() string {
-	c.paramsLock.Lock()
-	defer c.paramsLock.Unlock()
+// RedactedValue replaces the secrets redacted from the urls and the configurations
+const RedactedValue = "******"
+
+// secretKeys are the fragments of the keys whose values are secrets
+var secretKeys = []string{"password", "secret", "token", "access-key", "accesskey", "key-file", "private-key"}
+
+// IsSecretKey tells whether the value of the param or the config with @key is a secret, like a password or a token
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns the string of the url like String, with the password and the values of the secret params, like
+// the tokens and the access keys, replaced by RedactedValue, so that it could be shown to the operators
+func (c *URL) Redacted() string {
 	var buf strings.Builder
 	if len(c.Username) == 0 && len(c.Password) == 0 {
-		buf.WriteString(fmt.Sprintf("%s://%s:%s%s?", c.Protocol, c.Ip, c.Port, c.Path))
+		buf.WriteString(fmt.Sprintf("%s://%s%s?", c.Protocol, joinHostPort(c.Ip, c.Port), c.Path))
 	} else {
-		buf.WriteString(fmt.Sprintf("%s://%s:%s@%s:%s%s?", c.Protocol, c.Username, c.Password, c.Ip, c.Port, c.Path))
+		buf.WriteString(fmt.Sprintf("%s://%s:%s@%s%s?", c.Protocol, c.Username, RedactedValue, joinHostPort(c.Ip, c.Port), c.Path))
+	}
+	params := c.loadParams()
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// the params are encoded like String, except that the redacted values are written as is to stay readable
+	first := true
+	for _, k := range keys {
+		for _, v := range params[k] {
+			if !first {
+				buf.WriteByte('&')
+			}
+			first = false
+			buf.WriteString(url.QueryEscape(k) + "=")
+			if IsSecretKey(k) {
+				buf.WriteString(RedactedValue)
+			} else {
+				buf.WriteString(strings.ReplaceAll(url.QueryEscape(v), "+", "%20"))
+			}
+		}
+	}
+	return buf.String()
+}
+
+// ToFullString serializes the url like Java Dubbo's URL#toFullString, which is
+// what Java registers and reads back with URL.valueOf. Params are sorted so the
+// result is stable and can be used to compare urls.
+func (c *URL) ToFullString() string {
+	var buf strings.Builder
+	buf.WriteString(c.Protocol)
+	buf.WriteString("://")
+	if len(c.Username) > 0 || len(c.Password) > 0 {
+		buf.WriteString(c.Username)
+		if len(c.Password) > 0 {
+			buf.WriteString(":" + c.Password)
+		}
+		buf.WriteString("@")
+	}
+	buf.WriteString(c.Address())
+	if c.Path != "" {
+		buf.WriteString("/" + strings.TrimPrefix(c.Path, "/"))
+	}
+	if query := ToQueryString(c.loadParams()); query != "" {
+		buf.WriteString("?" + query)
 	}
-	buf.WriteString(c.params.Encode())
 	return buf.String()
 }
 
@@ -443,8 +542,7 @@ func ParseServiceKey(serviceKey string) (string, string, string) {
 
 // IsAnyCondition judges if is any condition
 func IsAnyCondition(intf, group, version string, serviceURL *URL) bool {
-	return intf == constant.AnyValue && (group == constant.AnyValue ||
-		group == serviceURL.Group()) && (version == constant.AnyValue || version == serviceURL.Version())
+	return intf == constant.AnyValue && MatchGroup(group, serviceURL.Group()) && MatchVersion(version, serviceURL.Version())
 }
 
 // ColonSeparatedKey
@@ -489,59 +587,75 @@ func (c *URL) Service() string {
 	return ""
 }
 
-// AddParam will add the key-value pair
-func (c *URL) AddParam(key string, value string) {
+// loadParams returns the params, which must not be modified
+func (c *URL) loadParams() url.Values {
+	params, _ := c.params.Load().(url.Values)
+	return params
+}
+
+// updateParams replaces the params with a copy of them modified by @update. The values of a key in the copy are
+// shared with the params replaced, so that @update should replace them instead of modifying them.
+func (c *URL) updateParams(update func(params url.Values)) {
+	if c.readOnly {
+		panic(fmt.Sprintf("the url %s is read only, clone it to modify the params", c.Key()))
+	}
 	c.paramsLock.Lock()
 	defer c.paramsLock.Unlock()
-	if c.params == nil {
-		c.params = url.Values{}
+	old := c.loadParams()
+	params := make(url.Values, len(old)+1)
+	for k, v := range old {
+		params[k] = v
 	}
-	c.params.Add(key, value)
+	update(params)
+	c.params.Store(params)
+}
+
+// AddParam will add the key-value pair
+func (c *URL) AddParam(key string, value string) {
+	c.updateParams(func(params url.Values) {
+		values := make([]string, 0, len(params[key])+1)
+		params[key] = append(append(values, params[key]...), value)
+	})
 }
 
 // AddParamAvoidNil will add key-value pair
 func (c *URL) AddParamAvoidNil(key string, value string) {
-	c.paramsLock.Lock()
-	defer c.paramsLock.Unlock()
-	if c.params == nil {
-		c.params = url.Values{}
-	}
-	c.params.Add(key, value)
+	c.AddParam(key, value)
 }
 
 // SetParam will put the key-value pair into URL
 // usually it should only be invoked when you want to initialized an URL
 func (c *URL) SetParam(key string, value string) {
-	c.paramsLock.Lock()
-	defer c.paramsLock.Unlock()
-	if c.params == nil {
-		c.params = url.Values{}
-	}
-	c.params.Set(key, value)
+	c.updateParams(func(params url.Values) {
+		params.Set(key, value)
+	})
 }
 
 // DelParam will delete the given key from the URL
 func (c *URL) DelParam(key string) {
-	c.paramsLock.Lock()
-	defer c.paramsLock.Unlock()
-	if c.params != nil {
-		c.params.Del(key)
+	if _, ok := c.loadParams()[key]; !ok {
+		return
 	}
+	c.updateParams(func(params url.Values) {
+		params.Del(key)
+	})
 }
 
 // ReplaceParams will replace the URL.params
-// usually it should only be invoked when you want to modify an URL, such as MergeURL
+// usually it should only be invoked when you want to modify an URL, such as MergeURL.
+// @param is owned by the URL since then, which must not be modified.
 func (c *URL) ReplaceParams(param url.Values) {
+	if c.readOnly {
+		panic(fmt.Sprintf("the url %s is read only, clone it to modify the params", c.Key()))
+	}
 	c.paramsLock.Lock()
 	defer c.paramsLock.Unlock()
-	c.params = param
+	c.params.Store(param)
 }
 
 // RangeParams will iterate the params
 func (c *URL) RangeParams(f func(key, value string) bool) {
-	c.paramsLock.RLock()
-	defer c.paramsLock.RUnlock()
-	for k, v := range c.params {
+	for k, v := range c.loadParams() {
 		if !f(k, v[0]) {
 			break
 		}
@@ -550,13 +664,7 @@ func (c *URL) RangeParams(f func(key, value string) bool) {
 
 // GetParam gets value by key
 func (c *URL) GetParam(s string, d string) string {
-	c.paramsLock.RLock()
-	defer c.paramsLock.RUnlock()
-
-	var r string
-	if len(c.params) > 0 {
-		r = c.params.Get(s)
-	}
+	r := c.loadParams().Get(s)
 	if len(r) == 0 {
 		r = d
 	}
@@ -566,20 +674,14 @@ func (c *URL) GetParam(s string, d string) string {
 
 // GetNonDefaultParam gets value by key, return nil,false if no value found mapping to the key
 func (c *URL) GetNonDefaultParam(s string) (string, bool) {
-	c.paramsLock.RLock()
-	defer c.paramsLock.RUnlock()
-
-	var r string
-	if len(c.params) > 0 {
-		r = c.params.Get(s)
-	}
-
+	r := c.loadParams().Get(s)
 	return r, r != ""
 }
 
-// GetParams gets values
+// GetParams gets the params, which are shared by the URL and its clones and must not be modified, modify the params
+// by SetParam and the others instead
 func (c *URL) GetParams() url.Values {
-	return c.params
+	return c.loadParams()
 }
 
 // GetParamAndDecoded gets values and decode
@@ -597,7 +699,8 @@ func (c *URL) GetRawParam(key string) string {
 	case "username":
 		return c.Username
 	case "host":
-		return strings.Split(c.Location, ":")[0]
+		host, _ := splitLocation(c.Location)
+		return host
 	case "password":
 		return c.Password
 	case "port":
@@ -645,9 +748,76 @@ func (c *URL) GetParamByIntValue(key string, d int) int {
 	return int(r)
 }
 
+// GetParamDuration gets the duration of @key, which is either a duration like 3s or the bare milliseconds like 3000,
+// see ParseDuration. It returns 0 if @key is absent, or the error if the value is invalid.
+func (c *URL) GetParamDuration(key string) (time.Duration, error) {
+	value := c.GetParam(key, "")
+	if value == "" {
+		return 0, nil
+	}
+	t, err := ParseDuration(value)
+	if err != nil {
+		return 0, perrors.WithMessagef(err, "invalid %s=%s", key, value)
+	}
+	return t, nil
+}
+
+// GetParamInt64Strict gets int64 value by @key, it returns @d if @key is absent, or the error if the value is invalid
+func (c *URL) GetParamInt64Strict(key string, d int64) (int64, error) {
+	value := c.GetParam(key, "")
+	if value == "" {
+		return d, nil
+	}
+	r, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return d, perrors.Errorf("invalid %s=%s, it should be an integer", key, value)
+	}
+	return r, nil
+}
+
+// GetParamBoolStrict gets bool value by @key, it returns @d if @key is absent, or the error if the value is invalid
+func (c *URL) GetParamBoolStrict(key string, d bool) (bool, error) {
+	value := c.GetParam(key, "")
+	if value == "" {
+		return d, nil
+	}
+	r, err := strconv.ParseBool(value)
+	if err != nil {
+		return d, perrors.Errorf("invalid %s=%s, it should be true or false", key, value)
+	}
+	return r, nil
+}
+
+// MustParamDuration is GetParamDuration which panics if the value is invalid
+func (c *URL) MustParamDuration(key string) time.Duration {
+	t, err := c.GetParamDuration(key)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// MustParamInt64 is GetParamInt64Strict which panics if the value is invalid
+func (c *URL) MustParamInt64(key string, d int64) int64 {
+	r, err := c.GetParamInt64Strict(key, d)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// MustParamBool is GetParamBoolStrict which panics if the value is invalid
+func (c *URL) MustParamBool(key string, d bool) bool {
+	r, err := c.GetParamBoolStrict(key, d)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 // GetMethodParamInt gets int method param
 func (c *URL) GetMethodParamInt(method string, key string, d int64) int64 {
-	r, err := strconv.ParseInt(c.GetParam("methods."+method+"."+key, ""), 10, 64)
+	r, err := strconv.ParseInt(c.methodParam(method, key), 10, 64)
 	if err != nil {
 		return d
 	}
@@ -656,7 +826,7 @@ func (c *URL) GetMethodParamInt(method string, key string, d int64) int64 {
 
 // GetMethodParamIntValue gets int method param
 func (c *URL) GetMethodParamIntValue(method string, key string, d int) int {
-	r, err := strconv.ParseInt(c.GetParam("methods."+method+"."+key, ""), 10, 0)
+	r, err := strconv.ParseInt(c.methodParam(method, key), 10, 0)
 	if err != nil {
 		return d
 	}
@@ -674,27 +844,99 @@ func (c *URL) GetMethodParamInt64(method string, key string, d int64) int64 {
 
 // GetMethodParam gets method param
 func (c *URL) GetMethodParam(method string, key string, d string) string {
-	r := c.GetParam("methods."+method+"."+key, "")
+	r := c.methodParam(method, key)
 	if r == "" {
 		r = d
 	}
 	return r
 }
 
+// GetMethodParamDuration gets the duration method param, see ParseDuration, it returns @d if the param is absent
+// or invalid
+func (c *URL) GetMethodParamDuration(method string, key string, d time.Duration) time.Duration {
+	r, err := ParseDuration(c.methodParam(method, key))
+	if err != nil {
+		return d
+	}
+	return r
+}
+
 // GetMethodParamBool judge whether @method param exists or not
 func (c *URL) GetMethodParamBool(method string, key string, d bool) bool {
-	r := c.GetParamBool("methods."+method+"."+key, d)
+	r, err := strconv.ParseBool(c.methodParam(method, key))
+	if err != nil {
+		return d
+	}
+	return r
+}
+
+// methodParam gets the method level value of @key for @method. The value of the method, like methods.getUser.timeout,
+// beats the values of the wildcard methods matching it, like methods.get*.timeout and methods.*.timeout, and the
+// wildcard with more literal characters beats the other ones.
+func (c *URL) methodParam(method string, key string) string {
+	if r := c.GetParam(constant.MethodKeys+"."+method+"."+key, ""); r != "" {
+		return r
+	}
+	var (
+		r           string
+		matched     string
+		specificity = -1
+	)
+	c.RangeParams(func(k, v string) bool {
+		pattern, ok := methodParamPattern(k, key)
+		if !ok || v == "" || !strings.Contains(pattern, "*") || !MatchMethodPattern(pattern, method) {
+			return true
+		}
+		if n := len(pattern) - strings.Count(pattern, "*"); n > specificity || n == specificity && pattern < matched {
+			r, matched, specificity = v, pattern, n
+		}
+		return true
+	})
 	return r
 }
 
+// methodParamPattern returns the method name or the wildcard of the method level @key of @paramKey, like getUser or
+// get* of methods.get*.timeout
+func methodParamPattern(key, paramKey string) (string, bool) {
+	if !isMethodParamKey(key, paramKey) {
+		return "", false
+	}
+	pattern := key[len(constant.MethodKeys)+1 : len(key)-len(paramKey)-1]
+	if strings.Contains(pattern, ".") {
+		return "", false
+	}
+	return pattern, true
+}
+
+// MatchMethodPattern returns whether @method matches @pattern, in which '*' matches any characters
+func MatchMethodPattern(pattern, method string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == method
+	}
+	if !strings.HasPrefix(method, parts[0]) {
+		return false
+	}
+	method = method[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(method, part)
+		if i < 0 {
+			return false
+		}
+		method = method[i+len(part):]
+	}
+	return strings.HasSuffix(method, parts[len(parts)-1])
+}
+
 // SetParams will put all key-value pair into URL.
 // 1. if there already has same key, the value will be override
-// 2. it's not thread safe
-// 3. think twice when you want to invoke this method
+// 2. think twice when you want to invoke this method
 func (c *URL) SetParams(m url.Values) {
-	for k := range m {
-		c.SetParam(k, m.Get(k))
-	}
+	c.updateParams(func(params url.Values) {
+		for k := range m {
+			params.Set(k, m.Get(k))
+		}
+	})
 }
 
 // ToMap transfer URL to Map
@@ -716,13 +958,11 @@ func (c *URL) ToMap() map[string]string {
 		paramsMap["password"] = c.Password
 	}
 	if c.Location != "" {
-		paramsMap["host"] = strings.Split(c.Location, ":")[0]
-		var port string
-		if strings.Contains(c.Location, ":") {
-			port = strings.Split(c.Location, ":")[1]
-		} else {
+		host, port := splitLocation(c.Location)
+		if port == "" {
 			port = "0"
 		}
+		paramsMap["host"] = host
 		paramsMap["port"] = port
 	}
 	if c.Protocol != "" {
@@ -741,18 +981,35 @@ func (c *URL) ToMap() map[string]string {
 //  in this function we should merge the reference local URL config into the service URL from registry.
 // TODO configuration merge, in the future , the configuration center's config should merge too.
 
+// referenceOverrideKeys are the keys whose reference config overrides the service config, both on the service
+// level and on the method level
+var referenceOverrideKeys = []string{
+	constant.LoadbalanceKey, constant.RetriesKey, constant.TimeoutKey,
+	constant.RetryableKey, constant.RetryBackoffKey, constant.RetryBackoffMultiplierKey, constant.RetryOnKey,
+}
+
+// isMethodParamKey returns whether @key is the method level key of @paramKey, like methods.sayHello.retries
+func isMethodParamKey(key, paramKey string) bool {
+	return strings.HasPrefix(key, constant.MethodKeys+".") && strings.HasSuffix(key, "."+paramKey) &&
+		len(key) > len(constant.MethodKeys)+len(paramKey)+2
+}
+
 // MergeURL will merge those two URL
 // the result is based on serviceURL, and the key which si only contained in referenceURL
 // will be added into result.
 // for example, if serviceURL contains params (a1->v1, b1->v2) and referenceURL contains params(a2->v3, b1 -> v4)
 // the params of result will be (a1->v1, b1->v2, a2->v3).
 // You should notice that the value of b1 is v2, not v4
-// except constant.LoadbalanceKey, constant.ClusterKey, constant.RetriesKey, constant.TimeoutKey.
+// except the keys of referenceOverrideKeys, e.g. constant.LoadbalanceKey, constant.RetriesKey and constant.TimeoutKey,
+// and constant.ClusterKey if the reference url carries methods.
 // due to URL is not thread-safe, so this method is not thread-safe
 func MergeURL(serviceURL *URL, referenceURL *URL) *URL {
 	// After Clone, it is a new URL that there is no thread safe issue.
 	mergedURL := serviceURL.Clone()
-	params := mergedURL.GetParams()
+	params := make(url.Values, len(mergedURL.GetParams()))
+	for key, value := range mergedURL.GetParams() {
+		params[key] = value
+	}
 	// iterator the referenceURL if serviceURL not have the key ,merge in
 	// referenceURL usually will not changed. so change RangeParams to GetParams to avoid the string value copy.// Group get group
 	for key, value := range referenceURL.GetParams() {
@@ -765,25 +1022,71 @@ func MergeURL(serviceURL *URL, referenceURL *URL) *URL {
 		}
 	}
 
+	// the service url read from the registry has no attributes, the consumer ones are kept for the invoker
+	referenceURL.RangeAttributes(func(key string, value interface{}) bool {
+		if _, ok := mergedURL.GetAttribute(key); !ok {
+			mergedURL.SetAttribute(key, value)
+		}
+		return true
+	})
+
+	// the datacenter is where the provider runs, it must not be inherited from the consumer
+	if _, ok := serviceURL.GetNonDefaultParam(constant.DatacenterKey); !ok {
+		delete(params, constant.DatacenterKey)
+	}
+
 	// remote timestamp
 	if v, ok := serviceURL.GetNonDefaultParam(constant.TimestampKey); !ok {
 		params[constant.RemoteTimestampKey] = []string{v}
 		params[constant.TimestampKey] = []string{referenceURL.GetParam(constant.TimestampKey, "")}
 	}
 
-	// finally execute methodConfigMergeFcn
-	for _, method := range referenceURL.Methods {
-		for _, paramKey := range []string{constant.LoadbalanceKey, constant.ClusterKey, constant.RetriesKey, constant.TimeoutKey} {
-			if v := referenceURL.GetParam(paramKey, ""); len(v) > 0 {
-				params[paramKey] = []string{v}
-			}
-
-			methodsKey := "methods." + method + "." + paramKey
-			//if len(mergedURL.GetParam(methodsKey, "")) == 0 {
+	// the cluster of the reference overrides the one of the service only if the reference url carries methods
+	if len(referenceURL.Methods) > 0 {
+		if v := referenceURL.GetParam(constant.ClusterKey, ""); len(v) > 0 {
+			params[constant.ClusterKey] = []string{v}
+		}
+		for _, method := range referenceURL.Methods {
+			methodsKey := "methods." + method + "." + constant.ClusterKey
 			if v := referenceURL.GetParam(methodsKey, ""); len(v) > 0 {
 				params[methodsKey] = []string{v}
 			}
-			//}
+		}
+	}
+	// the reference config of referenceOverrideKeys always overrides the service config, even if the reference url
+	// carries no methods
+	for _, paramKey := range referenceOverrideKeys {
+		if v := referenceURL.GetParam(paramKey, ""); len(v) > 0 {
+			params[paramKey] = []string{v}
+			// the service level config of the reference also beats the method level config of the service
+			for key := range params {
+				if isMethodParamKey(key, paramKey) && len(referenceURL.GetParam(key, "")) == 0 {
+					delete(params, key)
+				}
+			}
+		}
+	}
+	for key := range referenceURL.GetParams() {
+		for _, paramKey := range referenceOverrideKeys {
+			if !isMethodParamKey(key, paramKey) {
+				continue
+			}
+			v := referenceURL.GetParam(key, "")
+			if len(v) == 0 {
+				continue
+			}
+			params[key] = []string{v}
+			// the wildcard method config of the reference beats the method config of the service it matches
+			pattern, ok := methodParamPattern(key, paramKey)
+			if !ok || !strings.Contains(pattern, "*") {
+				continue
+			}
+			for k := range params {
+				if p, ok := methodParamPattern(k, paramKey); ok && MatchMethodPattern(pattern, p) &&
+					len(referenceURL.GetParam(k, "")) == 0 {
+					delete(params, k)
+				}
+			}
 		}
 	}
 	// In this way, we will raise some performance.
@@ -791,35 +1094,104 @@ func MergeURL(serviceURL *URL, referenceURL *URL) *URL {
 	return mergedURL
 }
 
-// Clone will copy the URL
+// Clone will copy the URL, the clone shares the params with the URL until either of them modifies them, and it could
+// be modified even if the URL is read only. The attributes are copied as well, the objects of which are shared.
 func (c *URL) Clone() *URL {
-	newURL := &URL{}
-	if err := copier.Copy(newURL, c); err != nil {
-		// this is impossible
-		return newURL
-	}
-	newURL.params = url.Values{}
-	c.RangeParams(func(key, value string) bool {
-		newURL.SetParam(key, value)
+	return c.cloneWithParams(c.loadParams())
+}
+
+// cloneWithParams returns a copy of the URL with @params, the params are copied on write by the copy as well
+func (c *URL) cloneWithParams(params url.Values) *URL {
+	newURL := &URL{
+		baseURL:  c.baseURL,
+		Path:     c.Path,
+		Username: c.Username,
+		Password: c.Password,
+		Methods:  append([]string(nil), c.Methods...),
+		SubURL:   c.SubURL,
+	}
+	newURL.params.Store(params)
+	c.RangeAttributes(func(key string, value interface{}) bool {
+		newURL.SetAttribute(key, value)
 		return true
 	})
 	return newURL
 }
 
-func (c *URL) CloneExceptParams(excludeParams *gxset.HashSet) *URL {
-	newURL := &URL{}
-	if err := copier.Copy(newURL, c); err != nil {
-		// this is impossible
-		return newURL
+// SetAttribute sets the runtime object @value of @key on the URL, e.g. the RPCService exported by the ServiceConfig.
+// Unlike the params, the attributes are never serialized, so they are neither in String nor registered, and they
+// are only visible to the layers holding the URL or its clones in this process: the config, the protocols, the
+// registry protocol and the filters. Anything read from the registry or the config center has no attributes.
+// The keys are defined in constant, e.g. constant.RPCServiceAttributeKey.
+func (c *URL) SetAttribute(key string, value interface{}) {
+	if c.readOnly {
+		panic(fmt.Sprintf("the url %s is read only, clone it to modify the attributes", c.Key()))
 	}
-	newURL.params = url.Values{}
-	c.RangeParams(func(key, value string) bool {
+	c.attributesLock.Lock()
+	defer c.attributesLock.Unlock()
+	if c.attributes == nil {
+		c.attributes = make(map[string]interface{})
+	}
+	c.attributes[key] = value
+}
+
+// GetAttribute gets the runtime object of @key set by SetAttribute
+func (c *URL) GetAttribute(key string) (interface{}, bool) {
+	c.attributesLock.RLock()
+	defer c.attributesLock.RUnlock()
+	value, ok := c.attributes[key]
+	return value, ok
+}
+
+// DeleteAttribute deletes the runtime object of @key
+func (c *URL) DeleteAttribute(key string) {
+	if c.readOnly {
+		panic(fmt.Sprintf("the url %s is read only, clone it to modify the attributes", c.Key()))
+	}
+	c.attributesLock.Lock()
+	defer c.attributesLock.Unlock()
+	delete(c.attributes, key)
+}
+
+// RangeAttributes iterates over the attributes of the URL until @f returns false
+func (c *URL) RangeAttributes(f func(key string, value interface{}) bool) {
+	c.attributesLock.RLock()
+	attributes := make(map[string]interface{}, len(c.attributes))
+	for key, value := range c.attributes {
+		attributes[key] = value
+	}
+	c.attributesLock.RUnlock()
+	for key, value := range attributes {
+		if !f(key, value) {
+			return
+		}
+	}
+}
+
+// ToReadOnly returns a read only view of the URL, the params of which are those of the URL when it's called, and
+// modifying the params of which panics. It's handed to the routers, which should never modify the URL they route by.
+func (c *URL) ToReadOnly() *URL {
+	if c == nil || c.readOnly {
+		return c
+	}
+	view := c.Clone()
+	view.readOnly = true
+	return view
+}
+
+// IsReadOnly returns whether the URL is a read only view, see ToReadOnly
+func (c *URL) IsReadOnly() bool {
+	return c.readOnly
+}
+
+func (c *URL) CloneExceptParams(excludeParams *gxset.HashSet) *URL {
+	params := make(url.Values, len(c.loadParams()))
+	for key, value := range c.loadParams() {
 		if !excludeParams.Contains(key) {
-			newURL.SetParam(key, value)
+			params[key] = value
 		}
-		return true
-	})
-	return newURL
+	}
+	return c.cloneWithParams(params)
 }
 
 func (c *URL) Compare(comp cm.Comparator) int {
@@ -921,11 +1293,21 @@ func GetCompareURLEqualFunc() CompareURLEqualFunc {
 	return compareURLEqualFunc
 }
 
-// GetParamDuration get duration if param is invalid or missing will return 3s
-func (c *URL) GetParamDuration(s string, d string) time.Duration {
-	if t, err := time.ParseDuration(c.GetParam(s, d)); err == nil {
+// GetParamDurationWithDefault gets the duration of @s, or @d if it's absent, both of which are either the durations
+// like 3s or the bare milliseconds like 3000, see ParseDuration. The invalid value is reported by a warning and @d is
+// used instead, and 3s is used if @d is invalid as well.
+func (c *URL) GetParamDurationWithDefault(s string, d string) time.Duration {
+	value := c.GetParam(s, d)
+	t, err := ParseDuration(value)
+	if err == nil {
 		return t
 	}
+	if value != d {
+		logger.Warnf("The %s=%s of %s is invalid, use %s instead: %v", s, value, c.Path, d, err)
+		if t, err = ParseDuration(d); err == nil {
+			return t
+		}
+	}
 	return 3 * time.Second
 }
 
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// URLEncode encodes value the way java.net.URLEncoder does, which is what
+// Java Dubbo uses for registry node names: '*' is kept and '~' is escaped.
+func URLEncode(value string) string {
+	encoded := url.QueryEscape(value)
+	encoded = strings.ReplaceAll(encoded, "%2A", "*")
+	return strings.ReplaceAll(encoded, "~", "%7E")
+}
+
+// URLDecode decodes value the way java.net.URLDecoder does.
+func URLDecode(value string) (string, error) {
+	return url.QueryUnescape(value)
+}
+
+// ToQueryString serializes params like Java Dubbo's URL#toFullString: keys are
+// sorted, values are trimmed and written as is unless they have to be escaped
+// to survive parsing, so the result is stable and matches what Java produces.
+func ToQueryString(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, k := range keys {
+		for _, v := range params[k] {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(escapeParam(k))
+			buf.WriteByte('=')
+			buf.WriteString(escapeParam(strings.TrimSpace(v)))
+		}
+	}
+	return buf.String()
+}
+
+// isEncodedURL reports whether s is a whole URL encoded once more, as found in
+// registry node names, e.g. "dubbo%3A%2F%2F1.2.3.4%3A20880%2Ffoo%3Fa%3Db".
+func isEncodedURL(s string) bool {
+	if strings.Contains(s, "?") || strings.Contains(s, "://") {
+		return false
+	}
+	upper := strings.ToUpper(s)
+	return strings.Contains(upper, "%3A%2F%2F") || strings.Contains(upper, "%3F")
+}
+
+// parseQuery parses the query the way Java Dubbo's URL.valueOf does: pairs are
+// split on '&' and the first '=', a key without '=' is its own value and empty
+// values are kept.
+func parseQuery(query string) url.Values {
+	params := url.Values{}
+	for _, part := range strings.Split(query, "&") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value := part, part
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+		params.Add(unescapeParam(key), unescapeParam(value))
+	}
+	return params
+}
+
+// unescapeParam decodes a key or value once if it has been encoded, which Java
+// does with java.net.URLEncoder for values like the refer url or a router rule.
+// Values Java writes as is are kept, so the '+' of "2021-03-08T15:24:23+08:00"
+// is not turned into a space, and neither is a bare "100%".
+func unescapeParam(s string) string {
+	if !hasEscape(s) {
+		return s
+	}
+	if unescaped, err := URLDecode(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// escapeParam is the inverse of unescapeParam: nested queries and urls and the
+// values that would be decoded by mistake are encoded as a whole like Java does.
+func escapeParam(s string) string {
+	if strings.ContainsAny(s, "&?#") || strings.Contains(s, "://") || hasEscape(s) {
+		return URLEncode(s)
+	}
+	return s
+}
+
+func hasEscape(s string) bool {
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] == '%' && isHex(s[i+1]) && isHex(s[i+2]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLEncode(t *testing.T) {
+	// java.net.URLEncoder keeps '*' and escapes '~', unlike url.QueryEscape
+	assert.Equal(t, "a*b%7Ec+d%2Be%3D%E4%B8%AD", URLEncode("a*b~c d+e=中"))
+	decoded, err := URLDecode("a*b%7Ec+d%2Be%3D%E4%B8%AD")
+	assert.NoError(t, err)
+	assert.Equal(t, "a*b~c d+e=中", decoded)
+}
+
+func TestParseQuery(t *testing.T) {
+	params := parseQuery("a=1&empty=&bare&plus=1+2&pct=100%&eq=x=y&nested=k%3Dv%26k2%3Dv2& &sp=a%20b&form=a+b%2Cc")
+	assert.Equal(t, url.Values{
+		"a":      {"1"},
+		"empty":  {""},
+		"bare":   {"bare"},
+		"plus":   {"1+2"},
+		"pct":    {"100%"},
+		"eq":     {"x=y"},
+		"nested": {"k=v&k2=v2"},
+		"sp":     {"a b"},
+		"form":   {"a b,c"},
+	}, params)
+
+	assert.Equal(t, "a=1&bare=bare&empty=&eq=x=y&form=a b,c&nested=k%3Dv%26k2%3Dv2&pct=100%&plus=1+2&sp=a b",
+		ToQueryString(params))
+	assert.Equal(t, params, parseQuery(ToQueryString(params)))
+	assert.Equal(t, "v=%2541", ToQueryString(url.Values{"v": {"%41"}}))
+	assert.Equal(t, url.Values{"v": {"%41"}}, parseQuery("v=%2541"))
+}
+
+func TestJavaRegistryURLs(t *testing.T) {
+	// urls registered by Java Dubbo 2.7, the node names are encoded once more
+	data, err := os.ReadFile("testdata/java_registry_urls.json")
+	assert.NoError(t, err)
+	var cases []struct {
+		Name     string            `json:"name"`
+		URL      string            `json:"url"`
+		Protocol string            `json:"protocol"`
+		IP       string            `json:"ip"`
+		Port     string            `json:"port"`
+		Path     string            `json:"path"`
+		Params   map[string]string `json:"params"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &cases))
+	assert.NotEmpty(t, cases)
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			u, err := NewURL(c.URL)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Protocol, u.Protocol)
+			assert.Equal(t, c.IP, u.Ip)
+			assert.Equal(t, c.Port, u.Port)
+			assert.Equal(t, c.Path, u.Path)
+			params := map[string]string{}
+			u.RangeParams(func(key, value string) bool {
+				params[key] = value
+				return true
+			})
+			assert.Equal(t, c.Params, params)
+
+			// serializing it again gives back exactly what Java wrote
+			full := u.ToFullString()
+			if isEncodedURL(c.URL) {
+				assert.Equal(t, c.URL, URLEncode(full))
+			} else {
+				assert.Equal(t, c.URL, full)
+			}
+
+			// and it survives both serializations of dubbo-go
+			for _, s := range []string{full, URLEncode(full), u.String()} {
+				again, err := NewURL(s)
+				assert.NoError(t, err)
+				assert.Equal(t, u.GetParams(), again.GetParams())
+				assert.Equal(t, full, again.ToFullString())
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// The matchers follow org.apache.dubbo.common.utils.UrlUtils#isMatch of Java, with the deviations:
+//   - a version could be a comma list like a group, e.g. version=1.0.0,2.0.0, while Java only matches it exactly;
+//   - the lists are split by commas and matched item by item, e.g. the group a doesn't match a group list ab,c, nor
+//     the category provider the categories providers, while Java matches them as substrings;
+//   - the rules, like the router rules, apply to all groups or versions when they don't specify any, see IsRuleMatch.
+
+// MatchGroup reports whether the group @pattern of a consumer matches the group @group of a provider: "*" matches any
+// group, a comma list like "a,b" matches each of its items, and the empty group only matches the empty group.
+func MatchGroup(pattern, group string) bool {
+	return matchItem(pattern, group)
+}
+
+// MatchVersion reports whether the version @pattern of a consumer matches the version @version of a provider, in the
+// same way as MatchGroup.
+func MatchVersion(pattern, version string) bool {
+	return matchItem(pattern, version)
+}
+
+func matchItem(pattern, value string) bool {
+	if pattern == constant.AnyValue || pattern == value {
+		return true
+	}
+	if !strings.Contains(pattern, constant.CommaSeparator) {
+		return false
+	}
+	for _, item := range strings.Split(pattern, constant.CommaSeparator) {
+		item = strings.TrimSpace(item)
+		if item == constant.AnyValue || item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchInterface reports whether the interface @pattern of a consumer matches the interface @intf of a provider,
+// either of them could be "*"
+func MatchInterface(pattern, intf string) bool {
+	return pattern == constant.AnyValue || intf == constant.AnyValue || pattern == intf
+}
+
+// IsServiceKeyMatch reports whether @providerURL serves the service of @consumerURL: the interfaces, the groups and
+// the versions match, see MatchInterface, MatchGroup and MatchVersion.
+func IsServiceKeyMatch(consumerURL, providerURL *URL) bool {
+	return MatchInterface(consumerURL.Service(), providerURL.Service()) &&
+		MatchGroup(consumerURL.Group(), providerURL.Group()) &&
+		MatchVersion(consumerURL.Version(), providerURL.Version())
+}
+
+// IsMatch reports whether @providerURL, a provider, a configurator or a router rule pushed by the registry, matches the
+// subscription of @consumerURL like UrlUtils#isMatch of Java: besides the service keys, the category of the provider
+// url is subscribed, it's enabled unless the consumer subscribes to the disabled ones by enabled=*, and the
+// classifiers match if the consumer specifies one.
+func IsMatch(consumerURL, providerURL *URL) bool {
+	if !IsServiceKeyMatch(consumerURL, providerURL) {
+		return false
+	}
+	if !IsMatchCategory(providerURL.GetParam(constant.CategoryKey, constant.DefaultCategory),
+		consumerURL.GetParam(constant.CategoryKey, constant.DefaultCategory)) {
+		return false
+	}
+	if !providerURL.GetParamBool(constant.EnabledKey, true) &&
+		consumerURL.GetParam(constant.EnabledKey, "") != constant.AnyValue {
+		return false
+	}
+	classifier := consumerURL.GetParam(constant.ClassifierKey, "")
+	return classifier == "" || classifier == constant.AnyValue ||
+		classifier == providerURL.GetParam(constant.ClassifierKey, "")
+}
+
+// IsMatchCategory reports whether @category is in the subscribed @categories, which is a comma list like
+// "providers,routers", "*" for all the categories, or the categories excluded like "-routers". The empty categories
+// subscribe to the providers.
+func IsMatchCategory(category, categories string) bool {
+	if categories == "" {
+		return category == constant.DefaultCategory
+	}
+	items := strings.Split(categories, constant.CommaSeparator)
+	excluded := false
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == constant.AnyValue:
+			return true
+		case strings.HasPrefix(item, constant.RemoveValuePrefix):
+			excluded = true
+			if item[len(constant.RemoveValuePrefix):] == category {
+				return false
+			}
+		case item == category:
+			return true
+		}
+	}
+	return excluded
+}
+
+// IsRuleMatch reports whether the rule @ruleURL, e.g. a router rule, applies to the service of @consumerURL. Unlike
+// the providers, the rule applies to all groups or versions of the service when it doesn't specify the group or the
+// version.
+func IsRuleMatch(ruleURL, consumerURL *URL) bool {
+	if !MatchInterface(ruleURL.Service(), consumerURL.Service()) {
+		return false
+	}
+	if group := ruleURL.Group(); group != "" && !MatchGroup(group, consumerURL.Group()) {
+		return false
+	}
+	version := ruleURL.Version()
+	return version == "" || MatchVersion(version, consumerURL.Version())
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package common
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGroupAndVersion(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"a", "", false},
+		{"a", "a", true},
+		{"a", "b", false},
+		{"*", "", true},
+		{"*", "a", true},
+		{"a,b", "a", true},
+		{"a,b", "b", true},
+		{"a, b", "b", true},
+		{"a,b", "c", false},
+		{"a,b", "", false},
+		{"ab,c", "a", false},
+		{"a,*", "c", true},
+		{"a,", "", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, MatchGroup(c.pattern, c.value), "group %q ~ %q", c.pattern, c.value)
+		assert.Equal(t, c.match, MatchVersion(c.pattern, c.value), "version %q ~ %q", c.pattern, c.value)
+	}
+}
+
+func TestIsServiceKeyMatch(t *testing.T) {
+	provider, _ := NewURL("dubbo://127.0.0.1:20000/com.foo.BarService?group=g1&version=1.0.0")
+	cases := []struct {
+		consumer string
+		match    bool
+	}{
+		{"consumer://127.0.0.1/com.foo.BarService?group=g1&version=1.0.0", true},
+		{"consumer://127.0.0.1/com.foo.BarService?group=g2&version=1.0.0", false},
+		{"consumer://127.0.0.1/com.foo.BarService?group=g1&version=2.0.0", false},
+		{"consumer://127.0.0.1/com.foo.BarService?version=1.0.0", false},
+		{"consumer://127.0.0.1/com.foo.BarService?group=*&version=*", true},
+		{"consumer://127.0.0.1/com.foo.BarService?group=g2,g1&version=1.0.0,2.0.0", true},
+		{"consumer://127.0.0.1/com.foo.QuxService?group=g1&version=1.0.0", false},
+		{"consumer://127.0.0.1/*?group=g1&version=1.0.0", true},
+		{"consumer://127.0.0.1/?interface=com.foo.BarService&group=g1&version=1.0.0", true},
+	}
+	for _, c := range cases {
+		consumer, err := NewURL(c.consumer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, IsServiceKeyMatch(consumer, provider), c.consumer)
+	}
+}
+
+func TestIsMatch(t *testing.T) {
+	consumer, _ := NewURL("consumer://127.0.0.1/com.foo.BarService?group=g1&version=1.0.0" +
+		"&category=providers,configurators,routers")
+	cases := []struct {
+		provider string
+		match    bool
+	}{
+		{"dubbo://127.0.0.1:20000/com.foo.BarService?group=g1&version=1.0.0", true},
+		{"dubbo://127.0.0.1:20000/com.foo.BarService?group=g1&version=1.0.0&category=providers", true},
+		{"override://0.0.0.0/com.foo.BarService?group=g1&version=1.0.0&category=configurators", true},
+		{"dubbo://127.0.0.1:20000/com.foo.BarService?group=g1&version=1.0.0&category=consumers", false},
+		{"dubbo://127.0.0.1:20000/com.foo.BarService?group=g1&version=1.0.0&enabled=false", false},
+		{"dubbo://127.0.0.1:20000/com.foo.BarService?group=g2&version=1.0.0", false},
+	}
+	for _, c := range cases {
+		provider, err := NewURL(c.provider)
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, IsMatch(consumer, provider), c.provider)
+	}
+
+	// the disabled ones and the classifiers
+	provider, _ := NewURL("dubbo://127.0.0.1:20000/com.foo.BarService?enabled=false&classifier=c1")
+	consumer, _ = NewURL("consumer://127.0.0.1/com.foo.BarService?enabled=*")
+	assert.True(t, IsMatch(consumer, provider))
+	consumer, _ = NewURL("consumer://127.0.0.1/com.foo.BarService?enabled=*&classifier=c1")
+	assert.True(t, IsMatch(consumer, provider))
+	consumer, _ = NewURL("consumer://127.0.0.1/com.foo.BarService?enabled=*&classifier=c2")
+	assert.False(t, IsMatch(consumer, provider))
+}
+
+func TestIsMatchCategory(t *testing.T) {
+	assert.True(t, IsMatchCategory("providers", ""))
+	assert.False(t, IsMatchCategory("routers", ""))
+	assert.True(t, IsMatchCategory("routers", "*"))
+	assert.True(t, IsMatchCategory("routers", "providers,routers"))
+	assert.False(t, IsMatchCategory("provider", "providers,routers"))
+	assert.True(t, IsMatchCategory("providers", "-routers"))
+	assert.False(t, IsMatchCategory("routers", "-routers"))
+}
+
+func TestIsRuleMatch(t *testing.T) {
+	consumer, _ := NewURL("consumer://127.0.0.1/com.foo.BarService?group=g1&version=1.0.0")
+	cases := []struct {
+		rule  string
+		match bool
+	}{
+		{"condition://0.0.0.0/com.foo.BarService?category=routers", true},
+		{"condition://0.0.0.0/com.foo.BarService?category=routers&group=g1", true},
+		{"condition://0.0.0.0/com.foo.BarService?category=routers&group=g2", false},
+		{"condition://0.0.0.0/com.foo.BarService?category=routers&version=1.0.0", true},
+		{"condition://0.0.0.0/com.foo.BarService?category=routers&version=2.0.0", false},
+		{"condition://0.0.0.0/com.foo.BarService?category=routers&group=*&version=*", true},
+		{"condition://0.0.0.0/com.foo.QuxService?category=routers", false},
+	}
+	for _, c := range cases {
+		rule, err := NewURL(c.rule)
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, IsRuleMatch(rule, consumer), c.rule)
+	}
+}
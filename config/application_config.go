@@ -17,6 +17,10 @@
 
 package config
 
+import (
+	"net"
+)
+
 import (
 	"github.com/creasty/defaults"
 
@@ -24,7 +28,9 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 )
 
 // ApplicationConfig is a configuration for current applicationConfig, whether the applicationConfig is a provider or a consumer
@@ -39,6 +45,29 @@ type ApplicationConfig struct {
 	// the metadata type. remote or local
 	MetadataType string `default:"local" yaml:"metadata-type" json:"metadataType,omitempty" property:"metadataType"`
 	Tag          string `yaml:"tag" json:"tag,omitempty" property:"tag"`
+	// MetadataServicePort is the port of the MetadataService exported over the dubbo protocol, the MetadataService
+	// shares the server of the dubbo protocol if it's empty
+	MetadataServicePort string `yaml:"metadata-service-port" json:"metadataServicePort,omitempty" property:"metadataServicePort"`
+	// GoroutinePools sizes the goroutine pools of the framework by the names, i.e. registry-notify and
+	// metadata-publish, the defaults are kept for the zero values
+	GoroutinePools map[string]*GoroutinePoolConfig `yaml:"goroutine-pools" json:"goroutinePools,omitempty" property:"goroutinePools"`
+	// Network chooses the local ip advertised to the registries, see common.GetLocalIp
+	Network *NetworkConfig `yaml:"network" json:"network,omitempty" property:"network"`
+}
+
+// NetworkConfig is the preferences of choosing the local ip among the network interfaces, see common.NetworkOptions
+type NetworkConfig struct {
+	// PreferredInterfaces are the names of the interfaces in order, e.g. eth0
+	PreferredInterfaces []string `yaml:"preferred-interfaces" json:"preferredInterfaces,omitempty" property:"preferredInterfaces"`
+	// PreferredCIDRs are the networks in order, e.g. 10.0.0.0/8
+	PreferredCIDRs []string `yaml:"preferred-cidrs" json:"preferredCIDRs,omitempty" property:"preferredCIDRs"`
+	PreferIPv6     bool     `yaml:"prefer-ipv6" json:"preferIPv6,omitempty" property:"preferIPv6"`
+}
+
+// GoroutinePoolConfig is the size of a goroutine pool
+type GoroutinePoolConfig struct {
+	Workers   int `yaml:"workers" json:"workers,omitempty" property:"workers"`
+	QueueSize int `yaml:"queue-size" json:"queueSize,omitempty" property:"queueSize"`
 }
 
 // Prefix dubbo.application
@@ -54,6 +83,30 @@ func (ac *ApplicationConfig) Init() error {
 	if err := ac.check(); err != nil {
 		return err
 	}
+	for name, pool := range ac.GoroutinePools {
+		if pool == nil {
+			continue
+		}
+		if pool.Workers < 0 || pool.QueueSize < 0 {
+			return errors.Errorf("the size of the goroutine pool %s is negative, workers: %d, queue-size: %d",
+				name, pool.Workers, pool.QueueSize)
+		}
+		gopool.Configure(name, pool.Workers, pool.QueueSize)
+	}
+	if ac.Network != nil {
+		opts := common.NetworkOptions{
+			PreferredInterfaces: ac.Network.PreferredInterfaces,
+			PreferIPv6:          ac.Network.PreferIPv6,
+		}
+		for _, cidr := range ac.Network.PreferredCIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return errors.Wrapf(err, "invalid preferred cidr %s", cidr)
+			}
+			opts.PreferredCIDRs = append(opts.PreferredCIDRs, ipNet)
+		}
+		common.SetNetworkOptions(opts)
+	}
 	return nil
 }
 
@@ -107,6 +160,24 @@ func (acb *ApplicationConfigBuilder) SetMetadataType(metadataType string) *Appli
 	return acb
 }
 
+func (acb *ApplicationConfigBuilder) SetMetadataServicePort(port string) *ApplicationConfigBuilder {
+	acb.application.MetadataServicePort = port
+	return acb
+}
+
+func (acb *ApplicationConfigBuilder) SetGoroutinePool(name string, workers, queueSize int) *ApplicationConfigBuilder {
+	if acb.application.GoroutinePools == nil {
+		acb.application.GoroutinePools = make(map[string]*GoroutinePoolConfig)
+	}
+	acb.application.GoroutinePools[name] = &GoroutinePoolConfig{Workers: workers, QueueSize: queueSize}
+	return acb
+}
+
+func (acb *ApplicationConfigBuilder) SetNetwork(network *NetworkConfig) *ApplicationConfigBuilder {
+	acb.application.Network = network
+	return acb
+}
+
 func (acb *ApplicationConfigBuilder) Build() *ApplicationConfig {
 	return acb.application
 }
@@ -26,7 +26,9 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 )
 
 func TestApplicationConfig(t *testing.T) {
@@ -64,3 +66,31 @@ func TestApplicationConfigBuilder(t *testing.T) {
 	assert.Equal(t, application.MetadataType, "metadataType")
 	assert.Equal(t, application.Prefix(), constant.ApplicationConfigPrefix)
 }
+
+func TestApplicationConfigGoroutinePools(t *testing.T) {
+	application := NewApplicationConfigBuilder().
+		SetGoroutinePool("application-config-test", 3, 7).
+		Build()
+	assert.Nil(t, application.Init())
+	pool := gopool.GetPool("application-config-test")
+	assert.Equal(t, 3, pool.MaxWorkers())
+	assert.Equal(t, 7, pool.QueueSize())
+
+	application = NewApplicationConfigBuilder().
+		SetGoroutinePool("application-config-test-negative", -1, 7).
+		Build()
+	assert.NotNil(t, application.Init())
+}
+
+func TestApplicationConfigNetwork(t *testing.T) {
+	defer common.SetNetworkOptions(common.NetworkOptions{})
+	application := NewApplicationConfigBuilder().
+		SetNetwork(&NetworkConfig{PreferredInterfaces: []string{"eth0"}, PreferredCIDRs: []string{"10.0.0.0/8"}}).
+		Build()
+	assert.Nil(t, application.Init())
+
+	application = NewApplicationConfigBuilder().
+		SetNetwork(&NetworkConfig{PreferredCIDRs: []string{"10.0.0.0"}}).
+		Build()
+	assert.NotNil(t, application.Init())
+}
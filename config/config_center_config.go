@@ -18,8 +18,14 @@
 package config
 
 import (
+	"context"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 )
 
 import (
@@ -62,13 +68,67 @@ type CenterConfig struct {
 	AppID     string            `default:"dubbo" yaml:"app-id"  json:"app-id,omitempty"`
 	Timeout   string            `default:"10s" yaml:"timeout"  json:"timeout,omitempty"`
 	Params    map[string]string `yaml:"params"  json:"parameters,omitempty"`
+	// Credential is the access key, the secret key and the tls of the connection besides Username and Password
+	Credential *CredentialConfig `yaml:"credential" json:"credential,omitempty"`
+	// TLSProfile is the tls profile of the connection taking the place of Credential.TLS, see RootConfig.TLSProfiles
+	TLSProfile string `yaml:"tls-profile" json:"tls-profile,omitempty"`
 
 	//FileExtension the suffix of config dataId, also the file extension of config content
 	FileExtension string `default:"yaml" yaml:"file-extension" json:"file-extension" `
+
+	// Check makes the startup fail if the config center is unreachable, otherwise the last config retrieved, which is
+	// saved in the snapshot file, is used
+	Check *bool `yaml:"check" json:"check,omitempty"`
+	// SnapshotDir is the directory of the snapshot files, ~/.dubbo/config-center/snapshot by default
+	SnapshotDir string `yaml:"snapshot-dir" json:"snapshot-dir,omitempty"`
+
+	// HighestPriority makes the config of the config center override the local config files, true by default
+	HighestPriority *bool `yaml:"highest-priority" json:"highest-priority,omitempty"`
+	// AppDataId is the data id of the config of the application, which overrides the global config of DataId.
+	// It is not retrieved if absent.
+	AppDataId string `yaml:"app-data-id" json:"app-data-id,omitempty"`
+	// AppGroup is the group of AppDataId, the application name by default
+	AppGroup string `yaml:"app-group" json:"app-group,omitempty"`
+
+	// FallbackGroups are the groups the configs missing in Group are served from in order, e.g. Group is dubbo-${ENV}
+	// for the configs of an environment and the fallback group is dubbo for the ones shared by the environments
+	FallbackGroups []string `yaml:"fallback-groups" json:"fallback-groups,omitempty"`
+
+	// FetchTimeout is the timeout of each attempt to fetch the configs at startup, Timeout by default
+	FetchTimeout string `yaml:"fetch-timeout" json:"fetch-timeout,omitempty"`
+	// FetchAttempts is the max attempts to fetch the configs at startup before the snapshot is used
+	FetchAttempts int `default:"3" yaml:"fetch-attempts" json:"fetch-attempts,omitempty"`
+	// FetchBackoff is the backoff before the next attempt, which doubles after each attempt. The configs are fetched
+	// with the same backoff in background after the snapshot is used, until the config center is reachable.
+	FetchBackoff string `default:"1s" yaml:"fetch-backoff" json:"fetch-backoff,omitempty"`
+
+	// reconnectLock guards reconnectCancel, which stops fetching the configs in background
+	reconnectLock   sync.Mutex
+	reconnectCancel context.CancelFunc
+}
+
+const (
+	defaultFetchTimeout = 10 * time.Second
+	defaultFetchBackoff = time.Second
+	// maxFetchBackoff is the max backoff of fetching the configs in background
+	maxFetchBackoff = 30 * time.Second
+	// unreachableWarnInterval is the min interval of the warnings of the unreachable config center
+	unreachableWarnInterval = time.Minute
+)
+
+// configFile is a config file retrieved from the config center
+type configFile struct {
+	// source is SourceConfigCenter or SourceAppConfigCenter
+	source string
+	group  string
+	dataId string
 }
 
+// snapshotNameReplacer matches the characters replaced in the snapshot file names
+var snapshotNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
 // Prefix dubbo.config-center
-func (CenterConfig) Prefix() string {
+func (*CenterConfig) Prefix() string {
 	return constant.ConfigCenterPrefix
 }
 
@@ -77,6 +137,9 @@ func (c *CenterConfig) check() error {
 		return err
 	}
 	c.translateConfigAddress()
+	if err := c.Credential.check(); err != nil {
+		return err
+	}
 	return verify(c)
 }
 
@@ -84,12 +147,22 @@ func (c *CenterConfig) Init(rc *RootConfig) error {
 	if c == nil {
 		return nil
 	}
+	c.resolveProfiles(rc)
 	if err := c.check(); err != nil {
 		return err
 	}
 	return startConfigCenter(rc)
 }
 
+// resolveProfiles resolves the placeholders of the groups by the environment variables and the active profile
+func (c *CenterConfig) resolveProfiles(rc *RootConfig) {
+	c.Group = resolveProfile(c.Group, rc.Profiles)
+	c.AppGroup = resolveProfile(c.AppGroup, rc.Profiles)
+	for i, group := range c.FallbackGroups {
+		c.FallbackGroups[i] = resolveProfile(group, rc.Profiles)
+	}
+}
+
 // GetUrlMap gets url map from ConfigCenterConfig
 func (c *CenterConfig) GetUrlMap() url.Values {
 	urlMap := url.Values{}
@@ -99,6 +172,8 @@ func (c *CenterConfig) GetUrlMap() url.Values {
 	urlMap.Set(constant.ConfigAppIDKey, c.AppID)
 	urlMap.Set(constant.ConfigTimeoutKey, c.Timeout)
 	urlMap.Set(constant.ClientNameKey, clientNameID(c, c.Protocol, c.Address))
+	c.Credential.setParams(urlMap, constant.ConfigAccessKey, constant.ConfigSecretKey)
+	setTLSProfileParams(urlMap, c.TLSProfile)
 
 	for key, val := range c.Params {
 		urlMap.Set(key, val)
@@ -134,37 +209,373 @@ func (c *CenterConfig) toURL() (*common.URL, error) {
 
 }
 
+// IsCheck returns whether the startup fails if the config center is unreachable
+func (c *CenterConfig) IsCheck() bool {
+	return c != nil && c.Check != nil && *c.Check
+}
+
+// IsHighestPriority returns whether the config of the config center overrides the local config files
+func (c *CenterConfig) IsHighestPriority() bool {
+	return c.HighestPriority == nil || *c.HighestPriority
+}
+
+// configFiles returns the config files retrieved from the config center, from the lowest priority to the highest
+func (c *CenterConfig) configFiles(rc *RootConfig) []configFile {
+	files := []configFile{{source: SourceConfigCenter, group: c.Group, dataId: c.DataId}}
+	if len(c.AppDataId) == 0 {
+		return files
+	}
+	group := c.AppGroup
+	if len(group) == 0 && rc.Application != nil {
+		group = rc.Application.Name
+	}
+	return append(files, configFile{source: SourceAppConfigCenter, group: group, dataId: c.AppDataId})
+}
+
+// fileOf returns the config file of @dataId, nil if it is not retrieved from the config center
+func (c *CenterConfig) fileOf(rc *RootConfig, dataId string) *configFile {
+	if c == nil {
+		return nil
+	}
+	files := c.configFiles(rc)
+	for i := range files {
+		if files[i].dataId == dataId {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
 // startConfigCenter will start the config center.
-// it will prepare the environment
+// it will prepare the environment. The configs retrieved are merged into @rc before the other configs initialize,
+// and they are saved in the snapshot files, which are used instead if the config center is unreachable on the next
+// startup.
 func startConfigCenter(rc *RootConfig) error {
 	cc := rc.ConfigCenter
-	dynamicConfig, err := cc.GetDynamicConfiguration()
+	files := cc.configFiles(rc)
+	dynamicConfig, contents, err := cc.fetchWithRetry(files)
 	if err != nil {
-		logger.Errorf("[Config Center] Start dynamic configuration center error, error message is %v", err)
+		setConfigCenterHealthy(false)
+		err = cc.startFromSnapshot(rc, err)
+		if !cc.IsCheck() && !errors.Is(err, remoting.ErrAuthFailed) {
+			// the snapshot or the local config is used, switch to the live configs once the config center is reachable
+			if rc.sources == nil {
+				rc.sources = newConfigSources(nil)
+			}
+			rc.sources.setExternalFirst(cc.IsHighestPriority())
+			ctx, cancel := context.WithCancel(context.Background())
+			cc.reconnectLock.Lock()
+			cc.reconnectCancel = cancel
+			cc.reconnectLock.Unlock()
+			go cc.reconnect(ctx, rc, files)
+		}
 		return err
 	}
-
-	strConf, err := dynamicConfig.GetProperties(cc.DataId, config_center.WithGroup(cc.Group))
-	if err != nil {
-		logger.Warnf("[Config Center] Dynamic config center has started, but config may not be initialized, because: %s", err)
-		return nil
+	setConfigCenterHealthy(true)
+	for _, file := range files {
+		metrics.Publish(metricsConfigCenter.NewIncMetricEvent(file.dataId, file.group, remoting.EventTypeAdd, cc.Protocol))
 	}
-	defer metrics.Publish(metricsConfigCenter.NewIncMetricEvent(cc.DataId, cc.Group, remoting.EventTypeAdd, cc.Protocol))
-	if len(strConf) == 0 {
+	if strings.Join(contents, "") == "" {
 		logger.Warnf("[Config Center] Dynamic config center has started, but got empty config with config-center configuration %+v\n"+
 			"Please check if your config-center config is correct.", cc)
 		return nil
 	}
-	config := NewLoaderConf(WithDelim("."), WithGenre(cc.FileExtension), WithBytes([]byte(strConf)))
-	koan := GetConfigResolver(config)
-	if err = koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}); err != nil {
+	if err = cc.apply(rc, files, contents); err != nil {
 		return err
 	}
-
-	dynamicConfig.AddListener(cc.DataId, rc, config_center.WithGroup(cc.Group))
+	for i, file := range files {
+		cc.saveSnapshot(file, contents[i])
+	}
+	cc.addListeners(rc, dynamicConfig, files)
 	return nil
 }
 
+// addListeners listens to the changes of the config @files
+func (c *CenterConfig) addListeners(rc *RootConfig, dynamicConfig config_center.DynamicConfiguration, files []configFile) {
+	for _, file := range files {
+		listener := &configFileListener{rc: rc, file: file}
+		dynamicConfig.AddListener(file.dataId, config_center.NewCoalescingListener(listener, config_center.DefaultCoalescingWindow),
+			config_center.WithGroup(file.group))
+	}
+}
+
+// fetchWithRetry fetches the config @files at most FetchAttempts times with the backoff, it fails at once if the
+// config center rejects the credentials
+func (c *CenterConfig) fetchWithRetry(files []configFile) (config_center.DynamicConfiguration, []string, error) {
+	attempts := c.FetchAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := c.GetFetchBackoff()
+	for i := 1; ; i++ {
+		dynamicConfig, contents, err := c.fetch(context.Background(), files)
+		if err == nil || i >= attempts || errors.Is(err, remoting.ErrAuthFailed) {
+			return dynamicConfig, contents, err
+		}
+		logger.Warnf("[Config Center] Fetch the configs from the config center %s://%s failed, retry %d/%d after %s, cause: %v",
+			c.Protocol, c.Address, i, attempts-1, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// fetch fetches the config @files from the config center, it fails if they are not fetched in FetchTimeout or @ctx
+// is done. The client of the config center created after that is destroyed instead of being used.
+func (c *CenterConfig) fetch(ctx context.Context, files []configFile) (config_center.DynamicConfiguration, []string, error) {
+	type result struct {
+		dynamicConfig config_center.DynamicConfiguration
+		// created is true if dynamicConfig is created by this fetch
+		created  bool
+		contents []string
+		err      error
+	}
+	timeout := c.GetFetchTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// the result is unbuffered so that the fetch stuck in the config center knows it is abandoned
+	done := make(chan result)
+	go func() {
+		var r result
+		if r.dynamicConfig = conf.GetEnvInstance().GetDynamicConfiguration(); r.dynamicConfig == nil {
+			if r.dynamicConfig, r.err = c.CreateDynamicConfiguration(); r.err != nil {
+				r.err = errors.WithStack(r.err)
+			}
+			r.created = r.err == nil
+		}
+		if r.err == nil {
+			r.contents = make([]string, len(files))
+			for i, file := range files {
+				if r.contents[i], r.err = r.dynamicConfig.GetProperties(file.dataId, config_center.WithGroup(file.group)); r.err != nil {
+					r.err = errors.WithMessagef(r.err, "get the config %s", file.dataId)
+					break
+				}
+			}
+		}
+		select {
+		case done <- r:
+		case <-ctx.Done():
+			if r.created {
+				destroyDynamicConfiguration(r.dynamicConfig)
+			}
+		}
+	}()
+	select {
+	case r := <-done:
+		if r.created {
+			// the client is kept even if the configs are not fetched, it's reused by the next fetch
+			var installed bool
+			if r.dynamicConfig, installed = conf.GetEnvInstance().SetDynamicConfigurationIfAbsent(r.dynamicConfig); !installed {
+				destroyDynamicConfiguration(r.dynamicConfig)
+			}
+		}
+		if r.err != nil {
+			return nil, nil, r.err
+		}
+		return r.dynamicConfig, r.contents, nil
+	case <-ctx.Done():
+		return nil, nil, errors.Errorf("fetch the configs timeout after %s", timeout)
+	}
+}
+
+// reconnect fetches the config @files in background until the config center is reachable, then the live configs
+// replace the snapshot and the changes are listened to. It stops once @ctx is done.
+func (c *CenterConfig) reconnect(ctx context.Context, rc *RootConfig, files []configFile) {
+	backoff := c.GetFetchBackoff()
+	var lastWarn time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		dynamicConfig, contents, err := c.fetch(ctx, files)
+		if err != nil {
+			if time.Since(lastWarn) >= unreachableWarnInterval {
+				logger.Warnf("[Config Center] The config center %s://%s is still unreachable, the configs may be stale, cause: %v",
+					c.Protocol, c.Address, err)
+				lastWarn = time.Now()
+			}
+			if backoff *= 2; backoff > maxFetchBackoff {
+				backoff = maxFetchBackoff
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		logger.Infof("[Config Center] The config center %s://%s is reachable, switch to the live configs", c.Protocol, c.Address)
+		for i := range files {
+			rc.process(&files[i], &config_center.ConfigChangeEvent{Key: files[i].dataId, Value: contents[i],
+				ConfigType: remoting.EventTypeUpdate})
+		}
+		c.addListeners(rc, dynamicConfig, files)
+		setConfigCenterHealthy(true)
+		return
+	}
+}
+
+// stopReconnect stops fetching the configs in background
+func (c *CenterConfig) stopReconnect() {
+	c.reconnectLock.Lock()
+	defer c.reconnectLock.Unlock()
+	if c.reconnectCancel != nil {
+		c.reconnectCancel()
+		c.reconnectCancel = nil
+	}
+}
+
+// destroyDynamicConfiguration destroys the client of the config center @dc if it can be destroyed
+func destroyDynamicConfiguration(dc config_center.DynamicConfiguration) {
+	if destroyable, ok := dc.(interface{ Destroy() }); ok {
+		destroyable.Destroy()
+	}
+}
+
+// GetFetchTimeout returns FetchTimeout, or Timeout if it is absent
+func (c *CenterConfig) GetFetchTimeout() time.Duration {
+	timeout := c.FetchTimeout
+	if len(timeout) == 0 {
+		timeout = c.Timeout
+	}
+	result, err := common.ParseDuration(timeout)
+	if err != nil || result <= 0 {
+		logger.Errorf("The FetchTimeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
+			timeout, defaultFetchTimeout.String(), err)
+		return defaultFetchTimeout
+	}
+	return result
+}
+
+// GetFetchBackoff returns FetchBackoff
+func (c *CenterConfig) GetFetchBackoff() time.Duration {
+	result, err := common.ParseDuration(c.FetchBackoff)
+	if err != nil || result <= 0 {
+		logger.Errorf("The FetchBackoff configuration is invalid: %s, and we will use the default value: %s, err: %v",
+			c.FetchBackoff, defaultFetchBackoff.String(), err)
+		return defaultFetchBackoff
+	}
+	return result
+}
+
+// configFileListener processes the changes of a config file, the global one and the one of the application may
+// share the data id in different groups
+type configFileListener struct {
+	rc   *RootConfig
+	file configFile
+}
+
+func (l *configFileListener) Process(event *config_center.ConfigChangeEvent) {
+	l.rc.process(&l.file, event)
+}
+
+// startFromSnapshot applies the snapshots if the config center is unreachable for @cause, it fails if check is set
+// or the config center rejects the credentials
+func (c *CenterConfig) startFromSnapshot(rc *RootConfig, cause error) error {
+	if errors.Is(cause, remoting.ErrAuthFailed) {
+		return errors.WithMessagef(cause, "the config center %s://%s rejected the credentials", c.Protocol, c.Address)
+	}
+	if c.IsCheck() {
+		return errors.WithMessagef(cause, "the config center %s://%s is unreachable", c.Protocol, c.Address)
+	}
+	files := c.configFiles(rc)
+	contents := make([]string, len(files))
+	for i, file := range files {
+		path := c.snapshotPath(file)
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warnf("[Config Center] No snapshot %s of the config center is available, start with the local config only", path)
+			return cause
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			logger.Errorf("[Config Center] Read the snapshot %s of the config center error, %v", path, err)
+			return cause
+		}
+		logger.Warnf("[Config Center] !!! The config center %s://%s is unreachable, START WITH THE SNAPSHOT %s SAVED AT %s, "+
+			"WHICH MAY BE STALE !!! cause: %v", c.Protocol, c.Address, path, info.ModTime().Format(time.RFC3339), cause)
+		contents[i] = string(content)
+	}
+	return c.apply(rc, files, contents)
+}
+
+// apply merges the @contents of the config @files into @rc by the precedence, a malformed config is not applied
+// partially
+func (c *CenterConfig) apply(rc *RootConfig, files []configFile, contents []string) error {
+	if rc.sources == nil {
+		rc.sources = newConfigSources(nil)
+	}
+	values := make([]map[string]interface{}, len(files))
+	for i, content := range contents {
+		var err error
+		if values[i], err = parseConfig(c.FileExtension, content); err != nil {
+			return errors.WithMessagef(err, "the config %s of the config center", files[i].dataId)
+		}
+	}
+	for i, file := range files {
+		rc.sources.set(file.source, values[i])
+	}
+	rc.sources.setExternalFirst(c.IsHighestPriority())
+	koan, err := rc.sources.merged()
+	if err == nil {
+		err = koan.UnmarshalWithConf(rc.Prefix(), &RootConfig{}, koanf.UnmarshalConf{Tag: "yaml"})
+	}
+	if err != nil {
+		for _, file := range files {
+			rc.sources.set(file.source, nil)
+		}
+		return err
+	}
+	return koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"})
+}
+
+// saveSnapshot saves the @content of the config @file retrieved from the config center into the snapshot file
+func (c *CenterConfig) saveSnapshot(file configFile, content string) {
+	path := c.snapshotPath(file)
+	if err := writeSnapshot(path, content); err != nil {
+		logger.Warnf("[Config Center] Save the snapshot %s error, %v", path, err)
+	}
+}
+
+// writeSnapshot writes @content into the snapshot file @path through a temp file renamed, so that the snapshot is
+// never half written
+func writeSnapshot(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// snapshotPath returns the path of the snapshot file of the config @file, which is identified by the config center,
+// the namespace, the group and the data id
+func (c *CenterConfig) snapshotPath(file configFile) string {
+	dir := c.SnapshotDir
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		dir = filepath.Join(home, ".dubbo", "config-center", "snapshot")
+	}
+	name := strings.Join([]string{c.Protocol, c.Address, c.Namespace, file.group, file.dataId}, "_")
+	return filepath.Join(dir, snapshotNameReplacer.ReplaceAllString(name, "-")+".snapshot")
+}
+
 func (c *CenterConfig) CreateDynamicConfiguration() (config_center.DynamicConfiguration, error) {
 	configCenterUrl, err := c.toURL()
 	if err != nil {
@@ -174,20 +585,28 @@ func (c *CenterConfig) CreateDynamicConfiguration() (config_center.DynamicConfig
 	if err != nil {
 		return nil, err
 	}
-	return factory.GetDynamicConfiguration(configCenterUrl)
+	dynamicConfig, err := factory.GetDynamicConfiguration(configCenterUrl)
+	if err != nil || len(c.FallbackGroups) == 0 {
+		return dynamicConfig, err
+	}
+	return config_center.NewFallbackDynamicConfiguration(dynamicConfig, c.Group, c.FallbackGroups), nil
 }
 
 func (c *CenterConfig) GetDynamicConfiguration() (config_center.DynamicConfiguration, error) {
 	envInstance := conf.GetEnvInstance()
-	if envInstance.GetDynamicConfiguration() != nil {
-		return envInstance.GetDynamicConfiguration(), nil
+	if dynamicConfig := envInstance.GetDynamicConfiguration(); dynamicConfig != nil {
+		return dynamicConfig, nil
 	}
 	dynamicConfig, err := c.CreateDynamicConfiguration()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	envInstance.SetDynamicConfiguration(dynamicConfig)
-	return dynamicConfig, nil
+	// the client created concurrently is used if any
+	actual, installed := envInstance.SetDynamicConfigurationIfAbsent(dynamicConfig)
+	if !installed {
+		destroyDynamicConfiguration(dynamicConfig)
+	}
+	return actual, nil
 }
 
 func NewConfigCenterConfigBuilder() *ConfigCenterConfigBuilder {
@@ -233,6 +652,21 @@ func (ccb *ConfigCenterConfigBuilder) SetGroup(group string) *ConfigCenterConfig
 	return ccb
 }
 
+func (ccb *ConfigCenterConfigBuilder) SetHighestPriority(highestPriority bool) *ConfigCenterConfigBuilder {
+	ccb.configCenterConfig.HighestPriority = &highestPriority
+	return ccb
+}
+
+func (ccb *ConfigCenterConfigBuilder) SetAppDataID(appDataID string) *ConfigCenterConfigBuilder {
+	ccb.configCenterConfig.AppDataId = appDataID
+	return ccb
+}
+
+func (ccb *ConfigCenterConfigBuilder) SetTLSProfile(tlsProfile string) *ConfigCenterConfigBuilder {
+	ccb.configCenterConfig.TLSProfile = tlsProfile
+	return ccb
+}
+
 func (ccb *ConfigCenterConfigBuilder) Build() *CenterConfig {
 	return ccb.configCenterConfig
 }
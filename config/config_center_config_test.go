@@ -0,0 +1,247 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// snapshotTestServer is the config center of the snapshot tests, it is unreachable if content is empty
+var snapshotTestServer = &snapshotTestConfiguration{}
+
+func init() {
+	extension.SetConfigCenterFactory("snapshot-test", func() config_center.DynamicConfigurationFactory {
+		return snapshotTestServer
+	})
+}
+
+type snapshotTestConfiguration struct {
+	config_center.DynamicConfiguration
+	// lock guards the fields below, which are changed by the tests while the configs are fetched in background
+	lock    sync.Mutex
+	content string
+	err     error
+	// hanging blocks GetProperties until it is closed
+	hanging chan struct{}
+}
+
+func (c *snapshotTestConfiguration) set(content string, err error, hanging chan struct{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.content, c.err, c.hanging = content, err, hanging
+}
+
+func (c *snapshotTestConfiguration) GetDynamicConfiguration(_ *common.URL) (config_center.DynamicConfiguration, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	if len(c.content) == 0 {
+		return nil, perrors.New("dial tcp 127.0.0.1:8848: connect: connection refused")
+	}
+	return c, nil
+}
+
+func (c *snapshotTestConfiguration) GetProperties(string, ...config_center.Option) (string, error) {
+	c.lock.Lock()
+	hanging := c.hanging
+	c.lock.Unlock()
+	if hanging != nil {
+		<-hanging
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.content, nil
+}
+
+func (c *snapshotTestConfiguration) AddListener(string, config_center.ConfigurationListener, ...config_center.Option) {
+}
+
+// startSnapshotTest starts the config center with the snapshots in @dir
+func startSnapshotTest(t *testing.T, dir string, check bool) (*RootConfig, error) {
+	conf.GetEnvInstance().SetDynamicConfiguration(nil)
+	t.Cleanup(func() {
+		conf.GetEnvInstance().SetDynamicConfiguration(nil)
+	})
+	rc := NewRootConfigBuilder().SetConfigCenter(&CenterConfig{Protocol: "snapshot-test", Address: "127.0.0.1:8848",
+		DataId: "user-center", Check: &check, SnapshotDir: dir, FetchBackoff: "10ms"}).Build()
+	t.Cleanup(rc.ConfigCenter.stopReconnect)
+	return rc, rc.ConfigCenter.Init(rc)
+}
+
+func TestConfigCenterColdStartWithServerDown(t *testing.T) {
+	dir := t.TempDir()
+	snapshotTestServer.set("", nil, nil)
+
+	// no snapshot
+	rc, err := startSnapshotTest(t, dir, false)
+	assert.Error(t, err)
+	assert.Empty(t, rc.Application.Name)
+
+	cc := &CenterConfig{Protocol: "snapshot-test", Address: "127.0.0.1:8848", DataId: "user-center", SnapshotDir: dir}
+	assert.NoError(t, os.WriteFile(cc.snapshotPath(configFile{dataId: "user-center"}), []byte("dubbo:\n  application:\n    name: user-center\n"), 0644))
+	rc, err = startSnapshotTest(t, dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-center", rc.Application.Name)
+
+	// fail fast
+	rc, err = startSnapshotTest(t, dir, true)
+	assert.Error(t, err)
+	assert.Empty(t, rc.Application.Name)
+}
+
+func TestConfigCenterSnapshotRefresh(t *testing.T) {
+	dir := t.TempDir()
+	snapshotTestServer.set("dubbo:\n  application:\n    name: user-center\n", nil, nil)
+
+	rc, err := startSnapshotTest(t, dir, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-center", rc.Application.Name)
+	content, err := os.ReadFile(rc.ConfigCenter.snapshotPath(rc.ConfigCenter.configFiles(rc)[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo:\n  application:\n    name: user-center\n", string(content))
+
+	// the snapshot is refreshed by the changes
+	rc.Process(&config_center.ConfigChangeEvent{Key: "user-center", ConfigType: remoting.EventTypeUpdate,
+		Value: "dubbo:\n  application:\n    name: user-center-v2\n"})
+	snapshotTestServer.set("", nil, nil)
+	rc, err = startSnapshotTest(t, dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-center-v2", rc.Application.Name)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestConfigCenterMalformedConfig(t *testing.T) {
+	snapshotTestServer.set("dubbo:\n  application:\n    name: user-center\n  protocols: [dubbo\n", nil, nil)
+
+	rc, err := startSnapshotTest(t, t.TempDir(), false)
+	assert.Error(t, err)
+	assert.Empty(t, rc.Application.Name)
+	_, err = os.Stat(rc.ConfigCenter.snapshotPath(rc.ConfigCenter.configFiles(rc)[0]))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestConfigCenterAuthFailed(t *testing.T) {
+	dir := t.TempDir()
+	snapshotTestServer.set("", perrors.WithMessage(remoting.ErrAuthFailed, "login nacos as nacos: 403 Forbidden"), nil)
+	defer snapshotTestServer.set("", nil, nil)
+
+	// the snapshot is not used for the rejected credentials
+	cc := &CenterConfig{Protocol: "snapshot-test", Address: "127.0.0.1:8848", DataId: "user-center", SnapshotDir: dir}
+	assert.NoError(t, os.WriteFile(cc.snapshotPath(configFile{dataId: "user-center"}), []byte("dubbo:\n  application:\n    name: user-center\n"), 0644))
+	rc, err := startSnapshotTest(t, dir, false)
+	assert.True(t, perrors.Is(err, remoting.ErrAuthFailed))
+	assert.Contains(t, err.Error(), "snapshot-test://127.0.0.1:8848")
+	assert.Empty(t, rc.Application.Name)
+}
+
+func TestConfigCenterCredential(t *testing.T) {
+	cc := &CenterConfig{Protocol: "nacos", Address: "127.0.0.1:8848", Username: "nacos", Password: "nacos",
+		Credential: &CredentialConfig{AccessKey: "ak", SecretKey: "sk", TLS: &TLSConfig{CACertFile: "ca.pem",
+			TLSCertFile: "client.pem", TLSKeyFile: "client.key", TLSServerName: "nacos.local"}}}
+	assert.NoError(t, cc.check())
+	url, err := cc.toURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "nacos", url.Username)
+	assert.Equal(t, "nacos", url.Password)
+	assert.Equal(t, "ak", url.GetParam(constant.ConfigAccessKey, ""))
+	assert.Equal(t, "sk", url.GetParam(constant.ConfigSecretKey, ""))
+	assert.Equal(t, "ca.pem", url.GetParam(constant.CACert, ""))
+	assert.Equal(t, "client.pem", url.GetParam(constant.TLSCert, ""))
+	assert.Equal(t, "client.key", url.GetParam(constant.TLSKey, ""))
+	assert.Equal(t, "nacos.local", url.GetParam(constant.TLSServerNAME, ""))
+	assert.False(t, url.GetParamBool(constant.TLSInsecureSkipVerify, true))
+
+	// the server certificate is verified unless it is skipped explicitly
+	cc.Credential.TLS = &TLSConfig{}
+	assert.Error(t, cc.check())
+	cc.Credential.TLS.InsecureSkipVerify = true
+	assert.NoError(t, cc.check())
+	cc.Credential.TLS.TLSCertFile = "client.pem"
+	assert.Error(t, cc.check())
+}
+
+func TestConfigCenterHangingAtStartup(t *testing.T) {
+	dir := t.TempDir()
+	conf.GetEnvInstance().SetDynamicConfiguration(nil)
+	defer conf.GetEnvInstance().SetDynamicConfiguration(nil)
+	const content = "dubbo:\n  application:\n    name: user-center\n  consumer:\n    request-timeout: 5s\n"
+	hanging := make(chan struct{})
+	snapshotTestServer.set(content, nil, hanging)
+	defer snapshotTestServer.set("", nil, nil)
+	cc := &CenterConfig{Protocol: "snapshot-test", Address: "127.0.0.1:8848", DataId: "user-center", SnapshotDir: dir,
+		FetchTimeout: "100ms", FetchAttempts: 3, FetchBackoff: "50ms"}
+	assert.NoError(t, os.WriteFile(cc.snapshotPath(configFile{dataId: "user-center"}), []byte("dubbo:\n  application:\n    name: user-center\n"), 0644))
+	rc := NewRootConfigBuilder().SetConfigCenter(cc).Build()
+	defer cc.stopReconnect()
+
+	// 3 attempts of 100ms and the backoffs of 50ms and 100ms
+	start := time.Now()
+	assert.NoError(t, cc.Init(rc))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, "user-center", rc.Application.Name)
+	assert.False(t, IsConfigCenterHealthy())
+
+	// the live configs replace the snapshot once the config center responds
+	var healthy []bool
+	AddConfigCenterHealthListener(func(h bool) {
+		healthy = append(healthy, h)
+	})
+	close(hanging)
+	assert.Eventually(t, IsConfigCenterHealthy, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []bool{false, true}, healthy)
+	assert.Equal(t, "5s", rc.Consumer.RequestTimeout)
+	snapshot, err := os.ReadFile(cc.snapshotPath(configFile{dataId: "user-center"}))
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(snapshot))
+}
+
+func TestConfigCenterReconnectWithoutSnapshot(t *testing.T) {
+	snapshotTestServer.set("", nil, nil)
+
+	rc, err := startSnapshotTest(t, t.TempDir(), false)
+	assert.Error(t, err)
+	assert.Empty(t, rc.Application.Name)
+
+	// the local config is used until the config center is reachable
+	snapshotTestServer.set("dubbo:\n  consumer:\n    request-timeout: 5s\n", nil, nil)
+	assert.Eventually(t, IsConfigCenterHealthy, 3*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "5s", rc.Consumer.RequestTimeout)
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"sync"
+)
+
+// configCenterHealth is the connection health of the config center, which is false if the config center is
+// unreachable at startup and not reconnected yet
+var configCenterHealth = struct {
+	sync.Mutex
+	healthy   bool
+	listeners []func(healthy bool)
+}{healthy: true}
+
+// IsConfigCenterHealthy returns whether the configs are retrieved from the config center rather than the snapshot
+func IsConfigCenterHealthy() bool {
+	configCenterHealth.Lock()
+	defer configCenterHealth.Unlock()
+	return configCenterHealth.healthy
+}
+
+// AddConfigCenterHealthListener adds the @listener of the connection health of the config center, which is notified
+// of the current health at once, e.g. the health check service reports it
+func AddConfigCenterHealthListener(listener func(healthy bool)) {
+	configCenterHealth.Lock()
+	defer configCenterHealth.Unlock()
+	configCenterHealth.listeners = append(configCenterHealth.listeners, listener)
+	listener(configCenterHealth.healthy)
+}
+
+func setConfigCenterHealthy(healthy bool) {
+	configCenterHealth.Lock()
+	defer configCenterHealth.Unlock()
+	if configCenterHealth.healthy == healthy {
+		return
+	}
+	configCenterHealth.healthy = healthy
+	for _, listener := range configCenterHealth.listeners {
+		listener(healthy)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 import (
@@ -51,23 +52,65 @@ func init() {
 func Load(opts ...LoaderConfOption) error {
 	// conf
 	conf := NewLoaderConf(opts...)
+	// the logs of loading the config files go to the logger of AppLogConfFile
+	if err := initEnvLog(); err != nil {
+		return err
+	}
 	if conf.rc == nil {
-		koan := GetConfigResolver(conf)
-		koan = conf.MergeConfig(koan)
-		if err := koan.UnmarshalWithConf(rootConfig.Prefix(),
-			rootConfig, koanf.UnmarshalConf{Tag: "yaml"}); err != nil {
+		koan, files, err := loadConfigFiles(conf, rootConfig)
+		if err != nil {
 			return err
 		}
+		rootConfig.sources = newConfigSources(koan)
+		rootConfig.sources.setFiles(files)
 	} else {
 		rootConfig = conf.rc
 	}
 
+	if err := rootConfig.validate(); err != nil {
+		return err
+	}
 	if err := rootConfig.Init(); err != nil {
 		return err
 	}
+	// the remote config of the previous load is no longer refreshed
+	setRefreshing(nil)
+	if conf.remote != nil {
+		conf.remote.refresh(rootConfig, conf)
+	}
 	return nil
 }
 
+// loadConfigFiles unmarshals the config files of @conf into @rc, and returns the merged config with the files of the
+// values keyed by the flattened keys. The config files of the same name in the other formats are merged by the
+// precedence of their formats, then the config files of the active profiles in order, see configTree for the merge.
+// The deprecated keys of the config files are migrated to their replacements, see configMigrations.
+func loadConfigFiles(conf *loaderConf, rc *RootConfig) (*koanf.Koanf, map[string]string, error) {
+	tree := newConfigTree(conf.delim)
+	if err := conf.mergeFormats(tree); err != nil {
+		return nil, nil, err
+	}
+	profiles, err := conf.mergeProfiles(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	koan, err := tree.koanf()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}); err != nil {
+		return nil, nil, err
+	}
+	if err := rc.checkDeprecations(tree.deprecations); err != nil {
+		return nil, nil, err
+	}
+	if rc.Profiles == nil {
+		rc.Profiles = &ProfilesConfig{}
+	}
+	rc.Profiles.Active = strings.Join(profiles, ",")
+	return koan, tree.files, nil
+}
+
 func check() error {
 	if rootConfig == nil {
 		return errors.New("execute the config.Load() method first")
@@ -81,10 +124,6 @@ func registerServiceInstance() {
 	if url == nil {
 		return
 	}
-	instance, err := createInstance(url)
-	if err != nil {
-		panic(err)
-	}
 	p := extension.GetProtocol(constant.RegistryProtocol)
 	var rp registry.RegistryFactory
 	var ok bool
@@ -97,9 +136,18 @@ func registerServiceInstance() {
 		if sdr, ok = r.(registry.ServiceDiscoveryHolder); !ok {
 			continue
 		}
+		// each registry has its own instance, which is updated by the registry at runtime
+		instance, err := createInstance(url)
+		if err != nil {
+			panic(err)
+		}
 		// publish app level data to registry
 		logger.Infof("Starting register instance address %v", instance)
-		err := sdr.GetServiceDiscovery().Register(instance)
+		if ir, ok := r.(registry.InstanceRegisterer); ok {
+			err = ir.RegisterInstance(instance)
+		} else {
+			err = sdr.GetServiceDiscovery().Register(instance)
+		}
 		if err != nil {
 			panic(err)
 		}
@@ -115,14 +163,11 @@ func registerServiceInstance() {
 // // nolint
 func createInstance(url *common.URL) (registry.ServiceInstance, error) {
 	appConfig := GetApplicationConfig()
-	port, err := strconv.ParseInt(url.Port, 10, 32)
+	// the instance is advertised at the same address as the services, e.g. DUBBO_IP_TO_REGISTRY
+	host, portToRegistry := common.GetRegistryIP(url), common.GetRegistryPort(url)
+	port, err := strconv.ParseInt(portToRegistry, 10, 32)
 	if err != nil {
-		return nil, perrors.WithMessage(err, "invalid port: "+url.Port)
-	}
-
-	host := url.Ip
-	if len(host) == 0 {
-		host = common.GetLocalIp()
+		return nil, perrors.WithMessage(err, "invalid port: "+portToRegistry)
 	}
 
 	// usually we will add more metadata
@@ -133,7 +178,7 @@ func createInstance(url *common.URL) (registry.ServiceInstance, error) {
 		ServiceName: appConfig.Name,
 		Host:        host,
 		Port:        int(port),
-		ID:          host + constant.KeySeparator + url.Port,
+		ID:          host + constant.KeySeparator + portToRegistry,
 		Enable:      true,
 		Healthy:     true,
 		Metadata:    metadata,
@@ -30,6 +30,8 @@ import (
 
 	"github.com/knadh/koanf"
 
+	"github.com/magiconair/properties"
+
 	"github.com/pkg/errors"
 )
 
@@ -38,6 +40,10 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant/file"
 )
 
+// configFileSuffixes are the formats of the config files of the same name merged, from the lowest precedence to the
+// highest, e.g. the address of a registry in dubbogo.yaml overrides the one in dubbogo.properties
+var configFileSuffixes = []file.Suffix{file.PROPERTIES, file.TOML, file.JSON, file.YML, file.YAML}
+
 type loaderConf struct {
 	suffix string      // loaderConf file extension default yaml
 	path   string      // loaderConf file path default ./conf/dubbogo.yaml
@@ -45,6 +51,13 @@ type loaderConf struct {
 	bytes  []byte      // config bytes
 	rc     *RootConfig // user provide rootConfig built by config api
 	name   string      // config file name
+	file   string      // config file the bytes are read from
+	// remote is the config document the bytes are fetched from if the path is a http(s) url
+	remote *remoteConfig
+	// activeProfiles are the active profiles set by WithActiveProfiles, separated by the commas
+	activeProfiles string
+	// placeholders are the properties the placeholders in the config are resolved from besides the environment
+	placeholders map[string]string
 }
 
 func NewLoaderConf(opts ...LoaderConfOption) *loaderConf {
@@ -59,6 +72,9 @@ func NewLoaderConf(opts ...LoaderConfOption) *loaderConf {
 		delim:  ".",
 		name:   name,
 	}
+	if placeholderPath := os.Getenv(constant.PlaceholderFileEnvKey); placeholderPath != "" {
+		WithPlaceholderPath(placeholderPath).apply(conf)
+	}
 	for _, opt := range opts {
 		opt.apply(conf)
 	}
@@ -66,13 +82,30 @@ func NewLoaderConf(opts ...LoaderConfOption) *loaderConf {
 		return conf
 	}
 	if len(conf.bytes) <= 0 {
-		if bytes, err := ioutil.ReadFile(conf.path); err != nil {
+		conf.read()
+	}
+	return conf
+}
+
+// read reads the config bytes from the file or the http(s) url of the path
+func (conf *loaderConf) read() {
+	if isRemotePath(conf.path) {
+		conf.remote = newRemoteConfig(conf.path)
+		bytes, err := conf.remote.load()
+		if err != nil {
 			panic(err)
-		} else {
-			conf.bytes = bytes
 		}
+		conf.bytes = bytes
+		conf.file = ""
+		return
 	}
-	return conf
+	bytes, err := ioutil.ReadFile(conf.path)
+	if err != nil {
+		panic(err)
+	}
+	conf.bytes = bytes
+	conf.file = conf.path
+	conf.remote = nil
 }
 
 type LoaderConfOption interface {
@@ -104,27 +137,108 @@ func WithSuffix(suffix file.Suffix) LoaderConfOption {
 	})
 }
 
-// WithPath set load config path
+// WithPath set load config path, which is a file or a http(s) url
 func WithPath(path string) LoaderConfOption {
 	return loaderConfigFunc(func(conf *loaderConf) {
 		conf.path = absolutePath(path)
-		if bytes, err := ioutil.ReadFile(conf.path); err != nil {
-			panic(err)
-		} else {
-			conf.bytes = bytes
-		}
+		conf.read()
 		name, suffix := resolverFilePath(path)
 		conf.suffix = suffix
 		conf.name = name
 	})
 }
 
+// WithPlaceholderPath set the properties file the placeholders in the config are resolved from if the environment
+// variables are absent
+func WithPlaceholderPath(path string) LoaderConfOption {
+	return loaderConfigFunc(func(conf *loaderConf) {
+		bytes, err := ioutil.ReadFile(absolutePath(path))
+		if err != nil {
+			panic(err)
+		}
+		props, err := properties.Load(bytes, properties.UTF8)
+		if err != nil {
+			panic(errors.WithMessagef(err, "load the placeholder properties %s", path))
+		}
+		conf.placeholders = props.Map()
+	})
+}
+
+// WithActiveProfiles activates the @profiles in order, which take precedence over the env DUBBO_GO_PROFILES_ACTIVE and
+// dubbo.profiles.active of the config files
+func WithActiveProfiles(profiles ...string) LoaderConfOption {
+	return loaderConfigFunc(func(conf *loaderConf) {
+		conf.activeProfiles = strings.Join(profiles, ",")
+	})
+}
+
 func WithRootConfig(rc *RootConfig) LoaderConfOption {
 	return loaderConfigFunc(func(conf *loaderConf) {
 		conf.rc = rc
 	})
 }
 
+// WithConsumerConfig loads @cc and the application and the registries set by its builder without config files,
+// they are initialized the same as the ones loaded from the config files
+func WithConsumerConfig(cc *ConsumerConfig) LoaderConfOption {
+	return loaderConfigFunc(func(conf *loaderConf) {
+		rc := conf.builtRootConfig()
+		rc.Consumer = cc
+		cc.builder.addTo(rc)
+	})
+}
+
+// WithProviderConfig loads @pc and the application, the registries and the protocols set by its builder
+// without config files, they are initialized the same as the ones loaded from the config files
+func WithProviderConfig(pc *ProviderConfig) LoaderConfOption {
+	return loaderConfigFunc(func(conf *loaderConf) {
+		rc := conf.builtRootConfig()
+		rc.Provider = pc
+		pc.builder.addTo(rc)
+	})
+}
+
+// builtRootConfig returns the root config built by config api, an empty one is created if there is none
+func (conf *loaderConf) builtRootConfig() *RootConfig {
+	if conf.rc == nil {
+		conf.rc = NewRootConfigBuilder().Build()
+	}
+	return conf.rc
+}
+
+// builderConfigs are the root level configs set by the builders of the consumer config and the provider config
+type builderConfigs struct {
+	application *ApplicationConfig
+	registries  map[string]*RegistryConfig
+	protocols   map[string]*ProtocolConfig
+}
+
+func (b *builderConfigs) addRegistry(registryID string, registryConfig *RegistryConfig) {
+	if b.registries == nil {
+		b.registries = make(map[string]*RegistryConfig)
+	}
+	b.registries[registryID] = registryConfig
+}
+
+// addTo adds the configs to @rc, the application replaces the one of @rc if it's set
+func (b *builderConfigs) addTo(rc *RootConfig) {
+	if b.application != nil {
+		rc.Application = b.application
+	}
+	if rc.Registries == nil {
+		rc.Registries = make(map[string]*RegistryConfig, len(b.registries))
+	}
+	for id, registry := range b.registries {
+		rc.Registries[id] = registry
+	}
+	if rc.Protocols == nil {
+		rc.Protocols = make(map[string]*ProtocolConfig, len(b.protocols))
+	}
+	for id, protocol := range b.protocols {
+		rc.Protocols[id] = protocol
+	}
+}
+
 func WithDelim(delim string) LoaderConfOption {
 	return loaderConfigFunc(func(conf *loaderConf) {
 		conf.delim = delim
@@ -135,11 +249,16 @@ func WithDelim(delim string) LoaderConfOption {
 func WithBytes(bytes []byte) LoaderConfOption {
 	return loaderConfigFunc(func(conf *loaderConf) {
 		conf.bytes = bytes
+		conf.file = ""
+		conf.remote = nil
 	})
 }
 
 // absolutePath get absolut path
 func absolutePath(inPath string) string {
+	if isRemotePath(inPath) {
+		return inPath
+	}
 
 	if inPath == "$HOME" || strings.HasPrefix(inPath, "$HOME"+string(os.PathSeparator)) {
 		inPath = userHomeDir() + inPath[5:]
@@ -182,6 +301,9 @@ func checkFileSuffix(suffix string) error {
 // resolverFilePath resolver file path
 // eg: give a ./conf/dubbogo.yaml return dubbogo and yaml
 func resolverFilePath(path string) (name, suffix string) {
+	if i := strings.IndexAny(path, "?#"); i >= 0 && isRemotePath(path) {
+		path = path[:i]
+	}
 	paths := strings.Split(path, "/")
 	fileName := strings.Split(paths[len(paths)-1], ".")
 	if len(fileName) < 2 {
@@ -190,28 +312,101 @@ func resolverFilePath(path string) (name, suffix string) {
 	return fileName[0], fileName[1]
 }
 
-// MergeConfig merge config file
+// MergeConfig merges @koan with the config files of the active profiles, see mergeProfiles
 func (conf *loaderConf) MergeConfig(koan *koanf.Koanf) *koanf.Koanf {
-	var (
-		activeKoan *koanf.Koanf
-		activeConf *loaderConf
-	)
-	active := koan.String("dubbo.profiles.active")
+	tree := newConfigTree(conf.delim)
+	err := tree.merge(conf.source(), koan.Raw())
+	if err == nil {
+		_, err = conf.mergeProfiles(tree)
+	}
+	var merged *koanf.Koanf
+	if err == nil {
+		merged, err = tree.koanf()
+	}
+	if err != nil {
+		logger.Errorf("Merge the config files of the active profiles error, %v", err)
+		return koan
+	}
+	return merged
+}
+
+// mergeProfiles merges the config files of the active profiles in order over @tree, e.g. dubbogo-dev.yaml of the
+// profile dev, and returns the active profiles. They're set by WithActiveProfiles, the env DUBBO_GO_PROFILES_ACTIVE or
+// dubbo.profiles.active of the config files in order of precedence, separated by the commas.
+func (conf *loaderConf) mergeProfiles(tree *configTree) ([]string, error) {
+	active := conf.activeProfiles
+	if active == "" {
+		active = os.Getenv(constant.ProfilesActiveEnvKey)
+	}
+	if active == "" {
+		koan, err := tree.koanf()
+		if err != nil {
+			return nil, err
+		}
+		active = koan.String(constant.ProfilesConfigPrefix + ".active")
+	}
 	active = getLegalActive(active)
 	logger.Infof("The following profiles are active: %s", active)
-	if defaultActive != active {
-		path := conf.getActiveFilePath(active)
+	var profiles []string
+	for _, profile := range strings.Split(active, ",") {
+		if profile = strings.TrimSpace(profile); profile == "" {
+			continue
+		}
+		profiles = append(profiles, profile)
+		if profile == defaultActive {
+			continue
+		}
+		path := conf.getActiveFilePath(profile)
 		if !pathExists(path) {
 			logger.Debugf("Config file:%s not exist skip config merge", path)
-			return koan
+			continue
+		}
+		activeConf := NewLoaderConf(WithPath(path))
+		activeConf.placeholders = conf.placeholders
+		if err := tree.mergeFile(activeConf); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// mergeFormats merges the config file of @conf over @tree with the config files of the same name in the other formats
+// by the precedence of configFileSuffixes
+func (conf *loaderConf) mergeFormats(tree *configTree) error {
+	if conf.file == "" || checkFileSuffix(conf.suffix) != nil {
+		return tree.mergeFile(conf)
+	}
+	base := strings.TrimSuffix(conf.file, filepath.Ext(conf.file))
+	for _, suffix := range configFileSuffixes {
+		if string(suffix) == conf.suffix {
+			if err := tree.mergeFile(conf); err != nil {
+				return err
+			}
+			continue
+		}
+		path := base + constant.DotSeparator + string(suffix)
+		if !pathExists(path) {
+			continue
 		}
-		activeConf = NewLoaderConf(WithPath(path))
-		activeKoan = GetConfigResolver(activeConf)
-		if err := koan.Merge(activeKoan); err != nil {
-			logger.Debugf("Config merge err %s", err)
+		logger.Infof("Merge the config file %s of the same name", path)
+		other := NewLoaderConf(WithPath(path))
+		other.placeholders = conf.placeholders
+		if err := tree.mergeFile(other); err != nil {
+			return err
 		}
 	}
-	return koan
+	return nil
+}
+
+// source describes where the config bytes are from
+func (conf *loaderConf) source() string {
+	if conf.remote != nil {
+		return conf.remote.url
+	}
+	if conf.file == "" {
+		return "the config bytes"
+	}
+	return conf.file
 }
 
 func (conf *loaderConf) getActiveFilePath(active string) string {
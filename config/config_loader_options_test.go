@@ -107,3 +107,34 @@ func Test_getActiveFilePath(t *testing.T) {
 	assert.Equal(t, exists, false)
 
 }
+
+func TestNewLoaderConf_WithConsumerAndProviderConfig(t *testing.T) {
+	application := NewApplicationConfigBuilder().SetName("demo").Build()
+	registry := NewRegistryConfigWithProtocolDefaultPort("zookeeper")
+	protocol := NewProtocolConfigBuilder().SetName("tri").SetPort("20000").Build()
+	reference := NewReferenceConfigBuilder().SetInterface("com.test.UserService").Build()
+	service := NewServiceConfigBuilder().SetInterface("com.test.OrderService").Build()
+
+	cc := NewConsumerConfigBuilder().
+		SetApplication(application).
+		AddRegistry("zk", registry).
+		AddReference("UserService", reference).
+		Build()
+	pc := NewProviderConfigBuilder().
+		AddRegistry("zk", registry).
+		AddProtocol("tri", protocol).
+		AddService("OrderService", service).
+		Build()
+	conf := NewLoaderConf(WithConsumerConfig(cc), WithProviderConfig(pc))
+
+	assert.Nil(t, conf.bytes)
+	assert.Equal(t, cc, conf.rc.Consumer)
+	assert.Equal(t, pc, conf.rc.Provider)
+	assert.Equal(t, application, conf.rc.Application)
+	assert.Equal(t, map[string]*RegistryConfig{"zk": registry}, conf.rc.Registries)
+	assert.Equal(t, map[string]*ProtocolConfig{"tri": protocol}, conf.rc.Protocols)
+	// the other configs are the same as the ones of the empty config file
+	assert.NotNil(t, conf.rc.MetadataReport)
+	assert.NotNil(t, conf.rc.Shutdown)
+	assert.NotNil(t, conf.rc.Logger)
+}
@@ -0,0 +1,283 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	defaultRemoteConfigTimeout  = 5 * time.Second
+	defaultRemoteConfigAttempts = 3
+)
+
+// remoteConfigBackoff is the backoff between the attempts to fetch the remote config at startup, doubled each time
+var remoteConfigBackoff = time.Second
+
+var (
+	refreshingLock sync.Mutex
+	// refreshing is the remote config refreshed in background
+	refreshing *remoteConfig
+)
+
+func init() {
+	// the remote config is no longer refreshed once the application is shut down
+	extension.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		if event == extension.LifecycleAfterShutdown {
+			setRefreshing(nil)
+		}
+	}))
+}
+
+// isRemotePath returns whether the config @path is a http(s) url
+func isRemotePath(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// remoteConfig is the config document served by a http(s) url. It's fetched with the bearer token of the env
+// DUBBO_GO_CONFIG_TOKEN and saved in the snapshot file, which is used if the url is unreachable on the next startup.
+// The document is refreshed every DUBBO_GO_CONFIG_REFRESH_INTERVAL by its ETag, and the changes are applied the same
+// as the ones of the config center.
+type remoteConfig struct {
+	url    string
+	client *http.Client
+	// etag is the ETag of the document fetched last
+	etag string
+	done chan struct{}
+	// stopped is closed once the refresh exits, it's nil if the document isn't refreshed
+	stopped chan struct{}
+	// refreshes counts the changes of the document applied by the refresh
+	refreshes atomic.Int32
+}
+
+func newRemoteConfig(url string) *remoteConfig {
+	return &remoteConfig{
+		url:    url,
+		client: &http.Client{Timeout: envDuration(constant.ConfigFetchTimeoutEnvKey, defaultRemoteConfigTimeout)},
+		done:   make(chan struct{}),
+	}
+}
+
+// load fetches the document at most DUBBO_GO_CONFIG_FETCH_ATTEMPTS times with the backoff, the snapshot is used if
+// the url is unreachable. It fails at once if the credentials are rejected.
+func (r *remoteConfig) load() ([]byte, error) {
+	attempts := defaultRemoteConfigAttempts
+	if value, err := strconv.Atoi(os.Getenv(constant.ConfigFetchAttemptsEnvKey)); err == nil && value > 0 {
+		attempts = value
+	}
+	backoff := remoteConfigBackoff
+	for i := 1; ; i++ {
+		content, _, err := r.fetch()
+		if err == nil {
+			r.saveSnapshot(content)
+			return content, nil
+		}
+		if perrors.Is(err, remoting.ErrAuthFailed) {
+			return nil, err
+		}
+		if i >= attempts {
+			return r.loadSnapshot(err)
+		}
+		logger.Warnf("Fetch the config %s failed, retry %d/%d after %s, cause: %v", r.url, i, attempts-1, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// fetch fetches the document, it returns false with no content if the document is not modified since the last fetch
+func (r *remoteConfig) fetch() ([]byte, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if token := os.Getenv(constant.ConfigTokenEnvKey); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if r.etag != "" {
+		req.Header.Set("If-None-Match", r.etag)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, false, perrors.WithMessagef(err, "fetch the config %s", r.url)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, false, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, false, perrors.WithMessagef(remoting.ErrAuthFailed, "fetch the config %s, %s", r.url, resp.Status)
+	default:
+		return nil, false, perrors.Errorf("fetch the config %s, %s", r.url, resp.Status)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, perrors.WithMessagef(err, "fetch the config %s", r.url)
+	}
+	r.etag = resp.Header.Get("ETag")
+	return content, true, nil
+}
+
+// loadSnapshot returns the snapshot if the url is unreachable for @cause
+func (r *remoteConfig) loadSnapshot(cause error) ([]byte, error) {
+	path := r.snapshotPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, perrors.WithMessagef(cause, "no snapshot %s is available", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, perrors.WithMessagef(cause, "read the snapshot %s error %v", path, err)
+	}
+	logger.Warnf("!!! The config %s is unreachable, START WITH THE SNAPSHOT %s SAVED AT %s, WHICH MAY BE STALE !!! cause: %v",
+		r.url, path, info.ModTime().Format(time.RFC3339), cause)
+	return content, nil
+}
+
+func (r *remoteConfig) saveSnapshot(content []byte) {
+	path := r.snapshotPath()
+	if err := writeSnapshot(path, string(content)); err != nil {
+		logger.Warnf("Save the snapshot %s of the config %s error, %v", path, r.url, err)
+	}
+}
+
+// snapshotPath returns the path of the snapshot file identified by the url
+func (r *remoteConfig) snapshotPath() string {
+	dir := os.Getenv(constant.ConfigSnapshotDirEnvKey)
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		dir = filepath.Join(home, ".dubbo", "config", "snapshot")
+	}
+	return filepath.Join(dir, snapshotNameReplacer.ReplaceAllString(r.url, "-")+".snapshot")
+}
+
+// refresh refreshes the document of @conf every DUBBO_GO_CONFIG_REFRESH_INTERVAL in background and applies the
+// changes to @rc, until the application is shut down
+func (r *remoteConfig) refresh(rc *RootConfig, conf *loaderConf) {
+	interval := envDuration(constant.ConfigRefreshIntervalEnvKey, 0)
+	if interval <= 0 {
+		return
+	}
+	r.stopped = make(chan struct{})
+	setRefreshing(r)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer func() {
+			ticker.Stop()
+			close(r.stopped)
+		}()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+			}
+			content, modified, err := r.fetch()
+			if err != nil {
+				logger.Warnf("Refresh the config %s failed, cause: %v", r.url, err)
+				continue
+			}
+			if !modified {
+				continue
+			}
+			if err = r.apply(rc, conf, content); err != nil {
+				logger.Errorf("Refresh the config %s failed, got error %v", r.url, err)
+				continue
+			}
+			logger.Infof("The config %s is refreshed", r.url)
+			r.saveSnapshot(content)
+			r.refreshes.Inc()
+		}
+	}()
+}
+
+// apply applies the changed document @content of @conf to @rc like the changes of the config center
+func (r *remoteConfig) apply(rc *RootConfig, conf *loaderConf, content []byte) error {
+	koan, err := newConfigResolver(&loaderConf{
+		suffix:       conf.suffix,
+		delim:        conf.delim,
+		bytes:        content,
+		placeholders: conf.placeholders,
+	})
+	if err != nil {
+		return err
+	}
+	if rc.sources == nil {
+		rc.sources = newConfigSources(nil)
+	}
+	return rc.mergeSource(SourceLocal, koan.All())
+}
+
+// stop stops the refresh and waits for it to exit, so that no change is applied after it returns
+func (r *remoteConfig) stop() {
+	select {
+	case <-r.done:
+	default:
+		close(r.done)
+	}
+	if r.stopped != nil {
+		<-r.stopped
+	}
+}
+
+func setRefreshing(r *remoteConfig) {
+	refreshingLock.Lock()
+	defer refreshingLock.Unlock()
+	if refreshing != nil && refreshing != r {
+		refreshing.stop()
+	}
+	refreshing = r
+}
+
+// envDuration returns the duration of the env @key, or @def if it's absent or invalid
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	result, err := common.ParseDuration(value)
+	if err != nil || result < 0 {
+		logger.Errorf("The env %s is invalid: %s, and we will use the default value: %s", key, value, def)
+		return def
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// configServer serves the config document by its ETag, with the bearer token if it's set
+type configServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	content  string
+	etag     string
+	token    string
+	requests int32
+	// notModified counts the requests answered by 304
+	notModified int32
+}
+
+func newConfigServer(content string) *configServer {
+	s := &configServer{content: content, etag: `"1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("If-None-Match") == s.etag {
+			atomic.AddInt32(&s.notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", s.etag)
+		_, _ = w.Write([]byte(s.content))
+	}))
+	return s
+}
+
+func (s *configServer) update(content, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.content, s.etag = content, etag
+}
+
+func TestLoadRemoteConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote-config")
+	require.NoError(t, err)
+	backoff := remoteConfigBackoff
+	remoteConfigBackoff = time.Millisecond
+	defer func() {
+		_ = os.RemoveAll(dir)
+		_ = os.Unsetenv(constant.ConfigSnapshotDirEnvKey)
+		_ = os.Unsetenv(constant.ConfigTokenEnvKey)
+		_ = os.Unsetenv(constant.ConfigRefreshIntervalEnvKey)
+		remoteConfigBackoff = backoff
+		setRefreshing(nil)
+	}()
+	require.NoError(t, os.Setenv(constant.ConfigSnapshotDirEnvKey, dir))
+	server := newConfigServer("dubbo:\n  application:\n    name: remote-app\n  logger:\n    level: info\n")
+	defer server.Close()
+	path := server.URL + "/conf/dubbogo.yaml?env=test"
+
+	t.Run("success", func(t *testing.T) {
+		conf := NewLoaderConf(WithPath(path))
+		assert.Equal(t, "yaml", conf.suffix)
+		assert.Equal(t, "dubbogo", conf.name)
+		assert.Equal(t, path, conf.source())
+		assert.NoError(t, Load(WithPath(path)))
+		assert.Equal(t, "remote-app", rootConfig.Application.Name)
+		snapshot, err := ioutil.ReadFile(conf.remote.snapshotPath())
+		assert.NoError(t, err)
+		assert.Contains(t, string(snapshot), "remote-app")
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		r := newRemoteConfig(path)
+		content, modified, err := r.fetch()
+		assert.NoError(t, err)
+		assert.True(t, modified)
+		assert.Contains(t, string(content), "remote-app")
+		content, modified, err = r.fetch()
+		assert.NoError(t, err)
+		assert.False(t, modified)
+		assert.Nil(t, content)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&server.notModified))
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		require.NoError(t, os.Setenv(constant.ConfigRefreshIntervalEnvKey, "10ms"))
+		defer os.Unsetenv(constant.ConfigRefreshIntervalEnvKey)
+		assert.NoError(t, Load(WithPath(path)))
+		// the unmodified document is not applied again
+		notModified := atomic.LoadInt32(&server.notModified)
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&server.notModified) > notModified
+		}, time.Second, 10*time.Millisecond)
+		server.update("dubbo:\n  application:\n    name: remote-app\n  logger:\n    level: warn\n", `"2"`)
+		refreshingLock.Lock()
+		r := refreshing
+		refreshingLock.Unlock()
+		assert.Eventually(t, func() bool {
+			return r.refreshes.Load() > 0
+		}, time.Second, 10*time.Millisecond)
+		// the config is read after the refresh exits
+		setRefreshing(nil)
+		assert.Equal(t, "warn", GetRootConfig().Logger.Level)
+	})
+
+	t.Run("auth", func(t *testing.T) {
+		server.mu.Lock()
+		server.token = "secret"
+		server.mu.Unlock()
+		requests := atomic.LoadInt32(&server.requests)
+		// the rejected credentials are neither retried nor replaced by the snapshot
+		assert.PanicsWithError(t, "fetch the config "+path+", 401 Unauthorized: authentication failed", func() {
+			_ = Load(WithPath(path))
+		})
+		assert.Equal(t, requests+1, atomic.LoadInt32(&server.requests))
+		require.NoError(t, os.Setenv(constant.ConfigTokenEnvKey, "secret"))
+		assert.NoError(t, Load(WithPath(path)))
+	})
+
+	t.Run("unreachable with snapshot", func(t *testing.T) {
+		server.Close()
+		rootConfig.Application.Name = ""
+		assert.NoError(t, Load(WithPath(path)))
+		assert.Equal(t, "remote-app", rootConfig.Application.Name)
+		assert.Equal(t, "warn", rootConfig.Logger.Level)
+
+		require.NoError(t, os.Setenv(constant.ConfigSnapshotDirEnvKey, dir+"/absent"))
+		assert.Panics(t, func() {
+			_ = Load(WithPath(path))
+		})
+	})
+}
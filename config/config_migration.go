@@ -0,0 +1,263 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// configMigration migrates a deprecated key of the config files to its new location. The key is the dotted path of
+// the deprecated key, in which * matches any id, e.g. the id of a registry. The key is renamed to rename in the same
+// section unless rename is empty, and its value is migrated by migrate if it is set.
+type configMigration struct {
+	key    string
+	rename string
+	// migrate returns the migrated value and true if @value is in a deprecated form, or false if it is current
+	migrate func(value interface{}) (interface{}, bool)
+}
+
+// configMigrations are the renamed config keys and the deprecated forms of the values, the loader migrates them
+// before merging the config files, and tools/configmigrate rewrites the config files with them. A rename of a key
+// should be appended here with a test, so that the config files of the previous versions keep working.
+var configMigrations = []configMigration{
+	{key: "dubbo.config_center", rename: "config-center"},
+	{key: "dubbo.metadata_report", rename: "metadata-report"},
+	{key: "dubbo.shutdown_conf", rename: "shutdown"},
+	{key: "dubbo.registries.*.address", migrate: joinAddresses},
+	{key: "dubbo.provider.registry", rename: "registry-ids", migrate: splitIDs},
+	{key: "dubbo.provider.filter", migrate: renameFilters},
+	{key: "dubbo.provider.services.*.registry", rename: "registry-ids", migrate: splitIDs},
+	{key: "dubbo.provider.services.*.protocol", rename: "protocol-ids", migrate: splitIDs},
+	{key: "dubbo.provider.services.*.filter", migrate: renameFilters},
+	{key: "dubbo.consumer.request_timeout", rename: "request-timeout"},
+	{key: "dubbo.consumer.filter_conf", rename: "filter-conf"},
+	{key: "dubbo.consumer.registry", rename: "registry-ids", migrate: splitIDs},
+	{key: "dubbo.consumer.filter", migrate: renameFilters},
+	{key: "dubbo.consumer.references.*.registry", rename: "registry-ids", migrate: splitIDs},
+	{key: "dubbo.consumer.references.*.filter", migrate: renameFilters},
+}
+
+// filterRenames are the deprecated names of the filters, as named in Apache Dubbo, to the names of the filters
+var filterRenames = map[string]string{
+	"tpslimit":     constant.TpsLimitFilterKey,
+	"executelimit": constant.ExecuteLimitFilterKey,
+	"activelimit":  constant.ActiveFilterKey,
+}
+
+// ConfigDeprecation is a deprecated usage of the config migrated to its replacement
+type ConfigDeprecation struct {
+	// File is the config file of the deprecated usage, empty if it isn't loaded from a file
+	File        string
+	Key         string
+	Value       interface{}
+	Replacement string
+	NewValue    interface{}
+	// Ignored is true if the replacement is set as well, which takes precedence over the deprecated usage
+	Ignored bool
+}
+
+func (d ConfigDeprecation) String() string {
+	var usage string
+	switch {
+	case d.Key == d.Replacement:
+		usage = fmt.Sprintf("%s: %v -> %v", d.Key, d.Value, d.NewValue)
+	case fmt.Sprint(d.Value) == fmt.Sprint(d.NewValue):
+		usage = fmt.Sprintf("%s -> %s", d.Key, d.Replacement)
+	default:
+		usage = fmt.Sprintf("%s: %v -> %s: %v", d.Key, d.Value, d.Replacement, d.NewValue)
+	}
+	if d.Ignored {
+		usage += fmt.Sprintf(" (ignored, %s is set)", d.Replacement)
+	}
+	if d.File != "" {
+		usage = d.File + ": " + usage
+	}
+	return usage
+}
+
+// MigrateConfig migrates the deprecated keys of the nested config @values in place by the migrations of the loader,
+// and returns the deprecated usages found in order of the keys
+func MigrateConfig(values map[string]interface{}) []ConfigDeprecation {
+	var deprecations []ConfigDeprecation
+	for _, migration := range configMigrations {
+		deprecations = append(deprecations, migration.apply(values, "", strings.Split(migration.key, "."))...)
+	}
+	sort.SliceStable(deprecations, func(i, j int) bool {
+		return deprecations[i].Key < deprecations[j].Key
+	})
+	return deprecations
+}
+
+// checkDeprecations logs the deprecated usages of the config files in a single warning, or fails with them if
+// strict-config is set
+func (rc *RootConfig) checkDeprecations(deprecations []ConfigDeprecation) error {
+	if len(deprecations) == 0 {
+		return nil
+	}
+	usages := make([]string, 0, len(deprecations))
+	for _, deprecation := range deprecations {
+		usages = append(usages, "  "+deprecation.String())
+	}
+	if rc.StrictConfig {
+		return perrors.Errorf("the deprecated config keys are used with strict-config, "+
+			"migrate them by tools/configmigrate:\n%s", strings.Join(usages, "\n"))
+	}
+	logger.Warnf("The deprecated config keys are migrated, please replace them, e.g. by tools/configmigrate:\n%s",
+		strings.Join(usages, "\n"))
+	return nil
+}
+
+// apply migrates the keys matching the @segments of the key under the section @values of the key @path
+func (m configMigration) apply(values map[string]interface{}, path string, segments []string) []ConfigDeprecation {
+	keys := []string{segments[0]}
+	if segments[0] == "*" {
+		keys = keys[:0]
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+	var deprecations []ConfigDeprecation
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		if len(segments) > 1 {
+			if section, ok := sectionOf(values, key); ok {
+				deprecations = append(deprecations, m.apply(section, keyPath, segments[1:])...)
+			}
+			continue
+		}
+		if deprecation, ok := m.migrateKey(values, path, key, value); ok {
+			deprecation.Key = keyPath
+			deprecations = append(deprecations, deprecation)
+		}
+	}
+	return deprecations
+}
+
+// migrateKey migrates the @key of @value in the section @values of the key @path
+func (m configMigration) migrateKey(values map[string]interface{}, path, key string, value interface{}) (ConfigDeprecation, bool) {
+	newKey := key
+	if m.rename != "" {
+		newKey = m.rename
+	}
+	newValue, migrated := value, false
+	if m.migrate != nil {
+		newValue, migrated = m.migrate(value)
+	}
+	if newKey == key && !migrated {
+		return ConfigDeprecation{}, false
+	}
+	replacement := newKey
+	if path != "" {
+		replacement = path + "." + newKey
+	}
+	deprecation := ConfigDeprecation{Value: value, Replacement: replacement, NewValue: newValue}
+	if _, ok := values[newKey]; ok && newKey != key {
+		deprecation.Ignored = true
+	} else {
+		values[newKey] = newValue
+	}
+	if newKey != key {
+		delete(values, key)
+	}
+	return deprecation, true
+}
+
+// sectionOf returns the section @key of @values, which is converted to map[string]interface{} in place if it is
+// decoded as map[interface{}]interface{}, e.g. by yaml
+func sectionOf(values map[string]interface{}, key string) (map[string]interface{}, bool) {
+	if section, ok := values[key].(map[string]interface{}); ok {
+		return section, true
+	}
+	section, ok := toStringMap(values[key])
+	if ok {
+		values[key] = section
+	}
+	return section, ok
+}
+
+// joinAddresses migrates the list of addresses to the addresses separated by comma
+func joinAddresses(value interface{}) (interface{}, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value, false
+	}
+	addresses := make([]string, 0, len(list))
+	for _, address := range list {
+		addresses = append(addresses, fmt.Sprint(address))
+	}
+	return strings.Join(addresses, ","), true
+}
+
+// splitIDs migrates the ids separated by comma to the list of ids
+func splitIDs(value interface{}) (interface{}, bool) {
+	ids, ok := value.(string)
+	if !ok {
+		return value, false
+	}
+	list := make([]interface{}, 0)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			list = append(list, id)
+		}
+	}
+	return list, true
+}
+
+// renameFilters migrates the deprecated names of the filters separated by comma, including the excluded ones like
+// -tpslimit
+func renameFilters(value interface{}) (interface{}, bool) {
+	filters, ok := value.(string)
+	if !ok {
+		return value, false
+	}
+	names := strings.Split(filters, ",")
+	migrated := false
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		excluded := strings.HasPrefix(name, constant.RemoveValuePrefix)
+		if newName, ok := filterRenames[strings.TrimPrefix(name, constant.RemoveValuePrefix)]; ok {
+			if excluded {
+				newName = constant.RemoveValuePrefix + newName
+			}
+			names[i], migrated = newName, true
+		}
+	}
+	if !migrated {
+		return value, false
+	}
+	return strings.Join(names, ","), true
+}
@@ -0,0 +1,189 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		// migration is the key of the migration tested
+		migration string
+		config    string
+		migrated  string
+		usage     string
+	}{
+		{
+			migration: "dubbo.config_center",
+			config:    "dubbo:\n  config_center:\n    protocol: nacos\n",
+			migrated:  "dubbo:\n  config-center:\n    protocol: nacos\n",
+			usage:     "dubbo.config_center -> dubbo.config-center",
+		},
+		{
+			migration: "dubbo.metadata_report",
+			config:    "dubbo:\n  metadata_report:\n    protocol: zookeeper\n",
+			migrated:  "dubbo:\n  metadata-report:\n    protocol: zookeeper\n",
+			usage:     "dubbo.metadata_report -> dubbo.metadata-report",
+		},
+		{
+			migration: "dubbo.shutdown_conf",
+			config:    "dubbo:\n  shutdown_conf:\n    timeout: 60s\n",
+			migrated:  "dubbo:\n  shutdown:\n    timeout: 60s\n",
+			usage:     "dubbo.shutdown_conf -> dubbo.shutdown",
+		},
+		{
+			migration: "dubbo.registries.*.address",
+			config:    "dubbo:\n  registries:\n    zk:\n      address: [127.0.0.1:2181, 127.0.0.1:2182]\n",
+			migrated:  "dubbo:\n  registries:\n    zk:\n      address: 127.0.0.1:2181,127.0.0.1:2182\n",
+			usage:     "dubbo.registries.zk.address: [127.0.0.1:2181 127.0.0.1:2182] -> 127.0.0.1:2181,127.0.0.1:2182",
+		},
+		{
+			migration: "dubbo.provider.registry",
+			config:    "dubbo:\n  provider:\n    registry: zk, nacos\n",
+			migrated:  "dubbo:\n  provider:\n    registry-ids: [zk, nacos]\n",
+			usage:     "dubbo.provider.registry: zk, nacos -> dubbo.provider.registry-ids: [zk nacos]",
+		},
+		{
+			migration: "dubbo.provider.filter",
+			config:    "dubbo:\n  provider:\n    filter: echo,tpslimit\n",
+			migrated:  "dubbo:\n  provider:\n    filter: echo,tps\n",
+			usage:     "dubbo.provider.filter: echo,tpslimit -> echo,tps",
+		},
+		{
+			migration: "dubbo.provider.services.*.registry",
+			config:    "dubbo:\n  provider:\n    services:\n      Greeter:\n        registry: zk\n",
+			migrated:  "dubbo:\n  provider:\n    services:\n      Greeter:\n        registry-ids: [zk]\n",
+			usage:     "dubbo.provider.services.Greeter.registry: zk -> dubbo.provider.services.Greeter.registry-ids: [zk]",
+		},
+		{
+			migration: "dubbo.provider.services.*.protocol",
+			config:    "dubbo:\n  provider:\n    services:\n      Greeter:\n        protocol: dubbo,tri\n",
+			migrated:  "dubbo:\n  provider:\n    services:\n      Greeter:\n        protocol-ids: [dubbo, tri]\n",
+			usage:     "dubbo.provider.services.Greeter.protocol: dubbo,tri -> dubbo.provider.services.Greeter.protocol-ids: [dubbo tri]",
+		},
+		{
+			migration: "dubbo.provider.services.*.filter",
+			config:    "dubbo:\n  provider:\n    services:\n      Greeter:\n        filter: executelimit\n",
+			migrated:  "dubbo:\n  provider:\n    services:\n      Greeter:\n        filter: execute\n",
+			usage:     "dubbo.provider.services.Greeter.filter: executelimit -> execute",
+		},
+		{
+			migration: "dubbo.consumer.request_timeout",
+			config:    "dubbo:\n  consumer:\n    request_timeout: 5s\n",
+			migrated:  "dubbo:\n  consumer:\n    request-timeout: 5s\n",
+			usage:     "dubbo.consumer.request_timeout -> dubbo.consumer.request-timeout",
+		},
+		{
+			migration: "dubbo.consumer.filter_conf",
+			config:    "dubbo:\n  consumer:\n    filter_conf:\n      hystrix:\n        timeout: 1000\n",
+			migrated:  "dubbo:\n  consumer:\n    filter-conf:\n      hystrix:\n        timeout: 1000\n",
+			usage:     "dubbo.consumer.filter_conf -> dubbo.consumer.filter-conf",
+		},
+		{
+			migration: "dubbo.consumer.registry",
+			config:    "dubbo:\n  consumer:\n    registry: zk\n",
+			migrated:  "dubbo:\n  consumer:\n    registry-ids: [zk]\n",
+			usage:     "dubbo.consumer.registry: zk -> dubbo.consumer.registry-ids: [zk]",
+		},
+		{
+			migration: "dubbo.consumer.filter",
+			config:    "dubbo:\n  consumer:\n    filter: -activelimit\n",
+			migrated:  "dubbo:\n  consumer:\n    filter: -active\n",
+			usage:     "dubbo.consumer.filter: -activelimit -> -active",
+		},
+		{
+			migration: "dubbo.consumer.references.*.registry",
+			config:    "dubbo:\n  consumer:\n    references:\n      Greeter:\n        registry: zk\n",
+			migrated:  "dubbo:\n  consumer:\n    references:\n      Greeter:\n        registry-ids: [zk]\n",
+			usage:     "dubbo.consumer.references.Greeter.registry: zk -> dubbo.consumer.references.Greeter.registry-ids: [zk]",
+		},
+		{
+			migration: "dubbo.consumer.references.*.filter",
+			config:    "dubbo:\n  consumer:\n    references:\n      Greeter:\n        filter: tpslimit,echo\n",
+			migrated:  "dubbo:\n  consumer:\n    references:\n      Greeter:\n        filter: tps,echo\n",
+			usage:     "dubbo.consumer.references.Greeter.filter: tpslimit,echo -> tps,echo",
+		},
+	}
+	tested := make(map[string]bool)
+	for _, test := range tests {
+		tested[test.migration] = true
+		t.Run(test.migration, func(t *testing.T) {
+			values := parseYAML(t, test.config)
+			deprecations := MigrateConfig(values)
+			require.Len(t, deprecations, 1)
+			assert.Equal(t, test.usage, deprecations[0].String())
+			expected := parseYAML(t, test.migrated)
+			assert.True(t, reflect.DeepEqual(flatten(t, expected), flatten(t, values)),
+				"expected %v, actual %v", flatten(t, expected), flatten(t, values))
+			// the migrated config is current
+			assert.Empty(t, MigrateConfig(values))
+		})
+	}
+	for _, migration := range configMigrations {
+		assert.True(t, tested[migration.key], "the migration of %s isn't tested", migration.key)
+	}
+
+	t.Run("replacement set as well", func(t *testing.T) {
+		values := parseYAML(t, "dubbo:\n  consumer:\n    request_timeout: 5s\n    request-timeout: 3s\n")
+		deprecations := MigrateConfig(values)
+		require.Len(t, deprecations, 1)
+		assert.Equal(t, "dubbo.consumer.request_timeout -> dubbo.consumer.request-timeout "+
+			"(ignored, dubbo.consumer.request-timeout is set)", deprecations[0].String())
+		assert.Equal(t, map[string]interface{}{"dubbo.consumer.request-timeout": "3s"}, flatten(t, values))
+	})
+}
+
+func TestLoadDeprecatedConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dubbogo.yaml")
+	content := "dubbo:\n" +
+		"  registries:\n" +
+		"    zk:\n" +
+		"      protocol: zookeeper\n" +
+		"      address: [127.0.0.1:2181, 127.0.0.1:2182]\n" +
+		"  consumer:\n" +
+		"    request_timeout: 5s\n" +
+		"    registry: zk\n" +
+		"    filter: tpslimit\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	rc := newEmptyRootConfig()
+	_, files, err := loadConfigFiles(NewLoaderConf(WithPath(path)), rc)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:2181,127.0.0.1:2182", rc.Registries["zk"].Address)
+	assert.Equal(t, "5s", rc.Consumer.RequestTimeout)
+	assert.Equal(t, []string{"zk"}, rc.Consumer.RegistryIDs)
+	assert.Equal(t, "tps", rc.Consumer.Filter)
+	assert.Equal(t, path, files["dubbo.consumer.request-timeout"])
+
+	strict := filepath.Join(dir, "strict.yaml")
+	require.NoError(t, os.WriteFile(strict, []byte(content+"  strict-config: true\n"), 0o644))
+	_, _, err = loadConfigFiles(NewLoaderConf(WithPath(strict)), newEmptyRootConfig())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "strict-config")
+	assert.Contains(t, err.Error(), strict+": dubbo.consumer.request_timeout -> dubbo.consumer.request-timeout")
+	assert.Contains(t, err.Error(), strict+": dubbo.consumer.filter: tpslimit -> tps")
+}
@@ -18,6 +18,9 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"regexp"
 	"strings"
 )
 
@@ -39,8 +42,23 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/config/parsers/properties"
 )
 
+var (
+	// placeholderPattern matches the placeholders ${NAME} and ${NAME:default} in a value, and the escaped ones
+	// $${NAME} which are kept literally as ${NAME}
+	placeholderPattern = regexp.MustCompile(`\$?\$\{([^{}:]+)(?::([^{}]*))?}`)
+)
+
 // GetConfigResolver get config resolver
 func GetConfigResolver(conf *loaderConf) *koanf.Koanf {
+	k, err := newConfigResolver(conf)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// newConfigResolver parses the config bytes of @conf and resolves the placeholders in the values
+func newConfigResolver(conf *loaderConf) (*koanf.Koanf, error) {
 	var (
 		k   *koanf.Koanf
 		err error
@@ -65,7 +83,12 @@ func GetConfigResolver(conf *loaderConf) *koanf.Koanf {
 	case "toml":
 		err = k.Load(rawbytes.Provider(bytes), toml.Parser())
 	case "properties":
-		err = k.Load(rawbytes.Provider(bytes), properties.Parser())
+		if err = k.Load(rawbytes.Provider(bytes), properties.Parser()); err == nil {
+			// the keys of the Java dubbo.properties are mapped onto the ones of dubbo-go
+			mapped := koanf.New(conf.delim)
+			err = mapped.Load(confmap.Provider(mapJavaProperties(k.All()), conf.delim), nil)
+			k = mapped
+		}
 	default:
 		err = errors.Errorf("no support %s file suffix", conf.suffix)
 	}
@@ -73,46 +96,132 @@ func GetConfigResolver(conf *loaderConf) *koanf.Koanf {
 	if err != nil {
 		panic(err)
 	}
-	return resolvePlaceholder(k)
+	return resolvePlaceholder(k, conf)
 }
 
-// resolvePlaceholder replace ${xx} with real value
-func resolvePlaceholder(resolver *koanf.Koanf) *koanf.Koanf {
+// resolvePlaceholder replaces the placeholders ${xx} and ${xx:default} anywhere in the values with the config xx, or
+// the environment variable xx if the config is absent, or the property xx of the placeholder properties file, or the
+// default value at last. An error naming the key and the file is returned if a placeholder without default value can
+// not be resolved. The escaped placeholder $${xx} is kept literally as ${xx}.
+func resolvePlaceholder(resolver *koanf.Koanf, conf *loaderConf) (*koanf.Koanf, error) {
+	r := &placeholderResolver{koan: resolver, conf: conf, resolving: make(map[string]bool)}
 	m := make(map[string]interface{})
 	for k, v := range resolver.All() {
-		s, ok := v.(string)
-		if !ok {
-			continue
-		}
-		newKey, defaultValue := checkPlaceholder(s)
-		if newKey == "" {
-			continue
+		resolved, changed, err := r.resolve(k, v)
+		if err != nil {
+			return nil, err
 		}
-		m[k] = resolver.Get(newKey)
-		if m[k] == nil {
-			m[k] = defaultValue
+		if changed {
+			m[k] = resolved
 		}
 	}
 	err := resolver.Load(confmap.Provider(m, resolver.Delim()), nil)
 	if err != nil {
 		log.Errorf("resolvePlaceholder error %s", err)
 	}
-	return resolver
+	return resolver, nil
 }
 
-func checkPlaceholder(s string) (newKey, defaultValue string) {
-	s = strings.TrimSpace(s)
-	if !strings.HasPrefix(s, file.PlaceholderPrefix) || !strings.HasSuffix(s, file.PlaceholderSuffix) {
-		return
+type placeholderResolver struct {
+	koan *koanf.Koanf
+	conf *loaderConf
+	// resolving are the keys being resolved, which are referred by the placeholders circularly if met again
+	resolving map[string]bool
+}
+
+// resolve resolves the placeholders in the value @v of @key, the strings in the lists are resolved as well
+func (r *placeholderResolver) resolve(key string, v interface{}) (interface{}, bool, error) {
+	switch value := v.(type) {
+	case string:
+		return r.resolveString(key, value)
+	case []interface{}:
+		var changed bool
+		resolved := make([]interface{}, len(value))
+		for i, item := range value {
+			res, c, err := r.resolve(key, item)
+			if err != nil {
+				return nil, false, err
+			}
+			resolved[i], changed = res, changed || c
+		}
+		return resolved, changed, nil
+	default:
+		return v, false, nil
 	}
-	s = s[len(file.PlaceholderPrefix) : len(s)-len(file.PlaceholderSuffix)]
-	indexColon := strings.Index(s, ":")
-	if indexColon == -1 {
-		newKey = strings.TrimSpace(s)
-		return
+}
+
+func (r *placeholderResolver) resolveString(key, value string) (interface{}, bool, error) {
+	if !strings.Contains(value, file.PlaceholderPrefix) {
+		return value, false, nil
 	}
-	newKey = strings.TrimSpace(s[0:indexColon])
-	defaultValue = strings.TrimSpace(s[indexColon+1:])
+	// the config referred by the whole value is kept in its type, e.g. the port ${server.port}
+	if name, defaultValue, ok := checkPlaceholder(value); ok && !strings.HasPrefix(strings.TrimSpace(value), "$$") {
+		return r.lookup(key, name, defaultValue)
+	}
+	var err error
+	resolved := placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if err != nil {
+			return placeholder
+		}
+		if strings.HasPrefix(placeholder, "$$") {
+			return placeholder[1:]
+		}
+		matches := placeholderPattern.FindStringSubmatch(placeholder)
+		var v interface{}
+		v, _, err = r.lookup(key, strings.TrimSpace(matches[1]), placeholderDefault(placeholder, matches[2]))
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return resolved, true, nil
+}
 
-	return
+// lookup returns the value of the placeholder @name in the value of @key
+func (r *placeholderResolver) lookup(key, name string, defaultValue *string) (interface{}, bool, error) {
+	if r.koan.Exists(name) {
+		if r.resolving[name] {
+			return nil, false, errors.Errorf("the placeholder ${%s} of %s in %s is referred circularly",
+				name, key, r.conf.source())
+		}
+		r.resolving[name] = true
+		defer delete(r.resolving, name)
+		v, _, err := r.resolve(name, r.koan.Get(name))
+		return v, true, err
+	}
+	if env, ok := os.LookupEnv(name); ok {
+		return env, true, nil
+	}
+	if v, ok := r.conf.placeholders[name]; ok {
+		return v, true, nil
+	}
+	if defaultValue != nil {
+		return *defaultValue, true, nil
+	}
+	return nil, false, errors.Errorf("the placeholder ${%s} of %s in %s can not be resolved, "+
+		"please set the environment variable %s or give it a default value by ${%s:default}",
+		name, key, r.conf.source(), name, name)
+}
+
+// checkPlaceholder returns the name and the default value if @s is a placeholder as a whole
+func checkPlaceholder(s string) (name string, defaultValue *string, ok bool) {
+	s = strings.TrimSpace(s)
+	loc := placeholderPattern.FindStringSubmatchIndex(s)
+	if loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return "", nil, false
+	}
+	matches := placeholderPattern.FindStringSubmatch(s)
+	return strings.TrimSpace(matches[1]), placeholderDefault(s, matches[2]), true
+}
+
+// placeholderDefault returns the default value of @placeholder, nil if there is none
+func placeholderDefault(placeholder, defaultValue string) *string {
+	if !strings.Contains(placeholder, ":") {
+		return nil
+	}
+	defaultValue = strings.TrimSpace(defaultValue)
+	return &defaultValue
 }
@@ -18,6 +18,7 @@
 package config
 
 import (
+	"os"
 	"testing"
 )
 
@@ -46,3 +47,88 @@ func TestResolvePlaceHolder(t *testing.T) {
 
 	})
 }
+
+func TestResolvePlaceHolderFromEnv(t *testing.T) {
+	assert.NoError(t, os.Setenv("notexist", "prod"))
+	defer os.Unsetenv("notexist")
+
+	conf := NewLoaderConf(WithPath("./testdata/config/resolver/application.yaml"))
+	koan := GetConfigResolver(conf)
+	assert.Equal(t, "prod", koan.Get("dubbo.registries.nacos.group"))
+	assert.Equal(t, "prod", koan.Get("dubbo.registries.zk.group"))
+	// the config beats the environment variable
+	assert.Equal(t, "127.0.0.1", koan.Get("dubbo.protocols.dubbo.ip"))
+}
+
+func TestResolvePlaceHolderInValues(t *testing.T) {
+	assert.NoError(t, os.Setenv("DUBBO_TEST_ZK_HOST", "192.168.0.1"))
+	defer os.Unsetenv("DUBBO_TEST_ZK_HOST")
+
+	content := `
+dubbo:
+  application:
+    name: ${DUBBO_TEST_APP:dubbo-go}-${DUBBO_TEST_ENV:dev}
+  protocols:
+    dubbo:
+      port: ${dubbo.ports.dubbo}
+  ports:
+    dubbo: 20000
+  registries:
+    zk:
+      address: zookeeper://${DUBBO_TEST_ZK_HOST}:2181
+      username: ${DUBBO_TEST_USERNAME:}
+      params:
+        literal: $${DUBBO_TEST_ZK_HOST}
+  consumer:
+    registry-ids:
+      - ${DUBBO_TEST_REGISTRY:zk}
+      - nacos
+`
+	koan, err := newConfigResolver(NewLoaderConf(WithBytes([]byte(content))))
+	assert.Nil(t, err)
+	assert.Equal(t, "dubbo-go-dev", koan.String("dubbo.application.name"))
+	// the whole value referring a config keeps its type
+	assert.Equal(t, int64(20000), koan.Int64("dubbo.protocols.dubbo.port"))
+	assert.Equal(t, "zookeeper://192.168.0.1:2181", koan.String("dubbo.registries.zk.address"))
+	assert.Equal(t, "", koan.String("dubbo.registries.zk.username"))
+	assert.Equal(t, "${DUBBO_TEST_ZK_HOST}", koan.String("dubbo.registries.zk.params.literal"))
+	assert.Equal(t, []string{"zk", "nacos"}, koan.Strings("dubbo.consumer.registry-ids"))
+
+	rc := NewRootConfigBuilder().Build()
+	assert.Nil(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}))
+	assert.Equal(t, "dubbo-go-dev", rc.Application.Name)
+	assert.Equal(t, "20000", rc.Protocols["dubbo"].Port)
+	assert.Equal(t, "zookeeper://192.168.0.1:2181", rc.Registries["zk"].Address)
+	assert.Equal(t, "${DUBBO_TEST_ZK_HOST}", rc.Registries["zk"].Params["literal"])
+}
+
+func TestResolvePlaceHolderFromProperties(t *testing.T) {
+	conf := NewLoaderConf(WithPath("./testdata/config/resolver/unresolved.yaml"),
+		WithPlaceholderPath("./testdata/config/resolver/placeholder.properties"))
+	koan, err := newConfigResolver(conf)
+	assert.Nil(t, err)
+	assert.Equal(t, "zookeeper://10.0.0.1:2181", koan.String("dubbo.registries.zk.address"))
+
+	// the environment variable beats the properties
+	assert.NoError(t, os.Setenv("DUBBO_TEST_ZK_HOST", "192.168.0.1"))
+	defer os.Unsetenv("DUBBO_TEST_ZK_HOST")
+	koan, err = newConfigResolver(conf)
+	assert.Nil(t, err)
+	assert.Equal(t, "zookeeper://192.168.0.1:2181", koan.String("dubbo.registries.zk.address"))
+}
+
+func TestResolvePlaceHolderUnresolved(t *testing.T) {
+	_, err := newConfigResolver(NewLoaderConf(WithPath("./testdata/config/resolver/unresolved.yaml")))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "${DUBBO_TEST_ZK_HOST}")
+	assert.Contains(t, err.Error(), "dubbo.registries.zk.address")
+	assert.Contains(t, err.Error(), "unresolved.yaml")
+
+	_, err = newConfigResolver(NewLoaderConf(WithBytes([]byte("a: ${b}\nb: ${a}"))))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "circularly")
+
+	assert.Panics(t, func() {
+		GetConfigResolver(NewLoaderConf(WithPath("./testdata/config/resolver/unresolved.yaml")))
+	})
+}
@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+
+	perrors "github.com/pkg/errors"
+)
+
+// includeKey is the top level key of the config files included by a config file, e.g. include: [common.yml]. The
+// paths are relative to the directory of the including file.
+const includeKey = "include"
+
+// identityKeys are the keys identifying the elements of the lists merged by identity, in order of precedence
+var identityKeys = []string{"id", "name"}
+
+// configTree is the nested values merged from the config files, with the file each value comes from. The config
+// files are merged one over another in order:
+//   - the maps are merged key by key recursively, so that the named sections like registries, protocols, services
+//     and references are merged by their ids, and a file overrides only the keys it sets;
+//   - the lists whose elements are all maps identified by the same key of identityKeys in both files, e.g. the
+//     methods of a service by name, are merged element by element by the identity, the new elements are appended;
+//   - the other values, including the other lists, are replaced as a whole, an empty value replaces nothing;
+//   - a map can't be merged with a value of another kind, which fails with the file and the key path.
+//
+// A config file is merged after the files it includes, so that its own values override the included ones.
+type configTree struct {
+	delim  string
+	values map[string]interface{}
+	// files are the files of the values keyed by the flattened key, a list merged comes from the last file
+	files map[string]string
+	// deprecations are the deprecated usages migrated in the config files, see configMigrations
+	deprecations []ConfigDeprecation
+}
+
+func newConfigTree(delim string) *configTree {
+	if delim == "" {
+		delim = "."
+	}
+	return &configTree{delim: delim, values: make(map[string]interface{}), files: make(map[string]string)}
+}
+
+// mergeFile merges the config file of @conf over the tree after the files it includes, @including are the files
+// including it, which are checked for the cycles
+func (t *configTree) mergeFile(conf *loaderConf, including ...string) error {
+	source := conf.source()
+	for i, file := range including {
+		if file == source {
+			return perrors.Errorf("include cycle: %s", strings.Join(append(including[i:], source), " -> "))
+		}
+	}
+	koan, err := newConfigResolver(conf)
+	if err != nil {
+		return perrors.WithMessagef(err, "load %s", source)
+	}
+	values := koan.Raw()
+	includes, err := includesOf(values, source)
+	if err != nil {
+		return err
+	}
+	delete(values, includeKey)
+	for _, deprecation := range MigrateConfig(values) {
+		deprecation.File = source
+		t.deprecations = append(t.deprecations, deprecation)
+	}
+	for _, include := range includes {
+		if conf.file == "" {
+			return perrors.Errorf("%s: %s %s, only the config files could include the others", source, includeKey, include)
+		}
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(conf.file), path)
+		}
+		if !pathExists(path) {
+			return perrors.Errorf("%s: %s %s, the file is absent", source, includeKey, include)
+		}
+		logger.Debugf("Merge the config file %s included by %s", path, source)
+		included := NewLoaderConf(WithPath(path))
+		included.placeholders = conf.placeholders
+		if err = t.mergeFile(included, append(including, source)...); err != nil {
+			return err
+		}
+	}
+	return t.merge(source, values)
+}
+
+// includesOf returns the files included by the config @values of @source
+func includesOf(values map[string]interface{}, source string) ([]string, error) {
+	switch include := values[includeKey].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{include}, nil
+	case []interface{}:
+		files := make([]string, 0, len(include))
+		for _, file := range include {
+			name, ok := file.(string)
+			if !ok || name == "" {
+				return nil, perrors.Errorf("%s: %s should be a list of the files, got %v", source, includeKey, file)
+			}
+			files = append(files, name)
+		}
+		return files, nil
+	default:
+		return nil, perrors.Errorf("%s: %s should be a list of the files, got %v", source, includeKey, include)
+	}
+}
+
+// merge merges the nested @values of the config @file over the tree
+func (t *configTree) merge(file string, values map[string]interface{}) error {
+	return t.mergeMap(file, "", t.values, values)
+}
+
+func (t *configTree) mergeMap(file, path string, dst, src map[string]interface{}) error {
+	for key, value := range src {
+		keyPath := key
+		if path != "" {
+			keyPath = path + t.delim + key
+		}
+		merged, err := t.mergeValue(file, keyPath, dst[key], value)
+		if err != nil {
+			return err
+		}
+		dst[key] = merged
+	}
+	return nil
+}
+
+// mergeValue returns @src of the config @file merged over @dst of the key @path
+func (t *configTree) mergeValue(file, path string, dst, src interface{}) (interface{}, error) {
+	if src == nil {
+		if dst == nil {
+			t.files[path] = file
+		}
+		return dst, nil
+	}
+	srcMap, srcIsMap := toStringMap(src)
+	dstMap, dstIsMap := toStringMap(dst)
+	switch {
+	case srcIsMap && dstIsMap:
+		return dstMap, t.mergeMap(file, path, dstMap, srcMap)
+	case srcIsMap && dst != nil, dstIsMap:
+		return nil, perrors.Errorf("%s: %s is %s, which can't be merged with %s of %s", file, path, kindOf(src),
+			kindOf(dst), t.fileOf(path))
+	case srcIsMap:
+		merged := make(map[string]interface{}, len(srcMap))
+		return merged, t.mergeMap(file, path, merged, srcMap)
+	}
+	t.files[path] = file
+	if srcList, ok := src.([]interface{}); ok {
+		if dstList, ok := dst.([]interface{}); ok {
+			if identity := identityOf(dstList, srcList); identity != "" {
+				return t.mergeList(file, path, identity, dstList, srcList)
+			}
+		}
+	}
+	return src, nil
+}
+
+// mergeList merges the elements of @src into @dst by the @identity key
+func (t *configTree) mergeList(file, path, identity string, dst, src []interface{}) (interface{}, error) {
+	merged := make([]interface{}, len(dst))
+	index := make(map[interface{}]int, len(dst))
+	for i, element := range dst {
+		dstElement, _ := toStringMap(element)
+		copied := make(map[string]interface{}, len(dstElement))
+		for k, v := range dstElement {
+			copied[k] = v
+		}
+		merged[i] = copied
+		index[fmt.Sprint(dstElement[identity])] = i
+	}
+	for _, element := range src {
+		srcElement, _ := toStringMap(element)
+		id := fmt.Sprint(srcElement[identity])
+		i, ok := index[id]
+		if !ok {
+			merged = append(merged, srcElement)
+			continue
+		}
+		elementPath := fmt.Sprintf("%s[%s=%s]", path, identity, id)
+		if err := t.mergeMap(file, elementPath, merged[i].(map[string]interface{}), srcElement); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// identityOf returns the key identifying every element of the lists @dst and @src uniquely, empty if there is none
+func identityOf(dst, src []interface{}) string {
+	if len(dst) == 0 || len(src) == 0 {
+		return ""
+	}
+next:
+	for _, identity := range identityKeys {
+		for _, list := range [][]interface{}{dst, src} {
+			seen := make(map[string]bool, len(list))
+			for _, element := range list {
+				m, ok := toStringMap(element)
+				if !ok || m[identity] == nil {
+					continue next
+				}
+				id := fmt.Sprint(m[identity])
+				if seen[id] {
+					continue next
+				}
+				seen[id] = true
+			}
+		}
+		return identity
+	}
+	return ""
+}
+
+// fileOf returns the file the value of @path, or the values under it come from
+func (t *configTree) fileOf(path string) string {
+	if file, ok := t.files[path]; ok {
+		return file
+	}
+	for key, file := range t.files {
+		if strings.HasPrefix(key, path+t.delim) {
+			return file
+		}
+	}
+	return "the config merged before"
+}
+
+// koanf returns the resolver of the merged values
+func (t *configTree) koanf() (*koanf.Koanf, error) {
+	koan := koanf.New(t.delim)
+	if err := koan.Load(confmap.Provider(t.values, ""), nil); err != nil {
+		return nil, err
+	}
+	return koan, nil
+}
+
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			result[fmt.Sprint(k)] = v
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "a map"
+	case []interface{}:
+		return "a list"
+	default:
+		return fmt.Sprintf("the value %v", value)
+	}
+}
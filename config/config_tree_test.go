@@ -0,0 +1,287 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestConfigTreeMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		merged   string
+		files    map[string]string
+		err      string
+	}{
+		{
+			name:     "named maps merged by id",
+			base:     "registries:\n  zk:\n    address: zk:2181\n    timeout: 3s\n  nacos:\n    address: nacos:8848\n",
+			override: "registries:\n  zk:\n    timeout: 10s\n  etcd:\n    address: etcd:2379\n",
+			merged: "registries:\n  zk:\n    address: zk:2181\n    timeout: 10s\n  nacos:\n    address: nacos:8848\n" +
+				"  etcd:\n    address: etcd:2379\n",
+			files: map[string]string{
+				"registries.zk.address":    "base",
+				"registries.zk.timeout":    "override",
+				"registries.nacos.address": "base",
+				"registries.etcd.address":  "override",
+			},
+		},
+		{
+			name:     "nested maps merged key by key",
+			base:     "provider:\n  services:\n    greeter:\n      interface: Greeter\n      params:\n        a: 1\n",
+			override: "provider:\n  services:\n    greeter:\n      params:\n        b: 2\n",
+			merged:   "provider:\n  services:\n    greeter:\n      interface: Greeter\n      params:\n        a: 1\n        b: 2\n",
+			files: map[string]string{
+				"provider.services.greeter.interface": "base",
+				"provider.services.greeter.params.a":  "base",
+				"provider.services.greeter.params.b":  "override",
+			},
+		},
+		{
+			name:     "lists merged by name",
+			base:     "methods:\n  - name: a\n    retries: 1\n  - name: b\n    retries: 2\n",
+			override: "methods:\n  - name: a\n    timeout: 1s\n  - name: c\n",
+			merged: "methods:\n  - name: a\n    retries: 1\n    timeout: 1s\n  - name: b\n    retries: 2\n" +
+				"  - name: c\n",
+			files: map[string]string{"methods": "override"},
+		},
+		{
+			name:     "lists merged by id over name",
+			base:     "routers:\n  - id: r1\n    name: x\n    force: true\n",
+			override: "routers:\n  - id: r1\n    name: y\n",
+			merged:   "routers:\n  - id: r1\n    name: y\n    force: true\n",
+		},
+		{
+			name:     "lists of scalars replaced",
+			base:     "registry-ids: [nacos, zk]\n",
+			override: "registry-ids: [zk]\n",
+			merged:   "registry-ids: [zk]\n",
+			files:    map[string]string{"registry-ids": "override"},
+		},
+		{
+			name:     "lists without the identity of every element replaced",
+			base:     "methods:\n  - name: a\n    retries: 1\n",
+			override: "methods:\n  - retries: 2\n",
+			merged:   "methods:\n  - retries: 2\n",
+		},
+		{
+			name:     "lists of the duplicated identities replaced",
+			base:     "methods:\n  - name: a\n    retries: 1\n",
+			override: "methods:\n  - name: a\n    retries: 2\n  - name: a\n    retries: 3\n",
+			merged:   "methods:\n  - name: a\n    retries: 2\n  - name: a\n    retries: 3\n",
+		},
+		{
+			name:     "empty value replaces nothing",
+			base:     "logger:\n  level: info\napplication:\n  name: app\n",
+			override: "logger:\napplication:\n  name:\n",
+			merged:   "logger:\n  level: info\napplication:\n  name: app\n",
+			files:    map[string]string{"logger.level": "base", "application.name": "base"},
+		},
+		{
+			name:     "map replaces empty value",
+			base:     "logger:\n",
+			override: "logger:\n  level: warn\n",
+			merged:   "logger:\n  level: warn\n",
+			files:    map[string]string{"logger.level": "override"},
+		},
+		{
+			name:     "scalar over map",
+			base:     "registries:\n  zk:\n    address: zk:2181\n",
+			override: "registries: zk\n",
+			err:      "override: registries is the value zk, which can't be merged with a map of base",
+		},
+		{
+			name:     "map over scalar",
+			base:     "application:\n  name: app\n",
+			override: "application:\n  name:\n    first: app\n",
+			err:      "override: application.name is a map, which can't be merged with the value app of base",
+		},
+		{
+			name:     "map over list",
+			base:     "methods:\n  - name: a\n",
+			override: "methods:\n  - name: a\n    params:\n      x: 1\n",
+			merged:   "methods:\n  - name: a\n    params:\n      x: 1\n",
+		},
+		{
+			name:     "scalar over map in the element",
+			base:     "methods:\n  - name: a\n    params:\n      x: 1\n",
+			override: "methods:\n  - name: a\n    params: x\n",
+			err:      "override: methods[name=a].params is the value x, which can't be merged with a map of",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := newConfigTree(".")
+			require.NoError(t, tree.merge("base", parseYAML(t, test.base)))
+			err := tree.merge("override", parseYAML(t, test.override))
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			require.NoError(t, err)
+			koan, err := tree.koanf()
+			require.NoError(t, err)
+			expected := parseYAML(t, test.merged)
+			assert.True(t, reflect.DeepEqual(flatten(t, expected), koan.All()),
+				"expected %v, actual %v", flatten(t, expected), koan.All())
+			for key, file := range test.files {
+				assert.Equal(t, file, tree.files[key], key)
+			}
+		})
+	}
+}
+
+func parseYAML(t *testing.T, content string) map[string]interface{} {
+	values, err := parseConfig("yaml", content)
+	require.NoError(t, err)
+	tree := newConfigTree(".")
+	for key, value := range values {
+		setNested(tree.values, key, value)
+	}
+	return tree.values
+}
+
+func setNested(values map[string]interface{}, key string, value interface{}) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '.' {
+			child, ok := values[key[:i]].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				values[key[:i]] = child
+			}
+			setNested(child, key[i+1:], value)
+			return
+		}
+	}
+	values[key] = value
+}
+
+func flatten(t *testing.T, values map[string]interface{}) map[string]interface{} {
+	tree := newConfigTree(".")
+	tree.values = values
+	koan, err := tree.koanf()
+	require.NoError(t, err)
+	return koan.All()
+}
+
+func TestLoadProfilesWithIncludes(t *testing.T) {
+	dir, err := filepath.Abs("./testdata/config/include")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "dubbogo.yaml")
+
+	t.Run("base with includes", func(t *testing.T) {
+		rc := newEmptyRootConfig()
+		_, files, err := loadConfigFiles(NewLoaderConf(WithPath(path)), rc)
+		require.NoError(t, err)
+		// the including file overrides the included one
+		assert.Equal(t, "include-app", rc.Application.Name)
+		assert.Equal(t, "shared-org", rc.Application.Organization)
+		assert.Equal(t, "zookeeper://127.0.0.1:2181", rc.Registries["zk"].Address)
+		assert.Equal(t, "3s", rc.Registries["zk"].Timeout)
+		assert.Equal(t, "nacos://127.0.0.1:8848", rc.Registries["nacos"].Address)
+		assert.Equal(t, path, files["dubbo.registries.zk.address"])
+		assert.Equal(t, filepath.Join(dir, "common.yaml"), files["dubbo.registries.zk.timeout"])
+		assert.Equal(t, "default", rc.Profiles.Active)
+	})
+
+	t.Run("active profile by api", func(t *testing.T) {
+		require.NoError(t, os.Setenv(constant.ProfilesActiveEnvKey, "prod"))
+		defer os.Unsetenv(constant.ProfilesActiveEnvKey)
+		rc := newEmptyRootConfig()
+		koan, files, err := loadConfigFiles(NewLoaderConf(WithPath(path), WithActiveProfiles("dev")), rc)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", rc.Profiles.Active)
+		// the registries and the protocols are merged by id
+		assert.Equal(t, "zookeeper://127.0.0.1:2181", rc.Registries["zk"].Address)
+		assert.Equal(t, "10s", rc.Registries["zk"].Timeout)
+		assert.Equal(t, "nacos://127.0.0.1:8848", rc.Registries["nacos"].Address)
+		assert.Equal(t, "20000", rc.Protocols["dubbo"].Port)
+		assert.Equal(t, "20001", rc.Protocols["tri"].Port)
+		// the profile includes the fragments as well
+		assert.Equal(t, "dev-team", rc.Application.Owner)
+		// the methods are merged by name
+		methods := rc.Provider.Services["GreeterProvider"].Methods
+		require.Len(t, methods, 3)
+		assert.Equal(t, "SayHello", methods[0].Name)
+		assert.Equal(t, "1", methods[0].Retries)
+		assert.Equal(t, "1s", methods[0].RequestTimeout)
+		assert.Equal(t, "SayBye", methods[1].Name)
+		assert.Equal(t, "SayHi", methods[2].Name)
+
+		sources := newConfigSources(koan)
+		sources.setFiles(files)
+		effective := sources.effective()
+		assert.Equal(t, EffectiveValue{Value: "10s", Source: SourceLocal, File: filepath.Join(dir, "dubbogo-dev.yaml")},
+			effective["dubbo.registries.zk.timeout"])
+		assert.Equal(t, filepath.Join(dir, "fragments", "dev.yaml"), effective["dubbo.application.owner"].File)
+		assert.Equal(t, filepath.Join(dir, "common.yaml"), effective["dubbo.application.organization"].File)
+
+		// the dump shows the file of each value
+		d := &configDumper{sources: effective}
+		node := d.dump(rc.Prefix(), reflect.ValueOf(rc), "").(map[string]interface{})
+		zk := node["registries"].(map[string]interface{})["zk"].(map[string]interface{})
+		assert.Equal(t, filepath.Join(dir, "dubbogo-dev.yaml"), zk["timeout"].(EffectiveValue).File)
+		assert.Equal(t, path, zk["address"].(EffectiveValue).File)
+	})
+
+	t.Run("active profiles by env in order", func(t *testing.T) {
+		require.NoError(t, os.Setenv(constant.ProfilesActiveEnvKey, "dev, prod"))
+		defer os.Unsetenv(constant.ProfilesActiveEnvKey)
+		rc := newEmptyRootConfig()
+		_, files, err := loadConfigFiles(NewLoaderConf(WithPath(path)), rc)
+		require.NoError(t, err)
+		assert.Equal(t, "dev,prod", rc.Profiles.Active)
+		assert.Equal(t, "zookeeper://prod:2181", rc.Registries["zk"].Address)
+		assert.Equal(t, "10s", rc.Registries["zk"].Timeout)
+		assert.Equal(t, filepath.Join(dir, "dubbogo-prod.yaml"), files["dubbo.registries.zk.address"])
+	})
+
+	t.Run("include cycle", func(t *testing.T) {
+		a, b := filepath.Join(dir, "cycle", "a.yaml"), filepath.Join(dir, "cycle", "b.yaml")
+		_, _, err := loadConfigFiles(NewLoaderConf(WithPath(a)), newEmptyRootConfig())
+		require.Error(t, err)
+		assert.Equal(t, "include cycle: "+a+" -> "+b+" -> "+a, err.Error())
+	})
+
+	t.Run("include absent", func(t *testing.T) {
+		_, _, err := loadConfigFiles(NewLoaderConf(WithPath(filepath.Join(dir, "absent.yaml"))), newEmptyRootConfig())
+		require.Error(t, err)
+		assert.Equal(t, filepath.Join(dir, "absent.yaml")+": include absent-common.yaml, the file is absent", err.Error())
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, _, err := loadConfigFiles(NewLoaderConf(WithPath(filepath.Join(dir, "conflict.yaml"))), newEmptyRootConfig())
+		require.Error(t, err)
+		assert.Equal(t, filepath.Join(dir, "conflict.yaml")+": dubbo.registries is the value zk, which can't be merged "+
+			"with a map of "+filepath.Join(dir, "common.yaml"), err.Error())
+	})
+}
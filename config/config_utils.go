@@ -18,8 +18,8 @@
 package config
 
 import (
-	"fmt"
-	"regexp"
+	"net/url"
+	"sort"
 	"strings"
 )
 
@@ -30,8 +30,10 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
 )
 
 var validate *validator.Validate
@@ -40,41 +42,82 @@ func init() {
 	validate = validator.New()
 }
 
-func mergeValue(str1, str2, def string) string {
-	if str1 == "" && str2 == "" {
-		return def
+// checkFilters checks the filter specification @spec and whether the filters named by it are registered, the default
+// filters are registered by the imports of the framework
+func checkFilters(spec string) error {
+	configured, err := protocolwrapper.ResolveFilters(spec, nil)
+	if err != nil {
+		return err
 	}
-	s1 := strings.Split(str1, ",")
-	s2 := strings.Split(str2, ",")
-	str := "," + strings.Join(append(s1, s2...), ",")
-	defKey := strings.Contains(str, ","+constant.DefaultKey)
-	if !defKey {
-		str = "," + constant.DefaultKey + str
-	}
-	str = strings.TrimPrefix(strings.Replace(str, ","+constant.DefaultKey, ","+def, -1), ",")
-	return removeMinus(strings.Split(str, ","))
+	return protocolwrapper.CheckFilters(configured)
+}
+
+// durationParams are the names of the duration params, which are either the durations like 3s or the bare
+// milliseconds like 3000 in the configs, see common.ParseDuration
+var durationParams = map[string]bool{
+	constant.TimeoutKey:                  true,
+	constant.RetryBackoffKey:             true,
+	constant.CacheTTLKey:                 true,
+	constant.ExecuteLimitQueueTimeoutKey: true,
+	constant.TPSLimitIntervalKey:         true,
 }
 
-func removeMinus(strArr []string) string {
-	if len(strArr) == 0 {
-		return ""
+// paramName returns the name of the url param @key without the prefix of the method or the registry, e.g. timeout
+// of methods.getUser.timeout
+func paramName(key string) string {
+	if strings.HasPrefix(key, constant.MethodKeys+".") {
+		method := strings.TrimPrefix(key, constant.MethodKeys+".")
+		if i := strings.IndexByte(method, '.'); i >= 0 {
+			return method[i+1:]
+		}
+		return key
 	}
-	var normalStr string
-	var minusStrArr []string
-	for _, v := range strArr {
-		if strings.HasPrefix(v, "-") {
-			minusStrArr = append(minusStrArr, v[1:])
-		} else {
-			normalStr += fmt.Sprintf(",%s", v)
+	return strings.TrimPrefix(key, constant.RegistryKey+".")
+}
+
+// normalizeDurations converts the duration params of the url @params to the bare milliseconds which dubbo java reads,
+// e.g. timeout=3s to timeout=3000, the invalid ones are kept as is and reported by checkParams
+func normalizeDurations(params url.Values) {
+	for key, values := range params {
+		if !durationParams[paramName(key)] || len(values) == 0 {
+			continue
+		}
+		if normalized, err := common.NormalizeDuration(values[0]); err == nil {
+			params.Set(key, normalized)
 		}
 	}
-	normalStr = strings.Trim(normalStr, ",")
-	for _, v := range minusStrArr {
-		normalStr = strings.Replace(normalStr, v, "", 1)
+}
+
+// checkParams checks the durations like the timeouts, the retries and the weights of the url @params of a service or
+// a reference, including the method level ones like methods.getUser.timeout, so that the invalid values fail at the
+// export or the refer instead of being replaced by the defaults silently at the invocations
+func checkParams(params url.Values) error {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	u := common.NewURLWithOptions(common.WithParams(params))
+	for _, key := range keys {
+		name := paramName(key)
+		if durationParams[name] {
+			if _, err := u.GetParamDuration(key); err != nil {
+				return err
+			}
+			continue
+		}
+		switch name {
+		case constant.RetriesKey, constant.WeightKey:
+			n, err := u.GetParamInt64Strict(key, 0)
+			if err != nil {
+				return err
+			}
+			if n < 0 {
+				return errors.Errorf("invalid %s=%d, it should be non-negative", key, n)
+			}
+		}
 	}
-	reg := regexp.MustCompile("[,]+")
-	normalStr = reg.ReplaceAllString(strings.Trim(normalStr, ","), ",")
-	return normalStr
+	return nil
 }
 
 // removeDuplicateElement remove duplicate element
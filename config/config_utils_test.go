@@ -18,49 +18,19 @@
 package config
 
 import (
+	"net/url"
 	"testing"
+	"time"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestMergeValue(t *testing.T) {
-	str := mergeValue("", "", "a,b")
-	assert.Equal(t, "a,b", str)
-
-	str = mergeValue("c,d", "e,f", "a,b")
-	assert.Equal(t, "a,b,c,d,e,f", str)
-
-	str = mergeValue("c,d", "e,d,f", "a,b")
-	assert.Equal(t, "a,b,c,d,e,d,f", str)
-
-	str = mergeValue("c,default,d", "-c,-a,e,f", "a,b")
-	assert.Equal(t, "b,d,e,f", str)
-
-	str = mergeValue("", "default,-b,e,f", "a,b")
-	assert.Equal(t, "a,e,f", str)
-}
-
-func TestRemoveMinus(t *testing.T) {
-	strList := removeMinus([]string{})
-	assert.Equal(t, strList, "")
-
-	strList = removeMinus([]string{"a", "b", "c", "d", "-a"})
-	assert.Equal(t, strList, "b,c,d")
-
-	strList = removeMinus([]string{"a", "b", "c", "d", "-a", "-b"})
-	assert.Equal(t, strList, "c,d")
-
-	strList = removeMinus([]string{"a", "b", "c", "-c", "-a", "-b"})
-	assert.Equal(t, strList, "")
-
-	strList = removeMinus([]string{"b", "a", "-c", "c"})
-	assert.Equal(t, strList, "b,a")
-
-	strList = removeMinus([]string{"c", "b", "a", "d", "c", "-c", "-a", "e", "f"})
-	assert.Equal(t, strList, "b,d,c,e,f")
-}
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
 
 type mockConfig struct {
 	protocol string
@@ -81,3 +51,90 @@ func TestClientNameID(t *testing.T) {
 		assert.Equal(t, "mock--", clientNameID(m, m.protocol, m.address))
 	})
 }
+
+func TestCheckParams(t *testing.T) {
+	valid := url.Values{}
+	valid.Set(constant.TimeoutKey, "3s")
+	valid.Set("methods.GetUser."+constant.TimeoutKey, "3000")
+	valid.Set(constant.RetriesKey, "2")
+	valid.Set("methods.GetUser."+constant.RetriesKey, "")
+	valid.Set(constant.RegistryKey+"."+constant.WeightKey, "100")
+	valid.Set(constant.ExecuteLimitQueueTimeoutKey, "200ms")
+	assert.NoError(t, checkParams(valid))
+
+	tests := []struct {
+		key   string
+		value string
+		err   string
+	}{
+		{constant.TimeoutKey, "3 seconds", "invalid timeout=3 seconds"},
+		{"methods.GetUser." + constant.TimeoutKey, "3ss", "invalid methods.GetUser.timeout=3ss"},
+		{constant.RetriesKey, "2s", "invalid retries=2s, it should be an integer"},
+		{constant.RetriesKey, "-1", "invalid retries=-1, it should be non-negative"},
+		{"methods.GetUser." + constant.WeightKey, "heavy", "invalid methods.GetUser.weight=heavy"},
+		{constant.ExecuteLimitQueueTimeoutKey, "whatever", "invalid execute.limit.queue.timeout=whatever"},
+		{"methods.GetUser." + constant.TPSLimitIntervalKey, "1 minute", "invalid methods.GetUser.tps.limit.interval=1 minute"},
+	}
+	for _, test := range tests {
+		params := url.Values{}
+		params.Set(test.key, test.value)
+		err := checkParams(params)
+		if assert.Error(t, err, test.key) {
+			assert.Contains(t, err.Error(), test.err)
+		}
+	}
+}
+
+// TestDurationCompatibility checks that the durations keep their meaning in both the go form and the milliseconds of
+// dubbo java, and are written to the urls as the milliseconds
+func TestDurationCompatibility(t *testing.T) {
+	tests := []struct {
+		value    string
+		param    string
+		expected time.Duration
+	}{
+		{"3s", "3000", 3 * time.Second},
+		{"500ms", "500", 500 * time.Millisecond},
+		{"1m", "60000", time.Minute},
+		{"1m30s", "90000", 90 * time.Second},
+		{"3000", "3000", 3 * time.Second},
+		{"0", "0", 0},
+	}
+	for _, test := range tests {
+		reference := NewReferenceConfigBuilder().
+			SetInterface("org.apache.dubbo.HelloService").
+			SetRequestTimeout(test.value).
+			AddMethodConfig(&MethodConfig{Name: "getUser", RequestTimeout: test.value, RetryBackoff: test.value,
+				CacheTTL: test.value}).
+			Build()
+		reference.rootConfig = NewRootConfigBuilder().Build()
+		service := NewServiceConfigBuilder().SetInterface("org.apache.dubbo.HelloService").Build()
+		service.ExecuteLimitQueueTimeout = test.value
+		service.TpsLimitInterval = test.value
+		service.Methods = []*MethodConfig{{Name: "getUser", RequestTimeout: test.value}}
+
+		for _, params := range []url.Values{reference.getURLMap(), service.getUrlMap()} {
+			assert.NoError(t, checkParams(params), test.value)
+			u := common.NewURLWithOptions(common.WithParams(params))
+			for _, key := range []string{constant.TimeoutKey, "methods.getUser." + constant.TimeoutKey,
+				"methods.getUser." + constant.RetryBackoffKey, "methods.getUser." + constant.CacheTTLKey,
+				constant.ExecuteLimitQueueTimeoutKey, constant.TPSLimitIntervalKey} {
+				if _, ok := params[key]; !ok {
+					continue
+				}
+				assert.Equal(t, test.param, u.GetParam(key, ""), key+"="+test.value)
+				d, err := u.GetParamDuration(key)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, d, key+"="+test.value)
+			}
+			assert.Equal(t, test.expected, u.GetMethodParamDuration("getUser", constant.TimeoutKey, -1), test.value)
+		}
+	}
+
+	// the invalid ones are kept, and reported
+	reference := NewReferenceConfigBuilder().SetInterface("org.apache.dubbo.HelloService").SetRequestTimeout("3 seconds").Build()
+	reference.rootConfig = NewRootConfigBuilder().Build()
+	params := reference.getURLMap()
+	assert.Equal(t, "3 seconds", params.Get(constant.TimeoutKey))
+	assert.Error(t, checkParams(params))
+}
@@ -0,0 +1,400 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/dustin/go-humanize"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// Validate checks the config loaded by @opts the same as Load, but it neither initializes nor starts anything, so
+// the tooling could call it freely. All the problems found are reported at once by the returned error.
+func Validate(opts ...LoaderConfOption) error {
+	conf := NewLoaderConf(opts...)
+	rc := conf.rc
+	if rc == nil {
+		rc = NewRootConfigBuilder().Build()
+		if _, _, err := loadConfigFiles(conf, rc); err != nil {
+			return err
+		}
+	}
+	return rc.validate()
+}
+
+// configProblems are the problems of the config with the paths of the yaml
+type configProblems []string
+
+func (p *configProblems) add(path string, format string, args ...interface{}) {
+	*p = append(*p, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (p configProblems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return perrors.Errorf("invalid config, %d problem(s) found:\n\t%s", len(p), strings.Join(p, "\n\t"))
+}
+
+// validate checks the config before initializing, see also Validate. The empty values are not checked since they
+// take the defaults.
+func (rc *RootConfig) validate() error {
+	var problems configProblems
+	protocolIDs := rc.validateProtocols(&problems)
+	registryIDs := rc.validateRegistries(&problems)
+	if rc.Provider != nil {
+		rc.Provider.validate(rc, protocolIDs, registryIDs, &problems)
+	}
+	if rc.Consumer != nil {
+		rc.Consumer.validate(rc, registryIDs, &problems)
+	}
+	if rc.Shutdown != nil {
+		path := constant.ShutdownConfigPrefix
+		validateDuration(path+".timeout", rc.Shutdown.Timeout, &problems)
+		validateDuration(path+".step-timeout", rc.Shutdown.StepTimeout, &problems)
+		validateDuration(path+".consumer-update-wait-time", rc.Shutdown.ConsumerUpdateWaitTime, &problems)
+		validateDuration(path+".offline-request-window-timeout", rc.Shutdown.OfflineRequestWindowTimeout, &problems)
+	}
+	return problems.err()
+}
+
+// validateProtocols validates the protocols and returns their ids, the dubbo protocol is used if there is none
+func (rc *RootConfig) validateProtocols(problems *configProblems) map[string]bool {
+	if len(rc.Protocols) == 0 {
+		return map[string]bool{constant.Dubbo: true}
+	}
+	ids := make(map[string]bool, len(rc.Protocols))
+	for _, id := range sortedKeys(rc.Protocols) {
+		ids[id] = true
+		protocol := rc.Protocols[id]
+		if protocol == nil {
+			continue
+		}
+		path := "dubbo.protocols." + id
+		validateExtension(path+".name", extension.KindProtocol, protocol.Name, problems)
+		if protocol.Port != "" {
+			if port, err := strconv.Atoi(protocol.Port); err != nil || port < 0 || port > 65535 {
+				problems.add(path+".port", "invalid port %q, it should be a number in [0, 65535]", protocol.Port)
+			}
+		}
+		validateTransport(path+".transport", protocol.Transport, problems)
+		if t := protocol.Transport; t != nil && (t.WebsocketPort != "" || t.Websocket) &&
+			protocol.Name != "" && protocol.Name != constant.Dubbo {
+			// the websocket is served and dialed by getty, which the other protocols like triple don't use
+			problems.add(path+".transport", "the websocket transport is only supported by the dubbo protocol, not %q",
+				protocol.Name)
+		}
+		validateInboundLimit(path+".inbound-limit", protocol.InboundLimit, problems)
+	}
+	return ids
+}
+
+// validateRegistries validates the registries and returns their ids
+func (rc *RootConfig) validateRegistries(problems *configProblems) map[string]bool {
+	ids := make(map[string]bool, len(rc.Registries))
+	for _, id := range sortedKeys(rc.Registries) {
+		ids[id] = true
+		registry := rc.Registries[id]
+		if registry == nil {
+			continue
+		}
+		path := constant.RegistryConfigPrefix + "." + id
+		validateDuration(path+".timeout", registry.Timeout, problems)
+		if registry.Address == "" {
+			problems.add(path+".address", "the address is required, set it to %s to disable the registry",
+				constant.NotAvailable)
+			continue
+		}
+		if !isValid(registry.Address) {
+			continue
+		}
+		protocol := registry.Protocol
+		if strings.Contains(registry.Address, "://") {
+			u, err := url.Parse(registry.Address)
+			if err != nil {
+				problems.add(path+".address", "invalid address %q, %v", registry.Address, err)
+				continue
+			}
+			protocol = u.Scheme
+		}
+		if protocol == "" {
+			problems.add(path+".protocol", "the protocol is required, e.g. zookeeper, nacos")
+			continue
+		}
+		switch registry.RegistryType {
+		case constant.RegistryTypeInterface:
+			validateExtension(path+".protocol", extension.KindRegistry, protocol, problems)
+		case constant.RegistryTypeAll:
+			validateExtension(path+".protocol", extension.KindRegistry, protocol, problems)
+			validateExtension(path+".protocol", extension.KindServiceDiscovery, protocol, problems)
+		case constant.RegistryTypeService, "":
+			validateExtension(path+".protocol", extension.KindServiceDiscovery, protocol, problems)
+		default:
+			problems.add(path+".registry-type", "unknown registry type %q, it should be one of %s, %s and %s",
+				registry.RegistryType, constant.RegistryTypeService, constant.RegistryTypeInterface, constant.RegistryTypeAll)
+		}
+	}
+	return ids
+}
+
+func (c *ProviderConfig) validate(rc *RootConfig, protocolIDs, registryIDs map[string]bool, problems *configProblems) {
+	path := constant.ProviderConfigPrefix
+	validateFilters(path+".filter", c.Filter, problems)
+	validateIDs(path+".registry-ids", "registry", c.RegistryIDs, registryIDs, problems)
+	validateIDs(path+".protocol-ids", "protocol", c.ProtocolIDs, protocolIDs, problems)
+	serviceKeys := make(map[string]string, len(c.Services))
+	for _, key := range sortedKeys(c.Services) {
+		service := c.Services[key]
+		if service == nil {
+			continue
+		}
+		servicePath := path + ".services." + key
+		validateIDs(servicePath+".registry-ids", "registry", service.RegistryIDs, registryIDs, problems)
+		validateIDs(servicePath+".protocol-ids", "protocol", service.ProtocolIDs, protocolIDs, problems)
+		validateExtension(servicePath+".cluster", extension.KindCluster, service.Cluster, problems)
+		validateExtension(servicePath+".loadbalance", extension.KindLoadbalance, service.Loadbalance, problems)
+		validateFilters(servicePath+".filter", service.Filter, problems)
+		validateDuration(servicePath+".tps.limit.interval", service.TpsLimitInterval, problems)
+		validateDuration(servicePath+".execute.limit.queue.timeout", service.ExecuteLimitQueueTimeout, problems)
+		validateMethods(servicePath, service.Methods, problems)
+
+		interfaceName := service.Interface
+		if pb, ok := GetProviderService(key).(common.TriplePBService); interfaceName == "" && ok {
+			interfaceName = pb.XXX_InterfaceName()
+		}
+		if interfaceName == "" {
+			problems.add(servicePath+".interface", "the interface is required")
+			continue
+		}
+		serviceKey := common.ServiceKey(interfaceName, orDefault(service.Group, rc.Application.Group),
+			orDefault(service.Version, rc.Application.Version))
+		if duplicated, ok := serviceKeys[serviceKey]; ok {
+			problems.add(servicePath, "the service %s is duplicated with %s.services.%s", serviceKey, path, duplicated)
+			continue
+		}
+		serviceKeys[serviceKey] = key
+	}
+}
+
+func (cc *ConsumerConfig) validate(rc *RootConfig, registryIDs map[string]bool, problems *configProblems) {
+	path := constant.ConsumerConfigPrefix
+	validateFilters(path+".filter", cc.Filter, problems)
+	validateIDs(path+".registry-ids", "registry", cc.RegistryIDs, registryIDs, problems)
+	validateExtension(path+".protocol", extension.KindProtocol, cc.Protocol, problems)
+	validateDuration(path+".request-timeout", cc.RequestTimeout, problems)
+	validateTransport(path+".transport", cc.Transport, problems)
+	referenceKeys := make(map[string]string, len(cc.References))
+	for _, key := range sortedKeys(cc.References) {
+		reference := cc.References[key]
+		if reference == nil {
+			continue
+		}
+		referencePath := path + ".references." + key
+		validateIDs(referencePath+".registry-ids", "registry", reference.RegistryIDs, registryIDs, problems)
+		validateExtension(referencePath+".protocol", extension.KindProtocol, reference.Protocol, problems)
+		validateExtension(referencePath+".cluster", extension.KindCluster, reference.Cluster, problems)
+		validateExtension(referencePath+".loadbalance", extension.KindLoadbalance, reference.Loadbalance, problems)
+		validateFilters(referencePath+".filter", reference.Filter, problems)
+		validateDuration(referencePath+".timeout", reference.RequestTimeout, problems)
+		validateMethods(referencePath, reference.Methods, problems)
+
+		interfaceName := reference.InterfaceName
+		if pb, ok := GetConsumerService(key).(common.TriplePBService); interfaceName == "" && ok {
+			interfaceName = pb.XXX_InterfaceName()
+		}
+		if interfaceName == "" {
+			problems.add(referencePath+".interface", "the interface is required")
+			continue
+		}
+		referenceKey := common.ServiceKey(interfaceName, orDefault(reference.Group, rc.Application.Group),
+			orDefault(reference.Version, rc.Application.Version))
+		if duplicated, ok := referenceKeys[referenceKey]; ok {
+			problems.add(referencePath, "the reference of %s is duplicated with %s.references.%s", referenceKey, path, duplicated)
+			continue
+		}
+		referenceKeys[referenceKey] = key
+	}
+}
+
+func validateMethods(path string, methods []*MethodConfig, problems *configProblems) {
+	for i, method := range methods {
+		if method == nil {
+			continue
+		}
+		methodPath := fmt.Sprintf("%s.methods[%d]", path, i)
+		if method.Name == "" {
+			problems.add(methodPath+".name", "the name is required")
+		}
+		validateExtension(methodPath+".loadbalance", extension.KindLoadbalance, method.LoadBalance, problems)
+		validateDuration(methodPath+".timeout", method.RequestTimeout, problems)
+		validateDuration(methodPath+".tps.limit.interval", method.TpsLimitInterval, problems)
+		validateDuration(methodPath+".execute.limit.queue.timeout", method.ExecuteLimitQueueTimeout, problems)
+		validateDuration(methodPath+".retry.backoff", method.RetryBackoff, problems)
+		validateDuration(methodPath+".cache.ttl", method.CacheTTL, problems)
+	}
+}
+
+// validateExtension checks whether the extension @name of @kind is registered, the empty name takes the default
+func validateExtension(path, kind, name string, problems *configProblems) {
+	if name == "" {
+		return
+	}
+	for _, r := range extension.GetSupported(kind) {
+		if r == name {
+			return
+		}
+	}
+	problems.add(path, "%v", extension.UnknownExtensionError(kind, name))
+}
+
+func validateFilters(path, spec string, problems *configProblems) {
+	if err := checkFilters(spec); err != nil {
+		problems.add(path, "%v", err)
+	}
+}
+
+// validateIDs checks whether @ids of @kind are configured in @configured
+func validateIDs(path, kind string, ids []string, configured map[string]bool, problems *configProblems) {
+	for _, id := range translateIds(ids) {
+		if !configured[id] {
+			problems.add(path, "the %s %q is not configured", kind, id)
+		}
+	}
+}
+
+func validateDuration(path, duration string, problems *configProblems) {
+	if duration == "" {
+		return
+	}
+	if _, err := common.ParseDuration(duration); err != nil {
+		problems.add(path, "invalid duration %q, it should be like 300ms, 3s, 1m or the milliseconds like 3000", duration)
+	}
+}
+
+func validateTransport(path string, transport *TransportConfig, problems *configProblems) {
+	if transport == nil {
+		return
+	}
+	validateMinDuration(path+".keep-alive-period", transport.KeepAlivePeriod, time.Second, problems)
+	validateMinDuration(path+".tcp-read-timeout", transport.TcpReadTimeout, time.Second, problems)
+	validateMinDuration(path+".tcp-write-timeout", transport.TcpWriteTimeout, time.Second, problems)
+	validateBufSize(path+".tcp-r-buf-size", transport.TcpRBufSize, problems)
+	validateBufSize(path+".tcp-w-buf-size", transport.TcpWBufSize, problems)
+	if transport.WebsocketPort != "" {
+		if port, err := strconv.Atoi(transport.WebsocketPort); err != nil || port <= 0 || port > 65535 {
+			problems.add(path+".websocket-port", "invalid port %q, it should be a number in [1, 65535]",
+				transport.WebsocketPort)
+		}
+	}
+	if transport.WebsocketPath != "" && !strings.HasPrefix(transport.WebsocketPath, "/") {
+		problems.add(path+".websocket-path", "invalid path %q, it should start with /", transport.WebsocketPath)
+	}
+}
+
+func validateInboundLimit(path string, limit *InboundLimitConfig, problems *configProblems) {
+	if limit == nil {
+		return
+	}
+	validateNonNegative(path+".connection-requests", limit.ConnectionRequests, problems)
+	validateNonNegative(path+".ip-requests", limit.IPRequests, problems)
+	validateNonNegative(path+".close-after-throttled", limit.CloseAfterThrottled, problems)
+	validateBytes(path+".connection-bytes", limit.ConnectionBytes, problems)
+	validateBytes(path+".ip-bytes", limit.IPBytes, problems)
+	validateDuration(path+".ip-idle-timeout", limit.IPIdleTimeout, problems)
+}
+
+func validateNonNegative(path string, n int, problems *configProblems) {
+	if n < 0 {
+		problems.add(path, "invalid number %d, it should not be negative", n)
+	}
+}
+
+func validateBytes(path, size string, problems *configProblems) {
+	if size == "" {
+		return
+	}
+	if _, err := humanize.ParseBytes(size); err != nil {
+		problems.add(path, "invalid size %q, it should be like 512kib, 10mib or the bytes like 1048576", size)
+	}
+}
+
+func validateBufSize(path string, size int, problems *configProblems) {
+	if size != 0 && (size < MinTransportBufSize || size > MaxTransportBufSize) {
+		problems.add(path, "invalid buffer size %d, it should be in [%d, %d]", size, MinTransportBufSize, MaxTransportBufSize)
+	}
+}
+
+// validateMinDuration validates the @duration like validateDuration, and it should be at least @min
+func validateMinDuration(path, duration string, min time.Duration, problems *configProblems) {
+	if duration == "" {
+		return
+	}
+	d, err := common.ParseDuration(duration)
+	if err != nil {
+		validateDuration(path, duration, problems)
+		return
+	}
+	if d < min {
+		problems.add(path, "invalid duration %q, it should be at least %s", duration, min)
+	}
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch configs := m.(type) {
+	case map[string]*ProtocolConfig:
+		for k := range configs {
+			keys = append(keys, k)
+		}
+	case map[string]*RegistryConfig:
+		for k := range configs {
+			keys = append(keys, k)
+		}
+	case map[string]*ServiceConfig:
+		for k := range configs {
+			keys = append(keys, k)
+		}
+	case map[string]*ReferenceConfig:
+		for k := range configs {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+const healthyConfig = `
+dubbo:
+  application:
+    name: greeter
+  registries:
+    zk:
+      address: zookeeper://127.0.0.1:2181
+      timeout: 5s
+    direct:
+      address: N/A
+  protocols:
+    tri:
+      name: tri
+      port: 20000
+  provider:
+    registry-ids: zk
+    services:
+      GreeterProvider:
+        interface: org.apache.dubbo.Greeter
+        loadbalance: roundrobin
+      GreeterProviderV2:
+        interface: org.apache.dubbo.Greeter
+        version: 2.0.0
+        protocol-ids: tri
+  consumer:
+    request-timeout: 3s
+    references:
+      GreeterClient:
+        interface: org.apache.dubbo.Greeter
+        protocol: tri
+        timeout: 1s
+        methods:
+          - name: SayHello
+            loadbalance: roundrobin
+`
+
+const invalidConfig = `
+dubbo:
+  registries:
+    zk:
+      address: zookeeper://127.0.0.1:2181
+      timeout: 5 seconds
+    nacos:
+      protocol: nacos
+  protocols:
+    tri:
+      name: trip
+      port: 200000
+      transport:
+        tcp-r-buf-size: 100
+        keep-alive-period: 500ms
+        websocket-port: 70000
+        websocket-path: dubbo
+      inbound-limit:
+        connection-requests: -1
+        ip-bytes: 10 megabits
+        ip-idle-timeout: 10 minutes
+  provider:
+    services:
+      GreeterProvider:
+        interface: org.apache.dubbo.Greeter
+        registry-ids: zk,etcd
+        protocol-ids: dubbo
+      GreeterProviderCopy:
+        interface: org.apache.dubbo.Greeter
+  consumer:
+    references:
+      GreeterClient:
+        loadbalance: roundrobbin
+        cluster: failfast
+`
+
+func init() {
+	// the extensions of the test configs, which are imported by the applications
+	for _, name := range []string{"zookeeper", "nacos"} {
+		extension.SetServiceDiscovery(name, func(*common.URL) (registry.ServiceDiscovery, error) {
+			return nil, nil
+		})
+	}
+	extension.SetLoadbalance("roundrobin", func() loadbalance.LoadBalance {
+		return nil
+	})
+	for _, name := range []string{"dubbo", "tri"} {
+		extension.SetProtocol(name, func() protocol.Protocol {
+			return nil
+		})
+	}
+	extension.SetCluster("failover", func() cluster.Cluster {
+		return nil
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate(WithBytes([]byte(healthyConfig))))
+
+	err := Validate(WithBytes([]byte(invalidConfig)))
+	assert.NotNil(t, err)
+	problems := strings.Split(err.Error(), "\n\t")
+	assert.Equal(t, "invalid config, 18 problem(s) found:", problems[0])
+	assert.Equal(t, []string{
+		`dubbo.protocols.tri.name: unknown protocol "trip", available: [dubbo filter tri], ` +
+			`make sure you have imported the package of it`,
+		`dubbo.protocols.tri.port: invalid port "200000", it should be a number in [0, 65535]`,
+		`dubbo.protocols.tri.transport.keep-alive-period: invalid duration "500ms", it should be at least 1s`,
+		`dubbo.protocols.tri.transport.tcp-r-buf-size: invalid buffer size 100, it should be in [1024, 67108864]`,
+		`dubbo.protocols.tri.transport.websocket-port: invalid port "70000", it should be a number in [1, 65535]`,
+		`dubbo.protocols.tri.transport.websocket-path: invalid path "dubbo", it should start with /`,
+		`dubbo.protocols.tri.transport: the websocket transport is only supported by the dubbo protocol, not "trip"`,
+		`dubbo.protocols.tri.inbound-limit.connection-requests: invalid number -1, it should not be negative`,
+		`dubbo.protocols.tri.inbound-limit.ip-bytes: invalid size "10 megabits", it should be like 512kib, 10mib ` +
+			`or the bytes like 1048576`,
+		`dubbo.protocols.tri.inbound-limit.ip-idle-timeout: invalid duration "10 minutes", it should be like 300ms, ` +
+			`3s, 1m or the milliseconds like 3000`,
+		`dubbo.registries.nacos.address: the address is required, set it to N/A to disable the registry`,
+		`dubbo.registries.zk.timeout: invalid duration "5 seconds", it should be like 300ms, 3s, 1m or the milliseconds like 3000`,
+		`dubbo.provider.services.GreeterProvider.registry-ids: the registry "etcd" is not configured`,
+		`dubbo.provider.services.GreeterProvider.protocol-ids: the protocol "dubbo" is not configured`,
+		`dubbo.provider.services.GreeterProviderCopy: the service org.apache.dubbo.Greeter is duplicated with ` +
+			`dubbo.provider.services.GreeterProvider`,
+		`dubbo.consumer.references.GreeterClient.cluster: unknown cluster "failfast", ` +
+			`available: [failover], make sure you have imported the package of it`,
+		`dubbo.consumer.references.GreeterClient.loadbalance: unknown loadbalance "roundrobbin", ` +
+			`available: [roundrobin], make sure you have imported the package of it`,
+		`dubbo.consumer.references.GreeterClient.interface: the interface is required`,
+	}, problems[1:])
+}
@@ -20,7 +20,6 @@ package config
 import (
 	"fmt"
 	"strings"
-	"time"
 )
 
 import (
@@ -54,7 +53,11 @@ type ConsumerConfig struct {
 	FilterConf                     interface{}                 `yaml:"filter-conf" json:"filter-conf,omitempty" property:"filter-conf"`
 	MaxWaitTimeForServiceDiscovery string                      `default:"3s" yaml:"max-wait-time-for-service-discovery" json:"max-wait-time-for-service-discovery,omitempty" property:"max-wait-time-for-service-discovery"`
 	MeshEnabled                    bool                        `yaml:"mesh-enabled" json:"mesh-enabled,omitempty" property:"mesh-enabled"`
-	rootConfig                     *RootConfig
+	// Transport tunes the tcp sessions of the clients, the transport of the protocol is used if it is absent
+	Transport  *TransportConfig `yaml:"transport" json:"transport,omitempty" property:"transport"`
+	rootConfig *RootConfig
+	// builder is the application and the registries set by ConsumerConfigBuilder
+	builder builderConfigs
 }
 
 // Prefix dubbo.consumer
@@ -147,42 +150,19 @@ func (cc *ConsumerConfig) Load() {
 		refConfig.Implement(refRPCService)
 	}
 
-	var maxWait int
-
-	if maxWaitDuration, err := time.ParseDuration(cc.MaxWaitTimeForServiceDiscovery); err != nil {
-		logger.Warnf("Invalid consumer max wait time for service discovery: %s, fallback to 3s", cc.MaxWaitTimeForServiceDiscovery)
-		maxWait = 3
-	} else {
-		maxWait = int(maxWaitDuration.Seconds())
-	}
-
-	// wait for invoker is available, if wait over default 3s, then panic
-	var count int
-	for {
-		checkok := true
-		for key, ref := range cc.References {
-			if (ref.Check != nil && *ref.Check && GetProviderService(key) == nil) ||
-				(ref.Check == nil && cc.Check && GetProviderService(key) == nil) ||
-				(ref.Check == nil && GetProviderService(key) == nil) { // default to true
-
-				if ref.invoker != nil && !ref.invoker.IsAvailable() {
-					checkok = false
-					count++
-					if count > maxWait {
-						errMsg := fmt.Sprintf("No provider available of the service %v.please check configuration.", ref.InterfaceName)
-						logger.Error(errMsg)
-						panic(errMsg)
-					}
-					time.Sleep(time.Second * 1)
-					break
-				}
-				if ref.invoker == nil {
-					logger.Warnf("The interface %s invoker not exist, may you should check your interface config.", ref.InterfaceName)
-				}
-			}
+	// wait for the providers of the references checking them, the references with check=false start without any
+	// provider and work once the providers are notified, and the lazy references are checked at the first invocation
+	for key, ref := range cc.References {
+		if ref.Lazy || GetProviderService(key) != nil {
+			continue
 		}
-		if checkok {
-			break
+		if ref.invoker == nil {
+			logger.Warnf("The interface %s invoker not exist, may you should check your interface config.", ref.InterfaceName)
+			continue
+		}
+		if err := ref.checkAvailable(ref.invoker); err != nil {
+			logger.Error(err.Error())
+			panic(err.Error())
 		}
 	}
 }
@@ -259,6 +239,21 @@ func (ccb *ConsumerConfigBuilder) SetMeshEnabled(meshEnabled bool) *ConsumerConf
 	return ccb
 }
 
+func (ccb *ConsumerConfigBuilder) SetTransport(transport *TransportConfig) *ConsumerConfigBuilder {
+	ccb.consumerConfig.Transport = transport
+	return ccb
+}
+
+func (ccb *ConsumerConfigBuilder) SetApplication(application *ApplicationConfig) *ConsumerConfigBuilder {
+	ccb.consumerConfig.builder.application = application
+	return ccb
+}
+
+func (ccb *ConsumerConfigBuilder) AddRegistry(registryID string, registryConfig *RegistryConfig) *ConsumerConfigBuilder {
+	ccb.consumerConfig.builder.addRegistry(registryID, registryConfig)
+	return ccb
+}
+
 func (ccb *ConsumerConfigBuilder) SetRootConfig(rootConfig *RootConfig) *ConsumerConfigBuilder {
 	ccb.consumerConfig.rootConfig = rootConfig
 	return ccb
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/url"
+	"strconv"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// CredentialConfig is the credentials of the connections to the config center or the metadata report, besides the
+// username and the password
+type CredentialConfig struct {
+	// AccessKey and SecretKey are the credentials of the cloud-hosted servers, such as nacos on aliyun
+	AccessKey string `yaml:"access-key" json:"access-key,omitempty"`
+	SecretKey string `yaml:"secret-key" json:"secret-key,omitempty"`
+	// TLS connects the servers with tls, the server certificate is verified with CACertFile unless
+	// InsecureSkipVerify is set
+	TLS *TLSConfig `yaml:"tls" json:"tls,omitempty"`
+}
+
+func (c *CredentialConfig) check() error {
+	if c == nil || c.TLS == nil {
+		return nil
+	}
+	if c.TLS.CACertFile == "" && !c.TLS.InsecureSkipVerify {
+		return perrors.New("the tls credential requires ca-cert-file unless insecure-skip-verify is set")
+	}
+	if (c.TLS.TLSCertFile == "") != (c.TLS.TLSKeyFile == "") {
+		return perrors.New("the tls credential requires both tls-cert-file and tls-key-file for the client certificate")
+	}
+	return nil
+}
+
+// setParams sets the credentials into @params, the access key and the secret key as @accessKey and @secretKey
+func (c *CredentialConfig) setParams(params url.Values, accessKey, secretKey string) {
+	if c == nil {
+		return
+	}
+	if c.AccessKey != "" {
+		params.Set(accessKey, c.AccessKey)
+	}
+	if c.SecretKey != "" {
+		params.Set(secretKey, c.SecretKey)
+	}
+	if c.TLS == nil {
+		return
+	}
+	params.Set(constant.CACert, c.TLS.CACertFile)
+	params.Set(constant.TLSCert, c.TLS.TLSCertFile)
+	params.Set(constant.TLSKey, c.TLS.TLSKeyFile)
+	params.Set(constant.TLSServerNAME, c.TLS.TLSServerName)
+	params.Set(constant.TLSInsecureSkipVerify, strconv.FormatBool(c.TLS.InsecureSkipVerify))
+}
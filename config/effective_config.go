@@ -0,0 +1,485 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+
+	"github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+const (
+	// SourceLocal is the source of the values in the local config files
+	SourceLocal = "local"
+	// SourceConfigCenter is the source of the values in the global config of the config center
+	SourceConfigCenter = "config-center"
+	// SourceAppConfigCenter is the source of the values in the config of the application in the config center
+	SourceAppConfigCenter = "app-config-center"
+	// SourceRegistryOverride is the source of the values overridden by the override rules in the registry
+	SourceRegistryOverride = "registry-override"
+	// SourceDefault is the source of the values not configured, which are the defaults or derived from the others
+	SourceDefault = "default"
+	// SourceAPI is the source of the values set by the api, e.g. the config builders
+	SourceAPI = "api"
+
+	// resolvedMethodsKey is the key of the method configs resolved for a service or a reference in the dump
+	resolvedMethodsKey = "resolved-methods"
+)
+
+// EffectiveValue is a value of the effective configuration with the source it comes from
+type EffectiveValue struct {
+	Value  interface{} `yaml:"value" json:"value"`
+	Source string      `yaml:"source" json:"source"`
+	// File is the local config file the value comes from
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+}
+
+// GetEffectiveConfiguration returns the effective values merged from the local config files and the config center,
+// keyed by the property, e.g. dubbo.registries.zk.address, which is helpful to find out where a value comes from.
+func GetEffectiveConfiguration() map[string]EffectiveValue {
+	if rootConfig == nil || rootConfig.sources == nil {
+		return map[string]EffectiveValue{}
+	}
+	return rootConfig.sources.effective()
+}
+
+// configSources are the flattened values of the config sources, which are merged property by property, so that the
+// nested sections like protocols and registries are deep merged. The config center overrides the local config files
+// if it has the highest priority, and the config of the application overrides the global one in the config center.
+type configSources struct {
+	mu sync.RWMutex
+	// externalFirst makes the config center override the local config files
+	externalFirst bool
+	// values are keyed by the source
+	values map[string]map[string]interface{}
+	// files are the local config files of the values keyed by the flattened keys
+	files map[string]string
+}
+
+// newConfigSources returns the configSources with the local config @local, which is nil if the root config is not
+// loaded from the config files, e.g. built by the api
+func newConfigSources(local *koanf.Koanf) *configSources {
+	s := &configSources{externalFirst: true, values: make(map[string]map[string]interface{})}
+	if local != nil {
+		s.values[SourceLocal] = local.All()
+	}
+	return s
+}
+
+// set sets the values of @source, the source is removed if @values is nil
+func (s *configSources) set(source string, values map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if values == nil {
+		delete(s.values, source)
+		return
+	}
+	s.values[source] = values
+}
+
+// setFiles sets the local config @files of the values keyed by the flattened keys
+func (s *configSources) setFiles(files map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = files
+}
+
+// get returns the values of @source, nil if it is absent
+func (s *configSources) get(source string) map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[source]
+}
+
+func (s *configSources) setExternalFirst(externalFirst bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.externalFirst = externalFirst
+}
+
+// precedence returns the sources from the lowest priority to the highest
+func (s *configSources) precedence() []string {
+	if s.externalFirst {
+		return []string{SourceLocal, SourceConfigCenter, SourceAppConfigCenter}
+	}
+	return []string{SourceConfigCenter, SourceAppConfigCenter, SourceLocal}
+}
+
+func (s *configSources) effective() map[string]EffectiveValue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]EffectiveValue)
+	for _, source := range s.precedence() {
+		for key, value := range s.values[source] {
+			effective := EffectiveValue{Value: value, Source: source}
+			if source == SourceLocal {
+				effective.File = s.files[key]
+			}
+			result[key] = effective
+		}
+	}
+	return result
+}
+
+// merged returns the resolver of the effective values
+func (s *configSources) merged() (*koanf.Koanf, error) {
+	values := make(map[string]interface{})
+	for key, value := range s.effective() {
+		values[key] = value.Value
+	}
+	koan := koanf.New(".")
+	if err := koan.Load(confmap.Provider(values, "."), nil); err != nil {
+		return nil, errors.WithMessage(err, "merge the configs")
+	}
+	return koan, nil
+}
+
+// parseConfig returns the flattened values of the config @content in the format of @genre
+func parseConfig(genre, content string) (values map[string]interface{}, err error) {
+	if len(content) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	defer func() {
+		// GetConfigResolver panics if the content can not be parsed
+		if e := recover(); e != nil {
+			err = errors.Errorf("parse the config error, %v", e)
+		}
+	}()
+	return GetConfigResolver(NewLoaderConf(WithDelim("."), WithGenre(genre), WithBytes([]byte(content)))).All(), nil
+}
+
+// DumpEffectiveConfig returns the yaml document of the effective configuration, which contains the application,
+// registries, protocols, services and references with the source of each value, and the method configs resolved for
+// the exported services and the referred references. The secrets like the passwords are masked.
+func DumpEffectiveConfig() ([]byte, error) {
+	tree, err := effectiveConfigTree()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(tree)
+}
+
+// EffectiveConfigHandler returns the http handler serving the effective configuration, in json if the query
+// parameter format is json, otherwise in yaml. It could be mounted on any admin server, e.g.
+// http.Handle("/dubbo/config", config.EffectiveConfigHandler()).
+func EffectiveConfigHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tree, err := effectiveConfigTree()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		var content []byte
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			content, err = json.Marshal(tree)
+		} else {
+			w.Header().Set("Content-Type", "application/x-yaml")
+			content, err = yaml.Marshal(tree)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(content)
+	})
+}
+
+// effectiveConfigTree returns the root config as a tree, whose leaves are the effective values
+func effectiveConfigTree() (map[string]interface{}, error) {
+	if rootConfig == nil {
+		return nil, errors.New("the config has not been loaded")
+	}
+	d := &configDumper{api: rootConfig.sources == nil}
+	if rootConfig.sources != nil {
+		d.sources = rootConfig.sources.effective()
+	}
+	node, _ := d.dump(rootConfig.Prefix(), reflect.ValueOf(rootConfig), "").(map[string]interface{})
+	if node == nil {
+		node = map[string]interface{}{}
+	}
+	if rootConfig.Provider != nil {
+		for id, sc := range rootConfig.Provider.Services {
+			if resolved := d.resolveService("dubbo.provider.services."+id, sc); len(resolved) > 0 {
+				child(node, "provider", "services", id)[resolvedMethodsKey] = resolved
+			}
+		}
+	}
+	if rootConfig.Consumer != nil {
+		for id, rc := range rootConfig.Consumer.References {
+			if resolved := d.resolveReference("dubbo.consumer.references."+id, rc); len(resolved) > 0 {
+				child(node, "consumer", "references", id)[resolvedMethodsKey] = resolved
+			}
+		}
+	}
+	return map[string]interface{}{rootConfig.Prefix(): node}, nil
+}
+
+// child returns the nested node of @node by @path, which is created if it is absent
+func child(node map[string]interface{}, path ...string) map[string]interface{} {
+	for _, key := range path {
+		next, ok := node[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			node[key] = next
+		}
+		node = next
+	}
+	return node
+}
+
+// configDumper dumps the configs by their yaml tags, the source of a value is the one of the nearest key configured
+type configDumper struct {
+	sources map[string]EffectiveValue
+	// api is true if the root config is built by the api
+	api bool
+}
+
+// dump returns the node of @v keyed by @key, which is a map for the structs and the maps, a list for the slices of
+// the structs and an EffectiveValue for the others, nil if the node is empty. @def is the default tag of the field.
+func (d *configDumper) dump(key string, v reflect.Value, def string) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		node := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if field.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			if value := d.dump(key+"."+name, v.Field(i), field.Tag.Get("default")); value != nil {
+				node[name] = value
+			}
+		}
+		if len(node) == 0 {
+			return nil
+		}
+		return node
+	case reflect.Map:
+		node := make(map[string]interface{})
+		for _, k := range v.MapKeys() {
+			name := fmt.Sprint(k.Interface())
+			if value := d.dump(key+"."+name, v.MapIndex(k), ""); value != nil {
+				node[name] = value
+			}
+		}
+		if len(node) == 0 {
+			return nil
+		}
+		return node
+	case reflect.Slice, reflect.Array:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return d.leaf(key, v, def)
+		}
+		var node []interface{}
+		for i := 0; i < v.Len(); i++ {
+			if value := d.dump(key+"."+strconv.Itoa(i), v.Index(i), ""); value != nil {
+				node = append(node, value)
+			}
+		}
+		if len(node) == 0 {
+			return nil
+		}
+		return node
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	default:
+		return d.leaf(key, v, def)
+	}
+}
+
+// leaf returns the effective value of @v keyed by @key, nil if it's neither set nor configured
+func (d *configDumper) leaf(key string, v reflect.Value, def string) interface{} {
+	configuredValue, configured := d.configured(key)
+	source := configuredValue.Source
+	if isEmpty(v) {
+		// the empty fields of a list configured as a whole are not configured
+		if _, exact := d.sources[key]; !exact {
+			return nil
+		}
+	}
+	value := v.Interface()
+	if !configured {
+		source = SourceDefault
+		if d.api && (def == "" || fmt.Sprint(value) != def) {
+			source = SourceAPI
+		}
+	}
+	return EffectiveValue{Value: mask(key, value), Source: source, File: configuredValue.File}
+}
+
+// isEmpty returns whether @v is zero, or a list of the zero values
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			if !v.Index(i).IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+	return v.IsZero()
+}
+
+// source returns the source of the nearest key configured of @key, the lists are configured as a whole
+func (d *configDumper) source(key string) (string, bool) {
+	value, ok := d.configured(key)
+	return value.Source, ok
+}
+
+// configured returns the effective value of the nearest key configured of @key
+func (d *configDumper) configured(key string) (EffectiveValue, bool) {
+	for k := key; ; {
+		if value, ok := d.sources[k]; ok {
+			return value, true
+		}
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			return EffectiveValue{}, false
+		}
+		k = k[:i]
+	}
+}
+
+// resolveService returns the method configs resolved for the exported service @sc. The values overridden by the
+// override rules in the registry are taken from the exporters, which are re-exported with the overridden urls.
+func (d *configDumper) resolveService(prefix string, sc *ServiceConfig) map[string]interface{} {
+	sc.exportersLock.Lock()
+	defer sc.exportersLock.Unlock()
+	if len(sc.publishedURLs) == 0 {
+		return nil
+	}
+	published := sc.publishedURLs[0]
+	current := published
+	for _, exporter := range sc.exporters {
+		if invoker := exporter.GetInvoker(); invoker != nil && invoker.GetURL().ServiceKey() == published.ServiceKey() {
+			current = invoker.GetURL()
+			break
+		}
+	}
+	return d.resolveMethods(prefix, published.Methods, sc.Methods, published, current)
+}
+
+// resolveReference returns the method configs resolved for the methods configured in the referred reference @rc
+func (d *configDumper) resolveReference(prefix string, rc *ReferenceConfig) map[string]interface{} {
+	if rc.publishedURL == nil {
+		return nil
+	}
+	methods := make([]string, 0, len(rc.Methods))
+	for _, method := range rc.Methods {
+		methods = append(methods, method.Name)
+	}
+	return d.resolveMethods(prefix, methods, rc.Methods, rc.publishedURL, rc.publishedURL)
+}
+
+// resolveMethods returns the values of the method scoped keys resolved for @methods by the url @current, the source
+// of a value is the method config or the service it's inherited from, or the registry if it differs from @configured
+func (d *configDumper) resolveMethods(prefix string, methods []string, configs []*MethodConfig,
+	configured, current *common.URL) map[string]interface{} {
+	resolved := make(map[string]interface{})
+	for _, method := range methods {
+		values := make(map[string]interface{})
+		for _, key := range methodScopedKeys {
+			value := current.GetMethodParam(method, key, current.GetParam(key, ""))
+			if value == "" {
+				continue
+			}
+			source := SourceRegistryOverride
+			if value == configured.GetMethodParam(method, key, configured.GetParam(key, "")) {
+				source = d.methodSource(prefix, method, key, configs)
+			}
+			values[key] = EffectiveValue{Value: value, Source: source}
+		}
+		if len(values) > 0 {
+			resolved[method] = values
+		}
+	}
+	return resolved
+}
+
+// methodSource returns the source of @key resolved for @method, which is the one of the method config setting it,
+// or the one of the service it's inherited from
+func (d *configDumper) methodSource(prefix, method, key string, configs []*MethodConfig) string {
+	candidates := make([]string, 0, len(configs)+2)
+	for _, exact := range []bool{true, false} {
+		for i, config := range configs {
+			if config == nil || (config.Name == method) != exact || !common.MatchMethodPattern(config.Name, method) {
+				continue
+			}
+			if yamlField(reflect.ValueOf(config).Elem(), key) != "" {
+				candidates = append(candidates, prefix+".methods."+strconv.Itoa(i)+"."+key)
+			}
+		}
+	}
+	candidates = append(candidates, prefix+"."+key, prefix+".params."+key)
+	for _, candidate := range candidates {
+		if source, ok := d.source(candidate); ok {
+			return source
+		}
+	}
+	if d.api {
+		return SourceAPI
+	}
+	return SourceDefault
+}
+
+// yamlField returns the value of the field of the struct @v tagged by the yaml @name, empty if it's zero or absent
+func yamlField(v reflect.Value, name string) string {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0] == name && !v.Field(i).IsZero() {
+			return fmt.Sprint(v.Field(i).Interface())
+		}
+	}
+	return ""
+}
+
+// mask masks @value if @key is a secret
+func mask(key string, value interface{}) interface{} {
+	if common.IsSecretKey(key[strings.LastIndex(key, ".")+1:]) {
+		return common.RedactedValue
+	}
+	return value
+}
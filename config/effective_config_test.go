@@ -0,0 +1,273 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/knadh/koanf"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	localMergeConfig = `
+dubbo:
+  application:
+    name: user-center
+  registries:
+    zk:
+      protocol: zookeeper
+      address: 127.0.0.1:2181
+      timeout: 3s
+  protocols:
+    dubbo:
+      name: dubbo
+      port: 20000
+`
+	globalMergeConfig = `
+dubbo:
+  registries:
+    zk:
+      address: 10.0.0.1:2181
+    nacos:
+      protocol: nacos
+      address: 10.0.0.2:8848
+  protocols:
+    dubbo:
+      port: 20001
+`
+	appMergeConfig = `
+dubbo:
+  protocols:
+    dubbo:
+      port: 20002
+    tri:
+      name: tri
+      port: 20003
+  consumer:
+    request-timeout: 5s
+`
+)
+
+// newMergeTestConfig returns the root config loaded from the local config, and merged with the global config and
+// the config of the application in the config center
+func newMergeTestConfig(t *testing.T, highestPriority bool) *RootConfig {
+	koan := GetConfigResolver(NewLoaderConf(WithBytes([]byte(localMergeConfig))))
+	rc := newEmptyRootConfig()
+	assert.NoError(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}))
+	rc.sources = newConfigSources(koan)
+	rc.ConfigCenter = &CenterConfig{Protocol: "nacos", DataId: "dubbo.yaml", AppDataId: "dubbo.yaml",
+		FileExtension: "yaml", HighestPriority: &highestPriority, SnapshotDir: t.TempDir()}
+
+	files := rc.ConfigCenter.configFiles(rc)
+	assert.Equal(t, []configFile{{source: SourceConfigCenter, dataId: "dubbo.yaml"},
+		{source: SourceAppConfigCenter, group: "user-center", dataId: "dubbo.yaml"}}, files)
+	assert.NoError(t, rc.ConfigCenter.apply(rc, files, []string{globalMergeConfig, appMergeConfig}))
+	return rc
+}
+
+func TestMergeExternalFirst(t *testing.T) {
+	rc := newMergeTestConfig(t, true)
+
+	// the nested sections are deep merged, the config of the application overrides the global one
+	assert.Equal(t, "10.0.0.1:2181", rc.Registries["zk"].Address)
+	assert.Equal(t, "zookeeper", rc.Registries["zk"].Protocol)
+	assert.Equal(t, "3s", rc.Registries["zk"].Timeout)
+	assert.Equal(t, "10.0.0.2:8848", rc.Registries["nacos"].Address)
+	assert.Equal(t, "dubbo", rc.Protocols["dubbo"].Name)
+	assert.Equal(t, "20002", rc.Protocols["dubbo"].Port)
+	assert.Equal(t, "20003", rc.Protocols["tri"].Port)
+	assert.Equal(t, "5s", rc.Consumer.RequestTimeout)
+	assert.Equal(t, "user-center", rc.Application.Name)
+
+	effective := rc.sources.effective()
+	assert.Equal(t, EffectiveValue{Value: "10.0.0.1:2181", Source: SourceConfigCenter}, effective["dubbo.registries.zk.address"])
+	assert.Equal(t, EffectiveValue{Value: "3s", Source: SourceLocal}, effective["dubbo.registries.zk.timeout"])
+	assert.Equal(t, EffectiveValue{Value: 20002, Source: SourceAppConfigCenter}, effective["dubbo.protocols.dubbo.port"])
+}
+
+func TestMergeLocalFirst(t *testing.T) {
+	rc := newMergeTestConfig(t, false)
+
+	// the local config overrides the config center, which only fills the absent ones
+	assert.Equal(t, "127.0.0.1:2181", rc.Registries["zk"].Address)
+	assert.Equal(t, "10.0.0.2:8848", rc.Registries["nacos"].Address)
+	assert.Equal(t, "20000", rc.Protocols["dubbo"].Port)
+	assert.Equal(t, "20003", rc.Protocols["tri"].Port)
+	assert.Equal(t, "5s", rc.Consumer.RequestTimeout)
+
+	effective := rc.sources.effective()
+	assert.Equal(t, EffectiveValue{Value: "127.0.0.1:2181", Source: SourceLocal}, effective["dubbo.registries.zk.address"])
+	assert.Equal(t, EffectiveValue{Value: 20000, Source: SourceLocal}, effective["dubbo.protocols.dubbo.port"])
+	assert.Equal(t, EffectiveValue{Value: "nacos", Source: SourceConfigCenter}, effective["dubbo.registries.nacos.protocol"])
+}
+
+func TestMergeDynamicUpdate(t *testing.T) {
+	rc := newMergeTestConfig(t, true)
+	listener := &configFileListener{rc: rc, file: rc.ConfigCenter.configFiles(rc)[1]}
+
+	// the registry timeout changed in the config of the application overrides the local one
+	listener.Process(&config_center.ConfigChangeEvent{Key: "dubbo.yaml", ConfigType: remoting.EventTypeUpdate,
+		Value: appMergeConfig + "  registries:\n    zk:\n      timeout: 5s\n"})
+	assert.Equal(t, "5s", rc.Registries["zk"].Timeout)
+	assert.Equal(t, SourceAppConfigCenter, rc.sources.effective()["dubbo.registries.zk.timeout"].Source)
+
+	// the malformed config is ignored
+	listener.Process(&config_center.ConfigChangeEvent{Key: "dubbo.yaml", ConfigType: remoting.EventTypeUpdate,
+		Value: "dubbo:\n  registries: [zk\n"})
+	assert.Equal(t, SourceAppConfigCenter, rc.sources.effective()["dubbo.registries.zk.timeout"].Source)
+
+	// the local timeout takes effect again once the config of the application is deleted
+	listener.Process(&config_center.ConfigChangeEvent{Key: "dubbo.yaml", ConfigType: remoting.EventTypeDel})
+	assert.Equal(t, "3s", rc.Registries["zk"].Timeout)
+	assert.Equal(t, EffectiveValue{Value: "3s", Source: SourceLocal}, rc.sources.effective()["dubbo.registries.zk.timeout"])
+}
+
+func TestGetEffectiveConfiguration(t *testing.T) {
+	origin := rootConfig
+	defer func() {
+		rootConfig = origin
+	}()
+	rootConfig = newMergeTestConfig(t, true)
+	effective := GetEffectiveConfiguration()
+	assert.Equal(t, EffectiveValue{Value: "user-center", Source: SourceLocal}, effective["dubbo.application.name"])
+	assert.Equal(t, EffectiveValue{Value: "5s", Source: SourceAppConfigCenter}, effective["dubbo.consumer.request-timeout"])
+
+	rootConfig = newEmptyRootConfig()
+	assert.Empty(t, GetEffectiveConfiguration())
+}
+
+const dumpConfig = `
+dubbo:
+  registries:
+    zk:
+      protocol: zookeeper
+      address: 127.0.0.1:2181
+      password: zk-secret
+  provider:
+    services:
+      UserProvider:
+        interface: org.apache.dubbo.UserProvider
+        retries: 1
+        methods:
+          - name: Get*
+            retries: 3
+          - name: GetUser
+            tps.limit.rate: 10
+`
+
+// newDumpTestConfig returns the root config loaded from the dump config, whose service UserProvider is exported by
+// the url @published and re-exported by the url @current
+func newDumpTestConfig(t *testing.T, published, current *common.URL) *RootConfig {
+	koan := GetConfigResolver(NewLoaderConf(WithBytes([]byte(dumpConfig))))
+	rc := newEmptyRootConfig()
+	assert.NoError(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}))
+	rc.sources = newConfigSources(koan)
+	sc := rc.Provider.Services["UserProvider"]
+	sc.Cluster = "failover"
+	sc.publishedURLs = []*common.URL{published}
+	sc.exporters = []protocol.Exporter{protocol.NewBaseExporter("UserProvider", protocol.NewBaseInvoker(current), &sync.Map{})}
+	return rc
+}
+
+func TestDumpEffectiveConfig(t *testing.T) {
+	origin := rootConfig
+	defer func() {
+		rootConfig = origin
+	}()
+	published, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.UserProvider?retries=1&methods.Get*.retries=3" +
+		"&methods.GetUser.tps.limit.rate=10&loadbalance=random")
+	published.Methods = []string{"GetUser", "GetOrder", "Delete"}
+	current := published.Clone()
+	current.SetParam("methods.GetOrder.retries", "5")
+	rootConfig = newDumpTestConfig(t, published, current)
+
+	content, err := DumpEffectiveConfig()
+	assert.NoError(t, err)
+	var dump map[string]map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(content, &dump))
+	value := func(path ...string) map[interface{}]interface{} {
+		var node interface{} = dump["dubbo"]
+		for _, key := range path {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				node = n[key]
+			case map[interface{}]interface{}:
+				node = n[key]
+			default:
+				return nil
+			}
+		}
+		v, _ := node.(map[interface{}]interface{})
+		return v
+	}
+
+	assert.Equal(t, map[interface{}]interface{}{"value": "127.0.0.1:2181", "source": SourceLocal}, value("registries", "zk", "address"))
+	// the secrets are masked
+	assert.Equal(t, map[interface{}]interface{}{"value": common.RedactedValue, "source": SourceLocal}, value("registries", "zk", "password"))
+	assert.Equal(t, map[interface{}]interface{}{"value": "failover", "source": SourceDefault}, value("provider", "services", "UserProvider", "cluster"))
+
+	// the method configs are resolved from the methods matched, the service and the registry
+	methods := []string{"provider", "services", "UserProvider", resolvedMethodsKey}
+	assert.Equal(t, map[interface{}]interface{}{"value": "3", "source": SourceLocal}, value(append(methods, "GetUser", "retries")...))
+	assert.Equal(t, map[interface{}]interface{}{"value": "10", "source": SourceLocal}, value(append(methods, "GetUser", "tps.limit.rate")...))
+	assert.Equal(t, map[interface{}]interface{}{"value": "5", "source": SourceRegistryOverride}, value(append(methods, "GetOrder", "retries")...))
+	assert.Equal(t, map[interface{}]interface{}{"value": "1", "source": SourceLocal}, value(append(methods, "Delete", "retries")...))
+	assert.Equal(t, map[interface{}]interface{}{"value": "random", "source": SourceDefault}, value(append(methods, "Delete", "loadbalance")...))
+
+	rootConfig = nil
+	_, err = DumpEffectiveConfig()
+	assert.Error(t, err)
+}
+
+func TestEffectiveConfigHandler(t *testing.T) {
+	origin := rootConfig
+	defer func() {
+		rootConfig = origin
+	}()
+	rootConfig = newEmptyRootConfig()
+	rootConfig.Application.Name = "user-center"
+	rootConfig.Registries["nacos"] = &RegistryConfig{Address: "127.0.0.1:8848", Password: "nacos"}
+
+	recorder := httptest.NewRecorder()
+	EffectiveConfigHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config?format=json", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `"name":{"value":"user-center","source":"api"}`)
+	assert.Contains(t, recorder.Body.String(), `"password":{"value":"******","source":"api"}`)
+	assert.NotContains(t, recorder.Body.String(), `"nacos"}`)
+
+	recorder = httptest.NewRecorder()
+	EffectiveConfigHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "address:\n")
+}
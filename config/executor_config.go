@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// ExecutorConfig is the goroutine pool executing the requests of the services of the dubbo protocol, e.g.
+//
+//	provider:
+//	  executor:
+//	    workers: 400
+//	  services:
+//	    SlowProvider:
+//	      executor:
+//	        workers: 16
+//	        queue-size: 64
+//
+// The requests of a service with its own executor are executed on the pool of the service, so a slow service
+// exhausts only its own workers. The other services share the executor of the provider if it's configured, which has
+// 200 workers and 1024 queued requests by default, or they are handled inline otherwise. The requests are dispatched to the pools before the filter chains, and the
+// requests over a full pool are rejected by the thread pool exhausted responses unless the rejection is caller-runs.
+type ExecutorConfig struct {
+	// Workers is the max workers of the pool, 32 for the services and 200 for the provider by default
+	Workers int `yaml:"workers" json:"workers,omitempty" property:"workers"`
+	// QueueSize is the max requests waiting for the workers, 1024 by default
+	QueueSize int `yaml:"queue-size" json:"queue-size,omitempty" property:"queue-size"`
+	// Rejection is what's done with the requests once the pool is full, abort by default, or caller-runs executing
+	// them by the goroutines receiving them
+	Rejection string `yaml:"rejection" json:"rejection,omitempty" property:"rejection"`
+}
+
+func (c *ExecutorConfig) check() error {
+	if c.Workers < 0 || c.QueueSize < 0 {
+		return perrors.Errorf("the size of the executor is negative, workers: %d, queue-size: %d", c.Workers, c.QueueSize)
+	}
+	switch c.Rejection {
+	case "", constant.ExecutorRejectionAbort, constant.ExecutorRejectionCallerRuns:
+		return nil
+	}
+	return perrors.Errorf("unknown rejection %q of the executor, it should be %s or %s", c.Rejection,
+		constant.ExecutorRejectionAbort, constant.ExecutorRejectionCallerRuns)
+}
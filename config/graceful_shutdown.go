@@ -18,9 +18,13 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -30,8 +34,11 @@ import (
 )
 
 import (
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/registry"
 )
 
 /*
@@ -53,6 +60,21 @@ import (
  */
 const defaultShutDownTime = time.Second * 60
 
+// the phases of the graceful shutdown in order, see also GracefulShutdown
+const (
+	ShutdownPhaseUnregister       = "unregister"
+	ShutdownPhaseRejectRequests   = "reject-requests"
+	ShutdownPhaseProviderRequests = "provider-requests"
+	ShutdownPhaseConsumerRequests = "consumer-requests"
+	ShutdownPhaseDestroy          = "destroy"
+	ShutdownPhaseFlush            = "flush"
+)
+
+// shutdownPollInterval is the interval to check whether the requests waited for are finished
+const shutdownPollInterval = 10 * time.Millisecond
+
+var gracefulShutdownOnce sync.Once
+
 func gracefulShutdownInit() {
 	// retrieve ShutdownConfig for gracefulShutdownFilter
 	cGracefulShutdownFilter, existcGracefulShutdownFilter := extension.GetFilter(constant.GracefulShutdownConsumerFilterKey)
@@ -79,12 +101,11 @@ func gracefulShutdownInit() {
 			select {
 			case sig := <-signals:
 				logger.Infof("get signal %s, applicationConfig will shutdown.", sig)
-				// gracefulShutdownOnce.Do(func() {
 				time.AfterFunc(totalTimeout(), func() {
 					logger.Warn("Shutdown gracefully timeout, applicationConfig will shutdown immediately. ")
 					os.Exit(0)
 				})
-				BeforeShutdown()
+				GracefulShutdown()
 				// those signals' original behavior is exit with dump ths stack, so we try to keep the behavior
 				for _, dumpSignal := range DumpHeapShutdownSignals {
 					if sig == dumpSignal {
@@ -98,23 +119,126 @@ func gracefulShutdownInit() {
 }
 
 // BeforeShutdown provides processing flow before shutdown
+//
+// Deprecated: replaced by GracefulShutdown
 func BeforeShutdown() {
-	destroyAllRegistries()
-	// waiting for a short time so that the clients have enough time to get the notification that server shutdowns
-	// The value of configuration depends on how long the clients will get notification.
-	waitAndAcceptNewRequests()
+	GracefulShutdown()
+}
 
-	// reject sending/receiving the new request, but keeping waiting for accepting requests
-	waitForSendingAndReceivingRequests()
+// GracefulShutdown shuts the application down gracefully by the phases in order:
+//  1. unregister: unregister the services and the instance from the registries and unpublish the references,
+//     then keep accepting the requests until the consumers update their invokers
+//  2. reject-requests: reject the new inbound requests
+//  3. provider-requests: wait for the in-flight provider requests
+//  4. consumer-requests: wait for the in-flight consumer requests
+//  5. destroy: destroy the protocols, the registries and the config center clients
+//  6. flush: execute the custom callbacks, which flush the metrics and so on
+//
+// The lifecycle listeners are notified before the phases and after them, the logger is flushed after all of them.
+// Each phase is bounded by its timeout of ShutdownConfig.PhaseTimeouts, at which it's cancelled, and the whole by
+// ShutdownConfig.Timeout, the phases left at the total timeout are abandoned. It's triggered by SIGTERM and SIGINT unless the internal signal is
+// disabled, and only the first invocation takes effect. In the shutdown diagnostics mode, the goroutines, the connections
+// and the requests left after the phases are logged, see VerifyShutdown.
+func GracefulShutdown() {
+	gracefulShutdownOnce.Do(func() {
+		shutdownConfig := GetShutDown()
+		eventbus.Publish(eventbus.ShutdownInitiated{})
+		fireLifecycleEvent(extension.LifecycleBeforeShutdown)
+		var registries []registry.Registry
+		phases := []shutdownPhase{
+			{name: ShutdownPhaseUnregister, run: func(ctx context.Context) string {
+				registries = getAllRegistries()
+				destroyAllRegistries()
+				destroyServiceDiscoveries(registries)
+				unpublishReferences()
+				waitAndAcceptNewRequests(ctx, shutdownConfig)
+				return ""
+			}},
+			{name: ShutdownPhaseRejectRequests, run: func(context.Context) string {
+				logger.Info("Graceful shutdown --- Reject the new requests. ")
+				shutdownConfig.RejectRequest.Store(true)
+				return ""
+			}},
+			{name: ShutdownPhaseProviderRequests, run: func(ctx context.Context) string {
+				return waitingProviderProcessedTimeout(ctx, shutdownConfig)
+			}},
+			{name: ShutdownPhaseConsumerRequests, run: func(ctx context.Context) string {
+				return waitingConsumerProcessedTimeout(ctx, shutdownConfig)
+			}},
+			{name: ShutdownPhaseDestroy, run: func(ctx context.Context) string {
+				return runShutdownSteps(ctx, destroyProtocols, publishDestroyed, func() {
+					destroyRegistries(registries)
+				}, destroyConfigCenter)
+			}},
+			{name: ShutdownPhaseFlush, run: func(ctx context.Context) string {
+				return executeCustomCallbacks(ctx)
+			}},
+		}
+		runShutdownPhases(shutdownConfig, phases)
+		reportShutdown(shutdownConfig)
+		fireLifecycleEvent(extension.LifecycleAfterShutdown)
+	})
+}
 
-	// destroy all protocols
-	destroyProtocols()
+// shutdownPhase is a phase of the graceful shutdown, run returns what's abandoned at the deadline. The context of run
+// is cancelled at the timeout of the phase, and the next phases start then, so run must return soon after that
+// instead of working underneath them.
+type shutdownPhase struct {
+	name string
+	run  func(ctx context.Context) (abandoned string)
+}
 
-	logger.Info("Graceful shutdown --- Execute the custom callbacks.")
-	customCallbacks := extension.GetAllCustomShutdownCallbacks()
-	for callback := customCallbacks.Front(); callback != nil; callback = callback.Next() {
-		callback.Value.(func())()
+// runShutdownSteps runs @steps in order until @ctx is done, it returns what's abandoned
+func runShutdownSteps(ctx context.Context, steps ...func()) string {
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return fmt.Sprintf("%d of %d steps left", len(steps)-i, len(steps))
+		}
+		step()
 	}
+	return ""
+}
+
+// runShutdownPhases runs @phases in order and logs the summary of how long each phase took and what's abandoned
+func runShutdownPhases(shutdownConfig *ShutdownConfig, phases []shutdownPhase) (abandons []string) {
+	start := time.Now()
+	totalDeadline := start.Add(shutdownConfig.GetTimeout())
+	var costs []string
+	for _, phase := range phases {
+		phaseStart := time.Now()
+		if !phaseStart.Before(totalDeadline) {
+			abandons = append(abandons, fmt.Sprintf("%s (total timeout %s exceeded)", phase.name, shutdownConfig.Timeout))
+			continue
+		}
+		deadline := phaseStart.Add(shutdownConfig.GetPhaseTimeout(phase.name))
+		if deadline.After(totalDeadline) {
+			deadline = totalDeadline
+		}
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		done := make(chan string, 1)
+		go func(phase shutdownPhase) {
+			done <- phase.run(ctx)
+		}(phase)
+		var abandoned string
+		// the phases waiting for the requests return by themselves at the deadline
+		select {
+		case abandoned = <-done:
+		case <-time.After(time.Until(deadline) + 2*shutdownPollInterval):
+			abandoned = "timeout"
+		}
+		cancel()
+		costs = append(costs, fmt.Sprintf("%s %v", phase.name, time.Since(phaseStart)))
+		if abandoned != "" {
+			abandons = append(abandons, fmt.Sprintf("%s (%s)", phase.name, abandoned))
+		}
+	}
+	summary := fmt.Sprintf("Graceful shutdown --- Finished in %v, phases: %s. ", time.Since(start), strings.Join(costs, ", "))
+	if len(abandons) == 0 {
+		logger.Info(summary)
+		return
+	}
+	logger.Warn(summary + "Abandoned: " + strings.Join(abandons, ", "))
+	return abandons
 }
 
 func destroyAllRegistries() {
@@ -123,6 +247,94 @@ func destroyAllRegistries() {
 	registryProtocol.Destroy()
 }
 
+// getAllRegistries returns the registries created by the registry protocol
+func getAllRegistries() []registry.Registry {
+	if rf, ok := extension.GetProtocol(constant.RegistryProtocol).(registry.RegistryFactory); ok {
+		return rf.GetRegistries()
+	}
+	return nil
+}
+
+// destroyServiceDiscoveries destroys the application level registries, which takes the instance offline
+func destroyServiceDiscoveries(registries []registry.Registry) {
+	for _, r := range registries {
+		if _, ok := r.(registry.ServiceDiscoveryHolder); ok {
+			r.Destroy()
+		}
+	}
+}
+
+// destroyRegistries destroys the interface level registries, the application level ones are destroyed on unregister
+func destroyRegistries(registries []registry.Registry) {
+	logger.Info("Graceful shutdown --- Destroy registries. ")
+	for _, r := range registries {
+		if _, ok := r.(registry.ServiceDiscoveryHolder); !ok {
+			r.Destroy()
+		}
+	}
+}
+
+// destroyConfigCenter destroys the client of the config center
+func destroyConfigCenter() {
+	dc := conf.GetEnvInstance().GetDynamicConfiguration()
+	if destroyable, ok := dc.(interface{ Destroy() }); ok {
+		logger.Info("Graceful shutdown --- Destroy the config center. ")
+		destroyable.Destroy()
+	}
+}
+
+// executeCustomCallbacks executes the custom callbacks in order until @ctx is done, it returns what's abandoned
+func executeCustomCallbacks(ctx context.Context) string {
+	logger.Info("Graceful shutdown --- Execute the custom callbacks.")
+	customCallbacks := extension.GetAllCustomShutdownCallbacks()
+	var steps []func()
+	for callback := customCallbacks.Front(); callback != nil; callback = callback.Next() {
+		steps = append(steps, callback.Value.(func()))
+	}
+	return runShutdownSteps(ctx, steps...)
+}
+
+// flushLogger flushes the buffered logs if the logger supports
+func flushLogger() {
+	if syncer, ok := logger.GetLogger().(interface{ Sync() error }); ok {
+		_ = syncer.Sync()
+	}
+}
+
+// unpublishReferences removes the metadata of the references from the metadata report,
+// it's published in background and flushed by the custom callback of the metadata report
+func unpublishReferences() {
+	if rootConfig == nil || rootConfig.Consumer == nil {
+		return
+	}
+	logger.Info("Graceful shutdown --- Unpublish the metadata of the references. ")
+	for _, rc := range rootConfig.Consumer.References {
+		rc.unpublish()
+	}
+}
+
+// publishDestroyed publishes the unexported services and the destroyed references once the protocols are destroyed,
+// they're marked so that they're not published again by ServiceConfig.Unexport or ReferenceConfig.Destroy
+func publishDestroyed() {
+	if rootConfig == nil {
+		return
+	}
+	if rootConfig.Provider != nil {
+		for _, s := range rootConfig.Provider.Services {
+			if s.exported == nil || !s.exported.CAS(true, false) {
+				continue
+			}
+			s.unexported.Store(true)
+			s.publishUnexported(s.publishedURLs)
+		}
+	}
+	if rootConfig.Consumer != nil {
+		for _, rc := range rootConfig.Consumer.References {
+			rc.publishDestroyed()
+		}
+	}
+}
+
 // destroyProtocols destroys protocols.
 // First we destroy provider's protocols, and then we destroy the consumer protocols.
 func destroyProtocols() {
@@ -158,62 +370,61 @@ func destroyConsumerProtocols(consumerProtocols *gxset.HashSet) {
 	}
 }
 
-func waitAndAcceptNewRequests() {
+// waitAndAcceptNewRequests keeps accepting the new requests for a short time so that the clients have enough time
+// to get the notification that server shutdowns, then waits until no request arrives within the offline request
+// window, or @ctx is done
+func waitAndAcceptNewRequests(ctx context.Context, shutdownConfig *ShutdownConfig) {
 	logger.Info("Graceful shutdown --- Keep waiting and accept new requests for a short time. ")
-	if rootConfig.Shutdown == nil {
-		return
-	}
-
-	time.Sleep(rootConfig.Shutdown.GetConsumerUpdateWaitTime())
-
-	timeout := rootConfig.Shutdown.GetStepTimeout()
-	// ignore this step
-	if timeout < 0 {
-		return
-	}
-	waitingProviderProcessedTimeout(rootConfig.Shutdown)
-}
-
-func waitingProviderProcessedTimeout(shutdownConfig *ShutdownConfig) {
-	timeout := shutdownConfig.GetStepTimeout()
-	if timeout <= 0 {
+	if !sleepShutdown(ctx, shutdownConfig.GetConsumerUpdateWaitTime()) {
 		return
 	}
-	deadline := time.Now().Add(timeout)
 
 	offlineRequestWindowTimeout := shutdownConfig.GetOfflineRequestWindowTimeout()
-	for time.Now().Before(deadline) &&
-		(shutdownConfig.ProviderActiveCount.Load() > 0 || time.Now().Before(shutdownConfig.ProviderLastReceivedRequestTime.Load().Add(offlineRequestWindowTimeout))) {
+	for shutdownConfig.ProviderActiveCount.Load() > 0 ||
+		time.Now().Before(shutdownConfig.ProviderLastReceivedRequestTime.Load().Add(offlineRequestWindowTimeout)) {
 		// sleep 10 ms and then we check it again
-		time.Sleep(10 * time.Millisecond)
-		logger.Infof("waiting for provider active invocation count = %d, provider last received request time: %v",
+		if !sleepShutdown(ctx, shutdownPollInterval) {
+			return
+		}
+		logger.Debugf("waiting for provider active invocation count = %d, provider last received request time: %v",
 			shutdownConfig.ProviderActiveCount.Load(), shutdownConfig.ProviderLastReceivedRequestTime.Load())
 	}
 }
 
-// for provider. It will wait for processing receiving requests
-func waitForSendingAndReceivingRequests() {
-	logger.Info("Graceful shutdown --- Keep waiting until sending/accepting requests finish or timeout. ")
-	if rootConfig == nil || rootConfig.Shutdown == nil {
-		// ignore this step
-		return
+// sleepShutdown sleeps @d, it returns false if @ctx is done before that
+func sleepShutdown(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
-	rootConfig.Shutdown.RejectRequest.Store(true)
-	waitingConsumerProcessedTimeout(rootConfig.Shutdown)
 }
 
-func waitingConsumerProcessedTimeout(shutdownConfig *ShutdownConfig) {
-	timeout := shutdownConfig.GetStepTimeout()
-	if timeout <= 0 {
-		return
+// waitingProviderProcessedTimeout waits for the in-flight provider requests until @ctx is done
+func waitingProviderProcessedTimeout(ctx context.Context, shutdownConfig *ShutdownConfig) string {
+	logger.Info("Graceful shutdown --- Keep waiting until accepting requests finish or timeout. ")
+	for shutdownConfig.ProviderActiveCount.Load() > 0 && sleepShutdown(ctx, shutdownPollInterval) {
+		logger.Debugf("waiting for provider active invocation count = %d", shutdownConfig.ProviderActiveCount.Load())
 	}
-	deadline := time.Now().Add(timeout)
+	if count := shutdownConfig.ProviderActiveCount.Load(); count > 0 {
+		return fmt.Sprintf("%d provider requests in flight", count)
+	}
+	return ""
+}
 
-	for time.Now().Before(deadline) && shutdownConfig.ConsumerActiveCount.Load() > 0 {
-		// sleep 10 ms and then we check it again
-		time.Sleep(10 * time.Millisecond)
-		logger.Infof("waiting for consumer active invocation count = %d", shutdownConfig.ConsumerActiveCount.Load())
+// waitingConsumerProcessedTimeout waits for the in-flight consumer requests until @ctx is done
+func waitingConsumerProcessedTimeout(ctx context.Context, shutdownConfig *ShutdownConfig) string {
+	logger.Info("Graceful shutdown --- Keep waiting until sending requests finish or timeout. ")
+	for shutdownConfig.ConsumerActiveCount.Load() > 0 && sleepShutdown(ctx, shutdownPollInterval) {
+		logger.Debugf("waiting for consumer active invocation count = %d", shutdownConfig.ConsumerActiveCount.Load())
+	}
+	if count := shutdownConfig.ConsumerActiveCount.Load(); count > 0 {
+		return fmt.Sprintf("%d consumer requests in flight", count)
 	}
+	return ""
 }
 
 func totalTimeout() time.Duration {
@@ -30,6 +30,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
@@ -38,6 +39,7 @@ const (
 	defaultStepTimeout                 = 3 * time.Second
 	defaultConsumerUpdateWaitTime      = 3 * time.Second
 	defaultOfflineRequestWindowTimeout = 3 * time.Second
+	defaultHookTimeout                 = 3 * time.Second
 )
 
 // ShutdownConfig is used as configuration for graceful shutdown
@@ -69,6 +71,14 @@ type ShutdownConfig struct {
 	InternalSignal *bool `default:"true" yaml:"internal-signal" json:"internal.signal,omitempty" property:"internal.signal"`
 	// offline request window length
 	OfflineRequestWindowTimeout string `yaml:"offline-request-window-timeout" json:"offlineRequestWindowTimeout,omitempty" property:"offlineRequestWindowTimeout"`
+	/*
+	 * PhaseTimeouts are the timeouts of the shutdown phases by the names: unregister, reject-requests,
+	 * provider-requests, consumer-requests, destroy and flush, see also GracefulShutdown.
+	 * The phase absent takes StepTimeout, except that the unregister phase takes ConsumerUpdateWaitTime + StepTimeout.
+	 */
+	PhaseTimeouts map[string]string `yaml:"phase-timeouts" json:"phase-timeouts,omitempty" property:"phase-timeouts"`
+	// HookTimeout is the timeout of each lifecycle listener notified at shutdown, see AddLifecycleListener
+	HookTimeout string `default:"3s" yaml:"hook-timeout" json:"hook-timeout,omitempty" property:"hook-timeout"`
 	// true -> new request will be rejected.
 	RejectRequest atomic.Bool
 	// active invocation
@@ -85,7 +95,7 @@ func (config *ShutdownConfig) Prefix() string {
 }
 
 func (config *ShutdownConfig) GetTimeout() time.Duration {
-	result, err := time.ParseDuration(config.Timeout)
+	result, err := common.ParseDuration(config.Timeout)
 	if err != nil {
 		logger.Errorf("The Timeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
 			config.Timeout, defaultTimeout.String(), err)
@@ -95,7 +105,7 @@ func (config *ShutdownConfig) GetTimeout() time.Duration {
 }
 
 func (config *ShutdownConfig) GetStepTimeout() time.Duration {
-	result, err := time.ParseDuration(config.StepTimeout)
+	result, err := common.ParseDuration(config.StepTimeout)
 	if err != nil {
 		logger.Errorf("The StepTimeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
 			config.StepTimeout, defaultStepTimeout.String(), err)
@@ -105,7 +115,7 @@ func (config *ShutdownConfig) GetStepTimeout() time.Duration {
 }
 
 func (config *ShutdownConfig) GetOfflineRequestWindowTimeout() time.Duration {
-	result, err := time.ParseDuration(config.OfflineRequestWindowTimeout)
+	result, err := common.ParseDuration(config.OfflineRequestWindowTimeout)
 	if err != nil {
 		logger.Errorf("The OfflineRequestWindowTimeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
 			config.OfflineRequestWindowTimeout, defaultOfflineRequestWindowTimeout.String(), err)
@@ -115,7 +125,7 @@ func (config *ShutdownConfig) GetOfflineRequestWindowTimeout() time.Duration {
 }
 
 func (config *ShutdownConfig) GetConsumerUpdateWaitTime() time.Duration {
-	result, err := time.ParseDuration(config.ConsumerUpdateWaitTime)
+	result, err := common.ParseDuration(config.ConsumerUpdateWaitTime)
 	if err != nil {
 		logger.Errorf("The ConsumerUpdateTimeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
 			config.ConsumerActiveCount.Load(), defaultConsumerUpdateWaitTime.String(), err)
@@ -124,6 +134,36 @@ func (config *ShutdownConfig) GetConsumerUpdateWaitTime() time.Duration {
 	return result
 }
 
+// GetPhaseTimeout returns the timeout of the shutdown @phase
+func (config *ShutdownConfig) GetPhaseTimeout(phase string) time.Duration {
+	defaultPhaseTimeout := config.GetStepTimeout()
+	if phase == ShutdownPhaseUnregister {
+		defaultPhaseTimeout += config.GetConsumerUpdateWaitTime()
+	}
+	timeout, ok := config.PhaseTimeouts[phase]
+	if !ok {
+		return defaultPhaseTimeout
+	}
+	result, err := common.ParseDuration(timeout)
+	if err != nil {
+		logger.Errorf("The timeout of the shutdown phase %s is invalid: %s, and we will use the default value: %s, err: %v",
+			phase, timeout, defaultPhaseTimeout.String(), err)
+		return defaultPhaseTimeout
+	}
+	return result
+}
+
+// GetHookTimeout returns the timeout of each lifecycle listener notified at shutdown
+func (config *ShutdownConfig) GetHookTimeout() time.Duration {
+	result, err := common.ParseDuration(config.HookTimeout)
+	if err != nil {
+		logger.Errorf("The HookTimeout configuration is invalid: %s, and we will use the default value: %s, err: %v",
+			config.HookTimeout, defaultHookTimeout.String(), err)
+		return defaultHookTimeout
+	}
+	return result
+}
+
 func (config *ShutdownConfig) GetInternalSignal() bool {
 	if config.InternalSignal == nil {
 		return false
@@ -168,6 +208,24 @@ func (scb *ShutdownConfigBuilder) SetInternalSignal(internalSignal bool) *Shutdo
 	return scb
 }
 
+func (scb *ShutdownConfigBuilder) SetHookTimeout(hookTimeout string) *ShutdownConfigBuilder {
+	scb.shutdownConfig.HookTimeout = hookTimeout
+	return scb
+}
+
+func (scb *ShutdownConfigBuilder) SetConsumerUpdateWaitTime(consumerUpdateWaitTime string) *ShutdownConfigBuilder {
+	scb.shutdownConfig.ConsumerUpdateWaitTime = consumerUpdateWaitTime
+	return scb
+}
+
+func (scb *ShutdownConfigBuilder) SetPhaseTimeout(phase, timeout string) *ShutdownConfigBuilder {
+	if scb.shutdownConfig.PhaseTimeouts == nil {
+		scb.shutdownConfig.PhaseTimeouts = make(map[string]string)
+	}
+	scb.shutdownConfig.PhaseTimeouts[phase] = timeout
+	return scb
+}
+
 func (scb *ShutdownConfigBuilder) Build() *ShutdownConfig {
 	defaults.MustSet(scb.shutdownConfig)
 	return scb.shutdownConfig
@@ -112,3 +112,91 @@ package config
 //	// test ignore steps
 //	BeforeShutdown()
 //}
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunShutdownPhases(t *testing.T) {
+	shutdownConfig := NewShutDownConfigBuilder().
+		SetTimeout("300ms").
+		SetPhaseTimeout(ShutdownPhaseDestroy, "50ms").
+		Build()
+	shutdownConfig.ProviderActiveCount.Store(2)
+	var ran []string
+	phases := []shutdownPhase{
+		{name: ShutdownPhaseRejectRequests, run: func(context.Context) string {
+			ran = append(ran, ShutdownPhaseRejectRequests)
+			return ""
+		}},
+		{name: ShutdownPhaseDestroy, run: func(ctx context.Context) string {
+			return runShutdownSteps(ctx, func() {
+				time.Sleep(time.Second)
+			}, func() {
+				t.Error("the step after the timeout of the phase is run")
+			})
+		}},
+		{name: ShutdownPhaseProviderRequests, run: func(ctx context.Context) string {
+			ran = append(ran, ShutdownPhaseProviderRequests)
+			return waitingProviderProcessedTimeout(ctx, shutdownConfig)
+		}},
+		{name: ShutdownPhaseFlush, run: func(context.Context) string {
+			ran = append(ran, ShutdownPhaseFlush)
+			return ""
+		}},
+	}
+
+	abandons := runShutdownPhases(shutdownConfig, phases)
+	assert.Equal(t, []string{ShutdownPhaseRejectRequests, ShutdownPhaseProviderRequests}, ran)
+	assert.Equal(t, []string{
+		"destroy (timeout)",
+		"provider-requests (2 provider requests in flight)",
+		"flush (total timeout 300ms exceeded)",
+	}, abandons)
+	// the destroy phase is cancelled at its timeout
+	time.Sleep(time.Second)
+}
+
+func TestRunShutdownSteps(t *testing.T) {
+	var ran int
+	step := func() {
+		ran++
+	}
+	assert.Equal(t, "", runShutdownSteps(context.Background(), step, step))
+	assert.Equal(t, 2, ran)
+
+	// the steps left after the context is done are skipped
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.Equal(t, "1 of 3 steps left", runShutdownSteps(ctx, step, cancel, step))
+	assert.Equal(t, 3, ran)
+}
+
+func TestWaitingConsumerProcessedTimeout(t *testing.T) {
+	shutdownConfig := NewShutDownConfigBuilder().Build()
+	shutdownConfig.ConsumerActiveCount.Store(1)
+	time.AfterFunc(50*time.Millisecond, func() {
+		shutdownConfig.ConsumerActiveCount.Dec()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, "", waitingConsumerProcessedTimeout(ctx, shutdownConfig))
+}
+
+func TestShutdownConfigGetPhaseTimeout(t *testing.T) {
+	shutdownConfig := NewShutDownConfigBuilder().
+		SetStepTimeout("2s").
+		SetConsumerUpdateWaitTime("1s").
+		SetPhaseTimeout(ShutdownPhaseDestroy, "5s").
+		SetPhaseTimeout(ShutdownPhaseFlush, "invalid").
+		Build()
+	assert.Equal(t, 3*time.Second, shutdownConfig.GetPhaseTimeout(ShutdownPhaseUnregister))
+	assert.Equal(t, 2*time.Second, shutdownConfig.GetPhaseTimeout(ShutdownPhaseProviderRequests))
+	assert.Equal(t, 5*time.Second, shutdownConfig.GetPhaseTimeout(ShutdownPhaseDestroy))
+	assert.Equal(t, 2*time.Second, shutdownConfig.GetPhaseTimeout(ShutdownPhaseFlush))
+}
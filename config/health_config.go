@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"time"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	"github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// HealthConfig decides which status checkers gate the liveness and the readiness probes served by the QoS server at
+// /live and /ready, e.g.
+//
+//	health:
+//	  timeout: 2s
+//	  readiness: [registry, provider]
+//
+// The built-in checkers are registry, config-center, provider and consumer, and the others are registered by
+// extension.SetStatusChecker. The checkers which don't gate a probe are only reported in its body.
+type HealthConfig struct {
+	// Timeout bounds each checker, which fails if it doesn't return in time, 3s by default
+	Timeout string `default:"3s" yaml:"timeout" json:"timeout,omitempty" property:"timeout"`
+	// Liveness are the checkers gating the liveness, none by default, so the application is live as long as the
+	// QoS server answers
+	Liveness []string `yaml:"liveness" json:"liveness,omitempty" property:"liveness"`
+	// Readiness are the checkers gating the readiness, all of the checkers gate it if it's empty
+	Readiness []string `yaml:"readiness" json:"readiness,omitempty" property:"readiness"`
+}
+
+// Init sets the defaults and checks the config
+func (c *HealthConfig) Init() error {
+	if err := defaults.Set(c); err != nil {
+		return err
+	}
+	if timeout, err := common.ParseDuration(c.Timeout); err != nil || timeout <= 0 {
+		return errors.Errorf("invalid health timeout %q", c.Timeout)
+	}
+	return nil
+}
+
+// GetTimeout returns the timeout of each checker
+func (c *HealthConfig) GetTimeout() time.Duration {
+	timeout, err := common.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return 3 * time.Second
+	}
+	return timeout
+}
+
+// GetHealthConfig returns the config of the health probes, which is the default one if it is absent
+func GetHealthConfig() *HealthConfig {
+	if rootConfig != nil && rootConfig.Health != nil {
+		return rootConfig.Health
+	}
+	c := NewHealthConfigBuilder().Build()
+	_ = defaults.Set(c)
+	return c
+}
+
+type HealthConfigBuilder struct {
+	healthConfig *HealthConfig
+}
+
+func NewHealthConfigBuilder() *HealthConfigBuilder {
+	return &HealthConfigBuilder{healthConfig: &HealthConfig{}}
+}
+
+func (hcb *HealthConfigBuilder) SetTimeout(timeout string) *HealthConfigBuilder {
+	hcb.healthConfig.Timeout = timeout
+	return hcb
+}
+
+func (hcb *HealthConfigBuilder) SetLiveness(liveness ...string) *HealthConfigBuilder {
+	hcb.healthConfig.Liveness = liveness
+	return hcb
+}
+
+func (hcb *HealthConfigBuilder) SetReadiness(readiness ...string) *HealthConfigBuilder {
+	hcb.healthConfig.Readiness = readiness
+	return hcb
+}
+
+func (hcb *HealthConfigBuilder) Build() *HealthConfig {
+	return hcb.healthConfig
+}
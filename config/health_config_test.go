@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+)
+
+func TestHealthConfigInit(t *testing.T) {
+	hc := NewHealthConfigBuilder().Build()
+	assert.NoError(t, hc.Init())
+	assert.Equal(t, 3*time.Second, hc.GetTimeout())
+	assert.Empty(t, hc.Readiness)
+
+	hc = NewHealthConfigBuilder().SetTimeout("500").SetReadiness("registry").Build()
+	assert.NoError(t, hc.Init())
+	assert.Equal(t, 500*time.Millisecond, hc.GetTimeout())
+
+	assert.EqualError(t, NewHealthConfigBuilder().SetTimeout("0s").Build().Init(), `invalid health timeout "0s"`)
+}
+
+func TestProviderAndConsumerStatus(t *testing.T) {
+	defer func(rc *RootConfig) { rootConfig = rc }(rootConfig)
+	rootConfig = nil
+	assert.Equal(t, status.OK("no service"), checkProvider(context.Background()))
+	assert.Equal(t, status.OK("no reference"), checkConsumer(context.Background()))
+
+	sc := NewServiceConfigBuilder().SetInterface("org.apache.dubbo.Greeter").Build()
+	unchecked := false
+	rootConfig = NewRootConfigBuilder().
+		SetProvider(NewProviderConfigBuilder().AddService("GreeterProvider", sc).Build()).
+		SetConsumer(NewConsumerConfigBuilder().
+			AddReference("GreeterClient", NewReferenceConfigBuilder().Build()).
+			AddReference("UserClient", &ReferenceConfig{Check: &unchecked}).Build()).
+		Build()
+	assert.Equal(t, status.Error("unexported services: GreeterProvider"), checkProvider(context.Background()))
+	// the references not checking their providers are ignored
+	assert.Equal(t, status.Error("references without available providers: GreeterClient"),
+		checkConsumer(context.Background()))
+
+	sc.InitExported()
+	sc.exported.Store(true)
+	assert.Equal(t, status.OK("1 services exported"), checkProvider(context.Background()))
+}
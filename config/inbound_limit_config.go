@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+// InboundLimitConfig limits the requests received by the servers of the getty based protocols like dubbo per
+// connection and per source ip, e.g.
+//
+//	protocols:
+//	  dubbo:
+//	    inbound-limit:
+//	      connection-requests: 2000
+//	      ip-bytes: 64mib
+//	      close-after-throttled: 1000
+//
+// Unlike the tps filter, the limits are enforced before the bodies of the requests are decoded, so a flood of large
+// or malformed requests costs little. The requests over the limits are answered by the throttled responses, and the
+// heartbeats are never limited. The absent or zero limits are unlimited, and the limiter is disabled if all of them
+// are unlimited.
+type InboundLimitConfig struct {
+	// ConnectionRequests is the max requests per second of a connection
+	ConnectionRequests int `yaml:"connection-requests" json:"connection-requests,omitempty" property:"connection-requests"`
+	// ConnectionBytes is the max bytes per second of a connection, like 10mib
+	ConnectionBytes string `yaml:"connection-bytes" json:"connection-bytes,omitempty" property:"connection-bytes"`
+	// IPRequests is the max requests per second of all the connections from a source ip
+	IPRequests int `yaml:"ip-requests" json:"ip-requests,omitempty" property:"ip-requests"`
+	// IPBytes is the max bytes per second of all the connections from a source ip, like 64mib
+	IPBytes string `yaml:"ip-bytes" json:"ip-bytes,omitempty" property:"ip-bytes"`
+	// CloseAfterThrottled closes the connection once so many requests in a row are throttled, the connection is
+	// never closed for the limits if it is 0
+	CloseAfterThrottled int `yaml:"close-after-throttled" json:"close-after-throttled,omitempty" property:"close-after-throttled"`
+	// IPIdleTimeout is how long the limit and the statistics of a source ip are kept after all its connections are
+	// closed, so that reconnecting doesn't reset them, 10m by default
+	IPIdleTimeout string `yaml:"ip-idle-timeout" json:"ip-idle-timeout,omitempty" property:"ip-idle-timeout"`
+}
@@ -82,6 +82,10 @@ func (m mockMetadataReport) StoreConsumerMetadata(*identifier.MetadataIdentifier
 	panic("implement me")
 }
 
+func (m mockMetadataReport) RemoveConsumerMetadata(*identifier.MetadataIdentifier) error {
+	panic("implement me")
+}
+
 func (m mockMetadataReport) SaveServiceMetadata(*identifier.ServiceMetadataIdentifier, *common.URL) error {
 	panic("implement me")
 }
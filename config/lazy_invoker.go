@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// lazyInvoker creates the invoker of a lazy reference at the first invocation, the invocations racing for the first
+// one wait for the creation. The creation failed is retried by the next invocation.
+type lazyInvoker struct {
+	url    *common.URL
+	create func() (protocol.Invoker, error)
+	check  func(protocol.Invoker) error
+
+	lock      sync.Mutex
+	invoker   atomic.Value
+	destroyed atomic.Bool
+}
+
+func newLazyInvoker(url *common.URL, create func() (protocol.Invoker, error), check func(protocol.Invoker) error) *lazyInvoker {
+	return &lazyInvoker{url: url, create: create, check: check}
+}
+
+func (li *lazyInvoker) GetURL() *common.URL {
+	return li.url
+}
+
+// IsAvailable is true before the invoker is created since it's unknown until the first invocation
+func (li *lazyInvoker) IsAvailable() bool {
+	if li.destroyed.Load() {
+		return false
+	}
+	if invoker := li.created(); invoker != nil {
+		return invoker.IsAvailable()
+	}
+	return true
+}
+
+func (li *lazyInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	invoker, err := li.getOrCreate()
+	if err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// GetDirectory returns the directory of the invoker, which is nil before the invoker is created
+func (li *lazyInvoker) GetDirectory() directory.Directory {
+	if holder, ok := li.created().(directory.Holder); ok {
+		return holder.GetDirectory()
+	}
+	return nil
+}
+
+// Destroy destroys the invoker if it has been created, the invocations after are failed
+func (li *lazyInvoker) Destroy() {
+	li.lock.Lock()
+	defer li.lock.Unlock()
+	li.destroyed.Store(true)
+	if invoker := li.created(); invoker != nil {
+		invoker.Destroy()
+	}
+}
+
+func (li *lazyInvoker) created() protocol.Invoker {
+	invoker, _ := li.invoker.Load().(protocol.Invoker)
+	return invoker
+}
+
+// getOrCreate returns the invoker, which is created by the first invocation and checked for the providers then
+func (li *lazyInvoker) getOrCreate() (protocol.Invoker, error) {
+	if invoker := li.created(); invoker != nil && !li.destroyed.Load() {
+		return invoker, nil
+	}
+	li.lock.Lock()
+	defer li.lock.Unlock()
+	if li.destroyed.Load() {
+		return nil, perrors.Errorf("the reference %s has been destroyed", li.url.Path)
+	}
+	if invoker := li.created(); invoker != nil {
+		return invoker, nil
+	}
+	logger.Infof("Refer the lazy reference %s at the first invocation", li.url.Path)
+	invoker, err := li.create()
+	if err != nil {
+		return nil, err
+	}
+	li.invoker.Store(invoker)
+	if err = li.check(invoker); err != nil {
+		return nil, err
+	}
+	return invoker, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestLazyInvokerFirstInvocationRacing(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.Greeter")
+	var created atomic.Int32
+	var invoker *protocol.BaseInvoker
+	li := newLazyInvoker(url, func() (protocol.Invoker, error) {
+		created.Inc()
+		// the latency of the subscription and the connections
+		time.Sleep(100 * time.Millisecond)
+		invoker = protocol.NewBaseInvoker(url)
+		return invoker, nil
+	}, func(protocol.Invoker) error {
+		return nil
+	})
+	assert.True(t, li.IsAvailable())
+	assert.Zero(t, created.Load())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+			assert.Nil(t, res.Error())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), created.Load())
+
+	li.Destroy()
+	assert.False(t, invoker.IsAvailable())
+	assert.False(t, li.IsAvailable())
+	res := li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+	assert.NotNil(t, res.Error())
+	assert.Equal(t, int32(1), created.Load())
+}
+
+func TestLazyInvokerCreateFailed(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.Greeter")
+	var created int
+	li := newLazyInvoker(url, func() (protocol.Invoker, error) {
+		created++
+		if created == 1 {
+			return nil, errors.New("connect failed")
+		}
+		return protocol.NewBaseInvoker(url), nil
+	}, func(protocol.Invoker) error {
+		return errors.New("no provider available")
+	})
+
+	res := li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+	assert.EqualError(t, res.Error(), "connect failed")
+	// retried, and the check fails the first invocation only
+	res = li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+	assert.EqualError(t, res.Error(), "no provider available")
+	res = li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+	assert.Nil(t, res.Error())
+	assert.Equal(t, 2, created)
+}
+
+func TestLazyInvokerDestroyBeforeCreated(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.Greeter")
+	li := newLazyInvoker(url, func() (protocol.Invoker, error) {
+		t.Fatal("the invoker should not be created after destroyed")
+		return nil, nil
+	}, func(protocol.Invoker) error {
+		return nil
+	})
+	li.Destroy()
+	assert.False(t, li.IsAvailable())
+	res := li.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil))
+	assert.NotNil(t, res.Error())
+}
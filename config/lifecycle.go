@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// Lifecycle is notified at the points of the lifecycle of the application, see extension.LifecycleEvent
+type Lifecycle = extension.Lifecycle
+
+var (
+	lifecyclesLock sync.RWMutex
+	lifecycles     []Lifecycle
+)
+
+func init() {
+	// the logs are flushed once the application is shut down
+	extension.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		if event == extension.LifecycleAfterShutdown {
+			flushLogger()
+		}
+	}))
+}
+
+// AddLifecycleListener adds the listener of the application notified at the points of its lifecycle. The listeners
+// are notified synchronously in the order they're added at startup, and in the reverse order at shutdown like the
+// defers. The listeners of the framework components are notified before the ones of the application at startup and
+// after them at shutdown. A panicking listener doesn't break the others, and each listener notified at shutdown is
+// bounded by ShutdownConfig.HookTimeout.
+func AddLifecycleListener(l Lifecycle) {
+	lifecyclesLock.Lock()
+	defer lifecyclesLock.Unlock()
+	lifecycles = append(lifecycles, l)
+}
+
+// AddLifecycleHook adds the @hook run at the @event of the lifecycle of the application, see AddLifecycleListener
+func AddLifecycleHook(event extension.LifecycleEvent, hook func()) {
+	AddLifecycleListener(extension.LifecycleFunc(func(e extension.LifecycleEvent) {
+		if e == event {
+			hook()
+		}
+	}))
+}
+
+// fireLifecycleEvent notifies the listeners of the framework components and the application of @event in order
+func fireLifecycleEvent(event extension.LifecycleEvent) {
+	lifecyclesLock.RLock()
+	listeners := append(extension.GetLifecycleListeners(), lifecycles...)
+	lifecyclesLock.RUnlock()
+	timeout := time.Duration(0)
+	if event.IsShutdown() {
+		timeout = GetShutDown().GetHookTimeout()
+		for i, j := 0, len(listeners)-1; i < j; i, j = i+1, j-1 {
+			listeners[i], listeners[j] = listeners[j], listeners[i]
+		}
+	}
+	for _, l := range listeners {
+		notifyLifecycle(l, event, timeout)
+	}
+}
+
+// notifyLifecycle notifies @l of @event, which is abandoned after @timeout unless it's zero
+func notifyLifecycle(l Lifecycle, event extension.LifecycleEvent, timeout time.Duration) {
+	done := make(chan struct{})
+	notify := func() {
+		defer close(done)
+		defer func() {
+			if e := recover(); e != nil {
+				logger.Errorf("The lifecycle listener panics at %s: %v\n%s", event, e, debug.Stack())
+			}
+		}()
+		l.OnLifecycleEvent(event)
+	}
+	if timeout <= 0 {
+		notify()
+		return
+	}
+	go notify()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logger.Warnf("The lifecycle listener at %s is abandoned after the timeout %v", event, timeout)
+	}
+}
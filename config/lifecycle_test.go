@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// recorder records the lifecycle events notified
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) hook(name string) func() {
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, name)
+	}
+}
+
+func (r *recorder) listener(name string) Lifecycle {
+	return extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		r.hook(name + ":" + string(event))()
+	})
+}
+
+func (r *recorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// withLifecycles runs @f with the listeners of the application cleared, and the shutdown config of the hook @timeout
+func withLifecycles(timeout string, f func()) {
+	originLifecycles, originRoot := lifecycles, rootConfig
+	defer func() {
+		lifecycles, rootConfig = originLifecycles, originRoot
+	}()
+	lifecycles = nil
+	rootConfig = NewRootConfigBuilder().SetShutDown(NewShutDownConfigBuilder().SetHookTimeout(timeout).Build()).Build()
+	f()
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	withLifecycles("1s", func() {
+		r := &recorder{}
+		AddLifecycleListener(r.listener("first"))
+		AddLifecycleHook(extension.LifecycleServicesExported, r.hook("exported"))
+		AddLifecycleListener(r.listener("second"))
+
+		// in the order they're added at startup
+		fireLifecycleEvent(extension.LifecycleServicesExported)
+		assert.Equal(t, []string{"first:services-exported", "exported", "second:services-exported"}, r.take())
+		fireLifecycleEvent(extension.LifecycleConfigLoaded)
+		assert.Equal(t, []string{"first:config-loaded", "second:config-loaded"}, r.take())
+
+		// in the reverse order at shutdown
+		fireLifecycleEvent(extension.LifecycleBeforeShutdown)
+		assert.Equal(t, []string{"second:before-shutdown", "first:before-shutdown"}, r.take())
+	})
+}
+
+func TestLifecyclePanicAndTimeout(t *testing.T) {
+	withLifecycles("50ms", func() {
+		r := &recorder{}
+		AddLifecycleHook(extension.LifecycleReferencesReady, func() {
+			panic("references-ready")
+		})
+		AddLifecycleHook(extension.LifecycleReferencesReady, r.hook("ready"))
+		AddLifecycleHook(extension.LifecycleAfterShutdown, r.hook("shutdown"))
+		AddLifecycleHook(extension.LifecycleAfterShutdown, func() {
+			time.Sleep(time.Second)
+		})
+		AddLifecycleHook(extension.LifecycleAfterShutdown, func() {
+			panic("after-shutdown")
+		})
+
+		// the panicking hooks don't break the others
+		fireLifecycleEvent(extension.LifecycleReferencesReady)
+		assert.Equal(t, []string{"ready"}, r.take())
+
+		// the slow hook is abandoned at the timeout at shutdown
+		start := time.Now()
+		fireLifecycleEvent(extension.LifecycleAfterShutdown)
+		assert.True(t, time.Since(start) < 500*time.Millisecond)
+		assert.Equal(t, []string{"shutdown"}, r.take())
+	})
+}
@@ -19,7 +19,11 @@ package config
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
 import (
@@ -28,6 +32,13 @@ import (
 	"github.com/creasty/defaults"
 
 	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"gopkg.in/yaml.v2"
 )
 
 import (
@@ -36,6 +47,20 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 )
 
+// the initializers of the logger, see LoggerConfig
+const (
+	logByDefault int32 = iota
+	logByEnv
+	logByInitLog
+)
+
+// logInitializer is the initializer of the current logger
+var logInitializer int32
+
+// LoggerConfig is the logger section of the config. The logger is initialized by the precedence: InitLog called by
+// the application > the logger section > the log config file of the env AppLogConfFile > the defaults. The logs of
+// loading the config files go to the logger of InitLog or AppLogConfFile, or the default console logger, since the
+// logger section is read with the config files.
 type LoggerConfig struct {
 	// logger driver default zap
 	Driver string `default:"zap" yaml:"driver"`
@@ -51,6 +76,11 @@ type LoggerConfig struct {
 
 	// logger file
 	File *File `yaml:"file"`
+
+	// ZapConfig and LumberjackConfig are the zap config and the rolling file config in the shape of the log config
+	// file of InitLog, the logger is built with them instead of the fields above once ZapConfig is set
+	ZapConfig        map[string]interface{} `yaml:"zap-config" json:"zap-config,omitempty"`
+	LumberjackConfig map[string]interface{} `yaml:"lumberjack-config" json:"lumberjack-config,omitempty"`
 }
 
 type File struct {
@@ -82,6 +112,12 @@ func (l *LoggerConfig) Init() error {
 	if err = l.check(); err != nil {
 		return err
 	}
+	if l.ZapConfig != nil {
+		return initZapLogger(map[string]interface{}{
+			"zap-config":        l.ZapConfig,
+			"lumberjack-config": l.LumberjackConfig,
+		})
+	}
 
 	if log, err = extension.GetLogger(l.Driver, l.toURL()); err != nil {
 		return err
@@ -92,6 +128,119 @@ func (l *LoggerConfig) Init() error {
 	return nil
 }
 
+// InitLog initializes the logger by the log config file @logConfFile, which has the zap config as zap-config and the
+// rolling file config as lumberjack-config. The logger initialized by InitLog is kept over the logger section of the
+// config and the env AppLogConfFile.
+func InitLog(logConfFile string) error {
+	if err := initLogConfFile(logConfFile); err != nil {
+		return err
+	}
+	atomic.StoreInt32(&logInitializer, logByInitLog)
+	return nil
+}
+
+// initEnvLog initializes the logger by the log config file of the env AppLogConfFile unless InitLog is called
+func initEnvLog() error {
+	if atomic.LoadInt32(&logInitializer) == logByInitLog {
+		return nil
+	}
+	file := os.Getenv(constant.AppLogConfFile)
+	if file == "" {
+		atomic.StoreInt32(&logInitializer, logByDefault)
+		return nil
+	}
+	if err := initLogConfFile(file); err != nil {
+		return perrors.WithMessagef(err, "the env %s", constant.AppLogConfFile)
+	}
+	atomic.StoreInt32(&logInitializer, logByEnv)
+	return nil
+}
+
+// initLogger initializes the logger by the logger section of @rc unless the logger is initialized by InitLog, or by
+// AppLogConfFile and the logger section is absent
+func (rc *RootConfig) initLogger() error {
+	switch atomic.LoadInt32(&logInitializer) {
+	case logByInitLog:
+		return nil
+	case logByEnv:
+		if !rc.loggerConfigured() {
+			return nil
+		}
+	}
+	return rc.Logger.Init()
+}
+
+// loggerConfigured returns whether the logger section is configured in the config files or the config center, which
+// is always the case of the root config built by the api
+func (rc *RootConfig) loggerConfigured() bool {
+	if rc.sources == nil || rc.sources.get(SourceLocal) == nil {
+		return true
+	}
+	prefix := rc.Logger.Prefix() + "."
+	for key := range rc.sources.effective() {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func initLogConfFile(logConfFile string) error {
+	content, err := ioutil.ReadFile(logConfFile)
+	if err != nil {
+		return perrors.WithMessagef(err, "read the log config file %s", logConfFile)
+	}
+	conf := make(map[string]interface{})
+	if err = yaml.Unmarshal(content, &conf); err != nil {
+		return perrors.WithMessagef(err, "parse the log config file %s", logConfFile)
+	}
+	return initZapLogger(conf)
+}
+
+// initZapLogger initializes the zap logger of dubbo and getty by @conf in the shape of logger.Config, the fields absent
+// from the zap config are the ones of the default console logger
+func initZapLogger(conf map[string]interface{}) error {
+	content, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	loggerConf := &logger.Config{ZapConfig: defaultZapConfig()}
+	if err = yaml.Unmarshal(content, loggerConf); err != nil {
+		return perrors.WithMessage(err, "the zap config")
+	}
+	if loggerConf.ZapConfig == nil {
+		loggerConf.ZapConfig = defaultZapConfig()
+	}
+	// InitLogger falls back to the default logger if the zap config is invalid, which is checked in advance
+	if _, err = loggerConf.ZapConfig.Build(); err != nil {
+		return perrors.WithMessage(err, "the zap config")
+	}
+	logger.InitLogger(loggerConf)
+	getty.SetLogger(logger.GetLogger())
+	return nil
+}
+
+func defaultZapConfig() *zap.Config {
+	return &zap.Config{
+		Level:    zap.NewAtomicLevelAt(zap.InfoLevel),
+		Encoding: "console",
+		EncoderConfig: zapcore.EncoderConfig{
+			TimeKey:        "time",
+			LevelKey:       "level",
+			NameKey:        "logger",
+			CallerKey:      "caller",
+			MessageKey:     "message",
+			StacktraceKey:  "stacktrace",
+			EncodeLevel:    zapcore.CapitalColorLevelEncoder,
+			EncodeTime:     zapcore.ISO8601TimeEncoder,
+			EncodeDuration: zapcore.SecondsDurationEncoder,
+			EncodeCaller:   zapcore.ShortCallerEncoder,
+		},
+		OutputPaths:      []string{"stdout"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+}
+
 func (l *LoggerConfig) check() error {
 	if err := defaults.Set(l); err != nil {
 		return err
@@ -115,9 +264,17 @@ func (l *LoggerConfig) toURL() *common.URL {
 	return url
 }
 
-// DynamicUpdateProperties dynamically update properties.
+// DynamicUpdateProperties dynamically update properties, only the level could be changed at runtime.
 func (l *LoggerConfig) DynamicUpdateProperties(new *LoggerConfig) {
-
+	if l == nil || new == nil || len(new.Level) == 0 || new.Level == l.Level {
+		return
+	}
+	if !logger.SetLoggerLevel(new.Level) {
+		logger.Warnf("The logger of driver %s does not support changing the level at runtime", l.Driver)
+		return
+	}
+	logger.Infof("The logger level is changed from %s to %s", l.Level, new.Level)
+	l.Level = new.Level
 }
 
 type LoggerConfigBuilder struct {
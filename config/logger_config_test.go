@@ -18,6 +18,11 @@
 package config
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 )
 
@@ -25,6 +30,11 @@ import (
 	"github.com/dubbogo/gost/log/logger"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
 func TestLoggerInit(t *testing.T) {
@@ -83,3 +93,71 @@ func TestNewLoggerConfigBuilder(t *testing.T) {
 	assert.Equal(t, *config.File.Compress, true)
 	assert.Equal(t, config.File.MaxBackups, 5)
 }
+
+func TestLoggerDynamicUpdateLevel(t *testing.T) {
+	config := NewLoggerConfigBuilder().SetDriver("zap").SetLevel("info").Build()
+	assert.NoError(t, config.Init())
+	_, ok := logger.GetLogger().(logger.OpsLogger)
+	assert.True(t, ok)
+
+	config.DynamicUpdateProperties(&LoggerConfig{Level: "error"})
+	assert.Equal(t, "error", config.Level)
+	// the level absent in the config center is kept
+	config.DynamicUpdateProperties(&LoggerConfig{})
+	assert.Equal(t, "error", config.Level)
+}
+
+func TestLoggerPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	require.NoError(t, err)
+	previous := logger.GetLogger()
+	defer func() {
+		_ = os.RemoveAll(dir)
+		_ = os.Unsetenv(constant.AppLogConfFile)
+		atomic.StoreInt32(&logInitializer, logByDefault)
+		logger.SetLogger(previous)
+	}()
+	// logConf writes the log config file logging to the file @name.log
+	logConf := func(name string) string {
+		path := filepath.Join(dir, name+".yml")
+		content := fmt.Sprintf("zap-config:\n  level: debug\n  encoding: json\n  outputPaths:\n    - %s\n",
+			filepath.Join(dir, name+".log"))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	logged := func(name string) string {
+		content, _ := ioutil.ReadFile(filepath.Join(dir, name+".log"))
+		return string(content)
+	}
+
+	t.Run("invalid env", func(t *testing.T) {
+		require.NoError(t, os.Setenv(constant.AppLogConfFile, filepath.Join(dir, "absent.yml")))
+		err := Load(WithPath("./testdata/config/formats/application.yaml"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), constant.AppLogConfFile)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		require.NoError(t, os.Setenv(constant.AppLogConfFile, logConf("env")))
+		assert.NoError(t, Load(WithPath("./testdata/config/formats/application.yaml")))
+		logger.Info("after loading")
+		// the logs of loading the config files go to the logger of the env as well
+		assert.Contains(t, logged("env"), "Merge the config file")
+		assert.Contains(t, logged("env"), "after loading")
+	})
+
+	t.Run("logger section over env", func(t *testing.T) {
+		assert.NoError(t, Load(WithPath("./testdata/config/logger/log.yaml")))
+		logger.Info("logger section")
+		assert.NotContains(t, logged("env"), "logger section")
+		_, ok := logger.GetLogger().(logger.OpsLogger)
+		assert.True(t, ok)
+	})
+
+	t.Run("InitLog over logger section", func(t *testing.T) {
+		assert.NoError(t, InitLog(logConf("init")))
+		assert.NoError(t, Load(WithPath("./testdata/config/logger/log.yaml")))
+		logger.Debug("init log")
+		assert.Contains(t, logged("init"), "init log")
+	})
+}
@@ -17,6 +17,11 @@
 
 package config
 
+import (
+	"net/url"
+	"strconv"
+)
+
 import (
 	"github.com/dubbogo/gost/log/logger"
 
@@ -39,6 +44,18 @@ type MetadataReportConfig struct {
 	Timeout   string `yaml:"timeout" json:"timeout,omitempty"`
 	Group     string `yaml:"group" json:"group,omitempty"`
 	Namespace string `yaml:"namespace" json:"namespace,omitempty"`
+	// Credential is the access key, the secret key and the tls of the connection besides Username and Password
+	Credential *CredentialConfig `yaml:"credential" json:"credential,omitempty"`
+	// TLSProfile is the tls profile of the connection taking the place of Credential.TLS, see RootConfig.TLSProfiles
+	TLSProfile string `yaml:"tls-profile" json:"tls-profile,omitempty"`
+	// SyncReport blocks exporting and referring the services until the metadata is reported,
+	// or else the metadata is reported in background
+	SyncReport bool `yaml:"sync-report" json:"sync-report,omitempty"`
+	// ReportConsumerDefinition reports the parameters of the references, which dubbo-admin shows as the consumers
+	// of the services
+	ReportConsumerDefinition bool `yaml:"report-consumer-definition" json:"report-consumer-definition,omitempty"`
+	// Params are the extra parameters of the metadata report, e.g. the ttl and the cluster mode of redis
+	Params map[string]string `yaml:"params" json:"params,omitempty" property:"params"`
 	// metadataType of this application is defined by application config, local or remote
 	metadataType string
 }
@@ -53,6 +70,10 @@ func (mc *MetadataReportConfig) Init(rc *RootConfig) error {
 		return nil
 	}
 	mc.metadataType = rc.Application.MetadataType
+	mc.Group = resolveProfile(mc.Group, rc.Profiles)
+	if err := mc.Credential.check(); err != nil {
+		return err
+	}
 	return mc.StartMetadataReport()
 }
 
@@ -66,11 +87,24 @@ func (mc *MetadataReportConfig) ToUrl() (*common.URL, error) {
 		common.WithParamsValue(constant.MetadataReportGroupKey, mc.Group),
 		common.WithParamsValue(constant.MetadataReportNamespaceKey, mc.Namespace),
 		common.WithParamsValue(constant.MetadataTypeKey, mc.metadataType),
+		common.WithParamsValue(constant.SyncReportKey, strconv.FormatBool(mc.SyncReport)),
+		common.WithParamsValue(constant.ReportConsumerDefinitionKey, strconv.FormatBool(mc.ReportConsumerDefinition)),
 		common.WithParamsValue(constant.ClientNameKey, clientNameID(mc, mc.Protocol, mc.Address)),
 	)
 	if err != nil || len(res.Protocol) == 0 {
 		return nil, perrors.New("Invalid MetadataReport Config.")
 	}
+	for k, v := range mc.Params {
+		if len(res.GetParam(k, "")) == 0 {
+			res.SetParam(k, v)
+		}
+	}
+	credentials := url.Values{}
+	mc.Credential.setParams(credentials, constant.MetadataReportAccessKey, constant.MetadataReportSecretKey)
+	setTLSProfileParams(credentials, mc.TLSProfile)
+	for key := range credentials {
+		res.SetParam(key, credentials.Get(key))
+	}
 	res.SetParam("metadata", res.Protocol)
 	return res, nil
 }
@@ -107,6 +141,18 @@ func publishServiceDefinition(url *common.URL) {
 	}
 }
 
+// unpublishServiceDefinition removes the metadata of the unexported @url from the remote metadata report
+func unpublishServiceDefinition(url *common.URL) {
+	if url.GetParam(constant.MetadataTypeKey, "") != constant.RemoteMetadataStorageType {
+		return
+	}
+	if remoteMetadataService, err := extension.GetRemoteMetadataService(); err == nil && remoteMetadataService != nil {
+		if err = remoteMetadataService.UnpublishServiceDefinition(url); err != nil {
+			logger.Warnf("Unpublish the service definition of %s failed: %v", url.ServiceKey(), err)
+		}
+	}
+}
+
 // selectMetadataServiceExportedURL get already be exported url
 func selectMetadataServiceExportedURL() *common.URL {
 	var selectedUrl *common.URL
@@ -170,6 +216,26 @@ func (mrcb *MetadataReportConfigBuilder) SetGroup(group string) *MetadataReportC
 	return mrcb
 }
 
+func (mrcb *MetadataReportConfigBuilder) SetSyncReport(syncReport bool) *MetadataReportConfigBuilder {
+	mrcb.metadataReportConfig.SyncReport = syncReport
+	return mrcb
+}
+
+func (mrcb *MetadataReportConfigBuilder) SetReportConsumerDefinition(report bool) *MetadataReportConfigBuilder {
+	mrcb.metadataReportConfig.ReportConsumerDefinition = report
+	return mrcb
+}
+
+func (mrcb *MetadataReportConfigBuilder) SetTLSProfile(tlsProfile string) *MetadataReportConfigBuilder {
+	mrcb.metadataReportConfig.TLSProfile = tlsProfile
+	return mrcb
+}
+
+func (mrcb *MetadataReportConfigBuilder) SetParams(params map[string]string) *MetadataReportConfigBuilder {
+	mrcb.metadataReportConfig.Params = params
+	return mrcb
+}
+
 func (mrcb *MetadataReportConfigBuilder) Build() *MetadataReportConfig {
 	return mrcb.metadataReportConfig
 }
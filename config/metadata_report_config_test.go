@@ -37,6 +37,9 @@ func TestNewMetadataReportConfigBuilder(t *testing.T) {
 		SetPassword("123456").
 		SetTimeout("10s").
 		SetGroup("dubbo").
+		SetSyncReport(true).
+		SetReportConsumerDefinition(true).
+		SetParams(map[string]string{constant.MetadataReportTTLKey: "10m"}).
 		Build()
 
 	assert.Equal(t, config.IsValid(), true)
@@ -45,4 +48,22 @@ func TestNewMetadataReportConfigBuilder(t *testing.T) {
 	url, err := config.ToUrl()
 	assert.NoError(t, err)
 	assert.Equal(t, url.GetParam(constant.TimeoutKey, "3s"), "10s")
+	assert.True(t, url.GetParamBool(constant.SyncReportKey, false))
+	assert.True(t, url.GetParamBool(constant.ReportConsumerDefinitionKey, false))
+	assert.Equal(t, "10m", url.GetParam(constant.MetadataReportTTLKey, ""))
+}
+
+func TestMetadataReportConfigCredential(t *testing.T) {
+	config := NewMetadataReportConfigBuilder().
+		SetProtocol("nacos").
+		SetAddress("127.0.0.1:8848").
+		Build()
+	config.Credential = &CredentialConfig{AccessKey: "ak", SecretKey: "sk", TLS: &TLSConfig{InsecureSkipVerify: true}}
+
+	url, err := config.ToUrl()
+	assert.NoError(t, err)
+	assert.Equal(t, "ak", url.GetParam(constant.MetadataReportAccessKey, ""))
+	assert.Equal(t, "sk", url.GetParam(constant.MetadataReportSecretKey, ""))
+	assert.Empty(t, url.GetParam(constant.CACert, ""))
+	assert.True(t, url.GetParamBool(constant.TLSInsecureSkipVerify, false))
 }
@@ -27,11 +27,20 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 )
 
-// MethodConfig defines method config
+// methodScopedKeys are the keys configured by the methods, whose values could be inherited from the service and
+// matched by the wildcard methods
+var methodScopedKeys = []string{
+	constant.TimeoutKey, constant.RetriesKey, constant.LoadbalanceKey, constant.StickyKey,
+	constant.TPSLimitRateKey, constant.TPSLimitIntervalKey, constant.TPSLimitStrategyKey,
+}
+
+// MethodConfig defines method config, the Name could be a wildcard like get* or *, which configures the methods
+// matching it. The values not set are inherited from the service or the reference.
 type MethodConfig struct {
 	InterfaceId                 string
 	InterfaceName               string
@@ -44,8 +53,18 @@ type MethodConfig struct {
 	TpsLimitStrategy            string `yaml:"tps.limit.strategy" json:"tps.limit.strategy,omitempty" property:"tps.limit.strategy"`
 	ExecuteLimit                string `yaml:"execute.limit" json:"execute.limit,omitempty" property:"execute.limit"`
 	ExecuteLimitRejectedHandler string `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
+	ExecuteLimitQueue           string `yaml:"execute.limit.queue" json:"execute.limit.queue,omitempty" property:"execute.limit.queue"`
+	ExecuteLimitQueueTimeout    string `yaml:"execute.limit.queue.timeout" json:"execute.limit.queue.timeout,omitempty" property:"execute.limit.queue.timeout"`
 	Sticky                      bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout              string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
+	Retryable                   string `yaml:"retryable" json:"retryable,omitempty" property:"retryable"`
+	RetryBackoff                string `yaml:"retry.backoff" json:"retry.backoff,omitempty" property:"retry.backoff"`
+	RetryBackoffMultiplier      string `yaml:"retry.backoff.multiplier" json:"retry.backoff.multiplier,omitempty" property:"retry.backoff.multiplier"`
+	RetryOn                     string `yaml:"retry.on" json:"retry.on,omitempty" property:"retry.on"`
+	Cache                       string `yaml:"cache" json:"cache,omitempty" property:"cache"`
+	CacheSize                   string `yaml:"cache.size" json:"cache.size,omitempty" property:"cache.size"`
+	CacheTTL                    string `yaml:"cache.ttl" json:"cache.ttl,omitempty" property:"cache.ttl"`
+	Validation                  string `yaml:"validation" json:"validation,omitempty" property:"validation"`
 }
 
 // nolint
@@ -57,6 +76,18 @@ func (m *MethodConfig) Prefix() string {
 	return constant.Dubbo + "." + m.InterfaceName + "." + m.Name + "."
 }
 
+// resolveMethodParams sets the values of the wildcard methods, like methods.get*.timeout, to the methods of @url
+// matching them, like methods.getUser.timeout, so that the registries see the values of every method
+func resolveMethodParams(url *common.URL) {
+	for _, method := range url.Methods {
+		for _, key := range methodScopedKeys {
+			if v := url.GetMethodParam(method, key, ""); v != "" {
+				url.SetParam(constant.MethodKeys+"."+method+"."+key, v)
+			}
+		}
+	}
+}
+
 func (m *MethodConfig) Init() error {
 	return m.check()
 }
@@ -86,7 +117,7 @@ func (m *MethodConfig) check() error {
 	}
 
 	if m.TpsLimitInterval != "" {
-		tpsLimitInterval, err := strconv.ParseInt(m.TpsLimitInterval, 0, 0)
+		tpsLimitInterval, err := common.ParseDuration(m.TpsLimitInterval)
 		if err != nil {
 			return fmt.Errorf("[MethodConfig] Cannot parse the configuration tps.limit.interval for method %s, please check your configuration", qualifieldMethodName)
 		}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestResolveMethodParams(t *testing.T) {
+	u, err := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.HelloService?"+
+		"timeout=3s&methods.*.timeout=2s&methods.get*.timeout=1s&methods.getUser.timeout=500ms&"+
+		"methods.get*.tps.limit.rate=10&methods.list*.loadbalance=roundrobin",
+		common.WithMethods([]string{"getUser", "getOrder", "listUsers", "sayHello"}))
+	assert.Nil(t, err)
+
+	resolveMethodParams(u)
+	for key, want := range map[string]string{
+		"methods.getUser.timeout":          "500ms",
+		"methods.getOrder.timeout":         "1s",
+		"methods.listUsers.timeout":        "2s",
+		"methods.sayHello.timeout":         "2s",
+		"methods.getUser.tps.limit.rate":   "10",
+		"methods.getOrder.tps.limit.rate":  "10",
+		"methods.listUsers.tps.limit.rate": "",
+		"methods.listUsers.loadbalance":    "roundrobin",
+		"methods.sayHello.loadbalance":     "",
+		"methods.sayHello.retries":         "",
+		constant.TimeoutKey:                "3s",
+	} {
+		assert.Equal(t, want, u.GetParam(key, ""), key)
+	}
+}
@@ -19,12 +19,15 @@ package config
 
 import (
 	"strconv"
+	"strings"
 )
 
 import (
 	"github.com/creasty/defaults"
 
 	"github.com/pkg/errors"
+
+	"github.com/prometheus/common/model"
 )
 
 import (
@@ -41,7 +44,16 @@ type MetricConfig struct {
 	Protocol    string            `default:"prometheus" yaml:"protocol" json:"protocol,omitempty" property:"protocol"`
 	Prometheus  *PrometheusConfig `yaml:"prometheus" json:"prometheus" property:"prometheus"`
 	Aggregation *AggregateConfig  `yaml:"aggregation" json:"aggregation" property:"aggregation"`
-	rootConfig  *RootConfig
+	Histogram   *HistogramConfig  `yaml:"histogram" json:"histogram" property:"histogram"`
+	MethodLabel *bool             `default:"true" yaml:"method-label" json:"method-label,omitempty" property:"method-label"`
+	// Namespace is prepended to the names of all the metrics with "_", like myapp_dubbo_consumer_requests_total
+	Namespace string `yaml:"namespace" json:"namespace,omitempty" property:"namespace"`
+	// ConstLabels are added to all the series, like application: shop and version: 1.0.0. The labels named like the
+	// tags of a metric are ignored for the metric.
+	ConstLabels map[string]string `yaml:"const-labels" json:"const-labels,omitempty" property:"const-labels"`
+	// CollectInterval is the interval the runtime, the goroutine pools and the logger are sampled, like 5s
+	CollectInterval string `default:"5s" yaml:"collect-interval" json:"collect-interval,omitempty" property:"collect-interval"`
+	rootConfig      *RootConfig
 }
 
 type AggregateConfig struct {
@@ -50,6 +62,12 @@ type AggregateConfig struct {
 	TimeWindowSeconds int   `default:"120" yaml:"time-window-seconds" json:"time-window-seconds,omitempty" property:"time-window-seconds"`
 }
 
+// HistogramConfig is the config of the histograms, like the duration of requests
+type HistogramConfig struct {
+	// Buckets are the increasing upper bounds of the buckets, the default buckets of the registry are used if empty
+	Buckets []float64 `yaml:"buckets" json:"buckets,omitempty" property:"buckets"`
+}
+
 type PrometheusConfig struct {
 	Exporter    *Exporter          `yaml:"exporter" json:"exporter,omitempty" property:"exporter"`
 	Pushgateway *PushgatewayConfig `yaml:"pushgateway" json:"pushgateway,omitempty" property:"pushgateway"`
@@ -88,6 +106,17 @@ func (mc *MetricConfig) Init(rc *RootConfig) error {
 	if err := verify(mc); err != nil {
 		return err
 	}
+	if mc.Namespace != "" && !model.IsValidMetricName(model.LabelValue(mc.Namespace)) {
+		return errors.Errorf("invalid metrics namespace %q", mc.Namespace)
+	}
+	for name := range mc.ConstLabels {
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return errors.Errorf("invalid metrics const label %q", name)
+		}
+	}
+	if interval, err := common.ParseDuration(mc.CollectInterval); err != nil || interval <= 0 {
+		return errors.Errorf("invalid metrics collect-interval %q", mc.CollectInterval)
+	}
 	mc.rootConfig = rc
 	metrics.Init(mc.toURL())
 	return nil
@@ -114,10 +143,26 @@ func (mc *MetricConfig) DynamicUpdateProperties(newMetricConfig *MetricConfig) {
 func (mc *MetricConfig) toURL() *common.URL {
 	url, _ := common.NewURL("localhost", common.WithProtocol(mc.Protocol))
 	url.SetParam(constant.PrometheusExporterEnabledKey, strconv.FormatBool(*mc.Enable))
+	url.SetParam(constant.MetricsEnabledKey, strconv.FormatBool(*mc.Enable))
+	url.SetParam(constant.MetricsCollectIntervalKey, mc.CollectInterval)
 	url.SetParam(constant.PrometheusExporterMetricsPortKey, mc.Port)
 	url.SetParam(constant.PrometheusExporterMetricsPathKey, mc.Path)
 	url.SetParam(constant.ApplicationKey, mc.rootConfig.Application.Name)
 	url.SetParam(constant.AppVersionKey, mc.rootConfig.Application.Version)
+	url.SetParam(constant.MethodLabelEnabledKey, strconv.FormatBool(mc.MethodLabel == nil || *mc.MethodLabel))
+	if mc.Namespace != "" {
+		url.SetParam(constant.MetricsNamespaceKey, mc.Namespace)
+	}
+	for name, value := range mc.ConstLabels {
+		url.SetParam(constant.MetricsConstLabelKeyPrefix+name, value)
+	}
+	if mc.Histogram != nil && len(mc.Histogram.Buckets) > 0 {
+		buckets := make([]string, 0, len(mc.Histogram.Buckets))
+		for _, bucket := range mc.Histogram.Buckets {
+			buckets = append(buckets, strconv.FormatFloat(bucket, 'f', -1, 64))
+		}
+		url.SetParam(constant.HistogramBucketsKey, strings.Join(buckets, ","))
+	}
 	if mc.Aggregation != nil {
 		url.SetParam(constant.AggregationEnabledKey, strconv.FormatBool(*mc.Aggregation.Enabled))
 		url.SetParam(constant.AggregationBucketNumKey, strconv.Itoa(mc.Aggregation.BucketNum))
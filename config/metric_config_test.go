@@ -19,12 +19,18 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
 )
 
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
 func TestMetricConfigBuilder(t *testing.T) {
 	config := NewMetricConfigBuilder().Build()
 	err := config.Init(&RootConfig{Application: &ApplicationConfig{Name: "dubbo", Version: "1.0.0"}})
@@ -32,3 +38,41 @@ func TestMetricConfigBuilder(t *testing.T) {
 	reporterConfig := config.ToReporterConfig()
 	assert.Equal(t, string(reporterConfig.Mode), "pull")
 }
+
+func TestMetricConfigToURL(t *testing.T) {
+	enable, methodLabel := true, false
+	config := NewMetricConfigBuilder().Build()
+	config.Enable = &enable
+	config.MethodLabel = &methodLabel
+	config.Histogram = &HistogramConfig{Buckets: []float64{0.005, 0.1, 1}}
+	config.rootConfig = &RootConfig{Application: &ApplicationConfig{Name: "dubbo", Version: "1.0.0"}}
+	url := config.toURL()
+	assert.Equal(t, "0.005,0.1,1", url.GetParam(constant.HistogramBucketsKey, ""))
+	assert.Equal(t, "false", url.GetParam(constant.MethodLabelEnabledKey, ""))
+
+	config.Namespace = "shop"
+	config.ConstLabels = map[string]string{"zone": "hz"}
+	url = config.toURL()
+	assert.Equal(t, "shop", url.GetParam(constant.MetricsNamespaceKey, ""))
+	assert.Equal(t, "hz", url.GetParam(constant.MetricsConstLabelKeyPrefix+"zone", ""))
+
+	config.CollectInterval = "10s"
+	url = config.toURL()
+	assert.True(t, metrics.Enabled(url))
+	assert.Equal(t, 10*time.Second, metrics.CollectInterval(url))
+}
+
+func TestMetricConfigInvalidLabels(t *testing.T) {
+	rc := &RootConfig{Application: &ApplicationConfig{Name: "dubbo", Version: "1.0.0"}}
+	config := NewMetricConfigBuilder().Build()
+	config.Namespace = "my-shop"
+	assert.EqualError(t, config.Init(rc), `invalid metrics namespace "my-shop"`)
+
+	config = NewMetricConfigBuilder().Build()
+	config.ConstLabels = map[string]string{"__zone": "hz"}
+	assert.EqualError(t, config.Init(rc), `invalid metrics const label "__zone"`)
+
+	config = NewMetricConfigBuilder().Build()
+	config.CollectInterval = "0s"
+	assert.EqualError(t, config.Init(rc), `invalid metrics collect-interval "0s"`)
+}
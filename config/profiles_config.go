@@ -17,12 +17,19 @@
 
 package config
 
+import (
+	"os"
+	"regexp"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
 var (
 	defaultActive = "default"
+	// profilePlaceholder matches the placeholders ${NAME} and ${NAME:default} in a value
+	profilePlaceholder = regexp.MustCompile(`\$\{([^{}:]+)(?::([^{}]*))?}`)
 )
 
 type ProfilesConfig struct {
@@ -42,3 +49,24 @@ func getLegalActive(active string) string {
 	}
 	return active
 }
+
+// resolveProfile replaces the placeholders in @value, e.g. the group dubbo-${ENV} is dubbo-prod in the environment of
+// ENV=prod. The placeholder ${profiles.active} is the active profile of @profiles, and the others are the
+// environment variables, the default value is used if the environment variable is absent.
+func resolveProfile(value string, profiles *ProfilesConfig) string {
+	return profilePlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		matches := profilePlaceholder.FindStringSubmatch(placeholder)
+		name, defaultValue := matches[1], matches[2]
+		switch name {
+		case "profiles.active", constant.ProfilesConfigPrefix + ".active":
+			if profiles == nil {
+				return getLegalActive("")
+			}
+			return getLegalActive(profiles.Active)
+		}
+		if env, ok := os.LookupEnv(name); ok {
+			return env
+		}
+		return defaultValue
+	})
+}
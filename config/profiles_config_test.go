@@ -18,6 +18,7 @@
 package config
 
 import (
+	"os"
 	"testing"
 )
 
@@ -29,6 +30,8 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
 )
 
 func TestProfilesConfig_Prefix(t *testing.T) {
@@ -73,3 +76,36 @@ func Test_getLegalActive(t *testing.T) {
 		assert.Equal(t, active, "active")
 	})
 }
+
+func TestResolveProfile(t *testing.T) {
+	assert.NoError(t, os.Setenv("DUBBO_TEST_ENV", "prod"))
+	defer os.Unsetenv("DUBBO_TEST_ENV")
+
+	assert.Equal(t, "dubbo-prod", resolveProfile("dubbo-${DUBBO_TEST_ENV}", nil))
+	assert.Equal(t, "dubbo-prod", resolveProfile("dubbo-${DUBBO_TEST_ENV:dev}", nil))
+	assert.Equal(t, "dubbo-dev", resolveProfile("dubbo-${DUBBO_TEST_ABSENT:dev}", nil))
+	assert.Equal(t, "dubbo-", resolveProfile("dubbo-${DUBBO_TEST_ABSENT}", nil))
+	assert.Equal(t, "dubbo-default", resolveProfile("dubbo-${profiles.active}", nil))
+	assert.Equal(t, "dubbo-staging", resolveProfile("dubbo-${profiles.active}", &ProfilesConfig{Active: "staging"}))
+	assert.Equal(t, "staging-prod", resolveProfile("${dubbo.profiles.active}-${DUBBO_TEST_ENV}", &ProfilesConfig{Active: "staging"}))
+	assert.Equal(t, "dubbo", resolveProfile("dubbo", nil))
+}
+
+func TestConfigCenterFallbackGroups(t *testing.T) {
+	assert.NoError(t, os.Setenv("DUBBO_TEST_ENV", "prod"))
+	defer os.Unsetenv("DUBBO_TEST_ENV")
+	extension.SetConfigCenterFactory("fallback-test", func() config_center.DynamicConfigurationFactory {
+		return &config_center.MockDynamicConfigurationFactory{}
+	})
+
+	rc := NewRootConfigBuilder().SetProfiles(&ProfilesConfig{Active: "staging"}).Build()
+	cc := &CenterConfig{Protocol: "fallback-test", Address: "127.0.0.1:8848", Group: "dubbo-${DUBBO_TEST_ENV}",
+		FallbackGroups: []string{"dubbo-${profiles.active}", "dubbo"}}
+	cc.resolveProfiles(rc)
+	assert.Equal(t, "dubbo-prod", cc.Group)
+	assert.Equal(t, []string{"dubbo-staging", "dubbo"}, cc.FallbackGroups)
+
+	dynamicConfig, err := cc.CreateDynamicConfiguration()
+	assert.NoError(t, err)
+	assert.IsType(t, &config_center.FallbackDynamicConfiguration{}, dynamicConfig)
+}
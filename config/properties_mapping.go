@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// javaPropertyRules rewrite the keys of the Java dubbo.properties into the ones of dubbo-go in order, the * in the
+// keys matches any segment. The keys of dubbo-go are kept, and take precedence over the Java ones rewritten to them.
+var javaPropertyRules = []struct {
+	java   string
+	golang string
+}{
+	{"dubbo.registry", "dubbo.registries.default"},
+	{"dubbo.protocol", "dubbo.protocols.default"},
+	{"dubbo.protocols.*.host", "dubbo.protocols.*.ip"},
+	{"dubbo.registries.*.parameters", "dubbo.registries.*.params"},
+	{"dubbo.config-center.parameters", "dubbo.config-center.params"},
+	{"dubbo.config-center.config-file", "dubbo.config-center.data-id"},
+	{"dubbo.config-center.app-config-file", "dubbo.config-center.app-data-id"},
+	{"dubbo.metadata-report.parameters", "dubbo.metadata-report.params"},
+	{"dubbo.consumer.timeout", "dubbo.consumer.request-timeout"},
+}
+
+// javaMillisecondKeys are the durations which are numbers of milliseconds in the Java dubbo.properties
+var javaMillisecondKeys = []string{
+	"dubbo.registries.*.timeout",
+	"dubbo.config-center.timeout",
+	"dubbo.metadata-report.timeout",
+	"dubbo.consumer.request-timeout",
+}
+
+var millisecondsPattern = regexp.MustCompile(`^\d+$`)
+
+// mapJavaProperties maps the flattened properties @values onto the keys of dubbo-go, so that the dubbo.properties of
+// the Java applications could be loaded as they are
+func mapJavaProperties(values map[string]interface{}) map[string]interface{} {
+	mapped := make(map[string]interface{}, len(values))
+	rewritten := make(map[string]interface{})
+	for key, value := range values {
+		golang := key
+		for _, rule := range javaPropertyRules {
+			golang = rewriteKey(golang, rule.java, rule.golang)
+		}
+		if golang == key {
+			mapped[key] = value
+		} else {
+			rewritten[golang] = value
+		}
+	}
+	for key, value := range rewritten {
+		if _, ok := mapped[key]; !ok {
+			mapped[key] = value
+		}
+	}
+	for key, value := range mapped {
+		for _, pattern := range javaMillisecondKeys {
+			if s, ok := value.(string); ok && matchKey(key, pattern) && millisecondsPattern.MatchString(s) {
+				mapped[key] = s + "ms"
+			}
+		}
+	}
+	return mapped
+}
+
+// rewriteKey replaces the prefix of @key matching @from with @to, the segments matched by * are kept
+func rewriteKey(key, from, to string) string {
+	segments, patterns := strings.Split(key, "."), strings.Split(from, ".")
+	if len(segments) < len(patterns) {
+		return key
+	}
+	var matched []string
+	for i, pattern := range patterns {
+		if pattern == "*" {
+			matched = append(matched, segments[i])
+		} else if pattern != segments[i] {
+			return key
+		}
+	}
+	replaced := strings.Split(to, ".")
+	for i := range replaced {
+		if replaced[i] == "*" && len(matched) > 0 {
+			replaced[i], matched = matched[0], matched[1:]
+		}
+	}
+	return strings.Join(append(replaced, segments[len(patterns):]...), ".")
+}
+
+// matchKey returns whether @key matches @pattern segment by segment, the * in @pattern matches any segment
+func matchKey(key, pattern string) bool {
+	segments, patterns := strings.Split(key, "."), strings.Split(pattern, ".")
+	if len(segments) != len(patterns) {
+		return false
+	}
+	for i := range patterns {
+		if patterns[i] != "*" && patterns[i] != segments[i] {
+			return false
+		}
+	}
+	return true
+}
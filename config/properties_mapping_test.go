@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/knadh/koanf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const javaProperties = `
+dubbo.application.name=user-center
+dubbo.application.version=1.0.0
+dubbo.application.owner=dubbo
+dubbo.application.organization=apache
+dubbo.application.environment=product
+dubbo.application.metadata-type=remote
+dubbo.registry.address=zookeeper://127.0.0.1:2181
+dubbo.registry.username=admin
+dubbo.registry.password=secret
+dubbo.registry.group=dev
+dubbo.registry.timeout=3000
+dubbo.registry.parameters.namespace=public
+dubbo.registries.nacos.address=nacos://127.0.0.1:8848
+dubbo.registries.nacos.timeout=10s
+dubbo.protocol.name=dubbo
+dubbo.protocol.port=20880
+dubbo.protocol.host=192.168.0.1
+dubbo.protocols.tri.name=tri
+dubbo.protocols.tri.port=50051
+dubbo.config-center.address=nacos://127.0.0.1:8848
+dubbo.config-center.namespace=dubbo
+dubbo.config-center.group=dubbo
+dubbo.config-center.config-file=dubbo.properties
+dubbo.config-center.app-config-file=user-center.properties
+dubbo.config-center.timeout=5000
+dubbo.metadata-report.address=zookeeper://127.0.0.1:2181
+dubbo.metadata-report.timeout=1000
+dubbo.consumer.timeout=2000
+dubbo.consumer.check=false
+dubbo.consumer.registry-ids=zk,nacos
+`
+
+func TestMapJavaProperties(t *testing.T) {
+	rc := newEmptyRootConfig()
+	koan := GetConfigResolver(NewLoaderConf(WithBytes([]byte(javaProperties)), WithGenre("properties")))
+	assert.NoError(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}))
+
+	// the common keys of the Java dubbo.properties and the fields of dubbo-go they are mapped onto
+	tests := []struct {
+		java   string
+		actual interface{}
+		expect interface{}
+	}{
+		{"dubbo.application.name", rc.Application.Name, "user-center"},
+		{"dubbo.application.version", rc.Application.Version, "1.0.0"},
+		{"dubbo.application.owner", rc.Application.Owner, "dubbo"},
+		{"dubbo.application.organization", rc.Application.Organization, "apache"},
+		{"dubbo.application.environment", rc.Application.Environment, "product"},
+		{"dubbo.application.metadata-type", rc.Application.MetadataType, "remote"},
+		{"dubbo.registry.address", rc.Registries["default"].Address, "zookeeper://127.0.0.1:2181"},
+		{"dubbo.registry.username", rc.Registries["default"].Username, "admin"},
+		{"dubbo.registry.password", rc.Registries["default"].Password, "secret"},
+		{"dubbo.registry.group", rc.Registries["default"].Group, "dev"},
+		{"dubbo.registry.timeout", rc.Registries["default"].Timeout, "3000ms"},
+		{"dubbo.registry.parameters.namespace", rc.Registries["default"].Params["namespace"], "public"},
+		{"dubbo.registries.nacos.address", rc.Registries["nacos"].Address, "nacos://127.0.0.1:8848"},
+		{"dubbo.registries.nacos.timeout", rc.Registries["nacos"].Timeout, "10s"},
+		{"dubbo.protocol.name", rc.Protocols["default"].Name, "dubbo"},
+		{"dubbo.protocol.port", rc.Protocols["default"].Port, "20880"},
+		{"dubbo.protocol.host", rc.Protocols["default"].Ip, "192.168.0.1"},
+		{"dubbo.protocols.tri.port", rc.Protocols["tri"].Port, "50051"},
+		{"dubbo.config-center.address", rc.ConfigCenter.Address, "nacos://127.0.0.1:8848"},
+		{"dubbo.config-center.namespace", rc.ConfigCenter.Namespace, "dubbo"},
+		{"dubbo.config-center.group", rc.ConfigCenter.Group, "dubbo"},
+		{"dubbo.config-center.config-file", rc.ConfigCenter.DataId, "dubbo.properties"},
+		{"dubbo.config-center.app-config-file", rc.ConfigCenter.AppDataId, "user-center.properties"},
+		{"dubbo.config-center.timeout", rc.ConfigCenter.Timeout, "5000ms"},
+		{"dubbo.metadata-report.address", rc.MetadataReport.Address, "zookeeper://127.0.0.1:2181"},
+		{"dubbo.metadata-report.timeout", rc.MetadataReport.Timeout, "1000ms"},
+		{"dubbo.consumer.timeout", rc.Consumer.RequestTimeout, "2000ms"},
+		{"dubbo.consumer.check", rc.Consumer.Check, false},
+		{"dubbo.consumer.registry-ids", rc.Consumer.RegistryIDs, []string{"zk", "nacos"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expect, test.actual, test.java)
+	}
+}
+
+func TestMapJavaPropertiesPrecedence(t *testing.T) {
+	mapped := mapJavaProperties(map[string]interface{}{
+		"dubbo.registry.address":            "zookeeper://127.0.0.1:2181",
+		"dubbo.registries.default.address":  "nacos://127.0.0.1:8848",
+		"dubbo.registries.default.timeout":  "5s",
+		"dubbo.protocols.dubbo.port":        "20000",
+		"dubbo.provider.services.Greeter.a": "b",
+	})
+	assert.Equal(t, map[string]interface{}{
+		// the keys of dubbo-go take precedence over the Java ones
+		"dubbo.registries.default.address":  "nacos://127.0.0.1:8848",
+		"dubbo.registries.default.timeout":  "5s",
+		"dubbo.protocols.dubbo.port":        "20000",
+		"dubbo.provider.services.Greeter.a": "b",
+	}, mapped)
+}
+
+func TestConfigFormats(t *testing.T) {
+	yamlConfig := "dubbo:\n  application:\n    name: app\n  registries:\n    zk:\n      address: 127.0.0.1:2181\n" +
+		"      timeout: 3s\n  consumer:\n    registry-ids: [zk]\n    check: true\n"
+	jsonConfig := `{"dubbo": {"application": {"name": "app"}, "registries": {"zk": {"address": "127.0.0.1:2181",` +
+		` "timeout": "3s"}}, "consumer": {"registry-ids": ["zk"], "check": true}}}`
+	propertiesConfig := "dubbo.application.name=app\ndubbo.registries.zk.address=127.0.0.1:2181\n" +
+		"dubbo.registries.zk.timeout=3s\ndubbo.consumer.registry-ids=zk\ndubbo.consumer.check=true\n"
+
+	// the formats are of the identical semantics after parsing
+	var configs []*RootConfig
+	for genre, content := range map[string]string{"yaml": yamlConfig, "json": jsonConfig, "properties": propertiesConfig} {
+		rc := newEmptyRootConfig()
+		koan := GetConfigResolver(NewLoaderConf(WithBytes([]byte(content)), WithGenre(genre)))
+		assert.NoError(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}), genre)
+		configs = append(configs, rc)
+	}
+	for _, rc := range configs[1:] {
+		assert.Equal(t, configs[0].Application, rc.Application)
+		assert.Equal(t, configs[0].Registries, rc.Registries)
+		assert.Equal(t, configs[0].Consumer, rc.Consumer)
+	}
+}
+
+func TestMergeConfigFormats(t *testing.T) {
+	rc := newEmptyRootConfig()
+	_, _, err := loadConfigFiles(NewLoaderConf(WithPath("./testdata/config/formats/application.json")), rc)
+	assert.NoError(t, err)
+
+	// yaml overrides json, which overrides properties
+	assert.Equal(t, "yaml-app", rc.Application.Name)
+	assert.Equal(t, "json-module", rc.Application.Module)
+	assert.Equal(t, "properties-owner", rc.Application.Owner)
+	assert.Equal(t, "zookeeper://127.0.0.1:2181", rc.Registries["default"].Address)
+	assert.Equal(t, "5s", rc.Registries["default"].Timeout)
+}
@@ -31,6 +31,19 @@ type ProtocolConfig struct {
 	Ip     string      `yaml:"ip"  json:"ip,omitempty" property:"ip"`
 	Port   string      `default:"20000" yaml:"port" json:"port,omitempty" property:"port"`
 	Params interface{} `yaml:"params" json:"params,omitempty" property:"params"`
+	// Host is the host advertised to the registries instead of Ip, which the server binds, e.g. the public ip
+	// of a NAT, see common.GetRegistryIP
+	Host string `yaml:"host" json:"host,omitempty" property:"host"`
+	// Serialization is the serialization of the services exported by the protocol unless they set their own
+	Serialization string `yaml:"serialization" json:"serialization,omitempty" property:"serialization"`
+	// TLSProfile is the tls profile of the servers and the clients of the protocol, see RootConfig.TLSProfiles
+	TLSProfile string `yaml:"tls-profile" json:"tls-profile,omitempty" property:"tls-profile"`
+	// Transport tunes the tcp sessions of the servers and the clients of the getty based protocols like dubbo, the
+	// clients use the transport of the consumer instead if it is set
+	Transport *TransportConfig `yaml:"transport" json:"transport,omitempty" property:"transport"`
+	// InboundLimit limits the requests received by the servers of the getty based protocols like dubbo per
+	// connection and per source ip
+	InboundLimit *InboundLimitConfig `yaml:"inbound-limit" json:"inbound-limit,omitempty" property:"inbound-limit"`
 
 	// MaxServerSendMsgSize max size of server send message, 1mb=1000kb=1000000b 1mib=1024kb=1048576b.
 	// more detail to see https://pkg.go.dev/github.com/dustin/go-humanize#pkg-constants
@@ -73,6 +86,11 @@ func (pcb *ProtocolConfigBuilder) SetIp(ip string) *ProtocolConfigBuilder {
 	return pcb
 }
 
+func (pcb *ProtocolConfigBuilder) SetHost(host string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.Host = host
+	return pcb
+}
+
 func (pcb *ProtocolConfigBuilder) SetPort(port string) *ProtocolConfigBuilder {
 	pcb.protocolConfig.Port = port
 	return pcb
@@ -83,6 +101,26 @@ func (pcb *ProtocolConfigBuilder) SetParams(params interface{}) *ProtocolConfigB
 	return pcb
 }
 
+func (pcb *ProtocolConfigBuilder) SetSerialization(serialization string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.Serialization = serialization
+	return pcb
+}
+
+func (pcb *ProtocolConfigBuilder) SetTLSProfile(tlsProfile string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.TLSProfile = tlsProfile
+	return pcb
+}
+
+func (pcb *ProtocolConfigBuilder) SetTransport(transport *TransportConfig) *ProtocolConfigBuilder {
+	pcb.protocolConfig.Transport = transport
+	return pcb
+}
+
+func (pcb *ProtocolConfigBuilder) SetInboundLimit(inboundLimit *InboundLimitConfig) *ProtocolConfigBuilder {
+	pcb.protocolConfig.InboundLimit = inboundLimit
+	return pcb
+}
+
 func (pcb *ProtocolConfigBuilder) SetMaxServerSendMsgSize(maxServerSendMsgSize string) *ProtocolConfigBuilder {
 	pcb.protocolConfig.MaxServerSendMsgSize = maxServerSendMsgSize
 	return pcb
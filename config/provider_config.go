@@ -35,6 +35,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 	aslimiter "dubbo.apache.org/dubbo-go/v3/filter/adaptivesvc/limiter"
 )
 
@@ -57,8 +58,12 @@ type ProviderConfig struct {
 	// adaptive service
 	AdaptiveService        bool `yaml:"adaptive-service" json:"adaptive-service" property:"adaptive-service"`
 	AdaptiveServiceVerbose bool `yaml:"adaptive-service-verbose" json:"adaptive-service-verbose" property:"adaptive-service-verbose"`
+	// Executor is the pool shared by the services without their own executors
+	Executor *ExecutorConfig `yaml:"executor" json:"executor,omitempty" property:"executor"`
 
 	rootConfig *RootConfig
+	// builder is the application, the registries and the protocols set by ProviderConfigBuilder
+	builder builderConfigs
 }
 
 func (ProviderConfig) Prefix() string {
@@ -69,6 +74,12 @@ func (c *ProviderConfig) check() error {
 	if err := defaults.Set(c); err != nil {
 		return err
 	}
+	if c.Executor != nil {
+		if err := c.Executor.check(); err != nil {
+			return err
+		}
+		gopool.Configure(gopool.ProviderExecute, c.Executor.Workers, c.Executor.QueueSize)
+	}
 	return verify(c)
 }
 
@@ -269,6 +280,29 @@ func (pcb *ProviderConfigBuilder) AddConfigType(key, value string) *ProviderConf
 	return pcb
 }
 
+func (pcb *ProviderConfigBuilder) SetExecutor(executor *ExecutorConfig) *ProviderConfigBuilder {
+	pcb.providerConfig.Executor = executor
+	return pcb
+}
+
+func (pcb *ProviderConfigBuilder) SetApplication(application *ApplicationConfig) *ProviderConfigBuilder {
+	pcb.providerConfig.builder.application = application
+	return pcb
+}
+
+func (pcb *ProviderConfigBuilder) AddRegistry(registryID string, registryConfig *RegistryConfig) *ProviderConfigBuilder {
+	pcb.providerConfig.builder.addRegistry(registryID, registryConfig)
+	return pcb
+}
+
+func (pcb *ProviderConfigBuilder) AddProtocol(protocolID string, protocolConfig *ProtocolConfig) *ProviderConfigBuilder {
+	if pcb.providerConfig.builder.protocols == nil {
+		pcb.providerConfig.builder.protocols = make(map[string]*ProtocolConfig)
+	}
+	pcb.providerConfig.builder.protocols[protocolID] = protocolConfig
+	return pcb
+}
+
 func (pcb *ProviderConfigBuilder) SetRootConfig(rootConfig *RootConfig) *ProviderConfigBuilder {
 	pcb.providerConfig.rootConfig = rootConfig
 	return pcb
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	"github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/qos"
+)
+
+// QosConfig is the config of the QoS server, which serves the commands of the operators by telnet and http on the
+// same port, e.g.
+//
+//	qos:
+//	  port: 22222
+//	  accept-foreign-ip: false
+//
+// The server listens on 127.0.0.1:22222 by default, and it is disabled if enable is false or the port is 0. The host
+// should be 0.0.0.0 for the kubelets to reach the /live and /ready probes, which are public.
+type QosConfig struct {
+	Enable *bool `default:"true" yaml:"enable" json:"enable,omitempty" property:"enable"`
+	// Host is the address the server binds to, only the local operators could connect it by default
+	Host string `default:"127.0.0.1" yaml:"host" json:"host,omitempty" property:"host"`
+	Port string `default:"22222" yaml:"port" json:"port,omitempty" property:"port"`
+	// AcceptForeignIP allows the operators from the foreign ips, which are neither loopback nor in the whitelist, to
+	// run all the commands. Otherwise they're only allowed to run the commands of the anonymous access permission level.
+	AcceptForeignIP *bool `default:"false" yaml:"accept-foreign-ip" json:"accept-foreign-ip,omitempty" property:"accept-foreign-ip"`
+	// AcceptForeignIPWhitelist are the ips or the cidrs separated by commas, which are trusted like the loopback ips
+	AcceptForeignIPWhitelist string `yaml:"accept-foreign-ip-whitelist" json:"accept-foreign-ip-whitelist,omitempty" property:"accept-foreign-ip-whitelist"`
+	// AnonymousAccessPermissionLevel is the highest permission level of the commands the foreign ips could run, one
+	// of none, public, protected and private
+	AnonymousAccessPermissionLevel string `default:"public" yaml:"anonymous-access-permission-level" json:"anonymous-access-permission-level,omitempty" property:"anonymous-access-permission-level"`
+}
+
+// Init sets the defaults and checks the config
+func (c *QosConfig) Init() error {
+	if err := defaults.Set(c); err != nil {
+		return err
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 0 || port > 65535 {
+		return errors.Errorf("invalid qos port %q", c.Port)
+	}
+	if _, err := qos.ParsePermissionLevel(c.AnonymousAccessPermissionLevel); err != nil {
+		return errors.Wrap(err, "invalid qos anonymous-access-permission-level")
+	}
+	if _, err := c.Whitelist(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsEnabled returns whether the QoS server is enabled
+func (c *QosConfig) IsEnabled() bool {
+	return (c.Enable == nil || *c.Enable) && c.Port != "0"
+}
+
+// Whitelist parses the AcceptForeignIPWhitelist, the ips are converted to the cidrs of themselves
+func (c *QosConfig) Whitelist() ([]*net.IPNet, error) {
+	var whitelist []*net.IPNet
+	for _, item := range strings.Split(c.AcceptForeignIPWhitelist, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, errors.Errorf("invalid ip %q in the qos accept-foreign-ip-whitelist", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			whitelist = append(whitelist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, errors.Errorf("invalid cidr %q in the qos accept-foreign-ip-whitelist", item)
+		}
+		whitelist = append(whitelist, cidr)
+	}
+	return whitelist, nil
+}
+
+// GetQosConfig returns the config of the QoS server, which is the default one if it is absent
+func GetQosConfig() *QosConfig {
+	if rootConfig != nil && rootConfig.Qos != nil {
+		return rootConfig.Qos
+	}
+	c := NewQosConfigBuilder().Build()
+	_ = defaults.Set(c)
+	return c
+}
+
+type QosConfigBuilder struct {
+	qosConfig *QosConfig
+}
+
+func NewQosConfigBuilder() *QosConfigBuilder {
+	return &QosConfigBuilder{qosConfig: &QosConfig{}}
+}
+
+func (qcb *QosConfigBuilder) SetEnable(enable bool) *QosConfigBuilder {
+	qcb.qosConfig.Enable = &enable
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) SetHost(host string) *QosConfigBuilder {
+	qcb.qosConfig.Host = host
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) SetPort(port string) *QosConfigBuilder {
+	qcb.qosConfig.Port = port
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) SetAcceptForeignIP(accept bool) *QosConfigBuilder {
+	qcb.qosConfig.AcceptForeignIP = &accept
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) SetAcceptForeignIPWhitelist(whitelist string) *QosConfigBuilder {
+	qcb.qosConfig.AcceptForeignIPWhitelist = whitelist
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) SetAnonymousAccessPermissionLevel(level string) *QosConfigBuilder {
+	qcb.qosConfig.AnonymousAccessPermissionLevel = level
+	return qcb
+}
+
+func (qcb *QosConfigBuilder) Build() *QosConfig {
+	return qcb.qosConfig
+}
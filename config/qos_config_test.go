@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQosConfigInit(t *testing.T) {
+	qc := NewQosConfigBuilder().Build()
+	assert.NoError(t, qc.Init())
+	assert.True(t, qc.IsEnabled())
+	assert.Equal(t, "127.0.0.1", qc.Host)
+	assert.Equal(t, "22222", qc.Port)
+	assert.False(t, *qc.AcceptForeignIP)
+	assert.Equal(t, "public", qc.AnonymousAccessPermissionLevel)
+
+	assert.False(t, NewQosConfigBuilder().SetPort("0").Build().IsEnabled())
+	assert.False(t, NewQosConfigBuilder().SetEnable(false).Build().IsEnabled())
+
+	assert.EqualError(t, NewQosConfigBuilder().SetPort("65536").Build().Init(), `invalid qos port "65536"`)
+	assert.Error(t, NewQosConfigBuilder().SetAnonymousAccessPermissionLevel("admin").Build().Init())
+	assert.EqualError(t, NewQosConfigBuilder().SetAcceptForeignIPWhitelist("10.0.0.1,10.0.0.0/33").Build().Init(),
+		`invalid cidr "10.0.0.0/33" in the qos accept-foreign-ip-whitelist`)
+}
+
+func TestQosConfigWhitelist(t *testing.T) {
+	whitelist, err := NewQosConfigBuilder().SetAcceptForeignIPWhitelist(" 10.0.0.1, ,192.168.0.0/16,::2").Build().Whitelist()
+	assert.NoError(t, err)
+	assert.Len(t, whitelist, 3)
+	assert.Equal(t, "10.0.0.1/32", whitelist[0].String())
+	assert.Equal(t, "192.168.0.0/16", whitelist[1].String())
+	assert.Equal(t, "::2/128", whitelist[2].String())
+
+	_, err = NewQosConfigBuilder().SetAcceptForeignIPWhitelist("localhost").Build().Whitelist()
+	assert.EqualError(t, err, `invalid ip "localhost" in the qos accept-foreign-ip-whitelist`)
+}
@@ -22,6 +22,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -32,12 +33,15 @@ import (
 	gxstrings "github.com/dubbogo/gost/strings"
 
 	constant2 "github.com/dubbogo/triple/pkg/common/constant"
+
+	perrors "github.com/pkg/errors"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/config/generic"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -47,26 +51,32 @@ import (
 
 // ReferenceConfig is the configuration of service consumer
 type ReferenceConfig struct {
-	pxy              *proxy.Proxy
-	id               string
-	InterfaceName    string            `yaml:"interface"  json:"interface,omitempty" property:"interface"`
-	Check            *bool             `yaml:"check"  json:"check,omitempty" property:"check"`
-	URL              string            `yaml:"url"  json:"url,omitempty" property:"url"`
-	Filter           string            `yaml:"filter" json:"filter,omitempty" property:"filter"`
-	Protocol         string            `yaml:"protocol"  json:"protocol,omitempty" property:"protocol"`
-	RegistryIDs      []string          `yaml:"registry-ids"  json:"registry-ids,omitempty"  property:"registry-ids"`
-	Cluster          string            `yaml:"cluster"  json:"cluster,omitempty" property:"cluster"`
-	Loadbalance      string            `yaml:"loadbalance"  json:"loadbalance,omitempty" property:"loadbalance"`
-	Retries          string            `yaml:"retries"  json:"retries,omitempty" property:"retries"`
-	Group            string            `yaml:"group"  json:"group,omitempty" property:"group"`
-	Version          string            `yaml:"version"  json:"version,omitempty" property:"version"`
-	Serialization    string            `yaml:"serialization" json:"serialization" property:"serialization"`
-	ProvidedBy       string            `yaml:"provided_by"  json:"provided_by,omitempty" property:"provided_by"`
-	Methods          []*MethodConfig   `yaml:"methods"  json:"methods,omitempty" property:"methods"`
-	Async            bool              `yaml:"async"  json:"async,omitempty" property:"async"`
-	Params           map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
-	invoker          protocol.Invoker
-	urls             []*common.URL
+	pxy           *proxy.Proxy
+	id            string
+	InterfaceName string            `yaml:"interface"  json:"interface,omitempty" property:"interface"`
+	Check         *bool             `yaml:"check"  json:"check,omitempty" property:"check"`
+	URL           string            `yaml:"url"  json:"url,omitempty" property:"url"`
+	Filter        string            `yaml:"filter" json:"filter,omitempty" property:"filter"`
+	Protocol      string            `yaml:"protocol"  json:"protocol,omitempty" property:"protocol"`
+	RegistryIDs   []string          `yaml:"registry-ids"  json:"registry-ids,omitempty"  property:"registry-ids"`
+	Cluster       string            `yaml:"cluster"  json:"cluster,omitempty" property:"cluster"`
+	Loadbalance   string            `yaml:"loadbalance"  json:"loadbalance,omitempty" property:"loadbalance"`
+	Retries       string            `yaml:"retries"  json:"retries,omitempty" property:"retries"`
+	Group         string            `yaml:"group"  json:"group,omitempty" property:"group"`
+	Version       string            `yaml:"version"  json:"version,omitempty" property:"version"`
+	Serialization string            `yaml:"serialization" json:"serialization" property:"serialization"`
+	ProvidedBy    string            `yaml:"provided_by"  json:"provided_by,omitempty" property:"provided_by"`
+	Methods       []*MethodConfig   `yaml:"methods"  json:"methods,omitempty" property:"methods"`
+	Async         bool              `yaml:"async"  json:"async,omitempty" property:"async"`
+	Params        map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
+	invoker       protocol.Invoker
+	urls          []*common.URL
+	// publishedURL is the url whose metadata is published, it's removed when the reference is destroyed
+	publishedURL *common.URL
+	// referredURL is the url the reference is referred with, it's removed when the reference is destroyed
+	referredURL *common.URL
+	// srv is the service referred, it's unregistered when the reference is destroyed
+	srv              interface{}
 	Generic          string `yaml:"generic"  json:"generic,omitempty" property:"generic"`
 	Sticky           bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout   string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
@@ -76,6 +86,8 @@ type ReferenceConfig struct {
 	metaDataType     string
 	metricsEnable    bool
 	MeshProviderPort int `yaml:"mesh-provider-port" json:"mesh-provider-port,omitempty" propertiy:"mesh-provider-port"`
+	// Lazy defers the subscription and the connections of the reference to its first invocation
+	Lazy bool `yaml:"lazy" json:"lazy,omitempty" property:"lazy"`
 }
 
 func (rc *ReferenceConfig) Prefix() string {
@@ -125,9 +137,35 @@ func (rc *ReferenceConfig) Init(root *RootConfig) error {
 	if rc.Check == nil {
 		rc.Check = &root.Consumer.Check
 	}
+	if err := checkFilters(rc.Filter); err != nil {
+		return fmt.Errorf("[ReferenceConfig] Invalid filters of reference %s: %v, please check your configuration", rc.InterfaceName, err)
+	}
+	if err := checkParams(rc.getURLMap()); err != nil {
+		return fmt.Errorf("[ReferenceConfig] Invalid params of reference %s: %v, please check your configuration", rc.InterfaceName, err)
+	}
 	return verify(rc)
 }
 
+// filters resolves the filters of the reference from its specification, the default filters are the
+// DefaultReferenceFilters and the enabled features
+func (rc *ReferenceConfig) filters() ([]string, error) {
+	defaults := strings.Split(constant.DefaultReferenceFilters, ",")
+	if rc.Generic != "" {
+		defaults = append([]string{constant.GenericFilterKey}, defaults...)
+	}
+	if rc.metricsEnable {
+		defaults = append(defaults, constant.MetricsFilterKey)
+	}
+	for _, v := range rc.Methods {
+		if len(v.Cache) != 0 {
+			// the results of the method are cached as soon as the cache is configured, the same as dubbo java
+			defaults = append(defaults, constant.CacheFilterKey)
+			break
+		}
+	}
+	return protocolwrapper.ResolveFilters(rc.Filter, defaults)
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -182,6 +220,7 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 	)
 
 	SetConsumerServiceByInterfaceName(rc.InterfaceName, srv)
+	rc.srv = srv
 	if rc.ForceTag {
 		cfgURL.AddParam(constant.ForceUseTag, "true")
 	}
@@ -190,7 +229,47 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 	// if mesh-enabled is set
 	updateOrCreateMeshURL(rc)
 
+	if rc.Lazy {
+		rc.invoker = newLazyInvoker(cfgURL, func() (protocol.Invoker, error) {
+			return rc.createInvoker(cfgURL)
+		}, rc.checkAvailable)
+	} else {
+		rc.invoker = rc.mustCreateInvoker(cfgURL)
+	}
+
+	// publish consumer's metadata
+	publishServiceDefinition(cfgURL)
+	rc.publishedURL = cfgURL
+	// create proxy
+	if rc.Async {
+		callback := GetCallback(rc.id)
+		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetAsyncProxy(rc.invoker, callback, cfgURL)
+	} else {
+		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetProxy(rc.invoker, cfgURL)
+	}
+	rc.referredURL = cfgURL
+	eventbus.Publish(eventbus.ReferenceReady{
+		ServiceKey: common.ServiceKey(rc.InterfaceName, rc.Group, rc.Version),
+		Interface:  rc.InterfaceName,
+		URL:        cfgURL,
+	})
+}
+
+// createInvoker creates the invoker of the reference described by @cfgURL, the panics on creating are returned as
+// the error
+func (rc *ReferenceConfig) createInvoker(cfgURL *common.URL) (invoker protocol.Invoker, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = perrors.Errorf("refer the service %s failed, %v", rc.InterfaceName, e)
+		}
+	}()
+	return rc.mustCreateInvoker(cfgURL), nil
+}
+
+// mustCreateInvoker creates the invoker of the reference described by @cfgURL, it panics if the config is invalid
+func (rc *ReferenceConfig) mustCreateInvoker(cfgURL *common.URL) protocol.Invoker {
 	// retrieving urls from config, and appending the urls to rc.urls
+	rc.urls = nil
 	if rc.URL != "" { // use user-specific urls
 		/*
 			 Two types of URL are allowed for rc.URL:
@@ -256,17 +335,17 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 
 	// TODO(hxmhlt): decouple from directory, config should not depend on directory module
 	if len(invokers) == 1 {
-		rc.invoker = invokers[0]
+		invoker = invokers[0]
 		if rc.URL != "" {
 			hitClu := constant.ClusterKeyFailover
-			if u := rc.invoker.GetURL(); u != nil {
+			if u := invoker.GetURL(); u != nil {
 				hitClu = u.GetParam(constant.ClusterKey, constant.ClusterKeyZoneAware)
 			}
 			cluster, err := extension.GetCluster(hitClu)
 			if err != nil {
 				panic(err)
 			} else {
-				rc.invoker = cluster.Join(static.NewDirectory(invokers))
+				invoker = cluster.Join(static.NewDirectory(invokers))
 			}
 		}
 	} else {
@@ -285,19 +364,84 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 		if err != nil {
 			panic(err)
 		} else {
-			rc.invoker = cluster.Join(static.NewDirectory(invokers))
+			invoker = cluster.Join(static.NewDirectory(invokers))
 		}
 	}
+	return invoker
+}
 
-	// publish consumer's metadata
-	publishServiceDefinition(cfgURL)
-	// create proxy
-	if rc.Async {
-		callback := GetCallback(rc.id)
-		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetAsyncProxy(rc.invoker, callback, cfgURL)
-	} else {
-		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetProxy(rc.invoker, cfgURL)
+// checkAvailable waits for the providers of @invoker if the reference checks them, it fails if no provider is
+// available within the max wait time for service discovery
+func (rc *ReferenceConfig) checkAvailable(invoker protocol.Invoker) error {
+	if rc.Check != nil && !*rc.Check {
+		return nil
+	}
+	maxWait, err := common.ParseDuration(rc.rootConfig.Consumer.MaxWaitTimeForServiceDiscovery)
+	if err != nil {
+		maxWait = 3 * time.Second
 	}
+	deadline := time.Now().Add(maxWait)
+	for !invoker.IsAvailable() {
+		if !time.Now().Before(deadline) {
+			return perrors.Errorf("No provider available of the service %v from %v within %v, please check if the "+
+				"providers have been started and registered, or set check=false to start without them",
+				rc.InterfaceName, rc.providerSource(), maxWait)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+// providerSource describes where the providers of the reference come from, the urls specified or the registries
+func (rc *ReferenceConfig) providerSource() string {
+	if rc.URL != "" {
+		return "the url " + rc.URL
+	}
+	registries := make([]string, 0, len(rc.RegistryIDs))
+	for _, id := range rc.RegistryIDs {
+		if reg, ok := rc.rootConfig.Registries[id]; ok && reg != nil {
+			id += "(" + reg.Protocol + "://" + reg.Address + ")"
+		}
+		registries = append(registries, id)
+	}
+	return "the registries [" + strings.Join(registries, ", ") + "]"
+}
+
+// Destroy destroys the invoker of the reference, which unsubscribes the registries and closes the clients, and
+// removes the metadata and the service of the reference, it could be called at runtime to drop the reference
+func (rc *ReferenceConfig) Destroy() {
+	if rc.invoker != nil {
+		rc.invoker.Destroy()
+		rc.invoker = nil
+	}
+	rc.unpublish()
+	if rc.srv != nil {
+		removeConsumerServiceByInterfaceName(rc.InterfaceName, rc.srv)
+		rc.srv = nil
+	}
+	rc.publishDestroyed()
+}
+
+// publishDestroyed publishes the ReferenceDestroyed once if the reference is referred
+func (rc *ReferenceConfig) publishDestroyed() {
+	if rc.referredURL == nil {
+		return
+	}
+	eventbus.Publish(eventbus.ReferenceDestroyed{
+		ServiceKey: common.ServiceKey(rc.InterfaceName, rc.Group, rc.Version),
+		Interface:  rc.InterfaceName,
+		URL:        rc.referredURL,
+	})
+	rc.referredURL = nil
+}
+
+// unpublish removes the metadata of the reference from the metadata report
+func (rc *ReferenceConfig) unpublish() {
+	if rc.publishedURL == nil {
+		return
+	}
+	unpublishServiceDefinition(rc.publishedURL)
+	rc.publishedURL = nil
 }
 
 // Implement
@@ -341,6 +485,10 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	if len(rc.RequestTimeout) != 0 {
 		urlMap.Set(constant.TimeoutKey, rc.RequestTimeout)
 	}
+	// the consumers dialing the dubbo providers by websocket select only the providers accepting it
+	if transport := GetClientTransport(rc.rootConfig.Protocols[rc.Protocol]); transport != nil && transport.Websocket {
+		urlMap.Set(constant.TransportKey, constant.TransportWebsocket)
+	}
 	// getty invoke async or sync
 	urlMap.Set(constant.AsyncKey, strconv.FormatBool(rc.Async))
 	urlMap.Set(constant.StickyKey, strconv.FormatBool(rc.Sticky))
@@ -354,24 +502,33 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	urlMap.Set(constant.OwnerKey, rc.rootConfig.Application.Owner)
 	urlMap.Set(constant.EnvironmentKey, rc.rootConfig.Application.Environment)
 
-	// filter
-	defaultReferenceFilter := constant.DefaultReferenceFilters
-	if rc.Generic != "" {
-		defaultReferenceFilter = constant.GenericFilterKey + "," + defaultReferenceFilter
-	}
-	if rc.metricsEnable {
-		defaultReferenceFilter += fmt.Sprintf(",%s", constant.MetricsFilterKey)
-	}
-	urlMap.Set(constant.ReferenceFilterKey, mergeValue(rc.Filter, "", defaultReferenceFilter))
+	// filter, the specification is validated by Init
+	filters, _ := rc.filters()
+	urlMap.Set(constant.ReferenceFilterKey, strings.Join(filters, ","))
 
+	// the methods, which could be wildcards like get*, inherit the values they don't set from the reference
 	for _, v := range rc.Methods {
-		urlMap.Set("methods."+v.Name+"."+constant.LoadbalanceKey, v.LoadBalance)
-		urlMap.Set("methods."+v.Name+"."+constant.RetriesKey, v.Retries)
-		urlMap.Set("methods."+v.Name+"."+constant.StickyKey, strconv.FormatBool(v.Sticky))
-		if len(v.RequestTimeout) != 0 {
-			urlMap.Set("methods."+v.Name+"."+constant.TimeoutKey, v.RequestTimeout)
+		if v.Sticky {
+			urlMap.Set("methods."+v.Name+"."+constant.StickyKey, strconv.FormatBool(v.Sticky))
+		}
+		for key, value := range map[string]string{
+			constant.LoadbalanceKey:            v.LoadBalance,
+			constant.RetriesKey:                v.Retries,
+			constant.TimeoutKey:                v.RequestTimeout,
+			constant.RetryableKey:              v.Retryable,
+			constant.RetryBackoffKey:           v.RetryBackoff,
+			constant.RetryBackoffMultiplierKey: v.RetryBackoffMultiplier,
+			constant.RetryOnKey:                v.RetryOn,
+			constant.CacheKey:                  v.Cache,
+			constant.CacheSizeKey:              v.CacheSize,
+			constant.CacheTTLKey:               v.CacheTTL,
+		} {
+			if len(value) != 0 {
+				urlMap.Set("methods."+v.Name+"."+key, value)
+			}
 		}
 	}
+	normalizeDurations(urlMap)
 
 	return urlMap
 }
@@ -399,6 +556,23 @@ func (rc *ReferenceConfig) postProcessConfig(url *common.URL) {
 
 //////////////////////////////////// reference config api
 
+// NewReferenceConfigByAPI initializes @rc built by the ReferenceConfigBuilder with the root config loaded, so that
+// the reference could be referred after the bootstrap, it's dropped by Destroy at last:
+//
+//	rc, err := config.NewReferenceConfigByAPI(config.NewReferenceConfigBuilder().SetInterface(name).Build())
+//	rc.Refer(srv)
+//	rc.Implement(srv)
+//	defer rc.Destroy()
+func NewReferenceConfigByAPI(rc *ReferenceConfig) (*ReferenceConfig, error) {
+	if err := check(); err != nil {
+		return nil, err
+	}
+	if err := rc.Init(rootConfig); err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
 // newEmptyReferenceConfig returns empty ReferenceConfig
 func newEmptyReferenceConfig() *ReferenceConfig {
 	newReferenceConfig := &ReferenceConfig{}
@@ -425,6 +599,11 @@ func (pcb *ReferenceConfigBuilder) SetRegistryIDs(registryIDs ...string) *Refere
 	return pcb
 }
 
+func (pcb *ReferenceConfigBuilder) SetCheck(check bool) *ReferenceConfigBuilder {
+	pcb.referenceConfig.Check = &check
+	return pcb
+}
+
 func (pcb *ReferenceConfigBuilder) SetGeneric(generic bool) *ReferenceConfigBuilder {
 	if generic {
 		pcb.referenceConfig.Generic = "true"
@@ -524,6 +703,11 @@ func (pcb *ReferenceConfigBuilder) SetTracingKey(tracingKey string) *ReferenceCo
 	return pcb
 }
 
+func (pcb *ReferenceConfigBuilder) SetLazy(lazy bool) *ReferenceConfigBuilder {
+	pcb.referenceConfig.Lazy = lazy
+	return pcb
+}
+
 func (pcb *ReferenceConfigBuilder) Build() *ReferenceConfig {
 	return pcb.referenceConfig
 }
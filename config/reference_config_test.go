@@ -18,6 +18,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -26,7 +27,9 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	_ "dubbo.apache.org/dubbo-go/v3/proxy/proxy_factory"
 )
 
@@ -460,3 +463,107 @@ func TestNewReferenceConfigBuilder(t *testing.T) {
 	invoker := config.GetInvoker()
 	assert.Nil(t, invoker)
 }
+
+func TestReferenceConfigMethodCache(t *testing.T) {
+	config := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		AddMethodConfig(&MethodConfig{Name: "GetUser", Cache: "expiring", CacheTTL: "10s"}).
+		Build()
+	config.rootConfig = NewRootConfigBuilder().Build()
+
+	values := config.getURLMap()
+	assert.Contains(t, strings.Split(values.Get(constant.ReferenceFilterKey), ","), constant.CacheFilterKey)
+	assert.Equal(t, "expiring", values.Get("methods.GetUser."+constant.CacheKey))
+	assert.Equal(t, "10000", values.Get("methods.GetUser."+constant.CacheTTLKey))
+	assert.Empty(t, values.Get("methods.GetUser."+constant.CacheSizeKey))
+}
+
+func TestReferenceConfigMethodInheritance(t *testing.T) {
+	config := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		SetRequestTimeout("5s").
+		SetRetries("2").
+		SetSticky(true).
+		AddMethodConfig(&MethodConfig{Name: "get*", RequestTimeout: "1s"}).
+		AddMethodConfig(&MethodConfig{Name: "getUser", Retries: "0", Sticky: true}).
+		Build()
+	config.rootConfig = NewRootConfigBuilder().Build()
+
+	u := common.NewURLWithOptions(common.WithParams(config.getURLMap()))
+	// the methods set nothing but what they override
+	assert.Empty(t, u.GetParam("methods.get*."+constant.RetriesKey, ""))
+	assert.Empty(t, u.GetParam("methods.get*."+constant.StickyKey, ""))
+	assert.Empty(t, u.GetParam("methods.getUser."+constant.TimeoutKey, ""))
+	assert.Equal(t, "1000", u.GetMethodParam("getUser", constant.TimeoutKey, u.GetParam(constant.TimeoutKey, "")))
+	assert.Equal(t, "0", u.GetMethodParam("getUser", constant.RetriesKey, u.GetParam(constant.RetriesKey, "")))
+	assert.Equal(t, "1000", u.GetMethodParam("getOrder", constant.TimeoutKey, u.GetParam(constant.TimeoutKey, "")))
+	assert.Equal(t, "2", u.GetMethodParam("getOrder", constant.RetriesKey, u.GetParam(constant.RetriesKey, "")))
+	assert.True(t, u.GetMethodParamBool("getOrder", constant.StickyKey, u.GetParamBool(constant.StickyKey, false)))
+	assert.Equal(t, "5000", u.GetMethodParam("sayHello", constant.TimeoutKey, u.GetParam(constant.TimeoutKey, "")))
+}
+
+func TestReferenceConfigCheckAvailable(t *testing.T) {
+	root := NewRootConfigBuilder().
+		AddRegistry("zk", NewRegistryConfigBuilder().SetProtocol("zookeeper").SetAddress("127.0.0.1:2181").Build()).
+		Build()
+	root.Consumer.MaxWaitTimeForServiceDiscovery = "100ms"
+	unavailable := protocol.NewBaseInvoker(common.NewURLWithOptions())
+	unavailable.Destroy()
+
+	rc := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		SetRegistryIDs("zk").
+		SetCheck(true).
+		Build()
+	rc.rootConfig = root
+	err := rc.checkAvailable(unavailable)
+	assert.EqualError(t, err, "No provider available of the service org.apache.dubbo.HelloService from the "+
+		"registries [zk(zookeeper://127.0.0.1:2181)] within 100ms, please check if the providers have been started and "+
+		"registered, or set check=false to start without them")
+
+	rc.URL = "dubbo://127.0.0.1:20000"
+	assert.Contains(t, rc.checkAvailable(unavailable).Error(), "from the url dubbo://127.0.0.1:20000 within")
+
+	// the reference starts without any provider if it doesn't check them
+	rc = NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		SetRegistryIDs("zk").
+		SetCheck(false).
+		Build()
+	rc.rootConfig = root
+	assert.Nil(t, rc.checkAvailable(unavailable))
+}
+
+func TestReferenceConfigFilters(t *testing.T) {
+	config := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		Build()
+	config.rootConfig = NewRootConfigBuilder().Build()
+	config.Generic = "true"
+
+	config.Filter = "-cshutdown,myFilter"
+	values := config.getURLMap()
+	assert.Equal(t, "generic,myFilter", values.Get(constant.ReferenceFilterKey))
+
+	config.Filter = "myFilter,default"
+	values = config.getURLMap()
+	assert.Equal(t, "myFilter,generic,cshutdown", values.Get(constant.ReferenceFilterKey))
+
+	config.Filter = "myFilter,-myFilter"
+	assert.Error(t, checkFilters(config.Filter))
+}
+
+func TestReferenceConfigWebsocketTransport(t *testing.T) {
+	config := NewReferenceConfigBuilder().
+		SetInterface("org.apache.dubbo.HelloService").
+		SetProtocol("dubbo").
+		Build()
+	config.rootConfig = NewRootConfigBuilder().Build()
+	assert.Empty(t, config.getURLMap().Get(constant.TransportKey))
+
+	// the consumer dialing by websocket selects only the providers accepting it
+	config.rootConfig = NewRootConfigBuilder().
+		SetProtocols(map[string]*ProtocolConfig{"dubbo": {Name: "dubbo", Transport: &TransportConfig{Websocket: true}}}).
+		Build()
+	assert.Equal(t, constant.TransportWebsocket, config.getURLMap().Get(constant.TransportKey))
+}
@@ -57,6 +57,8 @@ type RegistryConfig struct {
 	RegistryType      string            `yaml:"registry-type"`
 	UseAsMetaReport   bool              `default:"true" yaml:"use-as-meta-report" json:"use-as-meta-report,omitempty" property:"use-as-meta-report"`
 	UseAsConfigCenter bool              `default:"true" yaml:"use-as-config-center" json:"use-as-config-center,omitempty" property:"use-as-config-center"`
+	// TLSProfile is the tls profile of the connections to the registry, see RootConfig.TLSProfiles
+	TLSProfile string `yaml:"tls-profile" json:"tls-profile,omitempty" property:"tls-profile"`
 }
 
 // Prefix dubbo.registries
@@ -84,6 +86,7 @@ func (c *RegistryConfig) getUrlMap(roleType common.RoleType) url.Values {
 	urlMap.Set(constant.RegistryKey+"."+constant.WeightKey, strconv.FormatInt(c.Weight, 10))
 	urlMap.Set(constant.RegistryTTLKey, c.TTL)
 	urlMap.Set(constant.ClientNameKey, clientNameID(c, c.Protocol, c.Address))
+	setTLSProfileParams(urlMap, c.TLSProfile)
 
 	for k, v := range c.Params {
 		urlMap.Set(k, v)
@@ -476,6 +479,11 @@ func (rcb *RegistryConfigBuilder) SetRegistryType(registryType string) *Registry
 	return rcb
 }
 
+func (rcb *RegistryConfigBuilder) SetTLSProfile(tlsProfile string) *RegistryConfigBuilder {
+	rcb.registryConfig.TLSProfile = tlsProfile
+	return rcb
+}
+
 func (rcb *RegistryConfigBuilder) Build() *RegistryConfig {
 	if err := rcb.registryConfig.Init(); err != nil {
 		panic(err)
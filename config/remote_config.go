@@ -56,7 +56,7 @@ func (rc *RemoteConfig) Init() error {
 // GetTimeout return timeout duration.
 // if the configure is invalid, or missing, the default value 5s will be returned
 func (rc *RemoteConfig) GetTimeout() time.Duration {
-	if res, err := time.ParseDuration(rc.Timeout); err == nil {
+	if res, err := common.ParseDuration(rc.Timeout); err == nil {
 		return res
 	}
 	return 5 * time.Second
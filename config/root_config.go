@@ -39,6 +39,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/metadata/service/exporter"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 var (
@@ -60,12 +61,23 @@ type RootConfig struct {
 	Tracing             map[string]*TracingConfig  `yaml:"tracing" json:"tracing,omitempty" property:"tracing"`
 	Logger              *LoggerConfig              `yaml:"logger" json:"logger,omitempty" property:"logger"`
 	Shutdown            *ShutdownConfig            `yaml:"shutdown" json:"shutdown,omitempty" property:"shutdown"`
+	Qos                 *QosConfig                 `yaml:"qos" json:"qos,omitempty" property:"qos"`
+	Health              *HealthConfig              `yaml:"health" json:"health,omitempty" property:"health"`
 	Router              []*RouterConfig            `yaml:"router" json:"router,omitempty" property:"router"`
 	EventDispatcherType string                     `default:"direct" yaml:"event-dispatcher-type" json:"event-dispatcher-type,omitempty"`
 	CacheFile           string                     `yaml:"cache_file" json:"cache_file,omitempty" property:"cache_file"`
 	Custom              *CustomConfig              `yaml:"custom" json:"custom,omitempty" property:"custom"`
 	Profiles            *ProfilesConfig            `yaml:"profiles" json:"profiles,omitempty" property:"profiles"`
 	TLSConfig           *TLSConfig                 `yaml:"tls_config" json:"tls_config,omitempty" property:"tls_config"`
+	// TLSProfiles are the named tls profiles shared by the protocols, the registries, the config center and the
+	// metadata report referring to them by tls-profile
+	TLSProfiles map[string]*TLSConfig `yaml:"tls-config" json:"tls-config,omitempty" property:"tls-config"`
+	// StrictConfig fails the loading of the config files using the deprecated keys instead of migrating them
+	StrictConfig bool `yaml:"strict-config" json:"strict-config,omitempty" property:"strict-config"`
+
+	// sources are the configs merged into the root config, nil if it is neither loaded from the config files nor
+	// merged with the config center
+	sources *configSources
 }
 
 func SetRootConfig(r RootConfig) {
@@ -131,14 +143,20 @@ func registerPOJO() {
 // It's deprecated for user to call rootConfig.Init() manually, try config.Load(config.WithRootConfig(rootConfig)) instead.
 func (rc *RootConfig) Init() error {
 	registerPOJO()
-	if err := rc.Logger.Init(); err != nil { // init default logger
+	if err := rc.initLogger(); err != nil { // init default logger
+		return err
+	}
+	if err := initTLSProfiles(rc); err != nil {
 		return err
 	}
 	if err := rc.ConfigCenter.Init(rc); err != nil {
+		if rc.ConfigCenter.IsCheck() {
+			return err
+		}
 		logger.Infof("[Config Center] Config center doesn't start")
 		logger.Debugf("config center doesn't start because %s", err)
 	} else {
-		if err = rc.Logger.Init(); err != nil { // init logger using config from config center again
+		if err = rc.initLogger(); err != nil { // init logger using config from config center again
 			return err
 		}
 	}
@@ -168,6 +186,7 @@ func (rc *RootConfig) Init() error {
 
 	// init registry
 	for _, reg := range rc.Registries {
+		reg.Group = resolveProfile(reg.Group, rc.Profiles)
 		if err := reg.Init(); err != nil {
 			return err
 		}
@@ -200,6 +219,18 @@ func (rc *RootConfig) Init() error {
 	if err := rc.Shutdown.Init(); err != nil {
 		return err
 	}
+	if rc.Qos == nil {
+		rc.Qos = NewQosConfigBuilder().Build()
+	}
+	if err := rc.Qos.Init(); err != nil {
+		return err
+	}
+	if rc.Health == nil {
+		rc.Health = NewHealthConfigBuilder().Build()
+	}
+	if err := rc.Health.Init(); err != nil {
+		return err
+	}
 	SetRootConfig(*rc)
 	// todo if we can remove this from Init in the future?
 	rc.Start()
@@ -208,9 +239,13 @@ func (rc *RootConfig) Init() error {
 
 func (rc *RootConfig) Start() {
 	startOnce.Do(func() {
+		fireLifecycleEvent(extension.LifecycleConfigLoaded)
 		gracefulShutdownInit()
-		rc.Consumer.Load()
+		// export the services first so that references to them in the same process can be connected
 		rc.Provider.Load()
+		fireLifecycleEvent(extension.LifecycleServicesExported)
+		rc.Consumer.Load()
+		fireLifecycleEvent(extension.LifecycleReferencesReady)
 		// todo if register consumer instance or has exported services
 		exportMetadataService()
 		registerServiceInstance()
@@ -233,6 +268,8 @@ func newEmptyRootConfig() *RootConfig {
 		Logger:         NewLoggerConfigBuilder().Build(),
 		Custom:         NewCustomConfigBuilder().Build(),
 		Shutdown:       NewShutDownConfigBuilder().Build(),
+		Qos:            NewQosConfigBuilder().Build(),
+		Health:         NewHealthConfigBuilder().Build(),
 		TLSConfig:      NewTLSConfigBuilder().Build(),
 	}
 	return newRootConfig
@@ -306,6 +343,16 @@ func (rb *RootConfigBuilder) SetShutdown(shutdown *ShutdownConfig) *RootConfigBu
 	return rb
 }
 
+func (rb *RootConfigBuilder) SetQos(qos *QosConfig) *RootConfigBuilder {
+	rb.rootConfig.Qos = qos
+	return rb
+}
+
+func (rb *RootConfigBuilder) SetHealth(health *HealthConfig) *RootConfigBuilder {
+	rb.rootConfig.Health = health
+	return rb
+}
+
 func (rb *RootConfigBuilder) SetRouter(router []*RouterConfig) *RootConfigBuilder {
 	rb.rootConfig.Router = router
 	return rb
@@ -336,11 +383,24 @@ func (rb *RootConfigBuilder) SetShutDown(shutDownConfig *ShutdownConfig) *RootCo
 	return rb
 }
 
+func (rb *RootConfigBuilder) SetProfiles(profiles *ProfilesConfig) *RootConfigBuilder {
+	rb.rootConfig.Profiles = profiles
+	return rb
+}
+
 func (rb *RootConfigBuilder) SetTLSConfig(tlsConfig *TLSConfig) *RootConfigBuilder {
 	rb.rootConfig.TLSConfig = tlsConfig
 	return rb
 }
 
+func (rb *RootConfigBuilder) AddTLSProfile(name string, tlsConfig *TLSConfig) *RootConfigBuilder {
+	if rb.rootConfig.TLSProfiles == nil {
+		rb.rootConfig.TLSProfiles = make(map[string]*TLSConfig)
+	}
+	rb.rootConfig.TLSProfiles[name] = tlsConfig
+	return rb
+}
+
 func (rb *RootConfigBuilder) Build() *RootConfig {
 	return rb.rootConfig
 }
@@ -397,20 +457,74 @@ func publishMapping(sc exporter.MetadataServiceExporter) error {
 
 // Process receive changing listener's event, dynamic update config
 func (rc *RootConfig) Process(event *config_center.ConfigChangeEvent) {
+	rc.process(rc.ConfigCenter.fileOf(rc, event.Key), event)
+}
+
+// process dynamically updates the config by the change of the config @file, which is merged with the other sources
+// by the precedence if the file is retrieved from the config center
+func (rc *RootConfig) process(file *configFile, event *config_center.ConfigChangeEvent) {
 	logger.Infof("CenterConfig process event:\n%+v", event)
-	config := NewLoaderConf(WithBytes([]byte(event.Value.(string))))
-	koan := GetConfigResolver(config)
+	if event.Unchanged() {
+		return
+	}
+	content, _ := event.Value.(string)
+	if file == nil || rc.sources == nil {
+		koan, err := newConfigResolver(NewLoaderConf(WithBytes([]byte(content))))
+		if err != nil {
+			logger.Errorf("CenterConfig process the config failed, got error %v", err)
+			return
+		}
+		rc.update(koan)
+		return
+	}
+	values, err := parseConfig(rc.ConfigCenter.FileExtension, content)
+	if err != nil {
+		logger.Errorf("CenterConfig process the config %s failed, got error %v", file.dataId, err)
+		return
+	}
+	if event.ConfigType == remoting.EventTypeDel {
+		values = nil
+	}
+	if err = rc.mergeSource(file.source, values); err != nil {
+		logger.Errorf("CenterConfig process the config %s failed, got error %v", file.dataId, err)
+		return
+	}
+	// refresh the snapshot used if the config center is unreachable on the next startup
+	if event.ConfigType != remoting.EventTypeDel {
+		rc.ConfigCenter.saveSnapshot(*file, content)
+	}
+}
 
+// mergeSource replaces the values of @source with @values, which are merged with the other sources by the precedence
+// to update the config dynamically. The previous values are restored if the merged config is malformed.
+func (rc *RootConfig) mergeSource(source string, values map[string]interface{}) error {
+	previous := rc.sources.get(source)
+	rc.sources.set(source, values)
+	koan, err := rc.sources.merged()
+	if err == nil {
+		if rc.update(koan) {
+			return nil
+		}
+		err = perrors.New("the merged config is malformed")
+	}
+	rc.sources.set(source, previous)
+	return err
+}
+
+// update dynamically updates the config by the resolver @koan, it returns false if the config is malformed
+func (rc *RootConfig) update(koan *koanf.Koanf) bool {
 	updateRootConfig := &RootConfig{}
 	if err := koan.UnmarshalWithConf(rc.Prefix(),
 		updateRootConfig, koanf.UnmarshalConf{Tag: "yaml"}); err != nil {
 		logger.Errorf("CenterConfig process unmarshalConf failed, got error %#v", err)
-		return
+		return false
 	}
 	// dynamically update register
 	for registerId, updateRegister := range updateRootConfig.Registries {
-		register := rc.Registries[registerId]
-		register.DynamicUpdateProperties(updateRegister)
+		// the registries added at runtime are not connected
+		if register, ok := rc.Registries[registerId]; ok {
+			register.DynamicUpdateProperties(updateRegister)
+		}
 	}
 	// dynamically update consumer
 	rc.Consumer.DynamicUpdateProperties(updateRootConfig.Consumer)
@@ -420,4 +534,5 @@ func (rc *RootConfig) Process(event *config_center.ConfigChangeEvent) {
 
 	// dynamically update metric
 	rc.Metric.DynamicUpdateProperties(updateRootConfig.Metric)
+	return true
 }
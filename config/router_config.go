@@ -17,8 +17,18 @@
 
 package config
 
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
 import (
 	"github.com/creasty/defaults"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
 )
 
 import (
@@ -38,8 +48,26 @@ type RouterConfig struct {
 	Priority   int      `default:"0" yaml:"priority" json:"priority,omitempty" property:"priority"`
 	Conditions []string `yaml:"conditions" json:"conditions,omitempty" property:"conditions"`
 	Tags       []Tag    `yaml:"tags" json:"tags,omitempty" property:"tags"`
+	// File is the yaml file the rule is loaded from, e.g. ./rules/tag-router.yml
+	File string `yaml:"file,omitempty" json:"file,omitempty" property:"file"`
+	// Subscribe makes the rule of the same key in the config center override the local rule at runtime, the local
+	// rule is used again once the one of the config center is deleted
+	Subscribe *bool `default:"true" yaml:"subscribe,omitempty" json:"subscribe,omitempty" property:"subscribe"`
 }
 
+// RouterRule is a router rule configured locally, which is used unless the config center has the rule of the key
+type RouterRule struct {
+	// Key is the key of the rule in the config center, e.g. user-center.tag-router
+	Key string
+	// Content is the rule in yaml, which is parsed by the router like the one of the config center
+	Content string
+	// Subscribe means the rule of the config center overrides it
+	Subscribe bool
+}
+
+// routerRules are the local router rules keyed by the key in the config center
+var routerRules sync.Map
+
 type Tag struct {
 	Name      string   `yaml:"name" json:"name,omitempty" property:"name"`
 	Addresses []string `yaml:"addresses" json:"addresses,omitempty" property:"addresses"`
@@ -51,12 +79,96 @@ func (RouterConfig) Prefix() string {
 }
 
 func (c *RouterConfig) Init() error {
+	if len(c.File) != 0 {
+		if err := c.loadFile(); err != nil {
+			return err
+		}
+	}
 	if err := defaults.Set(c); err != nil {
 		return err
 	}
 	return verify(c)
 }
 
+// loadFile loads the rule from File, the errors point to the line of the file
+func (c *RouterConfig) loadFile() error {
+	content, err := os.ReadFile(c.File)
+	if err != nil {
+		return perrors.WithMessagef(err, "read the router rule file %s", c.File)
+	}
+	rule := &RouterConfig{}
+	if err = yaml.UnmarshalStrict(content, rule); err != nil {
+		return perrors.WithMessagef(err, "the router rule file %s", c.File)
+	}
+	if field, err := rule.validate(); err != nil {
+		if line := lineOf(string(content), field); line > 0 {
+			return perrors.WithMessagef(err, "the router rule file %s line %d", c.File, line)
+		}
+		return perrors.WithMessagef(err, "the router rule file %s", c.File)
+	}
+	rule.File, rule.Subscribe = c.File, c.Subscribe
+	*c = *rule
+	return nil
+}
+
+// validate checks the rule is either a condition rule or a tag rule of the scope, the field returned is the one
+// the error is found in
+func (c *RouterConfig) validate() (field string, err error) {
+	switch {
+	case c.Scope != constant.RouterScopeService && c.Scope != constant.RouterScopeApplication:
+		return "scope", perrors.Errorf("scope %q is neither %s nor %s", c.Scope, constant.RouterScopeService,
+			constant.RouterScopeApplication)
+	case len(c.Key) == 0:
+		return "key", perrors.New("key is required")
+	case len(c.Conditions) == 0 && len(c.Tags) == 0:
+		return "key", perrors.New("either conditions or tags is required")
+	case len(c.Conditions) != 0 && len(c.Tags) != 0:
+		return "tags", perrors.New("conditions and tags are exclusive")
+	case len(c.Tags) != 0 && c.Scope != constant.RouterScopeApplication:
+		return "tags", perrors.Errorf("tags require the scope %s", constant.RouterScopeApplication)
+	}
+	for i, condition := range c.Conditions {
+		if len(strings.TrimSpace(condition)) == 0 {
+			return "conditions", perrors.Errorf("conditions[%d] is empty", i)
+		}
+	}
+	for i, tag := range c.Tags {
+		if len(tag.Name) == 0 {
+			return "tags", perrors.Errorf("the name of tags[%d] is required", i)
+		}
+	}
+	return "", nil
+}
+
+// ruleKey returns the key of the rule in the config center
+func (c *RouterConfig) ruleKey() string {
+	if len(c.Tags) != 0 {
+		return c.Key + constant.TagRouterRuleSuffix
+	}
+	key := c.Key
+	if c.Scope == constant.RouterScopeService {
+		// the key of the service rule is interface:version:group
+		if parts := strings.Split(key, ":"); len(parts) < 3 {
+			key += strings.Repeat(":", 3-len(parts))
+		}
+	}
+	return key + constant.ConditionRouterRuleSuffix
+}
+
+// lineOf returns the line number of the first occurrence of @field in the yaml @content, 0 if it is absent
+func lineOf(content, field string) int {
+	if len(field) == 0 {
+		return 0
+	}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "- ")
+		if strings.HasPrefix(line, field+":") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
 func initRouterConfig(rc *RootConfig) error {
 	routers := rc.Router
 	if len(routers) > 0 {
@@ -69,9 +181,46 @@ func initRouterConfig(rc *RootConfig) error {
 	}
 
 	//chain.SetVSAndDRConfigByte(vsBytes, drBytes)
+	return SetRouterRules(routers)
+}
+
+// SetRouterRules replaces the local router rules by @routers, which are initialized, the routers use them unless
+// the config center has the rules of the same keys
+func SetRouterRules(routers []*RouterConfig) error {
+	rules := make(map[string]*RouterRule, len(routers))
+	for _, r := range routers {
+		if _, err := r.validate(); err != nil {
+			return perrors.WithMessagef(err, "the router rule of %s", r.Key)
+		}
+		content, err := yaml.Marshal(r)
+		if err != nil {
+			return err
+		}
+		rule := &RouterRule{Key: r.ruleKey(), Content: string(content), Subscribe: r.Subscribe == nil || *r.Subscribe}
+		if _, ok := rules[rule.Key]; ok {
+			return perrors.Errorf("duplicate router rules of %s", rule.Key)
+		}
+		rules[rule.Key] = rule
+	}
+	routerRules.Range(func(key, _ interface{}) bool {
+		routerRules.Delete(key)
+		return true
+	})
+	for key, rule := range rules {
+		routerRules.Store(key, rule)
+	}
 	return nil
 }
 
+// GetRouterRule returns the local router rule of @key, which is the key of the rule in the config center
+func GetRouterRule(key string) (*RouterRule, bool) {
+	rule, ok := routerRules.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return rule.(*RouterRule), true
+}
+
 type RouterConfigBuilder struct {
 	routerConfig *RouterConfig
 }
@@ -24,6 +24,8 @@ import (
 
 import (
 	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/yaml.v2"
 )
 
 import (
@@ -90,3 +92,50 @@ func TestNewRouterConfigBuilder(t *testing.T) {
 
 	assert.Equal(t, config.Prefix(), constant.RouterConfigPrefix)
 }
+
+func TestRouterConfigFile(t *testing.T) {
+	rc := NewRootConfigBuilder().SetRouter([]*RouterConfig{{File: "testdata/router/tag-router.yml"},
+		{File: "testdata/router/condition-router.yml", Subscribe: new(bool)}}).Build()
+	assert.NoError(t, initRouterConfig(rc))
+	defer SetRouterRules(nil)
+
+	r := rc.Router[0]
+	assert.Equal(t, constant.RouterScopeApplication, r.Scope)
+	assert.Equal(t, "user-center", r.Key)
+	assert.True(t, *r.Force)
+	assert.True(t, *r.Enabled)
+	assert.Equal(t, []Tag{{Name: "gray", Addresses: []string{"192.168.0.1:20000"}}}, r.Tags)
+
+	rule, ok := GetRouterRule("user-center" + constant.TagRouterRuleSuffix)
+	assert.True(t, ok)
+	assert.True(t, rule.Subscribe)
+	parsed := &RouterConfig{}
+	assert.NoError(t, yaml.Unmarshal([]byte(rule.Content), parsed))
+	assert.Equal(t, r.Tags, parsed.Tags)
+
+	// the key of the service rule is interface:version:group
+	rule, ok = GetRouterRule("com.foo.UserService::" + constant.ConditionRouterRuleSuffix)
+	assert.True(t, ok)
+	assert.False(t, rule.Subscribe)
+	assert.Contains(t, rule.Content, "method = getUser => host = 192.168.0.1")
+}
+
+func TestRouterConfigInvalidFile(t *testing.T) {
+	rc := NewRootConfigBuilder().SetRouter([]*RouterConfig{{File: "testdata/router/invalid-scope.yml"}}).Build()
+	err := initRouterConfig(rc)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "testdata/router/invalid-scope.yml line 3")
+
+	rc = NewRootConfigBuilder().SetRouter([]*RouterConfig{{File: "testdata/router/unknown-field.yml"}}).Build()
+	err = initRouterConfig(rc)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 5")
+
+	rc = NewRootConfigBuilder().SetRouter([]*RouterConfig{{File: "testdata/router/absent.yml"}}).Build()
+	assert.Error(t, initRouterConfig(rc))
+
+	// the rules of the same key
+	rc = NewRootConfigBuilder().SetRouter([]*RouterConfig{{File: "testdata/router/tag-router.yml"},
+		{File: "testdata/router/tag-router.yml"}}).Build()
+	assert.Error(t, initRouterConfig(rc))
+}
@@ -91,6 +91,15 @@ func SetConsumerServiceByInterfaceName(interfaceName string, srv common.RPCServi
 	interfaceNameConServices[interfaceName] = srv
 }
 
+// removeConsumerServiceByInterfaceName removes @srv of @interfaceName if it's not replaced by another reference
+func removeConsumerServiceByInterfaceName(interfaceName string, srv interface{}) {
+	interfaceNameConServicesLock.Lock()
+	defer interfaceNameConServicesLock.Unlock()
+	if interfaceNameConServices[interfaceName] == srv {
+		delete(interfaceNameConServices, interfaceName)
+	}
+}
+
 // GetConsumerServiceByInterfaceName is used by pb serialization
 func GetConsumerServiceByInterfaceName(interfaceName string) common.RPCService {
 	interfaceNameConServicesLock.Lock()
@@ -42,7 +42,9 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
 )
@@ -72,11 +74,16 @@ type ServiceConfig struct {
 	TpsLimitRejectedHandler     string            `yaml:"tps.limit.rejected.handler" json:"tps.limit.rejected.handler,omitempty" property:"tps.limit.rejected.handler"`
 	ExecuteLimit                string            `yaml:"execute.limit" json:"execute.limit,omitempty" property:"execute.limit"`
 	ExecuteLimitRejectedHandler string            `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
+	ExecuteLimitQueue           string            `yaml:"execute.limit.queue" json:"execute.limit.queue,omitempty" property:"execute.limit.queue"`
+	ExecuteLimitQueueTimeout    string            `yaml:"execute.limit.queue.timeout" json:"execute.limit.queue.timeout,omitempty" property:"execute.limit.queue.timeout"`
 	Auth                        string            `yaml:"auth" json:"auth,omitempty" property:"auth"`
 	NotRegister                 bool              `yaml:"not_register" json:"not_register,omitempty" property:"not_register"`
 	ParamSign                   string            `yaml:"param.sign" json:"param.sign,omitempty" property:"param.sign"`
 	Tag                         string            `yaml:"tag" json:"tag,omitempty" property:"tag"`
 	TracingKey                  string            `yaml:"tracing-key" json:"tracing-key,omitempty" propertiy:"tracing-key"`
+	Validation                  string            `yaml:"validation" json:"validation,omitempty" property:"validation"`
+	// Executor is the pool of the service, the service shares the executor of the provider if it's nil
+	Executor *ExecutorConfig `yaml:"executor" json:"executor,omitempty" property:"executor"`
 
 	RCProtocolsMap  map[string]*ProtocolConfig
 	RCRegistriesMap map[string]*RegistryConfig
@@ -91,8 +98,12 @@ type ServiceConfig struct {
 	cacheProtocol   protocol.Protocol
 	exportersLock   sync.Mutex
 	exporters       []protocol.Exporter
+	// publishedURLs are the urls whose service definitions are published, they are unpublished at unexport
+	publishedURLs []*common.URL
 
 	metadataType string
+	// providerExecutor is the executor of the provider shared by the services without their own executors
+	providerExecutor *ExecutorConfig
 }
 
 // Prefix returns dubbo.service.${InterfaceName}.
@@ -127,6 +138,7 @@ func (s *ServiceConfig) Init(rc *RootConfig) error {
 	}
 	if rc.Provider != nil {
 		s.ProxyFactoryKey = rc.Provider.ProxyFactory
+		s.providerExecutor = rc.Provider.Executor
 	}
 	s.RegistryIDs = translateIds(s.RegistryIDs)
 	if len(s.RegistryIDs) <= 0 {
@@ -178,7 +190,7 @@ func (s *ServiceConfig) check() error {
 	}
 
 	if s.TpsLimitInterval != "" {
-		tpsLimitInterval, err := strconv.ParseInt(s.TpsLimitInterval, 0, 0)
+		tpsLimitInterval, err := common.ParseDuration(s.TpsLimitInterval)
 		if err != nil {
 			return fmt.Errorf("[ServiceConfig] Cannot parse the configuration tps.limit.interval for service %s, please check your configuration", s.Interface)
 		}
@@ -196,6 +208,15 @@ func (s *ServiceConfig) check() error {
 			return fmt.Errorf("[ServiceConfig] The configuration tps.limit.rate for service %s must be positive, please check your configuration", s.Interface)
 		}
 	}
+
+	if err := checkFilters(s.Filter); err != nil {
+		return fmt.Errorf("[ServiceConfig] Invalid filters of service %s: %v, please check your configuration", s.Interface, err)
+	}
+	if s.Executor != nil {
+		if err := s.Executor.check(); err != nil {
+			return fmt.Errorf("[ServiceConfig] Invalid executor of service %s: %v, please check your configuration", s.Interface, err)
+		}
+	}
 	return nil
 }
 
@@ -232,18 +253,23 @@ func getRandomPort(protocolConfigs []*ProtocolConfig) *list.List {
 	return ports
 }
 
-// Export exports the service
-func (s *ServiceConfig) Export() error {
+// Export exports the service and registers it to the registries, it could be called at runtime and again after
+// Unexport
+func (s *ServiceConfig) Export() (err error) {
 	// TODO: delay export
-	if s.unexported != nil && s.unexported.Load() {
-		err := perrors.Errorf("The service %v has already unexported!", s.Interface)
-		logger.Errorf(err.Error())
-		return err
-	}
 	if s.exported != nil && s.exported.Load() {
 		logger.Warnf("The service %v has already exported!", s.Interface)
 		return nil
 	}
+	if s.unexported != nil {
+		s.unexported.Store(false)
+	}
+	defer func() {
+		// the service is exported by either all of its protocols or none of them
+		if err != nil {
+			s.unexportAll()
+		}
+	}()
 
 	regUrls := make([]*common.URL, 0)
 	if !s.NotRegister {
@@ -251,6 +277,9 @@ func (s *ServiceConfig) Export() error {
 	}
 
 	urlMap := s.getUrlMap()
+	if err = checkParams(urlMap); err != nil {
+		return perrors.WithMessagef(err, "[ServiceConfig] Invalid params of service %s, please check your configuration", s.Interface)
+	}
 	protocolConfigs := loadProtocol(s.ProtocolIDs, s.RCProtocolsMap)
 	if len(protocolConfigs) == 0 {
 		logger.Warnf("The service %v's '%v' protocols don't has right protocolConfigs, Please check your configuration center and transfer protocol ", s.Interface, s.ProtocolIDs)
@@ -290,9 +319,23 @@ func (s *ServiceConfig) Export() error {
 			common.WithParamsValue(constant.MaxServerSendMsgSize, proto.MaxServerSendMsgSize),
 			common.WithParamsValue(constant.MaxServerRecvMsgSize, proto.MaxServerRecvMsgSize),
 		)
+		if len(proto.Host) > 0 {
+			ivkURL.SetParam(constant.HostToRegistryKey, proto.Host)
+		}
+		if proto.Transport != nil && proto.Transport.WebsocketPort != "" {
+			// the consumers connecting by websocket select only the providers with the marker
+			ivkURL.SetParam(constant.TransportKey, constant.TransportWebsocket)
+			ivkURL.SetParam(constant.WebsocketPortKey, proto.Transport.WebsocketPort)
+			ivkURL.SetParam(constant.WebsocketPathKey, orDefault(proto.Transport.WebsocketPath, constant.DefaultWebsocketPath))
+		}
+		ivkURL.SetAttribute(constant.RPCServiceAttributeKey, s.rpcService)
 		if len(s.Tag) > 0 {
 			ivkURL.AddParam(constant.Tagkey, s.Tag)
 		}
+		if len(s.Serialization) == 0 && len(proto.Serialization) > 0 {
+			ivkURL.SetParam(constant.SerializationKey, proto.Serialization)
+		}
+		resolveMethodParams(ivkURL)
 
 		// post process the URL to be exported
 		s.postProcessConfig(ivkURL)
@@ -337,8 +380,14 @@ func (s *ServiceConfig) Export() error {
 			s.exporters = append(s.exporters, exporter)
 		}
 		publishServiceDefinition(ivkURL)
+		s.publishedURLs = append(s.publishedURLs, ivkURL)
 	}
 	s.exported.Store(true)
+	eventbus.Publish(eventbus.ServiceExported{
+		ServiceKey: common.ServiceKey(s.Interface, s.Group, s.Version),
+		Interface:  s.Interface,
+		URLs:       append([]*common.URL(nil), s.publishedURLs...),
+	})
 	return nil
 }
 
@@ -361,7 +410,8 @@ func loadProtocol(protocolIds []string, protocols map[string]*ProtocolConfig) []
 	return returnProtocols
 }
 
-// Unexport will call unexport of all exporters service config exported
+// Unexport will call unexport of all exporters service config exported, which unregisters the service from the
+// registries
 func (s *ServiceConfig) Unexport() {
 	if !s.exported.Load() {
 		return
@@ -369,7 +419,24 @@ func (s *ServiceConfig) Unexport() {
 	if s.unexported.Load() {
 		return
 	}
+	urls := s.publishedURLs
+	s.unexportAll()
+	s.exported.Store(false)
+	s.unexported.Store(true)
+	s.publishUnexported(urls)
+}
 
+// publishUnexported publishes the ServiceUnexported of the service exported with @urls
+func (s *ServiceConfig) publishUnexported(urls []*common.URL) {
+	eventbus.Publish(eventbus.ServiceUnexported{
+		ServiceKey: common.ServiceKey(s.Interface, s.Group, s.Version),
+		Interface:  s.Interface,
+		URLs:       urls,
+	})
+}
+
+// unexportAll unexports the service from all of the protocols exported, and unpublishes their definitions
+func (s *ServiceConfig) unexportAll() {
 	func() {
 		s.exportersLock.Lock()
 		defer s.exportersLock.Unlock()
@@ -378,9 +445,10 @@ func (s *ServiceConfig) Unexport() {
 		}
 		s.exporters = nil
 	}()
-
-	s.exported.Store(false)
-	s.unexported.Store(true)
+	for _, url := range s.publishedURLs {
+		unpublishServiceDefinition(url)
+	}
+	s.publishedURLs = nil
 }
 
 // Implement only store the @s and return
@@ -421,20 +489,9 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.OwnerKey, ac.Owner)
 	urlMap.Set(constant.EnvironmentKey, ac.Environment)
 
-	// filter
-	var filters string
-	if s.Filter == "" {
-		filters = constant.DefaultServiceFilters
-	} else {
-		filters = s.Filter
-	}
-	if s.adaptiveService {
-		filters += fmt.Sprintf(",%s", constant.AdaptiveServiceProviderFilterKey)
-	}
-	if s.metricsEnable {
-		filters += fmt.Sprintf(",%s", constant.MetricsFilterKey)
-	}
-	urlMap.Set(constant.ServiceFilterKey, filters)
+	// filter, the specification is validated by check
+	filters, _ := s.filters()
+	urlMap.Set(constant.ServiceFilterKey, strings.Join(filters, ","))
 
 	// filter special config
 	urlMap.Set(constant.AccessLogFilterKey, s.AccessLog)
@@ -449,6 +506,30 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 	// execute limit filter
 	urlMap.Set(constant.ExecuteLimitKey, s.ExecuteLimit)
 	urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, s.ExecuteLimitRejectedHandler)
+	urlMap.Set(constant.ExecuteLimitQueueKey, s.ExecuteLimitQueue)
+	urlMap.Set(constant.ExecuteLimitQueueTimeoutKey, s.ExecuteLimitQueueTimeout)
+
+	// executor, the service without its own executor shares the one of the provider
+	if s.Executor != nil {
+		workers, queueSize := s.Executor.Workers, s.Executor.QueueSize
+		if workers == 0 {
+			workers = gopool.DefaultWorkers
+		}
+		if queueSize == 0 {
+			queueSize = gopool.DefaultQueueSize
+		}
+		urlMap.Set(constant.ExecutorWorkersKey, strconv.Itoa(workers))
+		urlMap.Set(constant.ExecutorQueueSizeKey, strconv.Itoa(queueSize))
+		urlMap.Set(constant.ExecutorRejectionKey, s.Executor.Rejection)
+	} else if s.providerExecutor != nil {
+		urlMap.Set(constant.ExecutorSharedKey, "true")
+		urlMap.Set(constant.ExecutorRejectionKey, s.providerExecutor.Rejection)
+	}
+
+	// validation filter
+	if len(s.Validation) != 0 {
+		urlMap.Set(constant.ValidationKey, s.Validation)
+	}
 
 	// auth filter
 	urlMap.Set(constant.ServiceAuthKey, s.Auth)
@@ -458,21 +539,63 @@ func (s *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.ExportKey, strconv.FormatBool(s.export))
 	urlMap.Set(constant.PIDKey, fmt.Sprintf("%d", os.Getpid()))
 
+	// the methods, which could be wildcards like get*, inherit the values they don't set from the service
 	for _, v := range s.Methods {
 		prefix := "methods." + v.Name + "."
-		urlMap.Set(prefix+constant.LoadbalanceKey, v.LoadBalance)
-		urlMap.Set(prefix+constant.RetriesKey, v.Retries)
 		urlMap.Set(prefix+constant.WeightKey, strconv.FormatInt(v.Weight, 10))
+		for key, value := range map[string]string{
+			constant.LoadbalanceKey:                     v.LoadBalance,
+			constant.RetriesKey:                         v.Retries,
+			constant.TimeoutKey:                         v.RequestTimeout,
+			constant.TPSLimitStrategyKey:                v.TpsLimitStrategy,
+			constant.TPSLimitIntervalKey:                v.TpsLimitInterval,
+			constant.TPSLimitRateKey:                    v.TpsLimitRate,
+			constant.ExecuteLimitKey:                    v.ExecuteLimit,
+			constant.ExecuteRejectedExecutionHandlerKey: v.ExecuteLimitRejectedHandler,
+			constant.ExecuteLimitQueueKey:               v.ExecuteLimitQueue,
+			constant.ExecuteLimitQueueTimeoutKey:        v.ExecuteLimitQueueTimeout,
+			constant.ValidationKey:                      v.Validation,
+		} {
+			if len(value) != 0 {
+				urlMap.Set(prefix+key, value)
+			}
+		}
+	}
+	normalizeDurations(urlMap)
 
-		urlMap.Set(prefix+constant.TPSLimitStrategyKey, v.TpsLimitStrategy)
-		urlMap.Set(prefix+constant.TPSLimitIntervalKey, v.TpsLimitInterval)
-		urlMap.Set(prefix+constant.TPSLimitRateKey, v.TpsLimitRate)
+	return urlMap
+}
 
-		urlMap.Set(constant.ExecuteLimitKey, v.ExecuteLimit)
-		urlMap.Set(constant.ExecuteRejectedExecutionHandlerKey, v.ExecuteLimitRejectedHandler)
+// filters resolves the filters of the service from its specification, the default filters are the recovery filter,
+// which recovers the panics of the other filters too, the DefaultServiceFilters and the enabled features
+func (s *ServiceConfig) filters() ([]string, error) {
+	defaults := append([]string{constant.RecoveryFilterKey}, strings.Split(constant.DefaultServiceFilters, ",")...)
+	if s.adaptiveService {
+		defaults = append(defaults, constant.AdaptiveServiceProviderFilterKey)
 	}
+	if s.metricsEnable {
+		defaults = append(defaults, constant.MetricsFilterKey)
+	}
+	if s.validationEnabled() {
+		defaults = append(defaults, constant.ValidationFilterKey)
+	}
+	return protocolwrapper.ResolveFilters(s.Filter, defaults)
+}
 
-	return urlMap
+// validationEnabled returns whether the arguments of the service or any of its methods are validated
+func (s *ServiceConfig) validationEnabled() bool {
+	enabled := func(v string) bool {
+		return len(v) != 0 && !strings.EqualFold(v, "false")
+	}
+	if enabled(s.Validation) || enabled(s.Params[constant.ValidationKey]) {
+		return true
+	}
+	for _, v := range s.Methods {
+		if enabled(v.Validation) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetExportedUrls will return the url in service config's exporter
@@ -590,6 +713,11 @@ func (pcb *ServiceConfigBuilder) SetServiceID(id string) *ServiceConfigBuilder {
 	return pcb
 }
 
+func (pcb *ServiceConfigBuilder) SetExecutor(executor *ExecutorConfig) *ServiceConfigBuilder {
+	pcb.serviceConfig.Executor = executor
+	return pcb
+}
+
 func (pcb *ServiceConfigBuilder) SetNotRegister(notRegister bool) *ServiceConfigBuilder {
 	pcb.serviceConfig.NotRegister = notRegister
 	return pcb
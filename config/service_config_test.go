@@ -114,7 +114,17 @@ func TestNewServiceConfigBuilder(t *testing.T) {
 		values := serviceConfig.getUrlMap()
 		assert.Equal(t, values.Get("methods.Say.weight"), "0")
 		assert.Equal(t, values.Get("methods.Say.tps.limit.rate"), "")
-		assert.Equal(t, values.Get(constant.ServiceFilterKey), "echo,token,accesslog,tps,generic_service,execute,pshutdown")
+		assert.Equal(t, values.Get(constant.ServiceFilterKey), "recovery,echo,token,accesslog,tps,generic_service,execute,pshutdown")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		serviceConfig.Methods[0].Validation = "true"
+		defer func() {
+			serviceConfig.Methods[0].Validation = ""
+		}()
+		values := serviceConfig.getUrlMap()
+		assert.Equal(t, "true", values.Get("methods.Say.validation"))
+		assert.Equal(t, "recovery,echo,token,accesslog,tps,generic_service,execute,pshutdown,validation", values.Get(constant.ServiceFilterKey))
 	})
 
 	t.Run("Implement", func(t *testing.T) {
@@ -124,3 +134,67 @@ func TestNewServiceConfigBuilder(t *testing.T) {
 		assert.NotNil(t, serviceConfig.rpcService)
 	})
 }
+
+func TestServiceConfigFilters(t *testing.T) {
+	s := &ServiceConfig{metricsEnable: true}
+	filters, err := s.filters()
+	assert.NoError(t, err)
+	assert.Equal(t, "recovery,echo,token,accesslog,tps,generic_service,execute,pshutdown,metrics", strings.Join(filters, ","))
+
+	// remove the default filters
+	s.Filter = "-accesslog,-metrics,myFilter"
+	filters, err = s.filters()
+	assert.NoError(t, err)
+	assert.Equal(t, "recovery,echo,token,tps,generic_service,execute,pshutdown,myFilter", strings.Join(filters, ","))
+
+	// reorder the default filters
+	s.Filter = "-recovery,tps,default,myFilter"
+	filters, err = s.filters()
+	assert.NoError(t, err)
+	assert.Equal(t, "tps,echo,token,accesslog,generic_service,execute,pshutdown,metrics,myFilter", strings.Join(filters, ","))
+
+	s.Filter = "-default,myFilter"
+	filters, err = s.filters()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myFilter"}, filters)
+
+	s.Filter = "tps,myFilter,tps"
+	_, err = s.filters()
+	assert.Error(t, err)
+}
+
+func TestServiceConfigCheckFilters(t *testing.T) {
+	s := &ServiceConfig{Interface: "org.apache.dubbo.UserProvider", Filter: "-default,unknown"}
+	assert.Error(t, s.check())
+
+	s.Filter = "-default,-unknown"
+	assert.NoError(t, s.check())
+}
+
+func TestServiceConfigExecutor(t *testing.T) {
+	s := &ServiceConfig{
+		Interface: "org.apache.dubbo.UserProvider",
+		Executor:  &ExecutorConfig{Workers: 16, Rejection: "discard"},
+	}
+	assert.Error(t, s.check())
+	s.Executor.Rejection = constant.ExecutorRejectionCallerRuns
+	assert.NoError(t, s.check())
+
+	urlMap := s.getUrlMap()
+	assert.Equal(t, "16", urlMap.Get(constant.ExecutorWorkersKey))
+	assert.Equal(t, "1024", urlMap.Get(constant.ExecutorQueueSizeKey))
+	assert.Equal(t, constant.ExecutorRejectionCallerRuns, urlMap.Get(constant.ExecutorRejectionKey))
+
+	// the service without its own executor shares the one of the provider
+	s = &ServiceConfig{providerExecutor: &ExecutorConfig{Workers: 400, Rejection: constant.ExecutorRejectionAbort}}
+	urlMap = s.getUrlMap()
+	assert.Empty(t, urlMap.Get(constant.ExecutorWorkersKey))
+	assert.Equal(t, "true", urlMap.Get(constant.ExecutorSharedKey))
+	assert.Equal(t, constant.ExecutorRejectionAbort, urlMap.Get(constant.ExecutorRejectionKey))
+
+	// the service is handled inline without any executor
+	urlMap = (&ServiceConfig{}).getUrlMap()
+	assert.Empty(t, urlMap.Get(constant.ExecutorSharedKey))
+
+	assert.Error(t, (&ExecutorConfig{QueueSize: -1}).check())
+}
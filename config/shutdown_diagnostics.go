@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// shutdownSettleTimeout is how long the goroutines are waited for to exit after their components are destroyed
+var shutdownSettleTimeout = 3 * time.Second
+
+// ShutdownReport is what's still alive after the shutdown in the diagnostics mode, see VerifyShutdown
+type ShutdownReport struct {
+	// Resources are the goroutines and the connections started by the framework, attributed to their components
+	Resources []diagnostics.Resource `json:"resources,omitempty"`
+	// ProviderRequests and ConsumerRequests are the in-flight requests undrained
+	ProviderRequests int32 `json:"providerRequests,omitempty"`
+	ConsumerRequests int32 `json:"consumerRequests,omitempty"`
+	// PendingResponses are the requests of the clients still waiting for their responses
+	PendingResponses int `json:"pendingResponses,omitempty"`
+}
+
+// newShutdownReport waits at most @timeout for the tracked resources to be released, and reports what's left
+func newShutdownReport(shutdownConfig *ShutdownConfig, timeout time.Duration) *ShutdownReport {
+	return &ShutdownReport{
+		Resources:        diagnostics.Wait(timeout),
+		ProviderRequests: shutdownConfig.ProviderActiveCount.Load(),
+		ConsumerRequests: shutdownConfig.ConsumerActiveCount.Load(),
+		PendingResponses: remoting.PendingResponses(),
+	}
+}
+
+// Clean returns true if nothing is left
+func (r *ShutdownReport) Clean() bool {
+	return len(r.Resources) == 0 && r.ProviderRequests == 0 && r.ConsumerRequests == 0 && r.PendingResponses == 0
+}
+
+// nolint
+func (r *ShutdownReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d goroutines and connections, %d provider requests, %d consumer requests and %d pending responses are left",
+		len(r.Resources), r.ProviderRequests, r.ConsumerRequests, r.PendingResponses)
+	for _, resource := range r.Resources {
+		b.WriteString("\n\t")
+		b.WriteString(resource.String())
+	}
+	return b.String()
+}
+
+// VerifyShutdown returns the error listing the goroutines, the connections and the requests still alive after the
+// application is shut down or its components are destroyed, it waits a moment for the goroutines to exit. It's used
+// by the tests in the diagnostics mode, e.g.
+//
+//	defer diagnostics.Enable()()
+//	... // start and use the services
+//	GracefulShutdown()
+//	assert.NoError(t, VerifyShutdown())
+func VerifyShutdown() error {
+	if !diagnostics.Enabled() {
+		return perrors.Errorf("the shutdown diagnostics is disabled, enable it by %s=true or diagnostics.Enable",
+			constant.ShutdownDiagnosticsEnvKey)
+	}
+	if report := newShutdownReport(GetShutDown(), shutdownSettleTimeout); !report.Clean() {
+		return perrors.New(report.String())
+	}
+	return nil
+}
+
+// reportShutdown logs what's still alive after the graceful shutdown in the diagnostics mode
+func reportShutdown(shutdownConfig *ShutdownConfig) {
+	if !diagnostics.Enabled() {
+		return
+	}
+	report := newShutdownReport(shutdownConfig, shutdownSettleTimeout)
+	if report.Clean() {
+		logger.Info("Graceful shutdown --- Nothing is left. ")
+		return
+	}
+	data, _ := json.Marshal(report)
+	logger.Warnf("Graceful shutdown --- Something is left: %s", data)
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+)
+
+func TestVerifyShutdown(t *testing.T) {
+	assert.Error(t, VerifyShutdown())
+
+	defer diagnostics.Enable()()
+	defer func(timeout time.Duration) {
+		shutdownSettleTimeout = timeout
+	}(shutdownSettleTimeout)
+	shutdownSettleTimeout = 50 * time.Millisecond
+	assert.NoError(t, VerifyShutdown())
+
+	done := make(chan struct{})
+	diagnostics.Go("test-watcher", "/dubbo/test", func() {
+		<-done
+	})
+	owner := &struct{ int }{}
+	diagnostics.Track(owner, "test-client", diagnostics.KindConnection, "127.0.0.1:20000")
+	err := VerifyShutdown()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "2 goroutines and connections, 0 provider requests")
+	assert.Contains(t, err.Error(), "test-watcher goroutine{/dubbo/test} started at shutdown_diagnostics_test.go:")
+	assert.Contains(t, err.Error(), "test-client connection{127.0.0.1:20000}")
+
+	// the goroutine exiting in the settle timeout isn't reported
+	time.AfterFunc(10*time.Millisecond, func() {
+		close(done)
+	})
+	diagnostics.Untrack(owner)
+	assert.NoError(t, VerifyShutdown())
+
+	shutdownConfig := NewShutDownConfigBuilder().Build()
+	shutdownConfig.ProviderActiveCount.Inc()
+	report := newShutdownReport(shutdownConfig, 0)
+	assert.False(t, report.Clean())
+	assert.Equal(t, int32(1), report.ProviderRequests)
+}
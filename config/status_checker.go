@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+)
+
+func init() {
+	extension.SetStatusChecker("config-center", status.CheckerFunc(checkConfigCenter))
+	extension.SetStatusChecker("provider", status.CheckerFunc(checkProvider))
+	extension.SetStatusChecker("consumer", status.CheckerFunc(checkConsumer))
+}
+
+// checkConfigCenter checks whether the config center is reachable
+func checkConfigCenter(_ context.Context) status.Status {
+	dc := config.GetEnvInstance().GetDynamicConfiguration()
+	if dc == nil {
+		return status.OK("no config center")
+	}
+	if node, ok := dc.(interface{ IsAvailable() bool }); ok && !node.IsAvailable() {
+		return status.Error("the config center is unavailable")
+	}
+	return status.OK("")
+}
+
+// checkProvider checks whether all of the services are exported, so the servers of their protocols are listening
+func checkProvider(_ context.Context) status.Status {
+	if rootConfig == nil || rootConfig.Provider == nil || len(rootConfig.Provider.Services) == 0 {
+		return status.OK("no service")
+	}
+	var unexported []string
+	for id, sc := range rootConfig.Provider.Services {
+		if sc.exported == nil || !sc.IsExport() {
+			unexported = append(unexported, id)
+		}
+	}
+	if len(unexported) > 0 {
+		sort.Strings(unexported)
+		return status.Error("unexported services: " + strings.Join(unexported, ", "))
+	}
+	return status.OK(fmt.Sprintf("%d services exported", len(rootConfig.Provider.Services)))
+}
+
+// checkConsumer checks whether the references which check their providers at startup have available providers
+func checkConsumer(_ context.Context) status.Status {
+	if rootConfig == nil || rootConfig.Consumer == nil || len(rootConfig.Consumer.References) == 0 {
+		return status.OK("no reference")
+	}
+	var unavailable []string
+	for id, rc := range rootConfig.Consumer.References {
+		if rc.Check != nil && !*rc.Check {
+			continue
+		}
+		if invoker := rc.GetInvoker(); invoker == nil || !invoker.IsAvailable() {
+			unavailable = append(unavailable, id)
+		}
+	}
+	if len(unavailable) > 0 {
+		sort.Strings(unavailable)
+		return status.Error("references without available providers: " + strings.Join(unavailable, ", "))
+	}
+	return status.OK(fmt.Sprintf("%d references", len(rootConfig.Consumer.References)))
+}
@@ -33,6 +33,10 @@ type TLSConfig struct {
 	TLSCertFile   string `yaml:"tls-cert-file" json:"tls-cert-file" property:"tls-cert-file"`
 	TLSKeyFile    string `yaml:"tls-key-file" json:"tls-key-file" property:"tls-key-file"`
 	TLSServerName string `yaml:"tls-server-name" json:"tls-server-name" property:"tls-server-name"`
+	// InsecureSkipVerify makes the clients skip the verification of the server certificate, only for testing
+	InsecureSkipVerify bool `yaml:"insecure-skip-verify" json:"insecure-skip-verify" property:"insecure-skip-verify"`
+	// MinVersion is the minimum tls version of the tls profiles, one of 1.0, 1.1, 1.2 and 1.3
+	MinVersion string `yaml:"min-version" json:"min-version,omitempty" property:"min-version"`
 }
 
 func (t *TLSConfig) Prefix() string {
@@ -73,21 +77,26 @@ func GetServerTlsConfig(opt *TLSConfig) (*tls.Config, error) {
 // GetClientTlsConfig build client tls config from TLSConfig
 func GetClientTlsConfig(opt *TLSConfig) (*tls.Config, error) {
 	//no TLS
-	if opt.CACertFile == "" {
+	if opt.CACertFile == "" && !opt.InsecureSkipVerify {
 		return nil, nil
 	}
 	cfg := &tls.Config{
-		ServerName: opt.TLSServerName,
+		ServerName:         opt.TLSServerName,
+		InsecureSkipVerify: opt.InsecureSkipVerify,
 	}
-	ca := x509.NewCertPool()
-	caBytes, err := ioutil.ReadFile(opt.CACertFile)
-	if err != nil {
-		return nil, err
-	}
-	if ok := ca.AppendCertsFromPEM(caBytes); !ok {
-		return nil, err
+	var err error
+	if opt.CACertFile != "" {
+		ca := x509.NewCertPool()
+		var caBytes []byte
+		caBytes, err = ioutil.ReadFile(opt.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		if ok := ca.AppendCertsFromPEM(caBytes); !ok {
+			return nil, err
+		}
+		cfg.RootCAs = ca
 	}
-	cfg.RootCAs = ca
 	//need mTls
 	if opt.TLSCertFile != "" {
 		var cert tls.Certificate
@@ -140,6 +149,22 @@ func (tcb *TLSConfigBuilder) SetTLSServerName(tlsServerName string) *TLSConfigBu
 	return tcb
 }
 
+func (tcb *TLSConfigBuilder) SetInsecureSkipVerify(insecureSkipVerify bool) *TLSConfigBuilder {
+	if tcb.tlsConfig == nil {
+		tcb.tlsConfig = &TLSConfig{}
+	}
+	tcb.tlsConfig.InsecureSkipVerify = insecureSkipVerify
+	return tcb
+}
+
+func (tcb *TLSConfigBuilder) SetMinVersion(minVersion string) *TLSConfigBuilder {
+	if tcb.tlsConfig == nil {
+		tcb.tlsConfig = &TLSConfig{}
+	}
+	tcb.tlsConfig.MinVersion = minVersion
+	return tcb
+}
+
 func (tcb *TLSConfigBuilder) Build() *TLSConfig {
 	return tcb.tlsConfig
 }
@@ -0,0 +1,432 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// tlsReloadPeriod is how often the files of the tls profiles are checked for rotation
+var tlsReloadPeriod = 10 * time.Second
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var (
+	tlsProfilesLock sync.RWMutex
+	tlsProfiles     map[string]*tlsProfile
+)
+
+func init() {
+	// the files of the tls profiles are no longer watched once the application is shut down
+	extension.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		if event == extension.LifecycleAfterShutdown {
+			setTLSProfiles(nil)
+		}
+	}))
+}
+
+// tlsProfile is the tls profile configured in the tls-config section, the certificates are loaded once and reloaded
+// when their files are rotated, so the *tls.Config built once for the profile serves the current ones
+type tlsProfile struct {
+	name       string
+	conf       *TLSConfig
+	minVersion uint16
+
+	lock     sync.RWMutex
+	cert     *tls.Certificate
+	ca       *x509.CertPool
+	modTimes map[string]time.Time
+
+	serverOnce sync.Once
+	serverConf *tls.Config
+	clientOnce sync.Once
+	clientConf *tls.Config
+
+	done chan struct{}
+}
+
+// initTLSProfiles loads the tls profiles of @rc and checks the profiles referred by its components are defined
+func initTLSProfiles(rc *RootConfig) error {
+	names := make([]string, 0, len(rc.TLSProfiles))
+	for name := range rc.TLSProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	profiles := make(map[string]*tlsProfile, len(names))
+	for _, name := range names {
+		p, err := newTLSProfile(name, rc.TLSProfiles[name])
+		if err != nil {
+			stopTLSProfiles(profiles)
+			return err
+		}
+		profiles[name] = p
+	}
+	if err := checkTLSProfileRefs(rc, profiles); err != nil {
+		stopTLSProfiles(profiles)
+		return err
+	}
+	for _, p := range profiles {
+		go p.watch(tlsReloadPeriod)
+	}
+	setTLSProfiles(profiles)
+	return nil
+}
+
+func checkTLSProfileRefs(rc *RootConfig, profiles map[string]*tlsProfile) error {
+	check := func(profile, component string) error {
+		if profile == "" || profiles[profile] != nil {
+			return nil
+		}
+		return perrors.Errorf("the tls profile %s of %s is not defined in tls-config", profile, component)
+	}
+	for id, p := range rc.Protocols {
+		if err := check(p.TLSProfile, "the protocol "+id); err != nil {
+			return err
+		}
+	}
+	for id, r := range rc.Registries {
+		if err := check(r.TLSProfile, "the registry "+id); err != nil {
+			return err
+		}
+	}
+	if rc.ConfigCenter != nil {
+		if err := check(rc.ConfigCenter.TLSProfile, "the config center"); err != nil {
+			return err
+		}
+	}
+	if rc.MetadataReport != nil {
+		if err := check(rc.MetadataReport.TLSProfile, "the metadata report"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setTLSProfiles(profiles map[string]*tlsProfile) {
+	tlsProfilesLock.Lock()
+	old := tlsProfiles
+	tlsProfiles = profiles
+	tlsProfilesLock.Unlock()
+	stopTLSProfiles(old)
+}
+
+func stopTLSProfiles(profiles map[string]*tlsProfile) {
+	for _, p := range profiles {
+		p.stop()
+	}
+}
+
+func getTLSProfile(name string) (*tlsProfile, error) {
+	tlsProfilesLock.RLock()
+	defer tlsProfilesLock.RUnlock()
+	p, ok := tlsProfiles[name]
+	if !ok {
+		return nil, perrors.Errorf("the tls profile %s is not defined in tls-config", name)
+	}
+	return p, nil
+}
+
+// GetServerTLSProfile returns the server *tls.Config of the tls profile @name, which is built once and serves the
+// certificate and verifies the client certificates with the ca of the profile as they're rotated
+func GetServerTLSProfile(name string) (*tls.Config, error) {
+	p, err := getTLSProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.serverConfig()
+}
+
+// GetClientTLSProfile returns the client *tls.Config of the tls profile @name, which is built once and presents the
+// certificate and verifies the server certificates with the ca of the profile as they're rotated
+func GetClientTLSProfile(name string) (*tls.Config, error) {
+	p, err := getTLSProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.clientConfig(), nil
+}
+
+// GetTLSProfileConfig returns the files of the tls profile @name for the clients loading the certificates themselves
+func GetTLSProfileConfig(name string) (*TLSConfig, error) {
+	p, err := getTLSProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.conf, nil
+}
+
+// GetProtocolTLSProfile returns the tls profile of the protocol keyed or named @protocol, empty if it has none
+func GetProtocolTLSProfile(protocol string) string {
+	protocols := GetRootConfig().Protocols
+	if p, ok := protocols[protocol]; ok {
+		return p.TLSProfile
+	}
+	ids := make([]string, 0, len(protocols))
+	for id := range protocols {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if protocols[id].Name == protocol {
+			return protocols[id].TLSProfile
+		}
+	}
+	return ""
+}
+
+// setTLSProfileParams sets the files of the tls profile @name into @params like CredentialConfig.TLS
+func setTLSProfileParams(params url.Values, name string) {
+	if name == "" {
+		return
+	}
+	conf, err := GetTLSProfileConfig(name)
+	if err != nil {
+		logger.Warnf("tls profile: %v", err)
+		return
+	}
+	params.Set(constant.CACert, conf.CACertFile)
+	params.Set(constant.TLSCert, conf.TLSCertFile)
+	params.Set(constant.TLSKey, conf.TLSKeyFile)
+	params.Set(constant.TLSServerNAME, conf.TLSServerName)
+	params.Set(constant.TLSInsecureSkipVerify, strconv.FormatBool(conf.InsecureSkipVerify))
+}
+
+func newTLSProfile(name string, conf *TLSConfig) (*tlsProfile, error) {
+	if conf == nil {
+		return nil, perrors.Errorf("tls profile %s is empty", name)
+	}
+	if (conf.TLSCertFile == "") != (conf.TLSKeyFile == "") {
+		return nil, perrors.Errorf("tls profile %s requires both tls-cert-file and tls-key-file", name)
+	}
+	if conf.TLSCertFile == "" && conf.CACertFile == "" && !conf.InsecureSkipVerify {
+		return nil, perrors.Errorf("tls profile %s configures neither the certificate nor ca-cert-file", name)
+	}
+	p := &tlsProfile{name: name, conf: conf, done: make(chan struct{})}
+	if conf.MinVersion != "" {
+		v, ok := tlsVersions[conf.MinVersion]
+		if !ok {
+			return nil, perrors.Errorf("tls profile %s has the unknown min-version %s, expected one of 1.0, 1.1, 1.2 and 1.3",
+				name, conf.MinVersion)
+		}
+		p.minVersion = v
+	}
+	modTimes, err := p.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.load(modTimes); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *tlsProfile) files() []string {
+	var files []string
+	for _, f := range []string{p.conf.CACertFile, p.conf.TLSCertFile, p.conf.TLSKeyFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func (p *tlsProfile) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, f := range p.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, perrors.Wrapf(err, "tls profile %s", p.name)
+		}
+		modTimes[f] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// load reads the certificate and the ca of the profile, which are kept unless both are read successfully
+func (p *tlsProfile) load(modTimes map[string]time.Time) error {
+	var (
+		cert *tls.Certificate
+		ca   *x509.CertPool
+	)
+	if p.conf.TLSCertFile != "" {
+		c, err := tls.LoadX509KeyPair(p.conf.TLSCertFile, p.conf.TLSKeyFile)
+		if err != nil {
+			return perrors.Wrapf(err, "tls profile %s", p.name)
+		}
+		cert = &c
+	}
+	if p.conf.CACertFile != "" {
+		caBytes, err := ioutil.ReadFile(p.conf.CACertFile)
+		if err != nil {
+			return perrors.Wrapf(err, "tls profile %s", p.name)
+		}
+		ca = x509.NewCertPool()
+		if !ca.AppendCertsFromPEM(caBytes) {
+			return perrors.Errorf("tls profile %s: no certificate is found in %s", p.name, p.conf.CACertFile)
+		}
+	}
+	p.lock.Lock()
+	p.cert, p.ca, p.modTimes = cert, ca, modTimes
+	p.lock.Unlock()
+	return nil
+}
+
+// watch reloads the profile once its files are modified, which are checked every @period, until it's stopped
+func (p *tlsProfile) watch(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.reload()
+		}
+	}
+}
+
+func (p *tlsProfile) reload() {
+	modTimes, err := p.statFiles()
+	if err != nil {
+		logger.Warnf("tls profile %s is not reloaded: %v", p.name, err)
+		return
+	}
+	p.lock.RLock()
+	changed := false
+	for f, t := range modTimes {
+		if !t.Equal(p.modTimes[f]) {
+			changed = true
+		}
+	}
+	p.lock.RUnlock()
+	if !changed {
+		return
+	}
+	// the files may be partially rotated, so the profile is reloaded again at the next check if it fails
+	if err = p.load(modTimes); err != nil {
+		logger.Warnf("tls profile %s is not reloaded: %v", p.name, err)
+		return
+	}
+	logger.Infof("tls profile %s is reloaded", p.name)
+}
+
+func (p *tlsProfile) stop() {
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+}
+
+func (p *tlsProfile) current() (*tls.Certificate, *x509.CertPool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.cert, p.ca
+}
+
+func (p *tlsProfile) serverConfig() (*tls.Config, error) {
+	if p.conf.TLSCertFile == "" {
+		return nil, perrors.Errorf("tls profile %s has no certificate for the servers", p.name)
+	}
+	p.serverOnce.Do(func() {
+		p.serverConf = &tls.Config{
+			MinVersion: p.minVersion,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				cert, _ := p.current()
+				return cert, nil
+			},
+		}
+		if p.conf.CACertFile != "" {
+			// the client certificates are verified against the current ca rather than a fixed ClientCAs
+			p.serverConf.ClientAuth = tls.RequireAnyClientCert
+			p.serverConf.VerifyConnection = func(cs tls.ConnectionState) error {
+				return p.verify(cs, "", x509.ExtKeyUsageClientAuth)
+			}
+		}
+	})
+	return p.serverConf, nil
+}
+
+func (p *tlsProfile) clientConfig() *tls.Config {
+	p.clientOnce.Do(func() {
+		p.clientConf = &tls.Config{
+			MinVersion: p.minVersion,
+			ServerName: p.conf.TLSServerName,
+			// the server certificates are verified against the current ca by VerifyConnection instead
+			InsecureSkipVerify: true,
+		}
+		if p.conf.TLSCertFile != "" {
+			p.clientConf.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				cert, _ := p.current()
+				return cert, nil
+			}
+		}
+		if !p.conf.InsecureSkipVerify {
+			p.clientConf.VerifyConnection = func(cs tls.ConnectionState) error {
+				serverName := p.conf.TLSServerName
+				if serverName == "" {
+					serverName = cs.ServerName
+				}
+				return p.verify(cs, serverName, x509.ExtKeyUsageServerAuth)
+			}
+		}
+	})
+	return p.clientConf
+}
+
+// verify verifies the peer certificates of @cs against the current ca, or the system roots if the profile has none
+func (p *tlsProfile) verify(cs tls.ConnectionState, serverName string, usage x509.ExtKeyUsage) error {
+	if len(cs.PeerCertificates) == 0 {
+		return perrors.Errorf("tls profile %s: no peer certificate", p.name)
+	}
+	_, ca := p.current()
+	opts := x509.VerifyOptions{
+		Roots:         ca,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
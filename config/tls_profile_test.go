@@ -0,0 +1,266 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// testCA issues the certificates of localhost written into dir
+type testCA struct {
+	dir    string
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	serial int64
+}
+
+func newTestCA(t *testing.T) *testCA {
+	dir, err := ioutil.TempDir("", "tls-profile")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "dubbo-go test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	ca := &testCA{dir: dir, cert: cert, key: key}
+	ca.write(t, "ca.pem", "CERTIFICATE", der)
+	return ca
+}
+
+func (ca *testCA) write(t *testing.T, name, typ string, der []byte) {
+	path := filepath.Join(ca.dir, name)
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+	// the rotated files are modified later than the ones before for the coarse modification times
+	modTime := time.Now().Add(time.Duration(ca.serial) * time.Second)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// issue writes the certificate of localhost with @serial and its key as cert.pem and key.pem
+func (ca *testCA) issue(t *testing.T, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	ca.serial = serial
+	ca.write(t, "key.pem", "EC PRIVATE KEY", keyDer)
+	ca.write(t, "cert.pem", "CERTIFICATE", der)
+}
+
+func (ca *testCA) tlsConfig() *TLSConfig {
+	return NewTLSConfigBuilder().
+		SetCACertFile(filepath.Join(ca.dir, "ca.pem")).
+		SetTLSCertFile(filepath.Join(ca.dir, "cert.pem")).
+		SetTLSKeyFile(filepath.Join(ca.dir, "key.pem")).
+		SetTLSServerName("localhost").
+		SetMinVersion("1.2").
+		Build()
+}
+
+func TestTLSProfileReuse(t *testing.T) {
+	ca := newTestCA(t)
+	ca.issue(t, 2)
+	rc := NewRootConfigBuilder().
+		AddTLSProfile("internal", ca.tlsConfig()).
+		SetProtocols(map[string]*ProtocolConfig{
+			"dubbo": NewProtocolConfigBuilder().SetName("dubbo").SetTLSProfile("internal").Build(),
+			"tri":   NewProtocolConfigBuilder().SetName("tri").SetTLSProfile("internal").Build(),
+		}).
+		AddRegistry("nacos", &RegistryConfig{Protocol: "nacos", Address: "127.0.0.1:8848", TLSProfile: "internal"}).
+		Build()
+	require.NoError(t, initTLSProfiles(rc))
+	defer setTLSProfiles(nil)
+
+	dubboConf, err := GetServerTLSProfile(rc.Protocols["dubbo"].TLSProfile)
+	assert.Nil(t, err)
+	triConf, err := GetServerTLSProfile(rc.Protocols["tri"].TLSProfile)
+	assert.Nil(t, err)
+	assert.Same(t, dubboConf, triConf)
+	assert.Equal(t, uint16(tls.VersionTLS12), dubboConf.MinVersion)
+	assert.Equal(t, tls.RequireAnyClientCert, dubboConf.ClientAuth)
+	clientConf, err := GetClientTLSProfile("internal")
+	assert.Nil(t, err)
+	again, _ := GetClientTLSProfile("internal")
+	assert.Same(t, clientConf, again)
+	assert.Equal(t, "localhost", clientConf.ServerName)
+
+	params := rc.Registries["nacos"].getUrlMap(common.CONSUMER)
+	assert.Equal(t, filepath.Join(ca.dir, "ca.pem"), params.Get(constant.CACert))
+	assert.Equal(t, filepath.Join(ca.dir, "cert.pem"), params.Get(constant.TLSCert))
+	assert.Equal(t, filepath.Join(ca.dir, "key.pem"), params.Get(constant.TLSKey))
+	assert.Equal(t, "localhost", params.Get(constant.TLSServerNAME))
+}
+
+func TestTLSProfileRotation(t *testing.T) {
+	reloadPeriod := tlsReloadPeriod
+	tlsReloadPeriod = 10 * time.Millisecond
+	defer func() {
+		tlsReloadPeriod = reloadPeriod
+	}()
+	ca := newTestCA(t)
+	ca.issue(t, 2)
+	rc := NewRootConfigBuilder().AddTLSProfile("internal", ca.tlsConfig()).Build()
+	require.NoError(t, initTLSProfiles(rc))
+	defer setTLSProfiles(nil)
+
+	serverConf, err := GetServerTLSProfile("internal")
+	require.NoError(t, err)
+	clientConf, err := GetClientTLSProfile("internal")
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConf)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	// handshake returns the serial number of the server certificate, verified mutually with the current ca
+	handshake := func() int64 {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), clientConf)
+		if err != nil {
+			return 0
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(2), handshake())
+
+	ca.issue(t, 3)
+	assert.Eventually(t, func() bool {
+		return handshake() == 3
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// the certificates are kept if the rotated ones are broken
+	require.NoError(t, ioutil.WriteFile(filepath.Join(ca.dir, "cert.pem"), []byte("broken"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(3), handshake())
+
+	// the watch is stopped once the profiles are replaced
+	p, err := getTLSProfile("internal")
+	require.NoError(t, err)
+	setTLSProfiles(nil)
+	_, ok := <-p.done
+	assert.False(t, ok)
+}
+
+func TestTLSProfileMisconfigured(t *testing.T) {
+	ca := newTestCA(t)
+	ca.issue(t, 2)
+	tests := []struct {
+		name    string
+		profile *TLSConfig
+		refer   string
+		err     string
+	}{
+		{
+			name:    "missing file",
+			profile: NewTLSConfigBuilder().SetCACertFile(filepath.Join(ca.dir, "missing.pem")).Build(),
+			err:     "tls profile broken: stat",
+		},
+		{
+			name: "key without cert",
+			profile: NewTLSConfigBuilder().
+				SetCACertFile(filepath.Join(ca.dir, "ca.pem")).
+				SetTLSKeyFile(filepath.Join(ca.dir, "key.pem")).
+				Build(),
+			err: "tls profile broken requires both tls-cert-file and tls-key-file",
+		},
+		{
+			name:    "unknown min version",
+			profile: NewTLSConfigBuilder().SetInsecureSkipVerify(true).SetMinVersion("1.4").Build(),
+			err:     "tls profile broken has the unknown min-version 1.4",
+		},
+		{
+			name: "mismatched key",
+			profile: NewTLSConfigBuilder().
+				SetTLSCertFile(filepath.Join(ca.dir, "ca.pem")).
+				SetTLSKeyFile(filepath.Join(ca.dir, "key.pem")).
+				Build(),
+			err: "tls profile broken",
+		},
+		{
+			name:    "undefined profile",
+			profile: ca.tlsConfig(),
+			refer:   "internal",
+			err:     "the tls profile internal of the protocol dubbo is not defined in tls-config",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rc := NewRootConfigBuilder().
+				AddTLSProfile("broken", test.profile).
+				SetProtocols(map[string]*ProtocolConfig{
+					"dubbo": NewProtocolConfigBuilder().SetTLSProfile(test.refer).Build(),
+				}).
+				Build()
+			err := rc.Init()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+			_, err = GetServerTLSProfile("broken")
+			assert.Error(t, err)
+		})
+	}
+}
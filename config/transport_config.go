@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+const (
+	// MinTransportBufSize and MaxTransportBufSize are the range of the tcp buffer sizes of TransportConfig
+	MinTransportBufSize = 1024
+	MaxTransportBufSize = 64 * 1024 * 1024
+)
+
+// TransportConfig tunes the tcp sessions of the getty based protocols like dubbo, and enables their websocket
+// transport, e.g.
+//
+//	protocols:
+//	  dubbo:
+//	    transport:
+//	      tcp-r-buf-size: 1048576
+//	      keep-alive-period: 60s
+//
+// The absent fields keep the defaults of the protocol, which are listed below. The options are applied when a
+// session is created, so the connected sessions keep their options until they reconnect, and the changes of the
+// config take effect after the restart of the servers and the clients. Getty writes the packages of a session
+// synchronously, so there is no write queue to bound.
+type TransportConfig struct {
+	// TcpNoDelay disables the nagle's algorithm, true by default
+	TcpNoDelay *bool `yaml:"tcp-no-delay" json:"tcp-no-delay,omitempty" property:"tcp-no-delay"`
+	// TcpKeepAlive enables the tcp keepalive, true by default
+	TcpKeepAlive *bool `yaml:"tcp-keep-alive" json:"tcp-keep-alive,omitempty" property:"tcp-keep-alive"`
+	// KeepAlivePeriod is the interval of the tcp keepalive probes, at least 1s, 180s by default
+	KeepAlivePeriod string `yaml:"keep-alive-period" json:"keep-alive-period,omitempty" property:"keep-alive-period"`
+	// TcpRBufSize is the size of the receive buffer in bytes, in [1KiB, 64MiB], 256KiB by default
+	TcpRBufSize int `yaml:"tcp-r-buf-size" json:"tcp-r-buf-size,omitempty" property:"tcp-r-buf-size"`
+	// TcpWBufSize is the size of the send buffer in bytes, in [1KiB, 64MiB], 64KiB by default
+	TcpWBufSize int `yaml:"tcp-w-buf-size" json:"tcp-w-buf-size,omitempty" property:"tcp-w-buf-size"`
+	// TcpReadTimeout is the read timeout of the sessions, at least 1s, 1s by default
+	TcpReadTimeout string `yaml:"tcp-read-timeout" json:"tcp-read-timeout,omitempty" property:"tcp-read-timeout"`
+	// TcpWriteTimeout is the write timeout of the sessions, at least 1s, 5s by default
+	TcpWriteTimeout string `yaml:"tcp-write-timeout" json:"tcp-write-timeout,omitempty" property:"tcp-write-timeout"`
+	// WebsocketPort is the port the servers also listen for the websocket upgrades on, so that the providers are
+	// reachable through the http ingresses. The providers are registered with transport=websocket, and the websocket
+	// is disabled if it is empty. It is wss if the tls config is set.
+	WebsocketPort string `yaml:"websocket-port" json:"websocket-port,omitempty" property:"websocket-port"`
+	// WebsocketPath is the http path of the websocket upgrades, /dubbo by default
+	WebsocketPath string `yaml:"websocket-path" json:"websocket-path,omitempty" property:"websocket-path"`
+	// Websocket makes the clients connect the dubbo providers by websocket, so that only the dubbo providers
+	// registered with transport=websocket are selected. The websocket is only supported by the dubbo protocol.
+	Websocket bool `yaml:"websocket" json:"websocket,omitempty" property:"websocket"`
+}
+
+// GetClientTransport returns the transport of the clients of the protocol @protocolConf, which is the transport of
+// the consumer if it is set, or the transport of the protocol
+func GetClientTransport(protocolConf *ProtocolConfig) *TransportConfig {
+	if rc := GetRootConfig(); rc != nil && rc.Consumer != nil && rc.Consumer.Transport != nil {
+		return rc.Consumer.Transport
+	}
+	if protocolConf != nil {
+		return protocolConf.Transport
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// pollTimeout is the timeout of a long poll, which is held by apollo for 60s at most
+	pollTimeout = 90 * time.Second
+	// initialNotificationID is the notification id of the namespaces never notified, which are notified at once
+	initialNotificationID = -1
+)
+
+// errNamespaceNotFound is returned if the namespace isn't released in the cluster of the app
+var errNamespaceNotFound = perrors.New("the namespace is not found")
+
+// apolloConfig is the release of a namespace, which is also the content of the local cache file of the namespace
+type apolloConfig struct {
+	AppID          string            `json:"appId"`
+	Cluster        string            `json:"cluster"`
+	NamespaceName  string            `json:"namespaceName"`
+	ReleaseKey     string            `json:"releaseKey"`
+	Configurations map[string]string `json:"configurations"`
+}
+
+// notification is the notification id of a namespace, which grows with the releases of the namespace
+type notification struct {
+	NamespaceName  string `json:"namespaceName"`
+	NotificationID int64  `json:"notificationId"`
+}
+
+// apolloClient calls the config services of apollo, the next one is called if a config service is unreachable
+type apolloClient struct {
+	servers []string
+	// current is the index of the config service called
+	current *atomic.Uint32
+	appID   string
+	cluster string
+	secret  string
+	timeout time.Duration
+	client  *http.Client
+}
+
+func newApolloClient(addresses []string, appID, cluster, secret string, timeout time.Duration) *apolloClient {
+	servers := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if address = strings.TrimSpace(address); len(address) == 0 {
+			continue
+		}
+		if !strings.Contains(address, "://") {
+			address = "http://" + address
+		}
+		servers = append(servers, strings.TrimRight(address, "/"))
+	}
+	return &apolloClient{
+		servers: servers,
+		current: atomic.NewUint32(0),
+		appID:   appID,
+		cluster: cluster,
+		secret:  secret,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// getConfig returns the release of @namespace, or nil if it is still the release of @releaseKey
+func (c *apolloClient) getConfig(ctx context.Context, namespace, releaseKey string) (*apolloConfig, error) {
+	path := "/configs/" + url.PathEscape(c.appID) + "/" + url.PathEscape(c.cluster) + "/" + url.PathEscape(namespace)
+	query := url.Values{}
+	if len(releaseKey) > 0 {
+		query.Set("releaseKey", releaseKey)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	config := &apolloConfig{}
+	switch status, err := c.get(ctx, path, query, config); {
+	case err != nil:
+		return nil, perrors.WithMessagef(err, "get the namespace %s", namespace)
+	case status == http.StatusNotModified:
+		return nil, nil
+	case status == http.StatusNotFound:
+		return nil, perrors.WithMessagef(errNamespaceNotFound, "get the namespace %s", namespace)
+	}
+	return config, nil
+}
+
+// poll waits for the releases of the namespaces after @notifications, and returns the notifications of the
+// namespaces released, which are empty if nothing is released before apollo ends the poll
+func (c *apolloClient) poll(ctx context.Context, notifications []notification) ([]notification, error) {
+	body, err := json.Marshal(notifications)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	query := url.Values{}
+	query.Set("appId", c.appID)
+	query.Set("cluster", c.cluster)
+	query.Set("notifications", string(body))
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+	var released []notification
+	if _, err = c.get(ctx, "/notifications/v2", query, &released); err != nil {
+		return nil, perrors.WithMessage(err, "poll the notifications")
+	}
+	return released, nil
+}
+
+// get decodes the response of @path into @result if it is ok, and returns the status of the response. The next
+// config service is called if the current one is unreachable.
+func (c *apolloClient) get(ctx context.Context, path string, query url.Values, result interface{}) (int, error) {
+	if len(c.servers) == 0 {
+		return 0, perrors.New("no apollo config service")
+	}
+	pathWithQuery := path
+	if len(query) > 0 {
+		pathWithQuery += "?" + query.Encode()
+	}
+	var err error
+	for i := 0; i < len(c.servers); i++ {
+		current := c.current.Load()
+		server := c.servers[int(current)%len(c.servers)]
+		var status int
+		if status, err = c.getFrom(ctx, server, pathWithQuery, result); err == nil {
+			return status, nil
+		}
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		c.current.CAS(current, current+1)
+	}
+	return 0, err
+}
+
+func (c *apolloClient) getFrom(ctx context.Context, server, pathWithQuery string, result interface{}) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, server+pathWithQuery, nil)
+	if err != nil {
+		return 0, perrors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	if len(c.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+		req.Header.Set("Authorization", "Apollo "+c.appID+":"+signature(timestamp, pathWithQuery, c.secret))
+		req.Header.Set("Timestamp", timestamp)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, perrors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return 0, perrors.WithMessagef(err, "decode the response of %s", server)
+		}
+		return resp.StatusCode, nil
+	case http.StatusNotModified, http.StatusNotFound:
+		return resp.StatusCode, nil
+	}
+	return 0, perrors.Errorf("the config service %s responds %s", server, resp.Status)
+}
+
+// signature signs the request of @pathWithQuery at @timestamp by the access key @secret of the app as apollo does
+func signature(timestamp, pathWithQuery, secret string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + pathWithQuery))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
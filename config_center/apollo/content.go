@@ -0,0 +1,233 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/magiconair/properties"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// contentKey is the key of the content of the namespaces not in the properties format
+const contentKey = "content"
+
+// format is the format of a namespace, which is told by the suffix of the namespace name as apollo does
+type format int
+
+const (
+	formatProperties format = iota
+	formatYAML
+	// formatText is json, xml and txt, whose content is not merged into the properties
+	formatText
+)
+
+func formatOf(namespace string) format {
+	switch strings.ToLower(filepath.Ext(namespace)) {
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".json", ".xml", ".txt":
+		return formatText
+	}
+	return formatProperties
+}
+
+// normalizeNamespace trims the suffix .properties, which apollo omits in the names of the properties namespaces
+func normalizeNamespace(namespace string) string {
+	namespace = strings.TrimSpace(namespace)
+	if strings.EqualFold(filepath.Ext(namespace), ".properties") {
+		return namespace[:len(namespace)-len(".properties")]
+	}
+	return namespace
+}
+
+// namespaceConfig is the release of a namespace last seen
+type namespaceConfig struct {
+	namespace  string
+	releaseKey string
+	// notificationID is the notification of the release, initialNotificationID if it is not notified yet
+	notificationID int64
+	// content is the properties format content of the properties namespaces, or the content of the others
+	content string
+	// properties are merged into the properties of the namespaces subscribed, the yaml content is flattened by the
+	// paths of the keys, e.g. a.b[0]
+	properties map[string]string
+}
+
+func newNamespaceConfig(namespace string, config *apolloConfig) *namespaceConfig {
+	nc := &namespaceConfig{namespace: namespace, releaseKey: config.ReleaseKey, notificationID: initialNotificationID}
+	var err error
+	switch formatOf(namespace) {
+	case formatProperties:
+		nc.properties = config.Configurations
+		nc.content, err = propertiesContent(config.Configurations)
+	case formatYAML:
+		nc.content = config.Configurations[contentKey]
+		nc.properties, err = flattenYAML(nc.content)
+	default:
+		nc.content = config.Configurations[contentKey]
+	}
+	if err != nil {
+		logger.Warnf("[Apollo ConfigCenter] Parse the namespace %s error, %v, its properties are ignored", namespace, err)
+	}
+	if nc.properties == nil {
+		nc.properties = map[string]string{}
+	}
+	return nc
+}
+
+// diffProperties returns the changes of the properties of @namespace from @oldProperties to @properties in the order
+// of the keys
+func diffProperties(namespace string, oldProperties, properties map[string]string) []PropertyChange {
+	var changes []PropertyChange
+	for key, value := range properties {
+		oldValue, ok := oldProperties[key]
+		switch {
+		case !ok:
+			changes = append(changes, PropertyChange{Key: key, Value: value, ChangeType: remoting.EventTypeAdd,
+				Namespace: namespace})
+		case oldValue != value:
+			changes = append(changes, PropertyChange{Key: key, Value: value, OldValue: oldValue,
+				ChangeType: remoting.EventTypeUpdate, Namespace: namespace, OldNamespace: namespace})
+		}
+	}
+	for key, oldValue := range oldProperties {
+		if _, ok := properties[key]; !ok {
+			changes = append(changes, PropertyChange{Key: key, OldValue: oldValue, ChangeType: remoting.EventTypeDel,
+				OldNamespace: namespace})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// propertiesContent returns the properties format content of @configurations in the order of the keys
+func propertiesContent(configurations map[string]string) (string, error) {
+	keys := make([]string, 0, len(configurations))
+	for key := range configurations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	p := properties.NewProperties()
+	p.DisableExpansion = true
+	for _, key := range keys {
+		if _, _, err := p.Set(key, configurations[key]); err != nil {
+			return "", perrors.WithStack(err)
+		}
+	}
+	var content strings.Builder
+	if _, err := p.Write(&content, properties.UTF8); err != nil {
+		return "", perrors.WithStack(err)
+	}
+	return content.String(), nil
+}
+
+// flattenYAML returns the properties of the yaml @content keyed by the paths, e.g. a.b[0]
+func flattenYAML(content string) (map[string]string, error) {
+	var root interface{}
+	if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	flattened := make(map[string]string)
+	switch root.(type) {
+	case nil:
+		return flattened, nil
+	case map[interface{}]interface{}:
+		flatten("", root, flattened)
+		return flattened, nil
+	}
+	return nil, perrors.New("the yaml content is not a map")
+}
+
+func flatten(path string, value interface{}, flattened map[string]string) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			childPath := fmt.Sprint(key)
+			if len(path) > 0 {
+				childPath = path + "." + childPath
+			}
+			flatten(childPath, child, flattened)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(path+"["+strconv.Itoa(i)+"]", child, flattened)
+		}
+	case nil:
+		flattened[path] = ""
+	default:
+		flattened[path] = fmt.Sprint(v)
+	}
+}
+
+// cacheFile returns the local cache file of @namespace in @dir, which is named as the one of agollo
+func cacheFile(dir, appID, namespace string) string {
+	return filepath.Join(dir, appID+"-"+namespace+".json")
+}
+
+// saveCache saves @config into the local cache file in @dir, a file written partially is never read
+func saveCache(dir string, config *apolloConfig) error {
+	content, err := json.Marshal(config)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	if len(dir) > 0 {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+	file := cacheFile(dir, config.AppID, config.NamespaceName)
+	tmp := file + ".tmp"
+	if err = ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return perrors.WithStack(err)
+	}
+	return perrors.WithStack(os.Rename(tmp, file))
+}
+
+// readCache reads the release of @namespace from the local cache file in @dir
+func readCache(dir, appID, namespace string) (*apolloConfig, error) {
+	content, err := ioutil.ReadFile(cacheFile(dir, appID, namespace))
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	config := &apolloConfig{}
+	if err = json.Unmarshal(content, config); err != nil {
+		return nil, perrors.WithMessagef(err, "parse the local cache file of the namespace %s", namespace)
+	}
+	return config, nil
+}
@@ -0,0 +1,527 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	metricsConfigCenter "dubbo.apache.org/dubbo-go/v3/metrics/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	defaultAppID     = "dubbo"
+	defaultCluster   = "default"
+	defaultNamespace = "application"
+	// minPollBackoff is the backoff of the first poll again after the poll fails, which doubles after each failure
+	minPollBackoff = time.Second
+	maxPollBackoff = 2 * time.Minute
+)
+
+// ErrUnsupportedOperation is returned by PublishConfig and RemoveConfig, the configs are published by the portal
+var ErrUnsupportedOperation = perrors.New("the configs of apollo are published by the portal")
+
+// PropertyChange is the change of a property with the namespaces holding its values
+type PropertyChange struct {
+	Key      string
+	Value    string
+	OldValue string
+	// ChangeType is EventTypeAdd, EventTypeUpdate or EventTypeDel
+	ChangeType remoting.EventType
+	// Namespace is the namespace holding Value, empty if the property is deleted
+	Namespace string
+	// OldNamespace is the namespace holding OldValue, empty if the property is added
+	OldNamespace string
+}
+
+// PropertyChangeListener is a listener receiving the changes of the properties with their namespaces instead of the
+// whole content. The listeners of a namespace receive the changes of its properties, the yaml namespaces are changed
+// by the flattened properties, e.g. a.b[0]. The listeners of a property receive the changes of the value taking
+// precedence, e.g. the value falls back to a later namespace once it is removed from an earlier one.
+type PropertyChangeListener interface {
+	config_center.ConfigurationListener
+	// ProcessPropertyChanges processes the @changes of the properties in the order of the keys
+	ProcessPropertyChanges(changes []PropertyChange)
+}
+
+type apolloDynamicConfiguration struct {
+	config_center.BaseDynamicConfiguration
+	url    *common.URL
+	client *apolloClient
+	parser parser.ConfigurationParser
+	// namespaces are the namespaces subscribed, the earlier ones take precedence over the later ones
+	namespaces []string
+	// cacheDir is the directory of the local cache files, which are not used unless cacheEnabled
+	cacheDir     string
+	cacheEnabled bool
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock sync.RWMutex
+	// configs are the releases of the namespaces subscribed or read, which are polled
+	configs map[string]*namespaceConfig
+	// namespaceListeners are the listeners of the namespaces, keyed by the namespaces
+	namespaceListeners map[string]map[config_center.ConfigurationListener]struct{}
+	// propertyListeners are the listeners of the properties of the namespaces subscribed, keyed by the properties
+	propertyListeners map[string]map[config_center.ConfigurationListener]struct{}
+}
+
+func newApolloDynamicConfiguration(url *common.URL) (*apolloDynamicConfiguration, error) {
+	c := newConfiguration(url)
+	logger.Infof("[Apollo ConfigCenter] New Apollo ConfigCenter with address %s, app %s, cluster %s, namespaces %v",
+		url.Location, c.client.appID, c.client.cluster, c.namespaces)
+	if err := c.start(); err != nil {
+		c.cancel()
+		return nil, err
+	}
+	return c, nil
+}
+
+func newConfiguration(url *common.URL) *apolloDynamicConfiguration {
+	ctx, cancel := context.WithCancel(context.Background())
+	timeout := url.GetParamDurationWithDefault(constant.ConfigTimeoutKey, config_center.DefaultConfigTimeout)
+	return &apolloDynamicConfiguration{
+		url: url,
+		client: newApolloClient(strings.Split(url.Location, ","), url.GetParam(constant.ConfigAppIDKey, defaultAppID),
+			url.GetParam(constant.ConfigClusterKey, defaultCluster), url.GetParam(constant.ConfigSecretKey, ""), timeout),
+		namespaces:         parseNamespaces(url.GetParam(constant.ConfigNamespaceKey, "")),
+		cacheDir:           url.GetParam(constant.ConfigBackupConfigPathKey, ""),
+		cacheEnabled:       url.GetParamBool(constant.ConfigBackupConfigKey, true),
+		minBackoff:         minPollBackoff,
+		maxBackoff:         maxPollBackoff,
+		ctx:                ctx,
+		cancel:             cancel,
+		configs:            make(map[string]*namespaceConfig),
+		namespaceListeners: make(map[string]map[config_center.ConfigurationListener]struct{}),
+		propertyListeners:  make(map[string]map[config_center.ConfigurationListener]struct{}),
+	}
+}
+
+// parseNamespaces returns the comma separated @namespaces without the duplicated ones, application if it is empty
+func parseNamespaces(namespaces string) []string {
+	var parsed []string
+	seen := make(map[string]struct{})
+	for _, namespace := range strings.Split(namespaces, ",") {
+		namespace = normalizeNamespace(namespace)
+		if _, ok := seen[namespace]; ok || len(namespace) == 0 {
+			continue
+		}
+		seen[namespace] = struct{}{}
+		parsed = append(parsed, namespace)
+	}
+	if len(parsed) == 0 {
+		return []string{defaultNamespace}
+	}
+	return parsed
+}
+
+// start loads the namespaces subscribed and polls their releases
+func (c *apolloDynamicConfiguration) start() error {
+	for _, namespace := range c.namespaces {
+		if _, err := c.load(namespace); err != nil {
+			return err
+		}
+	}
+	go c.poll()
+	return nil
+}
+
+// load returns the release of @namespace, which is got from apollo or the local cache file if apollo is unreachable
+// at the first time, and polled since then
+func (c *apolloDynamicConfiguration) load(namespace string) (*namespaceConfig, error) {
+	c.lock.RLock()
+	nc, ok := c.configs[namespace]
+	c.lock.RUnlock()
+	if ok {
+		return nc, nil
+	}
+	config, err := c.client.getConfig(c.ctx, namespace, "")
+	switch {
+	case err == nil:
+		c.saveCache(config)
+	case perrors.Is(err, errNamespaceNotFound):
+		logger.Warnf("[Apollo ConfigCenter] The namespace %s is not released, it is empty until released", namespace)
+		config = c.emptyConfig(namespace)
+	case !c.cacheEnabled:
+		return nil, err
+	default:
+		cached, cacheErr := readCache(c.cacheDir, c.client.appID, namespace)
+		if cacheErr != nil {
+			return nil, perrors.WithMessagef(err, "the local cache file is not read either, %v", cacheErr)
+		}
+		logger.Warnf("[Apollo ConfigCenter] Get the namespace %s error, %v, the local cache file is used until "+
+			"apollo is reachable", namespace, err)
+		config = cached
+	}
+	nc = newNamespaceConfig(namespace, config)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if loaded, ok := c.configs[namespace]; ok {
+		return loaded, nil
+	}
+	c.configs[namespace] = nc
+	return nc, nil
+}
+
+// poll polls the releases of the namespaces until the config center is destroyed, the poll is retried with the
+// backoff doubling up to maxBackoff while apollo is unreachable
+func (c *apolloDynamicConfiguration) poll() {
+	backoff := c.minBackoff
+	failed := false
+	for {
+		err := c.pollOnce()
+		if c.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			if failed {
+				logger.Infof("[Apollo ConfigCenter] Apollo is reachable again")
+				failed = false
+			}
+			backoff = c.minBackoff
+			continue
+		}
+		failed = true
+		logger.Warnf("[Apollo ConfigCenter] Poll the namespaces error, %v, poll them again in %s", err, backoff)
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// pollOnce waits for the releases of the namespaces and delivers them
+func (c *apolloDynamicConfiguration) pollOnce() error {
+	c.lock.RLock()
+	notifications := make([]notification, 0, len(c.configs))
+	for namespace, nc := range c.configs {
+		notifications = append(notifications, notification{NamespaceName: namespace, NotificationID: nc.notificationID})
+	}
+	c.lock.RUnlock()
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].NamespaceName < notifications[j].NamespaceName
+	})
+	released, err := c.client.poll(c.ctx, notifications)
+	if err != nil {
+		return err
+	}
+	for _, n := range released {
+		if err = c.refresh(normalizeNamespace(n.NamespaceName), n.NotificationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refresh gets the release of @namespace notified by @notificationID, and delivers the changes to the listeners
+func (c *apolloDynamicConfiguration) refresh(namespace string, notificationID int64) error {
+	c.lock.RLock()
+	old, ok := c.configs[namespace]
+	c.lock.RUnlock()
+	if !ok {
+		return nil
+	}
+	config, err := c.client.getConfig(c.ctx, namespace, old.releaseKey)
+	switch {
+	case err == nil && config == nil:
+		// the release is not modified, e.g. the one of the local cache file
+		notified := *old
+		notified.notificationID = notificationID
+		c.lock.Lock()
+		c.configs[namespace] = &notified
+		c.lock.Unlock()
+		return nil
+	case err == nil:
+		c.saveCache(config)
+	case perrors.Is(err, errNamespaceNotFound):
+		config = c.emptyConfig(namespace)
+	default:
+		return err
+	}
+	nc := newNamespaceConfig(namespace, config)
+	nc.notificationID = notificationID
+
+	c.lock.Lock()
+	oldProperties := c.propertiesLocked()
+	c.configs[namespace] = nc
+	events := c.propertyEventsLocked(oldProperties)
+	namespaceListeners := make([]config_center.ConfigurationListener, 0, len(c.namespaceListeners[namespace]))
+	for listener := range c.namespaceListeners[namespace] {
+		namespaceListeners = append(namespaceListeners, listener)
+	}
+	c.lock.Unlock()
+
+	c.notify(old, nc, namespaceListeners)
+	for _, event := range events {
+		event.deliver()
+	}
+	return nil
+}
+
+// notify delivers the change of a namespace from @old to @nc to its @listeners
+func (c *apolloDynamicConfiguration) notify(old, nc *namespaceConfig, listeners []config_center.ConfigurationListener) {
+	event := &config_center.ConfigChangeEvent{Key: nc.namespace, Value: nc.content, ConfigType: remoting.EventTypeUpdate}
+	switch {
+	case len(old.releaseKey) == 0 && len(nc.releaseKey) == 0:
+		return
+	case len(old.releaseKey) == 0:
+		event.ConfigType = remoting.EventTypeAdd
+	case len(nc.releaseKey) == 0:
+		event.ConfigType = remoting.EventTypeDel
+		event.OldValue = old.content
+	default:
+		event.OldValue = old.content
+	}
+	metrics.Publish(metricsConfigCenter.NewIncMetricEvent(nc.namespace, c.client.cluster, event.ConfigType,
+		metricsConfigCenter.Apollo))
+	changes := diffProperties(nc.namespace, old.properties, nc.properties)
+	for _, listener := range listeners {
+		if propertyListener, ok := listener.(PropertyChangeListener); ok {
+			if len(changes) > 0 {
+				propertyListener.ProcessPropertyChanges(changes)
+			}
+			continue
+		}
+		listener.Process(event)
+	}
+}
+
+// propertyValue is the value of a property taking precedence and the namespace holding it
+type propertyValue struct {
+	value     string
+	namespace string
+}
+
+// propertyEvent is the change of a property delivered to its listeners
+type propertyEvent struct {
+	change    PropertyChange
+	listeners []config_center.ConfigurationListener
+}
+
+// deliver delivers the change to the listeners, the ones not PropertyChangeListener skip the change of the namespace
+// only
+func (e *propertyEvent) deliver() {
+	for _, listener := range e.listeners {
+		if propertyListener, ok := listener.(PropertyChangeListener); ok {
+			propertyListener.ProcessPropertyChanges([]PropertyChange{e.change})
+			continue
+		}
+		if e.change.ChangeType == remoting.EventTypeUpdate && e.change.Value == e.change.OldValue {
+			continue
+		}
+		event := &config_center.ConfigChangeEvent{Key: e.change.Key, Value: e.change.Value, ConfigType: e.change.ChangeType}
+		if e.change.ChangeType != remoting.EventTypeAdd {
+			event.OldValue = e.change.OldValue
+		}
+		listener.Process(event)
+	}
+}
+
+// propertiesLocked returns the properties listened, whose values are the ones of the namespaces subscribed first
+func (c *apolloDynamicConfiguration) propertiesLocked() map[string]propertyValue {
+	properties := make(map[string]propertyValue, len(c.propertyListeners))
+	for key := range c.propertyListeners {
+		if value, namespace, ok := c.propertyLocked(key); ok {
+			properties[key] = propertyValue{value: value, namespace: namespace}
+		}
+	}
+	return properties
+}
+
+// propertyEventsLocked returns the changes of the properties listened from @oldProperties, including the ones whose
+// values are the same but held by the other namespaces
+func (c *apolloDynamicConfiguration) propertyEventsLocked(oldProperties map[string]propertyValue) []*propertyEvent {
+	var events []*propertyEvent
+	for key, listeners := range c.propertyListeners {
+		old, existed := oldProperties[key]
+		value, namespace, exists := c.propertyLocked(key)
+		change := PropertyChange{Key: key, Value: value, Namespace: namespace}
+		switch {
+		case existed && exists && (old.value != value || old.namespace != namespace):
+			change.OldValue, change.OldNamespace = old.value, old.namespace
+			change.ChangeType = remoting.EventTypeUpdate
+		case existed && !exists:
+			change.OldValue, change.OldNamespace = old.value, old.namespace
+			change.ChangeType = remoting.EventTypeDel
+		case !existed && exists:
+			change.ChangeType = remoting.EventTypeAdd
+		default:
+			continue
+		}
+		event := &propertyEvent{change: change, listeners: make([]config_center.ConfigurationListener, 0, len(listeners))}
+		for listener := range listeners {
+			event.listeners = append(event.listeners, listener)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// propertyLocked returns the property of @key in the first namespace subscribed holding it, and the namespace
+func (c *apolloDynamicConfiguration) propertyLocked(key string) (string, string, bool) {
+	for _, namespace := range c.namespaces {
+		if nc, ok := c.configs[namespace]; ok {
+			if value, ok := nc.properties[key]; ok {
+				return value, namespace, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func (c *apolloDynamicConfiguration) emptyConfig(namespace string) *apolloConfig {
+	return &apolloConfig{AppID: c.client.appID, Cluster: c.client.cluster, NamespaceName: namespace}
+}
+
+func (c *apolloDynamicConfiguration) saveCache(config *apolloConfig) {
+	if !c.cacheEnabled {
+		return
+	}
+	if err := saveCache(c.cacheDir, config); err != nil {
+		logger.Warnf("[Apollo ConfigCenter] Save the local cache file of the namespace %s error, %v",
+			config.NamespaceName, err)
+	}
+}
+
+// AddListener listens to @key, which is a namespace if it is subscribed or read by GetProperties, or a property of
+// the namespaces subscribed otherwise. The listeners of a namespace receive its whole content and the listeners of a
+// property receive its value taking precedence, or the changes of the properties if they are PropertyChangeListener.
+func (c *apolloDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	listeners := c.propertyListeners
+	if namespace := normalizeNamespace(key); c.configs[namespace] != nil {
+		key, listeners = namespace, c.namespaceListeners
+	}
+	if _, ok := listeners[key]; !ok {
+		listeners[key] = make(map[config_center.ConfigurationListener]struct{})
+	}
+	listeners[key][listener] = struct{}{}
+}
+
+// RemoveListener removes @listener of @key
+func (c *apolloDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, listeners := range []map[string]map[config_center.ConfigurationListener]struct{}{
+		c.namespaceListeners, c.propertyListeners} {
+		for _, k := range []string{key, normalizeNamespace(key)} {
+			if _, ok := listeners[k]; !ok {
+				continue
+			}
+			delete(listeners[k], listener)
+			if len(listeners[k]) == 0 {
+				delete(listeners, k)
+			}
+		}
+	}
+}
+
+// GetProperties returns the content of the namespace @key, the properties namespaces are in the properties format
+func (c *apolloDynamicConfiguration) GetProperties(key string, _ ...config_center.Option) (string, error) {
+	nc, err := c.load(normalizeNamespace(key))
+	if err != nil {
+		return "", err
+	}
+	return nc.content, nil
+}
+
+// GetInternalProperty returns the property of @key in the first namespace subscribed holding it, empty if it is
+// absent
+func (c *apolloDynamicConfiguration) GetInternalProperty(key string, _ ...config_center.Option) (string, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	value, _, _ := c.propertyLocked(key)
+	return value, nil
+}
+
+// GetRule returns the rule of @key, which is a property of the namespaces subscribed
+func (c *apolloDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
+	return c.GetInternalProperty(key, opts...)
+}
+
+// PublishConfig returns ErrUnsupportedOperation
+func (c *apolloDynamicConfiguration) PublishConfig(string, string, string) error {
+	return ErrUnsupportedOperation
+}
+
+// RemoveConfig returns ErrUnsupportedOperation
+func (c *apolloDynamicConfiguration) RemoveConfig(string, string) error {
+	return ErrUnsupportedOperation
+}
+
+// GetConfigKeysByGroup returns the properties of the namespaces subscribed, apollo has no groups
+func (c *apolloDynamicConfiguration) GetConfigKeysByGroup(string) (*gxset.HashSet, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	set := gxset.NewSet()
+	for _, namespace := range c.namespaces {
+		if nc, ok := c.configs[namespace]; ok {
+			for key := range nc.properties {
+				set.Add(key)
+			}
+		}
+	}
+	return set, nil
+}
+
+func (c *apolloDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return c.parser
+}
+
+func (c *apolloDynamicConfiguration) SetParser(p parser.ConfigurationParser) {
+	c.parser = p
+}
+
+func (c *apolloDynamicConfiguration) GetURL() *common.URL {
+	return c.url
+}
+
+func (c *apolloDynamicConfiguration) IsAvailable() bool {
+	return c.ctx.Err() == nil
+}
+
+// Destroy stops polling the namespaces
+func (c *apolloDynamicConfiguration) Destroy() {
+	c.cancel()
+}
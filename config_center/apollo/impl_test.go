@@ -0,0 +1,418 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// fakeApollo is an in memory config service of apollo, whose polls are held for 100ms at most
+type fakeApollo struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	releases map[string]*apolloConfig
+	// notificationIDs are the notification ids of the namespaces, which grow with the releases
+	notificationIDs map[string]int64
+	released        chan struct{}
+	down            bool
+	secret          string
+	polls           int
+}
+
+func newFakeApollo() *fakeApollo {
+	a := &fakeApollo{
+		releases:        make(map[string]*apolloConfig),
+		notificationIDs: make(map[string]int64),
+		released:        make(chan struct{}),
+	}
+	a.Server = httptest.NewServer(http.HandlerFunc(a.serve))
+	return a
+}
+
+// release releases @configurations of @namespace
+func (a *fakeApollo) release(namespace string, configurations map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.notificationIDs[namespace]++
+	a.releases[namespace] = &apolloConfig{AppID: "demo", Cluster: defaultCluster, NamespaceName: namespace,
+		ReleaseKey: namespace + "-" + strconv.FormatInt(a.notificationIDs[namespace], 10), Configurations: configurations}
+	close(a.released)
+	a.released = make(chan struct{})
+}
+
+func (a *fakeApollo) setDown(down bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.down = down
+}
+
+func (a *fakeApollo) pollCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.polls
+}
+
+func (a *fakeApollo) serve(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	down, secret := a.down, a.secret
+	if strings.HasPrefix(r.URL.Path, "/notifications/") {
+		a.polls++
+	}
+	a.mu.Unlock()
+	if down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if len(secret) > 0 && r.Header.Get("Authorization") !=
+		"Apollo demo:"+signature(r.Header.Get("Timestamp"), r.URL.RequestURI(), secret) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path == "/notifications/v2" {
+		a.poll(w, r)
+		return
+	}
+	parts := strings.Split(r.URL.Path, "/")
+	a.mu.Lock()
+	config, ok := a.releases[parts[len(parts)-1]]
+	a.mu.Unlock()
+	switch {
+	case !ok:
+		w.WriteHeader(http.StatusNotFound)
+	case config.ReleaseKey == r.URL.Query().Get("releaseKey"):
+		w.WriteHeader(http.StatusNotModified)
+	default:
+		_ = json.NewEncoder(w).Encode(config)
+	}
+}
+
+func (a *fakeApollo) poll(w http.ResponseWriter, r *http.Request) {
+	var notifications []notification
+	if err := json.Unmarshal([]byte(r.URL.Query().Get("notifications")), &notifications); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	timeout := time.After(100 * time.Millisecond)
+	for {
+		a.mu.Lock()
+		var released []notification
+		for _, n := range notifications {
+			if id := a.notificationIDs[n.NamespaceName]; id > n.NotificationID {
+				released = append(released, notification{NamespaceName: n.NamespaceName, NotificationID: id})
+			}
+		}
+		ch := a.released
+		a.mu.Unlock()
+		if len(released) > 0 {
+			_ = json.NewEncoder(w).Encode(released)
+			return
+		}
+		select {
+		case <-ch:
+		case <-timeout:
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+}
+
+func newApolloTestURL(t *testing.T, address, namespaces string, opts ...common.Option) *common.URL {
+	opts = append(opts, common.WithParamsValue(constant.ConfigAppIDKey, "demo"),
+		common.WithParamsValue(constant.ConfigNamespaceKey, namespaces),
+		common.WithParamsValue(constant.ConfigTimeoutKey, "1s"))
+	url, err := common.NewURL("apollo://"+strings.TrimPrefix(address, "http://"), opts...)
+	assert.NoError(t, err)
+	return url
+}
+
+// testListener records the events of a namespace or a property
+type testListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func newTestListener() *testListener {
+	return &testListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+}
+
+func (l *testListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *testListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("no change is delivered")
+		return nil
+	}
+}
+
+// testPropertyListener records the changes of the properties of a namespace or a property
+type testPropertyListener struct {
+	testListener
+	changes chan []PropertyChange
+}
+
+func newTestPropertyListener() *testPropertyListener {
+	return &testPropertyListener{testListener: *newTestListener(), changes: make(chan []PropertyChange, 16)}
+}
+
+func (l *testPropertyListener) ProcessPropertyChanges(changes []PropertyChange) {
+	l.changes <- changes
+}
+
+func (l *testPropertyListener) next(t *testing.T) []PropertyChange {
+	select {
+	case changes := <-l.changes:
+		return changes
+	case <-time.After(3 * time.Second):
+		t.Fatal("no change is delivered")
+		return nil
+	}
+}
+
+func TestApolloNamespaces(t *testing.T) {
+	apollo := newFakeApollo()
+	defer apollo.Close()
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	apollo.release("demo.dubbo", map[string]string{"timeout": "2s", "retries": "3"})
+	apollo.release("shared.middleware.yaml", map[string]string{
+		contentKey: "timeout: 3s\nregistry:\n  address: zookeeper://127.0.0.1:2181\n  ports: [2181, 2182]\n"})
+
+	c, err := newApolloDynamicConfiguration(newApolloTestURL(t, apollo.URL,
+		"application, demo.dubbo,shared.middleware.yaml,application.properties",
+		common.WithParamsValue(constant.ConfigBackupConfigKey, "false")))
+	assert.NoError(t, err)
+	defer c.Destroy()
+	assert.Equal(t, []string{"application", "demo.dubbo", "shared.middleware.yaml"}, c.namespaces)
+
+	// the earlier namespaces take precedence over the later ones
+	for key, value := range map[string]string{
+		"timeout":           "1s",
+		"retries":           "3",
+		"registry.address":  "zookeeper://127.0.0.1:2181",
+		"registry.ports[1]": "2182",
+		"absent":            "",
+	} {
+		property, err := c.GetInternalProperty(key)
+		assert.NoError(t, err)
+		assert.Equal(t, value, property, key)
+	}
+	keys, err := c.GetConfigKeysByGroup("")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, keys.Size())
+
+	content, err := c.GetProperties("demo.dubbo")
+	assert.NoError(t, err)
+	assert.Equal(t, "retries = 3\ntimeout = 2s\n", content)
+	content, err = c.GetProperties("shared.middleware.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, content, "ports: [2181, 2182]")
+	// the namespaces not released are empty
+	content, err = c.GetProperties("absent.yaml")
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+
+	assert.Equal(t, ErrUnsupportedOperation, c.PublishConfig("timeout", "", "5s"))
+	assert.Equal(t, ErrUnsupportedOperation, c.RemoveConfig("timeout", ""))
+}
+
+func TestApolloChangeEvents(t *testing.T) {
+	apollo := newFakeApollo()
+	defer apollo.Close()
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	apollo.release("demo.dubbo", map[string]string{"timeout": "2s", "retries": "3"})
+	apollo.release("shared.yaml", map[string]string{contentKey: "registry:\n  address: zk1\n"})
+
+	c, err := newApolloDynamicConfiguration(newApolloTestURL(t, apollo.URL, "application,demo.dubbo,shared.yaml",
+		common.WithParamsValue(constant.ConfigBackupConfigKey, "false")))
+	assert.NoError(t, err)
+	defer c.Destroy()
+	propertyListener := newTestPropertyListener()
+	c.AddListener("demo.dubbo", propertyListener)
+	contentListener := newTestListener()
+	c.AddListener("shared.yaml", contentListener)
+	timeoutListener := newTestListener()
+	c.AddListener("timeout", timeoutListener)
+	timeoutPropertyListener := newTestPropertyListener()
+	c.AddListener("timeout", timeoutPropertyListener)
+
+	// the changes of the properties are delivered with the namespace
+	apollo.release("demo.dubbo", map[string]string{"timeout": "2s", "retries": "4", "weight": "100"})
+	assert.Equal(t, []PropertyChange{
+		{Key: "retries", Value: "4", OldValue: "3", ChangeType: remoting.EventTypeUpdate, Namespace: "demo.dubbo",
+			OldNamespace: "demo.dubbo"},
+		{Key: "weight", Value: "100", ChangeType: remoting.EventTypeAdd, Namespace: "demo.dubbo"},
+	}, propertyListener.next(t))
+
+	apollo.release("shared.yaml", map[string]string{contentKey: "registry:\n  address: zk2\n"})
+	event := contentListener.next(t)
+	assert.Equal(t, "shared.yaml", event.Key)
+	assert.Equal(t, "registry:\n  address: zk2\n", event.Value)
+	assert.Equal(t, "registry:\n  address: zk1\n", event.OldValue)
+	assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+
+	// the property falls back to the later namespace once it is removed from the earlier one
+	apollo.release("application", map[string]string{})
+	event = timeoutListener.next(t)
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "timeout", Value: "2s", OldValue: "1s",
+		ConfigType: remoting.EventTypeUpdate}, event)
+	assert.Equal(t, []PropertyChange{{Key: "timeout", Value: "2s", OldValue: "1s", ChangeType: remoting.EventTypeUpdate,
+		Namespace: "demo.dubbo", OldNamespace: "application"}}, timeoutPropertyListener.next(t))
+
+	// the same value taking precedence from another namespace changes only the namespace
+	apollo.release("application", map[string]string{"timeout": "2s"})
+	assert.Equal(t, []PropertyChange{{Key: "timeout", Value: "2s", OldValue: "2s", ChangeType: remoting.EventTypeUpdate,
+		Namespace: "application", OldNamespace: "demo.dubbo"}}, timeoutPropertyListener.next(t))
+	assert.Empty(t, timeoutListener.events)
+
+	c.RemoveListener("timeout", timeoutListener)
+	c.RemoveListener("timeout", timeoutPropertyListener)
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	apollo.release("demo.dubbo", map[string]string{"timeout": "2s"})
+	changes := propertyListener.next(t)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "demo.dubbo", changes[0].OldNamespace)
+	assert.Empty(t, timeoutListener.events)
+	assert.Empty(t, timeoutPropertyListener.changes)
+	assert.Empty(t, propertyListener.events)
+}
+
+func TestApolloPollBackoff(t *testing.T) {
+	apollo := newFakeApollo()
+	defer apollo.Close()
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	c := newConfiguration(newApolloTestURL(t, apollo.URL, "",
+		common.WithParamsValue(constant.ConfigBackupConfigKey, "false")))
+	c.minBackoff, c.maxBackoff = 20*time.Millisecond, 80*time.Millisecond
+	assert.NoError(t, c.start())
+	defer c.Destroy()
+	listener := newTestListener()
+	c.AddListener("timeout", listener)
+
+	// the polls back off while apollo is unreachable
+	apollo.setDown(true)
+	polls := apollo.pollCount()
+	time.Sleep(500 * time.Millisecond)
+	failed := apollo.pollCount() - polls
+	assert.True(t, failed >= 4 && failed <= 12, failed)
+
+	// the release during the outage is delivered once apollo is reachable again
+	apollo.release("application", map[string]string{"timeout": "5s"})
+	apollo.setDown(false)
+	event := listener.next(t)
+	assert.Equal(t, "5s", event.Value)
+	assert.Equal(t, "1s", event.OldValue)
+}
+
+func TestApolloLocalCache(t *testing.T) {
+	dir := t.TempDir()
+	apollo := newFakeApollo()
+	defer apollo.Close()
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	apollo.release("dubbo.yaml", map[string]string{contentKey: "dubbo:\n  application:\n    name: demo\n"})
+	url := newApolloTestURL(t, apollo.URL, "application,dubbo.yaml",
+		common.WithParamsValue(constant.ConfigBackupConfigPathKey, dir))
+	c, err := newApolloDynamicConfiguration(url)
+	assert.NoError(t, err)
+	c.Destroy()
+	cached, err := readCache(dir, "demo", "dubbo.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo.yaml-1", cached.ReleaseKey)
+
+	// the local cache files are read at startup while apollo is unreachable
+	apollo.setDown(true)
+	c = newConfiguration(url)
+	c.minBackoff, c.maxBackoff = 20*time.Millisecond, 20*time.Millisecond
+	assert.NoError(t, c.start())
+	defer c.Destroy()
+	content, err := c.GetProperties("dubbo.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo:\n  application:\n    name: demo\n", content)
+	timeout, err := c.GetInternalProperty("timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, "1s", timeout)
+
+	// the namespace without the local cache file fails the startup
+	_, err = newApolloDynamicConfiguration(newApolloTestURL(t, apollo.URL, "absent",
+		common.WithParamsValue(constant.ConfigBackupConfigPathKey, dir)))
+	assert.Error(t, err)
+
+	// the releases are delivered once apollo is reachable
+	listener := newTestListener()
+	c.AddListener("dubbo.yaml", listener)
+	apollo.release("dubbo.yaml", map[string]string{contentKey: "dubbo:\n  application:\n    name: demo2\n"})
+	apollo.setDown(false)
+	event := listener.next(t)
+	assert.Equal(t, "dubbo:\n  application:\n    name: demo2\n", event.Value)
+	cached, err = readCache(dir, "demo", "dubbo.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo.yaml-2", cached.ReleaseKey)
+}
+
+func TestApolloSecret(t *testing.T) {
+	apollo := newFakeApollo()
+	defer apollo.Close()
+	apollo.secret = "secret"
+	apollo.release("application", map[string]string{"timeout": "1s"})
+	noCache := common.WithParamsValue(constant.ConfigBackupConfigKey, "false")
+
+	c, err := newApolloDynamicConfiguration(newApolloTestURL(t, apollo.URL, "", noCache,
+		common.WithParamsValue(constant.ConfigSecretKey, "secret")))
+	assert.NoError(t, err)
+	c.Destroy()
+	_, err = newApolloDynamicConfiguration(newApolloTestURL(t, apollo.URL, "", noCache,
+		common.WithParamsValue(constant.ConfigSecretKey, "wrong")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestFlattenYAML(t *testing.T) {
+	properties, err := flattenYAML("a:\n  b: 1\n  c: [x, {d: z}]\n  e:\n")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.b": "1", "a.c[0]": "x", "a.c[1].d": "z", "a.e": ""}, properties)
+	properties, err = flattenYAML("")
+	assert.NoError(t, err)
+	assert.Empty(t, properties)
+	_, err = flattenYAML("- a")
+	assert.Error(t, err)
+
+	assert.Equal(t, formatYAML, formatOf("shared.YML"))
+	assert.Equal(t, formatText, formatOf("rules.json"))
+	assert.Equal(t, formatProperties, formatOf("demo.dubbo"))
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config_center
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// DefaultCoalescingWindow is the window the governance listeners coalesce the rapid changes of their rules in
+const DefaultCoalescingWindow = 100 * time.Millisecond
+
+// CoalescingListener delivers the config change events to the wrapped listener, the changes of a key within the
+// window are coalesced and only the latest one is delivered, so rapid successive changes are not parsed
+// redundantly. The old value of the delivered event is the value before the first change coalesced, or the value
+// last delivered if the config center does not know it.
+type CoalescingListener struct {
+	listener ConfigurationListener
+	window   time.Duration
+
+	mu sync.Mutex
+	// pending are the coalesced events waiting for the window to end, keyed by the config key
+	pending map[string]*ConfigChangeEvent
+	// delivered are the values last delivered, keyed by the config key
+	delivered map[string]interface{}
+}
+
+// NewCoalescingListener returns a CoalescingListener of @listener, the events are delivered immediately if
+// @window is not positive.
+func NewCoalescingListener(listener ConfigurationListener, window time.Duration) *CoalescingListener {
+	return &CoalescingListener{
+		listener:  listener,
+		window:    window,
+		pending:   make(map[string]*ConfigChangeEvent),
+		delivered: make(map[string]interface{}),
+	}
+}
+
+// Process coalesces @event with the pending event of the same key
+func (l *CoalescingListener) Process(event *ConfigChangeEvent) {
+	l.mu.Lock()
+	if pending, ok := l.pending[event.Key]; ok {
+		pending.Value = event.Value
+		pending.ConfigType = coalescedType(pending.ConfigType, event.ConfigType)
+		l.mu.Unlock()
+		return
+	}
+	coalesced := *event
+	if coalesced.OldValue == nil {
+		coalesced.OldValue = l.delivered[event.Key]
+	}
+	if l.window <= 0 {
+		l.mu.Unlock()
+		l.deliver(&coalesced)
+		return
+	}
+	l.pending[event.Key] = &coalesced
+	l.mu.Unlock()
+	time.AfterFunc(l.window, func() {
+		l.mu.Lock()
+		pending := l.pending[event.Key]
+		delete(l.pending, event.Key)
+		l.mu.Unlock()
+		l.deliver(pending)
+	})
+}
+
+func (l *CoalescingListener) deliver(event *ConfigChangeEvent) {
+	l.mu.Lock()
+	if event.ConfigType == remoting.EventTypeDel {
+		delete(l.delivered, event.Key)
+	} else {
+		l.delivered[event.Key] = event.Value
+	}
+	l.mu.Unlock()
+	l.listener.Process(event)
+}
+
+// coalescedType returns the type of the change @previous followed by @latest. A config added then updated is
+// still added, and a config deleted then added again is updated.
+func coalescedType(previous, latest remoting.EventType) remoting.EventType {
+	switch {
+	case latest == remoting.EventTypeDel:
+		return remoting.EventTypeDel
+	case previous == remoting.EventTypeAdd:
+		return remoting.EventTypeAdd
+	case previous == remoting.EventTypeDel:
+		return remoting.EventTypeUpdate
+	default:
+		return latest
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config_center
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type recordingListener struct {
+	mu     sync.Mutex
+	events []*ConfigChangeEvent
+}
+
+func (l *recordingListener) Process(event *ConfigChangeEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *recordingListener) received() []*ConfigChangeEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*ConfigChangeEvent(nil), l.events...)
+}
+
+func TestCoalescingListenerFlapping(t *testing.T) {
+	recorder := &recordingListener{}
+	l := NewCoalescingListener(recorder, 50*time.Millisecond)
+
+	// the rule flaps rapidly, the listener sees only the final one
+	for i := 0; i < 100; i++ {
+		l.Process(&ConfigChangeEvent{Key: "user-center.tag-router", Value: "rate: " + strconv.Itoa(i),
+			ConfigType: remoting.EventTypeUpdate})
+		l.Process(&ConfigChangeEvent{Key: "user-center.tps-limiter", Value: "rate: " + strconv.Itoa(i%2),
+			ConfigType: remoting.EventTypeUpdate})
+	}
+	assert.Eventually(t, func() bool { return len(recorder.received()) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	events := recorder.received()
+	assert.Len(t, events, 2)
+	values := map[string]interface{}{events[0].Key: events[0].Value, events[1].Key: events[1].Value}
+	assert.Equal(t, "rate: 99", values["user-center.tag-router"])
+	assert.Equal(t, "rate: 1", values["user-center.tps-limiter"])
+
+	// the old value is the value last delivered, so flapping back is unchanged
+	l.Process(&ConfigChangeEvent{Key: "user-center.tag-router", Value: "rate: 100", ConfigType: remoting.EventTypeUpdate})
+	l.Process(&ConfigChangeEvent{Key: "user-center.tag-router", Value: "rate: 99", ConfigType: remoting.EventTypeUpdate})
+	assert.Eventually(t, func() bool { return len(recorder.received()) == 3 }, time.Second, 10*time.Millisecond)
+	event := recorder.received()[2]
+	assert.Equal(t, "rate: 99", event.OldValue)
+	assert.True(t, event.Unchanged())
+}
+
+func TestCoalescingListenerEventType(t *testing.T) {
+	recorder := &recordingListener{}
+	l := NewCoalescingListener(recorder, 0)
+
+	l.Process(&ConfigChangeEvent{Key: "key", Value: "v1", ConfigType: remoting.EventTypeAdd})
+	l.Process(&ConfigChangeEvent{Key: "key", Value: "v2", ConfigType: remoting.EventTypeUpdate})
+	l.Process(&ConfigChangeEvent{Key: "key", Value: "v3", OldValue: "v1", ConfigType: remoting.EventTypeUpdate})
+	l.Process(&ConfigChangeEvent{Key: "key", ConfigType: remoting.EventTypeDel})
+	events := recorder.received()
+	assert.Len(t, events, 4)
+	// the events are delivered immediately without the window, filled with the value last delivered
+	assert.Nil(t, events[0].OldValue)
+	assert.Equal(t, "v1", events[1].OldValue)
+	// the old value known by the config center is kept
+	assert.Equal(t, "v1", events[2].OldValue)
+	assert.Equal(t, "v3", events[3].OldValue)
+	assert.False(t, events[3].Unchanged())
+
+	assert.Equal(t, remoting.EventTypeAdd, coalescedType(remoting.EventTypeAdd, remoting.EventTypeUpdate))
+	assert.Equal(t, remoting.EventTypeDel, coalescedType(remoting.EventTypeAdd, remoting.EventTypeDel))
+	assert.Equal(t, remoting.EventTypeUpdate, coalescedType(remoting.EventTypeDel, remoting.EventTypeAdd))
+	assert.Equal(t, remoting.EventTypeUpdate, coalescedType(remoting.EventTypeUpdate, remoting.EventTypeUpdate))
+}
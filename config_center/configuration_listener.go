@@ -33,11 +33,25 @@ type ConfigurationListener interface {
 
 // ConfigChangeEvent for changing listener's event
 type ConfigChangeEvent struct {
-	Key        string
-	Value      interface{}
+	Key   string
+	Value interface{}
+	// OldValue is the value before the change, nil if it is unknown or the config is just added
+	OldValue   interface{}
 	ConfigType remoting.EventType
 }
 
 func (c ConfigChangeEvent) String() string {
-	return fmt.Sprintf("ConfigChangeEvent{key = %v , value = %v , changeType = %v}", c.Key, c.Value, c.ConfigType)
+	return fmt.Sprintf("ConfigChangeEvent{key = %v , value = %v , oldValue = %v , changeType = %v}",
+		c.Key, c.Value, c.OldValue, c.ConfigType)
+}
+
+// Unchanged checks whether the value is the same as the old value, e.g. the config is published again with the
+// same content or flaps back within a coalescing window, so the listeners could skip parsing it again
+func (c ConfigChangeEvent) Unchanged() bool {
+	oldValue, ok := c.OldValue.(string)
+	if !ok || c.ConfigType == remoting.EventTypeDel {
+		return false
+	}
+	value, ok := c.Value.(string)
+	return ok && value == oldValue
 }
@@ -59,10 +59,11 @@ func (c *overrideConfigurator) Configure(url *common.URL) {
 	if len(apiVersion) != 0 {
 		currentSide := url.GetParam(constant.SideKey, "")
 		configuratorSide := c.configuratorUrl.GetParam(constant.SideKey, "")
-		if currentSide == configuratorSide && common.DubboRole[common.CONSUMER] == currentSide && c.configuratorUrl.Port == "0" {
+		if currentSide == configuratorSide && common.DubboRole[common.CONSUMER] == currentSide && isAnyPort(c.configuratorUrl.Port) {
 			localIP := common.GetLocalIp()
 			c.configureIfMatch(localIP, url)
-		} else if currentSide == configuratorSide && common.DubboRole[common.PROVIDER] == currentSide && c.configuratorUrl.Port == url.Port {
+		} else if currentSide == configuratorSide && common.DubboRole[common.PROVIDER] == currentSide &&
+			(isAnyPort(c.configuratorUrl.Port) || c.configuratorUrl.Port == url.Port) {
 			c.configureIfMatch(url.Ip, url)
 		}
 	} else {
@@ -71,6 +72,12 @@ func (c *overrideConfigurator) Configure(url *common.URL) {
 	}
 }
 
+// isAnyPort checks whether the port of the configurator url matches any port, the rules written without the port,
+// such as the addresses [0.0.0.0] of dubbo-admin, are parsed to the urls without the port
+func isAnyPort(port string) bool {
+	return len(port) == 0 || port == "0"
+}
+
 func (c *overrideConfigurator) configureIfMatchInternal(url *common.URL) {
 	configApp := c.configuratorUrl.GetParam(constant.ApplicationKey, c.configuratorUrl.Username)
 	currentApp := url.GetParam(constant.ApplicationKey, url.Username)
@@ -107,7 +114,12 @@ func (c *overrideConfigurator) configureIfMatchInternal(url *common.URL) {
 
 // configureIfMatch translate from java, compatible rules in java
 func (c *overrideConfigurator) configureIfMatch(host string, url *common.URL) {
-	if constant.AnyHostValue == c.configuratorUrl.Ip || host == c.configuratorUrl.Ip {
+	configIp := c.configuratorUrl.Ip
+	if len(configIp) == 0 {
+		// the url parsed from the address without the port, such as 0.0.0.0, keeps the ip in the location only
+		configIp = c.configuratorUrl.Location
+	}
+	if constant.AnyHostValue == configIp || host == configIp {
 		providers := c.configuratorUrl.GetParam(constant.OverrideProvidersKey, "")
 		if len(providers) == 0 || strings.Contains(providers, url.Location) || strings.Contains(providers, constant.AnyHostValue) {
 			c.configureIfMatchInternal(url)
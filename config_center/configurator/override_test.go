@@ -84,3 +84,22 @@ func TestConfigureVersion2p7(t *testing.T) {
 	configurator.Configure(providerUrl)
 	assert.Equal(t, failfast, providerUrl.GetParam(constant.ClusterKey, ""))
 }
+
+func TestConfigureVersion2p7AnyAddress(t *testing.T) {
+	// the rule of dubbo-admin written with the addresses [0.0.0.0] has neither the ip nor the port
+	url, err := common.NewURL("override://0.0.0.0/com.ikurento.user.UserProvider?category=dynamicconfigurators&configVersion=v2.7&enabled=true&side=provider&weight=200&providerAddresses=")
+	assert.NoError(t, err)
+	configurator := extension.GetConfigurator(defaults, url)
+
+	providerUrl, err := common.NewURL("jsonrpc://127.0.0.1:20001/com.ikurento.user.UserProvider?application=BDTService&category=providers&side=provider&weight=100")
+	assert.NoError(t, err)
+	configurator.Configure(providerUrl)
+	assert.Equal(t, "200", providerUrl.GetParam(constant.WeightKey, ""))
+	assert.Empty(t, providerUrl.GetParam(constant.ConfigVersionKey, ""))
+
+	// the rules of the consumer side are not applied to the provider
+	url, err = common.NewURL("override://0.0.0.0/com.ikurento.user.UserProvider?category=dynamicconfigurators&configVersion=v2.7&enabled=true&side=consumer&weight=300&providerAddresses=")
+	assert.NoError(t, err)
+	extension.GetConfigurator(defaults, url).Configure(providerUrl)
+	assert.Equal(t, "200", providerUrl.GetParam(constant.WeightKey, ""))
+}
@@ -0,0 +1,381 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	gxetcd "github.com/dubbogo/gost/database/kv/etcd/v3"
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	metricsConfigCenter "dubbo.apache.org/dubbo-go/v3/metrics/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+	"dubbo.apache.org/dubbo-go/v3/remoting/etcdv3"
+)
+
+const (
+	defaultRootPath = "/dubbo/config"
+	pathSeparator   = "/"
+	clientName      = "etcd config center"
+	// rewatchInterval is the interval to watch again if the config can not be read after the watch breaks
+	rewatchInterval = time.Second
+)
+
+// ErrConcurrentModification is returned by PublishConfig if the config is modified after it is read
+var ErrConcurrentModification = perrors.New("the config is modified concurrently")
+
+type etcdDynamicConfiguration struct {
+	config_center.BaseDynamicConfiguration
+	url      *common.URL
+	rootPath string
+	timeout  time.Duration
+	client   *gxetcd.Client
+	kv       clientv3.KV
+	watcher  clientv3.Watcher
+	parser   parser.ConfigurationParser
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	watchesLock sync.Mutex
+	// watches are the watches of the listened configs, keyed by the etcd key
+	watches map[string]*keyWatch
+}
+
+// keyWatch is the watch of an etcd key shared by the listeners of the config
+type keyWatch struct {
+	key       string
+	group     string
+	listeners map[config_center.ConfigurationListener]struct{}
+	cancel    context.CancelFunc
+	// modRevision is the mod revision of the config last seen by the watch, zero if it is absent
+	modRevision int64
+	// value is the value of the config last seen by the watch
+	value string
+}
+
+func newEtcdDynamicConfiguration(url *common.URL) (*etcdDynamicConfiguration, error) {
+	timeout := url.GetParamDurationWithDefault(constant.ConfigTimeoutKey, config_center.DefaultConfigTimeout)
+	logger.Infof("[Etcd ConfigCenter] New Etcd ConfigCenter with address %s, timeout %s", url.Location, timeout)
+	client, err := etcdv3.AcquireClient(clientName, strings.Split(url.Location, ","), timeout, 0)
+	if err != nil {
+		logger.Errorf("etcd client start error ,error message is %v", err)
+		return nil, err
+	}
+	c := newConfiguration(url, client.GetRawClient(), client.GetRawClient())
+	c.client = client
+	return c, nil
+}
+
+func newConfiguration(url *common.URL, kv clientv3.KV, watcher clientv3.Watcher) *etcdDynamicConfiguration {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &etcdDynamicConfiguration{
+		url:      url,
+		rootPath: "/" + strings.Trim(url.GetParam(constant.ConfigRootKey, defaultRootPath), pathSeparator),
+		timeout:  url.GetParamDurationWithDefault(constant.ConfigTimeoutKey, config_center.DefaultConfigTimeout),
+		kv:       kv,
+		watcher:  watcher,
+		ctx:      ctx,
+		cancel:   cancel,
+		watches:  make(map[string]*keyWatch),
+	}
+}
+
+// AddListener watches the config of @key, the listeners of a config share a watch
+func (c *etcdDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	group := c.groupOf(opts)
+	path := c.getPath(key, group)
+	c.watchesLock.Lock()
+	defer c.watchesLock.Unlock()
+	if w, ok := c.watches[path]; ok {
+		w.listeners[listener] = struct{}{}
+		return
+	}
+	w := &keyWatch{key: key, group: group, listeners: map[config_center.ConfigurationListener]struct{}{listener: {}}}
+	// watch from the revision read, the latest if the config can not be read
+	var revision int64
+	if resp, err := c.get(path); err != nil {
+		logger.Warnf("[Etcd ConfigCenter] Get the config %s error, %v, watch it from the latest revision", path, err)
+	} else {
+		revision = resp.Header.Revision + 1
+		if len(resp.Kvs) > 0 {
+			w.modRevision = resp.Kvs[0].ModRevision
+			w.value = string(resp.Kvs[0].Value)
+		}
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	w.cancel = cancel
+	c.watches[path] = w
+	go c.watch(ctx, path, w, revision)
+}
+
+// RemoveListener removes @listener of @key, the watch is canceled once the config has no listener
+func (c *etcdDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	path := c.getPath(key, c.groupOf(opts))
+	c.watchesLock.Lock()
+	defer c.watchesLock.Unlock()
+	w, ok := c.watches[path]
+	if !ok {
+		return
+	}
+	delete(w.listeners, listener)
+	if len(w.listeners) == 0 {
+		w.cancel()
+		delete(c.watches, path)
+	}
+}
+
+// watch watches @path from @revision until @ctx is canceled. The watch is established again if it breaks, e.g.
+// the revision watched is compacted, and the changes missed are delivered after the config is read again.
+func (c *etcdDynamicConfiguration) watch(ctx context.Context, path string, w *keyWatch, revision int64) {
+	for {
+		revision = c.watchOnce(ctx, path, w, revision)
+		if ctx.Err() != nil {
+			return
+		}
+		var err error
+		if revision, err = c.resync(path, w); err == nil {
+			continue
+		}
+		logger.Warnf("[Etcd ConfigCenter] Read the config %s error, %v, watch it again in %s", path, err, rewatchInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(rewatchInterval):
+		}
+	}
+}
+
+// watchOnce delivers the changes of @path from @revision until the watch breaks, and returns the revision to
+// watch from again
+func (c *etcdDynamicConfiguration) watchOnce(ctx context.Context, path string, w *keyWatch, revision int64) int64 {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var opts []clientv3.OpOption
+	if revision > 0 {
+		opts = append(opts, clientv3.WithRev(revision))
+	}
+	for resp := range c.watcher.Watch(clientv3.WithRequireLeader(watchCtx), path, opts...) {
+		if resp.CompactRevision != 0 {
+			logger.Warnf("[Etcd ConfigCenter] The revision %d of the config %s is compacted, watch it again",
+				revision, path)
+			return revision
+		}
+		if err := resp.Err(); err != nil {
+			logger.Warnf("[Etcd ConfigCenter] Watch the config %s error, %v, watch it again", path, err)
+			return revision
+		}
+		for _, event := range resp.Events {
+			c.dispatch(w, event)
+			revision = event.Kv.ModRevision + 1
+		}
+	}
+	return revision
+}
+
+// resync reads @path again and delivers the change missed by the watch, and returns the revision to watch from
+func (c *etcdDynamicConfiguration) resync(path string, w *keyWatch) (int64, error) {
+	resp, err := c.get(path)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case len(resp.Kvs) == 0 && w.modRevision != 0:
+		c.notify(w, 0, "", remoting.EventTypeDel)
+	case len(resp.Kvs) > 0 && resp.Kvs[0].ModRevision != w.modRevision:
+		eventType := remoting.EventTypeUpdate
+		if w.modRevision == 0 {
+			eventType = remoting.EventTypeAdd
+		}
+		c.notify(w, resp.Kvs[0].ModRevision, string(resp.Kvs[0].Value), eventType)
+	}
+	return resp.Header.Revision + 1, nil
+}
+
+// dispatch translates the etcd @event into the config change event of the listeners
+func (c *etcdDynamicConfiguration) dispatch(w *keyWatch, event *clientv3.Event) {
+	switch {
+	case event.Type == mvccpb.DELETE:
+		c.notify(w, 0, "", remoting.EventTypeDel)
+	case event.IsCreate():
+		c.notify(w, event.Kv.ModRevision, string(event.Kv.Value), remoting.EventTypeAdd)
+	default:
+		c.notify(w, event.Kv.ModRevision, string(event.Kv.Value), remoting.EventTypeUpdate)
+	}
+}
+
+// notify records the config of @modRevision seen by @w, and delivers the change to the listeners
+func (c *etcdDynamicConfiguration) notify(w *keyWatch, modRevision int64, value string, eventType remoting.EventType) {
+	var oldValue interface{}
+	if w.modRevision != 0 {
+		oldValue = w.value
+	}
+	w.modRevision, w.value = modRevision, value
+	defer metrics.Publish(metricsConfigCenter.NewIncMetricEvent(w.key, w.group, eventType, metricsConfigCenter.Etcd))
+	c.watchesLock.Lock()
+	listeners := make([]config_center.ConfigurationListener, 0, len(w.listeners))
+	for listener := range w.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.watchesLock.Unlock()
+	for _, listener := range listeners {
+		listener.Process(&config_center.ConfigChangeEvent{Key: w.key, Value: value, OldValue: oldValue, ConfigType: eventType})
+	}
+}
+
+// GetProperties returns the config of @key, empty if it is absent
+func (c *etcdDynamicConfiguration) GetProperties(key string, opts ...config_center.Option) (string, error) {
+	resp, err := c.get(c.getPath(key, c.groupOf(opts)))
+	if err != nil {
+		return "", perrors.WithStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+// GetInternalProperty For etcd, getConfig and getConfigs have the same meaning.
+func (c *etcdDynamicConfiguration) GetInternalProperty(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+func (c *etcdDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+// PublishConfig puts the config if it is not modified after it is read, ErrConcurrentModification is returned
+// otherwise
+func (c *etcdDynamicConfiguration) PublishConfig(key string, group string, value string) error {
+	path := c.getPath(key, group)
+	resp, err := c.get(path)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	// the mod revision of an absent key is 0
+	var modRevision int64
+	if len(resp.Kvs) > 0 {
+		modRevision = resp.Kvs[0].ModRevision
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+	txnResp, err := c.kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(path), "=", modRevision)).
+		Then(clientv3.OpPut(path, value)).
+		Commit()
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	if !txnResp.Succeeded {
+		return perrors.WithMessagef(ErrConcurrentModification, "publish the config %s", path)
+	}
+	return nil
+}
+
+// RemoveConfig will remove the config with the (key, group) pair
+func (c *etcdDynamicConfiguration) RemoveConfig(key string, group string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+	if _, err := c.kv.Delete(ctx, c.getPath(key, group)); err != nil {
+		return perrors.WithStack(err)
+	}
+	return nil
+}
+
+// GetConfigKeysByGroup will return all keys with the group
+func (c *etcdDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.HashSet, error) {
+	prefix := c.getPath("", group) + pathSeparator
+	resp, err := c.get(prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, perrors.New("could not find keys with group: " + group)
+	}
+	set := gxset.NewSet()
+	for _, kv := range resp.Kvs {
+		set.Add(strings.TrimPrefix(string(kv.Key), prefix))
+	}
+	return set, nil
+}
+
+func (c *etcdDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return c.parser
+}
+
+func (c *etcdDynamicConfiguration) SetParser(p parser.ConfigurationParser) {
+	c.parser = p
+}
+
+func (c *etcdDynamicConfiguration) GetURL() *common.URL {
+	return c.url
+}
+
+func (c *etcdDynamicConfiguration) IsAvailable() bool {
+	return c.ctx.Err() == nil
+}
+
+// Destroy cancels the watches and releases the etcd client
+func (c *etcdDynamicConfiguration) Destroy() {
+	c.cancel()
+	etcdv3.ReleaseClient(c.client)
+}
+
+func (c *etcdDynamicConfiguration) get(path string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+	return c.kv.Get(ctx, path, opts...)
+}
+
+// groupOf returns the group in @opts
+func (c *etcdDynamicConfiguration) groupOf(opts []config_center.Option) string {
+	options := &config_center.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options.Group
+}
+
+// getPath returns the etcd key of the config, root/group/key, the group is the namespace of the config center or
+// dubbo if it is empty
+func (c *etcdDynamicConfiguration) getPath(key string, group string) string {
+	if len(group) == 0 {
+		group = c.url.GetParam(constant.ConfigNamespaceKey, config_center.DefaultGroup)
+	}
+	if len(key) == 0 {
+		return c.rootPath + pathSeparator + group
+	}
+	return c.rootPath + pathSeparator + group + pathSeparator + key
+}
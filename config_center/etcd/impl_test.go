@@ -0,0 +1,335 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package etcd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// fakeEtcd is an in memory etcd keeping the whole history, whose watches could be broken by compactions
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Watcher
+
+	mu        sync.Mutex
+	revision  int64
+	kvs       map[string]*mvccpb.KeyValue
+	history   []*clientv3.Event
+	compacted int64
+	watches   map[*fakeWatch]struct{}
+	// beforeTxn is called before the comparisons of a txn are evaluated
+	beforeTxn func()
+}
+
+type fakeWatch struct {
+	key string
+	ch  chan clientv3.WatchResponse
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{kvs: make(map[string]*mvccpb.KeyValue), watches: make(map[*fakeWatch]struct{})}
+}
+
+func (e *fakeEtcd) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	op := clientv3.OpGet(key, opts...)
+	resp := &clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: e.revision}}
+	for k, kv := range e.kvs {
+		if k == key || (len(op.RangeBytes()) > 0 && strings.HasPrefix(k, key)) {
+			resp.Kvs = append(resp.Kvs, kv)
+		}
+	}
+	return resp, nil
+}
+
+func (e *fakeEtcd) Delete(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.delete(key)
+	return &clientv3.DeleteResponse{Header: &pb.ResponseHeader{Revision: e.revision}}, nil
+}
+
+func (e *fakeEtcd) Txn(_ context.Context) clientv3.Txn {
+	return &fakeTxn{etcd: e}
+}
+
+// put puts @value of @key, the watches are notified unless @silent, like the watches falling behind
+func (e *fakeEtcd) put(key, value string, silent bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.revision++
+	kv := &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value), CreateRevision: e.revision, ModRevision: e.revision}
+	if prev, ok := e.kvs[key]; ok {
+		kv.CreateRevision = prev.CreateRevision
+	}
+	e.kvs[key] = kv
+	event := &clientv3.Event{Type: mvccpb.PUT, Kv: kv}
+	e.history = append(e.history, event)
+	if !silent {
+		e.deliver(event)
+	}
+}
+
+func (e *fakeEtcd) delete(key string) {
+	if _, ok := e.kvs[key]; !ok {
+		return
+	}
+	e.revision++
+	delete(e.kvs, key)
+	event := &clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: e.revision}}
+	e.history = append(e.history, event)
+	e.deliver(event)
+}
+
+func (e *fakeEtcd) deliver(event *clientv3.Event) {
+	for w := range e.watches {
+		if w.key == string(event.Kv.Key) {
+			w.ch <- clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: e.revision}, Events: []*clientv3.Event{event}}
+		}
+	}
+}
+
+// compact compacts the history and breaks the watches, like the watches fall behind the compaction
+func (e *fakeEtcd) compact() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.compacted = e.revision
+	for w := range e.watches {
+		w.ch <- clientv3.WatchResponse{CompactRevision: e.compacted}
+		close(w.ch)
+		delete(e.watches, w)
+	}
+}
+
+func (e *fakeEtcd) watchCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.watches)
+}
+
+func (e *fakeEtcd) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	w := &fakeWatch{key: key, ch: make(chan clientv3.WatchResponse, 100)}
+	rev := clientv3.OpGet(key, opts...).Rev()
+	if rev > 0 && rev <= e.compacted {
+		w.ch <- clientv3.WatchResponse{CompactRevision: e.compacted}
+		close(w.ch)
+		return w.ch
+	}
+	for _, event := range e.history {
+		if string(event.Kv.Key) == key && rev > 0 && event.Kv.ModRevision >= rev {
+			w.ch <- clientv3.WatchResponse{Events: []*clientv3.Event{event}}
+		}
+	}
+	e.watches[w] = struct{}{}
+	go func() {
+		<-ctx.Done()
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.watches[w]; ok {
+			delete(e.watches, w)
+			close(w.ch)
+		}
+	}()
+	return w.ch
+}
+
+// fakeTxn supports the comparisons of the mod revisions and the puts only
+type fakeTxn struct {
+	etcd *fakeEtcd
+	cmps []clientv3.Cmp
+	ops  []clientv3.Op
+}
+
+func (t *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = cs
+	return t
+}
+
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.ops = ops
+	return t
+}
+
+func (t *fakeTxn) Else(...clientv3.Op) clientv3.Txn {
+	return t
+}
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	if t.etcd.beforeTxn != nil {
+		t.etcd.beforeTxn()
+	}
+	t.etcd.mu.Lock()
+	for _, cmp := range t.cmps {
+		var modRevision int64
+		if kv, ok := t.etcd.kvs[string(cmp.Key)]; ok {
+			modRevision = kv.ModRevision
+		}
+		if modRevision != cmp.TargetUnion.(*pb.Compare_ModRevision).ModRevision {
+			t.etcd.mu.Unlock()
+			return &clientv3.TxnResponse{Succeeded: false}, nil
+		}
+	}
+	t.etcd.mu.Unlock()
+	for _, op := range t.ops {
+		t.etcd.put(string(op.KeyBytes()), string(op.ValueBytes()), false)
+	}
+	return &clientv3.TxnResponse{Succeeded: true}, nil
+}
+
+// eventListener collects the config change events
+type eventListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func newEventListener() *eventListener {
+	return &eventListener{events: make(chan *config_center.ConfigChangeEvent, 100)}
+}
+
+func (l *eventListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *eventListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("no config change event")
+		return nil
+	}
+}
+
+func newTestConfiguration(t *testing.T, etcd *fakeEtcd) *etcdDynamicConfiguration {
+	url, err := common.NewURL("etcdv3://127.0.0.1:2379?config-center.timeout=1s")
+	assert.NoError(t, err)
+	c := newConfiguration(url, etcd, etcd)
+	t.Cleanup(c.Destroy)
+	return c
+}
+
+func TestGetAndPublishConfig(t *testing.T) {
+	etcd := newFakeEtcd()
+	c := newTestConfiguration(t, etcd)
+
+	content, err := c.GetProperties("dubbo.properties", config_center.WithGroup("user-center"))
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+
+	assert.NoError(t, c.PublishConfig("dubbo.properties", "user-center", "dubbo.consumer.check=false"))
+	assert.NoError(t, c.PublishConfig("condition-router", "user-center", "force: true"))
+	assert.Equal(t, "force: true", string(etcd.kvs["/dubbo/config/user-center/condition-router"].Value))
+	content, err = c.GetRule("dubbo.properties", config_center.WithGroup("user-center"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo.consumer.check=false", content)
+
+	keys, err := c.GetConfigKeysByGroup("user-center")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, keys.Size())
+	assert.True(t, keys.Contains("condition-router"))
+
+	assert.NoError(t, c.RemoveConfig("condition-router", "user-center"))
+	keys, err = c.GetConfigKeysByGroup("user-center")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, keys.Size())
+}
+
+func TestPublishConfigConcurrently(t *testing.T) {
+	etcd := newFakeEtcd()
+	c := newTestConfiguration(t, etcd)
+	assert.NoError(t, c.PublishConfig("condition-router", "user-center", "force: true"))
+
+	// modified by another one after it is read
+	etcd.beforeTxn = func() {
+		etcd.put("/dubbo/config/user-center/condition-router", "force: false", false)
+	}
+	err := c.PublishConfig("condition-router", "user-center", "enabled: false")
+	assert.ErrorIs(t, err, ErrConcurrentModification)
+	assert.Equal(t, "force: false", string(etcd.kvs["/dubbo/config/user-center/condition-router"].Value))
+}
+
+func TestAddListener(t *testing.T) {
+	etcd := newFakeEtcd()
+	c := newTestConfiguration(t, etcd)
+	l := newEventListener()
+	c.AddListener("condition-router", l, config_center.WithGroup("user-center"))
+
+	assert.NoError(t, c.PublishConfig("condition-router", "user-center", "force: true"))
+	event := l.next(t)
+	assert.Equal(t, "condition-router", event.Key)
+	assert.Equal(t, "force: true", event.Value)
+	assert.Equal(t, remoting.EventTypeAdd, event.ConfigType)
+
+	assert.NoError(t, c.PublishConfig("condition-router", "user-center", "force: false"))
+	event = l.next(t)
+	assert.Equal(t, "force: false", event.Value)
+	assert.Equal(t, "force: true", event.OldValue)
+	assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+
+	assert.NoError(t, c.RemoveConfig("condition-router", "user-center"))
+	event = l.next(t)
+	assert.Equal(t, "force: false", event.OldValue)
+	assert.Equal(t, remoting.EventTypeDel, event.ConfigType)
+
+	// the watch is canceled once the config has no listener
+	c.RemoveListener("condition-router", l, config_center.WithGroup("user-center"))
+	assert.Eventually(t, func() bool { return etcd.watchCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWatchAfterCompaction(t *testing.T) {
+	etcd := newFakeEtcd()
+	c := newTestConfiguration(t, etcd)
+	etcd.put("/dubbo/config/dubbo/condition-router", "force: true", false)
+	l := newEventListener()
+	c.AddListener("condition-router", l)
+	assert.Eventually(t, func() bool { return etcd.watchCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	// the change missed by the broken watch is delivered after the config is read again
+	etcd.put("/dubbo/config/dubbo/condition-router", "force: false", true)
+	etcd.compact()
+	event := l.next(t)
+	assert.Equal(t, "force: false", event.Value)
+	assert.Equal(t, "force: true", event.OldValue)
+	assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+
+	// the watch is established again
+	assert.Eventually(t, func() bool { return etcd.watchCount() == 1 }, time.Second, 10*time.Millisecond)
+	etcd.put("/dubbo/config/dubbo/condition-router", "enabled: false", false)
+	event = l.next(t)
+	assert.Equal(t, "enabled: false", event.Value)
+	assert.Empty(t, l.events)
+}
@@ -0,0 +1,208 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// FallbackDynamicConfiguration serves the configs missing in the group of the config center from the fallback groups
+// in order, e.g. the configs shared by the environments are kept in a shared group and overridden in the group of an
+// environment. The configs of the other groups are not affected.
+type FallbackDynamicConfiguration struct {
+	DynamicConfiguration
+	// groups are the group of the config center followed by the fallback groups
+	groups []string
+
+	mu sync.Mutex
+	// listeners are the listeners of the fallback chains, keyed by the config key and the listener added
+	listeners map[fallbackListenerKey]*fallbackListener
+}
+
+type fallbackListenerKey struct {
+	key      string
+	listener ConfigurationListener
+}
+
+// NewFallbackDynamicConfiguration returns a FallbackDynamicConfiguration of @configuration, the configs missing in
+// @group are served from @fallbackGroups in order
+func NewFallbackDynamicConfiguration(configuration DynamicConfiguration, group string, fallbackGroups []string) *FallbackDynamicConfiguration {
+	return &FallbackDynamicConfiguration{
+		DynamicConfiguration: configuration,
+		groups:               append([]string{group}, fallbackGroups...),
+		listeners:            make(map[fallbackListenerKey]*fallbackListener),
+	}
+}
+
+// chainOf returns the groups walked for the group of @opts, it is nil if the group has no fallback groups
+func (c *FallbackDynamicConfiguration) chainOf(opts ...Option) []string {
+	tmpOpts := &Options{}
+	for _, opt := range opts {
+		opt(tmpOpts)
+	}
+	if len(tmpOpts.Group) != 0 && tmpOpts.Group != c.groups[0] {
+		return nil
+	}
+	return c.groups
+}
+
+// GetProperties gets the config of @key from the first group of the chain having it
+func (c *FallbackDynamicConfiguration) GetProperties(key string, opts ...Option) (string, error) {
+	return c.get(c.DynamicConfiguration.GetProperties, key, opts...)
+}
+
+// GetRule gets the rule of @key from the first group of the chain having it
+func (c *FallbackDynamicConfiguration) GetRule(key string, opts ...Option) (string, error) {
+	return c.get(c.DynamicConfiguration.GetRule, key, opts...)
+}
+
+// GetInternalProperty gets the property of @key from the first group of the chain having it
+func (c *FallbackDynamicConfiguration) GetInternalProperty(key string, opts ...Option) (string, error) {
+	return c.get(c.DynamicConfiguration.GetInternalProperty, key, opts...)
+}
+
+// get gets @key from the groups of the chain in order, the error of the first group is returned if none has it
+func (c *FallbackDynamicConfiguration) get(get func(string, ...Option) (string, error), key string, opts ...Option) (string, error) {
+	chain := c.chainOf(opts...)
+	if chain == nil {
+		return get(key, opts...)
+	}
+	var firstErr error
+	for i, group := range chain {
+		content, err := get(key, withGroup(opts, group)...)
+		if err == nil && len(content) != 0 {
+			return content, nil
+		}
+		if i == 0 {
+			firstErr = err
+		}
+	}
+	return "", firstErr
+}
+
+// AddListener listens to @key in all the groups of the chain, @listener is notified of the changes of the config
+// served, e.g. the config deleted in the group of the config center is changed to the one of the fallback group
+func (c *FallbackDynamicConfiguration) AddListener(key string, listener ConfigurationListener, opts ...Option) {
+	chain := c.chainOf(opts...)
+	if chain == nil {
+		c.DynamicConfiguration.AddListener(key, listener, opts...)
+		return
+	}
+	l := &fallbackListener{key: key, listener: listener, values: make([]string, len(chain)),
+		groupListeners: make([]*fallbackGroupListener, len(chain))}
+	for i, group := range chain {
+		// the config missing in the group is empty
+		l.values[i], _ = c.DynamicConfiguration.GetProperties(key, withGroup(opts, group)...)
+		l.groupListeners[i] = &fallbackGroupListener{chain: l, index: i}
+	}
+	l.served = l.serving()
+	c.mu.Lock()
+	c.listeners[fallbackListenerKey{key: key, listener: listener}] = l
+	c.mu.Unlock()
+	for i, group := range chain {
+		c.DynamicConfiguration.AddListener(key, l.groupListeners[i], withGroup(opts, group)...)
+	}
+}
+
+// RemoveListener removes @listener of @key from all the groups of the chain
+func (c *FallbackDynamicConfiguration) RemoveListener(key string, listener ConfigurationListener, opts ...Option) {
+	chain := c.chainOf(opts...)
+	if chain == nil {
+		c.DynamicConfiguration.RemoveListener(key, listener, opts...)
+		return
+	}
+	c.mu.Lock()
+	l, ok := c.listeners[fallbackListenerKey{key: key, listener: listener}]
+	delete(c.listeners, fallbackListenerKey{key: key, listener: listener})
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	for i, group := range chain {
+		c.DynamicConfiguration.RemoveListener(key, l.groupListeners[i], withGroup(opts, group)...)
+	}
+}
+
+// fallbackListener notifies the listener of the config served by the chain, which is the first one not empty
+type fallbackListener struct {
+	key            string
+	listener       ConfigurationListener
+	groupListeners []*fallbackGroupListener
+
+	mu sync.Mutex
+	// values are the configs of the groups of the chain
+	values []string
+	// served is the config last served
+	served string
+}
+
+func (l *fallbackListener) serving() string {
+	for _, value := range l.values {
+		if len(value) != 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// process updates the config of the group at @index by @event, the listener is notified if the config served changes
+func (l *fallbackListener) process(index int, event *ConfigChangeEvent) {
+	l.mu.Lock()
+	if event.ConfigType == remoting.EventTypeDel {
+		l.values[index] = ""
+	} else {
+		l.values[index], _ = event.Value.(string)
+	}
+	oldValue, value := l.served, l.serving()
+	if value == oldValue {
+		l.mu.Unlock()
+		return
+	}
+	l.served = value
+	l.mu.Unlock()
+
+	changed := &ConfigChangeEvent{Key: l.key, Value: value, OldValue: oldValue, ConfigType: remoting.EventTypeUpdate}
+	switch {
+	case len(oldValue) == 0:
+		changed.OldValue = nil
+		changed.ConfigType = remoting.EventTypeAdd
+	case len(value) == 0:
+		changed.Value = nil
+		changed.ConfigType = remoting.EventTypeDel
+	}
+	l.listener.Process(changed)
+}
+
+// fallbackGroupListener listens to the config of a group of the chain
+type fallbackGroupListener struct {
+	chain *fallbackListener
+	index int
+}
+
+func (l *fallbackGroupListener) Process(event *ConfigChangeEvent) {
+	l.chain.process(l.index, event)
+}
+
+// withGroup returns @opts with @group, which overrides the group of @opts
+func withGroup(opts []Option, group string) []Option {
+	return append(opts[:len(opts):len(opts)], WithGroup(group))
+}
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// groupConfiguration keeps the configs by the group, the group is "dubbo" if absent
+type groupConfiguration struct {
+	DynamicConfiguration
+	configs   map[string]string
+	listeners map[string][]ConfigurationListener
+}
+
+func newGroupConfiguration() *groupConfiguration {
+	return &groupConfiguration{configs: make(map[string]string), listeners: make(map[string][]ConfigurationListener)}
+}
+
+func groupKey(key string, opts ...Option) string {
+	tmpOpts := &Options{Group: DefaultGroup}
+	for _, opt := range opts {
+		opt(tmpOpts)
+	}
+	return tmpOpts.Group + "/" + key
+}
+
+func (c *groupConfiguration) GetProperties(key string, opts ...Option) (string, error) {
+	return c.configs[groupKey(key, opts...)], nil
+}
+
+func (c *groupConfiguration) AddListener(key string, listener ConfigurationListener, opts ...Option) {
+	c.listeners[groupKey(key, opts...)] = append(c.listeners[groupKey(key, opts...)], listener)
+}
+
+func (c *groupConfiguration) RemoveListener(key string, listener ConfigurationListener, opts ...Option) {
+	listeners := c.listeners[groupKey(key, opts...)]
+	for i, l := range listeners {
+		if l == listener {
+			c.listeners[groupKey(key, opts...)] = append(listeners[:i], listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *groupConfiguration) publish(group, key, value string) {
+	configType := remoting.EventTypeUpdate
+	if len(value) == 0 {
+		configType = remoting.EventTypeDel
+		delete(c.configs, group+"/"+key)
+	} else {
+		c.configs[group+"/"+key] = value
+	}
+	for _, l := range c.listeners[group+"/"+key] {
+		l.Process(&ConfigChangeEvent{Key: key, Value: value, ConfigType: configType})
+	}
+}
+
+func TestFallbackGetProperties(t *testing.T) {
+	backend := newGroupConfiguration()
+	backend.publish("dubbo", "user-center", "shared")
+	backend.publish("dubbo", "order-center", "shared")
+	backend.publish("dubbo-prod", "user-center", "prod")
+	backend.publish("user-center", "user-center", "app")
+	c := NewFallbackDynamicConfiguration(backend, "dubbo-prod", []string{"dubbo"})
+
+	// the config of the environment overrides the shared one
+	content, err := c.GetProperties("user-center", WithGroup("dubbo-prod"))
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", content)
+	content, err = c.GetProperties("user-center")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", content)
+
+	// the shared config is served if it is missing in the environment
+	content, err = c.GetProperties("order-center", WithGroup("dubbo-prod"))
+	assert.NoError(t, err)
+	assert.Equal(t, "shared", content)
+	content, err = c.GetProperties("payment-center", WithGroup("dubbo-prod"))
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+
+	// the other groups have no fallback
+	content, err = c.GetProperties("order-center", WithGroup("user-center"))
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestFallbackListener(t *testing.T) {
+	backend := newGroupConfiguration()
+	backend.publish("dubbo", "user-center", "shared")
+	c := NewFallbackDynamicConfiguration(backend, "dubbo-prod", []string{"dubbo"})
+	recorder := &recordingListener{}
+	c.AddListener("user-center", recorder, WithGroup("dubbo-prod"))
+
+	// the override replaces the shared config
+	backend.publish("dubbo-prod", "user-center", "prod")
+	// the shared config is overridden
+	backend.publish("dubbo", "user-center", "shared-v2")
+	// the shared config is served again
+	backend.publish("dubbo-prod", "user-center", "")
+	// no config is served
+	backend.publish("dubbo", "user-center", "")
+	backend.publish("dubbo", "user-center", "shared-v3")
+
+	events := recorder.received()
+	assert.Len(t, events, 4)
+	assert.Equal(t, ConfigChangeEvent{Key: "user-center", Value: "prod", OldValue: "shared",
+		ConfigType: remoting.EventTypeUpdate}, *events[0])
+	assert.Equal(t, ConfigChangeEvent{Key: "user-center", Value: "shared-v2", OldValue: "prod",
+		ConfigType: remoting.EventTypeUpdate}, *events[1])
+	assert.Equal(t, ConfigChangeEvent{Key: "user-center", OldValue: "shared-v2",
+		ConfigType: remoting.EventTypeDel}, *events[2])
+	assert.Equal(t, ConfigChangeEvent{Key: "user-center", Value: "shared-v3",
+		ConfigType: remoting.EventTypeAdd}, *events[3])
+
+	c.RemoveListener("user-center", recorder, WithGroup("dubbo-prod"))
+	backend.publish("dubbo-prod", "user-center", "prod")
+	assert.Len(t, recorder.received(), 4)
+	assert.Empty(t, backend.listeners["dubbo/user-center"])
+}
@@ -109,7 +109,7 @@ func (fsdc *FileSystemDynamicConfiguration) AddListener(key string, listener con
 	}
 
 	tmpPath := fsdc.GetPath(key, tmpOpts.Group)
-	fsdc.cacheListener.AddListener(tmpPath, listener)
+	fsdc.cacheListener.AddListener(tmpPath, key, listener)
 }
 
 // RemoveListener Remove listener
@@ -124,7 +124,7 @@ func (fsdc *FileSystemDynamicConfiguration) RemoveListener(key string, listener
 	fsdc.cacheListener.RemoveListener(tmpPath, listener)
 }
 
-// GetProperties get properties file
+// GetProperties get properties file, empty if the file is absent
 func (fsdc *FileSystemDynamicConfiguration) GetProperties(key string, opts ...config_center.Option) (string, error) {
 	tmpOpts := &config_center.Options{}
 	for _, opt := range opts {
@@ -132,11 +132,11 @@ func (fsdc *FileSystemDynamicConfiguration) GetProperties(key string, opts ...co
 	}
 
 	tmpPath := fsdc.GetPath(key, tmpOpts.Group)
-	file, err := ioutil.ReadFile(tmpPath)
+	content, _, err := readFile(tmpPath)
 	if err != nil {
 		return "", perrors.WithStack(err)
 	}
-	return string(file), nil
+	return content, nil
 }
 
 // GetRule get Router rule properties file
@@ -164,8 +164,8 @@ func (fsdc *FileSystemDynamicConfiguration) GetConfigKeysByGroup(group string) (
 	fileInfo, _ := ioutil.ReadDir(tmpPath)
 
 	for _, file := range fileInfo {
-		// list file
-		if file.IsDir() {
+		// list file, skip the temp files being published
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
 			continue
 		}
 
@@ -175,7 +175,7 @@ func (fsdc *FileSystemDynamicConfiguration) GetConfigKeysByGroup(group string) (
 	return r, nil
 }
 
-// RemoveConfig will remove tconfig_center/nacos/impl_testhe config whit hte (key, group)
+// RemoveConfig will remove the config with the (key, group)
 func (fsdc *FileSystemDynamicConfiguration) RemoveConfig(key string, group string) error {
 	tmpPath := fsdc.GetPath(key, group)
 	_, err := fsdc.deleteDelay(tmpPath)
@@ -212,12 +212,32 @@ func (fsdc *FileSystemDynamicConfiguration) deleteDelay(path string) (bool, erro
 	return true, nil
 }
 
+// write2File writes a temp file and renames it, so that the listeners never read a half written file
 func (fsdc *FileSystemDynamicConfiguration) write2File(fp string, value string) error {
 	if err := forceMkdirParent(fp); err != nil {
 		return perrors.WithStack(err)
 	}
 
-	return ioutil.WriteFile(fp, []byte(value), os.ModePerm)
+	tmp, err := ioutil.TempFile(filepath.Dir(fp), "."+filepath.Base(fp)+".*")
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	_, err = tmp.WriteString(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	// the temp file is readable by the owner only
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fp)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return perrors.WithStack(err)
+	}
+	return nil
 }
 
 func forceMkdirParent(fp string) error {
@@ -303,7 +323,7 @@ func homeWindows() (string, error) {
 }
 
 func mkdirIfNecessary(urlRoot string) (string, error) {
-	if !legalPath(urlRoot) {
+	if len(urlRoot) == 0 {
 		// not exist, use default, mac is: /XXX/xx/.dubbo/config-center
 		rp, err := Home()
 		if err != nil {
@@ -323,17 +343,6 @@ func mkdirIfNecessary(urlRoot string) (string, error) {
 	return urlRoot, nil
 }
 
-func legalPath(path string) bool {
-	if len(path) == 0 {
-		return false
-	}
-	if _, err := os.Stat(path); err != nil {
-		return false
-	}
-
-	return true
-}
-
 func adapterUrl(rp string) string {
 	if osType == windowsOS {
 		return filepath.Join(rp, "_dubbo", "config-center")
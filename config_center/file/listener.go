@@ -14,12 +14,14 @@
  * See the License for the specific language governing permissions and
  * limitations under the License.
  */
-
 package file
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 )
 
 import (
@@ -34,45 +36,60 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
-// CacheListener is file watcher
+const (
+	// readRetries is the max times to read a changed file, which may be locked by the editor for a moment on windows
+	readRetries   = 3
+	readRetryWait = 10 * time.Millisecond
+)
+
+// CacheListener is file watcher. The directories of the files are watched rather than the files, so that the files
+// replaced by renaming, which most editors do on saving, are still watched, and so are the files not created yet.
 type CacheListener struct {
-	watch        *fsnotify.Watcher
-	keyListeners sync.Map
-	rootPath     string
+	watch    *fsnotify.Watcher
+	rootPath string
+
+	mu sync.Mutex
+	// keyListeners are the listeners keyed by the file path
+	keyListeners map[string]*fileListeners
+	// dirs are the counts of the listened files in the watched directories
+	dirs map[string]int
+}
+
+// fileListeners are the listeners of a file
+type fileListeners struct {
+	key       string
+	listeners map[config_center.ConfigurationListener]struct{}
+	// content is the content last seen, nil if the file is absent
+	content *string
 }
 
 // NewCacheListener creates a new CacheListener
 func NewCacheListener(rootPath string) *CacheListener {
-	cl := &CacheListener{rootPath: rootPath}
+	cl := &CacheListener{
+		rootPath:     rootPath,
+		keyListeners: make(map[string]*fileListeners),
+		dirs:         make(map[string]int),
+	}
 	// start watcher
 	watch, err := fsnotify.NewWatcher()
 	if err != nil {
 		logger.Errorf("file : listen config fail, error:%v ", err)
+		return cl
 	}
+	cl.watch = watch
 	go func() {
 		for {
 			select {
-			case event := <-watch.Events:
-				key := event.Name
-				logger.Debugf("watcher %s, event %v", cl.rootPath, event)
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					if l, ok := cl.keyListeners.Load(key); ok {
-						dataChangeCallback(l.(map[config_center.ConfigurationListener]struct{}), key,
-							remoting.EventTypeUpdate)
-					}
-				}
-				if event.Op&fsnotify.Create == fsnotify.Create {
-					if l, ok := cl.keyListeners.Load(key); ok {
-						dataChangeCallback(l.(map[config_center.ConfigurationListener]struct{}), key,
-							remoting.EventTypeAdd)
-					}
+			case event, ok := <-watch.Events:
+				if !ok {
+					return
 				}
-				if event.Op&fsnotify.Remove == fsnotify.Remove {
-					if l, ok := cl.keyListeners.Load(key); ok {
-						removeCallback(l.(map[config_center.ConfigurationListener]struct{}), key, remoting.EventTypeDel)
-					}
+				logger.Debugf("watcher %s, event %v", cl.rootPath, event)
+				cl.refresh(filepath.Clean(event.Name))
+			case err, ok := <-watch.Errors:
+				if !ok {
+					return
 				}
-			case err := <-watch.Errors:
 				// err may be nil, ignore
 				if err != nil {
 					logger.Warnf("file : listen watch fail:%+v", err)
@@ -80,7 +97,6 @@ func NewCacheListener(rootPath string) *CacheListener {
 			}
 		}
 	}()
-	cl.watch = watch
 
 	extension.AddCustomShutdownCallback(func() {
 		cl.watch.Close()
@@ -89,76 +105,133 @@ func NewCacheListener(rootPath string) *CacheListener {
 	return cl
 }
 
-func removeCallback(lmap map[config_center.ConfigurationListener]struct{}, key string, event remoting.EventType) {
-	if len(lmap) == 0 {
-		logger.Warnf("file watch callback but configuration listener is empty, key:%s, event:%v", key, event)
+// refresh reads the file of @path again, and delivers the change if it is listened. The events of a file are not
+// reliable across the platforms and editors, e.g. a file replaced by renaming is removed then created, and a file
+// written is truncated first, so the change is worked out by comparing the content.
+func (cl *CacheListener) refresh(path string) {
+	cl.mu.Lock()
+	fl, ok := cl.keyListeners[path]
+	if !ok {
+		cl.mu.Unlock()
 		return
 	}
-	for l := range lmap {
-		callback(l, key, "", event)
+	content, exist, err := readFile(path)
+	if err != nil {
+		cl.mu.Unlock()
+		logger.Errorf("read file path:%s err:%v", path, err)
+		return
 	}
-}
-
-func dataChangeCallback(lmap map[config_center.ConfigurationListener]struct{}, key string, event remoting.EventType) {
-	if len(lmap) == 0 {
-		logger.Warnf("file watch callback but configuration listener is empty, key:%s, event:%v", key, event)
+	event := &config_center.ConfigChangeEvent{Key: fl.key}
+	switch {
+	case !exist && fl.content == nil:
+		cl.mu.Unlock()
 		return
+	case !exist:
+		event.ConfigType, event.OldValue = remoting.EventTypeDel, *fl.content
+		fl.content = nil
+	case fl.content == nil:
+		event.ConfigType, event.Value = remoting.EventTypeAdd, content
+		fl.content = &content
+	case *fl.content == content:
+		cl.mu.Unlock()
+		return
+	default:
+		event.ConfigType, event.Value, event.OldValue = remoting.EventTypeUpdate, content, *fl.content
+		fl.content = &content
 	}
-	c := getFileContent(key)
-	for l := range lmap {
-		callback(l, key, c, event)
+	listeners := make([]config_center.ConfigurationListener, 0, len(fl.listeners))
+	for l := range fl.listeners {
+		listeners = append(listeners, l)
+	}
+	cl.mu.Unlock()
+	for _, l := range listeners {
+		l.Process(event)
 	}
-}
-
-func callback(listener config_center.ConfigurationListener, path, data string, event remoting.EventType) {
-	listener.Process(&config_center.ConfigChangeEvent{Key: path, Value: data, ConfigType: event})
 }
 
 // Close will remove key listener and close watcher
 func (cl *CacheListener) Close() error {
-	cl.keyListeners.Range(func(key, value interface{}) bool {
-		cl.keyListeners.Delete(key)
-		return true
-	})
+	cl.mu.Lock()
+	cl.keyListeners = make(map[string]*fileListeners)
+	cl.dirs = make(map[string]int)
+	cl.mu.Unlock()
+	if cl.watch == nil {
+		return nil
+	}
 	return cl.watch.Close()
 }
 
-// AddListener will add a listener if loaded
-// if you watcher a file or directory not exist, will error with no such file or directory
-func (cl *CacheListener) AddListener(key string, listener config_center.ConfigurationListener) {
-	// reference from https://stackoverflow.com/questions/34018908/golang-why-dont-we-have-a-set-datastructure
-	// make a map[your type]struct{} like set in java
-	listeners, loaded := cl.keyListeners.LoadOrStore(key, map[config_center.ConfigurationListener]struct{}{
-		listener: {},
-	})
-	if loaded {
-		listeners.(map[config_center.ConfigurationListener]struct{})[listener] = struct{}{}
-		cl.keyListeners.Store(key, listeners)
+// AddListener will add a listener of the config @key in the file @path, the file may be created later
+func (cl *CacheListener) AddListener(path, key string, listener config_center.ConfigurationListener) {
+	path = filepath.Clean(path)
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if fl, ok := cl.keyListeners[path]; ok {
+		fl.listeners[listener] = struct{}{}
 		return
 	}
-	if err := cl.watch.Add(key); err != nil {
-		logger.Errorf("watcher add path:%s err:%v", key, err)
+	fl := &fileListeners{key: key, listeners: map[config_center.ConfigurationListener]struct{}{listener: {}}}
+	if content, exist, err := readFile(path); err != nil {
+		logger.Errorf("read file path:%s err:%v", path, err)
+	} else if exist {
+		fl.content = &content
 	}
-}
+	cl.keyListeners[path] = fl
 
-// RemoveListener will delete a listener if loaded
-func (cl *CacheListener) RemoveListener(key string, listener config_center.ConfigurationListener) {
-	listeners, loaded := cl.keyListeners.Load(key)
-	if !loaded {
+	dir := filepath.Dir(path)
+	cl.dirs[dir]++
+	if cl.dirs[dir] > 1 || cl.watch == nil {
 		return
 	}
-	delete(listeners.(map[config_center.ConfigurationListener]struct{}), listener)
-	if err := cl.watch.Remove(key); err != nil {
-		logger.Errorf("watcher remove path:%s err:%v", key, err)
+	if err := createDir(dir); err != nil {
+		logger.Errorf("create the directory:%s of path:%s err:%v", dir, path, err)
+	}
+	if err := cl.watch.Add(dir); err != nil {
+		logger.Errorf("watcher add path:%s err:%v", dir, err)
 	}
 }
 
-func getFileContent(path string) string {
-	c, err := ioutil.ReadFile(path)
-	if err != nil {
-		logger.Errorf("read file path:%s err:%v", path, err)
-		return ""
+// RemoveListener will delete a listener, the file is not watched once it has no listener
+func (cl *CacheListener) RemoveListener(path string, listener config_center.ConfigurationListener) {
+	path = filepath.Clean(path)
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	fl, ok := cl.keyListeners[path]
+	if !ok {
+		return
+	}
+	delete(fl.listeners, listener)
+	if len(fl.listeners) > 0 {
+		return
 	}
+	delete(cl.keyListeners, path)
 
-	return string(c)
+	dir := filepath.Dir(path)
+	cl.dirs[dir]--
+	if cl.dirs[dir] > 0 {
+		return
+	}
+	delete(cl.dirs, dir)
+	if cl.watch == nil {
+		return
+	}
+	if err := cl.watch.Remove(dir); err != nil {
+		logger.Errorf("watcher remove path:%s err:%v", dir, err)
+	}
+}
+
+// readFile returns the content of the file of @path, and whether it exists
+func readFile(path string) (string, bool, error) {
+	var err error
+	for i := 0; i < readRetries; i++ {
+		var c []byte
+		if c, err = ioutil.ReadFile(path); err == nil {
+			return string(c), true, nil
+		}
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		time.Sleep(readRetryWait)
+	}
+	return "", false, err
 }
@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type eventListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func newEventListener() *eventListener {
+	return &eventListener{events: make(chan *config_center.ConfigChangeEvent, 100)}
+}
+
+func (l *eventListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *eventListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("no config change event")
+		return nil
+	}
+}
+
+func newTestConfiguration(t *testing.T) *FileSystemDynamicConfiguration {
+	url, err := common.NewURL("file://127.0.0.1", common.WithParamsValue(ConfigCenterDirParamName, t.TempDir()))
+	assert.NoError(t, err)
+	dc, err := newFileSystemDynamicConfiguration(url)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = dc.Close()
+	})
+	return dc
+}
+
+func TestListenFileEdited(t *testing.T) {
+	dc := newTestConfiguration(t)
+	l := newEventListener()
+	// the file is not created yet
+	dc.AddListener("user-center.tag-router", l, config_center.WithGroup("dubbogo"))
+	path := dc.GetPath("user-center.tag-router", "dubbogo")
+
+	assert.NoError(t, dc.PublishConfig("user-center.tag-router", "dubbogo", "force: true"))
+	event := l.next(t)
+	assert.Equal(t, "user-center.tag-router", event.Key)
+	assert.Equal(t, "force: true", event.Value)
+	assert.Equal(t, remoting.EventTypeAdd, event.ConfigType)
+
+	// edited in place
+	assert.NoError(t, ioutil.WriteFile(path, []byte("force: false"), 0644))
+	event = l.next(t)
+	assert.Equal(t, "force: false", event.Value)
+	assert.Equal(t, "force: true", event.OldValue)
+	assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+
+	// replaced by renaming like most editors do
+	tmp := filepath.Join(filepath.Dir(path), "user-center.tag-router.swp")
+	assert.NoError(t, ioutil.WriteFile(tmp, []byte("enabled: false"), 0644))
+	assert.NoError(t, os.Rename(tmp, path))
+	event = l.next(t)
+	assert.Equal(t, "enabled: false", event.Value)
+	assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+
+	// still watched after being replaced
+	assert.NoError(t, dc.PublishConfig("user-center.tag-router", "dubbogo", "enabled: true"))
+	assert.Equal(t, "enabled: true", l.next(t).Value)
+
+	assert.NoError(t, dc.RemoveConfig("user-center.tag-router", "dubbogo"))
+	event = l.next(t)
+	assert.Equal(t, "enabled: true", event.OldValue)
+	assert.Equal(t, remoting.EventTypeDel, event.ConfigType)
+
+	// the hidden temp files of publishing are not keys
+	assert.NoError(t, dc.PublishConfig("user-center.tps-limiter", "dubbogo", "rate: 1"))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(path), ".user-center.tps-limiter.123"), nil, 0644))
+	keys, err := dc.GetConfigKeysByGroup("dubbogo")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"user-center.tps-limiter"}, keys.Values())
+	assert.Empty(t, l.events)
+}
+
+func TestListenFileRemoveListener(t *testing.T) {
+	dc := newTestConfiguration(t)
+	l1, l2 := newEventListener(), newEventListener()
+	dc.AddListener("user-center.tps-limiter", l1)
+	dc.AddListener("user-center.tps-limiter", l2)
+	dc.AddListener("user-center.tag-router", l2)
+
+	assert.NoError(t, dc.PublishConfig("user-center.tps-limiter", "", "rate: 1"))
+	assert.Equal(t, "rate: 1", l1.next(t).Value)
+	assert.Equal(t, "rate: 1", l2.next(t).Value)
+
+	// the file is still watched for the other listeners
+	dc.RemoveListener("user-center.tps-limiter", l1)
+	assert.NoError(t, dc.PublishConfig("user-center.tps-limiter", "", "rate: 2"))
+	assert.Equal(t, "rate: 2", l2.next(t).Value)
+
+	// and so is the directory for the other files
+	dc.RemoveListener("user-center.tps-limiter", l2)
+	assert.NoError(t, dc.PublishConfig("user-center.tag-router", "", "force: true"))
+	event := l2.next(t)
+	assert.Equal(t, "user-center.tag-router", event.Key)
+	assert.Empty(t, l1.events)
+	assert.Empty(t, l2.events)
+
+	content, err := dc.GetRule("user-center.absent")
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+}
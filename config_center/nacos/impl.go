@@ -38,6 +38,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting/nacos"
 )
 
 const (
@@ -58,8 +59,12 @@ type nacosDynamicConfiguration struct {
 	cltLock      sync.Mutex
 	done         chan struct{}
 	client       *nacosClient.NacosConfigClient
-	keyListeners sync.Map
-	parser       parser.ConfigurationParser
+	listenerLock sync.RWMutex
+	// key is group/dataId and value is set of listeners
+	keyListeners map[string]map[config_center.ConfigurationListener]struct{}
+	// key is group/dataId and value is the func canceling the listening of the config
+	keyCancels map[string]func() error
+	parser     parser.ConfigurationParser
 }
 
 func newNacosDynamicConfiguration(url *common.URL) (*nacosDynamicConfiguration, error) {
@@ -71,8 +76,10 @@ func newNacosDynamicConfiguration(url *common.URL) (*nacosDynamicConfiguration,
 	url.SetParam(constant.NacosTimeout, url.GetParam(constant.ConfigTimeoutKey, ""))
 	url.SetParam(constant.NacosGroupKey, url.GetParam(constant.ConfigGroupKey, constant2.DEFAULT_GROUP))
 	c := &nacosDynamicConfiguration{
-		url:  url,
-		done: make(chan struct{}),
+		url:          url,
+		done:         make(chan struct{}),
+		keyListeners: make(map[string]map[config_center.ConfigurationListener]struct{}),
+		keyCancels:   make(map[string]func() error),
 	}
 	c.GetURL()
 	logger.Infof("[Nacos ConfigCenter] New Nacos ConfigCenter with Configuration: %+v, url = %+v", c, c.GetURL())
@@ -86,14 +93,14 @@ func newNacosDynamicConfiguration(url *common.URL) (*nacosDynamicConfiguration,
 	return c, err
 }
 
-// AddListener Add listener
+// AddListener Add listener of the key in the group of the options
 func (n *nacosDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, opions ...config_center.Option) {
-	n.addListener(key, listener)
+	n.addListener(key, listener, n.groupOf(opions...))
 }
 
 // RemoveListener Remove listener
 func (n *nacosDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, opions ...config_center.Option) {
-	n.removeListener(key, listener)
+	n.removeListener(key, listener, n.groupOf(opions...))
 }
 
 // GetProperties nacos distinguishes configuration files based on group and dataId. defalut group = "dubbo" and dataId = key
@@ -118,7 +125,7 @@ func (n *nacosDynamicConfiguration) PublishConfig(key string, group string, valu
 		return perrors.WithStack(err)
 	}
 	if !ok {
-		return perrors.New("publish config to Nocos failed")
+		return perrors.New("publish config to Nacos failed")
 	}
 	return nil
 }
@@ -162,14 +169,9 @@ func (n *nacosDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.H
 
 // GetRule Get router rule
 func (n *nacosDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
-	tmpOpts := &config_center.Options{}
-	for _, opt := range opts {
-		opt(tmpOpts)
-	}
-	resolvedGroup := n.resolvedGroup(tmpOpts.Group)
 	content, err := n.client.Client().GetConfig(vo.ConfigParam{
 		DataId: key,
-		Group:  resolvedGroup,
+		Group:  n.groupOf(opts...),
 	})
 	if err != nil {
 		return "", perrors.WithStack(err)
@@ -232,6 +234,15 @@ func (n *nacosDynamicConfiguration) resolvedGroup(group string) string {
 	return strings.ReplaceAll(group, "/", "-")
 }
 
+// groupOf returns the resolved group in @opts
+func (n *nacosDynamicConfiguration) groupOf(opts ...config_center.Option) string {
+	tmpOpts := &config_center.Options{}
+	for _, opt := range opts {
+		opt(tmpOpts)
+	}
+	return n.resolvedGroup(tmpOpts.Group)
+}
+
 // IsAvailable Get available status
 func (n *nacosDynamicConfiguration) IsAvailable() bool {
 	select {
@@ -243,7 +254,8 @@ func (n *nacosDynamicConfiguration) IsAvailable() bool {
 }
 
 func (n *nacosDynamicConfiguration) closeConfigs() {
-	// Close the old configClient first to close the tmp node
-	n.client.Close()
+	// Close the old configClient first to close the tmp node, it is closed once the metadata reports sharing it
+	// release it too
+	nacos.ReleaseNacosConfigClient(n.client)
 	logger.Infof("begin to close provider n configClient")
 }
@@ -21,6 +21,7 @@ package nacos
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 import (
@@ -31,12 +32,17 @@ import (
 
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // MockIConfigClient is a mock of IConfigClient interface
@@ -178,6 +184,8 @@ func newnNacosDynamicConfiguration(f *fields) *nacosDynamicConfiguration {
 		done:                     f.done,
 		client:                   f.client,
 		parser:                   f.parser,
+		keyListeners:             make(map[string]map[config_center.ConfigurationListener]struct{}),
+		keyCancels:               make(map[string]func() error),
 	}
 }
 
@@ -266,3 +274,79 @@ func Test_nacosDynamicConfiguration_GetConfigKeysByGroup(t *testing.T) {
 		})
 	}
 }
+
+type eventListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func (l *eventListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *eventListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		t.Fatal("no config change event")
+		return nil
+	}
+}
+
+func Test_nacosDynamicConfiguration_PublishAndListen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mnc := NewMockIConfigClient(ctrl)
+	// the mock nacos server notifies the listening configs once they are published
+	var listened vo.ConfigParam
+	mnc.EXPECT().ListenConfig(gomock.Any()).DoAndReturn(func(param vo.ConfigParam) error {
+		listened = param
+		return nil
+	})
+	mnc.EXPECT().PublishConfig(gomock.Any()).Times(2).DoAndReturn(func(param vo.ConfigParam) (bool, error) {
+		if param.DataId == listened.DataId && param.Group == listened.Group {
+			listened.OnChange("", param.Group, param.DataId, param.Content)
+		}
+		return true, nil
+	})
+	mnc.EXPECT().CancelListenConfig(gomock.Any()).DoAndReturn(func(param vo.ConfigParam) error {
+		assert.Equal(t, "dubbo-admin", param.Group)
+		return nil
+	})
+	nc := &nacosClient.NacosConfigClient{}
+	nc.SetClient(mnc)
+	n := newnNacosDynamicConfiguration(&fields{client: nc})
+
+	l1 := &eventListener{events: make(chan *config_center.ConfigChangeEvent, 10)}
+	l2 := &eventListener{events: make(chan *config_center.ConfigChangeEvent, 10)}
+	n.AddListener("user-center.condition-router", l1, config_center.WithGroup("dubbo/admin"))
+	n.AddListener("user-center.condition-router", l2, config_center.WithGroup("dubbo/admin"))
+	assert.Equal(t, "dubbo-admin", listened.Group)
+
+	assert.NoError(t, n.PublishConfig("user-center.condition-router", "dubbo/admin", "force: true"))
+	for _, l := range []*eventListener{l1, l2} {
+		event := l.next(t)
+		assert.Equal(t, "user-center.condition-router", event.Key)
+		assert.Equal(t, "force: true", event.Value)
+		assert.Equal(t, remoting.EventTypeUpdate, event.ConfigType)
+	}
+
+	// the listening is canceled once the last listener is removed
+	n.RemoveListener("user-center.condition-router", l1, config_center.WithGroup("dubbo/admin"))
+	assert.NoError(t, n.PublishConfig("user-center.condition-router", "dubbo/admin", "force: false"))
+	assert.Equal(t, "force: false", l2.next(t).Value)
+	assert.Empty(t, l1.events)
+	n.RemoveListener("user-center.condition-router", l2, config_center.WithGroup("dubbo/admin"))
+}
+
+func Test_nacosDynamicConfiguration_PublishConfigFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mnc := NewMockIConfigClient(ctrl)
+	mnc.EXPECT().PublishConfig(gomock.Any()).Return(false, nil)
+	mnc.EXPECT().DeleteConfig(gomock.Any()).Return(false, perrors.New("connection refused"))
+	nc := &nacosClient.NacosConfigClient{}
+	nc.SetClient(mnc)
+	n := newnNacosDynamicConfiguration(&fields{client: nc})
+
+	assert.EqualError(t, n.PublishConfig("dubbo.properties", "dubbogo", "dubbo.protocol.name=dubbo"), "publish config to Nacos failed")
+	assert.ErrorContains(t, n.RemoveConfig("dubbo.properties", "dubbogo"), "connection refused")
+}
@@ -17,14 +17,9 @@
 
 package nacos
 
-import (
-	"context"
-)
-
 import (
 	"github.com/dubbogo/gost/log/logger"
 
-	constant2 "github.com/nacos-group/nacos-sdk-go/v2/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
 
@@ -34,6 +29,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/metrics"
 	metricsConfigCenter "dubbo.apache.org/dubbo-go/v3/metrics/config_center"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
+	"dubbo.apache.org/dubbo-go/v3/remoting/nacos"
 )
 
 func callback(listener config_center.ConfigurationListener, _, group, dataId, data string) {
@@ -41,32 +37,63 @@ func callback(listener config_center.ConfigurationListener, _, group, dataId, da
 	metrics.Publish(metricsConfigCenter.NewIncMetricEvent(dataId, group, remoting.EventTypeUpdate, metricsConfigCenter.Nacos))
 }
 
-func (n *nacosDynamicConfiguration) addListener(key string, listener config_center.ConfigurationListener) {
-	_, loaded := n.keyListeners.Load(key)
-	if !loaded {
-		err := n.client.Client().ListenConfig(vo.ConfigParam{
-			DataId: key,
-			Group:  n.resolvedGroup(n.url.GetParam(constant.NacosGroupKey, constant2.DEFAULT_GROUP)),
-			OnChange: func(namespace, group, dataId, data string) {
-				go callback(listener, namespace, group, dataId, data)
-			},
-		})
-		if err != nil {
-			logger.Errorf("nacos : listen config fail, error:%v ", err)
-			return
-		}
-		_, cancel := context.WithCancel(context.Background())
-		newListener := make(map[config_center.ConfigurationListener]context.CancelFunc)
-		newListener[listener] = cancel
-		n.keyListeners.Store(key, newListener)
+// listenedKey returns the key of @dataId listened in @group
+func listenedKey(dataId, group string) string {
+	return group + constant.PathSeparator + dataId
+}
+
+func (n *nacosDynamicConfiguration) addListener(key string, listener config_center.ConfigurationListener, group string) {
+	n.listenerLock.Lock()
+	defer n.listenerLock.Unlock()
+	if listeners, ok := n.keyListeners[listenedKey(key, group)]; ok {
+		listeners[listener] = struct{}{}
+		return
+	}
+	// the client may be shared with the metadata reports, which listen the configs by their own
+	cancel, err := nacos.ListenConfig(n.client, vo.ConfigParam{
+		DataId: key,
+		Group:  group,
+		OnChange: func(namespace, group, dataId, data string) {
+			for _, l := range n.listenersOf(dataId, group) {
+				go callback(l, namespace, group, dataId, data)
+			}
+		},
+	})
+	if err != nil {
+		logger.Errorf("nacos : listen config fail, error:%v ", err)
 		return
 	}
+	n.keyListeners[listenedKey(key, group)] = map[config_center.ConfigurationListener]struct{}{listener: {}}
+	n.keyCancels[listenedKey(key, group)] = cancel
+}
 
-	// TODO check goroutine alive, but this version of go_nacos_sdk is not support.
-	logger.Infof("profile:%s. this profile is already listening", key)
+func (n *nacosDynamicConfiguration) removeListener(key string, listener config_center.ConfigurationListener, group string) {
+	n.listenerLock.Lock()
+	defer n.listenerLock.Unlock()
+	listeners, ok := n.keyListeners[listenedKey(key, group)]
+	if !ok {
+		return
+	}
+	delete(listeners, listener)
+	if len(listeners) > 0 {
+		return
+	}
+	// stop listening the config once it has no listener
+	delete(n.keyListeners, listenedKey(key, group))
+	cancel := n.keyCancels[listenedKey(key, group)]
+	delete(n.keyCancels, listenedKey(key, group))
+	if err := cancel(); err != nil {
+		logger.Errorf("nacos : cancel listen config fail, error:%v ", err)
+	}
 }
 
-func (n *nacosDynamicConfiguration) removeListener(key string, listener config_center.ConfigurationListener) {
-	// TODO: not supported in current go_nacos_sdk version
-	logger.Warn("not supported in current go_nacos_sdk version")
+// listenersOf returns the listeners of @dataId in @group
+func (n *nacosDynamicConfiguration) listenersOf(dataId, group string) []config_center.ConfigurationListener {
+	n.listenerLock.RLock()
+	defer n.listenerLock.RUnlock()
+	listeners := make([]config_center.ConfigurationListener, 0, len(n.keyListeners[listenedKey(dataId, group)]))
+	for l := range n.keyListeners[listenedKey(dataId, group)] {
+		listeners = append(listeners, l)
+	}
+	return listeners
 }
@@ -84,7 +84,8 @@ func (parser *DefaultConfigurationParser) Parse(content string) (map[string]stri
 
 // ParseToUrls is used to parse content to urls
 func (parser *DefaultConfigurationParser) ParseToUrls(content string) ([]*common.URL, error) {
-	config := ConfiguratorConfig{}
+	// a rule without the enabled field is enabled, as the rules written by dubbo-admin
+	config := ConfiguratorConfig{Enabled: true}
 	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
 		return nil, err
 	}
@@ -133,13 +134,13 @@ func serviceItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.UR
 		urlStr = urlStr + getEnabledString(item, config)
 		urlStr = urlStr + "&category="
 		urlStr = urlStr + constant.DynamicConfiguratorsCategory
-		urlStr = urlStr + "&configVersion="
+		urlStr = urlStr + "&" + constant.ConfigVersionKey + "="
 		urlStr = urlStr + config.ConfigVersion
 		apps := item.Applications
 		if len(apps) > 0 {
 			for _, v := range apps {
 				newUrlStr := urlStr
-				newUrlStr = newUrlStr + "&application"
+				newUrlStr = newUrlStr + "&application="
 				newUrlStr = newUrlStr + v
 				url, err := common.NewURL(newUrlStr)
 				if err != nil {
@@ -166,12 +167,12 @@ func appItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.URL, e
 	}
 	var urls []*common.URL
 	for _, v := range addresses {
-		urlStr := constant.OverrideProtocol + "://" + v + "/"
 		services := item.Services
 		if len(services) == 0 {
 			services = append(services, constant.AnyValue)
 		}
 		for _, vs := range services {
+			urlStr := constant.OverrideProtocol + "://" + v + "/"
 			serviceStr, err := getServiceString(vs)
 			if err != nil {
 				return nil, perrors.WithStack(err)
@@ -187,7 +188,7 @@ func appItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.URL, e
 			urlStr = urlStr + getEnabledString(item, config)
 			urlStr = urlStr + "&category="
 			urlStr = urlStr + constant.AppDynamicConfiguratorsCategory
-			urlStr = urlStr + "&configVersion="
+			urlStr = urlStr + "&" + constant.ConfigVersionKey + "="
 			urlStr = urlStr + config.ConfigVersion
 			url, err := common.NewURL(urlStr)
 			if err != nil {
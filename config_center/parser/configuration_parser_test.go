@@ -18,6 +18,8 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -25,6 +27,11 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
 func TestDefaultConfigurationParserParser(t *testing.T) {
 	parser := &DefaultConfigurationParser{}
 	m, err := parser.Parse("dubbo.registry.address=172.0.0.1\ndubbo.registry.name=test")
@@ -87,3 +94,94 @@ configs:
 	assert.Equal(t, "override", urls[0].Protocol)
 	assert.Equal(t, "0.0.0.0", urls[0].Location)
 }
+
+func parseFixture(t *testing.T, name string) ([]*common.URL, error) {
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	assert.NoError(t, err)
+	return (&DefaultConfigurationParser{}).ParseToUrls(string(content))
+}
+
+func TestParseToUrlsServiceNoApp(t *testing.T) {
+	urls, err := parseFixture(t, "ServiceNoApp.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "127.0.0.1:20880", urls[0].Location)
+	assert.Equal(t, "6666", urls[0].GetParam(constant.TimeoutKey, ""))
+	assert.Equal(t, "provider", urls[0].GetParam(constant.SideKey, ""))
+	assert.Equal(t, "true", urls[0].GetParam(constant.EnabledKey, ""))
+	assert.Equal(t, "v2.7", urls[0].GetParam(constant.ConfigVersionKey, ""))
+	assert.Equal(t, "127.0.0.1:20881", urls[1].Location)
+	assert.Equal(t, "222", urls[1].GetParam(constant.WeightKey, ""))
+}
+
+func TestParseToUrlsServiceMultiApps(t *testing.T) {
+	urls, err := parseFixture(t, "ServiceMultiApps.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 5)
+	assert.Equal(t, "127.0.0.1", urls[0].Location)
+	assert.Equal(t, "app1", urls[0].GetParam(constant.ApplicationKey, ""))
+	assert.Equal(t, "app2", urls[1].GetParam(constant.ApplicationKey, ""))
+	assert.Equal(t, "0.0.0.0", urls[2].Location)
+	assert.Equal(t, "127.0.0.1:20880", urls[0].GetParam(constant.OverrideProvidersKey, ""))
+	assert.Equal(t, "failfast", urls[0].GetParam(constant.ClusterKey, ""))
+	assert.Equal(t, "consumer", urls[0].GetParam(constant.SideKey, ""))
+	assert.Equal(t, "1", urls[4].GetParam(constant.WeightKey, ""))
+	assert.Equal(t, "provider", urls[4].GetParam(constant.SideKey, ""))
+}
+
+func TestParseToUrlsServiceNoRule(t *testing.T) {
+	_, err := parseFixture(t, "ServiceNoRule.yml")
+	assert.Error(t, err)
+}
+
+func TestParseToUrlsAppMultiServices(t *testing.T) {
+	urls, err := parseFixture(t, "AppMultiServices.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 4)
+	assert.Equal(t, "127.0.0.1", urls[0].Location)
+	assert.Equal(t, "/service1", urls[0].Path)
+	assert.Equal(t, "demo-consumer", urls[0].GetParam(constant.ApplicationKey, ""))
+	assert.Equal(t, "5000", urls[0].GetParam(constant.TimeoutKey, ""))
+	assert.Equal(t, "/service2", urls[1].Path)
+	assert.Equal(t, "group1", urls[1].GetParam(constant.GroupKey, ""))
+	assert.Equal(t, "1.0.0", urls[1].GetParam(constant.VersionKey, ""))
+	assert.Equal(t, "0.0.0.0", urls[2].Location)
+	assert.Equal(t, constant.AppDynamicConfiguratorsCategory, urls[0].GetParams()[constant.CategoryKey][1])
+}
+
+func TestParseToUrlsAppAnyServices(t *testing.T) {
+	urls, err := parseFixture(t, "AppAnyServices.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "/"+constant.AnyValue, urls[0].Path)
+	assert.Equal(t, "demo-consumer", urls[0].GetParam(constant.ApplicationKey, ""))
+	assert.Equal(t, "random", urls[1].GetParam(constant.LoadbalanceKey, ""))
+}
+
+func TestParseToUrlsConsumerSpecificProviders(t *testing.T) {
+	urls, err := parseFixture(t, "ConsumerSpecificProviders.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 1)
+	assert.Equal(t, "127.0.0.1:20880,127.0.0.1:20881", urls[0].GetParam(constant.OverrideProvidersKey, ""))
+	assert.Equal(t, "6666", urls[0].GetParam(constant.TimeoutKey, ""))
+}
+
+func TestParseToUrlsAdminRules(t *testing.T) {
+	// dubbo-admin omits the enabled field of the weight rule
+	urls, err := parseFixture(t, "AdminWeight.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 1)
+	assert.Equal(t, "192.168.1.1:20880", urls[0].Location)
+	assert.Equal(t, "200", urls[0].GetParam(constant.WeightKey, ""))
+	assert.Equal(t, "true", urls[0].GetParam(constant.EnabledKey, ""))
+
+	urls, err = parseFixture(t, "AdminDisable.yml")
+	assert.NoError(t, err)
+	assert.Len(t, urls, 1)
+	assert.Equal(t, "true", urls[0].GetParam(constant.DisabledKey, ""))
+	assert.Equal(t, "demo-provider", urls[0].GetParam(constant.ApplicationKey, ""))
+
+	urls, err = parseFixture(t, "DisabledRule.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", urls[0].GetParam(constant.EnabledKey, ""))
+}
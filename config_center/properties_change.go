@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config_center
+
+import (
+	"sort"
+)
+
+import (
+	"github.com/magiconair/properties"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// PropertyChange is the change of a property in the properties format content
+type PropertyChange struct {
+	Key      string
+	Value    string
+	OldValue string
+	// ChangeType is EventTypeAdd, EventTypeUpdate or EventTypeDel
+	ChangeType remoting.EventType
+}
+
+// DiffProperties returns the changes of the properties from @oldContent to @content in the order of the keys,
+// the listeners of properties format configs could apply only the properties changed.
+func DiffProperties(oldContent, content string) ([]PropertyChange, error) {
+	oldProperties, err := properties.LoadString(oldContent)
+	if err != nil {
+		return nil, perrors.WithMessage(err, "parse the old properties")
+	}
+	newProperties, err := properties.LoadString(content)
+	if err != nil {
+		return nil, perrors.WithMessage(err, "parse the properties")
+	}
+	oldMap, newMap := oldProperties.Map(), newProperties.Map()
+	var changes []PropertyChange
+	for key, value := range newMap {
+		oldValue, ok := oldMap[key]
+		switch {
+		case !ok:
+			changes = append(changes, PropertyChange{Key: key, Value: value, ChangeType: remoting.EventTypeAdd})
+		case oldValue != value:
+			changes = append(changes, PropertyChange{Key: key, Value: value, OldValue: oldValue,
+				ChangeType: remoting.EventTypeUpdate})
+		}
+	}
+	for key, oldValue := range oldMap {
+		if _, ok := newMap[key]; !ok {
+			changes = append(changes, PropertyChange{Key: key, OldValue: oldValue, ChangeType: remoting.EventTypeDel})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package config_center
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestDiffProperties(t *testing.T) {
+	changes, err := DiffProperties(`
+dubbo.consumer.check=true
+dubbo.consumer.timeout=3s
+dubbo.logger.level=info
+`, `
+dubbo.consumer.timeout=5s
+dubbo.logger.level=info
+dubbo.registry.address=zookeeper://127.0.0.1:2181
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []PropertyChange{
+		{Key: "dubbo.consumer.check", OldValue: "true", ChangeType: remoting.EventTypeDel},
+		{Key: "dubbo.consumer.timeout", Value: "5s", OldValue: "3s", ChangeType: remoting.EventTypeUpdate},
+		{Key: "dubbo.registry.address", Value: "zookeeper://127.0.0.1:2181", ChangeType: remoting.EventTypeAdd},
+	}, changes)
+
+	changes, err = DiffProperties("", "dubbo.logger.level=info")
+	assert.NoError(t, err)
+	assert.Equal(t, []PropertyChange{{Key: "dubbo.logger.level", Value: "info", ChangeType: remoting.EventTypeAdd}}, changes)
+
+	changes, err = DiffProperties("dubbo.logger.level=info", "dubbo.logger.level = info")
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = DiffProperties("", "dubbo.logger.level=${dubbo.logger.level}")
+	assert.Error(t, err)
+}
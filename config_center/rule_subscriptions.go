@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// RuleSubscriptions records the rule keys subscribed from the config center by a governance listener, so each
+// rule is subscribed only once. The zero value is ready to use.
+type RuleSubscriptions struct {
+	keys sync.Map
+}
+
+// Subscribed returns whether the rule of @key is subscribed
+func (s *RuleSubscriptions) Subscribed(key string) bool {
+	_, ok := s.keys.Load(key)
+	return ok
+}
+
+// Add records the rule of @key as subscribed, it returns false if the rule is subscribed already
+func (s *RuleSubscriptions) Add(key string) bool {
+	_, loaded := s.keys.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// Subscribe adds @listener of the rule of @key to @dynamicConfiguration and passes the current rule to it
+// immediately, the rule is regarded as absent if the query fails. The changes are coalesced in @window if it
+// is positive, see CoalescingListener. Nothing is subscribed if @dynamicConfiguration is nil, so the rule could
+// be subscribed again once the config center starts. It returns false if nothing is subscribed.
+func (s *RuleSubscriptions) Subscribe(dynamicConfiguration DynamicConfiguration, key string,
+	listener ConfigurationListener, window time.Duration) bool {
+	if dynamicConfiguration == nil || !s.Add(key) {
+		return false
+	}
+	if window > 0 {
+		dynamicConfiguration.AddListener(key, NewCoalescingListener(listener, window))
+	} else {
+		dynamicConfiguration.AddListener(key, listener)
+	}
+	value, err := dynamicConfiguration.GetRule(key)
+	if err != nil {
+		logger.Errorf("query rule fail,key=%s,err=%v", key, err)
+		value = ""
+	}
+	listener.Process(&ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
+	return true
+}
+
+// ServiceRuleKey returns the key of the rule of the service of @url, in the form of service:version:group followed by @suffix
+func ServiceRuleKey(url *common.URL, suffix string) string {
+	return strings.Join([]string{url.Service(), url.GetParam(constant.VersionKey, ""),
+		url.GetParam(constant.GroupKey, "")}, ":") + suffix
+}
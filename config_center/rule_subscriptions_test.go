@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// ruleConfiguration serves the rules of the default group, or fails to query them if err is set
+type ruleConfiguration struct {
+	*groupConfiguration
+	err error
+}
+
+func (c *ruleConfiguration) GetRule(key string, opts ...Option) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.GetProperties(key, opts...)
+}
+
+func TestRuleSubscriptions(t *testing.T) {
+	subscriptions := &RuleSubscriptions{}
+	listener := &recordingListener{}
+	assert.False(t, subscriptions.Subscribe(nil, "rule", listener, 0))
+	assert.False(t, subscriptions.Subscribed("rule"))
+
+	dynamicConfiguration := &ruleConfiguration{groupConfiguration: newGroupConfiguration()}
+	dynamicConfiguration.configs[DefaultGroup+"/rule"] = "v1"
+	assert.True(t, subscriptions.Subscribe(dynamicConfiguration, "rule", listener, 0))
+	assert.True(t, subscriptions.Subscribed("rule"))
+	assert.False(t, subscriptions.Subscribe(dynamicConfiguration, "rule", listener, 0))
+	assert.Len(t, dynamicConfiguration.listeners[DefaultGroup+"/rule"], 1)
+
+	dynamicConfiguration.publish(DefaultGroup, "rule", "v2")
+	events := listener.received()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "v1", events[0].Value)
+	assert.Equal(t, "v2", events[1].Value)
+
+	// the rule is regarded as absent if the query fails
+	dynamicConfiguration.err = errors.New("timeout")
+	assert.True(t, subscriptions.Subscribe(dynamicConfiguration, "other", listener, DefaultCoalescingWindow))
+	events = listener.received()
+	assert.Len(t, events, 3)
+	assert.Equal(t, "other", events[2].Key)
+	assert.Equal(t, "", events[2].Value)
+	assert.IsType(t, &CoalescingListener{}, dynamicConfiguration.listeners[DefaultGroup+"/other"][0])
+
+	assert.False(t, subscriptions.Add("rule"))
+	assert.True(t, subscriptions.Add("local"))
+}
+
+func TestServiceRuleKey(t *testing.T) {
+	url, err := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=groupA&version=1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "com.ikurento.user.UserProvider:1.0.0:groupA.degradation", ServiceRuleKey(url, ".degradation"))
+
+	url, err = common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	assert.Equal(t, "com.ikurento.user.UserProvider::.degradation", ServiceRuleKey(url, ".degradation"))
+}
@@ -19,6 +19,7 @@ package zookeeper
 
 import (
 	"encoding/base64"
+	"errors"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,11 +41,14 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 	"dubbo.apache.org/dubbo-go/v3/remoting/zookeeper"
 )
 
 const (
 	pathSeparator = "/"
+	// maxPublishRetries is the max times to set the value of a node modified by others concurrently
+	maxPublishRetries = 3
 )
 
 type zookeeperDynamicConfiguration struct {
@@ -100,12 +104,9 @@ func newZookeeperDynamicConfiguration(url *common.URL) (*zookeeperDynamicConfigu
 	return c, nil
 }
 
-// AddListener add listener for key
-// TODO this method should has a parameter 'group', and it does not now, so we should concat group and key with '/' manually
+// AddListener add listener for key, the group in @options, the namespace of the config center by default
 func (c *zookeeperDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, options ...config_center.Option) {
-	key = strings.Join([]string{c.GetURL().GetParam(constant.ConfigNamespaceKey, config_center.DefaultGroup), key}, "/")
-	qualifiedKey := buildPath(c.rootPath, key)
-	c.cacheListener.AddListener(qualifiedKey, listener)
+	c.cacheListener.AddListener(c.listenedPath(key, options...), listener)
 }
 
 // buildPath build path and format
@@ -119,7 +120,20 @@ func buildPath(rootPath, subPath string) string {
 }
 
 func (c *zookeeperDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, opions ...config_center.Option) {
-	c.cacheListener.RemoveListener(key, listener)
+	c.cacheListener.RemoveListener(c.listenedPath(key, opions...), listener)
+}
+
+// listenedPath returns the path of @key listened in the group of @opts
+func (c *zookeeperDynamicConfiguration) listenedPath(key string, opts ...config_center.Option) string {
+	tmpOpts := &config_center.Options{}
+	for _, opt := range opts {
+		opt(tmpOpts)
+	}
+	group := tmpOpts.Group
+	if len(group) == 0 {
+		group = c.GetURL().GetParam(constant.ConfigNamespaceKey, config_center.DefaultGroup)
+	}
+	return buildPath(c.rootPath, group+pathSeparator+key)
 }
 
 func (c *zookeeperDynamicConfiguration) GetProperties(key string, opts ...config_center.Option) (string, error) {
@@ -137,6 +151,9 @@ func (c *zookeeperDynamicConfiguration) GetProperties(key string, opts ...config
 		key = c.GetURL().GetParam(constant.ConfigNamespaceKey, config_center.DefaultGroup) + "/" + key
 	}
 	content, _, err := c.client.GetContent(c.rootPath + "/" + key)
+	if errors.Is(err, zk.ErrNoAuth) {
+		return "", perrors.WithMessagef(remoting.ErrAuthFailed, "read %s as %q: %v", key, c.GetURL().Username, err)
+	}
 	if err != nil {
 		return "", perrors.WithStack(err)
 	}
@@ -163,22 +180,29 @@ func (c *zookeeperDynamicConfiguration) PublishConfig(key string, group string,
 	if c.base64Enabled {
 		valueBytes = []byte(base64.StdEncoding.EncodeToString(valueBytes))
 	}
-	// FIXME this method need to be fixed, because it will recursively
-	// create every node in the path with given value which we may not expected.
+	// the parents are created with empty value if absent
 	err := c.client.CreateWithValue(path, valueBytes)
-	if err != nil {
-		// try update value if node already exists
-		if perrors.Is(err, zk.ErrNodeExists) {
-			_, stat, _ := c.client.GetContent(path)
-			_, setErr := c.client.SetContent(path, valueBytes, stat.Version)
-			if setErr != nil {
-				return perrors.WithStack(setErr)
-			}
+	if err == nil {
+		return nil
+	}
+	if !perrors.Is(err, zk.ErrNodeExists) {
+		return perrors.WithStack(err)
+	}
+	// update the value if the node already exists, retry if it is modified by others meanwhile
+	for i := 0; i < maxPublishRetries; i++ {
+		_, stat, err := c.client.GetContent(path)
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		_, err = c.client.SetContent(path, valueBytes, stat.Version)
+		if err == nil {
 			return nil
 		}
-		return perrors.WithStack(err)
+		if !perrors.Is(err, zk.ErrBadVersion) {
+			return perrors.WithStack(err)
+		}
 	}
-	return nil
+	return perrors.Errorf("publish config to %s failed, it is modified by others concurrently", path)
 }
 
 // RemoveConfig will remove the config with the (key, group) pair
@@ -267,7 +291,7 @@ func (c *zookeeperDynamicConfiguration) closeConfigs() {
 	logger.Infof("begin to close provider zk client")
 	c.cltLock.Lock()
 	defer c.cltLock.Unlock()
-	c.client.Close()
+	zookeeper.ReleaseZookeeperClient(c.client)
 	c.client = nil
 }
 
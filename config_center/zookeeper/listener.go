@@ -34,7 +34,9 @@ import (
 // CacheListener defines keyListeners and rootPath
 type CacheListener struct {
 	// key is zkNode Path and value is set of listeners
-	keyListeners    sync.Map
+	keyListeners sync.Map
+	// values are the contents last seen, keyed by zkNode Path
+	values          sync.Map
 	zkEventListener *zookeeper.ZkEventListener
 	rootPath        string
 }
@@ -77,11 +79,19 @@ func (l *CacheListener) DataChange(event remoting.Event) bool {
 
 	key, group := l.pathToKeyGroup(event.Path)
 	defer metrics.Publish(metricsConfigCenter.NewIncMetricEvent(key, group, changeType, metricsConfigCenter.Zookeeper))
+	var oldValue interface{}
+	if changeType == remoting.EventTypeDel {
+		oldValue, _ = l.values.LoadAndDelete(event.Path)
+	} else {
+		oldValue, _ = l.values.Load(event.Path)
+		l.values.Store(event.Path, event.Content)
+	}
 	if listeners, ok := l.keyListeners.Load(event.Path); ok {
 		for listener := range listeners.(map[config_center.ConfigurationListener]struct{}) {
 			listener.Process(&config_center.ConfigChangeEvent{
 				Key:        key,
 				Value:      event.Content,
+				OldValue:   oldValue,
 				ConfigType: changeType,
 			})
 		}
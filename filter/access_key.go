@@ -34,6 +34,8 @@ type AccessKeyPair struct {
 
 // AccessKeyStorage is the interface which supports us to store our AccessKeyPair or
 // load AccessKeyPair from other storage, such as filesystem.
+// On the provider side, the access key of the consumer is in the "ak" attachment of the invocation,
+// so that the storage can resolve the secret key of each consumer.
 type AccessKeyStorage interface {
 	GetAccessKeyPair(protocol.Invocation, *common.URL) *AccessKeyPair
 }
@@ -20,8 +20,8 @@ package accesslog
 
 import (
 	"context"
-	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,12 +29,16 @@ import (
 
 import (
 	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/masking"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -55,6 +59,12 @@ const (
 	Types = "types"
 	// nolint
 	Arguments = "arguments"
+
+	// StatusOK is the status of the invocations without error
+	StatusOK = "OK"
+
+	// flushTick is the period to check whether the access log files need to be flushed
+	flushTick = 100 * time.Millisecond
 )
 
 var (
@@ -77,66 +87,136 @@ func init() {
  *   interface : "com.ikurento.user.UserProvider"
  *   ... # other configuration
  *   accesslog: "/your/path/to/store/the/log/", # it should be the path of file.
+ *   params:
+ *     accesslog.format: "%{time} %{remote.ip} %{service} %{method} %{rt} %{status} %{attachment.traceId}"
  *
  * the value of "accesslog" can be "true" or "default" too.
  * If the value is one of them, the access log will be record in log file which defined in log.yml
+ * The logs are written into the file asynchronously, they are buffered and flushed by accesslog.flush.size
+ * and accesslog.flush.interval, and the file is rotated daily, or by size if accesslog.rotate is size.
+ * The logs are dropped once the channel is full, and the dropped count is reported periodically.
+ * The values of the fields matching masking.fields and masking.partial.fields in the logged arguments and result
+ * are masked, the invocation itself is untouched.
  * AccessLogFilter is designed to be singleton
  */
 type Filter struct {
 	logChan chan Data
+	// writers are the writers of the access log files, they are only accessed by the log goroutine
+	writers  map[string]*logWriter
+	dropped  atomic.Uint64
+	reported uint64
 }
 
 func newFilter() filter.Filter {
 	if accessLogFilter == nil {
 		once.Do(func() {
-			accessLogFilter = &Filter{logChan: make(chan Data, LogMaxBuffer)}
-			go func() {
-				for accessLogData := range accessLogFilter.logChan {
-					accessLogFilter.writeLogToFile(accessLogData)
-				}
-			}()
+			accessLogFilter = newAccessLogFilter(LogMaxBuffer)
 		})
 	}
 	return accessLogFilter
 }
 
+// newAccessLogFilter creates a filter whose channel buffers @size logs at most, and starts its log goroutine
+func newAccessLogFilter(size int) *Filter {
+	f := &Filter{logChan: make(chan Data, size)}
+	go f.run()
+	return f
+}
+
+// run writes the logs in the channel, and flushes the files periodically until the channel is closed
+func (f *Filter) run() {
+	ticker := time.NewTicker(flushTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case accessLogData, ok := <-f.logChan:
+			if !ok {
+				f.closeWriters()
+				return
+			}
+			f.writeLogToFile(accessLogData)
+		case now := <-ticker.C:
+			for accessLog, w := range f.writers {
+				if err := w.flushIfDue(now); err != nil {
+					logger.Warnf("Can not write the log into access log file: %s, %v", accessLog, err)
+				}
+			}
+			f.reportDropped()
+		}
+	}
+}
+
 // Invoke will check whether user wants to use this filter.
 // If we find the value of key constant.AccessLogFilterKey, we will log the invocation info
 func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
-	accessLog := invoker.GetURL().GetParam(constant.AccessLogFilterKey, "")
+	url := invoker.GetURL()
+	accessLog := url.GetParam(constant.AccessLogFilterKey, "")
 
 	// the user do not
-	if len(accessLog) > 0 {
-		accessLogData := Data{data: f.buildAccessLogData(invoker, invocation), accessLog: accessLog}
-		f.logIntoChannel(accessLogData)
+	if len(accessLog) == 0 {
+		return invoker.Invoke(ctx, invocation)
 	}
-	return invoker.Invoke(ctx, invocation)
+
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	accessLogData := Data{data: f.buildAccessLogData(invoker, invocation), accessLog: accessLog, url: url}
+	masker := masking.FromURL(url)
+	if template := url.GetParam(constant.AccessLogFormatKey, ""); len(template) > 0 {
+		accessLogData.format = getFormat(template)
+		f.buildFormatData(accessLogData.data, accessLogData.format, invocation, start, result, masker)
+	}
+	// the arguments are walked and masked only if they are logged
+	if accessLogData.format == nil || accessLogData.format.arguments {
+		f.buildArgumentsData(accessLogData.data, invocation, masker)
+	}
+	f.logIntoChannel(accessLogData)
+	return result
 }
 
-// logIntoChannel won't block the invocation
+// logIntoChannel won't block the invocation, the log is dropped and counted if the channel is full
 func (f *Filter) logIntoChannel(accessLogData Data) {
 	select {
 	case f.logChan <- accessLogData:
 		return
 	default:
-		logger.Warn("The channel is full and the access logIntoChannel data will be dropped")
+		if f.dropped.Inc() == 1 {
+			logger.Warn("The channel is full and the access logIntoChannel data will be dropped")
+		}
 		return
 	}
 }
 
+// Dropped returns the count of the access logs dropped since the filter is created
+func (f *Filter) Dropped() uint64 {
+	return f.dropped.Load()
+}
+
+// reportDropped logs the count of the access logs dropped since the last report
+func (f *Filter) reportDropped() {
+	if dropped := f.dropped.Load(); dropped > f.reported {
+		logger.Warnf("%d access logs were dropped as the channel is full, %d in total", dropped-f.reported, dropped)
+		f.reported = dropped
+	}
+}
+
 // buildAccessLogData builds the access log data
-func (f *Filter) buildAccessLogData(_ protocol.Invoker, invocation protocol.Invocation) map[string]string {
+func (f *Filter) buildAccessLogData(invoker protocol.Invoker, invocation protocol.Invocation) map[string]string {
 	dataMap := make(map[string]string, 16)
 	attachments := invocation.Attachments()
 	itf := attachments[constant.InterfaceKey]
 	if itf == nil || len(itf.(string)) == 0 {
 		itf = attachments[constant.PathKey]
 	}
+	if itf == nil || len(itf.(string)) == 0 {
+		itf = invoker.GetURL().Service()
+	}
 	if itf != nil {
 		dataMap[constant.InterfaceKey] = itf.(string)
 	}
 	if v, ok := attachments[constant.MethodKey]; ok && v != nil {
 		dataMap[constant.MethodKey] = v.(string)
+	} else {
+		dataMap[constant.MethodKey] = invocation.MethodName()
 	}
 	if v, ok := attachments[constant.VersionKey]; ok && v != nil {
 		dataMap[constant.VersionKey] = v.(string)
@@ -153,27 +233,51 @@ func (f *Filter) buildAccessLogData(_ protocol.Invoker, invocation protocol.Invo
 	if v, ok := attachments[constant.RemoteAddr]; ok && v != nil {
 		dataMap[constant.RemoteAddr] = v.(string)
 	}
+	return dataMap
+}
 
-	if len(invocation.Arguments()) > 0 {
-		builder := strings.Builder{}
-		// todo(after the paramTypes were set to the invocation. we should change this implementation)
-		typeBuilder := strings.Builder{}
-
-		builder.WriteString(reflect.ValueOf(invocation.Arguments()[0]).String())
-		typeBuilder.WriteString(reflect.TypeOf(invocation.Arguments()[0]).Name())
-		for idx := 1; idx < len(invocation.Arguments()); idx++ {
-			arg := invocation.Arguments()[idx]
+// buildArgumentsData adds the types and the arguments masked by @masker into @dataMap,
+// the arguments of the invocation are untouched
+func (f *Filter) buildArgumentsData(dataMap map[string]string, invocation protocol.Invocation, masker *masking.Masker) {
+	if len(invocation.Arguments()) == 0 {
+		return
+	}
+	builder := strings.Builder{}
+	// todo(after the paramTypes were set to the invocation. we should change this implementation)
+	typeBuilder := strings.Builder{}
+	for idx, arg := range invocation.Arguments() {
+		if idx > 0 {
 			builder.WriteString(",")
-			builder.WriteString(reflect.ValueOf(arg).String())
-
 			typeBuilder.WriteString(",")
+		}
+		builder.WriteString(masker.String(arg))
+		if arg != nil {
 			typeBuilder.WriteString(reflect.TypeOf(arg).Name())
 		}
-		dataMap[Arguments] = builder.String()
-		dataMap[Types] = typeBuilder.String()
 	}
+	dataMap[Arguments] = builder.String()
+	dataMap[Types] = typeBuilder.String()
+}
 
-	return dataMap
+// buildFormatData adds the fields only rendered by the format template into @dataMap,
+// the result is masked by @masker if it is rendered
+func (f *Filter) buildFormatData(dataMap map[string]string, format *format, invocation protocol.Invocation,
+	start time.Time, result protocol.Result, masker *masking.Masker) {
+	dataMap[TokenTime] = start.Format(MessageDateLayout)
+	dataMap[TokenRT] = strconv.FormatInt(time.Since(start).Milliseconds(), 10)
+	dataMap[TokenStatus] = StatusOK
+	if result != nil && result.Error() != nil {
+		dataMap[TokenStatus] = common.CodeOf(result.Error()).String()
+		dataMap[TokenError] = result.Error().Error()
+	}
+	if format.result && result != nil && result.Result() != nil {
+		dataMap[TokenResult] = masker.String(result.Result())
+	}
+	for _, key := range format.attachments {
+		if v, ok := invocation.GetAttachment(key); ok {
+			dataMap[TokenAttachment+key] = v
+		}
+	}
 }
 
 // OnResponse do nothing
@@ -189,54 +293,27 @@ func (f *Filter) writeLogToFile(data Data) {
 		return
 	}
 
-	logFile, err := f.openLogFile(accessLog)
-	if err != nil {
-		logger.Warnf("Can not open the access log file: %s, %v", accessLog, err)
-		return
+	if f.writers == nil {
+		f.writers = make(map[string]*logWriter)
+	}
+	w, ok := f.writers[accessLog]
+	if !ok {
+		w = newLogWriter(accessLog, data.url)
+		f.writers[accessLog] = w
 	}
-	logger.Debugf("Append log to %s", accessLog)
-	message := data.toLogMessage()
-	message = message + "\n"
-	_, err = logFile.WriteString(message)
-	if err != nil {
+	if err := w.write(data.toLogMessage()); err != nil {
 		logger.Warnf("Can not write the log into access log file: %s, %v", accessLog, err)
 	}
 }
 
-// openLogFile will open the log file with append mode.
-// If the file is not found, it will create the file.
-// Actually, the accessLog is the filename
-// You may find out that, once we want to write access log into log file,
-// we open the file again and again.
-// It needs to be optimized.
-func (f *Filter) openLogFile(accessLog string) (*os.File, error) {
-	logFile, err := os.OpenFile(accessLog, os.O_CREATE|os.O_APPEND|os.O_RDWR, LogFileMode)
-	if err != nil {
-		logger.Warnf("Can not open the access log file: %s, %v", accessLog, err)
-		return nil, err
-	}
-	now := time.Now().Format(FileDateFormat)
-	fileInfo, err := logFile.Stat()
-	if err != nil {
-		logger.Warnf("Can not get the info of access log file: %s, %v", accessLog, err)
-		return nil, err
-	}
-	last := fileInfo.ModTime().Format(FileDateFormat)
-
-	// this is confused.
-	// for example, if the last = '2020-03-04'
-	// and today is '2020-03-05'
-	// we will create one new file to log access data
-	// By this way, we can split the access log based on days.
-	if now != last {
-		err = os.Rename(fileInfo.Name(), fileInfo.Name()+"."+now)
-		if err != nil {
-			logger.Warnf("Can not rename access log file: %s, %v", fileInfo.Name(), err)
-			return nil, err
+// closeWriters flushes and closes all the access log files
+func (f *Filter) closeWriters() {
+	for accessLog, w := range f.writers {
+		if err := w.close(); err != nil {
+			logger.Warnf("Can not close the access log file: %s, %v", accessLog, err)
 		}
-		logFile, err = os.OpenFile(accessLog, os.O_CREATE|os.O_APPEND|os.O_RDWR, LogFileMode)
 	}
-	return logFile, err
+	f.writers = nil
 }
 
 // isDefault check whether accessLog == true or accessLog == default
@@ -248,10 +325,16 @@ func isDefault(accessLog string) bool {
 type Data struct {
 	accessLog string
 	data      map[string]string
+	url       *common.URL
+	// format is nil if no template is configured, the default format is used then
+	format *format
 }
 
 // toLogMessage convert the Data to String
 func (d *Data) toLogMessage() string {
+	if d.format != nil {
+		return d.format.render(d.data)
+	}
 	builder := strings.Builder{}
 	builder.WriteString("[")
 	builder.WriteString(d.data[constant.TimestampKey])
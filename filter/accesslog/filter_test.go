@@ -19,7 +19,12 @@ package accesslog
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 import (
@@ -81,3 +86,94 @@ func TestFilterOnResponse(t *testing.T) {
 	response := accessLogFilter.OnResponse(context.TODO(), result, nil, nil)
 	assert.Equal(t, result, response)
 }
+
+func TestFilterInvokeFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&accesslog=" + path + "&accesslog.flush.interval=10ms")
+	url.SetParam(constant.AccessLogFormatKey, "%{remote.ip} %{service} %{method} %{status} %{attachment.traceId}")
+	invoker := protocol.NewBaseInvoker(url)
+
+	attach := map[string]interface{}{constant.RemoteAddr: "10.0.0.1:54321", "traceId": "abc"}
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"A001"}, attach)
+
+	accessLogFilter := newAccessLogFilter(10)
+	defer close(accessLogFilter.logChan)
+	result := accessLogFilter.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, result.Error())
+
+	assert.Eventually(t, func() bool {
+		content, _ := ioutil.ReadFile(path)
+		return string(content) == "10.0.0.1 com.ikurento.user.UserProvider GetUser OK abc\n"
+	}, time.Second, 10*time.Millisecond)
+}
+
+type user struct {
+	Name     string
+	Password string
+	Phone    string
+}
+
+func TestFilterInvokeMasking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&accesslog=" + path + "&accesslog.flush.interval=10ms&masking.partial.fields=phone")
+	url.SetParam(constant.AccessLogFormatKey, "%{method} %{args} %{result}")
+	invoker := &resultInvoker{BaseInvoker: protocol.NewBaseInvoker(url),
+		result: &user{Name: "Alex", Password: "secret", Phone: "13800001234"}}
+
+	arg := &user{Name: "Alex", Password: "123456", Phone: "13800001234"}
+	inv := invocation.NewRPCInvocation("Login", []interface{}{arg}, nil)
+
+	accessLogFilter := newAccessLogFilter(10)
+	defer close(accessLogFilter.logChan)
+	result := accessLogFilter.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, result.Error())
+	assert.Equal(t, "123456", arg.Password)
+	assert.Equal(t, "secret", result.Result().(*user).Password)
+
+	masked := `{"Name":"Alex","Password":"******","Phone":"*******1234"}`
+	assert.Eventually(t, func() bool {
+		content, _ := ioutil.ReadFile(path)
+		return string(content) == "Login "+masked+" "+masked+"\n"
+	}, time.Second, 10*time.Millisecond)
+}
+
+type resultInvoker struct {
+	*protocol.BaseInvoker
+	result interface{}
+}
+
+func (i *resultInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Rest: i.result}
+}
+
+func TestFilterBuildFormatData(t *testing.T) {
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"traceId": "abc"})
+	start := time.Now().Add(-time.Second)
+	data := make(map[string]string)
+	accessLogFilter := &Filter{}
+	accessLogFilter.buildFormatData(data, parseFormat("%{attachment.traceId}"), inv, start,
+		&protocol.RPCResult{Err: common.NewRPCError(common.StatusTimeout, errors.New("read timeout"))}, nil)
+
+	assert.Equal(t, start.Format(MessageDateLayout), data[TokenTime])
+	assert.True(t, strings.HasPrefix(data[TokenRT], "10"))
+	assert.Equal(t, "timeout", data[TokenStatus])
+	assert.Equal(t, "read timeout", data[TokenError])
+	assert.Equal(t, "abc", data["attachment.traceId"])
+}
+
+func TestFilterDropped(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?accesslog=true")
+	invoker := protocol.NewBaseInvoker(url)
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+
+	// the channel is never consumed
+	accessLogFilter := &Filter{logChan: make(chan Data, 2)}
+	for i := 0; i < 5; i++ {
+		accessLogFilter.Invoke(context.Background(), invoker, inv)
+	}
+	assert.Equal(t, uint64(3), accessLogFilter.Dropped())
+	accessLogFilter.reportDropped()
+	assert.Equal(t, uint64(3), accessLogFilter.reported)
+}
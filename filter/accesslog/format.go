@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// the tokens supported by the access log format, e.g.
+// "%{time} %{remote.ip} %{service} %{method} %{rt} %{status} %{attachment.traceId}".
+// The sensitive fields in %{args} and %{result} are masked by masking.fields and masking.partial.fields.
+const (
+	TokenTime       = "time"
+	TokenRemote     = "remote"
+	TokenRemoteIP   = "remote.ip"
+	TokenLocal      = "local"
+	TokenService    = "service"
+	TokenGroup      = "group"
+	TokenVersion    = "version"
+	TokenMethod     = "method"
+	TokenTypes      = "types"
+	TokenArguments  = "args"
+	TokenRT         = "rt"
+	TokenStatus     = "status"
+	TokenError      = "error"
+	TokenResult     = "result"
+	TokenAttachment = "attachment."
+)
+
+var formats sync.Map // the format template -> *format
+
+// format is a parsed access log format template, which is made up of literals and %{token}s
+type format struct {
+	segments []segment
+	// attachments are the keys of the attachments referred by the template
+	attachments []string
+	// arguments and result show whether the arguments or the result are rendered, they are built only if so
+	arguments bool
+	result    bool
+}
+
+type segment struct {
+	literal string
+	token   string
+}
+
+// getFormat returns the parsed format of @template, the templates are parsed only once
+func getFormat(template string) *format {
+	if f, ok := formats.Load(template); ok {
+		return f.(*format)
+	}
+	f, _ := formats.LoadOrStore(template, parseFormat(template))
+	return f.(*format)
+}
+
+// parseFormat parses @template, an unclosed %{ is kept as a literal
+func parseFormat(template string) *format {
+	f := &format{}
+	for len(template) > 0 {
+		start := strings.Index(template, "%{")
+		if start < 0 {
+			f.segments = append(f.segments, segment{literal: template})
+			break
+		}
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			f.segments = append(f.segments, segment{literal: template})
+			break
+		}
+		if start > 0 {
+			f.segments = append(f.segments, segment{literal: template[:start]})
+		}
+		token := strings.TrimSpace(template[start+2 : start+end])
+		f.segments = append(f.segments, segment{token: token})
+		switch {
+		case strings.HasPrefix(token, TokenAttachment):
+			f.attachments = append(f.attachments, strings.TrimPrefix(token, TokenAttachment))
+		case token == TokenArguments || token == TokenTypes:
+			f.arguments = true
+		case token == TokenResult:
+			f.result = true
+		}
+		template = template[start+end+1:]
+	}
+	return f
+}
+
+// render renders the access log @data with the format, the unknown tokens and the absent fields are rendered as "-"
+func (f *format) render(data map[string]string) string {
+	builder := strings.Builder{}
+	for _, s := range f.segments {
+		if s.token == "" {
+			builder.WriteString(s.literal)
+			continue
+		}
+		v := tokenValue(s.token, data)
+		if len(v) == 0 {
+			v = "-"
+		}
+		builder.WriteString(v)
+	}
+	return builder.String()
+}
+
+func tokenValue(token string, data map[string]string) string {
+	switch token {
+	case TokenRemoteIP:
+		return hostOf(data[constant.RemoteAddr])
+	case TokenRemote:
+		return data[constant.RemoteAddr]
+	case TokenLocal:
+		return data[constant.LocalAddr]
+	case TokenService:
+		return data[constant.InterfaceKey]
+	case TokenGroup:
+		return data[constant.GroupKey]
+	case TokenVersion:
+		return data[constant.VersionKey]
+	case TokenMethod:
+		return data[constant.MethodKey]
+	case TokenTypes:
+		return data[Types]
+	case TokenArguments:
+		return data[Arguments]
+	case TokenTime, TokenRT, TokenStatus, TokenError, TokenResult:
+		return data[token]
+	}
+	if strings.HasPrefix(token, TokenAttachment) {
+		return data[token]
+	}
+	return ""
+}
+
+func hostOf(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestParseFormat(t *testing.T) {
+	f := parseFormat("[%{time}] %{ method }(%{attachment.traceId}) %{attachment.userId}")
+	assert.Equal(t, []segment{
+		{literal: "["}, {token: TokenTime}, {literal: "] "}, {token: TokenMethod}, {literal: "("},
+		{token: "attachment.traceId"}, {literal: ") "}, {token: "attachment.userId"},
+	}, f.segments)
+	assert.Equal(t, []string{"traceId", "userId"}, f.attachments)
+
+	// an unclosed token is kept as a literal
+	f = parseFormat("%{method} %{rt")
+	assert.Equal(t, []segment{{token: TokenMethod}, {literal: " %{rt"}}, f.segments)
+
+	assert.Same(t, getFormat("%{method}"), getFormat("%{method}"))
+}
+
+func TestFormatRender(t *testing.T) {
+	data := map[string]string{
+		TokenTime:             "2022-01-02 15:04:05",
+		constant.RemoteAddr:   "10.0.0.1:54321",
+		constant.LocalAddr:    "10.0.0.2:20000",
+		constant.InterfaceKey: "com.ikurento.user.UserProvider",
+		constant.GroupKey:     "g1",
+		constant.VersionKey:   "1.0.0",
+		constant.MethodKey:    "GetUser",
+		Types:                 "string,int",
+		Arguments:             "A001,1",
+		TokenRT:               "12",
+		TokenStatus:           "timeout",
+		TokenError:            "deadline exceeded",
+		"attachment.traceId":  "abc",
+	}
+	tests := map[string]string{
+		"%{time}":               "2022-01-02 15:04:05",
+		"%{remote}":             "10.0.0.1:54321",
+		"%{remote.ip}":          "10.0.0.1",
+		"%{local}":              "10.0.0.2:20000",
+		"%{service}":            "com.ikurento.user.UserProvider",
+		"%{group}":              "g1",
+		"%{version}":            "1.0.0",
+		"%{method}":             "GetUser",
+		"%{types}":              "string,int",
+		"%{args}":               "A001,1",
+		"%{rt}":                 "12",
+		"%{status}":             "timeout",
+		"%{error}":              "deadline exceeded",
+		"%{attachment.traceId}": "abc",
+		// the absent fields and the unknown tokens are rendered as "-"
+		"%{attachment.userId}": "-",
+		"%{unknown}":           "-",
+		"%{time} %{remote.ip} %{service} %{method} %{rt} %{status} %{attachment.traceId}": "2022-01-02 15:04:05 10.0.0.1 com.ikurento.user.UserProvider GetUser 12 timeout abc",
+	}
+	for template, expected := range tests {
+		assert.Equal(t, expected, parseFormat(template).render(data), template)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+)
+
+import (
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const rotateBySize = "size"
+
+// logWriter buffers the access logs written into a file, and flushes them once the buffer exceeds the flush size
+// or the flush interval elapses. The logs are only written by the log goroutine of the filter, so it is not
+// thread safe.
+type logWriter struct {
+	out           io.WriteCloser
+	buf           bytes.Buffer
+	flushSize     int
+	flushInterval time.Duration
+	lastFlush     time.Time
+}
+
+// newLogWriter creates the writer of the access log file @path with the config of @url. The file is rotated
+// daily by default, or by size through lumberjack if accesslog.rotate is size.
+func newLogWriter(path string, url *common.URL) *logWriter {
+	var out io.WriteCloser
+	if url.GetParam(constant.AccessLogRotateKey, "") == rotateBySize {
+		out = &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    url.GetParamByIntValue(constant.AccessLogMaxSizeKey, constant.DefaultAccessLogMaxSize),
+			MaxBackups: url.GetParamByIntValue(constant.AccessLogMaxBackupsKey, constant.DefaultAccessLogMaxBackups),
+			MaxAge:     url.GetParamByIntValue(constant.AccessLogMaxAgeKey, constant.DefaultAccessLogMaxAge),
+			LocalTime:  true,
+		}
+	} else {
+		out = &dailyFile{path: path}
+	}
+	return &logWriter{
+		out:           out,
+		flushSize:     url.GetParamByIntValue(constant.AccessLogFlushSizeKey, constant.DefaultAccessLogFlushSize),
+		flushInterval: url.GetParamDurationWithDefault(constant.AccessLogFlushIntervalKey, constant.DefaultAccessLogFlushInterval),
+		lastFlush:     time.Now(),
+	}
+}
+
+// write appends @message as a line into the buffer, and flushes the buffer if it exceeds the flush size
+func (w *logWriter) write(message string) error {
+	w.buf.WriteString(message)
+	w.buf.WriteByte('\n')
+	if w.buf.Len() >= w.flushSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// flushIfDue flushes the buffer if the flush interval has elapsed since the last flush
+func (w *logWriter) flushIfDue(now time.Time) error {
+	if w.buf.Len() == 0 || now.Sub(w.lastFlush) < w.flushInterval {
+		return nil
+	}
+	return w.flush()
+}
+
+// flush writes the buffered logs into the file at once, so that a line is never split by the rotation
+func (w *logWriter) flush() error {
+	w.lastFlush = time.Now()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.out.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *logWriter) close() error {
+	err := w.flush()
+	if closeErr := w.out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// dailyFile is a file which is renamed with the date suffix of its content, e.g. access.log.2020-03-04,
+// once the logs of a new day are written.
+type dailyFile struct {
+	path string
+	file *os.File
+	day  string
+}
+
+func (d *dailyFile) Write(p []byte) (int, error) {
+	if today := time.Now().Format(FileDateFormat); d.file == nil || d.day != today {
+		if err := d.open(today); err != nil {
+			return 0, err
+		}
+	}
+	return d.file.Write(p)
+}
+
+// open opens the file with append mode, and rotates the file first if it was written before @today
+func (d *dailyFile) open(today string) error {
+	if d.file != nil {
+		_ = d.file.Close()
+		d.file = nil
+	}
+	if info, err := os.Stat(d.path); err == nil && info.Size() > 0 {
+		if last := info.ModTime().Format(FileDateFormat); last != today {
+			if err = os.Rename(d.path, d.path+"."+last); err != nil {
+				return err
+			}
+		}
+	}
+	file, err := os.OpenFile(d.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, LogFileMode)
+	if err != nil {
+		return err
+	}
+	d.file, d.day = file, today
+	return nil
+}
+
+func (d *dailyFile) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
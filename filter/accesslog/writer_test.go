@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func readFile(t *testing.T, path string) string {
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	return string(content)
+}
+
+func TestLogWriterFlushBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000?accesslog.flush.size=10&accesslog.flush.interval=1h")
+	w := newLogWriter(path, url)
+	defer w.close()
+
+	assert.Nil(t, w.write("first"))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// the buffer exceeds the flush size
+	assert.Nil(t, w.write("second"))
+	assert.Equal(t, "first\nsecond\n", readFile(t, path))
+}
+
+func TestLogWriterFlushByTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000?accesslog.flush.interval=1s")
+	w := newLogWriter(path, url)
+	defer w.close()
+
+	assert.Nil(t, w.write("first"))
+	assert.Nil(t, w.flushIfDue(time.Now()))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Nil(t, w.flushIfDue(time.Now().Add(time.Second)))
+	assert.Equal(t, "first\n", readFile(t, path))
+}
+
+func TestLogWriterRotate(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000?accesslog.rotate=size&accesslog.max.size=10&accesslog.max.backups=3")
+	w := newLogWriter("access.log", url)
+	out, ok := w.out.(*lumberjack.Logger)
+	assert.True(t, ok)
+	assert.Equal(t, 10, out.MaxSize)
+	assert.Equal(t, 3, out.MaxBackups)
+
+	url, _ = common.NewURL("dubbo://127.0.0.1:20000")
+	_, ok = newLogWriter("access.log", url).out.(*dailyFile)
+	assert.True(t, ok)
+}
+
+func TestDailyFileRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("yesterday\n"), LogFileMode))
+	yesterday := time.Now().AddDate(0, 0, -1)
+	assert.Nil(t, os.Chtimes(path, yesterday, yesterday))
+
+	d := &dailyFile{path: path}
+	defer d.Close()
+	_, err := d.Write([]byte("today\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "today\n", readFile(t, path))
+	assert.Equal(t, "yesterday\n", readFile(t, path+"."+yesterday.Format(FileDateFormat)))
+
+	// the file of today is appended
+	_, err = d.Write([]byte("again\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "today\nagain\n", readFile(t, path))
+}
@@ -32,6 +32,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
@@ -155,7 +156,8 @@ func (f *adaptiveServiceProviderFilter) OnResponse(_ context.Context, result pro
 }
 
 func wrapErrAdaptiveSvcInterrupted(customizedErr interface{}) error {
-	return fmt.Errorf("%w: %v", ErrAdaptiveSvcInterrupted, customizedErr)
+	// the request is rejected as the provider is over its capacity
+	return common.NewRPCError(common.StatusThrottled, fmt.Errorf("%w: %v", ErrAdaptiveSvcInterrupted, customizedErr))
 }
 
 func isErrAdaptiveSvcInterrupted(err error) bool {
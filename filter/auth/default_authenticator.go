@@ -57,7 +57,7 @@ func newDefaultAuthenticator() filter.Authenticator {
 
 // Sign adds the signature to the invocation
 func (authenticator *defaultAuthenticator) Sign(invocation protocol.Invocation, url *common.URL) error {
-	currentTimeMillis := strconv.Itoa(int(time.Now().Unix() * 1000))
+	currentTimeMillis := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
 
 	consumer := url.GetParam(constant.ApplicationKey, "")
 	accessKeyPair, err := getAccessKeyPair(invocation, url)
@@ -107,21 +107,46 @@ func (authenticator *defaultAuthenticator) Authenticate(invocation protocol.Invo
 		return errors.New("failed to authenticate your ak/sk, maybe the consumer has not enabled the auth")
 	}
 
+	if err := checkTimestamp(requestTimestamp, url); err != nil {
+		return err
+	}
+
 	accessKeyPair, err := getAccessKeyPair(invocation, url)
 	if err != nil {
 		return errors.New("failed to authenticate , can't load the accessKeyPair")
 	}
+	if accessKeyPair.AccessKey != accessKeyId {
+		return errors.New("failed to authenticate, accessKeyId is not correct")
+	}
 
 	computeSignature, err := getSignature(url, invocation, accessKeyPair.SecretKey, requestTimestamp)
 	if err != nil {
 		return err
 	}
-	if success := computeSignature == originSignature; !success {
+	if success := signatureEqual(computeSignature, originSignature); !success {
 		return errors.New("failed to authenticate, signature is not correct")
 	}
 	return nil
 }
 
+// checkTimestamp verifies the request timestamp in milliseconds is within the skew of auth.timestamp.skew,
+// so that a captured request can not be replayed after then
+func checkTimestamp(requestTimestamp string, url *common.URL) error {
+	skew := url.GetParamDurationWithDefault(constant.AuthTimestampSkewKey, constant.DefaultAuthTimestampSkew)
+	if skew <= 0 {
+		return nil
+	}
+	millis, err := strconv.ParseInt(requestTimestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate, invalid request timestamp %s", requestTimestamp)
+	}
+	diff := time.Since(time.Unix(0, millis*int64(time.Millisecond)))
+	if diff > skew || diff < -skew {
+		return fmt.Errorf("failed to authenticate, request timestamp %s is out of the allowed skew %v", requestTimestamp, skew)
+	}
+	return nil
+}
+
 func getAccessKeyPair(invocation protocol.Invocation, url *common.URL) (*filter.AccessKeyPair, error) {
 	accesskeyStorage := extension.GetAccessKeyStorages(url.GetParam(constant.AccessKeyStorageKey, constant.DefaultAccessKeyStorage))
 	accessKeyPair := accesskeyStorage.GetAccessKeyPair(invocation, url)
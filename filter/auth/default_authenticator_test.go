@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -32,6 +33,9 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
@@ -143,3 +147,91 @@ func Test_getSignature(t *testing.T) {
 	assert.False(t, IsEmpty(signature, false))
 	assert.Equal(t, s, signature)
 }
+
+func Test_getSignatureJavaInterop(t *testing.T) {
+	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=gg&version=2.6.0")
+	inv := invocation.NewRPCInvocation("sayHello", []interface{}{"OK"}, nil)
+	signature, err := getSignature(testurl, inv, "dubbo-sk", "1672531200000")
+	assert.Nil(t, err)
+	assert.Equal(t, "+DLTzPF0QSVEddc8p57p+yiWAHNkP83XRcoznXy4B58=", signature)
+
+	testurl, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.demo.DemoService?interface=org.apache.dubbo.demo.DemoService")
+	inv = invocation.NewRPCInvocation("sayHello", []interface{}{"OK"}, nil)
+	signature, err = getSignature(testurl, inv, "secret", "1600000000000")
+	assert.Nil(t, err)
+	assert.Equal(t, "uN4qWEC8b3mARiJ3/pLeVArnn/rLA1qtite7aJf6Obo=", signature)
+}
+
+func newSignedInvocation(testurl *common.URL, access, secret, requestTime string) *invocation.RPCInvocation {
+	inv := invocation.NewRPCInvocation("test", []interface{}{"OK"}, nil)
+	signature, _ := getSignature(testurl, inv, secret, requestTime)
+	return invocation.NewRPCInvocation("test", []interface{}{"OK"}, map[string]interface{}{
+		constant.RequestSignatureKey: signature,
+		constant.Consumer:            "test",
+		constant.RequestTimestampKey: requestTime,
+		constant.AKKey:               access,
+	})
+}
+
+func TestDefaultAuthenticator_AuthenticateTimestamp(t *testing.T) {
+	authenticator = &defaultAuthenticator{}
+	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	testurl.SetParam(constant.AccessKeyIDKey, "akey")
+	testurl.SetParam(constant.SecretAccessKeyKey, "skey")
+	millis := func(t time.Time) string {
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	assert.Nil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", millis(time.Now().Add(-time.Minute))), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", millis(time.Now().Add(-time.Hour))), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", millis(time.Now().Add(time.Hour))), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", "now"), testurl))
+
+	testurl.SetParam(constant.AuthTimestampSkewKey, "2h")
+	assert.Nil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", millis(time.Now().Add(-time.Hour))), testurl))
+	testurl.SetParam(constant.AuthTimestampSkewKey, "0")
+	assert.Nil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "skey", "1600000000000"), testurl))
+}
+
+func TestDefaultAuthenticator_AuthenticateAccessKey(t *testing.T) {
+	authenticator = &defaultAuthenticator{}
+	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	testurl.SetParam(constant.AccessKeyIDKey, "akey")
+	testurl.SetParam(constant.SecretAccessKeyKey, "skey")
+	requestTime := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "other", "skey", requestTime), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "akey", "other", requestTime), testurl))
+
+	// the url encoded signature of the legacy consumers
+	inv := newSignedInvocation(testurl, "akey", "skey", requestTime)
+	signature, _ := inv.GetAttachment(constant.RequestSignatureKey)
+	inv.SetAttachment(constant.RequestSignatureKey, strings.NewReplacer("+", "-", "/", "_").Replace(signature))
+	assert.Nil(t, authenticator.Authenticate(inv, testurl))
+}
+
+// consumerAccessKeyStorage resolves the secret key by the access key of each consumer
+type consumerAccessKeyStorage struct {
+	secrets map[string]string
+}
+
+func (s *consumerAccessKeyStorage) GetAccessKeyPair(invocation protocol.Invocation, url *common.URL) *filter.AccessKeyPair {
+	access := invocation.GetAttachmentWithDefaultValue(constant.AKKey, "")
+	return &filter.AccessKeyPair{AccessKey: access, SecretKey: s.secrets[access]}
+}
+
+func TestDefaultAuthenticator_AuthenticateConsumerStorage(t *testing.T) {
+	storage := &consumerAccessKeyStorage{secrets: map[string]string{"consumer-a": "secret-a", "consumer-b": "secret-b"}}
+	extension.SetAccessKeyStorages("consumers", func() filter.AccessKeyStorage {
+		return storage
+	})
+	authenticator = &defaultAuthenticator{}
+	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	testurl.SetParam(constant.AccessKeyStorageKey, "consumers")
+	requestTime := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+
+	assert.Nil(t, authenticator.Authenticate(newSignedInvocation(testurl, "consumer-a", "secret-a", requestTime), testurl))
+	assert.Nil(t, authenticator.Authenticate(newSignedInvocation(testurl, "consumer-b", "secret-b", requestTime), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "consumer-a", "secret-b", requestTime), testurl))
+	assert.NotNil(t, authenticator.Authenticate(newSignedInvocation(testurl, "consumer-c", "secret-c", requestTime), testurl))
+}
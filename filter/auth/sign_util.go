@@ -64,7 +64,21 @@ func doSign(bytes []byte, key string) string {
 		logger.Error(err)
 	}
 	signature := mac.Sum(nil)
-	return base64.URLEncoding.EncodeToString(signature)
+	// the same encoding as Base64.getEncoder() used by SignatureUtils of Java dubbo-auth
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+// signatureEqual compares the @computed signature with the @origin one sent by the consumer in constant time.
+// The signatures of the consumers before the encoding is aligned with Java are url encoded, they are accepted too.
+func signatureEqual(computed, origin string) bool {
+	if hmac.Equal([]byte(computed), []byte(origin)) {
+		return true
+	}
+	raw, err := base64.StdEncoding.DecodeString(computed)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(base64.URLEncoding.EncodeToString(raw)), []byte(origin))
 }
 
 // IsEmpty verify whether the inputted string is empty
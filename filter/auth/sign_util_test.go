@@ -98,3 +98,38 @@ func Test_toBytes(t *testing.T) {
 	assert.NotNil(t, jsonBytes)
 	assert.Equal(t, jsonBytes, jsonBytes2)
 }
+
+// the signatures of the test vectors are the ones computed by SignatureUtils of Java dubbo-auth,
+// i.e. base64(hmac-sha256(secret, "service#method#secret#timestamp"))
+func TestSignJavaInterop(t *testing.T) {
+	tests := []struct {
+		metadata  string
+		key       string
+		signature string
+	}{
+		{
+			"com.ikurento.user.UserProvider:2.6.0:gg#sayHello#dubbo-sk#1672531200000", "dubbo-sk",
+			"+DLTzPF0QSVEddc8p57p+yiWAHNkP83XRcoznXy4B58=",
+		},
+		{
+			"org.apache.dubbo.demo.DemoService::#sayHello#secret#1600000000000", "secret",
+			"uN4qWEC8b3mARiJ3/pLeVArnn/rLA1qtite7aJf6Obo=",
+		},
+		{
+			"org.apache.dubbo.demo.DemoService:1.0.0:#getUser#SK-123#1700000000123", "SK-123",
+			"KeLq5ZrIxmEVXSyTk94Nbh4SePzZfrpDhJbx5o68E+c=",
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.signature, Sign(tt.metadata, tt.key), tt.metadata)
+	}
+}
+
+func TestSignatureEqual(t *testing.T) {
+	signature := "+DLTzPF0QSVEddc8p57p+yiWAHNkP83XRcoznXy4B58="
+	assert.True(t, signatureEqual(signature, signature))
+	// the url encoded signature of the legacy consumers
+	assert.True(t, signatureEqual(signature, "-DLTzPF0QSVEddc8p57p-yiWAHNkP83XRcoznXy4B58="))
+	assert.False(t, signatureEqual(signature, "uN4qWEC8b3mARiJ3/pLeVArnn/rLA1qtite7aJf6Obo="))
+	assert.False(t, signatureEqual(signature, ""))
+}
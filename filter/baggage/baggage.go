@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package baggage provides a pair of filters propagating the selected values of the context, e.g. the tenant, the
+// user and the w3c traceparent, from the consumer to the provider and onward through the nested calls.
+//
+// The consumer filter sets the baggage of the context whose keys are listed in baggage.keys of the reference into
+// the attachments, along with the traceparent of a new span. The provider filter extracts the attachments listed in
+// baggage.keys of the service into the baggage of the context handed to the service, so that the nested calls made
+// with that context propagate them again.
+package baggage
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type baggageKey struct{}
+
+// WithValue returns a copy of @ctx whose baggage carries @key=@value
+func WithValue(ctx context.Context, key, value string) context.Context {
+	return withBaggage(ctx, map[string]string{key: value})
+}
+
+// Value returns the baggage @key of @ctx
+func Value(ctx context.Context, key string) (string, bool) {
+	value, ok := FromContext(ctx)[key]
+	return value, ok
+}
+
+// FromContext returns the baggage of @ctx, which must not be modified
+func FromContext(ctx context.Context) map[string]string {
+	if bags, ok := ctx.Value(baggageKey{}).(map[string]string); ok {
+		return bags
+	}
+	return nil
+}
+
+// withBaggage returns a copy of @ctx whose baggage is merged with @bags
+func withBaggage(ctx context.Context, bags map[string]string) context.Context {
+	if len(bags) == 0 {
+		return ctx
+	}
+	parent := FromContext(ctx)
+	merged := make(map[string]string, len(parent)+len(bags))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range bags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, baggageKey{}, merged)
+}
+
+// allowedKeys returns the keys of the baggage propagated by @url, traceparent excluded as it is always propagated
+func allowedKeys(url *common.URL) []string {
+	var keys []string
+	for _, key := range strings.Split(url.GetParam(constant.BaggageKeysKey, ""), ",") {
+		key = strings.TrimSpace(key)
+		if key != "" && key != constant.TraceparentKey {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// truncate cuts @value to the baggage.max.size bytes of @url without splitting a rune
+func truncate(url *common.URL, key, value string) string {
+	maxSize := int(url.GetParamInt(constant.BaggageMaxSizeKey, constant.DefaultBaggageMaxSize))
+	if maxSize <= 0 || len(value) <= maxSize {
+		return value
+	}
+	logger.Warnf("[baggage filter] the value of %s is %d bytes, truncated to %d bytes", key, len(value), maxSize)
+	truncated := value[:maxSize]
+	for len(truncated) > 0 && !utf8.ValidString(truncated) {
+		truncated = truncated[:len(truncated)-1]
+	}
+	return truncated
+}
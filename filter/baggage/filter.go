@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package baggage
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	consumerOnce    sync.Once
+	consumerBaggage *consumerFilter
+
+	providerOnce    sync.Once
+	providerBaggage *providerFilter
+)
+
+func init() {
+	extension.SetFilter(constant.BaggageConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.BaggageProviderFilterKey, newProviderFilter)
+}
+
+// consumerFilter sets the baggage of the context into the attachments
+type consumerFilter struct{}
+
+func newConsumerFilter() filter.Filter {
+	if consumerBaggage == nil {
+		consumerOnce.Do(func() {
+			consumerBaggage = &consumerFilter{}
+		})
+	}
+	return consumerBaggage
+}
+
+// Invoke sets the baggage listed in baggage.keys, which falls back to the attachments set by the user, and the
+// traceparent of a new span of the trace of the context, or of a new trace
+func (f *consumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	bags := FromContext(ctx)
+	for _, key := range allowedKeys(url) {
+		value, ok := bags[key]
+		if !ok {
+			if value, ok = invocation.GetAttachment(key); !ok {
+				continue
+			}
+		}
+		invocation.SetAttachment(key, truncate(url, key, value))
+	}
+	invocation.SetAttachment(constant.TraceparentKey, newTraceparent(bags[constant.TraceparentKey]))
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse does nothing
+func (f *consumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// providerFilter extracts the attachments into the baggage of the context handed to the service
+type providerFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	if providerBaggage == nil {
+		providerOnce.Do(func() {
+			providerBaggage = &providerFilter{}
+		})
+	}
+	return providerBaggage
+}
+
+// Invoke extracts the attachments listed in baggage.keys and a valid traceparent, so that the nested calls made with
+// the context of the service propagate them again
+func (f *providerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	bags := make(map[string]string)
+	for _, key := range allowedKeys(url) {
+		if value, ok := invocation.GetAttachment(key); ok {
+			bags[key] = truncate(url, key, value)
+		}
+	}
+	if traceparent, ok := invocation.GetAttachment(constant.TraceparentKey); ok {
+		if _, _, valid := parseTraceparent(traceparent); valid {
+			bags[constant.TraceparentKey] = traceparent
+		} else {
+			logger.Warnf("[baggage filter] drop the invalid traceparent %q of the method %s of %s",
+				traceparent, invocation.MethodName(), url.ServiceKey())
+		}
+	}
+	return invoker.Invoke(withBaggage(ctx, bags), invocation)
+}
+
+// OnResponse does nothing
+func (f *providerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
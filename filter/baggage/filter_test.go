@@ -0,0 +1,190 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package baggage
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// serviceInvoker calls the service with the context handed by the provider filter
+type serviceInvoker struct {
+	*protocol.BaseInvoker
+	service func(ctx context.Context)
+}
+
+func (ivk *serviceInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	ivk.service(ctx)
+	return &protocol.RPCResult{}
+}
+
+// remoteInvoker simulates a remote call, where the attachments cross the wire but the context does not
+type remoteInvoker struct {
+	*protocol.BaseInvoker
+	provider protocol.Invoker
+}
+
+func (ivk *remoteInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	received := invocation.NewRPCInvocation(inv.MethodName(), inv.Arguments(), map[string]interface{}{})
+	for k, v := range inv.Attachments() {
+		received.SetAttachment(k, v)
+	}
+	return newProviderFilter().Invoke(context.Background(), ivk.provider, received)
+}
+
+func newURL(t *testing.T, params string) *common.URL {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" + params)
+	assert.NoError(t, err)
+	return url
+}
+
+// call calls the service through the consumer filter of a reference with @referenceParams and the provider filter
+// of a service with @serviceParams
+func call(t *testing.T, ctx context.Context, referenceParams, serviceParams string, service func(ctx context.Context)) {
+	provider := &serviceInvoker{BaseInvoker: protocol.NewBaseInvoker(newURL(t, serviceParams)), service: service}
+	remote := &remoteInvoker{BaseInvoker: protocol.NewBaseInvoker(newURL(t, referenceParams)), provider: provider}
+	result := newConsumerFilter().Invoke(ctx, remote, invocation.NewRPCInvocation("Hello", nil, nil))
+	assert.NoError(t, result.Error())
+}
+
+func TestPropagateThroughNestedCalls(t *testing.T) {
+	var bagsB, bagsC map[string]string
+	serviceC := func(ctx context.Context) {
+		bagsC = FromContext(ctx)
+	}
+	serviceB := func(ctx context.Context) {
+		bagsB = FromContext(ctx)
+		call(t, ctx, "baggage.keys=tenant,user", "baggage.keys=tenant,user", serviceC)
+	}
+
+	ctx := WithValue(context.Background(), "tenant", "t1")
+	ctx = WithValue(ctx, "user", "u1")
+	ctx = WithValue(ctx, "secret", "s1")
+	call(t, ctx, "baggage.keys=tenant, user", "baggage.keys=tenant,user", serviceB)
+
+	for _, bags := range []map[string]string{bagsB, bagsC} {
+		assert.Equal(t, "t1", bags["tenant"])
+		assert.Equal(t, "u1", bags["user"])
+		assert.NotContains(t, bags, "secret")
+	}
+	traceB, _, ok := parseTraceparent(bagsB[constant.TraceparentKey])
+	assert.True(t, ok)
+	traceC, _, ok := parseTraceparent(bagsC[constant.TraceparentKey])
+	assert.True(t, ok)
+	assert.Equal(t, traceB, traceC)
+	assert.NotEqual(t, bagsB[constant.TraceparentKey], bagsC[constant.TraceparentKey])
+}
+
+func TestPropagateAllowedKeys(t *testing.T) {
+	var bags map[string]string
+	service := func(ctx context.Context) {
+		bags = FromContext(ctx)
+	}
+	ctx := WithValue(WithValue(context.Background(), "tenant", "t1"), "user", "u1")
+
+	// the provider only extracts its own keys
+	call(t, ctx, "baggage.keys=tenant,user", "baggage.keys=tenant", service)
+	assert.Equal(t, "t1", bags["tenant"])
+	assert.NotContains(t, bags, "user")
+
+	// the traceparent is always propagated
+	call(t, ctx, "", "", service)
+	assert.Len(t, bags, 1)
+	assert.Contains(t, bags, constant.TraceparentKey)
+}
+
+func TestPropagateTraceparent(t *testing.T) {
+	var traceparent string
+	service := func(ctx context.Context) {
+		traceparent, _ = Value(ctx, constant.TraceparentKey)
+	}
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	call(t, WithValue(context.Background(), constant.TraceparentKey, parent), "", "", service)
+	traceID, flags, ok := parseTraceparent(traceparent)
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "01", flags)
+	assert.NotEqual(t, parent, traceparent)
+
+	// an invalid traceparent starts a new trace
+	call(t, WithValue(context.Background(), constant.TraceparentKey, "invalid"), "", "", service)
+	traceID, _, ok = parseTraceparent(traceparent)
+	assert.True(t, ok)
+	assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+}
+
+func TestProviderFilterDropInvalidTraceparent(t *testing.T) {
+	var bags map[string]string
+	provider := &serviceInvoker{BaseInvoker: protocol.NewBaseInvoker(newURL(t, "")), service: func(ctx context.Context) {
+		bags = FromContext(ctx)
+	}}
+	inv := invocation.NewRPCInvocation("Hello", nil, map[string]interface{}{constant.TraceparentKey: "00-00-00-00"})
+	newProviderFilter().Invoke(context.Background(), provider, inv)
+	assert.Empty(t, bags)
+}
+
+func TestConsumerFilterTruncate(t *testing.T) {
+	var bags map[string]string
+	service := func(ctx context.Context) {
+		bags = FromContext(ctx)
+	}
+	ctx := WithValue(context.Background(), "tenant", "héllo wörld")
+	call(t, ctx, "baggage.keys=tenant&baggage.max.size=4", "baggage.keys=tenant", service)
+	assert.Equal(t, "hél", bags["tenant"])
+
+	// the attachments set by the user are propagated as well
+	inv := invocation.NewRPCInvocation("Hello", nil, map[string]interface{}{"tenant": "t2"})
+	remote := &remoteInvoker{
+		BaseInvoker: protocol.NewBaseInvoker(newURL(t, "baggage.keys=tenant")),
+		provider:    &serviceInvoker{BaseInvoker: protocol.NewBaseInvoker(newURL(t, "baggage.keys=tenant")), service: service},
+	}
+	newConsumerFilter().Invoke(context.Background(), remote, inv)
+	assert.Equal(t, "t2", bags["tenant"])
+}
+
+func TestParseTraceparent(t *testing.T) {
+	for traceparent, valid := range map[string]bool{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":       true,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00":       true,
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra": true,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra": false,
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":       false,
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01":       false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01":       false,
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01":       false,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7":          false,
+		"": false,
+	} {
+		_, _, ok := parseTraceparent(traceparent)
+		assert.Equal(t, valid, ok, traceparent)
+	}
+	_, _, ok := parseTraceparent(newTraceparent(""))
+	assert.True(t, ok)
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package baggage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	traceparentVersion = "00"
+	sampledFlags       = "01"
+	traceIDSize        = 16
+	spanIDSize         = 8
+)
+
+// newTraceparent returns the traceparent of a new span, which is a child of @parent if it is valid, or the root of a
+// new sampled trace otherwise, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func newTraceparent(parent string) string {
+	traceID, flags, ok := parseTraceparent(parent)
+	if !ok {
+		traceID, flags = randomHex(traceIDSize), sampledFlags
+	}
+	return strings.Join([]string{traceparentVersion, traceID, randomHex(spanIDSize), flags}, "-")
+}
+
+// parseTraceparent returns the trace id and the flags of @traceparent
+func parseTraceparent(traceparent string) (traceID, flags string, ok bool) {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || !isHex(parts[0], 1) || parts[0] == "ff" ||
+		// the version 00 has exactly 4 parts, the later versions may append more
+		(parts[0] == traceparentVersion && len(parts) != 4) ||
+		!isHex(parts[1], traceIDSize) || isZero(parts[1]) ||
+		!isHex(parts[2], spanIDSize) || isZero(parts[2]) ||
+		!isHex(parts[3], 1) {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// isHex reports whether @s is the lowercase hex of @size bytes
+func isHex(s string, size int) bool {
+	if len(s) != size*2 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero reports whether @s is an invalid all zero id
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	for {
+		_, _ = rand.Read(b)
+		if id := hex.EncodeToString(b); !isZero(id) {
+			return id
+		}
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// Cache stores the results of a method, it must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value of @key, false if it is absent or expired
+	Get(key string) (interface{}, bool)
+	// Put stores the @value of @key
+	Put(key string, value interface{})
+	// Clear removes all the values
+	Clear()
+}
+
+// CacheFactory returns the cache of the results of the method of the invocation. It is configured on the method
+// level by cache, e.g. cache: lru. A nil Cache means the invocation is not cached.
+type CacheFactory interface {
+	GetCache(ctx context.Context, url *common.URL, invocation protocol.Invocation) Cache
+}
+
+// CacheKeyGenerator generates the key of the result of the invocation in the cache of its method. It is configured
+// by cache.key.generator, for the methods whose arguments can't be serialized by the default generator.
+type CacheKeyGenerator interface {
+	// Generate returns the key of the @invocation, an error means the invocation is not cached
+	Generate(invocation protocol.Invocation) (string, error)
+}
@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cache provides a consumer filter which caches the results of the read-mostly methods, e.g.
+//
+//	references:
+//	  UserProvider:
+//	    interface: com.ikurento.user.UserProvider
+//	    methods:
+//	      - name: GetUser
+//	        cache: expiring # lru, expiring or request
+//	        cache.size: 1000
+//	        cache.ttl: 60s
+//
+// The results are keyed by the arguments of the invocations. Only the successful results are cached, and a
+// provider can prevent a result from being cached by the result attachment cacheable=false.
+package cache
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	once        sync.Once
+	cacheFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.CacheFilterKey, newFilter)
+	extension.SetCacheFactory(LRUCacheName, &lruCacheFactory{})
+	extension.SetCacheFactory(ExpiringCacheName, &expiringCacheFactory{})
+	extension.SetCacheFactory(RequestCacheName, &requestCacheFactory{})
+	extension.SetCacheKeyGenerator(constant.DefaultCacheKeyGenerator, &defaultKeyGenerator{})
+}
+
+// Filter returns the cached result of the invocation if present, otherwise stores the successful result
+type Filter struct{}
+
+func newFilter() filter.Filter {
+	if cacheFilter == nil {
+		once.Do(func() {
+			cacheFilter = &Filter{}
+		})
+	}
+	return cacheFilter
+}
+
+// cachedResult is the value of a result in the cache
+type cachedResult struct {
+	value       interface{}
+	attachments map[string]interface{}
+}
+
+// Invoke returns the cached result if present, otherwise invokes and caches the result
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	name := url.GetMethodParam(methodName, constant.CacheKey, url.GetParam(constant.CacheKey, ""))
+	if len(name) == 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+	factory, err := extension.GetCacheFactory(name)
+	if err != nil {
+		logger.Warnf("[cache filter] %v, the method %s is not cached", err, methodName)
+		return invoker.Invoke(ctx, invocation)
+	}
+	c := factory.GetCache(ctx, url, invocation)
+	if c == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+	generatorName := url.GetMethodParam(methodName, constant.CacheKeyGeneratorKey,
+		url.GetParam(constant.CacheKeyGeneratorKey, constant.DefaultCacheKeyGenerator))
+	generator, err := extension.GetCacheKeyGenerator(generatorName)
+	if err != nil {
+		logger.Warnf("[cache filter] %v, the method %s is not cached", err, methodName)
+		return invoker.Invoke(ctx, invocation)
+	}
+	key, err := generator.Generate(invocation)
+	if err != nil {
+		logger.Debugf("[cache filter] generate the cache key of the method %s error: %v", methodName, err)
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	counters := getCounters(url.Service(), methodName)
+	if v, ok := c.Get(key); ok {
+		counters.hits.Inc()
+		return newCachedRPCResult(invocation, v.(*cachedResult))
+	}
+	counters.misses.Inc()
+	result := invoker.Invoke(ctx, invocation)
+	if isCacheable(result) {
+		c.Put(key, newCachedResult(invocation, result))
+	}
+	return result
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// isCacheable returns false for the error results and the results with the attachment cacheable=false
+func isCacheable(result protocol.Result) bool {
+	if result == nil || result.Error() != nil {
+		return false
+	}
+	v, ok := result.Attachment(constant.CacheableKey, "").(string)
+	return !ok || !strings.EqualFold(v, "false")
+}
+
+// newCachedResult copies the value of the result, as the reply of the invocation is reused by the caller
+func newCachedResult(invocation protocol.Invocation, result protocol.Result) *cachedResult {
+	c := &cachedResult{value: result.Result(), attachments: make(map[string]interface{}, len(result.Attachments()))}
+	if reply := reflect.ValueOf(invocation.Reply()); reply.Kind() == reflect.Ptr && !reply.IsNil() {
+		c.value = reply.Elem().Interface()
+	}
+	for k, v := range result.Attachments() {
+		c.attachments[k] = v
+	}
+	return c
+}
+
+// newCachedRPCResult returns the cached result, the value is copied into the reply of the invocation if any
+func newCachedRPCResult(invocation protocol.Invocation, c *cachedResult) protocol.Result {
+	result := &protocol.RPCResult{Rest: c.value, Attrs: make(map[string]interface{}, len(c.attachments))}
+	if reply := reflect.ValueOf(invocation.Reply()); reply.Kind() == reflect.Ptr && !reply.IsNil() {
+		if value := reflect.ValueOf(c.value); value.IsValid() && value.Type().AssignableTo(reply.Elem().Type()) {
+			reply.Elem().Set(value)
+			result.Rest = invocation.Reply()
+		}
+	}
+	for k, v := range c.attachments {
+		result.Attrs[k] = v
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type user struct {
+	ID   string
+	Name string
+}
+
+// userInvoker replies the user of the id in the first argument, as a protocol filling the reply
+type userInvoker struct {
+	*protocol.BaseInvoker
+	invoked     int
+	err         error
+	attachments map[string]interface{}
+}
+
+func (ivk *userInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	if ivk.err != nil {
+		return &protocol.RPCResult{Err: ivk.err}
+	}
+	id := inv.Arguments()[0].(string)
+	reply := inv.Reply().(*user)
+	*reply = user{ID: id, Name: "user" + id}
+	return &protocol.RPCResult{Rest: reply, Attrs: ivk.attachments}
+}
+
+func newUserInvocation(id string) *invocation.RPCInvocation {
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{id}, nil)
+	inv.SetReply(&user{})
+	return inv
+}
+
+func newUserInvoker(t *testing.T, params string) *userInvoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" + params)
+	assert.NoError(t, err)
+	return &userInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+}
+
+func TestFilterInvoke(t *testing.T) {
+	f := newFilter()
+	invoker := newUserInvoker(t, "interface=com.ikurento.user.CacheProvider&methods.GetUser.cache=lru")
+	before := GetStats("com.ikurento.user.CacheProvider", "GetUser")
+
+	inv := newUserInvocation("1")
+	result := f.Invoke(context.Background(), invoker, inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "user1", inv.Reply().(*user).Name)
+
+	// the cached result is copied into the reply of the invocation
+	inv = newUserInvocation("1")
+	result = f.Invoke(context.Background(), invoker, inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, &user{ID: "1", Name: "user1"}, inv.Reply())
+	assert.Equal(t, inv.Reply(), result.Result())
+	assert.Equal(t, 1, invoker.invoked)
+
+	f.Invoke(context.Background(), invoker, newUserInvocation("2"))
+	assert.Equal(t, 2, invoker.invoked)
+	stats := GetStats("com.ikurento.user.CacheProvider", "GetUser")
+	assert.Equal(t, uint64(1), stats.Hits-before.Hits)
+	assert.Equal(t, uint64(2), stats.Misses-before.Misses)
+
+	// the other methods are not cached
+	f.Invoke(context.Background(), invoker, newUserInvocation("2"))
+	assert.Equal(t, 2, invoker.invoked)
+	inv = invocation.NewRPCInvocation("ListUsers", []interface{}{"2"}, nil)
+	inv.SetReply(&user{})
+	f.Invoke(context.Background(), invoker, inv)
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 4, invoker.invoked)
+}
+
+func TestFilterNotCacheable(t *testing.T) {
+	f := newFilter()
+	invoker := newUserInvoker(t, "interface=com.ikurento.user.CacheProvider&cache=lru")
+	invoker.err = errors.New("error")
+	f.Invoke(context.Background(), invoker, newUserInvocation("3"))
+	invoker.err = nil
+	f.Invoke(context.Background(), invoker, newUserInvocation("3"))
+	assert.Equal(t, 2, invoker.invoked)
+
+	invoker.attachments = map[string]interface{}{constant.CacheableKey: "false"}
+	f.Invoke(context.Background(), invoker, newUserInvocation("4"))
+	f.Invoke(context.Background(), invoker, newUserInvocation("4"))
+	assert.Equal(t, 4, invoker.invoked)
+
+	// the arguments which can't be serialized are not cached
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"5", func() {}}, nil)
+	inv.SetReply(&user{})
+	invoker.attachments = nil
+	f.Invoke(context.Background(), invoker, inv)
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 6, invoker.invoked)
+
+	// the unknown cache is ignored
+	invoker = newUserInvoker(t, "interface=com.ikurento.user.CacheProvider&cache=unknown")
+	f.Invoke(context.Background(), invoker, newUserInvocation("6"))
+	f.Invoke(context.Background(), invoker, newUserInvocation("6"))
+	assert.Equal(t, 2, invoker.invoked)
+}
+
+type idKeyGenerator struct{}
+
+func (g *idKeyGenerator) Generate(inv protocol.Invocation) (string, error) {
+	return inv.Arguments()[0].(string), nil
+}
+
+func TestFilterKeyGenerator(t *testing.T) {
+	extension.SetCacheKeyGenerator("id", &idKeyGenerator{})
+	f := newFilter()
+	invoker := newUserInvoker(t, "interface=com.ikurento.user.KeyProvider&cache=lru&cache.key.generator=id")
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"7", func() {}}, nil)
+	inv.SetReply(&user{})
+	f.Invoke(context.Background(), invoker, inv)
+	inv = newUserInvocation("7")
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 1, invoker.invoked)
+	assert.Equal(t, "user7", inv.Reply().(*user).Name)
+}
+
+func TestInvalidate(t *testing.T) {
+	f := newFilter()
+	invoker := newUserInvoker(t, "interface=com.ikurento.user.InvalidateProvider&cache=expiring")
+	f.Invoke(context.Background(), invoker, newUserInvocation("8"))
+	f.Invoke(context.Background(), invoker, newUserInvocation("8"))
+	assert.Equal(t, 1, invoker.invoked)
+
+	Invalidate("com.ikurento.user.InvalidateProvider", "SetUser")
+	f.Invoke(context.Background(), invoker, newUserInvocation("8"))
+	assert.Equal(t, 1, invoker.invoked)
+
+	Invalidate("com.ikurento.user.InvalidateProvider", "GetUser")
+	f.Invoke(context.Background(), invoker, newUserInvocation("8"))
+	assert.Equal(t, 2, invoker.invoked)
+
+	Invalidate("com.ikurento.user.InvalidateProvider", "")
+	f.Invoke(context.Background(), invoker, newUserInvocation("8"))
+	assert.Equal(t, 3, invoker.invoked)
+}
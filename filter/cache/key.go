@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// defaultKeyGenerator generates the key by serializing the arguments into json, which is deterministic as the
+// fields of the structs are in the declared order and the keys of the maps are sorted. The arguments which
+// can't be serialized, e.g. the maps with struct keys, need a custom generator.
+type defaultKeyGenerator struct{}
+
+func (g *defaultKeyGenerator) Generate(invocation protocol.Invocation) (string, error) {
+	key, err := json.Marshal(invocation.Arguments())
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// LRUCacheName evicts the least recently used result once cache.size is reached
+	LRUCacheName = "lru"
+	// ExpiringCacheName evicts the results cache.ttl after they are stored, and the least recently used result
+	// once cache.size is reached
+	ExpiringCacheName = "expiring"
+)
+
+type lruCacheFactory struct{}
+
+// GetCache returns the lru cache of the method, the size of which is fixed once the cache is created
+func (f *lruCacheFactory) GetCache(_ context.Context, url *common.URL, invocation protocol.Invocation) filter.Cache {
+	methodName := invocation.MethodName()
+	return getOrCreateCache(LRUCacheName, url, methodName, func() filter.Cache {
+		return newLRUCache(cacheSize(url, methodName), 0)
+	})
+}
+
+type expiringCacheFactory struct{}
+
+// GetCache returns the expiring cache of the method, the size and the ttl of which are fixed once the cache
+// is created
+func (f *expiringCacheFactory) GetCache(_ context.Context, url *common.URL, invocation protocol.Invocation) filter.Cache {
+	methodName := invocation.MethodName()
+	return getOrCreateCache(ExpiringCacheName, url, methodName, func() filter.Cache {
+		return newLRUCache(cacheSize(url, methodName), cacheTTL(url, methodName))
+	})
+}
+
+func cacheSize(url *common.URL, methodName string) int {
+	size := url.GetMethodParamInt64(methodName, constant.CacheSizeKey, constant.DefaultCacheSize)
+	if size <= 0 {
+		size = constant.DefaultCacheSize
+	}
+	return int(size)
+}
+
+func cacheTTL(url *common.URL, methodName string) time.Duration {
+	ttl, err := common.ParseDuration(url.GetMethodParam(methodName, constant.CacheTTLKey,
+		url.GetParam(constant.CacheTTLKey, constant.DefaultCacheTTL)))
+	if err != nil || ttl <= 0 {
+		ttl, _ = time.ParseDuration(constant.DefaultCacheTTL)
+	}
+	return ttl
+}
+
+type lruEntry struct {
+	key      string
+	value    interface{}
+	expireAt time.Time
+}
+
+// lruCache is a cache of fixed size which evicts the least recently used value, the values expire after ttl
+// if ttl > 0
+type lruCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order is the entries from the most recently used to the least recently used one
+	order *list.List
+	clock func() time.Time
+}
+
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		clock:   time.Now,
+	}
+}
+
+func (c *lruCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if c.ttl > 0 && !c.clock().Before(entry.expireAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (c *lruCache) Put(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var expireAt time.Time
+	if c.ttl > 0 {
+		expireAt = c.clock().Add(c.ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value, entry.expireAt = value, expireAt
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expireAt: expireAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *lruCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
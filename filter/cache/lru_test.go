@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache(2, 0)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	// a is used recently, so b is evicted
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+	c.Put("c", 3)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+
+	c.Put("c", 4)
+	v, _ = c.Get("c")
+	assert.Equal(t, 4, v)
+
+	c.Clear()
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestExpiringCache(t *testing.T) {
+	now := time.Now()
+	c := newLRUCache(10, time.Minute)
+	c.clock = func() time.Time { return now }
+	c.Put("a", 1)
+	now = now.Add(30 * time.Second)
+	c.Put("b", 2)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(30 * time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, c.order.Len())
+}
+
+func TestCacheFactory(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"cache.size=10&methods.GetUser.cache.size=2&methods.GetUser.cache.ttl=1s&methods.SetUser.cache.ttl=bad")
+	getUser := invocation.NewRPCInvocation("GetUser", nil, nil)
+	c := (&lruCacheFactory{}).GetCache(context.Background(), url, getUser).(*lruCache)
+	assert.Equal(t, 2, c.size)
+	assert.Equal(t, time.Duration(0), c.ttl)
+	assert.Same(t, c, (&lruCacheFactory{}).GetCache(context.Background(), url, getUser))
+
+	c = (&expiringCacheFactory{}).GetCache(context.Background(), url, getUser).(*lruCache)
+	assert.Equal(t, 2, c.size)
+	assert.Equal(t, time.Second, c.ttl)
+
+	c = (&expiringCacheFactory{}).GetCache(context.Background(), url, invocation.NewRPCInvocation("SetUser", nil, nil)).(*lruCache)
+	assert.Equal(t, 10, c.size)
+	assert.Equal(t, 180*time.Second, c.ttl)
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// RequestCacheName caches the results in the scope created by WithRequestCache, so the same invocation is
+// invoked only once while handling a request. The invocations out of any scope are not cached.
+const RequestCacheName = "request"
+
+const requestCacheCtxKey = constant.DubboCtxKey("$$requestCache")
+
+// requestScope holds the caches of the methods invoked in the scope
+type requestScope struct {
+	caches sync.Map
+}
+
+// WithRequestCache returns a context carrying a new scope of the request cache, the results of the methods
+// configured with cache: request are cached until the scope is dropped.
+func WithRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestCacheCtxKey, &requestScope{})
+}
+
+type requestCacheFactory struct{}
+
+func (f *requestCacheFactory) GetCache(ctx context.Context, url *common.URL, invocation protocol.Invocation) filter.Cache {
+	if ctx == nil {
+		return nil
+	}
+	scope, ok := ctx.Value(requestCacheCtxKey).(*requestScope)
+	if !ok {
+		return nil
+	}
+	key := url.ServiceKey() + "#" + invocation.MethodName()
+	if c, ok := scope.caches.Load(key); ok {
+		return c.(filter.Cache)
+	}
+	c, _ := scope.caches.LoadOrStore(key, &mapCache{})
+	return c.(filter.Cache)
+}
+
+// mapCache is an unbounded cache, which lives as long as the request
+type mapCache struct {
+	values sync.Map
+}
+
+func (c *mapCache) Get(key string) (interface{}, bool) {
+	return c.values.Load(key)
+}
+
+func (c *mapCache) Put(key string, value interface{}) {
+	c.values.Store(key, value)
+}
+
+func (c *mapCache) Clear() {
+	c.values.Range(func(key, _ interface{}) bool {
+		c.values.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCache(t *testing.T) {
+	f := newFilter()
+	invoker := newUserInvoker(t, "interface=com.ikurento.user.RequestProvider&cache=request")
+
+	// the invocations out of any request scope are not cached
+	f.Invoke(context.Background(), invoker, newUserInvocation("1"))
+	f.Invoke(context.Background(), invoker, newUserInvocation("1"))
+	assert.Equal(t, 2, invoker.invoked)
+
+	ctx := WithRequestCache(context.Background())
+	f.Invoke(ctx, invoker, newUserInvocation("1"))
+	inv := newUserInvocation("1")
+	f.Invoke(ctx, invoker, inv)
+	assert.Equal(t, 3, invoker.invoked)
+	assert.Equal(t, "user1", inv.Reply().(*user).Name)
+
+	// a new request has its own scope
+	f.Invoke(WithRequestCache(context.Background()), invoker, newUserInvocation("1"))
+	assert.Equal(t, 4, invoker.invoked)
+}
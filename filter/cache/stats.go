@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"sync"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+var (
+	// methodCaches are the caches of the methods created by the lru and expiring factories
+	methodCaches sync.Map
+	// methodStats are the hits and misses of the cached methods, keyed by interface#method
+	methodStats sync.Map
+)
+
+// methodCache is a cache of the method of a service
+type methodCache struct {
+	service string
+	method  string
+	cache   filter.Cache
+}
+
+// getOrCreateCache returns the cache of the method of the service of @url created by @factory, the cache is
+// created by @create on the first call
+func getOrCreateCache(factory string, url *common.URL, methodName string, create func() filter.Cache) filter.Cache {
+	key := factory + "#" + url.ServiceKey() + "#" + methodName
+	if c, ok := methodCaches.Load(key); ok {
+		return c.(*methodCache).cache
+	}
+	c, _ := methodCaches.LoadOrStore(key, &methodCache{service: url.Service(), method: methodName, cache: create()})
+	return c.(*methodCache).cache
+}
+
+// Invalidate clears the cached results of the @method of the @service, which is the interface name. The
+// results of all the methods of the service are cleared if @method is empty. The request caches are not
+// affected as they are dropped with the requests.
+func Invalidate(service, method string) {
+	methodCaches.Range(func(_, v interface{}) bool {
+		c := v.(*methodCache)
+		if c.service == service && (len(method) == 0 || c.method == method) {
+			c.cache.Clear()
+		}
+		return true
+	})
+}
+
+// Stats is the hits and misses of the cache of a method
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+type methodCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func getCounters(service, method string) *methodCounters {
+	key := service + "#" + method
+	if c, ok := methodStats.Load(key); ok {
+		return c.(*methodCounters)
+	}
+	c, _ := methodStats.LoadOrStore(key, &methodCounters{})
+	return c.(*methodCounters)
+}
+
+// GetStats returns the hits and misses of the cache of the @method of the @service, which is the interface name
+func GetStats(service, method string) Stats {
+	c, ok := methodStats.Load(service + "#" + method)
+	if !ok {
+		return Stats{}
+	}
+	counters := c.(*methodCounters)
+	return Stats{Hits: counters.hits.Load(), Misses: counters.misses.Load()}
+}
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package circuitbreaker provides a consumer filter which short-circuits the requests to the provider endpoints
+// whose circuit breaker is open.
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	once          sync.Once
+	breakerFilter *circuitBreakerFilter
+)
+
+func init() {
+	extension.SetFilter(constant.CircuitBreakerFilterKey, newCircuitBreakerFilter)
+}
+
+// circuitBreakerFilter fails fast with circuitbreaker.ErrCircuitOpen if the circuit breaker is open,
+// the failover cluster will try another invoker then.
+type circuitBreakerFilter struct{}
+
+func newCircuitBreakerFilter() filter.Filter {
+	if breakerFilter == nil {
+		once.Do(func() {
+			breakerFilter = &circuitBreakerFilter{}
+		})
+	}
+	return breakerFilter
+}
+
+// Invoke short-circuits the request if the circuit breaker of the method does not allow it
+func (f *circuitBreakerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	if !circuitbreaker.GetCircuitBreaker(url, invocation.MethodName()).Allow() {
+		return &protocol.RPCResult{
+			Err: perrors.Wrapf(circuitbreaker.ErrCircuitOpen, "the method %s of %s is short-circuited",
+				invocation.MethodName(), url.Location),
+		}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse reports the result of the request to the circuit breaker
+func (f *circuitBreakerFilter) OnResponse(ctx context.Context, result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	if circuitbreaker.IsCircuitOpen(result.Error()) {
+		// short-circuited by Invoke
+		return result
+	}
+	circuitbreaker.GetCircuitBreaker(invoker.GetURL(), invocation.MethodName()).OnResult(result.Error())
+	return result
+}
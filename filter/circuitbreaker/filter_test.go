@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type failedInvoker struct {
+	*protocol.BaseInvoker
+	invoked int
+}
+
+func (ivk *failedInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	return &protocol.RPCResult{Err: errors.New("request failed")}
+}
+
+func TestFilterShortCircuit(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider" +
+		"?circuit.breaker.consecutive.failures=2&circuit.breaker.open.time=1h")
+	invoker := &failedInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	f := newCircuitBreakerFilter()
+	defer circuitbreaker.Reset(url, "GetUser")
+
+	for i := 0; i < 2; i++ {
+		result := f.OnResponse(context.Background(), f.Invoke(context.Background(), invoker, inv), invoker, inv)
+		assert.False(t, circuitbreaker.IsCircuitOpen(result.Error()))
+	}
+	assert.Equal(t, circuitbreaker.StateOpen, circuitbreaker.GetCircuitBreaker(url, "GetUser").State())
+
+	result := f.OnResponse(context.Background(), f.Invoke(context.Background(), invoker, inv), invoker, inv)
+	assert.True(t, circuitbreaker.IsCircuitOpen(result.Error()))
+	assert.Equal(t, 2, invoker.invoked)
+	assert.Equal(t, circuitbreaker.StateOpen, circuitbreaker.GetCircuitBreaker(url, "GetUser").State())
+
+	// other methods are not affected
+	other := invocation.NewRPCInvocation("GetUsers", nil, nil)
+	result = f.Invoke(context.Background(), invoker, other)
+	assert.False(t, circuitbreaker.IsCircuitOpen(result.Error()))
+	assert.Equal(t, 3, invoker.invoked)
+}
@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package degradation provides a consumer filter which degrades the failed invocations of the methods configured
+// with a degradation policy, e.g. returns an empty list if the recommendation service fails.
+//
+// On the error classes listed in degradation.on, the filter returns the result of the fallback named by
+// degradation.fallback, or the json literal of degradation.default decoded into the reply, instead of the error.
+// With degradation.force, the invocations are degraded without calling the providers. The policies could be
+// updated by the degradation rules in the config center, see Rule.
+package degradation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	metricsRpc "dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// ReasonForce is the reason of the degradations forced by the policies
+	ReasonForce = "force"
+	// ClassTimeout is the error class of the timeouts
+	ClassTimeout = "timeout"
+	// ClassCircuitOpen is the error class of the invocations short-circuited by the circuit breakers
+	ClassCircuitOpen = "circuit-open"
+	// ClassNoProvider is the error class of the invocations without any available provider
+	ClassNoProvider = "no-provider"
+)
+
+var (
+	once        sync.Once
+	degradation *Filter
+
+	fallbacksLock sync.RWMutex
+	fallbacks     = make(map[string]Fallback)
+)
+
+func init() {
+	extension.SetFilter(constant.DegradationFilterKey, newFilter)
+}
+
+// Fallback returns the value of a degraded invocation, @err is nil if the degradation is forced
+type Fallback func(ctx context.Context, invocation protocol.Invocation, err error) (interface{}, error)
+
+// SetFallback registers the fallback named by degradation.fallback
+func SetFallback(name string, fallback Fallback) {
+	fallbacksLock.Lock()
+	defer fallbacksLock.Unlock()
+	fallbacks[name] = fallback
+}
+
+// GetFallback returns the fallback named @name, nil if it is not registered
+func GetFallback(name string) Fallback {
+	fallbacksLock.RLock()
+	defer fallbacksLock.RUnlock()
+	return fallbacks[name]
+}
+
+// Filter degrades the invocations by the degradation policies
+type Filter struct {
+	rules *rules
+}
+
+func newFilter() filter.Filter {
+	if degradation == nil {
+		once.Do(func() {
+			degradation = &Filter{rules: &rules{}}
+		})
+	}
+	return degradation
+}
+
+// Invoke degrades the invocation without calling the providers if the policy forces it
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	policy := f.rules.policyOf(invoker.GetURL(), invocation.MethodName())
+	if policy.forced() {
+		return f.degrade(ctx, invoker, invocation, policy, ReasonForce, nil)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse degrades the invocation if its error is of the classes of the policy
+func (f *Filter) OnResponse(ctx context.Context, result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	err := result.Error()
+	if err == nil {
+		return result
+	}
+	policy := f.rules.policyOf(invoker.GetURL(), invocation.MethodName())
+	if policy.forced() || (len(policy.Default) == 0 && len(policy.Fallback) == 0) {
+		// the forced result is degraded already
+		return result
+	}
+	class, ok := classify(err, policy.On)
+	if !ok {
+		return result
+	}
+	return f.degrade(ctx, invoker, invocation, policy, class, err)
+}
+
+// degrade returns the result of the fallback, or the default value of @policy, the original error is returned if
+// both fail
+func (f *Filter) degrade(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, policy Policy,
+	reason string, cause error) protocol.Result {
+	url := invoker.GetURL()
+	value, err := degradedValue(ctx, invocation, policy, cause)
+	if err != nil {
+		logger.Warnf("[degradation filter] failed to degrade the method %s of %s for %s, %v",
+			invocation.MethodName(), url.ServiceKey(), reason, err)
+		if cause == nil {
+			cause = err
+		}
+		return &protocol.RPCResult{Err: cause}
+	}
+	metrics.Publish(metricsRpc.NewDegradationEvent(url, invocation.MethodName(), reason))
+	logger.Debugf("[degradation filter] the method %s of %s is degraded for %s", invocation.MethodName(),
+		url.ServiceKey(), reason)
+	return newResult(invocation, value)
+}
+
+// degradedValue returns the value of the fallback, or the default value decoded into the reply of @invocation
+func degradedValue(ctx context.Context, invocation protocol.Invocation, policy Policy, cause error) (interface{}, error) {
+	if len(policy.Fallback) > 0 {
+		fallback := GetFallback(policy.Fallback)
+		if fallback == nil {
+			return nil, perrors.Errorf("the fallback %s is not registered", policy.Fallback)
+		}
+		return fallback(ctx, invocation, cause)
+	}
+	if len(policy.Default) == 0 {
+		// the zero value for the forced degradation without any default
+		return nil, nil
+	}
+	if reply := reflect.ValueOf(invocation.Reply()); reply.Kind() == reflect.Ptr && !reply.IsNil() {
+		// decode into a new value as the reply is left untouched on failure
+		value := reflect.New(reply.Elem().Type())
+		if err := json.Unmarshal([]byte(policy.Default), value.Interface()); err != nil {
+			return nil, perrors.Wrapf(err, "decode the default %s", policy.Default)
+		}
+		return value.Elem().Interface(), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(policy.Default), &value); err != nil {
+		return nil, perrors.Wrapf(err, "decode the default %s", policy.Default)
+	}
+	return value, nil
+}
+
+// newResult returns the result of @value, which is copied into the reply of @invocation if any
+func newResult(invocation protocol.Invocation, value interface{}) protocol.Result {
+	result := &protocol.RPCResult{Rest: value}
+	reply := reflect.ValueOf(invocation.Reply())
+	if reply.Kind() != reflect.Ptr || reply.IsNil() {
+		return result
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		reply.Elem().Set(reflect.Zero(reply.Elem().Type()))
+	case v.Type().AssignableTo(reply.Elem().Type()):
+		reply.Elem().Set(v)
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type().AssignableTo(reply.Elem().Type()):
+		reply.Elem().Set(v.Elem())
+	default:
+		logger.Warnf("[degradation filter] the degraded value of %T can not be set into the reply of %T of the method %s",
+			value, invocation.Reply(), invocation.MethodName())
+		return result
+	}
+	result.Rest = invocation.Reply()
+	return result
+}
+
+// classify returns the class of @err in @classes, false if it is none of them
+func classify(err error, classes []string) (string, bool) {
+	for _, class := range classes {
+		class = strings.ToLower(strings.TrimSpace(class))
+		switch class {
+		case ClassTimeout:
+			if common.CodeOf(err) == common.StatusTimeout {
+				return class, true
+			}
+		case ClassCircuitOpen:
+			if circuitbreaker.IsCircuitOpen(err) {
+				return class, true
+			}
+		case ClassNoProvider:
+			if errors.Is(err, protocol.ErrNoProvider) {
+				return class, true
+			}
+		default:
+			if code, ok := common.ParseRPCStatus(class); ok && common.CodeOf(err) == code {
+				return class, true
+			}
+		}
+	}
+	return "", false
+}
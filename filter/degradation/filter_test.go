@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package degradation
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/circuitbreaker"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const recommendationRuleKey = "org.apache.dubbo.Recommendation::.degradation"
+
+type item struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// failingInvoker fails with err, and counts the invocations
+type failingInvoker struct {
+	*protocol.BaseInvoker
+	err   error
+	calls int
+}
+
+func newFailingInvoker(t *testing.T, params string, err error) *failingInvoker {
+	url, err2 := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.Recommendation?" +
+		"interface=org.apache.dubbo.Recommendation&" + params)
+	assert.NoError(t, err2)
+	return &failingInvoker{BaseInvoker: protocol.NewBaseInvoker(url), err: err}
+}
+
+func (i *failingInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	i.calls++
+	return &protocol.RPCResult{Err: i.err}
+}
+
+// call invokes @method by @f like the filter chain, with a reply of []item
+func call(f *Filter, invoker protocol.Invoker, method string) (protocol.Result, *[]item) {
+	reply := &[]item{}
+	inv := invocation.NewRPCInvocation(method, nil, nil)
+	inv.SetReply(reply)
+	ctx := context.Background()
+	return f.OnResponse(ctx, f.Invoke(ctx, invoker, inv), invoker, inv), reply
+}
+
+func TestFilterDefault(t *testing.T) {
+	ch := make(chan metrics.MetricsEvent, 10)
+	metrics.Subscribe(constant.MetricsRpc, ch)
+
+	f := &Filter{rules: &rules{}}
+	invoker := newFailingInvoker(t, `methods.List.degradation.default=[{"id":1,"name":"hot"}]`,
+		perrors.Wrap(protocol.ErrNoProvider, "invoke List"))
+
+	result, reply := call(f, invoker, "List")
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []item{{ID: 1, Name: "hot"}}, *reply)
+	assert.Equal(t, reply, result.Result())
+	assert.Len(t, ch, 1)
+
+	// the method without any default
+	result, _ = call(f, invoker, "Top")
+	assert.True(t, perrors.Is(result.Error(), protocol.ErrNoProvider))
+	assert.Equal(t, 2, invoker.calls)
+}
+
+func TestFilterErrorClasses(t *testing.T) {
+	f := &Filter{rules: &rules{}}
+	for _, c := range []struct {
+		on       string
+		err      error
+		degraded bool
+	}{
+		{on: "", err: common.NewRPCError(common.StatusTimeout, perrors.New("timeout")), degraded: true},
+		{on: "", err: perrors.Wrap(circuitbreaker.ErrCircuitOpen, "List"), degraded: true},
+		{on: "", err: perrors.New("biz error"), degraded: false},
+		{on: "&degradation.on=timeout", err: perrors.Wrap(protocol.ErrNoProvider, "List"), degraded: false},
+		{on: "&degradation.on=biz", err: common.NewRPCError(common.StatusBiz, perrors.New("biz")), degraded: true},
+	} {
+		invoker := newFailingInvoker(t, "degradation.default=[]"+c.on, c.err)
+		result, _ := call(f, invoker, "List")
+		assert.Equal(t, c.degraded, result.Error() == nil, "%s %v", c.on, c.err)
+	}
+}
+
+func TestFilterFallback(t *testing.T) {
+	SetFallback("recommend-local", func(_ context.Context, inv protocol.Invocation, err error) (interface{}, error) {
+		if err == nil {
+			return nil, perrors.New("not forced")
+		}
+		return []item{{ID: 2, Name: inv.MethodName()}}, nil
+	})
+	f := &Filter{rules: &rules{}}
+	invoker := newFailingInvoker(t, "degradation.fallback=recommend-local&degradation.default=[]",
+		common.NewRPCError(common.StatusTimeout, perrors.New("timeout")))
+
+	// the fallback is preferred to the default
+	result, reply := call(f, invoker, "List")
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []item{{ID: 2, Name: "List"}}, *reply)
+
+	// the original error is returned if the fallback fails
+	invoker = newFailingInvoker(t, "degradation.fallback=recommend-absent",
+		common.NewRPCError(common.StatusTimeout, perrors.New("timeout")))
+	result, _ = call(f, invoker, "List")
+	assert.Equal(t, common.StatusTimeout, common.CodeOf(result.Error()))
+}
+
+func TestFilterForce(t *testing.T) {
+	f := &Filter{rules: &rules{}}
+	invoker := newFailingInvoker(t, "methods.List.degradation.default=[]", nil)
+
+	// force the service by the rule
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: recommendationRuleKey, ConfigType: remoting.EventTypeAdd,
+		Value: "force: true\nmethods:\n  Top:\n    default: '[{\"id\":3}]'\n"})
+	result, reply := call(f, invoker, "List")
+	assert.NoError(t, result.Error())
+	assert.Empty(t, *reply)
+	result, reply = call(f, invoker, "Top")
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []item{{ID: 3}}, *reply)
+	assert.Equal(t, 0, invoker.calls)
+
+	// a malformed rule is ignored
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: recommendationRuleKey, ConfigType: remoting.EventTypeUpdate,
+		Value: "force: [true"})
+	call(f, invoker, "List")
+	assert.Equal(t, 0, invoker.calls)
+
+	// the method is not forced
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: recommendationRuleKey, ConfigType: remoting.EventTypeUpdate,
+		Value: "force: true\nmethods:\n  List:\n    force: false\n"})
+	call(f, invoker, "List")
+	assert.Equal(t, 1, invoker.calls)
+
+	// recover by deleting the rule
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: recommendationRuleKey, ConfigType: remoting.EventTypeDel})
+	call(f, invoker, "Top")
+	assert.Equal(t, 2, invoker.calls)
+}
+
+func TestFilterInvalidDefault(t *testing.T) {
+	f := &Filter{rules: &rules{}}
+	invoker := newFailingInvoker(t, "degradation.default={", perrors.Wrap(protocol.ErrNoProvider, "List"))
+
+	result, reply := call(f, invoker, "List")
+	assert.True(t, perrors.Is(result.Error(), protocol.ErrNoProvider))
+	assert.Empty(t, *reply)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package degradation
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Policy is the degradation policy of a service or a method. The absent fields of the policy of a method fall back
+// to the policy of the service.
+type Policy struct {
+	// On are the error classes degraded, timeout, circuit-open, no-provider or the names of the error codes
+	On []string `yaml:"on"`
+	// Default is the json literal of the value returned on degradation
+	Default string `yaml:"default"`
+	// Fallback is the name of the fallback registered by SetFallback, which is preferred to Default
+	Fallback string `yaml:"fallback"`
+	// Force degrades the invocations without calling the providers
+	Force *bool `yaml:"force"`
+}
+
+// Rule is the degradation rule in the config center, e.g.
+//
+//	force: true
+//	methods:
+//	  GetRecommendations:
+//	    on: [timeout, no-provider]
+//	    default: "[]"
+//
+// The rule of a service is keyed by service:version:group.degradation, it takes precedence over the static config
+// of the service.
+type Rule struct {
+	Policy  `yaml:",inline"`
+	Methods map[string]*Policy `yaml:"methods"`
+}
+
+func parseRule(content string) (*Rule, error) {
+	rule := &Rule{}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// merge overrides the fields of @p with the present fields of @override
+func (p Policy) merge(override *Policy) Policy {
+	if override == nil {
+		return p
+	}
+	if len(override.On) > 0 {
+		p.On = override.On
+	}
+	if len(override.Default) > 0 {
+		p.Default = override.Default
+	}
+	if len(override.Fallback) > 0 {
+		p.Fallback = override.Fallback
+	}
+	if override.Force != nil {
+		p.Force = override.Force
+	}
+	return p
+}
+
+// forced returns whether the invocations are degraded without calling the providers
+func (p Policy) forced() bool {
+	return p.Force != nil && *p.Force
+}
+
+// rules holds the degradation rules in the config center, the rules are subscribed on the first invocation of
+// the services
+type rules struct {
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+	// dynamic are the rules keyed by the rule key
+	dynamic sync.Map
+}
+
+// policyOf returns the degradation policy of @method of the service with @url. The policy of the method in the rule
+// is preferred, then the one of the service in the rule, the one of the method in the static config and the one of
+// the service in the static config at last.
+func (r *rules) policyOf(url *common.URL, method string) Policy {
+	p := Policy{
+		On: strings.Split(url.GetMethodParam(method, constant.DegradationOnKey,
+			url.GetParam(constant.DegradationOnKey, constant.DefaultDegradationOn)), ","),
+		Default:  url.GetMethodParam(method, constant.DegradationDefaultKey, url.GetParam(constant.DegradationDefaultKey, "")),
+		Fallback: url.GetMethodParam(method, constant.DegradationFallbackKey, url.GetParam(constant.DegradationFallbackKey, "")),
+	}
+	if force := url.GetMethodParamBool(method, constant.DegradationForceKey,
+		url.GetParamBool(constant.DegradationForceKey, false)); force {
+		p.Force = &force
+	}
+	if rule := r.load(config_center.ServiceRuleKey(url, constant.DegradationRuleSuffix)); rule != nil {
+		p = p.merge(&rule.Policy).merge(rule.Methods[method])
+	}
+	return p
+}
+
+// load returns the rule of @key, and subscribes the rule if it is not subscribed yet
+func (r *rules) load(key string) *Rule {
+	if rule, ok := r.dynamic.Load(key); ok {
+		return rule.(*Rule)
+	}
+	if !r.subscriptions.Subscribe(conf.GetEnvInstance().GetDynamicConfiguration(), key, r, 0) {
+		return nil
+	}
+	if rule, ok := r.dynamic.Load(key); ok {
+		return rule.(*Rule)
+	}
+	return nil
+}
+
+// Process applies the changed rule immediately. A malformed rule is ignored and the previous rule is kept.
+func (r *rules) Process(event *config_center.ConfigChangeEvent) {
+	content, ok := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || !ok || len(content) == 0 {
+		r.dynamic.Delete(event.Key)
+		return
+	}
+	rule, err := parseRule(content)
+	if err != nil {
+		logger.Warnf("[degradation filter]Parse degradation rule %s error, %v, the previous rule is kept.", event.Key, err)
+		return
+	}
+	r.dynamic.Store(event.Key, rule)
+	logger.Infof("[degradation filter]Parse degradation rule success,key=%s,force=%v,methods=%d",
+		event.Key, rule.forced(), len(rule.Methods))
+}
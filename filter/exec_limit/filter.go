@@ -25,20 +25,24 @@
    ... # other configuration
    execute.limit: 200 # the name of MethodServiceTpsLimiterImpl. if the value < 0, invocation will be ignored.
    execute.limit.rejected.handle: "default" # the name of rejected handler
+   execute.limit.queue: 50 # at most 50 invocations wait for a slot, they are rejected at once if it is 0 by default
+   execute.limit.queue.timeout: 200ms # an invocation waits for a slot for 200ms at most, 100ms by default
    methods:
     - name: "GetUser"
-      execute.limit: 20, # in this case, this configuration in service-level will be ignored.
+      execute.limit: 20, # in this case, both this configuration and the one in service-level are enforced.
     - name: "UpdateUser"
-      execute.limit: -1, # If the rate<0, the method will be ignored
+      execute.limit: -1, # If the rate<0, the method will only be limited by the service-level configuration
     - name: "DeleteUser"
       execute.limit.rejected.handle: "customHandler" # Using the custom handler to do something when the request was rejected.
     - name: "AddUser"
  From the example, the configuration in service-level is 200, and the configuration of method GetUser is 20.
- it means that, the GetUser will be counted separately.
- The configuration of method UpdateUser is -1, so the invocation for it will not be counted.
- So the method DeleteUser and method AddUser will be limited by service-level configuration.
+ it means that, the GetUser will be counted separately, and counted in the service-level too.
+ So the method UpdateUser, DeleteUser and AddUser will be limited by service-level configuration only.
  Sometimes we want to do something, like log the request or return default value when the request is over limitation.
  Then you can implement the RejectedExecutionHandler interface and register it by invoking SetRejectedExecutionHandler.
+ The "abort" handler returns a throttled error, and the "default" one only logs the rejection.
+ The active and the queued invocations of the limits are reported by the metrics dubbo_provider_execute_active
+ and dubbo_provider_execute_queued.
 */
 package exec_limit
 
@@ -47,6 +51,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 import (
@@ -56,22 +61,21 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/handler"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
-var (
-	once         sync.Once
-	executeLimit *executeLimitFilter
-)
-
 func init() {
-	extension.SetFilter(constant.ExecuteLimitFilterKey, newFilter)
+	extension.SetFilterFactory(constant.ExecuteLimitFilterKey, newFilter)
 }
 
+// executeLimitFilter is created per exported service, so the services are limited independently
 type executeLimitFilter struct {
 	executeState *concurrent.Map
 }
@@ -79,65 +83,86 @@ type executeLimitFilter struct {
 // ExecuteState defines the concurrent count
 type ExecuteState struct {
 	concurrentCount int64
+	queuedCount     int64
+	// released is closed and replaced on a release if any invocation is waiting, to wake up the waiting ones
+	mu       sync.Mutex
+	released chan struct{}
 }
 
-// newFilter returns the singleton Filter instance
-func newFilter() filter.Filter {
-	if executeLimit == nil {
-		once.Do(func() {
-			executeLimit = &executeLimitFilter{
-				executeState: concurrent.NewMap(),
-			}
-		})
+// newFilter returns the Filter of the service of @url
+func newFilter(_ *common.URL) filter.Filter {
+	return &executeLimitFilter{
+		executeState: concurrent.NewMap(),
 	}
-	return executeLimit
 }
 
-// Invoke judges whether the current processing requests over the threshold
+// Invoke judges whether the current processing requests over the threshold of the method or the service,
+// the invocation waits for a slot in the queue if it is configured
 func (f *executeLimitFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
-	methodConfigPrefix := "methods." + invocation.MethodName() + "."
+	methodName := invocation.MethodName()
 	ivkURL := invoker.GetURL()
-	limitTarget := ivkURL.ServiceKey()
-	var limitRateConfig string
-
-	methodLevelConfig := ivkURL.GetParam(methodConfigPrefix+constant.ExecuteLimitKey, "")
-	if len(methodLevelConfig) > 0 {
-		// we have the method-level configuration
-		limitTarget = limitTarget + "#" + invocation.MethodName()
-		limitRateConfig = methodLevelConfig
-	} else {
-		limitRateConfig = ivkURL.GetParam(constant.ExecuteLimitKey, constant.DefaultExecuteLimit)
-	}
 
-	limitRate, err := strconv.ParseInt(limitRateConfig, 0, 0)
+	methodLimit, err := parseLimit(ivkURL.GetMethodParam(methodName, constant.ExecuteLimitKey, constant.DefaultExecuteLimit))
 	if err != nil {
-		logger.Errorf("The configuration of execute.limit is invalid: %s", limitRateConfig)
 		return &protocol.RPCResult{}
 	}
+	serviceLimit, err := parseLimit(ivkURL.GetParam(constant.ExecuteLimitKey, constant.DefaultExecuteLimit))
+	if err != nil {
+		return &protocol.RPCResult{}
+	}
+	queueSize := ivkURL.GetMethodParamInt64(methodName, constant.ExecuteLimitQueueKey, 0)
+	queueTimeout := ivkURL.GetMethodParamDuration(methodName, constant.ExecuteLimitQueueTimeoutKey,
+		ivkURL.GetParamDurationWithDefault(constant.ExecuteLimitQueueTimeoutKey, constant.DefaultExecuteLimitQueueTimeout))
+
+	// the method-level limit is acquired before the service-level one, so that the waiting invocations of a
+	// method do not hold the slots of the service
+	if methodLimit >= 0 {
+		state := f.stateOf(ivkURL.ServiceKey() + "#" + methodName)
+		acquired := state.acquire(methodLimit, queueSize, queueTimeout)
+		reportState(ivkURL, methodName, state)
+		if !acquired {
+			return f.reject(ivkURL, invocation)
+		}
+		defer state.release(ivkURL, methodName)
+	}
+	if serviceLimit >= 0 {
+		state := f.stateOf(ivkURL.ServiceKey())
+		acquired := state.acquire(serviceLimit, queueSize, queueTimeout)
+		reportState(ivkURL, "", state)
+		if !acquired {
+			return f.reject(ivkURL, invocation)
+		}
+		defer state.release(ivkURL, "")
+	}
+	return invoker.Invoke(ctx, invocation)
+}
 
-	if limitRate < 0 {
-		return invoker.Invoke(ctx, invocation)
+func parseLimit(limitRateConfig string) (int64, error) {
+	limitRate, err := strconv.ParseInt(limitRateConfig, 0, 0)
+	if err != nil {
+		logger.Errorf("The configuration of execute.limit is invalid: %s", limitRateConfig)
 	}
+	return limitRate, err
+}
 
+func (f *executeLimitFilter) stateOf(limitTarget string) *ExecuteState {
 	state, _ := f.executeState.LoadOrStore(limitTarget, &ExecuteState{
-		concurrentCount: 0,
+		released: make(chan struct{}),
 	})
+	return state.(*ExecuteState)
+}
 
-	concurrentCount := state.(*ExecuteState).increase()
-	defer state.(*ExecuteState).decrease()
-	if concurrentCount > limitRate {
-		logger.Errorf("The invocation was rejected due to over the execute limitation, url: %s ", ivkURL.String())
-		rejectedHandlerConfig := ivkURL.GetParam(methodConfigPrefix+constant.ExecuteRejectedExecutionHandlerKey,
-			ivkURL.GetParam(constant.ExecuteRejectedExecutionHandlerKey, constant.DefaultKey))
-		rejectedExecutionHandler, err := extension.GetRejectedExecutionHandler(rejectedHandlerConfig)
-		if err != nil {
-			logger.Warn(err)
-		} else {
-			return rejectedExecutionHandler.RejectedExecution(ivkURL, invocation)
-		}
+// reject returns the result of the rejected execution handler of the method or the service
+func (f *executeLimitFilter) reject(ivkURL *common.URL, invocation protocol.Invocation) protocol.Result {
+	logger.Errorf("The invocation was rejected due to over the execute limitation, url: %s ", ivkURL.String())
+	rejectedHandlerConfig := ivkURL.GetMethodParam(invocation.MethodName(), constant.ExecuteRejectedExecutionHandlerKey,
+		ivkURL.GetParam(constant.ExecuteRejectedExecutionHandlerKey, constant.DefaultKey))
+	rejectedExecutionHandler, err := extension.GetRejectedExecutionHandler(rejectedHandlerConfig)
+	if err != nil {
+		logger.Warn(err)
+		return &protocol.RPCResult{}
 	}
-
-	return invoker.Invoke(ctx, invocation)
+	return rejectedExecutionHandler.RejectedExecution(ivkURL, invocation)
 }
 
 // OnResponse dummy process, returns the result directly
@@ -145,10 +170,76 @@ func (f *executeLimitFilter) OnResponse(_ context.Context, result protocol.Resul
 	return result
 }
 
-func (state *ExecuteState) increase() int64 {
-	return atomic.AddInt64(&state.concurrentCount, 1)
+// Active returns the count of the invocations being executed
+func (state *ExecuteState) Active() int64 {
+	return atomic.LoadInt64(&state.concurrentCount)
 }
 
-func (state *ExecuteState) decrease() {
+// Queued returns the count of the invocations waiting for a slot
+func (state *ExecuteState) Queued() int64 {
+	return atomic.LoadInt64(&state.queuedCount)
+}
+
+// acquire takes a slot if there are less than @limit invocations being executed, or waits for one for
+// @queueTimeout at most if there are less than @queueSize invocations waiting
+func (state *ExecuteState) acquire(limit, queueSize int64, queueTimeout time.Duration) bool {
+	if state.tryAcquire(limit) {
+		return true
+	}
+	if queueSize <= 0 || queueTimeout <= 0 {
+		return false
+	}
+	if atomic.AddInt64(&state.queuedCount, 1) > queueSize {
+		atomic.AddInt64(&state.queuedCount, -1)
+		return false
+	}
+	defer atomic.AddInt64(&state.queuedCount, -1)
+
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+	for {
+		// take the channel before trying, so that a release after the try is not missed
+		state.mu.Lock()
+		released := state.released
+		state.mu.Unlock()
+		if state.tryAcquire(limit) {
+			return true
+		}
+		select {
+		case <-released:
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+func (state *ExecuteState) tryAcquire(limit int64) bool {
+	for {
+		count := atomic.LoadInt64(&state.concurrentCount)
+		if count >= limit {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&state.concurrentCount, count, count+1) {
+			return true
+		}
+	}
+}
+
+// release returns the slot and wakes up the waiting invocations, it is deferred so that the slot is returned
+// even if the invocation panics
+func (state *ExecuteState) release(ivkURL *common.URL, methodName string) {
 	atomic.AddInt64(&state.concurrentCount, -1)
+	if atomic.LoadInt64(&state.queuedCount) > 0 {
+		state.mu.Lock()
+		close(state.released)
+		state.released = make(chan struct{})
+		state.mu.Unlock()
+	}
+	reportState(ivkURL, methodName, state)
+}
+
+// reportState reports the active and the queued invocations of the limit of @methodName, or of the service if
+// it is empty
+func reportState(ivkURL *common.URL, methodName string, state *ExecuteState) {
+	metrics.Publish(rpc.NewExecuteStateEvent(ivkURL, methodName, state.Active(), state.Queued()))
 }
@@ -19,17 +19,22 @@ package exec_limit
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"testing"
+	"time"
 )
 
 import (
+	"github.com/modern-go/concurrent"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter/handler"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
@@ -42,7 +47,7 @@ func TestFilterInvokeIgnored(t *testing.T) {
 		common.WithParams(url.Values{}),
 		common.WithParamsValue(constant.InterfaceKey, methodName))
 
-	limitFilter := newFilter()
+	limitFilter := newFilter(invokeUrl)
 
 	result := limitFilter.Invoke(context.Background(), protocol.NewBaseInvoker(invokeUrl), invoc)
 	assert.NotNil(t, result)
@@ -59,7 +64,7 @@ func TestFilterInvokeConfigureError(t *testing.T) {
 		common.WithParamsValue(constant.ExecuteLimitKey, "13a"),
 	)
 
-	limitFilter := newFilter()
+	limitFilter := newFilter(invokeUrl)
 
 	result := limitFilter.Invoke(context.Background(), protocol.NewBaseInvoker(invokeUrl), invoc)
 	assert.NotNil(t, result)
@@ -76,9 +81,155 @@ func TestFilterInvoke(t *testing.T) {
 		common.WithParamsValue(constant.ExecuteLimitKey, "20"),
 	)
 
-	limitFilter := newFilter()
+	limitFilter := newFilter(invokeUrl)
 
 	result := limitFilter.Invoke(context.Background(), protocol.NewBaseInvoker(invokeUrl), invoc)
 	assert.NotNil(t, result)
 	assert.Nil(t, result.Error())
 }
+
+// blockingInvoker blocks the invocations until they are released
+type blockingInvoker struct {
+	*protocol.BaseInvoker
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingInvoker(t *testing.T, params string) *blockingInvoker {
+	invokeUrl, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&execute.limit.rejected.handler=abort&" + params)
+	assert.NoError(t, err)
+	return &blockingInvoker{
+		BaseInvoker: protocol.NewBaseInvoker(invokeUrl),
+		entered:     make(chan struct{}, 10),
+		release:     make(chan struct{}),
+	}
+}
+
+func (i *blockingInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	if inv.MethodName() == "Panic" {
+		panic("panic in the service")
+	}
+	i.entered <- struct{}{}
+	<-i.release
+	return &protocol.RPCResult{}
+}
+
+func newTestFilter() *executeLimitFilter {
+	return &executeLimitFilter{executeState: concurrent.NewMap()}
+}
+
+// invokeAsync invokes @method in a goroutine, and returns the channel of the result
+func invokeAsync(f *executeLimitFilter, invoker protocol.Invoker, method string) chan protocol.Result {
+	results := make(chan protocol.Result, 1)
+	go func() {
+		results <- f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation(method, nil, nil))
+	}()
+	return results
+}
+
+func assertRejected(t *testing.T, result protocol.Result) {
+	assert.True(t, errors.Is(result.Error(), handler.ErrRejectedExecution))
+}
+
+func TestFilterInvokeAbort(t *testing.T) {
+	f := newTestFilter()
+	invoker := newBlockingInvoker(t, "execute.limit=1")
+
+	first := invokeAsync(f, invoker, "GetUser")
+	<-invoker.entered
+	assertRejected(t, <-invokeAsync(f, invoker, "GetUser"))
+
+	close(invoker.release)
+	assert.Nil(t, (<-first).Error())
+	assert.Nil(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+}
+
+func TestFilterInvokeQueue(t *testing.T) {
+	f := newTestFilter()
+	invoker := newBlockingInvoker(t, "execute.limit=1&execute.limit.queue=1&execute.limit.queue.timeout=1s")
+	state := f.stateOf(invoker.GetURL().ServiceKey())
+
+	first := invokeAsync(f, invoker, "GetUser")
+	<-invoker.entered
+	queued := invokeAsync(f, invoker, "GetUser")
+	assert.Eventually(t, func() bool { return state.Queued() == 1 }, time.Second, time.Millisecond)
+	// the queue is full
+	assertRejected(t, <-invokeAsync(f, invoker, "GetUser"))
+
+	// the queued invocation takes the slot released by the first one
+	invoker.release <- struct{}{}
+	assert.Nil(t, (<-first).Error())
+	<-invoker.entered
+	assert.Equal(t, int64(0), state.Queued())
+	assert.Equal(t, int64(1), state.Active())
+	close(invoker.release)
+	assert.Nil(t, (<-queued).Error())
+	assert.Equal(t, int64(0), state.Active())
+}
+
+func TestFilterInvokeQueueTimeout(t *testing.T) {
+	f := newTestFilter()
+	invoker := newBlockingInvoker(t, "execute.limit=1&execute.limit.queue=1&execute.limit.queue.timeout=50ms")
+	defer close(invoker.release)
+
+	invokeAsync(f, invoker, "GetUser")
+	<-invoker.entered
+	start := time.Now()
+	assertRejected(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.Equal(t, int64(0), f.stateOf(invoker.GetURL().ServiceKey()).Queued())
+}
+
+func TestFilterInvokeMethodAndServiceLimit(t *testing.T) {
+	f := newTestFilter()
+	invoker := newBlockingInvoker(t, "execute.limit=2&methods.GetUser.execute.limit=1")
+	defer close(invoker.release)
+
+	// the limit of the method
+	invokeAsync(f, invoker, "GetUser")
+	<-invoker.entered
+	assertRejected(t, <-invokeAsync(f, invoker, "GetUser"))
+
+	// the limit of the service is enforced for the method too
+	invokeAsync(f, invoker, "ListUsers")
+	<-invoker.entered
+	assertRejected(t, <-invokeAsync(f, invoker, "ListUsers"))
+	assert.Equal(t, int64(2), f.stateOf(invoker.GetURL().ServiceKey()).Active())
+	assert.Equal(t, int64(1), f.stateOf(invoker.GetURL().ServiceKey()+"#GetUser").Active())
+}
+
+func TestFilterInvokePanic(t *testing.T) {
+	f := newTestFilter()
+	invoker := newBlockingInvoker(t, "execute.limit=1&methods.Panic.execute.limit=1")
+
+	for i := 0; i < 2; i++ {
+		assert.Panics(t, func() {
+			f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("Panic", nil, nil))
+		})
+	}
+	// the slots are released even if the invocation panics
+	assert.Equal(t, int64(0), f.stateOf(invoker.GetURL().ServiceKey()).Active())
+	assert.Equal(t, int64(0), f.stateOf(invoker.GetURL().ServiceKey()+"#Panic").Active())
+}
+
+func TestFilterInvokeIndependentServices(t *testing.T) {
+	userInvoker := newBlockingInvoker(t, "execute.limit=1&group=user")
+	orderInvoker := newBlockingInvoker(t, "execute.limit=1&group=order")
+	defer close(userInvoker.release)
+	defer close(orderInvoker.release)
+	userFilter := newFilter(userInvoker.GetURL()).(*executeLimitFilter)
+	orderFilter := newFilter(orderInvoker.GetURL()).(*executeLimitFilter)
+
+	invokeAsync(userFilter, userInvoker, "GetUser")
+	<-userInvoker.entered
+	assertRejected(t, <-invokeAsync(userFilter, userInvoker, "GetUser"))
+
+	// the filter of the other service has its own state
+	invokeAsync(orderFilter, orderInvoker, "GetOrder")
+	<-orderInvoker.entered
+	assert.Equal(t, int64(1), userFilter.stateOf(userInvoker.GetURL().ServiceKey()).Active())
+	assert.Equal(t, int64(1), orderFilter.stateOf(orderInvoker.GetURL().ServiceKey()).Active())
+	_, found := userFilter.executeState.Load(orderInvoker.GetURL().ServiceKey())
+	assert.False(t, found)
+}
@@ -82,7 +82,8 @@ func (f *genericServiceFilter) Invoke(ctx context.Context, invoker protocol.Invo
 	method := svc.Method()[mtdname]
 	if method == nil {
 		return &protocol.RPCResult{
-			Err: perrors.Errorf("\"%s\" method is not found, service key: %s", mtdname, ivkUrl.ServiceKey()),
+			Err: common.NewRPCError(common.StatusServiceNotFound,
+				perrors.Errorf("\"%s\" method is not found, service key: %s", mtdname, ivkUrl.ServiceKey())),
 		}
 	}
 	argsType := method.ArgsType()
@@ -94,7 +95,8 @@ func (f *genericServiceFilter) Invoke(ctx context.Context, invoker protocol.Invo
 
 	if len(args) != len(argsType) {
 		return &protocol.RPCResult{
-			Err: perrors.Errorf("the number of args(=%d) is not matched with \"%s\" method", len(args), mtdname),
+			Err: common.NewRPCError(common.StatusSerialization,
+				perrors.Errorf("the number of args(=%d) is not matched with \"%s\" method", len(args), mtdname)),
 		}
 	}
 
@@ -104,7 +106,7 @@ func (f *genericServiceFilter) Invoke(ctx context.Context, invoker protocol.Invo
 		newarg, err := g.Realize(args[i], argsType[i])
 		if err != nil {
 			return &protocol.RPCResult{
-				Err: perrors.Errorf("realization failed, %v", err),
+				Err: common.NewRPCError(common.StatusSerialization, perrors.Errorf("realization failed, %v", err)),
 			}
 		}
 		newargs[i] = newarg
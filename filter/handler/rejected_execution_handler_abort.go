@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// AbortHandlerName handler name
+	AbortHandlerName = "abort"
+)
+
+// ErrRejectedExecution is the error returned by AbortRejectedExecutionHandler
+var ErrRejectedExecution = errors.New("rejected execution")
+
+func init() {
+	extension.SetRejectedExecutionHandler(AbortHandlerName, GetAbortRejectedExecutionHandler)
+}
+
+var (
+	abortHandlerInstance *AbortRejectedExecutionHandler
+	abortHandlerOnce     sync.Once
+)
+
+// AbortRejectedExecutionHandler implements the RejectedExecutionHandler
+/**
+ * This implementation aborts the invocation with a throttled error at once, which is safe for the consumer to
+ * retry on another provider.
+ * "UserProvider":
+ *   registry: "hangzhouzk"
+ *   protocol : "dubbo"
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   execute.limit: 200
+ *   execute.limit.rejected.handler: "abort"
+ * AbortRejectedExecutionHandler is designed to be singleton
+ */
+type AbortRejectedExecutionHandler struct{}
+
+// RejectedExecution returns the throttled error
+func (handler *AbortRejectedExecutionHandler) RejectedExecution(url *common.URL,
+	invocation protocol.Invocation) protocol.Result {
+
+	return &protocol.RPCResult{
+		Err: common.NewRPCError(common.StatusThrottled, fmt.Errorf("%w: the invocation of %s#%s is over the limitation",
+			ErrRejectedExecution, url.ServiceKey(), invocation.MethodName())),
+	}
+}
+
+// GetAbortRejectedExecutionHandler will return the instance of AbortRejectedExecutionHandler
+func GetAbortRejectedExecutionHandler() filter.RejectedExecutionHandler {
+	abortHandlerOnce.Do(func() {
+		abortHandlerInstance = &AbortRejectedExecutionHandler{}
+	})
+	return abortHandlerInstance
+}
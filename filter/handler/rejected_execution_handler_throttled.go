@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// ThrottledHandlerName handler name
+	ThrottledHandlerName = "throttled"
+)
+
+func init() {
+	extension.SetRejectedExecutionHandler(ThrottledHandlerName, GetThrottledRejectedExecutionHandler)
+}
+
+var (
+	throttledHandlerInstance *ThrottledRejectedExecutionHandler
+	throttledHandlerOnce     sync.Once
+)
+
+// ThrottledRejectedExecutionHandler implements the RejectedExecutionHandler
+/**
+ * This implementation returns a throttled error, which is safe for the consumer to retry, with the
+ * retry-after attachment of the milliseconds to retry after. The hint is the interval of the exceeded
+ * limit, or the configured tps.limit.interval.
+ * "UserProvider":
+ *   registry: "hangzhouzk"
+ *   protocol : "dubbo"
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   tps.limiter: "method-service" # the name of limiter
+ *   tps.limit.rejected.handler: "throttled"
+ * ThrottledRejectedExecutionHandler is designed to be singleton
+ */
+type ThrottledRejectedExecutionHandler struct{}
+
+// RejectedExecution returns the throttled error with the retry-after hint
+func (handler *ThrottledRejectedExecutionHandler) RejectedExecution(url *common.URL,
+	invocation protocol.Invocation) protocol.Result {
+
+	interval, _ := url.GetParamDuration(constant.TPSLimitIntervalKey)
+	retryAfter := interval.Milliseconds()
+	if v, ok := invocation.GetAttribute(constant.RetryAfterKey); ok {
+		if interval, ok := v.(int64); ok {
+			retryAfter = interval
+		}
+	}
+	result := &protocol.RPCResult{
+		Err: common.NewRPCError(common.StatusThrottled, fmt.Errorf(
+			"the invocation of %s#%s is throttled, please retry after %dms", url.ServiceKey(), invocation.MethodName(), retryAfter)),
+	}
+	if retryAfter > 0 {
+		result.AddAttachment(constant.RetryAfterKey, strconv.FormatInt(retryAfter, 10))
+	}
+	return result
+}
+
+// GetThrottledRejectedExecutionHandler will return the instance of ThrottledRejectedExecutionHandler
+func GetThrottledRejectedExecutionHandler() filter.RejectedExecutionHandler {
+	throttledHandlerOnce.Do(func() {
+		throttledHandlerInstance = &ThrottledRejectedExecutionHandler{}
+	})
+	return throttledHandlerInstance
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestThrottledRejectedExecutionHandler_RejectedExecution(t *testing.T) {
+	handler := GetThrottledRejectedExecutionHandler()
+	invokeUrl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?tps.limit.interval=1000")
+
+	// the interval of the static config is the hint
+	result := handler.RejectedExecution(invokeUrl, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(result.Error()))
+	assert.Equal(t, "1000", result.Attachment(constant.RetryAfterKey, ""))
+
+	// the interval of the exceeded limit is preferred
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	inv.SetAttribute(constant.RetryAfterKey, int64(200))
+	result = handler.RejectedExecution(invokeUrl, inv)
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(result.Error()))
+	assert.Equal(t, "200", result.Attachment(constant.RetryAfterKey, ""))
+}
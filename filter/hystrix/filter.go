@@ -37,6 +37,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/config"
@@ -77,6 +78,19 @@ func (hfError *FilterError) FailByHystrix() bool {
 	return hfError.failByHystrix
 }
 
+// RPCStatus returns the code of the error, the errors of hystrix are classified by their causes
+func (hfError *FilterError) RPCStatus() common.RPCStatus {
+	switch hfError.err {
+	case hystrix.ErrCircuitOpen:
+		return common.StatusCircuitOpen
+	case hystrix.ErrMaxConcurrency:
+		return common.StatusThrottled
+	case hystrix.ErrTimeout:
+		return common.StatusTimeout
+	}
+	return common.CodeOf(hfError.err)
+}
+
 // NewHystrixFilterError return a FilterError instance
 func NewHystrixFilterError(err error, failByHystrix bool) error {
 	return &FilterError{
@@ -47,6 +47,12 @@ func TestNewHystrixFilterError(t *testing.T) {
 	get := NewHystrixFilterError(errors.New("test"), true)
 	assert.True(t, get.(*FilterError).FailByHystrix())
 	assert.Equal(t, "test", get.Error())
+
+	assert.Equal(t, common.StatusCircuitOpen, common.CodeOf(NewHystrixFilterError(hystrix.ErrCircuitOpen, true)))
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(NewHystrixFilterError(hystrix.ErrMaxConcurrency, true)))
+	assert.Equal(t, common.StatusTimeout, common.CodeOf(NewHystrixFilterError(hystrix.ErrTimeout, true)))
+	assert.Equal(t, common.StatusBiz, common.CodeOf(NewHystrixFilterError(
+		common.NewRPCError(common.StatusBiz, errors.New("biz")), false)))
 }
 
 func mockInitHystrixConfig() {
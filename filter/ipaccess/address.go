@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ipaccess
+
+import (
+	"net"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// callerIP returns the ip of the caller of @invocation, nil if it is unknown. The x-forwarded-for of the rest
+// protocol is only trusted if the peer is one of the @trustedProxies, otherwise any consumer could forge it, and
+// the caller is the nearest hop of it which is not a trusted proxy.
+func callerIP(invocation protocol.Invocation, trustedProxies []*net.IPNet) net.IP {
+	addr, _ := invocation.GetAttachment(constant.RemoteAddr)
+	peer := parseIP(addr)
+	if peer == nil || !containsIP(trustedProxies, peer) {
+		return peer
+	}
+	forwardedFor, ok := invocation.GetAttachment(constant.ForwardedForKey)
+	if !ok {
+		return peer
+	}
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := parseIP(hops[i])
+		if hop == nil {
+			// a malformed hop can not be trusted to forward the one before it
+			return peer
+		}
+		peer = hop
+		if !containsIP(trustedProxies, hop) {
+			break
+		}
+	}
+	return peer
+}
+
+// parseIP parses the ip of @addr, which is an ip or an ip:port, and the ipv6 may be bracketed or zoned
+func parseIP(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	if i := strings.LastIndex(addr, "%"); i >= 0 {
+		addr = addr[:i]
+	}
+	ip := net.ParseIP(addr)
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ipaccess
+
+import (
+	"net"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestParseIP(t *testing.T) {
+	for addr, expected := range map[string]string{
+		"10.0.0.1":              "10.0.0.1",
+		"10.0.0.1:20000":        "10.0.0.1",
+		" 10.0.0.1 ":            "10.0.0.1",
+		"fd00::1":               "fd00::1",
+		"[fd00::1]":             "fd00::1",
+		"[fd00::1]:20000":       "fd00::1",
+		"fe80::1%eth0":          "fe80::1",
+		"[fe80::1%eth0]:20000":  "fe80::1",
+		"[::ffff:10.0.0.1]:200": "10.0.0.1",
+	} {
+		ip := parseIP(addr)
+		assert.NotNil(t, ip, addr)
+		assert.Equal(t, expected, ip.String(), addr)
+	}
+	assert.Nil(t, parseIP(""))
+	assert.Nil(t, parseIP("unknown"))
+}
+
+func TestCallerIP(t *testing.T) {
+	proxies, err := parseNets([]string{"192.168.0.0/16", "fd00::/8"})
+	assert.NoError(t, err)
+	callerOf := func(remoteAddr, forwardedFor string) net.IP {
+		attachments := map[string]interface{}{constant.RemoteAddr: remoteAddr}
+		if len(forwardedFor) != 0 {
+			attachments[constant.ForwardedForKey] = forwardedFor
+		}
+		return callerIP(invocation.NewRPCInvocation("GetUser", nil, attachments), proxies)
+	}
+
+	assert.Equal(t, "10.0.0.1", callerOf("10.0.0.1:20000", "").String())
+	// the x-forwarded-for of an untrusted peer may be forged
+	assert.Equal(t, "10.0.0.1", callerOf("10.0.0.1:20000", "10.1.2.3").String())
+	// the nearest untrusted hop is the caller
+	assert.Equal(t, "10.1.2.3", callerOf("192.168.0.1:80", "1.1.1.1, 10.1.2.3, 192.168.0.2").String())
+	assert.Equal(t, "2001:db8::1", callerOf("[fd00::1]:80", "2001:db8::1").String())
+	// all the hops are trusted
+	assert.Equal(t, "192.168.0.3", callerOf("192.168.0.1:80", "192.168.0.3,192.168.0.2").String())
+	// a malformed hop can not be trusted
+	assert.Equal(t, "192.168.0.1", callerOf("192.168.0.1:80", "10.1.2.3,unknown").String())
+	assert.Nil(t, callerOf("", "10.1.2.3"))
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ipaccess provides a provider filter which denies the consumers by their addresses, with the allowlist
+// and the denylist of ips and cidrs in the static config of the service, e.g.
+//
+//	params:
+//	  ip.access.allow: 10.0.0.0/8,fd00::/8
+//	  ip.access.deny: 10.1.2.3
+//
+// or in the rules of the config center which are applied without redeploy, see Rule.
+package ipaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// ErrAccessDenied is the error returned to a denied consumer
+var ErrAccessDenied = errors.New("access denied")
+
+var (
+	once           sync.Once
+	ipAccessFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.IPAccessFilterKey, newFilter)
+}
+
+// Filter denies the consumers by their addresses
+type Filter struct {
+	rules rules
+}
+
+func newFilter() filter.Filter {
+	if ipAccessFilter == nil {
+		once.Do(func() {
+			ipAccessFilter = &Filter{}
+		})
+	}
+	return ipAccessFilter
+}
+
+// Invoke returns a permission denied error if the caller is not permitted by the acl of the service
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	a := f.rules.aclOf(url)
+	if a == nil {
+		return invoker.Invoke(ctx, invocation)
+	}
+	ip := callerIP(invocation, a.trustedProxies)
+	if a.permits(ip) {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	source := "unknown"
+	if ip != nil {
+		source = ip.String()
+	}
+	logger.Debugf("[ip access filter] deny the call of the method %s of %s from %s",
+		invocation.MethodName(), url.ServiceKey(), source)
+	metrics.Publish(rpc.NewAccessDeniedEvent(url, invocation.MethodName(), source))
+	return &protocol.RPCResult{
+		Err: common.NewRPCError(common.StatusPermissionDenied, fmt.Errorf("%w: %s", ErrAccessDenied, source)),
+		// the same as the proxy invoker, so that the protocol knows the version of the consumer
+		Attrs: invocation.Attachments(),
+	}
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
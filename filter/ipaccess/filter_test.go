@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ipaccess
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const userProviderRuleKey = "com.ikurento.user.UserProvider::.ip-access"
+
+func newInvoker(t *testing.T, params string) protocol.Invoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&application=user-center&" + params)
+	assert.NoError(t, err)
+	return protocol.NewBaseInvoker(url)
+}
+
+// permitted calls @f with an invocation from @remoteAddr, and returns whether it is permitted
+func permitted(t *testing.T, f *Filter, invoker protocol.Invoker, remoteAddr string) bool {
+	attachments := map[string]interface{}{}
+	if len(remoteAddr) != 0 {
+		attachments[constant.RemoteAddr] = remoteAddr
+	}
+	result := f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, attachments))
+	if result.Error() == nil {
+		return true
+	}
+	assert.Equal(t, common.StatusPermissionDenied, common.CodeOf(result.Error()))
+	assert.True(t, errors.Is(result.Error(), ErrAccessDenied))
+	return false
+}
+
+func TestFilterStatic(t *testing.T) {
+	f := &Filter{}
+	invoker := newInvoker(t, "ip.access.allow=10.0.0.0/8,fd00::/8&ip.access.deny=10.1.2.3,fd00::bad")
+	assert.True(t, permitted(t, f, invoker, "10.0.0.1:20000"))
+	assert.False(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+	assert.False(t, permitted(t, f, invoker, "192.168.1.1:20000"))
+	assert.True(t, permitted(t, f, invoker, "[fd00::1]:20000"))
+	assert.True(t, permitted(t, f, invoker, "[::ffff:10.0.0.1]:20000"))
+	assert.False(t, permitted(t, f, invoker, "[fd00::bad]:20000"))
+	assert.False(t, permitted(t, f, invoker, "[fe80::1%eth0]:20000"))
+	// the unknown caller is not in the allowlist
+	assert.False(t, permitted(t, f, invoker, ""))
+
+	invoker = newInvoker(t, "ip.access.deny=10.1.2.0/24")
+	assert.True(t, permitted(t, f, invoker, "10.0.0.1:20000"))
+	assert.False(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+	assert.True(t, permitted(t, f, invoker, ""))
+
+	// no access control
+	assert.True(t, permitted(t, f, newInvoker(t, ""), "10.1.2.3:20000"))
+
+	// a malformed config denies all
+	invoker = newInvoker(t, "ip.access.allow=10.0.0.0/33")
+	assert.False(t, permitted(t, f, invoker, "10.0.0.1:20000"))
+}
+
+func TestFilterAccessDeniedEvent(t *testing.T) {
+	ch := make(chan metrics.MetricsEvent, 1)
+	metrics.Subscribe(constant.MetricsRpc, ch)
+
+	assert.False(t, permitted(t, &Filter{}, newInvoker(t, "ip.access.deny=10.1.2.3"), "10.1.2.3:20000"))
+	select {
+	case <-ch:
+	default:
+		t.Fatal("the access denied event is not published")
+	}
+}
+
+func TestFilterRule(t *testing.T) {
+	f := &Filter{}
+	invoker := newInvoker(t, "ip.access.allow=10.0.0.0/8")
+	update := func(key, rule string) {
+		f.rules.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule, ConfigType: remoting.EventTypeUpdate})
+	}
+
+	// the rule of the service takes precedence over the static config
+	update(userProviderRuleKey, "deny: [10.1.2.3]")
+	assert.True(t, permitted(t, f, invoker, "192.168.1.1:20000"))
+	assert.False(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+
+	// a malformed rule is ignored
+	update(userProviderRuleKey, "deny: [10.1.2.300]")
+	assert.False(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+
+	update(userProviderRuleKey, "enabled: false")
+	assert.False(t, permitted(t, f, invoker, "192.168.1.1:20000"))
+	assert.True(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+
+	// the rule of the application is the default of the services without config
+	update("user-center.ip-access", "allow: [192.168.0.0/16]")
+	assert.True(t, permitted(t, f, newInvoker(t, ""), "192.168.1.1:20000"))
+	assert.False(t, permitted(t, f, newInvoker(t, ""), "10.1.2.3:20000"))
+	assert.True(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: "user-center.ip-access", ConfigType: remoting.EventTypeDel})
+	assert.True(t, permitted(t, f, newInvoker(t, ""), "10.1.2.3:20000"))
+}
+
+func TestFilterSubscribeRule(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: "deny: [10.1.2.3]"}
+	mockURL, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, err := factory.GetDynamicConfiguration(mockURL)
+	assert.NoError(t, err)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	f := &Filter{}
+	invoker := newInvoker(t, "")
+	assert.False(t, permitted(t, f, invoker, "10.1.2.3:20000"))
+	assert.True(t, permitted(t, f, invoker, "10.0.0.1:20000"))
+	assert.True(t, f.rules.subscriptions.Subscribed(userProviderRuleKey))
+}
@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ipaccess
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Rule is the ip access rule in the config center, e.g.
+//
+//	enabled: true
+//	allow:
+//	  - 10.0.0.0/8
+//	  - fd00::/8
+//	deny:
+//	  - 10.1.2.3
+//	trustedProxies:
+//	  - 192.168.0.1
+//
+// The rule of a service is keyed by service:version:group.ip-access, it takes precedence over the static config
+// of the service. The rule keyed by application.ip-access is the default of all the services of the application
+// in the process which have neither a rule nor a static config.
+type Rule struct {
+	Enabled        bool     `default:"true" yaml:"enabled"`
+	Allow          []string `yaml:"allow"`
+	Deny           []string `yaml:"deny"`
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+func parseRule(content string) (*Rule, error) {
+	rule := &Rule{}
+	if err := defaults.Set(rule); err != nil {
+		return nil, err
+	}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// acl is the parsed allowlist and denylist of a rule or a static config
+type acl struct {
+	allow          []*net.IPNet
+	deny           []*net.IPNet
+	trustedProxies []*net.IPNet
+	// denyAll is true for a malformed static config, as access control must not fail open
+	denyAll bool
+}
+
+func newACL(allow, deny, trustedProxies []string) (*acl, error) {
+	a := &acl{}
+	var err error
+	if a.allow, err = parseNets(allow); err != nil {
+		return nil, err
+	}
+	if a.deny, err = parseNets(deny); err != nil {
+		return nil, err
+	}
+	if a.trustedProxies, err = parseNets(trustedProxies); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseNets parses the ips and cidrs of both ipv4 and ipv6, an ip is a cidr of itself only
+func parseNets(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, perrors.Errorf("invalid cidr %s", entry)
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, perrors.Errorf("invalid ip %s", entry)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+	}
+	return nets, nil
+}
+
+// permits returns false if @ip is denied, or there is an allowlist which @ip is not in. An unknown caller is
+// only permitted without an allowlist.
+func (a *acl) permits(ip net.IP) bool {
+	if a.denyAll {
+		return false
+	}
+	if ip == nil {
+		return len(a.allow) == 0
+	}
+	if containsIP(a.deny, ip) {
+		return false
+	}
+	return len(a.allow) == 0 || containsIP(a.allow, ip)
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// rules holds the static acls of the services and the acls of the rules in the config center, the rules are
+// subscribed on the first invocation of the services
+type rules struct {
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+	// dynamic are the acls of the enabled rules, keyed by the rule key
+	dynamic sync.Map
+	// static are the acls of the static configs, keyed by the config
+	static sync.Map
+}
+
+// aclOf returns the acl of the service with @url, nil if the service is not under access control
+func (r *rules) aclOf(url *common.URL) *acl {
+	if a := r.load(config_center.ServiceRuleKey(url, constant.IPAccessRuleSuffix)); a != nil {
+		return a
+	}
+	if a := r.staticOf(url); a != nil {
+		return a
+	}
+	application := url.GetParam(constant.ApplicationKey, "")
+	if len(application) == 0 {
+		return nil
+	}
+	return r.load(application + constant.IPAccessRuleSuffix)
+}
+
+// staticOf returns the acl of the static config of the service with @url
+func (r *rules) staticOf(url *common.URL) *acl {
+	allow := url.GetParam(constant.IPAccessAllowKey, "")
+	deny := url.GetParam(constant.IPAccessDenyKey, "")
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil
+	}
+	trustedProxies := url.GetParam(constant.IPAccessTrustedProxiesKey, "")
+	key := strings.Join([]string{allow, deny, trustedProxies}, "|")
+	if a, ok := r.static.Load(key); ok {
+		return a.(*acl)
+	}
+	a, err := newACL(strings.Split(allow, ","), strings.Split(deny, ","), strings.Split(trustedProxies, ","))
+	if err != nil {
+		logger.Errorf("[ip access filter] Parse the ip access config of %s error, %v, all the calls are denied.",
+			url.ServiceKey(), err)
+		a = &acl{denyAll: true}
+	}
+	r.static.Store(key, a)
+	return a
+}
+
+// load returns the acl of the rule of @key, and subscribes the rule if it is not subscribed yet
+func (r *rules) load(key string) *acl {
+	if a, ok := r.dynamic.Load(key); ok {
+		return a.(*acl)
+	}
+	if !r.subscriptions.Subscribe(conf.GetEnvInstance().GetDynamicConfiguration(), key, r, 0) {
+		return nil
+	}
+	if a, ok := r.dynamic.Load(key); ok {
+		return a.(*acl)
+	}
+	return nil
+}
+
+// Process applies the changed rule immediately. A malformed rule is ignored and the previous rule is kept.
+func (r *rules) Process(event *config_center.ConfigChangeEvent) {
+	content, ok := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || !ok || len(content) == 0 {
+		r.dynamic.Delete(event.Key)
+		return
+	}
+	rule, err := parseRule(content)
+	if err == nil && !rule.Enabled {
+		r.dynamic.Delete(event.Key)
+		return
+	}
+	var a *acl
+	if err == nil {
+		a, err = newACL(rule.Allow, rule.Deny, rule.TrustedProxies)
+	}
+	if err != nil {
+		logger.Warnf("[ip access filter]Parse ip access rule %s error, %v, the previous rule is kept.", event.Key, err)
+		return
+	}
+	r.dynamic.Store(event.Key, a)
+	logger.Infof("[ip access filter]Parse ip access rule success,key=%s,allow=%v,deny=%v", event.Key, rule.Allow, rule.Deny)
+}
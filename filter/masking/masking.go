@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package masking masks the sensitive values, like the passwords and the phone numbers, in the invocation
+// arguments and results before they are logged.
+package masking
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	// Redacted replaces the values of the fully masked fields
+	Redacted = "******"
+	// keptLength is the number of the trailing characters kept by the partial masking
+	keptLength = 4
+	// maxDepth limits the depth of the walk, the values deeper are replaced by "..."
+	maxDepth = 32
+)
+
+type rule int
+
+const (
+	ruleNone rule = iota
+	ruleFull
+	rulePartial
+)
+
+var maskers sync.Map // the patterns -> *Masker
+
+// Masker makes the masked copies of the values for logging, in which the values of the fields or the map entries
+// whose names match the patterns are masked. The patterns are case-insensitive globs, like "*password*".
+type Masker struct {
+	full    []string
+	partial []string
+}
+
+// New creates a Masker which redacts the fields matching @full, and masks the fields matching @partial except
+// the last 4 characters. @full takes precedence over @partial.
+func New(full, partial []string) *Masker {
+	return &Masker{full: normalize(full), partial: normalize(partial)}
+}
+
+// FromURL returns the Masker configured by masking.fields and masking.partial.fields of @url,
+// the maskers of the same patterns are shared
+func FromURL(url *common.URL) *Masker {
+	full := url.GetParam(constant.MaskingFieldsKey, constant.DefaultMaskingFields)
+	partial := url.GetParam(constant.MaskingPartialFieldsKey, "")
+	key := full + "|" + partial
+	if m, ok := maskers.Load(key); ok {
+		return m.(*Masker)
+	}
+	m, _ := maskers.LoadOrStore(key, New(strings.Split(full, ","), strings.Split(partial, ",")))
+	return m.(*Masker)
+}
+
+func normalize(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			normalized = append(normalized, p)
+		}
+	}
+	return normalized
+}
+
+// Mask returns the masked copy of @v, @v itself is untouched. The structs and the maps are copied into
+// map[string]interface{} with the exported fields and the entries, and the slices and the arrays into []interface{}.
+func (m *Masker) Mask(v interface{}) interface{} {
+	return m.mask(reflect.ValueOf(v), 0)
+}
+
+// String renders the masked copy of @v, the strings are rendered as they are, and the others in json
+func (m *Masker) String(v interface{}) string {
+	masked := m.Mask(v)
+	if s, ok := masked.(string); ok {
+		return s
+	}
+	if b, err := json.Marshal(masked); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", masked)
+}
+
+func (m *Masker) mask(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxDepth {
+		return "..."
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return m.mask(v.Elem(), depth+1)
+	case reflect.Struct:
+		t := v.Type()
+		fields := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				fields[f.Name] = m.maskField(f.Name, v.Field(i), depth+1)
+			}
+		}
+		if len(fields) == 0 {
+			// the structs without exported fields, like time.Time, are logged as they are
+			return v.Interface()
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			name := fmt.Sprint(iter.Key().Interface())
+			entries[name] = m.maskField(name, iter.Value(), depth+1)
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = m.mask(v.Index(i), depth+1)
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// maskField masks the value @v of the field or the map entry @name
+func (m *Masker) maskField(name string, v reflect.Value, depth int) interface{} {
+	r := m.match(name)
+	if r == ruleNone {
+		return m.mask(v, depth)
+	}
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if r == ruleFull {
+		return Redacted
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		// only the scalar values are masked partially
+		return Redacted
+	}
+	return maskPartially(fmt.Sprint(v.Interface()))
+}
+
+func (m *Masker) match(name string) rule {
+	name = strings.ToLower(name)
+	for _, p := range m.full {
+		if ok, _ := path.Match(p, name); ok {
+			return ruleFull
+		}
+	}
+	for _, p := range m.partial {
+		if ok, _ := path.Match(p, name); ok {
+			return rulePartial
+		}
+	}
+	return ruleNone
+}
+
+// maskPartially masks @s except the last 4 characters, all of them are masked if @s is not longer than 4
+func maskPartially(s string) string {
+	runes := []rune(s)
+	if len(runes) <= keptLength {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-keptLength) + string(runes[len(runes)-keptLength:])
+}
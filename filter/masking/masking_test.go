@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package masking
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type card struct {
+	IDCard string
+	Bank   string
+}
+
+type account struct {
+	Name     string
+	Password string
+	Phone    *string
+	Cards    []card
+	Extra    map[string]interface{}
+	Created  time.Time
+	secret   string
+}
+
+func TestMaskerMask(t *testing.T) {
+	phone := "13800001234"
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := &account{
+		Name:     "Alex",
+		Password: "123456",
+		Phone:    &phone,
+		Cards:    []card{{IDCard: "110101199001011234", Bank: "ICBC"}},
+		Extra: map[string]interface{}{
+			"password": []string{"a", "b"},
+			"contact":  map[string]string{"phone": "99"},
+		},
+		Created: created,
+		secret:  "hidden",
+	}
+	m := New([]string{"*password*"}, []string{"phone", "idcard"})
+
+	assert.Equal(t, map[string]interface{}{
+		"Name":     "Alex",
+		"Password": Redacted,
+		"Phone":    "*******1234",
+		"Cards": []interface{}{
+			map[string]interface{}{"IDCard": "**************1234", "Bank": "ICBC"},
+		},
+		"Extra": map[string]interface{}{
+			"password": Redacted,
+			"contact":  map[string]interface{}{"phone": "**"},
+		},
+		"Created": created,
+	}, m.Mask(a))
+
+	// the value is untouched
+	assert.Equal(t, "123456", a.Password)
+	assert.Equal(t, "13800001234", *a.Phone)
+	assert.Equal(t, "110101199001011234", a.Cards[0].IDCard)
+	assert.Equal(t, []string{"a", "b"}, a.Extra["password"])
+}
+
+func TestMaskerMaskPartialComposite(t *testing.T) {
+	m := New(nil, []string{"phone*"})
+	assert.Equal(t, map[string]interface{}{"phones": Redacted, "phone": nil},
+		m.Mask(map[string]interface{}{"phones": []string{"13800001234"}, "phone": nil}))
+}
+
+func TestMaskerString(t *testing.T) {
+	m := New([]string{"password"}, nil)
+	assert.Equal(t, "plain", m.String("plain"))
+	assert.Equal(t, "1", m.String(1))
+	assert.Equal(t, "null", m.String(nil))
+	assert.Equal(t, `[{"Password":"******"}]`, m.String([]struct{ Password string }{{Password: "123456"}}))
+}
+
+func TestMaskerMaxDepth(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+	assert.NotPanics(t, func() {
+		New(nil, nil).String(n)
+	})
+}
+
+func TestFromURL(t *testing.T) {
+	url := common.NewURLWithOptions(common.WithParamsValue(constant.MaskingPartialFieldsKey, "phone"))
+	m := FromURL(url)
+	assert.Same(t, m, FromURL(url))
+	assert.Equal(t, map[string]interface{}{"Secret": Redacted, "Phone": "*******1234"},
+		m.Mask(struct{ Secret, Phone string }{Secret: "s", Phone: "13800001234"}))
+}
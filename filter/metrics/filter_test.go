@@ -36,8 +36,8 @@ import (
 
 func TestMetricsFilterInvoke(t *testing.T) {
 	mockChan := make(chan metrics.MetricsEvent, 10)
-	defer close(mockChan)
 	metrics.Subscribe(constant.MetricsRpc, mockChan)
+	defer metrics.Unsubscribe(constant.MetricsRpc)
 
 	url, _ := common.NewURL(
 		"dubbo://:20000/UserProvider?app.version=0.0.1&application=BDTService&bean.name=UserProvider" +
@@ -60,3 +60,29 @@ func TestMetricsFilterInvoke(t *testing.T) {
 	assert.Equal(t, 2, len(mockChan))
 	assert.Equal(t, constant.MetricsRpc, (<-mockChan).Type())
 }
+
+// BenchmarkMetricsFilterInvoke measures the overhead of the filter on the invocation, which only publishes the events,
+// the metrics are recorded by the collector goroutine.
+func BenchmarkMetricsFilterInvoke(b *testing.B) {
+	events := make(chan metrics.MetricsEvent, 1024)
+	metrics.Subscribe(constant.MetricsRpc, events)
+	defer metrics.Unsubscribe(constant.MetricsRpc)
+	go func() {
+		for range events {
+		}
+	}()
+
+	url, _ := common.NewURL("dubbo://:20000/UserProvider?application=BDTService&interface=com.ikurento.user.UserProvider&registry.role=3")
+	invoker := protocol.NewBaseInvoker(url)
+	inv := invocation.NewRPCInvocation("MethodName", []interface{}{"OK"}, map[string]interface{}{
+		constant.TraceparentKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	ctx := context.Background()
+	filter := newFilter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filter.Invoke(ctx, invoker, inv)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	_ "dubbo.apache.org/dubbo-go/v3/metrics/prometheus"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+// greeterInvoker is the provider of the greeter service, which greets the first argument
+type greeterInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (i *greeterInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Rest: "hello " + inv.Arguments()[0].(string)}
+}
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// TestRPCMetricsScrapeE2E makes the rpc calls by the dubbo protocol through the metrics filters of the consumer and
+// the provider, and scrapes the metrics from the prometheus endpoint
+func TestRPCMetricsScrapeE2E(t *testing.T) {
+	metricsPort := strconv.Itoa(freePort(t))
+	metrics.Init(common.NewURLWithOptions(
+		common.WithProtocol(constant.ProtocolPrometheus),
+		common.WithParamsValue(constant.PrometheusExporterEnabledKey, "true"),
+		common.WithParamsValue(constant.PrometheusExporterMetricsPortKey, metricsPort),
+		common.WithParamsValue(constant.PrometheusExporterMetricsPathKey, "/metrics"),
+		common.WithParamsValue(constant.ApplicationKey, "shop"),
+		common.WithParamsValue(constant.AppVersionKey, "1.0.0"),
+		common.WithParamsValue(constant.MetricsNamespaceKey, "e2e"),
+		common.WithParamsValue(constant.MetricsConstLabelKeyPrefix+"zone", "hz"),
+		common.WithParamsValue(constant.MetricsConstLabelKeyPrefix+"version", "1.0.0"),
+	))
+
+	getty.SetServerConfig(*getty.GetDefaultServerConfig())
+	getty.SetClientConf(*getty.GetDefaultClientConfig())
+	proto := dubbo.GetProtocol()
+	defer proto.Destroy()
+
+	location := "127.0.0.1:" + strconv.Itoa(freePort(t))
+	serviceURL := "dubbo://" + location + "/com.example.Greeter?interface=com.example.Greeter&application=shop&timeout=3000"
+	providerURL, err := common.NewURL(serviceURL + "&registry.role=3&service.filter=metrics")
+	assert.NoError(t, err)
+	proto.Export(protocolwrapper.BuildInvokerChain(&greeterInvoker{BaseInvoker: *protocol.NewBaseInvoker(providerURL)},
+		constant.ServiceFilterKey))
+	consumerURL, err := common.NewURL(serviceURL + "&registry.role=0&reference.filter=metrics")
+	assert.NoError(t, err)
+	consumer := protocolwrapper.BuildInvokerChain(proto.Refer(consumerURL), constant.ReferenceFilterKey)
+
+	scrape := func() string {
+		resp, err := http.Get("http://127.0.0.1:" + metricsPort + "/metrics")
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	var text string
+	// the calls are repeated until the collector subscribes the events and the endpoint is serving
+	assert.Eventually(t, func() bool {
+		var reply string
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Greet"),
+			invocation.WithArguments([]interface{}{"dubbo"}), invocation.WithReply(&reply))
+		res := consumer.Invoke(context.Background(), inv)
+		if !assert.NoError(t, res.Error()) || !assert.Equal(t, "hello dubbo", reply) {
+			return true
+		}
+		text = scrape()
+		return assert.ObjectsAreEqual(true, contains(text,
+			`e2e_dubbo_consumer_requests_total{`, `e2e_dubbo_provider_requests_total{`,
+			`e2e_dubbo_consumer_requests_succeed_total{`, `e2e_dubbo_provider_rt_milliseconds_sum{`))
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.Contains(t, text, `interface="com.example.Greeter"`)
+	assert.Contains(t, text, `method="Greet"`)
+	assert.Contains(t, text, `zone="hz"`)
+	// the const label is ignored for the metrics with the version tag
+	assert.Regexp(t, `e2e_dubbo_consumer_requests_total\{[^}]*version=""[^}]*} `, text)
+	// the runtime metrics are registered by the default registry
+	assert.Contains(t, text, "go_goroutines ")
+}
+
+func contains(text string, series ...string) bool {
+	for _, s := range series {
+		if !strings.Contains(text, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -19,6 +19,7 @@ package trace
 
 import (
 	"context"
+	"strings"
 )
 
 import (
@@ -35,18 +36,31 @@ type metadataSupplier struct {
 
 var _ propagation.TextMapCarrier = &metadataSupplier{}
 
+// Get returns the value of the @key, which is a string in the attachments of the dubbo protocol, or a []string in
+// the ones of the triple protocol. The key is matched case-insensitively if it's absent, as the other frameworks
+// like brave may capitalize the headers, e.g. X-B3-TraceId.
 func (s *metadataSupplier) Get(key string) string {
 	if s.metadata == nil {
 		return ""
 	}
-	item, ok := s.metadata[key].([]string)
+	value, ok := s.metadata[key]
 	if !ok {
-		return ""
+		for k, v := range s.metadata {
+			if strings.EqualFold(k, key) {
+				value = v
+				break
+			}
+		}
 	}
-	if len(item) == 0 {
-		return ""
+	switch item := value.(type) {
+	case string:
+		return item
+	case []string:
+		if len(item) > 0 {
+			return item[0]
+		}
 	}
-	return item[0]
+	return ""
 }
 
 func (s *metadataSupplier) Set(key string, value string) {
@@ -117,6 +117,22 @@ func Test_metadataSupplier_Get(t *testing.T) {
 			key:  "key",
 			want: "test",
 		},
+		{
+			name: "string",
+			metadata: map[string]interface{}{
+				"key": "test",
+			},
+			key:  "key",
+			want: "test",
+		},
+		{
+			name: "case insensitive",
+			metadata: map[string]interface{}{
+				"X-B3-TraceId": "463ac35c9f6413ad48485a3953bb6124",
+			},
+			key:  "x-b3-traceid",
+			want: "463ac35c9f6413ad48485a3953bb6124",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -17,4 +17,15 @@
 
 // Package trace instruments dubbogo with open-telemetry
 // (https://github.com/open-telemetry/opentelemetry-go).
+//
+// The otelClientTrace filter of the consumers starts the client spans and injects their contexts into the
+// attachments, and the otelServerTrace filter of the providers extracts them and starts the server spans, whose
+// contexts are passed to the services. The tracer provider and the propagator are the global ones of open-telemetry,
+// which are set by the otel.trace config if it's enabled, e.g.
+//
+//	otel:
+//	  trace:
+//	    enable: true
+//	    exporter: otlp-grpc
+//	    propagator: w3c,b3
 package trace
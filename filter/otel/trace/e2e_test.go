@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	otelTrace "dubbo.apache.org/dubbo-go/v3/otel/trace"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+// funcInvoker is the provider invoking its func
+type funcInvoker struct {
+	protocol.BaseInvoker
+	invoke func(ctx context.Context, inv protocol.Invocation) protocol.Result
+}
+
+func (i *funcInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	return i.invoke(ctx, inv)
+}
+
+func freeLocation(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	return "127.0.0.1:" + strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+// TestTracingAcrossServices calls the greeter service, which calls the user service, by the dubbo protocol through
+// the tracing filters, and checks the spans of both of the calls are linked in a trace
+func TestTracingAcrossServices(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	propagator, err := otelTrace.NewPropagator("w3c,b3")
+	assert.NoError(t, err)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+
+	getty.SetServerConfig(*getty.GetDefaultServerConfig())
+	getty.SetClientConf(*getty.GetDefaultClientConfig())
+	proto := dubbo.GetProtocol()
+	defer proto.Destroy()
+
+	export := func(service string, invoke func(ctx context.Context, inv protocol.Invocation) protocol.Result) protocol.Invoker {
+		serviceURL := "dubbo://" + freeLocation(t) + "/" + service + "?interface=" + service + "&version=1.0.0&timeout=3000"
+		providerURL, err := common.NewURL(serviceURL + "&service.filter=" + constant.OTELServerTraceKey)
+		assert.NoError(t, err)
+		proto.Export(protocolwrapper.BuildInvokerChain(
+			&funcInvoker{BaseInvoker: *protocol.NewBaseInvoker(providerURL), invoke: invoke}, constant.ServiceFilterKey))
+		consumerURL, err := common.NewURL(serviceURL + "&reference.filter=" + constant.OTELClientTraceKey)
+		assert.NoError(t, err)
+		return protocolwrapper.BuildInvokerChain(proto.Refer(consumerURL), constant.ReferenceFilterKey)
+	}
+
+	var attachments map[string]interface{}
+	user := export("com.example.User", func(ctx context.Context, inv protocol.Invocation) protocol.Result {
+		attachments = inv.Attachments()
+		return &protocol.RPCResult{Rest: "dubbo"}
+	})
+	var greeterSpan trace.SpanContext
+	greeter := export("com.example.Greeter", func(ctx context.Context, inv protocol.Invocation) protocol.Result {
+		// the span of the provider is exposed to the business code, so its calls are the children of it
+		greeterSpan = trace.SpanContextFromContext(ctx)
+		var name string
+		res := user.Invoke(ctx, invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+			invocation.WithArguments([]interface{}{}), invocation.WithReply(&name)))
+		if res.Error() != nil {
+			return res
+		}
+		return &protocol.RPCResult{Rest: "hello " + name}
+	})
+
+	var reply string
+	res := greeter.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Greet"),
+		invocation.WithArguments([]interface{}{}), invocation.WithReply(&reply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "hello dubbo", reply)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.SpanKind().String()+" "+span.Name()] = span
+	}
+	assert.Len(t, spans, 4)
+	greetClient, greetServer := spans["client Greet"], spans["server Greet"]
+	userClient, userServer := spans["client GetName"], spans["server GetName"]
+	if !assert.NotNil(t, greetClient) || !assert.NotNil(t, greetServer) || !assert.NotNil(t, userClient) ||
+		!assert.NotNil(t, userServer) {
+		return
+	}
+
+	// the spans are linked across the wire
+	traceID := greetClient.SpanContext().TraceID()
+	for _, span := range spans {
+		assert.Equal(t, traceID, span.SpanContext().TraceID())
+	}
+	assert.False(t, greetClient.Parent().IsValid())
+	assert.Equal(t, greetClient.SpanContext().SpanID(), greetServer.Parent().SpanID())
+	assert.True(t, greetServer.Parent().IsRemote())
+	assert.Equal(t, greetServer.SpanContext().SpanID(), greeterSpan.SpanID())
+	assert.Equal(t, greetServer.SpanContext().SpanID(), userClient.Parent().SpanID())
+	assert.Equal(t, userClient.SpanContext().SpanID(), userServer.Parent().SpanID())
+
+	// both of the w3c and the b3 headers are propagated
+	assert.Equal(t, "00-"+traceID.String()+"-"+userClient.SpanContext().SpanID().String()+"-01", attachments["traceparent"])
+	assert.Equal(t, traceID.String(), attachments["x-b3-traceid"])
+	assert.Contains(t, attachments, "b3")
+
+	assert.Contains(t, userClient.Attributes(), RPCDubboVersionKey.String("1.0.0"))
+	assert.Contains(t, userClient.Attributes(), RPCSystemDubbo)
+	var peerIP bool
+	for _, attr := range userServer.Attributes() {
+		peerIP = peerIP || (attr.Key == "net.peer.ip" && attr.Value.AsString() == "127.0.0.1")
+	}
+	assert.True(t, peerIP)
+}
@@ -19,10 +19,13 @@ package trace
 
 import (
 	"context"
+	"net"
+	"strconv"
 )
 
 import (
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
@@ -31,6 +34,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
@@ -72,25 +76,18 @@ func (f *otelServerFilter) Invoke(ctx context.Context, invoker protocol.Invoker,
 		trace.WithInstrumentationVersion(constant.OtelPackageVersion),
 	)
 
+	// the remote address is attached by the dubbo protocol
+	peer, _ := attachments[constant.RemoteAddr].(string)
 	ctx, span := tracer.Start(
 		trace.ContextWithRemoteSpanContext(ctx, spanCtx),
 		invocation.ActualMethodName(),
 		trace.WithSpanKind(trace.SpanKindServer),
-		trace.WithAttributes(
-			RPCSystemDubbo,
-			semconv.RPCServiceKey.String(invoker.GetURL().ServiceKey()),
-			semconv.RPCMethodKey.String(invocation.MethodName()),
-		),
+		trace.WithAttributes(rpcAttributes(invoker.GetURL(), invocation, peer)...),
 	)
 	defer span.End()
 
 	result := invoker.Invoke(ctx, invocation)
-
-	if result.Error() != nil {
-		span.SetStatus(codes.Error, result.Error().Error())
-	} else {
-		span.SetStatus(codes.Ok, codes.Ok.String())
-	}
+	setSpanStatus(span, result)
 	return result
 }
 
@@ -114,11 +111,7 @@ func (f *otelClientFilter) Invoke(ctx context.Context, invoker protocol.Invoker,
 		ctx,
 		invocation.ActualMethodName(),
 		trace.WithSpanKind(trace.SpanKindClient),
-		trace.WithAttributes(
-			RPCSystemDubbo,
-			semconv.RPCServiceKey.String(invoker.GetURL().ServiceKey()),
-			semconv.RPCMethodKey.String(invocation.MethodName()),
-		),
+		trace.WithAttributes(rpcAttributes(invoker.GetURL(), invocation, invoker.GetURL().Location)...),
 	)
 	defer span.End()
 
@@ -131,11 +124,39 @@ func (f *otelClientFilter) Invoke(ctx context.Context, invoker protocol.Invoker,
 		invocation.SetAttachment(k, v)
 	}
 	result := invoker.Invoke(ctx, invocation)
+	setSpanStatus(span, result)
+	return result
+}
 
-	if result.Error() != nil {
-		span.SetStatus(codes.Error, result.Error().Error())
-	} else {
-		span.SetStatus(codes.Ok, codes.Ok.String())
+// rpcAttributes returns the attributes of the span of the @invocation to the service of the @url, the @peer is the
+// address of the remote side
+func rpcAttributes(url *common.URL, invocation protocol.Invocation, peer string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		RPCSystemDubbo,
+		semconv.RPCServiceKey.String(url.ServiceKey()),
+		semconv.RPCMethodKey.String(invocation.MethodName()),
 	}
-	return result
+	if group := url.GetParam(constant.GroupKey, ""); group != "" {
+		attrs = append(attrs, RPCDubboGroupKey.String(group))
+	}
+	if version := url.GetParam(constant.VersionKey, ""); version != "" {
+		attrs = append(attrs, RPCDubboVersionKey.String(version))
+	}
+	if host, port, err := net.SplitHostPort(peer); err == nil {
+		attrs = append(attrs, semconv.NetPeerIPKey.String(host))
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+		}
+	}
+	return attrs
+}
+
+// setSpanStatus records the error of the @result in the @span
+func setSpanStatus(span trace.Span, result protocol.Result) {
+	if err := result.Error(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetStatus(codes.Ok, codes.Ok.String())
 }
@@ -31,4 +31,6 @@ var (
 	RPCNameMessage         = RPCNameKey.String("message")
 	RPCMessageTypeSent     = RPCMessageTypeKey.String("SENT")
 	RPCMessageTypeReceived = RPCMessageTypeKey.String("RECEIVED")
+	RPCDubboGroupKey       = attribute.Key("rpc.dubbo.group")
+	RPCDubboVersionKey     = attribute.Key("rpc.dubbo.version")
 )
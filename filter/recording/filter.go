@@ -0,0 +1,330 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package recording records a sample of the invocations of the providers into a file, which could be replayed
+// against a dev instance by tools/replay to reproduce the bugs of production.
+package recording
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/generic/generalizer"
+	"dubbo.apache.org/dubbo-go/v3/filter/masking"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// bufferedRecords is the max records waiting to be written, the records beyond it are dropped
+	bufferedRecords = 1024
+	// flushSize is the size of the buffered records which are flushed at once
+	flushSize = 32 * 1024
+	// flushTick is the period the buffered records are flushed
+	flushTick = 100 * time.Millisecond
+)
+
+var (
+	once            sync.Once
+	recordingFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.RecordingFilterKey, newFilter)
+}
+
+// Filter records the invocations of the providers with the param recording, which is the path of the file, e.g.
+//
+//	services:
+//	  UserProvider:
+//	    filter: recording
+//	    params:
+//	      recording: /var/log/user.rec
+//	      recording.rate: 0.01
+//	      recording.methods: GetUser,QueryUsers
+//
+// The recording is off unless the path is set, and the filter only looks the path up then. recording.rate is the
+// sampling rate in [0, 1], 1 by default, which could be overridden for a method like methods.GetUser.recording.rate,
+// and recording.methods limits the methods recorded. The arguments and the result are generalized into the maps like
+// the generic invocations, and they are masked with the attachments by masking.fields and masking.partial.fields,
+// the secret attachments like the tokens are redacted too. The records are encoded by recording.encoding, json by
+// default or hessian, and the records whose bodies are larger than recording.max.record.size bytes are dropped. The
+// file is rotated once it exceeds recording.max.size megabytes, and recording.max.backups files are kept. The records
+// are written asynchronously, and dropped if the writing falls behind.
+type Filter struct {
+	records chan pendingRecord
+	// writers are the writers of the recording files, they are only accessed by the writing goroutine
+	writers   map[string]*recordWriter
+	dropped   atomic.Uint64
+	oversized atomic.Uint64
+	reported  uint64
+	done      chan struct{}
+}
+
+type pendingRecord struct {
+	path   string
+	url    *common.URL
+	record *Record
+}
+
+func newFilter() filter.Filter {
+	once.Do(func() {
+		recordingFilter = newRecordingFilter(bufferedRecords)
+	})
+	return recordingFilter
+}
+
+// newRecordingFilter creates a filter buffering @size records at most, and starts its writing goroutine
+func newRecordingFilter(size int) *Filter {
+	f := &Filter{records: make(chan pendingRecord, size), done: make(chan struct{})}
+	go f.run()
+	return f
+}
+
+// Invoke records the invocation if the recording is enabled and the invocation is sampled
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	path := url.GetParam(constant.RecordingFilterKey, "")
+	if len(path) == 0 || !sampled(url, invocation.MethodName()) {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	// the arguments are copied before the invocation, which may modify them
+	masker := masking.FromURL(url)
+	record := &Record{
+		Time:        time.Now(),
+		Interface:   url.Service(),
+		Group:       url.Group(),
+		Version:     url.Version(),
+		Method:      invocation.MethodName(),
+		Types:       invocation.ParameterTypeNames(),
+		Arguments:   make([]interface{}, 0, len(invocation.Arguments())),
+		Attachments: maskAttachments(masker, invocation.Attachments()),
+	}
+	for _, arg := range invocation.Arguments() {
+		record.Arguments = append(record.Arguments, generalize(masker, arg))
+	}
+
+	result := invoker.Invoke(ctx, invocation)
+	record.Latency = time.Since(record.Time)
+	if result != nil {
+		if result.Error() != nil {
+			record.Error = result.Error().Error()
+		} else {
+			record.Result = generalize(masker, result.Result())
+		}
+	}
+
+	select {
+	case f.records <- pendingRecord{path: path, url: url, record: record}:
+	default:
+		f.dropped.Inc()
+	}
+	return result
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// sampled tells whether the invocation of @method is recorded
+func sampled(url *common.URL, method string) bool {
+	if methods := url.GetParam(constant.RecordingMethodsKey, ""); len(methods) != 0 {
+		matched := false
+		for _, m := range strings.Split(methods, ",") {
+			if strings.TrimSpace(m) == method {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	rate, err := strconv.ParseFloat(url.GetMethodParam(method, constant.RecordingRateKey, ""), 64)
+	if err != nil {
+		rate = constant.DefaultRecordingRate
+	}
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// generalize generalizes @v into the maps like the generic invocations, and masks it by @masker
+func generalize(masker *masking.Masker, v interface{}) interface{} {
+	generalized, err := generalizer.GetMapGeneralizer().Generalize(v)
+	if err != nil {
+		generalized = v
+	}
+	return masker.Mask(generalized)
+}
+
+// maskAttachments masks the @attachments by @masker, and redacts the secret ones
+func maskAttachments(masker *masking.Masker, attachments map[string]interface{}) map[string]interface{} {
+	if len(attachments) == 0 {
+		return nil
+	}
+	masked, _ := masker.Mask(attachments).(map[string]interface{})
+	for k := range masked {
+		if common.IsSecretKey(k) {
+			masked[k] = common.RedactedValue
+		}
+	}
+	return masked
+}
+
+// run writes the records in the channel, and flushes the files periodically until the channel is closed
+func (f *Filter) run() {
+	defer close(f.done)
+	ticker := time.NewTicker(flushTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case pending, ok := <-f.records:
+			if !ok {
+				f.closeWriters()
+				return
+			}
+			f.write(pending)
+		case <-ticker.C:
+			for path, w := range f.writers {
+				if err := w.flush(); err != nil {
+					logger.Warnf("Can not write the records into the recording file %s: %v", path, err)
+				}
+			}
+			f.reportDropped()
+		}
+	}
+}
+
+func (f *Filter) write(pending pendingRecord) {
+	if f.writers == nil {
+		f.writers = make(map[string]*recordWriter)
+	}
+	w, ok := f.writers[pending.path]
+	if !ok {
+		w = newRecordWriter(pending.path, pending.url)
+		f.writers[pending.path] = w
+	}
+	data, err := Marshal(pending.record, w.encoding)
+	if err != nil {
+		logger.Warnf("Can not encode the record of %s.%s: %v", pending.record.Interface, pending.record.Method, err)
+		return
+	}
+	if len(data)-headerLength > w.maxRecordSize {
+		f.oversized.Inc()
+		return
+	}
+	if err = w.write(data); err != nil {
+		logger.Warnf("Can not write the records into the recording file %s: %v", pending.path, err)
+	}
+}
+
+// reportDropped logs the count of the records dropped since the last report
+func (f *Filter) reportDropped() {
+	dropped := f.dropped.Load() + f.oversized.Load()
+	if dropped > f.reported {
+		logger.Warnf("%d records were dropped, %d in total, %d of them are too large", dropped-f.reported, dropped,
+			f.oversized.Load())
+		f.reported = dropped
+	}
+}
+
+// close stops the writing goroutine after the records in the channel are written, and closes the files
+func (f *Filter) close() {
+	close(f.records)
+	<-f.done
+}
+
+func (f *Filter) closeWriters() {
+	for path, w := range f.writers {
+		if err := w.close(); err != nil {
+			logger.Warnf("Can not close the recording file %s: %v", path, err)
+		}
+	}
+	f.writers = nil
+}
+
+// recordWriter buffers the records written into a rolling file, a record is never split by the rotation as the
+// buffered records are written at once. It is only accessed by the writing goroutine.
+type recordWriter struct {
+	out           *lumberjack.Logger
+	buf           bytes.Buffer
+	encoding      Encoding
+	maxRecordSize int
+}
+
+// newRecordWriter creates the writer of the recording file @path with the config of @url
+func newRecordWriter(path string, url *common.URL) *recordWriter {
+	encoding, err := ParseEncoding(url.GetParam(constant.RecordingEncodingKey, ""))
+	if err != nil {
+		logger.Warnf("%v, the records of %s are encoded by json", err, path)
+		encoding = EncodingJSON
+	}
+	return &recordWriter{
+		out: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    url.GetParamByIntValue(constant.RecordingMaxSizeKey, constant.DefaultRecordingMaxSize),
+			MaxBackups: url.GetParamByIntValue(constant.RecordingMaxBackupsKey, constant.DefaultRecordingMaxBackups),
+			LocalTime:  true,
+		},
+		encoding:      encoding,
+		maxRecordSize: url.GetParamByIntValue(constant.RecordingMaxRecordSizeKey, constant.DefaultRecordingMaxRecordSize),
+	}
+}
+
+func (w *recordWriter) write(data []byte) error {
+	w.buf.Write(data)
+	if w.buf.Len() >= flushSize {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *recordWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.out.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *recordWriter) close() error {
+	err := w.flush()
+	if closeErr := w.out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type recordedUser struct {
+	Name     string
+	Password string
+}
+
+// recordedInvoker returns the @result, or the error if the method is Fail
+type recordedInvoker struct {
+	protocol.BaseInvoker
+	result protocol.Result
+	failed protocol.Result
+}
+
+func (i *recordedInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	if inv.MethodName() == "Fail" {
+		return i.failed
+	}
+	return i.result
+}
+
+func newRecordedInvoker(t *testing.T, params url.Values) *recordedInvoker {
+	u, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" + params.Encode())
+	assert.NoError(t, err)
+	return &recordedInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(u),
+		result:      &protocol.RPCResult{Rest: &recordedUser{Name: "alex", Password: "pass"}},
+		failed:      &protocol.RPCResult{Err: errors.New("user not found")},
+	}
+}
+
+func readRecords(t *testing.T, path string) []*Record {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	reader := NewReader(file)
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	record := &Record{
+		Time:        time.Unix(1700000000, 0),
+		Interface:   "com.ikurento.user.UserProvider",
+		Version:     "1.0.0",
+		Method:      "GetUser",
+		Types:       []string{"java.lang.String"},
+		Arguments:   []interface{}{"1"},
+		Attachments: map[string]interface{}{"traceId": "t1"},
+		Result:      map[string]interface{}{"name": "alex"},
+		Latency:     time.Millisecond,
+	}
+	for _, encoding := range []Encoding{EncodingJSON, EncodingHessian} {
+		data, err := Marshal(record, encoding)
+		assert.NoError(t, err)
+		assert.Equal(t, byte(encoding), data[0])
+
+		// the records are read one by one, and the truncated one is reported
+		reader := NewReader(io.MultiReader(bytes.NewReader(data), bytes.NewReader(data), bytes.NewReader(data[:len(data)-1])))
+		for i := 0; i < 2; i++ {
+			decoded, err := reader.Next()
+			assert.NoError(t, err)
+			assert.Equal(t, record.Interface, decoded.Interface)
+			assert.Equal(t, record.Method, decoded.Method)
+			assert.Equal(t, record.Types, decoded.Types)
+			assert.Equal(t, []interface{}{"1"}, decoded.Arguments)
+			assert.Equal(t, map[string]interface{}{"traceId": "t1"}, decoded.Attachments)
+			assert.Equal(t, time.Millisecond, decoded.Latency)
+			assert.True(t, record.Time.Equal(decoded.Time))
+		}
+		_, err = reader.Next()
+		assert.EqualError(t, err, "truncated record body: unexpected EOF")
+	}
+
+	_, err := ParseEncoding("xml")
+	assert.EqualError(t, err, `unknown recording encoding "xml"`)
+}
+
+func TestRecordingFilter(t *testing.T) {
+	for _, encoding := range []string{"json", "hessian"} {
+		path := filepath.Join(t.TempDir(), "user.rec")
+		invoker := newRecordedInvoker(t, url.Values{
+			"interface":                      {"com.ikurento.user.UserProvider"},
+			"version":                        {"1.0.0"},
+			"recording":                      {path},
+			"recording.encoding":             {encoding},
+			"recording.methods":              {"GetUser,Login,Fail"},
+			"methods.Login.recording.rate":   {"0"},
+			"recording.max.record.size":      {"4096"},
+			"methods.GetUser.recording.rate": {"1"},
+		})
+		f := newRecordingFilter(16)
+		call := func(method string, args ...interface{}) {
+			inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName(method),
+				invocation.WithArguments(args),
+				invocation.WithAttachments(map[string]interface{}{"traceId": "t1", "token": "abc"}))
+			f.Invoke(context.Background(), invoker, inv)
+		}
+		call("GetUser", &recordedUser{Name: "alex", Password: "pass"})
+		// Login is sampled at the rate 0, and Logout isn't recorded
+		call("Login", "alex")
+		call("Logout", "alex")
+		call("Fail", "bob")
+		// the records too large are dropped
+		call("GetUser", string(make([]byte, 8192)))
+		f.close()
+
+		records := readRecords(t, path)
+		assert.Len(t, records, 2, encoding)
+		record := records[0]
+		assert.Equal(t, "com.ikurento.user.UserProvider", record.Interface)
+		assert.Equal(t, "1.0.0", record.Version)
+		assert.Equal(t, "GetUser", record.Method)
+		assert.Len(t, record.Arguments, 1)
+		assert.Equal(t, "******", toStringMap(record.Arguments[0])["password"])
+		assert.Equal(t, "alex", toStringMap(record.Arguments[0])["name"])
+		assert.Equal(t, "******", toStringMap(record.Result)["password"])
+		assert.Equal(t, "t1", record.Attachments["traceId"])
+		assert.Equal(t, "******", record.Attachments["token"])
+		assert.Equal(t, "Fail", records[1].Method)
+		assert.Equal(t, "user not found", records[1].Error)
+		assert.Equal(t, uint64(1), f.oversized.Load())
+	}
+}
+
+func TestRecordingDisabled(t *testing.T) {
+	f := newRecordingFilter(1)
+	defer f.close()
+	invoker := newRecordedInvoker(t, url.Values{"interface": {"com.ikurento.user.UserProvider"}})
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+	assert.Equal(t, invoker.result, f.Invoke(context.Background(), invoker, inv))
+	assert.Equal(t, float64(0), testing.AllocsPerRun(10, func() {
+		f.Invoke(context.Background(), invoker, inv)
+	}))
+}
+
+func toStringMap(v interface{}) map[string]interface{} {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			converted[k.(string)] = v
+		}
+		return converted
+	}
+	return nil
+}
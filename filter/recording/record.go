@@ -0,0 +1,214 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+// Encoding is the encoding of the body of a record
+type Encoding byte
+
+const (
+	EncodingJSON    Encoding = 'j'
+	EncodingHessian Encoding = 'h'
+)
+
+const (
+	// headerLength is the length of the header of a record, which is the encoding and the length of the body
+	headerLength = 5
+	// maxBodyLength guards the reader against the corrupted lengths
+	maxBodyLength = 64 * 1024 * 1024
+)
+
+// ParseEncoding returns the Encoding named @name, which is json or hessian
+func ParseEncoding(name string) (Encoding, error) {
+	switch name {
+	case "", "json":
+		return EncodingJSON, nil
+	case "hessian":
+		return EncodingHessian, nil
+	}
+	return 0, perrors.Errorf("unknown recording encoding %q", name)
+}
+
+// Record is an invocation recorded by the provider. The arguments and the result are generalized into the maps like
+// the generic invocations, with the sensitive fields masked.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Interface string    `json:"interface"`
+	Group     string    `json:"group,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Method    string    `json:"method"`
+	// Types are the java types of the arguments, which are empty if they're unknown
+	Types       []string               `json:"types,omitempty"`
+	Arguments   []interface{}          `json:"arguments"`
+	Attachments map[string]interface{} `json:"attachments,omitempty"`
+	Result      interface{}            `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	Latency     time.Duration          `json:"latency"`
+}
+
+// Marshal encodes the @record with @encoding into a length-prefixed record, which is the encoding in a byte, the
+// length of the body in 4 bytes of big endian, and the body
+func Marshal(record *Record, encoding Encoding) ([]byte, error) {
+	var body []byte
+	switch encoding {
+	case EncodingJSON:
+		b, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	case EncodingHessian:
+		encoder := hessian.NewEncoder()
+		if err := encoder.Encode(record.toMap()); err != nil {
+			return nil, err
+		}
+		body = encoder.Buffer()
+	default:
+		return nil, perrors.Errorf("unknown recording encoding %q", encoding)
+	}
+	data := make([]byte, headerLength+len(body))
+	data[0] = byte(encoding)
+	binary.BigEndian.PutUint32(data[1:headerLength], uint32(len(body)))
+	copy(data[headerLength:], body)
+	return data, nil
+}
+
+// Reader reads the records written by the recording filter, the records of different encodings could be mixed
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a Reader reading the records from @r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next reads the next record, it returns io.EOF if there is no more record
+func (r *Reader) Next() (*Record, error) {
+	var header [headerLength]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, perrors.New("truncated record header")
+		}
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxBodyLength {
+		return nil, perrors.Errorf("invalid record length %d", length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r.r, body); err != nil {
+		return nil, perrors.Wrap(err, "truncated record body")
+	}
+
+	switch Encoding(header[0]) {
+	case EncodingJSON:
+		record := &Record{}
+		if err := json.Unmarshal(body, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	case EncodingHessian:
+		decoded, err := hessian.NewDecoder(body).Decode()
+		if err != nil {
+			return nil, err
+		}
+		fields, ok := decoded.(map[interface{}]interface{})
+		if !ok {
+			return nil, perrors.Errorf("invalid hessian record %T", decoded)
+		}
+		return recordFromMap(fields), nil
+	}
+	return nil, perrors.Errorf("unknown recording encoding %q", header[0])
+}
+
+// toMap converts the record into a map, so that it is encoded by hessian without being registered as a pojo
+func (r *Record) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"time":        r.Time,
+		"interface":   r.Interface,
+		"group":       r.Group,
+		"version":     r.Version,
+		"method":      r.Method,
+		"types":       r.Types,
+		"arguments":   r.Arguments,
+		"attachments": r.Attachments,
+		"result":      r.Result,
+		"error":       r.Error,
+		"latency":     int64(r.Latency),
+	}
+}
+
+func recordFromMap(fields map[interface{}]interface{}) *Record {
+	record := &Record{
+		Interface: toString(fields["interface"]),
+		Group:     toString(fields["group"]),
+		Version:   toString(fields["version"]),
+		Method:    toString(fields["method"]),
+		Result:    fields["result"],
+		Error:     toString(fields["error"]),
+	}
+	if t, ok := fields["time"].(time.Time); ok {
+		record.Time = t
+	}
+	if latency, ok := fields["latency"].(int64); ok {
+		record.Latency = time.Duration(latency)
+	}
+	switch types := fields["types"].(type) {
+	case []string:
+		record.Types = types
+	case []interface{}:
+		for _, t := range types {
+			record.Types = append(record.Types, toString(t))
+		}
+	}
+	if args, ok := fields["arguments"].([]interface{}); ok {
+		record.Arguments = args
+	}
+	if attachments, ok := fields["attachments"].(map[interface{}]interface{}); ok {
+		record.Attachments = make(map[string]interface{}, len(attachments))
+		for k, v := range attachments {
+			record.Attachments[toString(k)] = v
+		}
+	}
+	return record
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
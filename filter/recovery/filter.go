@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package recovery provides a provider filter which turns the panics of the service into errors. It is put at the
+// head of the provider filters unless -recovery is configured in the filters of the service.
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// ErrProviderInternal is the error returned to the consumer for a panic of the provider, the panic message is
+// only appended if recovery.panic.message is true, and the stack is never returned
+var ErrProviderInternal = errors.New("provider internal error")
+
+var (
+	once           sync.Once
+	recoveryFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.RecoveryFilterKey, newFilter)
+}
+
+// Filter recovers the panics raised by the filters behind it and the service
+type Filter struct{}
+
+func newFilter() filter.Filter {
+	if recoveryFilter == nil {
+		once.Do(func() {
+			recoveryFilter = &Filter{}
+		})
+	}
+	return recoveryFilter
+}
+
+// Invoke returns an internal error for a panic, either raised by the filters behind or recovered by the proxy
+// invoker from the service
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) (result protocol.Result) {
+	defer func() {
+		if e := recover(); e != nil {
+			result = onPanic(invoker, invocation, &protocol.PanicError{Value: e, Stack: debug.Stack()}, nil)
+		}
+	}()
+	result = invoker.Invoke(ctx, invocation)
+	if result == nil {
+		return result
+	}
+	var panicErr *protocol.PanicError
+	if errors.As(result.Error(), &panicErr) {
+		result = onPanic(invoker, invocation, panicErr, result.Attachments())
+	}
+	return result
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// PanicResult logs and reports the panic @value raised by handling @invocation of @invoker outside the filters, e.g.
+// on the executor of the service, and returns the result of the internal error the same as the filter
+func PanicResult(invoker protocol.Invoker, invocation protocol.Invocation, value interface{}, stack []byte) protocol.Result {
+	return onPanic(invoker, invocation, &protocol.PanicError{Value: value, Stack: stack}, nil)
+}
+
+// onPanic logs and reports the panic, and returns the result of the internal error
+func onPanic(invoker protocol.Invoker, invocation protocol.Invocation, panicErr *protocol.PanicError,
+	attachments map[string]interface{}) protocol.Result {
+	url := invoker.GetURL()
+	logger.Errorf("[recovery filter] recover a panic of the service %s, method: %s, arguments: %d, remote: %v, panic: %v\n%s",
+		url.ServiceKey(), invocation.MethodName(), len(invocation.Arguments()),
+		invocation.GetAttachmentInterface(constant.RemoteAddr), panicErr.Value, panicErr.Stack)
+	metrics.Publish(rpc.NewPanicEvent(invoker, invocation))
+
+	err := ErrProviderInternal
+	if url.GetMethodParamBool(invocation.MethodName(), constant.RecoveryPanicMessageKey,
+		url.GetParamBool(constant.RecoveryPanicMessageKey, false)) {
+		err = fmt.Errorf("%w: %v", ErrProviderInternal, panicErr.Value)
+	}
+	if attachments == nil {
+		// the same as the proxy invoker, so that the protocol knows the version of the consumer
+		attachments = invocation.Attachments()
+	}
+	return &protocol.RPCResult{Err: common.NewRPCError(common.StatusInternal, err), Attrs: attachments}
+}
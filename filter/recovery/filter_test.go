@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type panicInvoker struct {
+	*protocol.BaseInvoker
+	result protocol.Result
+}
+
+func (ivk *panicInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	if ivk.result == nil {
+		panic("nil map")
+	}
+	return ivk.result
+}
+
+func newPanicInvoker(t *testing.T, params string, result protocol.Result) *panicInvoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" + params)
+	assert.NoError(t, err)
+	return &panicInvoker{BaseInvoker: protocol.NewBaseInvoker(url), result: result}
+}
+
+func TestFilterInvokePanic(t *testing.T) {
+	ch := make(chan metrics.MetricsEvent, 1)
+	metrics.Subscribe(constant.MetricsRpc, ch)
+
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"1"},
+		map[string]interface{}{constant.Dubbo: "2.0.2"})
+	result := newFilter().Invoke(context.Background(), newPanicInvoker(t, "", nil), inv)
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Error()))
+	assert.True(t, errors.Is(result.Error(), ErrProviderInternal))
+	assert.NotContains(t, result.Error().Error(), "nil map")
+	assert.Equal(t, "2.0.2", result.Attachment(constant.Dubbo, nil))
+
+	select {
+	case <-ch:
+	default:
+		t.Fatal("the panic event is not published")
+	}
+}
+
+func TestFilterInvokePanicMessage(t *testing.T) {
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	result := newFilter().Invoke(context.Background(),
+		newPanicInvoker(t, "methods.GetUser.recovery.panic.message=true", nil), inv)
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Error()))
+	assert.Contains(t, result.Error().Error(), "nil map")
+
+	result = newFilter().Invoke(context.Background(),
+		newPanicInvoker(t, "recovery.panic.message=true&methods.GetUser.recovery.panic.message=false", nil), inv)
+	assert.NotContains(t, result.Error().Error(), "nil map")
+}
+
+func TestFilterInvokePanicError(t *testing.T) {
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	attachments := map[string]interface{}{"key": "value"}
+	panicked := &protocol.RPCResult{Err: &protocol.PanicError{Value: "nil map"}, Attrs: attachments}
+	result := newFilter().Invoke(context.Background(), newPanicInvoker(t, "", panicked), inv)
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Error()))
+	assert.Equal(t, attachments, result.Attachments())
+
+	bizErr := errors.New("biz")
+	result = newFilter().Invoke(context.Background(), newPanicInvoker(t, "", &protocol.RPCResult{Err: bizErr}), inv)
+	assert.Equal(t, bizErr, result.Error())
+
+	result = newFilter().Invoke(context.Background(), newPanicInvoker(t, "", &protocol.RPCResult{Rest: "ok"}), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "ok", result.Result())
+}
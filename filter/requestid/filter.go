@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package requestid
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	consumerOnce      sync.Once
+	consumerRequestID *consumerFilter
+
+	providerOnce      sync.Once
+	providerRequestID *providerFilter
+)
+
+func init() {
+	extension.SetFilter(constant.RequestIDConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.RequestIDProviderFilterKey, newProviderFilter)
+}
+
+// consumerFilter propagates the request id to the providers
+type consumerFilter struct{}
+
+func newConsumerFilter() filter.Filter {
+	if consumerRequestID == nil {
+		consumerOnce.Do(func() {
+			consumerRequestID = &consumerFilter{}
+		})
+	}
+	return consumerRequestID
+}
+
+// Invoke sets the request id of the context, the one set by the user, or a new one into the attachments
+func (f *consumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	key := keyOf(url)
+	id := FromContext(ctx)
+	if len(id) == 0 {
+		id = invocation.GetAttachmentWithDefaultValue(key, "")
+	}
+	if len(strings.TrimSpace(id)) == 0 {
+		id = generatorOf(url).Generate()
+	}
+	invocation.SetAttachment(key, id)
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse does nothing, the request id echoed by the provider is in the attachments of @result
+func (f *consumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// providerFilter binds the request id to the context handed to the service
+type providerFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	if providerRequestID == nil {
+		providerOnce.Do(func() {
+			providerRequestID = &providerFilter{}
+		})
+	}
+	return providerRequestID
+}
+
+// Invoke binds the received request id, or a new one if the caller sent none, to the context
+func (f *providerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	key := keyOf(url)
+	id := invocation.GetAttachmentWithDefaultValue(key, "")
+	if len(strings.TrimSpace(id)) == 0 {
+		id = generatorOf(url).Generate()
+		invocation.SetAttachment(key, id)
+	}
+	return invoker.Invoke(NewContext(ctx, id), invocation)
+}
+
+// OnResponse echoes the request id in the attachments of @result
+func (f *providerFilter) OnResponse(_ context.Context, result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	key := keyOf(invoker.GetURL())
+	if id, ok := invocation.GetAttachment(key); ok {
+		result.AddAttachment(key, id)
+	}
+	return result
+}
+
+func keyOf(url *common.URL) string {
+	return url.GetParam(constant.RequestIDKey, constant.DefaultRequestIDKey)
+}
@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package requestid
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// serviceInvoker records the request id of the context handed to the service
+type serviceInvoker struct {
+	*protocol.BaseInvoker
+	id string
+}
+
+func newServiceInvoker(t *testing.T, params string) *serviceInvoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.UserProvider?" + params)
+	assert.NoError(t, err)
+	return &serviceInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+}
+
+func (iv *serviceInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	iv.id = FromContext(ctx)
+	return &protocol.RPCResult{}
+}
+
+// invokeProvider invokes the provider filter like the protocols
+func invokeProvider(invoker protocol.Invoker, inv protocol.Invocation) protocol.Result {
+	f := &providerFilter{}
+	return f.OnResponse(context.Background(), f.Invoke(context.Background(), invoker, inv), invoker, inv)
+}
+
+func TestProviderFilterPassthrough(t *testing.T) {
+	invoker := newServiceInvoker(t, "")
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"x-request-id": "req-1"})
+
+	result := invokeProvider(invoker, inv)
+	assert.Equal(t, "req-1", invoker.id)
+	assert.Equal(t, "req-1", result.Attachment("x-request-id", ""))
+}
+
+func TestProviderFilterGenerate(t *testing.T) {
+	invoker := newServiceInvoker(t, "")
+
+	result := invokeProvider(invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	_, err := uuid.Parse(invoker.id)
+	assert.NoError(t, err)
+	assert.Equal(t, invoker.id, result.Attachment("x-request-id", ""))
+
+	// a new one for each request
+	first := invoker.id
+	invokeProvider(invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.NotEqual(t, first, invoker.id)
+}
+
+func TestProviderFilterKeyAndGenerator(t *testing.T) {
+	invoker := newServiceInvoker(t, "request.id.key=trace-no&request.id.generator=snowflake")
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"x-request-id": "req-1"})
+
+	result := invokeProvider(invoker, inv)
+	_, err := strconv.ParseInt(invoker.id, 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, invoker.id, result.Attachment("trace-no", ""))
+}
+
+func TestConsumerFilterInvoke(t *testing.T) {
+	f := &consumerFilter{}
+	invoker := newServiceInvoker(t, "")
+
+	// the request id of the context handed to a service is propagated by the nested calls
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	f.Invoke(NewContext(context.Background(), "req-1"), invoker, inv)
+	assert.Equal(t, "req-1", inv.GetAttachmentWithDefaultValue("x-request-id", ""))
+
+	// the one set by the user
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"x-request-id": "req-2"})
+	f.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, "req-2", inv.GetAttachmentWithDefaultValue("x-request-id", ""))
+
+	// a new one
+	inv = invocation.NewRPCInvocation("GetUser", nil, nil)
+	f.Invoke(context.Background(), invoker, inv)
+	_, err := uuid.Parse(inv.GetAttachmentWithDefaultValue("x-request-id", ""))
+	assert.NoError(t, err)
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	g := newSnowflakeGenerator()
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id, err := strconv.ParseInt(g.Generate(), 10, 64)
+		assert.NoError(t, err)
+		assert.Greater(t, id, last)
+		last = id
+	}
+}
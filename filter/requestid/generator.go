@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package requestid
+
+import (
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/google/uuid"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeSequenceMask = 1<<snowflakeSequenceBits - 1
+)
+
+// snowflakeEpoch is the start of the timestamps of the snowflake ids, 2020-01-01 UTC
+var snowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	generatorsLock sync.RWMutex
+	generators     = map[string]Generator{
+		constant.DefaultRequestIDGenerator: uuidGenerator{},
+		"snowflake":                        newSnowflakeGenerator(),
+	}
+)
+
+// Generator generates the unique request ids
+type Generator interface {
+	Generate() string
+}
+
+// SetGenerator registers the generator selected by request.id.generator
+func SetGenerator(name string, generator Generator) {
+	generatorsLock.Lock()
+	defer generatorsLock.Unlock()
+	generators[name] = generator
+}
+
+// GetGenerator returns the generator named @name, or the uuid one if it is not registered
+func GetGenerator(name string) Generator {
+	generatorsLock.RLock()
+	defer generatorsLock.RUnlock()
+	if generator, ok := generators[name]; ok {
+		return generator
+	}
+	logger.Warnf("[requestid filter] the request id generator %s is not registered, use uuid instead", name)
+	return generators[constant.DefaultRequestIDGenerator]
+}
+
+// generatorOf returns the generator configured by @url
+func generatorOf(url *common.URL) Generator {
+	return GetGenerator(url.GetParam(constant.RequestIDGeneratorKey, constant.DefaultRequestIDGenerator))
+}
+
+type uuidGenerator struct{}
+
+func (uuidGenerator) Generate() string {
+	return uuid.NewString()
+}
+
+// snowflakeGenerator generates the time ordered ids made of the milliseconds since snowflakeEpoch, the node id and
+// a sequence in the millisecond
+type snowflakeGenerator struct {
+	mu       sync.Mutex
+	node     int64
+	last     int64
+	sequence int64
+}
+
+func newSnowflakeGenerator() *snowflakeGenerator {
+	// the node id is derived from the host and the pid, so that the processes of a host get different ones mostly
+	host, _ := os.Hostname()
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(host + "/" + strconv.Itoa(os.Getpid())))
+	return &snowflakeGenerator{node: int64(h.Sum32()) & (1<<snowflakeNodeBits - 1)}
+}
+
+func (g *snowflakeGenerator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Since(snowflakeEpoch).Milliseconds()
+	if now < g.last {
+		// the clock moved backwards, keep the ids increasing
+		now = g.last
+	}
+	if now == g.last {
+		g.sequence = (g.sequence + 1) & snowflakeSequenceMask
+		if g.sequence == 0 {
+			// the sequence of the millisecond is used up, borrow the next millisecond
+			now++
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.last = now
+	return strconv.FormatInt(now<<(snowflakeNodeBits+snowflakeSequenceBits)|g.node<<snowflakeSequenceBits|g.sequence, 10)
+}
@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package requestid provides a pair of filters correlating the logs of a request end to end by a request id.
+//
+// The consumer filter propagates the request id of the context, or a new one, by the attachment request.id.key. The
+// provider filter binds the received request id, or a new one if the caller sent none, to the context handed to the
+// service, so that it is logged by logger.CtxInfof and the like and propagated by the nested calls, and echoes it in
+// the attachments of the response so that the callers could quote it.
+package requestid
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/logger"
+)
+
+// LogField is the field name of the request id in the logs
+const LogField = "request_id"
+
+type requestIDKey struct{}
+
+func init() {
+	logger.SetContextField(LogField, func(ctx context.Context) (string, bool) {
+		id := FromContext(ctx)
+		return id, len(id) > 0
+	})
+}
+
+// NewContext returns a copy of @ctx carrying the request id @id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// FromContext returns the request id of @ctx, or "" if it has none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
@@ -15,7 +15,10 @@
  * limitations under the License.
  */
 
-// Package seata provides a filter when use seata-golang, use this filter to transfer xid.
+// Package seata provides the filters propagating the xid of the global transactions of seata-golang, like
+// the TransactionPropagationFilter of seata. The consumer filter sets the xid of the context into the attachments,
+// and the provider filter binds the received xid to the context handling the invocation. The attachment key is
+// set by tx.xid.key, and other transaction frameworks could be integrated by SetTransactionManager and tx.manager.
 package seata
 
 import (
@@ -29,6 +32,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
@@ -40,36 +44,84 @@ const (
 )
 
 var (
-	once  sync.Once
-	seata *seataFilter
+	consumerOnce  sync.Once
+	consumerSeata *consumerFilter
+
+	providerOnce  sync.Once
+	providerSeata *providerFilter
 )
 
 func init() {
-	extension.SetFilter(constant.SeataFilterKey, newSeataFilter)
+	extension.SetFilter(constant.SeataConsumerFilterKey, newConsumerFilter)
+	extension.SetFilter(constant.SeataProviderFilterKey, newProviderFilter)
+	// seata is the provider filter for compatibility
+	extension.SetFilter(constant.SeataFilterKey, newProviderFilter)
 }
 
-type seataFilter struct{}
+// consumerFilter sets the xid of the global transaction of the context into the attachments
+type consumerFilter struct{}
 
-func newSeataFilter() filter.Filter {
-	if seata == nil {
-		once.Do(func() {
-			seata = &seataFilter{}
+func newConsumerFilter() filter.Filter {
+	if consumerSeata == nil {
+		consumerOnce.Do(func() {
+			consumerSeata = &consumerFilter{}
 		})
 	}
-	return seata
+	return consumerSeata
 }
 
-// Invoke Get Xid by attachment key `SEATA_XID`. When use Seata, transfer xid by attachments
-func (f *seataFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
-	xid := invocation.GetAttachmentWithDefaultValue(string(SEATA_XID), "")
-	if len(strings.TrimSpace(xid)) > 0 {
-		logger.Debugf("Method: %v,Xid: %v", invocation.MethodName(), xid)
-		return invoker.Invoke(context.WithValue(ctx, SEATA_XID, xid), invocation)
+// Invoke sets the xid got from the transaction manager by the attachment key tx.xid.key
+func (f *consumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	if xid := managerOf(url).GetXID(ctx); len(xid) > 0 {
+		invocation.SetAttachment(xidKeyOf(url), xid)
 	}
 	return invoker.Invoke(ctx, invocation)
 }
 
 // OnResponse dummy process, returns the result directly
-func (f *seataFilter) OnResponse(ctx context.Context, result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+func (f *consumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
 	return result
 }
+
+// providerFilter binds the received xid to the context handling the invocation
+type providerFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	if providerSeata == nil {
+		providerOnce.Do(func() {
+			providerSeata = &providerFilter{}
+		})
+	}
+	return providerSeata
+}
+
+// Invoke gets the xid by the attachment key tx.xid.key, or `SEATA_XID` sent by the former versions, and binds it
+// by the transaction manager until the invocation returns or panics
+func (f *providerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	xid := invocation.GetAttachmentWithDefaultValue(xidKeyOf(url), "")
+	if len(strings.TrimSpace(xid)) == 0 {
+		xid = invocation.GetAttachmentWithDefaultValue(string(SEATA_XID), "")
+	}
+	if len(strings.TrimSpace(xid)) == 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+	logger.Debugf("Method: %v,Xid: %v", invocation.MethodName(), xid)
+	ctx, unbind := managerOf(url).Bind(ctx, xid)
+	defer unbind()
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *providerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func managerOf(url *common.URL) TransactionManager {
+	return GetTransactionManager(url.GetParam(constant.TransactionManagerKey, constant.DefaultTransactionManager))
+}
+
+func xidKeyOf(url *common.URL) string {
+	return url.GetParam(constant.TransactionXidKey, constant.DefaultTransactionXidKey)
+}
@@ -19,6 +19,7 @@ package seata
 
 import (
 	"context"
+	"sync"
 	"testing"
 )
 
@@ -27,6 +28,8 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
@@ -47,10 +50,121 @@ func (iv *testMockSeataInvoker) Invoke(ctx context.Context, _ protocol.Invocatio
 }
 
 func TestSeataFilter_Invoke(t *testing.T) {
-	filter := &seataFilter{}
-	result := filter.Invoke(context.Background(), &testMockSeataInvoker{}, invocation.NewRPCInvocation("$echo",
+	filter := &providerFilter{}
+	invoker := &testMockSeataInvoker{BaseInvoker: *protocol.NewBaseInvoker(common.NewURLWithOptions())}
+	result := filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("$echo",
 		[]interface{}{"OK"}, map[string]interface{}{
 			string(SEATA_XID): "10.30.21.227:8091:2000047792",
 		}))
 	assert.Equal(t, "10.30.21.227:8091:2000047792", result.Result())
 }
+
+// fakeManager binds the xid to the manager like a goroutine local transaction context
+type fakeManager struct {
+	mu  sync.Mutex
+	xid string
+}
+
+func (m *fakeManager) GetXID(_ context.Context) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.xid
+}
+
+func (m *fakeManager) Bind(ctx context.Context, xid string) (context.Context, func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.xid = xid
+	return ctx, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.xid = ""
+	}
+}
+
+// serviceInvoker is the invoker of a service whose transactions are managed by the fake manager named @name
+type serviceInvoker struct {
+	*protocol.BaseInvoker
+	manager *fakeManager
+	service func(ctx context.Context) protocol.Result
+}
+
+func newServiceInvoker(name string, service func(ctx context.Context) protocol.Result) *serviceInvoker {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo.Transfer?tx.xid.key=X-TX&tx.manager=" + name)
+	manager := &fakeManager{}
+	SetTransactionManager(name, manager)
+	return &serviceInvoker{BaseInvoker: protocol.NewBaseInvoker(url), manager: manager, service: service}
+}
+
+func (iv *serviceInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	return iv.service(ctx)
+}
+
+// remoteInvoker passes the attachments to the provider filter of the remote service, like the protocols
+type remoteInvoker struct {
+	*protocol.BaseInvoker
+	remote *serviceInvoker
+}
+
+func (iv *remoteInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	received := invocation.NewRPCInvocation(inv.MethodName(), inv.Arguments(), inv.Attachments())
+	return (&providerFilter{}).Invoke(context.Background(), iv.remote, received)
+}
+
+// invokeRemote invokes @remote by the consumer filter with the config of @local
+func invokeRemote(ctx context.Context, local, remote *serviceInvoker, method string) protocol.Result {
+	invoker := &remoteInvoker{BaseInvoker: protocol.NewBaseInvoker(local.GetURL()), remote: remote}
+	return (&consumerFilter{}).Invoke(ctx, invoker, invocation.NewRPCInvocation(method, nil, nil))
+}
+
+func TestFilterPropagation(t *testing.T) {
+	const xid = "10.30.21.227:8091:2000047792"
+	var received string
+	storage := newServiceInvoker("fake-storage", nil)
+	storage.service = func(ctx context.Context) protocol.Result {
+		received = storage.manager.GetXID(ctx)
+		return &protocol.RPCResult{}
+	}
+	order := newServiceInvoker("fake-order", nil)
+	order.service = func(ctx context.Context) protocol.Result {
+		assert.Equal(t, xid, order.manager.GetXID(ctx))
+		return invokeRemote(ctx, order, storage, "Deduct")
+	}
+	business := newServiceInvoker("fake-business", nil)
+	business.manager.xid = xid
+
+	assert.Nil(t, invokeRemote(context.Background(), business, order, "Create").Error())
+	assert.Equal(t, xid, received)
+	// the xids are unbound after the invocations
+	assert.Empty(t, order.manager.GetXID(context.Background()))
+	assert.Empty(t, storage.manager.GetXID(context.Background()))
+}
+
+func TestFilterPropagationPanic(t *testing.T) {
+	order := newServiceInvoker("fake-panic", nil)
+	order.service = func(ctx context.Context) protocol.Result {
+		panic("panic in the transaction")
+	}
+	inv := invocation.NewRPCInvocation("Create", nil, map[string]interface{}{"X-TX": "10.30.21.227:8091:2000047792"})
+
+	assert.Panics(t, func() {
+		(&providerFilter{}).Invoke(context.Background(), order, inv)
+	})
+	assert.Empty(t, order.manager.GetXID(context.Background()))
+}
+
+func TestConsumerFilterInvoke(t *testing.T) {
+	filter := &consumerFilter{}
+	invoker := &testMockSeataInvoker{BaseInvoker: *protocol.NewBaseInvoker(common.NewURLWithOptions())}
+
+	// seata-golang binds the xid to the context
+	ctx := context.WithValue(context.Background(), SEATA_XID, "10.30.21.227:8091:2000047792")
+	inv := invocation.NewRPCInvocation("Deduct", nil, nil)
+	filter.Invoke(ctx, invoker, inv)
+	assert.Equal(t, "10.30.21.227:8091:2000047792", inv.GetAttachmentWithDefaultValue(constant.DefaultTransactionXidKey, ""))
+
+	inv = invocation.NewRPCInvocation("Deduct", nil, nil)
+	filter.Invoke(context.Background(), invoker, inv)
+	_, ok := inv.GetAttachment(constant.DefaultTransactionXidKey)
+	assert.False(t, ok)
+}
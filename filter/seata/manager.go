@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package seata
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+var (
+	managersLock sync.RWMutex
+	managers     = map[string]TransactionManager{
+		constant.DefaultTransactionManager: &seataManager{},
+	}
+)
+
+// TransactionManager is the integration point of a transaction framework, which binds the xid of the global
+// transaction to the contexts
+type TransactionManager interface {
+	// GetXID returns the xid of the global transaction of @ctx, or "" if there is none
+	GetXID(ctx context.Context) string
+	// Bind binds @xid to the context handling an invocation, and returns the func unbinding it
+	Bind(ctx context.Context, xid string) (context.Context, func())
+}
+
+// SetTransactionManager registers the transaction manager selected by tx.manager
+func SetTransactionManager(name string, manager TransactionManager) {
+	managersLock.Lock()
+	defer managersLock.Unlock()
+	managers[name] = manager
+}
+
+// GetTransactionManager returns the transaction manager named @name, or the seata one if it is not registered
+func GetTransactionManager(name string) TransactionManager {
+	managersLock.RLock()
+	defer managersLock.RUnlock()
+	if manager, ok := managers[name]; ok {
+		return manager
+	}
+	return managers[constant.DefaultTransactionManager]
+}
+
+// seataManager binds the xid to the context value SEATA_XID, which is read by seata-golang
+type seataManager struct{}
+
+func (m *seataManager) GetXID(ctx context.Context) string {
+	if xid, ok := ctx.Value(SEATA_XID).(string); ok {
+		return xid
+	}
+	return ""
+}
+
+func (m *seataManager) Bind(ctx context.Context, xid string) (context.Context, func()) {
+	return context.WithValue(ctx, SEATA_XID, xid), func() {}
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sentinel_test
+
+import (
+	"context"
+)
+
+import (
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter/sentinel"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// The resources are named com.ikurento.user.UserProvider and com.ikurento.user.UserProvider:GetUser
+// with sentinel.resource.simple=true configured on the service.
+func ExampleLoadRules() {
+	err := sentinel.LoadRules("com.ikurento.user.UserProvider", &sentinel.Rules{
+		FlowRules: []*flow.Rule{{
+			Resource:               "com.ikurento.user.UserProvider:GetUser",
+			TokenCalculateStrategy: flow.Direct,
+			ControlBehavior:        flow.Reject,
+			Threshold:              100,
+			StatIntervalInMs:       1000,
+		}},
+		DegradeRules: []*circuitbreaker.Rule{{
+			Resource:         "com.ikurento.user.UserProvider",
+			Strategy:         circuitbreaker.ErrorRatio,
+			RetryTimeoutMs:   3000,
+			MinRequestAmount: 10,
+			StatIntervalMs:   1000,
+			Threshold:        0.5,
+		}},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ExampleSetDubboConsumerServiceFallback() {
+	sentinel.SetDubboConsumerServiceFallback("com.ikurento.user.UserProvider",
+		func(_ context.Context, _ protocol.Invoker, _ protocol.Invocation, _ *base.BlockError) protocol.Result {
+			// degrade to an empty user instead of failing the request
+			return &protocol.RPCResult{Rest: struct{}{}}
+		})
+}
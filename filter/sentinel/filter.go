@@ -18,7 +18,11 @@
 // Package sentinel provides a filter when using sentinel.
 // Integrate Sentinel Go MUST HAVE:
 // 1. Must initialize Sentinel Go run environment, refer to https://github.com/alibaba/sentinel-golang/blob/master/api/init.go
-// 2. Register rules for resources user want to guard
+// 2. Register rules for resources user want to guard, by the rule managers of sentinel, by LoadRules, or by the
+// rules of each service in the config center, see Rules
+//
+// A blocked invocation fails with a throttled error by default, which the failover cluster does not retry.
+// The fallback can be replaced globally or per service, see SetDubboConsumerServiceFallback.
 package sentinel
 
 import (
@@ -88,15 +92,51 @@ func (d DubboLoggerWrapper) ErrorEnabled() bool {
 	return true
 }
 
-func sentinelExit(ctx context.Context, result protocol.Result) {
-	if methodEntry := ctx.Value(MethodEntryKey); methodEntry != nil {
-		e := methodEntry.(*base.SentinelEntry)
-		sentinel.TraceError(e, result.Error())
-		e.Exit()
+// sentinelEntry enters the interface resource and the method resource of the invocation, the entries are exited
+// in OnResponse with the error of the result recorded. The @fallback is called once the invocation is blocked.
+func sentinelEntry(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
+	prefix string, trafficType base.TrafficType, fallback DubboFallback) protocol.Result {
+	defaultRuleManager.subscribe(invoker.GetURL())
+	interfaceResourceName, methodResourceName := getResourceName(invoker, invocation, prefix)
+
+	interfaceEntry, b := sentinel.Entry(interfaceResourceName,
+		sentinel.WithResourceType(base.ResTypeRPC),
+		sentinel.WithTrafficType(trafficType))
+	if b != nil {
+		// interface blocked
+		return fallback(ctx, invoker, invocation, b)
+	}
+	methodEntry, b := sentinel.Entry(methodResourceName,
+		sentinel.WithResourceType(base.ResTypeRPC),
+		sentinel.WithTrafficType(trafficType),
+		sentinel.WithArgs(invocation.Arguments()...))
+	if b != nil {
+		// method blocked
+		interfaceEntry.Exit()
+		return fallback(ctx, invoker, invocation, b)
 	}
-	if interfaceEntry := ctx.Value(InterfaceEntryKey); interfaceEntry != nil {
-		e := interfaceEntry.(*base.SentinelEntry)
-		sentinel.TraceError(e, result.Error())
+	// the entries are passed to OnResponse by the invocation, as the context of OnResponse is the one before Invoke
+	invocation.SetAttribute(string(InterfaceEntryKey), interfaceEntry)
+	invocation.SetAttribute(string(MethodEntryKey), methodEntry)
+	ctx = context.WithValue(ctx, InterfaceEntryKey, interfaceEntry)
+	ctx = context.WithValue(ctx, MethodEntryKey, methodEntry)
+	return invoker.Invoke(ctx, invocation)
+}
+
+// sentinelExit records the error of the @result and exits the entries of the invocation
+func sentinelExit(invocation protocol.Invocation, result protocol.Result) {
+	var err error
+	if result != nil {
+		err = result.Error()
+	}
+	for _, key := range []string{string(MethodEntryKey), string(InterfaceEntryKey)} {
+		v, _ := invocation.GetAttribute(key)
+		e, ok := v.(*base.SentinelEntry)
+		if !ok || e == nil {
+			continue
+		}
+		invocation.SetAttribute(key, nil)
+		sentinel.TraceError(e, err)
 		e.Exit()
 	}
 }
@@ -118,34 +158,12 @@ func newSentinelProviderFilter() filter.Filter {
 }
 
 func (d *sentinelProviderFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
-	interfaceResourceName, methodResourceName := getResourceName(invoker, invocation, getProviderPrefix())
-
-	var (
-		interfaceEntry *base.SentinelEntry
-		methodEntry    *base.SentinelEntry
-		b              *base.BlockError
-	)
-	interfaceEntry, b = sentinel.Entry(interfaceResourceName, sentinel.WithResourceType(base.ResTypeRPC), sentinel.WithTrafficType(base.Inbound))
-	if b != nil {
-		// interface blocked
-		return sentinelDubboProviderFallback(ctx, invoker, invocation, b)
-	}
-	ctx = context.WithValue(ctx, InterfaceEntryKey, interfaceEntry)
-
-	methodEntry, b = sentinel.Entry(methodResourceName,
-		sentinel.WithResourceType(base.ResTypeRPC),
-		sentinel.WithTrafficType(base.Inbound),
-		sentinel.WithArgs(invocation.Arguments()...))
-	if b != nil {
-		// method blocked
-		return sentinelDubboProviderFallback(ctx, invoker, invocation, b)
-	}
-	ctx = context.WithValue(ctx, MethodEntryKey, methodEntry)
-	return invoker.Invoke(ctx, invocation)
+	return sentinelEntry(ctx, invoker, invocation, getProviderPrefix(), base.Inbound,
+		getServiceFallback(&providerServiceFallbacks, invoker.GetURL(), sentinelDubboProviderFallback))
 }
 
-func (d *sentinelProviderFilter) OnResponse(ctx context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
-	sentinelExit(ctx, result)
+func (d *sentinelProviderFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	sentinelExit(invocation, result)
 	return result
 }
 
@@ -166,39 +184,22 @@ func newSentinelConsumerFilter() filter.Filter {
 }
 
 func (d *sentinelConsumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
-	interfaceResourceName, methodResourceName := getResourceName(invoker, invocation, getConsumerPrefix())
-	var (
-		interfaceEntry *base.SentinelEntry
-		methodEntry    *base.SentinelEntry
-		b              *base.BlockError
-	)
-
-	interfaceEntry, b = sentinel.Entry(interfaceResourceName, sentinel.WithResourceType(base.ResTypeRPC), sentinel.WithTrafficType(base.Outbound))
-	if b != nil {
-		// interface blocked
-		return sentinelDubboConsumerFallback(ctx, invoker, invocation, b)
-	}
-	ctx = context.WithValue(ctx, InterfaceEntryKey, interfaceEntry)
-
-	methodEntry, b = sentinel.Entry(methodResourceName, sentinel.WithResourceType(base.ResTypeRPC),
-		sentinel.WithTrafficType(base.Outbound), sentinel.WithArgs(invocation.Arguments()...))
-	if b != nil {
-		// method blocked
-		return sentinelDubboConsumerFallback(ctx, invoker, invocation, b)
-	}
-	ctx = context.WithValue(ctx, MethodEntryKey, methodEntry)
-
-	return invoker.Invoke(ctx, invocation)
+	return sentinelEntry(ctx, invoker, invocation, getConsumerPrefix(), base.Outbound,
+		getServiceFallback(&consumerServiceFallbacks, invoker.GetURL(), sentinelDubboConsumerFallback))
 }
 
-func (d *sentinelConsumerFilter) OnResponse(ctx context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
-	sentinelExit(ctx, result)
+func (d *sentinelConsumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	sentinelExit(invocation, result)
 	return result
 }
 
 var (
 	sentinelDubboConsumerFallback = getDefaultDubboFallback()
 	sentinelDubboProviderFallback = getDefaultDubboFallback()
+
+	// consumerServiceFallbacks and providerServiceFallbacks are the fallbacks of each service, keyed by interface
+	consumerServiceFallbacks sync.Map
+	providerServiceFallbacks sync.Map
 )
 
 type DubboFallback func(context.Context, protocol.Invoker, protocol.Invocation, *base.BlockError) protocol.Result
@@ -211,9 +212,38 @@ func SetDubboProviderFallback(f DubboFallback) {
 	sentinelDubboProviderFallback = f
 }
 
+// SetDubboConsumerServiceFallback sets the fallback of the blocked invocations to the service @interfaceName,
+// which takes precedence over the one set by SetDubboConsumerFallback. A nil @f removes the fallback.
+func SetDubboConsumerServiceFallback(interfaceName string, f DubboFallback) {
+	setServiceFallback(&consumerServiceFallbacks, interfaceName, f)
+}
+
+// SetDubboProviderServiceFallback sets the fallback of the blocked invocations of the service @interfaceName,
+// which takes precedence over the one set by SetDubboProviderFallback. A nil @f removes the fallback.
+func SetDubboProviderServiceFallback(interfaceName string, f DubboFallback) {
+	setServiceFallback(&providerServiceFallbacks, interfaceName, f)
+}
+
+func setServiceFallback(fallbacks *sync.Map, interfaceName string, f DubboFallback) {
+	if f == nil {
+		fallbacks.Delete(interfaceName)
+		return
+	}
+	fallbacks.Store(interfaceName, f)
+}
+
+func getServiceFallback(fallbacks *sync.Map, url *common.URL, defaultFallback DubboFallback) DubboFallback {
+	if f, ok := fallbacks.Load(url.Service()); ok {
+		return f.(DubboFallback)
+	}
+	return defaultFallback
+}
+
+// getDefaultDubboFallback returns the fallback which fails the blocked invocation with a throttled error, the
+// *base.BlockError is wrapped and can be unwrapped by errors.As
 func getDefaultDubboFallback() DubboFallback {
 	return func(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, blockError *base.BlockError) protocol.Result {
-		return &protocol.RPCResult{Err: blockError}
+		return &protocol.RPCResult{Err: common.NewRPCError(common.StatusThrottled, blockError)}
 	}
 }
 
@@ -225,14 +255,24 @@ const (
 	InterfaceEntryKey = constant.DubboCtxKey("$$sentinelInterfaceEntry")
 )
 
+// getResourceName returns the resource names of the interface and the method. They are interface and
+// interface:method if sentinel.resource.simple is true, which are the same as the rules of the java stack,
+// otherwise they are interface:group:version and prefix+interface:group:version:method(parameter types).
 func getResourceName(invoker protocol.Invoker, invocation protocol.Invocation, prefix string) (interfaceResourceName, methodResourceName string) {
+	url := invoker.GetURL()
+	if url.GetParamBool(constant.SentinelSimpleResourceKey, false) {
+		interfaceResourceName = url.Service()
+		methodResourceName = interfaceResourceName + ":" + invocation.MethodName()
+		return
+	}
+
 	var sb strings.Builder
 
 	sb.WriteString(prefix)
 	if getInterfaceGroupAndVersionEnabled() {
-		interfaceResourceName = getColonSeparatedKey(invoker.GetURL())
+		interfaceResourceName = getColonSeparatedKey(url)
 	} else {
-		interfaceResourceName = invoker.GetURL().Service()
+		interfaceResourceName = url.Service()
 	}
 	sb.WriteString(interfaceResourceName)
 	sb.WriteString(":")
@@ -19,12 +19,15 @@ package sentinel
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
 )
 
 import (
+	"github.com/alibaba/sentinel-golang/core/base"
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
 	"github.com/alibaba/sentinel-golang/core/flow"
 
 	"github.com/stretchr/testify/assert"
@@ -127,3 +130,86 @@ func TestGetResourceName(t *testing.T) {
 	assert.Equal(t, "com.ikurento.user.UserProvider:myGroup:1.0.0", interfaceResourceName)
 	assert.Equal(t, "prefix_com.ikurento.user.UserProvider:myGroup:1.0.0:hello()", methodResourceName)
 }
+
+func TestGetSimpleResourceName(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?interface=com.ikurento.user.UserProvider&" +
+		"version=1.0.0&group=myGroup&sentinel.resource.simple=true")
+	assert.NoError(t, err)
+	mockInvoker := protocol.NewBaseInvoker(url)
+	interfaceResourceName, methodResourceName := getResourceName(mockInvoker,
+		invocation.NewRPCInvocation("hello", []interface{}{"OK"}, make(map[string]interface{})), "prefix_")
+	assert.Equal(t, "com.ikurento.user.UserProvider", interfaceResourceName)
+	assert.Equal(t, "com.ikurento.user.UserProvider:hello", methodResourceName)
+}
+
+func TestSentinelFilter_Fallback(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/FallbackProvider?interface=com.ikurento.user.FallbackProvider&" +
+		"sentinel.resource.simple=true")
+	assert.NoError(t, err)
+	mockInvoker := protocol.NewBaseInvoker(url)
+	_, err = flow.LoadRules([]*flow.Rule{{
+		Resource:               "com.ikurento.user.FallbackProvider:hello",
+		TokenCalculateStrategy: flow.Direct,
+		ControlBehavior:        flow.Reject,
+		Threshold:              0,
+		StatIntervalInMs:       1000,
+	}})
+	assert.NoError(t, err)
+	defer flow.ClearRules()
+
+	// a blocked invocation fails with a throttled error by default
+	f := &sentinelConsumerFilter{}
+	result := f.Invoke(context.TODO(), mockInvoker, invocation.NewRPCInvocation("hello", nil, nil))
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(result.Error()))
+	var blockError *base.BlockError
+	assert.True(t, errors.As(result.Error(), &blockError))
+	assert.Equal(t, base.BlockTypeFlow, blockError.BlockType())
+
+	// the fallback of the service takes precedence over the global one
+	SetDubboConsumerServiceFallback("com.ikurento.user.FallbackProvider",
+		func(context.Context, protocol.Invoker, protocol.Invocation, *base.BlockError) protocol.Result {
+			return &protocol.RPCResult{Rest: "fallback"}
+		})
+	result = f.Invoke(context.TODO(), mockInvoker, invocation.NewRPCInvocation("hello", nil, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "fallback", result.Result())
+
+	SetDubboConsumerServiceFallback("com.ikurento.user.FallbackProvider", nil)
+	result = f.Invoke(context.TODO(), mockInvoker, invocation.NewRPCInvocation("hello", nil, nil))
+	assert.Error(t, result.Error())
+}
+
+type failedInvoker struct {
+	*protocol.BaseInvoker
+	invoked int
+}
+
+func (ivk *failedInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	return &protocol.RPCResult{Err: errors.New("request failed")}
+}
+
+func TestSentinelFilter_DegradeOnError(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/DegradeProvider?interface=com.ikurento.user.DegradeProvider&" +
+		"sentinel.resource.simple=true")
+	assert.NoError(t, err)
+	mockInvoker := &failedInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	_, err = circuitbreaker.LoadRules([]*circuitbreaker.Rule{{
+		Resource:         "com.ikurento.user.DegradeProvider",
+		Strategy:         circuitbreaker.ErrorCount,
+		RetryTimeoutMs:   60000,
+		MinRequestAmount: 1,
+		StatIntervalMs:   10000,
+		Threshold:        3,
+	}})
+	assert.NoError(t, err)
+	defer circuitbreaker.ClearRules()
+
+	// the errors recorded on exit open the circuit breaker of the interface
+	f := &sentinelProviderFilter{}
+	for i := 0; i < 5; i++ {
+		inv := invocation.NewRPCInvocation("hello", nil, nil)
+		f.OnResponse(context.TODO(), f.Invoke(context.TODO(), mockInvoker, inv), mockInvoker, inv)
+	}
+	assert.Equal(t, 3, mockInvoker.invoked)
+}
@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sentinel
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+import (
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Rules are the flow rules and the degrade(circuit breaking) rules from a source. In the config center the rules
+// of a service are keyed by interface.sentinel-rules in json, e.g.
+//
+//	{
+//	  "flowRules": [{"resource": "com.ikurento.user.UserProvider:GetUser", "threshold": 100}],
+//	  "degradeRules": [{"resource": "com.ikurento.user.UserProvider", "strategy": 1, "retryTimeoutMs": 3000,
+//	    "minRequestAmount": 10, "statIntervalMs": 1000, "threshold": 0.5}]
+//	}
+type Rules struct {
+	FlowRules    []*flow.Rule           `json:"flowRules"`
+	DegradeRules []*circuitbreaker.Rule `json:"degradeRules"`
+}
+
+var defaultRuleManager = newRuleManager()
+
+// LoadRules replaces the rules loaded from @source with @rules, the rules of the resources no longer in @rules
+// are cleared. The rules of the resources not loaded from @source are left untouched, so the rules of different
+// services can be loaded independently.
+func LoadRules(source string, rules *Rules) error {
+	return defaultRuleManager.load(source, rules)
+}
+
+// ruleManager loads the rules of each source into the rule managers of sentinel resource by resource
+type ruleManager struct {
+	mutex sync.Mutex
+	// flowResources and degradeResources are the resources of the rules loaded from each source
+	flowResources    map[string]map[string]struct{}
+	degradeResources map[string]map[string]struct{}
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+}
+
+func newRuleManager() *ruleManager {
+	return &ruleManager{
+		flowResources:    make(map[string]map[string]struct{}),
+		degradeResources: make(map[string]map[string]struct{}),
+	}
+}
+
+func (m *ruleManager) load(source string, rules *Rules) error {
+	if rules == nil {
+		rules = &Rules{}
+	}
+	flowRules := make(map[string][]*flow.Rule)
+	for _, rule := range rules.FlowRules {
+		if err := flow.IsValidRule(rule); err != nil {
+			return perrors.WithMessagef(err, "invalid flow rule %v", rule)
+		}
+		flowRules[rule.Resource] = append(flowRules[rule.Resource], rule)
+	}
+	degradeRules := make(map[string][]*circuitbreaker.Rule)
+	for _, rule := range rules.DegradeRules {
+		if err := circuitbreaker.IsValidRule(rule); err != nil {
+			return perrors.WithMessagef(err, "invalid degrade rule %v", rule)
+		}
+		degradeRules[rule.Resource] = append(degradeRules[rule.Resource], rule)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	flowResources := make(map[string]struct{}, len(flowRules))
+	for resource, resourceRules := range flowRules {
+		if _, err := flow.LoadRulesOfResource(resource, resourceRules); err != nil {
+			return err
+		}
+		flowResources[resource] = struct{}{}
+	}
+	for resource := range m.flowResources[source] {
+		if _, ok := flowResources[resource]; !ok {
+			if err := flow.ClearRulesOfResource(resource); err != nil {
+				return err
+			}
+		}
+	}
+	m.flowResources[source] = flowResources
+
+	degradeResources := make(map[string]struct{}, len(degradeRules))
+	for resource, resourceRules := range degradeRules {
+		if _, err := circuitbreaker.LoadRulesOfResource(resource, resourceRules); err != nil {
+			return err
+		}
+		degradeResources[resource] = struct{}{}
+	}
+	for resource := range m.degradeResources[source] {
+		if _, ok := degradeResources[resource]; !ok {
+			if err := circuitbreaker.ClearRulesOfResource(resource); err != nil {
+				return err
+			}
+		}
+	}
+	m.degradeResources[source] = degradeResources
+	return nil
+}
+
+// subscribe subscribes the rules of the service of @url from the config center if it is not subscribed yet
+func (m *ruleManager) subscribe(url *common.URL) {
+	key := url.Service() + constant.SentinelRuleSuffix
+	if m.subscriptions.Subscribed(key) {
+		return
+	}
+	m.subscriptions.Subscribe(conf.GetEnvInstance().GetDynamicConfiguration(), key, m, 0)
+}
+
+// Process applies the changed rules of a service, the rules are cleared once deleted. Malformed rules are
+// ignored and the previous rules are kept.
+func (m *ruleManager) Process(event *config_center.ConfigChangeEvent) {
+	content, ok := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || !ok || len(content) == 0 {
+		if err := m.load(event.Key, nil); err != nil {
+			logger.Warnf("[Sentinel Filter] clear sentinel rules %s error, %v", event.Key, err)
+		}
+		return
+	}
+	rules := &Rules{}
+	err := json.Unmarshal([]byte(content), rules)
+	if err == nil {
+		err = m.load(event.Key, rules)
+	}
+	if err != nil {
+		logger.Warnf("[Sentinel Filter] parse sentinel rules %s error, %v, the previous rules are kept.", event.Key, err)
+		return
+	}
+	logger.Infof("[Sentinel Filter] load sentinel rules success,key=%s,flowRules=%d,degradeRules=%d",
+		event.Key, len(rules.FlowRules), len(rules.DegradeRules))
+}
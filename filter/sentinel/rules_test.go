@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sentinel
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/alibaba/sentinel-golang/core/circuitbreaker"
+	"github.com/alibaba/sentinel-golang/core/flow"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const ruleContent = `{
+	"flowRules": [{"resource": "com.ikurento.user.RuleProvider:GetUser", "threshold": 100}],
+	"degradeRules": [{"resource": "com.ikurento.user.RuleProvider", "strategy": 2, "retryTimeoutMs": 3000,
+		"minRequestAmount": 10, "statIntervalMs": 1000, "threshold": 5}]
+}`
+
+func TestLoadRules(t *testing.T) {
+	defer flow.ClearRules()
+	err := LoadRules("user", &Rules{FlowRules: []*flow.Rule{
+		{Resource: "user:GetUser", Threshold: 10},
+		{Resource: "user:SetUser", Threshold: 10},
+	}})
+	assert.NoError(t, err)
+	err = LoadRules("order", &Rules{FlowRules: []*flow.Rule{{Resource: "order:GetOrder", Threshold: 10}}})
+	assert.NoError(t, err)
+	assert.Len(t, flow.GetRules(), 3)
+
+	// the rules of the resources no longer loaded from the source are cleared, the others are left untouched
+	err = LoadRules("user", &Rules{FlowRules: []*flow.Rule{{Resource: "user:GetUser", Threshold: 20}}})
+	assert.NoError(t, err)
+	assert.Empty(t, flow.GetRulesOfResource("user:SetUser"))
+	assert.Equal(t, float64(20), flow.GetRulesOfResource("user:GetUser")[0].Threshold)
+	assert.Len(t, flow.GetRulesOfResource("order:GetOrder"), 1)
+
+	// invalid rules are rejected
+	err = LoadRules("user", &Rules{FlowRules: []*flow.Rule{{Resource: "user:GetUser", Threshold: -1}}})
+	assert.Error(t, err)
+	assert.Len(t, flow.GetRulesOfResource("user:GetUser"), 1)
+}
+
+func TestRuleManagerProcess(t *testing.T) {
+	defer flow.ClearRules()
+	defer circuitbreaker.ClearRules()
+	m := newRuleManager()
+	key := "com.ikurento.user.RuleProvider.sentinel-rules"
+	m.Process(&config_center.ConfigChangeEvent{Key: key, Value: ruleContent, ConfigType: remoting.EventTypeAdd})
+	assert.Len(t, flow.GetRulesOfResource("com.ikurento.user.RuleProvider:GetUser"), 1)
+	assert.Len(t, circuitbreaker.GetRulesOfResource("com.ikurento.user.RuleProvider"), 1)
+
+	// malformed rules are ignored
+	m.Process(&config_center.ConfigChangeEvent{Key: key, Value: "{", ConfigType: remoting.EventTypeUpdate})
+	assert.Len(t, flow.GetRulesOfResource("com.ikurento.user.RuleProvider:GetUser"), 1)
+
+	m.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeDel})
+	assert.Empty(t, flow.GetRulesOfResource("com.ikurento.user.RuleProvider:GetUser"))
+	assert.Empty(t, circuitbreaker.GetRulesOfResource("com.ikurento.user.RuleProvider"))
+}
+
+func TestRuleManagerSubscribe(t *testing.T) {
+	defer flow.ClearRules()
+	defer circuitbreaker.ClearRules()
+	factory := &config_center.MockDynamicConfigurationFactory{Content: ruleContent}
+	mockURL, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, err := factory.GetDynamicConfiguration(mockURL)
+	assert.NoError(t, err)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	m := newRuleManager()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/RuleProvider?interface=com.ikurento.user.RuleProvider")
+	m.subscribe(url)
+	assert.True(t, m.subscriptions.Subscribed("com.ikurento.user.RuleProvider.sentinel-rules"))
+	assert.Len(t, flow.GetRulesOfResource("com.ikurento.user.RuleProvider:GetUser"), 1)
+}
@@ -0,0 +1,231 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package slowinvocation provides a filter which flags the invocations whose rt exceeds the slow threshold, e.g.
+//
+//	params:
+//	  slow.threshold: 500ms
+//	  methods.GetUser.slow.threshold: 2s
+//
+// The thresholds can be overridden by the rules in the config center without redeploy, see Rule. Each slow
+// invocation is counted in the slow requests metric, and the first slow.sample.limit ones of a method in each
+// slow.sample.window are logged in detail, with the masked and size-limited arguments. If slow.dump is true, the
+// goroutines are dumped into slow.dump.dir on the first slow invocation of a method in each window.
+package slowinvocation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/masking"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	once                 sync.Once
+	slowInvocationFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.SlowInvocationFilterKey, newFilter)
+}
+
+// Filter flags the slow invocations
+type Filter struct {
+	rules rules
+	// methods are the states of the methods, keyed by the service key and the method name
+	methods sync.Map
+	// report outputs the detailed records, they are logged by default
+	report func(*record)
+}
+
+// methodState is the state of the invocations of a method
+type methodState struct {
+	inFlight atomic.Int64
+	sampler  sampler
+}
+
+// record is the detailed record of a slow invocation
+type record struct {
+	service   string
+	method    string
+	rt        time.Duration
+	threshold time.Duration
+	// caller is the remote address for the provider, and the application for the consumer
+	caller   string
+	inFlight int64
+	// suppressed is the count of the slow invocations of the method not recorded since the last record
+	suppressed int
+	arguments  string
+	// dump is the file of the goroutine dump, empty if the goroutines are not dumped
+	dump string
+}
+
+func (r *record) String() string {
+	s := fmt.Sprintf("slow invocation service=%s method=%s rt=%s threshold=%s caller=%s inflight=%d suppressed=%d args=%s",
+		r.service, r.method, r.rt, r.threshold, r.caller, r.inFlight, r.suppressed, r.arguments)
+	if len(r.dump) > 0 {
+		s += " dump=" + r.dump
+	}
+	return s
+}
+
+func newFilter() filter.Filter {
+	if slowInvocationFilter == nil {
+		once.Do(func() {
+			slowInvocationFilter = &Filter{report: logRecord}
+		})
+	}
+	return slowInvocationFilter
+}
+
+func logRecord(r *record) {
+	logger.Warnf("[slow invocation filter] %s", r)
+}
+
+// Invoke measures the rt of the invocation, and flags it if it exceeds the threshold of the method
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	state := f.stateOf(url, invocation.MethodName())
+	state.inFlight.Inc()
+	defer state.inFlight.Dec()
+
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	rt := time.Since(start)
+	if threshold := f.rules.thresholdOf(url, invocation.MethodName()); rt > threshold {
+		f.onSlow(invoker, invocation, state, rt, threshold)
+	}
+	return result
+}
+
+// OnResponse does nothing
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func (f *Filter) stateOf(url *common.URL, method string) *methodState {
+	key := url.ServiceKey() + "#" + method
+	if s, ok := f.methods.Load(key); ok {
+		return s.(*methodState)
+	}
+	s, _ := f.methods.LoadOrStore(key, &methodState{})
+	return s.(*methodState)
+}
+
+// onSlow counts the slow invocation, and reports its record if it is sampled
+func (f *Filter) onSlow(invoker protocol.Invoker, invocation protocol.Invocation, state *methodState,
+	rt, threshold time.Duration) {
+	metrics.Publish(rpc.NewSlowInvocationEvent(invoker, invocation, rt))
+
+	url := invoker.GetURL()
+	sampled, suppressed, first := state.sampler.sample(time.Now(),
+		url.GetParamByIntValue(constant.SlowInvocationSampleLimitKey, constant.DefaultSlowInvocationSampleLimit),
+		url.GetParamDurationWithDefault(constant.SlowInvocationSampleWindowKey, constant.DefaultSlowInvocationSampleWindow))
+	if !sampled {
+		return
+	}
+	r := &record{
+		service:    url.ServiceKey(),
+		method:     invocation.MethodName(),
+		rt:         rt,
+		threshold:  threshold,
+		caller:     callerOf(url, invocation),
+		inFlight:   state.inFlight.Load(),
+		suppressed: suppressed,
+		arguments: summarize(invocation.Arguments(), masking.FromURL(url),
+			url.GetParamByIntValue(constant.SlowInvocationArgsMaxSizeKey, constant.DefaultSlowInvocationArgsMaxSize)),
+	}
+	if first && url.GetParamBool(constant.SlowInvocationDumpKey, false) {
+		r.dump = dumpGoroutines(url.GetParam(constant.SlowInvocationDumpDirKey, os.TempDir()), url.Service(), r.method)
+	}
+	f.report(r)
+}
+
+func callerOf(url *common.URL, invocation protocol.Invocation) string {
+	if remote, ok := invocation.GetAttachment(constant.RemoteAddr); ok && len(remote) > 0 {
+		return remote
+	}
+	return url.GetParam(constant.ApplicationKey, "")
+}
+
+// summarize renders the masked @args, which is truncated to @maxSize characters
+func summarize(args []interface{}, masker *masking.Masker, maxSize int) string {
+	builder := strings.Builder{}
+	for i, arg := range args {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(masker.String(arg))
+		if builder.Len() > maxSize*4 {
+			// long enough for maxSize characters of any encoding
+			break
+		}
+	}
+	runes := []rune(builder.String())
+	if len(runes) <= maxSize {
+		return string(runes)
+	}
+	return string(runes[:maxSize]) + "..."
+}
+
+// dumpGoroutines writes the stacks of all the goroutines into a file in @dir, and returns the path of the file
+func dumpGoroutines(dir, service, method string) string {
+	name := fmt.Sprintf("slow-%s-%s-%d.goroutine", sanitize(service), sanitize(method), time.Now().UnixNano())
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		logger.Warnf("[slow invocation filter] Can not create the goroutine dump %s, %v", path, err)
+		return ""
+	}
+	defer file.Close()
+	if err = pprof.Lookup("goroutine").WriteTo(file, 2); err != nil {
+		logger.Warnf("[slow invocation filter] Can not dump the goroutines into %s, %v", path, err)
+		return ""
+	}
+	return path
+}
+
+// sanitize replaces the characters not allowed in a file name with _
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,212 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowinvocation
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/filter/masking"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const userProviderRuleKey = "com.ikurento.user.UserProvider::.slow-invocation"
+
+// slowInvoker sleeps the delay of the method before it returns
+type slowInvoker struct {
+	*protocol.BaseInvoker
+	delays map[string]time.Duration
+}
+
+func newSlowInvoker(t *testing.T, params string, delays map[string]time.Duration) protocol.Invoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&application=user-center&" + params)
+	assert.NoError(t, err)
+	return &slowInvoker{BaseInvoker: protocol.NewBaseInvoker(url), delays: delays}
+}
+
+func (i *slowInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	time.Sleep(i.delays[inv.MethodName()])
+	return &protocol.RPCResult{}
+}
+
+// recorder collects the reported records
+type recorder struct {
+	mu      sync.Mutex
+	records []*record
+}
+
+func (r *recorder) report(rec *record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+}
+
+func (r *recorder) get() []*record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*record(nil), r.records...)
+}
+
+func call(f *Filter, invoker protocol.Invoker, method string, args ...interface{}) {
+	attachments := map[string]interface{}{constant.RemoteAddr: "10.0.0.1:54321"}
+	f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation(method, args, attachments))
+}
+
+func TestFilterThreshold(t *testing.T) {
+	r := &recorder{}
+	f := &Filter{report: r.report}
+	invoker := newSlowInvoker(t, "slow.threshold=1s&methods.GetUser.slow.threshold=10ms",
+		map[string]time.Duration{"GetUser": 30 * time.Millisecond, "ListUsers": 30 * time.Millisecond})
+
+	call(f, invoker, "ListUsers")
+	assert.Empty(t, r.get())
+
+	call(f, invoker, "GetUser", "A001", map[string]string{"password": "123456"})
+	records := r.get()
+	assert.Len(t, records, 1)
+	rec := records[0]
+	assert.Equal(t, "com.ikurento.user.UserProvider", rec.service)
+	assert.Equal(t, "GetUser", rec.method)
+	assert.True(t, rec.rt >= 30*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, rec.threshold)
+	assert.Equal(t, "10.0.0.1:54321", rec.caller)
+	assert.Equal(t, int64(1), rec.inFlight)
+	assert.Equal(t, `A001,{"password":"******"}`, rec.arguments)
+	assert.Empty(t, rec.dump)
+	assert.Contains(t, rec.String(), "slow invocation service=com.ikurento.user.UserProvider method=GetUser")
+}
+
+func TestFilterInFlight(t *testing.T) {
+	r := &recorder{}
+	f := &Filter{report: r.report}
+	invoker := newSlowInvoker(t, "slow.threshold=10ms", map[string]time.Duration{"GetUser": 50 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call(f, invoker, "GetUser")
+		}()
+	}
+	wg.Wait()
+	records := r.get()
+	assert.Len(t, records, 3)
+	var maxInFlight int64
+	for _, rec := range records {
+		if rec.inFlight > maxInFlight {
+			maxInFlight = rec.inFlight
+		}
+	}
+	assert.True(t, maxInFlight > 1)
+	assert.Equal(t, int64(0), f.stateOf(invoker.GetURL(), "GetUser").inFlight.Load())
+}
+
+func TestFilterSampling(t *testing.T) {
+	ch := make(chan metrics.MetricsEvent, 10)
+	metrics.Subscribe(constant.MetricsRpc, ch)
+
+	r := &recorder{}
+	f := &Filter{report: r.report}
+	invoker := newSlowInvoker(t, "slow.threshold=5ms&slow.sample.limit=2&slow.sample.window=200ms",
+		map[string]time.Duration{"GetUser": 10 * time.Millisecond})
+
+	for i := 0; i < 5; i++ {
+		call(f, invoker, "GetUser")
+	}
+	// all the slow invocations are counted, but only 2 of them are recorded in the window
+	assert.Len(t, ch, 5)
+	assert.Len(t, r.get(), 2)
+
+	time.Sleep(200 * time.Millisecond)
+	call(f, invoker, "GetUser")
+	records := r.get()
+	assert.Len(t, records, 3)
+	assert.Equal(t, 3, records[2].suppressed)
+}
+
+func TestFilterDump(t *testing.T) {
+	dir := t.TempDir()
+	r := &recorder{}
+	f := &Filter{report: r.report}
+	invoker := newSlowInvoker(t, "slow.threshold=5ms&slow.dump=true&slow.dump.dir="+dir,
+		map[string]time.Duration{"GetUser": 10 * time.Millisecond})
+
+	call(f, invoker, "GetUser")
+	call(f, invoker, "GetUser")
+	records := r.get()
+	assert.Len(t, records, 2)
+	// only the first slow invocation in the window is dumped
+	assert.True(t, strings.HasPrefix(records[0].dump, dir))
+	assert.Empty(t, records[1].dump)
+	content, err := os.ReadFile(records[0].dump)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "goroutine")
+}
+
+func TestFilterRule(t *testing.T) {
+	r := &recorder{}
+	f := &Filter{report: r.report}
+	invoker := newSlowInvoker(t, "slow.threshold=1s",
+		map[string]time.Duration{"GetUser": 20 * time.Millisecond, "ListUsers": 20 * time.Millisecond})
+	update := func(rule string) {
+		f.rules.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey, Value: rule, ConfigType: remoting.EventTypeUpdate})
+	}
+
+	update("methods:\n  GetUser: 5ms")
+	call(f, invoker, "GetUser")
+	call(f, invoker, "ListUsers")
+	assert.Len(t, r.get(), 1)
+
+	update("threshold: 5ms")
+	call(f, invoker, "ListUsers")
+	assert.Len(t, r.get(), 2)
+
+	// a malformed rule is ignored
+	update("threshold: -5ms")
+	call(f, invoker, "ListUsers")
+	assert.Len(t, r.get(), 3)
+
+	f.rules.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey, ConfigType: remoting.EventTypeDel})
+	call(f, invoker, "ListUsers")
+	assert.Len(t, r.get(), 3)
+}
+
+func TestSummarize(t *testing.T) {
+	masker := masking.New(nil, nil)
+	assert.Equal(t, "", summarize(nil, masker, 10))
+	assert.Equal(t, "abc,1", summarize([]interface{}{"abc", 1}, masker, 10))
+	assert.Equal(t, "用户用户用...", summarize([]interface{}{"用户用户用户"}, masker, 5))
+}
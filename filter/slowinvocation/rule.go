@@ -0,0 +1,163 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowinvocation
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Rule is the slow invocation rule in the config center, e.g.
+//
+//	threshold: 500ms
+//	methods:
+//	  GetUser: 2s
+//
+// The rule of a service is keyed by service:version:group.slow-invocation, its thresholds take precedence over
+// the static config of the service.
+type Rule struct {
+	Threshold string            `yaml:"threshold"`
+	Methods   map[string]string `yaml:"methods"`
+}
+
+// thresholds are the parsed thresholds of a rule, zero if absent
+type thresholds struct {
+	service time.Duration
+	methods map[string]time.Duration
+}
+
+func parseRule(content string) (*thresholds, error) {
+	rule := &Rule{}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	t := &thresholds{methods: make(map[string]time.Duration, len(rule.Methods))}
+	var err error
+	if len(rule.Threshold) > 0 {
+		if t.service, err = parseThreshold(rule.Threshold); err != nil {
+			return nil, err
+		}
+	}
+	for method, threshold := range rule.Methods {
+		if t.methods[method], err = parseThreshold(threshold); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func parseThreshold(threshold string) (time.Duration, error) {
+	d, err := common.ParseDuration(threshold)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, perrors.Errorf("the threshold %s is not positive", threshold)
+	}
+	return d, nil
+}
+
+// rules holds the thresholds of the rules in the config center and the parsed static thresholds, the rules are
+// subscribed on the first invocation of the services
+type rules struct {
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+	// dynamic are the thresholds of the rules, keyed by the rule key
+	dynamic sync.Map
+	// static are the parsed static thresholds, keyed by the config
+	static sync.Map
+}
+
+// thresholdOf returns the slow threshold of @method of the service with @url. The threshold of the method in the
+// rule is preferred, then the one of the service in the rule, the one of the method in the static config, the one
+// of the service in the static config and the default at last.
+func (r *rules) thresholdOf(url *common.URL, method string) time.Duration {
+	if t := r.load(config_center.ServiceRuleKey(url, constant.SlowInvocationRuleSuffix)); t != nil {
+		if d, ok := t.methods[method]; ok {
+			return d
+		}
+		if t.service > 0 {
+			return t.service
+		}
+	}
+	return r.staticOf(url.GetMethodParam(method, constant.SlowInvocationThresholdKey,
+		url.GetParam(constant.SlowInvocationThresholdKey, constant.DefaultSlowInvocationThreshold)))
+}
+
+// staticOf returns the parsed static @threshold, the default is used if it is invalid
+func (r *rules) staticOf(threshold string) time.Duration {
+	if d, ok := r.static.Load(threshold); ok {
+		return d.(time.Duration)
+	}
+	d, err := parseThreshold(threshold)
+	if err != nil {
+		logger.Warnf("[slow invocation filter] Invalid slow threshold %s, %v, the default %s is used.",
+			threshold, err, constant.DefaultSlowInvocationThreshold)
+		d, _ = time.ParseDuration(constant.DefaultSlowInvocationThreshold)
+	}
+	r.static.Store(threshold, d)
+	return d
+}
+
+// load returns the thresholds of the rule of @key, and subscribes the rule if it is not subscribed yet
+func (r *rules) load(key string) *thresholds {
+	if t, ok := r.dynamic.Load(key); ok {
+		return t.(*thresholds)
+	}
+	if !r.subscriptions.Subscribe(conf.GetEnvInstance().GetDynamicConfiguration(), key, r, 0) {
+		return nil
+	}
+	if t, ok := r.dynamic.Load(key); ok {
+		return t.(*thresholds)
+	}
+	return nil
+}
+
+// Process applies the changed rule immediately. A malformed rule is ignored and the previous rule is kept.
+func (r *rules) Process(event *config_center.ConfigChangeEvent) {
+	content, ok := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || !ok || len(content) == 0 {
+		r.dynamic.Delete(event.Key)
+		return
+	}
+	t, err := parseRule(content)
+	if err != nil {
+		logger.Warnf("[slow invocation filter]Parse slow invocation rule %s error, %v, the previous rule is kept.", event.Key, err)
+		return
+	}
+	r.dynamic.Store(event.Key, t)
+	logger.Infof("[slow invocation filter]Parse slow invocation rule success,key=%s,threshold=%v,methods=%v",
+		event.Key, t.service, t.methods)
+}
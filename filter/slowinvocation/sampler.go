@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowinvocation
+
+import (
+	"sync"
+	"time"
+)
+
+// sampler limits the detailed records of the slow invocations of a method in each window
+type sampler struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	sampled     int
+	// suppressed is the count of the slow invocations not recorded since the last recorded one
+	suppressed int
+}
+
+// sample returns whether the slow invocation at @now is recorded in detail, which is true for the first @limit ones
+// in the @window, and the count of the ones suppressed before it. first is true for the first one in the window.
+func (s *sampler) sample(now time.Time, limit int, window time.Duration) (sampled bool, suppressed int, first bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.windowStart) >= window {
+		s.windowStart = now
+		s.sampled = 0
+		first = true
+	}
+	if s.sampled >= limit {
+		s.suppressed++
+		return false, 0, first
+	}
+	s.sampled++
+	suppressed, s.suppressed = s.suppressed, 0
+	return true, suppressed, first
+}
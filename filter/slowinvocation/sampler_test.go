@@ -0,0 +1,56 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowinvocation
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	s := &sampler{}
+	now := time.Now()
+
+	sampled, suppressed, first := s.sample(now, 2, time.Second)
+	assert.True(t, sampled)
+	assert.Equal(t, 0, suppressed)
+	assert.True(t, first)
+
+	sampled, _, first = s.sample(now.Add(100*time.Millisecond), 2, time.Second)
+	assert.True(t, sampled)
+	assert.False(t, first)
+
+	for i := 0; i < 3; i++ {
+		sampled, _, _ = s.sample(now.Add(200*time.Millisecond), 2, time.Second)
+		assert.False(t, sampled)
+	}
+
+	// a new window
+	sampled, suppressed, first = s.sample(now.Add(time.Second), 2, time.Second)
+	assert.True(t, sampled)
+	assert.Equal(t, 3, suppressed)
+	assert.True(t, first)
+
+	// nothing is recorded with a zero limit
+	sampled, _, _ = (&sampler{}).sample(now, 0, time.Second)
+	assert.False(t, sampled)
+}
@@ -29,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
@@ -68,7 +69,7 @@ func (f *tokenFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invo
 		var remoteTkn string
 		remoteTknIface, exist := attas[constant.TokenKey]
 		if !exist || remoteTknIface == nil {
-			return &protocol.RPCResult{Err: perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName())}
+			return &protocol.RPCResult{Err: common.NewRPCError(common.StatusPermissionDenied, perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName()))}
 		}
 		switch remoteTknIface.(type) {
 		case string:
@@ -78,17 +79,17 @@ func (f *tokenFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invo
 			// deal with triple protocol
 			remoteTkns := remoteTknIface.([]string)
 			if len(remoteTkns) != 1 {
-				return &protocol.RPCResult{Err: perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName())}
+				return &protocol.RPCResult{Err: common.NewRPCError(common.StatusPermissionDenied, perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName()))}
 			}
 			remoteTkn = remoteTkns[0]
 		default:
-			return &protocol.RPCResult{Err: perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName())}
+			return &protocol.RPCResult{Err: common.NewRPCError(common.StatusPermissionDenied, perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName()))}
 		}
 
 		if strings.EqualFold(invokerTkn, remoteTkn) {
 			return invoker.Invoke(ctx, invocation)
 		}
-		return &protocol.RPCResult{Err: perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName())}
+		return &protocol.RPCResult{Err: common.NewRPCError(common.StatusPermissionDenied, perrors.Errorf(InValidTokenFormat, invoker, invocation.MethodName()))}
 	}
 
 	return invoker.Invoke(ctx, invocation)
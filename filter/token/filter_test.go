@@ -83,4 +83,5 @@ func TestTokenFilterInvokeNotEqual(t *testing.T) {
 	result := filter.Invoke(context.Background(),
 		protocol.NewBaseInvoker(testUrl), invocation.NewRPCInvocation("MethodName", []interface{}{"OK"}, attch))
 	assert.NotNil(t, result.Error())
+	assert.Equal(t, common.StatusPermissionDenied, common.CodeOf(result.Error()))
 }
@@ -27,8 +27,12 @@ Package tps provides a filter for limiting the requests by TPS.
 	  ... # other configuration
 	  tps.limiter: "method-service", # it should be the name of limiter. if the value is 'default',
 	                                 # the MethodServiceTpsLimiter will be used.
-	  tps.limit.rejected.handler: "default", # optional, or the name of the implementation
+	  tps.limit.rejected.handler: "default", # optional, or the name of the implementation, e.g. "throttled"
+	                                         # which returns a retriable error with the retry-after hint
 	  if the value of 'tps.limiter' is nil or empty string, the tps filter will do nothing
+
+	the filter is created per exported service, and it creates its own limiter, so the services are limited
+	independently
 */
 package tps
 
@@ -42,6 +46,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/filter"
@@ -50,24 +55,39 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
-var (
-	once     sync.Once
-	tpsLimit *tpsLimitFilter
-)
-
 func init() {
-	extension.SetFilter(constant.TpsLimitFilterKey, newTpsLimitFilter)
+	extension.SetFilterFactory(constant.TpsLimitFilterKey, newTpsLimitFilter)
 }
 
-type tpsLimitFilter struct{}
+type tpsLimitFilter struct {
+	// limiters are the limiters of the service, keyed by the name
+	limiters sync.Map
+}
 
-func newTpsLimitFilter() filter.Filter {
-	if tpsLimit == nil {
-		once.Do(func() {
-			tpsLimit = &tpsLimitFilter{}
-		})
+// newTpsLimitFilter returns the Filter of the service of @url, and creates the limiter configured by the url ahead
+func newTpsLimitFilter(url *common.URL) filter.Filter {
+	f := &tpsLimitFilter{}
+	if url != nil {
+		if name := url.GetParam(constant.TPSLimiterKey, ""); len(name) > 0 {
+			if _, err := f.limiterOf(name); err != nil {
+				logger.Warn(err)
+			}
+		}
+	}
+	return f
+}
+
+// limiterOf returns the limiter @name of the service, the limiter is created once
+func (t *tpsLimitFilter) limiterOf(name string) (filter.TpsLimiter, error) {
+	if limiter, ok := t.limiters.Load(name); ok {
+		return limiter.(filter.TpsLimiter), nil
+	}
+	limiter, err := extension.GetTpsLimiter(name)
+	if err != nil {
+		return nil, err
 	}
-	return tpsLimit
+	actual, _ := t.limiters.LoadOrStore(name, limiter)
+	return actual.(filter.TpsLimiter), nil
 }
 
 // Invoke gets the configured limter to impose TPS limiting
@@ -76,7 +96,7 @@ func (t *tpsLimitFilter) Invoke(ctx context.Context, invoker protocol.Invoker, i
 	tpsLimiter := url.GetParam(constant.TPSLimiterKey, "")
 	rejectedExeHandler := url.GetParam(constant.TPSRejectedExecutionHandlerKey, constant.DefaultKey)
 	if len(tpsLimiter) > 0 {
-		limiter, err := extension.GetTpsLimiter(tpsLimiter)
+		limiter, err := t.limiterOf(tpsLimiter)
 		if err != nil {
 			logger.Warn(err)
 			return invoker.Invoke(ctx, invocation)
@@ -36,6 +36,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/filter/handler"
 	"dubbo.apache.org/dubbo-go/v3/filter/tps/limiter"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/tps/strategy"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
@@ -107,3 +108,29 @@ func TestGenericFilterInvokeWithDefaultTpsLimiterNotAllow(t *testing.T) {
 	assert.Nil(t, result.Error())
 	assert.Nil(t, result.Result())
 }
+
+func TestTpsLimitFilterIndependentServices(t *testing.T) {
+	newInvoker := func(service string) protocol.Invoker {
+		invokeUrl := common.NewURLWithOptions(
+			common.WithParams(url.Values{}),
+			common.WithParamsValue(constant.InterfaceKey, service),
+			common.WithParamsValue(constant.TPSLimiterKey, "method-service"),
+			common.WithParamsValue(constant.TPSLimitRateKey, "1"),
+			common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+			common.WithParamsValue(constant.TPSRejectedExecutionHandlerKey, "abort"))
+		return protocol.NewBaseInvoker(invokeUrl)
+	}
+	invoke := func(f filter.Filter, invoker protocol.Invoker) protocol.Result {
+		return f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("MethodName", nil, nil))
+	}
+
+	userInvoker, orderInvoker := newInvoker("com.test.UserService"), newInvoker("com.test.OrderService")
+	userFilter, orderFilter := newTpsLimitFilter(userInvoker.GetURL()), newTpsLimitFilter(orderInvoker.GetURL())
+	assert.Nil(t, invoke(userFilter, userInvoker).Error())
+	assert.NotNil(t, invoke(userFilter, userInvoker).Error())
+	// the limit of the other service is not consumed
+	assert.Nil(t, invoke(orderFilter, orderInvoker).Error())
+	assert.NotNil(t, invoke(orderFilter, orderInvoker).Error())
+	// neither is the one of the same service exported again
+	assert.Nil(t, invoke(newTpsLimitFilter(userInvoker.GetURL()), userInvoker).Error())
+}
@@ -0,0 +1,240 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/creasty/defaults"
+
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	conf "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// Rule is the tps limit rule in the config center, e.g.
+//
+//	key: org.apache.dubbo.UserProvider
+//	enabled: true
+//	rate: 1000
+//	interval: 1000
+//	strategy: tokenBucket
+//	burst: 100
+//	methods:
+//	  - name: GetUser
+//	    rate: 100
+//
+// The rule of a service is keyed by service:version:group.tps-limiter, it takes precedence over the static
+// config of the service, and both its service-level limit and method-level limits are enforced. The rule keyed
+// by application.tps-limiter is the ceiling shared by all the services of the application in the process.
+// A rate < 0 means no limit, and the interval(ms), strategy and burst of a method default to the service-level
+// ones.
+type Rule struct {
+	Key      string        `yaml:"key"`
+	Enabled  bool          `default:"true" yaml:"enabled"`
+	Rate     int           `default:"-1" yaml:"rate"`
+	Interval int           `default:"1000" yaml:"interval"`
+	Strategy string        `default:"default" yaml:"strategy"`
+	Burst    int           `yaml:"burst"`
+	Methods  []*MethodRule `yaml:"methods"`
+}
+
+// MethodRule is the method-level limit of a Rule
+type MethodRule struct {
+	Name     string `yaml:"name"`
+	Rate     int    `yaml:"rate"`
+	Interval int    `yaml:"interval"`
+	Strategy string `yaml:"strategy"`
+	Burst    int    `yaml:"burst"`
+}
+
+// parseRule parses the tps limit rule, the method-level config is inherited from the service-level one
+func parseRule(content string) (*Rule, error) {
+	rule := &Rule{}
+	if err := defaults.Set(rule); err != nil {
+		return nil, err
+	}
+	if err := yaml.NewDecoder(strings.NewReader(content)).Decode(rule); err != nil {
+		return nil, err
+	}
+	for _, m := range rule.Methods {
+		if len(m.Name) == 0 {
+			return nil, perrors.New("the method-level tps limit rule must have a name")
+		}
+		if m.Interval == 0 {
+			m.Interval = rule.Interval
+		}
+		if len(m.Strategy) == 0 {
+			m.Strategy = rule.Strategy
+		}
+		if m.Burst == 0 {
+			m.Burst = rule.Burst
+		}
+	}
+	return rule, nil
+}
+
+// limit is a limit strategy with its interval, which is the hint to retry a rejected request after
+type limit struct {
+	strategy filter.TpsLimitStrategy
+	interval int
+}
+
+// isAllowable returns true if there is no limit or the limit is not reached, or records the retry after
+// hint into the @invocation
+func (l *limit) isAllowable(invocation protocol.Invocation) bool {
+	if l == nil || l.strategy.IsAllowable() {
+		return true
+	}
+	invocation.SetAttribute(constant.RetryAfterKey, int64(l.interval))
+	return false
+}
+
+// ruleLimits are the limits created from a rule
+type ruleLimits struct {
+	service *limit
+	methods map[string]*limit
+}
+
+func newRuleLimits(rule *Rule) (*ruleLimits, error) {
+	limits := &ruleLimits{methods: make(map[string]*limit, len(rule.Methods))}
+	var err error
+	if limits.service, err = newLimit(rule.Rate, rule.Interval, rule.Strategy, rule.Burst); err != nil {
+		return nil, err
+	}
+	for _, m := range rule.Methods {
+		if limits.methods[m.Name], err = newLimit(m.Rate, m.Interval, m.Strategy, m.Burst); err != nil {
+			return nil, err
+		}
+	}
+	return limits, nil
+}
+
+// newLimit creates the limit, nil if @rate < 0 which means no limit
+func newLimit(rate, interval int, strategy string, burst int) (*limit, error) {
+	if rate < 0 {
+		return nil, nil
+	}
+	if interval <= 0 {
+		return nil, perrors.Errorf("the interval of the tps limit must be positive, but got %d", interval)
+	}
+	creator, err := extension.GetTpsLimitStrategyCreator(strategy)
+	if err != nil {
+		return nil, err
+	}
+	return &limit{strategy: createStrategy(creator, rate, interval, burst), interval: interval}, nil
+}
+
+// createStrategy creates the strategy with the burst if the strategy allows one, the burst defaults to the rate
+func createStrategy(creator filter.TpsLimitStrategyCreator, rate, interval, burst int) filter.TpsLimitStrategy {
+	if burstCreator, ok := creator.(filter.TpsLimitStrategyBurstCreator); ok {
+		if burst <= 0 {
+			burst = rate
+		}
+		return burstCreator.CreateWithBurst(rate, interval, burst)
+	}
+	return creator.Create(rate, interval)
+}
+
+// dynamicLimits holds the limits of the rules in the config center, the rules are subscribed on the first
+// invocation of the services
+type dynamicLimits struct {
+	// subscriptions are the rules subscribed from the config center
+	subscriptions config_center.RuleSubscriptions
+	// limits are the limits of the enabled rules, keyed by the rule key
+	limits sync.Map
+}
+
+// serviceAllowable checks the invocation against the rule of the service, ruled is false if the service
+// has no enabled rule
+func (d *dynamicLimits) serviceAllowable(url *common.URL, invocation protocol.Invocation) (allowed bool, ruled bool) {
+	limits := d.load(config_center.ServiceRuleKey(url, constant.TPSLimiterRuleSuffix))
+	if limits == nil {
+		return true, false
+	}
+	return limits.methods[invocation.MethodName()].isAllowable(invocation) && limits.service.isAllowable(invocation), true
+}
+
+// applicationAllowable checks the invocation against the ceiling of the application
+func (d *dynamicLimits) applicationAllowable(url *common.URL, invocation protocol.Invocation) bool {
+	application := url.GetParam(constant.ApplicationKey, "")
+	if len(application) == 0 {
+		return true
+	}
+	limits := d.load(application + constant.TPSLimiterRuleSuffix)
+	return limits == nil || limits.service.isAllowable(invocation)
+}
+
+// load returns the limits of the rule of @key, and subscribes the rule if it is not subscribed yet
+func (d *dynamicLimits) load(key string) *ruleLimits {
+	if limits, ok := d.limits.Load(key); ok {
+		return limits.(*ruleLimits)
+	}
+	if !d.subscriptions.Subscribe(conf.GetEnvInstance().GetDynamicConfiguration(), key, d, config_center.DefaultCoalescingWindow) {
+		return nil
+	}
+	if limits, ok := d.limits.Load(key); ok {
+		return limits.(*ruleLimits)
+	}
+	return nil
+}
+
+// Process applies the changed rule immediately, the limits are recreated so the requests limited by the
+// previous rule are not counted. A malformed rule is ignored and the previous rule is kept, and so is an
+// unchanged one with its counters.
+func (d *dynamicLimits) Process(event *config_center.ConfigChangeEvent) {
+	if event.Unchanged() {
+		return
+	}
+	content, ok := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel || !ok || len(content) == 0 {
+		d.limits.Delete(event.Key)
+		return
+	}
+	rule, err := parseRule(content)
+	if err == nil && !rule.Enabled {
+		d.limits.Delete(event.Key)
+		return
+	}
+	var limits *ruleLimits
+	if err == nil {
+		limits, err = newRuleLimits(rule)
+	}
+	if err != nil {
+		logger.Warnf("[tps limiter]Parse tps limit rule %s error, %v, the previous rule is kept.", event.Key, err)
+		return
+	}
+	d.limits.Store(event.Key, limits)
+	logger.Infof("[tps limiter]Parse tps limit rule success,key=%s,rate=%d,interval=%d", event.Key, rule.Rate, rule.Interval)
+}
@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/modern-go/concurrent"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/file"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/tps/strategy"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const userProviderRuleKey = "com.ikurento.user.UserProvider::.tps-limiter"
+
+func newDynamicTestLimiter() *MethodServiceTpsLimiter {
+	return &MethodServiceTpsLimiter{tpsState: concurrent.NewMap(), dynamic: &dynamicLimits{}}
+}
+
+func updateRule(limiter *MethodServiceTpsLimiter, key, rule string) {
+	limiter.dynamic.Process(&config_center.ConfigChangeEvent{Key: key, Value: rule, ConfigType: remoting.EventTypeUpdate})
+}
+
+// countAllowed returns the count of the allowed invocations of @method in @n invocations
+func countAllowed(limiter *MethodServiceTpsLimiter, url *common.URL, method string, n int) int {
+	allowed := 0
+	for i := 0; i < n; i++ {
+		if limiter.IsAllowable(url, invocation.NewRPCInvocation(method, nil, nil)) {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestParseRule(t *testing.T) {
+	rule, err := parseRule(`
+rate: 100
+strategy: tokenBucket
+burst: 10
+methods:
+  - name: GetUser
+    rate: 10
+  - name: UpdateUser
+    rate: 5
+    interval: 3000
+    strategy: slidingWindow
+`)
+	assert.Nil(t, err)
+	assert.True(t, rule.Enabled)
+	assert.Equal(t, 100, rule.Rate)
+	assert.Equal(t, 1000, rule.Interval)
+	assert.Equal(t, &MethodRule{Name: "GetUser", Rate: 10, Interval: 1000, Strategy: "tokenBucket", Burst: 10}, rule.Methods[0])
+	assert.Equal(t, &MethodRule{Name: "UpdateUser", Rate: 5, Interval: 3000, Strategy: "slidingWindow", Burst: 10}, rule.Methods[1])
+
+	rule, err = parseRule(`enabled: false`)
+	assert.Nil(t, err)
+	assert.False(t, rule.Enabled)
+	assert.Equal(t, -1, rule.Rate)
+
+	_, err = parseRule(`methods: [{rate: 1}]`)
+	assert.NotNil(t, err)
+}
+
+func TestDynamicRuleFlip(t *testing.T) {
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&tps.limit.rate=100&tps.limit.interval=60000")
+
+	// the static config takes effect without a rule
+	assert.Equal(t, 10, countAllowed(limiter, url, "GetUser", 10))
+
+	updateRule(limiter, userProviderRuleKey, "rate: 2\ninterval: 60000")
+	assert.Equal(t, 2, countAllowed(limiter, url, "GetUser", 10))
+
+	// the new limit takes effect immediately, and the requests limited by the previous rule are not counted
+	updateRule(limiter, userProviderRuleKey, "rate: 5\ninterval: 60000")
+	assert.Equal(t, 5, countAllowed(limiter, url, "GetUser", 10))
+
+	// the malformed rule is ignored
+	updateRule(limiter, userProviderRuleKey, "rate: [")
+	assert.Equal(t, 0, countAllowed(limiter, url, "GetUser", 10))
+	updateRule(limiter, userProviderRuleKey, "rate: 1\ninterval: -1")
+	assert.Equal(t, 0, countAllowed(limiter, url, "GetUser", 10))
+
+	// the static config takes effect again once the rule is disabled or deleted
+	updateRule(limiter, userProviderRuleKey, "rate: 1\nenabled: false")
+	assert.Equal(t, 10, countAllowed(limiter, url, "GetUser", 10))
+	updateRule(limiter, userProviderRuleKey, "rate: 1")
+	assert.Equal(t, 1, countAllowed(limiter, url, "GetUser", 10))
+	limiter.dynamic.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey, ConfigType: remoting.EventTypeDel})
+	assert.Equal(t, 10, countAllowed(limiter, url, "GetUser", 10))
+}
+
+func TestDynamicRuleMethodAndService(t *testing.T) {
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	updateRule(limiter, userProviderRuleKey, `
+rate: 3
+interval: 60000
+methods:
+  - name: GetUser
+    rate: 2
+  - name: UpdateUser
+    rate: -1
+`)
+
+	// the method-level limit is enforced
+	assert.Equal(t, 2, countAllowed(limiter, url, "GetUser", 10))
+	// and the service-level limit is enforced too, including the method without method-level limit
+	assert.Equal(t, 1, countAllowed(limiter, url, "UpdateUser", 10))
+	assert.Equal(t, 0, countAllowed(limiter, url, "DeleteUser", 10))
+
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	assert.False(t, limiter.IsAllowable(url, inv))
+	retryAfter, ok := inv.GetAttribute(constant.RetryAfterKey)
+	assert.True(t, ok)
+	assert.Equal(t, int64(60000), retryAfter)
+}
+
+func TestDynamicRuleApplicationCeiling(t *testing.T) {
+	limiter := newDynamicTestLimiter()
+	userURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&application=demo")
+	orderURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.order.OrderProvider?" +
+		"interface=com.ikurento.order.OrderProvider&application=demo&tps.limit.rate=2&tps.limit.interval=60000")
+	updateRule(limiter, "demo"+constant.TPSLimiterRuleSuffix, "rate: 5\ninterval: 60000")
+
+	// the ceiling is shared by the services of the application, and the limit of each service is enforced too
+	assert.Equal(t, 2, countAllowed(limiter, orderURL, "GetOrder", 10))
+	assert.Equal(t, 3, countAllowed(limiter, userURL, "GetUser", 10))
+	assert.Equal(t, 0, countAllowed(limiter, userURL, "GetUser", 10))
+}
+
+func TestDynamicRuleFlapping(t *testing.T) {
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	listener := config_center.NewCoalescingListener(limiter.dynamic, 50*time.Millisecond)
+
+	// only the final rule of the rapid changes is applied
+	for i := 1; i <= 100; i++ {
+		listener.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey,
+			Value: "rate: " + strconv.Itoa(i%5+1) + "\ninterval: 60000", ConfigType: remoting.EventTypeUpdate})
+	}
+	// counting consumes the limit, so wait for the coalesced rule rather than polling
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 1, countAllowed(limiter, url, "GetUser", 10))
+
+	// the counters are kept if the rule flaps back, rather than limiting from scratch
+	listener.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey, Value: "rate: 3\ninterval: 60000",
+		ConfigType: remoting.EventTypeUpdate})
+	listener.Process(&config_center.ConfigChangeEvent{Key: userProviderRuleKey, Value: "rate: 1\ninterval: 60000",
+		ConfigType: remoting.EventTypeUpdate})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0, countAllowed(limiter, url, "GetUser", 10))
+}
+
+func TestDynamicRuleSubscribe(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: "rate: 1\ninterval: 60000"}
+	mockURL, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, err := factory.GetDynamicConfiguration(mockURL)
+	assert.NoError(t, err)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	assert.Equal(t, 1, countAllowed(limiter, url, "GetUser", 10))
+	assert.True(t, limiter.dynamic.subscriptions.Subscribed(userProviderRuleKey))
+}
+
+func TestDynamicRuleFromFile(t *testing.T) {
+	factory, err := extension.GetConfigCenterFactory(constant.FileKey)
+	assert.NoError(t, err)
+	dc, err := factory.GetDynamicConfiguration(common.NewURLWithOptions(common.WithProtocol(constant.FileKey),
+		common.WithParamsValue(file.ConfigCenterDirParamName, t.TempDir())))
+	assert.NoError(t, err)
+	defer dc.(*file.FileSystemDynamicConfiguration).Close()
+	assert.NoError(t, dc.PublishConfig(userProviderRuleKey, "", "rate: 1\ninterval: 60000"))
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	assert.Equal(t, 1, countAllowed(limiter, url, "GetUser", 10))
+
+	// the rule edited takes effect
+	assert.NoError(t, dc.PublishConfig(userProviderRuleKey, "", "rate: 3\ninterval: 60000"))
+	assert.Eventually(t, func() bool { return countAllowed(limiter, url, "GetUser", 10) == 3 },
+		3*time.Second, 50*time.Millisecond)
+}
+
+func TestDynamicRuleFlipConcurrently(t *testing.T) {
+	limiter := newDynamicTestLimiter()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	updateRule(limiter, userProviderRuleKey, "rate: 1000\ninterval: 60000\nstrategy: slidingWindow")
+
+	var wg sync.WaitGroup
+	rejected := make(chan struct{}, 800)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if !limiter.IsAllowable(url, invocation.NewRPCInvocation("GetUser", nil, nil)) {
+					rejected <- struct{}{}
+				}
+			}
+		}()
+	}
+	// the traffic under the limits is never rejected while the rule is flipped
+	for i := 0; i < 50; i++ {
+		updateRule(limiter, userProviderRuleKey, "rate: 2000\ninterval: 60000\nstrategy: tokenBucket")
+		updateRule(limiter, userProviderRuleKey, "rate: 1000\ninterval: 60000\nstrategy: slidingWindow")
+	}
+	wg.Wait()
+	assert.Len(t, rejected, 0)
+}
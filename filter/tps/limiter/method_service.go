@@ -42,8 +42,8 @@ const (
 )
 
 func init() {
-	extension.SetTpsLimiter(constant.DefaultKey, GetMethodServiceTpsLimiter)
-	extension.SetTpsLimiter(name, GetMethodServiceTpsLimiter)
+	extension.SetTpsLimiter(constant.DefaultKey, NewMethodServiceTpsLimiter)
+	extension.SetTpsLimiter(name, NewMethodServiceTpsLimiter)
 }
 
 // MethodServiceTpsLimiter allows developer to config both method-level and service-level tps limiter.
@@ -111,22 +111,38 @@ func init() {
  *      tps.limit.rate: 70,
  *      tps.limit.interval: 40000
  * In this case, only UpdateUser will be limited by its configuration (70 times in 40000ms)
+ *
+ * The methods could be matched by the wildcards as well, e.g. "Get*" or "*", see common.URL GetMethodParam.
+ *
+ * The strategy is configured by tps.limit.strategy, e.g. fixedWindow, slidingWindow or tokenBucket,
+ * and the burst of the tokenBucket strategy is configured by tps.limit.burst in the same levels.
+ *
+ * The limits can be changed in the config center without redeploying, see Rule for details.
  */
 type MethodServiceTpsLimiter struct {
 	tpsState *concurrent.Map
+	dynamic  *dynamicLimits
 }
 
-// IsAllowable based on method-level and service-level.
+// IsAllowable checks the invocation against the rule of the service in the config center, or the static config
+// if there is no rule, and then against the ceiling of the application in the config center.
+func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation protocol.Invocation) bool {
+	allowed, ruled := limiter.dynamic.serviceAllowable(url, invocation)
+	if !ruled {
+		allowed = limiter.staticAllowable(url, invocation)
+	}
+	return allowed && limiter.dynamic.applicationAllowable(url, invocation)
+}
+
+// staticAllowable based on method-level and service-level.
 // The method-level has high priority which means that if there is any rate limit configuration for the method,
 // the service-level rate limit strategy will be ignored.
 // The key point is how to keep thread-safe
 // This implementation use concurrent map + loadOrStore to make implementation thread-safe
 // You can image that even multiple threads create limiter, but only one could store the limiter into tpsState
-func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation protocol.Invocation) bool {
-	methodConfigPrefix := "methods." + invocation.MethodName() + "."
-
-	methodLimitRateConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitRateKey, "")
-	methodIntervalConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitIntervalKey, "")
+func (limiter MethodServiceTpsLimiter) staticAllowable(url *common.URL, invocation protocol.Invocation) bool {
+	methodLimitRateConfig := url.GetMethodParam(invocation.MethodName(), constant.TPSLimitRateKey, "")
+	methodIntervalConfig := url.GetMethodParam(invocation.MethodName(), constant.TPSLimitIntervalKey, "")
 
 	// service-level tps limit
 	limitTarget := url.ServiceKey()
@@ -165,7 +181,7 @@ func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation p
 	}
 
 	// find the strategy config and then create one
-	limitStrategyConfig := url.GetParam(methodConfigPrefix+constant.TPSLimitStrategyKey,
+	limitStrategyConfig := url.GetMethodParam(invocation.MethodName(), constant.TPSLimitStrategyKey,
 		url.GetParam(constant.TPSLimitStrategyKey, constant.DefaultKey))
 	limitStateCreator, err := extension.GetTpsLimitStrategyCreator(limitStrategyConfig)
 	if err != nil {
@@ -173,8 +189,12 @@ func (limiter MethodServiceTpsLimiter) IsAllowable(url *common.URL, invocation p
 		return true
 	}
 
+	burst := url.GetMethodParamInt(invocation.MethodName(), constant.TPSLimitBurstKey,
+		url.GetParamInt(constant.TPSLimitBurstKey, 0))
+	strategy := createStrategy(limitStateCreator, int(limitRate), int(limitInterval), int(burst))
+
 	// we using loadOrStore to ensure thread-safe
-	limitState, _ = limiter.tpsState.LoadOrStore(limitTarget, limitStateCreator.Create(int(limitRate), int(limitInterval)))
+	limitState, _ = limiter.tpsState.LoadOrStore(limitTarget, strategy)
 
 	return limitState.(filter.TpsLimitStrategy).IsAllowable()
 }
@@ -188,8 +208,19 @@ func getLimitConfig(methodLevelConfig string,
 	configKey string,
 	defaultVal int64) int64 {
 
+	parse := func(value string) (int64, error) {
+		return strconv.ParseInt(value, 0, 0)
+	}
+	if configKey == constant.TPSLimitIntervalKey {
+		// the interval is either the milliseconds or a duration like 5s
+		parse = func(value string) (int64, error) {
+			d, err := common.ParseDuration(value)
+			return d.Milliseconds(), err
+		}
+	}
+
 	if len(methodLevelConfig) > 0 {
-		result, err := strconv.ParseInt(methodLevelConfig, 0, 0)
+		result, err := parse(methodLevelConfig)
 		if err != nil {
 			logger.Error(fmt.Sprintf("The %s for invocation %s # %s must be positive, please check your configuration!",
 				configKey, url.ServiceKey(), invocation.MethodName()))
@@ -200,7 +231,7 @@ func getLimitConfig(methodLevelConfig string,
 
 	// actually there is no method-level configuration, so we use the service-level configuration
 
-	result, err := strconv.ParseInt(url.GetParam(configKey, ""), 0, 0)
+	result, err := parse(url.GetParam(configKey, ""))
 	if err != nil {
 		logger.Errorf(fmt.Sprintf("Cannot parse the configuration %s, please check your configuration!", configKey))
 		return defaultVal
@@ -211,14 +242,23 @@ func getLimitConfig(methodLevelConfig string,
 var (
 	methodServiceTpsLimiterInstance *MethodServiceTpsLimiter
 	methodServiceTpsLimiterOnce     sync.Once
+	// sharedDynamicLimits are the limits of the rules in the config center, which are subscribed once
+	sharedDynamicLimits = &dynamicLimits{}
 )
 
 // GetMethodServiceTpsLimiter will return an MethodServiceTpsLimiter instance.
 func GetMethodServiceTpsLimiter() filter.TpsLimiter {
 	methodServiceTpsLimiterOnce.Do(func() {
-		methodServiceTpsLimiterInstance = &MethodServiceTpsLimiter{
-			tpsState: concurrent.NewMap(),
-		}
+		methodServiceTpsLimiterInstance = NewMethodServiceTpsLimiter().(*MethodServiceTpsLimiter)
 	})
 	return methodServiceTpsLimiterInstance
 }
+
+// NewMethodServiceTpsLimiter returns a new MethodServiceTpsLimiter with its own limit states of the static config,
+// while the limits in the config center are shared by all the limiters.
+func NewMethodServiceTpsLimiter() filter.TpsLimiter {
+	return &MethodServiceTpsLimiter{
+		tpsState: concurrent.NewMap(),
+		dynamic:  sharedDynamicLimits,
+	}
+}
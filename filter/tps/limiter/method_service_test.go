@@ -114,6 +114,39 @@ func TestMethodServiceTpsLimiterImplIsAllowableMethodLevelOverride(t *testing.T)
 	assert.True(t, result)
 }
 
+func TestMethodServiceTpsLimiterImplIsAllowableWildcardMethod(t *testing.T) {
+	methodName := "helloWildcard"
+	invoc := invocation.NewRPCInvocation(methodName, []interface{}{"OK"}, make(map[string]interface{}))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, methodName),
+		common.WithParamsValue(constant.TPSLimitRateKey, "20"),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "3000"),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, "invalid"),
+		common.WithParamsValue("methods.*."+constant.TPSLimitRateKey, "30"),
+		common.WithParamsValue("methods.hello*."+constant.TPSLimitRateKey, "50"),
+		common.WithParamsValue("methods.hello*."+constant.TPSLimitIntervalKey, "8000"),
+		common.WithParamsValue("methods.hello*."+constant.TPSLimitStrategyKey, "default"),
+	)
+
+	mockStrategyImpl := strategy.NewMockTpsLimitStrategy(ctrl)
+	mockStrategyImpl.EXPECT().IsAllowable().Return(true).Times(1)
+
+	extension.SetTpsLimitStrategy(constant.DefaultKey, &mockStrategyCreator{
+		rate:     50,
+		interval: 8000,
+		t:        t,
+		strategy: mockStrategyImpl,
+	})
+
+	limiter := GetMethodServiceTpsLimiter()
+	result := limiter.IsAllowable(invokeUrl, invoc)
+	assert.True(t, result)
+}
+
 func TestMethodServiceTpsLimiterImplIsAllowableBothMethodAndService(t *testing.T) {
 	methodName := "hello3"
 	methodConfigPrefix := "methods." + methodName + "."
@@ -156,3 +189,23 @@ func (creator *mockStrategyCreator) Create(rate int, interval int) filter.TpsLim
 	assert.Equal(creator.t, creator.interval, interval)
 	return creator.strategy
 }
+
+func TestMethodServiceTpsLimiterImplIsAllowableTokenBucket(t *testing.T) {
+	methodName := "hello4"
+	invoc := invocation.NewRPCInvocation(methodName, []interface{}{"OK"}, make(map[string]interface{}))
+
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.InterfaceKey, methodName),
+		common.WithParamsValue(constant.TPSLimitRateKey, "20"),
+		common.WithParamsValue(constant.TPSLimitIntervalKey, "60000"),
+		common.WithParamsValue(constant.TPSLimitStrategyKey, strategy.TokenBucketKey),
+		common.WithParamsValue(constant.TPSLimitBurstKey, "5"),
+		common.WithParamsValue("methods."+methodName+"."+constant.TPSLimitBurstKey, "2"))
+
+	limiter := GetMethodServiceTpsLimiter()
+	// the method-level burst takes effect
+	assert.True(t, limiter.IsAllowable(invokeUrl, invoc))
+	assert.True(t, limiter.IsAllowable(invokeUrl, invoc))
+	assert.False(t, limiter.IsAllowable(invokeUrl, invoc))
+}
@@ -18,7 +18,6 @@
 package strategy
 
 import (
-	"container/list"
 	"sync"
 	"time"
 )
@@ -32,9 +31,15 @@ func init() {
 	extension.SetTpsLimitStrategy("slidingWindow", &slidingWindowStrategyCreator{})
 }
 
+// slidingWindowBuckets is the number of buckets the window is divided into
+const slidingWindowBuckets = 10
+
 // SlidingWindowTpsLimitStrategy implements a thread-safe TPS limit strategy base on requests count.
 /**
  * it's thread-safe.
+ * The window is divided into buckets, the requests are counted in the buckets of the last interval,
+ * so that the memory is constant and the burst at the window boundary of the fixed window is avoided.
+ * The window slides by a bucket, so at most rate/buckets more requests may be allowed in an interval.
  * "UserProvider":
  *   registry: "hangzhouzk"
  *   protocol : "dubbo"
@@ -50,8 +55,14 @@ func init() {
 type SlidingWindowTpsLimitStrategy struct {
 	rate     int
 	interval int64
+	width    int64
 	mutex    *sync.Mutex
-	queue    *list.List
+	buckets  [slidingWindowBuckets]windowBucket
+}
+
+type windowBucket struct {
+	start int64
+	count int
 }
 
 // IsAllowable determines whether the number of requests within the time window overs the threshold
@@ -59,38 +70,39 @@ type SlidingWindowTpsLimitStrategy struct {
 func (impl *SlidingWindowTpsLimitStrategy) IsAllowable() bool {
 	impl.mutex.Lock()
 	defer impl.mutex.Unlock()
-	// quick path
-	size := impl.queue.Len()
 	current := time.Now().UnixNano()
-	if size < impl.rate {
-		impl.queue.PushBack(current)
-		return true
+	start := current - current%impl.width
+	bucket := &impl.buckets[(start/impl.width)%slidingWindowBuckets]
+	if bucket.start != start {
+		// the bucket belongs to a previous window
+		*bucket = windowBucket{start: start}
 	}
 
-	// slow path
-	boundary := current - impl.interval
-
-	timestamp := impl.queue.Front()
-	// remove the element that out of the window
-	for timestamp != nil && timestamp.Value.(int64) < boundary {
-		impl.queue.Remove(timestamp)
-		timestamp = impl.queue.Front()
+	count := 0
+	for _, b := range impl.buckets {
+		if current-b.start < impl.interval {
+			count += b.count
+		}
 	}
-	if impl.queue.Len() < impl.rate {
-		impl.queue.PushBack(current)
-		return true
+	if count >= impl.rate {
+		return false
 	}
-	return false
+	bucket.count++
+	return true
 }
 
 type slidingWindowStrategyCreator struct{}
 
 // Create returns SlidingWindowTpsLimitStrategy instance with configured limit rate and interval
 func (creator *slidingWindowStrategyCreator) Create(rate int, interval int) filter.TpsLimitStrategy {
-	return &SlidingWindowTpsLimitStrategy{
+	strategy := &SlidingWindowTpsLimitStrategy{
 		rate:     rate,
 		interval: int64(interval) * int64(time.Millisecond),
 		mutex:    &sync.Mutex{},
-		queue:    list.New(),
 	}
+	strategy.width = strategy.interval / slidingWindowBuckets
+	if strategy.width <= 0 {
+		strategy.width = 1
+	}
+	return strategy
 }
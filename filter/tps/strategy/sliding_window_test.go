@@ -18,12 +18,19 @@
 package strategy
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
 )
 
 func TestSlidingWindowTpsLimitStrategyImplIsAllowable(t *testing.T) {
@@ -44,3 +51,50 @@ func TestSlidingWindowTpsLimitStrategyImplIsAllowable(t *testing.T) {
 	assert.True(t, strategy.IsAllowable())
 	assert.False(t, strategy.IsAllowable())
 }
+
+func TestSlidingWindowTpsLimitStrategyBoundary(t *testing.T) {
+	creator := &slidingWindowStrategyCreator{}
+	strategy := creator.Create(10, 1000)
+	for i := 0; i < 10; i++ {
+		assert.True(t, strategy.IsAllowable())
+	}
+	assert.False(t, strategy.IsAllowable())
+
+	// the requests of the last interval are still counted at the boundary of the fixed window
+	time.Sleep(600 * time.Millisecond)
+	assert.False(t, strategy.IsAllowable())
+	time.Sleep(600 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		assert.True(t, strategy.IsAllowable())
+	}
+	assert.False(t, strategy.IsAllowable())
+}
+
+// stressAllowed calls IsAllowable of @strategy in @parallelism goroutines for @duration,
+// and returns the count of the allowed calls
+func stressAllowed(strategy filter.TpsLimitStrategy, parallelism int, duration time.Duration) int64 {
+	allowed := atomic.NewInt64(0)
+	deadline := time.Now().Add(duration)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				if strategy.IsAllowable() {
+					allowed.Inc()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return allowed.Load()
+}
+
+func TestSlidingWindowTpsLimitStrategyStress(t *testing.T) {
+	creator := &slidingWindowStrategyCreator{}
+	// 100 requests per 100ms in 1s, the window slides by a bucket so 10 more requests may be allowed
+	allowed := stressAllowed(creator.Create(100, 100), 16, time.Second)
+	assert.LessOrEqual(t, allowed, int64(1000+100))
+	assert.GreaterOrEqual(t, allowed, int64(800))
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// TokenBucketKey defines limiter limit algorithm
+	TokenBucketKey = "tokenBucket"
+)
+
+func init() {
+	extension.SetTpsLimitStrategy(TokenBucketKey, &tokenBucketStrategyCreator{})
+}
+
+// TokenBucketTpsLimitStrategy implements the TPS limit strategy base on a token bucket.
+/**
+ * it's thread-safe.
+ * The bucket is refilled with rate tokens per interval continuously, and holds burst tokens at most,
+ * every request takes one token. So the requests are limited to the rate, and at most burst requests
+ * are allowed at once after the bucket is idle for a while.
+ * "UserProvider":
+ *   registry: "hangzhouzk"
+ *   protocol : "dubbo"
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   tps.limiter: "method-service" # the name of limiter
+ *   tps.limit.strategy: "tokenBucket" # service-level
+ *   tps.limit.burst: 50 # optional, the capacity of the bucket, it's the rate by default
+ *   methods:
+ *    - name: "GetUser"
+ *      tps.interval: 3000
+ *      tps.limit.strategy: "tokenBucket" # method-level
+ */
+type TokenBucketTpsLimitStrategy struct {
+	mutex *sync.Mutex
+	// tokensPerNano is the refill rate of the bucket
+	tokensPerNano float64
+	capacity      float64
+	tokens        float64
+	last          int64
+}
+
+// IsAllowable takes a token from the bucket, it returns false if there is no token left.
+// It is thread-safe.
+func (impl *TokenBucketTpsLimitStrategy) IsAllowable() bool {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+	current := time.Now().UnixNano()
+	if elapsed := current - impl.last; elapsed > 0 {
+		impl.tokens += float64(elapsed) * impl.tokensPerNano
+		if impl.tokens > impl.capacity {
+			impl.tokens = impl.capacity
+		}
+		impl.last = current
+	}
+	if impl.tokens < 1 {
+		return false
+	}
+	impl.tokens--
+	return true
+}
+
+type tokenBucketStrategyCreator struct{}
+
+// Create returns a TokenBucketTpsLimitStrategy instance whose burst is the rate
+func (creator *tokenBucketStrategyCreator) Create(rate int, interval int) filter.TpsLimitStrategy {
+	return creator.CreateWithBurst(rate, interval, rate)
+}
+
+// CreateWithBurst returns a TokenBucketTpsLimitStrategy instance with the rate per interval and the burst,
+// the bucket is full at the beginning
+func (creator *tokenBucketStrategyCreator) CreateWithBurst(rate int, interval int, burst int) filter.TpsLimitStrategy {
+	if burst <= 0 {
+		burst = rate
+	}
+	return &TokenBucketTpsLimitStrategy{
+		mutex:         &sync.Mutex{},
+		tokensPerNano: float64(rate) / float64(int64(interval)*int64(time.Millisecond)),
+		capacity:      float64(burst),
+		tokens:        float64(burst),
+		last:          time.Now().UnixNano(),
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strategy
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+func TestTokenBucketTpsLimitStrategyIsAllowable(t *testing.T) {
+	creator := &tokenBucketStrategyCreator{}
+	strategy := creator.Create(2, 1000)
+	assert.True(t, strategy.IsAllowable())
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+	// a token is refilled every 500ms
+	time.Sleep(600 * time.Millisecond)
+	assert.True(t, strategy.IsAllowable())
+	assert.False(t, strategy.IsAllowable())
+}
+
+func TestTokenBucketTpsLimitStrategyBurst(t *testing.T) {
+	var creator filter.TpsLimitStrategyBurstCreator = &tokenBucketStrategyCreator{}
+	strategy := creator.CreateWithBurst(10, 1000, 3)
+	for i := 0; i < 3; i++ {
+		assert.True(t, strategy.IsAllowable())
+	}
+	assert.False(t, strategy.IsAllowable())
+
+	// the bucket holds burst tokens at most even if it is idle for a long time
+	time.Sleep(1100 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		assert.True(t, strategy.IsAllowable())
+	}
+	assert.False(t, strategy.IsAllowable())
+}
+
+func TestTokenBucketTpsLimitStrategyStress(t *testing.T) {
+	creator := &tokenBucketStrategyCreator{}
+	// the burst of 50 at the beginning, and 100 requests per 100ms in 1s
+	allowed := stressAllowed(creator.CreateWithBurst(100, 100, 50), 16, time.Second)
+	assert.LessOrEqual(t, allowed, int64(50+1000+10))
+	assert.GreaterOrEqual(t, allowed, int64(800))
+}
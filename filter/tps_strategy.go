@@ -48,3 +48,12 @@ type TpsLimitStrategyCreator interface {
 	// which means that the limiter limitation is 100 times per 1000ms (100/1000ms)
 	Create(limit int, interval int) TpsLimitStrategy
 }
+
+// TpsLimitStrategyBurstCreator is implemented by the TpsLimitStrategyCreator whose strategy allows a burst,
+// e.g. the token bucket strategy. The burst is the max requests allowed at once, which is configured by
+// tps.limit.burst.
+type TpsLimitStrategyBurstCreator interface {
+	TpsLimitStrategyCreator
+	// CreateWithBurst will create an instance of TpsLimitStrategy which allows @burst requests at once
+	CreateWithBurst(limit int, interval int, burst int) TpsLimitStrategy
+}
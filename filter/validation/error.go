@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation is a constraint violated by an argument of the invocation
+type Violation struct {
+	// Argument is the index of the argument
+	Argument int `json:"argument"`
+	// Field is the path of the field in the argument, e.g. User.Addresses[0].City
+	Field string `json:"field"`
+	// Constraint is the violated validation tag, e.g. required, and Param is its parameter, e.g. 10 of max=10
+	Constraint string `json:"constraint"`
+	Param      string `json:"param,omitempty"`
+	Message    string `json:"message"`
+}
+
+// ValidationError is the error of the invalid arguments, the consumer with the cvalidation filter gets it by
+// errors.As without parsing the error message
+type ValidationError struct {
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return fmt.Sprintf("invalid arguments: %s", strings.Join(messages, "; "))
+}
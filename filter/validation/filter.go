@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validation provides the filters validating the arguments of the invocations on the provider side, by
+// the validate tags of go-playground/validator or a custom filter.Validator, e.g.
+//
+//	services:
+//	  UserProvider:
+//	    interface: com.ikurento.user.UserProvider
+//	    validation: true # or the name of a custom validator
+//
+// The invalid invocations are rejected with a *ValidationError before reaching the service, and the violations
+// are passed to the consumer by the result attachment validation.violations. The consumer filter cvalidation
+// decodes them into a *ValidationError.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	providerOnce       sync.Once
+	validationProvider *providerFilter
+	consumerOnce       sync.Once
+	validationConsumer *consumerFilter
+)
+
+func init() {
+	extension.SetFilter(constant.ValidationFilterKey, newProviderFilter)
+	extension.SetFilter(constant.ValidationConsumerFilterKey, newConsumerFilter)
+	extension.SetValidator(constant.DefaultValidator, newDefaultValidator())
+}
+
+// providerFilter rejects the invocations whose arguments are invalid
+type providerFilter struct{}
+
+func newProviderFilter() filter.Filter {
+	if validationProvider == nil {
+		providerOnce.Do(func() {
+			validationProvider = &providerFilter{}
+		})
+	}
+	return validationProvider
+}
+
+// Invoke validates the arguments by the validator configured on the method or the service
+func (f *providerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	name := url.GetMethodParam(methodName, constant.ValidationKey, url.GetParam(constant.ValidationKey, ""))
+	if len(name) == 0 || strings.EqualFold(name, "false") {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if strings.EqualFold(name, "true") {
+		name = constant.DefaultValidator
+	}
+	validator, err := extension.GetValidator(name)
+	if err != nil {
+		logger.Warnf("[validation filter] %v, the arguments of the method %s are not validated", err, methodName)
+		return invoker.Invoke(ctx, invocation)
+	}
+	if err = validator.Validate(url, invocation); err != nil {
+		return newValidationResult(err)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse does nothing
+func (f *providerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// newValidationResult returns the result of the rejected invocation, the violations of the *ValidationError are
+// attached in json
+func newValidationResult(err error) protocol.Result {
+	result := &protocol.RPCResult{Err: common.NewRPCError(common.StatusBiz, err)}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		if payload, e := json.Marshal(validationErr.Violations); e == nil {
+			result.AddAttachment(constant.ValidationViolationsKey, string(payload))
+		}
+	}
+	return result
+}
+
+// consumerFilter turns the violations attached by the provider into a *ValidationError
+type consumerFilter struct{}
+
+func newConsumerFilter() filter.Filter {
+	if validationConsumer == nil {
+		consumerOnce.Do(func() {
+			validationConsumer = &consumerFilter{}
+		})
+	}
+	return validationConsumer
+}
+
+// Invoke does nothing
+func (f *consumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse replaces the error of the rejected invocation with a *ValidationError
+func (f *consumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	if result == nil || result.Error() == nil {
+		return result
+	}
+	payload, ok := result.Attachment(constant.ValidationViolationsKey, "").(string)
+	if !ok || len(payload) == 0 {
+		return result
+	}
+	var violations []*Violation
+	if err := json.Unmarshal([]byte(payload), &violations); err != nil {
+		logger.Warnf("[validation filter] decode the violations %s error: %v", payload, err)
+		return result
+	}
+	result.SetError(common.NewRPCError(common.StatusBiz, &ValidationError{Violations: violations}))
+	return result
+}
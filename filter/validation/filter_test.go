@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/hessian2"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type countInvoker struct {
+	*protocol.BaseInvoker
+	invoked int
+}
+
+func (ivk *countInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	ivk.invoked++
+	return &protocol.RPCResult{}
+}
+
+func newCountInvoker(t *testing.T, params string) *countInvoker {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" + params)
+	assert.NoError(t, err)
+	return &countInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+}
+
+func TestProviderFilterInvoke(t *testing.T) {
+	f := newProviderFilter()
+	invalid := invocation.NewRPCInvocation("SetUser", []interface{}{&User{}}, nil)
+
+	invoker := newCountInvoker(t, "methods.SetUser.validation=true")
+	result := f.Invoke(context.Background(), invoker, invalid)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(result.Error(), &validationErr))
+	assert.Equal(t, common.StatusBiz, common.CodeOf(result.Error()))
+	assert.NotEmpty(t, result.Attachment(constant.ValidationViolationsKey, ""))
+	assert.Equal(t, 0, invoker.invoked)
+
+	valid := invocation.NewRPCInvocation("SetUser", []interface{}{&User{Name: "dubbo", Home: Address{City: "hz"}}}, nil)
+	assert.NoError(t, f.Invoke(context.Background(), invoker, valid).Error())
+	assert.Equal(t, 1, invoker.invoked)
+
+	// the methods without validation are not validated
+	invoker = newCountInvoker(t, "validation=true&methods.SetUser.validation=false")
+	assert.NoError(t, f.Invoke(context.Background(), invoker, invalid).Error())
+	invoker = newCountInvoker(t, "validation=unknown")
+	assert.NoError(t, f.Invoke(context.Background(), invoker, invalid).Error())
+}
+
+type idValidator struct{}
+
+func (v *idValidator) Validate(_ *common.URL, inv protocol.Invocation) error {
+	if id, ok := inv.Arguments()[0].(string); !ok || len(id) == 0 {
+		return &ValidationError{Violations: []*Violation{{Field: "id", Constraint: "required", Message: "id is required"}}}
+	}
+	return nil
+}
+
+func TestProviderFilterCustomValidator(t *testing.T) {
+	extension.SetValidator("id", &idValidator{})
+	f := newProviderFilter()
+	invoker := newCountInvoker(t, "validation=id")
+	result := f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{""}, nil))
+	assert.EqualError(t, result.Error(), "invalid arguments: id is required")
+	assert.NoError(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil)).Error())
+	assert.Equal(t, 1, invoker.invoked)
+}
+
+func TestValidationErrorCrossingWire(t *testing.T) {
+	invoker := newCountInvoker(t, "validation=true")
+	inv := invocation.NewRPCInvocation("SetUser", []interface{}{&User{Name: "dubbo", Addresses: []*Address{{}}}}, nil)
+	result := newProviderFilter().Invoke(context.Background(), invoker, inv)
+
+	// the provider encodes the error and the attachments into the response as the dubbo protocol does
+	attachments := map[string]interface{}{hessian2.DUBBO_VERSION_KEY: "2.0.2"}
+	for k, v := range result.Attachments() {
+		attachments[k] = v
+	}
+	payload, err := hessian2.NewHessianCodec(nil).Write(hessian2.Service{},
+		hessian2.DubboHeader{SerialID: 2, Type: hessian2.PackageResponse, ID: 1, ResponseStatus: hessian2.Response_OK},
+		hessian2.NewResponse(nil, result.Error(), attachments))
+	assert.NoError(t, err)
+
+	codec := hessian2.NewHessianCodec(bufio.NewReader(bytes.NewReader(payload)))
+	header := &hessian2.DubboHeader{}
+	assert.NoError(t, codec.ReadHeader(header))
+	response := &hessian2.DubboResponse{}
+	assert.NoError(t, codec.ReadBody(response))
+	assert.Error(t, response.Exception)
+
+	// the consumer gets the violations without parsing the error message
+	received := &protocol.RPCResult{Err: response.Exception, Attrs: response.Attachments}
+	received = newConsumerFilter().OnResponse(context.Background(), received, invoker, inv).(*protocol.RPCResult)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(received.Error(), &validationErr))
+	assert.Len(t, validationErr.Violations, 2)
+	assert.Equal(t, &Violation{
+		Argument:   0,
+		Field:      "User.Home.City",
+		Constraint: "required",
+		Message:    "Key: 'User.Home.City' Error:Field validation for 'City' failed on the 'required' tag",
+	}, validationErr.Violations[0])
+	assert.Equal(t, "User.Addresses[0].City", validationErr.Violations[1].Field)
+
+	// the other errors are kept
+	other := &protocol.RPCResult{Err: errors.New("biz error")}
+	assert.EqualError(t, newConsumerFilter().OnResponse(context.Background(), other, invoker, inv).Error(), "biz error")
+}
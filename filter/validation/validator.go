@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const validateTag = "validate"
+
+// defaultValidator validates the arguments by the validate tags of go-playground/validator, the arguments whose
+// types carry no validate tag are skipped
+type defaultValidator struct {
+	validate *validator.Validate
+	// tagged caches whether a type carries any validate tag
+	tagged sync.Map
+}
+
+func newDefaultValidator() *defaultValidator {
+	return &defaultValidator{validate: validator.New()}
+}
+
+func (v *defaultValidator) Validate(_ *common.URL, invocation protocol.Invocation) error {
+	var violations []*Violation
+	for i, arg := range invocation.Arguments() {
+		value := reflect.ValueOf(arg)
+		if !value.IsValid() || !v.isTagged(value.Type()) {
+			continue
+		}
+		var err error
+		switch reflect.Indirect(value).Kind() {
+		case reflect.Struct:
+			if value.Kind() == reflect.Ptr && value.IsNil() {
+				continue
+			}
+			err = v.validate.Struct(arg)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			err = v.validate.Var(arg, "dive")
+		default:
+			continue
+		}
+		var fieldErrors validator.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			continue
+		}
+		for _, fe := range fieldErrors {
+			violations = append(violations, &Violation{
+				Argument:   i,
+				Field:      fe.Namespace(),
+				Constraint: fe.Tag(),
+				Param:      fe.Param(),
+				Message:    fe.Error(),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+func (v *defaultValidator) isTagged(t reflect.Type) bool {
+	if tagged, ok := v.tagged.Load(t); ok {
+		return tagged.(bool)
+	}
+	tagged := hasValidateTag(t, make(map[reflect.Type]struct{}))
+	v.tagged.Store(t, tagged)
+	return tagged
+}
+
+// hasValidateTag returns whether any field of @t, or of the types it contains, carries a validate tag
+func hasValidateTag(t reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[t]; ok {
+		return false
+	}
+	visited[t] = struct{}{}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasValidateTag(t.Elem(), visited)
+	case reflect.Map:
+		return hasValidateTag(t.Key(), visited) || hasValidateTag(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if _, ok := field.Tag.Lookup(validateTag); ok || hasValidateTag(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
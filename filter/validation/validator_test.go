@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type Address struct {
+	City string `validate:"required"`
+}
+
+type User struct {
+	Name      string `validate:"required,max=10"`
+	Age       int    `validate:"gte=0,lte=150"`
+	Home      Address
+	Addresses []*Address `validate:"dive"`
+}
+
+type plain struct {
+	Name string
+	Tags []string
+}
+
+func TestDefaultValidator(t *testing.T) {
+	v := newDefaultValidator()
+	valid := &User{Name: "dubbo", Age: 10, Home: Address{City: "hz"}, Addresses: []*Address{{City: "bj"}}}
+	assert.NoError(t, v.Validate(nil, invocation.NewRPCInvocation("SetUser", []interface{}{valid, "id", plain{}}, nil)))
+
+	// the violations of the nested structs and the slices of structs are all collected
+	invalid := &User{Name: "dubbo-go-user", Age: 200, Addresses: []*Address{{City: "bj"}, {}}}
+	err := v.Validate(nil, invocation.NewRPCInvocation("SetUser", []interface{}{"id", invalid}, nil))
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, validationErr.Violations, 4)
+	fields := make(map[string]*Violation)
+	for _, violation := range validationErr.Violations {
+		assert.Equal(t, 1, violation.Argument)
+		assert.NotEmpty(t, violation.Message)
+		fields[violation.Field] = violation
+	}
+	assert.Equal(t, "max", fields["User.Name"].Constraint)
+	assert.Equal(t, "10", fields["User.Name"].Param)
+	assert.Equal(t, "lte", fields["User.Age"].Constraint)
+	assert.Equal(t, "required", fields["User.Home.City"].Constraint)
+	assert.Equal(t, "required", fields["User.Addresses[1].City"].Constraint)
+
+	// the slices of structs in the arguments
+	err = v.Validate(nil, invocation.NewRPCInvocation("SetAddresses", []interface{}{[]Address{{City: "hz"}, {}}}, nil))
+	validationErr, ok = err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, validationErr.Violations, 1)
+	assert.Equal(t, "required", validationErr.Violations[0].Constraint)
+
+	// nil arguments are skipped
+	var nilUser *User
+	assert.NoError(t, v.Validate(nil, invocation.NewRPCInvocation("SetUser", []interface{}{nilUser, nil}, nil)))
+}
+
+func TestHasValidateTag(t *testing.T) {
+	v := newDefaultValidator()
+	assert.True(t, v.isTagged(reflect.TypeOf(&User{})))
+	assert.True(t, v.isTagged(reflect.TypeOf([]Address{})))
+	assert.True(t, v.isTagged(reflect.TypeOf(map[string]*User{})))
+	assert.False(t, v.isTagged(reflect.TypeOf(plain{})))
+	assert.False(t, v.isTagged(reflect.TypeOf("")))
+
+	type node struct {
+		Next *node
+	}
+	assert.False(t, v.isTagged(reflect.TypeOf(node{})))
+	tagged, ok := v.tagged.Load(reflect.TypeOf(node{}))
+	assert.True(t, ok)
+	assert.False(t, tagged.(bool))
+}
@@ -0,0 +1,28 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package generic calls the services by the names of their interfaces and methods without their Go types, e.g. in
+// a gateway, by the generic references sharing the registries and the consumer defaults of the bootstrap:
+//
+//	svc, err := generic.NewGenericService("org.apache.dubbo.UserProvider", generic.WithVersion("1.0.0"))
+//	if err != nil {
+//		return err
+//	}
+//	defer svc.Destroy()
+//	user, err := svc.InvokeJSON(ctx, "getUser", `["A003"]`)
+//
+// The timeout and the attachments of a call are carried by its context, see WithAttachments.
+package generic
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package generic_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/generic"
+	_ "dubbo.apache.org/dubbo-go/v3/imports"
+)
+
+// The gateway refers the services by their names after loading the config of its registries, and forwards the json
+// bodies of the requests as the arguments.
+func Example() {
+	if err := config.Load(); err != nil {
+		panic(err)
+	}
+	svc, err := generic.NewGenericService("org.apache.dubbo.samples.UserProvider",
+		generic.WithVersion("1.0.0"), generic.WithRegistryIDs("zk"))
+	if err != nil {
+		panic(err)
+	}
+	defer svc.Destroy()
+
+	user, err := svc.InvokeJSON(context.Background(), "GetUser", `["A003"]`)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(user)
+}
+
+// The types of the arguments are passed if they can't be inferred, e.g. a primitive int of java.
+func ExampleGenericService_Invoke() {
+	svc, err := generic.NewGenericService("org.apache.dubbo.samples.UserProvider")
+	if err != nil {
+		panic(err)
+	}
+	defer svc.Destroy()
+
+	users, err := svc.Invoke(context.Background(), "QueryUsers", []string{"java.lang.String", "int"},
+		[]interface{}{"Alex", int32(10)})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(users)
+}
+
+// The timeout and the attachments of a call are carried by its context, the timeout shorter than the timeout of the
+// reference takes effect.
+func ExampleWithAttachments() {
+	svc, err := generic.NewGenericService("org.apache.dubbo.samples.UserProvider")
+	if err != nil {
+		panic(err)
+	}
+	defer svc.Destroy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	ctx = generic.WithAttachments(ctx, map[string]interface{}{"tenant": "gateway"})
+	user, err := svc.InvokeJSON(ctx, "GetUser", `"A003"`)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(user)
+}
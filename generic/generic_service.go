@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package generic
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	config_generic "dubbo.apache.org/dubbo-go/v3/config/generic"
+	"dubbo.apache.org/dubbo-go/v3/filter/generic/generalizer"
+)
+
+// GenericService calls the methods of a service by the generic reference of its interface
+type GenericService struct {
+	interfaceName string
+	generic       string
+	reference     *config.ReferenceConfig
+	service       *config_generic.GenericService
+}
+
+// NewGenericService refers the service of @interfaceName by a generic reference after the bootstrap, which shares
+// the registries and the consumer defaults of the root config, it should be destroyed by Destroy at last
+func NewGenericService(interfaceName string, opts ...Option) (*GenericService, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	builder := config.NewReferenceConfigBuilder().
+		SetInterface(interfaceName).
+		SetGroup(o.group).
+		SetVersion(o.version).
+		SetRegistryIDs(o.registryIDs...).
+		SetProtocol(o.protocol).
+		SetURL(o.url).
+		SetCluster(o.cluster).
+		SetLoadbalance(o.loadbalance).
+		SetRetries(o.retries)
+	if o.check != nil {
+		builder.SetCheck(*o.check)
+	}
+	rc := builder.Build()
+	rc.Generic = o.generic
+	rc, err := config.NewReferenceConfigByAPI(rc)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "generic reference of %s", interfaceName)
+	}
+	service := config_generic.NewGenericService(interfaceName)
+	if err = refer(rc, service); err != nil {
+		return nil, err
+	}
+	return &GenericService{interfaceName: interfaceName, generic: o.generic, reference: rc, service: service}, nil
+}
+
+// refer refers the generic @service by @rc, the panics on referring, e.g. of the invalid url, are returned as the error
+func refer(rc *config.ReferenceConfig, service *config_generic.GenericService) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = perrors.Errorf("generic reference of %s failed, %v", rc.InterfaceName, e)
+		}
+	}()
+	rc.Refer(service)
+	rc.Implement(service)
+	return nil
+}
+
+// Reference returns the name of the interface of the service
+func (s *GenericService) Reference() string {
+	return s.interfaceName
+}
+
+// Invoke calls the @method with @args of @argTypes, the java names of the types of the arguments. The types are
+// inferred from the arguments if @argTypes is nil, see javaTypeOf, and the hessian.POJO arguments are passed as the
+// maps of their fields with their classes. The timeout and the attachments of the call are carried by @ctx.
+func (s *GenericService) Invoke(ctx context.Context, method string, argTypes []string, args []interface{}) (interface{}, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if argTypes == nil {
+		types, err := typesOf(args)
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "invoke %s.%s", s.interfaceName, method)
+		}
+		argTypes = types
+	}
+	if len(argTypes) != len(args) {
+		return nil, perrors.Errorf("invoke %s.%s: %d types of %d arguments", s.interfaceName, method,
+			len(argTypes), len(args))
+	}
+	objects := make([]hessian.Object, 0, len(args))
+	for _, arg := range args {
+		if pojo, ok := arg.(hessian.POJO); ok && strings.ToLower(s.generic) == constant.GenericSerializationDefault {
+			object, err := generalizer.GetMapGeneralizer().Generalize(pojo)
+			if err != nil {
+				return nil, perrors.WithMessagef(err, "invoke %s.%s", s.interfaceName, method)
+			}
+			arg = object
+		}
+		objects = append(objects, arg)
+	}
+	return s.service.Invoke(ctx, method, argTypes, objects)
+}
+
+// InvokeJSON calls the @method with the arguments of @jsonArgs, a json array of the arguments or a single argument,
+// the types of which are inferred, e.g. `["A003", 18, {"class": "org.apache.dubbo.User", "name": "Alex"}]` are the
+// arguments of java.lang.String, java.lang.Long and org.apache.dubbo.User, see parseJSONArgs.
+func (s *GenericService) InvokeJSON(ctx context.Context, method, jsonArgs string) (interface{}, error) {
+	args, err := parseJSONArgs(jsonArgs)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "invoke %s.%s", s.interfaceName, method)
+	}
+	return s.Invoke(ctx, method, nil, args)
+}
+
+// Destroy destroys the generic reference of the service
+func (s *GenericService) Destroy() {
+	s.reference.Destroy()
+}
+
+// WithAttachments returns a copy of @ctx carrying @attachments to the calls, over the attachments carried by @ctx
+func WithAttachments(ctx context.Context, attachments map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(attachments))
+	switch carried := ctx.Value(constant.AttachmentKey).(type) {
+	case map[string]string:
+		for k, v := range carried {
+			merged[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range carried {
+			merged[k] = v
+		}
+	}
+	for k, v := range attachments {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, constant.AttachmentKey, merged)
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package generic
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	config_generic "dubbo.apache.org/dubbo-go/v3/config/generic"
+)
+
+type User struct {
+	Name string
+	Age  int32
+}
+
+func (u *User) JavaClassName() string {
+	return "org.apache.dubbo.User"
+}
+
+func TestJavaTypeOf(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		arg interface{}
+		typ string
+	}{
+		{nil, "java.lang.Object"},
+		{"A003", "java.lang.String"},
+		{true, "java.lang.Boolean"},
+		{int8(1), "java.lang.Byte"},
+		{int16(1), "java.lang.Short"},
+		{int32(1), "java.lang.Integer"},
+		{1, "java.lang.Long"},
+		{int64(1), "java.lang.Long"},
+		{float32(1), "java.lang.Float"},
+		{1.5, "java.lang.Double"},
+		{[]byte("a"), "[B"},
+		{now, "java.util.Date"},
+		{&now, "java.util.Date"},
+		{&User{}, "org.apache.dubbo.User"},
+		{map[string]interface{}{"class": "org.apache.dubbo.User", "name": "Alex"}, "org.apache.dubbo.User"},
+		{map[string]interface{}{"name": "Alex"}, "java.util.Map"},
+		{map[string]string{"name": "Alex"}, "java.util.Map"},
+		{[]interface{}{"a", 1}, "java.util.List"},
+		{[]string{"a"}, "java.util.List"},
+		{[2]int{1, 2}, "java.util.List"},
+		{new(string), "java.lang.String"},
+	}
+	for _, test := range tests {
+		typ, err := javaTypeOf(test.arg)
+		require.NoError(t, err, "%T", test.arg)
+		assert.Equal(t, test.typ, typ, "%T", test.arg)
+	}
+
+	_, err := javaTypeOf(struct{}{})
+	assert.Error(t, err)
+	_, err = typesOf([]interface{}{"a", struct{}{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "argument 1")
+}
+
+func TestParseJSONArgs(t *testing.T) {
+	args, err := parseJSONArgs(`["A003", 18, 1.5, true, null, {"class": "org.apache.dubbo.User", "age": 18}, [1, "a"]]`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		"A003", int64(18), 1.5, true, nil,
+		map[string]interface{}{"class": "org.apache.dubbo.User", "age": int64(18)},
+		[]interface{}{int64(1), "a"},
+	}, args)
+	types, err := typesOf(args)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"java.lang.String", "java.lang.Long", "java.lang.Double", "java.lang.Boolean", "java.lang.Object",
+		"org.apache.dubbo.User", "java.util.List",
+	}, types)
+
+	args, err = parseJSONArgs(`"A003"`)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"A003"}, args)
+
+	_, err = parseJSONArgs(`["A003"`)
+	assert.Error(t, err)
+	_, err = parseJSONArgs(`"A003" "A004"`)
+	assert.Error(t, err)
+}
+
+func TestInvoke(t *testing.T) {
+	var (
+		invokedCtx    context.Context
+		invokedMethod string
+		invokedTypes  []string
+		invokedArgs   []hessian.Object
+	)
+	service := config_generic.NewGenericService("org.apache.dubbo.UserProvider")
+	service.Invoke = func(ctx context.Context, methodName string, types []string, args []hessian.Object) (interface{}, error) {
+		invokedCtx, invokedMethod, invokedTypes, invokedArgs = ctx, methodName, types, args
+		return "ok", nil
+	}
+	svc := &GenericService{
+		interfaceName: "org.apache.dubbo.UserProvider",
+		generic:       constant.GenericSerializationDefault,
+		service:       service,
+	}
+	assert.Equal(t, "org.apache.dubbo.UserProvider", svc.Reference())
+
+	t.Run("types passed", func(t *testing.T) {
+		res, err := svc.Invoke(context.Background(), "getUser", []string{"java.lang.Integer"}, []interface{}{int64(1)})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", res)
+		assert.Equal(t, "getUser", invokedMethod)
+		assert.Equal(t, []string{"java.lang.Integer"}, invokedTypes)
+		assert.Equal(t, []hessian.Object{int64(1)}, invokedArgs)
+	})
+
+	t.Run("types inferred", func(t *testing.T) {
+		_, err := svc.Invoke(context.Background(), "addUser", nil, []interface{}{&User{Name: "Alex", Age: 18}, "admin"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"org.apache.dubbo.User", "java.lang.String"}, invokedTypes)
+		assert.Equal(t, []hessian.Object{
+			map[string]interface{}{"class": "org.apache.dubbo.User", "name": "Alex", "age": int32(18)}, "admin",
+		}, invokedArgs)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		ctx = WithAttachments(ctx, map[string]interface{}{"tenant": "a"})
+		_, err := svc.InvokeJSON(ctx, "queryUsers", `[{"name": "Alex"}, 10]`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"java.util.Map", "java.lang.Long"}, invokedTypes)
+		_, ok := invokedCtx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"tenant": "a"}, invokedCtx.Value(constant.AttachmentKey))
+
+		_, err = svc.InvokeJSON(ctx, "queryUsers", `[`)
+		assert.Error(t, err)
+	})
+
+	t.Run("types mismatched", func(t *testing.T) {
+		_, err := svc.Invoke(context.Background(), "getUser", []string{}, []interface{}{"A003"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "0 types of 1 arguments")
+	})
+}
+
+func TestWithAttachments(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constant.AttachmentKey, map[string]string{"a": "1", "b": "1"})
+	ctx = WithAttachments(ctx, map[string]interface{}{"b": "2", "c": 3})
+	assert.Equal(t, map[string]interface{}{"a": "1", "b": "2", "c": 3}, ctx.Value(constant.AttachmentKey))
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package generic
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type options struct {
+	generic     string
+	group       string
+	version     string
+	registryIDs []string
+	protocol    string
+	url         string
+	cluster     string
+	loadbalance string
+	retries     string
+	check       *bool
+}
+
+func defaultOptions() *options {
+	return &options{generic: constant.GenericSerializationDefault}
+}
+
+// Option configures the reference of a GenericService
+type Option func(*options)
+
+// WithGeneric sets the serialization of the generic calls, which is true by default, the arguments and the results
+// are the maps of the objects, or gson, which are the json of the objects, the types of the arguments should be
+// passed to the calls with gson
+func WithGeneric(generic string) Option {
+	return func(o *options) {
+		o.generic = generic
+	}
+}
+
+func WithGroup(group string) Option {
+	return func(o *options) {
+		o.group = group
+	}
+}
+
+func WithVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithRegistryIDs sets the registries of the reference, which are the registries of the consumer by default
+func WithRegistryIDs(registryIDs ...string) Option {
+	return func(o *options) {
+		o.registryIDs = registryIDs
+	}
+}
+
+// WithProtocol sets the protocol of the reference, which is the protocol of the consumer by default
+func WithProtocol(protocol string) Option {
+	return func(o *options) {
+		o.protocol = protocol
+	}
+}
+
+// WithURL calls the provider of @url directly instead of the providers in the registries
+func WithURL(url string) Option {
+	return func(o *options) {
+		o.url = url
+	}
+}
+
+func WithCluster(cluster string) Option {
+	return func(o *options) {
+		o.cluster = cluster
+	}
+}
+
+func WithLoadbalance(loadbalance string) Option {
+	return func(o *options) {
+		o.loadbalance = loadbalance
+	}
+}
+
+func WithRetries(retries string) Option {
+	return func(o *options) {
+		o.retries = retries
+	}
+}
+
+// WithCheck sets whether the reference fails without the available providers, which is the check of the consumer
+// by default
+func WithCheck(check bool) Option {
+	return func(o *options) {
+		o.check = &check
+	}
+}
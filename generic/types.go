@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+// classKey is the key of the java class of an object passed as a map, see generalizer.MapGeneralizer
+const classKey = "class"
+
+// typesOf returns the java types of @args inferred by javaTypeOf
+func typesOf(args []interface{}) ([]string, error) {
+	types := make([]string, 0, len(args))
+	for i, arg := range args {
+		typ, err := javaTypeOf(arg)
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "argument %d", i)
+		}
+		types = append(types, typ)
+	}
+	return types, nil
+}
+
+// javaTypeOf infers the java type of @arg: the boxed types of the numbers, strings and booleans, byte[] of []byte,
+// java.util.Date of time.Time, the class of a hessian.POJO or a map with the class, java.util.Map of the other maps
+// and java.util.List of the slices. The other types can't be inferred, whose types should be passed to the call.
+func javaTypeOf(arg interface{}) (string, error) {
+	switch v := arg.(type) {
+	case nil:
+		return "java.lang.Object", nil
+	case string:
+		return "java.lang.String", nil
+	case bool:
+		return "java.lang.Boolean", nil
+	case int8:
+		return "java.lang.Byte", nil
+	case int16:
+		return "java.lang.Short", nil
+	case int32:
+		return "java.lang.Integer", nil
+	case int, int64:
+		return "java.lang.Long", nil
+	case float32:
+		return "java.lang.Float", nil
+	case float64:
+		return "java.lang.Double", nil
+	case []byte:
+		return "[B", nil
+	case time.Time, *time.Time:
+		return "java.util.Date", nil
+	case hessian.POJO:
+		return v.JavaClassName(), nil
+	case map[string]interface{}:
+		if class, ok := v[classKey].(string); ok && class != "" {
+			return class, nil
+		}
+		return "java.util.Map", nil
+	}
+	switch value := reflect.ValueOf(arg); value.Kind() {
+	case reflect.Map:
+		return "java.util.Map", nil
+	case reflect.Slice, reflect.Array:
+		return "java.util.List", nil
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return javaTypeOf(value.Elem().Interface())
+		}
+		return "java.lang.Object", nil
+	}
+	return "", perrors.Errorf("the java type of %T can't be inferred, pass the types of the arguments", arg)
+}
+
+// parseJSONArgs parses the arguments of @jsonArgs, a json array of the arguments or a single argument, the integers
+// are parsed as int64 and the other numbers as float64, so that they are inferred as java.lang.Long and
+// java.lang.Double
+func parseJSONArgs(jsonArgs string) ([]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(jsonArgs))
+	decoder.UseNumber()
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, perrors.WithMessage(err, "parse the json arguments")
+	}
+	if decoder.More() {
+		return nil, perrors.Errorf("parse the json arguments: %s is not a single json value", jsonArgs)
+	}
+	args, ok := parsed.([]interface{})
+	if !ok {
+		args = []interface{}{parsed}
+	}
+	for i, arg := range args {
+		args[i] = fromJSON(arg)
+	}
+	return args, nil
+}
+
+// fromJSON converts the json.Number values of @value to int64 or float64
+func fromJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i, e := range v {
+			v[i] = fromJSON(e)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = fromJSON(e)
+		}
+	}
+	return value
+}
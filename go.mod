@@ -8,6 +8,7 @@ require (
 	github.com/Workiva/go-datastructures v1.0.52
 	github.com/afex/hystrix-go v0.0.0-20180502004556-fa1af6a1f4f5
 	github.com/alibaba/sentinel-golang v1.0.4
+	github.com/alicebob/miniredis/v2 v2.23.0
 	github.com/apache/dubbo-getty v1.4.9
 	github.com/apache/dubbo-go-hessian2 v1.12.2
 	github.com/cespare/xxhash/v2 v2.2.0
@@ -26,6 +27,7 @@ require (
 	github.com/go-co-op/gocron v1.9.0
 	github.com/go-logr/logr v1.2.4 // indirect
 	github.com/go-playground/validator/v10 v10.12.0
+	github.com/go-redis/redis/v8 v8.11.5
 	github.com/go-resty/resty/v2 v2.7.0
 	github.com/golang/glog v1.1.0 // indirect
 	github.com/golang/mock v1.6.0
@@ -37,7 +39,6 @@ require (
 	github.com/hashicorp/golang-lru v0.5.4
 	github.com/hashicorp/vault/sdk v0.7.0
 	github.com/influxdata/tdigest v0.0.1
-	github.com/jinzhu/copier v0.3.5
 	github.com/knadh/koanf v1.5.0
 	github.com/magiconair/properties v1.8.1
 	github.com/mattn/go-colorable v0.1.13
@@ -0,0 +1,358 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package imports_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	_ "dubbo.apache.org/dubbo-go/v3/imports"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+type GreeterProvider struct{}
+
+func (g *GreeterProvider) SayHello(ctx context.Context, name string) (string, error) {
+	return "hello " + name, nil
+}
+
+func (g *GreeterProvider) SayHelloSlowly(ctx context.Context, name string) (string, error) {
+	time.Sleep(time.Second)
+	return "hello " + name, nil
+}
+
+type GreeterConsumer struct {
+	SayHello       func(ctx context.Context, name string) (string, error)
+	SayHelloSlowly func(ctx context.Context, name string) (string, error)
+}
+
+type UnreachableGreeterConsumer struct {
+	SayHello func(ctx context.Context, name string) (string, error)
+}
+
+// TestLoadWithoutConfigFiles brings up a provider and a consumer of it by the config api only, and shuts them down
+// gracefully with the requests in flight. The references are lazy, the unreachable one costs nothing at startup.
+// The services and the references added and dropped at runtime leak neither goroutines nor connections, and the
+// reference with check=false starting before its providers works once they are registered. A service exported over
+// several protocols is called through each of them. The lifecycle listeners and the event bus are notified from the
+// start to the end, and nothing the framework started is left after the shutdown.
+func TestLoadWithoutConfigFiles(t *testing.T) {
+	defer diagnostics.Enable()()
+	port := freePort(t)
+	application := config.NewApplicationConfigBuilder().SetName("greeter").Build()
+	memory := config.NewRegistryConfigBuilder().
+		SetProtocol("memory").
+		SetAddress("127.0.0.1:0").
+		SetRegistryType(constant.RegistryTypeInterface).
+		Build()
+
+	config.SetProviderService(&GreeterProvider{})
+	pc := config.NewProviderConfigBuilder().
+		SetApplication(application).
+		AddRegistry("memory", memory).
+		AddProtocol("dubbo", config.NewProtocolConfigBuilder().SetName("dubbo").SetPort(port).Build()).
+		AddService("GreeterProvider", config.NewServiceConfigBuilder().
+			SetInterface("org.apache.dubbo.demo.Greeter").
+			SetProtocolIDs("dubbo").
+			Build()).
+		Build()
+
+	consumer := &GreeterConsumer{}
+	config.SetConsumerService(consumer)
+	cc := config.NewConsumerConfigBuilder().
+		SetApplication(application).
+		AddRegistry("memory", memory).
+		AddReference("GreeterConsumer", config.NewReferenceConfigBuilder().
+			SetInterface("org.apache.dubbo.demo.Greeter").
+			SetProtocol("dubbo").
+			SetURL("dubbo://127.0.0.1:"+port).
+			SetLazy(true).
+			Build()).
+		AddReference("UnreachableGreeterConsumer", config.NewReferenceConfigBuilder().
+			SetInterface("org.apache.dubbo.demo.Greeter").
+			SetGroup("unreachable").
+			SetProtocol("dubbo").
+			SetURL("dubbo://127.0.0.1:"+freePort(t)).
+			SetLazy(true).
+			Build()).
+		Build()
+	config.SetConsumerService(&UnreachableGreeterConsumer{})
+
+	var (
+		eventsLock sync.Mutex
+		events     []extension.LifecycleEvent
+	)
+	lifecycleEvents := func() []extension.LifecycleEvent {
+		eventsLock.Lock()
+		defer eventsLock.Unlock()
+		return append([]extension.LifecycleEvent(nil), events...)
+	}
+	config.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		eventsLock.Lock()
+		defer eventsLock.Unlock()
+		events = append(events, event)
+	}))
+
+	var busEvents []string
+	takeBusEvents := func() []string {
+		eventsLock.Lock()
+		defer eventsLock.Unlock()
+		taken := busEvents
+		busEvents = nil
+		return taken
+	}
+	unsubscribe := eventbus.Subscribe(func(event eventbus.Event) {
+		var subject string
+		switch e := event.(type) {
+		case eventbus.ServiceExported:
+			subject = e.ServiceKey
+		case eventbus.ServiceUnexported:
+			subject = e.ServiceKey
+		case eventbus.ReferenceReady:
+			subject = e.ServiceKey
+		case eventbus.ReferenceDestroyed:
+			subject = e.ServiceKey
+		case eventbus.RegistryConnected:
+			subject = e.URL.Protocol
+		}
+		eventsLock.Lock()
+		defer eventsLock.Unlock()
+		busEvents = append(busEvents, strings.TrimSpace(string(event.Type())+" "+subject))
+	})
+	defer unsubscribe()
+
+	start := time.Now()
+	assert.Nil(t, config.Load(config.WithProviderConfig(pc), config.WithConsumerConfig(cc)))
+	assert.Equal(t, []extension.LifecycleEvent{extension.LifecycleConfigLoaded, extension.LifecycleServicesExported,
+		extension.LifecycleReferencesReady}, lifecycleEvents())
+	// the references are referred in any order
+	started := takeBusEvents()
+	assert.Len(t, started, 5)
+	assert.Equal(t, []string{"registry-connected memory", "service-exported org.apache.dubbo.demo.Greeter"}, started[:2])
+	assert.ElementsMatch(t, []string{"reference-ready org.apache.dubbo.demo.Greeter",
+		"reference-ready unreachable/org.apache.dubbo.demo.Greeter"}, started[2:4])
+	assert.Equal(t, "service-exported greeter/org.apache.dubbo.metadata.MetadataService:1.0.0", started[4])
+	// connecting the unreachable provider costs 3s at least if it's not lazy
+	startup := time.Since(start)
+	t.Logf("started in %v", startup)
+	assert.True(t, startup < 3*time.Second)
+	// the lazy reference never invoked is destroyed as well
+	config.GetConsumerConfig().References["UnreachableGreeterConsumer"].Destroy()
+	assert.Equal(t, []string{"reference-destroyed unreachable/org.apache.dubbo.demo.Greeter"}, takeBusEvents())
+
+	reply, err := consumer.SayHello(context.Background(), "dubbo")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello dubbo", reply)
+	// the defaults are the same as the ones of the config files
+	assert.Equal(t, "3s", config.GetConsumerConfig().RequestTimeout)
+	assert.Equal(t, "default", config.GetProviderConfig().ProxyFactory)
+
+	t.Run("runtime services and references", func(t *testing.T) {
+		const interfaceName = "org.apache.dubbo.demo.RuntimeGreeter"
+		runtimePort := freePort(t)
+		protocolConfig := config.NewProtocolConfigBuilder().SetName("dubbo").SetPort(runtimePort).Build()
+		cycle := func() {
+			svc := config.NewServiceConfigBuilder().
+				SetInterface(interfaceName).
+				SetProtocolIDs("runtime").
+				AddRCProtocol("runtime", protocolConfig).
+				SetRPCService(&GreeterProvider{}).
+				Build()
+			assert.Nil(t, svc.Init(config.GetRootConfig()))
+			assert.Nil(t, svc.Export())
+			defer svc.Unexport()
+
+			rc, err := config.NewReferenceConfigByAPI(config.NewReferenceConfigBuilder().
+				SetInterface(interfaceName).
+				SetProtocol("dubbo").
+				SetURL("dubbo://127.0.0.1:" + runtimePort).
+				Build())
+			assert.Nil(t, err)
+			runtimeConsumer := &GreeterConsumer{}
+			rc.Refer(runtimeConsumer)
+			rc.Implement(runtimeConsumer)
+			reply, err := runtimeConsumer.SayHello(context.Background(), "runtime")
+			assert.Nil(t, err)
+			assert.Equal(t, "hello runtime", reply)
+
+			rc.Destroy()
+			assert.Nil(t, config.GetConsumerServiceByInterfaceName(interfaceName))
+			_, err = runtimeConsumer.SayHello(context.Background(), "runtime")
+			assert.NotNil(t, err)
+		}
+
+		// the first cycle starts the server of the port, which lives as long as the protocol
+		cycle()
+		baseline := runtime.NumGoroutine()
+		for i := 0; i < 10; i++ {
+			cycle()
+		}
+		// every connection holds the goroutines of its sessions, which are closed asynchronously
+		assert.Eventually(t, func() bool {
+			return runtime.NumGoroutine() <= baseline
+		}, 5*time.Second, 50*time.Millisecond, "the goroutines or the connections leaked")
+	})
+
+	t.Run("providers registered after the consumer", func(t *testing.T) {
+		const interfaceName = "org.apache.dubbo.demo.LateGreeter"
+		rc, err := config.NewReferenceConfigByAPI(config.NewReferenceConfigBuilder().
+			SetInterface(interfaceName).
+			SetProtocol("dubbo").
+			SetRegistryIDs("memory").
+			SetCheck(false).
+			Build())
+		assert.Nil(t, err)
+		lateConsumer := &GreeterConsumer{}
+		rc.Refer(lateConsumer)
+		rc.Implement(lateConsumer)
+		defer rc.Destroy()
+
+		// the calls without any provider fail fast
+		start := time.Now()
+		_, err = lateConsumer.SayHello(context.Background(), "late")
+		assert.True(t, errors.Is(err, protocol.ErrNoProvider), "%v", err)
+		assert.True(t, time.Since(start) < time.Second)
+
+		svc := config.NewServiceConfigBuilder().
+			SetInterface(interfaceName).
+			SetRegistryIDs("memory").
+			SetProtocolIDs("late").
+			AddRCProtocol("late", config.NewProtocolConfigBuilder().SetName("dubbo").SetPort(freePort(t)).Build()).
+			SetRPCService(&GreeterProvider{}).
+			Build()
+		assert.Nil(t, svc.Init(config.GetRootConfig()))
+		assert.Nil(t, svc.Export())
+		defer svc.Unexport()
+
+		// the reference works once the providers are notified, without any re-initialization
+		assert.Eventually(t, func() bool {
+			reply, err := lateConsumer.SayHello(context.Background(), "late")
+			return err == nil && reply == "hello late"
+		}, 3*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("multiple protocols", func(t *testing.T) {
+		const interfaceName = "org.apache.dubbo.demo.MultiProtocolGreeter"
+		svc := config.NewServiceConfigBuilder().
+			SetInterface(interfaceName).
+			SetRegistryIDs("memory").
+			SetProtocolIDs("dubbo", "tri").
+			AddRCProtocol("dubbo", config.NewProtocolConfigBuilder().SetName("dubbo").SetPort(freePort(t)).Build()).
+			AddRCProtocol("tri", config.NewProtocolConfigBuilder().
+				SetName("tri").
+				SetPort(freePort(t)).
+				SetSerialization(constant.Hessian2Serialization).
+				Build()).
+			SetRPCService(&GreeterProvider{}).
+			Build()
+		assert.Nil(t, svc.Init(config.GetRootConfig()))
+		assert.Nil(t, svc.Export())
+
+		// the consumers pick the providers of their protocols
+		for _, name := range []string{"dubbo", "tri"} {
+			rc, err := config.NewReferenceConfigByAPI(config.NewReferenceConfigBuilder().
+				SetInterface(interfaceName).
+				SetProtocol(name).
+				SetSerialization(constant.Hessian2Serialization).
+				SetRegistryIDs("memory").
+				Build())
+			assert.Nil(t, err)
+			multiConsumer := &GreeterConsumer{}
+			rc.Refer(multiConsumer)
+			rc.Implement(multiConsumer)
+			assert.Eventually(t, func() bool {
+				reply, err := multiConsumer.SayHello(context.Background(), name)
+				return err == nil && reply == "hello "+name
+			}, 3*time.Second, 50*time.Millisecond, name)
+			rc.Destroy()
+		}
+
+		// all of the protocols are torn down at unexport
+		svc.Unexport()
+		rc, err := config.NewReferenceConfigByAPI(config.NewReferenceConfigBuilder().
+			SetInterface(interfaceName).
+			SetProtocol("tri").
+			SetSerialization(constant.Hessian2Serialization).
+			SetRegistryIDs("memory").
+			SetCheck(false).
+			Build())
+		assert.Nil(t, err)
+		multiConsumer := &GreeterConsumer{}
+		rc.Refer(multiConsumer)
+		rc.Implement(multiConsumer)
+		defer rc.Destroy()
+		_, err = multiConsumer.SayHello(context.Background(), "tri")
+		assert.True(t, errors.Is(err, protocol.ErrNoProvider), "%v", err)
+	})
+
+	t.Run("graceful shutdown", func(t *testing.T) {
+		const requests = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, requests)
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := consumer.SayHelloSlowly(context.Background(), "dubbo")
+				errs <- err
+			}()
+		}
+		assert.Eventually(t, func() bool {
+			return config.GetShutDown().ProviderActiveCount.Load() == requests
+		}, time.Second, 10*time.Millisecond)
+
+		takeBusEvents()
+		config.GracefulShutdown()
+		assert.Equal(t, []extension.LifecycleEvent{extension.LifecycleBeforeShutdown, extension.LifecycleAfterShutdown},
+			lifecycleEvents()[3:])
+		assert.Equal(t, []string{"shutdown-initiated", "service-unexported org.apache.dubbo.demo.Greeter",
+			"reference-destroyed org.apache.dubbo.demo.Greeter"}, takeBusEvents())
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.Nil(t, err)
+		}
+		assert.True(t, config.GetShutDown().RejectRequest.Load())
+		assert.Zero(t, config.GetShutDown().ProviderActiveCount.Load())
+		assert.NoError(t, config.VerifyShutdown())
+	})
+}
+
+func freePort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
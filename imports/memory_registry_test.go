@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imports_test
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func init() {
+	memory := &memoryRegistry{
+		providers: make(map[string][]*common.URL),
+		listeners: make(map[string][]registry.NotifyListener),
+	}
+	extension.SetRegistry("memory", func(*common.URL) (registry.Registry, error) {
+		return memory, nil
+	})
+}
+
+// memoryRegistry is the registry in the memory of the process, the providers registered are notified to the consumers
+// subscribing them at once
+type memoryRegistry struct {
+	lock      sync.Mutex
+	providers map[string][]*common.URL             // service key -> providers
+	listeners map[string][]registry.NotifyListener // service key -> consumers
+}
+
+func (r *memoryRegistry) GetURL() *common.URL {
+	return common.NewURLWithOptions(common.WithProtocol("memory"))
+}
+
+func (r *memoryRegistry) IsAvailable() bool {
+	return true
+}
+
+func (r *memoryRegistry) Destroy() {}
+
+func (r *memoryRegistry) Register(url *common.URL) error {
+	if url.GetParam(constant.SideKey, "") != constant.ProviderProtocol {
+		return nil
+	}
+	r.lock.Lock()
+	r.providers[url.ServiceKey()] = append(r.providers[url.ServiceKey()], url)
+	listeners := r.listeners[url.ServiceKey()]
+	r.lock.Unlock()
+	for _, l := range listeners {
+		l.Notify(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: url})
+	}
+	return nil
+}
+
+func (r *memoryRegistry) UnRegister(url *common.URL) error {
+	r.lock.Lock()
+	providers := r.providers[url.ServiceKey()]
+	for i, p := range providers {
+		if p.String() == url.String() {
+			r.providers[url.ServiceKey()] = append(providers[:i:i], providers[i+1:]...)
+			break
+		}
+	}
+	listeners := r.listeners[url.ServiceKey()]
+	r.lock.Unlock()
+	for _, l := range listeners {
+		l.Notify(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: url})
+	}
+	return nil
+}
+
+func (r *memoryRegistry) Subscribe(url *common.URL, listener registry.NotifyListener) error {
+	r.lock.Lock()
+	r.listeners[url.ServiceKey()] = append(r.listeners[url.ServiceKey()], listener)
+	providers := append([]*common.URL(nil), r.providers[url.ServiceKey()]...)
+	r.lock.Unlock()
+	for _, p := range providers {
+		listener.Notify(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: p})
+	}
+	return nil
+}
+
+func (r *memoryRegistry) UnSubscribe(url *common.URL, listener registry.NotifyListener) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	listeners := r.listeners[url.ServiceKey()]
+	for i, l := range listeners {
+		if l == listener {
+			r.listeners[url.ServiceKey()] = append(listeners[:i:i], listeners[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryRegistry) LoadSubscribeInstances(*common.URL, registry.NotifyListener) error {
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+// ContextField returns the value of a field of @ctx logged by the Ctx functions, and false if @ctx has none
+type ContextField func(ctx context.Context) (string, bool)
+
+var (
+	fieldsLock sync.RWMutex
+	fieldNames []string
+	fields     = make(map[string]ContextField)
+)
+
+// SetContextField registers the field @name logged by the Ctx functions, e.g. the request id
+func SetContextField(name string, field ContextField) {
+	fieldsLock.Lock()
+	defer fieldsLock.Unlock()
+	if _, ok := fields[name]; !ok {
+		fieldNames = append(fieldNames, name)
+	}
+	fields[name] = field
+}
+
+// CtxDebugf logs at debug level with the fields of @ctx
+func CtxDebugf(ctx context.Context, template string, args ...interface{}) {
+	logger.GetLogger().Debugf(withFields(ctx, template), args...)
+}
+
+// CtxInfof logs at info level with the fields of @ctx
+func CtxInfof(ctx context.Context, template string, args ...interface{}) {
+	logger.GetLogger().Infof(withFields(ctx, template), args...)
+}
+
+// CtxWarnf logs at warn level with the fields of @ctx
+func CtxWarnf(ctx context.Context, template string, args ...interface{}) {
+	logger.GetLogger().Warnf(withFields(ctx, template), args...)
+}
+
+// CtxErrorf logs at error level with the fields of @ctx
+func CtxErrorf(ctx context.Context, template string, args ...interface{}) {
+	logger.GetLogger().Errorf(withFields(ctx, template), args...)
+}
+
+// withFields prefixes @template with the fields of @ctx, like "[request_id=1a2b] template"
+func withFields(ctx context.Context, template string) string {
+	if ctx == nil {
+		return template
+	}
+	fieldsLock.RLock()
+	defer fieldsLock.RUnlock()
+	var sb strings.Builder
+	for _, name := range fieldNames {
+		value, ok := fields[name](ctx)
+		if !ok {
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("[")
+		} else {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(name)
+		sb.WriteString("=")
+		// the value is a part of the template
+		sb.WriteString(strings.ReplaceAll(value, "%", "%%"))
+	}
+	if sb.Len() == 0 {
+		return template
+	}
+	sb.WriteString("] ")
+	sb.WriteString(template)
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestWithFields(t *testing.T) {
+	SetContextField("tenant", func(ctx context.Context) (string, bool) {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		return tenant, ok
+	})
+
+	assert.Equal(t, "get %s", withFields(context.Background(), "get %s"))
+	ctx := context.WithValue(context.Background(), tenantKey{}, "100%")
+	assert.Equal(t, "[tenant=100%%] get %s", withFields(ctx, "get %s"))
+}
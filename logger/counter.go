@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"go.uber.org/atomic"
+)
+
+// EntryLevels are the levels of the entries counted by CountEntry
+var EntryLevels = []string{"debug", "info", "warn", "error", "panic", "fatal"}
+
+var entryCounts = make([]atomic.Int64, len(EntryLevels))
+
+// CountEntry counts an entry of the @level written by the logger drivers, the trace entries are counted as debug,
+// and the unknown levels are ignored
+func CountEntry(level string) {
+	switch level {
+	case "trace":
+		level = "debug"
+	case "warning":
+		level = "warn"
+	case "dpanic":
+		level = "panic"
+	}
+	for i, l := range EntryLevels {
+		if l == level {
+			entryCounts[i].Inc()
+			return
+		}
+	}
+}
+
+// EntryCounts returns the number of the entries written by the logger drivers by the level since the start
+func EntryCounts() map[string]int64 {
+	counts := make(map[string]int64, len(EntryLevels))
+	for i, level := range EntryLevels {
+		counts[level] = entryCounts[i].Load()
+	}
+	return counts
+}
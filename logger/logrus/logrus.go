@@ -86,9 +86,29 @@ func instantiate(config *common.URL) (log logger.Logger, err error) {
 		formatter = &logrus.TextFormatter{}
 	}
 	lg.SetFormatter(formatter)
+	lg.AddHook(entryCounter{})
 	return &Logger{lg: lg}, err
 }
 
+// entryCounter counts the written entries for the metrics
+type entryCounter struct{}
+
+func (entryCounter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (entryCounter) Fire(entry *logrus.Entry) error {
+	CountEntry(entry.Level.String())
+	return nil
+}
+
+// SetLoggerLevel changes the level, the unknown level is ignored
+func (l *Logger) SetLoggerLevel(level string) {
+	if lv, err := logrus.ParseLevel(level); err == nil {
+		l.lg.SetLevel(lv)
+	}
+}
+
 func (l *Logger) Debug(args ...interface{}) {
 	l.lg.Debug(args...)
 }
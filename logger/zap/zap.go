@@ -79,11 +79,34 @@ func instantiate(config *common.URL) (log logger.Logger, err error) {
 		encoder = zapcore.NewConsoleEncoder(encoderConfig())
 	}
 
-	log = zap.New(zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sync...), lv),
-		zap.AddCaller(), zap.AddCallerSkip(1)).Sugar()
+	atomicLevel := zap.NewAtomicLevelAt(lv)
+	log = &dynamicLevelLogger{
+		SugaredLogger: zap.New(zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sync...), atomicLevel),
+			zap.AddCaller(), zap.AddCallerSkip(1), zap.Hooks(countEntry)).Sugar(),
+		level: atomicLevel,
+	}
 	return log, nil
 }
 
+// dynamicLevelLogger is the zap logger whose level could be changed at runtime
+type dynamicLevelLogger struct {
+	*zap.SugaredLogger
+	level zap.AtomicLevel
+}
+
+// SetLoggerLevel changes the level, the unknown level is ignored
+func (l *dynamicLevelLogger) SetLoggerLevel(level string) {
+	if lv, err := zapcore.ParseLevel(level); err == nil {
+		l.level.SetLevel(lv)
+	}
+}
+
+// countEntry counts the written @entry for the metrics
+func countEntry(entry zapcore.Entry) error {
+	CountEntry(entry.Level.String())
+	return nil
+}
+
 type Logger struct {
 	lg *zap.SugaredLogger
 }
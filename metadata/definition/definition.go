@@ -35,12 +35,13 @@ type ServiceDefiner interface {
 	ToBytes() ([]byte, error)
 }
 
-// ServiceDefinition is the describer of service definition
+// ServiceDefinition is the describer of service definition, whose json is the same as the ServiceDefinition of java
 type ServiceDefinition struct {
 	CanonicalName string             `json:"canonicalName"`
 	CodeSource    string             `json:"codeSource"`
 	Methods       []MethodDefinition `json:"methods"`
 	Types         []TypeDefinition   `json:"types"`
+	Annotations   []string           `json:"annotations"`
 }
 
 // ToBytes convert ServiceDefinition to json string
@@ -80,44 +81,56 @@ type MethodDefinition struct {
 	Name           string           `json:"name"`
 	ParameterTypes []string         `json:"parameterTypes"`
 	ReturnType     string           `json:"returnType"`
-	Parameters     []TypeDefinition `json:"parameters"`
+	Parameters     []TypeDefinition `json:"parameters,omitempty"`
+	Annotations    []string         `json:"annotations"`
 }
 
-// TypeDefinition is the describer of type definition
+// TypeDefinition is the describer of type definition, Items are the types of the elements of the arrays or the keys
+// and the values of the maps, and Properties are the types of the fields of the structs
 type TypeDefinition struct {
-	ID              string                    `json:"id"`
-	Type            string                    `json:"type"`
-	Items           []TypeDefinition          `json:"items"`
-	Enums           []string                  `json:"enums"`
-	Properties      map[string]TypeDefinition `json:"properties"`
-	TypeBuilderName string                    `json:"typeBuilderName"`
+	ID              string            `json:"id,omitempty"`
+	Type            string            `json:"type"`
+	Items           []string          `json:"items,omitempty"`
+	Enums           []string          `json:"enum,omitempty"`
+	Properties      map[string]string `json:"properties,omitempty"`
+	TypeBuilderName string            `json:"typeBuilderName,omitempty"`
 }
 
-// BuildServiceDefinition can build service definition which will be used to describe a service
+// BuildServiceDefinition can build service definition which will be used to describe a service,
+// the types of the parameters and the return values are the java types which hessian maps the go types to
 func BuildServiceDefinition(service common.Service, url *common.URL) *ServiceDefinition {
-	sd := &ServiceDefinition{}
+	sd := &ServiceDefinition{Annotations: []string{}}
 	sd.CanonicalName = url.Service()
 
-	for k, m := range service.Method() {
-		var paramTypes []string
-		if len(m.ArgsType()) > 0 {
-			for _, t := range m.ArgsType() {
-				paramTypes = append(paramTypes, t.Kind().String())
-			}
+	methods := service.Method()
+	names := make([]string, 0, len(methods))
+	for k := range methods {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	builder := newTypeDefinitionBuilder()
+	for _, k := range names {
+		m := methods[k]
+		paramTypes := make([]string, 0, len(m.ArgsType()))
+		for _, t := range m.ArgsType() {
+			paramTypes = append(paramTypes, builder.build(t))
 		}
 
-		var returnType string
+		returnType := "void"
 		if m.ReplyType() != nil {
-			returnType = m.ReplyType().Kind().String()
+			returnType = builder.build(m.ReplyType())
 		}
 
 		methodD := MethodDefinition{
 			Name:           k,
 			ParameterTypes: paramTypes,
 			ReturnType:     returnType,
+			Annotations:    []string{},
 		}
 		sd.Methods = append(sd.Methods, methodD)
 	}
+	sd.Types = builder.types
 
 	return sd
 }
@@ -18,7 +18,9 @@
 package definition
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"testing"
 )
 
@@ -39,7 +41,7 @@ func TestBuildServiceDefinition(t *testing.T) {
 	url, err := common.NewURL(fmt.Sprintf(
 		"%v://127.0.0.1:20000/com.ikurento.user.UserProvider1?anyhost=true&"+
 			"application=BDTService&category=providers&default.timeout=10000&dubbo=dubbo-provider-golang-1.0.0&"+
-			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo+user-info+server&org=ikurento.com&"+
+			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo%%20user-info%%20server&org=ikurento.com&"+
 			"owner=ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245&group=%v&version=%v&bean.name=%v",
 		protocol, serviceName, group, version, beanName))
 	assert.NoError(t, err)
@@ -47,7 +49,45 @@ func TestBuildServiceDefinition(t *testing.T) {
 	assert.NoError(t, err)
 	service := common.ServiceMap.GetServiceByServiceKey(url.Protocol, url.ServiceKey())
 	sd := BuildServiceDefinition(*service, url)
-	assert.Equal(t, "{canonicalName:com.ikurento.user.UserProvider, codeSource:, methods:[{name:GetUser,parameterTypes:[{type:slice}],returnType:ptr,params:[] }], types:[]}", sd.String())
+	assert.Equal(t, "{canonicalName:com.ikurento.user.UserProvider, codeSource:, methods:[{name:GetUser,parameterTypes:[{type:java.lang.Object[]}],returnType:com.ikurento.user.User,params:[] }], types:[{id:,type:java.lang.Object[],builderName:}{id:,type:java.lang.Object,builderName:}{id:,type:com.ikurento.user.User,builderName:}{id:,type:java.lang.String,builderName:}{id:,type:int,builderName:}{id:,type:java.util.Date,builderName:}]}", sd.String())
 	fsd := BuildFullDefinition(*service, url)
-	assert.Equal(t, "{parameters:{anyhost:true,application:BDTService,bean.name:UserProvider,category:providers,default.timeout:10000,dubbo:dubbo-provider-golang-1.0.0,environment:dev,group:group1,interface:com.ikurento.user.UserProvider,ip:192.168.56.1,methods:GetUser,module:dubbogo user-info server,org:ikurento.com,owner:ZX,pid:1447,revision:0.0.1,side:provider,timeout:3000,timestamp:1556509797245,version:0.0.1}, canonicalName:com.ikurento.user.UserProvider, codeSource:, methods:[{name:GetUser,parameterTypes:[{type:slice}],returnType:ptr,params:[] }], types:[]}", fsd.String())
+	assert.Equal(t, "{parameters:{anyhost:true,application:BDTService,bean.name:UserProvider,category:providers,default.timeout:10000,dubbo:dubbo-provider-golang-1.0.0,environment:dev,group:group1,interface:com.ikurento.user.UserProvider,ip:192.168.56.1,methods:GetUser,module:dubbogo user-info server,org:ikurento.com,owner:ZX,pid:1447,revision:0.0.1,side:provider,timeout:3000,timestamp:1556509797245,version:0.0.1}, canonicalName:com.ikurento.user.UserProvider, codeSource:, methods:[{name:GetUser,parameterTypes:[{type:java.lang.Object[]}],returnType:com.ikurento.user.User,params:[] }], types:[{id:,type:java.lang.Object[],builderName:}{id:,type:java.lang.Object,builderName:}{id:,type:com.ikurento.user.User,builderName:}{id:,type:java.lang.String,builderName:}{id:,type:int,builderName:}{id:,type:java.util.Date,builderName:}]}", fsd.String())
+}
+
+// Node refers to itself
+type Node struct {
+	Name     string
+	Children []*Node
+	Parent   *Node `hessian:"up"`
+	Weight   *int32
+	Labels   map[string]string
+}
+
+func (Node) JavaClassName() string {
+	return "com.test.Node"
+}
+
+type NodeProvider struct{}
+
+func (p *NodeProvider) Walk(ctx context.Context, root *Node, depth int64) ([]*Node, error) {
+	return nil, nil
+}
+
+func (p *NodeProvider) Count(ctx context.Context, root *Node) (uint16, error) {
+	return 0, nil
+}
+
+func TestBuildServiceDefinitionJson(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.test.NodeProvider?interface=com.test.NodeProvider&group=tree&version=1.0.0")
+	assert.NoError(t, err)
+	_, err = common.ServiceMap.Register("com.test.NodeProvider", "dubbo", "tree", "1.0.0", &NodeProvider{})
+	assert.NoError(t, err)
+	service := common.ServiceMap.GetServiceByServiceKey(url.Protocol, url.ServiceKey())
+
+	data, err := BuildServiceDefinition(*service, url).ToBytes()
+	assert.NoError(t, err)
+	// the json of the same service in java
+	expected, err := ioutil.ReadFile("testdata/node_provider.json")
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(data))
 }
@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package definition
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	pojoType = reflect.TypeOf((*hessian.POJO)(nil)).Elem()
+
+	// javaPrimitiveTypes are the java types of the go kinds which hessian encodes as java primitives
+	javaPrimitiveTypes = map[reflect.Kind]string{
+		reflect.Bool:    "boolean",
+		reflect.Int8:    "byte",
+		reflect.Uint8:   "byte",
+		reflect.Int16:   "short",
+		reflect.Uint16:  "int",
+		reflect.Int32:   "int",
+		reflect.Int:     "long",
+		reflect.Int64:   "long",
+		reflect.Uint:    "long",
+		reflect.Uint32:  "long",
+		reflect.Uint64:  "long",
+		reflect.Float32: "float",
+		reflect.Float64: "double",
+	}
+
+	// javaBoxedTypes are the java types of the pointers to the go kinds in javaPrimitiveTypes
+	javaBoxedTypes = map[string]string{
+		"boolean": "java.lang.Boolean",
+		"byte":    "java.lang.Byte",
+		"short":   "java.lang.Short",
+		"int":     "java.lang.Integer",
+		"long":    "java.lang.Long",
+		"float":   "java.lang.Float",
+		"double":  "java.lang.Double",
+	}
+)
+
+// typeDefinitionBuilder builds the definitions of the types in the same way as the TypeDefinitionBuilder of java,
+// every type is defined once so that the recursive types end.
+type typeDefinitionBuilder struct {
+	types   []TypeDefinition
+	defined map[string]struct{}
+}
+
+func newTypeDefinitionBuilder() *typeDefinitionBuilder {
+	return &typeDefinitionBuilder{defined: make(map[string]struct{})}
+}
+
+// build defines @t and the types it refers to, and returns the java type name of @t
+func (b *typeDefinitionBuilder) build(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		t = t.Elem()
+	}
+	name := javaTypeName(t)
+	if _, ok := b.defined[name]; ok {
+		return name
+	}
+	b.defined[name] = struct{}{}
+	// reserve the place of @t before its items and properties are defined
+	index := len(b.types)
+	b.types = append(b.types, TypeDefinition{Type: name})
+	td := TypeDefinition{Type: name}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		td.Items = []string{b.build(t.Elem())}
+	case reflect.Map:
+		td.Items = []string{b.build(t.Key()), b.build(t.Elem())}
+	case reflect.Struct:
+		if t != timeType && len(t.Name()) != 0 {
+			td.Properties = make(map[string]string)
+			b.buildProperties(t, td.Properties)
+		}
+	}
+	b.types[index] = td
+	return name
+}
+
+// buildProperties defines the fields of the struct @t by the field names of hessian, the fields of the embedded
+// structs are flattened
+func (b *typeDefinitionBuilder) buildProperties(t reflect.Type, properties map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) != 0 {
+			continue
+		}
+		tag, ok := field.Tag.Lookup("hessian")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			b.buildProperties(field.Type, properties)
+			continue
+		}
+		name := tag
+		if !ok {
+			name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+		properties[name] = b.build(field.Type)
+	}
+}
+
+// javaTypeName returns the canonical java type name of @t by the mapping of hessian, the java class name is used for
+// the POJO, and the go type name with the package path is used for the other structs
+func javaTypeName(t reflect.Type) string {
+	if name, ok := javaPrimitiveTypes[t.Kind()]; ok {
+		return name
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "java.lang.String"
+	case reflect.Interface:
+		return "java.lang.Object"
+	case reflect.Ptr:
+		name := javaTypeName(t.Elem())
+		if boxed, ok := javaBoxedTypes[name]; ok {
+			return boxed
+		}
+		return name
+	case reflect.Slice, reflect.Array:
+		return javaTypeName(t.Elem()) + "[]"
+	case reflect.Map:
+		return "java.util.Map<" + javaTypeName(t.Key()) + "," + javaTypeName(t.Elem()) + ">"
+	case reflect.Struct:
+		if t == timeType {
+			return "java.util.Date"
+		}
+		if reflect.PtrTo(t).Implements(pojoType) {
+			return reflect.New(t).Interface().(hessian.POJO).JavaClassName()
+		}
+		if len(t.Name()) == 0 {
+			return "java.lang.Object"
+		}
+		return strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+	}
+	return t.String()
+}
@@ -23,6 +23,7 @@ import (
 
 import (
 	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/gof/observer"
 	"github.com/dubbogo/gost/log/logger"
 
 	perrors "github.com/pkg/errors"
@@ -51,6 +52,9 @@ func init() {
 // MetadataServiceNameMapping is the implementation based on metadata report
 // it's a singleton
 type MetadataServiceNameMapping struct {
+	// cache stores the app names of the interfaces looked up last time, which are updated by the listeners and
+	// used when the metadata report is unavailable
+	cache sync.Map
 }
 
 // Map will map the service to this application-level service
@@ -77,18 +81,57 @@ func (d *MetadataServiceNameMapping) Map(url *common.URL) error {
 }
 
 // Get will return the application-level services. If not found, the empty set will be returned.
+// The services looked up last time are returned if the metadata report is unavailable.
 func (d *MetadataServiceNameMapping) Get(url *common.URL, listener registry.MappingListener) (*gxset.HashSet, error) {
 	serviceInterface := url.GetParam(constant.InterfaceKey, "")
-	metadataReport := instance.GetMetadataReportInstance()
-	return metadataReport.GetServiceAppMapping(serviceInterface, defaultGroup, listener)
+	metadataReport := getMetaDataReport(url.GetParam(constant.RegistryKey, ""))
+	if metadataReport == nil {
+		return d.cached(serviceInterface, perrors.New("the metadata report instance is not found"))
+	}
+	if listener != nil {
+		listener = &cachedMappingListener{MappingListener: listener, mapping: d, serviceInterface: serviceInterface}
+	}
+	services, err := metadataReport.GetServiceAppMapping(serviceInterface, defaultGroup, listener)
+	if err != nil {
+		return d.cached(serviceInterface, err)
+	}
+	d.cache.Store(serviceInterface, services)
+	return services, nil
+}
+
+// cached returns the services of @serviceInterface looked up last time, or @err if there is none
+func (d *MetadataServiceNameMapping) cached(serviceInterface string, err error) (*gxset.HashSet, error) {
+	if services, ok := d.cache.Load(serviceInterface); ok {
+		logger.Warnf("Get the services of %s failed, the cached ones are used: %v", serviceInterface, err)
+		return services.(*gxset.HashSet), nil
+	}
+	return nil, err
 }
 
 func (d *MetadataServiceNameMapping) Remove(url *common.URL) error {
 	serviceInterface := url.GetParam(constant.InterfaceKey, "")
-	metadataReport := instance.GetMetadataReportInstance()
+	metadataReport := getMetaDataReport(url.GetParam(constant.RegistryKey, ""))
+	if metadataReport == nil {
+		return nil
+	}
 	return metadataReport.RemoveServiceAppMappingListener(serviceInterface, defaultGroup)
 }
 
+// cachedMappingListener updates the cache of the mapping before notifying the listener
+type cachedMappingListener struct {
+	registry.MappingListener
+	mapping          *MetadataServiceNameMapping
+	serviceInterface string
+}
+
+// OnEvent caches the services of the ServiceMappingChangeEvent
+func (l *cachedMappingListener) OnEvent(e observer.Event) error {
+	if sm, ok := e.(*registry.ServiceMappingChangeEvent); ok && !sm.GetServiceNames().Empty() {
+		l.mapping.cache.Store(l.serviceInterface, sm.GetServiceNames())
+	}
+	return l.MappingListener.OnEvent(e)
+}
+
 // buildMappingKey will return mapping key, it looks like defaultGroup/serviceInterface
 func (d *MetadataServiceNameMapping) buildMappingKey(serviceInterface string) string {
 	// the issue : https://github.com/apache/dubbo/issues/4671
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package metadata
+
+import (
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/gof/observer"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config/instance"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// mappingReport stores the mapping in memory like the metadata reports do
+type mappingReport struct {
+	report.MetadataReport
+	mappings  map[string]string
+	listeners map[string]registry.MappingListener
+	err       error
+}
+
+func (m *mappingReport) RegisterServiceAppMapping(key string, group string, value string) error {
+	if content, ok := report.AppendAppName(m.mappings[group+"/"+key], value); ok {
+		m.mappings[group+"/"+key] = content
+		if listener := m.listeners[group+"/"+key]; listener != nil {
+			return listener.OnEvent(registry.NewServiceMappingChangedEvent(key, report.ParseAppNames(content)))
+		}
+	}
+	return nil
+}
+
+func (m *mappingReport) GetServiceAppMapping(key string, group string, listener registry.MappingListener) (*gxset.HashSet, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if listener != nil {
+		m.listeners[group+"/"+key] = listener
+	}
+	return report.ParseAppNames(m.mappings[group+"/"+key]), nil
+}
+
+func (m *mappingReport) RemoveServiceAppMappingListener(key string, group string) error {
+	delete(m.listeners, group+"/"+key)
+	return nil
+}
+
+type mappingReportFactory struct {
+	report *mappingReport
+}
+
+func (f *mappingReportFactory) CreateMetadataReport(*common.URL) report.MetadataReport {
+	return f.report
+}
+
+type recordingListener struct {
+	services *gxset.HashSet
+}
+
+func (l *recordingListener) OnEvent(e observer.Event) error {
+	l.services = e.(*registry.ServiceMappingChangeEvent).GetServiceNames()
+	return nil
+}
+
+func (l *recordingListener) Stop() {}
+
+func TestMetadataServiceNameMapping(t *testing.T) {
+	config.SetRootConfig(*config.NewRootConfigBuilder().
+		SetApplication(config.NewApplicationConfigBuilder().SetName("user-app").SetMetadataType(constant.DefaultMetadataStorageType).Build()).
+		Build())
+	mr := &mappingReport{mappings: map[string]string{}, listeners: map[string]registry.MappingListener{}}
+	extension.SetMetadataReportFactory("mapping-mock", func() factory.MetadataReportFactory {
+		return &mappingReportFactory{report: mr}
+	})
+	regURL, err := common.NewURL("mapping-mock://127.0.0.1:2181")
+	assert.Nil(t, err)
+	instance.SetMetadataReportInstanceByReg(regURL)
+
+	u, err := common.NewURL("dubbo://127.0.0.1:20000/com.foo.UserService?interface=com.foo.UserService&registry=mapping-mock")
+	assert.Nil(t, err)
+	m := GetNameMappingInstance()
+	assert.Nil(t, m.Map(u))
+	assert.Equal(t, "user-app", mr.mappings["mapping/com.foo.UserService"])
+
+	listener := &recordingListener{}
+	services, err := m.Get(u, listener)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"user-app"}, services.Values())
+
+	// the other application exposing the service is appended
+	assert.Nil(t, mr.RegisterServiceAppMapping("com.foo.UserService", defaultGroup, "user-app-v2"))
+	assert.Equal(t, "user-app,user-app-v2", mr.mappings["mapping/com.foo.UserService"])
+	assert.Equal(t, 2, listener.services.Size())
+
+	// the services updated by the listener are used when the metadata report is unavailable
+	mr.err = perrors.New("unavailable")
+	services, err = m.Get(u, nil)
+	assert.Nil(t, err)
+	assert.True(t, services.Contains("user-app"))
+	assert.True(t, services.Contains("user-app-v2"))
+
+	other, err := common.NewURL("dubbo://127.0.0.1:20000/com.foo.OrderService?interface=com.foo.OrderService&registry=mapping-mock")
+	assert.Nil(t, err)
+	_, err = m.Get(other, nil)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, m.Remove(u))
+	assert.NotContains(t, mr.listeners, "mapping/com.foo.UserService")
+}
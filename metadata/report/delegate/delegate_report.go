@@ -19,6 +19,7 @@ package delegate
 
 import (
 	"encoding/json"
+	"fmt"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -37,6 +38,8 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 	"dubbo.apache.org/dubbo-go/v3/config/instance"
 	"dubbo.apache.org/dubbo-go/v3/metadata/definition"
 	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
@@ -94,9 +97,13 @@ func (mrr *metadataReportRetry) startRetryTask() {
 
 // MetadataReport is a absolute delegate for MetadataReport
 type MetadataReport struct {
-	reportUrl           *common.URL
-	syncReport          bool
-	metadataReportRetry *metadataReportRetry
+	reportUrl  *common.URL
+	syncReport bool
+	// reportConsumerDefinition enables reporting the parameters of the consumers
+	reportConsumerDefinition bool
+	metadataReportRetry      *metadataReportRetry
+	// publisher publishes the metadata in background unless syncReport is set
+	publisher *metadataPublisher
 
 	failedReports     map[*identifier.MetadataIdentifier]interface{}
 	failedReportsLock sync.RWMutex
@@ -114,11 +121,17 @@ func NewMetadataReport() (*MetadataReport, error) {
 		return nil, perrors.New("the metadataReport URL is not configured, you should configure it.")
 	}
 	bmr := &MetadataReport{
-		reportUrl:          url,
-		syncReport:         url.GetParamBool(constant.SyncReportKey, false),
-		failedReports:      make(map[*identifier.MetadataIdentifier]interface{}, 4),
-		allMetadataReports: make(map[*identifier.MetadataIdentifier]interface{}, 4),
+		reportUrl:                url,
+		syncReport:               url.GetParamBool(constant.SyncReportKey, false),
+		reportConsumerDefinition: url.GetParamBool(constant.ReportConsumerDefinitionKey, false),
+		failedReports:            make(map[*identifier.MetadataIdentifier]interface{}, 4),
+		allMetadataReports:       make(map[*identifier.MetadataIdentifier]interface{}, 4),
+		publisher:                newMetadataPublisher(gopool.GetPool(gopool.MetadataPublish), defaultPublishRetryTimes, defaultPublishRetryBackoff),
 	}
+	// flush the queued metadata at shutdown
+	extension.AddCustomShutdownCallback(func() {
+		bmr.publisher.flush(defaultPublishFlushTimeout)
+	})
 
 	mrr, err := newMetadataReportRetry(
 		url.GetParamInt(constant.RetryPeriodKey, defaultMetadataReportRetryPeriod),
@@ -169,14 +182,24 @@ func (mr *MetadataReport) retry() bool {
 
 // StoreProviderMetadata will delegate to call remote metadata's sdk to store provider service definition
 func (mr *MetadataReport) StoreProviderMetadata(identifier *identifier.MetadataIdentifier, definer definition.ServiceDefiner) {
+	mr.storeMetadata(common.PROVIDER, identifier, definer)
+}
+
+// storeMetadata stores the metadata at once if syncReport is set, or else it is published in background
+func (mr *MetadataReport) storeMetadata(role int, identifier *identifier.MetadataIdentifier, definer interface{}) {
 	if mr.syncReport {
-		mr.storeMetadataTask(common.PROVIDER, identifier, definer)
+		if err := mr.storeMetadataTask(role, identifier, definer); err != nil {
+			logger.Errorf("storeProviderMetadataTask error in stage call  metadata report to StoreProviderMetadata, msg is %+v", err)
+		}
+		return
 	}
-	go mr.storeMetadataTask(common.PROVIDER, identifier, definer)
+	mr.publisher.submit(fmt.Sprintf("the metadata of %s", identifier.GetIdentifierKey()), func() error {
+		return mr.storeMetadataTask(role, identifier, definer)
+	})
 }
 
 // storeMetadataTask will delegate to call remote metadata's sdk to store
-func (mr *MetadataReport) storeMetadataTask(role int, identifier *identifier.MetadataIdentifier, definer interface{}) {
+func (mr *MetadataReport) storeMetadataTask(role int, identifier *identifier.MetadataIdentifier, definer interface{}) error {
 	logger.Infof("publish provider identifier and definition:  Identifier :%v ; definition: %v .", identifier, definer)
 	mr.allMetadataReportsLock.Lock()
 	mr.allMetadataReports[identifier] = definer
@@ -213,18 +236,48 @@ func (mr *MetadataReport) storeMetadataTask(role int, identifier *identifier.Met
 	} else if role == common.CONSUMER {
 		err = report.StoreConsumerMetadata(identifier, string(data))
 	}
+	return err
+}
 
-	if err != nil {
-		logger.Errorf("storeProviderMetadataTask error in stage call  metadata report to StoreProviderMetadata, msg is %+v", err)
+// StoreConsumerMetadata will delegate to call remote metadata's sdk to store consumer side service definition,
+// it's skipped unless the report of the consumer definition is enabled
+func (mr *MetadataReport) StoreConsumerMetadata(identifier *identifier.MetadataIdentifier, definer map[string]string) {
+	if !mr.reportConsumerDefinition {
+		return
 	}
+	mr.storeMetadata(common.CONSUMER, identifier, definer)
 }
 
-// StoreConsumerMetadata will delegate to call remote metadata's sdk to store consumer side service definition
-func (mr *MetadataReport) StoreConsumerMetadata(identifier *identifier.MetadataIdentifier, definer map[string]string) {
+// RemoveConsumerMetadata will delegate to call remote metadata's sdk to remove the consumer side service definition
+func (mr *MetadataReport) RemoveConsumerMetadata(id *identifier.MetadataIdentifier) error {
+	if !mr.reportConsumerDefinition {
+		return nil
+	}
+	// the metadata is not reported again by the cycle report or the retry
+	key := id.GetIdentifierKey()
+	mr.allMetadataReportsLock.Lock()
+	for k := range mr.allMetadataReports {
+		if k.GetIdentifierKey() == key {
+			delete(mr.allMetadataReports, k)
+		}
+	}
+	mr.allMetadataReportsLock.Unlock()
+	mr.failedReportsLock.Lock()
+	for k := range mr.failedReports {
+		if k.GetIdentifierKey() == key {
+			delete(mr.failedReports, k)
+		}
+	}
+	mr.failedReportsLock.Unlock()
+
+	report := instance.GetMetadataReportInstance()
 	if mr.syncReport {
-		mr.storeMetadataTask(common.CONSUMER, identifier, definer)
+		return report.RemoveConsumerMetadata(id)
 	}
-	go mr.storeMetadataTask(common.CONSUMER, identifier, definer)
+	mr.publisher.submit(fmt.Sprintf("the removal of the metadata of %s", key), func() error {
+		return report.RemoveConsumerMetadata(id)
+	})
+	return nil
 }
 
 // SaveServiceMetadata will delegate to call remote metadata's sdk to save service metadata
@@ -233,11 +286,9 @@ func (mr *MetadataReport) SaveServiceMetadata(identifier *identifier.ServiceMeta
 	if mr.syncReport {
 		return report.SaveServiceMetadata(identifier, url)
 	}
-	go func() {
-		if err := report.SaveServiceMetadata(identifier, url); err != nil {
-			logger.Warnf("report.SaveServiceMetadata(identifier:%v, url:%v) = error:%v", identifier, url, err)
-		}
-	}()
+	mr.publisher.submit(fmt.Sprintf("the service metadata of %s", identifier.GetIdentifierKey()), func() error {
+		return report.SaveServiceMetadata(identifier, url)
+	})
 	return nil
 }
 
@@ -247,11 +298,9 @@ func (mr *MetadataReport) RemoveServiceMetadata(identifier *identifier.ServiceMe
 	if mr.syncReport {
 		return report.RemoveServiceMetadata(identifier)
 	}
-	go func() {
-		if err := report.RemoveServiceMetadata(identifier); err != nil {
-			logger.Warnf("report.RemoveServiceMetadata(identifier:%v) = error:%v", identifier, err)
-		}
-	}()
+	mr.publisher.submit(fmt.Sprintf("the removal of the service metadata of %s", identifier.GetIdentifierKey()), func() error {
+		return report.RemoveServiceMetadata(identifier)
+	})
 	return nil
 }
 
@@ -276,12 +325,9 @@ func (mr *MetadataReport) SaveSubscribedData(identifier *identifier.SubscriberMe
 	if mr.syncReport {
 		return report.SaveSubscribedData(identifier, string(bytes))
 	}
-	go func() {
-		if err := report.SaveSubscribedData(identifier, string(bytes)); err != nil {
-			logger.Warnf("report.SaveSubscribedData(identifier:%v, string(bytes):%v) = error: %v",
-				identifier, string(bytes), err)
-		}
-	}()
+	mr.publisher.submit(fmt.Sprintf("the subscribed data of %s", identifier.GetIdentifierKey()), func() error {
+		return report.SaveSubscribedData(identifier, string(bytes))
+	})
 	return nil
 }
 
@@ -297,6 +343,11 @@ func (mr *MetadataReport) GetServiceDefinition(identifier *identifier.MetadataId
 	return report.GetServiceDefinition(identifier)
 }
 
+// FailedPublications returns the count of the metadata failed to be published in background after the retries
+func (mr *MetadataReport) FailedPublications() int64 {
+	return mr.publisher.failedCount()
+}
+
 // doHandlerMetadataCollection will store metadata to metadata support with given metadataMap
 func (mr *MetadataReport) doHandlerMetadataCollection(metadataMap map[*identifier.MetadataIdentifier]interface{}) bool {
 	if len(metadataMap) == 0 {
@@ -304,7 +355,7 @@ func (mr *MetadataReport) doHandlerMetadataCollection(metadataMap map[*identifie
 	}
 	for e := range metadataMap {
 		if common.RoleType(common.PROVIDER).Role() == e.Side {
-			mr.StoreProviderMetadata(e, metadataMap[e].(*definition.FullServiceDefinition))
+			mr.StoreProviderMetadata(e, metadataMap[e].(definition.ServiceDefiner))
 		} else if common.RoleType(common.CONSUMER).Role() == e.Side {
 			mr.StoreConsumerMetadata(e, metadataMap[e].(map[string]string))
 		}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package delegate
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+)
+
+const (
+	defaultPublishRetryTimes   = 5
+	defaultPublishRetryBackoff = time.Second
+	maxPublishRetryBackoff     = 30 * time.Second
+	// defaultPublishFlushTimeout is the max time to wait for the queued metadata to be published at shutdown
+	defaultPublishFlushTimeout = 5 * time.Second
+)
+
+// publishTask is a write of the metadata to the metadata center
+type publishTask struct {
+	name    string
+	publish func() error
+}
+
+// metadataPublisher publishes the metadata to the metadata center in background by the goroutine pool, so that a
+// slow metadata center never blocks exporting or referring the services. Every task is retried with the exponential
+// backoff, and it is logged and counted as failed once the retries are exhausted.
+type metadataPublisher struct {
+	tasks        *gopool.Pool
+	retryTimes   int
+	retryBackoff time.Duration
+	pending      sync.WaitGroup
+	failed       *atomic.Int64
+}
+
+func newMetadataPublisher(tasks *gopool.Pool, retryTimes int, retryBackoff time.Duration) *metadataPublisher {
+	return &metadataPublisher{
+		tasks:        tasks,
+		retryTimes:   retryTimes,
+		retryBackoff: retryBackoff,
+		failed:       atomic.NewInt64(0),
+	}
+}
+
+// submit enqueues the task without blocking, the task is dropped and counted as failed if the queue is full
+func (p *metadataPublisher) submit(name string, publish func() error) {
+	p.pending.Add(1)
+	task := &publishTask{name: name, publish: publish}
+	if err := p.tasks.SubmitTimeout(func() {
+		defer p.pending.Done()
+		p.publish(task)
+	}, 0); err != nil {
+		p.pending.Done()
+		p.failed.Inc()
+		logger.Errorf("The metadata publishing queue is unavailable, %s is dropped: %v", name, err)
+	}
+}
+
+// publish does the task at most retryTimes+1 times
+func (p *metadataPublisher) publish(task *publishTask) {
+	backoff := p.retryBackoff
+	for i := 0; ; i++ {
+		err := task.publish()
+		if err == nil {
+			return
+		}
+		if i >= p.retryTimes {
+			p.failed.Inc()
+			logger.Errorf("Publishing %s failed after %d retries: %v", task.name, p.retryTimes, err)
+			return
+		}
+		logger.Warnf("Publishing %s failed, retry %d/%d after %s: %v", task.name, i+1, p.retryTimes, backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxPublishRetryBackoff {
+			backoff = maxPublishRetryBackoff
+		}
+	}
+}
+
+// flush waits until all the queued tasks are done or the @timeout expires, it returns whether all are done
+func (p *metadataPublisher) flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		logger.Warnf("The queued metadata is not published in %s at shutdown", timeout)
+		return false
+	}
+}
+
+// failedCount returns the count of the tasks failed after the retries or dropped
+func (p *metadataPublisher) failedCount() int64 {
+	return p.failed.Load()
+}
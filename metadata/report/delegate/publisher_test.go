@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package delegate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+	"dubbo.apache.org/dubbo-go/v3/config/instance"
+	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
+)
+
+func TestMetadataPublisherRetry(t *testing.T) {
+	p := newMetadataPublisher(gopool.NewPool("test", 1, 8), 2, time.Millisecond)
+
+	// it succeeds at the last retry
+	attempts := atomic.NewInt32(0)
+	p.submit("succeeded", func() error {
+		if attempts.Inc() < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.True(t, p.flush(time.Second))
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, int64(0), p.failedCount())
+
+	// it fails after the retries are exhausted
+	attempts.Store(0)
+	p.submit("failed", func() error {
+		attempts.Inc()
+		return errors.New("unavailable")
+	})
+	assert.True(t, p.flush(time.Second))
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, int64(1), p.failedCount())
+}
+
+func TestMetadataPublisherFlushTimeout(t *testing.T) {
+	p := newMetadataPublisher(gopool.NewPool("test", 1, 8), 0, time.Millisecond)
+	hanging := make(chan struct{})
+	p.submit("hanging", func() error {
+		<-hanging
+		return nil
+	})
+	assert.False(t, p.flush(10*time.Millisecond))
+	close(hanging)
+	assert.True(t, p.flush(time.Second))
+}
+
+func TestMetadataPublisherQueueFull(t *testing.T) {
+	p := newMetadataPublisher(gopool.NewPool("test", 1, 1), 0, time.Millisecond)
+	hanging, running := make(chan struct{}), make(chan struct{})
+	defer close(hanging)
+	p.submit("running", func() error {
+		close(running)
+		<-hanging
+		return nil
+	})
+	<-running
+	for i := 0; i < 2; i++ {
+		p.submit("hanging", func() error {
+			<-hanging
+			return nil
+		})
+	}
+	// one is running, one is queued and the last one is dropped
+	assert.Equal(t, int64(1), p.failedCount())
+}
+
+// hangingMetadataReport is a metadata report whose writes hang until released
+type hangingMetadataReport struct {
+	report.MetadataReport
+	released chan struct{}
+	stored   *atomic.Int32
+}
+
+func (r *hangingMetadataReport) StoreProviderMetadata(*identifier.MetadataIdentifier, string) error {
+	<-r.released
+	r.stored.Inc()
+	return nil
+}
+
+func TestMetadataReportNotBlockedByHangingBackend(t *testing.T) {
+	backend := &hangingMetadataReport{released: make(chan struct{}), stored: atomic.NewInt32(0)}
+	extension.SetMetadataReportFactory("hanging", func() factory.MetadataReportFactory {
+		return &hangingMetadataReportFactory{report: backend}
+	})
+	url, err := common.NewURL("hanging://127.0.0.1:2181")
+	assert.NoError(t, err)
+	instance.SetMetadataReportInstance(url)
+	mtr, err := NewMetadataReport()
+	assert.NoError(t, err)
+
+	metadataId := &identifier.MetadataIdentifier{
+		Application: "app",
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: "com.ikurento.user.UserProvider",
+			Version:          "0.0.2",
+			Group:            "group1",
+			Side:             "provider",
+		},
+	}
+	definition := getMockDefinition(metadataId, t)
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		mtr.StoreProviderMetadata(metadataId, definition)
+	}
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, int32(0), backend.stored.Load())
+
+	// the queued metadata is published once the backend recovers
+	close(backend.released)
+	assert.True(t, mtr.publisher.flush(time.Second))
+	assert.Equal(t, int32(10), backend.stored.Load())
+}
+
+type hangingMetadataReportFactory struct {
+	report report.MetadataReport
+}
+
+func (f *hangingMetadataReportFactory) CreateMetadataReport(*common.URL) report.MetadataReport {
+	return f.report
+}
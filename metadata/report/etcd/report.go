@@ -38,6 +38,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/metadata/report"
 	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
 	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting/etcdv3"
 )
 
 const DEFAULT_ROOT = "dubbo"
@@ -91,6 +92,11 @@ func (e *etcdMetadataReport) StoreConsumerMetadata(consumerMetadataIdentifier *i
 	return e.client.Put(key, serviceParameterString)
 }
 
+// RemoveConsumerMetadata will remove the metadata of the consumer
+func (e *etcdMetadataReport) RemoveConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier) error {
+	return e.client.Delete(e.getNodeKey(consumerMetadataIdentifier))
+}
+
 // SaveServiceMetadata will store the metadata
 // metadata including the basic info of the server, service info, and other user custom info
 func (e *etcdMetadataReport) SaveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier, url *common.URL) error {
@@ -107,6 +113,9 @@ func (e *etcdMetadataReport) RemoveServiceMetadata(metadataIdentifier *identifie
 // if not found, an empty list will be returned.
 func (e *etcdMetadataReport) GetExportedURLs(metadataIdentifier *identifier.ServiceMetadataIdentifier) ([]string, error) {
 	content, err := e.client.Get(e.getNodeKey(metadataIdentifier))
+	if perrors.Cause(err) == gxetcd.ErrKVPairNotFound {
+		return []string{}, nil
+	}
 	if err != nil {
 		logger.Errorf("etcdMetadataReport GetExportedURLs err:{%v}", err.Error())
 		return []string{}, err
@@ -154,11 +163,11 @@ func (e *etcdMetadataReport) RegisterServiceAppMapping(key string, group string,
 	} else if err != nil {
 		return err
 	}
-	if strings.Contains(oldVal, value) {
+	newVal, ok := report.AppendAppName(oldVal, value)
+	if !ok {
 		return nil
 	}
-	value = oldVal + constant.CommaSeparator + value
-	return e.client.Put(path, value)
+	return e.client.Put(path, newVal)
 }
 
 // GetServiceAppMapping get the app names from the specified Dubbo service interface
@@ -168,12 +177,7 @@ func (e *etcdMetadataReport) GetServiceAppMapping(key string, group string, list
 	if err != nil {
 		return nil, err
 	}
-	appNames := strings.Split(v, constant.CommaSeparator)
-	set := gxset.NewSet()
-	for _, app := range appNames {
-		set.Add(app)
-	}
-	return set, nil
+	return report.ParseAppNames(v), nil
 }
 
 func (e *etcdMetadataReport) RemoveServiceAppMappingListener(key string, group string) error {
@@ -182,16 +186,20 @@ func (e *etcdMetadataReport) RemoveServiceAppMappingListener(key string, group s
 
 type etcdMetadataReportFactory struct{}
 
-// CreateMetadataReport get the MetadataReport instance of etcd
+// CreateMetadataReport get the MetadataReport instance of etcd, the client is shared with the etcd registries and
+// config centers of the same addresses, and the metadata is stored under the group of the metadata report
 func (e *etcdMetadataReportFactory) CreateMetadataReport(url *common.URL) report.MetadataReport {
-	timeout := url.GetParamDuration(constant.TimeoutKey, constant.DefaultRegTimeout)
+	timeout := url.GetParamDurationWithDefault(constant.TimeoutKey, constant.DefaultRegTimeout)
 	addresses := strings.Split(url.Location, ",")
-	client, err := gxetcd.NewClient(gxetcd.MetadataETCDV3Client, addresses, timeout, 1)
+	client, err := etcdv3.AcquireClient(gxetcd.MetadataETCDV3Client, addresses, timeout, 1)
 	if err != nil {
 		logger.Errorf("Could not create etcd metadata report. URL: %s,error:{%v}", url.String(), err)
 		return nil
 	}
-	group := url.GetParam(constant.GroupKey, DEFAULT_ROOT)
+	group := url.GetParam(constant.MetadataReportGroupKey, url.GetParam(constant.GroupKey, DEFAULT_ROOT))
+	if len(group) == 0 {
+		group = DEFAULT_ROOT
+	}
 	group = constant.PathSeparator + strings.TrimPrefix(group, constant.PathSeparator)
 	return &etcdMetadataReport{client: client, root: group}
 }
@@ -17,6 +17,18 @@
 
 package etcd
 
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
+)
+
 /*
 import (
 	"encoding/json"
@@ -363,3 +375,21 @@ func Test_etcdMetadataReport_GetAppMetadata(t *testing.T) {
 	}
 }
 */
+
+func TestEtcdMetadataReportGetNodeKey(t *testing.T) {
+	id := &identifier.ServiceMetadataIdentifier{
+		Protocol: "dubbo",
+		Revision: "a",
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: "com.test.MyTest",
+			Version:          "1.0.0",
+			Group:            "test_group",
+			Side:             "provider",
+		},
+	}
+	e := &etcdMetadataReport{root: "/custom"}
+	assert.Equal(t, "/custom/"+id.GetFilePathKey(), e.getNodeKey(id))
+
+	e = &etcdMetadataReport{root: "/"}
+	assert.Equal(t, "/"+id.GetFilePathKey(), e.getNodeKey(id))
+}
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package report
+
+import (
+	"strings"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// ParseAppNames parses the app names of the service app mapping, which are separated by comma as Dubbo stores them
+func ParseAppNames(content string) *gxset.HashSet {
+	set := gxset.NewSet()
+	for _, app := range strings.Split(content, constant.CommaSeparator) {
+		if app = strings.TrimSpace(app); len(app) > 0 {
+			set.Add(app)
+		}
+	}
+	return set
+}
+
+// AppendAppName appends @app to the app names of the service app mapping @content, the apps of the other
+// applications exposing the service are kept. It returns false if @app is present.
+func AppendAppName(content string, app string) (string, bool) {
+	if ParseAppNames(content).Contains(app) {
+		return content, false
+	}
+	if len(strings.TrimSpace(content)) == 0 {
+		return app, true
+	}
+	return content + constant.CommaSeparator + app, true
+}
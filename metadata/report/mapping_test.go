@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package report
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAppNames(t *testing.T) {
+	apps := ParseAppNames("app1, app2,,app1")
+	assert.Equal(t, 2, apps.Size())
+	assert.True(t, apps.Contains("app1"))
+	assert.True(t, apps.Contains("app2"))
+	assert.True(t, ParseAppNames("").Empty())
+}
+
+func TestAppendAppName(t *testing.T) {
+	content, ok := AppendAppName("", "user-app")
+	assert.True(t, ok)
+	assert.Equal(t, "user-app", content)
+
+	content, ok = AppendAppName(content, "user")
+	assert.True(t, ok)
+	assert.Equal(t, "user-app,user", content)
+
+	content, ok = AppendAppName(content, "user-app")
+	assert.False(t, ok)
+	assert.Equal(t, "user-app,user", content)
+}
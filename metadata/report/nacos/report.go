@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 )
 
 import (
@@ -51,10 +53,25 @@ func init() {
 	})
 }
 
+const (
+	// defaultPublishAttempts is the max attempts to publish the metadata
+	defaultPublishAttempts = 3
+	// defaultPublishBackoff is the backoff before the next attempt to publish, which doubles after each attempt
+	defaultPublishBackoff = 500 * time.Millisecond
+)
+
 // nacosMetadataReport is the implementation
 // of MetadataReport based on nacos.
 type nacosMetadataReport struct {
 	client *nacosClient.NacosConfigClient
+	// group is the nacos group of the service definitions and the revision records
+	group           string
+	publishAttempts int
+	publishBackoff  time.Duration
+
+	listenersLock sync.Mutex
+	// mappingCancels are the funcs canceling the mapping listeners by the groups and the keys
+	mappingCancels map[string][]func() error
 }
 
 // GetAppMetadata get metadata info from nacos
@@ -89,38 +106,48 @@ func (n *nacosMetadataReport) PublishAppMetadata(metadataIdentifier *identifier.
 	})
 }
 
-// StoreProviderMetadata stores the metadata.
+// StoreProviderMetadata stores the service definition of the provider,
+// the data id is providers:interface:version:group:application.
 func (n *nacosMetadataReport) StoreProviderMetadata(providerIdentifier *identifier.MetadataIdentifier, serviceDefinitions string) error {
 	return n.storeMetadata(vo.ConfigParam{
-		DataId:  providerIdentifier.GetIdentifierKey(),
-		Group:   providerIdentifier.Group,
+		DataId:  getDataId(providerIdentifier),
+		Group:   n.group,
 		Content: serviceDefinitions,
 	})
 }
 
-// StoreConsumerMetadata stores the metadata.
+// StoreConsumerMetadata stores the parameters of the consumer,
+// the data id is consumers:interface:version:group:application.
 func (n *nacosMetadataReport) StoreConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier, serviceParameterString string) error {
 	return n.storeMetadata(vo.ConfigParam{
-		DataId:  consumerMetadataIdentifier.GetIdentifierKey(),
-		Group:   consumerMetadataIdentifier.Group,
+		DataId:  getDataId(consumerMetadataIdentifier),
+		Group:   n.group,
 		Content: serviceParameterString,
 	})
 }
 
-// SaveServiceMetadata saves the metadata.
-func (n *nacosMetadataReport) SaveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier, url *common.URL) error {
+// RemoveConsumerMetadata removes the parameters of the consumer.
+func (n *nacosMetadataReport) RemoveConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier) error {
+	return n.deleteMetadata(vo.ConfigParam{
+		DataId: getDataId(consumerMetadataIdentifier),
+		Group:  n.group,
+	})
+}
+
+// SaveServiceMetadata saves the url of the revision, which is read by GetExportedURLs in service discovery mode.
+func (n *nacosMetadataReport) SaveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier, serviceURL *common.URL) error {
 	return n.storeMetadata(vo.ConfigParam{
 		DataId:  metadataIdentifier.GetIdentifierKey(),
-		Group:   metadataIdentifier.Group,
-		Content: url.String(),
+		Group:   n.group,
+		Content: url.QueryEscape(serviceURL.String()),
 	})
 }
 
-// RemoveServiceMetadata removes the metadata.
+// RemoveServiceMetadata removes the url of the revision.
 func (n *nacosMetadataReport) RemoveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier) error {
 	return n.deleteMetadata(vo.ConfigParam{
 		DataId: metadataIdentifier.GetIdentifierKey(),
-		Group:  metadataIdentifier.Group,
+		Group:  n.group,
 	})
 }
 
@@ -128,7 +155,7 @@ func (n *nacosMetadataReport) RemoveServiceMetadata(metadataIdentifier *identifi
 func (n *nacosMetadataReport) GetExportedURLs(metadataIdentifier *identifier.ServiceMetadataIdentifier) ([]string, error) {
 	return n.getConfigAsArray(vo.ConfigParam{
 		DataId: metadataIdentifier.GetIdentifierKey(),
-		Group:  metadataIdentifier.Group,
+		Group:  n.group,
 	})
 }
 
@@ -150,14 +177,42 @@ func (n *nacosMetadataReport) GetSubscribedURLs(subscriberMetadataIdentifier *id
 // GetServiceDefinition gets the service definition.
 func (n *nacosMetadataReport) GetServiceDefinition(metadataIdentifier *identifier.MetadataIdentifier) (string, error) {
 	return n.getConfig(vo.ConfigParam{
-		DataId: metadataIdentifier.GetIdentifierKey(),
-		Group:  metadataIdentifier.Group,
+		DataId: getDataId(metadataIdentifier),
+		Group:  n.group,
 	})
 }
 
-// storeMetadata will publish the metadata to Nacos
-// if failed or error is not nil, error will be returned
+// getDataId returns the data id of the metadata, which is category:interface:version:group:application
+func getDataId(metadataIdentifier *identifier.MetadataIdentifier) string {
+	category := constant.ConsumerCategory
+	if metadataIdentifier.Side == constant.SideProvider {
+		category = constant.ProviderCategory
+	}
+	return strings.Join([]string{category, metadataIdentifier.ServiceInterface, metadataIdentifier.Version,
+		metadataIdentifier.Group, metadataIdentifier.Application}, constant.KeySeparator)
+}
+
+// storeMetadata will publish the metadata to Nacos, and retry with the backoff if failed.
+// the error of the last attempt will be returned
 func (n *nacosMetadataReport) storeMetadata(param vo.ConfigParam) error {
+	attempts, backoff := n.publishAttempts, n.publishBackoff
+	if attempts <= 0 {
+		attempts = 1
+	}
+	var err error
+	for i := 1; ; i++ {
+		if err = n.publishMetadata(param); err == nil || i >= attempts {
+			return err
+		}
+		logger.Warnf("Publishing the metadata %s failed at attempt %d/%d, retry after %s: %v",
+			param.DataId, i, attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// publishMetadata publishes the metadata to Nacos once
+func (n *nacosMetadataReport) publishMetadata(param vo.ConfigParam) error {
 	res, err := n.client.Client().PublishConfig(param)
 	if err != nil {
 		return perrors.WithMessage(err, "Could not publish the metadata")
@@ -211,31 +266,44 @@ func (n *nacosMetadataReport) getConfig(param vo.ConfigParam) (string, error) {
 }
 
 func (n *nacosMetadataReport) addListener(key string, group string, notify registry.MappingListener) error {
-	return n.client.Client().ListenConfig(vo.ConfigParam{
+	// the client may be shared with the config centers, which listen the configs by their own
+	cancel, err := nacos.ListenConfig(n.client, vo.ConfigParam{
 		DataId: key,
 		Group:  group,
 		OnChange: func(namespace, group, dataId, data string) {
 			go callback(notify, dataId, data)
 		},
 	})
+	if err != nil {
+		return err
+	}
+	n.listenersLock.Lock()
+	defer n.listenersLock.Unlock()
+	if n.mappingCancels == nil {
+		n.mappingCancels = make(map[string][]func() error)
+	}
+	n.mappingCancels[group+constant.PathSeparator+key] = append(n.mappingCancels[group+constant.PathSeparator+key], cancel)
+	return nil
 }
 
 func callback(notify registry.MappingListener, dataId, data string) {
-	appNames := strings.Split(data, constant.CommaSeparator)
-	set := gxset.NewSet()
-	for _, app := range appNames {
-		set.Add(app)
-	}
-	if err := notify.OnEvent(registry.NewServiceMappingChangedEvent(dataId, set)); err != nil {
+	if err := notify.OnEvent(registry.NewServiceMappingChangedEvent(dataId, report.ParseAppNames(data))); err != nil {
 		logger.Errorf("serviceMapping callback err: %s", err.Error())
 	}
 }
 
 func (n *nacosMetadataReport) removeServiceMappingListener(key string, group string) error {
-	return n.client.Client().CancelListenConfig(vo.ConfigParam{
-		DataId: key,
-		Group:  group,
-	})
+	n.listenersLock.Lock()
+	cancels := n.mappingCancels[group+constant.PathSeparator+key]
+	delete(n.mappingCancels, group+constant.PathSeparator+key)
+	n.listenersLock.Unlock()
+	var err error
+	for _, cancel := range cancels {
+		if cancelErr := cancel(); cancelErr != nil {
+			err = cancelErr
+		}
+	}
+	return err
 }
 
 // RegisterServiceAppMapping map the specified Dubbo service interface to current Dubbo app name
@@ -244,21 +312,14 @@ func (n *nacosMetadataReport) RegisterServiceAppMapping(key string, group string
 		DataId: key,
 		Group:  group,
 	})
-	if oldVal != "" {
-		oldApps := strings.Split(oldVal, constant.CommaSeparator)
-		if len(oldApps) > 0 {
-			for _, app := range oldApps {
-				if app == value {
-					return nil
-				}
-			}
-		}
-		value = oldVal + constant.CommaSeparator + value
+	newVal, ok := report.AppendAppName(oldVal, value)
+	if !ok {
+		return nil
 	}
 	return n.storeMetadata(vo.ConfigParam{
 		DataId:  key,
 		Group:   group,
-		Content: value,
+		Content: newVal,
 	})
 }
 
@@ -280,12 +341,7 @@ func (n *nacosMetadataReport) GetServiceAppMapping(key string, group string, lis
 	if v == "" {
 		return nil, perrors.New("There is no service app mapping data.")
 	}
-	appNames := strings.Split(v, constant.CommaSeparator)
-	set := gxset.NewSet()
-	for _, e := range appNames {
-		set.Add(e)
-	}
-	return set, nil
+	return report.ParseAppNames(v), nil
 }
 
 // RemoveServiceAppMappingListener remove the serviceMapping listener from metadata center
@@ -302,10 +358,18 @@ func (n *nacosMetadataReportFactory) CreateMetadataReport(url *common.URL) repor
 	url.SetParam(constant.NacosGroupKey, url.GetParam(constant.MetadataReportGroupKey, constant.ServiceDiscoveryDefaultGroup))
 	url.SetParam(constant.NacosUsername, url.Username)
 	url.SetParam(constant.NacosPassword, url.Password)
-	client, err := nacos.NewNacosConfigClientByUrl(url)
+	url.SetParam(constant.NacosAccessKey, url.GetParam(constant.MetadataReportAccessKey, ""))
+	url.SetParam(constant.NacosSecretKey, url.GetParam(constant.MetadataReportSecretKey, ""))
+	// the client is shared with the nacos config centers of the same servers and settings
+	client, err := nacos.AcquireNacosConfigClient(url)
 	if err != nil {
-		logger.Errorf("Could not create nacos metadata report. URL: %s", url.String())
+		logger.Errorf("Could not create nacos metadata report. URL: %s, error: %v", url.String(), err)
 		return nil
 	}
-	return &nacosMetadataReport{client: client}
+	return &nacosMetadataReport{
+		client:          client,
+		group:           url.GetParam(constant.NacosGroupKey, constant.ServiceDiscoveryDefaultGroup),
+		publishAttempts: defaultPublishAttempts,
+		publishBackoff:  defaultPublishBackoff,
+	}
 }
@@ -19,9 +19,11 @@ package nacos
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 import (
@@ -31,6 +33,8 @@ import (
 
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+
+	"github.com/stretchr/testify/assert"
 )
 
 import (
@@ -500,3 +504,60 @@ func Test_nacosMetadataReport_RegisterServiceAppMapping(t *testing.T) {
 		})
 	}
 }
+
+func Test_nacosMetadataReport_StoreMetadataRoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mnc := NewMockIConfigClient(ctrl)
+	configs := make(map[string]string)
+	key := func(param vo.ConfigParam) string {
+		return param.Group + "/" + param.DataId
+	}
+	// the first publish of every metadata fails, and then it succeeds by the retry
+	failed := make(map[string]bool)
+	mnc.EXPECT().PublishConfig(gomock.Any()).DoAndReturn(func(param vo.ConfigParam) (bool, error) {
+		if !failed[key(param)] {
+			failed[key(param)] = true
+			return false, errors.New("nacos is unavailable")
+		}
+		configs[key(param)] = param.Content
+		return true, nil
+	}).AnyTimes()
+	mnc.EXPECT().GetConfig(gomock.Any()).DoAndReturn(func(param vo.ConfigParam) (string, error) {
+		return configs[key(param)], nil
+	}).AnyTimes()
+	mnc.EXPECT().DeleteConfig(gomock.Any()).DoAndReturn(func(param vo.ConfigParam) (bool, error) {
+		delete(configs, key(param))
+		return true, nil
+	}).AnyTimes()
+	nc := &nacosClient.NacosConfigClient{}
+	nc.SetClient(mnc)
+	n := &nacosMetadataReport{client: nc, group: "dubbo-metadata", publishAttempts: 2, publishBackoff: time.Millisecond}
+
+	providerIdentifier := newMetadataIdentifier(constant.SideProvider)
+	assert.Nil(t, n.StoreProviderMetadata(providerIdentifier, `{"canonicalName":"com.test.MyTest"}`))
+	assert.Contains(t, configs, "dubbo-metadata/providers:com.test.MyTest:1.0.0:test_group:test")
+	definition, err := n.GetServiceDefinition(providerIdentifier)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"canonicalName":"com.test.MyTest"}`, definition)
+
+	consumerIdentifier := newMetadataIdentifier(constant.SideConsumer)
+	assert.Nil(t, n.StoreConsumerMetadata(consumerIdentifier, `{"timeout":"3s"}`))
+	assert.Equal(t, `{"timeout":"3s"}`, configs["dubbo-metadata/consumers:com.test.MyTest:1.0.0:test_group:test"])
+
+	serviceURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.test.MyTest?version=1.0.0&group=test_group")
+	serviceMetadataIdentifier := newServiceMetadataIdentifier()
+	assert.Nil(t, n.SaveServiceMetadata(serviceMetadataIdentifier, serviceURL))
+	urls, err := n.GetExportedURLs(serviceMetadataIdentifier)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{serviceURL.String()}, urls)
+	assert.Nil(t, n.RemoveServiceMetadata(serviceMetadataIdentifier))
+	urls, err = n.GetExportedURLs(serviceMetadataIdentifier)
+	assert.Nil(t, err)
+	assert.Empty(t, urls)
+
+	// the error is returned after all attempts fail
+	n.publishAttempts = 1
+	providerIdentifier.Application = "retry"
+	err = n.StoreProviderMetadata(providerIdentifier, "definition")
+	assert.NotNil(t, err)
+}
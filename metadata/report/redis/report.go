@@ -0,0 +1,298 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/go-redis/redis/v8"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+const (
+	// defaultRoot is the prefix of the keys of the mapping data
+	defaultRoot = "dubbo"
+	// metadataStoreTag is the suffix of the keys of the metadata, the same as Dubbo
+	metadataStoreTag = ".metaData"
+)
+
+var emptyStrSlice = make([]string, 0)
+
+// appendAppScript appends the app name to the app names of the service in the mapping hash atomically,
+// it is a no-op if the app name is present
+var appendAppScript = redis.NewScript(`
+local old = redis.call('HGET', KEYS[1], ARGV[1])
+if not old then
+	redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+	return 1
+end
+for app in string.gmatch(old, '[^,]+') do
+	if app == ARGV[2] then
+		return 0
+	end
+end
+redis.call('HSET', KEYS[1], ARGV[1], old .. ',' .. ARGV[2])
+return 1
+`)
+
+func init() {
+	mf := &redisMetadataReportFactory{}
+	extension.SetMetadataReportFactory("redis", func() factory.MetadataReportFactory {
+		return mf
+	})
+}
+
+// redisMetadataReport is the implementation of MetadataReport based on redis. Each identifier is stored in its own
+// key as Dubbo does, which expires after ttl if it's set, and the mapping data of a group is stored in a hash.
+type redisMetadataReport struct {
+	client redis.UniversalClient
+	root   string
+	ttl    time.Duration
+}
+
+// GetAppMetadata get metadata info from redis
+func (r *redisMetadataReport) GetAppMetadata(metadataIdentifier *identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
+	data, err := r.get(metadataIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	var metadataInfo common.MetadataInfo
+	if err = json.Unmarshal([]byte(data), &metadataInfo); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return &metadataInfo, nil
+}
+
+// PublishAppMetadata publish metadata info to redis
+func (r *redisMetadataReport) PublishAppMetadata(metadataIdentifier *identifier.SubscriberMetadataIdentifier, info *common.MetadataInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	return r.set(metadataIdentifier, string(data))
+}
+
+// StoreProviderMetadata stores the metadata.
+func (r *redisMetadataReport) StoreProviderMetadata(providerIdentifier *identifier.MetadataIdentifier, serviceDefinitions string) error {
+	return r.set(providerIdentifier, serviceDefinitions)
+}
+
+// StoreConsumerMetadata stores the metadata.
+func (r *redisMetadataReport) StoreConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier, serviceParameterString string) error {
+	return r.set(consumerMetadataIdentifier, serviceParameterString)
+}
+
+// RemoveConsumerMetadata removes the metadata of the consumer.
+func (r *redisMetadataReport) RemoveConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier) error {
+	return r.del(consumerMetadataIdentifier)
+}
+
+// SaveServiceMetadata saves the metadata.
+func (r *redisMetadataReport) SaveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier, url *common.URL) error {
+	return r.set(metadataIdentifier, url.String())
+}
+
+// RemoveServiceMetadata removes the metadata.
+func (r *redisMetadataReport) RemoveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier) error {
+	return r.del(metadataIdentifier)
+}
+
+// GetExportedURLs gets the urls.
+// if not found, an empty list will be returned.
+func (r *redisMetadataReport) GetExportedURLs(metadataIdentifier *identifier.ServiceMetadataIdentifier) ([]string, error) {
+	return r.getURLs(metadataIdentifier)
+}
+
+// SaveSubscribedData saves the urls.
+func (r *redisMetadataReport) SaveSubscribedData(subscriberMetadataIdentifier *identifier.SubscriberMetadataIdentifier, urls string) error {
+	return r.set(subscriberMetadataIdentifier, urls)
+}
+
+// GetSubscribedURLs gets the urls.
+// if not found, an empty list will be returned.
+func (r *redisMetadataReport) GetSubscribedURLs(subscriberMetadataIdentifier *identifier.SubscriberMetadataIdentifier) ([]string, error) {
+	return r.getURLs(subscriberMetadataIdentifier)
+}
+
+// GetServiceDefinition gets the service definition.
+func (r *redisMetadataReport) GetServiceDefinition(metadataIdentifier *identifier.MetadataIdentifier) (string, error) {
+	return r.get(metadataIdentifier)
+}
+
+// RegisterServiceAppMapping map the specified Dubbo service interface to current Dubbo app name
+func (r *redisMetadataReport) RegisterServiceAppMapping(key string, group string, value string) error {
+	err := appendAppScript.Run(context.Background(), r.client, []string{r.getMappingKey(group)}, key, value).Err()
+	if err == redis.Nil {
+		err = nil
+	}
+	return perrors.WithMessagef(err, "register the mapping of %s to %s", key, value)
+}
+
+// GetServiceAppMapping get the app names from the specified Dubbo service interface
+func (r *redisMetadataReport) GetServiceAppMapping(key string, group string, listener registry.MappingListener) (*gxset.HashSet, error) {
+	v, err := r.client.HGet(context.Background(), r.getMappingKey(group), key).Result()
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "get the mapping of %s", key)
+	}
+	return report.ParseAppNames(v), nil
+}
+
+// RemoveServiceAppMappingListener does nothing because the mapping listener of redis is not supported yet
+func (r *redisMetadataReport) RemoveServiceAppMappingListener(key string, group string) error {
+	return nil
+}
+
+func (r *redisMetadataReport) set(metadataIdentifier identifier.IMetadataIdentifier, value string) error {
+	err := r.client.Set(context.Background(), getKey(metadataIdentifier), value, r.ttl).Err()
+	return perrors.WithMessagef(err, "store the metadata of %s", metadataIdentifier.GetIdentifierKey())
+}
+
+func (r *redisMetadataReport) del(metadataIdentifier identifier.IMetadataIdentifier) error {
+	err := r.client.Del(context.Background(), getKey(metadataIdentifier)).Err()
+	return perrors.WithMessagef(err, "remove the metadata of %s", metadataIdentifier.GetIdentifierKey())
+}
+
+func (r *redisMetadataReport) get(metadataIdentifier identifier.IMetadataIdentifier) (string, error) {
+	v, err := r.client.Get(context.Background(), getKey(metadataIdentifier)).Result()
+	if err != nil {
+		return "", perrors.WithMessagef(err, "get the metadata of %s", metadataIdentifier.GetIdentifierKey())
+	}
+	return v, nil
+}
+
+func (r *redisMetadataReport) getURLs(metadataIdentifier identifier.IMetadataIdentifier) ([]string, error) {
+	v, err := r.get(metadataIdentifier)
+	if perrors.Cause(err) == redis.Nil {
+		return emptyStrSlice, nil
+	}
+	if err != nil || len(v) == 0 {
+		return emptyStrSlice, err
+	}
+	return []string{v}, nil
+}
+
+func (r *redisMetadataReport) getMappingKey(group string) string {
+	return r.root + constant.KeySeparator + group
+}
+
+func getKey(metadataIdentifier identifier.IMetadataIdentifier) string {
+	return metadataIdentifier.GetIdentifierKey() + metadataStoreTag
+}
+
+type redisMetadataReportFactory struct{}
+
+// CreateMetadataReport get the MetadataReport instance of redis, it connects to a redis cluster if cluster is true
+// or there are more than one addresses
+func (f *redisMetadataReportFactory) CreateMetadataReport(url *common.URL) report.MetadataReport {
+	client, err := newClient(url)
+	if err != nil {
+		logger.Errorf("Could not create redis metadata report. URL: %s,error:{%v}", url.String(), err)
+		return nil
+	}
+	root := url.GetParam(constant.MetadataReportGroupKey, "")
+	if len(root) == 0 {
+		root = defaultRoot
+	}
+	return &redisMetadataReport{
+		client: client,
+		root:   root,
+		ttl:    url.GetParamDurationWithDefault(constant.MetadataReportTTLKey, "0s"),
+	}
+}
+
+// newClient creates the redis client of @url and checks the connection
+func newClient(url *common.URL) (redis.UniversalClient, error) {
+	opts, err := newUniversalOptions(url)
+	if err != nil {
+		return nil, err
+	}
+	var client redis.UniversalClient
+	if url.GetParamBool(constant.RedisClusterKey, false) {
+		client = redis.NewClusterClient(opts.Cluster())
+	} else {
+		// it is a cluster client if there are more than one addresses
+		client = redis.NewUniversalClient(opts)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	if err = client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, perrors.WithMessagef(err, "connect to redis %s", url.Location)
+	}
+	return client, nil
+}
+
+// newUniversalOptions builds the options of the redis client from the location, the username and the password,
+// the timeout, the db and the tls of @url
+func newUniversalOptions(url *common.URL) (*redis.UniversalOptions, error) {
+	addresses := parseAddresses(url.Location)
+	if len(addresses) == 0 {
+		return nil, perrors.New("the address of redis is required")
+	}
+	timeout := url.GetParamDurationWithDefault(constant.TimeoutKey, constant.DefaultRegTimeout)
+	tlsConfig, err := config.GetClientTlsConfig(&config.TLSConfig{
+		CACertFile:         url.GetParam(constant.CACert, ""),
+		TLSCertFile:        url.GetParam(constant.TLSCert, ""),
+		TLSKeyFile:         url.GetParam(constant.TLSKey, ""),
+		TLSServerName:      url.GetParam(constant.TLSServerNAME, ""),
+		InsecureSkipVerify: url.GetParamBool(constant.TLSInsecureSkipVerify, false),
+	})
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "load the tls config of redis %s", url.Location)
+	}
+	return &redis.UniversalOptions{
+		Addrs:        addresses,
+		Username:     url.Username,
+		Password:     url.Password,
+		DB:           int(url.GetParamInt(constant.RedisDBKey, 0)),
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+		TLSConfig:    tlsConfig,
+	}, nil
+}
+
+// parseAddresses splits the comma separated addresses of @location, the blank ones are ignored
+func parseAddresses(location string) []string {
+	addresses := make([]string, 0, 1)
+	for _, address := range strings.Split(location, constant.CommaSeparator) {
+		if address = strings.TrimSpace(address); len(address) > 0 {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
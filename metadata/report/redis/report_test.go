@@ -0,0 +1,212 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package redis
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/stretchr/testify/assert"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
+)
+
+func newTestReport(t *testing.T, params url.Values) (*miniredis.Miniredis, *redisMetadataReport) {
+	s := miniredis.RunT(t)
+	u, err := common.NewURL("redis://"+s.Addr(), common.WithParams(params))
+	assert.Nil(t, err)
+	mr := (&redisMetadataReportFactory{}).CreateMetadataReport(u)
+	assert.NotNil(t, mr)
+	return s, mr.(*redisMetadataReport)
+}
+
+func newMetadataIdentifier(side string) *identifier.MetadataIdentifier {
+	return &identifier.MetadataIdentifier{
+		Application: "test",
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: "com.test.MyTest",
+			Version:          "1.0.0",
+			Group:            "test_group",
+			Side:             side,
+		},
+	}
+}
+
+func newServiceMetadataIdentifier() *identifier.ServiceMetadataIdentifier {
+	return &identifier.ServiceMetadataIdentifier{
+		Protocol: "dubbo",
+		Revision: "a",
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: "com.test.MyTest",
+			Version:          "1.0.0",
+			Group:            "test_group",
+			Side:             constant.ProviderProtocol,
+		},
+	}
+}
+
+func TestRedisMetadataReportProviderMetadata(t *testing.T) {
+	s, r := newTestReport(t, nil)
+	id := newMetadataIdentifier(constant.ProviderProtocol)
+
+	_, err := r.GetServiceDefinition(id)
+	assert.Equal(t, redis.Nil, perrors.Cause(err))
+
+	assert.Nil(t, r.StoreProviderMetadata(id, "definition"))
+	assert.True(t, s.Exists(id.GetIdentifierKey()+metadataStoreTag))
+	definition, err := r.GetServiceDefinition(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "definition", definition)
+
+	assert.Nil(t, r.StoreProviderMetadata(id, "new definition"))
+	definition, err = r.GetServiceDefinition(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "new definition", definition)
+
+	assert.Nil(t, r.StoreConsumerMetadata(newMetadataIdentifier(constant.Consumer), "params"))
+	v, err := s.Get(newMetadataIdentifier(constant.Consumer).GetIdentifierKey() + metadataStoreTag)
+	assert.Nil(t, err)
+	assert.Equal(t, "params", v)
+}
+
+func TestRedisMetadataReportServiceMetadata(t *testing.T) {
+	_, r := newTestReport(t, nil)
+	id := newServiceMetadataIdentifier()
+
+	urls, err := r.GetExportedURLs(id)
+	assert.Nil(t, err)
+	assert.Empty(t, urls)
+
+	u, err := common.NewURL("dubbo://127.0.0.1:20000/com.test.MyTest?interface=com.test.MyTest")
+	assert.Nil(t, err)
+	assert.Nil(t, r.SaveServiceMetadata(id, u))
+	urls, err = r.GetExportedURLs(id)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{u.String()}, urls)
+
+	assert.Nil(t, r.RemoveServiceMetadata(id))
+	urls, err = r.GetExportedURLs(id)
+	assert.Nil(t, err)
+	assert.Empty(t, urls)
+}
+
+func TestRedisMetadataReportAppMetadata(t *testing.T) {
+	_, r := newTestReport(t, nil)
+	id := identifier.NewSubscriberMetadataIdentifier("demo", "1")
+
+	_, err := r.GetAppMetadata(id)
+	assert.NotNil(t, err)
+
+	info := common.NewMetadataInfo("demo", "1", map[string]*common.ServiceInfo{
+		"com.test.MyTest:dubbo": common.NewServiceInfo("com.test.MyTest", "", "", "dubbo", "com.test.MyTest", nil),
+	})
+	assert.Nil(t, r.PublishAppMetadata(id, info))
+	got, err := r.GetAppMetadata(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "demo", got.App)
+	assert.Equal(t, "1", got.Revision)
+	assert.Contains(t, got.Services, "com.test.MyTest:dubbo")
+
+	assert.Nil(t, r.SaveSubscribedData(id, "urls"))
+	urls, err := r.GetSubscribedURLs(id)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"urls"}, urls)
+}
+
+func TestRedisMetadataReportTTL(t *testing.T) {
+	s, r := newTestReport(t, url.Values{constant.MetadataReportTTLKey: []string{"10s"}})
+	id := newMetadataIdentifier(constant.ProviderProtocol)
+	assert.Nil(t, r.StoreProviderMetadata(id, "definition"))
+	assert.Equal(t, 10*time.Second, s.TTL(id.GetIdentifierKey()+metadataStoreTag))
+
+	s.FastForward(11 * time.Second)
+	_, err := r.GetServiceDefinition(id)
+	assert.NotNil(t, err)
+}
+
+func TestRedisMetadataReportServiceAppMapping(t *testing.T) {
+	s, r := newTestReport(t, url.Values{constant.MetadataReportGroupKey: []string{"root"}})
+
+	_, err := r.GetServiceAppMapping("com.test.MyTest", "mapping", nil)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, r.RegisterServiceAppMapping("com.test.MyTest", "mapping", "app1"))
+	assert.Nil(t, r.RegisterServiceAppMapping("com.test.MyTest", "mapping", "app2"))
+	assert.Nil(t, r.RegisterServiceAppMapping("com.test.MyTest", "mapping", "app1"))
+	assert.Equal(t, "app1,app2", s.HGet("root:mapping", "com.test.MyTest"))
+
+	apps, err := r.GetServiceAppMapping("com.test.MyTest", "mapping", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, apps.Size())
+	assert.True(t, apps.Contains("app1"))
+	assert.True(t, apps.Contains("app2"))
+}
+
+func TestParseAddresses(t *testing.T) {
+	assert.Equal(t, []string{"127.0.0.1:6379"}, parseAddresses("127.0.0.1:6379"))
+	assert.Equal(t, []string{"127.0.0.1:7000", "127.0.0.1:7001", "127.0.0.1:7002"},
+		parseAddresses("127.0.0.1:7000, 127.0.0.1:7001,,127.0.0.1:7002 "))
+	assert.Empty(t, parseAddresses(" , "))
+}
+
+func TestNewUniversalOptions(t *testing.T) {
+	u, err := common.NewURL("redis://127.0.0.1:7000,127.0.0.1:7001",
+		common.WithUsername("user"),
+		common.WithPassword("pass"),
+		common.WithParamsValue(constant.TimeoutKey, "3s"),
+		common.WithParamsValue(constant.RedisDBKey, "2"))
+	assert.Nil(t, err)
+	opts, err := newUniversalOptions(u)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"127.0.0.1:7000", "127.0.0.1:7001"}, opts.Addrs)
+	assert.Equal(t, "user", opts.Username)
+	assert.Equal(t, "pass", opts.Password)
+	assert.Equal(t, 2, opts.DB)
+	assert.Equal(t, 3*time.Second, opts.DialTimeout)
+	assert.Nil(t, opts.TLSConfig)
+	_, ok := redis.NewUniversalClient(opts).(*redis.ClusterClient)
+	assert.True(t, ok)
+
+	u, err = common.NewURL("redis://127.0.0.1:6379",
+		common.WithParamsValue(constant.TLSInsecureSkipVerify, "true"),
+		common.WithParamsValue(constant.TLSServerNAME, "redis.test"))
+	assert.Nil(t, err)
+	opts, err = newUniversalOptions(u)
+	assert.Nil(t, err)
+	assert.NotNil(t, opts.TLSConfig)
+	assert.True(t, opts.TLSConfig.InsecureSkipVerify)
+	assert.Equal(t, "redis.test", opts.TLSConfig.ServerName)
+}
+
+func TestCreateMetadataReportFailure(t *testing.T) {
+	u, err := common.NewURL("redis://127.0.0.1:1", common.WithParamsValue(constant.TimeoutKey, "100ms"))
+	assert.Nil(t, err)
+	assert.Nil(t, (&redisMetadataReportFactory{}).CreateMetadataReport(u))
+}
@@ -39,6 +39,9 @@ type MetadataReport interface {
 	// consumer info, and other user custom info.
 	StoreConsumerMetadata(*identifier.MetadataIdentifier, string) error
 
+	// RemoveConsumerMetadata removes the metadata stored by StoreConsumerMetadata.
+	RemoveConsumerMetadata(*identifier.MetadataIdentifier) error
+
 	// SaveServiceMetadata saves the metadata.
 	// Metadata includes the basic info of the server,
 	// service info, and other user custom info.
@@ -20,6 +20,8 @@ package zookeeper
 import (
 	"encoding/json"
 	"strings"
+	"sync"
+	"time"
 )
 
 import (
@@ -40,6 +42,14 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/metadata/report"
 	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
 	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting/zookeeper"
+)
+
+const (
+	// mappingRetryTimes is the max times of updating the mapping when it's updated concurrently
+	mappingRetryTimes = 5
+	// mappingWatchRetryPeriod is the period of watching the mapping again after failure
+	mappingWatchRetryPeriod = 3 * time.Second
 )
 
 var emptyStrSlice = make([]string, 0)
@@ -56,6 +66,9 @@ func init() {
 type zookeeperMetadataReport struct {
 	client  *gxzookeeper.ZookeeperClient
 	rootDir string
+	// mappingWatchers are closed to stop watching the mappings, keyed by the path
+	mappingWatchers     map[string]chan struct{}
+	mappingWatchersLock sync.Mutex
 }
 
 // GetAppMetadata get metadata info from zookeeper
@@ -104,12 +117,18 @@ func (m *zookeeperMetadataReport) StoreConsumerMetadata(consumerMetadataIdentifi
 	k := m.rootDir + consumerMetadataIdentifier.GetFilePathKey()
 	err := m.client.CreateWithValue(k, []byte(serviceParameterString))
 	if perrors.Is(err, zk.ErrNodeExists) {
-		logger.Debugf("Try to store consumer metadata failed. In most cases, it's not a problem. ")
-		return nil
+		// the parameters of the consumer are refreshed when it's referred again
+		_, err = m.client.SetContent(k, []byte(serviceParameterString), -1)
 	}
 	return err
 }
 
+// RemoveConsumerMetadata removes the metadata of the consumer.
+func (m *zookeeperMetadataReport) RemoveConsumerMetadata(consumerMetadataIdentifier *identifier.MetadataIdentifier) error {
+	k := m.rootDir + consumerMetadataIdentifier.GetFilePathKey()
+	return m.client.Delete(k)
+}
+
 // SaveServiceMetadata saves the metadata.
 func (m *zookeeperMetadataReport) SaveServiceMetadata(metadataIdentifier *identifier.ServiceMetadataIdentifier, url *common.URL) error {
 	k := m.rootDir + metadataIdentifier.GetFilePathKey()
@@ -155,53 +174,107 @@ func (m *zookeeperMetadataReport) GetServiceDefinition(metadataIdentifier *ident
 	return string(v), err
 }
 
-// RegisterServiceAppMapping map the specified Dubbo service interface to current Dubbo app name
+// RegisterServiceAppMapping map the specified Dubbo service interface to current Dubbo app name, the app names are
+// stored in /rootDir/group/key separated by comma as Dubbo does. It's retried if the node is updated concurrently.
 func (m *zookeeperMetadataReport) RegisterServiceAppMapping(key string, group string, value string) error {
 	path := m.rootDir + group + constant.PathSeparator + key
-	v, state, err := m.client.GetContent(path)
-	if err == zk.ErrNoNode {
-		return m.client.CreateWithValue(path, []byte(value))
-	} else if err != nil {
-		return err
+	var err error
+	for i := 0; i < mappingRetryTimes; i++ {
+		var (
+			v     []byte
+			state *zk.Stat
+		)
+		v, state, err = m.client.GetContent(path)
+		if err == zk.ErrNoNode {
+			err = m.client.CreateWithValue(path, []byte(value))
+		} else if err == nil {
+			newValue, ok := report.AppendAppName(string(v), value)
+			if !ok {
+				return nil
+			}
+			_, err = m.client.SetContent(path, []byte(newValue), state.Version)
+		}
+		if !perrors.Is(err, zk.ErrNodeExists) && !perrors.Is(err, zk.ErrBadVersion) {
+			return err
+		}
+		logger.Debugf("The mapping %s is updated concurrently, try again", path)
 	}
-	oldValue := string(v)
-	if strings.Contains(oldValue, value) {
-		return nil
-	}
-	value = oldValue + constant.CommaSeparator + value
-	_, err = m.client.SetContent(path, []byte(value), state.Version)
-	return err
+	return perrors.WithMessagef(err, "register the mapping of %s after %d times", path, mappingRetryTimes)
 }
 
-// GetServiceAppMapping get the app names from the specified Dubbo service interface
+// GetServiceAppMapping get the app names from the specified Dubbo service interface,
+// and @listener is notified when they change
 func (m *zookeeperMetadataReport) GetServiceAppMapping(key string, group string, listener registry.MappingListener) (*gxset.HashSet, error) {
 	path := m.rootDir + group + constant.PathSeparator + key
 	v, _, err := m.client.GetContent(path)
 	if err != nil {
 		return nil, err
 	}
-	appNames := strings.Split(string(v), constant.CommaSeparator)
-	set := gxset.NewSet()
-	for _, e := range appNames {
-		set.Add(e)
+	if listener != nil {
+		m.watchMapping(path, key, string(v), listener)
 	}
-	return set, nil
+	return report.ParseAppNames(string(v)), nil
 }
 
+// RemoveServiceAppMappingListener stops watching the mapping of the specified Dubbo service interface
 func (m *zookeeperMetadataReport) RemoveServiceAppMappingListener(key string, group string) error {
+	path := m.rootDir + group + constant.PathSeparator + key
+	m.mappingWatchersLock.Lock()
+	defer m.mappingWatchersLock.Unlock()
+	if done, ok := m.mappingWatchers[path]; ok {
+		close(done)
+		delete(m.mappingWatchers, path)
+	}
 	return nil
 }
 
+// watchMapping notifies @listener of the app names of @path when they change from @content
+// until RemoveServiceAppMappingListener is called
+func (m *zookeeperMetadataReport) watchMapping(path string, key string, content string, listener registry.MappingListener) {
+	m.mappingWatchersLock.Lock()
+	defer m.mappingWatchersLock.Unlock()
+	if _, ok := m.mappingWatchers[path]; ok {
+		return
+	}
+	done := make(chan struct{})
+	m.mappingWatchers[path] = done
+
+	go func() {
+		for {
+			events, err := m.client.ExistW(path)
+			if err != nil {
+				logger.Warnf("Watch the mapping %s failed, try again later: %v", path, err)
+				select {
+				case <-done:
+					return
+				case <-time.After(mappingWatchRetryPeriod):
+					continue
+				}
+			}
+			select {
+			case <-done:
+				return
+			case <-events:
+			}
+			v, _, err := m.client.GetContent(path)
+			if err != nil || string(v) == content {
+				continue
+			}
+			content = string(v)
+			if err = listener.OnEvent(registry.NewServiceMappingChangedEvent(key, report.ParseAppNames(content))); err != nil {
+				logger.Errorf("Notify the change of the mapping %s failed: %v", path, err)
+			}
+		}
+	}()
+}
+
 type zookeeperMetadataReportFactory struct{}
 
 // nolint
 func (mf *zookeeperMetadataReportFactory) CreateMetadataReport(url *common.URL) report.MetadataReport {
-	client, err := gxzookeeper.NewZookeeperClient(
-		"zookeeperMetadataReport",
-		strings.Split(url.Location, ","),
-		false,
-		gxzookeeper.WithZkTimeOut(url.GetParamDuration(constant.TimeoutKey, "15s")),
-	)
+	timeout := url.GetParamDurationWithDefault(constant.TimeoutKey, "15s")
+	// the client is shared with the zookeeper registries and config centers of the same addresses and settings
+	client, err := zookeeper.AcquireZookeeperClient("zookeeperMetadataReport", url, timeout)
 	if err != nil {
 		panic(err)
 	}
@@ -214,5 +287,5 @@ func (mf *zookeeperMetadataReportFactory) CreateMetadataReport(url *common.URL)
 		rootDir = rootDir + constant.PathSeparator
 	}
 
-	return &zookeeperMetadataReport{client: client, rootDir: rootDir}
+	return &zookeeperMetadataReport{client: client, rootDir: rootDir, mappingWatchers: make(map[string]chan struct{})}
 }
@@ -64,9 +64,7 @@ func (exporter *MetadataServiceExporter) Export(url *common.URL) error {
 		exporter.ServiceConfig = config.NewServiceConfigBuilder().
 			SetServiceID(constant.SimpleMetadataServiceName).
 			SetProtocolIDs(constant.DefaultProtocol).
-			AddRCProtocol(constant.DefaultProtocol, config.NewProtocolConfigBuilder().
-				SetName(constant.DefaultProtocol).
-				Build()).
+			AddRCProtocol(constant.DefaultProtocol, newMetadataServiceProtocol()).
 			SetRegistryIDs("N/A").
 			SetInterface(constant.MetadataServiceName).
 			SetGroup(config.GetApplicationConfig().Name).
@@ -84,6 +82,25 @@ func (exporter *MetadataServiceExporter) Export(url *common.URL) error {
 	return nil
 }
 
+// newMetadataServiceProtocol returns the dubbo protocol of the metadataService, which binds and advertises the
+// same address as the dubbo protocol of the services, on the configured port of the metadataService or the port
+// of the dubbo protocol so that the metadataService shares its server
+func newMetadataServiceProtocol() *config.ProtocolConfig {
+	builder := config.NewProtocolConfigBuilder().SetName(constant.DefaultProtocol)
+	var port string
+	for _, protocol := range config.GetRootConfig().Protocols {
+		if protocol.Name == constant.DefaultProtocol {
+			builder.SetIp(protocol.Ip).SetHost(protocol.Host)
+			port = protocol.Port
+			break
+		}
+	}
+	if servicePort := config.GetApplicationConfig().MetadataServicePort; len(servicePort) != 0 {
+		port = servicePort
+	}
+	return builder.SetPort(port).Build()
+}
+
 // Unexport will unexport the metadataService
 func (exporter *MetadataServiceExporter) Unexport() {
 	if exporter.IsExported() {
@@ -71,6 +71,24 @@ func TestConfigurableExporter(t *testing.T) {
 	})
 }
 
+func TestNewMetadataServiceProtocol(t *testing.T) {
+	config.SetRootConfig(config.RootConfig{
+		Application: &config.ApplicationConfig{Name: "dubbo"},
+		Protocols: map[string]*config.ProtocolConfig{
+			"tri":   {Name: "tri", Port: "20000"},
+			"dubbo": {Name: "dubbo", Ip: "10.0.0.1", Host: "1.2.3.4", Port: "20880"},
+		},
+	})
+	// shares the server of the dubbo protocol
+	protocol := newMetadataServiceProtocol()
+	assert.Equal(t, "20880", protocol.Port)
+	assert.Equal(t, "10.0.0.1", protocol.Ip)
+	assert.Equal(t, "1.2.3.4", protocol.Host)
+
+	config.GetApplicationConfig().MetadataServicePort = "20881"
+	assert.Equal(t, "20881", newMetadataServiceProtocol().Port)
+}
+
 // mockInitProviderWithSingleRegistry will init a mocked providerConfig
 func mockInitProviderWithSingleRegistry() {
 	providerConfig := config.NewProviderConfigBuilder().AddService("MockService", config.NewServiceConfigBuilder().Build()).Build()
@@ -26,6 +26,8 @@ import (
 	"github.com/Workiva/go-datastructures/slice/skip"
 
 	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
 )
 
 import (
@@ -233,13 +235,16 @@ func (mts *MetadataService) GetSubscribedURLs() ([]*common.URL, error) {
 // GetServiceDefinition can get service definition by interfaceName, group and version
 func (mts *MetadataService) GetServiceDefinition(interfaceName string, group string, version string) (string, error) {
 	serviceKey := definition.ServiceDescriperBuild(interfaceName, group, version)
-	v, _ := mts.serviceDefinitions.Load(serviceKey)
-	return v.(string), nil
+	return mts.GetServiceDefinitionByServiceKey(serviceKey)
 }
 
-// GetServiceDefinitionByServiceKey can get service definition by serviceKey
+// GetServiceDefinitionByServiceKey can get service definition by serviceKey, which is the json of the
+// ServiceDefinition of java
 func (mts *MetadataService) GetServiceDefinitionByServiceKey(serviceKey string) (string, error) {
-	v, _ := mts.serviceDefinitions.Load(serviceKey)
+	v, ok := mts.serviceDefinitions.Load(serviceKey)
+	if !ok {
+		return "", perrors.Errorf("the service definition of %s is not found", serviceKey)
+	}
 	return v.(string), nil
 }
 
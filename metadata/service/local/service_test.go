@@ -42,7 +42,7 @@ func TestMetadataService(t *testing.T) {
 	u2, err := common.NewURL(fmt.Sprintf(
 		"%v://127.0.0.1:20000/com.ikurento.user.UserProvider2?anyhost=true&"+
 			"application=BDTService&category=providers&default.timeout=10000&dubbo=dubbo-provider-golang-1.0.0&"+
-			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo+user-info+server&org=ikurento.com&"+
+			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo%%20user-info%%20server&org=ikurento.com&"+
 			"owner=ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245&group=%v&version=%v&bean.name=%v",
 		protocol, serviceName, group, version, beanName))
 	assert.NoError(t, err)
@@ -53,7 +53,7 @@ func TestMetadataService(t *testing.T) {
 	u3, err := common.NewURL(fmt.Sprintf(
 		"%v://127.0.0.1:20000/com.ikurento.user.UserProvider3?anyhost=true&"+
 			"application=BDTService&category=providers&default.timeout=10000&dubbo=dubbo-provider-golang-1.0.0&"+
-			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo+user-info+server&org=ikurento.com&"+
+			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo%%20user-info%%20server&org=ikurento.com&"+
 			"owner=ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245&group=%v&version=%v&bean.name=%v",
 		protocol, serviceName, group, version, beanName))
 	assert.NoError(t, err)
@@ -64,7 +64,7 @@ func TestMetadataService(t *testing.T) {
 	u, err := common.NewURL(fmt.Sprintf(
 		"%v://127.0.0.1:20000/com.ikurento.user.UserProvider1?anyhost=true&"+
 			"application=BDTService&category=providers&default.timeout=10000&dubbo=dubbo-provider-golang-1.0.0&"+
-			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo+user-info+server&org=ikurento.com&"+
+			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo%%20user-info%%20server&org=ikurento.com&"+
 			"owner=ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245&group=%v&version=%v&bean.name=%v",
 		protocol, serviceName, group, version, beanName))
 	assert.NoError(t, err)
@@ -107,8 +107,12 @@ func TestMetadataService(t *testing.T) {
 		"\"owner\":\"ZX\",\"pid\":\"1447\",\"revision\":\"0.0.1\",\"side\":\"provider\"," +
 		"\"timeout\":\"3000\",\"timestamp\":\"1556509797245\",\"version\":\"0.0.1\"}," +
 		"\"canonicalName\":\"com.ikurento.user.UserProvider\",\"codeSource\":\"\"," +
-		"\"methods\":[{\"name\":\"GetUser\",\"parameterTypes\":[\"slice\"],\"returnType\":\"ptr\"," +
-		"\"parameters\":null}],\"types\":null}"
+		"\"methods\":[{\"name\":\"GetUser\",\"parameterTypes\":[\"java.lang.Object[]\"]," +
+		"\"returnType\":\"com.ikurento.user.User\",\"annotations\":[]}]," +
+		"\"types\":[{\"type\":\"java.lang.Object[]\",\"items\":[\"java.lang.Object\"]},{\"type\":\"java.lang.Object\"}," +
+		"{\"type\":\"com.ikurento.user.User\",\"properties\":{\"age\":\"int\",\"iD\":\"java.lang.String\"," +
+		"\"name\":\"java.lang.String\",\"time\":\"java.util.Date\"}},{\"type\":\"java.lang.String\"}," +
+		"{\"type\":\"int\"},{\"type\":\"java.util.Date\"}],\"annotations\":[]}"
 	def1, err := mts.GetServiceDefinition(serviceName, group, version)
 	assert.Equal(t, expected, def1)
 	assert.NoError(t, err)
@@ -116,4 +120,6 @@ func TestMetadataService(t *testing.T) {
 	def2, err := mts.GetServiceDefinitionByServiceKey(serviceKey)
 	assert.Equal(t, expected, def2)
 	assert.NoError(t, err)
+	_, err = mts.GetServiceDefinition("com.ikurento.user.Unknown", group, version)
+	assert.Error(t, err)
 }
@@ -76,25 +76,46 @@ func GetRemoteMetadataService() (service.RemoteMetadataService, error) {
 	return remoteMetadataServiceInstance, err
 }
 
-// PublishMetadata publishes the metadata info of @service to remote metadata center
+// PublishMetadata publishes the metadata info of @service to remote metadata center.
+// It's skipped when the revision is unchanged, both since the last publishing and in the metadata center,
+// so that restarting with the same services doesn't publish the same metadata again
 func (s *MetadataService) PublishMetadata(service string) {
 	info, err := s.MetadataService.GetMetadataInfo("")
 	if err != nil {
 		logger.Errorf("GetMetadataInfo error[%v]", err)
 		return
 	}
-	if info.HasReported() {
+	if info == nil || info.HasReported() {
 		return
 	}
-	id := identifier.NewSubscriberMetadataIdentifier(service, info.CalAndGetRevision())
-	err = s.delegateReport.PublishAppMetadata(id, info)
-	if err != nil {
+	revision := info.CalAndGetRevision()
+	if s.exportedRevision.Load() == revision {
+		info.MarkReported()
+		return
+	}
+	id := identifier.NewSubscriberMetadataIdentifier(service, revision)
+	if published, err := s.delegateReport.GetAppMetadata(id); err == nil && published != nil && published.Revision == revision {
+		logger.Infof("The metadata of revision %s has been published, skip it", revision)
+	} else if err = s.delegateReport.PublishAppMetadata(id, info); err != nil {
 		logger.Errorf("Publishing metadata to error[%v]", err)
 		return
 	}
+	s.exportedRevision.Store(revision)
 	info.MarkReported()
 }
 
+// UnpublishServiceDefinition will call remote metadata's RemoveServiceMetadata to remove the url info of the provider,
+// or RemoveConsumerMetadata to remove the parameters of the consumer
+func (s *MetadataService) UnpublishServiceDefinition(url *common.URL) error {
+	if len(url.GetParam(constant.InterfaceKey, "")) == 0 {
+		return nil
+	}
+	if common.RoleType(common.PROVIDER).Role() == url.GetParam(constant.SideKey, "") {
+		return s.delegateReport.RemoveServiceMetadata(identifier.NewServiceMetadataIdentifier(url))
+	}
+	return s.delegateReport.RemoveConsumerMetadata(newConsumerMetadataIdentifier(url))
+}
+
 // GetMetadata get the medata info of service from report
 func (s *MetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
 	revision := instance.GetMetadata()[constant.ExportedServicesRevisionPropertyName]
@@ -111,6 +132,7 @@ func (s *MetadataService) PublishServiceDefinition(url *common.URL) error {
 			sv := common.ServiceMap.GetServiceByServiceKey(url.Protocol, url.ServiceKey())
 			sd := definition.BuildFullDefinition(*sv, url)
 			id := &identifier.MetadataIdentifier{
+				Application: url.GetParam(constant.ApplicationKey, ""),
 				BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
 					ServiceInterface: interfaceName,
 					Version:          url.GetParam(constant.VersionKey, ""),
@@ -119,7 +141,8 @@ func (s *MetadataService) PublishServiceDefinition(url *common.URL) error {
 				},
 			}
 			s.delegateReport.StoreProviderMetadata(id, sd)
-			return nil
+			// the url is read by GetExportedURLs, it's overwritten when the service is exported again
+			return s.delegateReport.SaveServiceMetadata(identifier.NewServiceMetadataIdentifier(url), url)
 		}
 		logger.Errorf("publishProvider interfaceName is empty . providerUrl:%v ", url)
 	} else {
@@ -128,17 +151,23 @@ func (s *MetadataService) PublishServiceDefinition(url *common.URL) error {
 			params[key] = value
 			return true
 		})
-		id := &identifier.MetadataIdentifier{
-			BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
-				ServiceInterface: interfaceName,
-				Version:          url.GetParam(constant.VersionKey, ""),
-				Group:            url.GetParam(constant.GroupKey, constant.Dubbo),
-				Side:             url.GetParam(constant.SideKey, "consumer"),
-			},
-		}
-		s.delegateReport.StoreConsumerMetadata(id, params)
+		s.delegateReport.StoreConsumerMetadata(newConsumerMetadataIdentifier(url), params)
 		return nil
 	}
 
 	return nil
 }
+
+// newConsumerMetadataIdentifier returns the identifier of the consumer @url, which is the same as Dubbo's so that
+// dubbo-admin finds the consumers of the services in different languages
+func newConsumerMetadataIdentifier(url *common.URL) *identifier.MetadataIdentifier {
+	return &identifier.MetadataIdentifier{
+		Application: url.GetParam(constant.ApplicationKey, ""),
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: url.GetParam(constant.InterfaceKey, ""),
+			Version:          url.GetParam(constant.VersionKey, ""),
+			Group:            url.GetParam(constant.GroupKey, ""),
+			Side:             constant.Consumer,
+		},
+	}
+}
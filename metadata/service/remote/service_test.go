@@ -44,6 +44,7 @@ import (
 var (
 	serviceMetadata    = make(map[*identifier.ServiceMetadataIdentifier]*common.URL, 4)
 	subscribedMetadata = make(map[*identifier.SubscriberMetadataIdentifier]string, 4)
+	consumerMetadata   = make(map[string]string, 4)
 )
 
 func getMetadataReportFactory() factory.MetadataReportFactory {
@@ -82,7 +83,13 @@ func (metadataReport) StoreProviderMetadata(*identifier.MetadataIdentifier, stri
 	return nil
 }
 
-func (metadataReport) StoreConsumerMetadata(*identifier.MetadataIdentifier, string) error {
+func (metadataReport) StoreConsumerMetadata(id *identifier.MetadataIdentifier, params string) error {
+	consumerMetadata[id.GetIdentifierKey()] = params
+	return nil
+}
+
+func (metadataReport) RemoveConsumerMetadata(id *identifier.MetadataIdentifier) error {
+	delete(consumerMetadata, id.GetIdentifierKey())
 	return nil
 }
 
@@ -116,7 +123,7 @@ func (metadataReport) GetServiceDefinition(*identifier.MetadataIdentifier) (stri
 
 func TestMetadataService(t *testing.T) {
 	extension.SetMetadataReportFactory("mock", getMetadataReportFactory)
-	u, err := common.NewURL("mock://127.0.0.1:20000/?sync.report=true")
+	u, err := common.NewURL("mock://127.0.0.1:20000/?sync.report=true&report-consumer-definition=true")
 	assert.NoError(t, err)
 	instance.SetMetadataReportInstance(u)
 	mts, err := GetRemoteMetadataService()
@@ -136,7 +143,7 @@ func TestMockInmemoryProc(t *testing.T) {
 	u, err := common.NewURL(fmt.Sprintf(
 		"%v://127.0.0.1:20000/com.ikurento.user.UserProvider1?anyhost=true&"+
 			"application=BDTService&category=providers&default.timeout=10000&dubbo=dubbo-provider-golang-1.0.0&"+
-			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo+user-info+server&org=ikurento.com&"+
+			"environment=dev&interface=%v&ip=192.168.56.1&methods=GetUser&module=dubbogo%%20user-info%%20server&org=ikurento.com&"+
 			"owner=ZX&pid=1447&revision=0.0.1&side=provider&timeout=3000&timestamp=1556509797245&group=%v&version=%v&bean.name=%v",
 		protocol, serviceName, group, version, beanName))
 	assert.NoError(t, err)
@@ -159,11 +166,42 @@ func TestMockInmemoryProc(t *testing.T) {
 		"\"owner\":\"ZX\",\"pid\":\"1447\",\"revision\":\"0.0.1\",\"side\":\"provider\"," +
 		"\"timeout\":\"3000\",\"timestamp\":\"1556509797245\",\"version\":\"0.0.1\"}," +
 		"\"canonicalName\":\"com.ikurento.user.UserProvider\",\"codeSource\":\"\"," +
-		"\"methods\":[{\"name\":\"GetUser\",\"parameterTypes\":[\"slice\"],\"returnType\":\"ptr\"," +
-		"\"parameters\":null}],\"types\":null}"
+		"\"methods\":[{\"name\":\"GetUser\",\"parameterTypes\":[\"java.lang.Object[]\"]," +
+		"\"returnType\":\"com.ikurento.user.User\",\"annotations\":[]}]," +
+		"\"types\":[{\"type\":\"java.lang.Object[]\",\"items\":[\"java.lang.Object\"]},{\"type\":\"java.lang.Object\"}," +
+		"{\"type\":\"com.ikurento.user.User\",\"properties\":{\"age\":\"int\",\"iD\":\"java.lang.String\"," +
+		"\"name\":\"java.lang.String\",\"time\":\"java.util.Date\"}},{\"type\":\"java.lang.String\"}," +
+		"{\"type\":\"int\"},{\"type\":\"java.util.Date\"}],\"annotations\":[]}"
 	def1, _ := mts.GetServiceDefinition(serviceName, group, version)
 	assert.Equal(t, expected, def1)
 	serviceKey := definition.ServiceDescriperBuild(serviceName, group, version)
 	def2, _ := mts.GetServiceDefinitionByServiceKey(serviceKey)
 	assert.Equal(t, expected, def2)
 }
+
+func TestPublishConsumerDefinition(t *testing.T) {
+	mts, err := GetRemoteMetadataService()
+	assert.NoError(t, err)
+
+	u, err := common.NewURL("consumer://192.168.1.2/org.apache.dubbo.demo.DemoService?application=demo-consumer&" +
+		"dubbo=2.0.2&group=g1&interface=org.apache.dubbo.demo.DemoService&methods=sayHello,sayHelloAsync&pid=1234&" +
+		"release=3.0.7&side=consumer&sticky=false&timestamp=1650000000000&version=1.0.0")
+	assert.NoError(t, err)
+	assert.NoError(t, mts.PublishServiceDefinition(u))
+
+	// what Dubbo writes for the reference of the same parameters
+	key := "org.apache.dubbo.demo.DemoService:1.0.0:g1:consumer:demo-consumer"
+	expected := `{"side":"consumer","release":"3.0.7","methods":"sayHello,sayHelloAsync","dubbo":"2.0.2",` +
+		`"pid":"1234","interface":"org.apache.dubbo.demo.DemoService","version":"1.0.0","application":"demo-consumer",` +
+		`"sticky":"false","group":"g1","timestamp":"1650000000000"}`
+	assert.JSONEq(t, expected, consumerMetadata[key])
+
+	assert.NoError(t, mts.UnpublishServiceDefinition(u))
+	assert.NotContains(t, consumerMetadata, key)
+
+	u, err = common.NewURL("consumer://192.168.1.2/org.apache.dubbo.demo.DemoService?application=demo-consumer&" +
+		"interface=org.apache.dubbo.demo.DemoService&side=consumer")
+	assert.NoError(t, err)
+	assert.NoError(t, mts.PublishServiceDefinition(u))
+	assert.Contains(t, consumerMetadata, "org.apache.dubbo.demo.DemoService:::consumer:demo-consumer")
+}
@@ -29,4 +29,7 @@ type RemoteMetadataService interface {
 	GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error)
 	// PublishServiceDefinition will call remote metadata's StoreProviderMetadata to store url info and service definition
 	PublishServiceDefinition(url *common.URL) error
+	// UnpublishServiceDefinition will call remote metadata's RemoveServiceMetadata to remove the url info of the
+	// unexported service
+	UnpublishServiceDefinition(url *common.URL) error
 }
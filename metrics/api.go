@@ -20,6 +20,7 @@ package metrics
 import (
 	"encoding/json"
 	"sync"
+	"time"
 )
 
 import (
@@ -64,6 +65,20 @@ func Init(url *common.URL) {
 	})
 }
 
+// Enabled returns whether the metrics are enabled by the metrics config of the @url
+func Enabled(url *common.URL) bool {
+	return url.GetParamBool(constant.MetricsEnabledKey, false)
+}
+
+// CollectInterval returns the interval the sampling collectors sample at by the @url, 5s by default
+func CollectInterval(url *common.URL) time.Duration {
+	interval, err := common.ParseDuration(url.GetParam(constant.MetricsCollectIntervalKey, ""))
+	if err != nil || interval <= 0 {
+		return 5 * time.Second
+	}
+	return interval
+}
+
 // SetRegistry extend more MetricRegistry, default PrometheusRegistry
 func SetRegistry(name string, v func(*common.URL) MetricRegistry) {
 	registries[name] = v
@@ -165,6 +180,12 @@ type ObservableMetric interface {
 	Observe(float64)
 }
 
+// ExemplarObservableMetric is an ObservableMetric which can attach an exemplar, like the trace id, to the observation
+type ExemplarObservableMetric interface {
+	ObservableMetric
+	ObserveWithExemplar(v float64, exemplar map[string]string)
+}
+
 type BaseCollector struct {
 	R MetricRegistry
 }
@@ -277,6 +298,41 @@ func (d *DefaultRtVec) Record(labels map[string]string, v float64) {
 	d.metricRegistry.Rt(NewMetricIdByLabels(d.metricKey, labels), d.rtOpts).Observe(v)
 }
 
+// HistogramVec means a set of histograms with the same metricKey but different labels
+type HistogramVec interface {
+	Record(labels map[string]string, v float64)
+	RecordWithExemplar(labels map[string]string, v float64, exemplar map[string]string)
+}
+
+// NewHistogramVec create a HistogramVec default implementation.
+func NewHistogramVec(metricRegistry MetricRegistry, metricKey *MetricKey) HistogramVec {
+	return &DefaultHistogramVec{
+		metricRegistry: metricRegistry,
+		metricKey:      metricKey,
+	}
+}
+
+// DefaultHistogramVec is a default HistogramVec implementation.
+//
+// The exemplar is dropped if the histogram of the registry is not an ExemplarObservableMetric.
+type DefaultHistogramVec struct {
+	metricRegistry MetricRegistry
+	metricKey      *MetricKey
+}
+
+func (d *DefaultHistogramVec) Record(labels map[string]string, v float64) {
+	d.metricRegistry.Histogram(NewMetricIdByLabels(d.metricKey, labels)).Observe(v)
+}
+
+func (d *DefaultHistogramVec) RecordWithExemplar(labels map[string]string, v float64, exemplar map[string]string) {
+	h := d.metricRegistry.Histogram(NewMetricIdByLabels(d.metricKey, labels))
+	if eh, ok := h.(ExemplarObservableMetric); ok && len(exemplar) > 0 {
+		eh.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	h.Observe(v)
+}
+
 // labelsToString convert @labels to json format string for cache key
 func labelsToString(labels map[string]string) string {
 	labelsJson, err := json.Marshal(labels)
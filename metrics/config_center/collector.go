@@ -60,6 +60,7 @@ const (
 	Nacos     = "nacos"
 	Apollo    = "apollo"
 	Zookeeper = "zookeeper"
+	Etcd      = "etcd"
 )
 
 type ConfigCenterMetricEvent struct {
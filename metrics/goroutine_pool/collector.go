@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package goroutine_pool
+
+import (
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	activeWorkers = metrics.NewMetricKey("dubbo_goroutine_pool_active_workers", "Active Workers Of The Goroutine Pool")
+	workers       = metrics.NewMetricKey("dubbo_goroutine_pool_workers", "Started Workers Of The Goroutine Pool")
+	queueDepth    = metrics.NewMetricKey("dubbo_goroutine_pool_queue_depth", "Queued Tasks Of The Goroutine Pool")
+	panics        = metrics.NewMetricKey("dubbo_goroutine_pool_panics", "Panicked Tasks Of The Goroutine Pool")
+	rejected      = metrics.NewMetricKey("dubbo_goroutine_pool_rejected", "Rejected Tasks Of The Goroutine Pool")
+)
+
+func init() {
+	metrics.AddCollector("goroutine_pool", func(mr metrics.MetricRegistry, url *common.URL) {
+		c := &goroutinePoolCollector{r: mr}
+		go c.start(metrics.CollectInterval(url))
+	})
+}
+
+// goroutinePoolCollector samples the shared goroutine pools periodically
+type goroutinePoolCollector struct {
+	r metrics.MetricRegistry
+}
+
+func (c *goroutinePoolCollector) start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.sample()
+	}
+}
+
+func (c *goroutinePoolCollector) sample() {
+	for _, p := range gopool.GetPools() {
+		tags := metrics.GetApplicationLevel().Tags()
+		tags[constant.TagPool] = p.Name()
+		c.r.Gauge(metrics.NewMetricIdByLabels(activeWorkers, tags)).Set(float64(p.ActiveWorkers()))
+		c.r.Gauge(metrics.NewMetricIdByLabels(workers, tags)).Set(float64(p.Workers()))
+		c.r.Gauge(metrics.NewMetricIdByLabels(queueDepth, tags)).Set(float64(p.QueueDepth()))
+		c.r.Gauge(metrics.NewMetricIdByLabels(panics, tags)).Set(float64(p.Panics()))
+		c.r.Gauge(metrics.NewMetricIdByLabels(rejected, tags)).Set(float64(p.Rejected()))
+	}
+}
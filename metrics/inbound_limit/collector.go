@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inbound_limit
+
+import (
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+var (
+	throttled = metrics.NewMetricKey("dubbo_inbound_limit_throttled", "Requests Throttled By The Inbound Limit")
+	closed    = metrics.NewMetricKey("dubbo_inbound_limit_closed", "Connections Closed By The Inbound Limit")
+)
+
+func init() {
+	metrics.AddCollector("inbound_limit", func(mr metrics.MetricRegistry, url *common.URL) {
+		c := &inboundLimitCollector{r: mr}
+		go c.start(metrics.CollectInterval(url))
+	})
+}
+
+// inboundLimitCollector samples the counters of the inbound limiters of the started servers periodically
+type inboundLimitCollector struct {
+	r metrics.MetricRegistry
+}
+
+func (c *inboundLimitCollector) start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.sample()
+	}
+}
+
+func (c *inboundLimitCollector) sample() {
+	// the top talkers are not sampled, as the source ips are unbounded labels
+	all, _ := getty.InboundLimits(0, "")
+	for addr, stats := range all {
+		tags := metrics.GetApplicationLevel().Tags()
+		tags[constant.TagServer] = addr
+		c.r.Gauge(metrics.NewMetricIdByLabels(closed, tags)).Set(float64(stats.Closed))
+		for limit, n := range stats.Throttled {
+			limitTags := metrics.GetApplicationLevel().Tags()
+			limitTags[constant.TagServer] = addr
+			limitTags[constant.TagLimit] = limit
+			c.r.Gauge(metrics.NewMetricIdByLabels(throttled, limitTags)).Set(float64(n))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lifecycle collects the metrics of the events of the lifecycle of the services, the references and the
+// registries published by the eventbus.
+package lifecycle
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var (
+	events     = metrics.NewMetricKey("dubbo_lifecycle_events_total", "Total Lifecycle Events")
+	services   = metrics.NewMetricKey("dubbo_services_exported", "Services Exported")
+	references = metrics.NewMetricKey("dubbo_references_ready", "References Ready")
+)
+
+func init() {
+	metrics.AddCollector("lifecycle", func(mr metrics.MetricRegistry, _ *common.URL) {
+		c := newLifecycleCollector(mr)
+		eventbus.SubscribeAsync(c.onEvent)
+	})
+}
+
+type lifecycleCollector struct {
+	r          metrics.MetricRegistry
+	services   metrics.GaugeMetric
+	references metrics.GaugeMetric
+}
+
+func newLifecycleCollector(mr metrics.MetricRegistry) *lifecycleCollector {
+	return &lifecycleCollector{
+		r:          mr,
+		services:   mr.Gauge(metrics.NewMetricIdByLabels(services, metrics.GetApplicationLevel().Tags())),
+		references: mr.Gauge(metrics.NewMetricIdByLabels(references, metrics.GetApplicationLevel().Tags())),
+	}
+}
+
+func (c *lifecycleCollector) onEvent(event eventbus.Event) {
+	tags := metrics.GetApplicationLevel().Tags()
+	tags[constant.TagEvent] = string(event.Type())
+	c.r.Counter(metrics.NewMetricIdByLabels(events, tags)).Inc()
+
+	switch event.Type() {
+	case eventbus.TypeServiceExported:
+		c.services.Inc()
+	case eventbus.TypeServiceUnexported:
+		c.services.Dec()
+	case eventbus.TypeReferenceReady:
+		c.references.Inc()
+	case eventbus.TypeReferenceDestroyed:
+		c.references.Dec()
+	}
+}
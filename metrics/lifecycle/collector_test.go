@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+type gauge struct {
+	metrics.GaugeMetric
+	value float64
+}
+
+func (g *gauge) Inc() {
+	g.value++
+}
+
+func (g *gauge) Dec() {
+	g.value--
+}
+
+type counter struct {
+	metrics.CounterMetric
+	value float64
+}
+
+func (c *counter) Inc() {
+	c.value++
+}
+
+// valueRegistry keeps the gauges by the names and the counters by the events, the other methods of
+// metrics.MetricRegistry are not implemented
+type valueRegistry struct {
+	metrics.MetricRegistry
+	gauges   map[string]*gauge
+	counters map[string]*counter
+}
+
+func (r *valueRegistry) Gauge(id *metrics.MetricId) metrics.GaugeMetric {
+	r.gauges[id.Name] = &gauge{}
+	return r.gauges[id.Name]
+}
+
+func (r *valueRegistry) Counter(id *metrics.MetricId) metrics.CounterMetric {
+	event := id.Tags[constant.TagEvent]
+	if _, ok := r.counters[event]; !ok {
+		r.counters[event] = &counter{}
+	}
+	return r.counters[event]
+}
+
+func TestLifecycleCollector(t *testing.T) {
+	r := &valueRegistry{gauges: make(map[string]*gauge), counters: make(map[string]*counter)}
+	c := newLifecycleCollector(r)
+	for _, event := range []eventbus.Event{
+		eventbus.ServiceExported{}, eventbus.ServiceExported{}, eventbus.ServiceUnexported{},
+		eventbus.ReferenceReady{}, eventbus.RegistryConnected{}, eventbus.ShutdownInitiated{},
+	} {
+		c.onEvent(event)
+	}
+	assert.Equal(t, float64(1), r.gauges["dubbo_services_exported"].value)
+	assert.Equal(t, float64(1), r.gauges["dubbo_references_ready"].value)
+	assert.Equal(t, float64(2), r.counters["service-exported"].value)
+	assert.Equal(t, float64(1), r.counters["shutdown-initiated"].value)
+	assert.Equal(t, float64(1), r.counters["registry-connected"].value)
+}
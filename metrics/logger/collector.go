@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	dubboLogger "dubbo.apache.org/dubbo-go/v3/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+var entries = metrics.NewMetricKey("dubbo_logger_entries_total", "The number of entries written by the logger")
+
+func init() {
+	metrics.AddCollector("logger", func(mr metrics.MetricRegistry, url *common.URL) {
+		c := &loggerCollector{r: mr, last: make(map[string]int64)}
+		go c.start(metrics.CollectInterval(url))
+	})
+}
+
+// loggerCollector samples the entry counts of the logger periodically
+type loggerCollector struct {
+	r    metrics.MetricRegistry
+	last map[string]int64
+}
+
+func (c *loggerCollector) start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.sample()
+	}
+}
+
+// sample adds the entries written since the last sample to the counters
+func (c *loggerCollector) sample() {
+	for level, count := range dubboLogger.EntryCounts() {
+		tags := metrics.GetApplicationLevel().Tags()
+		tags[constant.TagLevel] = level
+		c.r.Counter(metrics.NewMetricIdByLabels(entries, tags)).Add(float64(count - c.last[level]))
+		c.last[level] = count
+	}
+}
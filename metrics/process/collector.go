@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package process collects the runtime metrics of the process, which are the goroutines, the heap, the gc, the open
+// connections of the providers and the consumers, and the services subscribed from the registries. They're sampled
+// at the collect interval of the metrics config if the metrics are enabled.
+package process
+
+import (
+	"runtime"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+var (
+	goroutines    = metrics.NewMetricKey("dubbo_process_goroutines", "Goroutines Of The Process")
+	heapAlloc     = metrics.NewMetricKey("dubbo_process_heap_alloc_bytes", "Bytes Of The Allocated Heap Objects")
+	heapInuse     = metrics.NewMetricKey("dubbo_process_heap_inuse_bytes", "Bytes Of The In-Use Heap Spans")
+	heapObjects   = metrics.NewMetricKey("dubbo_process_heap_objects", "Allocated Heap Objects")
+	sysBytes      = metrics.NewMetricKey("dubbo_process_sys_bytes", "Bytes Obtained From The OS")
+	gcCount       = metrics.NewMetricKey("dubbo_process_gc_count", "Completed GC Cycles")
+	gcPause       = metrics.NewMetricKey("dubbo_process_gc_pause_seconds", "Cumulative Seconds Of The GC Pauses")
+	connections   = metrics.NewMetricKey("dubbo_process_connections", "Open Connections Of The Providers Or The Consumers")
+	subscriptions = metrics.NewMetricKey("dubbo_process_registry_subscriptions", "Services Subscribed From The Registries")
+)
+
+func init() {
+	metrics.AddCollector("process", func(mr metrics.MetricRegistry, url *common.URL) {
+		if !metrics.Enabled(url) {
+			return
+		}
+		c := newProcessCollector(mr)
+		go c.start(metrics.CollectInterval(url))
+	})
+}
+
+// processCollector samples the process periodically, the gauges are resolved once so that a sample allocates nothing
+type processCollector struct {
+	memStats runtime.MemStats
+
+	goroutines          metrics.GaugeMetric
+	heapAlloc           metrics.GaugeMetric
+	heapInuse           metrics.GaugeMetric
+	heapObjects         metrics.GaugeMetric
+	sysBytes            metrics.GaugeMetric
+	gcCount             metrics.GaugeMetric
+	gcPause             metrics.GaugeMetric
+	providerConnections metrics.GaugeMetric
+	consumerConnections metrics.GaugeMetric
+	subscriptions       metrics.GaugeMetric
+}
+
+func newProcessCollector(mr metrics.MetricRegistry) *processCollector {
+	gauge := func(key *metrics.MetricKey) metrics.GaugeMetric {
+		return mr.Gauge(metrics.NewMetricIdByLabels(key, metrics.GetApplicationLevel().Tags()))
+	}
+	connectionGauge := func(side string) metrics.GaugeMetric {
+		tags := metrics.GetApplicationLevel().Tags()
+		tags[constant.TagSide] = side
+		return mr.Gauge(metrics.NewMetricIdByLabels(connections, tags))
+	}
+	return &processCollector{
+		goroutines:          gauge(goroutines),
+		heapAlloc:           gauge(heapAlloc),
+		heapInuse:           gauge(heapInuse),
+		heapObjects:         gauge(heapObjects),
+		sysBytes:            gauge(sysBytes),
+		gcCount:             gauge(gcCount),
+		gcPause:             gauge(gcPause),
+		providerConnections: connectionGauge(constant.SideProvider),
+		consumerConnections: connectionGauge(constant.SideConsumer),
+		subscriptions:       gauge(subscriptions),
+	}
+}
+
+func (c *processCollector) start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.sample()
+	}
+}
+
+func (c *processCollector) sample() {
+	runtime.ReadMemStats(&c.memStats)
+	c.goroutines.Set(float64(runtime.NumGoroutine()))
+	c.heapAlloc.Set(float64(c.memStats.HeapAlloc))
+	c.heapInuse.Set(float64(c.memStats.HeapInuse))
+	c.heapObjects.Set(float64(c.memStats.HeapObjects))
+	c.sysBytes.Set(float64(c.memStats.Sys))
+	c.gcCount.Set(float64(c.memStats.NumGC))
+	c.gcPause.Set(float64(c.memStats.PauseTotalNs) / float64(time.Second))
+	c.providerConnections.Set(float64(remoting.Connections(constant.SideProvider)))
+	c.consumerConnections.Set(float64(remoting.Connections(constant.SideConsumer)))
+	c.subscriptions.Set(float64(registry.Subscriptions()))
+}
@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package process
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type gauge struct {
+	metrics.GaugeMetric
+	value float64
+}
+
+func (g *gauge) Set(v float64) {
+	g.value = v
+}
+
+// gaugeRegistry keeps the gauges by the names and the sides, the other methods of metrics.MetricRegistry are not
+// implemented
+type gaugeRegistry struct {
+	metrics.MetricRegistry
+	gauges map[string]*gauge
+}
+
+func (r *gaugeRegistry) Gauge(id *metrics.MetricId) metrics.GaugeMetric {
+	key := id.Name + id.Tags[constant.TagSide]
+	if _, ok := r.gauges[key]; !ok {
+		r.gauges[key] = &gauge{}
+	}
+	return r.gauges[key]
+}
+
+func TestProcessCollector(t *testing.T) {
+	r := &gaugeRegistry{gauges: make(map[string]*gauge)}
+	c := newProcessCollector(r)
+	c.sample()
+	assert.True(t, r.gauges["dubbo_process_goroutines"].value > 0)
+	assert.True(t, r.gauges["dubbo_process_heap_alloc_bytes"].value > 0)
+	assert.True(t, r.gauges["dubbo_process_sys_bytes"].value > 0)
+	provider := r.gauges["dubbo_process_connections"+constant.SideProvider].value
+	consumer := r.gauges["dubbo_process_connections"+constant.SideConsumer].value
+	subscribed := r.gauges["dubbo_process_registry_subscriptions"].value
+
+	remoting.AddConnections(constant.SideProvider, 2)
+	remoting.AddConnections(constant.SideConsumer, 1)
+	registry.AddSubscriptions(1)
+	c.sample()
+	assert.Equal(t, provider+2, r.gauges["dubbo_process_connections"+constant.SideProvider].value)
+	assert.Equal(t, consumer+1, r.gauges["dubbo_process_connections"+constant.SideConsumer].value)
+	assert.Equal(t, subscribed+1, r.gauges["dubbo_process_registry_subscriptions"].value)
+
+	remoting.AddConnections(constant.SideProvider, -2)
+	remoting.AddConnections(constant.SideConsumer, -1)
+	registry.AddSubscriptions(-1)
+	c.sample()
+	assert.Equal(t, provider, r.gauges["dubbo_process_connections"+constant.SideProvider].value)
+	assert.Equal(t, consumer, r.gauges["dubbo_process_connections"+constant.SideConsumer].value)
+	assert.Equal(t, subscribed, r.gauges["dubbo_process_registry_subscriptions"].value)
+
+	assert.Equal(t, float64(0), testing.AllocsPerRun(10, c.sample))
+}
@@ -21,6 +21,8 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -73,8 +75,10 @@ func (p *promMetricRegistry) getOrComputeVec(key string, supplier func() prom.Co
 func (p *promMetricRegistry) Counter(m *metrics.MetricId) metrics.CounterMetric {
 	vec := p.getOrComputeVec(m.Name, func() prom.Collector {
 		return prom.NewCounterVec(prom.CounterOpts{
-			Name: m.Name,
-			Help: m.Desc,
+			Namespace:   p.namespace(),
+			Name:        m.Name,
+			Help:        m.Desc,
+			ConstLabels: p.constLabels(m.TagKeys()),
 		}, m.TagKeys())
 	}).(*prom.CounterVec)
 	return vec.With(m.Tags)
@@ -83,8 +87,10 @@ func (p *promMetricRegistry) Counter(m *metrics.MetricId) metrics.CounterMetric
 func (p *promMetricRegistry) Gauge(m *metrics.MetricId) metrics.GaugeMetric {
 	vec := p.getOrComputeVec(m.Name, func() prom.Collector {
 		return prom.NewGaugeVec(prom.GaugeOpts{
-			Name: m.Name,
-			Help: m.Desc,
+			Namespace:   p.namespace(),
+			Name:        m.Name,
+			Help:        m.Desc,
+			ConstLabels: p.constLabels(m.TagKeys()),
 		}, m.TagKeys())
 	}).(*prom.GaugeVec)
 	return vec.With(m.Tags)
@@ -93,18 +99,76 @@ func (p *promMetricRegistry) Gauge(m *metrics.MetricId) metrics.GaugeMetric {
 func (p *promMetricRegistry) Histogram(m *metrics.MetricId) metrics.ObservableMetric {
 	vec := p.getOrComputeVec(m.Name, func() prom.Collector {
 		return prom.NewHistogramVec(prom.HistogramOpts{
-			Name: m.Name,
-			Help: m.Desc,
+			Namespace:   p.namespace(),
+			Name:        m.Name,
+			Help:        m.Desc,
+			ConstLabels: p.constLabels(m.TagKeys()),
+			Buckets:     p.histogramBuckets(),
 		}, m.TagKeys())
 	}).(*prom.HistogramVec)
-	return vec.With(m.Tags)
+	return &histogram{vec.With(m.Tags)}
+}
+
+// namespace returns the prefix of the metric names configured by metrics.namespace, it is joined to the names by "_"
+func (p *promMetricRegistry) namespace() string {
+	return p.url.GetParam(constant.MetricsNamespaceKey, "")
+}
+
+// constLabels returns the labels configured by metrics.const.label.*, which are added to all the series of a metric.
+// The labels named like the @tagKeys of the metric are dropped, so that the tags of the series take precedence.
+func (p *promMetricRegistry) constLabels(tagKeys []string) prom.Labels {
+	labels := prom.Labels{}
+	p.url.RangeParams(func(key, value string) bool {
+		if strings.HasPrefix(key, constant.MetricsConstLabelKeyPrefix) {
+			labels[strings.TrimPrefix(key, constant.MetricsConstLabelKeyPrefix)] = value
+		}
+		return true
+	})
+	for _, key := range tagKeys {
+		delete(labels, key)
+	}
+	return labels
+}
+
+// histogramBuckets returns the buckets configured by histogram.buckets, like "0.005,0.01,0.1,1",
+// the default buckets of prometheus are used if it is absent or invalid
+func (p *promMetricRegistry) histogramBuckets() []float64 {
+	value := p.url.GetParam(constant.HistogramBucketsKey, "")
+	if value == "" {
+		return prom.DefBuckets
+	}
+	var buckets []float64
+	for _, item := range strings.Split(value, ",") {
+		bucket, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+		if err != nil || (len(buckets) > 0 && bucket <= buckets[len(buckets)-1]) {
+			logger.Warnf("invalid histogram buckets %q, the buckets must be increasing numbers, use the default buckets", value)
+			return prom.DefBuckets
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// histogram is the histogram of prometheus which can be observed with an exemplar
+type histogram struct {
+	prom.Observer
+}
+
+func (h *histogram) ObserveWithExemplar(v float64, exemplar map[string]string) {
+	if eo, ok := h.Observer.(prom.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(v, exemplar)
+		return
+	}
+	h.Observe(v)
 }
 
 func (p *promMetricRegistry) Summary(m *metrics.MetricId) metrics.ObservableMetric {
 	vec := p.getOrComputeVec(m.Name, func() prom.Collector {
 		return prom.NewSummaryVec(prom.SummaryOpts{
-			Name: m.Name,
-			Help: m.Desc,
+			Namespace:   p.namespace(),
+			Name:        m.Name,
+			Help:        m.Desc,
+			ConstLabels: p.constLabels(m.TagKeys()),
 		}, m.TagKeys())
 	}).(*prom.SummaryVec)
 	return vec.With(m.Tags)
@@ -123,8 +187,10 @@ func (p *promMetricRegistry) Rt(m *metrics.MetricId, opts *metrics.RtOpts) metri
 		}
 		supplier = func() prom.Collector {
 			return NewAggRtVec(&RtOpts{
+				Namespace:         p.namespace(),
 				Name:              m.Name,
 				Help:              m.Desc,
+				ConstLabels:       p.constLabels(m.TagKeys()),
 				bucketNum:         opts.BucketNum,
 				timeWindowSeconds: opts.TimeWindowSeconds,
 			}, m.TagKeys())
@@ -132,8 +198,10 @@ func (p *promMetricRegistry) Rt(m *metrics.MetricId, opts *metrics.RtOpts) metri
 	} else {
 		supplier = func() prom.Collector {
 			return NewRtVec(&RtOpts{
-				Name: m.Name,
-				Help: m.Desc,
+				Namespace:   p.namespace(),
+				Name:        m.Name,
+				Help:        m.Desc,
+				ConstLabels: p.constLabels(m.TagKeys()),
 			}, m.TagKeys())
 		}
 	}
@@ -145,11 +213,11 @@ func (p *promMetricRegistry) Export() {
 	if p.url.GetParamBool(constant.PrometheusExporterEnabledKey, false) {
 		go func() {
 			mux := http.NewServeMux()
-			path := p.url.GetParam(constant.PrometheusDefaultMetricsPath, constant.PrometheusDefaultMetricsPath)
+			path := p.url.GetParam(constant.PrometheusExporterMetricsPathKey, constant.PrometheusDefaultMetricsPath)
 			port := p.url.GetParam(constant.PrometheusExporterMetricsPortKey, constant.PrometheusDefaultMetricsPort)
-			mux.Handle(path, promhttp.InstrumentMetricHandler(p.r, promhttp.HandlerFor(p.gather, promhttp.HandlerOpts{})))
+			mux.Handle(path, p.handler())
 			srv := &http.Server{Addr: ":" + port, Handler: mux}
-			extension.AddCustomShutdownCallback(func() {
+			onShutdown(func() {
 				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 				if err := srv.Shutdown(ctx); nil != err {
@@ -190,9 +258,22 @@ func (p *promMetricRegistry) Export() {
 				}
 			}
 		}()
+		// push the metrics collected since the last push before shutdown
+		onShutdown(func() {
+			ticker.Stop()
+			if err := pusher.Add(); err != nil {
+				logger.Errorf("push metric data to prometheus push gateway on shutdown error %v", err)
+			}
+		})
 	}
 }
 
+// handler serves the metrics in the text format, or in the OpenMetrics format which carries the exemplars
+// if the scraper accepts it
+func (p *promMetricRegistry) handler() http.Handler {
+	return promhttp.InstrumentMetricHandler(p.r, promhttp.HandlerFor(p.gather, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+}
+
 func (p *promMetricRegistry) Scrape() (string, error) {
 	gathering, err := p.gather.Gather()
 	if err != nil {
@@ -206,3 +287,12 @@ func (p *promMetricRegistry) Scrape() (string, error) {
 	}
 	return out.String(), nil
 }
+
+// onShutdown runs @f after the application is shut down
+func onShutdown(f func()) {
+	extension.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		if event == extension.LifecycleAfterShutdown {
+			f()
+		}
+	}))
+}
@@ -18,8 +18,13 @@
 package prometheus
 
 import (
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -35,6 +40,9 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/metrics/rpc"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 var (
@@ -86,6 +94,40 @@ func TestPromMetricRegistryHistogram(t *testing.T) {
 	assert.Contains(t, text, `dubbo_request_count{app="dubbo",version="1.0.0"} 1`)
 }
 
+func TestPromMetricRegistryHistogramBuckets(t *testing.T) {
+	bucketsURL := url.Clone()
+	bucketsURL.SetParam(constant.HistogramBucketsKey, "0.1, 1,10")
+	p := NewPromMetricRegistry(prom.NewRegistry(), bucketsURL)
+	p.Histogram(metricId).Observe(0.5)
+	text, err := p.Scrape()
+	assert.Nil(t, err)
+	assert.Contains(t, text, `dubbo_request_bucket{app="dubbo",version="1.0.0",le="0.1"} 0`)
+	assert.Contains(t, text, `dubbo_request_bucket{app="dubbo",version="1.0.0",le="1"} 1`)
+	assert.Contains(t, text, `dubbo_request_bucket{app="dubbo",version="1.0.0",le="10"} 1`)
+	assert.NotContains(t, text, `le="0.005"`)
+
+	// not increasing, fall back to the default buckets
+	bucketsURL.SetParam(constant.HistogramBucketsKey, "1,0.1")
+	p = NewPromMetricRegistry(prom.NewRegistry(), bucketsURL)
+	p.Histogram(metricId).Observe(0.5)
+	text, err = p.Scrape()
+	assert.Nil(t, err)
+	assert.Contains(t, text, `le="0.005"`)
+}
+
+func TestPromMetricRegistryHistogramExemplar(t *testing.T) {
+	p := NewPromMetricRegistry(prom.NewRegistry(), url)
+	h, ok := p.Histogram(metricId).(metrics.ExemplarObservableMetric)
+	assert.True(t, ok)
+	h.ObserveWithExemplar(0.2, map[string]string{constant.TagTraceId: "4bf92f3577b34da6a3ce929d0e0e4736"})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text")
+	rec := httptest.NewRecorder()
+	p.handler().ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `dubbo_request_bucket{app="dubbo",version="1.0.0",le="0.25"} 1 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.2`)
+}
+
 func TestPromMetricRegistrySummary(t *testing.T) {
 	p := NewPromMetricRegistry(prom.NewRegistry(), url)
 	p.Summary(metricId).Observe(100)
@@ -120,6 +162,23 @@ func TestPromMetricRegistryRt(t *testing.T) {
 	assert.Contains(t, text, "# HELP dubbo_request_min_milliseconds_aggregate The minimum request\n# TYPE dubbo_request_min_milliseconds_aggregate gauge\ndubbo_request_min_milliseconds_aggregate{app=\"dubbo\",version=\"1.0.0\"} 0")
 }
 
+func TestPromMetricRegistryNamespaceAndConstLabels(t *testing.T) {
+	labelsURL := url.Clone()
+	labelsURL.SetParam(constant.MetricsNamespaceKey, "shop")
+	labelsURL.SetParam(constant.MetricsConstLabelKeyPrefix+"zone", "hz")
+	labelsURL.SetParam(constant.MetricsConstLabelKeyPrefix+"version", "2.0.0")
+	p := NewPromMetricRegistry(prom.NewRegistry(), labelsURL)
+	p.Counter(metricId).Inc()
+	p.Gauge(&metrics.MetricId{Name: "dubbo_threads", Desc: "threads"}).Set(8)
+	p.Rt(&metrics.MetricId{Name: "dubbo_rt", Desc: "rt", Tags: tags}, &metrics.RtOpts{}).Observe(10)
+	text, err := p.Scrape()
+	assert.Nil(t, err)
+	// the version tag of the series takes precedence over the const label
+	assert.Contains(t, text, `shop_dubbo_request{app="dubbo",version="1.0.0",zone="hz"} 1`)
+	assert.Contains(t, text, `shop_dubbo_threads{version="2.0.0",zone="hz"} 8`)
+	assert.Contains(t, text, `shop_dubbo_rt_last{app="dubbo",version="1.0.0",zone="hz"} 10`)
+}
+
 func TestPromMetricRegistryCounterConcurrent(t *testing.T) {
 	p := NewPromMetricRegistry(prom.NewRegistry(), url)
 	var wg sync.WaitGroup
@@ -177,6 +236,68 @@ func TestPromMetricRegistryExport(t *testing.T) {
 	assert.Contains(t, text, "dubbo_request_avg")
 }
 
+func TestRPCMetricsScrape(t *testing.T) {
+	l, err := net.Listen("tcp", ":0")
+	assert.Nil(t, err)
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	assert.Nil(t, l.Close())
+	metrics.Init(common.NewURLWithOptions(
+		common.WithProtocol(constant.ProtocolPrometheus),
+		common.WithParamsValue(constant.PrometheusExporterEnabledKey, "true"),
+		common.WithParamsValue(constant.PrometheusExporterMetricsPortKey, port),
+		common.WithParamsValue(constant.PrometheusExporterMetricsPathKey, "/rpc/metrics"),
+		common.WithParamsValue(constant.HistogramBucketsKey, "0.01,0.1,1"),
+	))
+	scrape := func() string {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:"+port+"/rpc/metrics", nil)
+		assert.Nil(t, err)
+		req.Header.Set("Accept", "application/openmetrics-text")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		bodyBytes, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return string(bodyBytes)
+	}
+	newInvoker := func(service string) protocol.Invoker {
+		return protocol.NewBaseInvoker(common.NewURLWithOptions(
+			common.WithPath(service),
+			common.WithParamsValue(constant.InterfaceKey, service),
+			common.WithParamsValue(constant.ApplicationKey, "provider"),
+			common.WithParamsValue(constant.RegistryRoleKey, strconv.Itoa(common.PROVIDER)),
+		))
+	}
+
+	// the collector subscribes the bus asynchronously, publish until it is ready
+	probe := newInvoker("org.apache.dubbo.Probe")
+	assert.Eventually(t, func() bool {
+		metrics.Publish(rpc.NewAfterInvokeEvent(probe, invocation.NewRPCInvocation("Probe", nil, nil), time.Millisecond, &protocol.RPCResult{}))
+		return strings.Contains(scrape(), `interface="org.apache.dubbo.Probe"`)
+	}, 5*time.Second, 100*time.Millisecond)
+
+	invoker := newInvoker("org.apache.dubbo.Greeter")
+	traced := invocation.NewRPCInvocation("Greet", nil, map[string]interface{}{
+		constant.TraceparentKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	metrics.Publish(rpc.NewAfterInvokeEvent(invoker, traced, 50*time.Millisecond, &protocol.RPCResult{}))
+	metrics.Publish(rpc.NewAfterInvokeEvent(invoker, invocation.NewRPCInvocation("Greet", nil, nil), 5*time.Millisecond, &protocol.RPCResult{}))
+	metrics.Publish(rpc.NewAfterInvokeEvent(invoker, invocation.NewRPCInvocation("Greet", nil, nil), 500*time.Millisecond,
+		&protocol.RPCResult{Err: common.NewRPCError(common.StatusTimeout, errors.New("timeout"))}))
+
+	labels := `application_name="provider",group="",interface="org.apache.dubbo.Greeter",method="Greet",side="provider",version=""`
+	var text string
+	assert.Eventually(t, func() bool {
+		text = scrape()
+		return strings.Contains(text, `dubbo_rpc_requests_total{`+labels+`} 3`)
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.Contains(t, text, `dubbo_rpc_errors_total{`+strings.Replace(labels, `group=""`, `error="timeout",group=""`, 1)+`} 1`)
+	assert.Contains(t, text, `dubbo_rpc_duration_seconds_bucket{`+labels+`,le="0.01"} 1`)
+	assert.Contains(t, text, `dubbo_rpc_duration_seconds_bucket{`+labels+`,le="0.1"} 2 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.05`)
+	assert.Contains(t, text, `dubbo_rpc_duration_seconds_count{`+labels+`} 3`)
+}
+
 func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
 	c := make(chan struct{})
 	go func() {
@@ -17,6 +17,10 @@
 
 package registry
 
+import (
+	"strconv"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
@@ -54,6 +58,14 @@ func (rc *registryCollector) start() {
 				rc.serverRegHandler(registryEvent)
 			case ServerSub:
 				rc.serverSubHandler(registryEvent)
+			case Subset:
+				rc.subsetHandler(registryEvent)
+			case LeaseRecovery:
+				rc.StateCount(LeaseRecoveryMetricNum, LeaseRecoveryMetricNumSucceed, LeaseRecoveryMetricNumFailed,
+					metrics.GetApplicationLevel(), registryEvent.Succ)
+			case WatchResync:
+				rc.StateCount(WatchResyncMetricNum, WatchResyncMetricNumSucceed, WatchResyncMetricNumFailed,
+					metrics.GetApplicationLevel(), registryEvent.Succ)
 			default:
 			}
 		}
@@ -125,3 +137,11 @@ func (rc *registryCollector) serverSubHandler(event *RegistryMetricsEvent) {
 	level := metrics.GetApplicationLevel()
 	rc.StateCount(ServiceSubscribeMetricNum, ServiceSubscribeMetricNumSucceed, ServiceSubscribeMetricNumFailed, level, event.Succ)
 }
+
+// subsetHandler handles subset metrics
+func (rc *registryCollector) subsetHandler(event *RegistryMetricsEvent) {
+	level := metrics.NewServiceMetric(event.Attachment["Interface"])
+	size, _ := strconv.ParseFloat(event.Attachment["SubsetSize"], 64)
+	rc.R.Gauge(metrics.NewMetricId(DirectorySubsetSize, level)).Set(size)
+	rc.R.Counter(metrics.NewMetricId(DirectorySubsetRecomputeTotal, level)).Inc()
+}
@@ -18,6 +18,7 @@
 package registry
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -78,6 +79,14 @@ func NewDirectoryEvent(dirTyp string) metrics.MetricsEvent {
 	}
 }
 
+// NewSubsetEvent for the subset metrics of the directory of @interfaceName, reported on each recompute
+func NewSubsetEvent(interfaceName string, size int) metrics.MetricsEvent {
+	return &RegistryMetricsEvent{
+		Name:       Subset,
+		Attachment: map[string]string{"Interface": interfaceName, "SubsetSize": strconv.Itoa(size)},
+	}
+}
+
 // NewServerRegisterEvent for server register metrics
 func NewServerRegisterEvent(succ bool, start time.Time) metrics.MetricsEvent {
 	return &RegistryMetricsEvent{
@@ -95,3 +104,19 @@ func NewServerSubscribeEvent(succ bool) metrics.MetricsEvent {
 		Succ: succ,
 	}
 }
+
+// NewLeaseRecoveryEvent for the metrics of an attempt to recover the lost lease of the temporary keys
+func NewLeaseRecoveryEvent(succ bool) metrics.MetricsEvent {
+	return &RegistryMetricsEvent{
+		Name: LeaseRecovery,
+		Succ: succ,
+	}
+}
+
+// NewWatchResyncEvent for the metrics of an attempt to resync a compacted or broken watch
+func NewWatchResyncEvent(succ bool) metrics.MetricsEvent {
+	return &RegistryMetricsEvent{
+		Name: WatchResync,
+		Succ: succ,
+	}
+}
@@ -30,6 +30,9 @@ const (
 	Directory
 	ServerReg
 	ServerSub
+	Subset
+	LeaseRecovery
+	WatchResync
 )
 
 const (
@@ -57,6 +60,10 @@ var (
 	DirectoryMetricNumToReconnect = metrics.NewMetricKey("dubbo_registry_directory_num_to_reconnect_total", "ToReconnect Directory Urls")
 	DirectoryMetricNumDisable     = metrics.NewMetricKey("dubbo_registry_directory_num_disable_total", "Disable Directory Urls")
 
+	// subset metrics key
+	DirectorySubsetSize           = metrics.NewMetricKey("dubbo_registry_directory_subset_size", "Providers Selected Into The Subset")
+	DirectorySubsetRecomputeTotal = metrics.NewMetricKey("dubbo_registry_directory_subset_recompute_total", "Total Subset Recomputes")
+
 	NotifyMetricRequests = metrics.NewMetricKey("dubbo_registry_notify_requests_total", "Total Notify Requests")
 	NotifyMetricNumLast  = metrics.NewMetricKey("dubbo_registry_notify_num_last", "Last Notify Nums")
 
@@ -73,6 +80,16 @@ var (
 	// register metrics server rt key
 	RegisterServiceRt = metrics.NewMetricKey("dubbo_register_service_rt_milliseconds", "Service Register Time")
 
+	// the recoveries of the lost leases of the temporary keys
+	LeaseRecoveryMetricNum        = metrics.NewMetricKey("dubbo_registry_lease_recovery_num_total", "Total Lease Recovery Attempts")
+	LeaseRecoveryMetricNumSucceed = metrics.NewMetricKey("dubbo_registry_lease_recovery_num_succeed_total", "Succeed Lease Recovery Attempts")
+	LeaseRecoveryMetricNumFailed  = metrics.NewMetricKey("dubbo_registry_lease_recovery_num_failed_total", "Failed Lease Recovery Attempts")
+
+	// the resyncs of the compacted or broken watches
+	WatchResyncMetricNum        = metrics.NewMetricKey("dubbo_registry_watch_resync_num_total", "Total Watch Resync Attempts")
+	WatchResyncMetricNumSucceed = metrics.NewMetricKey("dubbo_registry_watch_resync_num_succeed_total", "Succeed Watch Resync Attempts")
+	WatchResyncMetricNumFailed  = metrics.NewMetricKey("dubbo_registry_watch_resync_num_failed_total", "Failed Watch Resync Attempts")
+
 	// register metrics rt key
 	RegisterRt = metrics.NewMetricKey("dubbo_register_rt_milliseconds", "Response Time")
 
@@ -35,8 +35,9 @@ var (
 func init() {
 	collectorFunc := func(registry metrics.MetricRegistry, c *common.URL) {
 		rc := &rpcCollector{
-			registry:  registry,
-			metricSet: buildMetricSet(registry),
+			registry:    registry,
+			metricSet:   buildMetricSet(registry),
+			methodLabel: c.GetParamBool(constant.MethodLabelEnabledKey, true),
 		}
 		go rc.start()
 	}
@@ -46,23 +47,39 @@ func init() {
 
 // rpcCollector is a collector which will collect the rpc metrics
 type rpcCollector struct {
-	registry  metrics.MetricRegistry
-	metricSet *metricSet // metricSet is a struct which contains all metrics about rpc
+	registry    metrics.MetricRegistry
+	metricSet   *metricSet // metricSet is a struct which contains all metrics about rpc
+	methodLabel bool       // methodLabel shows whether the rate, errors and duration metrics are labeled by method
 }
 
 // start will subscribe the rpc.metricsEvent from channel rpcMetricsChan, and handle the event from the channel
 func (c *rpcCollector) start() {
 	metrics.Subscribe(constant.MetricsRpc, rpcMetricsChan)
 	for event := range rpcMetricsChan {
-		if rpcEvent, ok := event.(*metricsEvent); ok {
+		switch rpcEvent := event.(type) {
+		case *metricsEvent:
 			switch rpcEvent.name {
 			case BeforeInvoke:
 				c.beforeInvokeHandler(rpcEvent)
 			case AfterInvoke:
 				c.afterInvokeHandler(rpcEvent)
+			case RetrySuppressed:
+				c.retrySuppressedHandler(rpcEvent)
+			case Panicked:
+				c.panicHandler(rpcEvent)
+			case SlowInvoked:
+				c.slowInvocationHandler(rpcEvent)
 			default:
 			}
-		} else {
+		case *circuitBreakerEvent:
+			c.circuitBreakerHandler(rpcEvent)
+		case *accessDeniedEvent:
+			c.accessDeniedHandler(rpcEvent)
+		case *executeStateEvent:
+			c.executeStateHandler(rpcEvent)
+		case *degradationEvent:
+			c.degradationHandler(rpcEvent)
+		default:
 			logger.Error("Bad metrics event found in RPC collector")
 		}
 	}
@@ -96,6 +113,58 @@ func (c *rpcCollector) afterInvokeHandler(event *metricsEvent) {
 		}
 	}
 	c.reportRTMilliseconds(role, labels, event.costTime.Milliseconds())
+	c.recordRED(role, event)
+}
+
+func (c *rpcCollector) retrySuppressedHandler(event *metricsEvent) {
+	url := event.invoker.GetURL()
+	if getRole(url) != constant.SideConsumer {
+		return
+	}
+	c.metricSet.consumer.retrySuppressedTotal.Inc(buildLabels(url, event.invocation))
+}
+
+func (c *rpcCollector) panicHandler(event *metricsEvent) {
+	url := event.invoker.GetURL()
+	if getRole(url) != constant.SideProvider {
+		return
+	}
+	c.metricSet.provider.panicsTotal.Inc(buildLabels(url, event.invocation))
+}
+
+func (c *rpcCollector) slowInvocationHandler(event *metricsEvent) {
+	url := event.invoker.GetURL()
+	labels := buildLabels(url, event.invocation)
+	switch getRole(url) {
+	case constant.SideProvider:
+		c.metricSet.provider.slowRequestsTotal.Inc(labels)
+	case constant.SideConsumer:
+		c.metricSet.consumer.slowRequestsTotal.Inc(labels)
+	}
+}
+
+func (c *rpcCollector) circuitBreakerHandler(event *circuitBreakerEvent) {
+	labels := buildMethodLabels(event.url, event.methodName)
+	labels[constant.TagState] = event.state
+	c.metricSet.consumer.circuitBreakerStateChangesTotal.Inc(labels)
+}
+
+func (c *rpcCollector) accessDeniedHandler(event *accessDeniedEvent) {
+	labels := buildMethodLabels(event.url, event.methodName)
+	labels[constant.TagSource] = event.source
+	c.metricSet.provider.accessDeniedTotal.Inc(labels)
+}
+
+func (c *rpcCollector) executeStateHandler(event *executeStateEvent) {
+	labels := buildMethodLabels(event.url, event.methodName)
+	c.metricSet.provider.executeActive.Set(labels, float64(event.active))
+	c.metricSet.provider.executeQueued.Set(labels, float64(event.queued))
+}
+
+func (c *rpcCollector) degradationHandler(event *degradationEvent) {
+	labels := buildMethodLabels(event.url, event.methodName)
+	labels[constant.TagReason] = event.reason
+	c.metricSet.consumer.degradationsTotal.Inc(labels)
 }
 
 func (c *rpcCollector) recordQps(role string, labels map[string]string) {
@@ -159,3 +228,17 @@ func (c *rpcCollector) reportRTMilliseconds(role string, labels map[string]strin
 		c.metricSet.consumer.rtMillisecondsQuantiles.Record(labels, float64(cost))
 	}
 }
+
+func (c *rpcCollector) recordRED(role string, event *metricsEvent) {
+	labels := buildREDLabels(event.invoker.GetURL(), event.invocation, role, c.methodLabel)
+	c.metricSet.red.requestsTotal.Inc(labels)
+	if event.result != nil && event.result.Error() != nil {
+		errorLabels := make(map[string]string, len(labels)+1)
+		for k, v := range labels {
+			errorLabels[k] = v
+		}
+		errorLabels[constant.TagErrorCode] = common.CodeOf(event.result.Error()).String()
+		c.metricSet.red.errorsTotal.Inc(errorLabels)
+	}
+	c.metricSet.red.durationSeconds.RecordWithExemplar(labels, event.costTime.Seconds(), traceExemplar(event.invocation))
+}
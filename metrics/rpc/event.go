@@ -22,6 +22,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -46,6 +47,9 @@ type metricsName uint8
 const (
 	BeforeInvoke metricsName = iota
 	AfterInvoke
+	RetrySuppressed
+	Panicked
+	SlowInvoked
 )
 
 func NewBeforeInvokeEvent(invoker protocol.Invoker, invocation protocol.Invocation) metrics.MetricsEvent {
@@ -65,3 +69,121 @@ func NewAfterInvokeEvent(invoker protocol.Invoker, invocation protocol.Invocatio
 		result:     result,
 	}
 }
+
+// NewRetrySuppressedEvent creates an event reported when a retry is given up because the retry budget is exhausted
+func NewRetrySuppressedEvent(invoker protocol.Invoker, invocation protocol.Invocation) metrics.MetricsEvent {
+	return &metricsEvent{
+		name:       RetrySuppressed,
+		invoker:    invoker,
+		invocation: invocation,
+	}
+}
+
+// NewPanicEvent creates an event reported when a panic of the provider is recovered
+func NewPanicEvent(invoker protocol.Invoker, invocation protocol.Invocation) metrics.MetricsEvent {
+	return &metricsEvent{
+		name:       Panicked,
+		invoker:    invoker,
+		invocation: invocation,
+	}
+}
+
+// NewSlowInvocationEvent creates an event reported when the rt of an invocation exceeds the slow threshold
+func NewSlowInvocationEvent(invoker protocol.Invoker, invocation protocol.Invocation, costTime time.Duration) metrics.MetricsEvent {
+	return &metricsEvent{
+		name:       SlowInvoked,
+		invoker:    invoker,
+		invocation: invocation,
+		costTime:   costTime,
+	}
+}
+
+// circuitBreakerEvent is the event reported when the state of a circuit breaker changes
+type circuitBreakerEvent struct {
+	url        *common.URL
+	methodName string
+	state      string
+}
+
+// Type returns the type of the event, it is used for metrics bus to dispatch the event to rpc collector
+func (c circuitBreakerEvent) Type() string {
+	return constant.MetricsRpc
+}
+
+// NewCircuitBreakerStateChangedEvent creates an event reported when the circuit breaker of the method of the
+// provider with @url changes to @state
+func NewCircuitBreakerStateChangedEvent(url *common.URL, methodName string, state string) metrics.MetricsEvent {
+	return &circuitBreakerEvent{
+		url:        url,
+		methodName: methodName,
+		state:      state,
+	}
+}
+
+// accessDeniedEvent is the event reported when the provider denies a consumer for its address
+type accessDeniedEvent struct {
+	url        *common.URL
+	methodName string
+	source     string
+}
+
+// Type returns the type of the event, it is used for metrics bus to dispatch the event to rpc collector
+func (a accessDeniedEvent) Type() string {
+	return constant.MetricsRpc
+}
+
+// NewAccessDeniedEvent creates an event reported when the provider with @url denies the call of @methodName from
+// the consumer at @source
+func NewAccessDeniedEvent(url *common.URL, methodName string, source string) metrics.MetricsEvent {
+	return &accessDeniedEvent{
+		url:        url,
+		methodName: methodName,
+		source:     source,
+	}
+}
+
+// executeStateEvent is the event reported when the active or the queued invocations under an execute limit change
+type executeStateEvent struct {
+	url        *common.URL
+	methodName string
+	active     int64
+	queued     int64
+}
+
+// Type returns the type of the event, it is used for metrics bus to dispatch the event to rpc collector
+func (e executeStateEvent) Type() string {
+	return constant.MetricsRpc
+}
+
+// NewExecuteStateEvent creates an event reported when the invocations under the execute limit of the method
+// @methodName, or of the service if it is empty, change to @active ones and @queued ones
+func NewExecuteStateEvent(url *common.URL, methodName string, active, queued int64) metrics.MetricsEvent {
+	return &executeStateEvent{
+		url:        url,
+		methodName: methodName,
+		active:     active,
+		queued:     queued,
+	}
+}
+
+// degradationEvent is the event reported when the consumer degrades an invocation
+type degradationEvent struct {
+	url        *common.URL
+	methodName string
+	reason     string
+}
+
+// Type returns the type of the event, it is used for metrics bus to dispatch the event to rpc collector
+func (d degradationEvent) Type() string {
+	return constant.MetricsRpc
+}
+
+// NewDegradationEvent creates an event reported when the consumer with @url degrades the invocation of
+// @methodName for @reason, the error class or force
+func NewDegradationEvent(url *common.URL, methodName string, reason string) metrics.MetricsEvent {
+	return &degradationEvent{
+		url:        url,
+		methodName: methodName,
+		reason:     reason,
+	}
+}
@@ -25,14 +25,22 @@ import (
 type metricSet struct {
 	provider *providerMetrics
 	consumer *consumerMetrics
+	red      *redMetrics
 }
 
 type providerMetrics struct {
 	rpcCommonMetrics
+	panicsTotal       metrics.CounterVec
+	accessDeniedTotal metrics.CounterVec
+	executeActive     metrics.GaugeVec
+	executeQueued     metrics.GaugeVec
 }
 
 type consumerMetrics struct {
 	rpcCommonMetrics
+	retrySuppressedTotal            metrics.CounterVec
+	circuitBreakerStateChangesTotal metrics.CounterVec
+	degradationsTotal               metrics.CounterVec
 }
 
 // rpcCommonMetrics is the common metrics for both provider and consumer
@@ -43,29 +51,45 @@ type rpcCommonMetrics struct {
 	requestsProcessingTotal       metrics.GaugeVec
 	requestsSucceedTotal          metrics.CounterVec
 	requestsSucceedTotalAggregate metrics.AggregateCounterVec
+	slowRequestsTotal             metrics.CounterVec
 	rtMilliseconds                metrics.RtVec
 	rtMillisecondsQuantiles       metrics.QuantileMetricVec
 	rtMillisecondsAggregate       metrics.RtVec
 }
 
+// redMetrics is the rate, errors and duration metrics of both the provider and the consumer, which are told apart
+// by the side label. Only the labels of the service are kept to bound the cardinality.
+type redMetrics struct {
+	requestsTotal   metrics.CounterVec
+	errorsTotal     metrics.CounterVec
+	durationSeconds metrics.HistogramVec
+}
+
 // buildMetricSet will call init functions to initialize the metricSet
 func buildMetricSet(registry metrics.MetricRegistry) *metricSet {
 	ms := &metricSet{
 		provider: &providerMetrics{},
 		consumer: &consumerMetrics{},
+		red:      &redMetrics{},
 	}
 	ms.provider.init(registry)
 	ms.consumer.init(registry)
+	ms.red.init(registry)
 	return ms
 }
 
 func (pm *providerMetrics) init(registry metrics.MetricRegistry) {
 	pm.qpsTotal = metrics.NewQpsMetricVec(registry, metrics.NewMetricKey("dubbo_provider_qps_total", "The number of requests received by the provider per second"))
+	pm.panicsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_panics_total", "The number of panics recovered by the provider"))
+	pm.accessDeniedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_access_denied_total", "The number of requests denied by the provider for the address of the consumer"))
+	pm.executeActive = metrics.NewGaugeVec(registry, metrics.NewMetricKey("dubbo_provider_execute_active", "The number of requests being executed under the execute limit of the provider, the method is empty for the limit of the service"))
+	pm.executeQueued = metrics.NewGaugeVec(registry, metrics.NewMetricKey("dubbo_provider_execute_queued", "The number of requests waiting for the execute limit of the provider, the method is empty for the limit of the service"))
 	pm.requestsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_requests_total", "The total number of received requests by the provider"))
 	pm.requestsTotalAggregate = metrics.NewAggregateCounterVec(registry, metrics.NewMetricKey("dubbo_provider_requests_total_aggregate", "The total number of received requests by the provider under the sliding window"))
 	pm.requestsProcessingTotal = metrics.NewGaugeVec(registry, metrics.NewMetricKey("dubbo_provider_requests_processing_total", "The number of received requests being processed by the provider"))
 	pm.requestsSucceedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_requests_succeed_total", "The number of requests successfully received by the provider"))
 	pm.requestsSucceedTotalAggregate = metrics.NewAggregateCounterVec(registry, metrics.NewMetricKey("dubbo_provider_requests_succeed_total_aggregate", "The number of successful requests received by the provider under the sliding window"))
+	pm.slowRequestsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_provider_slow_requests_total", "The number of requests whose response time exceeds the slow threshold of the provider"))
 	pm.rtMilliseconds = metrics.NewRtVec(registry,
 		metrics.NewMetricKey("dubbo_provider_rt_milliseconds", "response time among all requests processed by the provider"),
 		&metrics.RtOpts{Aggregate: false},
@@ -89,6 +113,10 @@ func (cm *consumerMetrics) init(registry metrics.MetricRegistry) {
 	cm.requestsProcessingTotal = metrics.NewGaugeVec(registry, metrics.NewMetricKey("dubbo_consumer_requests_processing_total", "The number of received requests being processed by the consumer"))
 	cm.requestsSucceedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_requests_succeed_total", "The number of successful requests sent by consumers"))
 	cm.requestsSucceedTotalAggregate = metrics.NewAggregateCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_requests_succeed_total_aggregate", "The number of successful requests sent by consumers under the sliding window"))
+	cm.retrySuppressedTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_retry_suppressed_total", "The number of retries suppressed by consumers because the retry budget is exhausted"))
+	cm.circuitBreakerStateChangesTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_circuit_breaker_state_changes_total", "The number of times the circuit breakers of consumers change to the state"))
+	cm.degradationsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_degradations_total", "The number of requests degraded by consumers for the reason"))
+	cm.slowRequestsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_consumer_slow_requests_total", "The number of requests whose response time exceeds the slow threshold of the consumer"))
 	cm.rtMilliseconds = metrics.NewRtVec(registry,
 		metrics.NewMetricKey("dubbo_consumer_rt_milliseconds", "response time among all requests from consumers"),
 		&metrics.RtOpts{Aggregate: false},
@@ -104,3 +132,9 @@ func (cm *consumerMetrics) init(registry metrics.MetricRegistry) {
 		metrics.NewMetricKey("dubbo_consumer_rt_milliseconds_p99", "The total response time spent by consumers processing 99% of requests"),
 	}, []float64{0.5, 0.9, 0.95, 0.99})
 }
+
+func (rm *redMetrics) init(registry metrics.MetricRegistry) {
+	rm.requestsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_rpc_requests_total", "The total number of requests handled"))
+	rm.errorsTotal = metrics.NewCounterVec(registry, metrics.NewMetricKey("dubbo_rpc_errors_total", "The number of requests failed with the error code"))
+	rm.durationSeconds = metrics.NewHistogramVec(registry, metrics.NewMetricKey("dubbo_rpc_duration_seconds", "The duration of requests in seconds"))
+}
@@ -34,18 +34,54 @@ import (
 
 // buildLabels will build the labels for the rpc metrics
 func buildLabels(url *common.URL, invocation protocol.Invocation) map[string]string {
+	return buildMethodLabels(url, invocation.MethodName())
+}
+
+// buildMethodLabels will build the labels of the method @methodName of the service with @url
+func buildMethodLabels(url *common.URL, methodName string) map[string]string {
 	return map[string]string{
 		constant.TagApplicationName:    url.GetParam(constant.ApplicationKey, ""),
 		constant.TagApplicationVersion: url.GetParam(constant.AppVersionKey, ""),
 		constant.TagHostname:           common.GetLocalHostName(),
 		constant.TagIp:                 common.GetLocalIp(),
 		constant.TagInterface:          url.Service(),
-		constant.TagMethod:             invocation.MethodName(),
+		constant.TagMethod:             methodName,
 		constant.TagGroup:              url.Group(),
 		constant.TagVersion:            url.GetParam(constant.VersionKey, ""),
 	}
 }
 
+// buildREDLabels will build the labels for the rate, errors and duration metrics, the method label is
+// omitted unless @withMethod to bound the cardinality
+func buildREDLabels(url *common.URL, invocation protocol.Invocation, role string, withMethod bool) map[string]string {
+	labels := map[string]string{
+		constant.TagApplicationName: url.GetParam(constant.ApplicationKey, ""),
+		constant.TagInterface:       url.Service(),
+		constant.TagGroup:           url.Group(),
+		constant.TagVersion:         url.GetParam(constant.VersionKey, ""),
+		constant.TagSide:            role,
+	}
+	if withMethod {
+		labels[constant.TagMethod] = invocation.MethodName()
+	}
+	return labels
+}
+
+// traceExemplar returns the exemplar with the trace id in the w3c traceparent of @invocation,
+// nil if the invocation is not traced
+func traceExemplar(invocation protocol.Invocation) map[string]string {
+	traceparent, ok := invocation.GetAttachment(constant.TraceparentKey)
+	if !ok {
+		return nil
+	}
+	// version-traceid-parentid-flags
+	fields := strings.Split(traceparent, "-")
+	if len(fields) != 4 || len(fields[1]) != 32 || strings.Trim(fields[1], "0") == "" {
+		return nil
+	}
+	return map[string]string{constant.TagTraceId: fields[1]}
+}
+
 // getRole will get the application role from the url
 func getRole(url *common.URL) (role string) {
 	if isProvider(url) {
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestBuildREDLabels(t *testing.T) {
+	url := common.NewURLWithOptions(
+		common.WithPath("org.apache.dubbo.Greeter"),
+		common.WithParamsValue(constant.InterfaceKey, "org.apache.dubbo.Greeter"),
+		common.WithParamsValue(constant.ApplicationKey, "consumer"),
+		common.WithParamsValue(constant.GroupKey, "g"),
+		common.WithParamsValue(constant.VersionKey, "1.0.0"),
+	)
+	inv := invocation.NewRPCInvocation("Greet", nil, nil)
+
+	labels := buildREDLabels(url, inv, constant.SideConsumer, true)
+	assert.Equal(t, map[string]string{
+		constant.TagApplicationName: "consumer",
+		constant.TagInterface:       "org.apache.dubbo.Greeter",
+		constant.TagGroup:           "g",
+		constant.TagVersion:         "1.0.0",
+		constant.TagSide:            constant.SideConsumer,
+		constant.TagMethod:          "Greet",
+	}, labels)
+
+	labels = buildREDLabels(url, inv, constant.SideConsumer, false)
+	assert.NotContains(t, labels, constant.TagMethod)
+}
+
+func TestTraceExemplar(t *testing.T) {
+	inv := invocation.NewRPCInvocation("Greet", nil, nil)
+	assert.Nil(t, traceExemplar(inv))
+
+	inv.SetAttachment(constant.TraceparentKey, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal(t, map[string]string{constant.TagTraceId: "4bf92f3577b34da6a3ce929d0e0e4736"}, traceExemplar(inv))
+
+	inv.SetAttachment(constant.TraceparentKey, "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+	assert.Nil(t, traceExemplar(inv))
+
+	inv.SetAttachment(constant.TraceparentKey, "invalid")
+	assert.Nil(t, traceExemplar(inv))
+}
@@ -20,6 +20,7 @@ package trace
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 import (
@@ -88,6 +89,11 @@ func NewExporter(config *ExporterConfig, customFunc func() (sdktrace.SpanExporte
 		return
 	}
 
+	if propagator, err = NewPropagator(config.Propagator); err != nil {
+		logger.Error(err)
+		return
+	}
+
 	tracerProvider = sdktrace.NewTracerProvider(
 		samplerOption,
 		sdktrace.WithBatcher(exporter),
@@ -98,13 +104,23 @@ func NewExporter(config *ExporterConfig, customFunc func() (sdktrace.SpanExporte
 		)),
 	)
 
-	switch config.Propagator {
-	case "w3c":
-		propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
-	case "b3":
-		b3Propagator := b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader | b3.B3SingleHeader))
-		propagator = propagation.NewCompositeTextMapPropagator(b3Propagator, propagation.Baggage{})
-	}
-
 	return tracerProvider, propagator, nil
 }
+
+// NewPropagator returns the propagator of the comma separated @names, which are w3c for the traceparent and the
+// tracestate, and b3 for the b3 headers which the brave of dubbo java expects, e.g. "w3c,b3" injects both and
+// extracts either of them. The baggage is always propagated.
+func NewPropagator(names string) (propagation.TextMapPropagator, error) {
+	var propagators []propagation.TextMapPropagator
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "w3c":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader|b3.B3SingleHeader)))
+		default:
+			return nil, fmt.Errorf("otel propagator %s not supported", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(append(propagators, propagation.Baggage{})...), nil
+}
@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPropagator(t *testing.T) {
+	propagator, err := NewPropagator("w3c")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, propagator.Fields())
+
+	propagator, err = NewPropagator("w3c, b3")
+	assert.NoError(t, err)
+	assert.Subset(t, propagator.Fields(), []string{"traceparent", "b3", "x-b3-traceid", "baggage"})
+
+	_, err = NewPropagator("jaeger")
+	assert.EqualError(t, err, "otel propagator jaeger not supported")
+}
@@ -32,6 +32,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
@@ -194,8 +195,8 @@ func (c *DubboCodec) isRequest(data []byte) bool {
 // decode request
 func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error) {
 	var request *remoting.Request
-	buf := bytes.NewBuffer(data)
-	pkg := impl.NewDubboPackage(buf)
+	// the data of getty is decoded in place, and the request decoded does not refer to it
+	pkg := impl.NewDubboPackageFromBytes(data)
 	pkg.SetBody(make([]interface{}, 7))
 	err := pkg.Unmarshal()
 	if err != nil {
@@ -206,7 +207,7 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 		if originErr == hessian.ErrBodyNotEnough {
 			return nil, hessian.HEADER_LENGTH + pkg.GetBodyLen(), nil
 		}
-		logger.Errorf("pkg.Unmarshal(len(@data):%d) = error:%+v", buf.Len(), err)
+		logger.Errorf("pkg.Unmarshal(len(@data):%d) = error:%+v", len(data), err)
 
 		return request, 0, perrors.WithStack(err)
 	}
@@ -246,8 +247,7 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 
 // decode response
 func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error) {
-	buf := bytes.NewBuffer(data)
-	pkg := impl.NewDubboPackage(buf)
+	pkg := impl.NewDubboPackageFromBytes(data)
 	err := pkg.Unmarshal()
 	if err != nil {
 		originErr := perrors.Cause(err)
@@ -259,7 +259,7 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 			return nil, hessian.HEADER_LENGTH + pkg.GetBodyLen(), nil
 		}
 
-		logger.Warnf("pkg.Unmarshal(len(@data):%d) = error:%+v", buf.Len(), err)
+		logger.Warnf("pkg.Unmarshal(len(@data):%d) = error:%+v", len(data), err)
 		return nil, 0, perrors.WithStack(err)
 	}
 	response := &remoting.Response{
@@ -291,7 +291,8 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 		if pkg.Err != nil {
 			rpcResult.Err = pkg.Err
 		} else if pkg.Body.(*impl.ResponsePayload).Exception != nil {
-			rpcResult.Err = pkg.Body.(*impl.ResponsePayload).Exception
+			rpcResult.Err = withErrorCode(pkg.Body.(*impl.ResponsePayload).Exception, pkg.Body.(*impl.ResponsePayload).Attachments,
+				pkg.Header.ResponseStatus)
 			response.Error = rpcResult.Err
 		}
 		rpcResult.Attrs = pkg.Body.(*impl.ResponsePayload).Attachments
@@ -300,3 +301,35 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 
 	return response, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
 }
+
+// withErrorCode tags @err with the code passed by the provider in the attachments, or the code of the response
+// status @status otherwise
+func withErrorCode(err error, attachments map[string]interface{}, status byte) error {
+	if name, ok := attachments[constant.ErrorCodeKey].(string); ok {
+		if code, ok := common.ParseRPCStatus(name); ok {
+			return common.NewRPCError(code, err)
+		}
+	}
+	return common.NewRPCError(rpcStatusOf(status), err)
+}
+
+// rpcStatusOf maps the dubbo response status to the code, an exception responded with the OK status is thrown by the
+// service, which is a business error
+func rpcStatusOf(status byte) common.RPCStatus {
+	switch status {
+	case hessian.Response_OK, hessian.Response_SERVICE_ERROR:
+		return common.StatusBiz
+	case hessian.Response_CLIENT_TIMEOUT, hessian.Response_SERVER_TIMEOUT:
+		return common.StatusTimeout
+	case hessian.Response_BAD_REQUEST, hessian.Response_BAD_RESPONSE:
+		return common.StatusSerialization
+	case hessian.Response_SERVICE_NOT_FOUND:
+		return common.StatusServiceNotFound
+	case hessian.Response_SERVER_ERROR:
+		return common.StatusInternal
+	case impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR:
+		return common.StatusThrottled
+	default:
+		return common.StatusUnknown
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestDubboCodecErrorCode(t *testing.T) {
+	codec := &DubboCodec{}
+	remoting.AddPendingResponse(remoting.NewPendingResponse(125))
+	response := remoting.NewResponse(125, "2.0.2")
+	response.SerialID = constant.SHessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{
+		Err: common.NewRPCError(common.StatusInternal, errors.New("provider internal error")),
+		Attrs: map[string]interface{}{
+			constant.Dubbo:        "2.0.2",
+			constant.ErrorCodeKey: common.StatusInternal.String(),
+		},
+	}
+	buf, err := codec.EncodeResponse(response)
+	assert.NoError(t, err)
+
+	decoded, _, err := codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	result := decoded.Result.(*remoting.Response).Result.(*protocol.RPCResult)
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Err))
+	assert.Contains(t, result.Err.Error(), "provider internal error")
+}
+
+func TestWithErrorCode(t *testing.T) {
+	err := errors.New("error")
+	// the exception thrown by the service without a code is a business error
+	assert.Equal(t, common.StatusBiz, common.CodeOf(withErrorCode(err, nil, hessian.Response_OK)))
+	assert.Equal(t, common.StatusBiz, common.CodeOf(withErrorCode(err,
+		map[string]interface{}{constant.ErrorCodeKey: "unexpected"}, hessian.Response_OK)))
+	coded := withErrorCode(err, map[string]interface{}{constant.ErrorCodeKey: common.StatusThrottled.String()}, hessian.Response_OK)
+	assert.Equal(t, common.StatusThrottled, common.CodeOf(coded))
+	assert.True(t, errors.Is(coded, err))
+
+	// the code is mapped from the response status
+	for status, code := range map[byte]common.RPCStatus{
+		hessian.Response_CLIENT_TIMEOUT:    common.StatusTimeout,
+		hessian.Response_SERVER_TIMEOUT:    common.StatusTimeout,
+		hessian.Response_BAD_REQUEST:       common.StatusSerialization,
+		hessian.Response_BAD_RESPONSE:      common.StatusSerialization,
+		hessian.Response_SERVICE_NOT_FOUND: common.StatusServiceNotFound,
+		hessian.Response_SERVICE_ERROR:     common.StatusBiz,
+		hessian.Response_SERVER_ERROR:      common.StatusInternal,
+		hessian.Response_CLIENT_ERROR:      common.StatusUnknown,
+		100:                                common.StatusThrottled,
+	} {
+		assert.Equal(t, code, common.CodeOf(withErrorCode(err, nil, status)), "status %d", status)
+	}
+}
+
+// benchmarkInvocation returns the invocation of a typical request, whose argument is about @size bytes
+func benchmarkInvocation(size int) protocol.Invocation {
+	return invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{strings.Repeat("a", size), int64(10086)}),
+		invocation.WithAttachments(map[string]interface{}{
+			constant.PathKey:      "com.ikurento.user.UserProvider",
+			constant.InterfaceKey: "com.ikurento.user.UserProvider",
+			constant.VersionKey:   "1.0.0",
+			constant.TimeoutKey:   "3000",
+		}))
+}
+
+func benchmarkRequest(size int) *remoting.Request {
+	inv := benchmarkInvocation(size)
+	request := remoting.NewRequest("2.0.2")
+	request.Data = &inv
+	request.TwoWay = true
+	return request
+}
+
+func benchmarkResponse(id int64, size int) *remoting.Response {
+	response := remoting.NewResponse(id, "2.0.2")
+	response.SerialID = constant.SHessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{
+		Rest:  strings.Repeat("b", size),
+		Attrs: map[string]interface{}{constant.Dubbo: "2.0.2"},
+	}
+	return response
+}
+
+func BenchmarkDubboCodecEncodeRequest(b *testing.B) {
+	for _, size := range []int{64, 4096} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			codec := &DubboCodec{}
+			request := benchmarkRequest(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.EncodeRequest(request); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDubboCodecDecodeRequest(b *testing.B) {
+	for _, size := range []int{64, 4096} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			codec := &DubboCodec{}
+			buf, err := codec.EncodeRequest(benchmarkRequest(size))
+			if err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := codec.Decode(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDubboCodecEncodeResponse(b *testing.B) {
+	for _, size := range []int{64, 4096} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			codec := &DubboCodec{}
+			response := benchmarkResponse(1, size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.EncodeResponse(response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDubboCodecDecodeResponse(b *testing.B) {
+	for _, size := range []int{64, 4096} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			codec := &DubboCodec{}
+			id := int64(20000 + size)
+			pending := remoting.NewPendingResponse(id)
+			pending.Reply = new(string)
+			remoting.AddPendingResponse(pending)
+			buf, err := codec.EncodeResponse(benchmarkResponse(id, size))
+			if err != nil {
+				b.Fatal(err)
+			}
+			data := buf.Bytes()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := codec.Decode(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// FuzzDubboCodecPooledBuffers checks that the pooled encoders and decoders never leak the data between the requests
+func FuzzDubboCodecPooledBuffers(f *testing.F) {
+	f.Add("first", "second")
+	f.Add(strings.Repeat("a", 4096), "b")
+	f.Add("", strings.Repeat("c", 1024))
+	f.Fuzz(func(t *testing.T, first, second string) {
+		// hessian encodes the strings as utf-8
+		if !utf8.ValidString(first) || !utf8.ValidString(second) {
+			t.Skip()
+		}
+		codec := &DubboCodec{}
+		encode := func(arg string) []byte {
+			var inv protocol.Invocation = invocation.NewRPCInvocationWithOptions(
+				invocation.WithMethodName("GetUser"),
+				invocation.WithArguments([]interface{}{arg}),
+				invocation.WithAttachments(map[string]interface{}{
+					constant.PathKey:      "com.ikurento.user.UserProvider",
+					constant.InterfaceKey: "com.ikurento.user.UserProvider",
+				}))
+			request := remoting.NewRequest("2.0.2")
+			request.Data = &inv
+			request.TwoWay = true
+			buf, err := codec.EncodeRequest(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return buf.Bytes()
+		}
+		decode := func(data []byte) string {
+			decoded, _, err := codec.Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inv := decoded.Result.(*remoting.Request).Data.(*invocation.RPCInvocation)
+			return inv.Arguments()[0].(string)
+		}
+
+		firstData := encode(first)
+		firstCopy := append([]byte(nil), firstData...)
+		secondData := encode(second)
+		// encoding the second request must not overwrite the first packet
+		assert.Equal(t, firstCopy, firstData)
+
+		firstArg := decode(firstData)
+		secondArg := decode(secondData)
+		// the decoded arguments must not alias the read buffers reused by the sessions
+		for i := range firstData {
+			firstData[i] = 0
+		}
+		for i := range secondData {
+			secondData[i] = 0
+		}
+		assert.Equal(t, first, firstArg)
+		assert.Equal(t, second, secondArg)
+	})
+}
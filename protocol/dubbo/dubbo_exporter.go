@@ -28,25 +28,40 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 // DubboExporter is dubbo service exporter.
 type DubboExporter struct {
 	protocol.BaseExporter
+	// executor executes the requests of the service, which is the pool of the service if isolated is true, or the
+	// pool shared by the services, or nil if the requests are handled inline
+	executor  *gopool.Pool
+	isolated  bool
+	rejection string
 }
 
 // NewDubboExporter get a DubboExporter.
 func NewDubboExporter(key string, invoker protocol.Invoker, exporterMap *sync.Map) *DubboExporter {
-	return &DubboExporter{
+	exporter := &DubboExporter{
 		BaseExporter: *protocol.NewBaseExporter(key, invoker, exporterMap),
 	}
+	if invoker != nil {
+		exporter.executor, exporter.isolated = newExecutor(invoker.GetURL())
+		exporter.rejection = invoker.GetURL().GetParam(constant.ExecutorRejectionKey, constant.ExecutorRejectionAbort)
+	}
+	return exporter
 }
 
 // Unexport unexport dubbo service exporter.
 func (de *DubboExporter) UnExport() {
 	interfaceName := de.GetInvoker().GetURL().GetParam(constant.InterfaceKey, "")
 	de.BaseExporter.UnExport()
+	if de.isolated {
+		// the queued requests are still executed
+		gopool.RemovePool(de.executor.Name())
+	}
 	err := common.ServiceMap.UnRegister(interfaceName, DUBBO, de.GetInvoker().GetURL().ServiceKey())
 	if err != nil {
 		logger.Errorf("[DubboExporter.UnExport] error: %v", err)
@@ -20,7 +20,6 @@ package dubbo
 import (
 	"context"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 )
@@ -58,7 +57,7 @@ type DubboInvoker struct {
 func NewDubboInvoker(url *common.URL, client *remoting.ExchangeClient) *DubboInvoker {
 	rt := config.GetConsumerConfig().RequestTimeout
 
-	timeout := url.GetParamDuration(constant.TimeoutKey, rt)
+	timeout := url.GetParamDurationWithDefault(constant.TimeoutKey, rt)
 	di := &DubboInvoker{
 		BaseInvoker: *protocol.NewBaseInvoker(url),
 		clientGuard: &sync.RWMutex{},
@@ -139,7 +138,11 @@ func (di *DubboInvoker) Invoke(ctx context.Context, ivc protocol.Invocation) pro
 	}
 	// response := NewResponse(inv.Reply(), nil)
 	rest := &protocol.RPCResult{}
-	timeout := di.getTimeout(inv)
+	timeout := di.getTimeout(ctx, inv)
+	if timeout <= 0 {
+		result.Err = context.DeadlineExceeded
+		return &result
+	}
 	if async {
 		if callBack, ok := inv.CallBack().(func(response common.CallbackResponse)); ok {
 			result.Err = di.client.AsyncRequest(&ivc, url, timeout, callBack, rest)
@@ -161,23 +164,21 @@ func (di *DubboInvoker) Invoke(ctx context.Context, ivc protocol.Invocation) pro
 	return &result
 }
 
-// get timeout including methodConfig
-func (di *DubboInvoker) getTimeout(ivc *invocation.RPCInvocation) time.Duration {
+// get timeout including methodConfig, which is shortened by the deadline of @ctx, e.g. a timeout of the call
+func (di *DubboInvoker) getTimeout(ctx context.Context, ivc *invocation.RPCInvocation) time.Duration {
 	methodName := ivc.MethodName()
 	if di.GetURL().GetParamBool(constant.GenericKey, false) {
 		methodName = ivc.Arguments()[0].(string)
 	}
-	timeout := di.GetURL().GetParam(strings.Join([]string{constant.MethodKeys, methodName, constant.TimeoutKey}, "."), "")
-	if len(timeout) != 0 {
-		if t, err := time.ParseDuration(timeout); err == nil {
-			// config timeout into attachment
-			ivc.SetAttachment(constant.TimeoutKey, strconv.Itoa(int(t.Milliseconds())))
-			return t
+	timeout := di.GetURL().GetMethodParamDuration(methodName, constant.TimeoutKey, di.timeout)
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
 		}
 	}
 	// set timeout into invocation at method level
-	ivc.SetAttachment(constant.TimeoutKey, strconv.Itoa(int(di.timeout.Milliseconds())))
-	return di.timeout
+	ivc.SetAttachment(constant.TimeoutKey, common.FormatDuration(timeout))
+	return timeout
 }
 
 func (di *DubboInvoker) IsAvailable() bool {
@@ -195,6 +196,7 @@ func (di *DubboInvoker) Destroy() {
 		di.BaseInvoker.Destroy()
 		client := di.getClient()
 		if client != nil {
+			client.RemoveServiceKey(di.GetURL().ServiceKey())
 			activeNumber := client.DecreaseActiveNumber()
 			di.setClient(nil)
 			if activeNumber == 0 {
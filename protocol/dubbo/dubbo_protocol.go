@@ -19,6 +19,7 @@ package dubbo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -127,7 +128,11 @@ func (dp *DubboProtocol) openServer(url *common.URL) {
 			handler := func(invocation *invocation.RPCInvocation) protocol.RPCResult {
 				return doHandleRequest(invocation)
 			}
-			srv := remoting.NewExchangeServer(url, getty.NewServer(url, handler))
+			server := getty.NewServer(url, handler)
+			// the requests are executed on the executors of their services, so a slow service doesn't starve the others
+			server.SetDispatcher(dispatch)
+			server.SetPanicHandler(handlePanic)
+			srv := remoting.NewExchangeServer(url, server)
 			dp.serverMap[url.Location] = srv
 			srv.Start()
 		}
@@ -169,8 +174,14 @@ func doHandleRequest(rpcInvocation *invocation.RPCInvocation) protocol.RPCResult
 			// p.Body = hessian.NewResponse(res, nil, result.Attachments())
 		}
 		result.Attrs = invokeResult.Attachments()
+		var codedErr *common.RPCError
+		if errors.As(result.Err, &codedErr) {
+			// the error crosses the wire as a message, so its code is passed by the attachment
+			result.AddAttachment(constant.ErrorCodeKey, codedErr.Code.String())
+		}
 	} else {
-		result.Err = fmt.Errorf("don't have the invoker, key: %s", rpcInvocation.ServiceKey())
+		result.Err = common.NewRPCError(common.StatusServiceNotFound,
+			fmt.Errorf("don't have the invoker, key: %s", rpcInvocation.ServiceKey()))
 	}
 	return result
 }
@@ -217,6 +228,7 @@ func getExchangeClient(url *common.URL) *remoting.ExchangeClient {
 	}
 	exchangeClient := clientTmp.(*remoting.ExchangeClient)
 	exchangeClient.IncreaseActiveNumber()
+	exchangeClient.AddServiceKey(url.ServiceKey())
 	return exchangeClient
 }
 
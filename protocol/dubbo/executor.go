@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+	"dubbo.apache.org/dubbo-go/v3/filter/recovery"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// executorPoolPrefix prefixes the names of the pools of the services, which are followed by the service keys
+const executorPoolPrefix = gopool.ProviderExecute + "/"
+
+// newExecutor returns the pool executing the requests of the service @url, which is the pool of the service if its
+// executor is configured, or the pool shared by the services if the executor of the provider is configured. It returns
+// nil if neither is configured, whose requests are handled inline by the goroutines receiving them.
+func newExecutor(url *common.URL) (pool *gopool.Pool, isolated bool) {
+	workers := url.GetParamInt(constant.ExecutorWorkersKey, 0)
+	if workers <= 0 {
+		if !url.GetParamBool(constant.ExecutorSharedKey, false) {
+			return nil, false
+		}
+		return gopool.GetPool(gopool.ProviderExecute), false
+	}
+	queueSize := url.GetParamInt(constant.ExecutorQueueSizeKey, gopool.DefaultQueueSize)
+	pool = gopool.NewPool(executorPoolPrefix+url.ServiceKey(), int(workers), int(queueSize))
+	// the pool is sampled with the shared pools by the metrics
+	gopool.AddPool(pool)
+	return pool, true
+}
+
+// dispatch runs @handle of the request @inv on the executor of its service before the filter chain, see
+// config.ExecutorConfig. The requests of the unknown services are handled at once, which answers the errors.
+func dispatch(inv *invocation.RPCInvocation, handle func()) error {
+	value, ok := dubboProtocol.ExporterMap().Load(inv.ServiceKey())
+	if !ok {
+		handle()
+		return nil
+	}
+	exporter, ok := value.(*DubboExporter)
+	if !ok || exporter.executor == nil {
+		handle()
+		return nil
+	}
+	switch err := exporter.executor.SubmitTimeout(handle, 0); err {
+	case nil:
+		return nil
+	case gopool.ErrPoolClosed:
+		// the service is being unexported
+		handle()
+		return nil
+	}
+	if exporter.rejection == constant.ExecutorRejectionCallerRuns {
+		handle()
+		return nil
+	}
+	return perrors.Errorf("the executor %s of the provider is exhausted, workers: %d, queue size: %d",
+		exporter.executor.Name(), exporter.executor.MaxWorkers(), exporter.executor.QueueSize())
+}
+
+// handlePanic answers the panic raised by handling @inv outside the filters the same as the recovery filter, so the
+// panic value is answered only if recovery.panic.message is true
+func handlePanic(inv *invocation.RPCInvocation, value interface{}, stack []byte) protocol.RPCResult {
+	result := protocol.RPCResult{Err: common.NewRPCError(common.StatusInternal, recovery.ErrProviderInternal)}
+	if exporter, ok := dubboProtocol.ExporterMap().Load(inv.ServiceKey()); ok {
+		panicResult := recovery.PanicResult(exporter.(protocol.Exporter).GetInvoker(), inv, value, stack)
+		result.Err, result.Attrs = panicResult.Error(), panicResult.Attachments()
+	}
+	// the error crosses the wire as a message, so its code is passed by the attachment
+	result.AddAttachment(constant.ErrorCodeKey, common.StatusInternal.String())
+	return result
+}
@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func exportExecutorTest(t *testing.T, path string, opts ...common.Option) (*DubboExporter, *invocation.RPCInvocation) {
+	opts = append(opts, common.WithPath(path), common.WithParamsValue(constant.InterfaceKey, path))
+	url, err := common.NewURL("dubbo://127.0.0.1:20098", opts...)
+	assert.NoError(t, err)
+	proto := GetProtocol().(*DubboProtocol)
+	exporter := NewDubboExporter(url.ServiceKey(), protocol.NewBaseInvoker(url), proto.ExporterMap())
+	proto.SetExporterMap(url.ServiceKey(), exporter)
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithAttachments(map[string]interface{}{constant.PathKey: path}))
+	return exporter, inv
+}
+
+func TestDispatchIsolation(t *testing.T) {
+	slow, slowInv := exportExecutorTest(t, "com.ikurento.user.SlowProvider",
+		common.WithParamsValue(constant.ExecutorWorkersKey, "2"),
+		common.WithParamsValue(constant.ExecutorQueueSizeKey, "2"))
+	defer slow.UnExport()
+	fast, fastInv := exportExecutorTest(t, "com.ikurento.user.FastProvider",
+		common.WithParamsValue(constant.ExecutorSharedKey, "true"))
+	defer fast.UnExport()
+	assert.True(t, slow.isolated)
+	assert.Equal(t, gopool.GetPool(executorPoolPrefix+slow.GetInvoker().GetURL().ServiceKey()), slow.executor)
+	assert.False(t, fast.isolated)
+	assert.Equal(t, gopool.GetPool(gopool.ProviderExecute), fast.executor)
+
+	// saturate the pool of the slow service
+	release := make(chan struct{})
+	var slowDone sync.WaitGroup
+	slowDone.Add(4)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, dispatch(slowInv, func() {
+			<-release
+			slowDone.Done()
+		}))
+	}
+	err := dispatch(slowInv, func() { t.Error("the rejected request is handled") })
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is exhausted")
+	assert.Equal(t, int64(1), slow.executor.Rejected())
+
+	// the latency of the other service is unaffected
+	for i := 0; i < 100; i++ {
+		start := time.Now()
+		done := make(chan struct{})
+		assert.NoError(t, dispatch(fastInv, func() { close(done) }))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("the request of the fast service waits for the slow service")
+		}
+		assert.True(t, time.Since(start) < 100*time.Millisecond)
+	}
+
+	close(release)
+	slowDone.Wait()
+}
+
+func TestDispatchInline(t *testing.T) {
+	exporter, inv := exportExecutorTest(t, "com.ikurento.user.InlineProvider")
+	defer exporter.UnExport()
+	assert.Nil(t, exporter.executor)
+	assert.False(t, exporter.isolated)
+
+	// the request is handled by the caller without any executor
+	handled := false
+	assert.NoError(t, dispatch(inv, func() { handled = true }))
+	assert.True(t, handled)
+}
+
+func TestDispatchRejection(t *testing.T) {
+	exporter, inv := exportExecutorTest(t, "com.ikurento.user.CallerRunsProvider",
+		common.WithParamsValue(constant.ExecutorWorkersKey, "1"),
+		common.WithParamsValue(constant.ExecutorQueueSizeKey, "0"),
+		common.WithParamsValue(constant.ExecutorRejectionKey, constant.ExecutorRejectionCallerRuns))
+	name := exporter.executor.Name()
+
+	release := make(chan struct{})
+	defer close(release)
+	assert.NoError(t, dispatch(inv, func() { <-release }))
+	// the rejected request is handled by the caller
+	handled := false
+	assert.NoError(t, dispatch(inv, func() { handled = true }))
+	assert.True(t, handled)
+	assert.Equal(t, int64(1), exporter.executor.Rejected())
+
+	// the requests of the unexported service are handled at once, which answers the errors
+	exporter.UnExport()
+	for _, pool := range gopool.GetPools() {
+		assert.NotEqual(t, name, pool.Name())
+	}
+	handled = false
+	assert.NoError(t, dispatch(inv, func() { handled = true }))
+	assert.True(t, handled)
+}
+
+func TestHandlePanic(t *testing.T) {
+	exporter, inv := exportExecutorTest(t, "com.ikurento.user.PanicProvider")
+	result := handlePanic(inv, "the provider is broken", nil)
+	exporter.UnExport()
+	// the panic value is answered only if it's enabled
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Err))
+	assert.NotContains(t, result.Err.Error(), "the provider is broken")
+	assert.Equal(t, common.StatusInternal.String(), result.Attachment(constant.ErrorCodeKey, ""))
+
+	exporter, inv = exportExecutorTest(t, "com.ikurento.user.PanicProvider",
+		common.WithParamsValue(constant.RecoveryPanicMessageKey, "true"))
+	result = handlePanic(inv, "the provider is broken", nil)
+	exporter.UnExport()
+	assert.Contains(t, result.Err.Error(), "the provider is broken")
+
+	// the panic of the unknown service is answered as well
+	result = handlePanic(inv, "the provider is broken", nil)
+	assert.Equal(t, common.StatusInternal, common.CodeOf(result.Err))
+	assert.NotContains(t, result.Err.Error(), "the provider is broken")
+}
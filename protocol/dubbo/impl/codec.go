@@ -20,6 +20,7 @@ package impl
 import (
 	"bufio"
 	"encoding/binary"
+	"io"
 )
 
 import (
@@ -36,7 +37,10 @@ import (
 )
 
 type ProtocolCodec struct {
-	reader     *bufio.Reader
+	reader *bufio.Reader
+	// data is the package read in place instead of the reader, and read is the bytes of it read so far
+	data       []byte
+	read       int
 	pkgType    PackageType
 	bodyLen    int
 	serializer Serializer
@@ -45,14 +49,14 @@ type ProtocolCodec struct {
 
 func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 	var err error
-	if c.reader.Size() < HEADER_LENGTH {
+	if c.size() < HEADER_LENGTH {
 		return hessian.ErrHeaderNotEnough
 	}
-	buf, err := c.reader.Peek(HEADER_LENGTH)
+	buf, err := c.peek(HEADER_LENGTH)
 	if err != nil { // this is impossible
 		return perrors.WithStack(err)
 	}
-	_, err = c.reader.Discard(HEADER_LENGTH)
+	err = c.discard(HEADER_LENGTH)
 	if err != nil { // this is impossible
 		return perrors.WithStack(err)
 	}
@@ -98,7 +102,7 @@ func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 	c.pkgType = header.Type
 	c.bodyLen = header.BodyLen
 
-	if c.reader.Buffered() < c.bodyLen {
+	if c.buffered() < c.bodyLen {
 		return hessian.ErrBodyNotEnough
 	}
 	c.headerRead = true
@@ -158,10 +162,10 @@ func (c *ProtocolCodec) Decode(p *DubboPackage) error {
 			return err
 		}
 	}
-	if c.reader.Size() < p.GetBodyLen() {
+	if c.size() < p.GetBodyLen() {
 		return hessian.ErrBodyNotEnough
 	}
-	body, err := c.reader.Peek(p.GetBodyLen())
+	body, err := c.peek(p.GetBodyLen())
 	if err != nil {
 		return err
 	}
@@ -194,90 +198,126 @@ func (c *ProtocolCodec) SetSerializer(serializer Serializer) {
 	c.serializer = serializer
 }
 
-func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
-	var (
-		byteArray []byte
-		pkgLen    int
-	)
+func (c *ProtocolCodec) size() int {
+	if c.reader == nil {
+		return len(c.data)
+	}
+	return c.reader.Size()
+}
 
-	header := p.Header
+func (c *ProtocolCodec) buffered() int {
+	if c.reader == nil {
+		return len(c.data) - c.read
+	}
+	return c.reader.Buffered()
+}
+
+// peek returns the next @n bytes without reading them, the bytes of data are not copied
+func (c *ProtocolCodec) peek(n int) ([]byte, error) {
+	if c.reader == nil {
+		if c.buffered() < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return c.data[c.read : c.read+n], nil
+	}
+	return c.reader.Peek(n)
+}
+
+func (c *ProtocolCodec) discard(n int) error {
+	if c.reader == nil {
+		if c.buffered() < n {
+			return io.ErrUnexpectedEOF
+		}
+		c.read += n
+		return nil
+	}
+	_, err := c.reader.Discard(n)
+	return err
+}
+
+func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
+	var header [HEADER_LENGTH]byte
 
-	//////////////////////////////////////////
-	// byteArray
-	//////////////////////////////////////////
 	// magic
-	switch header.Type {
+	switch p.Header.Type {
 	case PackageHeartbeat:
-		byteArray = append(byteArray, DubboRequestHeartbeatHeader[:]...)
+		header = DubboRequestHeartbeatHeader
 	case PackageRequest_TwoWay:
-		byteArray = append(byteArray, DubboRequestHeaderBytesTwoWay[:]...)
+		header = DubboRequestHeaderBytesTwoWay
 	default:
-		byteArray = append(byteArray, DubboRequestHeaderBytes[:]...)
+		header = DubboRequestHeaderBytes
 	}
 
 	// serialization id, two way flag, event, request/response flag
 	// SerialID is id of serialization approach in java dubbo
-	byteArray[2] |= header.SerialID & SERIAL_MASK
+	header[2] |= p.Header.SerialID & SERIAL_MASK
 	// request id
-	binary.BigEndian.PutUint64(byteArray[4:], uint64(header.ID))
+	binary.BigEndian.PutUint64(header[4:], uint64(p.Header.ID))
 
-	//////////////////////////////////////////
 	// body
-	//////////////////////////////////////////
 	if p.IsHeartBeat() {
-		byteArray = append(byteArray, byte('N'))
-		pkgLen = 1
-	} else {
-		body, err := serializer.Marshal(p)
-		if err != nil {
-			return nil, err
-		}
-		pkgLen = len(body)
-		if pkgLen > int(DEFAULT_LEN) { // recommand 8M
-			logger.Warnf("Data length %d too large, recommand max payload %d. "+
-				"Dubbo java can't handle the package whose size is greater than %d!!!", pkgLen, DEFAULT_LEN, DEFAULT_LEN)
-		}
-		byteArray = append(byteArray, body...)
+		byteArray := make([]byte, HEADER_LENGTH+1)
+		copy(byteArray, header[:])
+		byteArray[HEADER_LENGTH] = byte('N')
+		binary.BigEndian.PutUint32(byteArray[12:], 1)
+		return byteArray, nil
 	}
-	binary.BigEndian.PutUint32(byteArray[12:], uint32(pkgLen))
-	return byteArray, nil
+	return packBody(header[:], p, serializer)
 }
 
 func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
-	var byteArray []byte
-	header := p.Header
-	hb := p.IsHeartBeat()
+	var header [HEADER_LENGTH]byte
 
 	// magic
-	if hb {
-		byteArray = append(byteArray, DubboResponseHeartbeatHeader[:]...)
+	if p.IsHeartBeat() {
+		header = DubboResponseHeartbeatHeader
 	} else {
-		byteArray = append(byteArray, DubboResponseHeaderBytes[:]...)
+		header = DubboResponseHeaderBytes
 	}
 	// set serialID, identify serialization types, eg: fastjson->6, hessian2->2
-	byteArray[2] |= header.SerialID & SERIAL_MASK
+	header[2] |= p.Header.SerialID & SERIAL_MASK
 	// response status
-	if header.ResponseStatus != 0 {
-		byteArray[3] = header.ResponseStatus
+	if p.Header.ResponseStatus != 0 {
+		header[3] = p.Header.ResponseStatus
 	}
 
 	// request id
-	binary.BigEndian.PutUint64(byteArray[4:], uint64(header.ID))
+	binary.BigEndian.PutUint64(header[4:], uint64(p.Header.ID))
 
-	// body
-	body, err := serializer.Marshal(p)
-	if err != nil {
-		return nil, err
+	return packBody(header[:], p, serializer)
+}
+
+// packageSerializer marshals the body right after the header in its own buffer, so that the package is assembled
+// without copying the body
+type packageSerializer interface {
+	marshalPackage(header []byte, p DubboPackage) ([]byte, error)
+}
+
+// packBody returns the package of the @header and the body of @p marshaled by the @serializer
+func packBody(header []byte, p DubboPackage, serializer Serializer) ([]byte, error) {
+	var byteArray []byte
+	if ps, ok := serializer.(packageSerializer); ok {
+		pkg, err := ps.marshalPackage(header, p)
+		if err != nil {
+			return nil, err
+		}
+		byteArray = pkg
+	} else {
+		body, err := serializer.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		byteArray = make([]byte, 0, len(header)+len(body))
+		byteArray = append(append(byteArray, header...), body...)
 	}
 
-	pkgLen := len(body)
+	pkgLen := len(byteArray) - HEADER_LENGTH
 	if pkgLen > int(DEFAULT_LEN) { // recommand 8M
 		logger.Warnf("Data length %d too large, recommand max payload %d. "+
 			"Dubbo java can't handle the package whose size is greater than %d!!!", pkgLen, DEFAULT_LEN, DEFAULT_LEN)
 	}
 	// byteArray{body length}
 	binary.BigEndian.PutUint32(byteArray[12:], uint32(pkgLen))
-	byteArray = append(byteArray, body...)
 	return byteArray, nil
 }
 
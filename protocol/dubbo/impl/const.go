@@ -162,6 +162,8 @@ const (
 	Response_SERVICE_ERROR     byte = 70
 	Response_SERVER_ERROR      byte = 80
 	Response_CLIENT_ERROR      byte = 90
+	// Response_SERVER_THREADPOOL_EXHAUSTED_ERROR rejects the request for the rate limit of the provider
+	Response_SERVER_THREADPOOL_EXHAUSTED_ERROR byte = 100
 
 	// According to "java dubbo" There are two cases of response:
 	// 		1. with attachments
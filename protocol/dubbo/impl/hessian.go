@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -41,12 +42,52 @@ import (
 
 type HessianSerializer struct{}
 
+var (
+	// the encoders and the decoders are pooled, as creating them allocates their buffers and reference tables
+	encoderPool = sync.Pool{New: func() interface{} { return hessian.NewEncoder() }}
+	decoderPool = sync.Pool{New: func() interface{} { return hessian.NewDecoder(nil) }}
+)
+
+// acquireDecoder returns a pooled decoder of @body, whose references of the previous body are cleaned
+func acquireDecoder(body []byte) *hessian.Decoder {
+	return decoderPool.Get().(*hessian.Decoder).Reset(body)
+}
+
+func releaseDecoder(decoder *hessian.Decoder) {
+	decoderPool.Put(decoder)
+}
+
 func (h HessianSerializer) Marshal(p DubboPackage) ([]byte, error) {
-	encoder := hessian.NewEncoder()
+	return h.marshalPackage(nil, p)
+}
+
+// marshalPackage marshals the body of @p by a pooled encoder after the @header, the encoder buffer up to 512 bytes is
+// reused by the encoder, so the package is copied out of it, and the larger one is handed over without copying as
+// the encoder drops it
+func (h HessianSerializer) marshalPackage(header []byte, p DubboPackage) ([]byte, error) {
+	encoder := encoderPool.Get().(*hessian.Encoder)
+	defer func() {
+		encoder.ReuseBufferClean()
+		encoderPool.Put(encoder)
+	}()
+	encoder.Append(header)
+
+	var (
+		pkg []byte
+		err error
+	)
 	if p.IsRequest() {
-		return marshalRequest(encoder, p)
+		pkg, err = marshalRequest(encoder, p)
+	} else {
+		pkg, err = marshalResponse(encoder, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cap(encoder.Buffer()) <= 512 {
+		pkg = append(make([]byte, 0, len(pkg)), pkg...)
 	}
-	return marshalResponse(encoder, p)
+	return pkg, nil
 }
 
 func (h HessianSerializer) Unmarshal(input []byte, p *DubboPackage) error {
@@ -199,7 +240,8 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 	if p.Body == nil {
 		p.SetBody(make([]interface{}, 7))
 	}
-	decoder := hessian.NewDecoder(body)
+	decoder := acquireDecoder(body)
+	defer releaseDecoder(decoder)
 	var (
 		err                                                     error
 		dubboVersion, target, serviceVersion, method, argsTypes interface{}
@@ -269,7 +311,8 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 }
 
 func unmarshalResponseBody(body []byte, p *DubboPackage) error {
-	decoder := hessian.NewDecoder(body)
+	decoder := acquireDecoder(body)
+	defer releaseDecoder(decoder)
 	rspType, err := decoder.Decode()
 	if p.Body == nil {
 		p.SetBody(&ResponsePayload{})
@@ -18,7 +18,6 @@
 package impl
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"time"
@@ -154,13 +153,24 @@ func (p *DubboPackage) SetSerializer(serializer Serializer) {
 	p.Codec.SetSerializer(serializer)
 }
 
+// NewDubboPackage returns a package to marshal if @data is nil, or to unmarshal from @data otherwise, the unread
+// bytes of @data are read in place, so they must not be modified until the package is unmarshalled
 func NewDubboPackage(data *bytes.Buffer) *DubboPackage {
-	var codec *ProtocolCodec
 	if data == nil {
-		codec = NewDubboCodec(nil)
-	} else {
-		codec = NewDubboCodec(bufio.NewReaderSize(data, len(data.Bytes())))
+		return newDubboPackage(NewDubboCodec(nil))
 	}
+	return NewDubboPackageFromBytes(data.Bytes())
+}
+
+// NewDubboPackageFromBytes returns a package to unmarshal from @data in place without copying it, so @data must not
+// be modified until the package is unmarshalled. The body unmarshalled does not refer to @data.
+func NewDubboPackageFromBytes(data []byte) *DubboPackage {
+	codec := NewDubboCodec(nil)
+	codec.data = data
+	return newDubboPackage(codec)
+}
+
+func newDubboPackage(codec *ProtocolCodec) *DubboPackage {
 	return &DubboPackage{
 		Header:  DubboHeader{},
 		Service: Service{},
@@ -20,8 +20,6 @@ package dubbo3
 import (
 	"context"
 	"reflect"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 )
@@ -69,7 +67,7 @@ type DubboInvoker struct {
 func NewDubboInvoker(url *common.URL) (*DubboInvoker, error) {
 	rt := config.GetConsumerConfig().RequestTimeout
 
-	timeout := url.GetParamDuration(constant.TimeoutKey, rt)
+	timeout := url.GetParamDurationWithDefault(constant.TimeoutKey, rt)
 	// for triple pb serialization. The bean name from provider is the provider reference key,
 	// which can't locate the target consumer stub, so we use interface key..
 	interfaceKey := url.GetParam(constant.InterfaceKey, "")
@@ -118,6 +116,13 @@ func NewDubboInvoker(url *common.URL) (*DubboInvoker, error) {
 
 	triOption := triConfig.NewTripleOption(opts...)
 	tlsConfig := config.GetRootConfig().TLSConfig
+	if profile := config.GetProtocolTLSProfile(url.Protocol); profile != "" {
+		// triple loads the files of the profile itself, so the rotated certificates apply once it's restarted
+		var err error
+		if tlsConfig, err = config.GetTLSProfileConfig(profile); err != nil {
+			return nil, err
+		}
+	}
 	if tlsConfig != nil {
 		triOption.TLSCertFile = tlsConfig.TLSCertFile
 		triOption.TLSKeyFile = tlsConfig.TLSKeyFile
@@ -235,17 +240,10 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 
 // get timeout including methodConfig
 func (di *DubboInvoker) getTimeout(invocation *invocation_impl.RPCInvocation) time.Duration {
-	timeout := di.GetURL().GetParam(strings.Join([]string{constant.MethodKeys, invocation.MethodName(), constant.TimeoutKey}, "."), "")
-	if len(timeout) != 0 {
-		if t, err := time.ParseDuration(timeout); err == nil {
-			// config timeout into attachment
-			invocation.SetAttachment(constant.TimeoutKey, strconv.Itoa(int(t.Milliseconds())))
-			return t
-		}
-	}
+	timeout := di.GetURL().GetMethodParamDuration(invocation.MethodName(), constant.TimeoutKey, di.timeout)
 	// set timeout into invocation at method level
-	invocation.SetAttachment(constant.TimeoutKey, strconv.Itoa(int(di.timeout.Milliseconds())))
-	return di.timeout
+	invocation.SetAttachment(constant.TimeoutKey, common.FormatDuration(timeout))
+	return timeout
 }
 
 // IsAvailable check if invoker is available, now it is useless
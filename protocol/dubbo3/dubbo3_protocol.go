@@ -82,9 +82,13 @@ func (dp *DubboProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 	dp.SetExporterMap(serviceKey, exporter)
 	logger.Infof("[Triple Protocol] Export service: %s", url.String())
 
-	key := url.GetParam(constant.BeanNameKey, "")
-	var service interface{}
-	service = config.GetProviderService(key)
+	service, _ := url.GetAttribute(constant.RPCServiceAttributeKey)
+	if service == nil {
+		// the urls not built by the ServiceConfig have no service attribute
+		if s := common.ServiceMap.GetServiceByServiceKey(url.Protocol, url.ServiceKey()); s != nil {
+			service = s.Rcvr().Interface()
+		}
+	}
 
 	serializationType := url.GetParam(constant.SerializationKey, constant.ProtobufSerialization)
 	var triSerializationType tripleConstant.CodecType
@@ -94,7 +98,7 @@ func (dp *DubboProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 		if !ok {
 			logger.Errorf("PB service with key = %s is not support XXX_SetProxyImpl to pb."+
 				"Please run go install github.com/dubbogo/dubbogo-cli/cmd/protoc-gen-go-triple@latest to update your "+
-				"protoc-gen-go-triple and re-generate your pb file again.", key)
+				"protoc-gen-go-triple and re-generate your pb file again.", url.ServiceKey())
 			return nil
 		}
 		if invoker == nil {
@@ -257,6 +261,13 @@ func (dp *DubboProtocol) openServer(url *common.URL, tripleCodecType tripleConst
 	triOption := triConfig.NewTripleOption(opts...)
 
 	tlsConfig := config.GetRootConfig().TLSConfig
+	if profile := config.GetProtocolTLSProfile(url.Protocol); profile != "" {
+		// triple loads the files of the profile itself, so the rotated certificates apply once it's restarted
+		var err error
+		if tlsConfig, err = config.GetTLSProfileConfig(profile); err != nil {
+			panic(err)
+		}
+	}
 	if tlsConfig != nil {
 		triOption.TLSCertFile = tlsConfig.TLSCertFile
 		triOption.TLSKeyFile = tlsConfig.TLSKeyFile
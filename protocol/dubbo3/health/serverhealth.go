@@ -32,6 +32,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	healthpb "dubbo.apache.org/dubbo-go/v3/protocol/dubbo3/health/triple_health_v1"
 )
@@ -174,6 +175,13 @@ func (s *DubbogoHealthServer) Resume() {
 func init() {
 	healthServer = NewServer()
 	config.SetProviderService(healthServer)
+	config.AddConfigCenterHealthListener(func(healthy bool) {
+		if healthy {
+			SetServingStatusServing(constant.ConfigCenterHealthService)
+		} else {
+			SetServingStatusNotServing(constant.ConfigCenterHealthService)
+		}
+	})
 }
 
 func SetServingStatusServing(service string) {
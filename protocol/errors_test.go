@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func TestErrorCodes(t *testing.T) {
+	assert.Equal(t, common.StatusNotSent, common.CodeOf(perrors.WithStack(ErrClientClosed)))
+	assert.Equal(t, common.StatusNotSent, common.CodeOf(ErrDestroyedInvoker))
+	assert.Equal(t, common.StatusNoProvider, common.CodeOf(perrors.Wrap(ErrNoProvider, "invoke GetUser")))
+	assert.True(t, perrors.Is(perrors.Wrap(ErrNoProvider, "invoke GetUser"), ErrNoProvider))
+}
+
+func TestPanicError(t *testing.T) {
+	err := &PanicError{Value: "nil map"}
+	assert.EqualError(t, err, "nil map")
+	assert.Nil(t, err.Unwrap())
+
+	cause := perrors.New("index out of range")
+	err = &PanicError{Value: cause}
+	assert.EqualError(t, err, "index out of range")
+	assert.True(t, perrors.Is(err, cause))
+}
@@ -90,7 +90,14 @@ func NewClient(url *common.URL) (*Client, error) {
 	)
 	tlsConfig := config.GetRootConfig().TLSConfig
 
-	if tlsConfig != nil {
+	if profile := config.GetProtocolTLSProfile(url.Protocol); profile != "" {
+		cfg, err := config.GetClientTLSProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("Grpc Client initialized the tls profile %s", profile)
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+	} else if tlsConfig != nil {
 		cfg, err := config.GetClientTlsConfig(&config.TLSConfig{
 			CACertFile:    tlsConfig.CACertFile,
 			TLSCertFile:   tlsConfig.TLSCertFile,
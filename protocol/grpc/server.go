@@ -105,7 +105,14 @@ func (s *Server) Start(url *common.URL) {
 	)
 
 	tlsConfig := config.GetRootConfig().TLSConfig
-	if tlsConfig != nil {
+	if profile := config.GetProtocolTLSProfile(url.Protocol); profile != "" {
+		var cfg *tls.Config
+		if cfg, err = config.GetServerTLSProfile(profile); err != nil {
+			return
+		}
+		logger.Infof("Grpc Server initialized the tls profile %s", profile)
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(cfg)))
+	} else if tlsConfig != nil {
 		var cfg *tls.Config
 		cfg, err = config.GetServerTlsConfig(&config.TLSConfig{
 			CACertFile:    tlsConfig.CACertFile,
@@ -176,12 +183,6 @@ func waitGrpcExporter(providerServices map[string]*config.ServiceConfig) {
 // registerService SetProxyImpl invoker and grpc service
 func registerService(providerServices map[string]*config.ServiceConfig, server *grpc.Server) {
 	for key, providerService := range providerServices {
-		service := config.GetProviderService(key)
-		ds, ok := service.(DubboGrpcService)
-		if !ok {
-			panic("illegal service type registered")
-		}
-
 		serviceKey := common.ServiceKey(providerService.Interface, providerService.Group, providerService.Version)
 		exporter, _ := grpcProtocol.ExporterMap().Load(serviceKey)
 		if exporter == nil {
@@ -192,6 +193,15 @@ func registerService(providerServices map[string]*config.ServiceConfig, server *
 			panic(fmt.Sprintf("no invoker found for servicekey: %v", serviceKey))
 		}
 
+		service, _ := invoker.GetURL().GetAttribute(constant.RPCServiceAttributeKey)
+		if service == nil {
+			service = config.GetProviderService(key)
+		}
+		ds, ok := service.(DubboGrpcService)
+		if !ok {
+			panic("illegal service type registered")
+		}
+
 		ds.SetProxyImpl(invoker)
 		server.RegisterService(ds.ServiceDesc(), service)
 	}
@@ -35,9 +35,10 @@ import (
 )
 
 var (
-	ErrClientClosed     = perrors.New("remoting client has closed")
+	ErrClientClosed     = common.NewRPCError(common.StatusNotSent, perrors.New("remoting client has closed"))
 	ErrNoReply          = perrors.New("request need @response")
-	ErrDestroyedInvoker = perrors.New("request Destroyed invoker")
+	ErrDestroyedInvoker = common.NewRPCError(common.StatusNotSent, perrors.New("request Destroyed invoker"))
+	ErrNoProvider       = common.NewRPCError(common.StatusNoProvider, perrors.New("No provider available"))
 )
 
 // Invoker the service invocation interface for the consumer
@@ -88,11 +89,14 @@ func (bi *BaseInvoker) Invoke(context context.Context, invocation Invocation) Re
 	return &RPCResult{}
 }
 
-// Destroy changes available and destroyed flag
+// Destroy changes available and destroyed flag, and removes the RPC status of the URL
 func (bi *BaseInvoker) Destroy() {
 	logger.Infof("Destroy invoker: %s", bi.GetURL())
 	bi.destroyed.Store(true)
 	bi.available.Store(false)
+	if bi.url != nil {
+		RemoveURLStatus(bi.url)
+	}
 }
 
 func (bi *BaseInvoker) String() string {
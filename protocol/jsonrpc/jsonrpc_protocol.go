@@ -81,7 +81,7 @@ func (jp *JsonrpcProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 func (jp *JsonrpcProtocol) Refer(url *common.URL) protocol.Invoker {
 	rtStr := config.GetConsumerConfig().RequestTimeout
 	// the read order of requestTimeout is from url , if nil then from consumer config , if nil then default 3s. requestTimeout can be dynamically updated from config center.
-	requestTimeout := url.GetParamDuration(constant.TimeoutKey, rtStr)
+	requestTimeout := url.GetParamDurationWithDefault(constant.TimeoutKey, rtStr)
 	// New Json rpc Invoker
 	invoker := NewJsonrpcInvoker(url, NewHTTPClient(&HTTPOptions{
 		HandshakeTimeout: time.Second, // todo config timeout config.GetConsumerConfig().ConnectTimeout,
@@ -165,7 +165,7 @@ func (s *Server) handlePkg(conn net.Conn) {
 		}
 
 		if len(reqHeader["Timeout"]) > 0 {
-			timeout, err := time.ParseDuration(reqHeader["Timeout"])
+			timeout, err := common.ParseDuration(reqHeader["Timeout"])
 			if err == nil {
 				httpTimeout = timeout
 				var cancel context.CancelFunc
@@ -54,14 +54,16 @@ type Exporter interface {
 
 // BaseProtocol is default protocol implement.
 type BaseProtocol struct {
-	exporterMap *sync.Map
-	invokers    []Invoker
+	exporterMap  *sync.Map
+	invokersLock *sync.Mutex
+	invokers     []Invoker
 }
 
 // NewBaseProtocol creates a new BaseProtocol
 func NewBaseProtocol() BaseProtocol {
 	return BaseProtocol{
-		exporterMap: new(sync.Map),
+		exporterMap:  new(sync.Map),
+		invokersLock: new(sync.Mutex),
 	}
 }
 
@@ -75,14 +77,29 @@ func (bp *BaseProtocol) ExporterMap() *sync.Map {
 	return bp.exporterMap
 }
 
-// SetInvokers sets invoker into local memory
+// SetInvokers adds @invoker to the invokers of the protocol, the invokers destroyed at runtime are dropped so that
+// referring and destroying repeatedly doesn't leak them
 func (bp *BaseProtocol) SetInvokers(invoker Invoker) {
-	bp.invokers = append(bp.invokers, invoker)
+	bp.invokersLock.Lock()
+	defer bp.invokersLock.Unlock()
+	alive := bp.invokers[:0]
+	for _, ivk := range bp.invokers {
+		if d, ok := ivk.(interface{ IsDestroyed() bool }); ok && d.IsDestroyed() {
+			continue
+		}
+		alive = append(alive, ivk)
+	}
+	for i := len(alive); i < len(bp.invokers); i++ {
+		bp.invokers[i] = nil
+	}
+	bp.invokers = append(alive, invoker)
 }
 
 // Invokers gets all invokers
 func (bp *BaseProtocol) Invokers() []Invoker {
-	return bp.invokers
+	bp.invokersLock.Lock()
+	defer bp.invokersLock.Unlock()
+	return append([]Invoker(nil), bp.invokers...)
 }
 
 // Export is default export implement.
@@ -98,12 +115,15 @@ func (bp *BaseProtocol) Refer(url *common.URL) Invoker {
 // Destroy will destroy all invoker and exporter, so it only is called once.
 func (bp *BaseProtocol) Destroy() {
 	// destroy invokers
-	for _, invoker := range bp.invokers {
+	bp.invokersLock.Lock()
+	invokers := bp.invokers
+	bp.invokers = []Invoker{}
+	bp.invokersLock.Unlock()
+	for _, invoker := range invokers {
 		if invoker != nil {
 			invoker.Destroy()
 		}
 	}
-	bp.invokers = []Invoker{}
 
 	// un export exporters
 	bp.exporterMap.Range(func(key, exporter interface{}) bool {
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func TestBaseProtocolSetInvokers(t *testing.T) {
+	url, err := common.NewURL("dubbo://localhost:9090")
+	assert.Nil(t, err)
+
+	bp := NewBaseProtocol()
+	first := NewBaseInvoker(url)
+	bp.SetInvokers(first)
+	for i := 0; i < 10; i++ {
+		ivk := NewBaseInvoker(url)
+		bp.SetInvokers(ivk)
+		ivk.Destroy()
+	}
+	last := NewBaseInvoker(url)
+	bp.SetInvokers(last)
+	assert.Equal(t, []Invoker{first, last}, bp.Invokers())
+
+	bp.Destroy()
+	assert.Empty(t, bp.Invokers())
+	assert.True(t, first.IsDestroyed())
+	assert.True(t, last.IsDestroyed())
+}
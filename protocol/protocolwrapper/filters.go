@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocolwrapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+// ResolveFilters resolves the filter specification @spec of a service or a reference against the @defaults filters,
+// the same as dubbo java, e.g. "-accesslog,myFilter,default":
+//   - "-name" removes the filter name, and "-default" removes all the default filters
+//   - "default" expands to the default filters at its position, which are put ahead of the others if absent
+//   - the others keep their explicit order, and a default filter named explicitly is moved to its position
+//
+// A filter named twice, or both named and removed, is an error.
+func ResolveFilters(spec string, defaults []string) ([]string, error) {
+	names := make([]string, 0, 8)
+	named := make(map[string]bool)
+	removed := make(map[string]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.HasPrefix(name, constant.RemoveValuePrefix) {
+			name = strings.TrimSpace(strings.TrimPrefix(name, constant.RemoveValuePrefix))
+			if named[name] {
+				return nil, fmt.Errorf("the filter %s is both configured and removed in %q", name, spec)
+			}
+			removed[name] = true
+			continue
+		}
+		if removed[name] {
+			return nil, fmt.Errorf("the filter %s is both configured and removed in %q", name, spec)
+		}
+		if named[name] {
+			return nil, fmt.Errorf("the filter %s is duplicated in %q", name, spec)
+		}
+		named[name] = true
+		names = append(names, name)
+	}
+	if !named[constant.DefaultKey] {
+		names = append([]string{constant.DefaultKey}, names...)
+	}
+
+	filters := make([]string, 0, len(names)+len(defaults))
+	expanded := make(map[string]bool)
+	for _, name := range names {
+		if name != constant.DefaultKey {
+			filters = append(filters, name)
+			continue
+		}
+		if removed[constant.DefaultKey] {
+			continue
+		}
+		for _, d := range defaults {
+			if d == "" || named[d] || removed[d] || expanded[d] {
+				continue
+			}
+			expanded[d] = true
+			filters = append(filters, d)
+		}
+	}
+	return filters, nil
+}
+
+// CheckFilters returns an error if any of @filters is not registered
+func CheckFilters(filters []string) error {
+	for _, name := range filters {
+		if extension.IsFilterFactory(name) {
+			continue
+		}
+		if _, ok := extension.GetFilter(name); !ok {
+			return extension.UnknownExtensionError(extension.KindFilter, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocolwrapper
+
+import (
+	"net/url"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func TestResolveFilters(t *testing.T) {
+	defaults := []string{"echo", "token", "accesslog", "tps"}
+	for spec, expected := range map[string][]string{
+		"":                            {"echo", "token", "accesslog", "tps"},
+		"myFilter":                    {"echo", "token", "accesslog", "tps", "myFilter"},
+		"myFilter,default":            {"myFilter", "echo", "token", "accesslog", "tps"},
+		"-accesslog,myFilter,default": {"myFilter", "echo", "token", "tps"},
+		"tps, default ,echo":          {"tps", "token", "accesslog", "echo"},
+		"-default,myFilter,token":     {"myFilter", "token"},
+		"-default":                    {},
+		"-unknown,,myFilter":          {"echo", "token", "accesslog", "tps", "myFilter"},
+	} {
+		filters, err := ResolveFilters(spec, defaults)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, filters, spec)
+	}
+
+	for _, spec := range []string{"myFilter,myFilter", "tps,-tps", "-tps,tps", "default,default", "-default,default"} {
+		_, err := ResolveFilters(spec, defaults)
+		assert.Error(t, err, spec)
+	}
+
+	// the duplicated defaults are expanded once
+	filters, err := ResolveFilters("", []string{"echo", "echo", "tps"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"echo", "tps"}, filters)
+}
+
+func TestCheckFilters(t *testing.T) {
+	assert.NoError(t, CheckFilters([]string{mockFilterKey}))
+	assert.Error(t, CheckFilters([]string{mockFilterKey, "unknown"}))
+}
+
+func TestBuildInvokerChain(t *testing.T) {
+	newInvoker := func(filters string) protocol.Invoker {
+		return protocol.NewBaseInvoker(common.NewURLWithOptions(
+			common.WithParams(url.Values{}),
+			common.WithParamsValue(constant.ServiceFilterKey, filters)))
+	}
+
+	invoker := BuildInvokerChain(newInvoker("-default,"+mockFilterKey), constant.ServiceFilterKey)
+	_, ok := invoker.(*FilterInvoker)
+	assert.True(t, ok)
+
+	invoker = BuildInvokerChain(newInvoker("-"+mockFilterKey), constant.ServiceFilterKey)
+	_, ok = invoker.(*FilterInvoker)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		BuildInvokerChain(newInvoker(mockFilterKey+",unknown"), constant.ServiceFilterKey)
+	})
+	assert.Panics(t, func() {
+		BuildInvokerChain(newInvoker(mockFilterKey+","+mockFilterKey), constant.ServiceFilterKey)
+	})
+}
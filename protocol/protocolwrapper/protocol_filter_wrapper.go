@@ -20,6 +20,7 @@ package protocolwrapper
 import (
 	"context"
 	"strings"
+	"sync"
 )
 
 import (
@@ -39,6 +40,11 @@ const (
 	FILTER = "filter"
 )
 
+// urlFilters caches the filters created by the factories, keyed by the name, the side and the service of the url,
+// so that the filter is created once per exported service or reference, e.g. the invokers of the providers of a
+// reference share the same filter
+var urlFilters sync.Map
+
 func init() {
 	extension.SetProtocol(FILTER, GetProtocol)
 }
@@ -75,17 +81,24 @@ func (pfw *ProtocolFilterWrapper) Destroy() {
 	pfw.protocol.Destroy()
 }
 
+// BuildInvokerChain builds the chain of the filters resolved from the specification @key of the url of @invoker, and
+// panics if it is invalid or any of the filters is not registered, see ResolveFilters
 func BuildInvokerChain(invoker protocol.Invoker, key string) protocol.Invoker {
-	filterName := invoker.GetURL().GetParam(key, "")
-	if filterName == "" {
+	filterNames, err := ResolveFilters(invoker.GetURL().GetParam(key, ""), nil)
+	if err == nil {
+		err = CheckFilters(filterNames)
+	}
+	if err != nil {
+		panic(err)
+	}
+	if len(filterNames) == 0 {
 		return invoker
 	}
-	filterNames := strings.Split(filterName, ",")
 
 	// The order of filters is from left to right, so loading from right to left
 	next := invoker
 	for i := len(filterNames) - 1; i >= 0; i-- {
-		flt, _ := extension.GetFilter(strings.TrimSpace(filterNames[i]))
+		flt := filterOf(filterNames[i], invoker.GetURL(), key)
 		fi := &FilterInvoker{next: next, invoker: invoker, filter: flt}
 		next = fi
 	}
@@ -132,3 +145,19 @@ func (fi *FilterInvoker) Invoke(ctx context.Context, invocation protocol.Invocat
 func (fi *FilterInvoker) Destroy() {
 	fi.invoker.Destroy()
 }
+
+// filterOf returns the filter @name of the service or the reference of @url, the filter created by the factory is
+// cached by the service of @url and the side @key
+func filterOf(name string, url *common.URL, key string) filter.Filter {
+	if !extension.IsFilterFactory(name) {
+		flt, _ := extension.GetFilter(name)
+		return flt
+	}
+	cacheKey := strings.Join([]string{name, key, url.Protocol, url.ServiceKey()}, "|")
+	if flt, ok := urlFilters.Load(cacheKey); ok {
+		return flt.(filter.Filter)
+	}
+	flt, _ := extension.GetFilterByURL(name, url)
+	actual, _ := urlFilters.LoadOrStore(cacheKey, flt)
+	return actual.(filter.Filter)
+}
@@ -37,7 +37,10 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
-const mockFilterKey = "mockEcho"
+const (
+	mockFilterKey        = "mockEcho"
+	mockFactoryFilterKey = "mockFactory"
+)
 
 func TestProtocolFilterWrapperExport(t *testing.T) {
 	filtProto := extension.GetProtocol(FILTER)
@@ -63,9 +66,44 @@ func TestProtocolFilterWrapperRefer(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestBuildInvokerChainWithFilterFactory(t *testing.T) {
+	newInvoker := func(ip, path string) protocol.Invoker {
+		u, _ := common.NewURL("dubbo://" + ip + ":20000/" + path + "?" + constant.ReferenceFilterKey + "=" +
+			mockFactoryFilterKey + "&" + constant.VersionKey + "=1.0.0")
+		return protocol.NewBaseInvoker(u)
+	}
+	filterOfChain := func(invoker protocol.Invoker) *mockFactoryFilter {
+		return BuildInvokerChain(invoker, constant.ReferenceFilterKey).(*FilterInvoker).filter.(*mockFactoryFilter)
+	}
+
+	userFilter := filterOfChain(newInvoker("127.0.0.1", "com.test.UserService"))
+	assert.Equal(t, "com.test.UserService", userFilter.url.Service())
+	// the invokers of the providers of the same reference share the filter
+	assert.Same(t, userFilter, filterOfChain(newInvoker("127.0.0.2", "com.test.UserService")))
+	// but the other reference has its own one
+	orderFilter := filterOfChain(newInvoker("127.0.0.1", "com.test.OrderService"))
+	assert.NotSame(t, userFilter, orderFilter)
+	assert.Equal(t, "com.test.OrderService", orderFilter.url.Service())
+}
+
 // The initialization of mockEchoFilter, for test
 func init() {
 	extension.SetFilter(mockFilterKey, newFilter)
+	extension.SetFilterFactory(mockFactoryFilterKey, func(url *common.URL) filter.Filter {
+		return &mockFactoryFilter{url: url}
+	})
+}
+
+type mockFactoryFilter struct {
+	url *common.URL
+}
+
+func (f *mockFactoryFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	return invoker.Invoke(ctx, invocation)
+}
+
+func (f *mockFactoryFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
 }
 
 type mockEchoFilter struct{}
@@ -19,7 +19,6 @@ package rest
 
 import (
 	"sync"
-	"time"
 )
 
 import (
@@ -90,13 +89,9 @@ func (rp *RestProtocol) Refer(url *common.URL) protocol.Invoker {
 	// create rest_invoker
 	// todo fix timeout config
 	// start
-	requestTimeout := time.Duration(3 * time.Second)
-	requestTimeoutStr := url.GetParam(constant.TimeoutKey, "3s")
+	requestTimeout := url.GetParamDurationWithDefault(constant.TimeoutKey, "3s")
 	connectTimeout := requestTimeout // config.GetConsumerConfig().ConnectTimeout
 	// end
-	if t, err := time.ParseDuration(requestTimeoutStr); err == nil {
-		requestTimeout = t
-	}
 	id := url.GetParam(constant.BeanNameKey, "")
 	restServiceConfig := rest_config.GetRestConsumerServiceConfig(id)
 	if restServiceConfig == nil {
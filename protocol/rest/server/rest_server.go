@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 import (
@@ -33,6 +34,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
@@ -110,7 +112,7 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 				logger.Errorf("[Go Restful] WriteErrorString error:%v", err)
 			}
 		}
-		result := invoker.Invoke(context.Background(), invocation.NewRPCInvocation(methodConfig.MethodName, args, make(map[string]interface{})))
+		result := invoker.Invoke(context.Background(), invocation.NewRPCInvocation(methodConfig.MethodName, args, requestAttachments(req)))
 		if result.Error() != nil {
 			err = resp.WriteError(http.StatusInternalServerError, result.Error())
 			if err != nil {
@@ -125,6 +127,19 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 	}
 }
 
+// requestAttachments returns the attachments of the address of the caller, which may be a proxy forwarding the
+// request of the real caller in the x-forwarded-for header
+func requestAttachments(req RestServerRequest) map[string]interface{} {
+	attachments := make(map[string]interface{})
+	if raw := req.RawRequest(); raw != nil {
+		attachments[constant.RemoteAddr] = raw.RemoteAddr
+		if forwardedFor := raw.Header.Values("X-Forwarded-For"); len(forwardedFor) != 0 {
+			attachments[constant.ForwardedForKey] = strings.Join(forwardedFor, ",")
+		}
+	}
+	return attachments
+}
+
 // getArgsInterfaceFromRequest when service function like GetUser(req []interface{}, rsp *User) error
 // use this method to get arguments
 func getArgsInterfaceFromRequest(req RestServerRequest, methodConfig *rest_config.RestMethodConfig) ([]interface{}, error) {
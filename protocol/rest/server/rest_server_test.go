@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type rawRequest struct {
+	RestServerRequest
+	request *http.Request
+}
+
+func (r *rawRequest) RawRequest() *http.Request {
+	return r.request
+}
+
+func TestRequestAttachments(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/users/1", nil)
+	assert.NoError(t, err)
+	request.RemoteAddr = "192.168.0.1:52000"
+	attachments := requestAttachments(&rawRequest{request: request})
+	assert.Equal(t, "192.168.0.1:52000", attachments[constant.RemoteAddr])
+	assert.NotContains(t, attachments, constant.ForwardedForKey)
+
+	request.Header.Add("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	request.Header.Add("X-Forwarded-For", "10.0.0.3")
+	attachments = requestAttachments(&rawRequest{request: request})
+	assert.Equal(t, "10.0.0.1, 10.0.0.2,10.0.0.3", attachments[constant.ForwardedForKey])
+}
@@ -202,6 +202,17 @@ func CleanAllStatus() {
 	invokerBlackList.Range(delete3)
 }
 
+// RemoveURLStatus removes the RPC status and the unhealthy status of @url, it's called when the invokers of @url
+// are destroyed
+func RemoveURLStatus(url *common.URL) {
+	key := url.Key()
+	serviceStatistic.Delete(key)
+	methodStatistics.Delete(key)
+	if _, found := invokerBlackList.LoadAndDelete(key); found {
+		blackListCacheDirty.Store(true)
+	}
+}
+
 // GetInvokerHealthyStatus get invoker's conn healthy status
 func GetInvokerHealthyStatus(invoker Invoker) bool {
 	_, found := invokerBlackList.Load(invoker.GetURL().Key())
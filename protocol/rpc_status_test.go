@@ -78,6 +78,21 @@ func TestGetUrlStatus(t *testing.T) {
 	assert.Equal(t, int32(0), status.total)
 }
 
+func TestRemoveURLStatus(t *testing.T) {
+	defer CleanAllStatus()
+
+	url, _ := common.NewURL(mockCommonDubboUrl)
+	BeginCount(url, "test")
+	ivk := NewBaseInvoker(url)
+	SetInvokerUnhealthyStatus(ivk)
+	assert.False(t, GetInvokerHealthyStatus(ivk))
+
+	ivk.Destroy()
+	assert.True(t, GetInvokerHealthyStatus(ivk))
+	assert.Equal(t, int32(0), GetURLStatus(url).active)
+	assert.Equal(t, int32(0), GetMethodStatus(url, "test").active)
+}
+
 func TestBeginCount0(t *testing.T) {
 	defer CleanAllStatus()
 
@@ -18,15 +18,16 @@
 package proxy_factory
 
 import (
-	"fmt"
 	"reflect"
+	"runtime/debug"
 )
 
 import (
-	perrors "github.com/pkg/errors"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 // CallLocalMethod is used to handle invoke exception in user func.
+// A panic in user func is returned as a *protocol.PanicError with the stack.
 func callLocalMethod(method reflect.Method, in []reflect.Value) ([]reflect.Value, error) {
 	var (
 		returnValues []reflect.Value
@@ -36,13 +37,7 @@ func callLocalMethod(method reflect.Method, in []reflect.Value) ([]reflect.Value
 	func() {
 		defer func() {
 			if e := recover(); e != nil {
-				if err, ok := e.(error); ok {
-					retErr = err
-				} else if err, ok := e.(string); ok {
-					retErr = perrors.New(err)
-				} else {
-					retErr = fmt.Errorf("invoke function error, unknow exception: %+v", e)
-				}
+				retErr = &protocol.PanicError{Value: e, Stack: debug.Stack()}
 			}
 		}()
 
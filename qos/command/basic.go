@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+	registryProtocol "dubbo.apache.org/dubbo-go/v3/registry/protocol"
+)
+
+// helpCommand lists the usages of all the commands, or describes the command of its argument
+type helpCommand struct{}
+
+func (c *helpCommand) Usage() string {
+	return "help [command]"
+}
+
+func (c *helpCommand) Description() string {
+	return "Show the usages of the commands"
+}
+
+func (c *helpCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionPublic
+}
+
+func (c *helpCommand) Execute(ctx *qos.CommandContext) (string, error) {
+	if name := ctx.Arg(0); name != "" {
+		cmd, err := extension.GetQosCommand(name)
+		if err != nil {
+			return "", fmt.Errorf("unknown command %q", name)
+		}
+		return fmt.Sprintf("%s\n\t%s\n", cmd.Usage(), cmd.Description()), nil
+	}
+	var rows [][]string
+	for _, name := range extension.GetSupported(extension.KindQosCommand) {
+		if cmd, err := extension.GetQosCommand(name); err == nil {
+			rows = append(rows, []string{cmd.Usage(), cmd.Description()})
+		}
+	}
+	return renderTable([]string{"COMMAND", "DESCRIPTION"}, rows), nil
+}
+
+// quitCommand closes the telnet session
+type quitCommand struct{}
+
+func (c *quitCommand) Usage() string {
+	return "quit"
+}
+
+func (c *quitCommand) Description() string {
+	return "Close the telnet session"
+}
+
+func (c *quitCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionPublic
+}
+
+func (c *quitCommand) Execute(ctx *qos.CommandContext) (string, error) {
+	ctx.Quit = true
+	return "BYE!\n", nil
+}
+
+// onlineCommand registers the services to the registries again if online, otherwise unregisters them
+type onlineCommand struct {
+	online bool
+}
+
+func (c *onlineCommand) name() string {
+	if c.online {
+		return "online"
+	}
+	return "offline"
+}
+
+func (c *onlineCommand) Usage() string {
+	return c.name() + " [pattern]"
+}
+
+func (c *onlineCommand) Description() string {
+	if c.online {
+		return "Register the services matching the regular expression, or all the services, to the registries"
+	}
+	return "Unregister the services matching the regular expression, or all the services, from the registries"
+}
+
+func (c *onlineCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionPrivate
+}
+
+func (c *onlineCommand) Execute(ctx *qos.CommandContext) (string, error) {
+	change := registryProtocol.Offline
+	if c.online {
+		change = registryProtocol.Online
+	}
+	serviceKeys, err := change(ctx.Arg(0))
+	if err != nil {
+		return "", err
+	}
+	if len(serviceKeys) == 0 {
+		return "No service changed\n", nil
+	}
+	return fmt.Sprintf("%s: %s\nOK\n", c.name(), strings.Join(serviceKeys, ", ")), nil
+}
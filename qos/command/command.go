@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package command implements the built-in commands of the QoS server, which are help, quit, ls, online, offline,
+// invokers and inboundlimit.
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+)
+
+func init() {
+	extension.SetQosCommand("help", func() qos.Command { return &helpCommand{} })
+	extension.SetQosCommand("quit", func() qos.Command { return &quitCommand{} })
+	extension.SetQosCommand("ls", func() qos.Command { return &lsCommand{} })
+	extension.SetQosCommand("online", func() qos.Command { return &onlineCommand{online: true} })
+	extension.SetQosCommand("offline", func() qos.Command { return &onlineCommand{} })
+	extension.SetQosCommand("invokers", func() qos.Command { return &invokersCommand{} })
+	extension.SetQosCommand("inboundlimit", func() qos.Command { return &inboundLimitCommand{} })
+}
+
+// renderTable renders the @rows under the @header as a table with borders, e.g.
+//
+//	+-----------------------------+-----+
+//	| Provider Service Name       | PUB |
+//	+-----------------------------+-----+
+//	| org.apache.dubbo.Greeter    | Y   |
+//	+-----------------------------+-----+
+func renderTable(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	var sb strings.Builder
+	border := func() {
+		for _, width := range widths {
+			sb.WriteString("+" + strings.Repeat("-", width+2))
+		}
+		sb.WriteString("+\n")
+	}
+	line := func(row []string) {
+		for i, cell := range row {
+			sb.WriteString(fmt.Sprintf("| %-*s ", widths[i], cell))
+		}
+		sb.WriteString("|\n")
+	}
+	border()
+	line(header)
+	border()
+	for _, row := range rows {
+		line(row)
+	}
+	if len(rows) > 0 {
+		border()
+	}
+	return sb.String()
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/inspect"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+func TestRenderTable(t *testing.T) {
+	assert.Equal(t, "+------+-----+\n"+
+		"| NAME | PUB |\n"+
+		"+------+-----+\n"+
+		"| a    | Y   |\n"+
+		"| abcd | N   |\n"+
+		"+------+-----+\n", renderTable([]string{"NAME", "PUB"}, [][]string{{"a", "Y"}, {"abcd", "N"}}))
+	assert.Equal(t, "+---+\n| A |\n+---+\n", renderTable([]string{"A"}, nil))
+}
+
+func TestHelp(t *testing.T) {
+	help, err := extension.GetQosCommand("help")
+	assert.NoError(t, err)
+	assert.Equal(t, qos.PermissionPublic, help.Permission())
+
+	output, err := help.Execute(&qos.CommandContext{})
+	assert.NoError(t, err)
+	for _, usage := range []string{"help [command]", "quit", "ls", "online [pattern]", "offline [pattern]"} {
+		assert.Contains(t, output, "| "+usage+" ")
+	}
+
+	output, err = help.Execute(&qos.CommandContext{Args: []string{"offline"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "offline [pattern]\n\tUnregister the services matching the regular expression, or all the services, "+
+		"from the registries\n", output)
+
+	_, err = help.Execute(&qos.CommandContext{Args: []string{"unknown"}})
+	assert.EqualError(t, err, `unknown command "unknown"`)
+}
+
+func TestQuit(t *testing.T) {
+	ctx := &qos.CommandContext{}
+	output, err := (&quitCommand{}).Execute(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "BYE!\n", output)
+	assert.True(t, ctx.Quit)
+}
+
+func TestLs(t *testing.T) {
+	output, err := (&lsCommand{}).Execute(&qos.CommandContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, "As Provider side:\n"+
+		"+-----------------------+-----+\n"+
+		"| Provider Service Name | PUB |\n"+
+		"+-----------------------+-----+\n"+
+		"As Consumer side:\n"+
+		"+-----------------------+-----+\n"+
+		"| Consumer Service Name | NUM |\n"+
+		"+-----------------------+-----+\n", output)
+}
+
+func TestInvokers(t *testing.T) {
+	_, err := (&invokersCommand{}).Execute(&qos.CommandContext{})
+	assert.EqualError(t, err, "the service key is required")
+	_, err = (&invokersCommand{}).Execute(&qos.CommandContext{Args: []string{"org.apache.dubbo.Greeter"}})
+	assert.EqualError(t, err, `unknown reference "org.apache.dubbo.Greeter"`)
+
+	output := renderSnapshot(&inspect.Snapshot{
+		ServiceKey: "org.apache.dubbo.Greeter",
+		URL:        "dubbo://127.0.0.1:20000/org.apache.dubbo.Greeter?token=******",
+		Providers: []inspect.Provider{{Address: "127.0.0.1:20000", Weight: 100, Available: true, Routed: true,
+			Circuits: map[string]string{"SayHello": "open"}, Selections: 3}},
+		Routes: []inspect.Route{{Router: "tag.PriorityRouter", Remained: 1}},
+	})
+	assert.Equal(t, "Reference: org.apache.dubbo.Greeter\n"+
+		"URL: dubbo://127.0.0.1:20000/org.apache.dubbo.Greeter?token=******\n"+
+		"Providers:\n"+
+		"+-----------------+--------+-----------+--------+---------+---------------+------------+\n"+
+		"| Address         | Weight | Available | Routed | Ejected | Circuit       | Selections |\n"+
+		"+-----------------+--------+-----------+--------+---------+---------------+------------+\n"+
+		"| 127.0.0.1:20000 | 100    | Y         | Y      | N       | SayHello=open | 3          |\n"+
+		"+-----------------+--------+-----------+--------+---------+---------------+------------+\n"+
+		"Routes:\n"+
+		"+--------------------+----------+----------+\n"+
+		"| Router             | Filtered | Remained |\n"+
+		"+--------------------+----------+----------+\n"+
+		"| tag.PriorityRouter | -        | 1        |\n"+
+		"+--------------------+----------+----------+\n"+
+		"Decisions:\n"+
+		"+------+--------+---------+--------+-------+------------+\n"+
+		"| Time | Method | Address | Reason | Retry | Candidates |\n"+
+		"+------+--------+---------+--------+-------+------------+\n", output)
+}
+
+func TestInboundLimit(t *testing.T) {
+	_, err := (&inboundLimitCommand{}).Execute(&qos.CommandContext{Args: []string{"-1"}})
+	assert.EqualError(t, err, `invalid top "-1", it should be a non-negative number`)
+	_, err = (&inboundLimitCommand{}).Execute(&qos.CommandContext{Args: []string{"5", "size"}})
+	assert.EqualError(t, err, `invalid sort "size", it should be requests, bytes or throttled`)
+	output, err := (&inboundLimitCommand{}).Execute(&qos.CommandContext{HTTP: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", output)
+
+	output = renderInboundLimits(map[string]getty.InboundLimitStats{"0.0.0.0:20000": {
+		Throttled:  map[string]int64{"ip-requests": 3, "connection-requests": 0},
+		Closed:     1,
+		TopTalkers: []getty.TalkerStats{{IP: "192.168.0.1", Connections: 2, Requests: 10, Bytes: 1024, Throttled: 3}},
+	}})
+	assert.Equal(t, "Server: 0.0.0.0:20000\n"+
+		"+---------------------+-----------+\n"+
+		"| Limit               | Throttled |\n"+
+		"+---------------------+-----------+\n"+
+		"| connection-requests | 0         |\n"+
+		"| ip-requests         | 3         |\n"+
+		"+---------------------+-----------+\n"+
+		"Closed: 1\n"+
+		"Top Talkers:\n"+
+		"+-------------+-------------+----------+-------+-----------+\n"+
+		"| IP          | Connections | Requests | Bytes | Throttled |\n"+
+		"+-------------+-------------+----------+-------+-----------+\n"+
+		"| 192.168.0.1 | 2           | 10       | 1024  | 3         |\n"+
+		"+-------------+-------------+----------+-------+-----------+\n", output)
+}
+
+func TestOnlineAndOffline(t *testing.T) {
+	offline, err := extension.GetQosCommand("offline")
+	assert.NoError(t, err)
+	assert.Equal(t, qos.PermissionPrivate, offline.Permission())
+
+	output, err := offline.Execute(&qos.CommandContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, "No service changed\n", output)
+
+	online, err := extension.GetQosCommand("online")
+	assert.NoError(t, err)
+	assert.Equal(t, "online [pattern]", online.Usage())
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/qos"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+// defaultTopTalkers is the number of the top talkers listed by inboundLimitCommand by default
+const defaultTopTalkers = 10
+
+// inboundLimitCommand shows the requests throttled by the inbound limits of the servers and their top talkers, as
+// tables for telnet and as json for http
+type inboundLimitCommand struct{}
+
+func (c *inboundLimitCommand) Usage() string {
+	return "inboundlimit [top] [by]"
+}
+
+func (c *inboundLimitCommand) Description() string {
+	return "Show the requests throttled by the inbound limits and the top source ips sorted by the requests, " +
+		"the bytes or the throttled requests, 10 by the bytes by default"
+}
+
+func (c *inboundLimitCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionProtected
+}
+
+func (c *inboundLimitCommand) Execute(ctx *qos.CommandContext) (string, error) {
+	top := defaultTopTalkers
+	if arg := ctx.Arg(0); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid top %q, it should be a non-negative number", arg)
+		}
+		top = n
+	}
+	all, err := getty.InboundLimits(top, ctx.Arg(1))
+	if err != nil {
+		return "", err
+	}
+	if ctx.HTTP {
+		data, err := json.MarshalIndent(all, "", "  ")
+		return string(data), err
+	}
+	return renderInboundLimits(all), nil
+}
+
+// renderInboundLimits renders the statistics of the inbound limiters @all as tables ordered by the addresses
+func renderInboundLimits(all map[string]getty.InboundLimitStats) string {
+	addrs := make([]string, 0, len(all))
+	for addr := range all {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	var sb strings.Builder
+	for _, addr := range addrs {
+		stats := all[addr]
+		sb.WriteString("Server: " + addr + "\n")
+		limits := make([]string, 0, len(stats.Throttled))
+		for limit := range stats.Throttled {
+			limits = append(limits, limit)
+		}
+		sort.Strings(limits)
+		throttled := make([][]string, 0, len(limits))
+		for _, limit := range limits {
+			throttled = append(throttled, []string{limit, strconv.FormatInt(stats.Throttled[limit], 10)})
+		}
+		sb.WriteString(renderTable([]string{"Limit", "Throttled"}, throttled))
+		sb.WriteString("Closed: " + strconv.FormatInt(stats.Closed, 10) + "\n")
+
+		talkers := make([][]string, 0, len(stats.TopTalkers))
+		for _, t := range stats.TopTalkers {
+			talkers = append(talkers, []string{t.IP, strconv.Itoa(t.Connections), strconv.FormatInt(t.Requests, 10),
+				strconv.FormatInt(t.Bytes, 10), strconv.FormatInt(t.Throttled, 10)})
+		}
+		sb.WriteString("Top Talkers:\n")
+		sb.WriteString(renderTable([]string{"IP", "Connections", "Requests", "Bytes", "Throttled"}, talkers))
+	}
+	return sb.String()
+}
@@ -0,0 +1,126 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/inspect"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+)
+
+// invokersCommand shows the providers of a reference, how they are routed and selected, as tables for telnet and
+// as json for http
+type invokersCommand struct{}
+
+func (c *invokersCommand) Usage() string {
+	return "invokers <serviceKey> [method]"
+}
+
+func (c *invokersCommand) Description() string {
+	return "Show the providers of the reference with their weights, routing, ejection and circuit states, " +
+		"and the last selections"
+}
+
+func (c *invokersCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionProtected
+}
+
+func (c *invokersCommand) Execute(ctx *qos.CommandContext) (string, error) {
+	serviceKey := ctx.Arg(0)
+	if serviceKey == "" {
+		return "", errors.New("the service key is required")
+	}
+	var snapshot *inspect.Snapshot
+	for _, rc := range config.GetConsumerConfig().References {
+		if common.ServiceKey(rc.InterfaceName, rc.Group, rc.Version) == serviceKey {
+			s := inspect.Inspect(serviceKey, rc.GetInvoker(), ctx.Arg(1))
+			snapshot = &s
+			break
+		}
+	}
+	if snapshot == nil {
+		return "", fmt.Errorf("unknown reference %q", serviceKey)
+	}
+	if ctx.HTTP {
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		return string(data), err
+	}
+	return renderSnapshot(snapshot), nil
+}
+
+// renderSnapshot renders the @snapshot as tables
+func renderSnapshot(snapshot *inspect.Snapshot) string {
+	var sb strings.Builder
+	sb.WriteString("Reference: " + snapshot.ServiceKey + "\n")
+	sb.WriteString("URL: " + snapshot.URL + "\n")
+
+	providers := make([][]string, 0, len(snapshot.Providers))
+	for _, p := range snapshot.Providers {
+		circuits := make([]string, 0, len(p.Circuits))
+		for method, state := range p.Circuits {
+			circuits = append(circuits, method+"="+state)
+		}
+		sort.Strings(circuits)
+		providers = append(providers, []string{p.Address, strconv.FormatInt(p.Weight, 10), yesOrNo(p.Available),
+			yesOrNo(p.Routed), yesOrNo(p.Ejected), orDash(strings.Join(circuits, ",")),
+			strconv.FormatInt(p.Selections, 10)})
+	}
+	sb.WriteString("Providers:\n")
+	sb.WriteString(renderTable([]string{"Address", "Weight", "Available", "Routed", "Ejected", "Circuit",
+		"Selections"}, providers))
+
+	routes := make([][]string, 0, len(snapshot.Routes))
+	for _, r := range snapshot.Routes {
+		routes = append(routes, []string{r.Router, orDash(strings.Join(r.Filtered, ",")), strconv.Itoa(r.Remained)})
+	}
+	sb.WriteString("Routes:\n")
+	sb.WriteString(renderTable([]string{"Router", "Filtered", "Remained"}, routes))
+
+	decisions := make([][]string, 0, len(snapshot.Decisions))
+	for _, d := range snapshot.Decisions {
+		decisions = append(decisions, []string{d.Time.Format("15:04:05.000"), d.Method, d.Address, d.Reason,
+			yesOrNo(d.Retry), strconv.Itoa(d.Candidates)})
+	}
+	sb.WriteString("Decisions:\n")
+	sb.WriteString(renderTable([]string{"Time", "Method", "Address", "Reason", "Retry", "Candidates"}, decisions))
+	return sb.String()
+}
+
+func yesOrNo(b bool) string {
+	if b {
+		return "Y"
+	}
+	return "N"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+	registryProtocol "dubbo.apache.org/dubbo-go/v3/registry/protocol"
+)
+
+// lsCommand lists the services provided with their registration states, and the services referred with the numbers
+// of their providers
+type lsCommand struct{}
+
+func (c *lsCommand) Usage() string {
+	return "ls"
+}
+
+func (c *lsCommand) Description() string {
+	return "List the services provided and referred"
+}
+
+func (c *lsCommand) Permission() qos.PermissionLevel {
+	return qos.PermissionProtected
+}
+
+func (c *lsCommand) Execute(_ *qos.CommandContext) (string, error) {
+	// PUB is Y if the service is registered to all of its registries, N if it's not registered to any of them or
+	// some of them, and - if it has no registry
+	registered := make(map[string]bool)
+	for _, state := range registryProtocol.GetProviderStates() {
+		if r, ok := registered[state.ServiceKey]; !ok || r {
+			registered[state.ServiceKey] = state.Registered
+		}
+	}
+	var providers [][]string
+	for _, sc := range config.GetProviderConfig().Services {
+		serviceKey, pub := common.ServiceKey(sc.Interface, sc.Group, sc.Version), "-"
+		if r, ok := registered[serviceKey]; ok {
+			pub = "N"
+			if r {
+				pub = "Y"
+			}
+		}
+		providers = append(providers, []string{serviceKey, pub})
+	}
+
+	var consumers [][]string
+	for _, rc := range config.GetConsumerConfig().References {
+		num := len(directory.ProviderInvokers(rc.GetInvoker()))
+		consumers = append(consumers, []string{common.ServiceKey(rc.InterfaceName, rc.Group, rc.Version),
+			strconv.Itoa(num)})
+	}
+
+	var sb strings.Builder
+	sb.WriteString("As Provider side:\n")
+	sb.WriteString(renderTable([]string{"Provider Service Name", "PUB"}, sortRows(providers)))
+	sb.WriteString("As Consumer side:\n")
+	sb.WriteString(renderTable([]string{"Consumer Service Name", "NUM"}, sortRows(consumers)))
+	return sb.String(), nil
+}
+
+// sortRows sorts the @rows by their first cells
+func sortRows(rows [][]string) [][]string {
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+	return rows
+}
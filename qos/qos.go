@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package qos is the quality of service of the application, which is operated by the commands sent to the QoS server
+// by telnet or http, e.g.
+//
+//	telnet 127.0.0.1 22222
+//	dubbo>ls
+//
+//	curl http://127.0.0.1:22222/offline?service=org.apache.dubbo.Greeter
+//
+// The built-in commands are in the qos/command package, and more commands could be registered by
+// extension.SetQosCommand.
+package qos
+
+import (
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// PermissionLevel is the level of the permission required by a command, the operators from the foreign ips are only
+// allowed to run the commands of the anonymous access permission level or lower if the foreign ips are not accepted
+type PermissionLevel int
+
+const (
+	// PermissionNone allows no command, the connections from the foreign ips are rejected if it's the anonymous level
+	PermissionNone PermissionLevel = iota
+	// PermissionPublic is the level of the commands which are harmless, like help
+	PermissionPublic
+	// PermissionProtected is the level of the commands which read the state of the application, like ls
+	PermissionProtected
+	// PermissionPrivate is the level of the commands which change the state of the application, like offline
+	PermissionPrivate
+)
+
+var permissionLevelNames = [...]string{"none", "public", "protected", "private"}
+
+// nolint
+func (l PermissionLevel) String() string {
+	return permissionLevelNames[l]
+}
+
+// ParsePermissionLevel parses the case-insensitive name of a PermissionLevel
+func ParsePermissionLevel(name string) (PermissionLevel, error) {
+	for i, levelName := range permissionLevelNames {
+		if strings.EqualFold(name, levelName) {
+			return PermissionLevel(i), nil
+		}
+	}
+	return PermissionNone, perrors.Errorf("invalid permission level %q, it should be one of %s", name,
+		strings.Join(permissionLevelNames[:], ", "))
+}
+
+// Command is a command of the QoS server
+type Command interface {
+	// Usage is the syntax of the command, like "offline [pattern]"
+	Usage() string
+	// Description describes what the command does in a line
+	Description() string
+	// Permission is the level of the permission required to run the command
+	Permission() PermissionLevel
+	// Execute runs the command, and returns the output for the operator
+	Execute(ctx *CommandContext) (string, error)
+}
+
+// CommandContext is the context of a command sent to the QoS server
+type CommandContext struct {
+	// Args are the arguments following the command name
+	Args []string
+	// RemoteAddr is the address of the operator
+	RemoteAddr string
+	// HTTP is true if the command is sent by an http request, otherwise it's sent by telnet
+	HTTP bool
+	// Quit closes the telnet session after the output is written, it's ignored for http
+	Quit bool
+}
+
+// Arg returns the argument at @i, empty if it is absent
+func (c *CommandContext) Arg(i int) string {
+	if i < len(c.Args) {
+		return c.Args[i]
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qos
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePermissionLevel(t *testing.T) {
+	for name, level := range map[string]PermissionLevel{
+		"none":      PermissionNone,
+		"Public":    PermissionPublic,
+		"PROTECTED": PermissionProtected,
+		"private":   PermissionPrivate,
+	} {
+		parsed, err := ParsePermissionLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+	assert.Equal(t, "protected", PermissionProtected.String())
+
+	_, err := ParsePermissionLevel("admin")
+	assert.EqualError(t, err, `invalid permission level "admin", it should be one of none, public, protected, private`)
+}
+
+func TestCommandContextArg(t *testing.T) {
+	ctx := &CommandContext{Args: []string{"ls"}}
+	assert.Equal(t, "ls", ctx.Arg(0))
+	assert.Equal(t, "", ctx.Arg(1))
+}
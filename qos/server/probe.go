@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+	"dubbo.apache.org/dubbo-go/v3/config"
+)
+
+const (
+	livePath  = "/live"
+	readyPath = "/ready"
+)
+
+// shuttingDown is true once the graceful shutdown begins
+var shuttingDown = atomic.NewBool(false)
+
+// probe checks the liveness, or the readiness if @ready, by the status checkers gating them in the config
+func probe(ctx context.Context, ready bool) *status.Result {
+	conf := config.GetHealthConfig()
+	checkers := extension.GetStatusCheckers()
+	gating := conf.Liveness
+	if ready {
+		gating = conf.Readiness
+		if len(gating) == 0 {
+			for name := range checkers {
+				gating = append(gating, name)
+			}
+		}
+	}
+	result := status.Check(ctx, checkers, gating, conf.GetTimeout())
+	if ready && shuttingDown.Load() {
+		result.Up = false
+		result.Message = "shutting down"
+	}
+	return result
+}
+
+// serveProbe answers the liveness or the readiness probe by 200 if it's up, otherwise 503, with the result in json
+func serveProbe(conn net.Conn, ready bool) {
+	result := probe(context.Background(), ready)
+	code := http.StatusOK
+	if !result.Up {
+		code = http.StatusServiceUnavailable
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		writeHTTP(conn, http.StatusInternalServerError, textPlain, err.Error())
+		return
+	}
+	writeHTTP(conn, code, "application/json", string(body))
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+	"dubbo.apache.org/dubbo-go/v3/config"
+)
+
+func getProbe(t *testing.T, s *Server, path string) (int, *status.Result) {
+	resp, err := http.Get("http://" + s.Addr().String() + path)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	result := &status.Result{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(result))
+	return resp.StatusCode, result
+}
+
+func TestProbes(t *testing.T) {
+	registryUp := atomic.NewBool(true)
+	extension.SetStatusChecker("test-registry", status.CheckerFunc(func(context.Context) status.Status {
+		if registryUp.Load() {
+			return status.OK("")
+		}
+		return status.Error("unavailable registries: zookeeper://127.0.0.1:2181")
+	}))
+	s := newTestServer(t, config.NewQosConfigBuilder().SetPort("0").Build())
+	defer s.Stop()
+
+	code, result := getProbe(t, s, readyPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.Up)
+	assert.True(t, result.Checkers["test-registry"].Gating)
+
+	// the readiness fails once the registry is down, while the application is still live
+	registryUp.Store(false)
+	code, result = getProbe(t, s, readyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, result.Up)
+	assert.Equal(t, "unavailable registries: zookeeper://127.0.0.1:2181", result.Checkers["test-registry"].Message)
+	code, result = getProbe(t, s, livePath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, result.Checkers["test-registry"].Gating)
+
+	registryUp.Store(true)
+	code, _ = getProbe(t, s, readyPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	// the readiness fails at the beginning of the graceful shutdown
+	for _, l := range extension.GetLifecycleListeners() {
+		l.OnLifecycleEvent(extension.LifecycleBeforeShutdown)
+	}
+	defer shuttingDown.Store(false)
+	code, result = getProbe(t, s, readyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "shutting down", result.Message)
+	code, _ = getProbe(t, s, livePath)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestProbeGating(t *testing.T) {
+	extension.SetStatusChecker("test-down", status.CheckerFunc(func(context.Context) status.Status {
+		return status.Error("down")
+	}))
+	rc := config.NewRootConfigBuilder().SetHealth(config.NewHealthConfigBuilder().SetLiveness("test-down").
+		SetReadiness("provider").Build()).Build()
+	assert.NoError(t, rc.Health.Init())
+	config.SetRootConfig(*rc)
+	defer config.SetRootConfig(*config.NewRootConfigBuilder().Build())
+
+	result := probe(context.Background(), true)
+	assert.True(t, result.Up)
+	assert.False(t, result.Checkers["test-down"].Gating)
+	result = probe(context.Background(), false)
+	assert.False(t, result.Up)
+}
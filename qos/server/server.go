@@ -0,0 +1,385 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package server implements the QoS server, which serves the QoS commands by telnet and http on the same port. A
+// connection is served by http if it starts with an http request line, otherwise it's a telnet session. The liveness
+// and the readiness probes are served at /live and /ready by http, see config.HealthConfig.
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+	_ "dubbo.apache.org/dubbo-go/v3/qos/command"
+)
+
+const (
+	prompt  = "dubbo>"
+	welcome = "Welcome to the dubbo-go QoS server, type help to list the commands.\r\n"
+
+	foreignIPNotPermitted = "Foreign Ip Not Permitted, Consider Config It In Whitelist."
+
+	textPlain = "text/plain; charset=utf-8"
+
+	// detectTimeout is how long the server waits for the http request line before it treats the connection as telnet
+	detectTimeout = 500 * time.Millisecond
+)
+
+var httpMethods = [][]byte{
+	[]byte("GET "), []byte("POST "), []byte("PUT "), []byte("DELETE "), []byte("HEAD "), []byte("OPTIONS "),
+	[]byte("PATCH "),
+}
+
+var (
+	serverLock sync.Mutex
+	server     *Server
+)
+
+func init() {
+	extension.AddLifecycleListener(extension.LifecycleFunc(func(event extension.LifecycleEvent) {
+		switch event {
+		case extension.LifecycleConfigLoaded:
+			shuttingDown.Store(false)
+			start(config.GetQosConfig())
+		case extension.LifecycleBeforeShutdown:
+			// the readiness fails once the graceful shutdown begins, so no more traffic is routed to the application
+			shuttingDown.Store(true)
+		case extension.LifecycleAfterShutdown:
+			stop()
+		}
+	}))
+}
+
+// start starts the QoS server of @conf unless it's disabled or started
+func start(conf *config.QosConfig) {
+	serverLock.Lock()
+	defer serverLock.Unlock()
+	if server != nil || !conf.IsEnabled() {
+		return
+	}
+	s, err := NewServer(conf)
+	if err == nil {
+		err = s.Start()
+	}
+	if err != nil {
+		logger.Errorf("Failed to start the QoS server on %s:%s, %v", conf.Host, conf.Port, err)
+		return
+	}
+	server = s
+}
+
+// stop stops the QoS server started
+func stop() {
+	serverLock.Lock()
+	defer serverLock.Unlock()
+	if server != nil {
+		server.Stop()
+		server = nil
+	}
+}
+
+// Server is the QoS server
+type Server struct {
+	address         string
+	acceptForeignIP bool
+	whitelist       []*net.IPNet
+	anonymous       qos.PermissionLevel
+
+	lock     sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer creates the QoS server of @conf, which is started by Start
+func NewServer(conf *config.QosConfig) (*Server, error) {
+	anonymous, err := qos.ParsePermissionLevel(conf.AnonymousAccessPermissionLevel)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := conf.Whitelist()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		address:         net.JoinHostPort(conf.Host, conf.Port),
+		acceptForeignIP: conf.AcceptForeignIP != nil && *conf.AcceptForeignIP,
+		whitelist:       whitelist,
+		anonymous:       anonymous,
+		conns:           make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start listens on the address of the server, and serves the connections in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.listener = listener
+	s.lock.Unlock()
+	logger.Infof("The QoS server is listening on %s", listener.Addr())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if !s.track(conn) {
+				_ = conn.Close()
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.untrack(conn)
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server is listening on, which is nil before Start
+func (s *Server) Addr() net.Addr {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop closes the listener and the connections, and waits for the commands running
+func (s *Server) Stop() {
+	s.lock.Lock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+	s.lock.Unlock()
+	s.wg.Wait()
+}
+
+// track records the @conn to be closed by Stop, it's false if the server is stopped
+func (s *Server) track(conn net.Conn) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.conns == nil {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	_ = conn.Close()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.conns, conn)
+}
+
+// serve detects the protocol of the @conn by its first bytes, and serves the commands sent by it
+func (s *Server) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(detectTimeout))
+	head, _ := reader.Peek(8)
+	_ = conn.SetReadDeadline(time.Time{})
+	for _, method := range httpMethods {
+		if bytes.HasPrefix(head, method) {
+			s.serveHTTP(conn, reader)
+			return
+		}
+	}
+	s.serveTelnet(conn, reader)
+}
+
+func (s *Server) serveTelnet(conn net.Conn, reader *bufio.Reader) {
+	foreign := s.isForeign(conn.RemoteAddr())
+	if foreign && s.anonymous == qos.PermissionNone {
+		_, _ = conn.Write([]byte(foreignIPNotPermitted + "\r\n"))
+		return
+	}
+	if _, err := conn.Write([]byte(welcome + prompt)); err != nil {
+		return
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			if _, err = conn.Write([]byte(prompt)); err != nil {
+				return
+			}
+			continue
+		}
+		ctx := &qos.CommandContext{Args: args[1:], RemoteAddr: conn.RemoteAddr().String()}
+		output, _ := s.execute(args[0], ctx, foreign)
+		output = strings.ReplaceAll(strings.TrimRight(output, "\n"), "\n", "\r\n") + "\r\n"
+		if !ctx.Quit {
+			output += prompt
+		}
+		if _, err = conn.Write([]byte(output)); err != nil || ctx.Quit {
+			return
+		}
+	}
+}
+
+// serveHTTP serves an http request, the first segment of the path is the command, and the other segments and the
+// values of the query are the arguments in order, e.g. /offline/org.apache.dubbo.Greeter or
+// /offline?service=org.apache.dubbo.Greeter
+func (s *Server) serveHTTP(conn net.Conn, reader *bufio.Reader) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		writeHTTP(conn, http.StatusBadRequest, textPlain, err.Error())
+		return
+	}
+	foreign := s.isForeign(conn.RemoteAddr())
+	if foreign && s.anonymous == qos.PermissionNone {
+		writeHTTP(conn, http.StatusForbidden, textPlain, foreignIPNotPermitted)
+		return
+	}
+	// the probes are served to all of the operators allowed to connect, like the kubelets
+	if req.URL.Path == livePath || req.URL.Path == readyPath {
+		serveProbe(conn, req.URL.Path == readyPath)
+		return
+	}
+	args, err := httpArgs(req.URL)
+	if err != nil || len(args) == 0 {
+		writeHTTP(conn, http.StatusNotFound, textPlain, "Unsupported command")
+		return
+	}
+	ctx := &qos.CommandContext{Args: args[1:], RemoteAddr: conn.RemoteAddr().String(), HTTP: true}
+	output, status := s.execute(args[0], ctx, foreign)
+	writeHTTP(conn, status, textPlain, output)
+}
+
+// httpArgs returns the command and its arguments of the request @u
+func httpArgs(u *url.URL) ([]string, error) {
+	var args []string
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment != "" {
+			args = append(args, segment)
+		}
+	}
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		value := pair
+		if i := strings.Index(pair, "="); i >= 0 {
+			value = pair[i+1:]
+		}
+		value, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+func writeHTTP(conn net.Conn, status int, contentType, body string) {
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	resp := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Close:         true,
+	}
+	_ = resp.Write(conn)
+}
+
+// execute runs the command @name, and returns its output and the http status of the result
+func (s *Server) execute(name string, ctx *qos.CommandContext, foreign bool) (string, int) {
+	cmd, err := extension.GetQosCommand(name)
+	if err != nil {
+		return fmt.Sprintf("Unsupported command: %s", name), http.StatusNotFound
+	}
+	if foreign && cmd.Permission() > s.anonymous {
+		return fmt.Sprintf("Permission denied, the command %s requires the %s permission", name, cmd.Permission()),
+			http.StatusForbidden
+	}
+	output, err := runCommand(cmd, ctx)
+	if err != nil {
+		return "ERROR: " + err.Error(), http.StatusBadRequest
+	}
+	return output, http.StatusOK
+}
+
+// runCommand runs the @cmd, a panic of it is returned as an error so that it doesn't crash the application
+func runCommand(cmd qos.Command, ctx *qos.CommandContext) (output string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("The QoS command %s panics: %v", cmd.Usage(), e)
+			err = fmt.Errorf("the command panics: %v", e)
+		}
+	}()
+	return cmd.Execute(ctx)
+}
+
+// isForeign returns whether the operator from @addr is only allowed to run the anonymous commands, which is true if
+// the foreign ips are not accepted and the ip of @addr is neither loopback nor in the whitelist
+func (s *Server) isForeign(addr net.Addr) bool {
+	if s.acceptForeignIP {
+		return false
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return true
+	}
+	if tcpAddr.IP.IsLoopback() {
+		return false
+	}
+	for _, cidr := range s.whitelist {
+		if cidr.Contains(tcpAddr.IP) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/qos"
+)
+
+func newTestServer(t *testing.T, conf *config.QosConfig) *Server {
+	assert.NoError(t, conf.Init())
+	s, err := NewServer(conf)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Start())
+	return s
+}
+
+func TestHTTPArgs(t *testing.T) {
+	u, _ := url.Parse("/offline/a.b.C?service=org.apache.dubbo.Greeter&pattern=.%2A")
+	args, err := httpArgs(u)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"offline", "a.b.C", "org.apache.dubbo.Greeter", ".*"}, args)
+
+	u, _ = url.Parse("/")
+	args, err = httpArgs(u)
+	assert.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestIsForeign(t *testing.T) {
+	s, err := NewServer(config.NewQosConfigBuilder().SetAnonymousAccessPermissionLevel("public").
+		SetAcceptForeignIPWhitelist("192.168.1.1, 10.0.0.0/8").Build())
+	assert.NoError(t, err)
+	for ip, foreign := range map[string]bool{
+		"127.0.0.1":   false,
+		"::1":         false,
+		"192.168.1.1": false,
+		"10.1.2.3":    false,
+		"192.168.1.2": true,
+		"172.16.0.1":  true,
+	} {
+		assert.Equal(t, foreign, s.isForeign(&net.TCPAddr{IP: net.ParseIP(ip)}), ip)
+	}
+
+	s.acceptForeignIP = true
+	assert.False(t, s.isForeign(&net.TCPAddr{IP: net.ParseIP("172.16.0.1")}))
+}
+
+func TestExecutePermission(t *testing.T) {
+	s, err := NewServer(config.NewQosConfigBuilder().SetAnonymousAccessPermissionLevel("public").Build())
+	assert.NoError(t, err)
+
+	output, status := s.execute("help", &qos.CommandContext{Args: []string{"quit"}}, true)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "quit\n\tClose the telnet session\n", output)
+
+	output, status = s.execute("ls", &qos.CommandContext{}, true)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, "Permission denied, the command ls requires the protected permission", output)
+	_, status = s.execute("ls", &qos.CommandContext{}, false)
+	assert.Equal(t, http.StatusOK, status)
+
+	output, status = s.execute("unknown", &qos.CommandContext{}, false)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "Unsupported command: unknown", output)
+
+	output, status = s.execute("help", &qos.CommandContext{Args: []string{"unknown"}}, false)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `ERROR: unknown command "unknown"`, output)
+}
+
+func TestTelnet(t *testing.T) {
+	s := newTestServer(t, config.NewQosConfigBuilder().SetPort("0").Build())
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, welcome, line)
+
+	_, err = conn.Write([]byte("\r\nhelp   quit\r\n"))
+	assert.NoError(t, err)
+	line, _ = reader.ReadString('\n')
+	assert.Equal(t, prompt+prompt+"quit\r\n", line)
+	line, _ = reader.ReadString('\n')
+	assert.Equal(t, "\tClose the telnet session\r\n", line)
+
+	_, err = conn.Write([]byte("quit\r\n"))
+	assert.NoError(t, err)
+	rest, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, prompt+"BYE!\r\n", string(rest))
+}
+
+func TestHTTP(t *testing.T) {
+	s := newTestServer(t, config.NewQosConfigBuilder().SetPort("0").Build())
+	defer s.Stop()
+
+	resp, err := http.Get("http://" + s.Addr().String() + "/help?command=ls")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, resp.Close)
+	assert.Equal(t, "ls\n\tList the services provided and referred\n", string(body))
+
+	resp, err = http.Post("http://"+s.Addr().String()+"/unknown", "text/plain", strings.NewReader(""))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestForeignIPNotPermitted(t *testing.T) {
+	s, err := NewServer(config.NewQosConfigBuilder().SetAnonymousAccessPermissionLevel("none").Build())
+	assert.NoError(t, err)
+	conn, client := net.Pipe()
+	go func() {
+		s.serve(&foreignConn{Conn: conn})
+		_ = conn.Close()
+	}()
+	output, _ := ioutil.ReadAll(client)
+	assert.Equal(t, foreignIPNotPermitted+"\r\n", string(output))
+}
+
+func TestStartDisabled(t *testing.T) {
+	start(config.NewQosConfigBuilder().SetPort("0").Build())
+	assert.Nil(t, server)
+	start(config.NewQosConfigBuilder().SetEnable(false).Build())
+	assert.Nil(t, server)
+}
+
+// foreignConn is a connection from a foreign ip
+type foreignConn struct {
+	net.Conn
+}
+
+func (c *foreignConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("192.168.1.2"), Port: 12345}
+}
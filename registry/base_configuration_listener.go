@@ -54,7 +54,7 @@ func (bcl *BaseConfigurationListener) InitWith(key string, listener config_cente
 		return
 	}
 	bcl.defaultConfiguratorFunc = f
-	bcl.dynamicConfiguration.AddListener(key, listener)
+	bcl.dynamicConfiguration.AddListener(key, listener, config_center.WithGroup(constant.Dubbo))
 	if rawConfig, err := bcl.dynamicConfiguration.GetInternalProperty(key,
 		config_center.WithGroup(constant.Dubbo)); err != nil {
 		//set configurators to empty
@@ -96,6 +96,15 @@ func (bcl *BaseConfigurationListener) OverrideUrl(url *common.URL) {
 	}
 }
 
+// IsDisabled checks whether the provider is disabled, by the disabled=true of the override rules of dubbo-admin, or
+// by the enabled=false of the provider url
+func IsDisabled(url *common.URL) bool {
+	if disabled, ok := url.GetNonDefaultParam(constant.DisabledKey); ok {
+		return disabled == "true"
+	}
+	return !url.GetParamBool(constant.EnabledKey, true)
+}
+
 // ToConfigurators converts @urls by @f to config_center.Configurators
 func ToConfigurators(urls []*common.URL, f func(url *common.URL) config_center.Configurator) []config_center.Configurator {
 	if len(urls) == 0 {
@@ -108,9 +117,9 @@ func ToConfigurators(urls []*common.URL, f func(url *common.URL) config_center.C
 			break
 		}
 
+		// the params of the url are shared, the url overriding nothing but the anyhost is skipped without deleting it
 		override := url.GetParams()
-		delete(override, constant.AnyhostKey)
-		if len(override) == 0 {
+		if _, anyhost := override[constant.AnyhostKey]; len(override) == 0 || anyhost && len(override) == 1 {
 			continue
 		}
 		configurators = append(configurators, f(url))
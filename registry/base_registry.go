@@ -19,6 +19,7 @@ package registry
 
 import (
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"strconv"
@@ -36,6 +37,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
 	metricsRegistry "dubbo.apache.org/dubbo-go/v3/metrics/registry"
 )
@@ -47,12 +49,10 @@ const (
 
 var (
 	processID = ""
-	localIP   = ""
 )
 
 func init() {
 	processID = fmt.Sprintf("%d", os.Getpid())
-	localIP = common.GetLocalIp()
 }
 
 type createPathFunc func(dubboPath string) error
@@ -133,16 +133,8 @@ func (r *BaseRegistry) Destroy() {
 
 // Register implement interface registry to register
 func (r *BaseRegistry) Register(url *common.URL) error {
-	// if developer define registry port and ip, use it first.
 	start := time.Now()
-	if ipToRegistry := os.Getenv(constant.DubboIpToRegistryKey); len(ipToRegistry) > 0 {
-		url.Ip = ipToRegistry
-	} else {
-		url.Ip = common.GetLocalIp()
-	}
-	if portToRegistry := os.Getenv(constant.DubboPortToRegistryKey); len(portToRegistry) > 0 {
-		url.Port = portToRegistry
-	}
+	common.HandleRegisterIPAndPort(url)
 	// todo bug when provider、consumer simultaneous initialization
 	if _, ok := r.registered.Load(url.Key()); ok {
 		return perrors.Errorf("Service {%s} has been registered", url.Key())
@@ -178,11 +170,12 @@ func (r *BaseRegistry) UnRegister(url *common.URL) error {
 
 // service is for getting service path stored in url
 func (r *BaseRegistry) service(c *common.URL) string {
-	return url.QueryEscape(c.Service())
+	return common.URLEncode(c.Service())
 }
 
 // RestartCallBack for reregister when reconnect
 func (r *BaseRegistry) RestartCallBack() bool {
+	eventbus.Publish(eventbus.RegistryLost{URL: r.GetURL()})
 	flag := true
 	r.registered.Range(func(key, value interface{}) bool {
 		registeredUrl := value.(*common.URL)
@@ -200,6 +193,7 @@ func (r *BaseRegistry) RestartCallBack() bool {
 
 	if flag {
 		r.facadeBasedRegistry.InitListeners()
+		eventbus.Publish(eventbus.RegistryConnected{URL: r.GetURL()})
 	}
 
 	return flag
@@ -246,7 +240,7 @@ func (r *BaseRegistry) processURL(c *common.URL, f func(string, string) error, c
 		return perrors.WithMessagef(err, "@c{%v} registry fail", c)
 	}
 
-	encodedURL = url.QueryEscape(rawURL)
+	encodedURL = common.URLEncode(rawURL)
 	dubboPath = strings.ReplaceAll(dubboPath, "$", "%24")
 	err = f(dubboPath, encodedURL)
 
@@ -290,13 +284,11 @@ func (r *BaseRegistry) providerRegistry(c *common.URL, params url.Values, f crea
 		params.Add(constant.MethodsKey, strings.Join(c.Methods, ","))
 	}
 	logger.Debugf("provider url params:%#v", params)
-	var host string
-	if len(c.Ip) == 0 {
-		host = localIP
-	} else {
-		host = c.Ip
+	host := c.Ip
+	if len(host) == 0 {
+		host = common.GetLocalIp()
 	}
-	host += ":" + c.Port
+	host = net.JoinHostPort(host, c.Port)
 
 	// delete empty param key
 	for key, val := range params {
@@ -305,8 +297,7 @@ func (r *BaseRegistry) providerRegistry(c *common.URL, params url.Values, f crea
 		}
 	}
 
-	s, _ := url.QueryUnescape(params.Encode())
-	rawURL = fmt.Sprintf("%s://%s%s?%s", c.Protocol, host, c.Path, s)
+	rawURL = fmt.Sprintf("%s://%s%s?%s", c.Protocol, host, c.Path, common.ToQueryString(params))
 	// Print your own registration service providers.
 	logger.Debugf("provider path:%s, url:%s", dubboPath, rawURL)
 	return dubboPath, rawURL, nil
@@ -331,8 +322,11 @@ func (r *BaseRegistry) consumerRegistry(c *common.URL, params url.Values, f crea
 	}
 
 	params.Add("protocol", c.Protocol)
-	s, _ := url.QueryUnescape(params.Encode())
-	rawURL = fmt.Sprintf("consumer://%s%s?%s", localIP, c.Path, s)
+	host := common.GetLocalIp()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	rawURL = fmt.Sprintf("consumer://%s%s?%s", host, c.Path, common.ToQueryString(params))
 	logger.Debugf("consumer path:%s, url:%s", dubboPath, rawURL)
 	return dubboPath, rawURL, nil
 }
@@ -29,16 +29,20 @@ import (
 	"github.com/dubbogo/gost/log/logger"
 
 	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
 	"dubbo.apache.org/dubbo-go/v3/cluster/router/chain"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/configurator"
@@ -69,6 +73,17 @@ type RegistryDirectory struct {
 	consumerConfigurationListener  *consumerConfigurationListener
 	referenceConfigurationListener *referenceConfigurationListener
 	registerLock                   sync.Mutex // this lock if for register
+	routerURLs                     sync.Map   // the router rule urls from the routers category, keyed by url string
+	subset                         *subsetSelector
+	subsetLock                     sync.Mutex
+	// providerURLs holds the urls of all providers keyed by the invoker cache key when subsetting is enabled,
+	// while only the selected providers are referred in cacheInvokersMap
+	providerURLs sync.Map
+	// originURLs holds the urls of all providers before the override rules are applied, keyed by the invoker cache
+	// key, the rules are applied to them again once the rules change, so a deleted rule restores the original values
+	originURLs sync.Map
+	// subscribed is true once the directory subscribes the registry until it's destroyed
+	subscribed atomic.Bool
 }
 
 // NewRegistryDirectory will create a new RegistryDirectory
@@ -86,6 +101,7 @@ func NewRegistryDirectory(url *common.URL, registry registry.Registry) (director
 	}
 
 	dir.consumerURL = dir.getConsumerUrl(url.SubURL)
+	dir.subset = newSubsetSelector(url.SubURL)
 
 	if routerChain, err := chain.NewRouterChain(); err == nil {
 		dir.Directory.SetRouterChain(routerChain)
@@ -107,6 +123,9 @@ func NewRegistryDirectory(url *common.URL, registry registry.Registry) (director
 // subscribe from registry
 func (dir *RegistryDirectory) Subscribe(url *common.URL) {
 	logger.Debugf("subscribe service :%s for RegistryDirectory.", url.Key())
+	if dir.subscribed.CAS(false, true) {
+		registry.AddSubscriptions(1)
+	}
 	if err := dir.registry.Subscribe(url, dir); err != nil {
 		logger.Error("registry.Subscribe(url:%v, dir:%v) = error:%v", url, dir, err)
 	}
@@ -125,7 +144,13 @@ func (dir *RegistryDirectory) Notify(event *registry.ServiceEvent) {
 // NotifyAll notify the events that are complete Service Event List.
 // After notify the address, the callback func will be invoked.
 func (dir *RegistryDirectory) NotifyAll(events []*registry.ServiceEvent, callback func()) {
-	go dir.refreshAllInvokers(events, callback)
+	// the notifications of all the directories are dispatched by the shared pool, which slows down the registry
+	// rather than starting the goroutines unboundedly under the churn
+	if err := gopool.GetPool(gopool.RegistryNotify).Submit(func() {
+		dir.refreshAllInvokers(events, callback)
+	}); err != nil {
+		logger.Errorf("[Registry Directory] dispatch the notification of %s error: %v", dir.GetURL().Key(), err)
+	}
 }
 
 // refreshInvokers refreshes service's events.
@@ -154,10 +179,23 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 	var (
 		oldInvokers []protocol.Invoker
 		addEvents   []*registry.ServiceEvent
+		originURLs  = make(map[string]*common.URL, len(events))
 	)
 	dir.overrideUrl(dir.GetDirectoryUrl())
 	referenceUrl := dir.GetDirectoryUrl().SubURL
 
+	// the router rule urls are not providers, take them out of the complete list
+	events = dir.refreshAllRouters(events)
+	// nor the providers of other services, e.g. the other versions pushed along by the registry, or the providers
+	// not accepting the transport of the consumer
+	matched := make([]*registry.ServiceEvent, 0, len(events))
+	for _, event := range events {
+		if dir.isServiceMatched(event.Service) && acceptsTransport(event.Service, referenceUrl) {
+			matched = append(matched, event)
+		}
+	}
+	events = matched
+
 	// loop the events to check the Action should be EventTypeUpdate.
 	for _, event := range events {
 		if event.Action != remoting.EventTypeUpdate && event.Action != remoting.EventTypeAdd {
@@ -168,8 +206,10 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 		// MergeURL is executed once and put the result into Event. After this, the key will get from Event.Key().
 		newUrl := dir.convertUrl(event)
 		newUrl = common.MergeURL(newUrl, referenceUrl)
-		dir.overrideUrl(newUrl)
+		originUrl := newUrl.Clone()
+		dir.overrideProviderUrl(newUrl)
 		event.Update(newUrl)
+		originURLs[event.Key()] = originUrl
 	}
 	// After notify all addresses, do some callback.
 	defer callback()
@@ -177,6 +217,22 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 		// this lock is work at batch update of InvokeCache
 		dir.registerLock.Lock()
 		defer dir.registerLock.Unlock()
+		// forget the providers which are gone, whether they are in the subset or not
+		dir.providerURLs.Range(func(k, _ interface{}) bool {
+			if !dir.eventMatched(k.(string), events) {
+				dir.providerURLs.Delete(k)
+			}
+			return true
+		})
+		dir.originURLs.Range(func(k, _ interface{}) bool {
+			if _, ok := originURLs[k.(string)]; !ok {
+				dir.originURLs.Delete(k)
+			}
+			return true
+		})
+		for key, originUrl := range originURLs {
+			dir.originURLs.Store(key, originUrl)
+		}
 		// get need clear invokers from original invoker list
 		dir.cacheInvokersMap.Range(func(k, v interface{}) bool {
 			if !dir.eventMatched(k.(string), events) {
@@ -189,6 +245,13 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 		})
 		// get need add invokers from events
 		for _, event := range events {
+			if registry.IsDisabled(event.Service) {
+				logger.Infof("[Registry Directory] provider %s is disabled", event.Service.Location)
+				if invoker := dir.evictInvoker(event.Key()); invoker != nil {
+					oldInvokers = append(oldInvokers, invoker)
+				}
+				continue
+			}
 			// Get the key from Event.Key()
 			if _, ok := dir.cacheInvokersMap.Load(event.Key()); !ok {
 				addEvents = append(addEvents, event)
@@ -200,10 +263,7 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 			if event != nil && event.Service != nil {
 				logger.Infof("[Registry Directory] selector add service url{%s}", event.Service.String())
 			}
-			if event != nil && event.Service != nil && constant.RouterProtocol == event.Service.Protocol {
-				dir.configRouters()
-			}
-			if oldInvoker, _ := dir.doCacheInvoker(event.Service, event); oldInvoker != nil {
+			if oldInvoker, _ := dir.doCacheInvoker(event.Service, event.Key()); oldInvoker != nil {
 				oldInvokers = append(oldInvokers, oldInvoker)
 			}
 		}
@@ -239,6 +299,7 @@ func (dir *RegistryDirectory) invokerCacheKey(event *registry.ServiceEvent) stri
 
 // setNewInvokers groups the invokers from the cache first, then set the result to both directory and router chain.
 func (dir *RegistryDirectory) setNewInvokers() {
+	dir.applySubset()
 	newInvokers := dir.toGroupInvokers()
 	dir.invokersLock.Lock()
 	defer dir.invokersLock.Unlock()
@@ -251,13 +312,19 @@ func (dir *RegistryDirectory) cacheInvokerByEvent(event *registry.ServiceEvent)
 	// judge is override or others
 	if event != nil {
 
+		if event.Service != nil && isRouterURL(event.Service) {
+			dir.refreshRouter(event)
+			return nil, nil
+		}
+
 		switch event.Action {
 		case remoting.EventTypeAdd, remoting.EventTypeUpdate:
+			if !dir.isServiceMatched(event.Service) {
+				logger.Debugf("[Registry Directory] ignore the service url{%s} not subscribed", event.Service)
+				return nil, nil
+			}
 			u := dir.convertUrl(event)
 			logger.Infof("[Registry Directory] selector add service url{%s}", event.Service)
-			if u != nil && constant.RouterProtocol == u.Protocol {
-				dir.configRouters()
-			}
 			return []protocol.Invoker{dir.cacheInvoker(u, event)}, nil
 		case remoting.EventTypeDel:
 			logger.Infof("[Registry Directory] selector delete service url{%s}", event.Service)
@@ -269,8 +336,101 @@ func (dir *RegistryDirectory) cacheInvokerByEvent(event *registry.ServiceEvent)
 	return nil, nil
 }
 
-// configRouters configures dynamic routers into the router chain, but, the current impl is incorrect, see FIXME above.
+// isServiceMatched checks whether the provider url serves the service referred by the directory, see
+// common.IsServiceKeyMatch. The configurator urls are always matched, they are filtered by the configurators.
+func (dir *RegistryDirectory) isServiceMatched(url *common.URL) bool {
+	referenceUrl := dir.GetDirectoryUrl().SubURL
+	if url == nil || referenceUrl == nil || referenceUrl.Service() == "" {
+		return true
+	}
+	if url.Protocol == constant.OverrideProtocol ||
+		url.GetParam(constant.CategoryKey, constant.DefaultCategory) == constant.ConfiguratorsCategory {
+		return true
+	}
+	return common.IsServiceKeyMatch(referenceUrl, url)
+}
+
+// isRouterURL checks whether the url is a router rule url of the routers category rather than a provider url
+func isRouterURL(url *common.URL) bool {
+	return url.Protocol == constant.RouterProtocol || url.Protocol == constant.RouteProtocol ||
+		url.Protocol == constant.ConditionRouteProtocol ||
+		url.GetParam(constant.CategoryKey, constant.DefaultCategory) == constant.RoutersCategory
+}
+
+// refreshRouter adds, updates or deletes the router rule url of the event, then configures the routers
+func (dir *RegistryDirectory) refreshRouter(event *registry.ServiceEvent) {
+	key := event.Service.String()
+	switch event.Action {
+	case remoting.EventTypeAdd, remoting.EventTypeUpdate:
+		logger.Infof("[Registry Directory] add router url{%s}", event.Service)
+		dir.routerURLs.Store(key, event.Service)
+	case remoting.EventTypeDel:
+		logger.Infof("[Registry Directory] delete router url{%s}", event.Service)
+		dir.routerURLs.Delete(key)
+	default:
+		return
+	}
+	dir.configRouters()
+}
+
+// refreshAllRouters replaces the router rule urls with the ones in the complete event list,
+// and returns the remaining provider events.
+func (dir *RegistryDirectory) refreshAllRouters(events []*registry.ServiceEvent) []*registry.ServiceEvent {
+	providerEvents := make([]*registry.ServiceEvent, 0, len(events))
+	routerURLs := make(map[string]*common.URL)
+	for _, event := range events {
+		if event.Service != nil && isRouterURL(event.Service) {
+			routerURLs[event.Service.String()] = event.Service
+			continue
+		}
+		providerEvents = append(providerEvents, event)
+	}
+
+	changed := false
+	dir.routerURLs.Range(func(key, _ interface{}) bool {
+		if _, ok := routerURLs[key.(string)]; !ok {
+			dir.routerURLs.Delete(key)
+			changed = true
+		}
+		return true
+	})
+	for key, url := range routerURLs {
+		if _, loaded := dir.routerURLs.LoadOrStore(key, url); !loaded {
+			changed = true
+		}
+	}
+	if changed {
+		dir.configRouters()
+	}
+	return providerEvents
+}
+
+// configRouters creates routers from the router rule urls which apply to this directory, and
+// puts them into the router chain together with the builtin routers.
 func (dir *RegistryDirectory) configRouters() {
+	routers := make([]router.PriorityRouter, 0)
+	dir.routerURLs.Range(func(_, value interface{}) bool {
+		url := value.(*common.URL)
+		if !dir.IsProperRouter(url) {
+			return true
+		}
+		name := url.GetParam(constant.RouterKey, url.Protocol)
+		newRouter, ok := extension.GetURLRouter(name)
+		if !ok {
+			logger.Warnf("[Registry Directory] router %s is not existing, make sure you have import the package, url{%s}", name, url)
+			return true
+		}
+		r, err := newRouter(url)
+		if err != nil {
+			logger.Errorf("[Registry Directory] create router from url{%s} failed, err: %v", url, err)
+			return true
+		}
+		routers = append(routers, r)
+		return true
+	})
+	if routerChain := dir.RouterChain(); routerChain != nil {
+		routerChain.AddRouters(routers)
+	}
 }
 
 // convertUrl processes override:// and router://
@@ -280,8 +440,7 @@ func (dir *RegistryDirectory) convertUrl(res *registry.ServiceEvent) *common.URL
 		ret.GetParam(constant.CategoryKey, constant.DefaultCategory) == constant.ConfiguratorsCategory {
 		dir.configurators = append(dir.configurators, extension.GetDefaultConfigurator(ret))
 		ret = nil
-	} else if ret.Protocol == constant.RouterProtocol || // 2.for router
-		ret.GetParam(constant.CategoryKey, constant.DefaultCategory) == constant.RouterCategory {
+	} else if isRouterURL(ret) { // 2.for router
 		ret = nil
 	}
 	return ret
@@ -351,6 +510,13 @@ func (dir *RegistryDirectory) uncacheInvoker(event *registry.ServiceEvent) []pro
 func (dir *RegistryDirectory) uncacheInvokerWithKey(key string) protocol.Invoker {
 	logger.Debugf("service will be deleted in cache invokers: invokers key is  %s!", key)
 	protocol.RemoveUrlKeyUnhealthyStatus(key)
+	dir.originURLs.Delete(key)
+	return dir.evictInvoker(key)
+}
+
+// evictInvoker removes the invoker of the provider from the cache, and returns it if it was referred
+func (dir *RegistryDirectory) evictInvoker(key string) protocol.Invoker {
+	dir.providerURLs.Delete(key)
 	if cacheInvoker, ok := dir.cacheInvokersMap.Load(key); ok {
 		dir.cacheInvokersMap.Delete(key)
 		return cacheInvoker.(protocol.Invoker)
@@ -375,17 +541,42 @@ func (dir *RegistryDirectory) cacheInvoker(url *common.URL, event *registry.Serv
 	// check the url's protocol is equal to the protocol which is configured in reference config or referenceUrl is not care about protocol
 	if url.Protocol == referenceUrl.Protocol || referenceUrl.Protocol == "" {
 		newUrl := common.MergeURL(url, referenceUrl)
-		dir.overrideUrl(newUrl)
+		originUrl := newUrl.Clone()
+		dir.overrideProviderUrl(newUrl)
 		event.Update(newUrl)
-		if v, ok := dir.doCacheInvoker(newUrl, event); ok {
+		dir.originURLs.Store(event.Key(), originUrl)
+		if registry.IsDisabled(newUrl) {
+			logger.Infof("[Registry Directory] provider %s is disabled", newUrl.Location)
+			return dir.evictInvoker(event.Key())
+		}
+		if !acceptsTransport(url, referenceUrl) {
+			logger.Infof("[Registry Directory] provider %s does not accept the transport %s", url.Location,
+				referenceUrl.GetParam(constant.TransportKey, ""))
+			return dir.evictInvoker(event.Key())
+		}
+		if v, ok := dir.doCacheInvoker(newUrl, event.Key()); ok {
 			return v
 		}
 	}
 	return nil
 }
 
-func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, event *registry.ServiceEvent) (protocol.Invoker, bool) {
-	key := event.Key()
+// acceptsTransport reports whether the provider @url accepts the transport the consumer of @referenceUrl dials it by.
+// The consumers dialing by websocket select only the dubbo providers registered with transport=websocket, while the
+// providers of the other protocols are dialed as usual, as only the dubbo protocol supports the websocket.
+func acceptsTransport(url, referenceUrl *common.URL) bool {
+	transport := referenceUrl.GetParam(constant.TransportKey, "")
+	return transport == "" || url.Protocol != constant.Dubbo || url.GetParam(constant.TransportKey, "") == transport
+}
+
+func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, key string) (protocol.Invoker, bool) {
+	if dir.subset != nil {
+		dir.providerURLs.Store(key, newUrl)
+		if _, ok := dir.cacheInvokersMap.Load(key); !ok {
+			// the provider is referred by applySubset once it is selected into the subset
+			return nil, false
+		}
+	}
 	if cacheInvoker, ok := dir.cacheInvokersMap.Load(key); !ok {
 		logger.Debugf("service will be added in cache invokers: invokers url is  %s!", newUrl)
 		newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(newUrl)
@@ -414,6 +605,53 @@ func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, event *registry
 	return nil, false
 }
 
+// applySubset reselects the subset of the providers, refers the providers which join the subset and destroys
+// the ones which leave it. It does nothing if subsetting is disabled.
+func (dir *RegistryDirectory) applySubset() {
+	if dir.subset == nil {
+		return
+	}
+	dir.subsetLock.Lock()
+	defer dir.subsetLock.Unlock()
+
+	providers := make(map[string]*common.URL)
+	dir.providerURLs.Range(func(key, value interface{}) bool {
+		providers[key.(string)] = value.(*common.URL)
+		return true
+	})
+	selected := dir.subset.selectKeys(providers)
+
+	var evicted []protocol.Invoker
+	dir.cacheInvokersMap.Range(func(key, value interface{}) bool {
+		if _, ok := selected[key.(string)]; !ok {
+			dir.cacheInvokersMap.Delete(key)
+			evicted = append(evicted, value.(protocol.Invoker))
+		}
+		return true
+	})
+	joined := 0
+	for key := range selected {
+		if _, ok := dir.cacheInvokersMap.Load(key); ok {
+			continue
+		}
+		newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(providers[key])
+		if newInvoker == nil {
+			logger.Warnf("[Registry Directory] refer the subset provider fail, url is %s", providers[key])
+			continue
+		}
+		dir.cacheInvokersMap.Store(key, newInvoker)
+		joined++
+	}
+	for _, invoker := range evicted {
+		go invoker.Destroy()
+	}
+	if joined > 0 || len(evicted) > 0 {
+		logger.Infof("[Registry Directory] subset of %s is recomputed, %d of %d providers are selected, %d joined, %d left",
+			dir.serviceType, len(selected), len(providers), joined, len(evicted))
+	}
+	metrics.Publish(metricsRegistry.NewSubsetEvent(dir.serviceType, len(selected)))
+}
+
 // List selected protocol invokers from the directory
 func (dir *RegistryDirectory) List(invocation protocol.Invocation) []protocol.Invoker {
 	routerChain := dir.RouterChain()
@@ -426,6 +664,28 @@ func (dir *RegistryDirectory) List(invocation protocol.Invocation) []protocol.In
 	return routerChain.Route(dir.consumerURL, invocation)
 }
 
+// Invokers returns the invokers of the providers notified by the registry before routing
+func (dir *RegistryDirectory) Invokers() []protocol.Invoker {
+	dir.invokersLock.RLock()
+	defer dir.invokersLock.RUnlock()
+	return dir.cacheInvokers
+}
+
+// Inspect snapshots the invokers notified by the registry and how @invocation is routed among them, the invokers
+// are copied under the read lock only
+func (dir *RegistryDirectory) Inspect(invocation protocol.Invocation) directory.Inspection {
+	dir.invokersLock.RLock()
+	inspection := directory.Inspection{
+		URL:      dir.consumerURL,
+		Invokers: append([]protocol.Invoker(nil), dir.cacheInvokers...),
+	}
+	dir.invokersLock.RUnlock()
+	if routerChain := dir.RouterChain(); routerChain != nil {
+		inspection.Routes = directory.Trace(routerChain, dir.consumerURL, invocation)
+	}
+	return inspection
+}
+
 // IsAvailable  whether the directory is available
 func (dir *RegistryDirectory) IsAvailable() bool {
 	if !dir.Directory.IsAvailable() {
@@ -451,6 +711,9 @@ func (dir *RegistryDirectory) Destroy() {
 			ivk.Destroy()
 		}
 	})
+	if dir.subscribed.CAS(true, false) {
+		registry.AddSubscriptions(-1)
+	}
 	metrics.Publish(metricsRegistry.NewDirectoryEvent(metricsRegistry.NumAllDec))
 }
 
@@ -460,6 +723,59 @@ func (dir *RegistryDirectory) overrideUrl(targetUrl *common.URL) {
 	doOverrideUrl(dir.referenceConfigurationListener.Configurators(), targetUrl)
 }
 
+// overrideProviderUrl applies the override rules to the provider url merged with the reference url. The merged url
+// keeps the side and the application of the provider, so the rules of the consumer side are applied to it once more
+// as if it was the consumer url, they pick the providers by the providerAddresses.
+func (dir *RegistryDirectory) overrideProviderUrl(providerUrl *common.URL) {
+	dir.overrideUrl(providerUrl)
+
+	referenceUrl := dir.GetDirectoryUrl().SubURL
+	restore := make(map[string][]string, 2)
+	for _, key := range []string{constant.SideKey, constant.ApplicationKey} {
+		restore[key] = providerUrl.GetParams()[key]
+	}
+	providerUrl.SetParam(constant.SideKey, common.DubboRole[common.CONSUMER])
+	providerUrl.SetParam(constant.ApplicationKey, referenceUrl.GetParam(constant.ApplicationKey, ""))
+	dir.overrideUrl(providerUrl)
+	for key, values := range restore {
+		if len(values) == 0 {
+			providerUrl.DelParam(key)
+		} else {
+			providerUrl.SetParam(key, values[0])
+		}
+	}
+}
+
+// refreshOverride applies the override rules to the original urls of all providers again after the rules change.
+// The invokers whose url is changed are referred again, and the disabled providers are removed from the invokers.
+func (dir *RegistryDirectory) refreshOverride() {
+	var oldInvokers []protocol.Invoker
+	func() {
+		dir.registerLock.Lock()
+		defer dir.registerLock.Unlock()
+		dir.originURLs.Range(func(k, v interface{}) bool {
+			key := k.(string)
+			newUrl := v.(*common.URL).Clone()
+			dir.overrideProviderUrl(newUrl)
+			if registry.IsDisabled(newUrl) {
+				if invoker := dir.evictInvoker(key); invoker != nil {
+					logger.Infof("[Registry Directory] provider %s is disabled by the override rules", newUrl.Location)
+					oldInvokers = append(oldInvokers, invoker)
+				}
+				return true
+			}
+			if oldInvoker, _ := dir.doCacheInvoker(newUrl, key); oldInvoker != nil {
+				oldInvokers = append(oldInvokers, oldInvoker)
+			}
+			return true
+		})
+	}()
+	dir.setNewInvokers()
+	for _, invoker := range oldInvokers {
+		go invoker.Destroy()
+	}
+}
+
 func (dir *RegistryDirectory) getConsumerUrl(c *common.URL) *common.URL {
 	processID := fmt.Sprintf("%d", os.Getpid())
 	localIP := common.GetLocalIp()
@@ -502,9 +818,11 @@ func newReferenceConfigurationListener(dir *RegistryDirectory, url *common.URL)
 
 // Process handle events and update Invokers
 func (l *referenceConfigurationListener) Process(event *config_center.ConfigChangeEvent) {
+	if event.Unchanged() {
+		return
+	}
 	l.BaseConfigurationListener.Process(event)
-	// FIXME: this doesn't trigger dir.overrideUrl()
-	l.directory.refreshInvokers(nil)
+	l.directory.refreshOverride()
 }
 
 type consumerConfigurationListener struct {
@@ -530,7 +848,9 @@ func (l *consumerConfigurationListener) addNotifyListener(listener registry.Noti
 
 // Process handles events from Configuration Center and update Invokers
 func (l *consumerConfigurationListener) Process(event *config_center.ConfigChangeEvent) {
+	if event.Unchanged() {
+		return
+	}
 	l.BaseConfigurationListener.Process(event)
-	// FIXME: this doesn't trigger dir.overrideUrl()
-	l.directory.refreshInvokers(nil)
+	l.directory.refreshOverride()
 }
@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package directory
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// subsetSelector selects a stable subset of the providers of a reference by rendezvous hashing: each provider
+// is scored by the hash of the consumer identity and the provider identity, and the providers with the highest
+// scores are selected. As the scores are independent of each other, a provider joining or leaving changes at
+// most one member of the subset, and the subsets of different consumers are spread evenly over the providers.
+type subsetSelector struct {
+	size       int
+	consumerID string
+}
+
+// newSubsetSelector returns nil if subset.size is not configured on the consumer url
+func newSubsetSelector(consumerURL *common.URL) *subsetSelector {
+	size := consumerURL.GetParamByIntValue(constant.SubsetSizeKey, 0)
+	if size <= 0 {
+		return nil
+	}
+	return &subsetSelector{
+		size:       size,
+		consumerID: consumerURL.GetParam(constant.SubsetIDKey, fmt.Sprintf("%s:%d", common.GetLocalIp(), os.Getpid())),
+	}
+}
+
+// selectKeys returns the cache keys of the selected providers
+func (s *subsetSelector) selectKeys(providers map[string]*common.URL) map[string]struct{} {
+	selected := make(map[string]struct{}, s.size)
+	if len(providers) <= s.size {
+		for key := range providers {
+			selected[key] = struct{}{}
+		}
+		return selected
+	}
+
+	type scored struct {
+		key   string
+		score uint64
+	}
+	candidates := make([]scored, 0, len(providers))
+	for key, url := range providers {
+		candidates = append(candidates, scored{key: key, score: s.score(url)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].key < candidates[j].key
+	})
+	for _, c := range candidates[:s.size] {
+		selected[c.key] = struct{}{}
+	}
+	return selected
+}
+
+// score hashes the consumer identity with the provider identity, which excludes the params changing on restart
+func (s *subsetSelector) score(url *common.URL) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s.consumerID))
+	_, _ = h.Write([]byte{'#'})
+	_, _ = h.Write([]byte(url.Protocol + "://" + url.Location + "/" + url.ServiceKey()))
+	// fnv mixes the trailing bytes poorly, finalize it as splitmix64 does
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
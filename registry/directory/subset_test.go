@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package directory
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func newSubsetProviders(from, to int) map[string]*common.URL {
+	providers := make(map[string]*common.URL)
+	for i := from; i < to; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://10.0.%d.%d:20000/org.apache.dubbo-go.mockService", i/256, i%256))
+		providers[url.GetCacheInvokerMapKey()] = url
+	}
+	return providers
+}
+
+func newTestSubsetSelector(size int, consumerID string) *subsetSelector {
+	url, _ := common.NewURL("consumer://127.0.0.1/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.SubsetSizeKey, fmt.Sprint(size)),
+		common.WithParamsValue(constant.SubsetIDKey, consumerID))
+	return newSubsetSelector(url)
+}
+
+func TestNewSubsetSelector(t *testing.T) {
+	url, _ := common.NewURL("consumer://127.0.0.1/org.apache.dubbo-go.mockService")
+	assert.Nil(t, newSubsetSelector(url))
+
+	url.SetParam(constant.SubsetSizeKey, "20")
+	s := newSubsetSelector(url)
+	assert.Equal(t, 20, s.size)
+	assert.NotEmpty(t, s.consumerID)
+
+	s = newTestSubsetSelector(2, "consumer-1")
+	assert.Equal(t, "consumer-1", s.consumerID)
+	assert.Len(t, s.selectKeys(newSubsetProviders(0, 1)), 1)
+}
+
+func TestSubsetBalance(t *testing.T) {
+	const (
+		providerNum = 100
+		consumerNum = 1000
+		size        = 10
+	)
+	providers := newSubsetProviders(0, providerNum)
+	counts := make(map[string]int)
+	for i := 0; i < consumerNum; i++ {
+		selected := newTestSubsetSelector(size, fmt.Sprintf("192.168.%d.%d:%d", i/256, i%256, 1000+i)).selectKeys(providers)
+		assert.Len(t, selected, size)
+		for key := range selected {
+			counts[key]++
+		}
+	}
+	// each provider is expected to be selected by consumerNum*size/providerNum consumers
+	expected := consumerNum * size / providerNum
+	assert.Len(t, counts, providerNum)
+	for key, count := range counts {
+		assert.InDelta(t, expected, count, float64(expected)/2, key)
+	}
+}
+
+func TestSubsetStable(t *testing.T) {
+	s := newTestSubsetSelector(10, "192.168.0.1:1000")
+	providers := newSubsetProviders(0, 100)
+	selected := s.selectKeys(providers)
+	assert.Equal(t, selected, s.selectKeys(providers))
+
+	// a provider joining replaces at most one member
+	for key, url := range newSubsetProviders(100, 101) {
+		providers[key] = url
+	}
+	assert.GreaterOrEqual(t, overlap(selected, s.selectKeys(providers)), 9)
+
+	// a provider leaving replaces only itself
+	for key := range selected {
+		delete(providers, key)
+		break
+	}
+	assert.GreaterOrEqual(t, overlap(selected, s.selectKeys(providers)), 8)
+
+	// a non selected provider leaving changes nothing
+	current := s.selectKeys(providers)
+	for key := range providers {
+		if _, ok := current[key]; !ok {
+			delete(providers, key)
+			break
+		}
+	}
+	assert.Equal(t, current, s.selectKeys(providers))
+}
+
+func overlap(a, b map[string]struct{}) int {
+	n := 0
+	for key := range a {
+		if _, ok := b[key]; ok {
+			n++
+		}
+	}
+	return n
+}
@@ -73,7 +73,7 @@ func (r *etcdV3Registry) ClientLock() *sync.Mutex {
 }
 
 func newETCDV3Registry(url *common.URL) (registry.Registry, error) {
-	timeout := url.GetParamDuration(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)
+	timeout := url.GetParamDurationWithDefault(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)
 
 	logger.Infof("etcd address is: %v, timeout is: %s", url.Location, timeout.String())
 
@@ -106,7 +106,7 @@ func (r *etcdV3Registry) InitListeners() {
 // DoRegister actually do the register job in the registry center of etcd
 // for lease
 func (r *etcdV3Registry) DoRegister(root string, node string) error {
-	return r.client.RegisterTemp(path.Join(root, node), "")
+	return etcdv3.RegisterTemp(r.client, path.Join(root, node), "")
 }
 
 // nolint
@@ -116,7 +116,7 @@ func (r *etcdV3Registry) DoUnregister(root string, node string) error {
 
 // CloseAndNilClient closes listeners and clear client
 func (r *etcdV3Registry) CloseAndNilClient() {
-	r.client.Close()
+	etcdv3.ReleaseClient(r.client)
 	r.client = nil
 }
 
@@ -88,7 +88,7 @@ func (e *etcdV3ServiceDiscovery) Register(instance registry.ServiceInstance) err
 	if nil != e.client {
 		ins, err := jsonutil.EncodeJSON(instance)
 		if err == nil {
-			err = e.client.RegisterTemp(path, string(ins))
+			err = etcdv3.RegisterTemp(e.client, path, string(ins))
 			if err != nil {
 				logger.Errorf("cannot register the instance: %s", string(ins), err)
 			} else {
@@ -107,8 +107,8 @@ func (e *etcdV3ServiceDiscovery) Update(instance registry.ServiceInstance) error
 	if nil != e.client {
 		ins, err := jsonutil.EncodeJSON(instance)
 		if err == nil {
-			if err = e.client.RegisterTemp(path, string(ins)); err != nil {
-				logger.Warnf("etcdV3ServiceDiscovery.client.RegisterTemp(path:%v, instance:%v) = error:%v",
+			if err = etcdv3.RegisterTemp(e.client, path, string(ins)); err != nil {
+				logger.Warnf("etcdv3.RegisterTemp(path:%v, instance:%v) = error:%v",
 					path, string(ins), err)
 			}
 			e.services.Add(instance.GetServiceName())
@@ -123,7 +123,7 @@ func (e *etcdV3ServiceDiscovery) Unregister(instance registry.ServiceInstance) e
 	path := toPath(instance)
 
 	if nil != e.client {
-		err := e.client.Delete(path)
+		err := etcdv3.DeleteTemp(e.client, path)
 		e.services.Remove(instance.GetServiceName())
 		e.serviceInstance = nil
 		return err
@@ -305,7 +305,7 @@ func newEtcdV3ServiceDiscovery(url *common.URL) (registry.ServiceDiscovery, erro
 	initLock.Lock()
 	defer initLock.Unlock()
 
-	timeout := url.GetParamDuration(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)
+	timeout := url.GetParamDurationWithDefault(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)
 
 	logger.Infof("etcd address is: %v,timeout is:%s", url.Location, timeout.String())
 
@@ -108,9 +108,9 @@ func ToConfigurators(urls []*common.URL, f func(url *common.URL) config_center.C
 			break
 		}
 
+		// the params of the url are shared, the url overriding nothing but the anyhost is skipped without deleting it
 		override := url.GetParams()
-		delete(override, constant.AnyhostKey)
-		if len(override) == 0 {
+		if _, anyhost := override[constant.AnyhostKey]; len(override) == 0 || anyhost && len(override) == 1 {
 			continue
 		}
 		configurators = append(configurators, f(url))
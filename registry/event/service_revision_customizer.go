@@ -49,7 +49,8 @@ func (e *exportedServicesRevisionMetadataCustomizer) GetPriority() int {
 	return 1
 }
 
-// Customize calculate the revision for exported urls and then put it into instance metadata
+// Customize puts the revision of the metadata info into instance metadata, it's the same revision the metadata info
+// is published with so that the consumers could fetch and cache the metadata info by it
 func (e *exportedServicesRevisionMetadataCustomizer) Customize(instance registry.ServiceInstance) {
 	ms, err := local.GetLocalMetadataService()
 	if err != nil {
@@ -57,14 +58,12 @@ func (e *exportedServicesRevisionMetadataCustomizer) Customize(instance registry
 		return
 	}
 
-	urls, err := ms.GetExportedURLs(constant.AnyValue, constant.AnyValue, constant.AnyValue, constant.AnyValue)
+	revision := defaultRevision
+	info, err := ms.GetMetadataInfo("")
 	if err != nil {
-		logger.Errorf("could not find the exported url", err)
-	}
-
-	revision := resolveRevision(urls)
-	if len(revision) == 0 {
-		revision = defaultRevision
+		logger.Errorf("could not get the metadata info", err)
+	} else if info != nil {
+		revision = info.CalAndGetRevision()
 	}
 	instance.GetMetadata()[constant.ExportedServicesRevisionPropertyName] = revision
 }
@@ -355,7 +355,8 @@ func (nr *nacosRegistry) Destroy() {
 			logger.Errorf("Deregister URL:%+v err:%v", url, err.Error())
 		}
 	}
-	return
+	// the client is closed once the service discoveries sharing it release it too
+	nacos.ReleaseNacosNamingClient(nr.namingClient)
 }
 
 // newNacosRegistry will create new instance
@@ -369,7 +370,7 @@ func newNacosRegistry(url *common.URL) (registry.Registry, error) {
 	url.SetParam(constant.NacosSecretKey, url.GetParam(constant.RegistrySecretKey, ""))
 	url.SetParam(constant.NacosTimeout, url.GetParam(constant.RegistryTimeoutKey, constant.DefaultRegTimeout))
 	url.SetParam(constant.NacosGroupKey, url.GetParam(constant.RegistryGroupKey, defaultGroup))
-	namingClient, err := nacos.NewNacosClientByURL(url)
+	namingClient, err := nacos.AcquireNacosNamingClient(url)
 	if err != nil {
 		return &nacosRegistry{}, err
 	}
@@ -69,7 +69,7 @@ type nacosServiceDiscovery struct {
 }
 
 // Destroy will close the service discovery.
-// Actually, it only marks the naming namingClient as null and then return
+// Actually, it only releases the naming namingClient shared with the registries and then return
 func (n *nacosServiceDiscovery) Destroy() error {
 	for _, inst := range n.registryInstances {
 		err := n.Unregister(inst)
@@ -78,7 +78,7 @@ func (n *nacosServiceDiscovery) Destroy() error {
 			logger.Errorf("Unregister nacos instance:%+v, err:%+v", inst, err)
 		}
 	}
-	n.namingClient.Close()
+	nacos.ReleaseNacosNamingClient(n.namingClient)
 	return nil
 }
 
@@ -331,6 +331,7 @@ func newNacosServiceDiscovery(url *common.URL) (registry.ServiceDiscovery, error
 	discoveryURL := common.NewURLWithOptions(
 		common.WithParams(url.GetParams()),
 		common.WithParamsValue(constant.TimeoutKey, url.GetParam(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)),
+		common.WithParamsValue(constant.NacosTimeout, url.GetParam(constant.RegistryTimeoutKey, constant.DefaultRegTimeout)),
 		common.WithParamsValue(constant.NacosGroupKey, url.GetParam(constant.RegistryGroupKey, defaultGroup)),
 		common.WithParamsValue(constant.NacosUsername, url.Username),
 		common.WithParamsValue(constant.NacosPassword, url.Password),
@@ -338,7 +339,7 @@ func newNacosServiceDiscovery(url *common.URL) (registry.ServiceDiscovery, error
 	discoveryURL.Location = url.Location
 	discoveryURL.Username = url.Username
 	discoveryURL.Password = url.Password
-	client, err := nacos.NewNacosClientByURL(discoveryURL)
+	client, err := nacos.AcquireNacosNamingClient(discoveryURL)
 	if err != nil {
 		return nil, perrors.WithMessage(err, "create nacos namingClient failed.")
 	}
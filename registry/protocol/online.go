@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"regexp"
+	"sort"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// ProviderState is the registration state of a service exported to a registry
+type ProviderState struct {
+	// ServiceKey is the service key of the provider url, like group/interface:version
+	ServiceKey string
+	// Registry is the address of the registry, like zookeeper://127.0.0.1:2181
+	Registry string
+	// Registered is false if the provider is offline, disabled by the override rules or failed to register
+	Registered bool
+	// Offline is true if the provider is unregistered by Offline
+	Offline bool
+}
+
+// GetProviderStates returns the registration states of the services exported to the registries, which are ordered by
+// the service keys
+func GetProviderStates() []ProviderState {
+	if regProtocol == nil {
+		return nil
+	}
+	return regProtocol.providerStates()
+}
+
+// Offline unregisters the services whose service keys or interfaces fully match the regular expression @pattern, or
+// all the services if it's empty, from the registries. The services are still exported and keep unregistered until
+// Online, even if the override rules change. It returns the service keys of the services unregistered.
+func Offline(pattern string) ([]string, error) {
+	if regProtocol == nil {
+		return nil, nil
+	}
+	return regProtocol.setOffline(pattern, true)
+}
+
+// Online registers the services whose service keys or interfaces fully match the regular expression @pattern, or all
+// the services if it's empty, to the registries again, except the ones disabled by the override rules. It returns the
+// service keys of the services registered.
+func Online(pattern string) ([]string, error) {
+	if regProtocol == nil {
+		return nil, nil
+	}
+	return regProtocol.setOffline(pattern, false)
+}
+
+func (proto *registryProtocol) providerStates() []ProviderState {
+	proto.registerLock.Lock()
+	defer proto.registerLock.Unlock()
+	var states []ProviderState
+	proto.bounds.Range(func(_, value interface{}) bool {
+		exporter := value.(*exporterChangeableWrapper)
+		registryUrl := getRegistryUrl(exporter.originInvoker)
+		if len(registryUrl.Protocol) == 0 {
+			return true
+		}
+		states = append(states, ProviderState{
+			ServiceKey: exporter.GetInvoker().GetURL().ServiceKey(),
+			Registry:   registryUrl.Protocol + "://" + registryUrl.Location,
+			Registered: exporter.registerUrl != nil,
+			Offline:    exporter.offline,
+		})
+		return true
+	})
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].ServiceKey != states[j].ServiceKey {
+			return states[i].ServiceKey < states[j].ServiceKey
+		}
+		return states[i].Registry < states[j].Registry
+	})
+	return states
+}
+
+// setOffline unregisters the services matching @pattern if @offline, otherwise registers them again
+func (proto *registryProtocol) setOffline(pattern string, offline bool) ([]string, error) {
+	matcher, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, perrors.Errorf("invalid service pattern %q, %v", pattern, err)
+	}
+	proto.registerLock.Lock()
+	defer proto.registerLock.Unlock()
+	changed := make(map[string]bool)
+	proto.bounds.Range(func(_, value interface{}) bool {
+		exporter := value.(*exporterChangeableWrapper)
+		providerUrl := exporter.GetInvoker().GetURL()
+		if pattern != "" && !matcher.MatchString(providerUrl.ServiceKey()) && !matcher.MatchString(providerUrl.Service()) {
+			return true
+		}
+		registryUrl := getRegistryUrl(exporter.originInvoker)
+		if len(registryUrl.Protocol) == 0 || exporter.offline == offline {
+			return true
+		}
+		exporter.offline = offline
+		reg := proto.getRegistry(registryUrl)
+		if offline {
+			if exporter.registerUrl == nil {
+				return true
+			}
+			if err := reg.UnRegister(exporter.registerUrl); err != nil {
+				logger.Warnf("provider service %v unregister registry %v error, error message is %s",
+					providerUrl.Key(), registryUrl.Key(), err.Error())
+				return true
+			}
+			exporter.SetRegisterUrl(nil)
+		} else {
+			if registry.IsDisabled(providerUrl) {
+				return true
+			}
+			registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
+			if err := reg.Register(registeredProviderUrl); err != nil {
+				logger.Errorf("provider service %v register registry %v error, error message is %s",
+					providerUrl.Key(), registryUrl.Key(), err.Error())
+				return true
+			}
+			exporter.SetRegisterUrl(registeredProviderUrl)
+		}
+		changed[providerUrl.ServiceKey()] = true
+		return true
+	})
+	serviceKeys := make([]string, 0, len(changed))
+	for serviceKey := range changed {
+		serviceKeys = append(serviceKeys, serviceKey)
+	}
+	sort.Strings(serviceKeys)
+	return serviceKeys, nil
+}
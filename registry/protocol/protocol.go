@@ -34,7 +34,9 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/status"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/configurator"
@@ -67,10 +69,13 @@ type registryProtocol struct {
 	serviceConfigurationListeners *sync.Map
 	providerConfigurationListener *providerConfigurationListener
 	once                          sync.Once
+	// registerLock guards the registrations of the exported services changed at runtime, see Offline
+	registerLock sync.Mutex
 }
 
 func init() {
 	extension.SetProtocol(constant.RegistryProtocol, GetProtocol)
+	extension.SetStatusChecker("registry", status.CheckerFunc(checkRegistries))
 }
 
 func newRegistryProtocol() *registryProtocol {
@@ -90,6 +95,7 @@ func (proto *registryProtocol) getRegistry(registryUrl *common.URL) registry.Reg
 			panic(err)
 		}
 		proto.registries.Store(registryUrl.PrimitiveURL, reg)
+		eventbus.Publish(eventbus.RegistryConnected{URL: registryUrl})
 	}
 	return reg.(registry.Registry)
 }
@@ -169,8 +175,11 @@ func (proto *registryProtocol) Refer(url *common.URL) protocol.Invoker {
 			serviceUrl.String(), registryUrl.String(), err.Error())
 	}
 
-	// new cluster invoker
+	// new cluster invoker, the results of multiple groups are merged by the mergeable cluster
 	clusterKey := serviceUrl.GetParam(constant.ClusterKey, constant.DefaultCluster)
+	if isMultiGroup(serviceUrl.GetParam(constant.GroupKey, "")) {
+		clusterKey = constant.ClusterKeyMergeable
+	}
 	cluster, err := extension.GetCluster(clusterKey)
 	if err != nil {
 		panic(err)
@@ -205,13 +214,19 @@ func (proto *registryProtocol) Export(originInvoker protocol.Invoker) protocol.E
 	if len(registryUrl.Protocol) > 0 {
 		// url to registry
 		reg := proto.getRegistry(registryUrl)
-		registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
-
-		err := reg.Register(registeredProviderUrl)
-		if err != nil {
-			logger.Errorf("provider service %v register registry %v error, error message is %s",
-				providerUrl.Key(), registryUrl.Key(), err.Error())
-			return nil
+		if registry.IsDisabled(providerUrl) {
+			logger.Infof("provider service %v is disabled by the override rules, it is not registered to registry %v",
+				providerUrl.Key(), registryUrl.Key())
+		} else {
+			registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
+
+			err := reg.Register(registeredProviderUrl)
+			if err != nil {
+				logger.Errorf("provider service %v register registry %v error, error message is %s",
+					providerUrl.Key(), registryUrl.Key(), err.Error())
+				return nil
+			}
+			exporter.SetRegisterUrl(registeredProviderUrl)
 		}
 
 		go func() {
@@ -220,7 +235,6 @@ func (proto *registryProtocol) Export(originInvoker protocol.Invoker) protocol.E
 			}
 		}()
 
-		exporter.SetRegisterUrl(registeredProviderUrl)
 		exporter.SetSubscribeUrl(overriderUrl)
 
 	} else {
@@ -237,49 +251,105 @@ func (proto *registryProtocol) doLocalExport(originInvoker protocol.Invoker, pro
 	if !loaded {
 		// new Exporter
 		invokerDelegate := newInvokerDelegate(originInvoker, providerUrl)
-		cachedExporter = newExporterChangeableWrapper(originInvoker,
+		wrapper := newExporterChangeableWrapper(originInvoker,
 			extension.GetProtocol(protocolwrapper.FILTER).Export(invokerDelegate))
+		wrapper.protocol, wrapper.key = proto, key
+		cachedExporter = wrapper
 		proto.bounds.Store(key, cachedExporter)
 	}
 	return cachedExporter.(*exporterChangeableWrapper)
 }
 
+// reExport exports the service again with the provider url @newUrl which the override rules are applied to, and
+// replaces the registered provider url. The exporter is still keyed by the original invoker, so the original url is
+// restored once the rules are deleted.
 func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common.URL) {
 	key := getCacheKey(invoker)
-	if oldExporter, loaded := proto.bounds.Load(key); loaded {
-		wrappedNewInvoker := newInvokerDelegate(invoker, newUrl)
-		oldExporter.(protocol.Exporter).UnExport()
-		proto.bounds.Delete(key)
+	if cachedExporter, loaded := proto.bounds.Load(key); loaded {
+		exporter := cachedExporter.(*exporterChangeableWrapper)
+		exporter.exporter.UnExport()
 		// oldExporter UnExport function unRegister rpcService from the serviceMap, so need register it again as far as possible
 		if err := registerServiceMap(invoker); err != nil {
 			logger.Error(err.Error())
 		}
-		proto.Export(wrappedNewInvoker)
-		// TODO:  unregister & unsubscribe
+		exporter.exporter = extension.GetProtocol(protocolwrapper.FILTER).Export(newInvokerDelegate(invoker, newUrl))
+		proto.reRegister(exporter, newUrl)
 	}
 }
 
-func registerServiceMap(invoker protocol.Invoker) error {
-	providerUrl := getProviderUrl(invoker)
-	// the bean.name param of providerUrl is the ServiceConfig id property
-	// such as dubbo://:20000/org.apache.dubbo.UserProvider?bean.name=UserProvider&cluster=failfast...
-	id := providerUrl.GetParam(constant.BeanNameKey, "")
+// reRegister unregisters the provider url registered by the exporter, and registers @providerUrl instead unless the
+// provider is disabled by the override rules. The disabled provider keeps being exported, so it can be enabled again.
+func (proto *registryProtocol) reRegister(exporter *exporterChangeableWrapper, providerUrl *common.URL) {
+	registryUrl := getRegistryUrl(exporter.originInvoker)
+	if len(registryUrl.Protocol) == 0 {
+		return
+	}
+	proto.registerLock.Lock()
+	defer proto.registerLock.Unlock()
+	reg := proto.getRegistry(registryUrl)
+	registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
+	if exporter.registerUrl != nil {
+		if registeredProviderUrl.String() == exporter.registerUrl.String() {
+			return
+		}
+		if err := reg.UnRegister(exporter.registerUrl); err != nil {
+			logger.Warnf("provider service %v unregister registry %v error, error message is %s",
+				providerUrl.Key(), registryUrl.Key(), err.Error())
+		}
+		exporter.SetRegisterUrl(nil)
+	}
+	if registry.IsDisabled(providerUrl) {
+		logger.Infof("provider service %v is disabled by the override rules, it is unregistered from registry %v",
+			providerUrl.Key(), registryUrl.Key())
+		return
+	}
+	if exporter.offline {
+		return
+	}
+	if err := reg.Register(registeredProviderUrl); err != nil {
+		logger.Errorf("provider service %v register registry %v error, error message is %s",
+			providerUrl.Key(), registryUrl.Key(), err.Error())
+		return
+	}
+	exporter.SetRegisterUrl(registeredProviderUrl)
+}
 
-	serviceConfig := config.GetProviderConfig().Services[id]
-	if serviceConfig == nil {
-		s := "reExport can not get serviceConfig"
-		return perrors.New(s)
+// unregister unregisters the provider url registered by @exporter and unsubscribes the override rules it subscribes
+func (proto *registryProtocol) unregister(exporter *exporterChangeableWrapper) {
+	registryUrl := getRegistryUrl(exporter.originInvoker)
+	if len(registryUrl.Protocol) == 0 {
+		return
+	}
+	if exporter.registerUrl != nil {
+		if err := proto.getRegistry(registryUrl).UnRegister(exporter.registerUrl); err != nil {
+			logger.Warnf("provider service %v unregister registry %v error, error message is %s",
+				exporter.registerUrl.Key(), registryUrl.Key(), err.Error())
+		}
+		exporter.SetRegisterUrl(nil)
+	}
+	if exporter.subscribeUrl != nil {
+		if listener, ok := proto.overrideListeners.Load(exporter.subscribeUrl); ok {
+			if err := proto.getRegistry(registryUrl).UnSubscribe(exporter.subscribeUrl,
+				listener.(registry.NotifyListener)); err != nil {
+				logger.Warnf("provider service unsubscribe %v error, error message is %s", exporter.subscribeUrl, err.Error())
+			}
+			proto.overrideListeners.Delete(exporter.subscribeUrl)
+		}
+		exporter.SetSubscribeUrl(nil)
 	}
-	rpcService := config.GetProviderService(id)
+}
+
+func registerServiceMap(invoker protocol.Invoker) error {
+	providerUrl := getProviderUrl(invoker)
+	// the RPCService is set on the provider url by the ServiceConfig exporting it
+	rpcService, _ := providerUrl.GetAttribute(constant.RPCServiceAttributeKey)
 	if rpcService == nil {
 		s := "reExport can not get RPCService"
 		return perrors.New(s)
 	}
 
-	_, err := common.ServiceMap.Register(serviceConfig.Interface,
-		// FIXME
-		serviceConfig.ProtocolIDs[0], serviceConfig.Group,
-		serviceConfig.Version, rpcService)
+	_, err := common.ServiceMap.Register(providerUrl.Service(), providerUrl.Protocol, providerUrl.Group(),
+		providerUrl.Version(), rpcService)
 	if err != nil {
 		s := "reExport can not re register ServiceMap. Error message is " + err.Error()
 		return perrors.New(s)
@@ -288,6 +358,7 @@ func registerServiceMap(invoker protocol.Invoker) error {
 }
 
 type overrideSubscribeListener struct {
+	mu            sync.Mutex
 	url           *common.URL
 	originInvoker protocol.Invoker
 	protocol      *registryProtocol
@@ -320,6 +391,8 @@ func (nl *overrideSubscribeListener) doOverrideIfNecessary() {
 	providerUrl := getProviderUrl(nl.originInvoker)
 	key := getCacheKey(nl.originInvoker)
 	if exporter, ok := nl.protocol.bounds.Load(key); ok {
+		nl.mu.Lock()
+		defer nl.mu.Unlock()
 		currentUrl := exporter.(protocol.Exporter).GetInvoker().GetURL()
 		// Compatible with the 2.6.x
 		if nl.configurator != nil {
@@ -338,9 +411,7 @@ func (nl *overrideSubscribeListener) doOverrideIfNecessary() {
 		}
 
 		if currentUrl.String() != providerUrl.String() {
-			newRegUrl := nl.originInvoker.GetURL().Clone()
-			setProviderUrl(newRegUrl, providerUrl)
-			nl.protocol.reExport(nl.originInvoker, newRegUrl)
+			nl.protocol.reExport(nl.originInvoker, providerUrl)
 		}
 	}
 }
@@ -351,50 +422,12 @@ func isMatched(providerUrl *common.URL, consumerUrl *common.URL) bool {
 		providerUrl.Protocol == constant.OverrideProtocol {
 		providerUrl.AddParam(constant.CategoryKey, constant.ConfiguratorsCategory)
 	}
-	consumerInterface := consumerUrl.GetParam(constant.InterfaceKey, consumerUrl.Path)
-	providerInterface := providerUrl.GetParam(constant.InterfaceKey, providerUrl.Path)
-
-	if !(constant.AnyValue == consumerInterface ||
-		constant.AnyValue == providerInterface ||
-		providerInterface == consumerInterface) {
-		return false
-	}
-
-	if !isMatchCategory(providerUrl.GetParam(constant.CategoryKey, constant.DefaultCategory),
-		consumerUrl.GetParam(constant.CategoryKey, constant.DefaultCategory)) {
-		return false
-	}
-
-	if !providerUrl.GetParamBool(constant.EnabledKey, true) &&
-		consumerUrl.GetParam(constant.EnabledKey, "") != constant.AnyValue {
-		return false
-	}
-	consumerGroup := consumerUrl.GetParam(constant.GroupKey, "")
-	consumerVersion := consumerUrl.GetParam(constant.VersionKey, "")
-	consumerClassifier := consumerUrl.GetParam(constant.ClassifierKey, "")
-
-	providerGroup := providerUrl.GetParam(constant.GroupKey, "")
-	providerVersion := providerUrl.GetParam(constant.VersionKey, "")
-	providerClassifier := providerUrl.GetParam(constant.ClassifierKey, "")
-	// todo: public static boolean isContains(String values, String value) {
-	//        return isNotEmpty(values) && isContains(CommaSplitPattern.split(values), value);
-	//    }
-	return (consumerGroup == constant.AnyValue || consumerGroup == providerGroup ||
-		strings.Contains(consumerGroup, providerGroup)) && (consumerVersion == constant.AnyValue ||
-		consumerVersion == providerVersion) && (len(consumerClassifier) == 0 ||
-		consumerClassifier == constant.AnyValue || consumerClassifier == providerClassifier)
+	return common.IsMatch(consumerUrl, providerUrl)
 }
 
-func isMatchCategory(category string, categories string) bool {
-	if len(categories) == 0 {
-		return category == constant.DefaultCategory
-	} else if strings.Contains(categories, constant.AnyValue) {
-		return true
-	} else if strings.Contains(categories, constant.RemoveValuePrefix) {
-		return !strings.Contains(categories, constant.RemoveValuePrefix+category)
-	} else {
-		return strings.Contains(categories, category)
-	}
+// isMultiGroup returns whether @group refers to all groups or several groups
+func isMultiGroup(group string) bool {
+	return group == constant.AnyValue || strings.Contains(group, constant.CommaSeparator)
 }
 
 func getSubscribedOverrideUrl(providerUrl *common.URL) *common.URL {
@@ -411,11 +444,16 @@ func (proto *registryProtocol) Destroy() {
 		// protocol holds the exporters actually, instead, registry holds them in order to avoid export repeatedly, so
 		// the work for unexport should be finished in protocol.UnExport(), see also config.destroyProviderProtocols().
 		exporter := value.(*exporterChangeableWrapper)
-		reg := proto.getRegistry(getRegistryUrl(exporter.originInvoker))
-		if err := reg.UnRegister(exporter.registerUrl); err != nil {
-			panic(err)
+		// the provider disabled by the override rules has been unregistered
+		if exporter.registerUrl != nil {
+			reg := proto.getRegistry(getRegistryUrl(exporter.originInvoker))
+			if err := reg.UnRegister(exporter.registerUrl); err != nil {
+				panic(err)
+			}
+			exporter.SetRegisterUrl(nil)
 		}
 		// TODO unsubscribeUrl
+		exporter.SetSubscribeUrl(nil)
 
 		// close all protocol server after consumerUpdateWait + stepTimeout(max time wait during
 		// waitAndAcceptNewRequests procedure)
@@ -451,10 +489,6 @@ func getProviderUrl(invoker protocol.Invoker) *common.URL {
 	return url.SubURL.Clone()
 }
 
-func setProviderUrl(regURL *common.URL, providerURL *common.URL) {
-	regURL.SubURL = providerURL
-}
-
 // GetProtocol return the singleton registryProtocol
 func GetProtocol() protocol.Protocol {
 	once.Do(func() {
@@ -486,9 +520,20 @@ type exporterChangeableWrapper struct {
 	exporter      protocol.Exporter
 	registerUrl   *common.URL
 	subscribeUrl  *common.URL
+	// offline keeps the provider unregistered until it is online, see Offline
+	offline bool
+	// protocol and key are the registry protocol caching the exporter and the key of it
+	protocol *registryProtocol
+	key      string
 }
 
+// UnExport unregisters the provider url from the registry, unsubscribes the override rules of the provider and
+// unexports the service, so that the service could be exported again at runtime
 func (e *exporterChangeableWrapper) UnExport() {
+	if e.protocol != nil {
+		e.protocol.unregister(e)
+		e.protocol.bounds.Delete(e.key)
+	}
 	e.exporter.UnExport()
 }
 
@@ -497,6 +542,11 @@ func (e *exporterChangeableWrapper) SetRegisterUrl(registerUrl *common.URL) {
 }
 
 func (e *exporterChangeableWrapper) SetSubscribeUrl(subscribeUrl *common.URL) {
+	if e.subscribeUrl == nil && subscribeUrl != nil {
+		registry.AddSubscriptions(1)
+	} else if e.subscribeUrl != nil && subscribeUrl == nil {
+		registry.AddSubscriptions(-1)
+	}
 	e.subscribeUrl = subscribeUrl
 }
 
@@ -18,6 +18,7 @@
 package protocol
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -34,6 +35,7 @@ import (
 	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/status"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/configurator"
@@ -219,6 +221,17 @@ func TestDestroy(t *testing.T) {
 	assert.Equal(t, 0, count)
 }
 
+// exportedUrl returns the provider url exported for @url, the exporter is keyed by the original provider url even if
+// the override rules are applied
+func exportedUrl(t *testing.T, regProtocol *registryProtocol, url *common.URL) *common.URL {
+	delKeys := gxset.NewSet("dynamic", "enabled")
+	exporter, ok := regProtocol.bounds.Load(url.SubURL.CloneExceptParams(delKeys).String())
+	if !assert.True(t, ok) {
+		return &common.URL{}
+	}
+	return exporter.(*exporterChangeableWrapper).GetInvoker().GetURL()
+}
+
 func TestExportWithOverrideListener(t *testing.T) {
 	extension.SetDefaultConfigurator(configurator.NewMockConfigurator)
 
@@ -237,12 +250,7 @@ func TestExportWithOverrideListener(t *testing.T) {
 	event := &registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: overrideUrl}
 	reg.MockEvent(event)
 	time.Sleep(1e9)
-	newUrl := url.SubURL.Clone()
-	newUrl.SetParam(constant.ClusterKey, "mock1")
-	delKeys := gxset.NewSet("dynamic", "enabled")
-	key := newUrl.CloneExceptParams(delKeys).String()
-	v2, _ := regProtocol.bounds.Load(key)
-	assert.NotNil(t, v2)
+	assert.Equal(t, "mock1", exportedUrl(t, regProtocol, url).GetParam(constant.ClusterKey, ""))
 }
 
 func TestExportWithServiceConfig(t *testing.T) {
@@ -258,14 +266,7 @@ func TestExportWithServiceConfig(t *testing.T) {
 	}
 	dc.(*config_center.MockDynamicConfiguration).MockServiceConfigEvent()
 
-	newUrl := url.SubURL.Clone()
-	newUrl.SetParam(constant.ClusterKey, "mock1")
-
-	delKeys := gxset.NewSet("dynamic", "enabled")
-	key := newUrl.CloneExceptParams(delKeys).String()
-	v2, _ := regProtocol.bounds.Load(key)
-
-	assert.NotNil(t, v2)
+	assert.Equal(t, "mock1", exportedUrl(t, regProtocol, url).GetParam(constant.ClusterKey, ""))
 }
 
 func TestExportWithApplicationConfig(t *testing.T) {
@@ -281,12 +282,7 @@ func TestExportWithApplicationConfig(t *testing.T) {
 	}
 	dc.(*config_center.MockDynamicConfiguration).MockApplicationConfigEvent()
 
-	newUrl := url.SubURL.Clone()
-	newUrl.SetParam(constant.ClusterKey, "mock1")
-	delKeys := gxset.NewSet("dynamic", "enabled")
-	key := newUrl.CloneExceptParams(delKeys).String()
-	v2, _ := regProtocol.bounds.Load(key)
-	assert.NotNil(t, v2)
+	assert.Equal(t, "mock1", exportedUrl(t, regProtocol, url).GetParam(constant.ClusterKey, ""))
 }
 
 func TestGetProviderUrlWithHideKey(t *testing.T) {
@@ -296,3 +292,182 @@ func TestGetProviderUrlWithHideKey(t *testing.T) {
 	assert.NotContains(t, providerUrl.GetParams(), ".d")
 	assert.Contains(t, providerUrl.GetParams(), "a")
 }
+
+func TestIsMultiGroup(t *testing.T) {
+	assert.True(t, isMultiGroup("*"))
+	assert.True(t, isMultiGroup("g1,g2"))
+	assert.False(t, isMultiGroup("g1"))
+	assert.False(t, isMultiGroup(""))
+}
+
+// defaultConfigurator is the override configurator, the tests above replace it with the mock one
+var defaultConfigurator = extension.GetDefaultConfiguratorFunc()
+
+// recordingRegistry records the provider urls which are registered
+type recordingRegistry struct {
+	*registry.MockRegistry
+	lock       sync.Mutex
+	registered map[string]*common.URL
+}
+
+func (r *recordingRegistry) Register(url *common.URL) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.registered[url.Key()] = url
+	return nil
+}
+
+func (r *recordingRegistry) UnRegister(url *common.URL) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.registered, url.Key())
+	return nil
+}
+
+func (r *recordingRegistry) registeredUrl(url *common.URL) *common.URL {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.registered[url.Key()]
+}
+
+func TestExportWithOverrideRules(t *testing.T) {
+	extension.SetDefaultConfigurator(defaultConfigurator)
+	defer extension.SetDefaultConfigurator(configurator.NewMockConfigurator)
+	reg := &recordingRegistry{registered: make(map[string]*common.URL)}
+	extension.SetRegistry("recording", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, _ := registry.NewMockRegistry(url)
+		reg.MockRegistry = mockRegistry.(*registry.MockRegistry)
+		return reg, nil
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	ccUrl, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, _ := (&config_center.MockDynamicConfigurationFactory{}).GetDynamicConfiguration(ccUrl)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+
+	regProtocol := newRegistryProtocol()
+	url, _ := common.NewURL("recording://127.0.0.1:3333")
+	url.SubURL, _ = common.NewURL(
+		"dubbo://127.0.0.1:20000/org.apache.dubbo-go.overrideService",
+		common.WithParamsValue(constant.SideKey, "provider"),
+		common.WithParamsValue(constant.WeightKey, "100"),
+	)
+	regProtocol.Export(protocol.NewBaseInvoker(url))
+	assert.Equal(t, "100", reg.registeredUrl(url.SubURL).GetParam(constant.WeightKey, ""))
+
+	listener, ok := regProtocol.serviceConfigurationListeners.Load(url.SubURL.ServiceKey())
+	assert.True(t, ok)
+	rule := func(params string) *config_center.ConfigChangeEvent {
+		return &config_center.ConfigChangeEvent{
+			Key: "org.apache.dubbo-go.overrideService" + constant.ConfiguratorSuffix,
+			Value: `configVersion: v2.7
+scope: service
+key: org.apache.dubbo-go.overrideService
+configs:
+  - side: provider
+    addresses: [127.0.0.1:20000]
+    parameters:
+      ` + params,
+			ConfigType: remoting.EventTypeUpdate,
+		}
+	}
+
+	// adjust the weight
+	listener.(*serviceConfigurationListener).Process(rule("weight: 200"))
+	assert.Equal(t, "200", exportedUrl(t, regProtocol, url).GetParam(constant.WeightKey, ""))
+	assert.Equal(t, "200", reg.registeredUrl(url.SubURL).GetParam(constant.WeightKey, ""))
+
+	// disable the provider, it is unregistered but still exported
+	listener.(*serviceConfigurationListener).Process(rule("disabled: true"))
+	assert.Nil(t, reg.registeredUrl(url.SubURL))
+	assert.Equal(t, "true", exportedUrl(t, regProtocol, url).GetParam(constant.DisabledKey, ""))
+
+	// delete the rule, the original provider url is registered again
+	listener.(*serviceConfigurationListener).Process(&config_center.ConfigChangeEvent{
+		Key:        "org.apache.dubbo-go.overrideService" + constant.ConfiguratorSuffix,
+		ConfigType: remoting.EventTypeDel,
+	})
+	registered := reg.registeredUrl(url.SubURL)
+	if assert.NotNil(t, registered) {
+		assert.Equal(t, "100", registered.GetParam(constant.WeightKey, ""))
+		assert.Empty(t, registered.GetParam(constant.DisabledKey, ""))
+	}
+	assert.Equal(t, "100", exportedUrl(t, regProtocol, url).GetParam(constant.WeightKey, ""))
+}
+
+func TestOfflineAndOnline(t *testing.T) {
+	reg := &recordingRegistry{registered: make(map[string]*common.URL)}
+	extension.SetRegistry("recording", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, _ := registry.NewMockRegistry(url)
+		reg.MockRegistry = mockRegistry.(*registry.MockRegistry)
+		return reg, nil
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	regProtocol := newRegistryProtocol()
+	export := func(service string) *common.URL {
+		url, _ := common.NewURL("recording://127.0.0.1:3333")
+		url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/"+service,
+			common.WithParamsValue(constant.InterfaceKey, service),
+			common.WithParamsValue(constant.GroupKey, "g1"))
+		regProtocol.Export(protocol.NewBaseInvoker(url))
+		return url
+	}
+	greeter, user := export("org.apache.dubbo.Greeter"), export("org.apache.dubbo.User")
+	assert.NotNil(t, reg.registeredUrl(greeter.SubURL))
+
+	_, err := regProtocol.setOffline("(", true)
+	assert.NotNil(t, err)
+
+	// the pattern matches the interfaces or the service keys fully
+	serviceKeys, err := regProtocol.setOffline(".*Greet", true)
+	assert.Nil(t, err)
+	assert.Empty(t, serviceKeys)
+	serviceKeys, err = regProtocol.setOffline("org.apache.dubbo.Greeter", true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"g1/org.apache.dubbo.Greeter"}, serviceKeys)
+	assert.Nil(t, reg.registeredUrl(greeter.SubURL))
+	assert.NotNil(t, reg.registeredUrl(user.SubURL))
+	assert.Equal(t, []ProviderState{
+		{ServiceKey: "g1/org.apache.dubbo.Greeter", Registry: "recording://127.0.0.1:3333", Offline: true},
+		{ServiceKey: "g1/org.apache.dubbo.User", Registry: "recording://127.0.0.1:3333", Registered: true},
+	}, regProtocol.providerStates())
+
+	// the offline provider is not registered by the override rules
+	exporter, _ := regProtocol.bounds.Load(getCacheKey(protocol.NewBaseInvoker(greeter)))
+	regProtocol.reRegister(exporter.(*exporterChangeableWrapper), greeter.SubURL)
+	assert.Nil(t, reg.registeredUrl(greeter.SubURL))
+
+	serviceKeys, err = regProtocol.setOffline("", true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"g1/org.apache.dubbo.User"}, serviceKeys)
+	serviceKeys, err = regProtocol.setOffline("g1/.*", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"g1/org.apache.dubbo.Greeter", "g1/org.apache.dubbo.User"}, serviceKeys)
+	assert.NotNil(t, reg.registeredUrl(greeter.SubURL))
+	assert.NotNil(t, reg.registeredUrl(user.SubURL))
+}
+
+func TestRegistryStatus(t *testing.T) {
+	regProtocol := newRegistryProtocol()
+	assert.Equal(t, status.OK("no registry"), regProtocol.registryStatus())
+
+	url, _ := common.NewURL("mock://127.0.0.1:2181")
+	mockRegistry, _ := registry.NewMockRegistry(url)
+	reg := &urlRegistry{MockRegistry: mockRegistry.(*registry.MockRegistry), url: url}
+	regProtocol.registries.Store(url.PrimitiveURL, reg)
+	assert.Equal(t, status.OK("1 registries"), regProtocol.registryStatus())
+
+	// the registry is down once it's destroyed
+	reg.Destroy()
+	assert.Equal(t, status.Error("unavailable registries: mock://127.0.0.1:2181"), regProtocol.registryStatus())
+}
+
+// urlRegistry is the mock registry of the url
+type urlRegistry struct {
+	*registry.MockRegistry
+	url *common.URL
+}
+
+func (r *urlRegistry) GetURL() *common.URL {
+	return r.url
+}
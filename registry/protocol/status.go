@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/status"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// checkRegistries checks whether the registries the services are exported to or referred from are available
+func checkRegistries(_ context.Context) status.Status {
+	if regProtocol == nil {
+		return status.OK("no registry")
+	}
+	return regProtocol.registryStatus()
+}
+
+func (proto *registryProtocol) registryStatus() status.Status {
+	var total int
+	var unavailable []string
+	proto.registries.Range(func(_, value interface{}) bool {
+		reg := value.(registry.Registry)
+		total++
+		if !reg.IsAvailable() {
+			unavailable = append(unavailable, reg.GetURL().Protocol+"://"+reg.GetURL().Location)
+		}
+		return true
+	})
+	if len(unavailable) > 0 {
+		sort.Strings(unavailable)
+		return status.Error("unavailable registries: " + strings.Join(unavailable, ", "))
+	}
+	if total == 0 {
+		return status.OK("no registry")
+	}
+	return status.OK(fmt.Sprintf("%d registries", total))
+}
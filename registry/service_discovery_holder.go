@@ -23,3 +23,10 @@ type ServiceDiscoveryHolder interface {
 	// GetServiceDiscovery get service discovery
 	GetServiceDiscovery() ServiceDiscovery
 }
+
+// InstanceRegisterer registers the instance of the application, and keeps the instance up to date while the services
+// are exported and unexported at runtime
+type InstanceRegisterer interface {
+	// RegisterInstance registers the instance of the application
+	RegisterInstance(instance ServiceInstance) error
+}
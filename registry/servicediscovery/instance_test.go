@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servicediscovery
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// updatedDiscovery counts the instances updated, the other methods of registry.ServiceDiscovery are not implemented
+type updatedDiscovery struct {
+	registry.ServiceDiscovery
+	lock    sync.Mutex
+	updated int
+}
+
+func (d *updatedDiscovery) Register(registry.ServiceInstance) error {
+	return nil
+}
+
+func (d *updatedDiscovery) Update(registry.ServiceInstance) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.updated++
+	return nil
+}
+
+func (d *updatedDiscovery) Destroy() error {
+	return nil
+}
+
+func (d *updatedDiscovery) updates() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.updated
+}
+
+func TestRegisterInstance(t *testing.T) {
+	discovery := &updatedDiscovery{}
+	r := &ServiceDiscoveryRegistry{serviceDiscovery: discovery}
+	// the services exported before the instance is registered are in the instance registered
+	eventbus.Publish(eventbus.ServiceExported{Interface: "com.ikurento.user.UserProvider"})
+	assert.Nil(t, r.RegisterInstance(&registry.DefaultServiceInstance{ServiceName: "user", Host: "127.0.0.1", Port: 20000}))
+
+	eventbus.Publish(eventbus.ServiceUnexported{Interface: "com.ikurento.user.UserProvider"})
+	assert.Eventually(t, func() bool {
+		return discovery.updates() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the instance is not updated once the registry is destroyed
+	r.Destroy()
+	eventbus.Publish(eventbus.ServiceExported{Interface: "com.ikurento.user.UserProvider"})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, discovery.updates())
+}
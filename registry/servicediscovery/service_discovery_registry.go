@@ -36,6 +36,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/metadata/mapping"
 	"dubbo.apache.org/dubbo-go/v3/metadata/service"
@@ -59,7 +60,10 @@ func init() {
 // In order to keep compatible with interface-level registry，
 // this implementation is
 type ServiceDiscoveryRegistry struct {
+	// lock guards the instance registered and the subscription of the services exported
 	lock                             sync.RWMutex
+	instance                         registry.ServiceInstance
+	unsubscribe                      func()
 	url                              *common.URL
 	serviceDiscovery                 registry.ServiceDiscovery
 	subscribedServices               *gxset.HashSet
@@ -168,12 +172,57 @@ func (s *ServiceDiscoveryRegistry) IsAvailable() bool {
 }
 
 func (s *ServiceDiscoveryRegistry) Destroy() {
+	s.lock.Lock()
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+		s.unsubscribe = nil
+	}
+	s.instance = nil
+	s.lock.Unlock()
 	err := s.serviceDiscovery.Destroy()
 	if err != nil {
 		logger.Errorf("destroy serviceDiscovery catch error:%s", err.Error())
 	}
 }
 
+// RegisterInstance registers the @instance of the application to the service discovery, the revisions of the
+// instance are updated once the services are exported or unexported later
+func (s *ServiceDiscoveryRegistry) RegisterInstance(instance registry.ServiceInstance) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.serviceDiscovery.Register(instance); err != nil {
+		return err
+	}
+	s.instance = instance
+	if s.unsubscribe == nil {
+		s.unsubscribe = eventbus.SubscribeAsync(s.updateInstance, eventbus.TypeServiceExported, eventbus.TypeServiceUnexported)
+	}
+	return nil
+}
+
+// updateInstance updates the revisions of the instance registered by the services exported now, and publishes the
+// metadata of the revision if it's stored remotely
+func (s *ServiceDiscoveryRegistry) updateInstance(event eventbus.Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.instance == nil {
+		return
+	}
+	for _, cus := range extension.GetCustomizers() {
+		cus.Customize(s.instance)
+	}
+	if err := s.serviceDiscovery.Update(s.instance); err != nil {
+		logger.Errorf("Update the instance %s at the event %s error: %v", s.instance.GetID(), event.Type(), err)
+		return
+	}
+	if s.instance.GetMetadata()[constant.MetadataStorageTypePropertyName] != constant.RemoteMetadataStorageType {
+		return
+	}
+	if remoteMetadataService, err := extension.GetRemoteMetadataService(); err == nil && remoteMetadataService != nil {
+		remoteMetadataService.PublishMetadata(s.instance.GetServiceName())
+	}
+}
+
 func (s *ServiceDiscoveryRegistry) Register(url *common.URL) error {
 	if !shouldRegister(url) {
 		return nil
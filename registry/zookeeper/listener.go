@@ -74,14 +74,22 @@ func (l *RegistryDataListener) UnSubscribeURL(url *common.URL) config_center.Con
 // DataChange accepts all events sent from the zookeeper server and trigger the corresponding listener for processing
 func (l *RegistryDataListener) DataChange(event remoting.Event) bool {
 	providersPath := constant.PathSeparator + constant.ProviderCategory + constant.PathSeparator
+	routersPath := constant.PathSeparator + constant.RoutersCategory + constant.PathSeparator
 	// Intercept the last bit
+	isRouter := false
 	index := strings.Index(event.Path, providersPath)
+	categoryPath := providersPath
+	if index == -1 {
+		index = strings.Index(event.Path, routersPath)
+		categoryPath = routersPath
+		isRouter = true
+	}
 	if index == -1 {
 		logger.Warnf("[RegistryDataListener][DataChange]Listen error zk node path {%s}, "+
-			"this listener is used to listen services which under the directory of providers/", event.Path)
+			"this listener is used to listen services which under the directory of providers/ or routers/", event.Path)
 		return false
 	}
-	url := event.Path[index+len(providersPath):]
+	url := event.Path[index+len(categoryPath):]
 	serviceURL, err := common.NewURL(url)
 	if err != nil {
 		logger.Errorf("[RegistryDataListener][DataChange]Listen NewURL({%s}) = error{%+v} event.Path={%s}", url, err, event.Path)
@@ -95,7 +103,10 @@ func (l *RegistryDataListener) DataChange(event remoting.Event) bool {
 	match := false
 	for serviceKey, listener := range l.subscribed {
 		intf, group, version := common.ParseServiceKey(serviceKey)
-		if serviceURL.ServiceKey() == serviceKey || common.IsAnyCondition(intf, group, version, serviceURL) {
+		// a router rule applies to all groups and versions of the service unless it says otherwise,
+		// which is checked later by the directory
+		if (isRouter && serviceURL.Service() == intf) || (common.MatchInterface(intf, serviceURL.Service()) &&
+			common.MatchGroup(group, serviceURL.Group()) && common.MatchVersion(version, serviceURL.Version())) {
 			listener.Process(
 				&config_center.ConfigChangeEvent{
 					Key:        event.Path,
@@ -19,7 +19,6 @@ package zookeeper
 
 import (
 	"fmt"
-	"net/url"
 	"path"
 	"sync"
 	"time"
@@ -113,7 +112,14 @@ func newMockZkRegistry(url *common.URL, opts ...gxzookeeper.Option) (*zk.TestClu
 
 // InitListeners initializes listeners of zookeeper registry center
 func (r *zkRegistry) InitListeners() {
+	r.listenerLock.Lock()
+	oldListener := r.listener
 	r.listener = zookeeper.NewZkEventListener(r.client)
+	r.listenerLock.Unlock()
+	// the goroutines of the old listener keep watching the paths after the client restarts unless it's closed
+	if oldListener != nil {
+		oldListener.Close()
+	}
 	newDataListener := NewRegistryDataListener()
 	// should recover if dataListener isn't nil before
 	if r.dataListener != nil {
@@ -134,7 +140,7 @@ func (r *zkRegistry) InitListeners() {
 					regConfigListener.Close()
 				}
 				newDataListener.SubscribeURL(regConfigListener.subscribeURL, NewRegistryConfigurationListener(r.client, r, regConfigListener.subscribeURL))
-				go r.listener.ListenServiceEvent(regConfigListener.subscribeURL, fmt.Sprintf("/%s/%s/"+constant.DefaultCategory, r.URL.GetParam(constant.RegistryGroupKey, "dubbo"), url.QueryEscape(regConfigListener.subscribeURL.Service())), newDataListener)
+				r.listenServiceEvent(regConfigListener.subscribeURL, newDataListener)
 
 			}
 		}
@@ -181,8 +187,15 @@ func (r *zkRegistry) LoadSubscribeInstances(_ *common.URL, _ registry.NotifyList
 
 // CloseAndNilClient closes listeners and clear client
 func (r *zkRegistry) CloseAndNilClient() {
-	r.listener.Close()
-	r.client.Close()
+	// the listener is nil once a service is unsubscribed
+	r.listenerLock.Lock()
+	listener := r.listener
+	r.listener = nil
+	r.listenerLock.Unlock()
+	if listener != nil {
+		listener.Close()
+	}
+	zookeeper.ReleaseZookeeperClient(r.client)
 	r.client = nil
 }
 
@@ -287,11 +300,22 @@ func (r *zkRegistry) getListener(conf *common.URL) (*RegistryConfigurationListen
 	// Interested register to dataconfig.
 	r.dataListener.SubscribeURL(conf, zkListener)
 
-	go r.listener.ListenServiceEvent(conf, fmt.Sprintf("/%s/%s/"+constant.DefaultCategory, r.URL.GetParam(constant.RegistryGroupKey, "dubbo"), url.QueryEscape(conf.Service())), r.dataListener)
+	r.listenServiceEvent(conf, r.dataListener)
 
 	return zkListener, nil
 }
 
+// listenServiceEvent listens the providers and the routers of the subscribed service
+func (r *zkRegistry) listenServiceEvent(conf *common.URL, listener *RegistryDataListener) {
+	root := fmt.Sprintf("/%s/%s/", r.URL.GetParam(constant.RegistryGroupKey, "dubbo"), common.URLEncode(conf.Service()))
+	// ListenServiceEvent returns at once, and the listening goroutines it starts are waited by the listener's Close
+	r.listener.ListenServiceEvent(conf, root+constant.DefaultCategory, listener)
+	// the listener listens all services for the any interface, no need to listen the routers again
+	if conf.Interface() != constant.AnyValue {
+		r.listener.ListenServiceEvent(conf, root+constant.RoutersCategory, listener)
+	}
+}
+
 func (r *zkRegistry) getCloseListener(conf *common.URL) (*RegistryConfigurationListener, error) {
 	var zkListener *RegistryConfigurationListener
 	r.dataListener.mutex.Lock()
@@ -34,6 +34,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/eventbus"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/registry"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
@@ -66,6 +67,7 @@ type zookeeperServiceDiscovery struct {
 func newZookeeperServiceDiscovery(url *common.URL) (registry.ServiceDiscovery, error) {
 	zksd := &zookeeperServiceDiscovery{
 		url:                 url,
+		done:                make(chan struct{}),
 		rootPath:            rootPath,
 		instanceListenerMap: make(map[string]*gxset.HashSet),
 	}
@@ -106,12 +108,16 @@ func (zksd *zookeeperServiceDiscovery) Done() chan struct{} {
 // RestartCallBack when zookeeper connection reconnect this function will be invoked.
 // try to re-register service, and listen services
 func (zksd *zookeeperServiceDiscovery) RestartCallBack() bool {
+	eventbus.Publish(eventbus.RegistryLost{URL: zksd.url})
 	zksd.csd.ReRegisterServices()
-	zksd.listenLock.Lock()
-	defer zksd.listenLock.Unlock()
-	for _, name := range zksd.listenNames {
-		zksd.csd.ListenServiceEvent(name, zksd)
-	}
+	func() {
+		zksd.listenLock.Lock()
+		defer zksd.listenLock.Unlock()
+		for _, name := range zksd.listenNames {
+			zksd.csd.ListenServiceEvent(name, zksd)
+		}
+	}()
+	eventbus.Publish(eventbus.RegistryConnected{URL: zksd.url})
 	return true
 }
 
@@ -127,7 +133,14 @@ func (zksd *zookeeperServiceDiscovery) String() string {
 
 // Destroy will destroy the clinet.
 func (zksd *zookeeperServiceDiscovery) Destroy() error {
+	close(zksd.done)
+	zksd.wg.Wait()
 	zksd.csd.Close()
+	zksd.cltLock.Lock()
+	defer zksd.cltLock.Unlock()
+	// the client is closed once the registries and the config centers sharing it release it too
+	zookeeper.ReleaseZookeeperClient(zksd.client)
+	zksd.client = nil
 	return nil
 }
 
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+var (
+	providerConnections = atomic.NewInt64(0)
+	consumerConnections = atomic.NewInt64(0)
+)
+
+// AddConnections adds @delta to the number of the open connections of the @side, which is constant.SideProvider or
+// constant.SideConsumer. It's called by the servers and the clients tracking their sessions, like the getty ones.
+func AddConnections(side string, delta int64) {
+	switch side {
+	case constant.SideProvider:
+		providerConnections.Add(delta)
+	case constant.SideConsumer:
+		consumerConnections.Add(delta)
+	}
+}
+
+// Connections returns the number of the open connections of the @side
+func Connections(side string) int64 {
+	switch side {
+	case constant.SideProvider:
+		return providerConnections.Load()
+	case constant.SideConsumer:
+		return consumerConnections.Load()
+	}
+	return 0
+}
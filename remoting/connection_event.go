@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+)
+
+// ConnectionEventType is the type of ConnectionEvent
+type ConnectionEventType int
+
+const (
+	// ConnectionConnected means the connection is established
+	ConnectionConnected ConnectionEventType = iota
+	// ConnectionDisconnected means the connection is broken by an error, or closed locally if there is no error
+	ConnectionDisconnected
+	// ConnectionReconnecting means the consumer connects the provider again to replace its lost connections
+	ConnectionReconnecting
+	// ConnectionClosedByPeer means the connection is closed by the remote side
+	ConnectionClosedByPeer
+)
+
+var connectionEventTypeStrings = [...]string{
+	"connected",
+	"disconnected",
+	"reconnecting",
+	"closed-by-peer",
+}
+
+// nolint
+func (t ConnectionEventType) String() string {
+	return connectionEventTypeStrings[t]
+}
+
+// ConnectionEvent is an event of a connection between a consumer and a provider
+type ConnectionEvent struct {
+	Type ConnectionEventType
+	// Side is the side publishing the event, constant.SideConsumer or constant.SideProvider
+	Side string
+	// LocalAddress is empty for ConnectionReconnecting, which has no connection yet
+	LocalAddress  string
+	RemoteAddress string
+	// ServiceKeys are the services multiplexed on the connection, which are the referred services on the consumer
+	// side, and the services invoked over the connection so far on the provider side
+	ServiceKeys []string
+	// Err is the cause of ConnectionDisconnected
+	Err error
+}
+
+// nolint
+func (e ConnectionEvent) String() string {
+	return fmt.Sprintf("ConnectionEvent{Type{%s}, Side{%s}, LocalAddress{%s}, RemoteAddress{%s}, ServiceKeys{%v}, Err{%v}}",
+		e.Type, e.Side, e.LocalAddress, e.RemoteAddress, e.ServiceKeys, e.Err)
+}
+
+// ConnectionListener listens the ConnectionEvent
+type ConnectionListener func(event ConnectionEvent)
+
+var (
+	connectionListenersLock sync.RWMutex
+	connectionListeners     []ConnectionListener
+
+	connectionEventsLock sync.Mutex
+	// connectionEvents are the events waiting to be delivered by the connections, a connection is present while
+	// its events are being delivered
+	connectionEvents = make(map[string][]ConnectionEvent)
+)
+
+// AddConnectionListener adds the @listener of the connection events of the consumers and the providers. The events
+// are delivered asynchronously, and in order for each connection, so a slow listener delays only the later events.
+func AddConnectionListener(listener ConnectionListener) {
+	connectionListenersLock.Lock()
+	defer connectionListenersLock.Unlock()
+	connectionListeners = append(connectionListeners, listener)
+}
+
+// PublishConnectionEvent publishes the @event to the listeners added by AddConnectionListener without blocking
+func PublishConnectionEvent(event ConnectionEvent) {
+	connectionListenersLock.RLock()
+	listening := len(connectionListeners) > 0
+	connectionListenersLock.RUnlock()
+	if !listening {
+		return
+	}
+
+	// the remote address identifies the connection as the consumers redial the same provider
+	key := event.Side + "|" + event.RemoteAddress
+	connectionEventsLock.Lock()
+	if pending, ok := connectionEvents[key]; ok {
+		connectionEvents[key] = append(pending, event)
+		connectionEventsLock.Unlock()
+		return
+	}
+	connectionEvents[key] = nil
+	connectionEventsLock.Unlock()
+
+	diagnostics.Go("connection-events", key, func() {
+		deliverConnectionEvents(key, event)
+	})
+}
+
+// deliverConnectionEvents delivers the @event and the events queued after it of the connection @key
+func deliverConnectionEvents(key string, event ConnectionEvent) {
+	for {
+		notifyConnectionListeners(event)
+
+		connectionEventsLock.Lock()
+		pending := connectionEvents[key]
+		if len(pending) == 0 {
+			delete(connectionEvents, key)
+			connectionEventsLock.Unlock()
+			return
+		}
+		event = pending[0]
+		connectionEvents[key] = pending[1:]
+		connectionEventsLock.Unlock()
+	}
+}
+
+func notifyConnectionListeners(event ConnectionEvent) {
+	connectionListenersLock.RLock()
+	listeners := connectionListeners
+	connectionListenersLock.RUnlock()
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("The connection listener panics at %s: %v", event, e)
+				}
+			}()
+			listener(event)
+		}()
+	}
+}
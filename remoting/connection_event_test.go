@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestPublishConnectionEvent(t *testing.T) {
+	const slowAddress, fastAddress = "127.0.0.1:20880", "127.0.0.1:20881"
+	release := make(chan struct{})
+	events := make(chan ConnectionEvent, 8)
+	AddConnectionListener(func(event ConnectionEvent) {
+		if event.RemoteAddress == slowAddress && event.Type == ConnectionConnected {
+			<-release
+		}
+		events <- event
+	})
+	AddConnectionListener(func(event ConnectionEvent) {
+		panic("the panics of the listeners are recovered")
+	})
+
+	publish := func(address string, eventType ConnectionEventType) {
+		PublishConnectionEvent(ConnectionEvent{Type: eventType, Side: constant.SideConsumer, RemoteAddress: address})
+	}
+	// the publishers are not blocked by the slow listener
+	publish(slowAddress, ConnectionConnected)
+	publish(slowAddress, ConnectionClosedByPeer)
+	publish(slowAddress, ConnectionReconnecting)
+	// the events of the other connections are not delayed by the slow one
+	publish(fastAddress, ConnectionConnected)
+	select {
+	case event := <-events:
+		assert.Equal(t, fastAddress, event.RemoteAddress)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the event of the fast connection is not delivered")
+	}
+
+	close(release)
+	for _, expected := range []ConnectionEventType{ConnectionConnected, ConnectionClosedByPeer, ConnectionReconnecting} {
+		select {
+		case event := <-events:
+			assert.Equal(t, slowAddress, event.RemoteAddress)
+			assert.Equal(t, expected, event.Type)
+		case <-time.After(time.Second):
+			assert.Fail(t, "the event is not delivered", expected.String())
+		}
+	}
+}
@@ -17,6 +17,10 @@
 
 package etcdv3
 
+import (
+	"time"
+)
+
 import (
 	gxetcd "github.com/dubbogo/gost/database/kv/etcd/v3"
 	"github.com/dubbogo/gost/log/logger"
@@ -24,6 +28,10 @@ import (
 	perrors "github.com/pkg/errors"
 )
 
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
 // ValidateClient validates client and sets options
 func ValidateClient(container clientFacade, opts ...gxetcd.Option) error {
 	options := &gxetcd.Options{}
@@ -36,22 +44,19 @@ func ValidateClient(container clientFacade, opts ...gxetcd.Option) error {
 
 	// new Client
 	if container.Client() == nil {
-		newClient, err := gxetcd.NewClient(options.Name, options.Endpoints, options.Timeout, options.Heartbeat)
+		newClient, err := AcquireClient(options.Name, options.Endpoints, options.Timeout, options.Heartbeat)
 		if err != nil {
-			logger.Warnf("new etcd client (name{%s}, etcd addresses{%v}, timeout{%d}) = error{%v}",
-				options.Name, options.Endpoints, options.Timeout, err)
-			return perrors.WithMessagef(err, "new client (address:%+v)", options.Endpoints)
+			return err
 		}
 		container.SetClient(newClient)
 	}
 
 	// Client lose connection with etcd server
 	if container.Client().GetRawClient() == nil {
-		newClient, err := gxetcd.NewClient(options.Name, options.Endpoints, options.Timeout, options.Heartbeat)
+		ReleaseClient(container.Client())
+		newClient, err := AcquireClient(options.Name, options.Endpoints, options.Timeout, options.Heartbeat)
 		if err != nil {
-			logger.Warnf("new etcd client (name{%s}, etcd addresses{%v}, timeout{%d}) = error{%v}",
-				options.Name, options.Endpoints, options.Timeout, err)
-			return perrors.WithMessagef(err, "new client (address:%+v)", options.Endpoints)
+			return err
 		}
 		container.SetClient(newClient)
 	}
@@ -59,6 +64,40 @@ func ValidateClient(container clientFacade, opts ...gxetcd.Option) error {
 	return nil
 }
 
+// sharedClients are the clients shared by the registries, the config centers and the metadata reports
+var sharedClients = remoting.NewSharedClients()
+
+// AcquireClient returns the client of @endpoints shared by the registries, the config centers and the metadata
+// reports with the same @timeout, a new one is created if there is none or the shared one lost the connection. The
+// client must be released by ReleaseClient.
+func AcquireClient(name string, endpoints []string, timeout time.Duration, heartbeat int) (*gxetcd.Client, error) {
+	client, err := sharedClients.Acquire(remoting.SharedClientKey(endpoints, timeout.String()),
+		func(client interface{}) bool {
+			return client.(*gxetcd.Client).GetRawClient() != nil
+		},
+		func() (interface{}, func(), error) {
+			newClient, err := gxetcd.NewClient(name, endpoints, timeout, heartbeat)
+			if err != nil {
+				logger.Warnf("new etcd client (name{%s}, etcd addresses{%v}, timeout{%d}) = error{%v}",
+					name, endpoints, timeout, err)
+				return nil, nil, perrors.WithMessagef(err, "new client (address:%+v)", endpoints)
+			}
+			return newClient, newClient.Close, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*gxetcd.Client), nil
+}
+
+// ReleaseClient releases the @client acquired by AcquireClient, it is closed once all its holders release it
+func ReleaseClient(client *gxetcd.Client) {
+	if client == nil {
+		return
+	}
+	sharedClients.Release(client)
+}
+
 // nolint
 func NewServiceDiscoveryClient(opts ...gxetcd.Option) *gxetcd.Client {
 	options := &gxetcd.Options{
@@ -0,0 +1,270 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcdv3
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	gxetcd "github.com/dubbogo/gost/database/kv/etcd/v3"
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	metricsRegistry "dubbo.apache.org/dubbo-go/v3/metrics/registry"
+)
+
+const (
+	// leaseTTL is the ttl of the lease of the temporary keys in seconds, which is long enough to survive a leader
+	// change of etcd
+	leaseTTL = 30
+	// failingAttempts is the number of the failed attempts to recover a lease or a watch after which the failure is
+	// published by StateLeaseFailing or StateWatchFailing
+	failingAttempts = 3
+
+	minRetryInterval = 100 * time.Millisecond
+	maxRetryInterval = 10 * time.Second
+)
+
+// rawClient is the part of the raw etcd client to keep the leases and the watches
+type rawClient interface {
+	clientv3.KV
+	clientv3.Lease
+	clientv3.Watcher
+}
+
+var (
+	leaseKeepersLock sync.Mutex
+	// leaseKeepers are the keepers of the temporary keys of the clients
+	leaseKeepers = make(map[*gxetcd.Client]*leaseKeeper)
+)
+
+// RegisterTemp puts the temporary key @k with the value @v by the @client. Unlike the RegisterTemp of the client,
+// whose key is lost silently once its keepalive stops, e.g. the lease expires during a leader change of etcd, the
+// temporary keys of a client share a lease, which is granted again and all the keys are put again once it is lost.
+// The key must be removed by DeleteTemp, otherwise it is put again on the recovery.
+func RegisterTemp(client *gxetcd.Client, k, v string) error {
+	keeper, err := getLeaseKeeper(client)
+	if err != nil {
+		return perrors.WithMessagef(err, "keepalive kv (key %s)", k)
+	}
+	return perrors.WithMessagef(keeper.put(k, v), "keepalive kv (key %s)", k)
+}
+
+// DeleteTemp deletes the temporary key @k put by RegisterTemp with the @client
+func DeleteTemp(client *gxetcd.Client, k string) error {
+	leaseKeepersLock.Lock()
+	keeper := leaseKeepers[client]
+	leaseKeepersLock.Unlock()
+	if keeper != nil {
+		keeper.remove(k)
+	}
+	return client.Delete(k)
+}
+
+func getLeaseKeeper(client *gxetcd.Client) (*leaseKeeper, error) {
+	leaseKeepersLock.Lock()
+	defer leaseKeepersLock.Unlock()
+	if keeper, ok := leaseKeepers[client]; ok {
+		return keeper, nil
+	}
+	raw := client.GetRawClient()
+	if raw == nil {
+		return nil, gxetcd.ErrNilETCDV3Client
+	}
+	keeper := newLeaseKeeper(raw, client.GetCtx(), client.Done(), client.GetEndPoints())
+	leaseKeepers[client] = keeper
+	go func() {
+		<-client.Done()
+		leaseKeepersLock.Lock()
+		delete(leaseKeepers, client)
+		leaseKeepersLock.Unlock()
+	}()
+	return keeper, nil
+}
+
+// leaseKeeper keeps the lease of the temporary keys of a client alive, and recovers the lease and the keys once the
+// keepalive channel of the lease is closed while the client is alive
+type leaseKeeper struct {
+	raw       rawClient
+	ctx       context.Context
+	done      <-chan struct{}
+	endpoints []string
+
+	lock sync.Mutex
+	// kvs are the temporary keys and their values
+	kvs       map[string]string
+	lease     clientv3.LeaseID
+	keepAlive <-chan *clientv3.LeaseKeepAliveResponse
+	keeping   bool
+}
+
+func newLeaseKeeper(raw rawClient, ctx context.Context, done <-chan struct{}, endpoints []string) *leaseKeeper {
+	return &leaseKeeper{
+		raw:       raw,
+		ctx:       ctx,
+		done:      done,
+		endpoints: endpoints,
+		kvs:       make(map[string]string),
+	}
+}
+
+// put puts the temporary key @k with the value @v by the lease, which is granted if there is none
+func (k *leaseKeeper) put(key, value string) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if k.lease == clientv3.NoLease {
+		if err := k.grant(); err != nil {
+			return err
+		}
+	}
+	if !k.keeping {
+		k.keeping = true
+		go k.keep()
+	}
+	// the key is owned even if it fails to be put, it is put again once the lost lease is recovered
+	k.kvs[key] = value
+	_, err := k.raw.Put(k.ctx, key, value, clientv3.WithLease(k.lease))
+	return perrors.WithMessage(err, "put k/v with lease")
+}
+
+func (k *leaseKeeper) remove(key string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	delete(k.kvs, key)
+}
+
+// grant grants a new lease and keeps it alive, it must be called with the lock held
+func (k *leaseKeeper) grant() error {
+	lease, err := k.raw.Grant(k.ctx, leaseTTL)
+	if err != nil {
+		return perrors.WithMessage(err, "grant lease")
+	}
+	keepAlive, err := k.raw.KeepAlive(k.ctx, lease.ID)
+	if err != nil {
+		_, _ = k.raw.Revoke(k.ctx, lease.ID)
+		return perrors.WithMessage(err, "keep alive lease")
+	}
+	k.lease = lease.ID
+	k.keepAlive = keepAlive
+	return nil
+}
+
+// keep consumes the keepalive responses, and recovers the lease once the keepalive channel is closed, until the
+// client is closed
+func (k *leaseKeeper) keep() {
+	for {
+		k.lock.Lock()
+		lease, keepAlive := k.lease, k.keepAlive
+		k.lock.Unlock()
+		for range keepAlive {
+			// the responses are drained, otherwise the keepalive queue of the raw client is full
+		}
+		if clientClosed(k.ctx, k.done) {
+			return
+		}
+		logger.Warnf("The keepalive of the etcd lease %x of %v stops, grant a new lease and put the keys again",
+			lease, k.endpoints)
+		if !k.recoverLease() {
+			return
+		}
+	}
+}
+
+// recoverLease grants a new lease and puts all the keys again by it until it succeeds, it returns false if the
+// client is closed
+func (k *leaseKeeper) recoverLease() bool {
+	for attempt := 1; ; attempt++ {
+		err := k.regrant()
+		metrics.Publish(metricsRegistry.NewLeaseRecoveryEvent(err == nil))
+		if err == nil {
+			logger.Infof("The etcd lease of %v is recovered after %d attempt(s)", k.endpoints, attempt)
+			publishStateEvent(StateEvent{Type: StateLeaseRecovered, Endpoints: k.endpoints, Attempts: attempt - 1})
+			return true
+		}
+		logger.Warnf("Failed to recover the etcd lease of %v at the attempt %d: %v", k.endpoints, attempt, err)
+		if attempt%failingAttempts == 0 {
+			publishStateEvent(StateEvent{Type: StateLeaseFailing, Endpoints: k.endpoints, Attempts: attempt, Err: err})
+		}
+		if !waitRetry(k.ctx, k.done, retryInterval(attempt)) {
+			return false
+		}
+	}
+}
+
+func (k *leaseKeeper) regrant() error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if err := k.grant(); err != nil {
+		return err
+	}
+	for key, value := range k.kvs {
+		if _, err := k.raw.Put(k.ctx, key, value, clientv3.WithLease(k.lease)); err != nil {
+			return perrors.WithMessagef(err, "put k/v with lease (key %s)", key)
+		}
+	}
+	return nil
+}
+
+// clientClosed returns true if the client of the @ctx and the @done is closed
+func clientClosed(ctx context.Context, done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// waitRetry waits for @d before a retry, it returns false if the client of the @ctx and the @done is closed in the
+// meantime
+func waitRetry(ctx context.Context, done <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return false
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// retryInterval returns the interval after the failed @attempt, which doubles from minRetryInterval to
+// maxRetryInterval
+func retryInterval(attempt int) time.Duration {
+	interval := minRetryInterval
+	for i := 1; i < attempt && interval < maxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return interval
+}
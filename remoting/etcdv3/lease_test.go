@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcdv3
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeRawClient is an etcd server in memory, whose leases and watches are broken by the tests
+type fakeRawClient struct {
+	rawClient
+
+	lock sync.Mutex
+	rev  int64
+	kvs  map[string]*mvccpb.KeyValue
+	// leases are the leases of the keys
+	leases     map[string]clientv3.LeaseID
+	lastLease  clientv3.LeaseID
+	keepAlives map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse
+	// failures are the number of the next calls of Grant and Get to fail
+	failures int
+	// watches receive the watches started, with the revisions they start from
+	watches chan fakeWatch
+}
+
+type fakeWatch struct {
+	rev int64
+	ch  chan clientv3.WatchResponse
+}
+
+func newFakeRawClient() *fakeRawClient {
+	return &fakeRawClient{
+		kvs:        make(map[string]*mvccpb.KeyValue),
+		leases:     make(map[string]clientv3.LeaseID),
+		keepAlives: make(map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse),
+		watches:    make(chan fakeWatch, 8),
+	}
+}
+
+func (c *fakeRawClient) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return nil, context.DeadlineExceeded
+	}
+	c.lastLease++
+	return &clientv3.LeaseGrantResponse{ID: c.lastLease, TTL: ttl}, nil
+}
+
+func (c *fakeRawClient) KeepAlive(_ context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ch := make(chan *clientv3.LeaseKeepAliveResponse)
+	c.keepAlives[id] = ch
+	return ch, nil
+}
+
+func (c *fakeRawClient) Put(_ context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	op := clientv3.OpPut(key, val, opts...)
+	c.put(key, val)
+	// the op has no getter of the lease
+	c.leases[key] = clientv3.LeaseID(reflect.ValueOf(op).FieldByName("leaseID").Int())
+	return &clientv3.PutResponse{}, nil
+}
+
+func (c *fakeRawClient) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return nil, context.DeadlineExceeded
+	}
+	prefix := clientv3.OpGet(key, opts...).RangeBytes() != nil
+	resp := &etcdserverpb.RangeResponse{Header: &etcdserverpb.ResponseHeader{Revision: c.rev}}
+	for k, kv := range c.kvs {
+		if k == key || prefix && strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, kv)
+		}
+	}
+	return (*clientv3.GetResponse)(resp), nil
+}
+
+func (c *fakeRawClient) Watch(_ context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	ch := make(chan clientv3.WatchResponse, 8)
+	c.watches <- fakeWatch{rev: clientv3.OpGet(key, opts...).Rev(), ch: ch}
+	return ch
+}
+
+// put puts the key with the lock held, and returns the event of it
+func (c *fakeRawClient) put(key, val string) *clientv3.Event {
+	c.rev++
+	kv := &mvccpb.KeyValue{Key: []byte(key), Value: []byte(val), CreateRevision: c.rev, ModRevision: c.rev}
+	if prev, ok := c.kvs[key]; ok {
+		kv.CreateRevision = prev.CreateRevision
+	}
+	c.kvs[key] = kv
+	return &clientv3.Event{Type: mvccpb.PUT, Kv: kv}
+}
+
+func (c *fakeRawClient) delete(key string) *clientv3.Event {
+	c.rev++
+	delete(c.kvs, key)
+	return &clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: c.rev}}
+}
+
+// expireLease closes the keepalive channel of the lease and deletes its keys, like the lease expires during a
+// leader change of etcd
+func (c *fakeRawClient) expireLease(id clientv3.LeaseID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, lease := range c.leases {
+		if lease == id {
+			c.delete(key)
+			delete(c.leases, key)
+		}
+	}
+	close(c.keepAlives[id])
+}
+
+func (c *fakeRawClient) leaseOf(key string) clientv3.LeaseID {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.kvs[key]; !ok {
+		return clientv3.NoLease
+	}
+	return c.leases[key]
+}
+
+func (c *fakeRawClient) setFailures(failures int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.failures = failures
+}
+
+// listenStateEvents returns the state events of the @endpoints
+func listenStateEvents(endpoints []string) chan StateEvent {
+	events := make(chan StateEvent, 16)
+	AddStateListener(func(event StateEvent) {
+		if strings.Join(event.Endpoints, ",") == strings.Join(endpoints, ",") {
+			events <- event
+		}
+	})
+	return events
+}
+
+func assertStateEvent(t *testing.T, events chan StateEvent, expected StateEventType, attempts int) {
+	select {
+	case event := <-events:
+		assert.Equal(t, expected, event.Type)
+		assert.Equal(t, attempts, event.Attempts)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no %s event", expected)
+	}
+}
+
+func TestLeaseKeeperRecover(t *testing.T) {
+	endpoints := []string{"127.0.0.1:12379"}
+	events := listenStateEvents(endpoints)
+	raw := newFakeRawClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	keeper := newLeaseKeeper(raw, ctx, nil, endpoints)
+
+	assert.NoError(t, keeper.put("/dubbo/a", "1"))
+	assert.NoError(t, keeper.put("/dubbo/b", "2"))
+	assert.Equal(t, clientv3.LeaseID(1), raw.leaseOf("/dubbo/a"))
+	assert.Equal(t, clientv3.LeaseID(1), raw.leaseOf("/dubbo/b"))
+
+	// the keys are put again by a new lease once the lease expires
+	raw.setFailures(1)
+	raw.expireLease(1)
+	assertStateEvent(t, events, StateLeaseRecovered, 1)
+	assert.Equal(t, clientv3.LeaseID(2), raw.leaseOf("/dubbo/a"))
+	assert.Equal(t, clientv3.LeaseID(2), raw.leaseOf("/dubbo/b"))
+
+	// the persistent failure is published before the recovery, and the deleted keys are not put again
+	keeper.remove("/dubbo/a")
+	raw.setFailures(failingAttempts)
+	raw.expireLease(2)
+	assertStateEvent(t, events, StateLeaseFailing, failingAttempts)
+	assertStateEvent(t, events, StateLeaseRecovered, failingAttempts)
+	assert.Equal(t, clientv3.NoLease, raw.leaseOf("/dubbo/a"))
+	assert.Equal(t, clientv3.LeaseID(3), raw.leaseOf("/dubbo/b"))
+
+	// the lease is not recovered once the client is closed
+	cancel()
+	raw.expireLease(3)
+	time.Sleep(2 * minRetryInterval)
+	assert.Equal(t, clientv3.NoLease, raw.leaseOf("/dubbo/b"))
+	assert.Empty(t, events)
+}
+
+func TestRetryInterval(t *testing.T) {
+	assert.Equal(t, minRetryInterval, retryInterval(1))
+	assert.Equal(t, 4*minRetryInterval, retryInterval(3))
+	assert.Equal(t, maxRetryInterval, retryInterval(100))
+}
@@ -18,6 +18,7 @@
 package etcdv3
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -26,8 +27,6 @@ import (
 	gxetcd "github.com/dubbogo/gost/database/kv/etcd/v3"
 	"github.com/dubbogo/gost/log/logger"
 
-	perrors "github.com/pkg/errors"
-
 	"go.etcd.io/etcd/api/v3/mvccpb"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -57,44 +56,19 @@ func NewEventListener(client *gxetcd.Client) *EventListener {
 // and return false when deep layer connection lose
 func (l *EventListener) ListenServiceNodeEvent(key string, listener ...remoting.DataListener) bool {
 	defer l.wg.Done()
-	for {
-		wc, err := l.client.Watch(key)
-		if err != nil {
-			logger.Warnf("WatchExist{key:%s} = error{%v}", key, err)
-			return false
-		}
-
-		select {
-
-		// client stopped
-		case <-l.client.Done():
-			logger.Warnf("etcd client stopped")
-			return false
-
-		// client ctx stop
-		case <-l.client.GetCtx().Done():
-			logger.Warnf("etcd client ctx cancel")
-			return false
-
-		// handle etcd events
-		case e, ok := <-wc:
-			if !ok {
-				logger.Warnf("etcd watch-chan closed")
-				return false
-			}
-
-			if e.Err() != nil {
-				logger.Errorf("etcd watch ERR {err: %s}", e.Err())
-				continue
-			}
-			for _, event := range e.Events {
-				if l.handleEvents(event, listener...) {
-					// if event is delete
-					return true
-				}
-			}
-		}
+	w, err := newKeyWatch(l.client, key, false, func(event *clientv3.Event) bool {
+		return l.handleEvents(event, listener...)
+	})
+	if err != nil {
+		logger.Warnf("WatchExist{key:%s} = error{%v}", key, err)
+		return false
+	}
+	// the key is known before watching, so that its deletion missed is handled once the watch is resynced
+	if w.kvs, w.rev, err = w.list(); err != nil {
+		logger.Warnf("WatchExist{key:%s} = error{%v}", key, err)
+		return false
 	}
+	return w.run()
 }
 
 // return true means the event type is DELETE
@@ -136,41 +110,29 @@ func (l *EventListener) handleEvents(event *clientv3.Event, listeners ...remotin
 // ListenServiceNodeEventWithPrefix listens on a set of key with spec prefix
 func (l *EventListener) ListenServiceNodeEventWithPrefix(prefix string, listener ...remoting.DataListener) {
 	defer l.wg.Done()
-	for {
-		wc, err := l.client.WatchWithPrefix(prefix)
-		if err != nil {
-			logger.Warnf("listenDirEvent(key{%s}) = error{%v}", prefix, err)
-		}
-
-		select {
-
-		// client stopped
-		case <-l.client.Done():
-			logger.Warnf("etcd client stopped")
-			return
-
-		// client ctx stop
-		case <-l.client.GetCtx().Done():
-			logger.Warnf("etcd client ctx cancel")
-			return
-
-		// etcd event stream
-		case e, ok := <-wc:
-
-			if !ok {
-				logger.Warnf("etcd watch-chan closed")
-				return
-			}
+	if w := l.newPrefixWatch(prefix, listener...); w != nil {
+		w.run()
+	}
+}
 
-			if e.Err() != nil {
-				logger.Errorf("etcd watch ERR {err: %s}", e.Err())
-				continue
-			}
-			for _, event := range e.Events {
-				l.handleEvents(event, listener...)
-			}
-		}
+// newPrefixWatch returns the watch of the keys with the @prefix, the keys are listed and notified to the @listener
+// as created before watching
+func (l *EventListener) newPrefixWatch(prefix string, listener ...remoting.DataListener) *keyWatch {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	w, err := newKeyWatch(l.client, prefix, true, func(event *clientv3.Event) bool {
+		l.handleEvents(event, listener...)
+		return false
+	})
+	if err != nil {
+		logger.Warnf("listenDirEvent(key{%s}) = error{%v}", prefix, err)
+		return nil
 	}
+	if _, err = w.resync(); err != nil {
+		logger.Warnf("Get new node path {%v} 's content error,message is  {%v}", prefix, err)
+	}
+	return w
 }
 
 func timeSecondDuration(sec int) time.Duration {
@@ -193,28 +155,17 @@ func (l *EventListener) ListenServiceEvent(key string, listener remoting.DataLis
 	l.keyMap[key] = struct{}{}
 	l.keyMapLock.Unlock()
 
-	keyList, valueList, err := l.client.GetChildren(key)
-	if err != nil {
-		logger.Warnf("Get new node path {%v} 's content error,message is  {%v}", key, perrors.WithMessage(err, "get children"))
-	}
-
-	logger.Debugf("get key children list %s, keys %v values %v", key, keyList, valueList)
-
-	for i, k := range keyList {
-		logger.Infof("got children list key -> %s", k)
-		listener.DataChange(remoting.Event{
-			Path:    k,
-			Action:  remoting.EventTypeAdd,
-			Content: valueList[i],
-		})
-	}
-
 	logger.Debugf("[ETCD Listener] listen dubbo provider key{%s} event and wait to get all provider etcdv3 nodes", key)
-	l.wg.Add(1)
-	go func(key string, listener remoting.DataListener) {
-		l.ListenServiceNodeEventWithPrefix(key, listener)
-		logger.Warnf("listenDirEvent(key{%s}) goroutine exit now", key)
-	}(key, listener)
+	// the keys are notified before returning
+	w := l.newPrefixWatch(key, listener)
+	if w != nil {
+		l.wg.Add(1)
+		go func(key string) {
+			defer l.wg.Done()
+			w.run()
+			logger.Warnf("listenDirEvent(key{%s}) goroutine exit now", key)
+		}(key)
+	}
 
 	logger.Infof("[ETCD Listener] listen dubbo service key{%s}", key)
 	l.wg.Add(1)
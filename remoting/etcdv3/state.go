@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcdv3
+
+import (
+	"fmt"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/gost/log/logger"
+)
+
+// StateEventType is the type of StateEvent
+type StateEventType int
+
+const (
+	// StateLeaseRecovered means the lost lease of the temporary keys is granted again and the keys are put again
+	StateLeaseRecovered StateEventType = iota
+	// StateLeaseFailing means the lost lease of the temporary keys is not granted again after several attempts, so
+	// the keys are missing in etcd until the lease is recovered
+	StateLeaseFailing
+	// StateWatchResynced means a compacted or broken watch is resumed after the keys are listed again
+	StateWatchResynced
+	// StateWatchFailing means the keys of a compacted or broken watch are not listed again after several attempts,
+	// so the changes of the keys are not notified until the watch is resynced
+	StateWatchFailing
+)
+
+var stateEventTypeStrings = [...]string{
+	"lease-recovered",
+	"lease-failing",
+	"watch-resynced",
+	"watch-failing",
+}
+
+// nolint
+func (t StateEventType) String() string {
+	return stateEventTypeStrings[t]
+}
+
+// StateEvent is an event of the state of the leases and the watches of an etcd client
+type StateEvent struct {
+	Type      StateEventType
+	Endpoints []string
+	// Key is the key or the prefix of the watch, it is empty for the lease events
+	Key string
+	// Attempts are the failed attempts to recover so far
+	Attempts int
+	// Err is the last error of the attempts
+	Err error
+}
+
+// nolint
+func (e StateEvent) String() string {
+	return fmt.Sprintf("StateEvent{Type{%s}, Endpoints{%v}, Key{%s}, Attempts{%d}, Err{%v}}",
+		e.Type, e.Endpoints, e.Key, e.Attempts, e.Err)
+}
+
+// StateListener listens the StateEvent
+type StateListener func(event StateEvent)
+
+var (
+	stateListenersLock sync.RWMutex
+	stateListeners     []StateListener
+)
+
+// AddStateListener adds the @listener of the state events of the etcd clients. The events are delivered by the
+// goroutines recovering the leases and the watches, so the listener should not block.
+func AddStateListener(listener StateListener) {
+	stateListenersLock.Lock()
+	defer stateListenersLock.Unlock()
+	stateListeners = append(stateListeners, listener)
+}
+
+func publishStateEvent(event StateEvent) {
+	stateListenersLock.RLock()
+	listeners := stateListeners
+	stateListenersLock.RUnlock()
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("The etcd state listener panics at %s: %v", event, e)
+				}
+			}()
+			listener(event)
+		}()
+	}
+}
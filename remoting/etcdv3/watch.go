@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcdv3
+
+import (
+	"context"
+)
+
+import (
+	gxetcd "github.com/dubbogo/gost/database/kv/etcd/v3"
+	"github.com/dubbogo/gost/log/logger"
+
+	perrors "github.com/pkg/errors"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	metricsRegistry "dubbo.apache.org/dubbo-go/v3/metrics/registry"
+)
+
+// keyWatch watches a key or the keys with a prefix from the revision its known keys are synced to. Once the watch is
+// compacted or broken while the client is alive, the keys are listed again, the changes missed are handled as the
+// events, and the watch is resumed from the revision of the list, so no change is lost.
+type keyWatch struct {
+	raw       rawClient
+	ctx       context.Context
+	done      <-chan struct{}
+	endpoints []string
+	key       string
+	prefix    bool
+	// handle handles an event, the watch stops if it returns true
+	handle func(event *clientv3.Event) bool
+
+	// kvs are the known keys, which are synced to the revision rev
+	kvs map[string]*mvccpb.KeyValue
+	rev int64
+}
+
+func newKeyWatch(client *gxetcd.Client, key string, prefix bool, handle func(event *clientv3.Event) bool) (*keyWatch, error) {
+	raw := client.GetRawClient()
+	if raw == nil {
+		return nil, gxetcd.ErrNilETCDV3Client
+	}
+	return &keyWatch{
+		raw:       raw,
+		ctx:       client.GetCtx(),
+		done:      client.Done(),
+		endpoints: client.GetEndPoints(),
+		key:       key,
+		prefix:    prefix,
+		handle:    handle,
+		kvs:       make(map[string]*mvccpb.KeyValue),
+	}, nil
+}
+
+// run watches the keys until the watch stops or the client is closed, it returns true if the watch stops
+func (w *keyWatch) run() bool {
+	for {
+		stopped, err := w.watch()
+		if stopped {
+			return true
+		}
+		if clientClosed(w.ctx, w.done) {
+			return false
+		}
+		logger.Warnf("The etcd watch of %s is interrupted at the revision %d: %v, list the keys again to resume it",
+			w.key, w.rev, err)
+		if stopped, ok := w.recoverWatch(); stopped || !ok {
+			return stopped
+		}
+	}
+}
+
+// watch handles the events from the revision after rev until the watch is interrupted, it returns true if the watch
+// stops, or the error interrupting it
+func (w *keyWatch) watch() (bool, error) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+	var opts []clientv3.OpOption
+	if w.prefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	if w.rev > 0 {
+		opts = append(opts, clientv3.WithRev(w.rev+1))
+	}
+	wc := w.raw.Watch(ctx, w.key, opts...)
+	for {
+		select {
+		case <-w.done:
+			return false, nil
+		case <-w.ctx.Done():
+			return false, nil
+		case resp, ok := <-wc:
+			if !ok {
+				return false, perrors.New("watch channel closed")
+			}
+			if err := resp.Err(); err != nil {
+				// the watch channel is closed after the error like ErrCompacted
+				return false, err
+			}
+			for _, event := range resp.Events {
+				w.update(event)
+				if w.handle(event) {
+					return true, nil
+				}
+			}
+			if resp.Header.Revision > w.rev {
+				w.rev = resp.Header.Revision
+			}
+		}
+	}
+}
+
+// recoverWatch resyncs the keys until it succeeds, it returns true if the watch stops by the changes missed, and
+// false if the client is closed before it succeeds
+func (w *keyWatch) recoverWatch() (stopped bool, ok bool) {
+	for attempt := 1; ; attempt++ {
+		stopped, err := w.resync()
+		metrics.Publish(metricsRegistry.NewWatchResyncEvent(err == nil))
+		if err == nil {
+			logger.Infof("The etcd watch of %s is resynced to the revision %d", w.key, w.rev)
+			publishStateEvent(StateEvent{Type: StateWatchResynced, Endpoints: w.endpoints, Key: w.key, Attempts: attempt - 1})
+			return stopped, true
+		}
+		logger.Warnf("Failed to resync the etcd watch of %s at the attempt %d: %v", w.key, attempt, err)
+		if attempt%failingAttempts == 0 {
+			publishStateEvent(StateEvent{Type: StateWatchFailing, Endpoints: w.endpoints, Key: w.key, Attempts: attempt, Err: err})
+		}
+		if !waitRetry(w.ctx, w.done, retryInterval(attempt)) {
+			return false, false
+		}
+	}
+}
+
+// resync lists the keys, and handles the differences from the known keys as the events, it returns true if the watch
+// stops
+func (w *keyWatch) resync() (bool, error) {
+	kvs, rev, err := w.list()
+	if err != nil {
+		return false, err
+	}
+	var events []*clientv3.Event
+	for key, kv := range kvs {
+		known, ok := w.kvs[key]
+		switch {
+		case !ok:
+			// the keys unknown are handled as created even if they are updated since then
+			created := *kv
+			created.CreateRevision = created.ModRevision
+			events = append(events, &clientv3.Event{Type: mvccpb.PUT, Kv: &created})
+		case known.ModRevision != kv.ModRevision:
+			events = append(events, &clientv3.Event{Type: mvccpb.PUT, Kv: kv, PrevKv: known})
+		}
+	}
+	for key, known := range w.kvs {
+		if _, ok := kvs[key]; !ok {
+			events = append(events, &clientv3.Event{
+				Type:   mvccpb.DELETE,
+				Kv:     &mvccpb.KeyValue{Key: known.Key, ModRevision: rev},
+				PrevKv: known,
+			})
+		}
+	}
+	w.kvs, w.rev = kvs, rev
+	for _, event := range events {
+		if w.handle(event) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// list returns the keys and the revision they are listed at
+func (w *keyWatch) list() (map[string]*mvccpb.KeyValue, int64, error) {
+	var opts []clientv3.OpOption
+	if w.prefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	resp, err := w.raw.Get(w.ctx, w.key, opts...)
+	if err != nil {
+		return nil, 0, perrors.WithMessagef(err, "list the keys of %s", w.key)
+	}
+	kvs := make(map[string]*mvccpb.KeyValue, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = kv
+	}
+	return kvs, resp.Header.Revision, nil
+}
+
+// update updates the known keys by the @event
+func (w *keyWatch) update(event *clientv3.Event) {
+	switch event.Type {
+	case mvccpb.PUT:
+		w.kvs[string(event.Kv.Key)] = event.Kv
+	case mvccpb.DELETE:
+		delete(w.kvs, string(event.Kv.Key))
+	}
+}
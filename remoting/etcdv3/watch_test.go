@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcdv3
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type mockDataListener struct {
+	lock   sync.Mutex
+	events []remoting.Event
+}
+
+func (l *mockDataListener) DataChange(event remoting.Event) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.events = append(l.events, event)
+	return true
+}
+
+func (l *mockDataListener) takeEvents() []remoting.Event {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	events := l.events
+	l.events = nil
+	return events
+}
+
+func nextWatch(t *testing.T, raw *fakeRawClient) fakeWatch {
+	select {
+	case watch := <-raw.watches:
+		return watch
+	case <-time.After(5 * time.Second):
+		t.Fatal("no watch started")
+		return fakeWatch{}
+	}
+}
+
+func (c *fakeRawClient) watchResponse(events ...*clientv3.Event) clientv3.WatchResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return clientv3.WatchResponse{Header: etcdserverpb.ResponseHeader{Revision: c.rev}, Events: events}
+}
+
+func TestKeyWatchResyncAfterCompaction(t *testing.T) {
+	endpoints := []string{"127.0.0.1:22379"}
+	events := listenStateEvents(endpoints)
+	raw := newFakeRawClient()
+	raw.put("/dubbo/svc/providers/a", "1")
+	raw.put("/dubbo/svc/providers/b", "2")
+	ctx, cancel := context.WithCancel(context.Background())
+	dataListener := &mockDataListener{}
+	l := NewEventListener(nil)
+	w := &keyWatch{
+		raw:       raw,
+		ctx:       ctx,
+		endpoints: endpoints,
+		key:       "/dubbo/svc/providers/",
+		prefix:    true,
+		handle: func(event *clientv3.Event) bool {
+			l.handleEvents(event, dataListener)
+			return false
+		},
+		kvs: make(map[string]*mvccpb.KeyValue),
+	}
+
+	// the keys listed are notified as created
+	_, err := w.resync()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []remoting.Event{
+		{Path: "/dubbo/svc/providers/a", Action: remoting.EventTypeAdd, Content: "1"},
+		{Path: "/dubbo/svc/providers/b", Action: remoting.EventTypeAdd, Content: "2"},
+	}, dataListener.takeEvents())
+
+	stopped := make(chan bool)
+	go func() {
+		stopped <- w.run()
+	}()
+	// the watch starts after the revision listed
+	watch := nextWatch(t, raw)
+	assert.Equal(t, int64(3), watch.rev)
+	watch.ch <- raw.watchResponse(raw.put("/dubbo/svc/providers/c", "3"))
+
+	// the changes during the compaction are handled once the watch is resynced, and the resync is retried
+	raw.lock.Lock()
+	raw.put("/dubbo/svc/providers/a", "4")
+	raw.delete("/dubbo/svc/providers/b")
+	raw.put("/dubbo/svc/providers/d", "5")
+	raw.failures = 1
+	raw.lock.Unlock()
+	watch.ch <- clientv3.WatchResponse{CompactRevision: 6, Canceled: true}
+	close(watch.ch)
+	watch = nextWatch(t, raw)
+	assert.Equal(t, int64(7), watch.rev)
+	assertStateEvent(t, events, StateWatchResynced, 1)
+	assert.ElementsMatch(t, []remoting.Event{
+		{Path: "/dubbo/svc/providers/c", Action: remoting.EventTypeAdd, Content: "3"},
+		{Path: "/dubbo/svc/providers/a", Action: remoting.EventTypeUpdate, Content: "4"},
+		{Path: "/dubbo/svc/providers/d", Action: remoting.EventTypeAdd, Content: "5"},
+	}, dataListener.takeEvents())
+
+	// the watch is resumed from the revision listed if it is broken
+	close(watch.ch)
+	watch = nextWatch(t, raw)
+	assert.Equal(t, int64(7), watch.rev)
+	assertStateEvent(t, events, StateWatchResynced, 0)
+	assert.Empty(t, dataListener.takeEvents())
+
+	cancel()
+	select {
+	case s := <-stopped:
+		assert.False(t, s)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch does not stop once the client is closed")
+	}
+}
+
+func TestKeyWatchDeletedDuringCompaction(t *testing.T) {
+	raw := newFakeRawClient()
+	raw.put("/dubbo/svc/providers", "")
+	l := NewEventListener(nil)
+	w := &keyWatch{
+		raw: raw,
+		ctx: context.Background(),
+		key: "/dubbo/svc/providers",
+		handle: func(event *clientv3.Event) bool {
+			return l.handleEvents(event)
+		},
+	}
+	var err error
+	w.kvs, w.rev, err = w.list()
+	assert.NoError(t, err)
+
+	stopped := make(chan bool)
+	go func() {
+		stopped <- w.run()
+	}()
+	watch := nextWatch(t, raw)
+	raw.lock.Lock()
+	raw.delete("/dubbo/svc/providers")
+	raw.lock.Unlock()
+	watch.ch <- clientv3.WatchResponse{CompactRevision: 2, Canceled: true}
+	close(watch.ch)
+
+	// the deletion missed stops the watch
+	select {
+	case s := <-stopped:
+		assert.True(t, s)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the deletion is not handled")
+	}
+}
@@ -93,9 +93,10 @@ func (response *Response) IsHeartbeat() bool {
 }
 
 func (response *Response) Handle() {
-	pendingResponse := removePendingResponse(SequenceType(response.ID))
+	pendingResponse := RemovePendingResponse(SequenceType(response.ID))
 	if pendingResponse == nil {
-		logger.Errorf("failed to get pending response context for response package %s", *response)
+		// the request timed out, and its pending response was removed
+		logger.Warnf("failed to get pending response context for response package %s", *response)
 		return
 	}
 
@@ -172,8 +173,8 @@ func AddPendingResponse(pr *PendingResponse) {
 	pendingResponses.Store(SequenceType(pr.seq), pr)
 }
 
-// get and remove response
-func removePendingResponse(seq SequenceType) *PendingResponse {
+// RemovePendingResponse gets and removes the response, it's called when the request gives up waiting for the response
+func RemovePendingResponse(seq SequenceType) *PendingResponse {
 	if pendingResponses == nil {
 		return nil
 	}
@@ -184,6 +185,16 @@ func removePendingResponse(seq SequenceType) *PendingResponse {
 	return nil
 }
 
+// PendingResponses returns the number of the requests waiting for their responses
+func PendingResponses() int {
+	count := 0
+	pendingResponses.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
 // GetPendingResponse gets the response
 func GetPendingResponse(seq SequenceType) *PendingResponse {
 	if presp, ok := pendingResponses.Load(seq); ok {
@@ -19,6 +19,8 @@ package remoting
 
 import (
 	"errors"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -62,6 +64,9 @@ type ExchangeClient struct {
 	client         Client         // dealing with the transport
 	init           bool           // the tag for init.
 	activeNum      uatomic.Uint32 // the number of service using the exchangeClient
+
+	serviceKeysLock sync.RWMutex
+	serviceKeys     map[string]int // the services using the exchangeClient with their numbers of references
 }
 
 // NewExchangeClient returns a ExchangeClient.
@@ -70,7 +75,10 @@ func NewExchangeClient(url *common.URL, client Client, connectTimeout time.Durat
 		ConnectTimeout: connectTimeout,
 		address:        url.Location,
 		client:         client,
+		serviceKeys:    make(map[string]int),
 	}
+	exchangeClient.AddServiceKey(url.ServiceKey())
+	client.SetExchangeClient(exchangeClient)
 	if !lazyInit {
 		if err := exchangeClient.doInit(url); err != nil {
 			return nil
@@ -112,6 +120,34 @@ func (client *ExchangeClient) GetActiveNumber() uint32 {
 	return client.activeNum.Load()
 }
 
+// AddServiceKey adds the service @key using the client.
+func (client *ExchangeClient) AddServiceKey(key string) {
+	client.serviceKeysLock.Lock()
+	defer client.serviceKeysLock.Unlock()
+	client.serviceKeys[key]++
+}
+
+// RemoveServiceKey removes the service @key added by AddServiceKey.
+func (client *ExchangeClient) RemoveServiceKey(key string) {
+	client.serviceKeysLock.Lock()
+	defer client.serviceKeysLock.Unlock()
+	if client.serviceKeys[key]--; client.serviceKeys[key] <= 0 {
+		delete(client.serviceKeys, key)
+	}
+}
+
+// ServiceKeys returns the sorted keys of the services using the client.
+func (client *ExchangeClient) ServiceKeys() []string {
+	client.serviceKeysLock.RLock()
+	defer client.serviceKeysLock.RUnlock()
+	keys := make([]string, 0, len(client.serviceKeys))
+	for key := range client.serviceKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Request means two way request.
 func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *common.URL, timeout time.Duration,
 	result *protocol.RPCResult) error {
@@ -131,6 +167,8 @@ func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *comm
 	err := client.client.Request(request, timeout, rsp)
 	// request error
 	if err != nil {
+		// the response of the timed out or unsent request is never handled
+		RemovePendingResponse(SequenceType(request.ID))
 		result.Err = err
 		return err
 	}
@@ -164,6 +202,7 @@ func (client *ExchangeClient) AsyncRequest(invocation *protocol.Invocation, url
 
 	err := client.client.Request(request, timeout, rsp)
 	if err != nil {
+		RemovePendingResponse(SequenceType(request.ID))
 		result.Err = err
 		return err
 	}
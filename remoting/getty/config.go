@@ -18,6 +18,7 @@
 package getty
 
 import (
+	"crypto/tls"
 	"time"
 )
 
@@ -28,6 +29,8 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
 )
 
@@ -35,6 +38,16 @@ const (
 	TCPReadWriteTimeoutMinValue = time.Second * 1
 )
 
+// tlsProfileBuilder builds the *tls.Config of the tls profile of the protocol
+type tlsProfileBuilder struct {
+	profile string
+	build   func(profile string) (*tls.Config, error)
+}
+
+func (b *tlsProfileBuilder) BuildTlsConfig() (*tls.Config, error) {
+	return b.build(b.profile)
+}
+
 type (
 	// GettySessionParam is session configuration for getty
 	GettySessionParam struct {
@@ -78,6 +91,13 @@ type (
 		QueueLen    int `default:"0" yaml:"queue-len" json:"queue-len,omitempty"`
 		QueueNumber int `default:"0" yaml:"queue-number" json:"queue-number,omitempty"`
 
+		// websocket, the server also listens for the websocket upgrades on the port if it is set
+		WebsocketPort string `yaml:"websocket-port" json:"websocket-port,omitempty"`
+		WebsocketPath string `default:"/dubbo" yaml:"websocket-path" json:"websocket-path,omitempty"`
+
+		// inbound limit, the requests over the limits are throttled before their bodies are decoded
+		InboundLimit *config.InboundLimitConfig `yaml:"inbound-limit" json:"inbound-limit,omitempty"`
+
 		// session tcp parameters
 		GettySessionParam GettySessionParam `required:"true" yaml:"getty-session-param" json:"getty-session-param,omitempty"`
 	}
@@ -109,6 +129,9 @@ type (
 		QueueLen    int `default:"0" yaml:"queue-len" json:"queue-len,omitempty"`
 		QueueNumber int `default:"0" yaml:"queue-number" json:"queue-number,omitempty"`
 
+		// websocket, the client connects the providers accepting websocket by it if it is true
+		Websocket bool `default:"false" yaml:"websocket" json:"websocket,omitempty"`
+
 		// session tcp parameters
 		GettySessionParam GettySessionParam `required:"true" yaml:"getty-session-param" json:"getty-session-param,omitempty"`
 	}
@@ -150,6 +173,7 @@ func GetDefaultServerConfig() *ServerConfig {
 		GrPoolSize:     120,
 		QueueNumber:    6,
 		QueueLen:       64,
+		WebsocketPath:  constant.DefaultWebsocketPath,
 		GettySessionParam: GettySessionParam{
 			CompressEncoding: false,
 			TcpNoDelay:       true,
@@ -172,27 +196,80 @@ func GetDefaultServerConfig() *ServerConfig {
 func (c *GettySessionParam) CheckValidity() error {
 	var err error
 
-	if c.keepAlivePeriod, err = time.ParseDuration(c.KeepAlivePeriod); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(KeepAlivePeriod{%#v})", c.KeepAlivePeriod)
+	if c.keepAlivePeriod, err = common.ParseDuration(c.KeepAlivePeriod); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(KeepAlivePeriod{%#v})", c.KeepAlivePeriod)
 	}
 
 	if c.tcpReadTimeout, err = parseTcpTimeoutDuration(c.TcpReadTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(TcpReadTimeout{%#v})", c.TcpReadTimeout)
+		return perrors.WithMessagef(err, "common.ParseDuration(TcpReadTimeout{%#v})", c.TcpReadTimeout)
 	}
 
 	if c.tcpWriteTimeout, err = parseTcpTimeoutDuration(c.TcpWriteTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(TcpWriteTimeout{%#v})", c.TcpWriteTimeout)
+		return perrors.WithMessagef(err, "common.ParseDuration(TcpWriteTimeout{%#v})", c.TcpWriteTimeout)
 	}
 
-	if c.waitTimeout, err = time.ParseDuration(c.WaitTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(WaitTimeout{%#v})", c.WaitTimeout)
+	if c.waitTimeout, err = common.ParseDuration(c.WaitTimeout); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(WaitTimeout{%#v})", c.WaitTimeout)
 	}
 
 	return nil
 }
 
+// applyTransport overrides the server config by the fields set in @transport, see config.TransportConfig
+func (c *ServerConfig) applyTransport(transport *config.TransportConfig) {
+	if transport == nil {
+		return
+	}
+	c.GettySessionParam.applyTransport(transport)
+	if transport.WebsocketPort != "" {
+		c.WebsocketPort = transport.WebsocketPort
+	}
+	if transport.WebsocketPath != "" {
+		c.WebsocketPath = transport.WebsocketPath
+	}
+}
+
+// applyTransport overrides the client config by the fields set in @transport, see config.TransportConfig
+func (c *ClientConfig) applyTransport(transport *config.TransportConfig) {
+	if transport == nil {
+		return
+	}
+	c.GettySessionParam.applyTransport(transport)
+	if transport.Websocket {
+		c.Websocket = true
+	}
+}
+
+// applyTransport overrides the session params by the fields set in @transport, see config.TransportConfig
+func (c *GettySessionParam) applyTransport(transport *config.TransportConfig) {
+	if transport == nil {
+		return
+	}
+	if transport.TcpNoDelay != nil {
+		c.TcpNoDelay = *transport.TcpNoDelay
+	}
+	if transport.TcpKeepAlive != nil {
+		c.TcpKeepAlive = *transport.TcpKeepAlive
+	}
+	if transport.KeepAlivePeriod != "" {
+		c.KeepAlivePeriod = transport.KeepAlivePeriod
+	}
+	if transport.TcpRBufSize != 0 {
+		c.TcpRBufSize = transport.TcpRBufSize
+	}
+	if transport.TcpWBufSize != 0 {
+		c.TcpWBufSize = transport.TcpWBufSize
+	}
+	if transport.TcpReadTimeout != "" {
+		c.TcpReadTimeout = transport.TcpReadTimeout
+	}
+	if transport.TcpWriteTimeout != "" {
+		c.TcpWriteTimeout = transport.TcpWriteTimeout
+	}
+}
+
 func parseTcpTimeoutDuration(timeStr string) (time.Duration, error) {
-	result, err := time.ParseDuration(timeStr)
+	result, err := common.ParseDuration(timeStr)
 	if err != nil {
 		return 0, err
 	}
@@ -208,8 +285,8 @@ func (c *ClientConfig) CheckValidity() error {
 
 	c.ReconnectInterval = c.ReconnectInterval * 1e6
 
-	if c.heartbeatPeriod, err = time.ParseDuration(c.HeartbeatPeriod); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(HeartbeatPeroid{%#v})", c.HeartbeatPeriod)
+	if c.heartbeatPeriod, err = common.ParseDuration(c.HeartbeatPeriod); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(HeartbeatPeroid{%#v})", c.HeartbeatPeriod)
 	}
 
 	if c.heartbeatPeriod >= time.Duration(config.MaxWheelTimeSpan) {
@@ -219,12 +296,12 @@ func (c *ClientConfig) CheckValidity() error {
 
 	if len(c.HeartbeatTimeout) == 0 {
 		c.heartbeatTimeout = 60 * time.Second
-	} else if c.heartbeatTimeout, err = time.ParseDuration(c.HeartbeatTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(HeartbeatTimeout{%#v})", c.HeartbeatTimeout)
+	} else if c.heartbeatTimeout, err = common.ParseDuration(c.HeartbeatTimeout); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(HeartbeatTimeout{%#v})", c.HeartbeatTimeout)
 	}
 
-	if c.sessionTimeout, err = time.ParseDuration(c.SessionTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(SessionTimeout{%#v})", c.SessionTimeout)
+	if c.sessionTimeout, err = common.ParseDuration(c.SessionTimeout); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(SessionTimeout{%#v})", c.SessionTimeout)
 	}
 
 	return perrors.WithStack(c.GettySessionParam.CheckValidity())
@@ -236,8 +313,8 @@ func (c *ServerConfig) CheckValidity() error {
 
 	if len(c.HeartbeatPeriod) == 0 {
 		c.heartbeatPeriod = 60 * time.Second
-	} else if c.heartbeatPeriod, err = time.ParseDuration(c.HeartbeatPeriod); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(HeartbeatPeroid{%#v})", c.HeartbeatPeriod)
+	} else if c.heartbeatPeriod, err = common.ParseDuration(c.HeartbeatPeriod); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(HeartbeatPeroid{%#v})", c.HeartbeatPeriod)
 	}
 
 	if c.heartbeatPeriod >= time.Duration(config.MaxWheelTimeSpan) {
@@ -247,12 +324,12 @@ func (c *ServerConfig) CheckValidity() error {
 
 	if len(c.HeartbeatTimeout) == 0 {
 		c.heartbeatTimeout = 60 * time.Second
-	} else if c.heartbeatTimeout, err = time.ParseDuration(c.HeartbeatTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(HeartbeatTimeout{%#v})", c.HeartbeatTimeout)
+	} else if c.heartbeatTimeout, err = common.ParseDuration(c.HeartbeatTimeout); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(HeartbeatTimeout{%#v})", c.HeartbeatTimeout)
 	}
 
-	if c.sessionTimeout, err = time.ParseDuration(c.SessionTimeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(SessionTimeout{%#v})", c.SessionTimeout)
+	if c.sessionTimeout, err = common.ParseDuration(c.SessionTimeout); err != nil {
+		return perrors.WithMessagef(err, "common.ParseDuration(SessionTimeout{%#v})", c.SessionTimeout)
 	}
 
 	if c.sessionTimeout >= time.Duration(config.MaxWheelTimeSpan) {
@@ -19,6 +19,7 @@ package getty
 
 import (
 	"math/rand"
+	"net"
 	"sync"
 	"time"
 )
@@ -39,6 +40,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
@@ -51,6 +53,8 @@ var (
 	clientConf *ClientConfig
 
 	clientGrPool gxsync.GenericTaskPool
+	// clientGrPoolSize is the size clientGrPool is created with
+	clientGrPoolSize int
 )
 
 // it is init client for single protocol.
@@ -65,18 +69,16 @@ func initClient(protocol string) {
 	if config.GetApplicationConfig() == nil {
 		return
 	}
-	if config.GetRootConfig().Protocols == nil {
-		return
-	}
-
 	protocolConf := config.GetRootConfig().Protocols[protocol]
 	if protocolConf == nil {
 		logger.Info("use default getty client config")
-		return
 	} else {
 		//client tls config
 		tlsConfig := config.GetRootConfig().TLSConfig
-		if tlsConfig != nil {
+		if protocolConf.TLSProfile != "" {
+			clientConf.SSLEnabled = true
+			clientConf.TLSBuilder = &tlsProfileBuilder{profile: protocolConf.TLSProfile, build: config.GetClientTLSProfile}
+		} else if tlsConfig != nil {
 			clientConf.SSLEnabled = true
 			clientConf.TLSBuilder = &getty.ClientTlsConfigBuilder{
 				ClientKeyCertChainPath:        tlsConfig.TLSCertFile,
@@ -85,20 +87,21 @@ func initClient(protocol string) {
 			}
 		}
 		//getty params
-		gettyClientConfig := protocolConf.Params
-		if gettyClientConfig == nil {
+		if gettyClientConfig := protocolConf.Params; gettyClientConfig != nil {
+			gettyClientConfigBytes, err := yaml.Marshal(gettyClientConfig)
+			if err != nil {
+				panic(err)
+			}
+			err = yaml.Unmarshal(gettyClientConfigBytes, clientConf)
+			if err != nil {
+				panic(err)
+			}
+		} else {
 			logger.Debugf("gettyClientConfig is nil")
-			return
-		}
-		gettyClientConfigBytes, err := yaml.Marshal(gettyClientConfig)
-		if err != nil {
-			panic(err)
-		}
-		err = yaml.Unmarshal(gettyClientConfigBytes, clientConf)
-		if err != nil {
-			panic(err)
 		}
 	}
+	// the transport of the consumer applies even if the protocol is not configured
+	clientConf.applyTransport(config.GetClientTransport(protocolConf))
 	if err := clientConf.CheckValidity(); err != nil {
 		logger.Warnf("[CheckValidity] error: %v", err)
 		return
@@ -119,8 +122,18 @@ func SetClientConf(c ClientConfig) {
 	setClientGrPool()
 }
 
+// setClientGrPool creates the task pool shared by all the clients once, since the workers of a replaced pool never exit.
+// A different size configured later is ignored with a warning.
 func setClientGrPool() {
+	if clientGrPool != nil && !clientGrPool.IsClosed() {
+		if clientConf.GrPoolSize != clientGrPoolSize {
+			logger.Warnf("the getty client task pool is created with gr-pool-size %d already, the gr-pool-size %d is ignored",
+				clientGrPoolSize, clientConf.GrPoolSize)
+		}
+		return
+	}
 	clientGrPool = gxsync.NewTaskPoolSimple(clientConf.GrPoolSize)
+	clientGrPoolSize = clientConf.GrPoolSize
 }
 
 // Options : param config
@@ -141,6 +154,9 @@ type Client struct {
 	gettyClientMux     sync.RWMutex
 	gettyClientCreated atomic.Bool
 	codec              remoting.Codec
+	exchangeClient     *remoting.ExchangeClient
+	// connected is true once the client has connected the server, so the later dials are reconnections
+	connected atomic.Bool
 }
 
 // NewClient create client
@@ -162,6 +178,15 @@ func NewClient(opt Options) *Client {
 }
 
 func (c *Client) SetExchangeClient(client *remoting.ExchangeClient) {
+	c.exchangeClient = client
+}
+
+// serviceKeys returns the keys of the services using the client
+func (c *Client) serviceKeys() []string {
+	if c.exchangeClient == nil {
+		return nil
+	}
+	return c.exchangeClient.ServiceKeys()
 }
 
 // Connect init client and try to connection.
@@ -172,6 +197,13 @@ func (c *Client) Connect(url *common.URL) error {
 	// codec
 	c.codec = remoting.GetCodec(url.Protocol)
 	c.addr = url.Location
+	if c.conf.Websocket {
+		addr, err := websocketAddress(url, c.sslEnabled)
+		if err != nil {
+			return err
+		}
+		c.addr = addr
+	}
 	_, _, err := c.selectSession(c.addr)
 	if err != nil {
 		logger.Errorf("try to connect server %v failed for : %v", url.Location, err)
@@ -179,6 +211,20 @@ func (c *Client) Connect(url *common.URL) error {
 	return err
 }
 
+// websocketAddress returns the ws or wss address of the provider of @url, which must accept websocket
+func websocketAddress(url *common.URL, sslEnabled bool) (string, error) {
+	port := url.GetParam(constant.WebsocketPortKey, "")
+	if url.GetParam(constant.TransportKey, "") != constant.TransportWebsocket || port == "" {
+		return "", perrors.Errorf("the provider %s does not accept websocket", url.Location)
+	}
+	scheme := "ws"
+	if sslEnabled {
+		scheme = "wss"
+	}
+	return scheme + "://" + net.JoinHostPort(url.Ip, port) +
+		url.GetParam(constant.WebsocketPathKey, constant.DefaultWebsocketPath), nil
+}
+
 // Close close network connection
 func (c *Client) Close() {
 	c.mux.Lock()
@@ -195,10 +241,10 @@ func (c *Client) Close() {
 func (c *Client) Request(request *remoting.Request, timeout time.Duration, response *remoting.PendingResponse) error {
 	_, session, err := c.selectSession(c.addr)
 	if err != nil {
-		return perrors.WithStack(err)
+		return common.NewRPCError(common.StatusNotSent, perrors.WithStack(err))
 	}
 	if session == nil {
-		return errSessionNotExist
+		return common.NewRPCError(common.StatusNotSent, errSessionNotExist)
 	}
 	var (
 		totalLen int
@@ -209,7 +255,10 @@ func (c *Client) Request(request *remoting.Request, timeout time.Duration, respo
 			logger.Warnf("start to close the session at request because %d of %d bytes data is sent success. err:%+v", sendLen, totalLen, err)
 			go c.Close()
 		}
-		return perrors.WithStack(err)
+		if sendLen == 0 {
+			return common.NewRPCError(common.StatusNotSent, perrors.WithStack(err))
+		}
+		return common.NewRPCError(common.StatusNetwork, perrors.WithStack(err))
 	}
 
 	if !request.TwoWay || response.Callback != nil {
@@ -218,7 +267,7 @@ func (c *Client) Request(request *remoting.Request, timeout time.Duration, respo
 
 	select {
 	case <-gxtime.After(timeout):
-		return perrors.WithStack(errClientReadTimeout)
+		return common.NewRPCError(common.StatusTimeout, perrors.WithStack(errClientReadTimeout))
 	case <-response.Done:
 		err = response.Err
 	}
@@ -244,12 +293,21 @@ func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, err
 	if !c.gettyClientCreated.Load() {
 		c.gettyClientMux.Lock()
 		if c.gettyClient == nil {
+			if c.connected.Load() {
+				remoting.PublishConnectionEvent(remoting.ConnectionEvent{
+					Type:          remoting.ConnectionReconnecting,
+					Side:          constant.SideConsumer,
+					RemoteAddress: addr,
+					ServiceKeys:   c.serviceKeys(),
+				})
+			}
 			rpcClientConn, rpcErr := newGettyRPCClientConn(c, addr)
 			if rpcErr != nil {
 				c.gettyClientMux.Unlock()
 				return nil, nil, perrors.WithStack(rpcErr)
 			}
 			c.gettyClientCreated.Store(true)
+			c.connected.Store(true)
 			c.gettyClient = rpcClientConn
 		}
 		client := c.gettyClient
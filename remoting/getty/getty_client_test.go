@@ -20,6 +20,11 @@ package getty
 import (
 	"bytes"
 	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	neturl "net/url"
 	"reflect"
 	"sync"
 	"testing"
@@ -45,13 +50,24 @@ import (
 )
 
 func TestRunSuite(t *testing.T) {
+	providers, consumers := remoting.Connections(SideProvider), remoting.Connections(SideConsumer)
 	svr, url := InitTest(t)
 	client := getClient(url)
 	assert.NotNil(t, client)
 	testRequestOneWay(t, client)
 	//testClient_Call(t, client)
 	testClient_AsyncCall(t, client)
+
+	// the sessions are counted on both sides once opened, and uncounted once closed
+	assert.True(t, remoting.Connections(SideConsumer) > consumers)
+	assert.Eventually(t, func() bool {
+		return remoting.Connections(SideProvider) > providers
+	}, 3*time.Second, 10*time.Millisecond)
+	client.Close()
 	svr.Stop()
+	assert.Eventually(t, func() bool {
+		return remoting.Connections(SideProvider) == providers && remoting.Connections(SideConsumer) == consumers
+	}, 3*time.Second, 10*time.Millisecond)
 }
 
 func testRequestOneWay(t *testing.T, client *Client) {
@@ -323,7 +339,7 @@ func testClient_AsyncCall(t *testing.T, client *Client) {
 	err := client.Request(request, 3*time.Second, rsp)
 	assert.NoError(t, err)
 	assert.Equal(t, User{}, *user)
-	wg.Done()
+	wg.Wait()
 }
 
 func InitTest(t *testing.T) (*Server, *common.URL) {
@@ -494,3 +510,109 @@ func TestInitClient(t *testing.T) {
 	config.SetRootConfig(*originRootConf)
 	assert.NotNil(t, srvConf)
 }
+
+func TestInitClientTransport(t *testing.T) {
+	originRootConf := config.GetRootConfig()
+	defer config.SetRootConfig(*originRootConf)
+
+	rootConf := config.RootConfig{
+		Application: &config.ApplicationConfig{Name: "getty-test"},
+		Protocols: map[string]*config.ProtocolConfig{
+			"dubbo": {
+				Name:      "dubbo",
+				Transport: &config.TransportConfig{TcpRBufSize: 1048576, TcpWBufSize: 1048576},
+			},
+		},
+		Consumer: &config.ConsumerConfig{},
+	}
+	config.SetRootConfig(rootConf)
+	initClient("dubbo")
+	assert.Equal(t, 1048576, clientConf.GettySessionParam.TcpRBufSize)
+	assert.Equal(t, 1048576, clientConf.GettySessionParam.TcpWBufSize)
+
+	// the transport of the consumer overrides the one of the protocol
+	rootConf.Consumer.Transport = &config.TransportConfig{TcpWBufSize: 2048}
+	config.SetRootConfig(rootConf)
+	initClient("dubbo")
+	defaults := GetDefaultClientConfig()
+	assert.Equal(t, defaults.GettySessionParam.TcpRBufSize, clientConf.GettySessionParam.TcpRBufSize)
+	assert.Equal(t, 2048, clientConf.GettySessionParam.TcpWBufSize)
+}
+
+func TestClientWebsocket(t *testing.T) {
+	hessian.RegisterPOJO(&User{})
+	remoting.RegistryCodec("dubbo", &DubboTestCodec{})
+	originRootConf := config.GetRootConfig()
+	defer config.SetRootConfig(*originRootConf)
+
+	tcpPort, wsPort := freePort(t), freePort(t)
+	config.SetRootConfig(config.RootConfig{
+		Application: &config.ApplicationConfig{Name: "getty-test"},
+		Protocols: map[string]*config.ProtocolConfig{
+			"dubbo": {
+				Name:      "dubbo",
+				Transport: &config.TransportConfig{WebsocketPort: wsPort, Websocket: true},
+			},
+		},
+	})
+	url, err := common.NewURL("dubbo://127.0.0.1:" + tcpPort + "/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	server := NewServer(url, func(invocation *invocation.RPCInvocation) protocol.RPCResult {
+		return protocol.RPCResult{Rest: &User{ID: "1", Name: "username"}}
+	})
+	server.Start()
+	defer server.Stop()
+
+	// the ingress serves the web pages and forwards the websocket upgrades to the provider
+	backend, err := neturl.Parse("http://127.0.0.1:" + wsPort)
+	assert.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle(DefaultWebsocketPath, httputil.NewSingleHostReverseProxy(backend))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("index"))
+	})
+	ingress := httptest.NewServer(mux)
+	defer ingress.Close()
+	_, ingressPort, err := net.SplitHostPort(ingress.Listener.Addr().String())
+	assert.NoError(t, err)
+
+	// the provider without the websocket marker is not connected
+	client := NewClient(Options{ConnectTimeout: 3 * time.Second})
+	assert.Error(t, client.Connect(url))
+
+	providerURL, err := common.NewURL("dubbo://127.0.0.1:" + tcpPort + "/com.ikurento.user.UserProvider?" +
+		TransportKey + "=" + TransportWebsocket + "&" + WebsocketPortKey + "=" + ingressPort + "&" +
+		WebsocketPathKey + "=" + DefaultWebsocketPath)
+	assert.NoError(t, err)
+	client = NewClient(Options{ConnectTimeout: 3 * time.Second})
+	assert.NoError(t, client.Connect(providerURL))
+	defer client.Close()
+	assert.Equal(t, "ws://127.0.0.1:"+ingressPort+DefaultWebsocketPath, client.addr)
+	testGetUser(t, client)
+}
+
+func freePort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(t, err)
+	return port
+}
+
+func TestSetClientGrPool(t *testing.T) {
+	defaultConf := clientConf
+	defer func() {
+		clientConf = defaultConf
+	}()
+	clientConf = GetDefaultClientConfig()
+	setClientGrPool()
+	pool, size := clientGrPool, clientGrPoolSize
+	assert.NotNil(t, pool)
+
+	// the shared pool is kept, the different size is ignored
+	clientConf.GrPoolSize = size + 10
+	setClientGrPool()
+	assert.Equal(t, pool, clientGrPool)
+	assert.Equal(t, size, clientGrPoolSize)
+}
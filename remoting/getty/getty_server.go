@@ -36,6 +36,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
@@ -68,7 +69,11 @@ func initServer(protocol string) {
 	} else {
 		//server tls config
 		tlsConfig := config.GetRootConfig().TLSConfig
-		if tlsConfig != nil {
+		if protocolConf.TLSProfile != "" {
+			srvConf.SSLEnabled = true
+			srvConf.TLSBuilder = &tlsProfileBuilder{profile: protocolConf.TLSProfile, build: config.GetServerTLSProfile}
+			logger.Infof("Getty Server initialized the tls profile %s", protocolConf.TLSProfile)
+		} else if tlsConfig != nil {
 			srvConf.SSLEnabled = true
 			srvConf.TLSBuilder = &getty.ServerTlsConfigBuilder{
 				ServerKeyCertChainPath:        tlsConfig.TLSCertFile,
@@ -78,19 +83,21 @@ func initServer(protocol string) {
 			logger.Infof("Getty Server initialized the TLSConfig configuration")
 		}
 		//getty params
-		gettyServerConfig := protocolConf.Params
-		if gettyServerConfig == nil {
+		if gettyServerConfig := protocolConf.Params; gettyServerConfig != nil {
+			gettyServerConfigBytes, err := yaml.Marshal(gettyServerConfig)
+			if err != nil {
+				panic(err)
+			}
+			err = yaml.Unmarshal(gettyServerConfigBytes, srvConf)
+			if err != nil {
+				panic(err)
+			}
+		} else {
 			logger.Debug("gettyServerConfig is nil")
-			return
-		}
-
-		gettyServerConfigBytes, err := yaml.Marshal(gettyServerConfig)
-		if err != nil {
-			panic(err)
 		}
-		err = yaml.Unmarshal(gettyServerConfigBytes, srvConf)
-		if err != nil {
-			panic(err)
+		srvConf.applyTransport(protocolConf.Transport)
+		if protocolConf.InboundLimit != nil {
+			srvConf.InboundLimit = protocolConf.InboundLimit
 		}
 	}
 
@@ -120,10 +127,26 @@ type Server struct {
 	addr           string
 	codec          remoting.Codec
 	tcpServer      getty.Server
+	wsServer       getty.Server
 	rpcHandler     *RpcServerHandler
 	requestHandler func(*invocation.RPCInvocation) protocol.RPCResult
+	// dispatcher is nil if the requests are handled by the goroutines of getty
+	dispatcher Dispatcher
+	// panicHandler answers the panics raised by handling the requests outside the filters
+	panicHandler PanicHandler
+	// limiter is nil if the inbound limit is disabled
+	limiter *inboundLimiter
 }
 
+// Dispatcher runs @handle of the request @inv on another goroutine, e.g. a worker of the pool of its service, so that
+// the slow services don't hold the goroutines of the server. It returns the error if the request is rejected, which
+// is answered by the thread pool exhausted response.
+type Dispatcher func(inv *invocation.RPCInvocation, handle func()) error
+
+// PanicHandler returns the result answering the panic @value raised by handling the request @inv outside the
+// filters, e.g. by the codec or the invoker of the service, @stack is the stack of the panic
+type PanicHandler func(inv *invocation.RPCInvocation, value interface{}, stack []byte) protocol.RPCResult
+
 // NewServer create a new Server
 func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protocol.RPCResult) *Server {
 	// init
@@ -133,6 +156,7 @@ func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protoco
 		addr:           url.Location,
 		codec:          remoting.GetCodec(url.Protocol),
 		requestHandler: handlers,
+		limiter:        newInboundLimiter(srvConf.InboundLimit),
 	}
 
 	s.rpcHandler = NewRpcServerHandler(s.conf.SessionNumber, s.conf.sessionTimeout, s)
@@ -140,6 +164,27 @@ func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protoco
 	return s
 }
 
+// SetPanicHandler sets the handler answering the panics raised by handling the requests, it should be called before
+// Start. The panics are answered by the internal errors without the panic values if it is not set.
+func (s *Server) SetPanicHandler(panicHandler PanicHandler) {
+	s.panicHandler = panicHandler
+}
+
+// handlePanic returns the result answering the panic @value raised by handling @inv, the panic value is never
+// answered unless the panic handler does
+func (s *Server) handlePanic(inv *invocation.RPCInvocation, value interface{}, stack []byte) protocol.RPCResult {
+	if s.panicHandler != nil {
+		return s.panicHandler(inv, value, stack)
+	}
+	logger.Errorf("Handle the request %s of the service %s panics: %v\n%s", inv.MethodName(), inv.ServiceKey(), value, stack)
+	return protocol.RPCResult{Err: common.NewRPCError(common.StatusInternal, perrors.New("provider internal error"))}
+}
+
+// SetDispatcher sets the dispatcher of the requests, it should be called before Start
+func (s *Server) SetDispatcher(dispatcher Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
 func (s *Server) newSession(session getty.Session) error {
 	var (
 		ok      bool
@@ -154,7 +199,7 @@ func (s *Server) newSession(session getty.Session) error {
 	if _, ok = session.Conn().(*tls.Conn); ok {
 		session.SetName(conf.GettySessionParam.SessionName)
 		session.SetMaxMsgLen(conf.GettySessionParam.MaxMsgLen)
-		session.SetPkgHandler(NewRpcServerPackageHandler(s))
+		session.SetPkgHandler(s.newPkgHandler(session))
 		session.SetEventListener(s.rpcHandler)
 		session.SetReadTimeout(conf.GettySessionParam.tcpReadTimeout)
 		session.SetWriteTimeout(conf.GettySessionParam.tcpWriteTimeout)
@@ -193,7 +238,7 @@ func (s *Server) newSession(session getty.Session) error {
 
 	session.SetName(conf.GettySessionParam.SessionName)
 	session.SetMaxMsgLen(conf.GettySessionParam.MaxMsgLen)
-	session.SetPkgHandler(NewRpcServerPackageHandler(s))
+	session.SetPkgHandler(s.newPkgHandler(session))
 	session.SetEventListener(s.rpcHandler)
 	session.SetReadTimeout(conf.GettySessionParam.tcpReadTimeout)
 	session.SetWriteTimeout(conf.GettySessionParam.tcpWriteTimeout)
@@ -203,6 +248,16 @@ func (s *Server) newSession(session getty.Session) error {
 	return nil
 }
 
+// newPkgHandler returns the package handler of @session, which is limited by the inbound limiter if it is enabled.
+// The limit is released when the session is closed.
+func (s *Server) newPkgHandler(session getty.Session) *RpcServerPackageHandler {
+	handler := NewRpcServerPackageHandler(s)
+	if s.limiter != nil {
+		handler.limit = s.limiter.open(session)
+	}
+	return handler
+}
+
 // Start dubbo server.
 func (s *Server) Start() {
 	var (
@@ -224,9 +279,62 @@ func (s *Server) Start() {
 	tcpServer.RunEventLoop(s.newSession)
 	logger.Debugf("s bind addr{%s} ok!", s.addr)
 	s.tcpServer = tcpServer
+	if s.limiter != nil {
+		inboundLimiters.Store(s.addr, s.limiter)
+	}
+
+	if s.conf.WebsocketPort != "" {
+		s.startWebsocket()
+	}
+}
+
+// startWebsocket starts listening for the websocket upgrades on the websocket port, the packages are framed as the
+// binary messages and the heartbeats are the ping and pong frames
+func (s *Server) startWebsocket() {
+	host, _, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		logger.Errorf("Getty Server failed to start the websocket of %s: %v", s.addr, err)
+		return
+	}
+	addr := net.JoinHostPort(host, s.conf.WebsocketPort)
+	path := s.conf.WebsocketPath
+	if path == "" {
+		path = constant.DefaultWebsocketPath
+	}
+	serverOpts := []getty.ServerOption{
+		getty.WithLocalAddress(addr),
+		getty.WithWebsocketServerPath(path),
+		getty.WithServerTaskPool(gxsync.NewTaskPoolSimple(s.conf.GrPoolSize)),
+	}
+
+	var wsServer getty.Server
+	if s.conf.SSLEnabled {
+		// getty loads the certificates of the wss server from the files
+		tlsConfig := config.GetRootConfig().TLSConfig
+		if tlsConfig == nil {
+			logger.Errorf("Getty Server failed to start the wss of %s, the tls config of the certificate files is required", addr)
+			return
+		}
+		serverOpts = append(serverOpts,
+			getty.WithWebsocketServerCert(tlsConfig.TLSCertFile),
+			getty.WithWebsocketServerPrivateKey(tlsConfig.TLSKeyFile),
+			getty.WithWebsocketServerRootCert(tlsConfig.CACertFile))
+		wsServer = getty.NewWSSServer(serverOpts...)
+	} else {
+		wsServer = getty.NewWSServer(serverOpts...)
+	}
+	wsServer.RunEventLoop(s.newSession)
+	logger.Infof("Getty Server listens for the websocket upgrades on %s%s", addr, path)
+	s.wsServer = wsServer
 }
 
 // Stop dubbo server
 func (s *Server) Stop() {
+	if s.limiter != nil {
+		inboundLimiters.Delete(s.addr)
+	}
 	s.tcpServer.Close()
+	if s.wsServer != nil {
+		s.wsServer.Close()
+	}
 }
@@ -45,3 +45,34 @@ func TestInitServer(t *testing.T) {
 	config.SetRootConfig(*originRootConf)
 	assert.NotNil(t, srvConf)
 }
+
+func TestInitServerTransport(t *testing.T) {
+	originRootConf := config.GetRootConfig()
+	defer config.SetRootConfig(*originRootConf)
+
+	noDelay := false
+	config.SetRootConfig(config.RootConfig{
+		Application: &config.ApplicationConfig{Name: "getty-test"},
+		Protocols: map[string]*config.ProtocolConfig{
+			"dubbo": {
+				Name: "dubbo",
+				Ip:   "127.0.0.1",
+				Port: "20003",
+				Transport: &config.TransportConfig{
+					TcpNoDelay:      &noDelay,
+					KeepAlivePeriod: "60s",
+					TcpRBufSize:     1048576,
+				},
+			},
+		},
+	})
+	initServer("dubbo")
+	defaults := GetDefaultServerConfig()
+	assert.False(t, srvConf.GettySessionParam.TcpNoDelay)
+	assert.Equal(t, "60s", srvConf.GettySessionParam.KeepAlivePeriod)
+	assert.Equal(t, 1048576, srvConf.GettySessionParam.TcpRBufSize)
+	// the absent fields keep the defaults
+	assert.Equal(t, defaults.GettySessionParam.TcpKeepAlive, srvConf.GettySessionParam.TcpKeepAlive)
+	assert.Equal(t, defaults.GettySessionParam.TcpWBufSize, srvConf.GettySessionParam.TcpWBufSize)
+	assert.Equal(t, defaults.GettySessionParam.TcpWriteTimeout, srvConf.GettySessionParam.TcpWriteTimeout)
+}
@@ -0,0 +1,439 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+import (
+	getty "github.com/apache/dubbo-getty"
+
+	"github.com/dubbogo/gost/log/logger"
+
+	"github.com/dustin/go-humanize"
+
+	perrors "github.com/pkg/errors"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// the kinds of the inbound limits
+const (
+	limitConnectionRequests = iota
+	limitConnectionBytes
+	limitIPRequests
+	limitIPBytes
+	limitKinds
+)
+
+// defaultIPIdleTimeout is how long the limit of a source ip is kept after all its connections are closed by default
+const defaultIPIdleTimeout = 10 * time.Minute
+
+var limitKindNames = [limitKinds]string{"connection-requests", "connection-bytes", "ip-requests", "ip-bytes"}
+
+var (
+	errTooManyThrottled = perrors.New("too many requests are throttled by the inbound limits")
+
+	// inboundLimiters are the limiters of the started servers by their addresses
+	inboundLimiters sync.Map
+)
+
+// InboundLimitStats is a snapshot of the statistics of the inbound limiter of a server
+type InboundLimitStats struct {
+	// Throttled are the requests throttled by each limit, keyed by the names of the limits like connection-requests
+	Throttled map[string]int64 `json:"throttled"`
+	// Closed is the number of the connections closed as too many of their requests in a row are throttled
+	Closed int64 `json:"closed"`
+	// TopTalkers are the source ips which send the most, including the ones idle within the ip idle timeout
+	TopTalkers []TalkerStats `json:"topTalkers"`
+}
+
+// TalkerStats is the statistics of the requests received from a source ip until it has been idle for the ip idle
+// timeout
+type TalkerStats struct {
+	IP          string `json:"ip"`
+	Connections int    `json:"connections"`
+	Requests    int64  `json:"requests"`
+	Bytes       int64  `json:"bytes"`
+	Throttled   int64  `json:"throttled"`
+}
+
+// tokenBucket allows @rate tokens per second with the burst of one second. It is not goroutine safe.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket of @rate, or nil if it is unlimited
+func newTokenBucket(rate float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// ready refills the bucket at @now and reports whether it has @n tokens. A request larger than the burst is allowed
+// by a full bucket, and leaves the bucket in debt.
+func (b *tokenBucket) ready(now time.Time, n float64) bool {
+	if b == nil {
+		return true
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.last = now
+	}
+	return b.tokens >= math.Min(n, b.rate)
+}
+
+func (b *tokenBucket) take(n float64) {
+	if b != nil {
+		b.tokens -= n
+	}
+}
+
+// inboundLimiter limits the requests received by a server per connection and per source ip, see
+// config.InboundLimitConfig
+type inboundLimiter struct {
+	connectionRequests  float64
+	connectionBytes     float64
+	ipRequests          float64
+	ipBytes             float64
+	closeAfterThrottled int
+	ipIdleTimeout       time.Duration
+
+	throttled [limitKinds]atomic.Int64
+	closed    atomic.Int64
+
+	lock  sync.Mutex
+	conns map[getty.Session]*connLimit
+	ips   map[string]*ipLimit
+	// evicted is when the idle ips were evicted last time
+	evicted time.Time
+}
+
+// ipLimit is shared by the connections from a source ip, and outlives them by the ip idle timeout
+type ipLimit struct {
+	ip string
+	// conns and idleSince are guarded by the lock of the inboundLimiter
+	conns     int
+	idleSince time.Time
+
+	lock      sync.Mutex
+	requests  *tokenBucket
+	bytes     *tokenBucket
+	received  int64
+	size      int64
+	throttled int64
+}
+
+// connLimit is only used by the goroutine reading the connection
+type connLimit struct {
+	ip             *ipLimit
+	requests       *tokenBucket
+	bytes          *tokenBucket
+	throttledInRow int
+}
+
+// newInboundLimiter returns the limiter of @conf, or nil if all the limits are unlimited
+func newInboundLimiter(conf *config.InboundLimitConfig) *inboundLimiter {
+	if conf == nil {
+		return nil
+	}
+	l := &inboundLimiter{
+		connectionRequests:  float64(conf.ConnectionRequests),
+		connectionBytes:     parseLimitBytes("connection-bytes", conf.ConnectionBytes),
+		ipRequests:          float64(conf.IPRequests),
+		ipBytes:             parseLimitBytes("ip-bytes", conf.IPBytes),
+		closeAfterThrottled: conf.CloseAfterThrottled,
+		ipIdleTimeout:       parseIPIdleTimeout(conf.IPIdleTimeout),
+		evicted:             time.Now(),
+		conns:               make(map[getty.Session]*connLimit),
+		ips:                 make(map[string]*ipLimit),
+	}
+	if l.connectionRequests <= 0 && l.connectionBytes <= 0 && l.ipRequests <= 0 && l.ipBytes <= 0 {
+		return nil
+	}
+	return l
+}
+
+// parseLimitBytes parses the bytes per second @size of the limit @name, it is unlimited if @size is invalid
+func parseLimitBytes(name, size string) float64 {
+	if size == "" {
+		return 0
+	}
+	bytes, err := humanize.ParseBytes(size)
+	if err != nil {
+		logger.Warnf("The inbound limit %s %q is invalid and ignored: %v", name, size, err)
+		return 0
+	}
+	return float64(bytes)
+}
+
+// parseIPIdleTimeout parses the ip idle timeout @timeout, it is defaultIPIdleTimeout if @timeout is absent or invalid
+func parseIPIdleTimeout(timeout string) time.Duration {
+	if timeout == "" {
+		return defaultIPIdleTimeout
+	}
+	d, err := common.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		logger.Warnf("The inbound limit ip-idle-timeout %q is invalid, %s is used instead", timeout, defaultIPIdleTimeout)
+		return defaultIPIdleTimeout
+	}
+	return d
+}
+
+// open returns the limit of the connection @session, which must be released by close
+func (l *inboundLimiter) open(session getty.Session) *connLimit {
+	ip := session.RemoteAddr()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.evictIdle(time.Now())
+	shared, ok := l.ips[ip]
+	if !ok {
+		shared = &ipLimit{ip: ip, requests: newTokenBucket(l.ipRequests), bytes: newTokenBucket(l.ipBytes)}
+		l.ips[ip] = shared
+	}
+	shared.conns++
+	c := &connLimit{
+		ip:       shared,
+		requests: newTokenBucket(l.connectionRequests),
+		bytes:    newTokenBucket(l.connectionBytes),
+	}
+	l.conns[session] = c
+	return c
+}
+
+// close releases the limit of the connection @session. The limit of its source ip is kept after all the connections
+// from it are closed, so that reconnecting doesn't refill it, until it has been idle for the ip idle timeout.
+func (l *inboundLimiter) close(session getty.Session) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	c, ok := l.conns[session]
+	if !ok {
+		return
+	}
+	delete(l.conns, session)
+	now := time.Now()
+	if c.ip.conns--; c.ip.conns <= 0 {
+		c.ip.idleSince = now
+	}
+	l.evictIdle(now)
+}
+
+// evictIdle drops the limits of the source ips which have been idle for the ip idle timeout at @now. The ips are
+// scanned at most once per ip idle timeout, so an idle ip is kept for up to twice the timeout. It must be called
+// with the lock held.
+func (l *inboundLimiter) evictIdle(now time.Time) {
+	if now.Sub(l.evicted) < l.ipIdleTimeout {
+		return
+	}
+	l.evicted = now
+	for ip, limit := range l.ips {
+		if limit.conns <= 0 && now.Sub(limit.idleSince) >= l.ipIdleTimeout {
+			delete(l.ips, ip)
+		}
+	}
+}
+
+// allow reports whether the request of @size bytes received by the connection of @c is within the limits, the
+// kind of the limit exceeded is returned otherwise
+func (l *inboundLimiter) allow(c *connLimit, size int) (int, bool) {
+	now := time.Now()
+	kind := -1
+	if !c.requests.ready(now, 1) {
+		kind = limitConnectionRequests
+	} else if !c.bytes.ready(now, float64(size)) {
+		kind = limitConnectionBytes
+	}
+
+	ip := c.ip
+	ip.lock.Lock()
+	if kind < 0 {
+		if !ip.requests.ready(now, 1) {
+			kind = limitIPRequests
+		} else if !ip.bytes.ready(now, float64(size)) {
+			kind = limitIPBytes
+		}
+	}
+	ip.received++
+	ip.size += int64(size)
+	if kind >= 0 {
+		ip.throttled++
+	} else {
+		ip.requests.take(1)
+		ip.bytes.take(float64(size))
+	}
+	ip.lock.Unlock()
+
+	if kind >= 0 {
+		l.throttled[kind].Inc()
+		c.throttledInRow++
+		return kind, false
+	}
+	c.requests.take(1)
+	c.bytes.take(float64(size))
+	c.throttledInRow = 0
+	return kind, true
+}
+
+// abused reports whether the connection of @c should be closed for too many throttled requests in a row
+func (l *inboundLimiter) abused(c *connLimit) bool {
+	return l.closeAfterThrottled > 0 && c.throttledInRow >= l.closeAfterThrottled
+}
+
+// stats returns the statistics of the limiter with the @top source ips of the largest @by, the source ips are not
+// collected at all if @top is 0
+func (l *inboundLimiter) stats(top int, by func(t TalkerStats) int64) InboundLimitStats {
+	stats := InboundLimitStats{
+		Throttled:  make(map[string]int64, limitKinds),
+		Closed:     l.closed.Load(),
+		TopTalkers: make([]TalkerStats, 0),
+	}
+	for kind, name := range limitKindNames {
+		stats.Throttled[name] = l.throttled[kind].Load()
+	}
+
+	if top <= 0 {
+		return stats
+	}
+	l.lock.Lock()
+	for _, ip := range l.ips {
+		ip.lock.Lock()
+		stats.TopTalkers = append(stats.TopTalkers, TalkerStats{
+			IP:          ip.ip,
+			Connections: ip.conns,
+			Requests:    ip.received,
+			Bytes:       ip.size,
+			Throttled:   ip.throttled,
+		})
+		ip.lock.Unlock()
+	}
+	l.lock.Unlock()
+
+	sort.Slice(stats.TopTalkers, func(i, j int) bool {
+		a, b := stats.TopTalkers[i], stats.TopTalkers[j]
+		if by(a) != by(b) {
+			return by(a) > by(b)
+		}
+		return a.IP < b.IP
+	})
+	if len(stats.TopTalkers) > top {
+		stats.TopTalkers = stats.TopTalkers[:top]
+	}
+	return stats
+}
+
+// throttledRequest is a request throttled by the inbound limiter, whose body is skipped without decoding
+type throttledRequest struct {
+	id       int64
+	serialID byte
+	twoWay   bool
+	limit    string
+}
+
+// response returns the cheap throttled response of the request, which the consumers take as common.StatusThrottled
+func (r *throttledRequest) response() *remoting.Response {
+	resp := remoting.NewResponse(r.id, "2.0.2")
+	resp.SerialID = r.serialID
+	resp.Status = impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR
+	resp.Result = protocol.RPCResult{
+		Err: perrors.Errorf("the request is throttled by the inbound limit %s of the provider", r.limit),
+	}
+	return resp
+}
+
+// limitRequest checks the request at the head of @data against the limits before its body is decoded. It returns
+// a throttledRequest and the length of the request to skip it if it is throttled, or nil if it is to be decoded,
+// which includes the heartbeats, the responses and the incomplete requests.
+func (p *RpcServerPackageHandler) limitRequest(ss getty.Session, data []byte) (interface{}, int, error) {
+	if len(data) < impl.HEADER_LENGTH || data[0] != impl.MAGIC_HIGH || data[1] != impl.MAGIC_LOW {
+		return nil, 0, nil
+	}
+	flag := data[2]
+	if flag&impl.FLAG_REQUEST == 0 || flag&impl.FLAG_EVENT != 0 {
+		return nil, 0, nil
+	}
+	length := impl.HEADER_LENGTH + int(binary.BigEndian.Uint32(data[12:]))
+	if len(data) < length {
+		return nil, 0, nil
+	}
+
+	limiter := p.server.limiter
+	kind, ok := limiter.allow(p.limit, length)
+	if ok {
+		return nil, 0, nil
+	}
+	if limiter.abused(p.limit) {
+		limiter.closed.Inc()
+		logger.Warnf("Getty Server closes the session %s as %d requests in a row are throttled", ss.Stat(),
+			p.limit.throttledInRow)
+		// the websocket sessions are not closed by the errors of the package handler
+		go ss.Close()
+		return nil, length, errTooManyThrottled
+	}
+	return &throttledRequest{
+		id:       int64(binary.BigEndian.Uint64(data[4:])),
+		serialID: flag & impl.SERIAL_MASK,
+		twoWay:   flag&impl.FLAG_TWOWAY != 0,
+		limit:    limitKindNames[kind],
+	}, length, nil
+}
+
+// InboundLimits returns the statistics of the inbound limiters of the started servers by their addresses, each with
+// the @top source ips sending the most by @by, which is requests, bytes or throttled, the bytes if it is empty
+func InboundLimits(top int, by string) (map[string]InboundLimitStats, error) {
+	var talker func(t TalkerStats) int64
+	switch by {
+	case "", "bytes":
+		talker = func(t TalkerStats) int64 { return t.Bytes }
+	case "requests":
+		talker = func(t TalkerStats) int64 { return t.Requests }
+	case "throttled":
+		talker = func(t TalkerStats) int64 { return t.Throttled }
+	default:
+		return nil, perrors.Errorf("invalid sort %q, it should be requests, bytes or throttled", by)
+	}
+	all := make(map[string]InboundLimitStats)
+	inboundLimiters.Range(func(key, value interface{}) bool {
+		all[key.(string)] = value.(*inboundLimiter).stats(top, talker)
+		return true
+	})
+	return all, nil
+}
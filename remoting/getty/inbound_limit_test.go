@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	getty "github.com/apache/dubbo-getty"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// limitedSession is a session from @remoteAddr, the other methods of getty.Session are not implemented
+type limitedSession struct {
+	getty.Session
+	remoteAddr string
+}
+
+func (s *limitedSession) RemoteAddr() string {
+	return s.remoteAddr
+}
+
+func (s *limitedSession) Stat() string {
+	return s.remoteAddr
+}
+
+func (s *limitedSession) Close() {}
+
+func encodeLimitedRequest(t *testing.T, id int64, event bool) []byte {
+	request := remoting.NewRequest("2.0.2")
+	request.ID = id
+	request.TwoWay = true
+	request.Event = event
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"1"}),
+		invocation.WithAttachments(map[string]interface{}{constant.PathKey: "com.ikurento.user.UserProvider"}))
+	request.Data = inv
+	buf, err := (&DubboTestCodec{}).EncodeRequest(request)
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestTokenBucket(t *testing.T) {
+	assert.Nil(t, newTokenBucket(0))
+	assert.True(t, (*tokenBucket)(nil).ready(time.Now(), 1))
+
+	b := newTokenBucket(2)
+	now := b.last
+	assert.True(t, b.ready(now, 2))
+	b.take(2)
+	assert.False(t, b.ready(now.Add(100*time.Millisecond), 1))
+	assert.True(t, b.ready(now.Add(500*time.Millisecond), 1))
+
+	// a request larger than the burst is allowed by a full bucket, and leaves the bucket in debt
+	assert.False(t, b.ready(now.Add(600*time.Millisecond), 10))
+	assert.True(t, b.ready(now.Add(time.Second), 10))
+	b.take(10)
+	assert.False(t, b.ready(now.Add(5*time.Second), 1))
+	assert.True(t, b.ready(now.Add(5500*time.Millisecond), 1))
+	// the burst is one second
+	assert.True(t, b.ready(now.Add(time.Hour), 1))
+	assert.Equal(t, float64(2), b.tokens)
+}
+
+func TestNewInboundLimiter(t *testing.T) {
+	assert.Nil(t, newInboundLimiter(nil))
+	assert.Nil(t, newInboundLimiter(&config.InboundLimitConfig{CloseAfterThrottled: 3}))
+	assert.Nil(t, newInboundLimiter(&config.InboundLimitConfig{ConnectionBytes: "invalid"}))
+	l := newInboundLimiter(&config.InboundLimitConfig{IPBytes: "1kib"})
+	assert.NotNil(t, l)
+	assert.Equal(t, float64(1024), l.ipBytes)
+	assert.Equal(t, defaultIPIdleTimeout, l.ipIdleTimeout)
+	l = newInboundLimiter(&config.InboundLimitConfig{IPRequests: 1, IPIdleTimeout: "1m"})
+	assert.Equal(t, time.Minute, l.ipIdleTimeout)
+	l = newInboundLimiter(&config.InboundLimitConfig{IPRequests: 1, IPIdleTimeout: "invalid"})
+	assert.Equal(t, defaultIPIdleTimeout, l.ipIdleTimeout)
+}
+
+func TestInboundLimitConnection(t *testing.T) {
+	server := &Server{
+		codec:   &DubboTestCodec{},
+		limiter: newInboundLimiter(&config.InboundLimitConfig{ConnectionRequests: 1, CloseAfterThrottled: 2}),
+	}
+	session := &limitedSession{remoteAddr: "192.168.0.1:12345"}
+	handler := server.newPkgHandler(session)
+
+	pkg, length, err := handler.Read(session, encodeLimitedRequest(t, 1, false))
+	assert.NoError(t, err)
+	assert.IsType(t, &remoting.DecodeResult{}, pkg)
+	assert.True(t, length > impl.HEADER_LENGTH)
+
+	// the incomplete request is not counted
+	data := encodeLimitedRequest(t, 2, false)
+	pkg, length, _ = handler.Read(session, data[:len(data)-1])
+	assert.Equal(t, 0, length)
+	assert.Equal(t, int64(0), server.limiter.throttled[limitConnectionRequests].Load())
+
+	pkg, length, err = handler.Read(session, data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	throttled := pkg.(*throttledRequest)
+	assert.Equal(t, int64(2), throttled.id)
+	assert.Equal(t, byte(constant.SHessian2), throttled.serialID)
+	assert.True(t, throttled.twoWay)
+	assert.Equal(t, "connection-requests", throttled.limit)
+	resp := throttled.response()
+	assert.Equal(t, int64(2), resp.ID)
+	assert.Equal(t, impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR, resp.Status)
+	assert.Contains(t, resp.Result.(protocol.RPCResult).Err.Error(), "connection-requests")
+
+	// the heartbeats are never limited
+	pkg, _, err = handler.Read(session, encodeLimitedRequest(t, 3, true))
+	assert.NoError(t, err)
+	assert.True(t, pkg.(*remoting.DecodeResult).Result.(*remoting.Request).Event)
+
+	// the connection is closed after 2 throttled requests in a row
+	_, _, err = handler.Read(session, encodeLimitedRequest(t, 4, false))
+	assert.Equal(t, errTooManyThrottled, err)
+	assert.Equal(t, int64(1), server.limiter.closed.Load())
+	assert.Equal(t, int64(2), server.limiter.throttled[limitConnectionRequests].Load())
+
+	server.limiter.close(session)
+	assert.Empty(t, server.limiter.conns)
+	assert.Len(t, server.limiter.ips, 1)
+}
+
+func TestInboundLimitIP(t *testing.T) {
+	server := &Server{
+		addr:    "127.0.0.1:20099",
+		codec:   &DubboTestCodec{},
+		limiter: newInboundLimiter(&config.InboundLimitConfig{IPRequests: 2}),
+	}
+	inboundLimiters.Store(server.addr, server.limiter)
+	defer inboundLimiters.Delete(server.addr)
+
+	first := &limitedSession{remoteAddr: "192.168.0.1:10001"}
+	second := &limitedSession{remoteAddr: "192.168.0.1:10002"}
+	other := &limitedSession{remoteAddr: "192.168.0.2:10001"}
+	firstHandler, secondHandler := server.newPkgHandler(first), server.newPkgHandler(second)
+	otherHandler := server.newPkgHandler(other)
+
+	data := encodeLimitedRequest(t, 1, false)
+	for _, handler := range []*RpcServerPackageHandler{firstHandler, secondHandler, otherHandler} {
+		pkg, _, err := handler.Read(first, data)
+		assert.NoError(t, err)
+		assert.IsType(t, &remoting.DecodeResult{}, pkg)
+	}
+	// the connections from the same ip share the limit
+	pkg, _, err := firstHandler.Read(first, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "ip-requests", pkg.(*throttledRequest).limit)
+
+	all, err := InboundLimits(1, "")
+	assert.NoError(t, err)
+	stats := all[server.addr]
+	assert.Equal(t, int64(1), stats.Throttled["ip-requests"])
+	assert.Equal(t, []TalkerStats{{
+		IP:          "192.168.0.1",
+		Connections: 2,
+		Requests:    3,
+		Bytes:       int64(3 * len(data)),
+		Throttled:   1,
+	}}, stats.TopTalkers)
+
+	all, err = InboundLimits(5, "throttled")
+	assert.NoError(t, err)
+	assert.Len(t, all[server.addr].TopTalkers, 2)
+	assert.Equal(t, "192.168.0.1", all[server.addr].TopTalkers[0].IP)
+	assert.Equal(t, "192.168.0.2", all[server.addr].TopTalkers[1].IP)
+	all, err = InboundLimits(0, "")
+	assert.NoError(t, err)
+	assert.Empty(t, all[server.addr].TopTalkers)
+	_, err = InboundLimits(5, "size")
+	assert.EqualError(t, err, `invalid sort "size", it should be requests, bytes or throttled`)
+
+	// reconnecting doesn't refill the limit of the ip
+	server.limiter.close(first)
+	server.limiter.close(second)
+	assert.Len(t, server.limiter.ips, 2)
+	first = &limitedSession{remoteAddr: "192.168.0.1:10003"}
+	firstHandler = server.newPkgHandler(first)
+	pkg, _, err = firstHandler.Read(first, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "ip-requests", pkg.(*throttledRequest).limit)
+
+	// the ip is evicted once it has been idle for the ip idle timeout
+	server.limiter.close(first)
+	server.limiter.lock.Lock()
+	server.limiter.evictIdle(time.Now().Add(server.limiter.ipIdleTimeout / 2))
+	assert.Len(t, server.limiter.ips, 2)
+	server.limiter.evictIdle(time.Now().Add(server.limiter.ipIdleTimeout))
+	assert.Len(t, server.limiter.ips, 1)
+	assert.Contains(t, server.limiter.ips, "192.168.0.2")
+	server.limiter.lock.Unlock()
+}
@@ -18,6 +18,8 @@
 package getty
 
 import (
+	"runtime/debug"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -36,6 +38,9 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
@@ -47,11 +52,19 @@ const (
 var (
 	errTooManySessions      = perrors.New("too many sessions")
 	errHeartbeatReadTimeout = perrors.New("heartbeat read timeout")
+
+	// sessionsClosed are the channels closed once the sessions are closed, which stop the heartbeats waiting for
+	// the responses of the sessions
+	sessionsClosed sync.Map
 )
 
 type rpcSession struct {
 	session getty.Session
 	reqNum  int32
+	// err is the error breaking the session, which is reported when the session is closed
+	err error
+	// serviceKeys are the services invoked over the session of the server
+	serviceKeys map[string]struct{}
 }
 
 func (s *rpcSession) AddReqNum(num int32) {
@@ -62,10 +75,38 @@ func (s *rpcSession) GetReqNum() int32 {
 	return atomic.LoadInt32(&s.reqNum)
 }
 
+func (s *rpcSession) sortedServiceKeys() []string {
+	keys := make([]string, 0, len(s.serviceKeys))
+	for key := range s.serviceKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// publishClosed publishes the close of the @session, which is broken by @err, or closed locally if @closedLocally,
+// or else closed by the peer
+func publishClosed(side string, session getty.Session, serviceKeys []string, err error, closedLocally bool) {
+	event := remoting.ConnectionEvent{
+		Type:          remoting.ConnectionClosedByPeer,
+		Side:          side,
+		LocalAddress:  session.LocalAddr(),
+		RemoteAddress: session.RemoteAddr(),
+		ServiceKeys:   serviceKeys,
+		Err:           err,
+	}
+	if err != nil || closedLocally {
+		event.Type = remoting.ConnectionDisconnected
+	}
+	remoting.PublishConnectionEvent(event)
+}
+
 // nolint
 type RpcClientHandler struct {
 	conn         *gettyRPCClient
 	timeoutTimes int
+	// err is the error breaking the session, OnError and OnClose are called by the same goroutine
+	err error
 }
 
 // nolint
@@ -76,19 +117,34 @@ func NewRpcClientHandler(client *gettyRPCClient) *RpcClientHandler {
 // OnOpen call the getty client session opened, add the session to getty client session list
 func (h *RpcClientHandler) OnOpen(session getty.Session) error {
 	h.conn.addSession(session)
+	diagnostics.Track(session, "getty-client", diagnostics.KindConnection, session.RemoteAddr())
+	remoting.AddConnections(constant.SideConsumer, 1)
+	remoting.PublishConnectionEvent(remoting.ConnectionEvent{
+		Type:          remoting.ConnectionConnected,
+		Side:          constant.SideConsumer,
+		LocalAddress:  session.LocalAddr(),
+		RemoteAddress: session.RemoteAddr(),
+		ServiceKeys:   h.conn.rpcClient.serviceKeys(),
+	})
 	return nil
 }
 
 // OnError the getty client session has errored, so remove the session from the getty client session list
 func (h *RpcClientHandler) OnError(session getty.Session, err error) {
 	logger.Infof("session{%s} got error{%v}, will be closed.", session.Stat(), err)
+	h.err = err
 	h.conn.removeSession(session)
 }
 
 // OnClose close the session, remove it from the getty session list
 func (h *RpcClientHandler) OnClose(session getty.Session) {
 	logger.Infof("session{%s} is closing......", session.Stat())
+	// the client is inactive once it is closed locally
+	closedLocally := h.conn.getActive() == 0
 	h.conn.removeSession(session)
+	onSessionClosed(session)
+	remoting.AddConnections(constant.SideConsumer, -1)
+	publishClosed(constant.SideConsumer, session, h.conn.rpcClient.serviceKeys(), h.err, closedLocally)
 }
 
 // OnMessage get response from getty server, and update the session to the getty client session list
@@ -148,6 +204,9 @@ func (h *RpcClientHandler) OnCron(session getty.Session) {
 		h.conn.removeSession(session) // -> h.conn.close() -> h.conn.pool.remove(h.conn)
 		return
 	}
+	if isWebsocket(session) {
+		return
+	}
 
 	heartbeatCallBack := func(err error) {
 		if err != nil {
@@ -162,9 +221,7 @@ func (h *RpcClientHandler) OnCron(session getty.Session) {
 		h.timeoutTimes = 0
 	}
 
-	if err := heartbeat(session, h.conn.rpcClient.conf.heartbeatTimeout, heartbeatCallBack); err != nil {
-		logger.Warnf("failed to send heartbeat, error{%v}", err)
-	}
+	heartbeat(session, h.conn.rpcClient.conf.heartbeatTimeout, heartbeatCallBack)
 }
 
 // nolint
@@ -197,21 +254,36 @@ func (h *RpcServerHandler) OnOpen(session getty.Session) error {
 	}
 	h.rwlock.RUnlock()
 	if err != nil {
+		// OnClose is not called for the rejected session
+		if h.server.limiter != nil {
+			h.server.limiter.close(session)
+		}
 		return perrors.WithStack(err)
 	}
 
 	logger.Infof("got session:%s", session.Stat())
 	h.rwlock.Lock()
-	h.sessionMap[session] = &rpcSession{session: session}
+	h.sessionMap[session] = &rpcSession{session: session, serviceKeys: make(map[string]struct{})}
 	h.rwlock.Unlock()
+	diagnostics.Track(session, "getty-server", diagnostics.KindConnection, session.RemoteAddr())
+	remoting.AddConnections(constant.SideProvider, 1)
+	remoting.PublishConnectionEvent(remoting.ConnectionEvent{
+		Type:          remoting.ConnectionConnected,
+		Side:          constant.SideProvider,
+		LocalAddress:  session.LocalAddr(),
+		RemoteAddress: session.RemoteAddr(),
+	})
 	return nil
 }
 
-// OnError the getty server session has errored, so remove the session from the getty server session list
+// OnError the getty server session has errored, the session is removed from the getty server session list when
+// it is closed
 func (h *RpcServerHandler) OnError(session getty.Session, err error) {
 	logger.Infof("session{%s} got error{%v}, will be closed.", session.Stat(), err)
 	h.rwlock.Lock()
-	delete(h.sessionMap, session)
+	if rs, ok := h.sessionMap[session]; ok {
+		rs.err = err
+	}
 	h.rwlock.Unlock()
 }
 
@@ -219,8 +291,21 @@ func (h *RpcServerHandler) OnError(session getty.Session, err error) {
 func (h *RpcServerHandler) OnClose(session getty.Session) {
 	logger.Infof("session{%s} is closing......", session.Stat())
 	h.rwlock.Lock()
+	rs, ok := h.sessionMap[session]
 	delete(h.sessionMap, session)
 	h.rwlock.Unlock()
+	// OnClose is called once for each session accepted by OnOpen, including the ones closed by OnCron
+	onSessionClosed(session)
+	remoting.AddConnections(constant.SideProvider, -1)
+	if h.server.limiter != nil {
+		h.server.limiter.close(session)
+	}
+	if !ok {
+		// the session is closed by OnCron
+		publishClosed(constant.SideProvider, session, nil, nil, true)
+		return
+	}
+	publishClosed(constant.SideProvider, session, rs.sortedServiceKeys(), rs.err, false)
 }
 
 // OnMessage get request from getty client, update the session reqNum and reply response to client
@@ -231,6 +316,13 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	}
 	h.rwlock.Unlock()
 
+	if throttled, ok := pkg.(*throttledRequest); ok {
+		if throttled.twoWay {
+			reply(session, throttled.response())
+		}
+		return
+	}
+
 	decodeResult, drOK := pkg.(*remoting.DecodeResult)
 	if !drOK || decodeResult == ((*remoting.DecodeResult)(nil)) {
 		logger.Errorf("illegal package{%#v}", pkg)
@@ -268,17 +360,45 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	if !ok {
 		panic("create invocation occur some exception for the type is not suitable one.")
 	}
+	h.rwlock.Lock()
+	if rs, ok := h.sessionMap[session]; ok {
+		rs.serviceKeys[invoc.ServiceKey()] = struct{}{}
+	}
+	h.rwlock.Unlock()
 	attachments := invoc.Attachments()
 	attachments[constant.LocalAddr] = session.LocalAddr()
 	attachments[constant.RemoteAddr] = session.RemoteAddr()
 
-	result := h.server.requestHandler(invoc)
-	if !req.TwoWay {
+	handle := func() {
+		replied := false
+		// the request may be handled on a goroutine pool, which recovers the panic without replying
+		defer func() {
+			if e := recover(); e != nil {
+				result := h.server.handlePanic(invoc, e, debug.Stack())
+				if req.TwoWay && !replied {
+					resp.Status = hessian.Response_SERVER_ERROR
+					resp.Result = result
+					reply(session, resp)
+				}
+			}
+		}()
+		result := h.server.requestHandler(invoc)
+		if !req.TwoWay {
+			return
+		}
+		resp.Result = result
+		replied = true
+		reply(session, resp)
+	}
+	if h.server.dispatcher == nil {
+		handle()
 		return
 	}
-	resp.Result = result
-
-	reply(session, resp)
+	if err := h.server.dispatcher(invoc, handle); err != nil && req.TwoWay {
+		resp.Status = impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR
+		resp.Result = protocol.RPCResult{Err: err}
+		reply(session, resp)
+	}
 }
 
 // OnCron check the session health periodic. if the session's sessionTimeout has reached, just close the session
@@ -305,6 +425,9 @@ func (h *RpcServerHandler) OnCron(session getty.Session) {
 		h.rwlock.Unlock()
 		session.Close()
 	}
+	if isWebsocket(session) {
+		return
+	}
 
 	heartbeatCallBack := func(err error) {
 		if err != nil {
@@ -322,9 +445,17 @@ func (h *RpcServerHandler) OnCron(session getty.Session) {
 		h.timeoutTimes = 0
 	}
 
-	if err := heartbeat(session, h.server.conf.heartbeatTimeout, heartbeatCallBack); err != nil {
-		logger.Warnf("failed to send heartbeat, error{%v}", err)
+	heartbeat(session, h.server.conf.heartbeatTimeout, heartbeatCallBack)
+}
+
+// isWebsocket returns true if @session is a websocket session, whose heartbeats are the ping frames sent by getty
+// before OnCron, and the pong frames keep it active
+func isWebsocket(session getty.Session) bool {
+	switch session.EndPoint().EndPointType() {
+	case getty.WS_CLIENT, getty.WSS_CLIENT, getty.WS_SERVER, getty.WSS_SERVER:
+		return true
 	}
+	return false
 }
 
 func reply(session getty.Session, resp *remoting.Response) {
@@ -337,7 +468,9 @@ func reply(session getty.Session, resp *remoting.Response) {
 	}
 }
 
-func heartbeat(session getty.Session, timeout time.Duration, callBack func(err error)) error {
+// heartbeat sends a heartbeat over @session, @callBack is called with the error once the heartbeat fails to be sent,
+// its response arrives or it times out, and isn't called if the session is closed before
+func heartbeat(session getty.Session, timeout time.Duration, callBack func(err error)) {
 	req := remoting.NewRequest("2.0.2")
 	req.TwoWay = true
 	req.Event = true
@@ -348,17 +481,46 @@ func heartbeat(session getty.Session, timeout time.Duration, callBack func(err e
 		logger.Warnf("start to close the session at heartbeat because %d of %d bytes data is sent success. err:%+v", sendLen, totalLen, err)
 		go session.Close()
 	}
+	if err != nil {
+		remoting.RemovePendingResponse(remoting.SequenceType(req.ID))
+		callBack(perrors.WithStack(err))
+		return
+	}
 
-	go func() {
+	closed := sessionClosed(session)
+	diagnostics.Go("getty-heartbeat", session.RemoteAddr(), func() {
 		var err1 error
 		select {
 		case <-gxtime.After(timeout):
+			remoting.RemovePendingResponse(remoting.SequenceType(req.ID))
 			err1 = errHeartbeatReadTimeout
 		case <-resp.Done:
 			err1 = resp.Err
+		case <-closed:
+			remoting.RemovePendingResponse(remoting.SequenceType(req.ID))
+			return
 		}
 		callBack(err1)
-	}()
+	})
+}
 
-	return perrors.WithStack(err)
+// sessionClosed returns the channel closed once @session is closed
+func sessionClosed(session getty.Session) <-chan struct{} {
+	closed, ok := sessionsClosed.Load(session)
+	if !ok {
+		closed, _ = sessionsClosed.LoadOrStore(session, make(chan struct{}))
+		// the session may be closed before the channel is stored
+		if session.IsClosed() {
+			onSessionClosed(session)
+		}
+	}
+	return closed.(chan struct{})
+}
+
+// onSessionClosed stops the heartbeats waiting for the responses of @session, and stops tracking it
+func onSessionClosed(session getty.Session) {
+	diagnostics.Untrack(session)
+	if closed, ok := sessionsClosed.LoadAndDelete(session); ok {
+		close(closed.(chan struct{}))
+	}
 }
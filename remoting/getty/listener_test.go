@@ -19,10 +19,16 @@ package getty
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 )
 
 import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	getty "github.com/apache/dubbo-getty"
+
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 
@@ -30,8 +36,12 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/gopool"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // test rebuild the ctx
@@ -74,3 +84,192 @@ func rebuildCtx(inv *invocation.RPCInvocation) context.Context {
 	}
 	return ctx
 }
+
+// listenConnectionEvents returns the connection events of the @side
+func listenConnectionEvents(side string) <-chan remoting.ConnectionEvent {
+	events := make(chan remoting.ConnectionEvent, 16)
+	remoting.AddConnectionListener(func(event remoting.ConnectionEvent) {
+		if event.Side != side {
+			return
+		}
+		select {
+		case events <- event:
+		default:
+			// the listener of the finished test drops the events
+		}
+	})
+	return events
+}
+
+// assertConnectionEvent asserts the next event of @remoteAddress in @events is @expected
+func assertConnectionEvent(t *testing.T, events <-chan remoting.ConnectionEvent, remoteAddress string,
+	expected remoting.ConnectionEventType) remoting.ConnectionEvent {
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.RemoteAddress != remoteAddress {
+				continue
+			}
+			assert.Equal(t, expected, event.Type, event.String())
+			return event
+		case <-timeout:
+			assert.Fail(t, "the connection event is not published", expected.String())
+			return remoting.ConnectionEvent{}
+		}
+	}
+}
+
+// mockProvider accepts the connections by @listener, and closes them all after @kill
+func mockProvider(listener net.Listener, kill <-chan struct{}) {
+	var conns []net.Conn
+	go func() {
+		<-kill
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			break
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+func TestClientConnectionEvents(t *testing.T) {
+	if raceEnabled {
+		// the sessions of dubbo-getty v1.4.9 copy their connections by value in readTimeout when they are closed by
+		// the client, which races with the atomic read deadline stored by their reading goroutines
+		t.Skip("the sessions of dubbo-getty race on closing")
+	}
+	remoting.RegistryCodec("dubbo", &DubboTestCodec{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	url, err := common.NewURL("dubbo://" + addr + "/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&version=1.0.0")
+	assert.NoError(t, err)
+	events := listenConnectionEvents(constant.SideConsumer)
+
+	conf := GetDefaultClientConfig()
+	conf.ConnectionNum = 1
+	assert.NoError(t, conf.CheckValidity())
+	client := NewClient(Options{ConnectTimeout: time.Second})
+	client.conf = *conf
+	client.addr = addr
+	client.codec = remoting.GetCodec("dubbo")
+	remoting.NewExchangeClient(url, client, time.Second, true)
+
+	kill := make(chan struct{})
+	go mockProvider(listener, kill)
+	assert.True(t, client.IsAvailable())
+	event := assertConnectionEvent(t, events, addr, remoting.ConnectionConnected)
+	assert.Equal(t, []string{url.ServiceKey()}, event.ServiceKeys)
+	// wait for getty to finish filling its connection pool
+	time.Sleep(500 * time.Millisecond)
+
+	// the mock provider is killed and restarted, getty reconnects it before closing the lost session
+	close(kill)
+	time.Sleep(100 * time.Millisecond)
+	listener, err = net.Listen("tcp", addr)
+	assert.NoError(t, err)
+	stop := make(chan struct{})
+	defer close(stop)
+	go mockProvider(listener, stop)
+	event = assertConnectionEvent(t, events, addr, remoting.ConnectionReconnecting)
+	assert.Equal(t, []string{url.ServiceKey()}, event.ServiceKeys)
+	assertConnectionEvent(t, events, addr, remoting.ConnectionConnected)
+	assertConnectionEvent(t, events, addr, remoting.ConnectionClosedByPeer)
+	assert.True(t, client.IsAvailable())
+
+	// wait for the client to shut down, whose sessions are closed in the background
+	client.mux.RLock()
+	rpcClient := client.gettyClient
+	client.mux.RUnlock()
+	client.Close()
+	select {
+	case <-rpcClient.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the client isn't shut down")
+	}
+	event = assertConnectionEvent(t, events, addr, remoting.ConnectionDisconnected)
+	assert.Nil(t, event.Err)
+}
+
+func TestServerConnectionEvents(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+	url, err := common.NewURL("dubbo://" + addr + "/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	events := listenConnectionEvents(constant.SideProvider)
+	server := NewServer(url, func(*invocation.RPCInvocation) protocol.RPCResult {
+		return protocol.RPCResult{}
+	})
+	server.Start()
+	defer server.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	assertConnectionEvent(t, events, conn.LocalAddr().String(), remoting.ConnectionConnected)
+	// the consumer is killed
+	conn.Close()
+	assertConnectionEvent(t, events, conn.LocalAddr().String(), remoting.ConnectionClosedByPeer)
+}
+
+// replySession records the packages written, the other methods of getty.Session are not implemented
+type replySession struct {
+	getty.Session
+	pkgs chan interface{}
+}
+
+func (s *replySession) LocalAddr() string {
+	return "127.0.0.1:20000"
+}
+
+func (s *replySession) RemoteAddr() string {
+	return "127.0.0.1:12345"
+}
+
+func (s *replySession) WritePkg(pkg interface{}, _ time.Duration) (int, int, error) {
+	s.pkgs <- pkg
+	return 0, 0, nil
+}
+
+func TestServerReplyPanic(t *testing.T) {
+	server := &Server{requestHandler: func(*invocation.RPCInvocation) protocol.RPCResult {
+		panic("the provider is broken")
+	}}
+	pool := gopool.NewPool("test-reply-panic", 1, 1)
+	defer pool.Close()
+	server.SetDispatcher(func(_ *invocation.RPCInvocation, handle func()) error {
+		return pool.Submit(handle)
+	})
+	session := &replySession{pkgs: make(chan interface{}, 1)}
+	handler := NewRpcServerHandler(1, time.Minute, server)
+
+	request := remoting.NewRequest("2.0.2")
+	request.ID = 1
+	request.TwoWay = true
+	request.Data = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithAttachments(map[string]interface{}{constant.PathKey: "com.ikurento.user.UserProvider"}))
+	handler.OnMessage(session, &remoting.DecodeResult{IsRequest: true, Result: request})
+
+	// the panic on the pool is answered by the error response instead of the timeout of the consumer
+	select {
+	case pkg := <-session.pkgs:
+		resp := pkg.(*remoting.Response)
+		assert.Equal(t, int64(1), resp.ID)
+		assert.Equal(t, hessian.Response_SERVER_ERROR, resp.Status)
+		// the panic value isn't answered to the consumer
+		err := resp.Result.(protocol.RPCResult).Err
+		assert.Equal(t, common.StatusInternal, common.CodeOf(err))
+		assert.Equal(t, "provider internal error", err.Error())
+	case <-time.After(time.Second):
+		t.Fatal("the panicked request isn't replied")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,6 +36,13 @@ import (
 	perrors "github.com/pkg/errors"
 )
 
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
 type gettyRPCClient struct {
 	once   sync.Once
 	addr   string // protocol string
@@ -45,6 +53,8 @@ type gettyRPCClient struct {
 	lock        sync.RWMutex
 	gettyClient getty.Client
 	sessions    []*rpcSession
+	// closed is closed once the getty client and the sessions are closed by close
+	closed chan struct{}
 }
 
 func newGettyRPCClientConn(rpcClient *Client, addr string) (*gettyRPCClient, error) {
@@ -58,20 +68,31 @@ func newGettyRPCClientConn(rpcClient *Client, addr string) (*gettyRPCClient, err
 		getty.WithConnectionNumber((int)(rpcClient.conf.ConnectionNum)),
 		getty.WithReconnectInterval(rpcClient.conf.ReconnectInterval),
 	}
-	if sslEnabled {
-		logger.Infof("Getty client initialized the TLS configuration")
-		clientOpts = append(clientOpts, getty.WithClientSslEnabled(sslEnabled), getty.WithClientTlsConfigBuilder(rpcClient.conf.TLSBuilder))
-	}
-
 	if clientGrPool != nil {
 		clientOpts = append(clientOpts, getty.WithClientTaskPool(clientGrPool))
 	}
 
-	gettyClient = getty.NewTCPClient(clientOpts...)
+	switch {
+	case strings.HasPrefix(addr, "ws://"):
+		gettyClient = getty.NewWSClient(clientOpts...)
+	case strings.HasPrefix(addr, "wss://"):
+		// the client certificate is loaded from the file, while getty does not verify the certificates of the servers
+		if tlsConfig := config.GetRootConfig().TLSConfig; tlsConfig != nil && tlsConfig.CACertFile != "" {
+			clientOpts = append(clientOpts, getty.WithRootCertificateFile(tlsConfig.CACertFile))
+		}
+		gettyClient = getty.NewWSSClient(clientOpts...)
+	default:
+		if sslEnabled {
+			logger.Infof("Getty client initialized the TLS configuration")
+			clientOpts = append(clientOpts, getty.WithClientSslEnabled(sslEnabled), getty.WithClientTlsConfigBuilder(rpcClient.conf.TLSBuilder))
+		}
+		gettyClient = getty.NewTCPClient(clientOpts...)
+	}
 	c := &gettyRPCClient{
 		addr:        addr,
 		rpcClient:   rpcClient,
 		gettyClient: gettyClient,
+		closed:      make(chan struct{}),
 	}
 	go c.gettyClient.RunEventLoop(c.newSession)
 
@@ -118,6 +139,15 @@ func (c *gettyRPCClient) newSession(session getty.Session) error {
 	)
 	conf = c.rpcClient.conf
 	sslEnabled = c.rpcClient.sslEnabled
+	// getty redials a lost session before closing it, so the loss is published after the reconnection
+	if c.hasStoppedSession() {
+		remoting.PublishConnectionEvent(remoting.ConnectionEvent{
+			Type:          remoting.ConnectionReconnecting,
+			Side:          constant.SideConsumer,
+			RemoteAddress: session.RemoteAddr(),
+			ServiceKeys:   c.rpcClient.serviceKeys(),
+		})
+	}
 	if conf.GettySessionParam.CompressEncoding {
 		session.SetCompressType(getty.CompressZip)
 	}
@@ -184,6 +214,18 @@ func (c *gettyRPCClient) selectSession() getty.Session {
 	return c.sessions[rand.Int31n(int32(count))].session
 }
 
+// hasStoppedSession returns true if a session is stopped but not closed yet
+func (c *gettyRPCClient) hasStoppedSession() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, s := range c.sessions {
+		if s.session.IsClosed() {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *gettyRPCClient) addSession(session getty.Session) {
 	logger.Debugf("add session{%s}", session.Stat())
 	if session == nil {
@@ -282,7 +324,8 @@ func (c *gettyRPCClient) isAvailable() bool {
 }
 
 func (c *gettyRPCClient) close() error {
-	closeErr := perrors.Errorf("close gettyRPCClient{%#v} again", c)
+	// the client is not formatted, whose fields are guarded by its lock
+	closeErr := perrors.Errorf("close gettyRPCClient{%s} again", c.addr)
 	c.once.Do(func() {
 		var (
 			gettyClient getty.Client
@@ -302,7 +345,8 @@ func (c *gettyRPCClient) close() error {
 
 		c.updateActive(0)
 
-		go func() {
+		diagnostics.Go("getty-client", c.addr, func() {
+			defer close(c.closed)
 			if gettyClient != nil {
 				gettyClient.Close()
 			}
@@ -311,7 +355,7 @@ func (c *gettyRPCClient) close() error {
 					s.session.Stat(), s.session.GetActive().String(), s.GetReqNum())
 				s.session.Close()
 			}
-		}()
+		})
 
 		closeErr = nil
 	})
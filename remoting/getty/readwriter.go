@@ -100,6 +100,8 @@ func (p *RpcClientPackageHandler) Write(ss getty.Session, pkg interface{}) ([]by
 // RpcServerPackageHandler Read data from client and Write data to client
 type RpcServerPackageHandler struct {
 	server *Server
+	// limit is the inbound limit of the session, nil if the inbound limiter of the server is disabled
+	limit *connLimit
 }
 
 func NewRpcServerPackageHandler(server *Server) *RpcServerPackageHandler {
@@ -109,6 +111,11 @@ func NewRpcServerPackageHandler(server *Server) *RpcServerPackageHandler {
 // Read data from client. if the package size from client is larger than 4096 byte, client will read 4096 byte
 // and send to client each time. the Read can assemble it.
 func (p *RpcServerPackageHandler) Read(ss getty.Session, data []byte) (interface{}, int, error) {
+	if p.limit != nil {
+		if throttled, length, err := p.limitRequest(ss, data); throttled != nil || err != nil {
+			return throttled, length, err
+		}
+	}
 	req, length, err := (p.server.codec).Decode(data)
 	if err != nil {
 		err = perrors.WithStack(err)
@@ -18,6 +18,7 @@
 package nacos
 
 import (
+	"errors"
 	"net"
 	"strconv"
 	"strings"
@@ -29,6 +30,8 @@ import (
 	"github.com/dubbogo/gost/log/logger"
 
 	nacosConstant "github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/http_agent"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/security"
 
 	perrors "github.com/pkg/errors"
 )
@@ -37,6 +40,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // NewNacosConfigClientByUrl read the config from url and build an instance
@@ -49,9 +53,29 @@ func NewNacosConfigClientByUrl(url *common.URL) (*nacosClient.NacosConfigClient,
 	if len(clientName) <= 0 {
 		return nil, perrors.New("nacos client name must set")
 	}
+	if err = login(sc, cc); err != nil {
+		return nil, err
+	}
 	return nacosClient.NewNacosConfigClient(clientName, true, sc, cc)
 }
 
+// login logs in the nacos servers if the username is set, the nacos client only logs the failures of the login so
+// the rejected credentials are found here rather than as the timeouts of the later requests
+func login(sc []nacosConstant.ServerConfig, cc nacosConstant.ClientConfig) error {
+	if len(cc.Username) == 0 {
+		return nil
+	}
+	authClient := security.NewAuthClient(cc, sc, &http_agent.HttpAgent{TlsConfig: cc.TLSCfg})
+	if _, err := authClient.Login(); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return perrors.WithMessagef(err, "login nacos %s", sc[0].IpAddr)
+		}
+		return perrors.WithMessagef(remoting.ErrAuthFailed, "login nacos as %s: %v", cc.Username, err)
+	}
+	return nil
+}
+
 // GetNacosConfig will return the nacos config
 func GetNacosConfig(url *common.URL) ([]nacosConstant.ServerConfig, nacosConstant.ClientConfig, error) {
 	if url == nil {
@@ -84,7 +108,17 @@ func GetNacosConfig(url *common.URL) ([]nacosConstant.ServerConfig, nacosConstan
 		serverConfigs = append(serverConfigs, nacosConstant.ServerConfig{IpAddr: ip, Port: uint64(port), ContextPath: contextPath})
 	}
 
-	timeout := url.GetParamDuration(constant.NacosTimeout, constant.DefaultRegTimeout)
+	tlsConfig, err := getTLSConfig(url)
+	if err != nil {
+		return []nacosConstant.ServerConfig{}, nacosConstant.ClientConfig{}, err
+	}
+	if tlsConfig.Enable {
+		for i := range serverConfigs {
+			serverConfigs[i].Scheme = "https"
+		}
+	}
+
+	timeout := url.GetParamDurationWithDefault(constant.NacosTimeout, constant.DefaultRegTimeout)
 
 	clientConfig := nacosConstant.ClientConfig{
 		TimeoutMs:            uint64(int32(timeout / time.Millisecond)),
@@ -104,10 +138,31 @@ func GetNacosConfig(url *common.URL) ([]nacosConstant.ServerConfig, nacosConstan
 		LogDir:               url.GetParam(constant.NacosLogDirKey, ""),
 		LogLevel:             url.GetParam(constant.NacosLogLevelKey, "info"),
 		UpdateCacheWhenEmpty: url.GetParamBool(constant.NacosUpdateCacheWhenEmpty, false),
+		TLSCfg:               tlsConfig,
 	}
 	return serverConfigs, clientConfig, nil
 }
 
+// getTLSConfig returns the tls config of the url, it is enabled if the ca cert is set or the verification of the
+// server certificate is skipped
+func getTLSConfig(url *common.URL) (nacosConstant.TLSConfig, error) {
+	caFile := url.GetParam(constant.CACert, "")
+	if len(caFile) == 0 && !url.GetParamBool(constant.TLSInsecureSkipVerify, false) {
+		return nacosConstant.TLSConfig{}, nil
+	}
+	certFile, keyFile := url.GetParam(constant.TLSCert, ""), url.GetParam(constant.TLSKey, "")
+	if (len(certFile) == 0) != (len(keyFile) == 0) {
+		return nacosConstant.TLSConfig{}, perrors.New("both the tls cert and the tls key are required for the client certificate")
+	}
+	return nacosConstant.TLSConfig{
+		Enable:             true,
+		CaFile:             caFile,
+		CertFile:           certFile,
+		KeyFile:            keyFile,
+		ServerNameOverride: url.GetParam(constant.TLSServerNAME, ""),
+	}, nil
+}
+
 // NewNacosClient create an instance with the config
 func NewNacosClient(rc *config.RemoteConfig) (*nacosClient.NacosNamingClient, error) {
 	url, err := rc.ToURL()
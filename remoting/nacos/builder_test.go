@@ -17,6 +17,109 @@
 
 package nacos
 
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+import (
+	nacosConstant "github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestGetNacosConfigWithCredential(t *testing.T) {
+	params := url.Values{}
+	params.Set(constant.NacosUsername, "nacos")
+	params.Set(constant.NacosPassword, "nacos")
+	params.Set(constant.NacosAccessKey, "ak")
+	params.Set(constant.NacosSecretKey, "sk")
+	params.Set(constant.CACert, "ca.pem")
+	params.Set(constant.TLSCert, "client.pem")
+	params.Set(constant.TLSKey, "client.key")
+	params.Set(constant.TLSServerNAME, "nacos.local")
+	u, _ := common.NewURL("registry://127.0.0.1:8848", common.WithParams(params))
+
+	sc, cc, err := GetNacosConfig(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "https", sc[0].Scheme)
+	assert.Equal(t, "nacos", cc.Username)
+	assert.Equal(t, "nacos", cc.Password)
+	assert.Equal(t, "ak", cc.AccessKey)
+	assert.Equal(t, "sk", cc.SecretKey)
+	assert.Equal(t, nacosConstant.TLSConfig{Enable: true, CaFile: "ca.pem", CertFile: "client.pem",
+		KeyFile: "client.key", ServerNameOverride: "nacos.local"}, cc.TLSCfg)
+
+	// the server certificate is not verified without the ca cert
+	params = url.Values{}
+	params.Set(constant.TLSInsecureSkipVerify, "true")
+	u, _ = common.NewURL("registry://127.0.0.1:8848", common.WithParams(params))
+	sc, cc, err = GetNacosConfig(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "https", sc[0].Scheme)
+	assert.Equal(t, nacosConstant.TLSConfig{Enable: true}, cc.TLSCfg)
+
+	params.Set(constant.TLSCert, "client.pem")
+	u, _ = common.NewURL("registry://127.0.0.1:8848", common.WithParams(params))
+	_, _, err = GetNacosConfig(u)
+	assert.Error(t, err)
+
+	// no tls
+	u, _ = common.NewURL("registry://127.0.0.1:8848")
+	sc, cc, err = GetNacosConfig(u)
+	assert.NoError(t, err)
+	assert.Empty(t, sc[0].Scheme)
+	assert.False(t, cc.TLSCfg.Enable)
+}
+
+func TestLogin(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"accessToken":"token","tokenTtl":18000}`))
+		} else {
+			_, _ = w.Write([]byte("unknown user!"))
+		}
+	}))
+	defer server.Close()
+
+	params := url.Values{}
+	params.Set(constant.NacosUsername, "nacos")
+	params.Set(constant.NacosPassword, "nacos")
+	u, _ := common.NewURL("registry://"+server.Listener.Addr().String(), common.WithParams(params))
+	sc, cc, err := GetNacosConfig(u)
+	assert.NoError(t, err)
+
+	status = http.StatusOK
+	assert.NoError(t, login(sc, cc))
+
+	status = http.StatusForbidden
+	err = login(sc, cc)
+	assert.True(t, perrors.Is(err, remoting.ErrAuthFailed))
+	assert.Contains(t, err.Error(), "unknown user!")
+
+	// the unreachable servers are not auth failures
+	server.Close()
+	err = login(sc, cc)
+	assert.Error(t, err)
+	assert.False(t, perrors.Is(err, remoting.ErrAuthFailed))
+
+	// no login without the username
+	cc.Username = ""
+	assert.NoError(t, login(sc, cc))
+}
+
 /*
 import (
 	"net/url"
@@ -0,0 +1,198 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nacos
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+import (
+	nacosClient "github.com/dubbogo/gost/database/kv/nacos"
+
+	nacosConstant "github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+var (
+	// sharedConfigClients are the config clients shared by the config centers and the metadata reports
+	sharedConfigClients = remoting.NewSharedClients()
+	// sharedNamingClients are the naming clients shared by the registries and the service discoveries
+	sharedNamingClients = remoting.NewSharedClients()
+
+	configWatchersLock sync.Mutex
+	// configWatchers are the listeners of the configs of each shared config client by the groups and the data ids
+	configWatchers = make(map[*nacosClient.NacosConfigClient]map[string][]*configWatcher)
+)
+
+// configWatcher is a listener of a config added by ListenConfig
+type configWatcher struct {
+	onChange func(namespace, group, dataId, data string)
+}
+
+// AcquireNacosConfigClient returns the config client of the nacos of @url shared by the config centers and the
+// metadata reports with the same servers, namespace, credentials and timeout, a new one is created if there is none.
+// The client must be released by ReleaseNacosConfigClient.
+func AcquireNacosConfigClient(url *common.URL) (*nacosClient.NacosConfigClient, error) {
+	sc, cc, err := GetNacosConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	clientName := url.GetParam(constant.ClientNameKey, "")
+	if len(clientName) <= 0 {
+		return nil, perrors.New("nacos client name must set")
+	}
+	client, err := sharedConfigClients.Acquire(sharedClientKey(sc, cc), nil, func() (interface{}, func(), error) {
+		if err := login(sc, cc); err != nil {
+			return nil, nil, err
+		}
+		newClient, err := nacosClient.NewNacosConfigClient(clientName, false, sc, cc)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newClient, func() {
+			configWatchersLock.Lock()
+			delete(configWatchers, newClient)
+			configWatchersLock.Unlock()
+			// the client is closed under the lock of gost, which its reconnection shares
+			newClient.Close()
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*nacosClient.NacosConfigClient), nil
+}
+
+// ReleaseNacosConfigClient releases the @client acquired by AcquireNacosConfigClient, it is closed once all its
+// holders release it
+func ReleaseNacosConfigClient(client *nacosClient.NacosConfigClient) {
+	if client == nil {
+		return
+	}
+	sharedConfigClients.Release(client)
+}
+
+// AcquireNacosNamingClient returns the naming client of the nacos of @url shared by the registries and the service
+// discoveries with the same servers, namespace, credentials and timeout, a new one is created if there is none. The
+// client must be released by ReleaseNacosNamingClient.
+func AcquireNacosNamingClient(url *common.URL) (*nacosClient.NacosNamingClient, error) {
+	sc, cc, err := GetNacosConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	clientName := url.GetParam(constant.ClientNameKey, "")
+	if len(clientName) <= 0 {
+		return nil, perrors.New("nacos client name must set")
+	}
+	client, err := sharedNamingClients.Acquire(sharedClientKey(sc, cc), nil, func() (interface{}, func(), error) {
+		newClient, err := nacosClient.NewNacosNamingClient(clientName, false, sc, cc)
+		if err != nil {
+			return nil, nil, err
+		}
+		return newClient, newClient.Close, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*nacosClient.NacosNamingClient), nil
+}
+
+// ReleaseNacosNamingClient releases the @client acquired by AcquireNacosNamingClient, it is closed once all its
+// holders release it
+func ReleaseNacosNamingClient(client *nacosClient.NacosNamingClient) {
+	if client == nil {
+		return
+	}
+	sharedNamingClients.Release(client)
+}
+
+// sharedClientKey returns the key of the shared client of the servers @sc, the namespace, the credentials and the
+// timeout in @cc
+func sharedClientKey(sc []nacosConstant.ServerConfig, cc nacosConstant.ClientConfig) string {
+	addresses := make([]string, 0, len(sc))
+	for _, s := range sc {
+		addresses = append(addresses, fmt.Sprintf("%s://%s:%d%s", s.Scheme, s.IpAddr, s.Port, s.ContextPath))
+	}
+	return remoting.SharedClientKey(addresses, cc.NamespaceId, cc.Username, cc.Password, cc.AccessKey, cc.SecretKey,
+		strconv.FormatUint(cc.TimeoutMs, 10))
+}
+
+// ListenConfig listens the config of @param by the shared config @client. Unlike the nacos config client, which
+// keeps only the first listener of a config and cancels all of them at once, each holder of the client listens the
+// config by its own, and the returned func cancels only the listener of @param.
+func ListenConfig(client *nacosClient.NacosConfigClient, param vo.ConfigParam) (func() error, error) {
+	key := param.Group + constant.PathSeparator + param.DataId
+	watcher := &configWatcher{onChange: param.OnChange}
+
+	configWatchersLock.Lock()
+	defer configWatchersLock.Unlock()
+	watchers, ok := configWatchers[client]
+	if !ok {
+		watchers = make(map[string][]*configWatcher)
+		configWatchers[client] = watchers
+	}
+	if len(watchers[key]) == 0 {
+		err := client.Client().ListenConfig(vo.ConfigParam{
+			DataId: param.DataId,
+			Group:  param.Group,
+			OnChange: func(namespace, group, dataId, data string) {
+				configWatchersLock.Lock()
+				listening := append([]*configWatcher(nil), configWatchers[client][key]...)
+				configWatchersLock.Unlock()
+				for _, w := range listening {
+					w.onChange(namespace, group, dataId, data)
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	watchers[key] = append(watchers[key], watcher)
+
+	return func() error {
+		configWatchersLock.Lock()
+		defer configWatchersLock.Unlock()
+		watchers := configWatchers[client]
+		for i, w := range watchers[key] {
+			if w == watcher {
+				watchers[key] = append(watchers[key][:i:i], watchers[key][i+1:]...)
+				break
+			}
+		}
+		if len(watchers[key]) > 0 {
+			return nil
+		}
+		// stop listening the config once no holder listens it
+		delete(watchers, key)
+		if client.Client() == nil {
+			return nil
+		}
+		return client.Client().CancelListenConfig(vo.ConfigParam{DataId: param.DataId, Group: param.Group})
+	}, nil
+}
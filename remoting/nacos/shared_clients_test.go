@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nacos
+
+import (
+	"testing"
+)
+
+import (
+	nacosClient "github.com/dubbogo/gost/database/kv/nacos"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// fakeConfigClient behaves like the nacos config client, which keeps only the first listener of a config and
+// cancels all of them at once
+type fakeConfigClient struct {
+	config_client.IConfigClient
+	listeners map[string]func(namespace, group, dataId, data string)
+}
+
+func (c *fakeConfigClient) ListenConfig(param vo.ConfigParam) error {
+	if _, ok := c.listeners[param.Group+"/"+param.DataId]; !ok {
+		c.listeners[param.Group+"/"+param.DataId] = param.OnChange
+	}
+	return nil
+}
+
+func (c *fakeConfigClient) CancelListenConfig(param vo.ConfigParam) error {
+	delete(c.listeners, param.Group+"/"+param.DataId)
+	return nil
+}
+
+func (c *fakeConfigClient) publish(group, dataId, data string) {
+	if onChange, ok := c.listeners[group+"/"+dataId]; ok {
+		onChange("", group, dataId, data)
+	}
+}
+
+func TestListenConfig(t *testing.T) {
+	fake := &fakeConfigClient{listeners: make(map[string]func(namespace, group, dataId, data string))}
+	client := &nacosClient.NacosConfigClient{}
+	client.SetClient(fake)
+
+	// the config center and the metadata report sharing the client listen the same config
+	var configCenter, metadataReport []string
+	cancelConfigCenter, err := ListenConfig(client, vo.ConfigParam{DataId: "mapping", Group: "dubbo",
+		OnChange: func(_, _, _, data string) { configCenter = append(configCenter, data) }})
+	assert.NoError(t, err)
+	cancelMetadataReport, err := ListenConfig(client, vo.ConfigParam{DataId: "mapping", Group: "dubbo",
+		OnChange: func(_, _, _, data string) { metadataReport = append(metadataReport, data) }})
+	assert.NoError(t, err)
+
+	fake.publish("dubbo", "mapping", "app1")
+	assert.Equal(t, []string{"app1"}, configCenter)
+	assert.Equal(t, []string{"app1"}, metadataReport)
+
+	// the cancel of a holder does not stop the listening of the others
+	assert.NoError(t, cancelConfigCenter())
+	fake.publish("dubbo", "mapping", "app1,app2")
+	assert.Equal(t, []string{"app1"}, configCenter)
+	assert.Equal(t, []string{"app1", "app1,app2"}, metadataReport)
+
+	assert.NoError(t, cancelMetadataReport())
+	assert.Empty(t, fake.listeners)
+}
+
+func TestSharedClientKey(t *testing.T) {
+	registryURL, _ := common.NewURL("registry://127.0.0.1:8848,127.0.0.2:8848",
+		common.WithParamsValue(constant.NacosNamespaceID, "dev"),
+		common.WithParamsValue(constant.NacosUsername, "nacos"),
+		common.WithParamsValue(constant.NacosTimeout, "5s"))
+	discoveryURL, _ := common.NewURL("nacos://127.0.0.2:8848,127.0.0.1:8848",
+		common.WithParamsValue(constant.NacosNamespaceID, "dev"),
+		common.WithParamsValue(constant.NacosUsername, "nacos"),
+		common.WithParamsValue(constant.NacosTimeout, "5000"))
+	otherURL, _ := common.NewURL("nacos://127.0.0.1:8848,127.0.0.2:8848",
+		common.WithParamsValue(constant.NacosNamespaceID, "test"),
+		common.WithParamsValue(constant.NacosUsername, "nacos"),
+		common.WithParamsValue(constant.NacosTimeout, "5s"))
+
+	keyOf := func(url *common.URL) string {
+		sc, cc, err := GetNacosConfig(url)
+		assert.NoError(t, err)
+		return sharedClientKey(sc, cc)
+	}
+	assert.Equal(t, keyOf(registryURL), keyOf(discoveryURL))
+	assert.NotEqual(t, keyOf(registryURL), keyOf(otherURL))
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SharedClients holds the clients of the governance centers like zookeeper, nacos and etcd, which are shared by the
+// registries, the config centers and the metadata reports connecting the same servers with the same settings, so
+// that a process opens one session to a center instead of one per component. The clients are reference counted,
+// and closed once all their holders release them.
+type SharedClients struct {
+	lock sync.Mutex
+	// clients are the clients to share by their keys
+	clients map[string]*sharedClient
+	// holders are all the clients held, including the broken ones replaced in clients
+	holders map[interface{}]*sharedClient
+}
+
+type sharedClient struct {
+	key    string
+	client interface{}
+	close  func()
+	refs   int
+}
+
+// NewSharedClients returns an empty SharedClients
+func NewSharedClients() *SharedClients {
+	return &SharedClients{
+		clients: make(map[string]*sharedClient),
+		holders: make(map[interface{}]*sharedClient),
+	}
+}
+
+// Acquire returns the client of @key, a new one is created by @create if there is none or @valid reports that the
+// shared one is broken. @create returns the client and the func closing it. The client must be released by Release.
+func (s *SharedClients) Acquire(key string, valid func(client interface{}) bool,
+	create func() (interface{}, func(), error)) (interface{}, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if shared, ok := s.clients[key]; ok && (valid == nil || valid(shared.client)) {
+		shared.refs++
+		return shared.client, nil
+	}
+	client, closeFunc, err := create()
+	if err != nil {
+		return nil, err
+	}
+	// the holders of the broken client release it by themselves
+	shared := &sharedClient{key: key, client: client, close: closeFunc, refs: 1}
+	s.clients[key] = shared
+	s.holders[client] = shared
+	return client, nil
+}
+
+// Release releases the @client acquired by Acquire, it is closed once all its holders release it. It returns true
+// if the client is closed.
+func (s *SharedClients) Release(client interface{}) bool {
+	s.lock.Lock()
+	shared, ok := s.holders[client]
+	if !ok {
+		s.lock.Unlock()
+		return false
+	}
+	if shared.refs--; shared.refs > 0 {
+		s.lock.Unlock()
+		return false
+	}
+	delete(s.holders, client)
+	if s.clients[shared.key] == shared {
+		delete(s.clients, shared.key)
+	}
+	s.lock.Unlock()
+
+	if shared.close != nil {
+		shared.close()
+	}
+	return true
+}
+
+// Refs returns the number of the holders of the client of @key
+func (s *SharedClients) Refs(key string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if shared, ok := s.clients[key]; ok {
+		return shared.refs
+	}
+	return 0
+}
+
+// SharedClientKey returns the key of the shared client of @addresses, which are compared regardless of the order,
+// and the other settings of the client like the credentials and the timeout in @settings
+func SharedClientKey(addresses []string, settings ...string) string {
+	sorted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		sorted = append(sorted, strings.TrimSpace(address))
+	}
+	sort.Strings(sorted)
+	return strings.Join(append([]string{strings.Join(sorted, ",")}, settings...), "|")
+}
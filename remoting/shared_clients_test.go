@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClient struct {
+	name   string
+	closed bool
+}
+
+func TestSharedClients(t *testing.T) {
+	clients := NewSharedClients()
+	created := 0
+	create := func(name string) func() (interface{}, func(), error) {
+		return func() (interface{}, func(), error) {
+			created++
+			client := &testClient{name: name}
+			return client, func() { client.closed = true }, nil
+		}
+	}
+
+	// the registry, the config center and the metadata report connecting the same servers
+	key := SharedClientKey([]string{"127.0.0.1:2181", "127.0.0.2:2181"}, "5s")
+	registry, err := clients.Acquire(key, nil, create("registry"))
+	assert.NoError(t, err)
+	configCenter, err := clients.Acquire(SharedClientKey([]string{" 127.0.0.2:2181", "127.0.0.1:2181"}, "5s"), nil,
+		create("config center"))
+	assert.NoError(t, err)
+	metadataReport, err := clients.Acquire(key, nil, create("metadata report"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Same(t, registry, configCenter)
+	assert.Same(t, registry, metadataReport)
+	assert.Equal(t, 3, clients.Refs(key))
+
+	// the other settings
+	other, err := clients.Acquire(SharedClientKey([]string{"127.0.0.1:2181", "127.0.0.2:2181"}, "10s"), nil,
+		create("other"))
+	assert.NoError(t, err)
+	assert.NotSame(t, registry, other)
+
+	// the client is closed once all its holders release it
+	assert.False(t, clients.Release(configCenter))
+	assert.False(t, clients.Release(registry))
+	assert.False(t, registry.(*testClient).closed)
+	assert.True(t, clients.Release(metadataReport))
+	assert.True(t, registry.(*testClient).closed)
+	assert.Equal(t, 0, clients.Refs(key))
+	assert.False(t, clients.Release(registry))
+	assert.False(t, other.(*testClient).closed)
+
+	_, err = clients.Acquire(key, nil, func() (interface{}, func(), error) {
+		return nil, nil, perrors.New("unreachable")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, clients.Refs(key))
+}
+
+func TestSharedClientsReplaceBroken(t *testing.T) {
+	clients := NewSharedClients()
+	create := func() (interface{}, func(), error) {
+		client := &testClient{}
+		return client, func() { client.closed = true }, nil
+	}
+	valid := func(client interface{}) bool {
+		return client.(*testClient).name != "broken"
+	}
+
+	broken, _ := clients.Acquire("zk", valid, create)
+	_, _ = clients.Acquire("zk", valid, create)
+	broken.(*testClient).name = "broken"
+
+	client, _ := clients.Acquire("zk", valid, create)
+	assert.NotSame(t, broken, client)
+	assert.Equal(t, 1, clients.Refs("zk"))
+
+	// the holders of the broken client release it by themselves
+	assert.False(t, clients.Release(broken))
+	assert.True(t, clients.Release(broken))
+	assert.True(t, broken.(*testClient).closed)
+	assert.False(t, client.(*testClient).closed)
+	assert.Equal(t, 1, clients.Refs("zk"))
+}
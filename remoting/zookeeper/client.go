@@ -18,10 +18,14 @@
 package zookeeper
 
 import (
+	"errors"
 	"strings"
+	"time"
 )
 
 import (
+	"github.com/dubbogo/go-zookeeper/zk"
+
 	gxzookeeper "github.com/dubbogo/gost/database/kv/zk"
 	"github.com/dubbogo/gost/log/logger"
 
@@ -29,14 +33,21 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 const (
 	ConnDelay    = 3 // connection delay interval
 	MaxFailTimes = 3 // max fail times
+
+	digestScheme = "digest"
 )
 
+// sharedClients are the clients shared by the registries, the config centers and the metadata reports
+var sharedClients = remoting.NewSharedClients()
+
 // ValidateZookeeperClient validates client and sets options
 func ValidateZookeeperClient(container ZkClientFacade, zkName string) error {
 	lock := container.ZkClientLock()
@@ -46,18 +57,86 @@ func ValidateZookeeperClient(container ZkClientFacade, zkName string) error {
 	defer lock.Unlock()
 
 	if container.ZkClient() == nil {
-		// in dubbo, every registry only connect one node, so this is []string{r.Address}
-		timeout := url.GetParamDuration(constant.ConfigTimeoutKey, constant.DefaultRegTimeout)
+		// the timeout of the config center, the registry and the service discovery in order
+		timeout := url.GetParamDurationWithDefault(constant.ConfigTimeoutKey,
+			url.GetParam(constant.RegistryTimeoutKey, url.GetParam(constant.TimeoutKey, constant.DefaultRegTimeout)))
+		newClient, err := AcquireZookeeperClient(zkName, url, timeout)
+		if err != nil {
+			return err
+		}
+		container.SetZkClient(newClient)
+	}
+	return nil
+}
 
-		zkAddresses := strings.Split(url.Location, ",")
+// AcquireZookeeperClient returns the client of the zookeeper of @url shared by the registries, the config centers
+// and the metadata reports with the same addresses, credentials and @timeout, a new one is created if there is none.
+// The client must be released by ReleaseZookeeperClient.
+func AcquireZookeeperClient(zkName string, url *common.URL, timeout time.Duration) (*gxzookeeper.ZookeeperClient, error) {
+	if err := CheckTLS(url); err != nil {
+		return nil, err
+	}
+	zkAddresses := strings.Split(url.Location, ",")
+	key := remoting.SharedClientKey(zkAddresses, url.Username, url.Password, timeout.String())
+	client, err := sharedClients.Acquire(key, nil, func() (interface{}, func(), error) {
+		// in dubbo, every registry only connect one node, so this is []string{r.Address}
 		logger.Infof("[Zookeeper Client] New zookeeper client with name = %s, zkAddress = %s, timeout = %s", zkName, url.Location, timeout.String())
-		newClient, cltErr := gxzookeeper.NewZookeeperClient(zkName, zkAddresses, true, gxzookeeper.WithZkTimeOut(timeout))
+		newClient, cltErr := gxzookeeper.NewZookeeperClient(zkName, zkAddresses, false, gxzookeeper.WithZkTimeOut(timeout))
 		if cltErr != nil {
 			logger.Warnf("newZookeeperClient(name{%s}, zk address{%v}, timeout{%d}) = error{%v}",
 				zkName, url.Location, timeout.String(), cltErr)
-			return perrors.WithMessagef(cltErr, "newZookeeperClient(address:%+v)", url.Location)
+			return nil, nil, perrors.WithMessagef(cltErr, "newZookeeperClient(address:%+v)", url.Location)
 		}
-		container.SetZkClient(newClient)
+		if err := AddAuth(newClient, url, timeout); err != nil {
+			newClient.Close()
+			return nil, nil, err
+		}
+		return newClient, newClient.Close, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.(*gxzookeeper.ZookeeperClient), nil
+}
+
+// ReleaseZookeeperClient releases the @client acquired by AcquireZookeeperClient, it is closed once all its holders
+// release it
+func ReleaseZookeeperClient(client *gxzookeeper.ZookeeperClient) {
+	if client == nil {
+		return
+	}
+	sharedClients.Release(client)
+}
+
+// CheckTLS fails if the tls of @url is set, the zookeeper client does not support it yet
+func CheckTLS(url *common.URL) error {
+	if len(url.GetParam(constant.CACert, "")) != 0 || url.GetParamBool(constant.TLSInsecureSkipVerify, false) {
+		return perrors.Errorf("the tls connection of zookeeper %s is not supported", url.Location)
 	}
 	return nil
 }
+
+// AddAuth adds the digest auth of the username and the password of @url to the connection of @client, which is
+// resubmitted on reconnection. It waits for the connection at most @timeout.
+func AddAuth(client *gxzookeeper.ZookeeperClient, url *common.URL, timeout time.Duration) error {
+	if len(url.Username) == 0 {
+		return nil
+	}
+	conn := client.Conn
+	if conn == nil {
+		return perrors.WithStack(gxzookeeper.ErrNilZkClientConn)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.AddAuth(digestScheme, []byte(url.Username+":"+url.Password))
+	}()
+	select {
+	case err := <-done:
+		if errors.Is(err, zk.ErrAuthFailed) {
+			return perrors.WithMessagef(remoting.ErrAuthFailed, "zookeeper %s as %s: %v", url.Location, url.Username, err)
+		}
+		return perrors.WithMessagef(err, "add the auth of zookeeper %s", url.Location)
+	case <-time.After(timeout):
+		return perrors.Errorf("add the auth of zookeeper %s timeout after %s", url.Location, timeout)
+	}
+}
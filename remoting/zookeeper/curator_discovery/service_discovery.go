@@ -292,11 +292,9 @@ func (sd *ServiceDiscovery) pathForName(name string) string {
 	return path.Join(sd.basePath, name)
 }
 
+// Close closes the listeners, the client is released by its holder
 func (sd *ServiceDiscovery) Close() {
 	if sd.listener != nil {
 		sd.listener.Close()
 	}
-	if sd.client != nil {
-		sd.client.Close()
-	}
 }
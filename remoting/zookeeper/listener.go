@@ -18,7 +18,6 @@
 package zookeeper
 
 import (
-	"net/url"
 	"path"
 	"strings"
 	"sync"
@@ -39,6 +38,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/diagnostics"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
@@ -51,6 +51,7 @@ type ZkEventListener struct {
 	pathMap     map[string]*uatomic.Int32
 	wg          sync.WaitGroup
 	exit        chan struct{}
+	closeOnce   sync.Once
 }
 
 // NewZkEventListener returns a EventListener instance
@@ -64,9 +65,7 @@ func NewZkEventListener(client *gxzookeeper.ZookeeperClient) *ZkEventListener {
 
 // ListenServiceNodeEvent listen a path node event
 func (l *ZkEventListener) ListenServiceNodeEvent(zkPath string, listener remoting.DataListener) {
-	l.wg.Add(1)
-	go func(zkPath string, listener remoting.DataListener) {
-		defer l.wg.Done()
+	l.goListen(zkPath, func() {
 		if l.listenServiceNodeEvent(zkPath, listener) {
 			listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.EventTypeDel})
 			l.pathMapLock.Lock()
@@ -74,15 +73,25 @@ func (l *ZkEventListener) ListenServiceNodeEvent(zkPath string, listener remotin
 			l.pathMapLock.Unlock()
 		}
 		logger.Warnf("ListenServiceNodeEvent->listenSelf(zk path{%s}) goroutine exit now", zkPath)
-	}(zkPath, listener)
+	})
+}
+
+// goListen runs @listen for @zkPath in a new goroutine, which is waited by Close
+func (l *ZkEventListener) goListen(zkPath string, listen func()) {
+	l.wg.Add(1)
+	diagnostics.Go("zookeeper-listener", zkPath, func() {
+		defer l.wg.Done()
+		listen()
+	})
 }
 
 // ListenConfigurationEvent listen a path node event
 func (l *ZkEventListener) ListenConfigurationEvent(zkPath string, listener remoting.DataListener) {
-	l.wg.Add(1)
-	go func(zkPath string, listener remoting.DataListener) {
+	l.goListen(zkPath, func() {
 		var eventChan = make(chan zk.Event, 16)
 		l.Client.RegisterEvent(zkPath, eventChan)
+		// the client may be shared with the other components, which keep watching after the listener is closed
+		defer l.Client.UnregisterEvent(zkPath, eventChan)
 		for {
 			select {
 			case event := <-eventChan:
@@ -124,8 +133,7 @@ func (l *ZkEventListener) ListenConfigurationEvent(zkPath string, listener remot
 				return
 			}
 		}
-
-	}(zkPath, listener)
+	})
 }
 
 // nolint
@@ -214,18 +222,17 @@ func (l *ZkEventListener) handleZkNodeEvent(zkPath string, children []string, li
 			continue
 		}
 		// listen l service node
-		l.wg.Add(1)
-		go func(node string, listener remoting.DataListener) {
-			defer l.wg.Done()
+		node := newNode
+		l.goListen(node, func() {
 			if l.listenServiceNodeEvent(node, listener) {
 				logger.Warnf("delete zkNode{%s}", node)
 				listener.DataChange(remoting.Event{Path: node, Action: remoting.EventTypeDel})
 				l.pathMapLock.Lock()
-				delete(l.pathMap, zkPath)
+				delete(l.pathMap, node)
 				l.pathMapLock.Unlock()
 			}
 			logger.Debugf("handleZkNodeEvent->listenSelf(zk path{%s}) goroutine exit now", node)
-		}(newNode, listener)
+		})
 	}
 
 	// old node was deleted
@@ -248,7 +255,7 @@ func (l *ZkEventListener) listenAllDirEvents(conf *common.URL, listener remoting
 	)
 	ttl = defaultTTL
 	if conf != nil {
-		if timeout, err := time.ParseDuration(conf.GetParam(constant.RegistryTTLKey, constant.DefaultRegTTL)); err == nil {
+		if timeout, err := common.ParseDuration(conf.GetParam(constant.RegistryTTLKey, constant.DefaultRegTTL)); err == nil {
 			ttl = timeout
 		} else {
 			logger.Warnf("[Zookeeper EventListener][listenDirEvent] Wrong configuration for registry.ttl, error=%+v, using default value %v instead", err, defaultTTL)
@@ -283,7 +290,7 @@ func (l *ZkEventListener) listenAllDirEvents(conf *common.URL, listener remoting
 		}
 		for _, c := range children {
 			// Build the child path
-			zkRootPath := path.Join(rootPath, constant.PathSeparator, url.QueryEscape(c), constant.PathSeparator, constant.ProvidersCategory)
+			zkRootPath := path.Join(rootPath, constant.PathSeparator, common.URLEncode(c), constant.PathSeparator, constant.ProvidersCategory)
 			// Save the path to avoid listen repeatedly
 			l.pathMapLock.Lock()
 			if _, ok := l.pathMap[zkRootPath]; ok {
@@ -295,9 +302,11 @@ func (l *ZkEventListener) listenAllDirEvents(conf *common.URL, listener remoting
 			}
 			l.pathMapLock.Unlock()
 			logger.Debugf("[Zookeeper EventListener][listenDirEvent] listen dubbo interface key{%s}", zkRootPath)
-			l.wg.Add(1)
 			// listen every interface
-			go l.listenDirEvent(conf, zkRootPath, listener, c)
+			intf := c
+			l.goListen(zkRootPath, func() {
+				l.listenDirEvent(conf, zkRootPath, listener, intf)
+			})
 		}
 
 		ticker := time.NewTicker(ttl)
@@ -317,7 +326,6 @@ func (l *ZkEventListener) listenAllDirEvents(conf *common.URL, listener remoting
 }
 
 func (l *ZkEventListener) listenDirEvent(conf *common.URL, zkRootPath string, listener remoting.DataListener, intf string) {
-	defer l.wg.Done()
 	if intf == constant.AnyValue {
 		l.listenAllDirEvents(conf, listener)
 		return
@@ -328,7 +336,7 @@ func (l *ZkEventListener) listenDirEvent(conf *common.URL, zkRootPath string, li
 	)
 	ttl = defaultTTL
 	if conf != nil {
-		timeout, err := time.ParseDuration(conf.GetParam(constant.RegistryTTLKey, constant.DefaultRegTTL))
+		timeout, err := common.ParseDuration(conf.GetParam(constant.RegistryTTLKey, constant.DefaultRegTTL))
 		if err == nil {
 			ttl = timeout
 		} else {
@@ -364,7 +372,8 @@ func (l *ZkEventListener) listenDirEvent(conf *common.URL, zkRootPath string, li
 			// Only need to compare Path when subscribing to provider
 			if strings.LastIndex(zkRootPath, constant.ProviderCategory) != -1 {
 				provider, _ := common.NewURL(c)
-				if provider.Interface() != intf || !common.IsAnyCondition(constant.AnyValue, conf.Group(), conf.Version(), provider) {
+				if provider.Interface() != intf || !common.MatchGroup(conf.Group(), provider.Group()) ||
+					!common.MatchVersion(conf.Version(), provider.Version()) {
 					continue
 				}
 			}
@@ -397,9 +406,8 @@ func (l *ZkEventListener) listenDirEvent(conf *common.URL, zkRootPath string, li
 				continue
 			}
 			logger.Debugf("[Zookeeper EventListener][listenDirEvent] listen dubbo service key{%s}", zkNodePath)
-			l.wg.Add(1)
-			go func(zkPath string, listener remoting.DataListener) {
-				defer l.wg.Done()
+			zkPath := zkNodePath
+			l.goListen(zkPath, func() {
 				if l.listenServiceNodeEvent(zkPath, listener) {
 					listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.EventTypeDel})
 					l.pathMapLock.Lock()
@@ -407,7 +415,7 @@ func (l *ZkEventListener) listenDirEvent(conf *common.URL, zkRootPath string, li
 					l.pathMapLock.Unlock()
 				}
 				logger.Warnf("listenDirEvent->listenSelf(zk path{%s}) goroutine exit now", zkPath)
-			}(zkNodePath, listener)
+			})
 		}
 		if l.startScheduleWatchTask(zkRootPath, children, ttl, listener, childEventCh) {
 			return
@@ -461,19 +469,20 @@ func timeSecondDuration(sec int) time.Duration {
 // registry.go:Listen -> listenServiceEvent -> listenServiceNodeEvent
 func (l *ZkEventListener) ListenServiceEvent(conf *common.URL, zkPath string, listener remoting.DataListener) {
 	logger.Infof("[Zookeeper Listener] listen dubbo path{%s}", zkPath)
-	l.wg.Add(1)
-	go func(zkPath string, listener remoting.DataListener) {
+	l.goListen(zkPath, func() {
 		intf := ""
 		if conf != nil {
 			intf = conf.Interface()
 		}
 		l.listenDirEvent(conf, zkPath, listener, intf)
 		logger.Warnf("ListenServiceEvent->listenDirEvent(zkPath{%s}) goroutine exit now", zkPath)
-	}(zkPath, listener)
+	})
 }
 
-// Close will let client listen exit
+// Close will let client listen exit, it waits for the listening goroutines and can be called more than once
 func (l *ZkEventListener) Close() {
-	close(l.exit)
+	l.closeOnce.Do(func() {
+		close(l.exit)
+	})
 	l.wg.Wait()
 }
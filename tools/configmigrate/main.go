@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// configmigrate rewrites a config file of dubbo-go with the deprecated keys migrated to their replacements, by the
+// same migrations as the loader of the config files. The deprecated usages are reported to stderr.
+//
+//	go run ./tools/configmigrate old.yml > new.yml
+//
+// The comments of the config file aren't kept.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/config"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s old.yml > new.yml\n", os.Args[0])
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := migrate(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func migrate(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]interface{})
+	if err = yaml.Unmarshal(content, &values); err != nil {
+		return fmt.Errorf("parse %s: %w", file, err)
+	}
+	deprecations := config.MigrateConfig(values)
+	for _, deprecation := range deprecations {
+		deprecation.File = file
+		fmt.Fprintln(os.Stderr, deprecation)
+	}
+	if len(deprecations) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: no deprecated keys\n", file)
+	}
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
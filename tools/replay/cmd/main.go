@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// The replay command replays the invocations recorded by the recording filter against a provider, and reports how
+// the responses and the latencies differ from the recorded ones.
+//
+//	go run ./tools/replay/cmd -target 127.0.0.1:20000 -rate 50 user.rec
+//
+// The exit code is 1 if any response differs.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+import (
+	_ "dubbo.apache.org/dubbo-go/v3/imports"
+	"dubbo.apache.org/dubbo-go/v3/tools/replay"
+)
+
+func main() {
+	var opts replay.Options
+	flag.StringVar(&opts.Target, "target", "", "the address of the provider, like 127.0.0.1:20000")
+	flag.StringVar(&opts.Protocol, "protocol", "dubbo", "the protocol of the provider")
+	flag.Float64Var(&opts.Rate, "rate", 0, "the max invocations per second, 0 is unlimited")
+	flag.DurationVar(&opts.Timeout, "timeout", 0, "the timeout of an invocation, 3s by default")
+	asJSON := flag.Bool("json", false, "print the report in json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -target host:port [options] file\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || opts.Target == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	report, err := replay.ReplayFile(ctx, flag.Arg(0), opts)
+	if report != nil {
+		if *asJSON {
+			out, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			fmt.Print(report)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if report.Diffs > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package replay replays the invocations recorded by the recording filter against a provider, like a dev instance,
+// by the generic invocations, and reports how the responses and the latencies differ from the recorded ones, e.g.
+//
+//	report, err := replay.ReplayFile(ctx, "/var/log/user.rec", replay.Options{Target: "127.0.0.1:20000", Rate: 50})
+//
+// The protocol of the target must be registered, like by importing dubbo.apache.org/dubbo-go/v3/imports, and the
+// provider must enable the generic invocations, which are enabled by default. The masked arguments are replayed as
+// they are, and the redacted attachments, like the tokens, are not replayed.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter/masking"
+	"dubbo.apache.org/dubbo-go/v3/filter/recording"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const (
+	defaultProtocol = "dubbo"
+	defaultTimeout  = 3 * time.Second
+)
+
+// skippedAttachments are the attachments set by the replayer itself
+var skippedAttachments = map[string]struct{}{
+	constant.PathKey:      {},
+	constant.InterfaceKey: {},
+	constant.GroupKey:     {},
+	constant.VersionKey:   {},
+	constant.GenericKey:   {},
+	constant.TimeoutKey:   {},
+}
+
+// Options are the options of the replay
+type Options struct {
+	// Target is the address of the provider, like 127.0.0.1:20000
+	Target string
+	// Protocol is the protocol of the provider, dubbo by default
+	Protocol string
+	// Rate is the max invocations per second, the invocations are not limited if it is 0
+	Rate float64
+	// Timeout is the timeout of an invocation, 3s by default
+	Timeout time.Duration
+	// MaskingFields and MaskingPartialFields mask the responses before they're compared with the recorded ones,
+	// they should be the same as the masking of the recording, constant.DefaultMaskingFields by default
+	MaskingFields        []string
+	MaskingPartialFields []string
+}
+
+// Result is the replay of a record
+type Result struct {
+	Record   *recording.Record `json:"record"`
+	Response interface{}       `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Latency  time.Duration     `json:"latency"`
+	// Diff describes how the response or the error differs from the recorded one, which is empty if they're the same
+	Diff string `json:"diff,omitempty"`
+}
+
+// Report is the results of a replay
+type Report struct {
+	Total int `json:"total"`
+	// Diffs is the number of the results differing from the records
+	Diffs int `json:"diffs"`
+	// RecordedLatency and ReplayedLatency are the mean latencies of the records and the replays
+	RecordedLatency time.Duration `json:"recordedLatency"`
+	ReplayedLatency time.Duration `json:"replayedLatency"`
+	Results         []Result      `json:"results"`
+}
+
+// String renders the summary of the report, followed by the differences
+func (r *Report) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("replayed %d, differed %d, mean latency %s recorded, %s replayed\n",
+		r.Total, r.Diffs, r.RecordedLatency, r.ReplayedLatency))
+	for i, result := range r.Results {
+		if result.Diff != "" {
+			sb.WriteString(fmt.Sprintf("#%d %s.%s: %s\n", i, result.Record.Interface, result.Record.Method, result.Diff))
+		}
+	}
+	return sb.String()
+}
+
+// Replayer replays the records against a provider. It refers the services of the records on demand, which are
+// destroyed by Destroy.
+type Replayer struct {
+	opts     Options
+	proto    protocol.Protocol
+	host     string
+	port     string
+	masker   *masking.Masker
+	invokers map[string]protocol.Invoker
+}
+
+// NewReplayer creates a Replayer by @opts
+func NewReplayer(opts Options) (*Replayer, error) {
+	host, port, err := net.SplitHostPort(opts.Target)
+	if err != nil {
+		return nil, perrors.Wrapf(err, "invalid target %q", opts.Target)
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = defaultProtocol
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.MaskingFields == nil {
+		opts.MaskingFields = strings.Split(constant.DefaultMaskingFields, ",")
+	}
+	r := &Replayer{
+		opts:     opts,
+		host:     host,
+		port:     port,
+		masker:   masking.New(opts.MaskingFields, opts.MaskingPartialFields),
+		invokers: make(map[string]protocol.Invoker),
+	}
+	if err = func() (err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				err = perrors.Errorf("%v", e)
+			}
+		}()
+		r.proto = extension.GetProtocol(opts.Protocol)
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ReplayFile replays the records in the file @path by the @opts
+func ReplayFile(ctx context.Context, path string, opts Options) (*Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	r, err := NewReplayer(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Destroy()
+	return r.Replay(ctx, recording.NewReader(file))
+}
+
+// Replay replays the records read from @reader in order until the end or @ctx is done, no faster than the rate
+func (r *Replayer) Replay(ctx context.Context, reader *recording.Reader) (*Report, error) {
+	report := &Report{Results: make([]Result, 0)}
+	var interval time.Duration
+	if r.opts.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / r.opts.Rate)
+	}
+	var recorded, replayed time.Duration
+	next := time.Now()
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		if wait := time.Until(next); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return report, ctx.Err()
+			case <-time.After(wait):
+			}
+		} else if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		next = next.Add(interval)
+
+		result := r.replay(ctx, record)
+		report.Total++
+		if result.Diff != "" {
+			report.Diffs++
+		}
+		recorded += record.Latency
+		replayed += result.Latency
+		report.Results = append(report.Results, result)
+	}
+	if report.Total > 0 {
+		report.RecordedLatency = recorded / time.Duration(report.Total)
+		report.ReplayedLatency = replayed / time.Duration(report.Total)
+	}
+	return report, nil
+}
+
+// Destroy destroys the invokers referred
+func (r *Replayer) Destroy() {
+	for _, invoker := range r.invokers {
+		invoker.Destroy()
+	}
+	r.invokers = make(map[string]protocol.Invoker)
+}
+
+// replay invokes the method of the @record by a generic invocation, and compares the response with the record
+func (r *Replayer) replay(ctx context.Context, record *recording.Record) Result {
+	result := Result{Record: record}
+	args := make([]hessian.Object, 0, len(record.Arguments))
+	for _, arg := range record.Arguments {
+		args = append(args, arg)
+	}
+	types := record.Types
+	if types == nil {
+		types = []string{}
+	}
+	attachments := make(map[string]interface{}, len(record.Attachments)+1)
+	for k, v := range record.Attachments {
+		if _, ok := skippedAttachments[k]; ok || v == common.RedactedValue {
+			continue
+		}
+		attachments[k] = v
+	}
+	attachments[constant.GenericKey] = constant.GenericSerializationDefault
+	inv := invocation.NewRPCInvocationWithOptions(
+		invocation.WithMethodName(constant.Generic),
+		invocation.WithArguments([]interface{}{record.Method, types, args}),
+		invocation.WithAttachments(attachments))
+	var reply interface{}
+	inv.SetReply(&reply)
+
+	invoker, err := r.invoker(record)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+		start := time.Now()
+		res := invoker.Invoke(ctx, inv)
+		result.Latency = time.Since(start)
+		cancel()
+		if res.Error() != nil {
+			result.Error = res.Error().Error()
+		} else {
+			result.Response = reply
+		}
+	}
+	result.Diff = r.diff(record, &result)
+	return result
+}
+
+// invoker returns the invoker of the service of the @record, which is referred on the first use
+func (r *Replayer) invoker(record *recording.Record) (protocol.Invoker, error) {
+	key := common.ServiceKey(record.Interface, record.Group, record.Version)
+	if invoker, ok := r.invokers[key]; ok {
+		return invoker, nil
+	}
+	url := common.NewURLWithOptions(
+		common.WithProtocol(r.opts.Protocol),
+		common.WithIp(r.host),
+		common.WithPort(r.port),
+		common.WithPath(record.Interface),
+		common.WithParamsValue(constant.InterfaceKey, record.Interface),
+		common.WithParamsValue(constant.GroupKey, record.Group),
+		common.WithParamsValue(constant.VersionKey, record.Version),
+		common.WithParamsValue(constant.GenericKey, constant.GenericSerializationDefault),
+		common.WithParamsValue(constant.TimeoutKey, r.opts.Timeout.String()),
+	)
+	invoker := r.proto.Refer(url)
+	if invoker == nil {
+		return nil, perrors.Errorf("can not refer %s at %s", key, r.opts.Target)
+	}
+	r.invokers[key] = invoker
+	return invoker, nil
+}
+
+// diff describes how the @result differs from the @record, the responses are masked and compared in json
+func (r *Replayer) diff(record *recording.Record, result *Result) string {
+	if record.Error != "" || result.Error != "" {
+		if record.Error != result.Error {
+			return fmt.Sprintf("error: recorded %q, replayed %q", record.Error, result.Error)
+		}
+		return ""
+	}
+	recorded, err := json.Marshal(r.masker.Mask(record.Result))
+	if err != nil {
+		return fmt.Sprintf("response: can not encode the recorded response: %v", err)
+	}
+	replayed, err := json.Marshal(r.masker.Mask(result.Response))
+	if err != nil {
+		return fmt.Sprintf("response: can not encode the replayed response: %v", err)
+	}
+	if string(recorded) != string(replayed) {
+		return fmt.Sprintf("response: recorded %s, replayed %s", recorded, replayed)
+	}
+	return ""
+}
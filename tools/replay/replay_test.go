@@ -0,0 +1,182 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter/recording"
+	_ "dubbo.apache.org/dubbo-go/v3/imports"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
+)
+
+const replayInterface = "org.apache.dubbo.replay.UserProvider"
+
+type ReplayUser struct {
+	Name     string
+	Password string
+}
+
+func (u *ReplayUser) JavaClassName() string {
+	return "org.apache.dubbo.replay.User"
+}
+
+// ReplayProvider is the mock provider, Next returns a different number each time
+type ReplayProvider struct {
+	counter atomic.Int64
+}
+
+func (p *ReplayProvider) GetUser(_ context.Context, name string) (*ReplayUser, error) {
+	return &ReplayUser{Name: name, Password: "secret-" + name}, nil
+}
+
+func (p *ReplayProvider) Next(_ context.Context) (int64, error) {
+	return p.counter.Inc(), nil
+}
+
+func (p *ReplayProvider) Fail(_ context.Context, name string) (string, error) {
+	return "", errors.New("no user " + name)
+}
+
+func freePort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	return strings.TrimPrefix(l.Addr().String(), "127.0.0.1:")
+}
+
+// exportProvider exports the mock provider recording its invocations into @path
+func exportProvider(t *testing.T, path string) string {
+	hessian.RegisterPOJO(&ReplayUser{})
+	_, err := common.ServiceMap.Register(replayInterface, "dubbo", "", "", &ReplayProvider{})
+	assert.NoError(t, err)
+	port := freePort(t)
+	url, err := common.NewURL("dubbo://127.0.0.1:"+port+"/"+replayInterface,
+		common.WithParamsValue(constant.InterfaceKey, replayInterface),
+		common.WithParamsValue(constant.ServiceFilterKey, "generic_service,recording"),
+		common.WithParamsValue(constant.RecordingFilterKey, path),
+		common.WithParamsValue(constant.RecordingEncodingKey, "hessian"))
+	assert.NoError(t, err)
+	invoker := protocolwrapper.BuildInvokerChain(extension.GetProxyFactory("default").GetInvoker(url),
+		constant.ServiceFilterKey)
+	exporter := extension.GetProtocol("dubbo").Export(invoker)
+	t.Cleanup(func() {
+		exporter.UnExport()
+		_ = common.ServiceMap.UnRegister(replayInterface, "dubbo", common.ServiceKey(replayInterface, "", ""))
+	})
+	return "127.0.0.1:" + port
+}
+
+func writeRecords(t *testing.T, path string, records ...*recording.Record) {
+	var data []byte
+	for _, record := range records {
+		b, err := recording.Marshal(record, recording.EncodingJSON)
+		assert.NoError(t, err)
+		data = append(data, b...)
+	}
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func countRecords(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	reader := recording.NewReader(file)
+	n := 0
+	for _, err = reader.Next(); err == nil; _, err = reader.Next() {
+		n++
+	}
+	return n
+}
+
+// TestRecordAndReplay records the invocations of the mock provider, which are issued by replaying the seeds, and
+// replays the recording against the provider again
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorded := filepath.Join(dir, "user.rec")
+	target := exportProvider(t, recorded)
+
+	seeds := filepath.Join(dir, "seeds.rec")
+	writeRecords(t, seeds,
+		&recording.Record{Interface: replayInterface, Method: "GetUser", Arguments: []interface{}{"alex"},
+			Attachments: map[string]interface{}{"traceId": "t1", "token": common.RedactedValue}},
+		&recording.Record{Interface: replayInterface, Method: "Next", Arguments: []interface{}{}},
+		&recording.Record{Interface: replayInterface, Method: "Fail", Arguments: []interface{}{"bob"}})
+	report, err := ReplayFile(context.Background(), seeds, Options{Target: target, Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Empty(t, report.Results[0].Error)
+	assert.Equal(t, "no user bob", report.Results[2].Error)
+	assert.Eventually(t, func() bool { return countRecords(recorded) == 3 }, 5*time.Second, 50*time.Millisecond)
+
+	// the provider keeps recording the replays, so a copy of the recording is replayed
+	data, err := os.ReadFile(recorded)
+	assert.NoError(t, err)
+	replayed := filepath.Join(dir, "replayed.rec")
+	assert.NoError(t, os.WriteFile(replayed, data, 0o600))
+	start := time.Now()
+	report, err = ReplayFile(context.Background(), replayed, Options{Target: target, Rate: 20, Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+	// the invocations are issued at the rate
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Diffs)
+	getUser := report.Results[0]
+	assert.Equal(t, "GetUser", getUser.Record.Method)
+	assert.Equal(t, "t1", getUser.Record.Attachments["traceId"])
+	// the redacted attachments are not replayed
+	assert.NotContains(t, getUser.Record.Attachments, "token")
+	assert.Empty(t, getUser.Diff)
+	assert.Equal(t, "response: recorded 1, replayed 2", report.Results[1].Diff)
+	assert.Empty(t, report.Results[2].Diff)
+	assert.True(t, report.ReplayedLatency > 0)
+	assert.Contains(t, report.String(), "replayed 3, differed 1")
+	assert.Contains(t, report.String(), "#1 "+replayInterface+".Next: response: recorded 1, replayed 2")
+}
+
+func TestNewReplayer(t *testing.T) {
+	_, err := NewReplayer(Options{Target: "127.0.0.1"})
+	assert.Error(t, err)
+	_, err = NewReplayer(Options{Target: "127.0.0.1:20000", Protocol: "unknown"})
+	assert.Error(t, err)
+	r, err := NewReplayer(Options{Target: "127.0.0.1:20000"})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTimeout, r.opts.Timeout)
+}